@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/user"
 )
 
@@ -65,6 +66,112 @@ func (m *MockService) PromoteToAdmin(ctx context.Context, userID uint) error {
 	return args.Error(0)
 }
 
+func (m *MockService) ListRoles(ctx context.Context) ([]user.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]user.Role), args.Error(1)
+}
+
+func (m *MockService) AssignRole(ctx context.Context, userID uint, roleName string) (*user.User, error) {
+	args := m.Called(ctx, userID, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) RemoveRole(ctx context.Context, userID uint, roleName string) (*user.User, error) {
+	args := m.Called(ctx, userID, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) LinkCorretor(ctx context.Context, userID uint, corretorID *uint) (*user.User, error) {
+	args := m.Called(ctx, userID, corretorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) AssignOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) (*user.User, error) {
+	args := m.Called(ctx, userID, organizacaoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) EnrollTwoFactor(ctx context.Context, userID uint) (string, string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockService) ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockService) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockService) VerifyTwoFactor(ctx context.Context, userID uint, code string) (*user.User, error) {
+	args := m.Called(ctx, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) VerifyTwoFactorLogin(ctx context.Context, challenge string, userID uint, code string) (*user.User, error) {
+	args := m.Called(ctx, challenge, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) InviteUser(ctx context.Context, req user.InviteUserRequest) (*user.Invitation, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.Invitation), args.Error(1)
+}
+
+func (m *MockService) AcceptInvitation(ctx context.Context, req user.AcceptInvitationRequest) (*user.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) AuthenticateOAuth(ctx context.Context, profile auth.OAuthProfile) (*user.User, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockService) ChangePassword(ctx context.Context, id uint, req user.ChangePasswordRequest) (*user.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
 func TestValidatePassword(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -2,21 +2,45 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 
 	_ "github.com/vahiiiid/go-rest-api-boilerplate/api/docs"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 )
 
 func main() {
 	// Parse command-line flags (organization ID is no longer required)
+	full := flag.Bool("full", false, "re-import every property, ignoring the incremental state")
+	cidades := flag.String("cidades", "", "comma-separated cities to import, e.g. \"São Paulo,Campinas\"")
+	corretores := flag.String("corretores", "", "comma-separated e-mails or external IDs of the corretores to import")
+	status := flag.String("status", "", "comma-separated listing statuses to import")
+	ids := flag.String("ids", "", "comma-separated external listing IDs to import")
+	resume := flag.Uint("resume", 0, "ID of an interrupted import run to resume from its checkpoint, with its mode and filters")
 	flag.Parse()
 
+	filters := imoveis.ImportFilters{
+		Cidades:    splitList(*cidades),
+		Corretores: splitList(*corretores),
+		Status:     splitList(*status),
+	}
+	for _, id := range splitList(*ids) {
+		parsed, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid listing ID %q\n", id)
+			os.Exit(2)
+		}
+		filters.IDs = append(filters.IDs, uint(parsed))
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig("")
 	if err != nil {
@@ -25,7 +49,7 @@ func main() {
 	}
 
 	// Setup logger
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	// Connect to database
 	database, err := db.NewPostgresDBFromDatabaseConfig(cfg.Database)
@@ -50,15 +74,49 @@ func main() {
 	imoveisRepo := imoveis.NewRepository(database)
 	imoveisService := imoveis.NewService(imoveisRepo)
 	// Organization ID is now taken from the external API data
-	imoveisImportService := imoveis.NewImportService(imoveisService, &cfg.ExternalAPI)
+	importOptions := []imoveis.ImportServiceOption{imoveis.WithLogger(logger)}
+	if cfg.ExternalAPI.DownloadImages {
+		anexoStorage, err := storage.New(&cfg.Storage)
+		if err != nil {
+			logger.Error("Failed to initialize storage", "error", err)
+			os.Exit(1)
+		}
+		importOptions = append(importOptions, imoveis.WithImageDownload(anexoStorage))
+	}
+	imoveisImportService, err := imoveis.NewImportService(imoveisService, imoveisRepo, &cfg.ExternalAPI, importOptions...)
+	if err != nil {
+		logger.Error("Failed to initialize import service", "error", err)
+		os.Exit(1)
+	}
 
-	logger.Info("Starting import of properties from external API")
+	logger.Info("Starting import of properties from external API", "full", *full, "incremental", cfg.ExternalAPI.Incremental,
+		"cidades", filters.Cidades, "corretores", filters.Corretores, "status", filters.Status, "ids", filters.IDs, "resume", *resume)
 
 	// Run import
 	ctx := context.Background()
-	if err := imoveisImportService.ImportPublishedProperties(ctx); err != nil {
-		logger.Error("Import completed with message", "result", err.Error())
+	result, err := imoveisImportService.ImportPublishedProperties(ctx, imoveis.ImportOptions{Full: *full, ImportFilters: filters, Resume: *resume})
+	if err != nil {
+		logger.Error("Import failed", "error", err, "result", result.String(), "run_id", result.RunID)
+		os.Exit(1)
+	}
+
+	// Print the result on stdout for scripts, apart from the logs
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		logger.Error("Failed to write import result", "error", err)
+		os.Exit(1)
 	}
+	logger.Info("Import process finished", "result", result.String(), "run_id", result.RunID)
+}
 
-	logger.Info("Import process finished")
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -18,6 +18,8 @@ func main() {
 	timeoutFlag := flag.String("timeout", "", "Migration timeout (e.g., 5m, 30s, 1h)")
 	lockTimeoutFlag := flag.String("lock-timeout", "", "Lock acquisition timeout (e.g., 30s, 1m)")
 	forceFlag := flag.Bool("force", false, "Skip confirmations for destructive operations")
+	tenantFlag := flag.Uint("tenant", 0, "Run the command against a single organizacao tenant")
+	allTenantsFlag := flag.Bool("all-tenants", false, "Run the command against every provisioned tenant")
 	flag.Parse()
 
 	args := flag.Args()
@@ -72,11 +74,54 @@ func main() {
 		}
 	}()
 
-	migrator, err := migrate.New(sqlDB, migrate.Config{
+	migrateCfg := migrate.Config{
 		MigrationsDir: cfg.Migrations.Directory,
 		Timeout:       timeout,
 		LockTimeout:   lockTimeout,
-	})
+	}
+
+	if command == "create" {
+		handleCreate(cfg.Migrations.Directory, args)
+		return
+	}
+
+	if command == "tenant-create" || *tenantFlag > 0 || *allTenantsFlag {
+		tenants := db.NewTenantManager(database, cfg.Database)
+		if !tenants.Enabled() {
+			slog.Error("Tenant commands require database.tenancy_mode to be schema or database")
+			os.Exit(1)
+		}
+		if command == "drop" {
+			slog.Error("drop is not supported for tenants")
+			os.Exit(1)
+		}
+
+		if command == "tenant-create" {
+			handleTenantCreate(tenants, migrateCfg, timeout, args)
+			return
+		}
+
+		ids := []uint{*tenantFlag}
+		if *allTenantsFlag {
+			listCtx, cancel := context.WithTimeout(context.Background(), timeout)
+			ids, err = tenants.Tenants(listCtx)
+			cancel()
+			if err != nil {
+				slog.Error("Failed to list tenants", "err", err)
+				os.Exit(1)
+			}
+		}
+
+		for _, id := range ids {
+			slog.Info("Running migration command for tenant", "command", command, "tenant", tenants.TenantName(id))
+			runTenant(tenants, id, migrateCfg, timeout, func(ctx context.Context, migrator *migrate.Migrator) {
+				runCommand(ctx, migrator, command, args, *forceFlag)
+			})
+		}
+		return
+	}
+
+	migrator, err := migrate.New(sqlDB, migrateCfg)
 	if err != nil {
 		slog.Error("Failed to create migrator", "err", err)
 		os.Exit(1)
@@ -90,6 +135,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	runCommand(ctx, migrator, command, args, *forceFlag)
+}
+
+func runCommand(ctx context.Context, migrator *migrate.Migrator, command string, args []string, force bool) {
 	switch command {
 	case "up":
 		handleUp(ctx, migrator, args)
@@ -102,9 +151,7 @@ func main() {
 	case "force":
 		handleForce(migrator, args)
 	case "drop":
-		handleDrop(migrator, *forceFlag)
-	case "create":
-		handleCreate(cfg.Migrations.Directory, args)
+		handleDrop(migrator, force)
 	default:
 		slog.Error("Unknown command", "command", command)
 		printUsage()
@@ -112,6 +159,70 @@ func main() {
 	}
 }
 
+// runTenant opens a dedicated connection scoped to the tenant, so the migrator (which closes
+// its connection) never touches the shared pool
+func runTenant(tenants *db.TenantManager, id uint, migrateCfg migrate.Config, timeout time.Duration, fn func(context.Context, *migrate.Migrator)) {
+	tenantDB, err := tenants.OpenTenant(id)
+	if err != nil {
+		slog.Error("Failed to connect to tenant", "tenant", tenants.TenantName(id), "err", err)
+		os.Exit(1)
+	}
+
+	tenantSQL, err := tenantDB.DB()
+	if err != nil {
+		slog.Error("Failed to get tenant database instance", "tenant", tenants.TenantName(id), "err", err)
+		os.Exit(1)
+	}
+
+	migrator, err := migrate.New(tenantSQL, migrateCfg)
+	if err != nil {
+		_ = tenantSQL.Close()
+		slog.Error("Failed to create tenant migrator", "tenant", tenants.TenantName(id), "err", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := migrator.Close(); err != nil {
+			slog.Warn("Failed to close tenant migrator", "tenant", tenants.TenantName(id), "err", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	fn(ctx, migrator)
+}
+
+func handleTenantCreate(tenants *db.TenantManager, migrateCfg migrate.Config, timeout time.Duration, args []string) {
+	if len(args) < 2 {
+		slog.Error("Organizacao ID required")
+		fmt.Println("Usage: migrate tenant-create ORG_ID")
+		os.Exit(1)
+	}
+
+	id, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil || id == 0 {
+		slog.Error("Invalid organizacao ID", "value", args[1])
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := tenants.Provision(ctx, uint(id)); err != nil {
+		slog.Error("Failed to provision tenant", "err", err)
+		os.Exit(1)
+	}
+
+	runTenant(tenants, uint(id), migrateCfg, timeout, func(ctx context.Context, migrator *migrate.Migrator) {
+		if err := migrator.Up(ctx); err != nil {
+			slog.Error("Migration error", "tenant", tenants.TenantName(uint(id)), "err", err)
+			os.Exit(1)
+		}
+	})
+
+	slog.Info("Tenant provisioned", "tenant", tenants.TenantName(uint(id)))
+}
+
 func handleUp(ctx context.Context, migrator *migrate.Migrator, args []string) {
 	if len(args) > 1 {
 		n, err := strconv.Atoi(args[1])
@@ -308,11 +419,14 @@ func printUsage() {
 	fmt.Println("  force VERSION    Force set migration version (recovery)")
 	fmt.Println("  drop             Drop all tables (requires confirmation)")
 	fmt.Println("  create NAME      Create new migration files")
+	fmt.Println("  tenant-create ID Create the schema/database of an organizacao and migrate it")
 	fmt.Println("")
 	fmt.Println("Flags:")
 	fmt.Println("  --timeout DURATION        Override migration timeout (e.g., 5m, 30s, 1h)")
 	fmt.Println("  --lock-timeout DURATION   Override lock timeout (e.g., 30s, 1m)")
 	fmt.Println("  --force                   Skip confirmations (for drop command)")
+	fmt.Println("  --tenant ID               Run against a single organizacao tenant")
+	fmt.Println("  --all-tenants             Run against every provisioned tenant")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  migrate up")
@@ -322,4 +436,6 @@ func printUsage() {
 	fmt.Println("  migrate version")
 	fmt.Println("  migrate create add_user_avatar")
 	fmt.Println("  migrate up --timeout=30m --lock-timeout=1m")
+	fmt.Println("  migrate --all-tenants up")
+	fmt.Println("  migrate tenant-create 42")
 }
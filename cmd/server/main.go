@@ -13,14 +13,30 @@ import (
 	"gorm.io/gorm"
 
 	_ "github.com/vahiiiid/go-rest-api-boilerplate/api/docs"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/analytics"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/authaudit"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/cache"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/caracteristicas"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/corretores"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/empreendimentos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/enderecos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/favoritos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/health"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/leads"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/migrate"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/notifications"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/organizacoes"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/precos"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/server"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/sliders"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/user"
 )
 
@@ -67,14 +83,29 @@ func run() error {
 
 	cfg.LogSafeConfig(logger)
 
+	if err := checkTenancyMode(&cfg.Database); err != nil {
+		logger.Error("Unsupported tenancy mode, refusing to start", "error", err)
+		return err
+	}
+
 	database, err := db.NewPostgresDBFromDatabaseConfig(cfg.Database)
 	if err != nil {
 		logger.Error("Failed to connect to database", "error", err)
 		return err
 	}
 
+	// Requests of users that belong to an organizacao only see its imoveis, sliders and emails
+	if err := database.Use(db.NewTenantScope(db.TenantTables)); err != nil {
+		logger.Error("Failed to register tenant scope", "error", err)
+		return err
+	}
+
 	if os.Getenv("SKIP_MIGRATION_CHECK") == "" {
 		if err := checkMigrationStatus(database, &cfg.Migrations); err != nil {
+			if cfg.Migrations.RequireUpToDate {
+				logger.Error("Migration check failed, refusing to start", "error", err)
+				return err
+			}
 			logger.Warn("Migration check", "status", "⚠️", "error", err)
 		} else {
 			logger.Info("Migration check", "status", "✓")
@@ -82,34 +113,209 @@ func run() error {
 	}
 
 	authService := auth.NewServiceWithRepo(&cfg.JWT, database)
-	userRepo := user.NewRepository(database)
-	userService := user.NewService(userRepo)
-	userHandler := user.NewHandler(userService, authService)
 
-	// Sliders module setup
-	sliderRepo := sliders.NewRepository(database)
-	sliderService := sliders.NewService(sliderRepo)
-	slidersHandler := sliders.NewHandler(sliderService)
-
-	// Imoveis module setup
-	imoveisRepo := imoveis.NewRepository(database)
-	imoveisService := imoveis.NewService(imoveisRepo)
-	imoveisImportService := imoveis.NewImportService(imoveisService, &cfg.ExternalAPI)
-	imoveisHandler := imoveis.NewHandler(imoveisService, imoveisImportService)
+	// Object storage for uploaded anexos, slider images and inline email images
+	anexoStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Error("Failed to initialize storage", "error", err)
+		return err
+	}
 
 	// Email module setup
-	emailService, err := email.NewService(cfg)
+	emailRepo := email.NewRepository(database)
+	emailService, err := email.NewService(cfg, emailRepo, email.WithStorage(anexoStorage))
 	if err != nil {
 		logger.Warn("Failed to initialize email service", "error", err)
 		logger.Warn("Email functionality will be limited. Please configure SMTP settings.")
 	}
 	emailHandler := email.NewHandler(emailService)
+	var emailDispatcher email.Dispatcher
+	if emailService != nil {
+		emailDispatcher = email.NewDispatcher(emailService, time.Duration(cfg.Email.QueueIntervalSeconds)*time.Second)
+	}
+
+	// Event bus: the domain events of the modules, emailed by the notifications module when enabled
+	var eventBus events.Bus
+	if cfg.Notifications.Enabled && emailService != nil {
+		eventBus = events.NewBus(0, cfg.Notifications.QueueSize)
+	}
+
+	// User module setup; failed logins are counted per account and address when protection is on,
+	// admins can invite users when email is configured, and Google sign-in is on when configured
+	userRepo := user.NewRepository(database)
+	totpIssuer := cfg.TwoFactor.Issuer
+	if totpIssuer == "" {
+		totpIssuer = cfg.App.Name
+	}
+	userOptions := []user.ServiceOption{user.WithTOTPIssuer(totpIssuer), user.WithEvents(eventBus)}
+	if cfg.Login.ProtectionEnabled {
+		attemptTTL := max(cfg.Login.Window, cfg.Login.Lockout, auth.TwoFactorChallengeTTL)
+		attemptStore := auth.NewMemoryAttemptStore(auth.DefaultAttemptStoreSize, attemptTTL)
+		if cfg.Login.Store == auth.AttemptStoreDatabase {
+			attemptStore = auth.NewDatabaseAttemptStore(database, attemptTTL)
+		}
+		userOptions = append(userOptions, user.WithLoginLimiter(auth.NewLoginLimiter(&cfg.Login, attemptStore)))
+	}
+	if emailService != nil {
+		userOptions = append(userOptions, user.WithInvitations(&cfg.Invites, cfg.JWT.Secret, user.NewEmailInvitationSender(emailService)))
+	}
+	userService := user.NewService(userRepo, userOptions...)
+	auditService := authaudit.NewService(authaudit.NewRepository(database))
+	userHandlerOptions := []user.HandlerOption{user.WithAuditLog(auditService)}
+	if google := auth.NewGoogleOAuth(&cfg.OAuth.Google); google != nil {
+		userHandlerOptions = append(userHandlerOptions, user.WithGoogleOAuth(google))
+	}
+	userHandler := user.NewHandler(userService, authService, userHandlerOptions...)
+
+	// Imoveis module setup
+	imoveisRepo := imoveis.NewRepository(database)
+	anexoProcessor := imoveis.NewAnexoProcessor(imoveisRepo, anexoStorage, cfg.Storage.ImageWorkers)
+	var archiveNotifier imoveis.ArchiveNotifier
+	if cfg.Archive.NotifyCorretor && emailService != nil {
+		archiveNotifier = imoveis.NewEmailArchiveNotifier(emailService)
+	}
+	imoveisService := imoveis.NewService(imoveisRepo,
+		imoveis.WithAnexoProcessor(anexoProcessor),
+		imoveis.WithStaleArchive(cfg.Archive.DefaultDays, archiveNotifier),
+		imoveis.WithEvents(eventBus),
+	)
+	responseCache, err := cache.New(&cfg.Cache)
+	if err != nil {
+		logger.Error("Failed to initialize cache", "error", err)
+		return err
+	}
+	if responseCache != nil {
+		imoveisService = imoveis.NewCachedService(imoveisService, responseCache, time.Duration(cfg.Cache.TTLSeconds)*time.Second)
+	}
+	notificationsRepo := notifications.NewRepository(database)
+	if eventBus != nil {
+		notifications.NewNotifier(emailService, imoveisService, notificationsRepo).Subscribe(eventBus)
+	}
+	importOptions := []imoveis.ImportServiceOption{imoveis.WithLogger(logger), imoveis.WithImportEvents(eventBus)}
+	if cfg.ExternalAPI.DownloadImages {
+		importOptions = append(importOptions, imoveis.WithImageDownload(anexoStorage))
+	}
+	imoveisImportService, err := imoveis.NewImportService(imoveisService, imoveisRepo, &cfg.ExternalAPI, importOptions...)
+	if err != nil {
+		logger.Error("Failed to initialize import service", "error", err)
+		return err
+	}
+	imoveisUploadService := imoveis.NewUploadService(imoveisService, anexoStorage, &cfg.Storage)
+	imoveisHandler := imoveis.NewHandler(imoveisService, imoveisImportService, imoveisUploadService)
+
+	// Leads module setup
+	leadOptions := []leads.ServiceOption{leads.WithEvents(eventBus)}
+	if captcha := leads.NewCaptchaVerifier(&cfg.Leads); captcha != nil {
+		leadOptions = append(leadOptions, leads.WithCaptcha(captcha))
+	}
+	leadsHandler := leads.NewHandler(leads.NewService(leads.NewRepository(database), leadOptions...))
+
+	// Sliders module setup
+	sliderRepo := sliders.NewRepository(database)
+	sliderService := sliders.NewService(sliderRepo, cfg, imoveisService, anexoStorage)
+	if responseCache != nil {
+		sliderService = sliders.NewCachedService(sliderService, responseCache, cfg.Sliders.CacheTTL)
+	}
+	slidersHandler := sliders.NewHandler(sliderService, cfg.Sliders.CacheTTL)
+
+	// Caracteristicas catalog setup
+	caracteristicasRepo := caracteristicas.NewRepository(database)
+	caracteristicasService := caracteristicas.NewService(caracteristicasRepo)
+	caracteristicasHandler := caracteristicas.NewHandler(caracteristicasService)
+
+	// Empreendimentos module setup
+	empreendimentosRepo := empreendimentos.NewRepository(database)
+	empreendimentosService := empreendimentos.NewService(empreendimentosRepo)
+	empreendimentosHandler := empreendimentos.NewHandler(empreendimentosService)
+
+	// Corretores module setup
+	corretoresRepo := corretores.NewRepository(database)
+	corretoresService := corretores.NewService(corretoresRepo)
+	corretoresHandler := corretores.NewHandler(corretoresService)
+
+	// Organizacoes module setup
+	organizacoesRepo := organizacoes.NewRepository(database)
+	organizacoesService := organizacoes.NewService(organizacoesRepo)
+	organizacoesHandler := organizacoes.NewHandler(organizacoesService)
+
+	// Enderecos module setup
+	enderecosRepo := enderecos.NewRepository(database)
+	enderecosService := enderecos.NewService(enderecosRepo, enderecos.NewViaCEPClient(&cfg.ViaCEP))
+	enderecosHandler := enderecos.NewHandler(enderecosService)
+
+	// Precos module setup (pacotes, precos de venda e aluguel)
+	precosRepo := precos.NewRepository(database)
+	precosService := precos.NewService(precosRepo, precos.WithEvents(eventBus))
+	precosHandler := precos.NewHandler(precosService)
+
+	// Publication scheduler: publishes and expires imoveis at their scheduled dates
+	var publicationScheduler imoveis.PublicationScheduler
+	if cfg.Scheduler.Enabled {
+		var notifier imoveis.ScheduleNotifier
+		if cfg.Scheduler.NotifyCorretor && emailService != nil {
+			notifier = imoveis.NewEmailScheduleNotifier(emailService)
+		}
+		interval := time.Duration(cfg.Scheduler.IntervalSeconds) * time.Second
+		publicationScheduler = imoveis.NewPublicationScheduler(imoveisService, interval, notifier)
+	}
+
+	// Stale listings archiver: archives published imoveis not updated within their organizacao's period
+	var staleArchiver imoveis.StaleArchiver
+	if cfg.Archive.IntervalSeconds > 0 {
+		staleArchiver = imoveis.NewStaleArchiver(imoveisService, time.Duration(cfg.Archive.IntervalSeconds)*time.Second)
+	}
+
+	// Analytics module setup (price per m² materialized view)
+	analyticsRepo := analytics.NewRepository(database)
+	analyticsService := analytics.NewService(analyticsRepo)
+	analyticsHandler := analytics.NewHandler(analyticsService)
+	var analyticsRefresher analytics.Refresher
+	if cfg.Analytics.RefreshSeconds > 0 {
+		analyticsRefresher = analytics.NewRefresher(analyticsService, time.Duration(cfg.Analytics.RefreshSeconds)*time.Second)
+	}
+
+	// Maintenance module setup
+	maintenanceTasks := []maintenance.Task{
+		maintenance.NewReindexTask(database, "imoveis", "enderecos", "sliders", "slider_items"),
+		maintenance.NewAnalyzeTask(database, "imoveis", "sliders", "slider_items", "slider_item_stats"),
+		analytics.NewRefreshTask(analyticsService),
+		imoveis.NewArchiveStaleTask(imoveisService),
+	}
+	if responseCache != nil {
+		maintenanceTasks = append(maintenanceTasks, cache.NewRebuildTask(responseCache, imoveis.CacheWarmer(imoveisService), sliders.CacheWarmer(sliderService)))
+	}
+	if cfg.Sliders.PurgeAfterDays > 0 {
+		maintenanceTasks = append(maintenanceTasks, sliders.NewPurgeTask(sliderService, time.Duration(cfg.Sliders.PurgeAfterDays)*24*time.Hour))
+	}
+	maintenanceService := maintenance.NewService(maintenanceTasks...)
+	maintenanceHandler := maintenance.NewHandler(maintenanceService)
 
 	handlers := &server.Handlers{
-		User:    userHandler,
-		Sliders: slidersHandler,
-		Imoveis: imoveisHandler,
-		Email:   emailHandler,
+		User:            userHandler,
+		Sliders:         slidersHandler,
+		Imoveis:         imoveisHandler,
+		Caracteristicas: caracteristicasHandler,
+		Empreendimentos: empreendimentosHandler,
+		Corretores:      corretoresHandler,
+		Organizacoes:    organizacoesHandler,
+		Enderecos:       enderecosHandler,
+		Precos:          precosHandler,
+		Email:           emailHandler,
+		Maintenance:     maintenanceHandler,
+		Analytics:       analyticsHandler,
+		AuthAudit:       authaudit.NewHandler(auditService),
+		Leads:           leadsHandler,
+		Favoritos:       favoritos.NewHandler(favoritos.NewService(favoritos.NewRepository(database))),
+		Notifications:   notifications.NewHandler(notifications.NewPreferenceService(notificationsRepo)),
+		Queues: map[string]health.QueueDepthFunc{
+			"anexos": health.MemoryQueue(anexoProcessor.QueueDepth),
+		},
+	}
+	if eventBus != nil {
+		handlers.Queues["events"] = health.MemoryQueue(eventBus.QueueDepth)
+	}
+	if emailService != nil {
+		handlers.Queues["email_outbox"] = emailRepo.CountPending
 	}
 
 	router := server.SetupRouter(handlers, authService, cfg, database)
@@ -153,14 +359,6 @@ func run() error {
 	logger.Info("Received shutdown signal", "signal", sig)
 	logger.Info("Shutting down server gracefully...")
 
-	sqlDB, err := database.DB()
-	if err == nil {
-		logger.Info("Closing database connections...")
-		if err := sqlDB.Close(); err != nil {
-			logger.Error("Error closing database", "error", err)
-		}
-	}
-
 	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
 	if shutdownTimeout == 0 {
 		shutdownTimeout = 30 * time.Second
@@ -169,6 +367,52 @@ func run() error {
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
+	if analyticsRefresher != nil {
+		if err := analyticsRefresher.Close(ctx); err != nil {
+			logger.Warn("Analytics refresh interrupted", "error", err)
+		}
+	}
+
+	if publicationScheduler != nil {
+		if err := publicationScheduler.Close(ctx); err != nil {
+			logger.Warn("Publication scheduler interrupted", "error", err)
+		}
+	}
+
+	if staleArchiver != nil {
+		if err := staleArchiver.Close(ctx); err != nil {
+			logger.Warn("Stale listings archiver interrupted", "error", err)
+		}
+	}
+
+	// Notification handlers queue emails, drain them before the email queue stops
+	if eventBus != nil {
+		if err := eventBus.Close(ctx); err != nil {
+			logger.Warn("Event handlers interrupted", "error", err)
+		}
+	}
+
+	// The email in flight records its outcome in the database
+	if emailDispatcher != nil {
+		if err := emailDispatcher.Close(ctx); err != nil {
+			logger.Warn("Email queue interrupted", "error", err)
+		}
+	}
+
+	// Renditions are written to the database, drain them before closing it
+	logger.Info("Waiting for image processing to finish...", "pending", anexoProcessor.QueueDepth())
+	if err := anexoProcessor.Close(ctx); err != nil {
+		logger.Warn("Image processing interrupted", "error", err)
+	}
+
+	sqlDB, err := database.DB()
+	if err == nil {
+		logger.Info("Closing database connections...")
+		if err := sqlDB.Close(); err != nil {
+			logger.Error("Error closing database", "error", err)
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 		return err
@@ -178,22 +422,26 @@ func run() error {
 	return nil
 }
 
+// checkTenancyMode refuses the schema and database tenancy modes: cmd/migrate provisions and
+// migrates the tenants, but the API serves every organizacao from the shared database (isolated
+// by db.TenantScope) and never routes requests to them
+func checkTenancyMode(cfg *config.DatabaseConfig) error {
+	if cfg.TenancyMode != "" && cfg.TenancyMode != db.TenancyNone {
+		return fmt.Errorf("database.tenancy_mode %q is not supported by the API server, only %q", cfg.TenancyMode, db.TenancyNone)
+	}
+	return nil
+}
+
 func checkMigrationStatus(database *gorm.DB, cfg *config.MigrationsConfig) error {
 	sqlDB, err := database.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
-	migrator, err := migrate.New(sqlDB, migrate.Config{
-		MigrationsDir: cfg.Directory,
-		Timeout:       time.Duration(cfg.Timeout) * time.Second,
-		LockTimeout:   time.Duration(cfg.LockTimeout) * time.Second,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	version, dirty, err := migrator.Version()
+	version, dirty, err := migrate.CurrentVersion(ctx, sqlDB)
 	if err != nil {
 		return fmt.Errorf("failed to get migration version: %w", err)
 	}
@@ -202,6 +450,14 @@ func checkMigrationStatus(database *gorm.DB, cfg *config.MigrationsConfig) error
 		return fmt.Errorf("database in dirty state at version %d", version)
 	}
 
+	pending, err := migrate.PendingVersions(cfg.Directory, version)
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database at version %d has %d pending migration(s), latest is %d", version, len(pending), pending[len(pending)-1])
+	}
+
 	slog.Info("Database schema", "version", version)
 	return nil
 }
@@ -8,6 +8,8 @@ import (
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
 )
 
 func TestRun_ConfigLoadError(t *testing.T) {
@@ -408,3 +410,16 @@ func TestHTTPServer_MaxHeaderBytes(t *testing.T) {
 		t.Error("server shutdown timed out")
 	}
 }
+
+func TestCheckTenancyMode(t *testing.T) {
+	for _, mode := range []string{"", "none"} {
+		if err := checkTenancyMode(&config.DatabaseConfig{TenancyMode: mode}); err != nil {
+			t.Errorf("mode %q: unexpected error %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"schema", "database"} {
+		if err := checkTenancyMode(&config.DatabaseConfig{TenancyMode: mode}); err == nil {
+			t.Errorf("mode %q: expected the server to refuse it", mode)
+		}
+	}
+}
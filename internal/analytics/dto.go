@@ -0,0 +1,43 @@
+package analytics
+
+import "time"
+
+// PrecoM2Query represents query parameters for the price per m² analytics. Cidade and bairro match
+// case-insensitively; objetivo selects sale (default) or rental prices.
+type PrecoM2Query struct {
+	Cidade   string `form:"cidade" binding:"omitempty,max=100"`
+	Bairro   string `form:"bairro" binding:"omitempty,max=100"`
+	Tipo     string `form:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
+	Objetivo string `form:"objetivo,default=VENDER" binding:"oneof=VENDER ALUGAR"`
+}
+
+// PrecoM2Summary represents the price per m² statistics of a group of properties
+type PrecoM2Summary struct {
+	Total   int64   `json:"total"`
+	Media   float64 `json:"media"`
+	Mediana float64 `json:"mediana"`
+	Minimo  float64 `json:"minimo"`
+	Maximo  float64 `json:"maximo"`
+}
+
+// TipoPrecoM2Response represents the price per m² of a tipo within a bairro
+type TipoPrecoM2Response struct {
+	Tipo string `json:"tipo"`
+	PrecoM2Summary
+}
+
+// BairroPrecoM2Response represents the price per m² of a bairro, overall and per tipo
+type BairroPrecoM2Response struct {
+	Cidade string `json:"cidade"`
+	Bairro string `json:"bairro"`
+	PrecoM2Summary
+	Tipos []TipoPrecoM2Response `json:"tipos"`
+}
+
+// PrecoM2Response represents the price per m² analytics. AtualizadoEm is when the statistics were
+// last computed; they lag behind property changes until the next refresh.
+type PrecoM2Response struct {
+	Objetivo     string                  `json:"objetivo"`
+	AtualizadoEm *time.Time              `json:"atualizadoEm,omitempty"`
+	Bairros      []BairroPrecoM2Response `json:"bairros"`
+}
@@ -0,0 +1,47 @@
+package analytics
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for market analytics
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new analytics handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// @Summary Price per m² by bairro
+// @Description Average and median price per m² of published properties per bairro, overall and per tipo. Statistics are recomputed periodically, see atualizadoEm.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param cidade query string false "City name (case-insensitive)"
+// @Param bairro query string false "Neighborhood name (case-insensitive)"
+// @Param tipo query string false "Property type (APARTAMENTO, CASA, COMERCIAL, SALA_COMERCIAL, TERRENO, GALPAO)"
+// @Param objetivo query string false "Sale or rental prices (VENDER, ALUGAR)" default(VENDER)
+// @Success 200 {object} errors.Response{success=bool,data=PrecoM2Response}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/analytics/preco-m2 [get]
+func (h *Handler) GetPrecoM2(c *gin.Context) {
+	var query PrecoM2Query
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	stats, err := h.service.GetPrecoM2(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(stats))
+}
@@ -0,0 +1,23 @@
+package analytics
+
+import "time"
+
+// PrecoM2Stat is a row of the imovel_preco_m2_stats materialized view: the price per m² of the
+// published imoveis of a bairro. An empty Tipo aggregates every tipo of the bairro.
+type PrecoM2Stat struct {
+	Cidade       string    `gorm:"primaryKey"`
+	Bairro       string    `gorm:"primaryKey"`
+	Tipo         string    `gorm:"primaryKey"`
+	Objetivo     string    `gorm:"primaryKey"`
+	Total        int64     `gorm:"column:total"`
+	Media        float64   `gorm:"column:media"`
+	Mediana      float64   `gorm:"column:mediana"`
+	Minimo       float64   `gorm:"column:minimo"`
+	Maximo       float64   `gorm:"column:maximo"`
+	AtualizadoEm time.Time `gorm:"column:atualizado_em"`
+}
+
+// TableName specifies the materialized view name
+func (PrecoM2Stat) TableName() string {
+	return "imovel_preco_m2_stats"
+}
@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+// Refresher recomputes the analytics statistics periodically in the background
+type Refresher interface {
+	// Close stops the refresher and waits for the current refresh until ctx is done
+	Close(ctx context.Context) error
+}
+
+type refresher struct {
+	service  Service
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewRefresher starts refreshing the statistics every interval. The view is populated when it is
+// created, so the first refresh happens after one interval.
+func NewRefresher(service Service, interval time.Duration) Refresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &refresher{
+		service:  service,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go r.loop(ctx)
+	return r
+}
+
+func (r *refresher) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		if err := r.service.RefreshPrecoM2(ctx); err != nil {
+			slog.Error("Analytics refresh failed", "error", err)
+			continue
+		}
+		slog.Info("Analytics refreshed", "duration", time.Since(start))
+	}
+}
+
+// Close cancels the current refresh and waits for the loop to exit until ctx is done
+func (r *refresher) Close(ctx context.Context) error {
+	r.once.Do(r.cancel)
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewRefreshTask exposes the statistics refresh as the preco_m2 maintenance task
+func NewRefreshTask(service Service) maintenance.Task {
+	return maintenance.TaskFunc{
+		TaskName: "preco_m2",
+		Fn: func(ctx context.Context, report maintenance.ProgressFunc) error {
+			if err := service.RefreshPrecoM2(ctx); err != nil {
+				return err
+			}
+			report(1, 1)
+			return nil
+		},
+	}
+}
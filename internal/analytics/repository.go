@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Repository defines analytics repository interface
+type Repository interface {
+	ListPrecoM2(ctx context.Context, query *PrecoM2Query) ([]PrecoM2Stat, error)
+	RefreshPrecoM2(ctx context.Context) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new analytics repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ListPrecoM2 reads the price per m² rows matching the query, ordered by cidade, bairro and tipo.
+// When a tipo is requested the bairro totals are still returned.
+func (r *repository) ListPrecoM2(ctx context.Context, query *PrecoM2Query) ([]PrecoM2Stat, error) {
+	db := r.db.WithContext(ctx).Where("objetivo = ?", query.Objetivo)
+	if cidade := strings.TrimSpace(query.Cidade); cidade != "" {
+		db = db.Where("LOWER(cidade) = LOWER(?)", cidade)
+	}
+	if bairro := strings.TrimSpace(query.Bairro); bairro != "" {
+		db = db.Where("LOWER(bairro) = LOWER(?)", bairro)
+	}
+	if query.Tipo != "" {
+		db = db.Where("tipo IN ?", []string{"", query.Tipo})
+	}
+
+	var stats []PrecoM2Stat
+	if err := db.Order("cidade").Order("bairro").Order("tipo").Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RefreshPrecoM2 recomputes the materialized view without blocking readers
+func (r *repository) RefreshPrecoM2(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY imovel_preco_m2_stats").Error
+}
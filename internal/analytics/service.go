@@ -0,0 +1,82 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Service defines analytics service interface
+type Service interface {
+	GetPrecoM2(ctx context.Context, query *PrecoM2Query) (*PrecoM2Response, error)
+	RefreshPrecoM2(ctx context.Context) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new analytics service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// GetPrecoM2 returns the price per m² per bairro, with a breakdown per tipo
+func (s *service) GetPrecoM2(ctx context.Context, query *PrecoM2Query) (*PrecoM2Response, error) {
+	stats, err := s.repo.ListPrecoM2(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price per m² statistics: %w", err)
+	}
+
+	response := &PrecoM2Response{Objetivo: query.Objetivo, Bairros: []BairroPrecoM2Response{}}
+	index := map[[2]string]int{}
+	for _, stat := range stats {
+		key := [2]string{stat.Cidade, stat.Bairro}
+		i, ok := index[key]
+		if !ok {
+			i = len(response.Bairros)
+			index[key] = i
+			response.Bairros = append(response.Bairros, BairroPrecoM2Response{
+				Cidade: stat.Cidade,
+				Bairro: stat.Bairro,
+				Tipos:  []TipoPrecoM2Response{},
+			})
+		}
+
+		if stat.Tipo == "" {
+			response.Bairros[i].PrecoM2Summary = toSummary(stat)
+		} else {
+			response.Bairros[i].Tipos = append(response.Bairros[i].Tipos, TipoPrecoM2Response{Tipo: stat.Tipo, PrecoM2Summary: toSummary(stat)})
+		}
+
+		if response.AtualizadoEm == nil || stat.AtualizadoEm.After(*response.AtualizadoEm) {
+			atualizadoEm := stat.AtualizadoEm
+			response.AtualizadoEm = &atualizadoEm
+		}
+	}
+
+	return response, nil
+}
+
+// RefreshPrecoM2 recomputes the price per m² statistics from the current published properties
+func (s *service) RefreshPrecoM2(ctx context.Context) error {
+	if err := s.repo.RefreshPrecoM2(ctx); err != nil {
+		return fmt.Errorf("failed to refresh price per m² statistics: %w", err)
+	}
+	return nil
+}
+
+func toSummary(stat PrecoM2Stat) PrecoM2Summary {
+	return PrecoM2Summary{
+		Total:   stat.Total,
+		Media:   roundMoney(stat.Media),
+		Mediana: roundMoney(stat.Mediana),
+		Minimo:  roundMoney(stat.Minimo),
+		Maximo:  roundMoney(stat.Maximo),
+	}
+}
+
+// roundMoney rounds a value to cents
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,90 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRepository struct {
+	stats     []PrecoM2Stat
+	query     *PrecoM2Query
+	refreshed int
+	err       error
+}
+
+func (r *stubRepository) ListPrecoM2(_ context.Context, query *PrecoM2Query) ([]PrecoM2Stat, error) {
+	r.query = query
+	return r.stats, r.err
+}
+
+func (r *stubRepository) RefreshPrecoM2(_ context.Context) error {
+	r.refreshed++
+	return r.err
+}
+
+func TestGetPrecoM2(t *testing.T) {
+	earlier := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	repo := &stubRepository{stats: []PrecoM2Stat{
+		{Cidade: "Curitiba", Bairro: "Batel", Tipo: "", Objetivo: "VENDER", Total: 3, Media: 12000.456, Mediana: 11500, Minimo: 9000, Maximo: 15500, AtualizadoEm: earlier},
+		{Cidade: "Curitiba", Bairro: "Batel", Tipo: "APARTAMENTO", Objetivo: "VENDER", Total: 2, Media: 11250, Mediana: 11250, Minimo: 9000, Maximo: 13500, AtualizadoEm: earlier},
+		{Cidade: "Curitiba", Bairro: "Batel", Tipo: "CASA", Objetivo: "VENDER", Total: 1, Media: 15500, Mediana: 15500, Minimo: 15500, Maximo: 15500, AtualizadoEm: earlier},
+		{Cidade: "Curitiba", Bairro: "Centro", Tipo: "", Objetivo: "VENDER", Total: 1, Media: 8000, Mediana: 8000, Minimo: 8000, Maximo: 8000, AtualizadoEm: later},
+	}}
+	svc := NewService(repo)
+
+	query := &PrecoM2Query{Cidade: "curitiba", Objetivo: "VENDER"}
+	response, err := svc.GetPrecoM2(context.Background(), query)
+	require.NoError(t, err)
+	assert.Same(t, query, repo.query)
+
+	assert.Equal(t, "VENDER", response.Objetivo)
+	require.NotNil(t, response.AtualizadoEm)
+	assert.Equal(t, later, *response.AtualizadoEm)
+
+	require.Len(t, response.Bairros, 2)
+	batel := response.Bairros[0]
+	assert.Equal(t, "Batel", batel.Bairro)
+	assert.Equal(t, int64(3), batel.Total)
+	assert.Equal(t, 12000.46, batel.Media)
+	assert.Equal(t, 11500.0, batel.Mediana)
+	require.Len(t, batel.Tipos, 2)
+	assert.Equal(t, "APARTAMENTO", batel.Tipos[0].Tipo)
+	assert.Equal(t, int64(2), batel.Tipos[0].Total)
+
+	centro := response.Bairros[1]
+	assert.Equal(t, "Centro", centro.Bairro)
+	assert.Empty(t, centro.Tipos)
+	assert.NotNil(t, centro.Tipos, "serialized as an empty list")
+}
+
+func TestGetPrecoM2_Empty(t *testing.T) {
+	svc := NewService(&stubRepository{})
+
+	response, err := svc.GetPrecoM2(context.Background(), &PrecoM2Query{Objetivo: "ALUGAR"})
+	require.NoError(t, err)
+	assert.Nil(t, response.AtualizadoEm)
+	assert.NotNil(t, response.Bairros)
+	assert.Empty(t, response.Bairros)
+}
+
+func TestRefreshPrecoM2(t *testing.T) {
+	repo := &stubRepository{}
+	svc := NewService(repo)
+
+	task := NewRefreshTask(svc)
+	assert.Equal(t, "preco_m2", task.Name())
+	var done, total int
+	require.NoError(t, task.Run(context.Background(), func(d, t int) { done, total = d, t }))
+	assert.Equal(t, 1, repo.refreshed)
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 1, total)
+
+	repo.err = errors.New("boom")
+	assert.ErrorContains(t, svc.RefreshPrecoM2(context.Background()), "boom")
+}
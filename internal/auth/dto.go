@@ -2,10 +2,14 @@ package auth
 
 // Claims represents JWT token claims
 type Claims struct {
-	UserID uint     `json:"user_id"`
-	Email  string   `json:"email"`
-	Name   string   `json:"name"`
-	Roles  []string `json:"roles"`
+	UserID        uint     `json:"user_id"`
+	Email         string   `json:"email"`
+	Name          string   `json:"name"`
+	Roles         []string `json:"roles"`
+	CorretorID    uint     `json:"corretor_id,omitempty"`    // set for users scoped to their corretor principal
+	OrganizacaoID uint     `json:"organizacao_id,omitempty"` // set for users that belong to an organizacao
+	SessionID     string   `json:"session_id,omitempty"`     // refresh token family the token was issued for
+	TwoFactor     bool     `json:"two_factor,omitempty"`     // the session was signed in with a TOTP or recovery code
 }
 
 // TokenResponse represents token response (deprecated: use TokenPairResponse)
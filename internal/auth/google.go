@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+const (
+	// ProviderGoogle identifies the Google accounts linked to users
+	ProviderGoogle = "google"
+	// googleUserInfoURL is the OpenID Connect userinfo endpoint of Google
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+	// googleTimeout bounds the code exchange and the userinfo request
+	googleTimeout = 10 * time.Second
+)
+
+var (
+	// ErrOAuthEmailNotVerified is returned when the provider has not verified the account's email
+	ErrOAuthEmailNotVerified = errors.New("email not verified by the identity provider")
+	// ErrOAuthDomainNotAllowed is returned when the account's email is outside the allowed domains
+	ErrOAuthDomainNotAllowed = errors.New("email domain not allowed")
+)
+
+// OAuthProfile is the account of a user at an external identity provider
+type OAuthProfile struct {
+	Provider string
+	Subject  string
+	Email    string
+	Name     string
+}
+
+// GoogleOAuth runs the OAuth2 authorization-code flow (with PKCE) of the Google sign-in
+type GoogleOAuth struct {
+	config         *oauth2.Config
+	userInfoURL    string
+	allowedDomains []string
+	client         *http.Client
+}
+
+// NewGoogleOAuth creates the Google sign-in of cfg, or returns nil when no client ID is configured
+func NewGoogleOAuth(cfg *config.GoogleOAuthConfig) *GoogleOAuth {
+	if cfg.ClientID == "" {
+		return nil
+	}
+	domains := make([]string, 0, len(cfg.AllowedDomains))
+	for _, domain := range cfg.AllowedDomains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return &GoogleOAuth{
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     endpoints.Google,
+			Scopes:       []string{"openid", "email", "profile"},
+		},
+		userInfoURL:    googleUserInfoURL,
+		allowedDomains: domains,
+		client:         &http.Client{Timeout: googleTimeout},
+	}
+}
+
+// AuthCodeURL returns the Google consent page URL carrying state and the challenge of verifier
+func (g *GoogleOAuth) AuthCodeURL(state, verifier string) string {
+	return g.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange trades the code of the callback for the Google account it was issued for. Accounts
+// whose email is not verified, or outside the allowed domains, are refused.
+func (g *GoogleOAuth) Exchange(ctx context.Context, code, verifier string) (*OAuthProfile, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, g.client)
+	token, err := g.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.config.Client(ctx, token).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch userinfo: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch userinfo: status %d", resp.StatusCode)
+	}
+
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode userinfo: %w", err)
+	}
+	if info.Sub == "" || info.Email == "" || !info.EmailVerified {
+		return nil, ErrOAuthEmailNotVerified
+	}
+	if !g.domainAllowed(info.Email) {
+		return nil, ErrOAuthDomainNotAllowed
+	}
+
+	return &OAuthProfile{Provider: ProviderGoogle, Subject: info.Sub, Email: info.Email, Name: info.Name}, nil
+}
+
+func (g *GoogleOAuth) domainAllowed(email string) bool {
+	if len(g.allowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range g.allowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
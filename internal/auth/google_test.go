@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// newTestGoogle returns a GoogleOAuth whose token and userinfo endpoints are served by a test
+// server answering with userinfo
+func newTestGoogle(t *testing.T, allowedDomains []string, userinfo map[string]interface{}) *GoogleOAuth {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "code-1", r.PostForm.Get("code"))
+		assert.Equal(t, "verifier-1", r.PostForm.Get("code_verifier"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"google-access","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer google-access", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(userinfo)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	google := NewGoogleOAuth(&config.GoogleOAuthConfig{
+		ClientID:       "client",
+		ClientSecret:   "secret",
+		RedirectURL:    "https://api.example.com/api/v1/auth/google/callback",
+		AllowedDomains: allowedDomains,
+	})
+	google.config.Endpoint.TokenURL = server.URL + "/token"
+	google.userInfoURL = server.URL + "/userinfo"
+	return google
+}
+
+func TestNewGoogleOAuth(t *testing.T) {
+	assert.Nil(t, NewGoogleOAuth(&config.GoogleOAuthConfig{}), "disabled without a client ID")
+
+	google := NewGoogleOAuth(&config.GoogleOAuthConfig{ClientID: "client", RedirectURL: "https://api.example.com/cb"})
+	authURL, err := url.Parse(google.AuthCodeURL("state-1", "verifier-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.google.com", authURL.Host)
+	assert.Equal(t, "state-1", authURL.Query().Get("state"))
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	assert.NotEmpty(t, authURL.Query().Get("code_challenge"))
+	assert.NotContains(t, authURL.RawQuery, "verifier-1")
+}
+
+func TestGoogleOAuth_Exchange(t *testing.T) {
+	verified := map[string]interface{}{"sub": "g-1", "email": "ana@triiio.com.br", "email_verified": true, "name": "Ana"}
+
+	t.Run("verified account", func(t *testing.T) {
+		profile, err := newTestGoogle(t, nil, verified).Exchange(context.Background(), "code-1", "verifier-1")
+		require.NoError(t, err)
+		assert.Equal(t, &OAuthProfile{Provider: ProviderGoogle, Subject: "g-1", Email: "ana@triiio.com.br", Name: "Ana"}, profile)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		unverified := map[string]interface{}{"sub": "g-1", "email": "ana@triiio.com.br", "email_verified": false}
+		_, err := newTestGoogle(t, nil, unverified).Exchange(context.Background(), "code-1", "verifier-1")
+		assert.ErrorIs(t, err, ErrOAuthEmailNotVerified)
+	})
+
+	t.Run("allowed domain", func(t *testing.T) {
+		_, err := newTestGoogle(t, []string{" Triiio.com.br "}, verified).Exchange(context.Background(), "code-1", "verifier-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("domain not allowed", func(t *testing.T) {
+		_, err := newTestGoogle(t, []string{"example.com"}, verified).Exchange(context.Background(), "code-1", "verifier-1")
+		assert.ErrorIs(t, err, ErrOAuthDomainNotAllowed)
+	})
+}
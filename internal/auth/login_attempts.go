@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Attempt stores selected by login.store
+const (
+	AttemptStoreMemory   = "memory"
+	AttemptStoreDatabase = "database"
+)
+
+// LoginAttempt is a failed login (or two-factor code) of a key, kept by the database store
+type LoginAttempt struct {
+	ID          uint      `gorm:"primaryKey"`
+	AttemptKey  string    `gorm:"type:varchar(300);not null;index"`
+	AttemptedAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for LoginAttempt
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginLockout is the lockout of a key, kept by the database store
+type LoginLockout struct {
+	AttemptKey  string    `gorm:"type:varchar(300);primaryKey"`
+	LockedUntil time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for LoginLockout
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
+
+// databaseAttemptStore keeps the attempts in the database shared by every instance, so the limits
+// hold for the whole deployment and survive restarts. Rows older than ttl are pruned as failures
+// and lockouts are recorded.
+type databaseAttemptStore struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewDatabaseAttemptStore creates a store shared by the instances using db. ttl must cover the
+// window, the lockout and auth.TwoFactorChallengeTTL.
+func NewDatabaseAttemptStore(db *gorm.DB, ttl time.Duration) AttemptStore {
+	return &databaseAttemptStore{db: db, ttl: ttl}
+}
+
+func (s *databaseAttemptStore) AddFailure(ctx context.Context, key string, t time.Time, window time.Duration) (int, error) {
+	t = t.UTC()
+	db := s.db.WithContext(ctx)
+	if err := db.Create(&LoginAttempt{AttemptKey: key, AttemptedAt: t}).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Where("attempted_at < ?", t.Add(-s.ttl)).Delete(&LoginAttempt{}).Error; err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Model(&LoginAttempt{}).
+		Where("attempt_key = ? AND attempted_at > ? AND attempted_at <= ?", key, t.Add(-window), t).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (s *databaseAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attempt_key = ?", key).Delete(&LoginAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("locked_until < ?", time.Now().UTC().Add(-s.ttl)).Delete(&LoginLockout{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "attempt_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"locked_until"}),
+		}).Create(&LoginLockout{AttemptKey: key, LockedUntil: until.UTC()}).Error
+	})
+}
+
+func (s *databaseAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var lockout LoginLockout
+	err := s.db.WithContext(ctx).Where("attempt_key = ?", key).First(&lockout).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return lockout.LockedUntil, nil
+}
+
+func (s *databaseAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("attempt_key = ?", key).Delete(&LoginAttempt{}).Error
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func setupAttemptDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&LoginAttempt{}, &LoginLockout{}))
+	return db
+}
+
+func TestDatabaseAttemptStore_SlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	store := NewDatabaseAttemptStore(setupAttemptDB(t), time.Hour)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	count, err := store.AddFailure(ctx, "login:account:ana@example.com", now, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = store.AddFailure(ctx, "login:account:ana@example.com", now.Add(5*time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = store.AddFailure(ctx, "login:account:bia@example.com", now.Add(5*time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "keys are counted apart")
+
+	count, err = store.AddFailure(ctx, "login:account:ana@example.com", now.Add(11*time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "the first failure left the window")
+
+	require.NoError(t, store.Reset(ctx, "login:account:ana@example.com"))
+	count, err = store.AddFailure(ctx, "login:account:ana@example.com", now.Add(12*time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestDatabaseAttemptStore_Lock(t *testing.T) {
+	ctx := context.Background()
+	store := NewDatabaseAttemptStore(setupAttemptDB(t), time.Hour)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	until, err := store.LockedUntil(ctx, "login:ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+
+	_, err = store.AddFailure(ctx, "login:ip:10.0.0.1", now, 10*time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Lock(ctx, "login:ip:10.0.0.1", now.Add(15*time.Minute)))
+	require.NoError(t, store.Lock(ctx, "login:ip:10.0.0.1", now.Add(20*time.Minute)), "locking again extends the lockout")
+
+	until, err = store.LockedUntil(ctx, "login:ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, now.Add(20*time.Minute).Equal(until))
+
+	count, err := store.AddFailure(ctx, "login:ip:10.0.0.1", now.Add(time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "a lockout forgets the failures")
+}
+
+func TestDatabaseAttemptStore_SharedByLimiters(t *testing.T) {
+	ctx := context.Background()
+	db := setupAttemptDB(t)
+	cfg := &config.LoginConfig{Window: 10 * time.Minute, MaxAccountAttempts: 3, Lockout: 15 * time.Minute}
+	// Two replicas of the API using the same database
+	first := NewLoginLimiter(cfg, NewDatabaseAttemptStore(db, time.Hour))
+	second := NewLoginLimiter(cfg, NewDatabaseAttemptStore(db, time.Hour))
+
+	_, err := first.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	_, err = second.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	failure, err := first.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, failure.AccountFailures)
+	assert.False(t, failure.AccountLockedUntil.IsZero())
+
+	assert.ErrorIs(t, second.Check(ctx, "ana@example.com", ""), ErrLoginLocked, "the lockout holds on every replica")
+}
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// DefaultAttemptStoreSize bounds the accounts and addresses tracked by the memory store
+const DefaultAttemptStoreSize = 10000
+
+var (
+	// ErrLoginLocked is returned while an account or address is locked out after too many failed logins
+	ErrLoginLocked = errors.New("too many failed login attempts")
+	// ErrChallengeExhausted is returned for a two-factor challenge that received too many wrong codes
+	ErrChallengeExhausted = errors.New("too many invalid two-factor codes")
+)
+
+// LockoutError tells how long a lockout lasts; errors.Is(err, ErrLoginLocked) matches it
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string { return ErrLoginLocked.Error() }
+
+func (e *LockoutError) Unwrap() error { return ErrLoginLocked }
+
+// AttemptStore keeps the failed logins of a key (an account or an address) over a sliding window,
+// and its lockout. The memory store is per instance: each replica allows the limits on its own and
+// forgets them on restart. The database store is shared by the instances, so the limits hold for
+// the whole deployment.
+type AttemptStore interface {
+	// AddFailure records a failure of key at t and returns the failures of key within window up to t
+	AddFailure(ctx context.Context, key string, t time.Time, window time.Duration) (int, error)
+	// Lock locks key out until until, forgetting its failures
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns the end of the lockout of key, zero when it was never locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset forgets the failures of key
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginFailure is the outcome of a failed login recorded by the limiter
+type LoginFailure struct {
+	// AccountFailures and IPFailures count the failures within the window, this one included
+	AccountFailures int
+	IPFailures      int
+	// AccountLockedUntil and IPLockedUntil are set when this failure locked the account or address out
+	AccountLockedUntil time.Time
+	IPLockedUntil      time.Time
+}
+
+// LoginLimiter protects the login against brute force: failed logins are counted per account and
+// per address, and reaching a limit locks the account or address out for a while
+type LoginLimiter struct {
+	store                AttemptStore
+	window               time.Duration
+	maxAccountAttempts   int
+	maxIPAttempts        int
+	maxChallengeAttempts int
+	lockout              time.Duration
+	now                  func() time.Time
+}
+
+// NewLoginLimiter creates a limiter with the limits of cfg, keeping the attempts in store
+func NewLoginLimiter(cfg *config.LoginConfig, store AttemptStore) *LoginLimiter {
+	return &LoginLimiter{
+		store:                store,
+		window:               cfg.Window,
+		maxAccountAttempts:   cfg.MaxAccountAttempts,
+		maxIPAttempts:        cfg.MaxIPAttempts,
+		maxChallengeAttempts: cfg.MaxTwoFactorAttempts,
+		lockout:              cfg.Lockout,
+		now:                  time.Now,
+	}
+}
+
+// Check returns a *LockoutError when the account or the address is locked out
+func (l *LoginLimiter) Check(ctx context.Context, account, ip string) error {
+	now := l.now()
+	var until time.Time
+	for _, key := range l.keys(account, ip) {
+		lockedUntil, err := l.store.LockedUntil(ctx, key)
+		if err != nil {
+			return err
+		}
+		if lockedUntil.After(until) {
+			until = lockedUntil
+		}
+	}
+	if until.After(now) {
+		return &LockoutError{RetryAfter: until.Sub(now)}
+	}
+	return nil
+}
+
+// Fail records a failed login of account from ip, locking either out when it reaches its limit
+func (l *LoginLimiter) Fail(ctx context.Context, account, ip string) (LoginFailure, error) {
+	now := l.now()
+	var failure LoginFailure
+	var err error
+
+	if l.maxAccountAttempts > 0 {
+		key := accountKey(account)
+		if failure.AccountFailures, err = l.store.AddFailure(ctx, key, now, l.window); err != nil {
+			return failure, err
+		}
+		if failure.AccountFailures >= l.maxAccountAttempts {
+			failure.AccountLockedUntil = now.Add(l.lockout)
+			if err := l.store.Lock(ctx, key, failure.AccountLockedUntil); err != nil {
+				return failure, err
+			}
+		}
+	}
+	if l.maxIPAttempts > 0 && ip != "" {
+		key := ipKey(ip)
+		if failure.IPFailures, err = l.store.AddFailure(ctx, key, now, l.window); err != nil {
+			return failure, err
+		}
+		if failure.IPFailures >= l.maxIPAttempts {
+			failure.IPLockedUntil = now.Add(l.lockout)
+			if err := l.store.Lock(ctx, key, failure.IPLockedUntil); err != nil {
+				return failure, err
+			}
+		}
+	}
+	return failure, nil
+}
+
+// Succeed forgets the failed logins of account; those of the address still count, so that one
+// valid account does not reset the attempts made on others
+func (l *LoginLimiter) Succeed(ctx context.Context, account string) error {
+	return l.store.Reset(ctx, accountKey(account))
+}
+
+// CheckChallenge returns ErrChallengeExhausted when the two-factor challenge token was invalidated
+// by FailChallenge
+func (l *LoginLimiter) CheckChallenge(ctx context.Context, challenge string) error {
+	lockedUntil, err := l.store.LockedUntil(ctx, challengeKey(challenge))
+	if err != nil {
+		return err
+	}
+	if lockedUntil.After(l.now()) {
+		return ErrChallengeExhausted
+	}
+	return nil
+}
+
+// FailChallenge records a wrong code sent for a two-factor challenge token and reports whether it
+// reached its limit, which invalidates the challenge until it expires
+func (l *LoginLimiter) FailChallenge(ctx context.Context, challenge string) (bool, error) {
+	if l.maxChallengeAttempts <= 0 {
+		return false, nil
+	}
+	now := l.now()
+	key := challengeKey(challenge)
+	failures, err := l.store.AddFailure(ctx, key, now, TwoFactorChallengeTTL)
+	if err != nil {
+		return false, err
+	}
+	if failures < l.maxChallengeAttempts {
+		return false, nil
+	}
+	return true, l.store.Lock(ctx, key, now.Add(TwoFactorChallengeTTL))
+}
+
+func (l *LoginLimiter) keys(account, ip string) []string {
+	keys := []string{accountKey(account)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+func accountKey(account string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// challengeKey identifies a challenge token by its hash, so the store does not hold usable tokens
+func challengeKey(challenge string) string {
+	sum := sha256.Sum256([]byte(challenge))
+	return "login:2fa:" + hex.EncodeToString(sum[:])
+}
+
+type attempts struct {
+	failures    []time.Time
+	lockedUntil time.Time
+}
+
+// memoryAttemptStore keeps the attempts in an LRU, so that the tracked accounts and addresses are
+// bounded; entries not touched for ttl are dropped
+type memoryAttemptStore struct {
+	mu      sync.Mutex
+	entries *expirable.LRU[string, *attempts]
+}
+
+// NewMemoryAttemptStore creates a per-instance store tracking up to size keys. ttl must cover the
+// window and the lockout.
+func NewMemoryAttemptStore(size int, ttl time.Duration) AttemptStore {
+	return &memoryAttemptStore{entries: expirable.NewLRU[string, *attempts](size, nil, ttl)}
+}
+
+func (s *memoryAttemptStore) AddFailure(_ context.Context, key string, t time.Time, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries.Get(key)
+	if !ok {
+		entry = &attempts{}
+	}
+	start := t.Add(-window)
+	kept := entry.failures[:0]
+	for _, failure := range entry.failures {
+		if failure.After(start) {
+			kept = append(kept, failure)
+		}
+	}
+	entry.failures = append(kept, t)
+	s.entries.Add(key, entry)
+	return len(entry.failures), nil
+}
+
+func (s *memoryAttemptStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.Add(key, &attempts{lockedUntil: until})
+	return nil
+}
+
+func (s *memoryAttemptStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries.Get(key); ok {
+		return entry.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *memoryAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.Remove(key)
+	return nil
+}
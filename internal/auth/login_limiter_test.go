@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func newTestLoginLimiter(now *time.Time) *LoginLimiter {
+	limiter := NewLoginLimiter(&config.LoginConfig{
+		Window:               10 * time.Minute,
+		MaxAccountAttempts:   3,
+		MaxIPAttempts:        5,
+		MaxTwoFactorAttempts: 2,
+		Lockout:              15 * time.Minute,
+	}, NewMemoryAttemptStore(100, time.Hour))
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestLoginLimiter_LocksAccount(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	limiter := newTestLoginLimiter(&now)
+
+	for i := 1; i <= 2; i++ {
+		failure, err := limiter.Fail(ctx, "Ana@example.com", "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, i, failure.AccountFailures)
+		assert.True(t, failure.AccountLockedUntil.IsZero())
+	}
+	require.NoError(t, limiter.Check(ctx, "ana@example.com", "10.0.0.1"))
+
+	failure, err := limiter.Fail(ctx, "ana@example.com", "10.0.0.2")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), failure.AccountLockedUntil, "accounts are matched ignoring case")
+
+	now = now.Add(5 * time.Minute)
+	err = limiter.Check(ctx, "ana@example.com", "10.0.0.3")
+	var locked *LockoutError
+	require.ErrorAs(t, err, &locked)
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.Equal(t, 10*time.Minute, locked.RetryAfter)
+	assert.NoError(t, limiter.Check(ctx, "bia@example.com", "10.0.0.3"), "other accounts are not locked")
+
+	now = now.Add(10 * time.Minute)
+	assert.NoError(t, limiter.Check(ctx, "ana@example.com", "10.0.0.3"), "the lockout ends")
+}
+
+func TestLoginLimiter_SlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	limiter := newTestLoginLimiter(&now)
+
+	_, err := limiter.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	now = now.Add(6 * time.Minute)
+	_, err = limiter.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	now = now.Add(5 * time.Minute)
+
+	failure, err := limiter.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, failure.AccountFailures, "failures older than the window no longer count")
+	assert.True(t, failure.AccountLockedUntil.IsZero())
+
+	require.NoError(t, limiter.Succeed(ctx, "ana@example.com"))
+	failure, err = limiter.Fail(ctx, "ana@example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, failure.AccountFailures, "a successful login clears the failures")
+}
+
+func TestLoginLimiter_LocksAddress(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	limiter := newTestLoginLimiter(&now)
+
+	var failure LoginFailure
+	var err error
+	for _, account := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		failure, err = limiter.Fail(ctx, account, "10.0.0.1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 5, failure.IPFailures)
+	assert.False(t, failure.IPLockedUntil.IsZero(), "credential stuffing over many accounts locks the address")
+
+	assert.ErrorIs(t, limiter.Check(ctx, "f@example.com", "10.0.0.1"), ErrLoginLocked)
+	assert.NoError(t, limiter.Check(ctx, "f@example.com", "10.0.0.2"))
+}
+
+func TestLoginLimiter_InvalidatesChallenge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	limiter := newTestLoginLimiter(&now)
+
+	exhausted, err := limiter.FailChallenge(ctx, "challenge-a")
+	require.NoError(t, err)
+	assert.False(t, exhausted)
+	require.NoError(t, limiter.CheckChallenge(ctx, "challenge-a"))
+
+	exhausted, err = limiter.FailChallenge(ctx, "challenge-a")
+	require.NoError(t, err)
+	assert.True(t, exhausted)
+	assert.ErrorIs(t, limiter.CheckChallenge(ctx, "challenge-a"), ErrChallengeExhausted)
+	assert.NoError(t, limiter.CheckChallenge(ctx, "challenge-b"), "other challenges are still valid")
+	assert.NoError(t, limiter.Check(ctx, "ana@example.com", ""), "challenge failures are not account failures")
+
+	now = now.Add(TwoFactorChallengeTTL)
+	assert.NoError(t, limiter.CheckChallenge(ctx, "challenge-a"), "the challenge token has expired by then")
+}
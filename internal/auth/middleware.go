@@ -55,6 +55,20 @@ func AuthMiddleware(authService Service) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware sets the JWT claims like AuthMiddleware when an authorization header is
+// sent, and lets anonymous requests through. A header that is malformed or carries an invalid token
+// is still refused, so clients are not silently downgraded to anonymous.
+func OptionalAuthMiddleware(authService Service) gin.HandlerFunc {
+	authenticate := AuthMiddleware(authService)
+	return func(c *gin.Context) {
+		if c.GetHeader(AuthorizationHeader) == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
 // GetUserIDFromContext extracts user ID from gin context
 func GetUserIDFromContext(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get(UserIDKey)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -61,6 +62,29 @@ func (m *MockAuthService) RevokeAllUserTokens(ctx context.Context, userID uint)
 	return args.Error(0)
 }
 
+func (m *MockAuthService) ListSessions(ctx context.Context, userID uint) ([]Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Session), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID uint, sessionID uuid.UUID) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GenerateTwoFactorToken(userID uint) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateTwoFactorToken(tokenString string) (uint, error) {
+	args := m.Called(tokenString)
+	return args.Get(0).(uint), args.Error(1)
+}
+
 func setupTestRouter(authService Service) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -222,6 +246,52 @@ func TestAuthMiddleware_ContextSetting(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestOptionalAuthMiddleware(t *testing.T) {
+	mockService := &MockAuthService{}
+	mockService.On("ValidateToken", "valid-token").Return(&Claims{UserID: 123}, nil)
+	mockService.On("ValidateToken", "expired-token").Return(nil, ErrExpiredToken)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(OptionalAuthMiddleware(mockService))
+	r.GET("/test", func(c *gin.Context) {
+		var userID uint
+		if value, exists := c.Get(KeyUser); exists {
+			userID = value.(*Claims).UserID
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+
+	tests := []struct {
+		name           string
+		authHeader     string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"anonymous", "", http.StatusOK, `{"user_id":0}`},
+		{"authenticated", "Bearer valid-token", http.StatusOK, `{"user_id":123}`},
+		{"expired token", "Bearer expired-token", http.StatusUnauthorized, `{"error":"invalid or expired token"}`},
+		{"invalid format", "Basic dGVzdDp0ZXN0", http.StatusUnauthorized, `{"error":"invalid authorization header format"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.authHeader != "" {
+				req.Header.Set(AuthorizationHeader, tt.authHeader)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetUserIDFromContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
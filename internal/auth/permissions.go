@@ -0,0 +1,86 @@
+package auth
+
+import "sort"
+
+// Roles that grant permissions. Every registered user also has the "user" role, which grants none.
+const (
+	RoleAdmin     = "admin"
+	RoleGestor    = "gestor"
+	RoleCorretor  = "corretor"
+	RoleMarketing = "marketing"
+)
+
+// Permissions checked by middleware.RequirePermission
+const (
+	PermissionImoveisWrite    = "imoveis:write"
+	PermissionCorretoresWrite = "corretores:write"
+	PermissionSlidersWrite    = "sliders:write"
+	PermissionEmailsSend      = "emails:send"
+	PermissionImportRun       = "import:run"
+)
+
+// AllPermissions lists every permission; the admin role grants all of them
+var AllPermissions = []string{
+	PermissionImoveisWrite,
+	PermissionCorretoresWrite,
+	PermissionSlidersWrite,
+	PermissionEmailsSend,
+	PermissionImportRun,
+}
+
+// rolePermissions maps each role to the permissions it grants. Permissions live in code rather than
+// in the database so that a route and the roles allowed on it are reviewed together.
+var rolePermissions = map[string][]string{
+	RoleAdmin:     AllPermissions,
+	RoleGestor:    AllPermissions,
+	RoleCorretor:  {PermissionImoveisWrite},
+	RoleMarketing: {PermissionSlidersWrite, PermissionEmailsSend},
+}
+
+// RolePermissions returns the permissions granted by role, nil for unknown roles
+func RolePermissions(role string) []string {
+	return rolePermissions[role]
+}
+
+// PermissionsForRoles returns the sorted union of the permissions granted by roles
+func PermissionsForRoles(roles []string) []string {
+	seen := make(map[string]bool)
+	permissions := []string{}
+	for _, role := range roles {
+		for _, permission := range rolePermissions[role] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// HasPermission reports whether any of roles grants permission
+func HasPermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ScopedToCorretor reports whether roles limit the user to the imoveis of their own corretor
+// principal. Corretor users are, unless another role grants access to every imovel.
+func ScopedToCorretor(roles []string) bool {
+	scoped := false
+	for _, role := range roles {
+		switch role {
+		case RoleAdmin, RoleGestor:
+			return false
+		case RoleCorretor:
+			scoped = true
+		}
+	}
+	return scoped
+}
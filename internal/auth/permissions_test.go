@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionsForRoles(t *testing.T) {
+	assert.Equal(t, []string{"emails:send", "imoveis:write", "sliders:write"}, PermissionsForRoles([]string{"user", "corretor", "marketing"}))
+	assert.Equal(t, []string{"corretores:write", "emails:send", "imoveis:write", "import:run", "sliders:write"}, PermissionsForRoles([]string{"admin"}))
+	assert.Empty(t, PermissionsForRoles([]string{"user", "unknown"}))
+	assert.NotNil(t, PermissionsForRoles(nil), "an empty list serializes as []")
+}
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, HasPermission([]string{"user", "gestor"}, PermissionImportRun))
+	assert.True(t, HasPermission([]string{"marketing"}, PermissionSlidersWrite))
+	assert.False(t, HasPermission([]string{"marketing"}, PermissionImoveisWrite))
+	assert.False(t, HasPermission([]string{"corretor"}, PermissionCorretoresWrite), "corretores do not manage the team")
+	assert.False(t, HasPermission(nil, PermissionEmailsSend))
+}
+
+func TestScopedToCorretor(t *testing.T) {
+	assert.True(t, ScopedToCorretor([]string{"user", "corretor"}))
+	assert.True(t, ScopedToCorretor([]string{"corretor", "marketing"}))
+	assert.False(t, ScopedToCorretor([]string{"corretor", "gestor"}), "gestor changes every imovel")
+	assert.False(t, ScopedToCorretor([]string{"admin", "corretor"}))
+	assert.False(t, ScopedToCorretor([]string{"user"}))
+}
@@ -13,8 +13,13 @@ import (
 
 var (
 	ErrTokenDoesNotBelongToUser = errors.New("token does not belong to user")
+	// ErrSessionNotFound is returned when revoking a session the user does not have active
+	ErrSessionNotFound = errors.New("session not found")
 )
 
+// maxUserAgentLength bounds the user agent stored with a session
+const maxUserAgentLength = 512
+
 // RefreshToken represents a refresh token in the database
 type RefreshToken struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key"`
@@ -24,6 +29,9 @@ type RefreshToken struct {
 	ExpiresAt   time.Time `gorm:"not null;index"`
 	UsedAt      *time.Time
 	RevokedAt   *time.Time
+	UserAgent   string    `gorm:"type:varchar(512)"`
+	IPAddress   string    `gorm:"type:varchar(45)"`
+	TwoFactor   bool      `gorm:"not null;default:false"` // the session was signed in with a second factor
 	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 }
 
@@ -51,6 +59,8 @@ type RefreshTokenRepository interface {
 	MarkAsUsed(ctx context.Context, id uuid.UUID) error
 	RevokeTokenFamily(ctx context.Context, tokenFamily uuid.UUID) error
 	RevokeByUserID(ctx context.Context, userID uint) error
+	ListActiveSessions(ctx context.Context, userID uint) ([]Session, error)
+	RevokeUserTokenFamily(ctx context.Context, userID uint, tokenFamily uuid.UUID) (bool, error)
 	DeleteExpired(ctx context.Context) error
 }
 
@@ -133,6 +143,67 @@ func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uint
 		Update("revoked_at", now).Error
 }
 
+// ListActiveSessions returns the token families of the user that can still be refreshed, most
+// recently used first. A family has a single unused token, so it describes the session.
+func (r *refreshTokenRepository) ListActiveSessions(ctx context.Context, userID uint) ([]Session, error) {
+	var active []RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL AND revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&active).Error
+	if err != nil || len(active) == 0 {
+		return nil, err
+	}
+
+	families := make([]uuid.UUID, len(active))
+	for i, token := range active {
+		families[i] = token.TokenFamily
+	}
+	var tokens []RefreshToken
+	if err := r.db.WithContext(ctx).
+		Select("token_family", "created_at").
+		Where("token_family IN ?", families).
+		Order("created_at ASC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	startedAt := make(map[uuid.UUID]time.Time, len(active))
+	for _, token := range tokens {
+		if _, ok := startedAt[token.TokenFamily]; !ok {
+			startedAt[token.TokenFamily] = token.CreatedAt
+		}
+	}
+
+	sessions := make([]Session, len(active))
+	for i, token := range active {
+		sessions[i] = Session{
+			ID:         token.TokenFamily,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  startedAt[token.TokenFamily],
+			LastUsedAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeUserTokenFamily revokes a token family of the user; found is false when the user has no
+// token of the family left to revoke
+func (r *refreshTokenRepository) RevokeUserTokenFamily(ctx context.Context, userID uint, tokenFamily uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&RefreshToken{}).
+		Where("user_id = ? AND token_family = ?", userID, tokenFamily).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	return r.db.WithContext(ctx).
 		Where("expires_at < ?", time.Now()).
@@ -27,6 +27,12 @@ var (
 	ErrTokenRevoked = errors.New("token has been revoked")
 )
 
+// TwoFactorChallengeTTL is how long a password login waits for its second factor
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+// twoFactorPurpose marks the challenge tokens of GenerateTwoFactorToken, which are not access tokens
+const twoFactorPurpose = "2fa"
+
 // TokenPair represents an access and refresh token pair
 type TokenPair struct {
 	AccessToken  string    `json:"access_token"`
@@ -45,6 +51,10 @@ type Service interface {
 	RevokeRefreshToken(ctx context.Context, refreshToken string) error
 	RevokeUserRefreshToken(ctx context.Context, userID uint, refreshToken string) error
 	RevokeAllUserTokens(ctx context.Context, userID uint) error
+	ListSessions(ctx context.Context, userID uint) ([]Session, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID uuid.UUID) error
+	GenerateTwoFactorToken(userID uint) (string, error)
+	ValidateTwoFactorToken(tokenString string) (uint, error)
 }
 
 type service struct {
@@ -115,6 +125,13 @@ func NewServiceWithRepo(cfg *config.JWTConfig, db *gorm.DB) Service {
 
 // GenerateToken generates a JWT token for a user (deprecated: use GenerateTokenPair)
 func (s *service) GenerateToken(userID uint, email string, name string) (string, error) {
+	return s.generateToken(userID, email, name, uuid.Nil, false)
+}
+
+// generateToken generates the access token of a user, tied to the session (refresh token family)
+// it was issued for unless sessionID is uuid.Nil. twoFactor records that the session was signed in
+// with a second factor.
+func (s *service) generateToken(userID uint, email string, name string, sessionID uuid.UUID, twoFactor bool) (string, error) {
 	now := time.Now()
 	expirationTime := now.Add(s.accessTokenTTL)
 
@@ -141,6 +158,40 @@ func (s *service) GenerateToken(userID uint, email string, name string) (string,
 		"exp":   expirationTime.Unix(),
 		"iat":   now.Unix(),
 	}
+	if sessionID != uuid.Nil {
+		claims["sid"] = sessionID.String()
+	}
+	if twoFactor {
+		claims["mfa"] = true
+	}
+
+	if s.db != nil && ScopedToCorretor(roles) {
+		var corretorID uint
+		err := s.db.Table("users").
+			Select("COALESCE(corretor_id, 0)").
+			Where("id = ?", userID).
+			Scan(&corretorID).Error
+		if err != nil {
+			// WHY: a scoped token without its corretor would lock the user out of their own imoveis
+			return "", fmt.Errorf("failed to fetch user corretor: %w", err)
+		}
+		claims["corretor_id"] = corretorID
+	}
+
+	if s.db != nil {
+		var organizacaoID uint
+		err := s.db.Table("users").
+			Select("COALESCE(organizacao_id, 0)").
+			Where("id = ?", userID).
+			Scan(&organizacaoID).Error
+		if err != nil {
+			// WHY: a token without its organizacao would let the user see every tenant's data
+			return "", fmt.Errorf("failed to fetch user organizacao: %w", err)
+		}
+		if organizacaoID > 0 {
+			claims["organizacao_id"] = organizacaoID
+		}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
@@ -153,26 +204,12 @@ func (s *service) GenerateToken(userID uint, email string, name string) (string,
 
 // ValidateToken validates a JWT token and returns the claims
 func (s *service) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
+	claims, err := s.parseToken(tokenString)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
-		return nil, ErrInvalidToken
-	}
-
-	if !token.Valid {
-		return nil, ErrInvalidToken
+		return nil, err
 	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
+	// WHY: a two-factor challenge only proves the password, it must not grant access
+	if _, ok := claims["purpose"]; ok {
 		return nil, ErrInvalidToken
 	}
 
@@ -198,21 +235,101 @@ func (s *service) ValidateToken(tokenString string) (*Claims, error) {
 		}
 	}
 
+	var corretorID uint
+	if value, ok := claims["corretor_id"].(float64); ok && value > 0 {
+		corretorID = uint(value)
+	}
+
+	var organizacaoID uint
+	if value, ok := claims["organizacao_id"].(float64); ok && value > 0 {
+		organizacaoID = uint(value)
+	}
+	sessionID, _ := claims["sid"].(string)
+	twoFactor, _ := claims["mfa"].(bool)
+
 	return &Claims{
-		UserID: uint(userID),
-		Email:  email,
-		Name:   name,
-		Roles:  roles,
+		UserID:        uint(userID),
+		Email:         email,
+		Name:          name,
+		Roles:         roles,
+		CorretorID:    corretorID,
+		OrganizacaoID: organizacaoID,
+		SessionID:     sessionID,
+		TwoFactor:     twoFactor,
 	}, nil
 }
 
+// parseToken verifies the signature and expiry of a JWT signed by the service
+func (s *service) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.jwtSecret), nil
+	})
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// GenerateTwoFactorToken issues the challenge token of a user who passed the password check and
+// still has to send a TOTP or recovery code. It is valid for TwoFactorChallengeTTL and is not
+// accepted as an access token.
+func (s *service) GenerateTwoFactorToken(userID uint) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     fmt.Sprintf("%d", userID),
+		"purpose": twoFactorPurpose,
+		"exp":     now.Add(TwoFactorChallengeTTL).Unix(),
+		"iat":     now.Unix(),
+	})
+	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// ValidateTwoFactorToken returns the user of a challenge token from GenerateTwoFactorToken
+func (s *service) ValidateTwoFactorToken(tokenString string) (uint, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != twoFactorPurpose {
+		return 0, ErrInvalidToken
+	}
+	subStr, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(subStr, 10, 32)
+	if err != nil || userID == 0 {
+		return 0, ErrInvalidToken
+	}
+	return uint(userID), nil
+}
+
 // GenerateTokenPair generates both access and refresh tokens with rotation support
 func (s *service) GenerateTokenPair(ctx context.Context, userID uint, email string, name string) (*TokenPair, error) {
 	if s.refreshTokenRepo == nil {
 		return nil, errors.New("refresh token repository not initialized")
 	}
 
-	accessToken, err := s.GenerateToken(userID, email, name)
+	tokenFamily := uuid.New()
+	twoFactor := twoFactorFromContext(ctx)
+	accessToken, err := s.generateToken(userID, email, name, tokenFamily, twoFactor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -222,14 +339,17 @@ func (s *service) GenerateTokenPair(ctx context.Context, userID uint, email stri
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	tokenFamily := uuid.New()
 	refreshTokenHash := HashToken(refreshToken)
 
+	client := clientFromContext(ctx)
 	dbToken := &RefreshToken{
 		UserID:      userID,
 		TokenHash:   refreshTokenHash,
 		TokenFamily: tokenFamily,
 		ExpiresAt:   time.Now().Add(s.refreshTokenTTL),
+		UserAgent:   client.userAgent,
+		IPAddress:   client.ip,
+		TwoFactor:   twoFactor,
 	}
 
 	if err := s.refreshTokenRepo.Create(ctx, dbToken); err != nil {
@@ -290,7 +410,7 @@ func (s *service) RefreshAccessToken(ctx context.Context, refreshToken string) (
 		return nil, fmt.Errorf("failed to fetch user for token claims: %w", err)
 	}
 
-	accessToken, err := s.GenerateToken(storedToken.UserID, user.Email, user.Name)
+	accessToken, err := s.generateToken(storedToken.UserID, user.Email, user.Name, storedToken.TokenFamily, storedToken.TwoFactor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -301,11 +421,19 @@ func (s *service) RefreshAccessToken(ctx context.Context, refreshToken string) (
 	}
 
 	newTokenHash := HashToken(newRefreshToken)
+	// The session keeps the device it started on unless the refresh request tells otherwise
+	client := clientFromContext(ctx)
+	if client.userAgent == "" && client.ip == "" {
+		client.userAgent, client.ip = storedToken.UserAgent, storedToken.IPAddress
+	}
 	newDBToken := &RefreshToken{
 		UserID:      storedToken.UserID,
 		TokenHash:   newTokenHash,
 		TokenFamily: storedToken.TokenFamily,
 		ExpiresAt:   time.Now().Add(s.refreshTokenTTL),
+		UserAgent:   client.userAgent,
+		IPAddress:   client.ip,
+		TwoFactor:   storedToken.TwoFactor,
 	}
 
 	if err := s.refreshTokenRepo.Create(ctx, newDBToken); err != nil {
@@ -370,6 +498,36 @@ func (s *service) RevokeAllUserTokens(ctx context.Context, userID uint) error {
 	return s.refreshTokenRepo.RevokeByUserID(ctx, userID)
 }
 
+// ListSessions returns the active sessions of a user, most recently used first
+func (s *service) ListSessions(ctx context.Context, userID uint) ([]Session, error) {
+	if s.refreshTokenRepo == nil {
+		return nil, errors.New("refresh token repository not initialized")
+	}
+
+	sessions, err := s.refreshTokenRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes every refresh token of one of the user's sessions, so that the device can
+// no longer refresh its access token
+func (s *service) RevokeSession(ctx context.Context, userID uint, sessionID uuid.UUID) error {
+	if s.refreshTokenRepo == nil {
+		return errors.New("refresh token repository not initialized")
+	}
+
+	found, err := s.refreshTokenRepo.RevokeUserTokenFamily(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
 // generateRandomToken generates a cryptographically secure random token
 func generateRandomToken() (string, error) {
 	b := make([]byte, 32)
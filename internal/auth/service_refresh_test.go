@@ -16,13 +16,15 @@ import (
 
 // testUser is a minimal user struct for testing
 type testUser struct {
-	ID           uint   `gorm:"primaryKey"`
-	Name         string `gorm:"not null"`
-	Email        string `gorm:"uniqueIndex;not null"`
-	PasswordHash string `gorm:"not null"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	ID            uint   `gorm:"primaryKey"`
+	Name          string `gorm:"not null"`
+	Email         string `gorm:"uniqueIndex;not null"`
+	PasswordHash  string `gorm:"not null"`
+	CorretorID    *uint
+	OrganizacaoID *uint
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
 
 func (testUser) TableName() string {
@@ -120,6 +122,43 @@ func TestService_GenerateTokenPair(t *testing.T) {
 	assert.Equal(t, "Test User", claims.Name)
 }
 
+func TestService_GenerateToken_CorretorScope(t *testing.T) {
+	svc, db := setupServiceTest(t)
+	require.NoError(t, db.Create(&testRole{ID: 4, Name: RoleCorretor}).Error)
+	require.NoError(t, db.Create(&testUserRole{UserID: 1, RoleID: 4}).Error)
+
+	token, err := svc.GenerateToken(1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	claims, err := svc.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Zero(t, claims.CorretorID, "an unlinked corretor gets no corretor")
+
+	require.NoError(t, db.Model(&testUser{}).Where("id = 1").Update("corretor_id", 5).Error)
+	token, err = svc.GenerateToken(1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	claims, err = svc.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), claims.CorretorID)
+	assert.ElementsMatch(t, []string{"user", RoleCorretor}, claims.Roles)
+}
+
+func TestService_GenerateToken_Organizacao(t *testing.T) {
+	svc, db := setupServiceTest(t)
+
+	token, err := svc.GenerateToken(1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	claims, err := svc.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Zero(t, claims.OrganizacaoID, "users without an organizacao are unscoped")
+
+	require.NoError(t, db.Model(&testUser{}).Where("id = 1").Update("organizacao_id", 3).Error)
+	token, err = svc.GenerateToken(1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	claims, err = svc.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), claims.OrganizacaoID)
+}
+
 func TestService_RefreshAccessToken_Success(t *testing.T) {
 	svc, _ := setupServiceTest(t)
 	ctx := context.Background()
@@ -535,3 +574,78 @@ func TestService_GenerateTokenPair_InvalidSecret(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, pair)
 }
+
+func TestService_Sessions(t *testing.T) {
+	svc, _ := setupServiceTest(t)
+	ctx := context.Background()
+
+	laptop, err := svc.GenerateTokenPair(WithClient(ctx, "Firefox", "10.0.0.1"), 1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	phone, err := svc.GenerateTokenPair(WithClient(ctx, "Safari iOS", "10.0.0.2"), 1, "test@example.com", "Test User")
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateToken(phone.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, phone.TokenFamily.String(), claims.SessionID)
+
+	rotated, err := svc.RefreshAccessToken(WithClient(ctx, "Firefox", "10.0.0.9"), laptop.RefreshToken)
+	require.NoError(t, err)
+	claims, err = svc.ValidateToken(rotated.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, laptop.TokenFamily.String(), claims.SessionID, "rotation keeps the session")
+
+	sessions, err := svc.ListSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, laptop.TokenFamily, sessions[0].ID, "most recently used first")
+	assert.Equal(t, "Firefox", sessions[0].UserAgent)
+	assert.Equal(t, "10.0.0.9", sessions[0].IPAddress)
+	assert.False(t, sessions[0].CreatedAt.After(sessions[0].LastUsedAt))
+	assert.Equal(t, phone.TokenFamily, sessions[1].ID)
+	assert.Equal(t, "Safari iOS", sessions[1].UserAgent)
+
+	assert.ErrorIs(t, svc.RevokeSession(ctx, 2, phone.TokenFamily), ErrSessionNotFound, "sessions of other users are not found")
+	require.NoError(t, svc.RevokeSession(ctx, 1, phone.TokenFamily))
+	assert.ErrorIs(t, svc.RevokeSession(ctx, 1, phone.TokenFamily), ErrSessionNotFound)
+
+	_, err = svc.RefreshAccessToken(ctx, phone.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked, "a revoked device cannot refresh")
+
+	sessions, err = svc.ListSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, laptop.TokenFamily, sessions[0].ID)
+}
+
+func TestService_TwoFactor(t *testing.T) {
+	svc, _ := setupServiceTest(t)
+	ctx := context.Background()
+
+	challenge, err := svc.GenerateTwoFactorToken(1)
+	require.NoError(t, err)
+	userID, err := svc.ValidateTwoFactorToken(challenge)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), userID)
+	_, err = svc.ValidateToken(challenge)
+	assert.ErrorIs(t, err, ErrInvalidToken, "a challenge is not an access token")
+
+	plain, err := svc.GenerateTokenPair(ctx, 1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	_, err = svc.ValidateTwoFactorToken(plain.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken, "an access token is not a challenge")
+	claims, err := svc.ValidateToken(plain.AccessToken)
+	require.NoError(t, err)
+	assert.False(t, claims.TwoFactor)
+
+	verified, err := svc.GenerateTokenPair(WithTwoFactor(ctx), 1, "test@example.com", "Test User")
+	require.NoError(t, err)
+	claims, err = svc.ValidateToken(verified.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, claims.TwoFactor)
+
+	rotated, err := svc.RefreshAccessToken(ctx, verified.RefreshToken)
+	require.NoError(t, err)
+	claims, err = svc.ValidateToken(rotated.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, claims.TwoFactor, "refreshing keeps the 2FA of the session")
+}
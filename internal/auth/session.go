@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device: a refresh token family, from the login that started it to its
+// latest rotation
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // the session of the access token making the request
+}
+
+type clientKey struct{}
+
+type client struct {
+	userAgent string
+	ip        string
+}
+
+// WithClient returns a context carrying the device issuing a token request, recorded with the
+// refresh tokens so that sessions can be told apart
+func WithClient(ctx context.Context, userAgent, ip string) context.Context {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return context.WithValue(ctx, clientKey{}, client{userAgent: userAgent, ip: ip})
+}
+
+func clientFromContext(ctx context.Context) client {
+	c, _ := ctx.Value(clientKey{}).(client)
+	return c
+}
+
+// ClientIP returns the address of the device set with WithClient, empty when there is none
+func ClientIP(ctx context.Context) string {
+	return clientFromContext(ctx).ip
+}
+
+type twoFactorKey struct{}
+
+// WithTwoFactor returns a context marking that the user passed the second factor, so that the
+// session GenerateTokenPair starts (and its refreshes) carries the mfa claim
+func WithTwoFactor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, twoFactorKey{}, true)
+}
+
+func twoFactorFromContext(ctx context.Context) bool {
+	twoFactor, _ := ctx.Value(twoFactorKey{}).(bool)
+	return twoFactor
+}
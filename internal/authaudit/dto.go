@@ -0,0 +1,16 @@
+package authaudit
+
+import "time"
+
+// ListQuery represents the filters of the authentication audit log; From and To bound the time
+// of the events (RFC 3339)
+type ListQuery struct {
+	Type   string    `form:"type" binding:"omitempty,oneof=login_succeeded login_failed token_refreshed token_refresh_failed password_changed role_assigned role_removed logout"`
+	UserID uint      `form:"user_id"`
+	Email  string    `form:"email"`
+	IP     string    `form:"ip"`
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page   int       `form:"page,default=1" binding:"min=1"`
+	Limit  int       `form:"limit,default=20" binding:"min=1,max=100"`
+}
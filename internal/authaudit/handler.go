@@ -0,0 +1,54 @@
+package authaudit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for the authentication audit log
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new authentication audit log handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// @Summary List authentication events (Admin only)
+// @Description Logins, token refreshes, logouts, password and role changes with the address and user agent of the client, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Event type (login_succeeded, login_failed, token_refreshed, token_refresh_failed, password_changed, role_assigned, role_removed, logout)"
+// @Param user_id query int false "User the events are about"
+// @Param email query string false "Email (exact match, case-insensitive)"
+// @Param ip query string false "Client IP address"
+// @Param from query string false "Events at or after this time (RFC 3339)"
+// @Param to query string false "Events before this time (RFC 3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[Event]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/auth-events [get]
+func (h *Handler) ListEvents(c *gin.Context) {
+	var query ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListEvents(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
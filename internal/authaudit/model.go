@@ -0,0 +1,34 @@
+package authaudit
+
+import "time"
+
+// Types of the recorded authentication events
+const (
+	TypeLoginSucceeded     = "login_succeeded"
+	TypeLoginFailed        = "login_failed"
+	TypeTokenRefreshed     = "token_refreshed"
+	TypeTokenRefreshFailed = "token_refresh_failed"
+	TypePasswordChanged    = "password_changed"
+	TypeRoleAssigned       = "role_assigned"
+	TypeRoleRemoved        = "role_removed"
+	TypeLogout             = "logout"
+)
+
+// Event is an entry of the authentication audit log. UserID is the account the event is about,
+// unknown for failed logins of an unregistered email; ActorID is the admin who changed its roles.
+type Event struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Type      string    `gorm:"size:50;not null;index" json:"type"`
+	UserID    *uint     `gorm:"index" json:"user_id,omitempty"`
+	ActorID   *uint     `json:"actor_id,omitempty"`
+	Email     string    `gorm:"size:255;index" json:"email,omitempty"`
+	IPAddress string    `gorm:"size:45" json:"ip_address,omitempty"`
+	UserAgent string    `gorm:"size:512" json:"user_agent,omitempty"`
+	Detail    string    `gorm:"size:255" json:"detail,omitempty"` // failure reason, role or sign-in method
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for Event model
+func (Event) TableName() string {
+	return "auth_events"
+}
@@ -0,0 +1,66 @@
+package authaudit
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Repository defines authentication audit log repository interface
+type Repository interface {
+	Create(ctx context.Context, event *Event) error
+	List(ctx context.Context, query *ListQuery) ([]Event, int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new authentication audit log repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create stores an event
+func (r *repository) Create(ctx context.Context, event *Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// List returns one page of the events matching query, newest first, with their total
+func (r *repository) List(ctx context.Context, query *ListQuery) ([]Event, int64, error) {
+	db := r.db.WithContext(ctx).Model(&Event{})
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.UserID != 0 {
+		db = db.Where("user_id = ?", query.UserID)
+	}
+	if query.Email != "" {
+		db = db.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(query.Email)))
+	}
+	if query.IP != "" {
+		db = db.Where("ip_address = ?", query.IP)
+	}
+	if !query.From.IsZero() {
+		db = db.Where("created_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		db = db.Where("created_at < ?", query.To)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []Event
+	err := db.Order("created_at DESC, id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
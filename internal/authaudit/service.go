@@ -0,0 +1,53 @@
+package authaudit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+// maxUserAgentLength bounds the stored user agents to their column
+const maxUserAgentLength = 512
+
+// Recorder records authentication events
+type Recorder interface {
+	// Record stores event; failures are logged, so that the audited request still succeeds
+	Record(ctx context.Context, event *Event)
+}
+
+// Service defines authentication audit log service interface
+type Service interface {
+	Recorder
+	ListEvents(ctx context.Context, query *ListQuery) (*pagination.Page[Event], error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new authentication audit log service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Record stores an authentication event
+func (s *service) Record(ctx context.Context, event *Event) {
+	if len(event.UserAgent) > maxUserAgentLength {
+		event.UserAgent = event.UserAgent[:maxUserAgentLength]
+	}
+	// The event is kept even when the request that caused it is cancelled
+	if err := s.repo.Create(context.WithoutCancel(ctx), event); err != nil {
+		slog.Error("Failed to record auth event", "type", event.Type, "user_id", event.UserID, "error", err)
+	}
+}
+
+// ListEvents returns one page of the events matching query, newest first
+func (s *service) ListEvents(ctx context.Context, query *ListQuery) (*pagination.Page[Event], error) {
+	events, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auth events: %w", err)
+	}
+	return pagination.New(events, total, query.Page, query.Limit), nil
+}
@@ -0,0 +1,81 @@
+package authaudit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func setupService(t *testing.T) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&Event{}))
+
+	return NewService(NewRepository(database)), database
+}
+
+func TestRecord_TruncatesUserAgent(t *testing.T) {
+	svc, database := setupService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.Record(ctx, &Event{Type: TypeLoginFailed, Email: "ana@example.com", UserAgent: strings.Repeat("a", 600)})
+
+	var stored Event
+	require.NoError(t, database.First(&stored).Error, "recorded although the request was cancelled")
+	assert.Len(t, stored.UserAgent, maxUserAgentLength)
+}
+
+func TestListEvents_Filters(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	userID := uint(5)
+	now := time.Now().UTC()
+	events := []Event{
+		{Type: TypeLoginSucceeded, UserID: &userID, Email: "ana@example.com", IPAddress: "10.0.0.1", CreatedAt: now.Add(-3 * time.Hour)},
+		{Type: TypeLoginFailed, Email: "Ana@Example.com", IPAddress: "10.0.0.2", CreatedAt: now.Add(-2 * time.Hour)},
+		{Type: TypeLogout, UserID: &userID, Email: "ana@example.com", IPAddress: "10.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{Type: TypeLoginFailed, Email: "bia@example.com", IPAddress: "10.0.0.3", CreatedAt: now},
+	}
+	require.NoError(t, database.Create(&events).Error)
+
+	page, err := svc.ListEvents(ctx, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 4)
+	assert.Equal(t, "bia@example.com", page.Results[0].Email, "newest first")
+
+	page, err = svc.ListEvents(ctx, &ListQuery{Type: TypeLoginFailed, Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	page, err = svc.ListEvents(ctx, &ListQuery{Email: " ana@example.com ", Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total, "email matches case-insensitively")
+
+	page, err = svc.ListEvents(ctx, &ListQuery{UserID: userID, IP: "10.0.0.1", Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	page, err = svc.ListEvents(ctx, &ListQuery{From: now.Add(-150 * time.Minute), To: now.Add(-30 * time.Minute), Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, TypeLogout, page.Results[0].Type)
+	assert.Equal(t, TypeLoginFailed, page.Results[1].Type)
+
+	page, err = svc.ListEvents(ctx, &ListQuery{Page: 2, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), page.Total)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "ana@example.com", page.Results[0].Email)
+	assert.Equal(t, TypeLoginSucceeded, page.Results[0].Type)
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// Cache stores serialized values under string keys. Implementations are safe for concurrent use.
+// A shared backend (e.g. Redis) lets several instances see the same entries and invalidations;
+// the memory driver is per instance.
+type Cache interface {
+	// Get returns the value stored under key; ok is false for missing and expired keys
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value under key for ttl; a zero ttl keeps it until evicted or deleted
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; deleting a missing key is not an error
+	Delete(ctx context.Context, keys ...string) error
+	// Clear removes every entry
+	Clear(ctx context.Context) error
+}
+
+// New creates the cache selected by cfg.Driver. The "none" driver disables caching and returns nil.
+func New(cfg *config.CacheConfig) (Cache, error) {
+	switch cfg.Driver {
+	case "", "memory":
+		return NewMemoryCache(cfg.Size), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown cache driver %q", cfg.Driver)
+	}
+}
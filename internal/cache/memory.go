@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// defaultMemorySize bounds the entries of a memory cache created without a size
+const defaultMemorySize = 10000
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryCache keeps entries in an LRU bounded by count; expired entries are dropped when read
+type memoryCache struct {
+	entries *lru.Cache[string, memoryEntry]
+	now     func() time.Time
+}
+
+// NewMemoryCache creates an in-process cache holding up to size entries
+func NewMemoryCache(size int) Cache {
+	if size <= 0 {
+		size = defaultMemorySize
+	}
+	entries, _ := lru.New[string, memoryEntry](size)
+	return &memoryCache{entries: entries, now: time.Now}
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	entry, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.entries.Remove(key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+	c.entries.Add(key, entry)
+	return nil
+}
+
+func (c *memoryCache) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.entries.Remove(key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Clear(_ context.Context) error {
+	c.entries.Purge()
+	return nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache(2).(*memoryCache)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		_, ok, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		_, ok, _ = c.Get(ctx, "b")
+		assert.True(t, ok, "entries without ttl do not expire")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, c.Delete(ctx, "b", "missing"))
+		_, ok, _ := c.Get(ctx, "b")
+		assert.False(t, ok)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+		require.NoError(t, c.Clear(ctx))
+		_, ok, _ := c.Get(ctx, "c")
+		assert.False(t, ok)
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		require.NoError(t, c.Set(ctx, "x", []byte("x"), 0))
+		require.NoError(t, c.Set(ctx, "y", []byte("y"), 0))
+		_, _, _ = c.Get(ctx, "x")
+		require.NoError(t, c.Set(ctx, "z", []byte("z"), 0))
+
+		_, ok, _ := c.Get(ctx, "y")
+		assert.False(t, ok)
+		_, ok, _ = c.Get(ctx, "x")
+		assert.True(t, ok)
+	})
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(&config.CacheConfig{Driver: "memory"})
+	require.NoError(t, err)
+	assert.NotNil(t, c)
+
+	c, err = New(&config.CacheConfig{Driver: "none"})
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = New(&config.CacheConfig{Driver: "redis"})
+	assert.Error(t, err)
+}
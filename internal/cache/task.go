@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+// Warmer fills the cache with the entries a module expects to be requested first
+type Warmer func(ctx context.Context) error
+
+// NewRebuildTask exposes the cache as the cache maintenance task, which clears every entry and
+// then runs the warmers. With the memory driver only the instance that runs the job is rebuilt;
+// the others catch up as their entries expire.
+func NewRebuildTask(c Cache, warmers ...Warmer) maintenance.Task {
+	return maintenance.TaskFunc{
+		TaskName: "cache",
+		Fn: func(ctx context.Context, report maintenance.ProgressFunc) error {
+			total := len(warmers) + 1
+			if err := c.Clear(ctx); err != nil {
+				return fmt.Errorf("failed to clear cache: %w", err)
+			}
+			report(1, total)
+			for i, warm := range warmers {
+				if err := warm(ctx); err != nil {
+					return fmt.Errorf("failed to warm cache: %w", err)
+				}
+				report(i+2, total)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildTask(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+	require.NoError(t, c.Set(ctx, "stale", []byte("1"), 0))
+
+	var progress [][2]int
+	task := NewRebuildTask(c, func(ctx context.Context) error {
+		return c.Set(ctx, "warm", []byte("2"), 0)
+	})
+	assert.Equal(t, "cache", task.Name())
+	require.NoError(t, task.Run(ctx, func(done, total int) { progress = append(progress, [2]int{done, total}) }))
+
+	_, ok, _ := c.Get(ctx, "stale")
+	assert.False(t, ok, "the cache is cleared")
+	_, ok, _ = c.Get(ctx, "warm")
+	assert.True(t, ok, "warmers run after the clear")
+	assert.Equal(t, [][2]int{{1, 2}, {2, 2}}, progress)
+
+	t.Run("warmer failure", func(t *testing.T) {
+		task := NewRebuildTask(c, func(ctx context.Context) error { return errors.New("db down") })
+		assert.ErrorContains(t, task.Run(ctx, func(done, total int) {}), "db down")
+	})
+}
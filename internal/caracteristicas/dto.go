@@ -0,0 +1,40 @@
+package caracteristicas
+
+import "time"
+
+// CreateCaracteristicaRequest represents characteristic creation request
+type CreateCaracteristicaRequest struct {
+	Nome          string `json:"nome" binding:"required,min=2,max=100"`
+	CategoriaID   uint   `json:"categoria_id" binding:"omitempty"`
+	CategoriaNome string `json:"categoria_nome" binding:"omitempty,max=100"`
+}
+
+// UpdateCaracteristicaRequest represents characteristic update request
+type UpdateCaracteristicaRequest struct {
+	Nome          string  `json:"nome" binding:"omitempty,min=2,max=100"`
+	CategoriaID   *uint   `json:"categoria_id" binding:"omitempty"`
+	CategoriaNome *string `json:"categoria_nome" binding:"omitempty,max=100"`
+}
+
+// ListCaracteristicasQuery represents query parameters for the catalog
+type ListCaracteristicasQuery struct {
+	CategoriaID uint   `form:"categoria_id" binding:"omitempty"`
+	Nome        string `form:"nome" binding:"omitempty,max=100"`
+}
+
+// CaracteristicaResponse represents characteristic response
+type CaracteristicaResponse struct {
+	ID            uint      `json:"id"`
+	Nome          string    `json:"nome"`
+	CategoriaID   uint      `json:"categoria_id,omitempty"`
+	CategoriaNome string    `json:"categoria_nome,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CategoriaResponse groups the characteristics of a category
+type CategoriaResponse struct {
+	CategoriaID     uint                     `json:"categoria_id"`
+	CategoriaNome   string                   `json:"categoria_nome"`
+	Caracteristicas []CaracteristicaResponse `json:"caracteristicas"`
+}
@@ -0,0 +1,173 @@
+package caracteristicas
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for the characteristics catalog
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new characteristic handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type uriRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Create characteristic
+// @Description Add a characteristic to the catalog; names are unique per categoria (case-insensitive)
+// @Tags caracteristicas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateCaracteristicaRequest true "Characteristic creation request"
+// @Success 201 {object} errors.Response{success=bool,data=CaracteristicaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/caracteristicas [post]
+func (h *Handler) CreateCaracteristica(c *gin.Context) {
+	var req CreateCaracteristicaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	caracteristica, err := h.service.CreateCaracteristica(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(caracteristica))
+}
+
+// @Summary List characteristics
+// @Description List the characteristics catalog grouped by categoria
+// @Tags caracteristicas
+// @Accept json
+// @Produce json
+// @Param categoria_id query int false "Only return this categoria"
+// @Param nome query string false "Filter by name (partial, case-insensitive)"
+// @Success 200 {object} errors.Response{success=bool,data=[]CategoriaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/caracteristicas [get]
+func (h *Handler) ListCaracteristicas(c *gin.Context) {
+	var query ListCaracteristicasQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	categorias, err := h.service.ListByCategoria(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(categorias))
+}
+
+// @Summary Get characteristic by ID
+// @Description Retrieve a characteristic from the catalog
+// @Tags caracteristicas
+// @Accept json
+// @Produce json
+// @Param id path uint true "Characteristic ID"
+// @Success 200 {object} errors.Response{success=bool,data=CaracteristicaResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/caracteristicas/{id} [get]
+func (h *Handler) GetCaracteristica(c *gin.Context) {
+	var uriReq uriRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	caracteristica, err := h.service.GetCaracteristica(c.Request.Context(), uriReq.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(caracteristica))
+}
+
+// @Summary Update characteristic
+// @Description Rename a characteristic or move it to another categoria
+// @Tags caracteristicas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Characteristic ID"
+// @Param request body UpdateCaracteristicaRequest true "Characteristic update request"
+// @Success 200 {object} errors.Response{success=bool,data=CaracteristicaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/caracteristicas/{id} [put]
+func (h *Handler) UpdateCaracteristica(c *gin.Context) {
+	var uriReq uriRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateCaracteristicaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	caracteristica, err := h.service.UpdateCaracteristica(c.Request.Context(), uriReq.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(caracteristica))
+}
+
+// @Summary Delete characteristic
+// @Description Soft delete a characteristic from the catalog
+// @Tags caracteristicas
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Characteristic ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/caracteristicas/{id} [delete]
+func (h *Handler) DeleteCaracteristica(c *gin.Context) {
+	var uriReq uriRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeleteCaracteristica(c.Request.Context(), uriReq.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCaracteristicaNotFound):
+		_ = c.Error(apiErrors.NotFound("Caracteristica not found"))
+	case errors.Is(err, ErrCaracteristicaExists):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
@@ -0,0 +1,90 @@
+package caracteristicas
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines characteristic repository interface
+type Repository interface {
+	Create(ctx context.Context, caracteristica *imoveis.Caracteristica) error
+	FindByID(ctx context.Context, id uint) (*imoveis.Caracteristica, error)
+	FindByNome(ctx context.Context, categoriaID uint, nome string) (*imoveis.Caracteristica, error)
+	Update(ctx context.Context, caracteristica *imoveis.Caracteristica) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, query *ListCaracteristicasQuery) ([]imoveis.Caracteristica, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new characteristic repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create creates a new characteristic
+func (r *repository) Create(ctx context.Context, caracteristica *imoveis.Caracteristica) error {
+	return r.db.WithContext(ctx).Create(caracteristica).Error
+}
+
+// FindByID finds a characteristic by ID
+func (r *repository) FindByID(ctx context.Context, id uint) (*imoveis.Caracteristica, error) {
+	var caracteristica imoveis.Caracteristica
+	if err := r.db.WithContext(ctx).First(&caracteristica, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &caracteristica, nil
+}
+
+// FindByNome finds a characteristic by case-insensitive name within a category
+func (r *repository) FindByNome(ctx context.Context, categoriaID uint, nome string) (*imoveis.Caracteristica, error) {
+	var caracteristica imoveis.Caracteristica
+	if err := r.db.WithContext(ctx).
+		Where("categoria_id = ? AND LOWER(nome) = LOWER(?)", categoriaID, nome).
+		First(&caracteristica).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &caracteristica, nil
+}
+
+// Update updates a characteristic
+func (r *repository) Update(ctx context.Context, caracteristica *imoveis.Caracteristica) error {
+	return r.db.WithContext(ctx).Model(caracteristica).
+		Select("nome", "categoria_id", "categoria_nome").
+		Updates(caracteristica).Error
+}
+
+// Delete soft deletes a characteristic
+func (r *repository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&imoveis.Caracteristica{}, id).Error
+}
+
+// List retrieves characteristics ordered by category and name
+func (r *repository) List(ctx context.Context, query *ListCaracteristicasQuery) ([]imoveis.Caracteristica, error) {
+	var caracteristicas []imoveis.Caracteristica
+
+	db := r.db.WithContext(ctx)
+	if query.CategoriaID > 0 {
+		db = db.Where("categoria_id = ?", query.CategoriaID)
+	}
+	if query.Nome != "" {
+		db = db.Where("LOWER(nome) LIKE LOWER(?)", "%"+query.Nome+"%")
+	}
+
+	if err := db.Order("categoria_id ASC, nome ASC").Find(&caracteristicas).Error; err != nil {
+		return nil, err
+	}
+	return caracteristicas, nil
+}
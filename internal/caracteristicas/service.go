@@ -0,0 +1,172 @@
+package caracteristicas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrCaracteristicaNotFound is returned when a characteristic does not exist
+	ErrCaracteristicaNotFound = errors.New("caracteristica not found")
+	// ErrCaracteristicaExists is returned when the category already has a characteristic with the same name
+	ErrCaracteristicaExists = errors.New("caracteristica already exists in this categoria")
+)
+
+// Service defines characteristic service interface
+type Service interface {
+	CreateCaracteristica(ctx context.Context, req *CreateCaracteristicaRequest) (*CaracteristicaResponse, error)
+	GetCaracteristica(ctx context.Context, id uint) (*CaracteristicaResponse, error)
+	UpdateCaracteristica(ctx context.Context, id uint, req *UpdateCaracteristicaRequest) (*CaracteristicaResponse, error)
+	DeleteCaracteristica(ctx context.Context, id uint) error
+	ListByCategoria(ctx context.Context, query *ListCaracteristicasQuery) ([]CategoriaResponse, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new characteristic service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreateCaracteristica creates a new characteristic
+func (s *service) CreateCaracteristica(ctx context.Context, req *CreateCaracteristicaRequest) (*CaracteristicaResponse, error) {
+	nome := strings.TrimSpace(req.Nome)
+	if err := s.ensureUnique(ctx, 0, req.CategoriaID, nome); err != nil {
+		return nil, err
+	}
+
+	caracteristica := &imoveis.Caracteristica{
+		Nome:          nome,
+		CategoriaID:   req.CategoriaID,
+		CategoriaNome: strings.TrimSpace(req.CategoriaNome),
+	}
+	if err := s.repo.Create(ctx, caracteristica); err != nil {
+		return nil, fmt.Errorf("failed to create caracteristica: %w", err)
+	}
+
+	return toResponse(caracteristica), nil
+}
+
+// GetCaracteristica retrieves a characteristic by ID
+func (s *service) GetCaracteristica(ctx context.Context, id uint) (*CaracteristicaResponse, error) {
+	caracteristica, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find caracteristica: %w", err)
+	}
+	if caracteristica == nil {
+		return nil, ErrCaracteristicaNotFound
+	}
+	return toResponse(caracteristica), nil
+}
+
+// UpdateCaracteristica updates a characteristic
+func (s *service) UpdateCaracteristica(ctx context.Context, id uint, req *UpdateCaracteristicaRequest) (*CaracteristicaResponse, error) {
+	caracteristica, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find caracteristica: %w", err)
+	}
+	if caracteristica == nil {
+		return nil, ErrCaracteristicaNotFound
+	}
+
+	if req.Nome != "" {
+		caracteristica.Nome = strings.TrimSpace(req.Nome)
+	}
+	if req.CategoriaID != nil {
+		caracteristica.CategoriaID = *req.CategoriaID
+	}
+	if req.CategoriaNome != nil {
+		caracteristica.CategoriaNome = strings.TrimSpace(*req.CategoriaNome)
+	}
+
+	if err := s.ensureUnique(ctx, id, caracteristica.CategoriaID, caracteristica.Nome); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, caracteristica); err != nil {
+		return nil, fmt.Errorf("failed to update caracteristica: %w", err)
+	}
+
+	return toResponse(caracteristica), nil
+}
+
+// DeleteCaracteristica soft deletes a characteristic; it disappears from every property that uses it
+func (s *service) DeleteCaracteristica(ctx context.Context, id uint) error {
+	caracteristica, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find caracteristica: %w", err)
+	}
+	if caracteristica == nil {
+		return ErrCaracteristicaNotFound
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete caracteristica: %w", err)
+	}
+	return nil
+}
+
+// ListByCategoria returns the catalog grouped by category, in category order
+func (s *service) ListByCategoria(ctx context.Context, query *ListCaracteristicasQuery) ([]CategoriaResponse, error) {
+	caracteristicas, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list caracteristicas: %w", err)
+	}
+
+	return groupByCategoria(caracteristicas), nil
+}
+
+// ensureUnique rejects a name already used by another characteristic of the same category
+func (s *service) ensureUnique(ctx context.Context, id, categoriaID uint, nome string) error {
+	existing, err := s.repo.FindByNome(ctx, categoriaID, nome)
+	if err != nil {
+		return fmt.Errorf("failed to check caracteristica uniqueness: %w", err)
+	}
+	if existing != nil && existing.ID != id {
+		return ErrCaracteristicaExists
+	}
+	return nil
+}
+
+// groupByCategoria groups characteristics already ordered by categoria_id
+func groupByCategoria(caracteristicas []imoveis.Caracteristica) []CategoriaResponse {
+	groups := make([]CategoriaResponse, 0)
+	index := make(map[uint]int)
+
+	for i := range caracteristicas {
+		c := &caracteristicas[i]
+		pos, ok := index[c.CategoriaID]
+		if !ok {
+			pos = len(groups)
+			index[c.CategoriaID] = pos
+			groups = append(groups, CategoriaResponse{
+				CategoriaID:     c.CategoriaID,
+				CategoriaNome:   c.CategoriaNome,
+				Caracteristicas: make([]CaracteristicaResponse, 0),
+			})
+		}
+		if groups[pos].CategoriaNome == "" {
+			groups[pos].CategoriaNome = c.CategoriaNome
+		}
+		groups[pos].Caracteristicas = append(groups[pos].Caracteristicas, *toResponse(c))
+	}
+
+	return groups
+}
+
+func toResponse(c *imoveis.Caracteristica) *CaracteristicaResponse {
+	return &CaracteristicaResponse{
+		ID:            c.ID,
+		Nome:          c.Nome,
+		CategoriaID:   c.CategoriaID,
+		CategoriaNome: c.CategoriaNome,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+package caracteristicas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+type stubRepository struct {
+	items  []imoveis.Caracteristica
+	nextID uint
+}
+
+func (r *stubRepository) Create(_ context.Context, c *imoveis.Caracteristica) error {
+	r.nextID++
+	c.ID = r.nextID
+	r.items = append(r.items, *c)
+	return nil
+}
+
+func (r *stubRepository) FindByID(_ context.Context, id uint) (*imoveis.Caracteristica, error) {
+	for i := range r.items {
+		if r.items[i].ID == id {
+			c := r.items[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) FindByNome(_ context.Context, categoriaID uint, nome string) (*imoveis.Caracteristica, error) {
+	for i := range r.items {
+		if r.items[i].CategoriaID == categoriaID && strings.EqualFold(r.items[i].Nome, nome) {
+			c := r.items[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) Update(_ context.Context, c *imoveis.Caracteristica) error {
+	for i := range r.items {
+		if r.items[i].ID == c.ID {
+			r.items[i] = *c
+		}
+	}
+	return nil
+}
+
+func (r *stubRepository) Delete(_ context.Context, id uint) error {
+	for i := range r.items {
+		if r.items[i].ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *stubRepository) List(_ context.Context, _ *ListCaracteristicasQuery) ([]imoveis.Caracteristica, error) {
+	return r.items, nil
+}
+
+func TestCreateCaracteristica_RejectsDuplicateInCategoria(t *testing.T) {
+	svc := NewService(&stubRepository{})
+	ctx := context.Background()
+
+	_, err := svc.CreateCaracteristica(ctx, &CreateCaracteristicaRequest{Nome: "Piscina", CategoriaID: 1})
+	require.NoError(t, err)
+
+	_, err = svc.CreateCaracteristica(ctx, &CreateCaracteristicaRequest{Nome: " piscina ", CategoriaID: 1})
+	assert.ErrorIs(t, err, ErrCaracteristicaExists)
+
+	_, err = svc.CreateCaracteristica(ctx, &CreateCaracteristicaRequest{Nome: "Piscina", CategoriaID: 2})
+	assert.NoError(t, err)
+}
+
+func TestUpdateCaracteristica(t *testing.T) {
+	svc := NewService(&stubRepository{})
+	ctx := context.Background()
+
+	piscina, err := svc.CreateCaracteristica(ctx, &CreateCaracteristicaRequest{Nome: "Piscina", CategoriaID: 1})
+	require.NoError(t, err)
+	_, err = svc.CreateCaracteristica(ctx, &CreateCaracteristicaRequest{Nome: "Churrasqueira", CategoriaID: 1})
+	require.NoError(t, err)
+
+	t.Run("keeping its own name is not a conflict", func(t *testing.T) {
+		updated, err := svc.UpdateCaracteristica(ctx, piscina.ID, &UpdateCaracteristicaRequest{Nome: "PISCINA"})
+		require.NoError(t, err)
+		assert.Equal(t, "PISCINA", updated.Nome)
+	})
+
+	t.Run("taking another name in the categoria conflicts", func(t *testing.T) {
+		_, err := svc.UpdateCaracteristica(ctx, piscina.ID, &UpdateCaracteristicaRequest{Nome: "churrasqueira"})
+		assert.ErrorIs(t, err, ErrCaracteristicaExists)
+	})
+
+	t.Run("unknown ID", func(t *testing.T) {
+		_, err := svc.UpdateCaracteristica(ctx, 99, &UpdateCaracteristicaRequest{Nome: "Sauna"})
+		assert.ErrorIs(t, err, ErrCaracteristicaNotFound)
+	})
+}
+
+func TestListByCategoria_GroupsInRepositoryOrder(t *testing.T) {
+	repo := &stubRepository{items: []imoveis.Caracteristica{
+		{ID: 1, Nome: "Academia", CategoriaID: 1, CategoriaNome: "Lazer"},
+		{ID: 2, Nome: "Piscina", CategoriaID: 1, CategoriaNome: "Lazer"},
+		{ID: 3, Nome: "Portaria 24h", CategoriaID: 2, CategoriaNome: "Segurança"},
+		{ID: 4, Nome: "Varanda"},
+	}}
+	svc := NewService(repo)
+
+	groups, err := svc.ListByCategoria(context.Background(), &ListCaracteristicasQuery{})
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "Lazer", groups[0].CategoriaNome)
+	assert.Len(t, groups[0].Caracteristicas, 2)
+	assert.Equal(t, "Segurança", groups[1].CategoriaNome)
+	assert.Equal(t, uint(0), groups[2].CategoriaID)
+	assert.Equal(t, "Varanda", groups[2].Caracteristicas[0].Nome)
+}
@@ -12,16 +12,29 @@ import (
 )
 
 type Config struct {
-	App         AppConfig         `mapstructure:"app" yaml:"app"`
-	Database    DatabaseConfig    `mapstructure:"database" yaml:"database"`
-	JWT         JWTConfig         `mapstructure:"jwt" yaml:"jwt"`
-	Server      ServerConfig      `mapstructure:"server" yaml:"server"`
-	Logging     LoggingConfig     `mapstructure:"logging" yaml:"logging"`
-	Ratelimit   RateLimitConfig   `mapstructure:"ratelimit" yaml:"ratelimit"`
-	Migrations  MigrationsConfig  `mapstructure:"migrations" yaml:"migrations"`
-	Health      HealthConfig      `mapstructure:"health" yaml:"health"`
-	ExternalAPI ExternalAPIConfig `mapstructure:"externalapi" yaml:"externalapi"`
-	Email       EmailConfig       `mapstructure:"email" yaml:"email"`
+	App           AppConfig           `mapstructure:"app" yaml:"app"`
+	Database      DatabaseConfig      `mapstructure:"database" yaml:"database"`
+	JWT           JWTConfig           `mapstructure:"jwt" yaml:"jwt"`
+	TwoFactor     TwoFactorConfig     `mapstructure:"two_factor" yaml:"two_factor"`
+	Login         LoginConfig         `mapstructure:"login" yaml:"login"`
+	Invites       InvitesConfig       `mapstructure:"invites" yaml:"invites"`
+	OAuth         OAuthConfig         `mapstructure:"oauth" yaml:"oauth"`
+	Server        ServerConfig        `mapstructure:"server" yaml:"server"`
+	Logging       LoggingConfig       `mapstructure:"logging" yaml:"logging"`
+	Ratelimit     RateLimitConfig     `mapstructure:"ratelimit" yaml:"ratelimit"`
+	Migrations    MigrationsConfig    `mapstructure:"migrations" yaml:"migrations"`
+	Health        HealthConfig        `mapstructure:"health" yaml:"health"`
+	ExternalAPI   ExternalAPIConfig   `mapstructure:"externalapi" yaml:"externalapi"`
+	Email         EmailConfig         `mapstructure:"email" yaml:"email"`
+	Sliders       SlidersConfig       `mapstructure:"sliders" yaml:"sliders"`
+	ViaCEP        ViaCEPConfig        `mapstructure:"viacep" yaml:"viacep"`
+	Storage       StorageConfig       `mapstructure:"storage" yaml:"storage"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler" yaml:"scheduler"`
+	Analytics     AnalyticsConfig     `mapstructure:"analytics" yaml:"analytics"`
+	Cache         CacheConfig         `mapstructure:"cache" yaml:"cache"`
+	Archive       ArchiveConfig       `mapstructure:"archive" yaml:"archive"`
+	Notifications NotificationsConfig `mapstructure:"notifications" yaml:"notifications"`
+	Leads         LeadsConfig         `mapstructure:"leads" yaml:"leads"`
 }
 
 type AppConfig struct {
@@ -38,6 +51,11 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password" yaml:"password"`
 	Name     string `mapstructure:"name" yaml:"name"`
 	SSLMode  string `mapstructure:"sslmode" yaml:"sslmode"`
+
+	// TenancyMode none, schema or database. Only cmd/migrate supports schema and database; the API
+	// server refuses to start with them, as it serves every organizacao from the shared database.
+	TenancyMode  string `mapstructure:"tenancy_mode" yaml:"tenancy_mode"`
+	TenantPrefix string `mapstructure:"tenant_prefix" yaml:"tenant_prefix"`
 }
 
 type JWTConfig struct {
@@ -47,6 +65,56 @@ type JWTConfig struct {
 	TTLHours        int           `mapstructure:"ttlhours" yaml:"ttlhours"` // Deprecated: kept for backward compatibility
 }
 
+// TwoFactorConfig holds the TOTP two-factor authentication settings. Issuer names the account in
+// authenticator apps (app.name when empty). Users with one of RequiredRoles can only use the
+// protected API with tokens obtained through a 2FA login; until they enroll, only their account and
+// 2FA endpoints are reachable.
+type TwoFactorConfig struct {
+	Issuer        string   `mapstructure:"issuer" yaml:"issuer"`
+	RequiredRoles []string `mapstructure:"required_roles" yaml:"required_roles"`
+}
+
+// LoginConfig holds the brute-force protection of the login, on top of the global rate limit.
+// Failed logins are counted per account and per address over a sliding Window; reaching
+// MaxAccountAttempts or MaxIPAttempts (0 disables either) locks the account or address out for
+// Lockout. The owner of a locked account is emailed when notifications are enabled. Wrong 2FA
+// codes count as failed logins of the account too, and a two-factor challenge is invalidated after
+// MaxTwoFactorAttempts of them (0 disables). Store selects where the attempts are kept: memory
+// (per instance, reset on restart, so each replica allows the limits on its own) or database
+// (shared by every instance).
+type LoginConfig struct {
+	ProtectionEnabled    bool          `mapstructure:"protection_enabled" yaml:"protection_enabled"`
+	Store                string        `mapstructure:"store" yaml:"store"`
+	Window               time.Duration `mapstructure:"window" yaml:"window"`
+	MaxAccountAttempts   int           `mapstructure:"max_account_attempts" yaml:"max_account_attempts"`
+	MaxIPAttempts        int           `mapstructure:"max_ip_attempts" yaml:"max_ip_attempts"`
+	MaxTwoFactorAttempts int           `mapstructure:"max_two_factor_attempts" yaml:"max_two_factor_attempts"`
+	Lockout              time.Duration `mapstructure:"lockout" yaml:"lockout"`
+}
+
+// InvitesConfig holds the user invitations sent by admins. The invitation email links to AcceptURL
+// with a signed token in the token query parameter; the page posts it with the password chosen by
+// the invitee to /api/v1/auth/invitations/accept. Invitations expire after TTL.
+type InvitesConfig struct {
+	AcceptURL string        `mapstructure:"accept_url" yaml:"accept_url"`
+	TTL       time.Duration `mapstructure:"ttl" yaml:"ttl"`
+}
+
+// OAuthConfig holds the external identity providers users can sign in with
+type OAuthConfig struct {
+	Google GoogleOAuthConfig `mapstructure:"google" yaml:"google"`
+}
+
+// GoogleOAuthConfig enables the Google sign-in when ClientID is set. RedirectURL must be registered
+// in the Google console and point to /api/v1/auth/google/callback. Users are matched by their
+// verified email; AllowedDomains, when set, limits the sign-in to those email domains.
+type GoogleOAuthConfig struct {
+	ClientID       string   `mapstructure:"client_id" yaml:"client_id"`
+	ClientSecret   string   `mapstructure:"client_secret" yaml:"client_secret"`
+	RedirectURL    string   `mapstructure:"redirect_url" yaml:"redirect_url"`
+	AllowedDomains []string `mapstructure:"allowed_domains" yaml:"allowed_domains"`
+}
+
 type ServerConfig struct {
 	Port            string `mapstructure:"port" yaml:"port"`
 	ReadTimeout     int    `mapstructure:"readtimeout" yaml:"readtimeout"`
@@ -67,31 +135,212 @@ type RateLimitConfig struct {
 }
 
 type MigrationsConfig struct {
-	Directory   string `mapstructure:"directory" yaml:"directory"`
-	Timeout     int    `mapstructure:"timeout" yaml:"timeout"`
-	LockTimeout int    `mapstructure:"locktimeout" yaml:"locktimeout"`
+	Directory       string `mapstructure:"directory" yaml:"directory"`
+	Timeout         int    `mapstructure:"timeout" yaml:"timeout"`
+	LockTimeout     int    `mapstructure:"locktimeout" yaml:"locktimeout"`
+	RequireUpToDate bool   `mapstructure:"require_up_to_date" yaml:"require_up_to_date"`
 }
 
+// HealthConfig holds the readiness checks. SMTPCheckEnabled connects and authenticates to the SMTP
+// server on each check, and only applies when email is sent through SMTP with a host configured.
 type HealthConfig struct {
-	Timeout              int  `mapstructure:"timeout" yaml:"timeout"`
-	DatabaseCheckEnabled bool `mapstructure:"database_check_enabled" yaml:"database_check_enabled"`
+	Timeout               int  `mapstructure:"timeout" yaml:"timeout"`
+	DatabaseCheckEnabled  bool `mapstructure:"database_check_enabled" yaml:"database_check_enabled"`
+	MigrationCheckEnabled bool `mapstructure:"migration_check_enabled" yaml:"migration_check_enabled"`
+	SMTPCheckEnabled      bool `mapstructure:"smtp_check_enabled" yaml:"smtp_check_enabled"`
 }
 
+// ExternalAPIConfig holds the external properties API used by the importer. Driver names the
+// provider the API belongs to (pi8 when empty), so each organização points the importer at its own
+// CRM or portal. PageSize is the number
+// of properties requested per page of the published list. Incremental runs only import the
+// properties changed since the last successful run; a full run can still be requested. Workers
+// properties are imported at once, which also bounds the concurrent requests to the API.
+// RemovedPolicy is applied after a full run to the imported properties no longer listed by the
+// source: archive, delete, review (flag only) or none. Failed requests (5xx, 429, timeouts) are
+// retried up to MaxRetries times with a jittered backoff starting at RetryBaseMs; after
+// BreakerThreshold requests in a row fail, the run is aborted and the API is not called again for
+// BreakerCooldown seconds. Zero MaxRetries or BreakerThreshold turns them off. RateLimit caps the
+// requests per second sent to the API across all runs, allowing bursts of RateBurst; zero is
+// unlimited. DownloadImages stores the listing images in the storage instead of linking to them.
+// WebhookSecret signs the events the API pushes to the webhook; empty disables the webhook.
+// WebhookTolerance is how many seconds the signed timestamp of an event may be off the clock
+// before the event is rejected as a replay (default 300).
+// ConflictPolicy decides what imports do with the fields of a property edited by hand:
+// local_wins (the default) keeps them, remote_wins overwrites them and review keeps them while
+// holding the values of the source as conflicts to resolve.
+// LogFailedResponses logs the status and start of the body of the responses with an unexpected
+// status or malformed JSON, to see what the source sent.
+// Mappings translate the enum values of the source to local ones, keyed by field, so a change
+// in the taxonomy of the source is a configuration change.
 type ExternalAPIConfig struct {
+	Driver            string `mapstructure:"driver" yaml:"driver"`
 	BaseURL           string `mapstructure:"baseurl" yaml:"baseurl"`
 	APIKey            string `mapstructure:"apikey" yaml:"apikey"`
 	IntegrationSource string `mapstructure:"integration_source" yaml:"integration_source"`
 	TimeoutSeconds    int    `mapstructure:"timeout_seconds" yaml:"timeout_seconds"`
+	PageSize          int    `mapstructure:"page_size" yaml:"page_size"`
+	Incremental       bool   `mapstructure:"incremental" yaml:"incremental"`
+	Workers           int    `mapstructure:"workers" yaml:"workers"`
+	RemovedPolicy     string `mapstructure:"removed_policy" yaml:"removed_policy"`
+	MaxRetries        int    `mapstructure:"max_retries" yaml:"max_retries"`
+	RetryBaseMs       int    `mapstructure:"retry_base_ms" yaml:"retry_base_ms"`
+	BreakerThreshold  int    `mapstructure:"breaker_threshold" yaml:"breaker_threshold"`
+	BreakerCooldown   int    `mapstructure:"breaker_cooldown" yaml:"breaker_cooldown"`
+	RateLimit         int    `mapstructure:"rate_limit" yaml:"rate_limit"`
+	RateBurst         int    `mapstructure:"rate_burst" yaml:"rate_burst"`
+	DownloadImages    bool   `mapstructure:"download_images" yaml:"download_images"`
+	WebhookSecret     string `mapstructure:"webhook_secret" yaml:"webhook_secret"`
+	WebhookTolerance  int    `mapstructure:"webhook_tolerance" yaml:"webhook_tolerance"`
+	ConflictPolicy    string `mapstructure:"conflict_policy" yaml:"conflict_policy"`
+
+	LogFailedResponses bool `mapstructure:"log_failed_responses" yaml:"log_failed_responses"`
+
+	Mappings map[string]FieldMappingConfig `mapstructure:"mappings" yaml:"mappings"`
+}
+
+// FieldMappingConfig translates the values of one enum field of the external API. Values are
+// matched ignoring case and surrounding spaces; the unmatched ones go through Transform (upper,
+// lower or upper_snake, e.g. "Sala Comercial" to SALA_COMERCIAL). A value left empty, or outside
+// Allowed when it is set, becomes Default.
+type FieldMappingConfig struct {
+	Values    map[string]string `mapstructure:"values" yaml:"values"`
+	Transform string            `mapstructure:"transform" yaml:"transform"`
+	Allowed   []string          `mapstructure:"allowed" yaml:"allowed"`
+	Default   string            `mapstructure:"default" yaml:"default"`
 }
 
+// ViaCEPConfig holds the settings of the ViaCEP postal code lookup used by enderecos
+type ViaCEPConfig struct {
+	BaseURL        string `mapstructure:"baseurl" yaml:"baseurl"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds" yaml:"timeout_seconds"`
+}
+
+// StorageConfig holds the object storage that receives uploaded anexos. The local driver writes
+// to LocalDir and is meant for development; s3 works with AWS S3 and S3-compatible servers (MinIO).
+type StorageConfig struct {
+	Driver           string   `mapstructure:"driver" yaml:"driver"`
+	LocalDir         string   `mapstructure:"local_dir" yaml:"local_dir"`
+	Endpoint         string   `mapstructure:"endpoint" yaml:"endpoint"`
+	Region           string   `mapstructure:"region" yaml:"region"`
+	Bucket           string   `mapstructure:"bucket" yaml:"bucket"`
+	AccessKey        string   `mapstructure:"access_key" yaml:"access_key"`
+	SecretKey        string   `mapstructure:"secret_key" yaml:"secret_key"`
+	UsePathStyle     bool     `mapstructure:"use_path_style" yaml:"use_path_style"`
+	PublicURL        string   `mapstructure:"public_url" yaml:"public_url"`
+	MaxUploadBytes   int64    `mapstructure:"max_upload_bytes" yaml:"max_upload_bytes"`
+	AllowedMIMETypes []string `mapstructure:"allowed_mime_types" yaml:"allowed_mime_types"`
+	ImageWorkers     int      `mapstructure:"image_workers" yaml:"image_workers"`
+}
+
+// SchedulerConfig holds the background job that publishes and expires imoveis at their scheduled
+// dates. NotifyCorretor emails the corretor principal of each property it changes.
+type SchedulerConfig struct {
+	Enabled         bool `mapstructure:"enabled" yaml:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds" yaml:"interval_seconds"`
+	NotifyCorretor  bool `mapstructure:"notify_corretor" yaml:"notify_corretor"`
+}
+
+// AnalyticsConfig holds the refresh of the market analytics statistics. A zero RefreshSeconds
+// disables the periodic refresh; the preco_m2 maintenance task can still be run from the admin API.
+type AnalyticsConfig struct {
+	RefreshSeconds int `mapstructure:"refresh_seconds" yaml:"refresh_seconds"`
+}
+
+// ArchiveConfig holds the job that archives published imoveis not updated or imported for a while.
+// DefaultDays applies to organizacoes without their own dias_arquivamento, 0 never archives them.
+// A zero IntervalSeconds disables the periodic run; the admin endpoint and the archive_stale
+// maintenance task still work. NotifyCorretor emails each corretor a summary.
+type ArchiveConfig struct {
+	DefaultDays     int  `mapstructure:"default_days" yaml:"default_days"`
+	IntervalSeconds int  `mapstructure:"interval_seconds" yaml:"interval_seconds"`
+	NotifyCorretor  bool `mapstructure:"notify_corretor" yaml:"notify_corretor"`
+}
+
+// NotificationsConfig holds the emails sent on domain events: import runs to the admins, new leads,
+// publications and price changes to the corretor principal. Events are handled in the background;
+// QueueSize bounds the ones waiting, further events are dropped.
+type NotificationsConfig struct {
+	Enabled   bool `mapstructure:"enabled" yaml:"enabled"`
+	QueueSize int  `mapstructure:"queue_size" yaml:"queue_size"`
+}
+
+// LeadsConfig holds the protection of the public contact requests. Each IP may leave
+// RateLimitRequests leads per RateLimitWindow (0 disables the limit). With CaptchaSecret set, leads
+// must carry a captcha response, checked against CaptchaVerifyURL (reCAPTCHA by default; hCaptcha
+// and Turnstile share its API).
+type LeadsConfig struct {
+	RateLimitRequests int           `mapstructure:"rate_limit_requests" yaml:"rate_limit_requests"`
+	RateLimitWindow   time.Duration `mapstructure:"rate_limit_window" yaml:"rate_limit_window"`
+	CaptchaSecret     string        `mapstructure:"captcha_secret" yaml:"captcha_secret"`
+	CaptchaVerifyURL  string        `mapstructure:"captcha_verify_url" yaml:"captcha_verify_url"`
+}
+
+// CacheConfig holds the response cache in front of the public imovel reads and slider location
+// lookups. The memory driver is per instance and bounded by Size entries; none disables caching.
+// Writes through the API and the importer invalidate entries right away, other changes (e.g. a
+// price edited in precos) show up after at most TTLSeconds, or sliders.cache_ttl for sliders.
+type CacheConfig struct {
+	Driver     string `mapstructure:"driver" yaml:"driver"`
+	Size       int    `mapstructure:"size" yaml:"size"`
+	TTLSeconds int    `mapstructure:"ttl_seconds" yaml:"ttl_seconds"`
+}
+
+// EmailConfig holds the provider emails are sent through and the outbox queue. Provider is smtp
+// (Host to UseStartTLS), ses (the SES* fields) or sendgrid (the SendGrid* fields). WebhookSecret
+// enables the delivery status webhook of ses and sendgrid; it is passed as the token query
+// parameter. Queued emails are sent every QueueIntervalSeconds; failed sends are retried after
+// RetryBaseSeconds, doubling each time, and dead-lettered after MaxAttempts. Marketing emails link
+// to UnsubscribeURL with the recipient and a token signed with UnsubscribeSecret; both are
+// required to send them. DefaultLocale is the locale of the templates without a locale suffix and
+// the fallback when no variant matches the requested one.
 type EmailConfig struct {
-	Host        string `mapstructure:"host" yaml:"host"`
-	Port        int    `mapstructure:"port" yaml:"port"`
-	Username    string `mapstructure:"username" yaml:"username"`
-	Password    string `mapstructure:"password" yaml:"password"`
-	From        string `mapstructure:"from" yaml:"from"`
-	UseTLS      bool   `mapstructure:"use_tls" yaml:"use_tls"`
-	UseStartTLS bool   `mapstructure:"use_starttls" yaml:"use_starttls"`
+	Provider             string `mapstructure:"provider" yaml:"provider"`
+	Host                 string `mapstructure:"host" yaml:"host"`
+	Port                 int    `mapstructure:"port" yaml:"port"`
+	Username             string `mapstructure:"username" yaml:"username"`
+	Password             string `mapstructure:"password" yaml:"password"`
+	From                 string `mapstructure:"from" yaml:"from"`
+	UseTLS               bool   `mapstructure:"use_tls" yaml:"use_tls"`
+	UseStartTLS          bool   `mapstructure:"use_starttls" yaml:"use_starttls"`
+	SESRegion            string `mapstructure:"ses_region" yaml:"ses_region"`
+	SESAccessKey         string `mapstructure:"ses_access_key" yaml:"ses_access_key"`
+	SESSecretKey         string `mapstructure:"ses_secret_key" yaml:"ses_secret_key"`
+	SESEndpoint          string `mapstructure:"ses_endpoint" yaml:"ses_endpoint"`
+	SESConfigurationSet  string `mapstructure:"ses_configuration_set" yaml:"ses_configuration_set"`
+	SendGridAPIKey       string `mapstructure:"sendgrid_api_key" yaml:"sendgrid_api_key"`
+	SendGridEndpoint     string `mapstructure:"sendgrid_endpoint" yaml:"sendgrid_endpoint"`
+	WebhookSecret        string `mapstructure:"webhook_secret" yaml:"webhook_secret"`
+	QueueIntervalSeconds int    `mapstructure:"queue_interval_seconds" yaml:"queue_interval_seconds"`
+	MaxAttempts          int    `mapstructure:"max_attempts" yaml:"max_attempts"`
+	RetryBaseSeconds     int    `mapstructure:"retry_base_seconds" yaml:"retry_base_seconds"`
+	UnsubscribeURL       string `mapstructure:"unsubscribe_url" yaml:"unsubscribe_url"`
+	UnsubscribeSecret    string `mapstructure:"unsubscribe_secret" yaml:"unsubscribe_secret"`
+	DefaultLocale        string `mapstructure:"default_locale" yaml:"default_locale"`
+}
+
+// SlidersConfig holds the per-type limits, preview, caching and purge settings of the slider service.
+// CacheTTL bounds how long a location lookup is served from the cache (cache.driver) and sets the
+// max-age clients and CDNs may reuse it for. PurgeAfterDays is how long deleted sliders and items
+// stay restorable before the purge_sliders maintenance task removes them; 0 keeps them.
+type SlidersConfig struct {
+	Slideshow       SliderLimitsConfig `mapstructure:"slideshow" yaml:"slideshow"`
+	Carousel        SliderLimitsConfig `mapstructure:"carousel" yaml:"carousel"`
+	Static          SliderLimitsConfig `mapstructure:"static" yaml:"static"`
+	PreviewTokenTTL time.Duration      `mapstructure:"preview_token_ttl" yaml:"preview_token_ttl"`
+	CacheTTL        time.Duration      `mapstructure:"cache_ttl" yaml:"cache_ttl"`
+	PurgeAfterDays  int                `mapstructure:"purge_after_days" yaml:"purge_after_days"`
+}
+
+// SliderLimitsConfig holds the constraints for a single slider type. A zero value disables the check.
+type SliderLimitsConfig struct {
+	MaxItems         int   `mapstructure:"max_items" yaml:"max_items"`
+	MaxContentLength int   `mapstructure:"max_content_length" yaml:"max_content_length"`
+	MinImageWidth    int   `mapstructure:"min_image_width" yaml:"min_image_width"`
+	MinImageHeight   int   `mapstructure:"min_image_height" yaml:"min_image_height"`
+	MaxImageWidth    int   `mapstructure:"max_image_width" yaml:"max_image_width"`
+	MaxImageHeight   int   `mapstructure:"max_image_height" yaml:"max_image_height"`
+	MaxImageBytes    int64 `mapstructure:"max_image_bytes" yaml:"max_image_bytes"`
 }
 
 // LoadConfig loads configuration using Viper. If configPath is non-empty it
@@ -169,6 +418,8 @@ func bindEnvVariables(v *viper.Viper) {
 		"database.password":              "DATABASE_PASSWORD",
 		"database.name":                  "DATABASE_NAME",
 		"database.sslmode":               "DATABASE_SSLMODE",
+		"database.tenancy_mode":          "DATABASE_TENANCY_MODE",
+		"database.tenant_prefix":         "DATABASE_TENANT_PREFIX",
 		"jwt.secret":                     "JWT_SECRET",
 		"jwt.access_token_ttl":           "JWT_ACCESS_TOKEN_TTL",
 		"jwt.refresh_token_ttl":          "JWT_REFRESH_TOKEN_TTL",
@@ -186,12 +437,31 @@ func bindEnvVariables(v *viper.Viper) {
 		"migrations.directory":           "MIGRATIONS_DIRECTORY",
 		"migrations.timeout":             "MIGRATIONS_TIMEOUT",
 		"migrations.locktimeout":         "MIGRATIONS_LOCKTIMEOUT",
+		"migrations.require_up_to_date":  "MIGRATIONS_REQUIRE_UP_TO_DATE",
 		"health.timeout":                 "HEALTH_TIMEOUT",
 		"health.database_check_enabled":  "HEALTH_DATABASE_CHECK_ENABLED",
+		"health.migration_check_enabled": "HEALTH_MIGRATION_CHECK_ENABLED",
+		"health.smtp_check_enabled":      "HEALTH_SMTP_CHECK_ENABLED",
+		"externalapi.driver":             "EXTERNAL_API_DRIVER",
 		"externalapi.baseurl":            "EXTERNAL_API_BASEURL",
 		"externalapi.apikey":             "EXTERNAL_API_KEY",
 		"externalapi.integration_source": "EXTERNAL_API_INTEGRATION_SOURCE",
 		"externalapi.timeout_seconds":    "EXTERNAL_API_TIMEOUT_SECONDS",
+		"externalapi.page_size":          "EXTERNAL_API_PAGE_SIZE",
+		"externalapi.incremental":        "EXTERNAL_API_INCREMENTAL",
+		"externalapi.workers":            "EXTERNAL_API_WORKERS",
+		"externalapi.removed_policy":     "EXTERNAL_API_REMOVED_POLICY",
+		"externalapi.max_retries":        "EXTERNAL_API_MAX_RETRIES",
+		"externalapi.retry_base_ms":      "EXTERNAL_API_RETRY_BASE_MS",
+		"externalapi.breaker_threshold":  "EXTERNAL_API_BREAKER_THRESHOLD",
+		"externalapi.breaker_cooldown":   "EXTERNAL_API_BREAKER_COOLDOWN",
+		"externalapi.rate_limit":         "EXTERNAL_API_RATE_LIMIT",
+		"externalapi.rate_burst":         "EXTERNAL_API_RATE_BURST",
+		"externalapi.download_images":    "EXTERNAL_API_DOWNLOAD_IMAGES",
+		"externalapi.webhook_secret":     "EXTERNAL_API_WEBHOOK_SECRET",
+		"externalapi.webhook_tolerance":  "EXTERNAL_API_WEBHOOK_TOLERANCE",
+		"externalapi.conflict_policy":    "EXTERNAL_API_CONFLICT_POLICY",
+		"email.provider":                 "EMAIL_PROVIDER",
 		"email.host":                     "EMAIL_HOST",
 		"email.port":                     "EMAIL_PORT",
 		"email.username":                 "EMAIL_USERNAME",
@@ -199,10 +469,85 @@ func bindEnvVariables(v *viper.Viper) {
 		"email.from":                     "EMAIL_FROM",
 		"email.use_tls":                  "EMAIL_USE_TLS",
 		"email.use_starttls":             "EMAIL_USE_STARTTLS",
+		"email.ses_region":               "EMAIL_SES_REGION",
+		"email.ses_access_key":           "EMAIL_SES_ACCESS_KEY",
+		"email.ses_secret_key":           "EMAIL_SES_SECRET_KEY",
+		"email.ses_endpoint":             "EMAIL_SES_ENDPOINT",
+		"email.ses_configuration_set":    "EMAIL_SES_CONFIGURATION_SET",
+		"email.sendgrid_api_key":         "EMAIL_SENDGRID_API_KEY",
+		"email.sendgrid_endpoint":        "EMAIL_SENDGRID_ENDPOINT",
+		"email.webhook_secret":           "EMAIL_WEBHOOK_SECRET",
+		"email.queue_interval_seconds":   "EMAIL_QUEUE_INTERVAL_SECONDS",
+		"email.max_attempts":             "EMAIL_MAX_ATTEMPTS",
+		"email.retry_base_seconds":       "EMAIL_RETRY_BASE_SECONDS",
+		"email.unsubscribe_url":          "EMAIL_UNSUBSCRIBE_URL",
+		"email.unsubscribe_secret":       "EMAIL_UNSUBSCRIBE_SECRET",
+		"email.default_locale":           "EMAIL_DEFAULT_LOCALE",
+		"two_factor.issuer":              "TWO_FACTOR_ISSUER",
+		"two_factor.required_roles":      "TWO_FACTOR_REQUIRED_ROLES",
+		"sliders.preview_token_ttl":      "SLIDERS_PREVIEW_TOKEN_TTL",
+		"sliders.cache_ttl":              "SLIDERS_CACHE_TTL",
+		"sliders.purge_after_days":       "SLIDERS_PURGE_AFTER_DAYS",
+		"viacep.baseurl":                 "VIACEP_BASEURL",
+		"viacep.timeout_seconds":         "VIACEP_TIMEOUT_SECONDS",
+		"storage.driver":                 "STORAGE_DRIVER",
+		"storage.local_dir":              "STORAGE_LOCAL_DIR",
+		"storage.endpoint":               "STORAGE_ENDPOINT",
+		"storage.region":                 "STORAGE_REGION",
+		"storage.bucket":                 "STORAGE_BUCKET",
+		"storage.access_key":             "STORAGE_ACCESS_KEY",
+		"storage.secret_key":             "STORAGE_SECRET_KEY",
+		"storage.use_path_style":         "STORAGE_USE_PATH_STYLE",
+		"storage.public_url":             "STORAGE_PUBLIC_URL",
+		"storage.max_upload_bytes":       "STORAGE_MAX_UPLOAD_BYTES",
+		"storage.allowed_mime_types":     "STORAGE_ALLOWED_MIME_TYPES",
+		"storage.image_workers":          "STORAGE_IMAGE_WORKERS",
+		"scheduler.enabled":              "SCHEDULER_ENABLED",
+		"scheduler.interval_seconds":     "SCHEDULER_INTERVAL_SECONDS",
+		"scheduler.notify_corretor":      "SCHEDULER_NOTIFY_CORRETOR",
+		"analytics.refresh_seconds":      "ANALYTICS_REFRESH_SECONDS",
+		"cache.driver":                   "CACHE_DRIVER",
+		"cache.size":                     "CACHE_SIZE",
+		"cache.ttl_seconds":              "CACHE_TTL_SECONDS",
+		"archive.default_days":           "ARCHIVE_DEFAULT_DAYS",
+		"archive.interval_seconds":       "ARCHIVE_INTERVAL_SECONDS",
+		"archive.notify_corretor":        "ARCHIVE_NOTIFY_CORRETOR",
+		"notifications.enabled":          "NOTIFICATIONS_ENABLED",
+		"notifications.queue_size":       "NOTIFICATIONS_QUEUE_SIZE",
+
+		"externalapi.log_failed_responses": "EXTERNAL_API_LOG_FAILED_RESPONSES",
+		"login.protection_enabled":         "LOGIN_PROTECTION_ENABLED",
+		"login.store":                      "LOGIN_STORE",
+		"login.window":                     "LOGIN_WINDOW",
+		"login.max_account_attempts":       "LOGIN_MAX_ACCOUNT_ATTEMPTS",
+		"login.max_ip_attempts":            "LOGIN_MAX_IP_ATTEMPTS",
+		"login.max_two_factor_attempts":    "LOGIN_MAX_TWO_FACTOR_ATTEMPTS",
+		"login.lockout":                    "LOGIN_LOCKOUT",
+		"invites.accept_url":               "INVITES_ACCEPT_URL",
+		"invites.ttl":                      "INVITES_TTL",
+		"oauth.google.client_id":           "OAUTH_GOOGLE_CLIENT_ID",
+		"oauth.google.client_secret":       "OAUTH_GOOGLE_CLIENT_SECRET",
+		"oauth.google.redirect_url":        "OAUTH_GOOGLE_REDIRECT_URL",
+		"oauth.google.allowed_domains":     "OAUTH_GOOGLE_ALLOWED_DOMAINS",
+		"leads.rate_limit_requests":        "LEADS_RATE_LIMIT_REQUESTS",
+		"leads.rate_limit_window":          "LEADS_RATE_LIMIT_WINDOW",
+		"leads.captcha_secret":             "LEADS_CAPTCHA_SECRET",
+		"leads.captcha_verify_url":         "LEADS_CAPTCHA_VERIFY_URL",
 	}
 	for key, env := range envBindings {
 		_ = v.BindEnv(key, env)
 	}
+
+	// Slider limits follow SLIDERS_<TYPE>_<FIELD>, e.g. SLIDERS_CAROUSEL_MAX_ITEMS
+	for _, sliderType := range []string{"slideshow", "carousel", "static"} {
+		for _, field := range []string{
+			"max_items", "max_content_length",
+			"min_image_width", "min_image_height", "max_image_width", "max_image_height", "max_image_bytes",
+		} {
+			key := fmt.Sprintf("sliders.%s.%s", sliderType, field)
+			_ = v.BindEnv(key, strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+		}
+	}
 }
 
 func (l *LoggingConfig) GetLogLevel() slog.Level {
@@ -843,3 +843,17 @@ func TestValidate_JWTSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_LoginStore(t *testing.T) {
+	for _, store := range []string{"", "memory", "database"} {
+		cfg := NewTestConfig()
+		cfg.Login.Store = store
+		assert.NoError(t, cfg.Validate(), store)
+	}
+
+	cfg := NewTestConfig()
+	cfg.Login.Store = "redis"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "login.store must be one of memory, database")
+}
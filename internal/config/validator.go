@@ -2,8 +2,13 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+
+	"golang.org/x/text/language"
 )
 
+var tenantPrefixPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
 func (c *Config) Validate() error {
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET environment variable is required - generate with: make generate-jwt-secret")
@@ -20,6 +25,17 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("database.host is required")
 	}
 
+	switch c.Database.TenancyMode {
+	case "", "none", "schema", "database":
+	default:
+		return fmt.Errorf("database.tenancy_mode must be one of none, schema, database")
+	}
+
+	// The prefix is interpolated into CREATE SCHEMA/DATABASE statements
+	if c.Database.TenantPrefix != "" && !tenantPrefixPattern.MatchString(c.Database.TenantPrefix) {
+		return fmt.Errorf("database.tenant_prefix must contain only lowercase letters, digits and underscores")
+	}
+
 	if c.Server.ReadTimeout < 0 {
 		return fmt.Errorf("server.readtimeout must be non-negative")
 	}
@@ -40,6 +56,122 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server.maxheaderbytes must be non-negative")
 	}
 
+	for name, limits := range map[string]SliderLimitsConfig{
+		"slideshow": c.Sliders.Slideshow,
+		"carousel":  c.Sliders.Carousel,
+		"static":    c.Sliders.Static,
+	} {
+		if limits.MaxItems < 0 || limits.MaxContentLength < 0 || limits.MaxImageBytes < 0 ||
+			limits.MinImageWidth < 0 || limits.MinImageHeight < 0 || limits.MaxImageWidth < 0 || limits.MaxImageHeight < 0 {
+			return fmt.Errorf("sliders.%s limits must be non-negative", name)
+		}
+	}
+
+	if c.Sliders.CacheTTL < 0 {
+		return fmt.Errorf("sliders.cache_ttl must be non-negative")
+	}
+	if c.Sliders.PurgeAfterDays < 0 {
+		return fmt.Errorf("sliders.purge_after_days must be non-negative")
+	}
+
+	switch c.ExternalAPI.Driver {
+	case "", "pi8":
+	default:
+		return fmt.Errorf("externalapi.driver must be pi8")
+	}
+
+	if c.ExternalAPI.PageSize < 0 || c.ExternalAPI.Workers < 0 {
+		return fmt.Errorf("externalapi.page_size and externalapi.workers must be non-negative")
+	}
+
+	if c.ExternalAPI.MaxRetries < 0 || c.ExternalAPI.RetryBaseMs < 0 || c.ExternalAPI.BreakerThreshold < 0 || c.ExternalAPI.BreakerCooldown < 0 {
+		return fmt.Errorf("externalapi retry and breaker settings must be non-negative")
+	}
+
+	if c.ExternalAPI.RateLimit < 0 || c.ExternalAPI.RateBurst < 0 {
+		return fmt.Errorf("externalapi.rate_limit and externalapi.rate_burst must be non-negative")
+	}
+
+	switch c.ExternalAPI.RemovedPolicy {
+	case "", "none", "archive", "delete", "review":
+	default:
+		return fmt.Errorf("externalapi.removed_policy must be one of none, archive, delete or review")
+	}
+
+	if c.ViaCEP.TimeoutSeconds < 0 {
+		return fmt.Errorf("viacep.timeout_seconds must be non-negative")
+	}
+
+	switch c.Storage.Driver {
+	case "", "local":
+	case "s3":
+		if c.Storage.Bucket == "" || c.Storage.Region == "" {
+			return fmt.Errorf("storage.bucket and storage.region are required for the s3 driver")
+		}
+	default:
+		return fmt.Errorf("storage.driver must be one of local, s3")
+	}
+
+	if c.Storage.MaxUploadBytes < 0 {
+		return fmt.Errorf("storage.max_upload_bytes must be non-negative")
+	}
+
+	if c.Storage.ImageWorkers < 0 {
+		return fmt.Errorf("storage.image_workers must be non-negative")
+	}
+
+	if c.Scheduler.IntervalSeconds < 0 {
+		return fmt.Errorf("scheduler.interval_seconds must be non-negative")
+	}
+
+	if c.Analytics.RefreshSeconds < 0 {
+		return fmt.Errorf("analytics.refresh_seconds must be non-negative")
+	}
+
+	switch c.Cache.Driver {
+	case "", "memory", "none":
+	default:
+		return fmt.Errorf("cache.driver must be one of memory, none")
+	}
+
+	if c.Cache.Size < 0 || c.Cache.TTLSeconds < 0 {
+		return fmt.Errorf("cache.size and cache.ttl_seconds must be non-negative")
+	}
+
+	switch c.Login.Store {
+	case "", "memory", "database":
+	default:
+		return fmt.Errorf("login.store must be one of memory, database")
+	}
+
+	switch c.Email.Provider {
+	case "", "smtp", "ses", "sendgrid":
+	default:
+		return fmt.Errorf("email.provider must be one of smtp, ses, sendgrid")
+	}
+
+	if c.Email.QueueIntervalSeconds < 0 || c.Email.MaxAttempts < 0 || c.Email.RetryBaseSeconds < 0 {
+		return fmt.Errorf("email.queue_interval_seconds, email.max_attempts and email.retry_base_seconds must be non-negative")
+	}
+
+	if c.Email.DefaultLocale != "" {
+		if _, err := language.Parse(c.Email.DefaultLocale); err != nil {
+			return fmt.Errorf("email.default_locale must be a BCP 47 language tag")
+		}
+	}
+
+	if c.Email.UnsubscribeURL != "" && c.Email.UnsubscribeSecret == "" {
+		return fmt.Errorf("email.unsubscribe_secret is required when email.unsubscribe_url is set")
+	}
+
+	if c.Archive.DefaultDays < 0 || c.Archive.IntervalSeconds < 0 {
+		return fmt.Errorf("archive.default_days and archive.interval_seconds must be non-negative")
+	}
+
+	if c.Notifications.QueueSize < 0 {
+		return fmt.Errorf("notifications.queue_size must be non-negative")
+	}
+
 	if c.App.Environment == "production" {
 		if c.Database.Password == "" {
 			return fmt.Errorf("database.password is required in production")
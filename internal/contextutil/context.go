@@ -1,6 +1,7 @@
 package contextutil
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -98,6 +99,24 @@ func HasRole(c *gin.Context, role string) bool {
 	return false
 }
 
+// HasPermission checks if any of the user's roles grants permission
+func HasPermission(c *gin.Context, permission string) bool {
+	claims := GetUser(c)
+	if claims == nil {
+		return false
+	}
+	return auth.HasPermission(claims.Roles, permission)
+}
+
+// GetCorretorID retrieves the corretor principal the user is scoped to, 0 when there is none
+func GetCorretorID(c *gin.Context) uint {
+	claims := GetUser(c)
+	if claims == nil {
+		return 0
+	}
+	return claims.CorretorID
+}
+
 // GetRoles retrieves user roles from context
 func GetRoles(c *gin.Context) []string {
 	claims := GetUser(c)
@@ -111,3 +130,32 @@ func GetRoles(c *gin.Context) []string {
 func IsAdmin(c *gin.Context) bool {
 	return HasRole(c, "admin")
 }
+
+type userIDKey struct{}
+
+// WithUserID returns a request context carrying the authenticated user's ID, for layers below
+// the handlers that have no access to the gin context
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext extracts the user ID set by WithUserID
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(userIDKey{}).(uint)
+	return id, ok && id > 0
+}
+
+type corretorScopeKey struct{}
+
+// WithCorretorScope returns a request context limited to the imoveis of the corretor principal
+// corretorID; 0 means the user is not linked to one and may change no imovel
+func WithCorretorScope(ctx context.Context, corretorID uint) context.Context {
+	return context.WithValue(ctx, corretorScopeKey{}, corretorID)
+}
+
+// CorretorScopeFromContext extracts the corretor set by WithCorretorScope; ok is false for
+// unscoped requests
+func CorretorScopeFromContext(ctx context.Context) (corretorID uint, ok bool) {
+	corretorID, ok = ctx.Value(corretorScopeKey{}).(uint)
+	return corretorID, ok
+}
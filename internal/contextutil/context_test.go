@@ -1,6 +1,7 @@
 package contextutil
 
 import (
+	"context"
 	"net/http/httptest"
 	"testing"
 
@@ -571,3 +572,16 @@ func TestIsAdmin(t *testing.T) {
 		})
 	}
 }
+
+func TestUserIDFromContext(t *testing.T) {
+	id, ok := UserIDFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, uint(0), id)
+
+	id, ok = UserIDFromContext(WithUserID(context.Background(), 7))
+	assert.True(t, ok)
+	assert.Equal(t, uint(7), id)
+
+	_, ok = UserIDFromContext(WithUserID(context.Background(), 0))
+	assert.False(t, ok)
+}
@@ -0,0 +1,72 @@
+package corretores
+
+import (
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// CreateCorretorRequest represents real estate agent creation request
+type CreateCorretorRequest struct {
+	Nome           string   `json:"nome" binding:"required,min=2,max=255"`
+	Email          string   `json:"email" binding:"required,email,max=255"`
+	Whatsapp       string   `json:"whatsapp" binding:"omitempty,max=20"`
+	Idiomas        []string `json:"idiomas" binding:"omitempty,max=20,dive,min=2,max=50"`
+	BairrosAtuacao []string `json:"bairrosAtuacao" binding:"omitempty,max=100,dive,min=2,max=100"`
+	OrganizacaoID  uint     `json:"organizacao_id" binding:"omitempty"`
+}
+
+// UpdateCorretorRequest represents real estate agent update request. Empty strings and absent
+// arrays are left untouched; an empty array clears it and organizacao_id 0 detaches the agency.
+type UpdateCorretorRequest struct {
+	Nome           string   `json:"nome" binding:"omitempty,min=2,max=255"`
+	Email          string   `json:"email" binding:"omitempty,email,max=255"`
+	Whatsapp       *string  `json:"whatsapp" binding:"omitempty,max=20"`
+	Idiomas        []string `json:"idiomas" binding:"omitempty,max=20,dive,min=2,max=50"`
+	BairrosAtuacao []string `json:"bairrosAtuacao" binding:"omitempty,max=100,dive,min=2,max=100"`
+	OrganizacaoID  *uint    `json:"organizacao_id" binding:"omitempty"`
+}
+
+// FotoRequest sets the profile picture of a real estate agent
+type FotoRequest struct {
+	URL     string `json:"url" binding:"required,url"`
+	Nome    string `json:"nome" binding:"omitempty,max=255"`
+	Tipo    string `json:"tipo" binding:"omitempty,max=100"`
+	Tamanho int64  `json:"tamanho" binding:"min=0"`
+}
+
+// CorretorListQuery represents query parameters for listing real estate agents
+type CorretorListQuery struct {
+	Page          int    `form:"page,default=1" binding:"min=1"`
+	Limit         int    `form:"limit,default=10" binding:"min=1,max=100"`
+	Nome          string `form:"nome" binding:"omitempty,max=255"`
+	OrganizacaoID uint   `form:"organizacao_id" binding:"omitempty"`
+	Ativo         *bool  `form:"ativo" binding:"omitempty"`
+}
+
+// CorretorResponse represents real estate agent response
+type CorretorResponse struct {
+	ID             uint                         `json:"id"`
+	Nome           string                       `json:"nome"`
+	Email          string                       `json:"email"`
+	Whatsapp       string                       `json:"whatsapp"`
+	Foto           *imoveis.AnexoResponse       `json:"foto,omitempty"`
+	Idiomas        []string                     `json:"idiomas"`
+	BairrosAtuacao []string                     `json:"bairrosAtuacao"`
+	OrganizacaoID  uint                         `json:"organizacao_id,omitempty"`
+	Organizacao    *imoveis.OrganizacaoResponse `json:"organizacao,omitempty"`
+	Ativo          bool                         `json:"ativo"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	UpdatedAt      time.Time                    `json:"updated_at"`
+}
+
+// CorretorListResponse represents paginated real estate agent list response
+type CorretorListResponse struct {
+	Total   int64              `json:"total"`
+	Page    int                `json:"page"`
+	Limit   int                `json:"limit"`
+	Pages   int64              `json:"pages"`
+	HasNext bool               `json:"hasNext"`
+	HasPrev bool               `json:"hasPrev"`
+	Results []CorretorResponse `json:"results"`
+}
@@ -0,0 +1,290 @@
+package corretores
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for real estate agent operations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new real estate agent handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type uriRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Create real estate agent
+// @Description Create a new active corretor principal
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateCorretorRequest true "Corretor creation request"
+// @Success 201 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores [post]
+func (h *Handler) CreateCorretor(c *gin.Context) {
+	var req CreateCorretorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	corretor, err := h.service.CreateCorretor(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(corretor))
+}
+
+// @Summary List real estate agents
+// @Description List corretores with pagination, filtered by name, organizacao and status
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param nome query string false "Filter by name (partial, case-insensitive)"
+// @Param organizacao_id query int false "Filter by organizacao"
+// @Param ativo query bool false "Filter by active status"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores [get]
+func (h *Handler) ListCorretores(c *gin.Context) {
+	var query CorretorListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListCorretores(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary List agents of an agency
+// @Description List the corretores of an organizacao, with the same filters as the corretores listing
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Param id path uint true "Organizacao ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param nome query string false "Filter by name (partial, case-insensitive)"
+// @Param ativo query bool false "Filter by active status"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes/{id}/corretores [get]
+func (h *Handler) ListByOrganizacao(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query CorretorListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	query.OrganizacaoID = uri.ID
+
+	result, err := h.service.ListCorretores(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get real estate agent by ID
+// @Description Retrieve a corretor with its foto and organizacao
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Param id path uint true "Corretor ID"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id} [get]
+func (h *Handler) GetCorretor(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	corretor, err := h.service.GetCorretor(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(corretor))
+}
+
+// @Summary Update real estate agent
+// @Description Update the provided fields of a corretor; an empty idiomas/bairrosAtuacao array clears it and organizacao_id 0 detaches the organizacao
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Corretor ID"
+// @Param request body UpdateCorretorRequest true "Corretor update request"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id} [put]
+func (h *Handler) UpdateCorretor(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateCorretorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	corretor, err := h.service.UpdateCorretor(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(corretor))
+}
+
+// @Summary Deactivate real estate agent
+// @Description Mark a corretor as inactive; its properties are kept
+// @Tags corretores
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Corretor ID"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id}/deactivate [post]
+func (h *Handler) DeactivateCorretor(c *gin.Context) {
+	h.setAtivo(c, false)
+}
+
+// @Summary Activate real estate agent
+// @Description Mark a previously deactivated corretor as active again
+// @Tags corretores
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Corretor ID"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id}/activate [post]
+func (h *Handler) ActivateCorretor(c *gin.Context) {
+	h.setAtivo(c, true)
+}
+
+func (h *Handler) setAtivo(c *gin.Context, ativo bool) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	corretor, err := h.service.SetAtivo(c.Request.Context(), uri.ID, ativo)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(corretor))
+}
+
+// @Summary Set real estate agent photo
+// @Description Replace the foto of a corretor; the previous foto attachment is removed
+// @Tags corretores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Corretor ID"
+// @Param request body FotoRequest true "Photo data"
+// @Success 200 {object} errors.Response{success=bool,data=CorretorResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id}/foto [put]
+func (h *Handler) SetFoto(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req FotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	corretor, err := h.service.SetFoto(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(corretor))
+}
+
+// @Summary Remove real estate agent photo
+// @Description Remove the foto of a corretor
+// @Tags corretores
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Corretor ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id}/foto [delete]
+func (h *Handler) RemoveFoto(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.RemoveFoto(c.Request.Context(), uri.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCorretorNotFound):
+		_ = c.Error(apiErrors.NotFound("Corretor not found"))
+	case errors.Is(err, ErrFotoNotFound):
+		_ = c.Error(apiErrors.NotFound(err.Error()))
+	case errors.Is(err, ErrEmailExists):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
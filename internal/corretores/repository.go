@@ -0,0 +1,138 @@
+package corretores
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines real estate agent repository interface
+type Repository interface {
+	Create(ctx context.Context, corretor *imoveis.CorretorPrincipal) error
+	FindByID(ctx context.Context, id uint) (*imoveis.CorretorPrincipal, error)
+	FindByEmail(ctx context.Context, email string) (*imoveis.CorretorPrincipal, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	List(ctx context.Context, query *CorretorListQuery) ([]imoveis.CorretorPrincipal, int64, error)
+	SetFoto(ctx context.Context, id, previousFotoID uint, foto *imoveis.Anexo) error
+	RemoveFoto(ctx context.Context, id, fotoID uint) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new real estate agent repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create creates a new real estate agent, leaving unset foreign keys and integration ID as NULL
+func (r *repository) Create(ctx context.Context, corretor *imoveis.CorretorPrincipal) error {
+	omitFields := []string{"FotoID", "Foto", "Organizacao"}
+	if corretor.IdIntegracao == "" {
+		omitFields = append(omitFields, "IdIntegracao")
+	}
+	if corretor.OrganizacaoID == 0 {
+		omitFields = append(omitFields, "OrganizacaoID")
+	}
+	return r.db.WithContext(ctx).Omit(omitFields...).Create(corretor).Error
+}
+
+// FindByID finds a real estate agent by ID with its photo and agency
+func (r *repository) FindByID(ctx context.Context, id uint) (*imoveis.CorretorPrincipal, error) {
+	var corretor imoveis.CorretorPrincipal
+	if err := r.db.WithContext(ctx).
+		Preload("Foto").
+		Preload("Organizacao").
+		First(&corretor, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &corretor, nil
+}
+
+// FindByEmail finds a real estate agent by case-insensitive email
+func (r *repository) FindByEmail(ctx context.Context, email string) (*imoveis.CorretorPrincipal, error) {
+	var corretor imoveis.CorretorPrincipal
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(email) = LOWER(?)", email).
+		First(&corretor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &corretor, nil
+}
+
+// Update updates the given columns of a real estate agent
+func (r *repository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&imoveis.CorretorPrincipal{ID: id}).
+		Updates(updates).Error
+}
+
+// List retrieves real estate agents with filters and pagination, ordered by name
+func (r *repository) List(ctx context.Context, query *CorretorListQuery) ([]imoveis.CorretorPrincipal, int64, error) {
+	var corretores []imoveis.CorretorPrincipal
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&imoveis.CorretorPrincipal{})
+	if query.Nome != "" {
+		db = db.Where("LOWER(nome) LIKE LOWER(?)", "%"+query.Nome+"%")
+	}
+	if query.OrganizacaoID > 0 {
+		db = db.Where("organizacao_id = ?", query.OrganizacaoID)
+	}
+	if query.Ativo != nil {
+		db = db.Where("ativo = ?", *query.Ativo)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	if err := db.
+		Preload("Foto").
+		Preload("Organizacao").
+		Order("nome ASC, id ASC").
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&corretores).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return corretores, total, nil
+}
+
+// SetFoto stores a new photo attachment, points the agent at it and soft deletes the previous one
+func (r *repository) SetFoto(ctx context.Context, id, previousFotoID uint, foto *imoveis.Anexo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("ImovelID", "EmpreendimentoID", "PlantaID").Create(foto).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&imoveis.CorretorPrincipal{ID: id}).Update("foto_id", foto.ID).Error; err != nil {
+			return err
+		}
+		if previousFotoID != 0 {
+			return tx.Delete(&imoveis.Anexo{}, previousFotoID).Error
+		}
+		return nil
+	})
+}
+
+// RemoveFoto detaches the photo of an agent and soft deletes the attachment
+func (r *repository) RemoveFoto(ctx context.Context, id, fotoID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&imoveis.CorretorPrincipal{ID: id}).Update("foto_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&imoveis.Anexo{}, fotoID).Error
+	})
+}
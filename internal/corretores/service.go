@@ -0,0 +1,289 @@
+package corretores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrCorretorNotFound is returned when a real estate agent does not exist
+	ErrCorretorNotFound = errors.New("corretor not found")
+	// ErrEmailExists is returned when another agent already uses the email
+	ErrEmailExists = errors.New("email already used by another corretor")
+	// ErrFotoNotFound is returned when removing the photo of an agent that has none
+	ErrFotoNotFound = errors.New("corretor has no foto")
+)
+
+// Service defines real estate agent service interface
+type Service interface {
+	CreateCorretor(ctx context.Context, req *CreateCorretorRequest) (*CorretorResponse, error)
+	GetCorretor(ctx context.Context, id uint) (*CorretorResponse, error)
+	UpdateCorretor(ctx context.Context, id uint, req *UpdateCorretorRequest) (*CorretorResponse, error)
+	ListCorretores(ctx context.Context, query *CorretorListQuery) (*CorretorListResponse, error)
+	SetAtivo(ctx context.Context, id uint, ativo bool) (*CorretorResponse, error)
+	SetFoto(ctx context.Context, id uint, req *FotoRequest) (*CorretorResponse, error)
+	RemoveFoto(ctx context.Context, id uint) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new real estate agent service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreateCorretor creates a new active real estate agent
+func (s *service) CreateCorretor(ctx context.Context, req *CreateCorretorRequest) (*CorretorResponse, error) {
+	email := strings.TrimSpace(req.Email)
+	if err := s.ensureEmailAvailable(ctx, 0, email); err != nil {
+		return nil, err
+	}
+
+	corretor := &imoveis.CorretorPrincipal{
+		Nome:           strings.TrimSpace(req.Nome),
+		Email:          email,
+		Whatsapp:       req.Whatsapp,
+		Idiomas:        normalizeList(req.Idiomas),
+		BairrosAtuacao: normalizeList(req.BairrosAtuacao),
+		OrganizacaoID:  req.OrganizacaoID,
+		Ativo:          true,
+	}
+	if err := s.repo.Create(ctx, corretor); err != nil {
+		return nil, fmt.Errorf("failed to create corretor: %w", err)
+	}
+
+	return s.GetCorretor(ctx, corretor.ID)
+}
+
+// GetCorretor retrieves a real estate agent by ID
+func (s *service) GetCorretor(ctx context.Context, id uint) (*CorretorResponse, error) {
+	corretor, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(corretor), nil
+}
+
+// UpdateCorretor updates the provided fields of a real estate agent
+func (s *service) UpdateCorretor(ctx context.Context, id uint, req *UpdateCorretorRequest) (*CorretorResponse, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Nome != "" {
+		updates["nome"] = strings.TrimSpace(req.Nome)
+	}
+	if req.Email != "" {
+		email := strings.TrimSpace(req.Email)
+		if err := s.ensureEmailAvailable(ctx, id, email); err != nil {
+			return nil, err
+		}
+		updates["email"] = email
+	}
+	if req.Whatsapp != nil {
+		updates["whatsapp"] = *req.Whatsapp
+	}
+	if req.Idiomas != nil {
+		updates["idiomas"] = normalizeList(req.Idiomas)
+	}
+	if req.BairrosAtuacao != nil {
+		updates["bairros_atuacao"] = normalizeList(req.BairrosAtuacao)
+	}
+	if req.OrganizacaoID != nil {
+		if *req.OrganizacaoID == 0 {
+			updates["organizacao_id"] = nil
+		} else {
+			updates["organizacao_id"] = *req.OrganizacaoID
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update corretor: %w", err)
+		}
+	}
+
+	return s.GetCorretor(ctx, id)
+}
+
+// ListCorretores lists real estate agents filtered by name, agency and status
+func (s *service) ListCorretores(ctx context.Context, query *CorretorListQuery) (*CorretorListResponse, error) {
+	corretores, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corretores: %w", err)
+	}
+
+	results := make([]CorretorResponse, len(corretores))
+	for i := range corretores {
+		results[i] = *toResponse(&corretores[i])
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &CorretorListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// SetAtivo activates or deactivates a real estate agent; inactive agents keep their properties
+func (s *service) SetAtivo(ctx context.Context, id uint, ativo bool) (*CorretorResponse, error) {
+	corretor, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if corretor.Ativo != ativo {
+		if err := s.repo.Update(ctx, id, map[string]interface{}{"ativo": ativo}); err != nil {
+			return nil, fmt.Errorf("failed to update corretor status: %w", err)
+		}
+	}
+
+	return s.GetCorretor(ctx, id)
+}
+
+// SetFoto replaces the profile picture of a real estate agent
+func (s *service) SetFoto(ctx context.Context, id uint, req *FotoRequest) (*CorretorResponse, error) {
+	corretor, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	nome := req.Nome
+	if nome == "" {
+		nome = path.Base(req.URL)
+	}
+	foto := &imoveis.Anexo{
+		Nome:          nome,
+		URL:           req.URL,
+		Tipo:          req.Tipo,
+		Tamanho:       req.Tamanho,
+		Image:         true,
+		CanPublish:    true,
+		IsExternalURL: true,
+	}
+
+	if err := s.repo.SetFoto(ctx, id, corretor.FotoID, foto); err != nil {
+		return nil, fmt.Errorf("failed to set corretor foto: %w", err)
+	}
+
+	return s.GetCorretor(ctx, id)
+}
+
+// RemoveFoto removes the profile picture of a real estate agent
+func (s *service) RemoveFoto(ctx context.Context, id uint) error {
+	corretor, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if corretor.FotoID == 0 {
+		return ErrFotoNotFound
+	}
+
+	if err := s.repo.RemoveFoto(ctx, id, corretor.FotoID); err != nil {
+		return fmt.Errorf("failed to remove corretor foto: %w", err)
+	}
+	return nil
+}
+
+func (s *service) find(ctx context.Context, id uint) (*imoveis.CorretorPrincipal, error) {
+	corretor, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find corretor: %w", err)
+	}
+	if corretor == nil {
+		return nil, ErrCorretorNotFound
+	}
+	return corretor, nil
+}
+
+// ensureEmailAvailable rejects an email already used by another agent
+func (s *service) ensureEmailAvailable(ctx context.Context, id uint, email string) error {
+	existing, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check corretor email: %w", err)
+	}
+	if existing != nil && existing.ID != id {
+		return ErrEmailExists
+	}
+	return nil
+}
+
+// normalizeList trims entries and drops blanks and case-insensitive duplicates, keeping order
+func normalizeList(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		key := strings.ToLower(value)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+func toResponse(c *imoveis.CorretorPrincipal) *CorretorResponse {
+	response := &CorretorResponse{
+		ID:             c.ID,
+		Nome:           c.Nome,
+		Email:          c.Email,
+		Whatsapp:       c.Whatsapp,
+		Idiomas:        c.Idiomas,
+		BairrosAtuacao: c.BairrosAtuacao,
+		OrganizacaoID:  c.OrganizacaoID,
+		Ativo:          c.Ativo,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+	if response.Idiomas == nil {
+		response.Idiomas = []string{}
+	}
+	if response.BairrosAtuacao == nil {
+		response.BairrosAtuacao = []string{}
+	}
+
+	if c.Foto != nil {
+		response.Foto = &imoveis.AnexoResponse{
+			ID:            c.Foto.ID,
+			Nome:          c.Foto.Nome,
+			Path:          c.Foto.Path,
+			Tamanho:       c.Foto.Tamanho,
+			Tipo:          c.Foto.Tipo,
+			URL:           c.Foto.URL,
+			CanPublish:    c.Foto.CanPublish,
+			Image:         c.Foto.Image,
+			Video:         c.Foto.Video,
+			IsExternalURL: c.Foto.IsExternalURL,
+			Variants:      c.Foto.Variants,
+			CreatedAt:     c.Foto.CreatedAt,
+			UpdatedAt:     c.Foto.UpdatedAt,
+		}
+	}
+	if c.Organizacao != nil {
+		response.Organizacao = &imoveis.OrganizacaoResponse{
+			ID:     c.Organizacao.ID,
+			Nome:   c.Organizacao.Nome,
+			Perfil: c.Organizacao.Perfil,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,157 @@
+package corretores
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+type stubRepository struct {
+	corretores map[uint]*imoveis.CorretorPrincipal
+	updates    map[string]interface{}
+	removed    uint
+}
+
+func newStubRepository() *stubRepository {
+	return &stubRepository{corretores: map[uint]*imoveis.CorretorPrincipal{}}
+}
+
+func (r *stubRepository) Create(_ context.Context, c *imoveis.CorretorPrincipal) error {
+	c.ID = uint(len(r.corretores) + 1)
+	r.corretores[c.ID] = c
+	return nil
+}
+
+func (r *stubRepository) FindByID(_ context.Context, id uint) (*imoveis.CorretorPrincipal, error) {
+	c, ok := r.corretores[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (r *stubRepository) FindByEmail(_ context.Context, email string) (*imoveis.CorretorPrincipal, error) {
+	for _, c := range r.corretores {
+		if strings.EqualFold(c.Email, email) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) Update(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.updates = updates
+	if ativo, ok := updates["ativo"].(bool); ok {
+		r.corretores[id].Ativo = ativo
+	}
+	return nil
+}
+
+func (r *stubRepository) List(_ context.Context, _ *CorretorListQuery) ([]imoveis.CorretorPrincipal, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *stubRepository) SetFoto(_ context.Context, id, _ uint, foto *imoveis.Anexo) error {
+	foto.ID = 100
+	r.corretores[id].FotoID = foto.ID
+	r.corretores[id].Foto = foto
+	return nil
+}
+
+func (r *stubRepository) RemoveFoto(_ context.Context, id, fotoID uint) error {
+	r.removed = fotoID
+	r.corretores[id].FotoID = 0
+	r.corretores[id].Foto = nil
+	return nil
+}
+
+func TestCreateCorretor(t *testing.T) {
+	repo := newStubRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateCorretor(ctx, &CreateCorretorRequest{
+		Nome:           " Ana ",
+		Email:          "ana@example.com",
+		Idiomas:        []string{"Português", " inglês ", "português", ""},
+		BairrosAtuacao: nil,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", created.Nome)
+	assert.True(t, created.Ativo)
+	assert.Equal(t, []string{"Português", "inglês"}, created.Idiomas)
+	assert.Equal(t, []string{}, created.BairrosAtuacao)
+
+	_, err = svc.CreateCorretor(ctx, &CreateCorretorRequest{Nome: "Outra Ana", Email: "ANA@example.com"})
+	assert.ErrorIs(t, err, ErrEmailExists)
+}
+
+func TestUpdateCorretor_Columns(t *testing.T) {
+	repo := newStubRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateCorretor(ctx, &CreateCorretorRequest{Nome: "Ana", Email: "ana@example.com", OrganizacaoID: 3})
+	require.NoError(t, err)
+
+	noOrganizacao := uint(0)
+	_, err = svc.UpdateCorretor(ctx, created.ID, &UpdateCorretorRequest{
+		Email:          "ana@example.com",
+		Idiomas:        []string{},
+		OrganizacaoID:  &noOrganizacao,
+		BairrosAtuacao: nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"email":          "ana@example.com",
+		"idiomas":        []string{},
+		"organizacao_id": nil,
+	}, repo.updates)
+
+	_, err = svc.UpdateCorretor(ctx, 42, &UpdateCorretorRequest{Nome: "Nobody"})
+	assert.ErrorIs(t, err, ErrCorretorNotFound)
+}
+
+func TestSetAtivo(t *testing.T) {
+	repo := newStubRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateCorretor(ctx, &CreateCorretorRequest{Nome: "Ana", Email: "ana@example.com"})
+	require.NoError(t, err)
+
+	deactivated, err := svc.SetAtivo(ctx, created.ID, false)
+	require.NoError(t, err)
+	assert.False(t, deactivated.Ativo)
+
+	activated, err := svc.SetAtivo(ctx, created.ID, true)
+	require.NoError(t, err)
+	assert.True(t, activated.Ativo)
+}
+
+func TestFoto(t *testing.T) {
+	repo := newStubRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateCorretor(ctx, &CreateCorretorRequest{Nome: "Ana", Email: "ana@example.com"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.RemoveFoto(ctx, created.ID), ErrFotoNotFound)
+
+	withFoto, err := svc.SetFoto(ctx, created.ID, &FotoRequest{URL: "https://cdn.example.com/fotos/ana.jpg"})
+	require.NoError(t, err)
+	require.NotNil(t, withFoto.Foto)
+	assert.Equal(t, "ana.jpg", withFoto.Foto.Nome)
+	assert.True(t, withFoto.Foto.Image)
+
+	require.NoError(t, svc.RemoveFoto(ctx, created.ID))
+	assert.Equal(t, uint(100), repo.removed)
+}
@@ -79,9 +79,21 @@ func NewPostgresDB(cfg Config) (*gorm.DB, error) {
 
 // NewPostgresDBFromDatabaseConfig creates a new PostgreSQL DB connection from typed config
 func NewPostgresDBFromDatabaseConfig(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	return newPostgresDB(postgresDSN(cfg, ""))
+}
+
+// postgresDSN builds a DSN from typed config. A non-empty searchPath is sent as a
+// startup parameter so every connection in the pool resolves tables in that schema.
+func postgresDSN(cfg config.DatabaseConfig, searchPath string) string {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
 		cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode)
+	if searchPath != "" {
+		dsn += fmt.Sprintf(" search_path=%s", searchPath)
+	}
+	return dsn
+}
 
+func newPostgresDB(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: customLogger{logger.Default.LogMode(logger.Info)},
 	})
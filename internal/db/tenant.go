@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// Tenancy modes supported by TenantManager
+const (
+	TenancyNone     = "none"
+	TenancySchema   = "schema"
+	TenancyDatabase = "database"
+)
+
+// DefaultTenantPrefix is used to name tenant schemas/databases when none is configured
+const DefaultTenantPrefix = "org_"
+
+type organizacaoKey struct{}
+
+// WithOrganizacao returns a context carrying the organizacao whose data should be accessed
+func WithOrganizacao(ctx context.Context, organizacaoID uint) context.Context {
+	return context.WithValue(ctx, organizacaoKey{}, organizacaoID)
+}
+
+// OrganizacaoFromContext extracts the organizacao set by WithOrganizacao
+func OrganizacaoFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(organizacaoKey{}).(uint)
+	return id, ok && id > 0
+}
+
+// TenantManager resolves a dedicated connection pool per organizacao, isolated either by
+// Postgres schema (search_path) or by database. With tenancy disabled it always returns the base DB.
+// Only cmd/migrate uses it, to provision and migrate the tenants: the API server serves every
+// organizacao from the base DB (see TenantScope) and refuses to start in the other modes.
+type TenantManager struct {
+	base   *gorm.DB
+	cfg    config.DatabaseConfig
+	mode   string
+	prefix string
+	open   func(cfg config.DatabaseConfig, searchPath string) (*gorm.DB, error)
+
+	mu      sync.Mutex
+	tenants map[uint]*gorm.DB
+}
+
+// NewTenantManager creates a tenant manager on top of the shared base connection
+func NewTenantManager(base *gorm.DB, cfg config.DatabaseConfig) *TenantManager {
+	mode := cfg.TenancyMode
+	if mode == "" {
+		mode = TenancyNone
+	}
+	prefix := cfg.TenantPrefix
+	if prefix == "" {
+		prefix = DefaultTenantPrefix
+	}
+
+	return &TenantManager{
+		base:    base,
+		cfg:     cfg,
+		mode:    mode,
+		prefix:  prefix,
+		open:    openPostgres,
+		tenants: make(map[uint]*gorm.DB),
+	}
+}
+
+// Enabled reports whether per-organizacao isolation is active
+func (m *TenantManager) Enabled() bool {
+	return m.mode == TenancySchema || m.mode == TenancyDatabase
+}
+
+// Mode returns the configured tenancy mode
+func (m *TenantManager) Mode() string {
+	return m.mode
+}
+
+// TenantName returns the schema or database name of an organizacao
+func (m *TenantManager) TenantName(organizacaoID uint) string {
+	return fmt.Sprintf("%s%d", m.prefix, organizacaoID)
+}
+
+// DB returns the connection for the organizacao in ctx, or the base connection when
+// tenancy is disabled or the context carries no organizacao
+func (m *TenantManager) DB(ctx context.Context) (*gorm.DB, error) {
+	organizacaoID, ok := OrganizacaoFromContext(ctx)
+	if !m.Enabled() || !ok {
+		return m.base.WithContext(ctx), nil
+	}
+
+	tenantDB, err := m.ForOrganizacao(organizacaoID)
+	if err != nil {
+		return nil, err
+	}
+	return tenantDB.WithContext(ctx), nil
+}
+
+// ForOrganizacao returns the cached connection pool of an organizacao, opening it on first use
+func (m *TenantManager) ForOrganizacao(organizacaoID uint) (*gorm.DB, error) {
+	if !m.Enabled() {
+		return m.base, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tenantDB, ok := m.tenants[organizacaoID]; ok {
+		return tenantDB, nil
+	}
+
+	tenantDB, err := m.OpenTenant(organizacaoID)
+	if err != nil {
+		return nil, err
+	}
+	m.tenants[organizacaoID] = tenantDB
+	return tenantDB, nil
+}
+
+// OpenTenant opens a new, uncached connection to an organizacao; the caller owns it and must close it
+func (m *TenantManager) OpenTenant(organizacaoID uint) (*gorm.DB, error) {
+	switch m.mode {
+	case TenancySchema:
+		return m.open(m.cfg, m.TenantName(organizacaoID))
+	case TenancyDatabase:
+		cfg := m.cfg
+		cfg.Name = m.TenantName(organizacaoID)
+		return m.open(cfg, "")
+	default:
+		return nil, fmt.Errorf("tenancy is disabled (mode %q)", m.mode)
+	}
+}
+
+// Provision creates the schema or database of an organizacao if it does not exist yet.
+// Tables are created afterwards by running the migrations against the tenant.
+func (m *TenantManager) Provision(ctx context.Context, organizacaoID uint) error {
+	name := m.TenantName(organizacaoID)
+
+	switch m.mode {
+	case TenancySchema:
+		if err := m.base.WithContext(ctx).Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", name)).Error; err != nil {
+			return fmt.Errorf("failed to create schema %s: %w", name, err)
+		}
+		return nil
+	case TenancyDatabase:
+		var exists bool
+		if err := m.base.WithContext(ctx).Raw("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = ?)", name).Scan(&exists).Error; err != nil {
+			return fmt.Errorf("failed to check database %s: %w", name, err)
+		}
+		if exists {
+			return nil
+		}
+		// CREATE DATABASE cannot run inside a transaction block nor take bind parameters
+		if err := m.base.WithContext(ctx).Exec(fmt.Sprintf("CREATE DATABASE %s", name)).Error; err != nil {
+			return fmt.Errorf("failed to create database %s: %w", name, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("tenancy is disabled (mode %q)", m.mode)
+	}
+}
+
+// Tenants lists the organizacao IDs that already have a provisioned schema or database
+func (m *TenantManager) Tenants(ctx context.Context) ([]uint, error) {
+	var names []string
+	var err error
+
+	switch m.mode {
+	case TenancySchema:
+		err = m.base.WithContext(ctx).
+			Raw("SELECT schema_name FROM information_schema.schemata WHERE schema_name LIKE ?", m.prefix+"%").
+			Scan(&names).Error
+	case TenancyDatabase:
+		err = m.base.WithContext(ctx).
+			Raw("SELECT datname FROM pg_database WHERE datname LIKE ?", m.prefix+"%").
+			Scan(&names).Error
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	return m.parseTenantNames(names), nil
+}
+
+// Close closes every cached tenant connection pool
+func (m *TenantManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []string
+	for id, tenantDB := range m.tenants {
+		if sqlDB, err := tenantDB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", m.TenantName(id), err))
+			}
+		}
+		delete(m.tenants, id)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close tenant connections: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (m *TenantManager) parseTenantNames(names []string) []uint {
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		suffix := strings.TrimPrefix(name, m.prefix)
+		id, err := strconv.ParseUint(suffix, 10, 32)
+		if err != nil || id == 0 {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// openPostgres opens a pool for cfg, pinning the search_path when one is given
+func openPostgres(cfg config.DatabaseConfig, searchPath string) (*gorm.DB, error) {
+	return newPostgresDB(postgresDSN(cfg, searchPath))
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// TenantTable describes how the rows of a table belong to an organizacao
+type TenantTable struct {
+	// Condition limits the rows to the organizacao: %[1]s is replaced by the quoted table name
+	// and the single ? receives the organizacao ID
+	Condition string
+	// Column, when set, is filled with the organizacao of the context on create if left empty
+	Column string
+}
+
+// ColumnTenant is the TenantTable of a table holding its own organizacao_id column
+var ColumnTenant = TenantTable{Condition: "%[1]s.organizacao_id = ?", Column: "organizacao_id"}
+
+// TenantTables are the tables isolated per organizacao. Imoveis and leads belong to the organizacao
+// of their corretor principal, favoritos to the one of their imovel and email logs to the one of
+// their email, so none has a column of its own.
+var TenantTables = map[string]TenantTable{
+	"corretores_principais": ColumnTenant,
+	"imoveis": {
+		Condition: "%[1]s.corretor_principal_id IN (SELECT id FROM corretores_principais WHERE organizacao_id = ?)",
+	},
+	"leads": {
+		Condition: "%[1]s.corretor_principal_id IN (SELECT id FROM corretores_principais WHERE organizacao_id = ?)",
+	},
+	"favoritos": {
+		Condition: "%[1]s.imovel_id IN (SELECT id FROM imoveis WHERE corretor_principal_id IN (SELECT id FROM corretores_principais WHERE organizacao_id = ?))",
+	},
+	"sliders":         ColumnTenant,
+	"email_outbox":    ColumnTenant,
+	"email_campaigns": ColumnTenant,
+	"email_logs": {
+		Condition: "%[1]s.email_id IN (SELECT id FROM email_outbox WHERE organizacao_id = ?)",
+	},
+}
+
+const tenantScopeApplied = "tenant_scope:applied"
+
+// TenantScope is a GORM plugin that isolates the registered tables per organizacao. Queries,
+// updates and deletes made with a context carrying an organizacao (see WithOrganizacao) only see
+// its rows, and creates are stamped with it. Contexts without an organizacao are not filtered, and
+// neither are Raw/Exec statements: those reading these tables in a scoped request must check the
+// organizacao themselves (see imoveis.CheckOwnersScope). Queries built with GORM and passed as
+// subqueries to Raw are filtered.
+type TenantScope struct {
+	tables map[string]TenantTable
+}
+
+// NewTenantScope creates the plugin for the given tables, keyed by table name
+func NewTenantScope(tables map[string]TenantTable) *TenantScope {
+	return &TenantScope{tables: tables}
+}
+
+// Name implements gorm.Plugin
+func (p *TenantScope) Name() string {
+	return "tenant_scope"
+}
+
+// Initialize implements gorm.Plugin by registering the scoping callbacks
+func (p *TenantScope) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant_scope:query", p.filter); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant_scope:row", p.filter); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant_scope:update", p.filter); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant_scope:delete", p.filter); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant_scope:create", p.stamp)
+}
+
+// table returns the rule of the statement's table and the organizacao of its context
+func (p *TenantScope) table(tx *gorm.DB) (TenantTable, uint, bool) {
+	stmt := tx.Statement
+	if tx.Error != nil || stmt.Context == nil {
+		return TenantTable{}, 0, false
+	}
+	organizacaoID, ok := OrganizacaoFromContext(stmt.Context)
+	if !ok {
+		return TenantTable{}, 0, false
+	}
+	rule, ok := p.tables[stmt.Table]
+	return rule, organizacaoID, ok
+}
+
+func (p *TenantScope) filter(tx *gorm.DB) {
+	rule, organizacaoID, ok := p.table(tx)
+	if !ok {
+		return
+	}
+	// WHY: a chained *gorm.DB reuses its statement (Count then Find), so add the condition once
+	if _, applied := tx.Statement.Settings.LoadOrStore(tenantScopeApplied, true); applied {
+		return
+	}
+
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: fmt.Sprintf(rule.Condition, tx.Statement.Quote(tx.Statement.Table)), Vars: []interface{}{organizacaoID}},
+	}})
+}
+
+func (p *TenantScope) stamp(tx *gorm.DB) {
+	rule, organizacaoID, ok := p.table(tx)
+	if !ok || rule.Column == "" || tx.Statement.Schema == nil {
+		return
+	}
+	field := tx.Statement.Schema.LookUpField(rule.Column)
+	if field == nil {
+		return
+	}
+
+	rv := tx.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			p.stampValue(tx, field, reflect.Indirect(rv.Index(i)), organizacaoID)
+		}
+	case reflect.Struct:
+		p.stampValue(tx, field, rv, organizacaoID)
+	}
+}
+
+func (p *TenantScope) stampValue(tx *gorm.DB, field *schema.Field, rv reflect.Value, organizacaoID uint) {
+	if _, zero := field.ValueOf(tx.Statement.Context, rv); !zero {
+		return
+	}
+	if err := field.Set(tx.Statement.Context, rv, organizacaoID); err != nil {
+		_ = tx.AddError(fmt.Errorf("failed to set %s: %w", field.DBName, err))
+	}
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type scopeCorretor struct {
+	ID            uint `gorm:"primaryKey"`
+	OrganizacaoID uint
+}
+
+func (scopeCorretor) TableName() string { return "corretores_principais" }
+
+type scopeImovel struct {
+	ID                  uint `gorm:"primaryKey"`
+	Codigo              string
+	CorretorPrincipalID uint
+}
+
+func (scopeImovel) TableName() string { return "imoveis" }
+
+type scopeSlider struct {
+	ID            uint `gorm:"primaryKey"`
+	Nome          string
+	OrganizacaoID *uint
+}
+
+func (scopeSlider) TableName() string { return "sliders" }
+
+func setupTenantScope(t *testing.T) *gorm.DB {
+	database, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.Use(NewTenantScope(TenantTables)))
+	require.NoError(t, database.AutoMigrate(&scopeCorretor{}, &scopeImovel{}, &scopeSlider{}))
+
+	require.NoError(t, database.Create(&[]scopeCorretor{{ID: 1, OrganizacaoID: 1}, {ID: 2, OrganizacaoID: 2}}).Error)
+	require.NoError(t, database.Create(&[]scopeImovel{
+		{ID: 1, Codigo: "A1", CorretorPrincipalID: 1},
+		{ID: 2, Codigo: "B1", CorretorPrincipalID: 2},
+		{ID: 3, Codigo: "A2", CorretorPrincipalID: 1},
+	}).Error)
+	return database
+}
+
+func TestTenantScope_FiltersQueries(t *testing.T) {
+	database := setupTenantScope(t)
+	ctx := WithOrganizacao(context.Background(), 1)
+
+	var imoveis []scopeImovel
+	require.NoError(t, database.WithContext(ctx).Order("id").Find(&imoveis).Error)
+	require.Len(t, imoveis, 2)
+	assert.Equal(t, "A1", imoveis[0].Codigo)
+	assert.Equal(t, "A2", imoveis[1].Codigo)
+
+	query := database.WithContext(ctx).Model(&scopeImovel{}).Where("codigo LIKE ?", "%1")
+	var total int64
+	require.NoError(t, query.Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+	imoveis = nil
+	require.NoError(t, query.Find(&imoveis).Error, "reusing the chain must not add the condition twice")
+	assert.Len(t, imoveis, 1)
+
+	var other scopeImovel
+	err := database.WithContext(ctx).First(&other, 2).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "imoveis of another organizacao are not found")
+
+	var corretores []scopeCorretor
+	require.NoError(t, database.WithContext(ctx).Find(&corretores).Error)
+	assert.Len(t, corretores, 1)
+
+	imoveis = nil
+	require.NoError(t, database.WithContext(context.Background()).Find(&imoveis).Error)
+	assert.Len(t, imoveis, 3, "requests without an organizacao are not filtered")
+}
+
+func TestTenantScope_FiltersUpdatesAndDeletes(t *testing.T) {
+	database := setupTenantScope(t)
+	ctx := WithOrganizacao(context.Background(), 1)
+
+	result := database.WithContext(ctx).Model(&scopeImovel{}).Where("id = ?", 2).Update("codigo", "X")
+	require.NoError(t, result.Error)
+	assert.Zero(t, result.RowsAffected)
+
+	result = database.WithContext(ctx).Delete(&scopeImovel{}, 2)
+	require.NoError(t, result.Error)
+	assert.Zero(t, result.RowsAffected)
+
+	result = database.WithContext(ctx).Delete(&scopeImovel{}, 1)
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(1), result.RowsAffected)
+}
+
+func TestTenantScope_StampsCreates(t *testing.T) {
+	database := setupTenantScope(t)
+	ctx := WithOrganizacao(context.Background(), 2)
+
+	slider := scopeSlider{Nome: "home"}
+	require.NoError(t, database.WithContext(ctx).Create(&slider).Error)
+	require.NotNil(t, slider.OrganizacaoID)
+	assert.Equal(t, uint(2), *slider.OrganizacaoID)
+
+	orgOne := uint(1)
+	batch := []scopeSlider{{Nome: "a"}, {Nome: "b", OrganizacaoID: &orgOne}}
+	require.NoError(t, database.WithContext(ctx).Create(&batch).Error)
+	assert.Equal(t, uint(2), *batch[0].OrganizacaoID)
+	assert.Equal(t, uint(1), *batch[1].OrganizacaoID, "an explicit organizacao is kept")
+
+	unscoped := scopeSlider{Nome: "global"}
+	require.NoError(t, database.Create(&unscoped).Error)
+	assert.Nil(t, unscoped.OrganizacaoID)
+
+	var sliders []scopeSlider
+	require.NoError(t, database.WithContext(ctx).Find(&sliders).Error)
+	assert.Len(t, sliders, 2)
+}
+
+func TestTenantScope_RawFiltersOnlySubqueries(t *testing.T) {
+	database := setupTenantScope(t)
+	ctx := WithOrganizacao(context.Background(), 1)
+
+	var total int64
+	require.NoError(t, database.WithContext(ctx).Raw("SELECT COUNT(*) FROM imoveis").Scan(&total).Error)
+	assert.Equal(t, int64(3), total, "raw SQL is not filtered")
+
+	sub := database.WithContext(ctx).Model(&scopeImovel{}).Select("id")
+	require.NoError(t, database.WithContext(ctx).Raw("SELECT COUNT(*) FROM (?) AS scoped", sub).Scan(&total).Error)
+	assert.Equal(t, int64(2), total, "GORM subqueries passed to Raw are filtered")
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func TestOrganizacaoContext(t *testing.T) {
+	_, ok := OrganizacaoFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = OrganizacaoFromContext(WithOrganizacao(context.Background(), 0))
+	assert.False(t, ok)
+
+	id, ok := OrganizacaoFromContext(WithOrganizacao(context.Background(), 42))
+	assert.True(t, ok)
+	assert.Equal(t, uint(42), id)
+}
+
+func TestPostgresDSN(t *testing.T) {
+	cfg := config.DatabaseConfig{Host: "localhost", Port: 5432, User: "u", Password: "p", Name: "app", SSLMode: "disable"}
+
+	assert.Equal(t, "host=localhost user=u password=p dbname=app port=5432 sslmode=disable", postgresDSN(cfg, ""))
+	assert.Equal(t, "host=localhost user=u password=p dbname=app port=5432 sslmode=disable search_path=org_7", postgresDSN(cfg, "org_7"))
+}
+
+func TestTenantManager_Disabled(t *testing.T) {
+	base, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+
+	m := NewTenantManager(base, config.DatabaseConfig{})
+	assert.False(t, m.Enabled())
+	assert.Equal(t, TenancyNone, m.Mode())
+
+	got, err := m.DB(WithOrganizacao(context.Background(), 3))
+	require.NoError(t, err)
+	assert.Same(t, base.ConnPool, got.ConnPool)
+
+	_, err = m.OpenTenant(3)
+	assert.Error(t, err)
+
+	ids, err := m.Tenants(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestTenantManager_ResolvesScopedConnection(t *testing.T) {
+	base, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		mode           string
+		wantSearchPath string
+		wantDBName     string
+	}{
+		{name: "schema mode", mode: TenancySchema, wantSearchPath: "tenant_5", wantDBName: "app"},
+		{name: "database mode", mode: TenancyDatabase, wantSearchPath: "", wantDBName: "tenant_5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTenantManager(base, config.DatabaseConfig{Name: "app", TenancyMode: tt.mode, TenantPrefix: "tenant_"})
+
+			var opened int
+			m.open = func(cfg config.DatabaseConfig, searchPath string) (*gorm.DB, error) {
+				opened++
+				assert.Equal(t, tt.wantSearchPath, searchPath)
+				assert.Equal(t, tt.wantDBName, cfg.Name)
+				return NewSQLiteDB(":memory:")
+			}
+
+			ctx := WithOrganizacao(context.Background(), 5)
+			first, err := m.DB(ctx)
+			require.NoError(t, err)
+			second, err := m.DB(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, opened, "tenant pool should be cached")
+			assert.Same(t, first.ConnPool, second.ConnPool)
+			assert.NotSame(t, base.ConnPool, first.ConnPool)
+
+			withoutOrg, err := m.DB(context.Background())
+			require.NoError(t, err)
+			assert.Same(t, base.ConnPool, withoutOrg.ConnPool)
+
+			assert.NoError(t, m.Close())
+		})
+	}
+}
+
+func TestTenantManager_ParseTenantNames(t *testing.T) {
+	m := NewTenantManager(nil, config.DatabaseConfig{TenancyMode: TenancySchema})
+
+	assert.Equal(t, "org_12", m.TenantName(12))
+	assert.Equal(t, []uint{2, 10}, m.parseTenantNames([]string{"org_10", "org_x", "org_2", "org_0", "org_"}))
+}
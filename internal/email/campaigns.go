@@ -0,0 +1,403 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+const (
+	// defaultCampaignRate se aplica quando a campanha não informa rate_per_minute
+	defaultCampaignRate = 60
+	// campaignMaxRecipients limita os destinatários de uma campanha
+	campaignMaxRecipients = 10000
+)
+
+// CreateCampaign envia um template para cada destinatário do segmento. Os emails são personalizados
+// e validados antes de qualquer um ser enfileirado, então um destinatário com dados inválidos recusa a
+// campanha inteira; depois são agendados na fila a intervalos de um minuto / rate_per_minute.
+//
+// Campanhas são emails de marketing: os destinatários da lista de supressão são pulados e contados
+// em Suppressed. Além de template_data e dos dados do destinatário, o template recebe
+// RecipientEmail, RecipientName e UnsubscribeURL.
+func (s *service) CreateCampaign(ctx context.Context, req *CreateCampaignRequest) (*CampaignResponse, error) {
+	if err := s.validateConfig(); err != nil {
+		return nil, err
+	}
+
+	locale, err := normalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	// Os templates resolvidos por locale; o da campanha é buscado já aqui para recusar um nome inexistente
+	templates := make(map[string]resolvedTemplate)
+	if _, err := s.campaignTemplate(ctx, templates, req.TemplateName, locale); err != nil {
+		return nil, err
+	}
+	subject, err := textTemplate.New("subject").Option("missingkey=zero").Parse(req.Subject)
+	if err != nil {
+		return nil, apiErrors.BadRequest(fmt.Sprintf("Invalid subject template: %v", err))
+	}
+
+	recipients, err := s.campaignRecipients(ctx, &req.Segment)
+	if err != nil {
+		return nil, err
+	}
+	recipients, suppressed, err := s.withoutSuppressed(ctx, recipients)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.withRecipientLocales(ctx, recipients, locale); err != nil {
+		return nil, err
+	}
+	images, err := s.resolveInlineImages(ctx, req.InlineImages)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := req.RatePerMinute
+	if rate <= 0 {
+		rate = defaultCampaignRate
+	}
+	interval := time.Minute / time.Duration(rate)
+	start := time.Now()
+
+	emails := make([]OutboxEmail, len(recipients))
+	for i, recipient := range recipients {
+		link, err := s.unsubscribeURL(recipient.Email)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := s.campaignTemplate(ctx, templates, req.TemplateName, recipient.Locale)
+		if err != nil {
+			return nil, err
+		}
+		data := campaignData(req.TemplateData, recipient, link)
+		if err := validateTemplateData(tmpl.variables, data); err != nil {
+			return nil, recipientError(recipient.Email, err)
+		}
+
+		body, err := s.render(tmpl.template, data)
+		if err != nil {
+			return nil, recipientError(recipient.Email, apiErrors.BadRequest(fmt.Sprintf("Failed to render template: %v", err)))
+		}
+		var subjectLine strings.Builder
+		if err := subject.Execute(&subjectLine, data); err != nil {
+			return nil, recipientError(recipient.Email, apiErrors.BadRequest(fmt.Sprintf("Failed to render subject: %v", err)))
+		}
+
+		emails[i] = OutboxEmail{
+			To:           []string{recipient.Email},
+			Subject:      subjectLine.String(),
+			Body:         body,
+			IsHTML:       true,
+			InlineImages: images,
+			TemplateName: req.TemplateName,
+			Marketing:    true,
+		}
+		if err := s.prepare(&emails[i], start.Add(time.Duration(i)*interval)); err != nil {
+			return nil, recipientError(recipient.Email, err)
+		}
+	}
+
+	segment := req.Segment
+	segment.Recipients = nil
+	campaign := &EmailCampaign{
+		Name:          req.Name,
+		TemplateName:  req.TemplateName,
+		Subject:       req.Subject,
+		TemplateData:  req.TemplateData,
+		InlineImages:  images,
+		Segment:       segment,
+		RatePerMinute: rate,
+		Total:         len(emails),
+		Suppressed:    suppressed,
+		Status:        CampaignSending,
+	}
+	if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+		campaign.CreatedBy = &userID
+	}
+	if err := s.repo.CreateCampaign(ctx, campaign, emails); err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to create email campaign: %w", err))
+	}
+
+	return newCampaignResponse(*campaign, []CampaignEmailCount{{
+		CampaignID: campaign.ID,
+		Status:     OutboxPending,
+		Count:      len(emails),
+	}}), nil
+}
+
+// campaignRecipients resolve os destinatários do segmento, sem endereços repetidos
+func (s *service) campaignRecipients(ctx context.Context, segment *CampaignSegment) ([]CampaignRecipient, error) {
+	var recipients []CampaignRecipient
+	switch segment.Type {
+	case SegmentRecipients:
+		if segment.Role != "" {
+			return nil, apiErrors.BadRequest("Role is only accepted in the users segment")
+		}
+		recipients = segment.Recipients
+	case SegmentUsers:
+		if len(segment.Recipients) > 0 {
+			return nil, apiErrors.BadRequest("Recipients are only accepted in the recipients segment")
+		}
+		var err error
+		recipients, err = s.repo.ListUserRecipients(ctx, segment.Role, campaignMaxRecipients+1)
+		if err != nil {
+			return nil, apiErrors.InternalServerError(fmt.Errorf("failed to list campaign recipients: %w", err))
+		}
+	default:
+		return nil, apiErrors.BadRequest(fmt.Sprintf("Unknown segment type '%s'", segment.Type))
+	}
+
+	seen := make(map[string]bool, len(recipients))
+	unique := make([]CampaignRecipient, 0, len(recipients))
+	for _, r := range recipients {
+		key := strings.ToLower(strings.TrimSpace(r.Email))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, r)
+	}
+
+	if len(unique) == 0 {
+		return nil, apiErrors.BadRequest("Segment has no recipients")
+	}
+	if len(unique) > campaignMaxRecipients {
+		return nil, apiErrors.BadRequest(fmt.Sprintf("Segment has more than %d recipients", campaignMaxRecipients))
+	}
+	return unique, nil
+}
+
+// withoutSuppressed remove os destinatários da lista de supressão e retorna quantos foram removidos
+func (s *service) withoutSuppressed(ctx context.Context, recipients []CampaignRecipient) ([]CampaignRecipient, int, error) {
+	addresses := make([]string, len(recipients))
+	for i, r := range recipients {
+		addresses[i] = r.Email
+	}
+	suppressed, err := s.suppressed(ctx, addresses)
+	if err != nil {
+		return nil, 0, apiErrors.InternalServerError(err)
+	}
+	if len(suppressed) == 0 {
+		return recipients, 0, nil
+	}
+
+	allowed := make([]CampaignRecipient, 0, len(recipients)-len(suppressed))
+	for _, r := range recipients {
+		if _, ok := suppressed[normalizeAddress(r.Email)]; !ok {
+			allowed = append(allowed, r)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, 0, apiErrors.BadRequest("All recipients of the segment are suppressed")
+	}
+	return allowed, len(recipients) - len(allowed), nil
+}
+
+// resolvedTemplate é um template de campanha já resolvido para um locale
+type resolvedTemplate struct {
+	template  *template.Template
+	variables []TemplateVariable
+}
+
+// campaignTemplate resolve o template da campanha em locale uma única vez, guardando-o em resolved
+func (s *service) campaignTemplate(ctx context.Context, resolved map[string]resolvedTemplate, name, locale string) (resolvedTemplate, error) {
+	if tmpl, ok := resolved[locale]; ok {
+		return tmpl, nil
+	}
+	tmpl, variables, err := s.resolveTemplate(ctx, name, locale)
+	if err != nil {
+		return resolvedTemplate{}, err
+	}
+	resolved[locale] = resolvedTemplate{template: tmpl, variables: variables}
+	return resolved[locale], nil
+}
+
+// withRecipientLocales define o locale de cada destinatário: forced quando informado, senão o do
+// destinatário ou, na falta dele, o preferido pelo usuário cadastrado com o endereço
+func (s *service) withRecipientLocales(ctx context.Context, recipients []CampaignRecipient, forced string) error {
+	if forced != "" {
+		for i := range recipients {
+			recipients[i].Locale = forced
+		}
+		return nil
+	}
+
+	var missing []string
+	for _, r := range recipients {
+		if r.Locale == "" {
+			missing = append(missing, r.Email)
+		}
+	}
+	stored := map[string]string{}
+	if len(missing) > 0 {
+		var err error
+		if stored, err = s.recipientLocales(ctx, missing); err != nil {
+			return err
+		}
+	}
+
+	for i, r := range recipients {
+		if r.Locale != "" {
+			locale, err := normalizeLocale(r.Locale)
+			if err != nil {
+				return recipientError(r.Email, err)
+			}
+			recipients[i].Locale = locale
+			continue
+		}
+		// O locale gravado já foi validado no cadastro; um inválido cai no locale padrão
+		recipients[i].Locale, _ = normalizeLocale(stored[normalizeAddress(r.Email)])
+	}
+	return nil
+}
+
+// campaignData junta os dados da campanha, o endereço e o nome do destinatário e os dados dele, que
+// prevalecem; o link de descadastro não pode ser substituído
+func campaignData(shared map[string]interface{}, recipient CampaignRecipient, unsubscribeURL string) map[string]interface{} {
+	data := make(map[string]interface{}, len(shared)+len(recipient.Data)+3)
+	for k, v := range shared {
+		data[k] = v
+	}
+	data["RecipientEmail"] = recipient.Email
+	data["RecipientName"] = recipient.Name
+	for k, v := range recipient.Data {
+		data[k] = v
+	}
+	data["UnsubscribeURL"] = unsubscribeURL
+	return data
+}
+
+// recipientError identifica o destinatário na mensagem de um erro de validação
+func recipientError(email string, err error) error {
+	var apiErr *apiErrors.APIError
+	if errors.As(err, &apiErr) && apiErr.Status < 500 {
+		return apiErrors.BadRequest(fmt.Sprintf("Recipient %s: %s", email, apiErr.Message))
+	}
+	return err
+}
+
+// ListCampaigns lista as campanhas com o andamento de cada uma
+func (s *service) ListCampaigns(ctx context.Context, query *CampaignListQuery) (*CampaignListResponse, error) {
+	campaigns, total, err := s.repo.ListCampaigns(ctx, query.Page, query.Limit)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to list email campaigns: %w", err))
+	}
+
+	ids := make([]uint, len(campaigns))
+	for i, campaign := range campaigns {
+		ids[i] = campaign.ID
+	}
+	counts, err := s.repo.CountCampaignEmails(ctx, ids)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to count campaign emails: %w", err))
+	}
+
+	results := make([]CampaignResponse, len(campaigns))
+	for i, campaign := range campaigns {
+		results[i] = *newCampaignResponse(campaign, counts)
+	}
+	return pagination.New(results, total, query.Page, query.Limit), nil
+}
+
+// GetCampaign retorna uma campanha com o andamento do envio
+func (s *service) GetCampaign(ctx context.Context, id uint) (*CampaignResponse, error) {
+	campaign, err := s.repo.FindCampaignByID(ctx, id)
+	if err != nil {
+		return nil, campaignLookupError(err)
+	}
+	return s.campaignResponse(ctx, campaign)
+}
+
+// CancelCampaign interrompe uma campanha em envio; os emails dela que ainda estão na fila não são
+// enviados
+func (s *service) CancelCampaign(ctx context.Context, id uint) (*CampaignResponse, error) {
+	campaign, err := s.repo.FindCampaignByID(ctx, id)
+	if err != nil {
+		return nil, campaignLookupError(err)
+	}
+	current, err := s.campaignResponse(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != CampaignSending {
+		return nil, apiErrors.Conflict(fmt.Sprintf("Campaign is already %s", strings.ToLower(current.Status)))
+	}
+
+	if err := s.repo.CancelCampaign(ctx, id, time.Now()); err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to cancel email campaign: %w", err))
+	}
+
+	campaign, err = s.repo.FindCampaignByID(ctx, id)
+	if err != nil {
+		return nil, campaignLookupError(err)
+	}
+	return s.campaignResponse(ctx, campaign)
+}
+
+// campaignResponse conta os emails da campanha e monta a resposta
+func (s *service) campaignResponse(ctx context.Context, campaign *EmailCampaign) (*CampaignResponse, error) {
+	counts, err := s.repo.CountCampaignEmails(ctx, []uint{campaign.ID})
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to count campaign emails: %w", err))
+	}
+	return newCampaignResponse(*campaign, counts), nil
+}
+
+// newCampaignResponse calcula o andamento da campanha a partir das contagens dos emails dela. Uma
+// campanha em envio sem emails na fila aparece como concluída.
+func newCampaignResponse(campaign EmailCampaign, counts []CampaignEmailCount) *CampaignResponse {
+	var progress CampaignProgress
+	for _, c := range counts {
+		if c.CampaignID != campaign.ID {
+			continue
+		}
+		switch c.Status {
+		case OutboxPending:
+			progress.Pending += c.Count
+		case OutboxSent:
+			progress.Sent += c.Count
+		case OutboxDead:
+			progress.Failed += c.Count
+		case OutboxCancelled:
+			progress.Cancelled += c.Count
+		}
+		switch c.DeliveryStatus {
+		case LogDelivered:
+			progress.Delivered += c.Count
+		case LogBounced, LogDropped:
+			progress.Bounced += c.Count
+		case LogComplained:
+			progress.Complained += c.Count
+		}
+	}
+	if campaign.Total > 0 {
+		done := float64(campaign.Total-progress.Pending) / float64(campaign.Total) * 100
+		progress.Percent = math.Round(done*10) / 10
+	}
+	if campaign.Status == CampaignSending && progress.Pending == 0 {
+		campaign.Status = CampaignCompleted
+	}
+	return &CampaignResponse{EmailCampaign: campaign, Progress: progress}
+}
+
+// campaignLookupError converte a falha da busca de uma campanha por ID
+func campaignLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apiErrors.NotFound("Campaign not found")
+	}
+	return apiErrors.InternalServerError(fmt.Errorf("failed to find email campaign: %w", err))
+}
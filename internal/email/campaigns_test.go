@@ -0,0 +1,210 @@
+package email
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+// createUserTables creates the role tables read by the users segment and fills them with users
+func createUserTables(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	require.NoError(t, db.Exec(`CREATE TABLE roles (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE user_roles (user_id INTEGER, role_id INTEGER)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO roles (id, name) VALUES (1, 'user'), (2, 'admin')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, name, email, deleted_at) VALUES
+		(1, 'Ana', 'ana@example.com', NULL),
+		(2, 'Bruno', 'bruno@example.com', NULL),
+		(3, 'Carla', 'carla@example.com', '2026-01-01 00:00:00')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_roles (user_id, role_id) VALUES (1, 1), (2, 1), (2, 2), (3, 2)`).Error)
+}
+
+func campaignEmails(t *testing.T, db *gorm.DB, campaignID uint) []OutboxEmail {
+	t.Helper()
+	var emails []OutboxEmail
+	require.NoError(t, db.Where("campaign_id = ?", campaignID).Order("id").Find(&emails).Error)
+	return emails
+}
+
+func TestService_CreateCampaign(t *testing.T) {
+	ctx := contextutil.WithUserID(context.Background(), 7)
+	var sent []*mail.Msg
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	_, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+
+	result, err := svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Lançamento Jardins",
+		TemplateName: "proposal",
+		Subject:      "{{.RecipientName}}, novidades no {{.Bairro}}",
+		TemplateData: map[string]interface{}{"Name": "cliente", "Amount": 1000, "Bairro": "Jardins"},
+		Segment: CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{
+			{Email: "ana@example.com", Name: "Ana", Data: map[string]interface{}{"Name": "Ana"}},
+			{Email: "bruno@example.com", Name: "Bruno", Data: map[string]interface{}{"Amount": 2500}},
+			{Email: "ANA@example.com", Name: "Ana de novo"},
+		}},
+		RatePerMinute: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CampaignSending, result.Status)
+	assert.Equal(t, 2, result.Total, "repeated addresses are sent once")
+	assert.Equal(t, 2, result.RatePerMinute)
+	assert.Equal(t, uint(7), *result.CreatedBy)
+	assert.Nil(t, result.Segment.Recipients, "recipients are kept in the queue, not in the campaign")
+	assert.Equal(t, CampaignProgress{Pending: 2}, result.Progress)
+
+	emails := campaignEmails(t, db, result.ID)
+	require.Len(t, emails, 2)
+	assert.Equal(t, []string{"ana@example.com"}, emails[0].To)
+	assert.Equal(t, "Ana, novidades no Jardins", emails[0].Subject)
+	assert.Contains(t, emails[0].Body, "Olá Ana, sua proposta de 1000 foi recebida.")
+	assert.Equal(t, "Bruno, novidades no Jardins", emails[1].Subject)
+	assert.Contains(t, emails[1].Body, "Olá cliente, sua proposta de 2500 foi recebida.")
+	assert.NotEqual(t, emails[0].MessageID, emails[1].MessageID)
+	assert.WithinDuration(t, emails[0].NextAttemptAt.Add(30*time.Second), emails[1].NextAttemptAt, time.Second,
+		"2 per minute are spread 30s apart")
+
+	// Só o primeiro já está na hora de sair
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+
+	progress, err := svc.GetCampaign(ctx, result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CampaignSending, progress.Status)
+	assert.Equal(t, CampaignProgress{Pending: 1, Sent: 1, Percent: 50}, progress.Progress)
+
+	makeDue(t, db, emails[1].ID)
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	require.NoError(t, svc.repo.SetDeliveryStatus(ctx, emails[1].ID, LogBounced, time.Now()))
+
+	progress, err = svc.GetCampaign(ctx, result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CampaignCompleted, progress.Status)
+	assert.Equal(t, CampaignProgress{Sent: 2, Bounced: 1, Percent: 100}, progress.Progress)
+
+	_, err = svc.CancelCampaign(ctx, result.ID)
+	assertAPIStatus(t, err, http.StatusConflict)
+
+	list, err := svc.ListCampaigns(ctx, &CampaignListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, CampaignCompleted, list.Results[0].Status)
+	assert.Equal(t, 2, list.Results[0].Progress.Sent)
+}
+
+func TestService_CreateCampaign_UsersSegment(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, nil)
+	createUserTables(t, db)
+
+	result, err := svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Aviso",
+		TemplateName: "welcome",
+		Subject:      "Olá {{.RecipientName}}",
+		Segment:      CampaignSegment{Type: SegmentUsers},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total, "deleted users are skipped")
+	assert.Equal(t, defaultCampaignRate, result.RatePerMinute)
+
+	result, err = svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Aviso aos admins",
+		TemplateName: "welcome",
+		Subject:      "Olá {{.RecipientName}}",
+		Segment:      CampaignSegment{Type: SegmentUsers, Role: "admin"},
+	})
+	require.NoError(t, err)
+	emails := campaignEmails(t, db, result.ID)
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"bruno@example.com"}, emails[0].To)
+	assert.Equal(t, "Olá Bruno", emails[0].Subject)
+
+	_, err = svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Ninguém",
+		TemplateName: "welcome",
+		Subject:      "Olá",
+		Segment:      CampaignSegment{Type: SegmentUsers, Role: "corretor"},
+	})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_CreateCampaign_InvalidRecipientData(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, nil)
+	_, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+
+	_, err = svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Propostas",
+		TemplateName: "proposal",
+		Subject:      "Sua proposta",
+		TemplateData: map[string]interface{}{"Amount": 1000},
+		Segment: CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{
+			{Email: "ana@example.com", Data: map[string]interface{}{"Name": "Ana"}},
+			{Email: "bruno@example.com"},
+		}},
+	})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+	assert.Contains(t, err.Error(), "bruno@example.com")
+
+	var count int64
+	require.NoError(t, db.Model(&OutboxEmail{}).Count(&count).Error)
+	assert.Zero(t, count, "nothing is queued when a recipient is invalid")
+
+	_, err = svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Propostas",
+		TemplateName: "proposal",
+		Subject:      "{{.Broken",
+		Segment:      CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{{Email: "ana@example.com"}}},
+	})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_CancelCampaign(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error { return nil })
+
+	result, err := svc.CreateCampaign(ctx, &CreateCampaignRequest{
+		Name:         "Lento",
+		TemplateName: "default",
+		Subject:      "Novidades",
+		Segment: CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{
+			{Email: "ana@example.com"}, {Email: "bruno@example.com"}, {Email: "carla@example.com"},
+		}},
+		RatePerMinute: 1,
+	})
+	require.NoError(t, err)
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+
+	cancelled, err := svc.CancelCampaign(ctx, result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CampaignCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CancelledAt)
+	assert.Equal(t, CampaignProgress{Sent: 1, Cancelled: 2, Percent: 100}, cancelled.Progress)
+
+	for _, queued := range campaignEmails(t, db, result.ID)[1:] {
+		makeDue(t, db, queued.ID)
+	}
+	processed, err := svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed, "cancelled emails leave the queue")
+
+	_, err = svc.CancelCampaign(ctx, result.ID)
+	assertAPIStatus(t, err, http.StatusConflict)
+	_, err = svc.GetCampaign(ctx, 999)
+	assertAPIStatus(t, err, http.StatusNotFound)
+}
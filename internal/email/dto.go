@@ -1,5 +1,11 @@
 package email
 
+import (
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
 // SendEmailRequest representa a requisição para envio de email
 type SendEmailRequest struct {
 	To      []string `json:"to" binding:"required,min=1,dive,email"`
@@ -10,20 +16,243 @@ type SendEmailRequest struct {
 	IsHTML  bool     `json:"is_html"`
 }
 
-// SendTemplateEmailRequest representa a requisição para envio de email com template
+// SendTemplateEmailRequest representa a requisição para envio de email com template. TemplateName
+// é um template cadastrado ou um dos embutidos (default, welcome, notification); InlineImages são as
+// imagens que o HTML referencia como cid:<content_id>. Um email de Marketing tem um único
+// destinatário, é recusado se ele estiver na lista de supressão e recebe UnsubscribeURL nos dados.
+// Locale escolhe a variante do template (welcome.en); sem ele vale a preferência do usuário
+// cadastrado com o primeiro endereço de To e, por fim, email.default_locale.
 type SendTemplateEmailRequest struct {
 	To           []string               `json:"to" binding:"required,min=1,dive,email"`
 	Cc           []string               `json:"cc" binding:"omitempty,dive,email"`
 	Bcc          []string               `json:"bcc" binding:"omitempty,dive,email"`
 	Subject      string                 `json:"subject" binding:"required,min=1,max=500"`
-	TemplateName string                 `json:"template_name" binding:"required,oneof=default welcome notification"`
+	TemplateName string                 `json:"template_name" binding:"required,max=100"`
 	TemplateData map[string]interface{} `json:"template_data"`
+	InlineImages []InlineImage          `json:"inline_images" binding:"omitempty,dive"`
+	Marketing    bool                   `json:"marketing"`
+	Locale       string                 `json:"locale" binding:"omitempty,max=35,bcp47_language_tag"`
 }
 
-// EmailResponse representa a resposta do envio de email
+// EmailResponse representa a resposta do envio de email. ID identifica o email na fila de envio;
+// ProviderMessageID é o ID atribuído pelo provedor, conhecido depois do envio (vazio no SMTP, em que
+// vale o MessageID). Status e DeliveryStatus só aparecem na consulta de um email.
 type EmailResponse struct {
-	Success   bool     `json:"success"`
-	MessageID string   `json:"message_id,omitempty"`
-	SentTo    []string `json:"sent_to"`
-	Message   string   `json:"message"`
+	Success           bool     `json:"success"`
+	ID                uint     `json:"id,omitempty"`
+	MessageID         string   `json:"message_id,omitempty"`
+	Provider          string   `json:"provider,omitempty"`
+	ProviderMessageID string   `json:"provider_message_id,omitempty"`
+	Status            string   `json:"status,omitempty"`
+	DeliveryStatus    string   `json:"delivery_status,omitempty"`
+	SentTo            []string `json:"sent_to"`
+	Message           string   `json:"message"`
+}
+
+// Status possíveis de uma etapa do diagnóstico de configuração
+const (
+	DiagnosticStatusOK      = "ok"
+	DiagnosticStatusFailed  = "failed"
+	DiagnosticStatusSkipped = "skipped"
+)
+
+// DiagnosticStep representa o resultado de uma etapa do teste de configuração
+type DiagnosticStep struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// TestConfigResponse representa o diagnóstico do teste de configuração de email
+type TestConfigResponse struct {
+	Success           bool             `json:"success"`
+	Provider          string           `json:"provider"`
+	Host              string           `json:"host"`
+	Port              int              `json:"port"`
+	Username          string           `json:"username"`
+	From              string           `json:"from"`
+	UseTLS            bool             `json:"use_tls"`
+	UseStartTLS       bool             `json:"use_starttls"`
+	SentTo            string           `json:"sent_to"`
+	MessageID         string           `json:"message_id,omitempty"`
+	ProviderMessageID string           `json:"provider_message_id,omitempty"`
+	Steps             []DiagnosticStep `json:"steps"`
+}
+
+// DeadLetterListQuery representa a paginação da listagem de emails não enviados
+type DeadLetterListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// DeadLetterResponse representa um email que esgotou as tentativas de envio
+type DeadLetterResponse struct {
+	ID        uint      `json:"id"`
+	To        []string  `json:"to"`
+	Cc        []string  `json:"cc,omitempty"`
+	Bcc       []string  `json:"bcc,omitempty"`
+	Subject   string    `json:"subject"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// DeadLetterListResponse representa uma página de emails não enviados
+type DeadLetterListResponse = pagination.Page[DeadLetterResponse]
+
+// Filtros de status do log de envio
+const (
+	EmailLogStatusSent       = "sent"
+	EmailLogStatusRetrying   = "retrying"
+	EmailLogStatusFailed     = "failed"
+	EmailLogStatusDelivered  = "delivered"
+	EmailLogStatusBounced    = "bounced"
+	EmailLogStatusDropped    = "dropped"
+	EmailLogStatusDeferred   = "deferred"
+	EmailLogStatusComplained = "complained"
+	EmailLogStatusSuppressed = "suppressed"
+)
+
+// EmailLogListQuery representa os filtros do log de envio. Recipient busca parte do endereço em
+// to, cc e bcc; MessageID aceita o Message-ID ou o ID do provedor; From e To limitam a data.
+type EmailLogListQuery struct {
+	Page      int        `form:"page,default=1" binding:"min=1"`
+	Limit     int        `form:"limit,default=20" binding:"min=1,max=100"`
+	Recipient string     `form:"recipient"`
+	Status    string     `form:"status" binding:"omitempty,oneof=sent retrying failed delivered bounced dropped deferred complained suppressed"`
+	Template  string     `form:"template"`
+	EmailID   uint       `form:"email_id"`
+	MessageID string     `form:"message_id"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// EmailLogListResponse representa uma página do log de envio
+type EmailLogListResponse = pagination.Page[EmailLog]
+
+// CreateEmailTemplateRequest representa a criação de um template de email
+type CreateEmailTemplateRequest struct {
+	Name        string             `json:"name" binding:"required,max=100"`
+	Description string             `json:"description" binding:"max=500"`
+	HTML        string             `json:"html" binding:"required"`
+	Variables   []TemplateVariable `json:"variables" binding:"omitempty,dive"`
+}
+
+// UpdateEmailTemplateRequest representa a alteração de um template; os campos omitidos são
+// mantidos. Toda alteração gera uma nova versão.
+type UpdateEmailTemplateRequest struct {
+	Description *string             `json:"description" binding:"omitempty,max=500"`
+	HTML        *string             `json:"html" binding:"omitempty,min=1"`
+	Variables   *[]TemplateVariable `json:"variables" binding:"omitempty,dive"`
+}
+
+// EmailTemplateListQuery representa a paginação da listagem de templates
+type EmailTemplateListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// EmailTemplateListResponse representa uma página de templates
+type EmailTemplateListResponse = pagination.Page[EmailTemplate]
+
+// PreviewTemplateRequest representa os dados de um preview. Data completa os exemplos do esquema
+// de variáveis; Version escolhe uma versão salva em vez da atual.
+type PreviewTemplateRequest struct {
+	Data    map[string]interface{} `json:"data"`
+	Version int                    `json:"version" binding:"omitempty,min=1"`
+}
+
+// PreviewTemplateResponse representa o HTML renderizado de um template. MissingVariables lista as
+// variáveis obrigatórias que ficaram sem valor.
+type PreviewTemplateResponse struct {
+	Name             string   `json:"name"`
+	Version          int      `json:"version"`
+	HTML             string   `json:"html"`
+	MissingVariables []string `json:"missing_variables,omitempty"`
+}
+
+// WebhookResponse representa o resultado de uma chamada do webhook de entrega
+type WebhookResponse struct {
+	Processed int `json:"processed"`
+}
+
+// CreateCampaignRequest representa o envio de um template para um segmento. TemplateData vale para
+// todos os destinatários; Subject também é um template, com os mesmos dados do corpo.
+// RatePerMinute limita os envios da campanha (padrão 60 por minuto). As InlineImages são as mesmas
+// para todos os destinatários. Campanhas são emails de marketing: os destinatários da lista de
+// supressão são pulados. Locale força a variante do template para todos os destinatários; sem
+// ele, cada um recebe a do seu locale ou da preferência do usuário cadastrado.
+type CreateCampaignRequest struct {
+	Name          string                 `json:"name" binding:"required,max=200"`
+	TemplateName  string                 `json:"template_name" binding:"required,max=100"`
+	Subject       string                 `json:"subject" binding:"required,min=1,max=500"`
+	TemplateData  map[string]interface{} `json:"template_data"`
+	InlineImages  []InlineImage          `json:"inline_images" binding:"omitempty,dive"`
+	Segment       CampaignSegment        `json:"segment" binding:"required"`
+	RatePerMinute int                    `json:"rate_per_minute" binding:"omitempty,min=1,max=6000"`
+	Locale        string                 `json:"locale" binding:"omitempty,max=35,bcp47_language_tag"`
+}
+
+// CampaignProgress conta os emails de uma campanha por situação. Sent inclui os entregues e os
+// devolvidos informados pelo webhook do provedor, que também aparecem em Delivered e Bounced;
+// Bounced inclui os recusados pelo provedor.
+type CampaignProgress struct {
+	Pending    int `json:"pending"`
+	Sent       int `json:"sent"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Delivered  int `json:"delivered"`
+	Bounced    int `json:"bounced"`
+	Complained int `json:"complained"`
+	// Percent é a parte dos emails que já saiu da fila, de 0 a 100
+	Percent float64 `json:"percent"`
+}
+
+// CampaignResponse representa uma campanha com o andamento do envio
+type CampaignResponse struct {
+	EmailCampaign
+	Progress CampaignProgress `json:"progress"`
+}
+
+// CampaignListQuery representa a paginação da listagem de campanhas
+type CampaignListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// CampaignListResponse representa uma página de campanhas
+type CampaignListResponse = pagination.Page[CampaignResponse]
+
+// SuppressionListQuery representa os filtros da lista de supressão. Email busca parte do endereço.
+type SuppressionListQuery struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Email  string `form:"email"`
+	Reason string `form:"reason" binding:"omitempty,oneof=UNSUBSCRIBED BOUNCED COMPLAINED MANUAL"`
+}
+
+// SuppressionListResponse representa uma página da lista de supressão
+type SuppressionListResponse = pagination.Page[Suppression]
+
+// CreateSuppressionRequest representa a inclusão de um endereço na lista de supressão pelo
+// administrador. Reason é MANUAL quando omitido.
+type CreateSuppressionRequest struct {
+	Email  string `json:"email" binding:"required,email,max=320"`
+	Reason string `json:"reason" binding:"omitempty,oneof=UNSUBSCRIBED BOUNCED COMPLAINED MANUAL"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// UnsubscribeRequest representa o link de descadastro: o endereço e a assinatura dele, recebidos
+// na query string
+type UnsubscribeRequest struct {
+	Email string `form:"email" binding:"required,email"`
+	Token string `form:"token" binding:"required"`
+}
+
+// UnsubscribeResponse representa o resultado do descadastro
+type UnsubscribeResponse struct {
+	Email   string `json:"email"`
+	Message string `json:"message"`
 }
@@ -1,13 +1,20 @@
 package email
 
 import (
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
 	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
 )
 
+// maxWebhookBytes limita o corpo de uma chamada do webhook de entrega; o SendGrid agrupa até
+// alguns milhares de eventos por chamada
+const maxWebhookBytes = 8 << 20
+
 // Handler gerencia as requisições HTTP relacionadas a emails
 type Handler struct {
 	service Service
@@ -20,13 +27,13 @@ func NewHandler(service Service) *Handler {
 
 // SendEmail envia um email simples
 // @Summary Send email
-// @Description Send a simple email to one or more recipients
+// @Description Queue a simple email to one or more recipients. It is sent in the background and retried on failure.
 // @Tags emails
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body SendEmailRequest true "Email data"
-// @Success 200 {object} errors.Response{success=bool,data=EmailResponse}
+// @Success 202 {object} errors.Response{success=bool,data=EmailResponse}
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
@@ -44,18 +51,18 @@ func (h *Handler) SendEmail(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, apiErrors.Success(result))
+	c.JSON(http.StatusAccepted, apiErrors.Success(result))
 }
 
 // SendTemplateEmail envia um email usando um template HTML
 // @Summary Send template email
-// @Description Send an email using a predefined HTML template
+// @Description Queue an email using a stored HTML template, or one of the built-in default, welcome and notification templates. Template data is checked against the template's variable schema. Images referenced as cid:<content_id> in the HTML are embedded from inline_images, given as a stored file key or base64 data. It is sent in the background and retried on failure.
 // @Tags emails
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body SendTemplateEmailRequest true "Template email data"
-// @Success 200 {object} errors.Response{success=bool,data=EmailResponse}
+// @Success 202 {object} errors.Response{success=bool,data=EmailResponse}
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
@@ -73,5 +80,604 @@ func (h *Handler) SendTemplateEmail(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusAccepted, apiErrors.Success(result))
+}
+
+// TestConfiguration valida a configuração de email enviando uma mensagem de teste ao administrador
+// @Summary Test email configuration
+// @Description Validate the configuration of the email provider by sending a test message to the caller. For SMTP it connects and authenticates first. Returns per-step diagnostics.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=TestConfigResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/test [post]
+func (h *Handler) TestConfiguration(c *gin.Context) {
+	recipient := contextutil.GetEmail(c)
+	if recipient == "" {
+		_ = c.Error(apiErrors.Unauthorized("Authenticated user email not found"))
+		return
+	}
+
+	result, err := h.service.TestConfiguration(c.Request.Context(), recipient)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// ListDeadLetters lista os emails que esgotaram as tentativas de envio
+// @Summary List dead-lettered emails (Admin only)
+// @Description Paginated list of the queued emails that could not be sent after all retries, most recent first, with the last error
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=DeadLetterListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/dead-letters [get]
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	var query DeadLetterListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListDeadLetters(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// ListEmailLogs lista o log de envio de emails
+// @Summary List sent emails (Admin only)
+// @Description Paginated log of the send attempts of queued emails and of the delivery events reported by the provider, most recent first, with their status, error, Message-ID and provider message ID. Filter by a part of a to/cc/bcc address, status, template, queued email, Message-ID or date range.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param recipient query string false "Part of a to, cc or bcc address"
+// @Param status query string false "Attempt or delivery status" Enums(sent, retrying, failed, delivered, bounced, dropped, deferred, complained, suppressed)
+// @Param template query string false "Template name"
+// @Param email_id query int false "Queued email ID"
+// @Param message_id query string false "Message-ID or provider message ID"
+// @Param from query string false "Attempts at or after (RFC3339)"
+// @Param to query string false "Attempts at or before (RFC3339)"
+// @Success 200 {object} errors.Response{success=bool,data=EmailLogListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails [get]
+func (h *Handler) ListEmailLogs(c *gin.Context) {
+	var query EmailLogListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListEmailLogs(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// ListEmailTemplates lista os templates cadastrados
+// @Summary List email templates (Admin only)
+// @Description Paginated list of the stored email templates by name. The built-in templates are not listed.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=EmailTemplateListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates [get]
+func (h *Handler) ListEmailTemplates(c *gin.Context) {
+	var query EmailTemplateListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListEmailTemplates(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// GetEmailTemplate busca um template cadastrado
+// @Summary Get email template (Admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} errors.Response{success=bool,data=EmailTemplate}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates/{id} [get]
+func (h *Handler) GetEmailTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetEmailTemplate(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// CreateEmailTemplate cadastra um template
+// @Summary Create email template (Admin only)
+// @Description Store an HTML template (Go html/template syntax) with its variable schema. A template named like a built-in one replaces it when sending.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateEmailTemplateRequest true "Template data"
+// @Success 201 {object} errors.Response{success=bool,data=EmailTemplate}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates [post]
+func (h *Handler) CreateEmailTemplate(c *gin.Context) {
+	var req CreateEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.CreateEmailTemplate(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(result))
+}
+
+// UpdateEmailTemplate altera um template, criando uma nova versão
+// @Summary Update email template (Admin only)
+// @Description Change the description, HTML or variable schema of a template. Every update is saved as a new version.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param request body UpdateEmailTemplateRequest true "Fields to change"
+// @Success 200 {object} errors.Response{success=bool,data=EmailTemplate}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates/{id} [put]
+func (h *Handler) UpdateEmailTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.UpdateEmailTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// DeleteEmailTemplate remove um template e suas versões
+// @Summary Delete email template (Admin only)
+// @Description Delete a template and its versions. If it replaced a built-in template, the built-in one is used again.
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 204
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates/{id} [delete]
+func (h *Handler) DeleteEmailTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEmailTemplate(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListEmailTemplateVersions lista as versões salvas de um template
+// @Summary List email template versions (Admin only)
+// @Description Every saved version of a template, most recent first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} errors.Response{success=bool,data=[]EmailTemplateVersion}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates/{id}/versions [get]
+func (h *Handler) ListEmailTemplateVersions(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ListEmailTemplateVersions(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// PreviewEmailTemplate renderiza um template sem enviar
+// @Summary Preview email template (Admin only)
+// @Description Render the HTML of a template with sample data without sending it. The examples of the variable schema fill in the variables missing from data; required variables left without a value are listed. The body is optional.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param request body PreviewTemplateRequest false "Sample data and version"
+// @Success 200 {object} errors.Response{success=bool,data=PreviewTemplateResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/templates/{id}/preview [post]
+func (h *Handler) PreviewEmailTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	var req PreviewTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(apiErrors.FromGinValidation(err))
+			return
+		}
+	}
+
+	result, err := h.service.PreviewEmailTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// GetEmail retorna o status de envio e de entrega de um email da fila
+// @Summary Get email status (Admin only)
+// @Description Send and delivery status of a queued email, with the provider that sent it and the message ID it assigned
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Queued email ID"
+// @Success 200 {object} errors.Response{success=bool,data=EmailResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/{id} [get]
+func (h *Handler) GetEmail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid email ID"))
+		return
+	}
+
+	result, err := h.service.GetEmail(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// DeliveryWebhook recebe os eventos de entrega do provedor
+// @Summary Email delivery webhook
+// @Description Receives the delivery events of the configured provider: the SendGrid Event Webhook or the SNS notifications of Amazon SES (the subscription is confirmed automatically). Bounces, complaints, drops, deferrals and deliveries are recorded in the send log and the delivery status of the email.
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider" Enums(ses, sendgrid)
+// @Param token query string true "Value of email.webhook_secret"
+// @Success 200 {object} errors.Response{success=bool,data=WebhookResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/webhooks/{provider} [post]
+func (h *Handler) DeliveryWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Failed to read request body"))
+		return
+	}
+
+	result, err := h.service.HandleDeliveryWebhook(c.Request.Context(), c.Param("provider"), c.Query("token"), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// CreateCampaign envia um template para um segmento de destinatários
+// @Summary Create email campaign (Admin only)
+// @Description Send a template to every recipient of a segment: a list given in the request (each with its own template data) or the registered users, optionally of one role. Each recipient gets a personalized email in the queue, and the emails are spread over time according to rate_per_minute. The subject is a template too, and RecipientEmail and RecipientName are available to both.
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateCampaignRequest true "Campaign data"
+// @Success 202 {object} errors.Response{success=bool,data=CampaignResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/campaigns [post]
+func (h *Handler) CreateCampaign(c *gin.Context) {
+	var req CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.CreateCampaign(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, apiErrors.Success(result))
+}
+
+// ListCampaigns lista as campanhas com o andamento do envio
+// @Summary List email campaigns (Admin only)
+// @Description Campaigns, newest first, with the number of emails pending, sent, failed, cancelled and the delivery events reported by the provider
+// @Tags emails
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=CampaignListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/campaigns [get]
+func (h *Handler) ListCampaigns(c *gin.Context) {
+	var query CampaignListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListCampaigns(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// GetCampaign retorna o andamento de uma campanha
+// @Summary Get email campaign progress (Admin only)
+// @Tags emails
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} errors.Response{success=bool,data=CampaignResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/campaigns/{id} [get]
+func (h *Handler) GetCampaign(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
 	c.JSON(http.StatusOK, apiErrors.Success(result))
 }
+
+// CancelCampaign interrompe uma campanha em envio
+// @Summary Cancel email campaign (Admin only)
+// @Description Stop a campaign that is still sending. Its emails still in the queue are not sent.
+// @Tags emails
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} errors.Response{success=bool,data=CampaignResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/campaigns/{id}/cancel [post]
+func (h *Handler) CancelCampaign(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.CancelCampaign(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// ListSuppressions lista os endereços que não recebem emails de marketing
+// @Summary List email suppressions (Admin only)
+// @Description Paginated list of the addresses that do not receive marketing emails (campaigns and template emails sent as marketing), most recent first: recipients who unsubscribed, hard bounces and spam complaints reported by the provider, and addresses added by an admin.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param email query string false "Part of the address"
+// @Param reason query string false "Suppression reason" Enums(UNSUBSCRIBED, BOUNCED, COMPLAINED, MANUAL)
+// @Success 200 {object} errors.Response{success=bool,data=SuppressionListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/suppressions [get]
+func (h *Handler) ListSuppressions(c *gin.Context) {
+	var query SuppressionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListSuppressions(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// CreateSuppression inclui um endereço na lista de supressão
+// @Summary Add email suppression (Admin only)
+// @Description Stop sending marketing emails to an address. The reason defaults to MANUAL. Transactional emails are still sent.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateSuppressionRequest true "Address to suppress"
+// @Success 201 {object} errors.Response{success=bool,data=Suppression}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/suppressions [post]
+func (h *Handler) CreateSuppression(c *gin.Context) {
+	var req CreateSuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.CreateSuppression(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(result))
+}
+
+// DeleteSuppression remove um endereço da lista de supressão
+// @Summary Delete email suppression (Admin only)
+// @Description Remove an address from the suppression list so it receives marketing emails again. Only do this when the recipient asked for it.
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Suppression ID"
+// @Success 204
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/emails/suppressions/{id} [delete]
+func (h *Handler) DeleteSuppression(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid suppression ID"))
+		return
+	}
+
+	if err := h.service.DeleteSuppression(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Unsubscribe descadastra o destinatário de um link de descadastro
+// @Summary Unsubscribe from marketing emails
+// @Description Target of the unsubscribe link of marketing emails and of the one-click unsubscribe of mail clients (List-Unsubscribe-Post). The address stops receiving marketing emails; calling it again is not an error. email.unsubscribe_url may also point at a site page that calls this endpoint with the same query parameters.
+// @Tags emails
+// @Produce json
+// @Param email query string true "Recipient address"
+// @Param token query string true "Signature of the address, from the unsubscribe link"
+// @Success 200 {object} errors.Response{success=bool,data=UnsubscribeResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/emails/unsubscribe [post]
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	var req UnsubscribeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.Unsubscribe(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// campaignID lê o ID da campanha da rota, respondendo 400 quando inválido
+func campaignID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid campaign ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// templateID lê o ID do template da rota, respondendo 400 quando inválido
+func templateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid template ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
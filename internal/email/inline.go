@@ -0,0 +1,149 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	mail "github.com/wneessen/go-mail"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+const (
+	// maxInlineImages limita as imagens embutidas em um email
+	maxInlineImages = 10
+	// maxInlineImageBytes limita o tamanho de cada imagem embutida
+	maxInlineImageBytes = 3 << 20
+	// maxInlineTotalBytes limita a soma das imagens embutidas, abaixo do limite de mensagem dos provedores
+	maxInlineTotalBytes = 10 << 20
+	// inlineImagePrefix é o prefixo no storage das imagens enviadas em Data
+	inlineImagePrefix = "emails/inline/"
+)
+
+// contentIDPattern aceita os Content-IDs que podem ser usados em cid: sem escape
+var contentIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(@[A-Za-z0-9.-]+)?$`)
+
+// inlineImageExtensions são os tipos de imagem exibidos pelos clientes de email, com a extensão usada
+// no nome do arquivo
+var inlineImageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// resolveInlineImages valida as imagens embutidas de um envio e retorna as referências gravadas no
+// email: as imagens de Data são armazenadas com uma chave derivada do conteúdo, então reenviar a mesma
+// imagem não cria outro arquivo.
+func (s *service) resolveInlineImages(ctx context.Context, images []InlineImage) ([]InlineImage, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+	if s.storage == nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("inline images require a storage"))
+	}
+	if len(images) > maxInlineImages {
+		return nil, apiErrors.BadRequest(fmt.Sprintf("At most %d inline images are allowed", maxInlineImages))
+	}
+
+	resolved := make([]InlineImage, len(images))
+	seen := make(map[string]bool, len(images))
+	total := 0
+	for i, image := range images {
+		cid := strings.Trim(image.ContentID, "<>")
+		switch {
+		case !contentIDPattern.MatchString(cid):
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Invalid inline image content_id '%s'", image.ContentID))
+		case seen[cid]:
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Inline image '%s' is declared more than once", cid))
+		case (image.StorageKey == "") == (len(image.Data) == 0):
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Inline image '%s' needs either storage_key or data", cid))
+		}
+		seen[cid] = true
+
+		data := image.Data
+		if image.StorageKey != "" {
+			var err error
+			data, err = s.readInlineImage(ctx, image.StorageKey)
+			if err != nil {
+				slog.Warn("Failed to read inline image", "content_id", cid, "key", image.StorageKey, "error", err)
+				return nil, apiErrors.BadRequest(fmt.Sprintf("Inline image '%s' was not found in the storage", cid))
+			}
+		}
+		if len(data) > maxInlineImageBytes {
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Inline image '%s' is larger than %d bytes", cid, maxInlineImageBytes))
+		}
+		total += len(data)
+		if total > maxInlineTotalBytes {
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Inline images add up to more than %d bytes", maxInlineTotalBytes))
+		}
+
+		// O tipo é detectado pelo conteúdo, como nos uploads
+		contentType := strings.ToLower(strings.SplitN(http.DetectContentType(data), ";", 2)[0])
+		ext, ok := inlineImageExtensions[contentType]
+		if !ok {
+			return nil, apiErrors.BadRequest(fmt.Sprintf("Inline image '%s' must be a PNG, JPEG or GIF image, not %s", cid, contentType))
+		}
+
+		key := image.StorageKey
+		if key == "" {
+			sum := sha256.Sum256(data)
+			key = inlineImagePrefix + hex.EncodeToString(sum[:]) + ext
+			if _, err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+				return nil, apiErrors.InternalServerError(fmt.Errorf("failed to store inline image: %w", err))
+			}
+		}
+
+		filename := image.Filename
+		if filename == "" {
+			filename = strings.SplitN(cid, "@", 2)[0] + ext
+		}
+		resolved[i] = InlineImage{ContentID: cid, StorageKey: key, Filename: filename, ContentType: contentType}
+	}
+	return resolved, nil
+}
+
+// readInlineImage lê uma imagem do storage, até um byte além do limite para detectar arquivos maiores
+func (s *service) readInlineImage(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := body.Close(); err != nil {
+			slog.Error("Failed to close inline image", "key", key, "error", err)
+		}
+	}()
+	return io.ReadAll(io.LimitReader(body, maxInlineImageBytes+1))
+}
+
+// embedInlineImages lê as imagens do storage e as embute na mensagem com o Content-ID de cada uma
+func (s *service) embedInlineImages(ctx context.Context, msg *mail.Msg, images []InlineImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+	if s.storage == nil {
+		return fmt.Errorf("inline images require a storage")
+	}
+
+	for _, image := range images {
+		data, err := s.readInlineImage(ctx, image.StorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to read inline image %s: %w", image.ContentID, err)
+		}
+		if err := msg.EmbedReader(image.Filename, bytes.NewReader(data),
+			mail.WithFileContentID("<"+image.ContentID+">"),
+			mail.WithFileContentType(mail.ContentType(image.ContentType)),
+		); err != nil {
+			return fmt.Errorf("failed to embed inline image %s: %w", image.ContentID, err)
+		}
+	}
+	return nil
+}
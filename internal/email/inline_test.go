@@ -0,0 +1,160 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	return buf.Bytes()
+}
+
+// newInlineService returns a queue service with a local storage in a temporary directory
+func newInlineService(t *testing.T, send func(ctx context.Context, msg *mail.Msg) error) (*service, *gorm.DB, string) {
+	t.Helper()
+	svc, db := newQueueService(t, send)
+	dir := t.TempDir()
+	svc.storage = storage.NewLocalStorage(dir, "http://localhost:8080/uploads")
+	return svc, db, dir
+}
+
+func logoRequest(images ...InlineImage) *SendTemplateEmailRequest {
+	return &SendTemplateEmailRequest{
+		To:           []string{"cliente@example.com"},
+		Subject:      "Novidades",
+		TemplateName: "default",
+		TemplateData: map[string]interface{}{"Body": `<img src="cid:logo">`},
+		InlineImages: images,
+	}
+}
+
+func TestService_SendTemplateEmail_InlineImages(t *testing.T) {
+	ctx := context.Background()
+	var sent []*mail.Msg
+	svc, db, dir := newInlineService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	logo := pngBytes(t)
+	_, err := svc.storage.Put(ctx, "imoveis/1/foto.png", bytes.NewReader(logo), int64(len(logo)), "image/png")
+	require.NoError(t, err)
+
+	resp, err := svc.SendTemplateEmail(ctx, logoRequest(
+		InlineImage{ContentID: "<logo>", Data: logo},
+		InlineImage{ContentID: "foto@triiio", StorageKey: "imoveis/1/foto.png", Filename: "apartamento.png"},
+	))
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(logo)
+	dataKey := "emails/inline/" + hex.EncodeToString(sum[:]) + ".png"
+	assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(dataKey)), "data images are stored by content")
+
+	queued := outboxEmail(t, db, resp.ID)
+	assert.Equal(t, []InlineImage{
+		{ContentID: "logo", StorageKey: dataKey, Filename: "logo.png", ContentType: "image/png"},
+		{ContentID: "foto@triiio", StorageKey: "imoveis/1/foto.png", Filename: "apartamento.png", ContentType: "image/png"},
+	}, queued.InlineImages)
+
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+
+	var raw bytes.Buffer
+	_, err = sent[0].WriteTo(&raw)
+	require.NoError(t, err)
+	assert.Contains(t, raw.String(), "Content-Id: <logo>")
+	assert.Contains(t, raw.String(), "Content-Id: <foto@triiio>")
+	assert.Contains(t, raw.String(), `Content-Disposition: inline; filename="apartamento.png"`)
+	assert.Contains(t, raw.String(), base64.StdEncoding.EncodeToString(logo)[:40])
+}
+
+func TestService_SendTemplateEmail_InvalidInlineImages(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newInlineService(t, nil)
+	logo := pngBytes(t)
+
+	tooMany := make([]InlineImage, maxInlineImages+1)
+	for i := range tooMany {
+		tooMany[i] = InlineImage{ContentID: "img" + strings.Repeat("x", i), Data: logo}
+	}
+
+	tests := map[string][]InlineImage{
+		"no source":      {{ContentID: "logo"}},
+		"two sources":    {{ContentID: "logo", Data: logo, StorageKey: "imoveis/1/foto.png"}},
+		"invalid id":     {{ContentID: "logo principal", Data: logo}},
+		"repeated id":    {{ContentID: "logo", Data: logo}, {ContentID: "<logo>", Data: logo}},
+		"not an image":   {{ContentID: "logo", Data: []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>")}},
+		"missing file":   {{ContentID: "logo", StorageKey: "imoveis/1/nao-existe.png"}},
+		"too many files": tooMany,
+	}
+	for name, images := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.SendTemplateEmail(ctx, logoRequest(images...))
+			assertAPIStatus(t, err, http.StatusBadRequest)
+		})
+	}
+
+	svc.storage = nil
+	_, err := svc.SendTemplateEmail(ctx, logoRequest(InlineImage{ContentID: "logo", Data: logo}))
+	assertAPIStatus(t, err, http.StatusInternalServerError)
+}
+
+func TestService_DeliverQueued_RetriesMissingInlineImage(t *testing.T) {
+	ctx := context.Background()
+	svc, db, dir := newInlineService(t, func(ctx context.Context, msg *mail.Msg) error {
+		t.Fatal("email sent without its inline image")
+		return nil
+	})
+
+	logo := pngBytes(t)
+	_, err := svc.storage.Put(ctx, "logos/triiio.png", bytes.NewReader(logo), int64(len(logo)), "image/png")
+	require.NoError(t, err)
+	resp, err := svc.SendTemplateEmail(ctx, logoRequest(InlineImage{ContentID: "logo", StorageKey: "logos/triiio.png"}))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(dir, "logos", "triiio.png")))
+
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+
+	queued := outboxEmail(t, db, resp.ID)
+	assert.Equal(t, OutboxPending, queued.Status)
+	assert.Contains(t, queued.LastError, "failed to read inline image logo")
+}
+
+func TestNewSendGridMessage_InlineImages(t *testing.T) {
+	msg := testMessage(t)
+	logo := pngBytes(t)
+	require.NoError(t, msg.EmbedReader("logo.png", bytes.NewReader(logo),
+		mail.WithFileContentID("<logo>"), mail.WithFileContentType("image/png")))
+
+	payload, err := newSendGridMessage(42, msg)
+	require.NoError(t, err)
+	assert.Equal(t, []sendGridAttachment{{
+		Content:     base64.StdEncoding.EncodeToString(logo),
+		Type:        "image/png",
+		Filename:    "logo.png",
+		Disposition: "inline",
+		ContentID:   "logo",
+	}}, payload.Attachments)
+}
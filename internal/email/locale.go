@@ -0,0 +1,84 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// defaultLocale se aplica quando email.default_locale não está configurado
+const defaultLocale = "pt-BR"
+
+// normalizeLocale retorna a forma canônica de uma tag BCP 47 (pt-br vira pt-BR), "" para nenhuma
+func normalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", apiErrors.BadRequest(fmt.Sprintf("Invalid locale '%s'", locale))
+	}
+	return tag.String(), nil
+}
+
+// templateName valida o nome de um template cadastrado: um slug, opcionalmente seguido de "." e do
+// locale da variante, que é gravado na forma canônica
+func templateName(name string) (string, error) {
+	base, locale, hasLocale := strings.Cut(strings.TrimSpace(name), ".")
+	if !templateNamePattern.MatchString(base) {
+		return "", apiErrors.BadRequest("Template name must contain only lowercase letters, digits, '-' and '_', optionally followed by '.' and a locale")
+	}
+	if !hasLocale {
+		return base, nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", apiErrors.BadRequest(fmt.Sprintf("Invalid template locale '%s'", locale))
+	}
+	return base + "." + tag.String(), nil
+}
+
+// templateCandidates retorna os nomes procurados para o template name em locale, do mais específico
+// ao mais genérico: a variante do locale (welcome.pt-BR), a do idioma (welcome.pt), as mesmas do
+// locale padrão e por fim o template sem sufixo, que está no locale padrão
+func (s *service) templateCandidates(name, locale string) []string {
+	candidates := make([]string, 0, 5)
+	seen := make(map[string]bool, 5)
+	add := func(tag string) {
+		if tag == "" || seen[tag] {
+			return
+		}
+		seen[tag] = true
+		candidates = append(candidates, name+"."+tag)
+	}
+	for _, l := range []string{locale, s.defaultLocale} {
+		parsed, err := language.Parse(l)
+		if err != nil {
+			continue
+		}
+		add(parsed.String())
+		if base, confidence := parsed.Base(); confidence != language.No {
+			add(base.String())
+		}
+	}
+	return append(candidates, name)
+}
+
+// recipientLocales retorna o locale preferido dos usuários cadastrados com os endereços, pelo
+// endereço em minúsculas; endereços sem usuário ou sem preferência ficam de fora
+func (s *service) recipientLocales(ctx context.Context, addresses []string) (map[string]string, error) {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = normalizeAddress(address)
+	}
+	locales, err := s.repo.FindUserLocales(ctx, normalized)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to find recipient locales: %w", err))
+	}
+	return locales, nil
+}
@@ -0,0 +1,107 @@
+package email
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+)
+
+func TestService_TemplateCandidates(t *testing.T) {
+	svc, _ := newQueueService(t, nil)
+
+	assert.Equal(t, []string{"welcome.en-US", "welcome.en", "welcome.pt-BR", "welcome.pt", "welcome"}, svc.templateCandidates("welcome", "en-US"))
+	assert.Equal(t, []string{"welcome.pt", "welcome.pt-BR", "welcome"}, svc.templateCandidates("welcome", "pt"))
+	assert.Equal(t, []string{"welcome.pt-BR", "welcome.pt", "welcome"}, svc.templateCandidates("welcome", ""))
+}
+
+func TestTemplateName(t *testing.T) {
+	name, err := templateName(" welcome ")
+	require.NoError(t, err)
+	assert.Equal(t, "welcome", name)
+
+	name, err = templateName("welcome.pt-br")
+	require.NoError(t, err)
+	assert.Equal(t, "welcome.pt-BR", name, "the locale is stored in canonical form")
+
+	_, err = templateName("welcome.not a locale")
+	assertAPIStatus(t, err, http.StatusBadRequest)
+	_, err = templateName("Welcome.en")
+	assertAPIStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_SendTemplateEmail_Locale(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, nil)
+	require.NoError(t, db.Exec(`INSERT INTO users (name, email, locale) VALUES ('Ana', 'ana@example.com', 'en'), ('Bruno', 'bruno@example.com', NULL)`).Error)
+
+	send := func(to, locale string) string {
+		t.Helper()
+		resp, err := svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{
+			To:           []string{to},
+			Subject:      "Bem-vindo",
+			TemplateName: "welcome",
+			TemplateData: map[string]interface{}{"UserName": "Ana", "UserEmail": to},
+			Locale:       locale,
+		})
+		require.NoError(t, err)
+		return outboxEmail(t, db, resp.ID).Body
+	}
+
+	assert.Contains(t, send("ANA@example.com", ""), "Welcome to Triiio!", "the stored preference selects the variant")
+	assert.Contains(t, send("ana@example.com", "pt-BR"), "Bem-vindo ao Triiio!", "the request locale wins over the preference")
+	assert.Contains(t, send("bruno@example.com", ""), "Bem-vindo ao Triiio!", "no preference uses the default locale")
+	assert.Contains(t, send("bruno@example.com", "en-GB"), "Welcome to Triiio!", "a regional locale falls back to its language")
+	assert.Contains(t, send("bruno@example.com", "es"), "Bem-vindo ao Triiio!", "a locale without variant falls back to the default")
+
+	// A stored variant replaces the built-in one of the same locale only
+	_, err := svc.CreateEmailTemplate(ctx, &CreateEmailTemplateRequest{Name: "welcome.EN", HTML: `<p>Hi {{.UserName}}</p>`})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi Ana</p>", send("bruno@example.com", "en"))
+	assert.Contains(t, send("bruno@example.com", ""), "Bem-vindo ao Triiio!")
+
+	_, err = svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{To: []string{"ana@example.com"}, Subject: "Oi", TemplateName: "missing", Locale: "en"})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_CreateCampaign_Locale(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error { return nil })
+	require.NoError(t, db.Exec(`INSERT INTO users (name, email, locale) VALUES ('Ana', 'ana@example.com', 'en')`).Error)
+	_, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+	english := proposalTemplate()
+	english.Name = "proposal.en"
+	english.HTML = `<p>Hello {{.Name}}, your {{.Amount}} proposal was received.</p>`
+	_, err = svc.CreateEmailTemplate(ctx, english)
+	require.NoError(t, err)
+
+	request := &CreateCampaignRequest{
+		Name:         "Lançamento Jardins",
+		TemplateName: "proposal",
+		Subject:      "Novidades",
+		TemplateData: map[string]interface{}{"Name": "cliente", "Amount": 1000},
+		Segment: CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{
+			{Email: "ana@example.com"},
+			{Email: "bruno@example.com"},
+			{Email: "carla@example.com", Locale: "en-US"},
+		}},
+	}
+	result, err := svc.CreateCampaign(ctx, request)
+	require.NoError(t, err)
+	emails := campaignEmails(t, db, result.ID)
+	require.Len(t, emails, 3)
+	assert.Contains(t, emails[0].Body, "Hello cliente", "the stored preference selects the variant")
+	assert.Contains(t, emails[1].Body, "Olá cliente")
+	assert.Contains(t, emails[2].Body, "Hello cliente", "the recipient locale selects the variant")
+
+	request.Locale = "pt-BR"
+	result, err = svc.CreateCampaign(ctx, request)
+	require.NoError(t, err)
+	for _, queued := range campaignEmails(t, db, result.ID) {
+		assert.Contains(t, queued.Body, "Olá cliente", "the campaign locale applies to every recipient")
+	}
+}
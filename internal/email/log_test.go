@@ -0,0 +1,81 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+)
+
+func TestService_ListEmailLogs(t *testing.T) {
+	ctx := context.Background()
+	failing := map[string]bool{"falha@example.com": true}
+	var sentIDs []string
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		if failing[strings.Trim(msg.GetToString()[0], "<>")] {
+			return errors.New("550 mailbox unavailable")
+		}
+		sentIDs = append(sentIDs, msg.GetMessageID())
+		return nil
+	})
+
+	proposta, err := svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{
+		To:           []string{"Cliente@Example.com"},
+		Subject:      "Sua proposta",
+		TemplateName: "notification",
+		TemplateData: map[string]interface{}{"Title": "Proposta"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, proposta.MessageID)
+
+	falha, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"falha@example.com"}, Bcc: []string{"copia@example.com"}, Subject: "Aviso", Body: "x"})
+	require.NoError(t, err)
+
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	makeDue(t, db, falha.ID)
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+
+	// The Message-ID returned when queueing is the one sent
+	assert.Equal(t, []string{"<" + proposta.MessageID + ">"}, sentIDs)
+
+	list := func(query EmailLogListQuery) *EmailLogListResponse {
+		t.Helper()
+		query.Page, query.Limit = 1, 20
+		result, err := svc.ListEmailLogs(ctx, &query)
+		require.NoError(t, err)
+		return result
+	}
+
+	all := list(EmailLogListQuery{})
+	assert.Equal(t, int64(3), all.Total)
+
+	received := list(EmailLogListQuery{Recipient: "cliente@example"})
+	require.Len(t, received.Results, 1)
+	assert.Equal(t, LogSent, received.Results[0].Status)
+	assert.Equal(t, "notification", received.Results[0].TemplateName)
+	assert.Equal(t, proposta.MessageID, received.Results[0].MessageID)
+	assert.Equal(t, 1, received.Results[0].Attempt)
+
+	retries := list(EmailLogListQuery{Recipient: "copia@", Status: EmailLogStatusRetrying})
+	require.Len(t, retries.Results, 2)
+	assert.Equal(t, 2, retries.Results[0].Attempt, "most recent first")
+	assert.Equal(t, "550 mailbox unavailable", retries.Results[0].Error)
+
+	assert.Len(t, list(EmailLogListQuery{EmailID: falha.ID}).Results, 2)
+	assert.Len(t, list(EmailLogListQuery{MessageID: "<" + proposta.MessageID + ">"}).Results, 1)
+	assert.Len(t, list(EmailLogListQuery{Template: "welcome"}).Results, 0)
+
+	future := time.Now().Add(time.Hour)
+	assert.Len(t, list(EmailLogListQuery{From: &future}).Results, 0)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = svc.ListEmailLogs(ctx, &EmailLogListQuery{Page: 1, Limit: 20, From: &future, To: &past})
+	assert.Error(t, err)
+}
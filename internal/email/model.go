@@ -0,0 +1,229 @@
+package email
+
+import "time"
+
+// Status de um email na fila de envio
+const (
+	OutboxPending = "PENDING"
+	OutboxSent    = "SENT"
+	OutboxDead    = "DEAD" // esgotou as tentativas ou não pode ser montado; fica para análise
+	// OutboxCancelled é um email que não foi enviado porque a campanha foi cancelada ou, no
+	// marketing, porque o destinatário entrou na lista de supressão
+	OutboxCancelled = "CANCELLED"
+)
+
+// OutboxEmail é um email aguardando envio pelo dispatcher em segundo plano. Emails de Marketing
+// não são enviados a endereços da lista de supressão e levam o link de descadastro.
+type OutboxEmail struct {
+	ID                uint          `gorm:"primaryKey" json:"id"`
+	To                []string      `gorm:"column:recipients;serializer:json;type:jsonb;not null" json:"to"`
+	Cc                []string      `gorm:"serializer:json;type:jsonb" json:"cc,omitempty"`
+	Bcc               []string      `gorm:"serializer:json;type:jsonb" json:"bcc,omitempty"`
+	Subject           string        `gorm:"not null" json:"subject"`
+	Body              string        `gorm:"not null" json:"body"`
+	IsHTML            bool          `gorm:"not null" json:"is_html"`
+	InlineImages      []InlineImage `gorm:"serializer:json;type:jsonb" json:"inline_images,omitempty"`
+	TemplateName      string        `json:"template_name,omitempty"`
+	Marketing         bool          `gorm:"not null" json:"marketing"`
+	MessageID         string        `json:"message_id,omitempty"`
+	Status            string        `gorm:"not null" json:"status"`
+	Provider          string        `json:"provider,omitempty"`
+	ProviderMessageID string        `json:"provider_message_id,omitempty"`
+	DeliveryStatus    string        `json:"delivery_status,omitempty"`
+	DeliveryUpdatedAt *time.Time    `json:"delivery_updated_at,omitempty"`
+	Attempts          int           `gorm:"not null" json:"attempts"`
+	NextAttemptAt     time.Time     `gorm:"not null" json:"next_attempt_at"`
+	LastError         string        `json:"last_error,omitempty"`
+	SentAt            *time.Time    `json:"sent_at,omitempty"`
+	CampaignID        *uint         `json:"campaign_id,omitempty"`
+	OrganizacaoID     *uint         `gorm:"index" json:"organizacao_id,omitempty"` // set from the request tenant
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+func (OutboxEmail) TableName() string {
+	return "email_outbox"
+}
+
+// InlineImage é uma imagem embutida no HTML de um email, referenciada como cid:<content_id>. Na
+// requisição a imagem vem de StorageKey (um arquivo já armazenado, como o path de um anexo) ou de
+// Data; a imagem de Data é armazenada ao enfileirar, então o email guarda só StorageKey.
+type InlineImage struct {
+	ContentID   string `json:"content_id" binding:"required,max=100"`
+	StorageKey  string `json:"storage_key,omitempty" binding:"max=500"`
+	Data        []byte `json:"data,omitempty" swaggertype:"string" format:"base64"`
+	Filename    string `json:"filename,omitempty" binding:"max=200"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Status de uma tentativa de envio no log
+const (
+	LogSent     = "SENT"
+	LogRetrying = "RETRYING" // falhou e será tentado de novo
+	LogFailed   = "FAILED"   // falhou e o email foi para os não enviados
+	// LogSuppressed é um email de marketing cancelado porque o destinatário está na lista de supressão
+	LogSuppressed = "SUPPRESSED"
+)
+
+// Status de entrega informados pelo webhook do provedor, gravados no log e em DeliveryStatus
+const (
+	LogDelivered  = "DELIVERED"
+	LogBounced    = "BOUNCED"
+	LogDropped    = "DROPPED" // recusado pelo provedor antes do envio
+	LogDeferred   = "DEFERRED"
+	LogComplained = "COMPLAINED" // marcado como spam pelo destinatário
+)
+
+// EmailLog registra uma tentativa de envio de um email da fila ou um evento de entrega informado
+// pelo provedor
+type EmailLog struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	EmailID           uint      `gorm:"not null;index" json:"email_id"`
+	To                []string  `gorm:"column:recipients;serializer:json;type:jsonb;not null" json:"to"`
+	Cc                []string  `gorm:"serializer:json;type:jsonb" json:"cc,omitempty"`
+	Bcc               []string  `gorm:"serializer:json;type:jsonb" json:"bcc,omitempty"`
+	Subject           string    `gorm:"not null" json:"subject"`
+	TemplateName      string    `json:"template_name,omitempty"`
+	Status            string    `gorm:"not null" json:"status"`
+	Error             string    `json:"error,omitempty"`
+	MessageID         string    `json:"message_id,omitempty"`
+	Provider          string    `json:"provider,omitempty"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	Attempt           int       `gorm:"not null" json:"attempt"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func (EmailLog) TableName() string {
+	return "email_logs"
+}
+
+// Tipos aceitos no esquema de variáveis de um template
+const (
+	VariableString  = "string"
+	VariableNumber  = "number"
+	VariableBoolean = "boolean"
+	VariableList    = "list"
+	VariableObject  = "object"
+)
+
+// TemplateVariable descreve uma variável usada por um template. Example é usado no preview quando
+// a variável não é informada.
+type TemplateVariable struct {
+	Name        string      `json:"name" binding:"required,max=100"`
+	Type        string      `json:"type,omitempty" binding:"omitempty,oneof=string number boolean list object"`
+	Required    bool        `json:"required"`
+	Description string      `json:"description,omitempty" binding:"max=500"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+// EmailTemplate é um template HTML editável pela API. Version aumenta a cada alteração.
+type EmailTemplate struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	Name        string             `gorm:"uniqueIndex;not null" json:"name"`
+	Description string             `json:"description,omitempty"`
+	HTML        string             `gorm:"column:html;not null" json:"html"`
+	Variables   []TemplateVariable `gorm:"serializer:json;type:jsonb" json:"variables"`
+	Version     int                `gorm:"not null" json:"version"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+func (EmailTemplate) TableName() string {
+	return "email_templates"
+}
+
+// EmailTemplateVersion guarda uma versão salva de um template
+type EmailTemplateVersion struct {
+	ID         uint               `gorm:"primaryKey" json:"id"`
+	TemplateID uint               `gorm:"not null" json:"template_id"`
+	Version    int                `gorm:"not null" json:"version"`
+	HTML       string             `gorm:"column:html;not null" json:"html"`
+	Variables  []TemplateVariable `gorm:"serializer:json;type:jsonb" json:"variables"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func (EmailTemplateVersion) TableName() string {
+	return "email_template_versions"
+}
+
+// Status de uma campanha. Só SENDING e CANCELLED são gravados; uma campanha em envio aparece como
+// concluída quando não há mais emails dela na fila.
+const (
+	CampaignSending   = "SENDING"
+	CampaignCompleted = "COMPLETED"
+	CampaignCancelled = "CANCELLED"
+)
+
+// Tipos de segmento de destinatários de uma campanha
+const (
+	SegmentRecipients = "recipients" // lista informada na requisição
+	SegmentUsers      = "users"      // usuários cadastrados, opcionalmente de um papel
+)
+
+// CampaignSegment descreve os destinatários de uma campanha. Recipients só é usado no segmento
+// recipients e não é gravado; os destinatários ficam nos emails da fila.
+type CampaignSegment struct {
+	Type       string              `json:"type" binding:"required,oneof=recipients users"`
+	Role       string              `json:"role,omitempty" binding:"max=50"`
+	Recipients []CampaignRecipient `json:"recipients,omitempty" binding:"omitempty,max=10000,dive"`
+}
+
+// CampaignRecipient é um destinatário de campanha. Data personaliza o template para ele,
+// sobrepondo os dados da campanha; Locale escolhe a variante do template, e sem ele vale a
+// preferência do usuário cadastrado com o endereço.
+type CampaignRecipient struct {
+	Email  string                 `json:"email" binding:"required,email"`
+	Name   string                 `json:"name,omitempty" binding:"max=200"`
+	Locale string                 `json:"locale,omitempty" binding:"omitempty,max=35,bcp47_language_tag"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+// EmailCampaign é o envio de um template para um segmento. Cada destinatário recebe um email da
+// fila, agendado para respeitar RatePerMinute.
+type EmailCampaign struct {
+	ID            uint                   `gorm:"primaryKey" json:"id"`
+	Name          string                 `gorm:"not null" json:"name"`
+	TemplateName  string                 `gorm:"not null" json:"template_name"`
+	Subject       string                 `gorm:"not null" json:"subject"`
+	TemplateData  map[string]interface{} `gorm:"serializer:json;type:jsonb" json:"template_data,omitempty"`
+	InlineImages  []InlineImage          `gorm:"serializer:json;type:jsonb" json:"inline_images,omitempty"`
+	Segment       CampaignSegment        `gorm:"serializer:json;type:jsonb;not null" json:"segment"`
+	RatePerMinute int                    `gorm:"not null" json:"rate_per_minute"`
+	Total         int                    `gorm:"not null" json:"total"`
+	Suppressed    int                    `gorm:"not null" json:"suppressed"`
+	Status        string                 `gorm:"not null" json:"status"`
+	CreatedBy     *uint                  `json:"created_by,omitempty"`
+	OrganizacaoID *uint                  `gorm:"index" json:"organizacao_id,omitempty"` // set from the request tenant
+	CancelledAt   *time.Time             `json:"cancelled_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+func (EmailCampaign) TableName() string {
+	return "email_campaigns"
+}
+
+// Motivos de um endereço estar na lista de supressão
+const (
+	SuppressionUnsubscribed = "UNSUBSCRIBED" // o destinatário usou o link de descadastro
+	SuppressionBounced      = "BOUNCED"      // devolução permanente informada pelo provedor
+	SuppressionComplained   = "COMPLAINED"   // o destinatário marcou um email como spam
+	SuppressionManual       = "MANUAL"       // incluído por um administrador
+)
+
+// Suppression é um endereço que não recebe emails de marketing. Email é gravado em minúsculas;
+// EmailID é o email que originou o descadastro, a devolução ou a reclamação.
+type Suppression struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
+	Reason    string    `gorm:"not null" json:"reason"`
+	Note      string    `json:"note,omitempty"`
+	EmailID   *uint     `json:"email_id,omitempty"`
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Suppression) TableName() string {
+	return "email_suppressions"
+}
@@ -0,0 +1,67 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// Nomes dos provedores, como em email.provider
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+)
+
+// providerTimeout limita cada envio pelas APIs HTTP dos provedores
+const providerTimeout = 30 * time.Second
+
+// Provider entrega as mensagens montadas pelo serviço
+type Provider interface {
+	// Name identifica o provedor nos logs e nas respostas
+	Name() string
+	// Validate verifica se a configuração do provedor está completa
+	Validate() error
+	// Send entrega msg e retorna o ID atribuído pelo provedor, vazio quando ele não atribui um.
+	// emailID identifica o email da fila (0 fora dela) e volta nos eventos de entrega.
+	Send(ctx context.Context, emailID uint, msg *mail.Msg) (string, error)
+}
+
+// DeliveryEvent é uma mudança no status de entrega informada pelo provedor. EmailID vem da tag
+// enviada com a mensagem, quando o provedor a devolve; senão o email é buscado por ProviderMessageID.
+// Suppress são os destinatários de uma devolução permanente ou de uma reclamação, que entram na
+// lista de supressão.
+type DeliveryEvent struct {
+	EmailID           uint
+	ProviderMessageID string
+	Status            string
+	Recipient         string
+	Reason            string
+	OccurredAt        time.Time
+	Suppress          []string
+}
+
+// WebhookReceiver é implementado pelos provedores que informam o status de entrega por webhook
+type WebhookReceiver interface {
+	// ParseWebhook lê os eventos de uma chamada do webhook. Chamadas sem eventos (como a
+	// confirmação de inscrição do SNS) retornam uma lista vazia.
+	ParseWebhook(ctx context.Context, body []byte) ([]DeliveryEvent, error)
+}
+
+// NewProvider cria o provedor configurado em email.provider
+func NewProvider(cfg *config.EmailConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", ProviderSMTP:
+		return newSMTPProvider(cfg), nil
+	case ProviderSES:
+		return newSESProvider(cfg), nil
+	case ProviderSendGrid:
+		return newSendGridProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,257 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	netmail "net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+)
+
+const (
+	defaultSendGridEndpoint = "https://api.sendgrid.com"
+	// sendGridEmailIDArg é o custom arg com o ID do email da fila, devolvido em cada evento
+	sendGridEmailIDArg = "email_id"
+)
+
+// sendGridProvider envia pela API v3 do SendGrid. O status de entrega chega pelo Event Webhook.
+type sendGridProvider struct {
+	cfg      *config.EmailConfig
+	endpoint string
+	http     *httpclient.Client
+}
+
+func newSendGridProvider(cfg *config.EmailConfig) *sendGridProvider {
+	endpoint := strings.TrimRight(cfg.SendGridEndpoint, "/")
+	if endpoint == "" {
+		endpoint = defaultSendGridEndpoint
+	}
+	return &sendGridProvider{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     httpclient.New("SendGrid", httpclient.WithHeader("Authorization", "Bearer "+cfg.SendGridAPIKey)),
+	}
+}
+
+func (p *sendGridProvider) Name() string {
+	return ProviderSendGrid
+}
+
+// Validate verifica se a chave da API está configurada
+func (p *sendGridProvider) Validate() error {
+	if p.cfg.SendGridAPIKey == "" {
+		return fmt.Errorf("email sendgrid_api_key not configured")
+	}
+	return nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To         []sendGridAddress `json:"to"`
+	Cc         []sendGridAddress `json:"cc,omitempty"`
+	Bcc        []sendGridAddress `json:"bcc,omitempty"`
+	CustomArgs map[string]string `json:"custom_args,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// sendGridAttachment é um anexo; as imagens embutidas usam disposition inline com o Content-ID
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type,omitempty"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+type sendGridMessage struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+}
+
+// Send envia a mensagem e retorna o X-Message-Id do SendGrid
+func (p *sendGridProvider) Send(ctx context.Context, emailID uint, msg *mail.Msg) (string, error) {
+	payload, err := newSendGridMessage(emailID, msg)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	header, err := doProviderRequest(p.http, req, nil)
+	if err != nil {
+		return "", err
+	}
+	return header.Get("X-Message-Id"), nil
+}
+
+// newSendGridMessage converte a mensagem montada no formato da API, mantendo o Message-ID e as
+// imagens embutidas
+func newSendGridMessage(emailID uint, msg *mail.Msg) (*sendGridMessage, error) {
+	from := msg.GetFrom()
+	if len(from) == 0 {
+		return nil, fmt.Errorf("message has no from address")
+	}
+
+	payload := &sendGridMessage{
+		Personalizations: []sendGridPersonalization{{
+			To:  sendGridAddresses(msg.GetTo()),
+			Cc:  sendGridAddresses(msg.GetCc()),
+			Bcc: sendGridAddresses(msg.GetBcc()),
+		}},
+		From: sendGridAddresses(from)[0],
+	}
+	if emailID != 0 {
+		payload.Personalizations[0].CustomArgs = map[string]string{sendGridEmailIDArg: strconv.FormatUint(uint64(emailID), 10)}
+	}
+	if subject := msg.GetGenHeader(mail.HeaderSubject); len(subject) > 0 {
+		payload.Subject = subject[0]
+	}
+	if messageID := msg.GetMessageID(); messageID != "" {
+		payload.Headers = map[string]string{string(mail.HeaderMessageID): messageID}
+	}
+	// Os cabeçalhos de descadastro dos emails de marketing
+	for _, header := range []mail.Header{mail.HeaderListUnsubscribe, mail.HeaderListUnsubscribePost} {
+		if values := msg.GetGenHeader(header); len(values) > 0 {
+			if payload.Headers == nil {
+				payload.Headers = make(map[string]string)
+			}
+			payload.Headers[string(header)] = values[0]
+		}
+	}
+
+	// A API exige text/plain antes de text/html
+	for _, contentType := range []mail.ContentType{mail.TypeTextPlain, mail.TypeTextHTML} {
+		for _, part := range msg.GetParts() {
+			if part.GetContentType() != contentType {
+				continue
+			}
+			content, err := part.GetContent()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read message body: %w", err)
+			}
+			payload.Content = append(payload.Content, sendGridContent{Type: string(contentType), Value: string(content)})
+		}
+	}
+	if len(payload.Content) == 0 {
+		return nil, fmt.Errorf("message has no text or HTML body")
+	}
+
+	for _, file := range msg.GetEmbeds() {
+		var content bytes.Buffer
+		if _, err := file.Writer(&content); err != nil {
+			return nil, fmt.Errorf("failed to read inline file %s: %w", file.Name, err)
+		}
+		payload.Attachments = append(payload.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(content.Bytes()),
+			Type:        string(file.ContentType),
+			Filename:    file.Name,
+			Disposition: "inline",
+			ContentID:   strings.Trim(file.Header.Get(string(mail.HeaderContentID)), "<>"),
+		})
+	}
+	return payload, nil
+}
+
+func sendGridAddresses(addresses []*netmail.Address) []sendGridAddress {
+	if len(addresses) == 0 {
+		return nil
+	}
+	result := make([]sendGridAddress, len(addresses))
+	for i, a := range addresses {
+		result[i] = sendGridAddress{Email: a.Address, Name: a.Name}
+	}
+	return result
+}
+
+// sendGridEvent é um evento do Event Webhook. Os custom args enviados com a mensagem vêm no
+// nível de cima do evento.
+type sendGridEvent struct {
+	Email       string `json:"email"`
+	Timestamp   int64  `json:"timestamp"`
+	Event       string `json:"event"`
+	Type        string `json:"type"` // bounce ou blocked, nos eventos bounce
+	SGMessageID string `json:"sg_message_id"`
+	Reason      string `json:"reason"`
+	Response    string `json:"response"`
+	EmailID     string `json:"email_id"`
+}
+
+// sendGridStatuses mapeia os eventos do SendGrid com status de entrega; os demais (processed,
+// open, click, unsubscribe) são ignorados
+var sendGridStatuses = map[string]string{
+	"delivered":  LogDelivered,
+	"bounce":     LogBounced,
+	"dropped":    LogDropped,
+	"deferred":   LogDeferred,
+	"spamreport": LogComplained,
+}
+
+// ParseWebhook lê a lista de eventos enviada pelo Event Webhook
+func (p *sendGridProvider) ParseWebhook(ctx context.Context, body []byte) ([]DeliveryEvent, error) {
+	var payload []sendGridEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid SendGrid events: %w", err)
+	}
+
+	events := make([]DeliveryEvent, 0, len(payload))
+	for _, e := range payload {
+		status, ok := sendGridStatuses[e.Event]
+		if !ok {
+			continue
+		}
+		event := DeliveryEvent{
+			// sg_message_id é o X-Message-Id retornado no envio seguido de um sufixo por destinatário
+			ProviderMessageID: strings.SplitN(e.SGMessageID, ".", 2)[0],
+			Status:            status,
+			Recipient:         e.Email,
+			Reason:            e.Reason,
+		}
+		if event.Reason == "" {
+			event.Reason = e.Response
+		}
+		// Um bounce do tipo blocked é uma recusa temporária do servidor de destino
+		if (e.Event == "bounce" && e.Type != "blocked") || e.Event == "spamreport" {
+			event.Suppress = []string{e.Email}
+		}
+		if e.Timestamp > 0 {
+			event.OccurredAt = time.Unix(e.Timestamp, 0)
+		}
+		if id, err := strconv.ParseUint(e.EmailID, 10, 32); err == nil {
+			event.EmailID = uint(id)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,331 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/sigv4"
+)
+
+// sesEmailIDTag é a tag com o ID do email da fila, devolvida nos eventos da configuration set
+const sesEmailIDTag = "email_id"
+
+// sesProvider envia pela API v2 do Amazon SES com a mensagem MIME completa. O status de entrega
+// chega pelo SNS: notificações da identidade ou eventos da configuration set.
+type sesProvider struct {
+	cfg      *config.EmailConfig
+	endpoint string
+	http     *httpclient.Client
+	now      func() time.Time
+}
+
+func newSESProvider(cfg *config.EmailConfig) *sesProvider {
+	endpoint := strings.TrimRight(cfg.SESEndpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", cfg.SESRegion)
+	}
+	return &sesProvider{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     httpclient.New("Amazon SES"),
+		now:      time.Now,
+	}
+}
+
+func (p *sesProvider) Name() string {
+	return ProviderSES
+}
+
+// Validate verifica se a região e as credenciais do SES estão configuradas
+func (p *sesProvider) Validate() error {
+	if p.cfg.SESRegion == "" {
+		return fmt.Errorf("email ses_region not configured")
+	}
+	if p.cfg.SESAccessKey == "" || p.cfg.SESSecretKey == "" {
+		return fmt.Errorf("email ses_access_key and ses_secret_key not configured")
+	}
+	return nil
+}
+
+type sesDestination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+type sesTag struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type sesSendRequest struct {
+	Destination sesDestination `json:"Destination"`
+	Content     struct {
+		Raw struct {
+			Data string `json:"Data"`
+		} `json:"Raw"`
+	} `json:"Content"`
+	ConfigurationSetName string   `json:"ConfigurationSetName,omitempty"`
+	EmailTags            []sesTag `json:"EmailTags,omitempty"`
+}
+
+// Send envia a mensagem e retorna o MessageId do SES
+func (p *sesProvider) Send(ctx context.Context, emailID uint, msg *mail.Msg) (string, error) {
+	var raw bytes.Buffer
+	if _, err := msg.WriteTo(&raw); err != nil {
+		return "", fmt.Errorf("failed to write message: %w", err)
+	}
+
+	// O Bcc não vai no MIME, então os destinatários são informados à parte
+	var payload sesSendRequest
+	payload.Destination = sesDestination{
+		ToAddresses:  addressStrings(msg.GetTo()),
+		CcAddresses:  addressStrings(msg.GetCc()),
+		BccAddresses: addressStrings(msg.GetBcc()),
+	}
+	payload.Content.Raw.Data = base64.StdEncoding.EncodeToString(raw.Bytes())
+	payload.ConfigurationSetName = p.cfg.SESConfigurationSet
+	if emailID != 0 {
+		payload.EmailTags = []sesTag{{Name: sesEmailIDTag, Value: strconv.FormatUint(uint64(emailID), 10)}}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	sigv4.Sign(req, sigv4.PayloadHash(body), "ses", p.cfg.SESRegion,
+		sigv4.Credentials{AccessKey: p.cfg.SESAccessKey, SecretKey: p.cfg.SESSecretKey}, p.now())
+
+	var result struct {
+		MessageId string `json:"MessageId"`
+	}
+	if _, err := doProviderRequest(p.http, req, &result); err != nil {
+		return "", err
+	}
+	return result.MessageId, nil
+}
+
+// snsMessage é o envelope das chamadas do SNS
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+	Timestamp    string `json:"Timestamp"`
+}
+
+// sesNotification cobre as notificações da identidade (notificationType) e os eventos da
+// configuration set (eventType)
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Mail             struct {
+		MessageID string              `json:"messageId"`
+		Tags      map[string][]string `json:"tags"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType        string `json:"bounceType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplainedRecipients []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+		ComplaintFeedbackType string    `json:"complaintFeedbackType"`
+		Timestamp             time.Time `json:"timestamp"`
+	} `json:"complaint"`
+	Delivery *struct {
+		Recipients   []string  `json:"recipients"`
+		SMTPResponse string    `json:"smtpResponse"`
+		Timestamp    time.Time `json:"timestamp"`
+	} `json:"delivery"`
+	Reject *struct {
+		Reason string `json:"reason"`
+	} `json:"reject"`
+	DeliveryDelay *struct {
+		DelayType         string `json:"delayType"`
+		DelayedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"delayedRecipients"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"deliveryDelay"`
+}
+
+// ParseWebhook lê uma chamada do SNS. A confirmação de inscrição é aceita aqui, acessando a
+// SubscribeURL; tipos de notificação sem status de entrega (Send, Open, Click) são ignorados.
+func (p *sesProvider) ParseWebhook(ctx context.Context, body []byte) ([]DeliveryEvent, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid SNS message: %w", err)
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, p.confirmSubscription(ctx, envelope.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var n sesNotification
+	if err := json.Unmarshal([]byte(envelope.Message), &n); err != nil {
+		return nil, fmt.Errorf("invalid SES notification: %w", err)
+	}
+
+	event := DeliveryEvent{ProviderMessageID: n.Mail.MessageID}
+	if ids := n.Mail.Tags[sesEmailIDTag]; len(ids) > 0 {
+		if id, err := strconv.ParseUint(ids[0], 10, 32); err == nil {
+			event.EmailID = uint(id)
+		}
+	}
+	event.OccurredAt, _ = time.Parse(time.RFC3339, envelope.Timestamp)
+
+	kind := n.EventType
+	if kind == "" {
+		kind = n.NotificationType
+	}
+	switch {
+	case kind == "Delivery" && n.Delivery != nil:
+		event.Status = LogDelivered
+		event.Recipient = strings.Join(n.Delivery.Recipients, ", ")
+		event.OccurredAt = eventTime(event.OccurredAt, n.Delivery.Timestamp)
+	case kind == "Bounce" && n.Bounce != nil:
+		event.Status = LogBounced
+		recipients := make([]string, len(n.Bounce.BouncedRecipients))
+		for i, r := range n.Bounce.BouncedRecipients {
+			recipients[i] = r.EmailAddress
+			if event.Reason == "" {
+				event.Reason = r.DiagnosticCode
+			}
+		}
+		event.Recipient = strings.Join(recipients, ", ")
+		if event.Reason == "" {
+			event.Reason = n.Bounce.BounceType + " bounce"
+		}
+		// Devoluções temporárias (Transient) e indeterminadas podem ser entregues depois
+		if n.Bounce.BounceType == "Permanent" {
+			event.Suppress = recipients
+		}
+		event.OccurredAt = eventTime(event.OccurredAt, n.Bounce.Timestamp)
+	case kind == "Complaint" && n.Complaint != nil:
+		event.Status = LogComplained
+		recipients := make([]string, len(n.Complaint.ComplainedRecipients))
+		for i, r := range n.Complaint.ComplainedRecipients {
+			recipients[i] = r.EmailAddress
+		}
+		event.Recipient = strings.Join(recipients, ", ")
+		event.Suppress = recipients
+		event.Reason = n.Complaint.ComplaintFeedbackType
+		event.OccurredAt = eventTime(event.OccurredAt, n.Complaint.Timestamp)
+	case kind == "Reject" && n.Reject != nil:
+		event.Status = LogDropped
+		event.Reason = n.Reject.Reason
+	case kind == "DeliveryDelay" && n.DeliveryDelay != nil:
+		event.Status = LogDeferred
+		recipients := make([]string, len(n.DeliveryDelay.DelayedRecipients))
+		for i, r := range n.DeliveryDelay.DelayedRecipients {
+			recipients[i] = r.EmailAddress
+		}
+		event.Recipient = strings.Join(recipients, ", ")
+		event.Reason = n.DeliveryDelay.DelayType
+		event.OccurredAt = eventTime(event.OccurredAt, n.DeliveryDelay.Timestamp)
+	default:
+		return nil, nil
+	}
+	return []DeliveryEvent{event}, nil
+}
+
+// eventTime prefere o horário do evento ao do envelope do SNS, quando informado
+func eventTime(envelope, event time.Time) time.Time {
+	if event.IsZero() {
+		return envelope
+	}
+	return event
+}
+
+// confirmSubscription confirma a inscrição do webhook no tópico SNS. Só URLs HTTPS da AWS são
+// acessadas.
+func (p *sesProvider) confirmSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("invalid SNS SubscribeURL %q", subscribeURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+	if _, err := p.http.Get(ctx, u.String(), nil); err != nil {
+		return fmt.Errorf("failed to confirm SNS subscription: %w", err)
+	}
+	slog.Info("Confirmed SNS subscription of the email delivery webhook", "url", u.Host)
+	return nil
+}
+
+// doProviderRequest envia req, decodifica a resposta 2xx em result (opcional) e retorna os
+// cabeçalhos dela. Outros status retornam o começo do corpo, que traz a mensagem de erro do provedor.
+func doProviderRequest(client *httpclient.Client, req *http.Request, result interface{}) (http.Header, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail := strings.TrimSpace(string(body))
+		if len(detail) > 512 {
+			detail = detail[:512]
+		}
+		return nil, fmt.Errorf("provider returned status %d: %s", resp.StatusCode, detail)
+	}
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, fmt.Errorf("failed to parse provider response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// addressStrings formata os endereços de um cabeçalho
+func addressStrings(addresses []*netmail.Address) []string {
+	if len(addresses) == 0 {
+		return nil
+	}
+	result := make([]string, len(addresses))
+	for i, a := range addresses {
+		result[i] = a.String()
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// smtpProvider envia pelo servidor SMTP configurado. O ID da mensagem é o Message-ID atribuído
+// ao enfileirar, então Send não retorna outro.
+type smtpProvider struct {
+	cfg *config.EmailConfig
+}
+
+func newSMTPProvider(cfg *config.EmailConfig) *smtpProvider {
+	return &smtpProvider{cfg: cfg}
+}
+
+func (p *smtpProvider) Name() string {
+	return ProviderSMTP
+}
+
+// Validate verifica se o servidor e as credenciais SMTP estão configurados
+func (p *smtpProvider) Validate() error {
+	if p.cfg.Host == "" {
+		return fmt.Errorf("email host not configured")
+	}
+	if p.cfg.Port == 0 {
+		return fmt.Errorf("email port not configured")
+	}
+	if p.cfg.Username == "" {
+		return fmt.Errorf("email username not configured")
+	}
+	if p.cfg.Password == "" {
+		return fmt.Errorf("email password not configured")
+	}
+	return nil
+}
+
+// Send envia a mensagem em uma nova conexão SMTP
+func (p *smtpProvider) Send(ctx context.Context, emailID uint, msg *mail.Msg) (string, error) {
+	client, err := p.newClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			slog.Error("Failed to close SMTP client", "error", err)
+		}
+	}()
+
+	return "", client.DialAndSendWithContext(ctx, msg)
+}
+
+// newClient cria e configura o cliente SMTP
+func (p *smtpProvider) newClient() (*mail.Client, error) {
+	return NewSMTPClient(p.cfg, 30*time.Second)
+}
+
+// NewSMTPClient cria o cliente do servidor SMTP de cfg, com TLS e autenticação, cujas operações
+// expiram após timeout. Também é usado pela verificação de saúde do SMTP.
+func NewSMTPClient(cfg *config.EmailConfig, timeout time.Duration) (*mail.Client, error) {
+	options := []mail.Option{
+		mail.WithPort(cfg.Port),
+		mail.WithSMTPAuth(mail.SMTPAuthPlain),
+		mail.WithUsername(cfg.Username),
+		mail.WithPassword(cfg.Password),
+		mail.WithTimeout(timeout),
+	}
+
+	// Configura TLS se habilitado
+	if cfg.UseTLS {
+		tlsConfig := &tls.Config{
+			ServerName:         cfg.Host,
+			InsecureSkipVerify: false,
+		}
+		options = append(options, mail.WithTLSConfig(tlsConfig))
+
+		// Se StartTLS estiver habilitado, usa essa opção
+		if cfg.UseStartTLS {
+			options = append(options, mail.WithTLSPolicy(mail.TLSMandatory))
+		} else {
+			options = append(options, mail.WithSSL())
+		}
+	}
+
+	client, err := mail.NewClient(cfg.Host, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -0,0 +1,275 @@
+package email
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func testMessage(t *testing.T) *mail.Msg {
+	t.Helper()
+	msg := mail.NewMsg()
+	require.NoError(t, msg.From("Triiio <noreply@example.com>"))
+	require.NoError(t, msg.To("cliente@example.com"))
+	require.NoError(t, msg.Bcc("copia@example.com"))
+	msg.Subject("Proposta")
+	msg.SetBodyString(mail.TypeTextHTML, "<p>Olá</p>")
+	msg.SetMessageIDWithValue("42.abc@example.com")
+	return msg
+}
+
+func TestNewProvider(t *testing.T) {
+	for name, want := range map[string]string{"": ProviderSMTP, "smtp": ProviderSMTP, "ses": ProviderSES, "sendgrid": ProviderSendGrid} {
+		provider, err := NewProvider(&config.EmailConfig{Provider: name})
+		require.NoError(t, err)
+		assert.Equal(t, want, provider.Name())
+	}
+
+	_, err := NewProvider(&config.EmailConfig{Provider: "mailgun"})
+	assert.Error(t, err)
+
+	assert.Error(t, newSESProvider(&config.EmailConfig{SESRegion: "sa-east-1"}).Validate())
+	assert.Error(t, newSendGridProvider(&config.EmailConfig{}).Validate())
+}
+
+func TestSESProvider_Send(t *testing.T) {
+	var gotAuth string
+	var got sesSendRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/email/outbound-emails", r.URL.Path)
+		gotAuth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"MessageId":"0100018f-ses-id"}`))
+	}))
+	defer server.Close()
+
+	provider := newSESProvider(&config.EmailConfig{
+		SESRegion:           "sa-east-1",
+		SESAccessKey:        "AKID",
+		SESSecretKey:        "secret",
+		SESEndpoint:         server.URL,
+		SESConfigurationSet: "entregas",
+	})
+	provider.now = func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) }
+
+	id, err := provider.Send(context.Background(), 42, testMessage(t))
+	require.NoError(t, err)
+	assert.Equal(t, "0100018f-ses-id", id)
+
+	assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/20261017/sa-east-1/ses/aws4_request"))
+	assert.Equal(t, []string{"<copia@example.com>"}, got.Destination.BccAddresses, "bcc is not in the MIME message")
+	assert.Equal(t, "entregas", got.ConfigurationSetName)
+	assert.Equal(t, []sesTag{{Name: "email_id", Value: "42"}}, got.EmailTags)
+	raw, err := base64.StdEncoding.DecodeString(got.Content.Raw.Data)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "Subject: Proposta")
+	assert.Contains(t, string(raw), "Message-ID: <42.abc@example.com>")
+}
+
+func TestSendGridProvider_Send(t *testing.T) {
+	var got sendGridMessage
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/mail/send", r.URL.Path)
+		assert.Equal(t, "Bearer SG.key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("X-Message-Id", "sg-message-id")
+		w.WriteHeader(status)
+		if status != http.StatusAccepted {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"The from address does not match a verified Sender Identity"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := newSendGridProvider(&config.EmailConfig{SendGridAPIKey: "SG.key", SendGridEndpoint: server.URL})
+	id, err := provider.Send(context.Background(), 42, testMessage(t))
+	require.NoError(t, err)
+	assert.Equal(t, "sg-message-id", id)
+
+	require.Len(t, got.Personalizations, 1)
+	assert.Equal(t, []sendGridAddress{{Email: "cliente@example.com"}}, got.Personalizations[0].To)
+	assert.Equal(t, []sendGridAddress{{Email: "copia@example.com"}}, got.Personalizations[0].Bcc)
+	assert.Equal(t, map[string]string{"email_id": "42"}, got.Personalizations[0].CustomArgs)
+	assert.Equal(t, sendGridAddress{Email: "noreply@example.com", Name: "Triiio"}, got.From)
+	assert.Equal(t, "Proposta", got.Subject)
+	assert.Equal(t, []sendGridContent{{Type: "text/html", Value: "<p>Olá</p>"}}, got.Content)
+	assert.Equal(t, "<42.abc@example.com>", got.Headers["Message-ID"])
+
+	status = http.StatusForbidden
+	_, err = provider.Send(context.Background(), 42, testMessage(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "verified Sender Identity")
+}
+
+func snsNotification(t *testing.T, message string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{
+		"Type":      "Notification",
+		"Message":   message,
+		"Timestamp": "2026-10-17T12:00:05Z",
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func TestSESProvider_ParseWebhook(t *testing.T) {
+	ctx := context.Background()
+	provider := newSESProvider(&config.EmailConfig{SESRegion: "sa-east-1"})
+
+	events, err := provider.ParseWebhook(ctx, snsNotification(t, `{
+		"notificationType": "Bounce",
+		"mail": {"messageId": "0100018f-ses-id"},
+		"bounce": {
+			"bounceType": "Permanent",
+			"bouncedRecipients": [{"emailAddress": "cliente@example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"}],
+			"timestamp": "2026-10-17T12:00:01Z"
+		}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, DeliveryEvent{
+		ProviderMessageID: "0100018f-ses-id",
+		Status:            LogBounced,
+		Recipient:         "cliente@example.com",
+		Reason:            "smtp; 550 5.1.1 user unknown",
+		OccurredAt:        time.Date(2026, 10, 17, 12, 0, 1, 0, time.UTC),
+		Suppress:          []string{"cliente@example.com"},
+	}, events[0])
+
+	// Transient bounces may be delivered later; complaints are suppressed
+	events, err = provider.ParseWebhook(ctx, snsNotification(t, `{
+		"notificationType": "Bounce",
+		"mail": {"messageId": "0100018f-ses-id"},
+		"bounce": {"bounceType": "Transient", "bouncedRecipients": [{"emailAddress": "cliente@example.com"}]}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Transient bounce", events[0].Reason)
+	assert.Empty(t, events[0].Suppress)
+
+	events, err = provider.ParseWebhook(ctx, snsNotification(t, `{
+		"notificationType": "Complaint",
+		"mail": {"messageId": "0100018f-ses-id"},
+		"complaint": {"complainedRecipients": [{"emailAddress": "cliente@example.com"}], "complaintFeedbackType": "abuse"}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, LogComplained, events[0].Status)
+	assert.Equal(t, []string{"cliente@example.com"}, events[0].Suppress)
+
+	// Configuration set events carry the email tag
+	events, err = provider.ParseWebhook(ctx, snsNotification(t, `{
+		"eventType": "Delivery",
+		"mail": {"messageId": "0100018f-ses-id", "tags": {"email_id": ["42"]}},
+		"delivery": {"recipients": ["cliente@example.com"]}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint(42), events[0].EmailID)
+	assert.Equal(t, LogDelivered, events[0].Status)
+	assert.Equal(t, time.Date(2026, 10, 17, 12, 0, 5, 0, time.UTC), events[0].OccurredAt, "falls back to the SNS timestamp")
+
+	events, err = provider.ParseWebhook(ctx, snsNotification(t, `{"eventType": "Open", "mail": {"messageId": "x"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = provider.ParseWebhook(ctx, []byte(`{"Type":"SubscriptionConfirmation","SubscribeURL":"https://attacker.example.com/confirm"}`))
+	assert.Error(t, err, "only AWS URLs are confirmed")
+}
+
+func TestSendGridProvider_ParseWebhook(t *testing.T) {
+	provider := newSendGridProvider(&config.EmailConfig{})
+	events, err := provider.ParseWebhook(context.Background(), []byte(`[
+		{"email":"cliente@example.com","timestamp":1792238400,"event":"processed","sg_message_id":"sg-message-id.filter0001"},
+		{"email":"cliente@example.com","timestamp":1792238401,"event":"delivered","sg_message_id":"sg-message-id.filter0001","email_id":"42"},
+		{"email":"copia@example.com","timestamp":1792238402,"event":"bounce","type":"bounce","sg_message_id":"sg-message-id.filter0002","reason":"550 5.1.1 user unknown"},
+		{"email":"outro@example.com","timestamp":1792238403,"event":"bounce","type":"blocked","sg_message_id":"sg-message-id.filter0003","reason":"421 try again later"},
+		{"email":"cliente@example.com","timestamp":1792238404,"event":"spamreport","sg_message_id":"sg-message-id.filter0001"}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, events, 4, "processed is not a delivery status")
+	assert.Equal(t, DeliveryEvent{EmailID: 42, ProviderMessageID: "sg-message-id", Status: LogDelivered, Recipient: "cliente@example.com", OccurredAt: time.Unix(1792238401, 0)}, events[0])
+	assert.Equal(t, LogBounced, events[1].Status)
+	assert.Equal(t, "sg-message-id", events[1].ProviderMessageID)
+	assert.Equal(t, "550 5.1.1 user unknown", events[1].Reason)
+	assert.Equal(t, []string{"copia@example.com"}, events[1].Suppress)
+	assert.Empty(t, events[2].Suppress, "blocked bounces are temporary")
+	assert.Equal(t, LogComplained, events[3].Status)
+	assert.Equal(t, []string{"cliente@example.com"}, events[3].Suppress)
+
+	_, err = provider.ParseWebhook(context.Background(), []byte(`{"not":"a list"}`))
+	assert.Error(t, err)
+}
+
+func TestService_HandleDeliveryWebhook(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("X-Message-Id", "sg-message-id")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	svc, db := newQueueService(t, nil)
+	svc.cfg.Email.WebhookSecret = "segredo"
+	svc.provider = newSendGridProvider(&config.EmailConfig{SendGridAPIKey: "SG.key", SendGridEndpoint: server.URL})
+
+	resp, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"cliente@example.com"}, Subject: "Proposta", Body: "Olá"})
+	require.NoError(t, err)
+	assert.Equal(t, ProviderSendGrid, resp.Provider)
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "sg-message-id", outboxEmail(t, db, resp.ID).ProviderMessageID)
+
+	// The events happen after the send, so the logs list them before the SENT one
+	now := time.Now().Unix()
+	events := fmt.Sprintf(`[
+		{"email":"cliente@example.com","timestamp":%d,"event":"delivered","sg_message_id":"sg-message-id.filter0001"},
+		{"email":"cliente@example.com","timestamp":%d,"event":"deferred","sg_message_id":"sg-message-id.filter0001","response":"421 try again later"},
+		{"email":"outro@example.com","timestamp":%d,"event":"bounce","sg_message_id":"unknown-id.filter0001"}
+	]`, now+1, now+2, now+3)
+
+	_, err = svc.HandleDeliveryWebhook(ctx, ProviderSendGrid, "errado", []byte(events))
+	assertAPIStatus(t, err, http.StatusUnauthorized)
+	_, err = svc.HandleDeliveryWebhook(ctx, ProviderSES, "segredo", []byte(events))
+	assertAPIStatus(t, err, http.StatusNotFound)
+
+	result, err := svc.HandleDeliveryWebhook(ctx, ProviderSendGrid, "segredo", []byte(events))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Processed, "events of unknown emails are ignored")
+
+	status, err := svc.GetEmail(ctx, resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "sent", status.Status)
+	assert.Equal(t, "delivered", status.DeliveryStatus, "a later deferral does not replace a delivery")
+	assert.Equal(t, "sg-message-id", status.ProviderMessageID)
+
+	logs, err := svc.ListEmailLogs(ctx, &EmailLogListQuery{Page: 1, Limit: 20, MessageID: "sg-message-id"})
+	require.NoError(t, err)
+	require.Len(t, logs.Results, 3)
+	assert.Equal(t, LogDeferred, logs.Results[0].Status)
+	assert.Equal(t, "cliente@example.com: 421 try again later", logs.Results[0].Error)
+	assert.Equal(t, LogDelivered, logs.Results[1].Status)
+	assert.Equal(t, LogSent, logs.Results[2].Status)
+
+	svc.cfg.Email.WebhookSecret = ""
+	_, err = svc.HandleDeliveryWebhook(ctx, ProviderSendGrid, "", []byte(events))
+	assertAPIStatus(t, err, http.StatusNotFound)
+
+	_, err = svc.GetEmail(ctx, 999)
+	assertAPIStatus(t, err, http.StatusNotFound)
+}
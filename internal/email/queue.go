@@ -0,0 +1,262 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+const (
+	// defaultMaxAttempts se aplica quando email.max_attempts não está configurado
+	defaultMaxAttempts = 8
+	// defaultRetryBase se aplica quando email.retry_base_seconds não está configurado
+	defaultRetryBase = 30 * time.Second
+	// maxRetryDelay limita o intervalo entre tentativas, que dobra a cada falha
+	maxRetryDelay = 6 * time.Hour
+	// defaultQueueInterval se aplica quando email.queue_interval_seconds não está configurado
+	defaultQueueInterval = 5 * time.Second
+	// deliveryBatchSize limita os emails reservados de cada vez
+	deliveryBatchSize = 50
+	// deliveryLease é o tempo de reserva de um email em envio; se a instância cair no meio do
+	// envio, ele volta para a fila depois desse tempo
+	deliveryLease = 5 * time.Minute
+)
+
+// DeliverQueued envia os emails da fila cuja tentativa já venceu e retorna quantos foram
+// processados. Falhas são reagendadas com backoff exponencial até max_attempts; depois disso o
+// email vai para a lista de não enviados.
+func (s *service) DeliverQueued(ctx context.Context) (int, error) {
+	emails, err := s.repo.ClaimDue(ctx, time.Now(), deliveryLease, deliveryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim queued emails: %w", err)
+	}
+
+	for i := range emails {
+		if ctx.Err() != nil {
+			// Os emails restantes voltam para a fila quando o lease expira
+			break
+		}
+		s.deliver(ctx, &emails[i])
+	}
+	return len(emails), nil
+}
+
+// deliver envia um email reservado e registra o resultado
+func (s *service) deliver(ctx context.Context, queued *OutboxEmail) {
+	// Um envio iniciado termina e tem o resultado gravado mesmo durante o desligamento; o SMTP tem
+	// timeout próprio
+	ctx = context.WithoutCancel(ctx)
+
+	// O destinatário de um email de marketing pode ter se descadastrado depois do enfileiramento
+	if queued.Marketing {
+		suppressed, err := s.suppressed(ctx, queued.To)
+		if err != nil {
+			// Fica reservado e volta para a fila quando o lease expira
+			slog.Error("Failed to check suppressed recipients", "email_id", queued.ID, "error", err)
+			return
+		}
+		if len(suppressed) > 0 {
+			s.cancelSuppressed(ctx, queued, suppressed)
+			return
+		}
+	}
+
+	msg, err := s.buildMessage(queued)
+	if err != nil {
+		slog.Error("Discarding email that cannot be built", "email_id", queued.ID, "error", err)
+		if err := s.repo.MarkDead(ctx, queued.ID, err.Error()); err != nil {
+			slog.Error("Failed to update queued email", "email_id", queued.ID, "error", err)
+		}
+		s.logAttempt(ctx, queued, LogFailed, err)
+		return
+	}
+
+	// Uma imagem que não pode ser lida agora é tratada como falha de envio e tentada de novo
+	var providerMessageID string
+	sendErr := s.embedInlineImages(ctx, msg, queued.InlineImages)
+	if sendErr == nil {
+		providerMessageID, sendErr = s.provider.Send(ctx, queued.ID, msg)
+	}
+	queued.Provider = s.provider.Name()
+	status := LogSent
+	switch {
+	case sendErr == nil:
+		queued.ProviderMessageID = providerMessageID
+		err = s.repo.MarkSent(ctx, queued.ID, time.Now(), queued.Provider, providerMessageID)
+	case queued.Attempts >= s.maxAttempts:
+		status = LogFailed
+		slog.Error("Email delivery failed, giving up", "email_id", queued.ID, "attempts", queued.Attempts, "error", sendErr)
+		err = s.repo.MarkDead(ctx, queued.ID, sendErr.Error())
+	default:
+		status = LogRetrying
+		delay := retryDelay(s.retryBase, queued.Attempts)
+		slog.Warn("Email delivery failed, retrying", "email_id", queued.ID, "attempts", queued.Attempts, "retry_in", delay, "error", sendErr)
+		err = s.repo.Reschedule(ctx, queued.ID, sendErr.Error(), time.Now().Add(delay))
+	}
+	if err != nil {
+		slog.Error("Failed to update queued email", "email_id", queued.ID, "error", err)
+	}
+	s.logAttempt(ctx, queued, status, sendErr)
+}
+
+// cancelSuppressed cancela um email de marketing cujo destinatário está na lista de supressão
+func (s *service) cancelSuppressed(ctx context.Context, queued *OutboxEmail, suppressed map[string]string) {
+	reasons := make([]string, 0, len(suppressed))
+	for address, reason := range suppressed {
+		reasons = append(reasons, fmt.Sprintf("%s is suppressed (%s)", address, reason))
+	}
+	sort.Strings(reasons)
+	reason := strings.Join(reasons, "; ")
+
+	slog.Info("Skipping marketing email to suppressed recipient", "email_id", queued.ID, "reason", reason)
+	if err := s.repo.MarkCancelled(ctx, queued.ID, reason); err != nil {
+		slog.Error("Failed to update queued email", "email_id", queued.ID, "error", err)
+	}
+	s.logAttempt(ctx, queued, LogSuppressed, fmt.Errorf("%s", reason))
+}
+
+// logAttempt grava a tentativa no log de envio. Uma falha aqui não desfaz o envio.
+func (s *service) logAttempt(ctx context.Context, queued *OutboxEmail, status string, sendErr error) {
+	entry := &EmailLog{
+		EmailID:           queued.ID,
+		To:                queued.To,
+		Cc:                queued.Cc,
+		Bcc:               queued.Bcc,
+		Subject:           queued.Subject,
+		TemplateName:      queued.TemplateName,
+		Status:            status,
+		MessageID:         queued.MessageID,
+		Provider:          queued.Provider,
+		ProviderMessageID: queued.ProviderMessageID,
+		Attempt:           queued.Attempts,
+	}
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+	if err := s.repo.AddLog(ctx, entry); err != nil {
+		slog.Error("Failed to write email log", "email_id", queued.ID, "error", err)
+	}
+}
+
+// retryDelay retorna a espera antes da próxima tentativa: base depois da primeira falha, dobrando
+// a cada nova falha até maxRetryDelay
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// ListDeadLetters lista os emails que esgotaram as tentativas de envio
+func (s *service) ListDeadLetters(ctx context.Context, query *DeadLetterListQuery) (*DeadLetterListResponse, error) {
+	emails, total, err := s.repo.ListDead(ctx, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	results := make([]DeadLetterResponse, len(emails))
+	for i, queued := range emails {
+		results[i] = DeadLetterResponse{
+			ID:        queued.ID,
+			To:        queued.To,
+			Cc:        queued.Cc,
+			Bcc:       queued.Bcc,
+			Subject:   queued.Subject,
+			Attempts:  queued.Attempts,
+			LastError: queued.LastError,
+			CreatedAt: queued.CreatedAt,
+			FailedAt:  queued.UpdatedAt,
+		}
+	}
+	return pagination.New(results, total, query.Page, query.Limit), nil
+}
+
+// ListEmailLogs lista as tentativas de envio que atendem aos filtros
+func (s *service) ListEmailLogs(ctx context.Context, query *EmailLogListQuery) (*EmailLogListResponse, error) {
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		return nil, errors.BadRequest("'to' must not be before 'from'")
+	}
+
+	logs, total, err := s.repo.ListLogs(ctx, query)
+	if err != nil {
+		return nil, errors.InternalServerError(fmt.Errorf("failed to list email logs: %w", err))
+	}
+	return pagination.New(logs, total, query.Page, query.Limit), nil
+}
+
+// Dispatcher envia a fila de emails periodicamente em segundo plano
+type Dispatcher interface {
+	// Close para de reservar emails e espera o envio em andamento até ctx terminar
+	Close(ctx context.Context) error
+}
+
+type dispatcher struct {
+	service  Service
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewDispatcher inicia o envio da fila a cada interval
+func NewDispatcher(service Service, interval time.Duration) Dispatcher {
+	if interval <= 0 {
+		interval = defaultQueueInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &dispatcher{
+		service:  service,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go d.loop(ctx)
+	return d
+}
+
+func (d *dispatcher) loop(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Esvazia a fila antes de esperar o próximo intervalo
+		for ctx.Err() == nil {
+			processed, err := d.service.DeliverQueued(ctx)
+			if err != nil {
+				slog.Error("Email queue delivery failed", "error", err)
+				break
+			}
+			if processed < deliveryBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// Close para o loop e espera o email em envio até ctx terminar
+func (d *dispatcher) Close(ctx context.Context) error {
+	d.once.Do(d.cancel)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,305 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	_, err = sqlDB.Exec(`
+		CREATE TABLE email_outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			recipients TEXT NOT NULL,
+			cc TEXT,
+			bcc TEXT,
+			subject TEXT NOT NULL,
+			body TEXT NOT NULL,
+			is_html BOOLEAN NOT NULL DEFAULT false,
+			inline_images TEXT,
+			template_name TEXT,
+			marketing BOOLEAN NOT NULL DEFAULT false,
+			message_id TEXT,
+			status TEXT NOT NULL,
+			provider TEXT,
+			provider_message_id TEXT,
+			delivery_status TEXT,
+			delivery_updated_at DATETIME,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL,
+			last_error TEXT,
+			sent_at DATETIME,
+			campaign_id INTEGER,
+			organizacao_id INTEGER,
+			created_at DATETIME,
+			updated_at DATETIME
+		);
+		CREATE TABLE email_logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email_id INTEGER NOT NULL,
+			recipients TEXT NOT NULL,
+			cc TEXT,
+			bcc TEXT,
+			subject TEXT NOT NULL,
+			template_name TEXT,
+			status TEXT NOT NULL,
+			error TEXT,
+			message_id TEXT,
+			provider TEXT,
+			provider_message_id TEXT,
+			attempt INTEGER NOT NULL,
+			created_at DATETIME
+		);
+		CREATE TABLE email_templates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			description TEXT,
+			html TEXT NOT NULL,
+			variables TEXT,
+			version INTEGER NOT NULL,
+			created_at DATETIME,
+			updated_at DATETIME
+		);
+		CREATE TABLE email_template_versions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			template_id INTEGER NOT NULL,
+			version INTEGER NOT NULL,
+			html TEXT NOT NULL,
+			variables TEXT,
+			created_at DATETIME
+		);
+		CREATE TABLE email_campaigns (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			template_name TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			template_data TEXT,
+			inline_images TEXT,
+			segment TEXT NOT NULL,
+			rate_per_minute INTEGER NOT NULL,
+			total INTEGER NOT NULL,
+			suppressed INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL,
+			created_by INTEGER,
+			organizacao_id INTEGER,
+			cancelled_at DATETIME,
+			created_at DATETIME,
+			updated_at DATETIME
+		);
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			email TEXT,
+			locale TEXT,
+			deleted_at DATETIME
+		);
+		CREATE TABLE email_suppressions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL UNIQUE,
+			reason TEXT NOT NULL,
+			note TEXT,
+			email_id INTEGER,
+			created_by INTEGER,
+			created_at DATETIME,
+			updated_at DATETIME
+		);
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+// newQueueService returns a service on a test database whose sends go through send
+func newQueueService(t *testing.T, send func(ctx context.Context, msg *mail.Msg) error) (*service, *gorm.DB) {
+	t.Helper()
+	db := setupTestDB(t)
+	cfg := &config.Config{App: config.AppConfig{Name: "Triiio"}, Email: config.EmailConfig{
+		Host:              "smtp.example.com",
+		Port:              587,
+		Username:          "user",
+		Password:          "secret",
+		From:              "noreply@example.com",
+		MaxAttempts:       3,
+		UnsubscribeURL:    "https://api.example.com/api/v1/emails/unsubscribe",
+		UnsubscribeSecret: "unsubscribe-secret",
+	}}
+	svc, err := NewService(cfg, NewRepository(db))
+	require.NoError(t, err)
+
+	s := svc.(*service)
+	s.provider = &testProvider{send: send}
+	return s, db
+}
+
+// testProvider entrega as mensagens para send no lugar de um provedor real
+type testProvider struct {
+	send func(ctx context.Context, msg *mail.Msg) error
+}
+
+func (p *testProvider) Name() string    { return "test" }
+func (p *testProvider) Validate() error { return nil }
+
+func (p *testProvider) Send(ctx context.Context, emailID uint, msg *mail.Msg) (string, error) {
+	return "", p.send(ctx, msg)
+}
+
+func outboxEmail(t *testing.T, db *gorm.DB, id uint) *OutboxEmail {
+	t.Helper()
+	var queued OutboxEmail
+	require.NoError(t, db.First(&queued, id).Error)
+	return &queued
+}
+
+// makeDue moves the next attempt of a queued email to now, skipping the backoff
+func makeDue(t *testing.T, db *gorm.DB, id uint) {
+	t.Helper()
+	require.NoError(t, db.Model(&OutboxEmail{}).Where("id = ?", id).Update("next_attempt_at", time.Now()).Error)
+}
+
+func TestService_SendEmail_Queues(t *testing.T) {
+	ctx := context.Background()
+	var sent []*mail.Msg
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	resp, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"cliente@example.com"}, Subject: "Proposta", Body: "Olá"})
+	require.NoError(t, err)
+	assert.NotZero(t, resp.ID)
+	assert.Empty(t, sent, "sending is left to the dispatcher")
+	assert.Equal(t, OutboxPending, outboxEmail(t, db, resp.ID).Status)
+
+	processed, err := svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"Proposta"}, sent[0].GetGenHeader(mail.HeaderSubject))
+
+	queued := outboxEmail(t, db, resp.ID)
+	assert.Equal(t, OutboxSent, queued.Status)
+	assert.Equal(t, 1, queued.Attempts)
+	assert.NotNil(t, queued.SentAt)
+
+	// Sent emails are not picked up again
+	processed, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+
+	t.Run("invalid addresses are refused before queueing", func(t *testing.T) {
+		_, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"not an address"}, Subject: "x", Body: "x"})
+		assert.Error(t, err)
+
+		var count int64
+		require.NoError(t, db.Model(&OutboxEmail{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestService_DeliverQueued_RetriesThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		attempts++
+		return errors.New("421 service not available")
+	})
+
+	resp, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"cliente@example.com"}, Subject: "Proposta", Body: "Olá"})
+	require.NoError(t, err)
+
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	queued := outboxEmail(t, db, resp.ID)
+	assert.Equal(t, OutboxPending, queued.Status)
+	assert.Equal(t, "421 service not available", queued.LastError)
+	assert.WithinDuration(t, time.Now().Add(defaultRetryBase), queued.NextAttemptAt, 5*time.Second)
+
+	// Not due before the backoff has passed
+	processed, err := svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+
+	for i := 0; i < 2; i++ {
+		makeDue(t, db, resp.ID)
+		_, err = svc.DeliverQueued(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, attempts)
+	queued = outboxEmail(t, db, resp.ID)
+	assert.Equal(t, OutboxDead, queued.Status)
+	assert.Equal(t, 3, queued.Attempts)
+
+	dead, err := svc.ListDeadLetters(ctx, &DeadLetterListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), dead.Total)
+	require.Len(t, dead.Results, 1)
+	assert.Equal(t, resp.ID, dead.Results[0].ID)
+	assert.Equal(t, []string{"cliente@example.com"}, dead.Results[0].To)
+	assert.Equal(t, "421 service not available", dead.Results[0].LastError)
+
+	makeDue(t, db, resp.ID)
+	processed, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed, "dead letters are not retried")
+}
+
+func TestRepository_ClaimDue_Lease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupTestDB(t))
+	now := time.Now()
+
+	queued := &OutboxEmail{To: []string{"a@example.com"}, Subject: "x", Body: "x", Status: OutboxPending, NextAttemptAt: now}
+	require.NoError(t, repo.Enqueue(ctx, queued))
+
+	claimed, err := repo.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	// Claimed emails are left alone until the lease expires
+	claimed, err = repo.ClaimDue(ctx, now.Add(30*time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	claimed, err = repo.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, 2, claimed[0].Attempts)
+}
+
+func TestRepository_CountPending(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupTestDB(t))
+	now := time.Now()
+
+	for _, status := range []string{OutboxPending, OutboxPending, OutboxDead} {
+		require.NoError(t, repo.Enqueue(ctx, &OutboxEmail{To: []string{"a@example.com"}, Subject: "x", Body: "x", Status: status, NextAttemptAt: now}))
+	}
+
+	pending, err := repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pending)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(30*time.Second, 1))
+	assert.Equal(t, time.Minute, retryDelay(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, retryDelay(30*time.Second, 4))
+	assert.Equal(t, maxRetryDelay, retryDelay(30*time.Second, 40))
+}
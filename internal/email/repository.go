@@ -0,0 +1,546 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository define a interface de persistência da fila de emails e do log de envio
+type Repository interface {
+	Enqueue(ctx context.Context, email *OutboxEmail) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxEmail, error)
+	MarkSent(ctx context.Context, id uint, sentAt time.Time, provider, providerMessageID string) error
+	Reschedule(ctx context.Context, id uint, lastError string, next time.Time) error
+	MarkDead(ctx context.Context, id uint, lastError string) error
+	MarkCancelled(ctx context.Context, id uint, lastError string) error
+	CountPending(ctx context.Context) (int64, error)
+	ListDead(ctx context.Context, page, limit int) ([]OutboxEmail, int64, error)
+	FindByID(ctx context.Context, id uint) (*OutboxEmail, error)
+	FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*OutboxEmail, error)
+	SetDeliveryStatus(ctx context.Context, id uint, status string, at time.Time) error
+	AddLog(ctx context.Context, log *EmailLog) error
+	ListLogs(ctx context.Context, query *EmailLogListQuery) ([]EmailLog, int64, error)
+	CreateTemplate(ctx context.Context, tmpl *EmailTemplate) error
+	UpdateTemplate(ctx context.Context, tmpl *EmailTemplate) error
+	DeleteTemplate(ctx context.Context, id uint) error
+	FindTemplateByID(ctx context.Context, id uint) (*EmailTemplate, error)
+	FindTemplateByName(ctx context.Context, name string) (*EmailTemplate, error)
+	FindTemplatesByName(ctx context.Context, names []string) ([]EmailTemplate, error)
+	ListTemplates(ctx context.Context, page, limit int) ([]EmailTemplate, int64, error)
+	ListTemplateVersions(ctx context.Context, templateID uint) ([]EmailTemplateVersion, error)
+	FindTemplateVersion(ctx context.Context, templateID uint, version int) (*EmailTemplateVersion, error)
+	CreateCampaign(ctx context.Context, campaign *EmailCampaign, emails []OutboxEmail) error
+	FindCampaignByID(ctx context.Context, id uint) (*EmailCampaign, error)
+	ListCampaigns(ctx context.Context, page, limit int) ([]EmailCampaign, int64, error)
+	CountCampaignEmails(ctx context.Context, campaignIDs []uint) ([]CampaignEmailCount, error)
+	CancelCampaign(ctx context.Context, id uint, at time.Time) error
+	ListUserRecipients(ctx context.Context, role string, limit int) ([]CampaignRecipient, error)
+	FindUserLocales(ctx context.Context, emails []string) (map[string]string, error)
+	AddSuppression(ctx context.Context, suppression *Suppression) (bool, error)
+	FindSuppressed(ctx context.Context, emails []string) ([]Suppression, error)
+	ListSuppressions(ctx context.Context, query *SuppressionListQuery) ([]Suppression, int64, error)
+	DeleteSuppression(ctx context.Context, id uint) error
+}
+
+// CampaignEmailCount é a quantidade de emails de uma campanha com um status de envio e de entrega
+type CampaignEmailCount struct {
+	CampaignID     uint
+	Status         string
+	DeliveryStatus string
+	Count          int
+}
+
+// campaignInsertBatch limita os emails de campanha gravados por INSERT
+const campaignInsertBatch = 500
+
+// ErrTemplateVersionConflict indica que o template foi alterado por outra requisição desde que foi lido
+var ErrTemplateVersionConflict = errors.New("email template was changed concurrently")
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository cria uma nova instância do repositório de emails
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Enqueue grava um email na fila
+func (r *repository) Enqueue(ctx context.Context, email *OutboxEmail) error {
+	return r.db.WithContext(ctx).Create(email).Error
+}
+
+// ClaimDue reserva até limit emails pendentes cuja tentativa já venceu, contando a tentativa e
+// adiando a próxima por lease. Outras instâncias ignoram as linhas reservadas (SKIP LOCKED), e um
+// email cujo envio foi interrompido volta para a fila quando o lease expira.
+func (r *repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxEmail, error) {
+	var emails []OutboxEmail
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", OutboxPending, now).
+			Order("next_attempt_at").
+			Order("id").
+			Limit(limit).
+			Find(&emails).Error; err != nil {
+			return err
+		}
+		if len(emails) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(emails))
+		for i := range emails {
+			ids[i] = emails[i].ID
+			emails[i].Attempts++
+			emails[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&OutboxEmail{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// MarkSent registra o envio de um email, com o provedor e o ID atribuído por ele
+func (r *repository) MarkSent(ctx context.Context, id uint, sentAt time.Time, provider, providerMessageID string) error {
+	return r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":              OutboxSent,
+		"sent_at":             sentAt,
+		"last_error":          "",
+		"provider":            provider,
+		"provider_message_id": providerMessageID,
+	}).Error
+}
+
+// Reschedule registra a falha de uma tentativa e agenda a próxima
+func (r *repository) Reschedule(ctx context.Context, id uint, lastError string, next time.Time) error {
+	return r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_error":      lastError,
+		"next_attempt_at": next,
+	}).Error
+}
+
+// MarkDead tira um email da fila, mantendo-o para análise
+func (r *repository) MarkDead(ctx context.Context, id uint, lastError string) error {
+	return r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     OutboxDead,
+		"last_error": lastError,
+	}).Error
+}
+
+// MarkCancelled cancela um email reservado que não deve mais ser enviado
+func (r *repository) MarkCancelled(ctx context.Context, id uint, lastError string) error {
+	return r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     OutboxCancelled,
+		"last_error": lastError,
+	}).Error
+}
+
+// CountPending conta os emails da fila que ainda aguardam envio
+func (r *repository) CountPending(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("status = ?", OutboxPending).Count(&total).Error
+	return total, err
+}
+
+// ListDead lista os emails que não puderam ser enviados, os mais recentes primeiro
+func (r *repository) ListDead(ctx context.Context, page, limit int) ([]OutboxEmail, int64, error) {
+	var emails []OutboxEmail
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("status = ?", OutboxDead)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("updated_at DESC").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&emails).Error; err != nil {
+		return nil, 0, err
+	}
+	return emails, total, nil
+}
+
+// FindByID busca um email da fila
+func (r *repository) FindByID(ctx context.Context, id uint) (*OutboxEmail, error) {
+	var email OutboxEmail
+	if err := r.db.WithContext(ctx).First(&email, id).Error; err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// FindByProviderMessageID busca o email enviado pelo provedor com o ID atribuído por ele
+func (r *repository) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*OutboxEmail, error) {
+	var email OutboxEmail
+	if err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_message_id = ?", provider, providerMessageID).
+		First(&email).Error; err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// SetDeliveryStatus registra o status de entrega informado pelo provedor
+func (r *repository) SetDeliveryStatus(ctx context.Context, id uint, status string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"delivery_status":     status,
+		"delivery_updated_at": at,
+	}).Error
+}
+
+// AddLog registra uma tentativa de envio ou um evento de entrega
+func (r *repository) AddLog(ctx context.Context, log *EmailLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// ListLogs lista as tentativas de envio que atendem aos filtros, as mais recentes primeiro
+func (r *repository) ListLogs(ctx context.Context, query *EmailLogListQuery) ([]EmailLog, int64, error) {
+	var logs []EmailLog
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&EmailLog{})
+	if recipient := strings.ToLower(strings.TrimSpace(query.Recipient)); recipient != "" {
+		pattern := "%" + recipient + "%"
+		db = db.Where("(LOWER(CAST(recipients AS TEXT)) LIKE ? OR LOWER(CAST(cc AS TEXT)) LIKE ? OR LOWER(CAST(bcc AS TEXT)) LIKE ?)",
+			pattern, pattern, pattern)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", strings.ToUpper(query.Status))
+	}
+	if query.Template != "" {
+		db = db.Where("template_name = ?", query.Template)
+	}
+	if query.EmailID != 0 {
+		db = db.Where("email_id = ?", query.EmailID)
+	}
+	if query.MessageID != "" {
+		messageID := strings.Trim(query.MessageID, "<>")
+		db = db.Where("(message_id = ? OR provider_message_id = ?)", messageID, messageID)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at <= ?", *query.To)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("created_at DESC").
+		Order("id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// CreateTemplate grava um template novo na versão 1, com a versão no histórico
+func (r *repository) CreateTemplate(ctx context.Context, tmpl *EmailTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tmpl.Version = 1
+		if err := tx.Create(tmpl).Error; err != nil {
+			return err
+		}
+		return tx.Create(newTemplateVersion(tmpl)).Error
+	})
+}
+
+// UpdateTemplate grava as alterações de tmpl como uma nova versão. tmpl.Version deve ser a versão
+// lida; se outra requisição já a alterou, retorna ErrTemplateVersionConflict.
+func (r *repository) UpdateTemplate(ctx context.Context, tmpl *EmailTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&EmailTemplate{}).
+			Where("id = ? AND version = ?", tmpl.ID, tmpl.Version).
+			Select("description", "html", "variables", "version", "updated_at").
+			Updates(&EmailTemplate{
+				Description: tmpl.Description,
+				HTML:        tmpl.HTML,
+				Variables:   tmpl.Variables,
+				Version:     tmpl.Version + 1,
+				UpdatedAt:   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTemplateVersionConflict
+		}
+
+		tmpl.Version++
+		return tx.Create(newTemplateVersion(tmpl)).Error
+	})
+}
+
+func newTemplateVersion(tmpl *EmailTemplate) *EmailTemplateVersion {
+	return &EmailTemplateVersion{
+		TemplateID: tmpl.ID,
+		Version:    tmpl.Version,
+		HTML:       tmpl.HTML,
+		Variables:  tmpl.Variables,
+	}
+}
+
+// DeleteTemplate remove um template e o histórico de versões
+func (r *repository) DeleteTemplate(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&EmailTemplateVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&EmailTemplate{}, id).Error
+	})
+}
+
+// FindTemplateByID busca um template pelo ID
+func (r *repository) FindTemplateByID(ctx context.Context, id uint) (*EmailTemplate, error) {
+	var tmpl EmailTemplate
+	if err := r.db.WithContext(ctx).First(&tmpl, id).Error; err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// FindTemplateByName busca um template pelo nome
+func (r *repository) FindTemplateByName(ctx context.Context, name string) (*EmailTemplate, error) {
+	var tmpl EmailTemplate
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&tmpl).Error; err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// FindTemplatesByName busca os templates com os nomes informados
+func (r *repository) FindTemplatesByName(ctx context.Context, names []string) ([]EmailTemplate, error) {
+	var templates []EmailTemplate
+	err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&templates).Error
+	return templates, err
+}
+
+// ListTemplates lista os templates em ordem alfabética
+func (r *repository) ListTemplates(ctx context.Context, page, limit int) ([]EmailTemplate, int64, error) {
+	var templates []EmailTemplate
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&EmailTemplate{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("name").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&templates).Error; err != nil {
+		return nil, 0, err
+	}
+	return templates, total, nil
+}
+
+// ListTemplateVersions lista as versões salvas de um template, a mais recente primeiro
+func (r *repository) ListTemplateVersions(ctx context.Context, templateID uint) ([]EmailTemplateVersion, error) {
+	var versions []EmailTemplateVersion
+	if err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("version DESC").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindTemplateVersion busca uma versão salva de um template
+func (r *repository) FindTemplateVersion(ctx context.Context, templateID uint, version int) (*EmailTemplateVersion, error) {
+	var v EmailTemplateVersion
+	if err := r.db.WithContext(ctx).
+		Where("template_id = ? AND version = ?", templateID, version).
+		First(&v).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CreateCampaign grava a campanha e os emails dos destinatários na mesma transação
+func (r *repository) CreateCampaign(ctx context.Context, campaign *EmailCampaign, emails []OutboxEmail) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+		for i := range emails {
+			emails[i].CampaignID = &campaign.ID
+		}
+		return tx.CreateInBatches(emails, campaignInsertBatch).Error
+	})
+}
+
+// FindCampaignByID busca uma campanha
+func (r *repository) FindCampaignByID(ctx context.Context, id uint) (*EmailCampaign, error) {
+	var campaign EmailCampaign
+	if err := r.db.WithContext(ctx).First(&campaign, id).Error; err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns lista as campanhas, as mais recentes primeiro
+func (r *repository) ListCampaigns(ctx context.Context, page, limit int) ([]EmailCampaign, int64, error) {
+	var campaigns []EmailCampaign
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&EmailCampaign{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&campaigns).Error; err != nil {
+		return nil, 0, err
+	}
+	return campaigns, total, nil
+}
+
+// CountCampaignEmails conta os emails das campanhas por status de envio e de entrega
+func (r *repository) CountCampaignEmails(ctx context.Context, campaignIDs []uint) ([]CampaignEmailCount, error) {
+	var counts []CampaignEmailCount
+	if len(campaignIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.WithContext(ctx).Model(&OutboxEmail{}).
+		Select("campaign_id, status, COALESCE(delivery_status, '') AS delivery_status, COUNT(*) AS count").
+		Where("campaign_id IN ?", campaignIDs).
+		Group("campaign_id, status, delivery_status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CancelCampaign cancela uma campanha em envio e os emails dela que ainda estão na fila. Um email
+// que já estava sendo enviado termina o envio normalmente.
+func (r *repository) CancelCampaign(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&EmailCampaign{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":       CampaignCancelled,
+			"cancelled_at": at,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&OutboxEmail{}).
+			Where("campaign_id = ? AND status = ?", id, OutboxPending).
+			Update("status", OutboxCancelled).Error
+	})
+}
+
+// ListUserRecipients lista até limit usuários ativos, opcionalmente só os que têm role
+func (r *repository) ListUserRecipients(ctx context.Context, role string, limit int) ([]CampaignRecipient, error) {
+	var recipients []CampaignRecipient
+	query := r.db.WithContext(ctx).
+		Table("users").
+		Select("users.email, users.name, COALESCE(users.locale, '') AS locale").
+		Where("users.deleted_at IS NULL")
+	if role != "" {
+		query = query.
+			Joins("JOIN user_roles ON user_roles.user_id = users.id").
+			Joins("JOIN roles ON roles.id = user_roles.role_id").
+			Where("roles.name = ?", role)
+	}
+	if err := query.Order("users.id").Limit(limit).Scan(&recipients).Error; err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// FindUserLocales retorna o locale preferido dos usuários ativos com os endereços, que devem estar
+// em minúsculas, pelo endereço; usuários sem preferência ficam de fora
+func (r *repository) FindUserLocales(ctx context.Context, emails []string) (map[string]string, error) {
+	locales := make(map[string]string)
+	if len(emails) == 0 {
+		return locales, nil
+	}
+
+	var rows []struct {
+		Email  string
+		Locale string
+	}
+	if err := r.db.WithContext(ctx).
+		Table("users").
+		Select("LOWER(email) AS email, locale").
+		Where("LOWER(email) IN ? AND locale IS NOT NULL AND locale <> '' AND deleted_at IS NULL", emails).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		locales[row.Email] = row.Locale
+	}
+	return locales, nil
+}
+
+// AddSuppression inclui um endereço na lista de supressão e retorna false quando ele já estava
+// nela; o registro existente é mantido
+func (r *repository) AddSuppression(ctx context.Context, suppression *Suppression) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, DoNothing: true}).
+		Create(suppression)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindSuppressed retorna os registros da lista de supressão dos endereços informados, que devem
+// estar em minúsculas
+func (r *repository) FindSuppressed(ctx context.Context, emails []string) ([]Suppression, error) {
+	var suppressions []Suppression
+	if len(emails) == 0 {
+		return suppressions, nil
+	}
+	err := r.db.WithContext(ctx).Where("email IN ?", emails).Find(&suppressions).Error
+	return suppressions, err
+}
+
+// ListSuppressions lista a lista de supressão com os filtros, os mais recentes primeiro
+func (r *repository) ListSuppressions(ctx context.Context, query *SuppressionListQuery) ([]Suppression, int64, error) {
+	var suppressions []Suppression
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&Suppression{})
+	if email := strings.ToLower(strings.TrimSpace(query.Email)); email != "" {
+		db = db.Where("email LIKE ?", "%"+email+"%")
+	}
+	if query.Reason != "" {
+		db = db.Where("reason = ?", query.Reason)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("created_at DESC").
+		Order("id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&suppressions).Error; err != nil {
+		return nil, 0, err
+	}
+	return suppressions, total, nil
+}
+
+// DeleteSuppression remove um endereço da lista de supressão
+func (r *repository) DeleteSuppression(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&Suppression{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
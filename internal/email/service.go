@@ -1,19 +1,23 @@
 package email
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"embed"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log/slog"
+	"net"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	mail "github.com/wneessen/go-mail"
 
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 )
 
 //go:embed templates/*.html
@@ -23,18 +27,77 @@ var templatesFS embed.FS
 type Service interface {
 	SendEmail(ctx context.Context, req *SendEmailRequest) (*EmailResponse, error)
 	SendTemplateEmail(ctx context.Context, req *SendTemplateEmailRequest) (*EmailResponse, error)
+	TestConfiguration(ctx context.Context, recipient string) (*TestConfigResponse, error)
+	DeliverQueued(ctx context.Context) (int, error)
+	ListDeadLetters(ctx context.Context, query *DeadLetterListQuery) (*DeadLetterListResponse, error)
+	ListEmailLogs(ctx context.Context, query *EmailLogListQuery) (*EmailLogListResponse, error)
+	ListEmailTemplates(ctx context.Context, query *EmailTemplateListQuery) (*EmailTemplateListResponse, error)
+	GetEmailTemplate(ctx context.Context, id uint) (*EmailTemplate, error)
+	CreateEmailTemplate(ctx context.Context, req *CreateEmailTemplateRequest) (*EmailTemplate, error)
+	UpdateEmailTemplate(ctx context.Context, id uint, req *UpdateEmailTemplateRequest) (*EmailTemplate, error)
+	DeleteEmailTemplate(ctx context.Context, id uint) error
+	ListEmailTemplateVersions(ctx context.Context, id uint) ([]EmailTemplateVersion, error)
+	PreviewEmailTemplate(ctx context.Context, id uint, req *PreviewTemplateRequest) (*PreviewTemplateResponse, error)
+	GetEmail(ctx context.Context, id uint) (*EmailResponse, error)
+	HandleDeliveryWebhook(ctx context.Context, provider, token string, body []byte) (*WebhookResponse, error)
+	CreateCampaign(ctx context.Context, req *CreateCampaignRequest) (*CampaignResponse, error)
+	ListCampaigns(ctx context.Context, query *CampaignListQuery) (*CampaignListResponse, error)
+	GetCampaign(ctx context.Context, id uint) (*CampaignResponse, error)
+	CancelCampaign(ctx context.Context, id uint) (*CampaignResponse, error)
+	ListSuppressions(ctx context.Context, query *SuppressionListQuery) (*SuppressionListResponse, error)
+	CreateSuppression(ctx context.Context, req *CreateSuppressionRequest) (*Suppression, error)
+	DeleteSuppression(ctx context.Context, id uint) error
+	Unsubscribe(ctx context.Context, req *UnsubscribeRequest) (*UnsubscribeResponse, error)
 }
 
 type service struct {
-	cfg       *config.Config
-	templates map[string]*template.Template
+	cfg           *config.Config
+	templates     map[string]*template.Template
+	repo          Repository
+	provider      Provider
+	storage       storage.Storage
+	maxAttempts   int
+	retryBase     time.Duration
+	defaultLocale string
 }
 
-// NewService cria uma nova instância do serviço de email
-func NewService(cfg *config.Config) (Service, error) {
+// ServiceOption configura comportamentos opcionais do serviço de email
+type ServiceOption func(*service)
+
+// WithStorage permite embutir imagens nos emails de template, lidas de store no envio
+func WithStorage(store storage.Storage) ServiceOption {
+	return func(s *service) {
+		s.storage = store
+	}
+}
+
+// NewService cria uma nova instância do serviço de email. Os emails são gravados na fila do repo
+// e enviados pelo Dispatcher através do provedor configurado em email.provider.
+func NewService(cfg *config.Config, repo Repository, opts ...ServiceOption) (Service, error) {
+	provider, err := NewProvider(&cfg.Email)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &service{
-		cfg:       cfg,
-		templates: make(map[string]*template.Template),
+		cfg:         cfg,
+		templates:   make(map[string]*template.Template),
+		repo:        repo,
+		provider:    provider,
+		maxAttempts: cfg.Email.MaxAttempts,
+		retryBase:   time.Duration(cfg.Email.RetryBaseSeconds) * time.Second,
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = defaultMaxAttempts
+	}
+	if s.retryBase <= 0 {
+		s.retryBase = defaultRetryBase
+	}
+	if s.defaultLocale, err = normalizeLocale(cfg.Email.DefaultLocale); err != nil || s.defaultLocale == "" {
+		s.defaultLocale = defaultLocale
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	// Carrega os templates HTML
@@ -45,12 +108,16 @@ func NewService(cfg *config.Config) (Service, error) {
 	return s, nil
 }
 
-// loadTemplates carrega todos os templates HTML do embed.FS
+// loadTemplates carrega os templates HTML embutidos, usados quando não há um cadastrado com o nome.
+// As variantes por idioma ficam em arquivos com o locale no nome (welcome.en.html).
 func (s *service) loadTemplates() error {
-	templateNames := []string{"default", "welcome", "notification"}
+	paths, err := fs.Glob(templatesFS, "templates/*.html")
+	if err != nil {
+		return err
+	}
 
-	for _, name := range templateNames {
-		tmplPath := fmt.Sprintf("templates/%s.html", name)
+	for _, tmplPath := range paths {
+		name := strings.TrimSuffix(path.Base(tmplPath), ".html")
 		content, err := templatesFS.ReadFile(tmplPath)
 		if err != nil {
 			return fmt.Errorf("failed to read template %s: %w", name, err)
@@ -67,25 +134,59 @@ func (s *service) loadTemplates() error {
 	return nil
 }
 
-// SendEmail envia um email simples
+// SendEmail valida o email e o grava na fila de envio. O envio acontece em segundo plano, com
+// novas tentativas em caso de falha; endereços inválidos são recusados aqui.
 func (s *service) SendEmail(ctx context.Context, req *SendEmailRequest) (*EmailResponse, error) {
 	// Validação das configurações de email
 	if err := s.validateConfig(); err != nil {
 		return nil, err
 	}
 
-	// Cria o cliente SMTP
-	client, err := s.createSMTPClient()
+	return s.enqueue(ctx, &OutboxEmail{
+		To:      req.To,
+		Cc:      req.Cc,
+		Bcc:     req.Bcc,
+		Subject: req.Subject,
+		Body:    req.Body,
+		IsHTML:  req.IsHTML,
+	})
+}
+
+// enqueue grava o email na fila com o Message-ID que ele terá em todas as tentativas
+func (s *service) enqueue(ctx context.Context, queued *OutboxEmail) (*EmailResponse, error) {
+	if err := s.prepare(queued, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Enqueue(ctx, queued); err != nil {
+		return nil, errors.InternalServerError(fmt.Errorf("failed to queue email: %w", err))
+	}
+
+	return &EmailResponse{
+		Success:   true,
+		ID:        queued.ID,
+		MessageID: queued.MessageID,
+		Provider:  s.provider.Name(),
+		SentTo:    queued.To,
+		Message:   "Email queued for delivery",
+	}, nil
+}
+
+// prepare valida os endereços montando a mensagem e atribui o Message-ID e a primeira tentativa
+func (s *service) prepare(queued *OutboxEmail, firstAttempt time.Time) error {
+	msg, err := s.buildMessage(queued)
 	if err != nil {
-		return nil, errors.InternalServerError(fmt.Errorf("failed to create SMTP client: %w", err))
+		return err
 	}
-	defer func() {
-		if err := client.Close(); err != nil {
-			slog.Error("Failed to close SMTP client", "error", err)
-		}
-	}()
+	msg.SetMessageID()
+	queued.MessageID = strings.Trim(msg.GetMessageID(), "<>")
+	queued.Status = OutboxPending
+	queued.NextAttemptAt = firstAttempt
+	return nil
+}
 
-	// Cria a mensagem
+// buildMessage monta a mensagem de um email da fila
+func (s *service) buildMessage(queued *OutboxEmail) (*mail.Msg, error) {
 	msg := mail.NewMsg()
 
 	// Define o remetente
@@ -94,136 +195,280 @@ func (s *service) SendEmail(ctx context.Context, req *SendEmailRequest) (*EmailR
 	}
 
 	// Define os destinatários
-	if err := msg.To(req.To...); err != nil {
+	if err := msg.To(queued.To...); err != nil {
 		return nil, errors.BadRequest("Invalid 'to' addresses")
 	}
 
 	// Define CC se fornecido
-	if len(req.Cc) > 0 {
-		if err := msg.Cc(req.Cc...); err != nil {
+	if len(queued.Cc) > 0 {
+		if err := msg.Cc(queued.Cc...); err != nil {
 			return nil, errors.BadRequest("Invalid 'cc' addresses")
 		}
 	}
 
 	// Define BCC se fornecido
-	if len(req.Bcc) > 0 {
-		if err := msg.Bcc(req.Bcc...); err != nil {
+	if len(queued.Bcc) > 0 {
+		if err := msg.Bcc(queued.Bcc...); err != nil {
 			return nil, errors.BadRequest("Invalid 'bcc' addresses")
 		}
 	}
 
-	// Define o assunto
-	msg.Subject(req.Subject)
+	// Define o assunto e o Message-ID atribuído ao enfileirar
+	msg.Subject(queued.Subject)
+	if queued.MessageID != "" {
+		msg.SetMessageIDWithValue(queued.MessageID)
+	}
 
-	// Define o corpo do email
-	if req.IsHTML {
-		msg.SetBodyString(mail.TypeTextHTML, req.Body)
-	} else {
-		msg.SetBodyString(mail.TypeTextPlain, req.Body)
+	// Emails de marketing levam o descadastro com um clique (RFC 8058) do destinatário
+	if queued.Marketing {
+		link, err := s.unsubscribeURL(queued.To[0])
+		if err != nil {
+			return nil, err
+		}
+		msg.SetGenHeader(mail.HeaderListUnsubscribe, "<"+link+">")
+		msg.SetGenHeader(mail.HeaderListUnsubscribePost, "List-Unsubscribe=One-Click")
 	}
 
-	// Envia o email
-	if err := client.DialAndSend(msg); err != nil {
-		return nil, errors.InternalServerError(fmt.Errorf("failed to send email: %w", err))
+	// Define o corpo do email
+	if queued.IsHTML {
+		msg.SetBodyString(mail.TypeTextHTML, queued.Body)
+	} else {
+		msg.SetBodyString(mail.TypeTextPlain, queued.Body)
 	}
 
-	return &EmailResponse{
-		Success: true,
-		SentTo:  req.To,
-		Message: "Email sent successfully",
-	}, nil
+	return msg, nil
 }
 
-// SendTemplateEmail envia um email usando um template HTML
+// SendTemplateEmail envia um email usando um template HTML cadastrado ou embutido
 func (s *service) SendTemplateEmail(ctx context.Context, req *SendTemplateEmailRequest) (*EmailResponse, error) {
 	// Validação das configurações de email
 	if err := s.validateConfig(); err != nil {
 		return nil, err
 	}
 
-	// Verifica se o template existe
-	tmpl, exists := s.templates[req.TemplateName]
-	if !exists {
-		return nil, errors.BadRequest(fmt.Sprintf("Template '%s' not found", req.TemplateName))
+	// Busca o template cadastrado ou o embutido no idioma do destinatário e confere os dados contra o
+	// esquema de variáveis
+	locale, err := s.requestLocale(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, variables, err := s.resolveTemplate(ctx, req.TemplateName, locale)
+	if err != nil {
+		return nil, err
+	}
+	data := req.TemplateData
+	if req.Marketing {
+		if data, err = s.marketingData(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateTemplateData(variables, data); err != nil {
+		return nil, err
 	}
 
 	// Renderiza o template
-	var body bytes.Buffer
-	if req.TemplateData == nil {
-		req.TemplateData = make(map[string]interface{})
+	body, err := s.render(tmpl, data)
+	if err != nil {
+		return nil, errors.InternalServerError(fmt.Errorf("failed to render template: %w", err))
+	}
+
+	// Valida e armazena as imagens referenciadas como cid: no HTML
+	images, err := s.resolveInlineImages(ctx, req.InlineImages)
+	if err != nil {
+		return nil, err
 	}
 
-	// Adiciona dados padrão ao template
-	req.TemplateData["Year"] = time.Now().Year()
-	req.TemplateData["AppName"] = s.cfg.App.Name
+	// Enfileira o email com o corpo renderizado
+	return s.enqueue(ctx, &OutboxEmail{
+		To:           req.To,
+		Cc:           req.Cc,
+		Bcc:          req.Bcc,
+		Subject:      req.Subject,
+		Body:         body,
+		IsHTML:       true,
+		InlineImages: images,
+		TemplateName: req.TemplateName,
+		Marketing:    req.Marketing,
+	})
+}
 
-	if err := tmpl.Execute(&body, req.TemplateData); err != nil {
-		return nil, errors.InternalServerError(fmt.Errorf("failed to render template: %w", err))
+// requestLocale retorna o locale do email: o da requisição ou o preferido pelo usuário cadastrado com
+// o primeiro destinatário; vazio quando nenhum deles existe
+func (s *service) requestLocale(ctx context.Context, req *SendTemplateEmailRequest) (string, error) {
+	if req.Locale != "" {
+		return normalizeLocale(req.Locale)
+	}
+	locales, err := s.recipientLocales(ctx, req.To[:1])
+	if err != nil {
+		return "", err
 	}
+	// O locale gravado já foi validado no cadastro; um inválido cai no locale padrão
+	locale, _ := normalizeLocale(locales[normalizeAddress(req.To[0])])
+	return locale, nil
+}
 
-	// Cria a requisição de email com o corpo renderizado
-	emailReq := &SendEmailRequest{
-		To:      req.To,
-		Cc:      req.Cc,
-		Bcc:     req.Bcc,
-		Subject: req.Subject,
-		Body:    body.String(),
-		IsHTML:  true,
+// marketingData confere o destinatário de um email de marketing contra a lista de supressão e
+// retorna os dados do template com o link de descadastro dele
+func (s *service) marketingData(ctx context.Context, req *SendTemplateEmailRequest) (map[string]interface{}, error) {
+	if len(req.To) != 1 || len(req.Cc) > 0 || len(req.Bcc) > 0 {
+		return nil, errors.BadRequest("Marketing emails must have a single recipient and no cc or bcc")
+	}
+	suppressed, err := s.suppressed(ctx, req.To)
+	if err != nil {
+		return nil, errors.InternalServerError(err)
+	}
+	if reason, ok := suppressed[normalizeAddress(req.To[0])]; ok {
+		return nil, errors.Conflict(fmt.Sprintf("Recipient %s is suppressed (%s)", req.To[0], reason))
 	}
 
-	return s.SendEmail(ctx, emailReq)
+	link, err := s.unsubscribeURL(req.To[0])
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{}, len(req.TemplateData)+1)
+	for k, v := range req.TemplateData {
+		data[k] = v
+	}
+	data["UnsubscribeURL"] = link
+	return data, nil
 }
 
-// createSMTPClient cria e configura o cliente SMTP
-func (s *service) createSMTPClient() (*mail.Client, error) {
-	options := []mail.Option{
-		mail.WithPort(s.cfg.Email.Port),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithUsername(s.cfg.Email.Username),
-		mail.WithPassword(s.cfg.Email.Password),
-		mail.WithTimeout(30 * time.Second),
-	}
-
-	// Configura TLS se habilitado
-	if s.cfg.Email.UseTLS {
-		tlsConfig := &tls.Config{
-			ServerName:         s.cfg.Email.Host,
-			InsecureSkipVerify: false,
+// TestConfiguration valida a configuração de email executando cada etapa do envio e retorna o
+// diagnóstico. No SMTP as etapas são validação, conexão TCP, handshake/autenticação e envio; nos
+// provedores por API, validação e envio.
+func (s *service) TestConfiguration(ctx context.Context, recipient string) (*TestConfigResponse, error) {
+	result := &TestConfigResponse{
+		Provider:    s.provider.Name(),
+		Host:        s.cfg.Email.Host,
+		Port:        s.cfg.Email.Port,
+		Username:    s.cfg.Email.Username,
+		From:        s.cfg.Email.From,
+		UseTLS:      s.cfg.Email.UseTLS,
+		UseStartTLS: s.cfg.Email.UseStartTLS,
+		SentTo:      recipient,
+		Steps:       make([]DiagnosticStep, 0, 4),
+	}
+
+	failed := false
+	runStep := func(name string, fn func() error) {
+		if failed {
+			result.Steps = append(result.Steps, DiagnosticStep{Name: name, Status: DiagnosticStatusSkipped, Duration: "0s"})
+			return
 		}
-		options = append(options, mail.WithTLSConfig(tlsConfig))
 
-		// Se StartTLS estiver habilitado, usa essa opção
-		if s.cfg.Email.UseStartTLS {
-			options = append(options, mail.WithTLSPolicy(mail.TLSMandatory))
-		} else {
-			options = append(options, mail.WithSSL())
+		start := time.Now()
+		err := fn()
+		step := DiagnosticStep{Name: name, Status: DiagnosticStatusOK, Duration: time.Since(start).String()}
+		if err != nil {
+			failed = true
+			step.Status = DiagnosticStatusFailed
+			step.Error = err.Error()
 		}
+		result.Steps = append(result.Steps, step)
 	}
 
-	client, err := mail.NewClient(s.cfg.Email.Host, options...)
-	if err != nil {
-		return nil, err
+	// Etapa 1: configuração presente
+	runStep("config", func() error {
+		if err := s.validateConfig(); err != nil {
+			if apiErr, ok := err.(*errors.APIError); ok && apiErr.Details != nil {
+				return fmt.Errorf("%v", apiErr.Details)
+			}
+			return err
+		}
+		return nil
+	})
+
+	// Mensagem de teste para o solicitante
+	newTestMessage := func() (*mail.Msg, error) {
+		msg := mail.NewMsg()
+		if err := msg.From(s.cfg.Email.From); err != nil {
+			return nil, fmt.Errorf("invalid from address: %w", err)
+		}
+		if err := msg.To(recipient); err != nil {
+			return nil, fmt.Errorf("invalid recipient address: %w", err)
+		}
+		msg.Subject(fmt.Sprintf("[%s] Email configuration test", s.cfg.App.Name))
+		msg.SetBodyString(mail.TypeTextPlain, fmt.Sprintf(
+			"This is a test message sent by %s through %s at %s to validate the email configuration.",
+			s.cfg.App.Name, s.provider.Name(), time.Now().UTC().Format(time.RFC3339),
+		))
+		msg.SetMessageID()
+		return msg, nil
+	}
+
+	smtp, ok := s.provider.(*smtpProvider)
+	if !ok {
+		// Etapa 2: envio pela API do provedor
+		runStep("send", func() error {
+			msg, err := newTestMessage()
+			if err != nil {
+				return err
+			}
+			providerMessageID, err := s.provider.Send(ctx, 0, msg)
+			if err != nil {
+				return err
+			}
+			result.MessageID = msg.GetMessageID()
+			result.ProviderMessageID = providerMessageID
+			return nil
+		})
+
+		result.Success = !failed
+		return result, nil
 	}
 
-	return client, nil
+	// Etapa 2: conectividade TCP com o servidor SMTP
+	runStep("connect", func() error {
+		dialer := &net.Dialer{Timeout: 10 * time.Second}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Email.Host, strconv.Itoa(s.cfg.Email.Port)))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+
+	// Etapa 3: handshake SMTP, TLS e autenticação
+	var client *mail.Client
+	runStep("authenticate", func() error {
+		var err error
+		client, err = smtp.newClient()
+		if err != nil {
+			return err
+		}
+		return client.DialWithContext(ctx)
+	})
+	if client != nil {
+		defer func() {
+			if err := client.Close(); err != nil {
+				slog.Error("Failed to close SMTP client", "error", err)
+			}
+		}()
+	}
+
+	// Etapa 4: envio da mensagem de teste para o solicitante
+	runStep("send", func() error {
+		msg, err := newTestMessage()
+		if err != nil {
+			return err
+		}
+		if err := client.Send(msg); err != nil {
+			return err
+		}
+		result.MessageID = msg.GetMessageID()
+		return nil
+	})
+
+	result.Success = !failed
+	return result, nil
 }
 
-// validateConfig valida as configurações de email
+// validateConfig valida as configurações de email do provedor
 func (s *service) validateConfig() error {
-	if s.cfg.Email.Host == "" {
-		return errors.InternalServerError(fmt.Errorf("email host not configured"))
-	}
-	if s.cfg.Email.Port == 0 {
-		return errors.InternalServerError(fmt.Errorf("email port not configured"))
-	}
 	if s.cfg.Email.From == "" {
 		return errors.InternalServerError(fmt.Errorf("email from address not configured"))
 	}
-	if s.cfg.Email.Username == "" {
-		return errors.InternalServerError(fmt.Errorf("email username not configured"))
-	}
-	if s.cfg.Email.Password == "" {
-		return errors.InternalServerError(fmt.Errorf("email password not configured"))
+	if err := s.provider.Validate(); err != nil {
+		return errors.InternalServerError(err)
 	}
 	return nil
 }
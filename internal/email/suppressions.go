@@ -0,0 +1,158 @@
+package email
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+// suppressionReasons mapeia os status de entrega que colocam o destinatário na lista de supressão
+var suppressionReasons = map[string]string{
+	LogBounced:    SuppressionBounced,
+	LogComplained: SuppressionComplained,
+}
+
+// ListSuppressions lista os endereços que não recebem emails de marketing
+func (s *service) ListSuppressions(ctx context.Context, query *SuppressionListQuery) (*SuppressionListResponse, error) {
+	suppressions, total, err := s.repo.ListSuppressions(ctx, query)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to list email suppressions: %w", err))
+	}
+	return pagination.New(suppressions, total, query.Page, query.Limit), nil
+}
+
+// CreateSuppression inclui um endereço na lista de supressão a pedido de um administrador
+func (s *service) CreateSuppression(ctx context.Context, req *CreateSuppressionRequest) (*Suppression, error) {
+	suppression := &Suppression{
+		Email:  normalizeAddress(req.Email),
+		Reason: req.Reason,
+		Note:   req.Note,
+	}
+	if suppression.Reason == "" {
+		suppression.Reason = SuppressionManual
+	}
+	if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+		suppression.CreatedBy = &userID
+	}
+
+	created, err := s.repo.AddSuppression(ctx, suppression)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to add email suppression: %w", err))
+	}
+	if !created {
+		return nil, apiErrors.Conflict(fmt.Sprintf("Email %s is already suppressed", suppression.Email))
+	}
+	return suppression, nil
+}
+
+// DeleteSuppression remove um endereço da lista de supressão; ele volta a receber emails de marketing
+func (s *service) DeleteSuppression(ctx context.Context, id uint) error {
+	if err := s.repo.DeleteSuppression(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apiErrors.NotFound("Suppression not found")
+		}
+		return apiErrors.InternalServerError(fmt.Errorf("failed to delete email suppression: %w", err))
+	}
+	return nil
+}
+
+// Unsubscribe inclui na lista de supressão o endereço de um link de descadastro. Usar o link de
+// novo não é um erro.
+func (s *service) Unsubscribe(ctx context.Context, req *UnsubscribeRequest) (*UnsubscribeResponse, error) {
+	if s.cfg.Email.UnsubscribeSecret == "" {
+		return nil, apiErrors.NotFound("Unsubscribe is not enabled")
+	}
+	if !hmac.Equal([]byte(req.Token), []byte(s.unsubscribeToken(req.Email))) {
+		return nil, apiErrors.BadRequest("Invalid unsubscribe link")
+	}
+
+	address := normalizeAddress(req.Email)
+	if _, err := s.repo.AddSuppression(ctx, &Suppression{Email: address, Reason: SuppressionUnsubscribed}); err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to add email suppression: %w", err))
+	}
+	slog.Info("Email address unsubscribed from marketing emails", "email", address)
+	return &UnsubscribeResponse{Email: address, Message: "You will no longer receive marketing emails"}, nil
+}
+
+// suppressed retorna o motivo da supressão de cada endereço da lista de supressão entre addresses,
+// pelo endereço em minúsculas
+func (s *service) suppressed(ctx context.Context, addresses []string) (map[string]string, error) {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = normalizeAddress(address)
+	}
+	suppressions, err := s.repo.FindSuppressed(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email suppressions: %w", err)
+	}
+	reasons := make(map[string]string, len(suppressions))
+	for _, suppression := range suppressions {
+		reasons[suppression.Email] = suppression.Reason
+	}
+	return reasons, nil
+}
+
+// suppressDeliveryEvent inclui na lista de supressão os destinatários de uma devolução permanente
+// ou de uma reclamação
+func (s *service) suppressDeliveryEvent(ctx context.Context, queued *OutboxEmail, event DeliveryEvent) error {
+	reason, ok := suppressionReasons[event.Status]
+	if !ok {
+		return nil
+	}
+	for _, address := range event.Suppress {
+		created, err := s.repo.AddSuppression(ctx, &Suppression{
+			Email:   normalizeAddress(address),
+			Reason:  reason,
+			Note:    event.Reason,
+			EmailID: &queued.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add email suppression: %w", err)
+		}
+		if created {
+			slog.Info("Email address suppressed", "email", normalizeAddress(address), "reason", reason, "email_id", queued.ID)
+		}
+	}
+	return nil
+}
+
+// unsubscribeURL retorna o link de descadastro de um destinatário de email de marketing
+func (s *service) unsubscribeURL(address string) (string, error) {
+	if s.cfg.Email.UnsubscribeURL == "" || s.cfg.Email.UnsubscribeSecret == "" {
+		return "", apiErrors.InternalServerError(fmt.Errorf("email.unsubscribe_url and email.unsubscribe_secret must be set to send marketing emails"))
+	}
+	link, err := url.Parse(s.cfg.Email.UnsubscribeURL)
+	if err != nil {
+		return "", apiErrors.InternalServerError(fmt.Errorf("invalid email.unsubscribe_url: %w", err))
+	}
+
+	query := link.Query()
+	query.Set("email", normalizeAddress(address))
+	query.Set("token", s.unsubscribeToken(address))
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// unsubscribeToken assina o endereço do link de descadastro com email.unsubscribe_secret
+func (s *service) unsubscribeToken(address string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Email.UnsubscribeSecret))
+	mac.Write([]byte(normalizeAddress(address)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// normalizeAddress retorna o endereço como ele é gravado na lista de supressão
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
@@ -0,0 +1,220 @@
+package email
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+func TestService_Suppressions(t *testing.T) {
+	ctx := contextutil.WithUserID(context.Background(), 7)
+	svc, _ := newQueueService(t, nil)
+
+	created, err := svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: " Ana@Example.com ", Note: "pediu por telefone"})
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", created.Email)
+	assert.Equal(t, SuppressionManual, created.Reason)
+	assert.Equal(t, uint(7), *created.CreatedBy)
+
+	_, err = svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: "ANA@example.com", Reason: SuppressionBounced})
+	assertAPIStatus(t, err, http.StatusConflict)
+
+	_, err = svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: "bruno@example.com", Reason: SuppressionComplained})
+	require.NoError(t, err)
+
+	list, err := svc.ListSuppressions(ctx, &SuppressionListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Len(t, list.Results, 2)
+	list, err = svc.ListSuppressions(ctx, &SuppressionListQuery{Page: 1, Limit: 20, Email: "ANA"})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, created.ID, list.Results[0].ID)
+	list, err = svc.ListSuppressions(ctx, &SuppressionListQuery{Page: 1, Limit: 20, Reason: SuppressionComplained})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, "bruno@example.com", list.Results[0].Email)
+
+	require.NoError(t, svc.DeleteSuppression(ctx, created.ID))
+	assertAPIStatus(t, svc.DeleteSuppression(ctx, created.ID), http.StatusNotFound)
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, nil)
+
+	link, err := svc.unsubscribeURL("Ana@Example.com")
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "api.example.com", parsed.Host)
+	assert.Equal(t, "ana@example.com", parsed.Query().Get("email"))
+	token := parsed.Query().Get("token")
+
+	_, err = svc.Unsubscribe(ctx, &UnsubscribeRequest{Email: "bruno@example.com", Token: token})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	result, err := svc.Unsubscribe(ctx, &UnsubscribeRequest{Email: "ANA@example.com", Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", result.Email)
+	_, err = svc.Unsubscribe(ctx, &UnsubscribeRequest{Email: "ana@example.com", Token: token})
+	require.NoError(t, err, "using the link again is not an error")
+
+	var suppressions []Suppression
+	require.NoError(t, db.Find(&suppressions).Error)
+	require.Len(t, suppressions, 1)
+	assert.Equal(t, SuppressionUnsubscribed, suppressions[0].Reason)
+
+	svc.cfg.Email.UnsubscribeSecret = ""
+	_, err = svc.Unsubscribe(ctx, &UnsubscribeRequest{Email: "ana@example.com", Token: token})
+	assertAPIStatus(t, err, http.StatusNotFound)
+}
+
+func TestService_SendTemplateEmail_Marketing(t *testing.T) {
+	ctx := context.Background()
+	var sent []*mail.Msg
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	request := func(to ...string) *SendTemplateEmailRequest {
+		return &SendTemplateEmailRequest{
+			To:           to,
+			Subject:      "Novidades",
+			TemplateName: "default",
+			TemplateData: map[string]interface{}{"Title": "Novidades", "Content": "Conheça os lançamentos"},
+			Marketing:    true,
+		}
+	}
+
+	_, err := svc.SendTemplateEmail(ctx, request("ana@example.com", "bruno@example.com"))
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	resp, err := svc.SendTemplateEmail(ctx, request("ana@example.com"))
+	require.NoError(t, err)
+	queued := outboxEmail(t, db, resp.ID)
+	assert.True(t, queued.Marketing)
+	assert.Contains(t, queued.Body, "https://api.example.com/api/v1/emails/unsubscribe?email=ana%40example.com")
+
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	link := sent[0].GetGenHeader(mail.HeaderListUnsubscribe)
+	require.Len(t, link, 1)
+	assert.Contains(t, link[0], "<https://api.example.com/api/v1/emails/unsubscribe?email=ana%40example.com&token=")
+	assert.Equal(t, []string{"List-Unsubscribe=One-Click"}, sent[0].GetGenHeader(mail.HeaderListUnsubscribePost))
+
+	// Transactional emails carry no unsubscribe link
+	resp, err = svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{
+		To: []string{"ana@example.com"}, Subject: "Recibo", TemplateName: "default",
+		TemplateData: map[string]interface{}{"Title": "Recibo", "Content": "Pagamento recebido"},
+	})
+	require.NoError(t, err)
+	assert.False(t, outboxEmail(t, db, resp.ID).Marketing)
+
+	_, err = svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: "ana@example.com"})
+	require.NoError(t, err)
+	_, err = svc.SendTemplateEmail(ctx, request("ana@example.com"))
+	assertAPIStatus(t, err, http.StatusConflict)
+
+	svc.cfg.Email.UnsubscribeURL = ""
+	_, err = svc.SendTemplateEmail(ctx, request("bruno@example.com"))
+	assertAPIStatus(t, err, http.StatusInternalServerError)
+}
+
+func TestService_DeliverQueued_SkipsSuppressedMarketing(t *testing.T) {
+	ctx := context.Background()
+	var sent []*mail.Msg
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	resp, err := svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{
+		To: []string{"ana@example.com"}, Subject: "Novidades", TemplateName: "default",
+		TemplateData: map[string]interface{}{"Title": "Novidades", "Content": "Conheça os lançamentos"},
+		Marketing:    true,
+	})
+	require.NoError(t, err)
+
+	// The recipient unsubscribes while the email is queued
+	_, err = svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: "ANA@example.com", Reason: SuppressionUnsubscribed})
+	require.NoError(t, err)
+	_, err = svc.DeliverQueued(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sent)
+
+	queued := outboxEmail(t, db, resp.ID)
+	assert.Equal(t, OutboxCancelled, queued.Status)
+	assert.Equal(t, "ana@example.com is suppressed (UNSUBSCRIBED)", queued.LastError)
+
+	logs, err := svc.ListEmailLogs(ctx, &EmailLogListQuery{Page: 1, Limit: 20, Status: EmailLogStatusSuppressed})
+	require.NoError(t, err)
+	require.Len(t, logs.Results, 1)
+	assert.Equal(t, resp.ID, logs.Results[0].EmailID)
+}
+
+func TestService_DeliveryEvent_Suppresses(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error { return nil })
+
+	resp, err := svc.SendEmail(ctx, &SendEmailRequest{To: []string{"ana@example.com", "bruno@example.com"}, Subject: "Proposta", Body: "Olá"})
+	require.NoError(t, err)
+
+	applied, err := svc.applyDeliveryEvent(ctx, DeliveryEvent{EmailID: resp.ID, Status: LogBounced, Recipient: "bruno@example.com", Reason: "550 5.1.1 user unknown"})
+	require.NoError(t, err)
+	require.True(t, applied)
+	_, err = svc.applyDeliveryEvent(ctx, DeliveryEvent{EmailID: resp.ID, Status: LogBounced, Reason: "550 5.1.1 user unknown", Suppress: []string{"Bruno@example.com"}})
+	require.NoError(t, err)
+	_, err = svc.applyDeliveryEvent(ctx, DeliveryEvent{EmailID: resp.ID, Status: LogComplained, Suppress: []string{"ana@example.com", "bruno@example.com"}})
+	require.NoError(t, err)
+
+	var suppressions []Suppression
+	require.NoError(t, db.Order("email").Find(&suppressions).Error)
+	require.Len(t, suppressions, 2)
+	assert.Equal(t, "ana@example.com", suppressions[0].Email)
+	assert.Equal(t, SuppressionComplained, suppressions[0].Reason)
+	assert.Equal(t, "bruno@example.com", suppressions[1].Email)
+	assert.Equal(t, SuppressionBounced, suppressions[1].Reason, "the first reason is kept")
+	assert.Equal(t, "550 5.1.1 user unknown", suppressions[1].Note)
+	assert.Equal(t, resp.ID, *suppressions[1].EmailID)
+}
+
+func TestService_CreateCampaign_SkipsSuppressed(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error { return nil })
+	_, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+	_, err = svc.CreateSuppression(ctx, &CreateSuppressionRequest{Email: "bruno@example.com", Reason: SuppressionUnsubscribed})
+	require.NoError(t, err)
+
+	request := &CreateCampaignRequest{
+		Name:         "Lançamento Jardins",
+		TemplateName: "proposal",
+		Subject:      "Novidades",
+		TemplateData: map[string]interface{}{"Name": "cliente", "Amount": 1000},
+		Segment: CampaignSegment{Type: SegmentRecipients, Recipients: []CampaignRecipient{
+			{Email: "ana@example.com"},
+			{Email: "BRUNO@example.com"},
+		}},
+	}
+	result, err := svc.CreateCampaign(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, 1, result.Suppressed)
+
+	emails := campaignEmails(t, db, result.ID)
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"ana@example.com"}, emails[0].To)
+	assert.True(t, emails[0].Marketing)
+
+	request.Segment.Recipients = request.Segment.Recipients[1:]
+	_, err = svc.CreateCampaign(ctx, request)
+	assertAPIStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,300 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+var (
+	// templateNamePattern limita os nomes de template a slugs, que é como os módulos os referenciam
+	templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// variableNamePattern aceita nomes acessíveis no template como {{.Nome}}
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// reservedVariables são preenchidas em toda renderização e não podem ser declaradas
+var reservedVariables = map[string]bool{"Year": true, "AppName": true}
+
+// ListEmailTemplates lista os templates cadastrados. Os templates embutidos não aparecem.
+func (s *service) ListEmailTemplates(ctx context.Context, query *EmailTemplateListQuery) (*EmailTemplateListResponse, error) {
+	templates, total, err := s.repo.ListTemplates(ctx, query.Page, query.Limit)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to list email templates: %w", err))
+	}
+	return pagination.New(templates, total, query.Page, query.Limit), nil
+}
+
+// GetEmailTemplate busca um template cadastrado
+func (s *service) GetEmailTemplate(ctx context.Context, id uint) (*EmailTemplate, error) {
+	tmpl, err := s.repo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, templateLookupError(err)
+	}
+	return tmpl, nil
+}
+
+// CreateEmailTemplate cadastra um template. Um template com o nome de um embutido passa a ser usado
+// no lugar dele. Uma variante por idioma leva o locale no nome (welcome.en, welcome.pt-BR).
+func (s *service) CreateEmailTemplate(ctx context.Context, req *CreateEmailTemplateRequest) (*EmailTemplate, error) {
+	name, err := templateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(name, req.HTML, req.Variables); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindTemplateByName(ctx, name); err == nil {
+		return nil, apiErrors.Conflict(fmt.Sprintf("Template '%s' already exists", name))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to find email template: %w", err))
+	}
+
+	tmpl := &EmailTemplate{
+		Name:        name,
+		Description: req.Description,
+		HTML:        req.HTML,
+		Variables:   req.Variables,
+	}
+	if err := s.repo.CreateTemplate(ctx, tmpl); err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to create email template: %w", err))
+	}
+	return tmpl, nil
+}
+
+// UpdateEmailTemplate altera um template, gravando o resultado como uma nova versão
+func (s *service) UpdateEmailTemplate(ctx context.Context, id uint, req *UpdateEmailTemplateRequest) (*EmailTemplate, error) {
+	tmpl, err := s.repo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, templateLookupError(err)
+	}
+
+	if req.Description != nil {
+		tmpl.Description = *req.Description
+	}
+	if req.HTML != nil {
+		tmpl.HTML = *req.HTML
+	}
+	if req.Variables != nil {
+		tmpl.Variables = *req.Variables
+	}
+	if err := validateTemplate(tmpl.Name, tmpl.HTML, tmpl.Variables); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTemplate(ctx, tmpl); err != nil {
+		if errors.Is(err, ErrTemplateVersionConflict) {
+			return nil, apiErrors.Conflict("Template was changed by another request, reload it and try again")
+		}
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to update email template: %w", err))
+	}
+	return s.GetEmailTemplate(ctx, id)
+}
+
+// DeleteEmailTemplate remove um template e suas versões. Se ele substituía um embutido, o embutido
+// volta a ser usado.
+func (s *service) DeleteEmailTemplate(ctx context.Context, id uint) error {
+	if _, err := s.repo.FindTemplateByID(ctx, id); err != nil {
+		return templateLookupError(err)
+	}
+	if err := s.repo.DeleteTemplate(ctx, id); err != nil {
+		return apiErrors.InternalServerError(fmt.Errorf("failed to delete email template: %w", err))
+	}
+	return nil
+}
+
+// ListEmailTemplateVersions lista as versões salvas de um template, a mais recente primeiro
+func (s *service) ListEmailTemplateVersions(ctx context.Context, id uint) ([]EmailTemplateVersion, error) {
+	if _, err := s.repo.FindTemplateByID(ctx, id); err != nil {
+		return nil, templateLookupError(err)
+	}
+	versions, err := s.repo.ListTemplateVersions(ctx, id)
+	if err != nil {
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to list email template versions: %w", err))
+	}
+	return versions, nil
+}
+
+// PreviewEmailTemplate renderiza um template sem enviar. Os exemplos do esquema de variáveis
+// preenchem o que não vier em req.Data.
+func (s *service) PreviewEmailTemplate(ctx context.Context, id uint, req *PreviewTemplateRequest) (*PreviewTemplateResponse, error) {
+	tmpl, err := s.repo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, templateLookupError(err)
+	}
+
+	html, variables, version := tmpl.HTML, tmpl.Variables, tmpl.Version
+	if req.Version != 0 && req.Version != tmpl.Version {
+		saved, err := s.repo.FindTemplateVersion(ctx, id, req.Version)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apiErrors.NotFound(fmt.Sprintf("Template version %d not found", req.Version))
+			}
+			return nil, apiErrors.InternalServerError(fmt.Errorf("failed to find email template version: %w", err))
+		}
+		html, variables, version = saved.HTML, saved.Variables, saved.Version
+	}
+
+	parsed, err := template.New(tmpl.Name).Parse(html)
+	if err != nil {
+		return nil, apiErrors.BadRequest(fmt.Sprintf("Invalid template: %v", err))
+	}
+
+	data := make(map[string]interface{}, len(variables)+len(req.Data))
+	for _, v := range variables {
+		if v.Example != nil {
+			data[v.Name] = v.Example
+		}
+	}
+	for k, v := range req.Data {
+		data[k] = v
+	}
+
+	body, err := s.render(parsed, data)
+	if err != nil {
+		// No preview o erro é do template ou dos dados de exemplo, não do servidor
+		return nil, apiErrors.BadRequest(fmt.Sprintf("Failed to render template: %v", err))
+	}
+
+	return &PreviewTemplateResponse{
+		Name:             tmpl.Name,
+		Version:          version,
+		HTML:             body,
+		MissingVariables: missingVariables(variables, data),
+	}, nil
+}
+
+// resolveTemplate retorna o template usado no envio em locale: para cada nome de
+// templateCandidates, do mais específico ao mais genérico, o cadastrado e depois o embutido
+func (s *service) resolveTemplate(ctx context.Context, name, locale string) (*template.Template, []TemplateVariable, error) {
+	candidates := s.templateCandidates(name, locale)
+	stored, err := s.repo.FindTemplatesByName(ctx, candidates)
+	if err != nil {
+		return nil, nil, apiErrors.InternalServerError(fmt.Errorf("failed to find email template: %w", err))
+	}
+	byName := make(map[string]EmailTemplate, len(stored))
+	for _, t := range stored {
+		byName[t.Name] = t
+	}
+
+	for _, candidate := range candidates {
+		if t, ok := byName[candidate]; ok {
+			parsed, err := template.New(t.Name).Parse(t.HTML)
+			if err != nil {
+				return nil, nil, apiErrors.InternalServerError(fmt.Errorf("failed to parse template %s: %w", t.Name, err))
+			}
+			return parsed, t.Variables, nil
+		}
+		if builtin, ok := s.templates[candidate]; ok {
+			return builtin, nil, nil
+		}
+	}
+	return nil, nil, apiErrors.BadRequest(fmt.Sprintf("Template '%s' not found", name))
+}
+
+// render executa o template com data e as variáveis padrão (Year, AppName)
+func (s *service) render(tmpl *template.Template, data map[string]interface{}) (string, error) {
+	values := make(map[string]interface{}, len(data)+2)
+	for k, v := range data {
+		values[k] = v
+	}
+	values["Year"] = time.Now().Year()
+	values["AppName"] = s.cfg.App.Name
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, values); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
+
+// validateTemplate verifica se html é um template válido e se o esquema de variáveis é consistente
+func validateTemplate(name, html string, variables []TemplateVariable) error {
+	if strings.TrimSpace(html) == "" {
+		return apiErrors.BadRequest("Template HTML must not be empty")
+	}
+	if _, err := template.New(name).Parse(html); err != nil {
+		return apiErrors.BadRequest(fmt.Sprintf("Invalid template: %v", err))
+	}
+
+	seen := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		switch {
+		case !variableNamePattern.MatchString(v.Name):
+			return apiErrors.BadRequest(fmt.Sprintf("Invalid variable name '%s'", v.Name))
+		case reservedVariables[v.Name]:
+			return apiErrors.BadRequest(fmt.Sprintf("Variable '%s' is filled in automatically and cannot be declared", v.Name))
+		case seen[v.Name]:
+			return apiErrors.BadRequest(fmt.Sprintf("Variable '%s' is declared more than once", v.Name))
+		case v.Example != nil && !variableTypeMatches(v.Type, v.Example):
+			return apiErrors.BadRequest(fmt.Sprintf("Example of variable '%s' is not a %s", v.Name, v.Type))
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
+
+// validateTemplateData verifica os dados de um envio contra o esquema de variáveis do template
+func validateTemplateData(variables []TemplateVariable, data map[string]interface{}) error {
+	if missing := missingVariables(variables, data); len(missing) > 0 {
+		return apiErrors.BadRequest(fmt.Sprintf("Missing template variables: %s", strings.Join(missing, ", ")))
+	}
+	for _, v := range variables {
+		if value, ok := data[v.Name]; ok && value != nil && !variableTypeMatches(v.Type, value) {
+			return apiErrors.BadRequest(fmt.Sprintf("Template variable '%s' must be a %s", v.Name, v.Type))
+		}
+	}
+	return nil
+}
+
+// missingVariables retorna, em ordem alfabética, as variáveis obrigatórias sem valor em data
+func missingVariables(variables []TemplateVariable, data map[string]interface{}) []string {
+	var missing []string
+	for _, v := range variables {
+		if value, ok := data[v.Name]; v.Required && (!ok || value == nil) {
+			missing = append(missing, v.Name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// variableTypeMatches informa se value é do tipo declarado. Um tipo vazio aceita qualquer valor.
+func variableTypeMatches(typ string, value interface{}) bool {
+	kind := reflect.Indirect(reflect.ValueOf(value)).Kind()
+	switch typ {
+	case VariableString:
+		return kind == reflect.String
+	case VariableNumber:
+		return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+	case VariableBoolean:
+		return kind == reflect.Bool
+	case VariableList:
+		return kind == reflect.Slice || kind == reflect.Array
+	case VariableObject:
+		return kind == reflect.Map || kind == reflect.Struct
+	default:
+		return true
+	}
+}
+
+// templateLookupError converte a falha da busca de um template por ID
+func templateLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apiErrors.NotFound("Template not found")
+	}
+	return apiErrors.InternalServerError(fmt.Errorf("failed to find email template: %w", err))
+}
@@ -0,0 +1,174 @@
+package email
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+func assertAPIStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var apiErr *apiErrors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, status, apiErr.Status)
+}
+
+func proposalTemplate() *CreateEmailTemplateRequest {
+	return &CreateEmailTemplateRequest{
+		Name: "proposal",
+		HTML: `<p>Olá {{.Name}}, sua proposta de {{.Amount}} foi recebida.</p>`,
+		Variables: []TemplateVariable{
+			{Name: "Name", Type: VariableString, Required: true, Example: "Maria"},
+			{Name: "Amount", Type: VariableNumber, Required: true},
+		},
+	}
+}
+
+func TestService_EmailTemplateCRUD(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newQueueService(t, nil)
+
+	tmpl, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+	assert.Equal(t, 1, tmpl.Version)
+
+	_, err = svc.CreateEmailTemplate(ctx, proposalTemplate())
+	assertAPIStatus(t, err, http.StatusConflict)
+
+	invalid := proposalTemplate()
+	invalid.Name = "Proposta Nova"
+	_, err = svc.CreateEmailTemplate(ctx, invalid)
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	invalid = proposalTemplate()
+	invalid.Name = "broken"
+	invalid.HTML = "{{.Name"
+	_, err = svc.CreateEmailTemplate(ctx, invalid)
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	invalid = proposalTemplate()
+	invalid.Name = "reserved"
+	invalid.Variables = []TemplateVariable{{Name: "Year"}}
+	_, err = svc.CreateEmailTemplate(ctx, invalid)
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	html := `<p>{{.Name}}: {{.Amount}}</p>`
+	updated, err := svc.UpdateEmailTemplate(ctx, tmpl.ID, &UpdateEmailTemplateRequest{HTML: &html})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, html, updated.HTML)
+	assert.Len(t, updated.Variables, 2, "omitted fields are kept")
+
+	versions, err := svc.ListEmailTemplateVersions(ctx, tmpl.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, proposalTemplate().HTML, versions[1].HTML)
+
+	list, err := svc.ListEmailTemplates(ctx, &EmailTemplateListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), list.Total)
+
+	require.NoError(t, svc.DeleteEmailTemplate(ctx, tmpl.ID))
+	_, err = svc.GetEmailTemplate(ctx, tmpl.ID)
+	assertAPIStatus(t, err, http.StatusNotFound)
+	assertAPIStatus(t, svc.DeleteEmailTemplate(ctx, tmpl.ID), http.StatusNotFound)
+}
+
+func TestRepository_UpdateTemplate_StaleVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupTestDB(t))
+
+	tmpl := &EmailTemplate{Name: "proposal", HTML: "<p>v1</p>"}
+	require.NoError(t, repo.CreateTemplate(ctx, tmpl))
+
+	stale := *tmpl
+	tmpl.HTML = "<p>v2</p>"
+	require.NoError(t, repo.UpdateTemplate(ctx, tmpl))
+	assert.Equal(t, 2, tmpl.Version)
+
+	stale.HTML = "<p>outra</p>"
+	assert.ErrorIs(t, repo.UpdateTemplate(ctx, &stale), ErrTemplateVersionConflict)
+}
+
+func TestService_PreviewEmailTemplate(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newQueueService(t, nil)
+
+	tmpl, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+
+	preview, err := svc.PreviewEmailTemplate(ctx, tmpl.ID, &PreviewTemplateRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, preview.HTML, "Olá Maria", "examples fill in missing data")
+	assert.Equal(t, []string{"Amount"}, preview.MissingVariables)
+
+	preview, err = svc.PreviewEmailTemplate(ctx, tmpl.ID, &PreviewTemplateRequest{Data: map[string]interface{}{"Name": "João", "Amount": 1500}})
+	require.NoError(t, err)
+	assert.Contains(t, preview.HTML, "Olá João, sua proposta de 1500")
+	assert.Empty(t, preview.MissingVariables)
+
+	html := `<p>Nova versão</p>`
+	_, err = svc.UpdateEmailTemplate(ctx, tmpl.ID, &UpdateEmailTemplateRequest{HTML: &html})
+	require.NoError(t, err)
+
+	preview, err = svc.PreviewEmailTemplate(ctx, tmpl.ID, &PreviewTemplateRequest{Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, preview.Version)
+	assert.Contains(t, preview.HTML, "Olá Maria")
+
+	_, err = svc.PreviewEmailTemplate(ctx, tmpl.ID, &PreviewTemplateRequest{Version: 9})
+	assertAPIStatus(t, err, http.StatusNotFound)
+}
+
+func TestService_SendTemplateEmail_StoredTemplates(t *testing.T) {
+	ctx := context.Background()
+	var sent []*mail.Msg
+	svc, db := newQueueService(t, func(ctx context.Context, msg *mail.Msg) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	send := func(name string, data map[string]interface{}) (*EmailResponse, error) {
+		return svc.SendTemplateEmail(ctx, &SendTemplateEmailRequest{
+			To:           []string{"cliente@example.com"},
+			Subject:      "Proposta",
+			TemplateName: name,
+			TemplateData: data,
+		})
+	}
+
+	_, err := svc.CreateEmailTemplate(ctx, proposalTemplate())
+	require.NoError(t, err)
+
+	resp, err := send("proposal", map[string]interface{}{"Name": "Maria", "Amount": 1500})
+	require.NoError(t, err)
+	assert.Contains(t, outboxEmail(t, db, resp.ID).Body, "Olá Maria, sua proposta de 1500")
+
+	_, err = send("proposal", map[string]interface{}{"Name": "Maria"})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	_, err = send("proposal", map[string]interface{}{"Name": "Maria", "Amount": "mil"})
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	_, err = send("unknown", nil)
+	assertAPIStatus(t, err, http.StatusBadRequest)
+
+	// A stored template replaces the built-in one of the same name until it is deleted
+	welcome, err := svc.CreateEmailTemplate(ctx, &CreateEmailTemplateRequest{Name: "welcome", HTML: "<p>Bem-vindo à {{.AppName}}</p>"})
+	require.NoError(t, err)
+	resp, err = send("welcome", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Bem-vindo à Triiio</p>", outboxEmail(t, db, resp.ID).Body)
+
+	require.NoError(t, svc.DeleteEmailTemplate(ctx, welcome.ID))
+	resp, err = send("welcome", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, "<p>Bem-vindo à Triiio</p>", outboxEmail(t, db, resp.ID).Body)
+}
@@ -0,0 +1,153 @@
+package email
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// finalDeliveryStatuses não são substituídos por um adiamento que chegue depois
+var finalDeliveryStatuses = map[string]bool{
+	LogDelivered:  true,
+	LogBounced:    true,
+	LogDropped:    true,
+	LogComplained: true,
+}
+
+// HandleDeliveryWebhook registra os eventos de entrega enviados pelo provedor e retorna quantos
+// foram aplicados. O webhook só existe para o provedor configurado, quando ele informa o status de
+// entrega e email.webhook_secret está definido; token deve ser esse segredo.
+func (s *service) HandleDeliveryWebhook(ctx context.Context, provider, token string, body []byte) (*WebhookResponse, error) {
+	receiver, ok := s.provider.(WebhookReceiver)
+	if !ok || provider != s.provider.Name() || s.cfg.Email.WebhookSecret == "" {
+		return nil, apiErrors.NotFound("Webhook not found")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Email.WebhookSecret)) != 1 {
+		return nil, apiErrors.Unauthorized("Invalid webhook token")
+	}
+
+	events, err := receiver.ParseWebhook(ctx, body)
+	if err != nil {
+		return nil, apiErrors.BadRequest(err.Error())
+	}
+
+	processed := 0
+	for _, event := range events {
+		applied, err := s.applyDeliveryEvent(ctx, event)
+		if err != nil {
+			// O provedor reenvia a chamada inteira; os eventos já aplicados só geram linhas repetidas no log
+			return nil, apiErrors.InternalServerError(err)
+		}
+		if applied {
+			processed++
+		}
+	}
+	return &WebhookResponse{Processed: processed}, nil
+}
+
+// applyDeliveryEvent grava o evento no log do email e atualiza o status de entrega dele; devoluções
+// permanentes e reclamações incluem os destinatários na lista de supressão. Eventos de emails
+// desconhecidos (enviados por outro sistema com a mesma conta) são ignorados.
+func (s *service) applyDeliveryEvent(ctx context.Context, event DeliveryEvent) (bool, error) {
+	var queued *OutboxEmail
+	var err error
+	switch {
+	case event.EmailID != 0:
+		queued, err = s.repo.FindByID(ctx, event.EmailID)
+	case event.ProviderMessageID != "":
+		queued, err = s.repo.FindByProviderMessageID(ctx, s.provider.Name(), event.ProviderMessageID)
+	default:
+		return false, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.Debug("Ignoring delivery event of unknown email", "provider", s.provider.Name(),
+			"email_id", event.EmailID, "provider_message_id", event.ProviderMessageID, "status", event.Status)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to find email of delivery event: %w", err)
+	}
+
+	at := event.OccurredAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if event.Status != LogDeferred || !finalDeliveryStatuses[queued.DeliveryStatus] {
+		if err := s.repo.SetDeliveryStatus(ctx, queued.ID, event.Status, at); err != nil {
+			return false, fmt.Errorf("failed to update delivery status: %w", err)
+		}
+	}
+
+	entry := &EmailLog{
+		EmailID:           queued.ID,
+		To:                queued.To,
+		Cc:                queued.Cc,
+		Bcc:               queued.Bcc,
+		Subject:           queued.Subject,
+		TemplateName:      queued.TemplateName,
+		Status:            event.Status,
+		Error:             deliveryEventDetail(event),
+		MessageID:         queued.MessageID,
+		Provider:          queued.Provider,
+		ProviderMessageID: queued.ProviderMessageID,
+		Attempt:           queued.Attempts,
+		CreatedAt:         at,
+	}
+	if err := s.repo.AddLog(ctx, entry); err != nil {
+		return false, fmt.Errorf("failed to write email log: %w", err)
+	}
+	if err := s.suppressDeliveryEvent(ctx, queued, event); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deliveryEventDetail descreve o destinatário e o motivo de um evento para o campo error do log
+func deliveryEventDetail(event DeliveryEvent) string {
+	parts := make([]string, 0, 2)
+	if event.Recipient != "" {
+		parts = append(parts, event.Recipient)
+	}
+	if event.Reason != "" {
+		parts = append(parts, event.Reason)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// GetEmail retorna o status de envio e de entrega de um email da fila
+func (s *service) GetEmail(ctx context.Context, id uint) (*EmailResponse, error) {
+	queued, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apiErrors.NotFound("Email not found")
+		}
+		return nil, apiErrors.InternalServerError(fmt.Errorf("failed to find email: %w", err))
+	}
+
+	message := "Email queued for delivery"
+	switch queued.Status {
+	case OutboxSent:
+		message = "Email sent"
+	case OutboxDead:
+		message = "Email could not be sent: " + queued.LastError
+	}
+	return &EmailResponse{
+		Success:           queued.Status != OutboxDead,
+		ID:                queued.ID,
+		MessageID:         queued.MessageID,
+		Provider:          queued.Provider,
+		ProviderMessageID: queued.ProviderMessageID,
+		Status:            strings.ToLower(queued.Status),
+		DeliveryStatus:    strings.ToLower(queued.DeliveryStatus),
+		SentTo:            queued.To,
+		Message:           message,
+	}, nil
+}
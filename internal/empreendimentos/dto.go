@@ -0,0 +1,78 @@
+package empreendimentos
+
+import "github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+
+// CreateEmpreendimentoRequest represents enterprise creation request
+type CreateEmpreendimentoRequest struct {
+	Titulo          string `json:"titulo" binding:"required,min=3,max=255"`
+	Descricao       string `json:"descricao" binding:"required,min=10,max=5000"`
+	DataEntrega     string `json:"data_entrega" binding:"omitempty,datetime=2006-01-02"`
+	EtapaLancamento string `json:"etapa_lancamento" binding:"omitempty,oneof=LANCAMENTO PRE_LANCAMENTO PRONTO EM_CONSTRUCAO"`
+	Finalidade      string `json:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
+	Tipo            string `json:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
+	Status          string `json:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
+	Localizacao     string `json:"localizacao" binding:"omitempty,max=255"`
+	EnderecoID      uint   `json:"endereco_id" binding:"omitempty"`
+}
+
+// UpdateEmpreendimentoRequest represents enterprise update request; empty fields are left untouched
+type UpdateEmpreendimentoRequest struct {
+	Titulo          string `json:"titulo" binding:"omitempty,min=3,max=255"`
+	Descricao       string `json:"descricao" binding:"omitempty,min=10,max=5000"`
+	DataEntrega     string `json:"data_entrega" binding:"omitempty,datetime=2006-01-02"`
+	EtapaLancamento string `json:"etapa_lancamento" binding:"omitempty,oneof=LANCAMENTO PRE_LANCAMENTO PRONTO EM_CONSTRUCAO"`
+	Finalidade      string `json:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
+	Tipo            string `json:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
+	Status          string `json:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
+	Localizacao     string `json:"localizacao" binding:"omitempty,max=255"`
+	EnderecoID      *uint  `json:"endereco_id" binding:"omitempty"`
+}
+
+// EmpreendimentoListQuery represents query parameters for listing enterprises
+type EmpreendimentoListQuery struct {
+	Page            int    `form:"page,default=1" binding:"min=1"`
+	Limit           int    `form:"limit,default=10" binding:"min=1,max=100"`
+	EtapaLancamento string `form:"etapa_lancamento" binding:"omitempty,oneof=LANCAMENTO PRE_LANCAMENTO PRONTO EM_CONSTRUCAO"`
+	Cidade          string `form:"cidade" binding:"omitempty,max=100"`
+	Status          string `form:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
+}
+
+// EmpreendimentoListResponse represents paginated enterprise list response
+type EmpreendimentoListResponse struct {
+	Total   int64                            `json:"total"`
+	Page    int                              `json:"page"`
+	Limit   int                              `json:"limit"`
+	Pages   int64                            `json:"pages"`
+	HasNext bool                             `json:"hasNext"`
+	HasPrev bool                             `json:"hasPrev"`
+	Results []imoveis.EmpreendimentoResponse `json:"results"`
+}
+
+// TorreRequest represents tower creation and update request
+type TorreRequest struct {
+	Nome            string `json:"nome" binding:"required,min=1,max=100"`
+	TotalColunas    int    `json:"totalColunas" binding:"min=0"`
+	TotalElevadores int    `json:"totalElevadores" binding:"min=0"`
+	TotalPavimentos int    `json:"totalPavimentos" binding:"min=0"`
+	TotalUnidades   int    `json:"totalUnidades" binding:"min=0"`
+}
+
+// PlantaRequest represents floor plan creation and update request
+type PlantaRequest struct {
+	Nome     string  `json:"nome" binding:"required,min=1,max=100"`
+	Metragem float64 `json:"metragem" binding:"required,gt=0"`
+}
+
+// AnexoRequest represents an enterprise attachment; set planta_id to attach it to one of its floor plans
+type AnexoRequest struct {
+	Nome          string `json:"nome" binding:"required,max=255"`
+	URL           string `json:"url" binding:"required,url"`
+	Path          string `json:"path" binding:"omitempty,max=500"`
+	Tipo          string `json:"tipo" binding:"omitempty,max=100"`
+	Tamanho       int64  `json:"tamanho" binding:"min=0"`
+	CanPublish    bool   `json:"canPublish"`
+	Image         bool   `json:"image"`
+	Video         bool   `json:"video"`
+	IsExternalURL bool   `json:"isExternalUrl"`
+	PlantaID      *uint  `json:"planta_id" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,525 @@
+package empreendimentos
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Handler defines HTTP handlers for enterprise operations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new enterprise handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type empreendimentoURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type torreURI struct {
+	ID      uint `uri:"id" binding:"required"`
+	TorreID uint `uri:"torre_id" binding:"required"`
+}
+
+type plantaURI struct {
+	ID       uint `uri:"id" binding:"required"`
+	PlantaID uint `uri:"planta_id" binding:"required"`
+}
+
+type anexoURI struct {
+	ID      uint `uri:"id" binding:"required"`
+	AnexoID uint `uri:"anexo_id" binding:"required"`
+}
+
+// @Summary Create enterprise
+// @Description Create a new empreendimento
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateEmpreendimentoRequest true "Enterprise creation request"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.EmpreendimentoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos [post]
+func (h *Handler) CreateEmpreendimento(c *gin.Context) {
+	var req CreateEmpreendimentoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	empreendimento, err := h.service.CreateEmpreendimento(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(empreendimento))
+}
+
+// @Summary List enterprises
+// @Description List empreendimentos with pagination and filters
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param etapa_lancamento query string false "Launch stage (LANCAMENTO, PRE_LANCAMENTO, PRONTO, EM_CONSTRUCAO)"
+// @Param cidade query string false "City of the enterprise address (case-insensitive)"
+// @Param status query string false "Status (PUBLICADO, EM_EDICAO, ARQUIVADO)"
+// @Success 200 {object} errors.Response{success=bool,data=EmpreendimentoListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos [get]
+func (h *Handler) ListEmpreendimentos(c *gin.Context) {
+	var query EmpreendimentoListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListEmpreendimentos(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get enterprise by ID
+// @Description Retrieve an empreendimento with its address, torres, plantas, caracteristicas and anexos
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Param id path uint true "Enterprise ID"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.EmpreendimentoResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id} [get]
+func (h *Handler) GetEmpreendimento(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	empreendimento, err := h.service.GetEmpreendimento(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(empreendimento))
+}
+
+// @Summary Update enterprise
+// @Description Update the provided fields of an empreendimento; endereco_id 0 removes the address
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param request body UpdateEmpreendimentoRequest true "Enterprise update request"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.EmpreendimentoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id} [put]
+func (h *Handler) UpdateEmpreendimento(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateEmpreendimentoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	empreendimento, err := h.service.UpdateEmpreendimento(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(empreendimento))
+}
+
+// @Summary Delete enterprise
+// @Description Soft delete an empreendimento
+// @Tags empreendimentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id} [delete]
+func (h *Handler) DeleteEmpreendimento(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeleteEmpreendimento(c.Request.Context(), uri.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List enterprise towers
+// @Description List the torres of an empreendimento
+// @Tags empreendimentos
+// @Produce json
+// @Param id path uint true "Enterprise ID"
+// @Success 200 {object} errors.Response{success=bool,data=[]imoveis.TorresResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/torres [get]
+func (h *Handler) ListTorres(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	torres, err := h.service.ListTorres(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(torres))
+}
+
+// @Summary Add tower to enterprise
+// @Description Create a torre in an empreendimento
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param request body TorreRequest true "Tower data"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.TorresResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/torres [post]
+func (h *Handler) AddTorre(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req TorreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	torre, err := h.service.AddTorre(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(torre))
+}
+
+// @Summary Update enterprise tower
+// @Description Replace the data of a torre
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param torre_id path uint true "Tower ID"
+// @Param request body TorreRequest true "Tower data"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.TorresResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/torres/{torre_id} [put]
+func (h *Handler) UpdateTorre(c *gin.Context) {
+	var uri torreURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req TorreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	torre, err := h.service.UpdateTorre(c.Request.Context(), uri.ID, uri.TorreID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(torre))
+}
+
+// @Summary Delete enterprise tower
+// @Description Remove a torre from an empreendimento
+// @Tags empreendimentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param torre_id path uint true "Tower ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/torres/{torre_id} [delete]
+func (h *Handler) DeleteTorre(c *gin.Context) {
+	var uri torreURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeleteTorre(c.Request.Context(), uri.ID, uri.TorreID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List enterprise floor plans
+// @Description List the plantas of an empreendimento with their anexos
+// @Tags empreendimentos
+// @Produce json
+// @Param id path uint true "Enterprise ID"
+// @Success 200 {object} errors.Response{success=bool,data=[]imoveis.PlantaResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/plantas [get]
+func (h *Handler) ListPlantas(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	plantas, err := h.service.ListPlantas(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(plantas))
+}
+
+// @Summary Add floor plan to enterprise
+// @Description Create a planta in an empreendimento
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param request body PlantaRequest true "Floor plan data"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.PlantaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/plantas [post]
+func (h *Handler) AddPlanta(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PlantaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	planta, err := h.service.AddPlanta(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(planta))
+}
+
+// @Summary Update enterprise floor plan
+// @Description Replace the data of a planta
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param planta_id path uint true "Floor plan ID"
+// @Param request body PlantaRequest true "Floor plan data"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PlantaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/plantas/{planta_id} [put]
+func (h *Handler) UpdatePlanta(c *gin.Context) {
+	var uri plantaURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PlantaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	planta, err := h.service.UpdatePlanta(c.Request.Context(), uri.ID, uri.PlantaID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(planta))
+}
+
+// @Summary Delete enterprise floor plan
+// @Description Remove a planta from an empreendimento
+// @Tags empreendimentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param planta_id path uint true "Floor plan ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/plantas/{planta_id} [delete]
+func (h *Handler) DeletePlanta(c *gin.Context) {
+	var uri plantaURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeletePlanta(c.Request.Context(), uri.ID, uri.PlantaID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List enterprise attachments
+// @Description List the anexos of an empreendimento, including those of its plantas
+// @Tags empreendimentos
+// @Produce json
+// @Param id path uint true "Enterprise ID"
+// @Success 200 {object} errors.Response{success=bool,data=[]imoveis.AnexoResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/anexos [get]
+func (h *Handler) ListAnexos(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	anexos, err := h.service.ListAnexos(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(anexos))
+}
+
+// @Summary Add attachment to enterprise
+// @Description Attach a file to an empreendimento, or to one of its plantas when planta_id is set
+// @Tags empreendimentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param request body AnexoRequest true "Attachment data"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.AnexoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/anexos [post]
+func (h *Handler) AddAnexo(c *gin.Context) {
+	var uri empreendimentoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req AnexoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	anexo, err := h.service.AddAnexo(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(anexo))
+}
+
+// @Summary Delete enterprise attachment
+// @Description Remove an anexo from an empreendimento
+// @Tags empreendimentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Enterprise ID"
+// @Param anexo_id path uint true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/anexos/{anexo_id} [delete]
+func (h *Handler) DeleteAnexo(c *gin.Context) {
+	var uri anexoURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeleteAnexo(c.Request.Context(), uri.ID, uri.AnexoID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEmpreendimentoNotFound):
+		_ = c.Error(apiErrors.NotFound("Empreendimento not found"))
+	case errors.Is(err, ErrTorreNotFound):
+		_ = c.Error(apiErrors.NotFound("Torre not found"))
+	case errors.Is(err, ErrPlantaNotFound):
+		_ = c.Error(apiErrors.NotFound("Planta not found"))
+	case errors.Is(err, ErrAnexoNotFound):
+		_ = c.Error(apiErrors.NotFound("Anexo not found"))
+	case errors.Is(err, imoveis.ErrForbidden):
+		_ = c.Error(apiErrors.Forbidden("Empreendimento has imoveis of another corretor"))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
@@ -0,0 +1,108 @@
+package empreendimentos
+
+import "github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+
+// toEmpreendimentoResponse converts an enterprise and its loaded relations to the API shape
+// shared with the empreendimento nested in property responses
+func toEmpreendimentoResponse(e *imoveis.Empreendimento) *imoveis.EmpreendimentoResponse {
+	response := &imoveis.EmpreendimentoResponse{
+		ID:              e.ID,
+		Titulo:          e.Titulo,
+		Descricao:       e.Descricao,
+		DataEntrega:     e.DataEntrega,
+		EtapaLancamento: e.EtapaLancamento,
+		Finalidade:      e.Finalidade,
+		Tipo:            e.Tipo,
+		Status:          e.Status,
+		Localizacao:     e.Localizacao,
+		Anexos:          toAnexoResponses(e.Anexos),
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+
+	if e.Endereco != nil {
+		response.Endereco = &imoveis.EnderecoResponse{
+			ID:        e.Endereco.ID,
+			Rua:       e.Endereco.Rua,
+			Numero:    e.Endereco.Numero,
+			Bairro:    e.Endereco.Bairro,
+			Cidade:    e.Endereco.Cidade,
+			Estado:    e.Endereco.Estado,
+			CEP:       e.Endereco.CEP,
+			Latitude:  e.Endereco.Latitude,
+			Longitude: e.Endereco.Longitude,
+		}
+	}
+
+	for i := range e.Torres {
+		response.Torres = append(response.Torres, toTorreResponse(&e.Torres[i]))
+	}
+	for i := range e.Plantas {
+		response.Plantas = append(response.Plantas, toPlantaResponse(&e.Plantas[i]))
+	}
+	for _, c := range e.Caracteristicas {
+		response.Caracteristicas = append(response.Caracteristicas, imoveis.CaracteristicaResponse{
+			ID:            c.ID,
+			Nome:          c.Nome,
+			CategoriaID:   c.CategoriaID,
+			CategoriaNome: c.CategoriaNome,
+			CreatedAt:     c.CreatedAt,
+			UpdatedAt:     c.UpdatedAt,
+		})
+	}
+
+	return response
+}
+
+func toTorreResponse(t *imoveis.Torres) imoveis.TorresResponse {
+	return imoveis.TorresResponse{
+		ID:              t.ID,
+		Nome:            t.Nome,
+		TotalColunas:    t.TotalColunas,
+		TotalElevadores: t.TotalElevadores,
+		TotalPavimentos: t.TotalPavimentos,
+		TotalUnidades:   t.TotalUnidades,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
+func toPlantaResponse(p *imoveis.Plantas) imoveis.PlantaResponse {
+	return imoveis.PlantaResponse{
+		ID:        p.ID,
+		Nome:      p.Nome,
+		Metragem:  p.Metragem,
+		Anexos:    toAnexoResponses(p.Anexos),
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func toAnexoResponse(a *imoveis.Anexo) imoveis.AnexoResponse {
+	return imoveis.AnexoResponse{
+		ID:            a.ID,
+		Nome:          a.Nome,
+		Path:          a.Path,
+		Tamanho:       a.Tamanho,
+		Tipo:          a.Tipo,
+		URL:           a.URL,
+		CanPublish:    a.CanPublish,
+		Image:         a.Image,
+		Video:         a.Video,
+		IsExternalURL: a.IsExternalURL,
+		Variants:      a.Variants,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+func toAnexoResponses(anexos []imoveis.Anexo) []imoveis.AnexoResponse {
+	if len(anexos) == 0 {
+		return nil
+	}
+	responses := make([]imoveis.AnexoResponse, len(anexos))
+	for i := range anexos {
+		responses[i] = toAnexoResponse(&anexos[i])
+	}
+	return responses
+}
@@ -0,0 +1,278 @@
+package empreendimentos
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines enterprise repository interface
+type Repository interface {
+	Create(ctx context.Context, empreendimento *imoveis.Empreendimento) error
+	FindByID(ctx context.Context, id uint) (*imoveis.Empreendimento, error)
+	Exists(ctx context.Context, id uint) (bool, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+	FindOwners(ctx context.Context, id uint) ([]imoveis.PropertyOwner, error)
+	List(ctx context.Context, query *EmpreendimentoListQuery) ([]imoveis.Empreendimento, int64, error)
+
+	// Torres
+	ListTorres(ctx context.Context, empreendimentoID uint) ([]imoveis.Torres, error)
+	FindTorre(ctx context.Context, empreendimentoID, torreID uint) (*imoveis.Torres, error)
+	SaveTorre(ctx context.Context, torre *imoveis.Torres) error
+	DeleteTorre(ctx context.Context, empreendimentoID, torreID uint) error
+
+	// Plantas
+	ListPlantas(ctx context.Context, empreendimentoID uint) ([]imoveis.Plantas, error)
+	FindPlanta(ctx context.Context, empreendimentoID, plantaID uint) (*imoveis.Plantas, error)
+	SavePlanta(ctx context.Context, planta *imoveis.Plantas) error
+	DeletePlanta(ctx context.Context, empreendimentoID, plantaID uint) error
+
+	// Anexos
+	ListAnexos(ctx context.Context, empreendimentoID uint) ([]imoveis.Anexo, error)
+	CreateAnexo(ctx context.Context, anexo *imoveis.Anexo) error
+	DeleteAnexo(ctx context.Context, empreendimentoID, anexoID uint) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new enterprise repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create creates a new enterprise, omitting empty date/enum columns, the address foreign key
+// and the integration ID when unset to avoid type and constraint errors
+func (r *repository) Create(ctx context.Context, empreendimento *imoveis.Empreendimento) error {
+	var omitFields []string
+	if empreendimento.IdIntegracao == "" {
+		omitFields = append(omitFields, "IdIntegracao")
+	}
+	if empreendimento.DataEntrega == "" {
+		omitFields = append(omitFields, "DataEntrega")
+	}
+	if empreendimento.EtapaLancamento == "" {
+		omitFields = append(omitFields, "EtapaLancamento")
+	}
+	if empreendimento.EnderecoID == 0 {
+		omitFields = append(omitFields, "EnderecoID")
+	}
+
+	db := r.db.WithContext(ctx)
+	if len(omitFields) > 0 {
+		db = db.Omit(omitFields...)
+	}
+	return db.Create(empreendimento).Error
+}
+
+// FindByID finds an enterprise by ID with its address, towers, floor plans, characteristics and attachments
+func (r *repository) FindByID(ctx context.Context, id uint) (*imoveis.Empreendimento, error) {
+	var empreendimento imoveis.Empreendimento
+	err := r.db.WithContext(ctx).
+		Preload("Endereco").
+		Preload("Torres", orderByID).
+		Preload("Plantas", orderByID).
+		Preload("Plantas.Anexos").
+		Preload("Caracteristicas").
+		Preload("Anexos", "planta_id IS NULL").
+		First(&empreendimento, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &empreendimento, nil
+}
+
+// FindOwners returns the corretor and organizacao of every property of the enterprise,
+// including the properties outside the organizacao of ctx
+func (r *repository) FindOwners(ctx context.Context, id uint) ([]imoveis.PropertyOwner, error) {
+	var owners []imoveis.PropertyOwner
+	// WHY: Raw is not filtered by db.TenantScope, so enterprises shared with another tenant are found
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT i.corretor_principal_id, COALESCE(c.organizacao_id, 0) AS organizacao_id
+		FROM imoveis i
+		LEFT JOIN corretores_principais c ON c.id = i.corretor_principal_id
+		WHERE i.empreendimento_id = ? AND i.deleted_at IS NULL`, id).Scan(&owners).Error; err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// Exists reports whether a non-deleted enterprise exists
+func (r *repository) Exists(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&imoveis.Empreendimento{}).
+		Where("id = ?", id).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Update updates the given columns of an enterprise
+func (r *repository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&imoveis.Empreendimento{ID: id}).
+		Updates(updates).Error
+}
+
+// Delete soft deletes an enterprise
+func (r *repository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&imoveis.Empreendimento{}, id).Error
+}
+
+// List retrieves enterprises with filters and pagination
+func (r *repository) List(ctx context.Context, query *EmpreendimentoListQuery) ([]imoveis.Empreendimento, int64, error) {
+	var empreendimentos []imoveis.Empreendimento
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&imoveis.Empreendimento{})
+	if query.EtapaLancamento != "" {
+		db = db.Where("empreendimentos.etapa_lancamento = ?", query.EtapaLancamento)
+	}
+	if query.Status != "" {
+		db = db.Where("empreendimentos.status = ?", query.Status)
+	}
+	if query.Cidade != "" {
+		db = db.Joins("JOIN enderecos ON enderecos.id = empreendimentos.endereco_id").
+			Where("LOWER(enderecos.cidade) = LOWER(?)", query.Cidade)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	if err := db.
+		Preload("Endereco").
+		Order("empreendimentos.created_at DESC").
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&empreendimentos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return empreendimentos, total, nil
+}
+
+// ListTorres retrieves the towers of an enterprise
+func (r *repository) ListTorres(ctx context.Context, empreendimentoID uint) ([]imoveis.Torres, error) {
+	var torres []imoveis.Torres
+	if err := r.db.WithContext(ctx).
+		Where("empreendimento_id = ?", empreendimentoID).
+		Order("id ASC").
+		Find(&torres).Error; err != nil {
+		return nil, err
+	}
+	return torres, nil
+}
+
+// FindTorre finds a tower that belongs to the given enterprise
+func (r *repository) FindTorre(ctx context.Context, empreendimentoID, torreID uint) (*imoveis.Torres, error) {
+	var torre imoveis.Torres
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND empreendimento_id = ?", torreID, empreendimentoID).
+		First(&torre).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &torre, nil
+}
+
+// SaveTorre creates or updates a tower
+func (r *repository) SaveTorre(ctx context.Context, torre *imoveis.Torres) error {
+	return r.db.WithContext(ctx).Omit("Empreendimento").Save(torre).Error
+}
+
+// DeleteTorre soft deletes a tower of an enterprise
+func (r *repository) DeleteTorre(ctx context.Context, empreendimentoID, torreID uint) error {
+	return r.db.WithContext(ctx).
+		Where("empreendimento_id = ?", empreendimentoID).
+		Delete(&imoveis.Torres{}, torreID).Error
+}
+
+// ListPlantas retrieves the floor plans of an enterprise with their attachments
+func (r *repository) ListPlantas(ctx context.Context, empreendimentoID uint) ([]imoveis.Plantas, error) {
+	var plantas []imoveis.Plantas
+	if err := r.db.WithContext(ctx).
+		Preload("Anexos").
+		Where("empreendimento_id = ?", empreendimentoID).
+		Order("id ASC").
+		Find(&plantas).Error; err != nil {
+		return nil, err
+	}
+	return plantas, nil
+}
+
+// FindPlanta finds a floor plan that belongs to the given enterprise
+func (r *repository) FindPlanta(ctx context.Context, empreendimentoID, plantaID uint) (*imoveis.Plantas, error) {
+	var planta imoveis.Plantas
+	if err := r.db.WithContext(ctx).
+		Preload("Anexos").
+		Where("id = ? AND empreendimento_id = ?", plantaID, empreendimentoID).
+		First(&planta).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &planta, nil
+}
+
+// SavePlanta creates or updates a floor plan
+func (r *repository) SavePlanta(ctx context.Context, planta *imoveis.Plantas) error {
+	return r.db.WithContext(ctx).Omit("Anexos").Save(planta).Error
+}
+
+// DeletePlanta soft deletes a floor plan of an enterprise
+func (r *repository) DeletePlanta(ctx context.Context, empreendimentoID, plantaID uint) error {
+	return r.db.WithContext(ctx).
+		Where("empreendimento_id = ?", empreendimentoID).
+		Delete(&imoveis.Plantas{}, plantaID).Error
+}
+
+// ListAnexos retrieves all attachments of an enterprise, including those of its floor plans
+func (r *repository) ListAnexos(ctx context.Context, empreendimentoID uint) ([]imoveis.Anexo, error) {
+	var anexos []imoveis.Anexo
+	if err := r.db.WithContext(ctx).
+		Where("empreendimento_id = ?", empreendimentoID).
+		Order("id ASC").
+		Find(&anexos).Error; err != nil {
+		return nil, err
+	}
+	return anexos, nil
+}
+
+// CreateAnexo creates an enterprise attachment, omitting unset foreign keys
+func (r *repository) CreateAnexo(ctx context.Context, anexo *imoveis.Anexo) error {
+	omitFields := []string{"ImovelID"}
+	if anexo.PlantaID == nil {
+		omitFields = append(omitFields, "PlantaID")
+	}
+	return r.db.WithContext(ctx).Omit(omitFields...).Create(anexo).Error
+}
+
+// DeleteAnexo soft deletes an attachment of an enterprise and reports whether it existed
+func (r *repository) DeleteAnexo(ctx context.Context, empreendimentoID, anexoID uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("empreendimento_id = ?", empreendimentoID).
+		Delete(&imoveis.Anexo{}, anexoID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
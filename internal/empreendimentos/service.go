@@ -0,0 +1,413 @@
+package empreendimentos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrEmpreendimentoNotFound is returned when an enterprise does not exist
+	ErrEmpreendimentoNotFound = errors.New("empreendimento not found")
+	// ErrTorreNotFound is returned when a tower does not exist in the enterprise
+	ErrTorreNotFound = errors.New("torre not found")
+	// ErrPlantaNotFound is returned when a floor plan does not exist in the enterprise
+	ErrPlantaNotFound = errors.New("planta not found")
+	// ErrAnexoNotFound is returned when an attachment does not exist in the enterprise
+	ErrAnexoNotFound = errors.New("anexo not found")
+)
+
+// Service defines enterprise service interface
+type Service interface {
+	CreateEmpreendimento(ctx context.Context, req *CreateEmpreendimentoRequest) (*imoveis.EmpreendimentoResponse, error)
+	GetEmpreendimento(ctx context.Context, id uint) (*imoveis.EmpreendimentoResponse, error)
+	UpdateEmpreendimento(ctx context.Context, id uint, req *UpdateEmpreendimentoRequest) (*imoveis.EmpreendimentoResponse, error)
+	DeleteEmpreendimento(ctx context.Context, id uint) error
+	ListEmpreendimentos(ctx context.Context, query *EmpreendimentoListQuery) (*EmpreendimentoListResponse, error)
+
+	// Torres
+	ListTorres(ctx context.Context, empreendimentoID uint) ([]imoveis.TorresResponse, error)
+	AddTorre(ctx context.Context, empreendimentoID uint, req *TorreRequest) (*imoveis.TorresResponse, error)
+	UpdateTorre(ctx context.Context, empreendimentoID, torreID uint, req *TorreRequest) (*imoveis.TorresResponse, error)
+	DeleteTorre(ctx context.Context, empreendimentoID, torreID uint) error
+
+	// Plantas
+	ListPlantas(ctx context.Context, empreendimentoID uint) ([]imoveis.PlantaResponse, error)
+	AddPlanta(ctx context.Context, empreendimentoID uint, req *PlantaRequest) (*imoveis.PlantaResponse, error)
+	UpdatePlanta(ctx context.Context, empreendimentoID, plantaID uint, req *PlantaRequest) (*imoveis.PlantaResponse, error)
+	DeletePlanta(ctx context.Context, empreendimentoID, plantaID uint) error
+
+	// Anexos
+	ListAnexos(ctx context.Context, empreendimentoID uint) ([]imoveis.AnexoResponse, error)
+	AddAnexo(ctx context.Context, empreendimentoID uint, req *AnexoRequest) (*imoveis.AnexoResponse, error)
+	DeleteAnexo(ctx context.Context, empreendimentoID, anexoID uint) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new enterprise service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreateEmpreendimento creates a new enterprise
+func (s *service) CreateEmpreendimento(ctx context.Context, req *CreateEmpreendimentoRequest) (*imoveis.EmpreendimentoResponse, error) {
+	empreendimento := &imoveis.Empreendimento{
+		Titulo:          req.Titulo,
+		Descricao:       req.Descricao,
+		DataEntrega:     req.DataEntrega,
+		EtapaLancamento: req.EtapaLancamento,
+		Finalidade:      req.Finalidade,
+		Tipo:            req.Tipo,
+		Status:          req.Status,
+		Localizacao:     req.Localizacao,
+		EnderecoID:      req.EnderecoID,
+	}
+
+	if err := s.repo.Create(ctx, empreendimento); err != nil {
+		return nil, fmt.Errorf("failed to create empreendimento: %w", err)
+	}
+
+	return s.GetEmpreendimento(ctx, empreendimento.ID)
+}
+
+// GetEmpreendimento retrieves an enterprise with all its relations
+func (s *service) GetEmpreendimento(ctx context.Context, id uint) (*imoveis.EmpreendimentoResponse, error) {
+	empreendimento, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find empreendimento: %w", err)
+	}
+	if empreendimento == nil {
+		return nil, ErrEmpreendimentoNotFound
+	}
+	return toEmpreendimentoResponse(empreendimento), nil
+}
+
+// UpdateEmpreendimento updates the non-empty fields of an enterprise; endereco_id 0 removes the address
+func (s *service) UpdateEmpreendimento(ctx context.Context, id uint, req *UpdateEmpreendimentoRequest) (*imoveis.EmpreendimentoResponse, error) {
+	if err := s.ensureWritable(ctx, id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	for column, value := range map[string]string{
+		"titulo":           req.Titulo,
+		"descricao":        req.Descricao,
+		"data_entrega":     req.DataEntrega,
+		"etapa_lancamento": req.EtapaLancamento,
+		"finalidade":       req.Finalidade,
+		"tipo":             req.Tipo,
+		"status":           req.Status,
+		"localizacao":      req.Localizacao,
+	} {
+		if value != "" {
+			updates[column] = value
+		}
+	}
+	if req.EnderecoID != nil {
+		if *req.EnderecoID == 0 {
+			updates["endereco_id"] = nil
+		} else {
+			updates["endereco_id"] = *req.EnderecoID
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update empreendimento: %w", err)
+		}
+	}
+
+	return s.GetEmpreendimento(ctx, id)
+}
+
+// DeleteEmpreendimento soft deletes an enterprise
+func (s *service) DeleteEmpreendimento(ctx context.Context, id uint) error {
+	if err := s.ensureWritable(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete empreendimento: %w", err)
+	}
+	return nil
+}
+
+// ListEmpreendimentos lists enterprises filtered by etapa_lancamento, cidade and status
+func (s *service) ListEmpreendimentos(ctx context.Context, query *EmpreendimentoListQuery) (*EmpreendimentoListResponse, error) {
+	empreendimentos, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list empreendimentos: %w", err)
+	}
+
+	results := make([]imoveis.EmpreendimentoResponse, len(empreendimentos))
+	for i := range empreendimentos {
+		results[i] = *toEmpreendimentoResponse(&empreendimentos[i])
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &EmpreendimentoListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// ListTorres lists the towers of an enterprise
+func (s *service) ListTorres(ctx context.Context, empreendimentoID uint) ([]imoveis.TorresResponse, error) {
+	if err := s.ensureExists(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+
+	torres, err := s.repo.ListTorres(ctx, empreendimentoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list torres: %w", err)
+	}
+
+	responses := make([]imoveis.TorresResponse, len(torres))
+	for i := range torres {
+		responses[i] = toTorreResponse(&torres[i])
+	}
+	return responses, nil
+}
+
+// AddTorre adds a tower to an enterprise
+func (s *service) AddTorre(ctx context.Context, empreendimentoID uint, req *TorreRequest) (*imoveis.TorresResponse, error) {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+
+	torre := &imoveis.Torres{EmpreendimentoID: empreendimentoID}
+	applyTorre(torre, req)
+	if err := s.repo.SaveTorre(ctx, torre); err != nil {
+		return nil, fmt.Errorf("failed to create torre: %w", err)
+	}
+
+	response := toTorreResponse(torre)
+	return &response, nil
+}
+
+// UpdateTorre replaces the data of a tower
+func (s *service) UpdateTorre(ctx context.Context, empreendimentoID, torreID uint, req *TorreRequest) (*imoveis.TorresResponse, error) {
+	torre, err := s.findTorre(ctx, empreendimentoID, torreID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTorre(torre, req)
+	if err := s.repo.SaveTorre(ctx, torre); err != nil {
+		return nil, fmt.Errorf("failed to update torre: %w", err)
+	}
+
+	response := toTorreResponse(torre)
+	return &response, nil
+}
+
+// DeleteTorre removes a tower from an enterprise
+func (s *service) DeleteTorre(ctx context.Context, empreendimentoID, torreID uint) error {
+	if _, err := s.findTorre(ctx, empreendimentoID, torreID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTorre(ctx, empreendimentoID, torreID); err != nil {
+		return fmt.Errorf("failed to delete torre: %w", err)
+	}
+	return nil
+}
+
+// ListPlantas lists the floor plans of an enterprise
+func (s *service) ListPlantas(ctx context.Context, empreendimentoID uint) ([]imoveis.PlantaResponse, error) {
+	if err := s.ensureExists(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+
+	plantas, err := s.repo.ListPlantas(ctx, empreendimentoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plantas: %w", err)
+	}
+
+	responses := make([]imoveis.PlantaResponse, len(plantas))
+	for i := range plantas {
+		responses[i] = toPlantaResponse(&plantas[i])
+	}
+	return responses, nil
+}
+
+// AddPlanta adds a floor plan to an enterprise
+func (s *service) AddPlanta(ctx context.Context, empreendimentoID uint, req *PlantaRequest) (*imoveis.PlantaResponse, error) {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+
+	planta := &imoveis.Plantas{
+		Nome:             req.Nome,
+		Metragem:         req.Metragem,
+		EmpreendimentoID: empreendimentoID,
+	}
+	if err := s.repo.SavePlanta(ctx, planta); err != nil {
+		return nil, fmt.Errorf("failed to create planta: %w", err)
+	}
+
+	response := toPlantaResponse(planta)
+	return &response, nil
+}
+
+// UpdatePlanta replaces the data of a floor plan
+func (s *service) UpdatePlanta(ctx context.Context, empreendimentoID, plantaID uint, req *PlantaRequest) (*imoveis.PlantaResponse, error) {
+	planta, err := s.findPlanta(ctx, empreendimentoID, plantaID)
+	if err != nil {
+		return nil, err
+	}
+
+	planta.Nome = req.Nome
+	planta.Metragem = req.Metragem
+	if err := s.repo.SavePlanta(ctx, planta); err != nil {
+		return nil, fmt.Errorf("failed to update planta: %w", err)
+	}
+
+	response := toPlantaResponse(planta)
+	return &response, nil
+}
+
+// DeletePlanta removes a floor plan from an enterprise
+func (s *service) DeletePlanta(ctx context.Context, empreendimentoID, plantaID uint) error {
+	if _, err := s.findPlanta(ctx, empreendimentoID, plantaID); err != nil {
+		return err
+	}
+	if err := s.repo.DeletePlanta(ctx, empreendimentoID, plantaID); err != nil {
+		return fmt.Errorf("failed to delete planta: %w", err)
+	}
+	return nil
+}
+
+// ListAnexos lists the attachments of an enterprise and its floor plans
+func (s *service) ListAnexos(ctx context.Context, empreendimentoID uint) ([]imoveis.AnexoResponse, error) {
+	if err := s.ensureExists(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+
+	anexos, err := s.repo.ListAnexos(ctx, empreendimentoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list anexos: %w", err)
+	}
+
+	responses := make([]imoveis.AnexoResponse, len(anexos))
+	for i := range anexos {
+		responses[i] = toAnexoResponse(&anexos[i])
+	}
+	return responses, nil
+}
+
+// AddAnexo attaches a file to an enterprise, or to one of its floor plans when planta_id is set
+func (s *service) AddAnexo(ctx context.Context, empreendimentoID uint, req *AnexoRequest) (*imoveis.AnexoResponse, error) {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+	if req.PlantaID != nil {
+		if _, err := s.findPlanta(ctx, empreendimentoID, *req.PlantaID); err != nil {
+			return nil, err
+		}
+	}
+
+	anexo := &imoveis.Anexo{
+		Nome:             req.Nome,
+		Path:             req.Path,
+		Tamanho:          req.Tamanho,
+		Tipo:             req.Tipo,
+		URL:              req.URL,
+		CanPublish:       req.CanPublish,
+		Image:            req.Image,
+		Video:            req.Video,
+		IsExternalURL:    req.IsExternalURL,
+		EmpreendimentoID: &empreendimentoID,
+		PlantaID:         req.PlantaID,
+	}
+	if err := s.repo.CreateAnexo(ctx, anexo); err != nil {
+		return nil, fmt.Errorf("failed to create anexo: %w", err)
+	}
+
+	response := toAnexoResponse(anexo)
+	return &response, nil
+}
+
+// DeleteAnexo removes an attachment from an enterprise
+func (s *service) DeleteAnexo(ctx context.Context, empreendimentoID, anexoID uint) error {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteAnexo(ctx, empreendimentoID, anexoID)
+	if err != nil {
+		return fmt.Errorf("failed to delete anexo: %w", err)
+	}
+	if !deleted {
+		return ErrAnexoNotFound
+	}
+	return nil
+}
+
+func (s *service) ensureExists(ctx context.Context, id uint) error {
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find empreendimento: %w", err)
+	}
+	if !exists {
+		return ErrEmpreendimentoNotFound
+	}
+	return nil
+}
+
+// ensureWritable checks that the enterprise exists and that the request may change it: none of
+// its properties belongs to another corretor or to an organizacao other than the request's
+func (s *service) ensureWritable(ctx context.Context, id uint) error {
+	if err := s.ensureExists(ctx, id); err != nil {
+		return err
+	}
+	owners, err := s.repo.FindOwners(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find properties of empreendimento: %w", err)
+	}
+	return imoveis.CheckOwnersScope(ctx, owners)
+}
+
+func (s *service) findTorre(ctx context.Context, empreendimentoID, torreID uint) (*imoveis.Torres, error) {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+	torre, err := s.repo.FindTorre(ctx, empreendimentoID, torreID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find torre: %w", err)
+	}
+	if torre == nil {
+		return nil, ErrTorreNotFound
+	}
+	return torre, nil
+}
+
+func (s *service) findPlanta(ctx context.Context, empreendimentoID, plantaID uint) (*imoveis.Plantas, error) {
+	if err := s.ensureWritable(ctx, empreendimentoID); err != nil {
+		return nil, err
+	}
+	planta, err := s.repo.FindPlanta(ctx, empreendimentoID, plantaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find planta: %w", err)
+	}
+	if planta == nil {
+		return nil, ErrPlantaNotFound
+	}
+	return planta, nil
+}
+
+func applyTorre(torre *imoveis.Torres, req *TorreRequest) {
+	torre.Nome = req.Nome
+	torre.TotalColunas = req.TotalColunas
+	torre.TotalElevadores = req.TotalElevadores
+	torre.TotalPavimentos = req.TotalPavimentos
+	torre.TotalUnidades = req.TotalUnidades
+}
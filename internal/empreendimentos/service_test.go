@@ -0,0 +1,205 @@
+package empreendimentos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+func setupService(t *testing.T) Service {
+	t.Helper()
+	svc, _ := setupServiceDB(t)
+	return svc
+}
+
+func setupServiceDB(t *testing.T) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(
+		&imoveis.Endereco{},
+		&imoveis.Caracteristica{},
+		&imoveis.Empreendimento{},
+		&imoveis.Torres{},
+		&imoveis.Plantas{},
+		&imoveis.Anexo{},
+	))
+
+	require.NoError(t, database.Create(&imoveis.Endereco{Cidade: "Curitiba"}).Error)
+	require.NoError(t, database.Create(&imoveis.Endereco{Cidade: "Londrina"}).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE corretores_principais (id INTEGER PRIMARY KEY, organizacao_id INTEGER)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY, empreendimento_id INTEGER, corretor_principal_id INTEGER, deleted_at DATETIME
+	)`).Error)
+
+	return NewService(NewRepository(database)), database
+}
+
+func createEmpreendimento(t *testing.T, svc Service, req CreateEmpreendimentoRequest) uint {
+	t.Helper()
+	if req.Descricao == "" {
+		req.Descricao = "Empreendimento de teste"
+	}
+	created, err := svc.CreateEmpreendimento(context.Background(), &req)
+	require.NoError(t, err)
+	return created.ID
+}
+
+func TestListEmpreendimentos_Filters(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Alpha", EtapaLancamento: "LANCAMENTO", Status: "PUBLICADO", EnderecoID: 1})
+	createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Beta", EtapaLancamento: "PRONTO", Status: "PUBLICADO", EnderecoID: 2})
+	createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Gamma", EtapaLancamento: "LANCAMENTO", Status: "EM_EDICAO"})
+
+	tests := []struct {
+		name   string
+		query  EmpreendimentoListQuery
+		titles []string
+	}{
+		{"no filters", EmpreendimentoListQuery{}, []string{"Gamma", "Beta", "Alpha"}},
+		{"etapa_lancamento", EmpreendimentoListQuery{EtapaLancamento: "LANCAMENTO"}, []string{"Gamma", "Alpha"}},
+		{"cidade is case-insensitive", EmpreendimentoListQuery{Cidade: "curitiba"}, []string{"Alpha"}},
+		{"status", EmpreendimentoListQuery{Status: "PUBLICADO"}, []string{"Beta", "Alpha"}},
+		{"combined", EmpreendimentoListQuery{Status: "PUBLICADO", Cidade: "Londrina"}, []string{"Beta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Page, tt.query.Limit = 1, 10
+			result, err := svc.ListEmpreendimentos(ctx, &tt.query)
+			require.NoError(t, err)
+
+			titles := make([]string, len(result.Results))
+			for i, r := range result.Results {
+				titles[i] = r.Titulo
+			}
+			assert.ElementsMatch(t, tt.titles, titles)
+			assert.Equal(t, int64(len(tt.titles)), result.Total)
+		})
+	}
+}
+
+func TestUpdateEmpreendimento(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	id := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Alpha", Status: "EM_EDICAO", EnderecoID: 1})
+
+	updated, err := svc.UpdateEmpreendimento(ctx, id, &UpdateEmpreendimentoRequest{Status: "PUBLICADO"})
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", updated.Titulo)
+	assert.Equal(t, "PUBLICADO", updated.Status)
+	require.NotNil(t, updated.Endereco)
+
+	noEndereco := uint(0)
+	updated, err = svc.UpdateEmpreendimento(ctx, id, &UpdateEmpreendimentoRequest{EnderecoID: &noEndereco})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Endereco)
+
+	_, err = svc.UpdateEmpreendimento(ctx, 99, &UpdateEmpreendimentoRequest{Titulo: "Missing"})
+	assert.ErrorIs(t, err, ErrEmpreendimentoNotFound)
+}
+
+func TestTorresAndPlantas_AreScopedToEmpreendimento(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	alpha := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Alpha"})
+	beta := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Beta"})
+
+	torre, err := svc.AddTorre(ctx, alpha, &TorreRequest{Nome: "Torre A", TotalPavimentos: 20})
+	require.NoError(t, err)
+
+	_, err = svc.UpdateTorre(ctx, beta, torre.ID, &TorreRequest{Nome: "Torre B"})
+	assert.ErrorIs(t, err, ErrTorreNotFound)
+
+	updated, err := svc.UpdateTorre(ctx, alpha, torre.ID, &TorreRequest{Nome: "Torre A1", TotalPavimentos: 22})
+	require.NoError(t, err)
+	assert.Equal(t, "Torre A1", updated.Nome)
+	assert.Equal(t, 22, updated.TotalPavimentos)
+
+	planta, err := svc.AddPlanta(ctx, alpha, &PlantaRequest{Nome: "Tipo 1", Metragem: 72.5})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.DeletePlanta(ctx, beta, planta.ID), ErrPlantaNotFound)
+	require.NoError(t, svc.DeleteTorre(ctx, alpha, torre.ID))
+
+	torres, err := svc.ListTorres(ctx, alpha)
+	require.NoError(t, err)
+	assert.Empty(t, torres)
+
+	_, err = svc.ListPlantas(ctx, 99)
+	assert.ErrorIs(t, err, ErrEmpreendimentoNotFound)
+}
+
+func TestAnexos(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	alpha := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Alpha"})
+	beta := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Beta"})
+
+	planta, err := svc.AddPlanta(ctx, alpha, &PlantaRequest{Nome: "Tipo 1", Metragem: 72.5})
+	require.NoError(t, err)
+
+	fachada, err := svc.AddAnexo(ctx, alpha, &AnexoRequest{Nome: "fachada.jpg", URL: "https://cdn.example.com/fachada.jpg", Image: true})
+	require.NoError(t, err)
+	_, err = svc.AddAnexo(ctx, alpha, &AnexoRequest{Nome: "planta.jpg", URL: "https://cdn.example.com/planta.jpg", PlantaID: &planta.ID})
+	require.NoError(t, err)
+
+	_, err = svc.AddAnexo(ctx, beta, &AnexoRequest{Nome: "x.jpg", URL: "https://cdn.example.com/x.jpg", PlantaID: &planta.ID})
+	assert.ErrorIs(t, err, ErrPlantaNotFound)
+
+	empreendimento, err := svc.GetEmpreendimento(ctx, alpha)
+	require.NoError(t, err)
+	require.Len(t, empreendimento.Anexos, 1)
+	assert.Equal(t, "fachada.jpg", empreendimento.Anexos[0].Nome)
+	require.Len(t, empreendimento.Plantas, 1)
+	require.Len(t, empreendimento.Plantas[0].Anexos, 1)
+
+	anexos, err := svc.ListAnexos(ctx, alpha)
+	require.NoError(t, err)
+	assert.Len(t, anexos, 2)
+
+	assert.ErrorIs(t, svc.DeleteAnexo(ctx, beta, fachada.ID), ErrAnexoNotFound)
+	require.NoError(t, svc.DeleteAnexo(ctx, alpha, fachada.ID))
+	assert.ErrorIs(t, svc.DeleteAnexo(ctx, alpha, fachada.ID), ErrAnexoNotFound)
+}
+
+func TestEmpreendimentoWrites_OwnerScope(t *testing.T) {
+	svc, database := setupServiceDB(t)
+	ctx := context.Background()
+	id := createEmpreendimento(t, svc, CreateEmpreendimentoRequest{Titulo: "Torre Azul"})
+	torre, err := svc.AddTorre(ctx, id, &TorreRequest{Nome: "A"})
+	require.NoError(t, err)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, organizacao_id) VALUES (5, 1), (6, 2)`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, empreendimento_id, corretor_principal_id) VALUES (1, ?, 5)`, id).Error)
+
+	for _, foreign := range []context.Context{contextutil.WithCorretorScope(ctx, 6), db.WithOrganizacao(ctx, 2)} {
+		_, err = svc.UpdateEmpreendimento(foreign, id, &UpdateEmpreendimentoRequest{Titulo: "Outro"})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden)
+		_, err = svc.AddTorre(foreign, id, &TorreRequest{Nome: "B"})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden)
+		_, err = svc.UpdateTorre(foreign, id, torre.ID, &TorreRequest{Nome: "B"})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden)
+		_, err = svc.AddPlanta(foreign, id, &PlantaRequest{Nome: "Tipo", Metragem: 70})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden)
+		assert.ErrorIs(t, svc.DeleteEmpreendimento(foreign, id), imoveis.ErrForbidden)
+	}
+
+	current, err := svc.GetEmpreendimento(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Torre Azul", current.Titulo)
+
+	owner := db.WithOrganizacao(contextutil.WithCorretorScope(ctx, 5), 1)
+	updated, err := svc.UpdateEmpreendimento(owner, id, &UpdateEmpreendimentoRequest{Titulo: "Torre Verde"})
+	require.NoError(t, err)
+	assert.Equal(t, "Torre Verde", updated.Titulo)
+}
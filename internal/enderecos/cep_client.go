@@ -0,0 +1,105 @@
+package enderecos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// CEPClient looks up postal codes in an external address service
+type CEPClient interface {
+	// Lookup returns the address of an 8-digit CEP, or ErrCEPNotFound when it does not exist
+	Lookup(ctx context.Context, cep string) (*CEPResponse, error)
+}
+
+type viaCEPClient struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewViaCEPClient creates a CEP client backed by the ViaCEP web service
+func NewViaCEPClient(cfg *config.ViaCEPConfig) CEPClient {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://viacep.com.br"
+	}
+
+	return &viaCEPClient{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    baseURL,
+	}
+}
+
+// viaCEPResult is the payload of GET /ws/{cep}/json/; unknown CEPs answer 200 with {"erro": true}
+type viaCEPResult struct {
+	CEP        string `json:"cep"`
+	Logradouro string `json:"logradouro"`
+	Bairro     string `json:"bairro"`
+	Localidade string `json:"localidade"`
+	UF         string `json:"uf"`
+	Erro       any    `json:"erro"`
+}
+
+// Lookup fetches the address of a CEP from ViaCEP
+func (c *viaCEPClient) Lookup(ctx context.Context, cep string) (*CEPResponse, error) {
+	url := fmt.Sprintf("%s/ws/%s/json/", c.baseURL, cep)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCEPLookupFailed, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	// ViaCEP answers 400 for malformed CEPs; the service validates the format beforehand
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCEPNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: ViaCEP returned status %d", ErrCEPLookupFailed, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %v", ErrCEPLookupFailed, err)
+	}
+
+	var result viaCEPResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse response: %v", ErrCEPLookupFailed, err)
+	}
+
+	// The flag has been sent both as a boolean and as the string "true"
+	if result.Erro != nil && result.Erro != false && result.Erro != "false" {
+		return nil, ErrCEPNotFound
+	}
+
+	return &CEPResponse{
+		CEP:    result.CEP,
+		Rua:    result.Logradouro,
+		Bairro: result.Bairro,
+		Cidade: result.Localidade,
+		Estado: result.UF,
+	}, nil
+}
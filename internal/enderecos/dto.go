@@ -0,0 +1,34 @@
+package enderecos
+
+// CreateEnderecoRequest represents address creation request
+type CreateEnderecoRequest struct {
+	Rua       string  `json:"rua" binding:"required,max=255"`
+	Numero    int     `json:"numero" binding:"omitempty,min=0"`
+	Bairro    string  `json:"bairro" binding:"omitempty,max=255"`
+	Cidade    string  `json:"cidade" binding:"required,max=255"`
+	Estado    string  `json:"estado" binding:"required,len=2"`
+	CEP       string  `json:"cep" binding:"omitempty,max=9"`
+	Latitude  float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// UpdateEnderecoRequest represents address update request; absent fields are left untouched
+type UpdateEnderecoRequest struct {
+	Rua       *string  `json:"rua" binding:"omitempty,min=1,max=255"`
+	Numero    *int     `json:"numero" binding:"omitempty,min=0"`
+	Bairro    *string  `json:"bairro" binding:"omitempty,max=255"`
+	Cidade    *string  `json:"cidade" binding:"omitempty,min=1,max=255"`
+	Estado    *string  `json:"estado" binding:"omitempty,len=2"`
+	CEP       *string  `json:"cep" binding:"omitempty,max=9"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// CEPResponse represents the address data found for a postal code, used to autofill forms
+type CEPResponse struct {
+	CEP    string `json:"cep"`
+	Rua    string `json:"rua"`
+	Bairro string `json:"bairro"`
+	Cidade string `json:"cidade"`
+	Estado string `json:"estado"`
+}
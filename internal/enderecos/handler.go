@@ -0,0 +1,158 @@
+package enderecos
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Handler defines HTTP handlers for address operations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new address handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type uriRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type cepURIRequest struct {
+	CEP string `uri:"cep" binding:"required"`
+}
+
+// @Summary Create address
+// @Description Create an endereco to be referenced by imoveis and empreendimentos
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateEnderecoRequest true "Endereco creation request"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.EnderecoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/enderecos [post]
+func (h *Handler) CreateEndereco(c *gin.Context) {
+	var req CreateEnderecoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	endereco, err := h.service.CreateEndereco(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(endereco))
+}
+
+// @Summary Get address by ID
+// @Description Retrieve an endereco
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param id path uint true "Endereco ID"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.EnderecoResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/enderecos/{id} [get]
+func (h *Handler) GetEndereco(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	endereco, err := h.service.GetEndereco(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(endereco))
+}
+
+// @Summary Update address
+// @Description Update the provided fields of an endereco
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Endereco ID"
+// @Param request body UpdateEnderecoRequest true "Endereco update request"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.EnderecoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/enderecos/{id} [put]
+func (h *Handler) UpdateEndereco(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateEnderecoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	endereco, err := h.service.UpdateEndereco(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(endereco))
+}
+
+// @Summary Look up CEP
+// @Description Fetch rua, bairro, cidade and estado of a CEP (via ViaCEP) to autofill an endereco
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param cep path string true "CEP, with or without punctuation"
+// @Success 200 {object} errors.Response{success=bool,data=CEPResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/enderecos/cep/{cep} [get]
+func (h *Handler) LookupCEP(c *gin.Context) {
+	var uri cepURIRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.LookupCEP(c.Request.Context(), uri.CEP)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEnderecoNotFound):
+		_ = c.Error(apiErrors.NotFound("Endereco not found"))
+	case errors.Is(err, ErrCEPNotFound):
+		_ = c.Error(apiErrors.NotFound("CEP not found"))
+	case errors.Is(err, ErrInvalidCEP):
+		_ = c.Error(apiErrors.BadRequest(err.Error()))
+	case errors.Is(err, imoveis.ErrForbidden):
+		_ = c.Error(apiErrors.Forbidden("Endereco belongs to another corretor's imovel"))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
@@ -0,0 +1,68 @@
+package enderecos
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines address repository interface
+type Repository interface {
+	Create(ctx context.Context, endereco *imoveis.Endereco) error
+	FindByID(ctx context.Context, id uint) (*imoveis.Endereco, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	FindOwners(ctx context.Context, id uint) ([]imoveis.PropertyOwner, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new address repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create creates a new address
+func (r *repository) Create(ctx context.Context, endereco *imoveis.Endereco) error {
+	return r.db.WithContext(ctx).Create(endereco).Error
+}
+
+// FindByID finds an address by ID
+func (r *repository) FindByID(ctx context.Context, id uint) (*imoveis.Endereco, error) {
+	var endereco imoveis.Endereco
+	if err := r.db.WithContext(ctx).First(&endereco, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &endereco, nil
+}
+
+// Update updates the given columns of an address
+func (r *repository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&imoveis.Endereco{ID: id}).
+		Updates(updates).Error
+}
+
+// FindOwners returns the corretor and organizacao of every property using the address, directly
+// or through its empreendimento, including the properties outside the organizacao of ctx
+func (r *repository) FindOwners(ctx context.Context, id uint) ([]imoveis.PropertyOwner, error) {
+	var owners []imoveis.PropertyOwner
+	// WHY: Raw is not filtered by db.TenantScope, so addresses shared with another tenant are found
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT i.corretor_principal_id, COALESCE(c.organizacao_id, 0) AS organizacao_id
+		FROM imoveis i
+		LEFT JOIN corretores_principais c ON c.id = i.corretor_principal_id
+		WHERE i.deleted_at IS NULL
+		  AND (i.endereco_id = ? OR i.empreendimento_id IN (SELECT id FROM empreendimentos WHERE endereco_id = ? AND deleted_at IS NULL))`,
+		id, id).Scan(&owners).Error; err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
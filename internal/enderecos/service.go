@@ -0,0 +1,189 @@
+package enderecos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrEnderecoNotFound is returned when an address does not exist
+	ErrEnderecoNotFound = errors.New("endereco not found")
+	// ErrInvalidCEP is returned when a CEP does not have 8 digits
+	ErrInvalidCEP = errors.New("cep must have 8 digits")
+	// ErrCEPNotFound is returned when the lookup service does not know the CEP
+	ErrCEPNotFound = errors.New("cep not found")
+	// ErrCEPLookupFailed is returned when the lookup service is unreachable or answers unexpectedly
+	ErrCEPLookupFailed = errors.New("cep lookup failed")
+)
+
+// Service defines address service interface
+type Service interface {
+	CreateEndereco(ctx context.Context, req *CreateEnderecoRequest) (*imoveis.EnderecoResponse, error)
+	GetEndereco(ctx context.Context, id uint) (*imoveis.EnderecoResponse, error)
+	UpdateEndereco(ctx context.Context, id uint, req *UpdateEnderecoRequest) (*imoveis.EnderecoResponse, error)
+	LookupCEP(ctx context.Context, cep string) (*CEPResponse, error)
+}
+
+type service struct {
+	repo      Repository
+	cepClient CEPClient
+}
+
+// NewService creates a new address service
+func NewService(repo Repository, cepClient CEPClient) Service {
+	return &service{repo: repo, cepClient: cepClient}
+}
+
+// CreateEndereco creates a new address
+func (s *service) CreateEndereco(ctx context.Context, req *CreateEnderecoRequest) (*imoveis.EnderecoResponse, error) {
+	cep, err := formatOptionalCEP(req.CEP)
+	if err != nil {
+		return nil, err
+	}
+
+	endereco := &imoveis.Endereco{
+		Rua:       strings.TrimSpace(req.Rua),
+		Numero:    req.Numero,
+		Bairro:    strings.TrimSpace(req.Bairro),
+		Cidade:    strings.TrimSpace(req.Cidade),
+		Estado:    strings.ToUpper(req.Estado),
+		CEP:       cep,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if err := s.repo.Create(ctx, endereco); err != nil {
+		return nil, fmt.Errorf("failed to create endereco: %w", err)
+	}
+
+	return toResponse(endereco), nil
+}
+
+// GetEndereco retrieves an address by ID
+func (s *service) GetEndereco(ctx context.Context, id uint) (*imoveis.EnderecoResponse, error) {
+	endereco, err := s.findEndereco(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(endereco), nil
+}
+
+// UpdateEndereco updates the provided fields of an address. Addresses used by properties the
+// request may not change (another corretor's or another organizacao's) are refused.
+func (s *service) UpdateEndereco(ctx context.Context, id uint, req *UpdateEnderecoRequest) (*imoveis.EnderecoResponse, error) {
+	if _, err := s.findEndereco(ctx, id); err != nil {
+		return nil, err
+	}
+	owners, err := s.repo.FindOwners(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties of endereco: %w", err)
+	}
+	if err := imoveis.CheckOwnersScope(ctx, owners); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Rua != nil {
+		updates["rua"] = strings.TrimSpace(*req.Rua)
+	}
+	if req.Numero != nil {
+		updates["numero"] = *req.Numero
+	}
+	if req.Bairro != nil {
+		updates["bairro"] = strings.TrimSpace(*req.Bairro)
+	}
+	if req.Cidade != nil {
+		updates["cidade"] = strings.TrimSpace(*req.Cidade)
+	}
+	if req.Estado != nil {
+		updates["estado"] = strings.ToUpper(*req.Estado)
+	}
+	if req.CEP != nil {
+		cep, err := formatOptionalCEP(*req.CEP)
+		if err != nil {
+			return nil, err
+		}
+		updates["cep"] = cep
+	}
+	if req.Latitude != nil {
+		updates["latitude"] = *req.Latitude
+	}
+	if req.Longitude != nil {
+		updates["longitude"] = *req.Longitude
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update endereco: %w", err)
+		}
+	}
+
+	return s.GetEndereco(ctx, id)
+}
+
+// LookupCEP returns the street, neighborhood, city and state of a CEP
+func (s *service) LookupCEP(ctx context.Context, cep string) (*CEPResponse, error) {
+	digits, err := normalizeCEP(cep)
+	if err != nil {
+		return nil, err
+	}
+	return s.cepClient.Lookup(ctx, digits)
+}
+
+func (s *service) findEndereco(ctx context.Context, id uint) (*imoveis.Endereco, error) {
+	endereco, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find endereco: %w", err)
+	}
+	if endereco == nil {
+		return nil, ErrEnderecoNotFound
+	}
+	return endereco, nil
+}
+
+// normalizeCEP strips punctuation ("01001-000", "01.001-000") and requires exactly 8 digits
+func normalizeCEP(cep string) (string, error) {
+	var b strings.Builder
+	for _, r := range cep {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == '.' || r == ' ':
+		default:
+			return "", ErrInvalidCEP
+		}
+	}
+	if b.Len() != 8 {
+		return "", ErrInvalidCEP
+	}
+	return b.String(), nil
+}
+
+// formatOptionalCEP stores CEPs as "00000-000", the format returned by the lookup; empty clears it
+func formatOptionalCEP(cep string) (string, error) {
+	if strings.TrimSpace(cep) == "" {
+		return "", nil
+	}
+	digits, err := normalizeCEP(cep)
+	if err != nil {
+		return "", err
+	}
+	return digits[:5] + "-" + digits[5:], nil
+}
+
+func toResponse(endereco *imoveis.Endereco) *imoveis.EnderecoResponse {
+	return &imoveis.EnderecoResponse{
+		ID:        endereco.ID,
+		Rua:       endereco.Rua,
+		Numero:    endereco.Numero,
+		Bairro:    endereco.Bairro,
+		Cidade:    endereco.Cidade,
+		Estado:    endereco.Estado,
+		CEP:       endereco.CEP,
+		Latitude:  endereco.Latitude,
+		Longitude: endereco.Longitude,
+	}
+}
@@ -0,0 +1,147 @@
+package enderecos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+type stubCEPClient struct {
+	lookups []string
+	result  *CEPResponse
+	err     error
+}
+
+func (c *stubCEPClient) Lookup(_ context.Context, cep string) (*CEPResponse, error) {
+	c.lookups = append(c.lookups, cep)
+	return c.result, c.err
+}
+
+func setupDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&imoveis.Endereco{}))
+	require.NoError(t, database.Exec(`CREATE TABLE corretores_principais (id INTEGER PRIMARY KEY, organizacao_id INTEGER)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE empreendimentos (id INTEGER PRIMARY KEY, endereco_id INTEGER, deleted_at DATETIME)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY, endereco_id INTEGER, empreendimento_id INTEGER, corretor_principal_id INTEGER, deleted_at DATETIME
+	)`).Error)
+	return database
+}
+
+func setupService(t *testing.T, cepClient CEPClient) Service {
+	t.Helper()
+	return NewService(NewRepository(setupDB(t)), cepClient)
+}
+
+func TestCreateAndUpdateEndereco(t *testing.T) {
+	svc := setupService(t, &stubCEPClient{})
+	ctx := context.Background()
+
+	created, err := svc.CreateEndereco(ctx, &CreateEnderecoRequest{
+		Rua: " Praça da Sé ", Numero: 10, Cidade: "São Paulo", Estado: "sp", CEP: "01001000",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Praça da Sé", created.Rua)
+	assert.Equal(t, "SP", created.Estado)
+	assert.Equal(t, "01001-000", created.CEP)
+
+	bairro := "Sé"
+	numero := 20
+	updated, err := svc.UpdateEndereco(ctx, created.ID, &UpdateEnderecoRequest{Bairro: &bairro, Numero: &numero})
+	require.NoError(t, err)
+	assert.Equal(t, "Sé", updated.Bairro)
+	assert.Equal(t, 20, updated.Numero)
+	assert.Equal(t, "Praça da Sé", updated.Rua)
+
+	invalid := "123"
+	_, err = svc.UpdateEndereco(ctx, created.ID, &UpdateEnderecoRequest{CEP: &invalid})
+	assert.ErrorIs(t, err, ErrInvalidCEP)
+
+	_, err = svc.UpdateEndereco(ctx, 999, &UpdateEnderecoRequest{Bairro: &bairro})
+	assert.ErrorIs(t, err, ErrEnderecoNotFound)
+}
+
+func TestUpdateEndereco_OwnerScope(t *testing.T) {
+	database := setupDB(t)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, organizacao_id) VALUES (5, 1), (6, 2)`).Error)
+	svc := NewService(NewRepository(database), &stubCEPClient{})
+	ctx := context.Background()
+
+	direct, err := svc.CreateEndereco(ctx, &CreateEnderecoRequest{Rua: "Rua A", Cidade: "Curitiba", Estado: "PR"})
+	require.NoError(t, err)
+	viaEmpreendimento, err := svc.CreateEndereco(ctx, &CreateEnderecoRequest{Rua: "Rua B", Cidade: "Curitiba", Estado: "PR"})
+	require.NoError(t, err)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, endereco_id, corretor_principal_id) VALUES (1, ?, 5)`, direct.ID).Error)
+	require.NoError(t, database.Exec(`INSERT INTO empreendimentos (id, endereco_id) VALUES (1, ?)`, viaEmpreendimento.ID).Error)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, empreendimento_id, corretor_principal_id) VALUES (2, 1, 5)`).Error)
+
+	rua := "Rua C"
+	for _, id := range []uint{direct.ID, viaEmpreendimento.ID} {
+		_, err = svc.UpdateEndereco(contextutil.WithCorretorScope(ctx, 6), id, &UpdateEnderecoRequest{Rua: &rua})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden, "the property belongs to another corretor")
+		_, err = svc.UpdateEndereco(db.WithOrganizacao(ctx, 2), id, &UpdateEnderecoRequest{Rua: &rua})
+		assert.ErrorIs(t, err, imoveis.ErrForbidden, "the property belongs to another organizacao")
+	}
+
+	current, err := svc.GetEndereco(ctx, direct.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Rua A", current.Rua)
+
+	updated, err := svc.UpdateEndereco(db.WithOrganizacao(contextutil.WithCorretorScope(ctx, 5), 1), direct.ID, &UpdateEnderecoRequest{Rua: &rua})
+	require.NoError(t, err)
+	assert.Equal(t, "Rua C", updated.Rua)
+}
+
+func TestLookupCEP_NormalizesBeforeCallingClient(t *testing.T) {
+	client := &stubCEPClient{result: &CEPResponse{CEP: "01001-000", Cidade: "São Paulo"}}
+	svc := setupService(t, client)
+
+	result, err := svc.LookupCEP(context.Background(), "01.001-000")
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.Cidade)
+	assert.Equal(t, []string{"01001000"}, client.lookups)
+
+	_, err = svc.LookupCEP(context.Background(), "0100100a")
+	assert.ErrorIs(t, err, ErrInvalidCEP)
+	assert.Len(t, client.lookups, 1)
+}
+
+func TestViaCEPClient_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws/01001000/json/":
+			_, _ = w.Write([]byte(`{"cep":"01001-000","logradouro":"Praça da Sé","bairro":"Sé","localidade":"São Paulo","uf":"SP"}`))
+		case "/ws/99999999/json/":
+			_, _ = w.Write([]byte(`{"erro": "true"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewViaCEPClient(&config.ViaCEPConfig{BaseURL: server.URL + "/"})
+	ctx := context.Background()
+
+	result, err := client.Lookup(ctx, "01001000")
+	require.NoError(t, err)
+	assert.Equal(t, &CEPResponse{CEP: "01001-000", Rua: "Praça da Sé", Bairro: "Sé", Cidade: "São Paulo", Estado: "SP"}, result)
+
+	_, err = client.Lookup(ctx, "99999999")
+	assert.ErrorIs(t, err, ErrCEPNotFound)
+
+	_, err = client.Lookup(ctx, "12345678")
+	assert.ErrorIs(t, err, ErrCEPLookupFailed)
+}
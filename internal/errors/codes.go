@@ -9,4 +9,5 @@ const (
 	CodeValidation      = "VALIDATION_ERROR"
 	CodeConflict        = "CONFLICT"
 	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	CodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
 )
@@ -71,6 +71,15 @@ func Unauthorized(message string) *APIError {
 	}
 }
 
+// PayloadTooLarge creates a 413 Payload Too Large error for oversized uploads.
+func PayloadTooLarge(message string) *APIError {
+	return &APIError{
+		Code:    CodePayloadTooLarge,
+		Message: message,
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+}
+
 // InternalServerError creates a 500 Internal Server Error with details from the original error.
 func InternalServerError(err error) *APIError {
 	return &APIError{
@@ -66,6 +66,15 @@ func TestUnauthorized(t *testing.T) {
 	assert.Nil(t, err.Details)
 }
 
+func TestPayloadTooLarge(t *testing.T) {
+	err := PayloadTooLarge("File exceeds the maximum size")
+
+	assert.Equal(t, CodePayloadTooLarge, err.Code)
+	assert.Equal(t, "File exceeds the maximum size", err.Message)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.Status)
+	assert.Nil(t, err.Details)
+}
+
 func TestInternalServerError(t *testing.T) {
 	originalErr := errors.New("database connection failed")
 	err := InternalServerError(originalErr)
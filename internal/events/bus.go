@@ -0,0 +1,163 @@
+// Package events is an in-process event bus: modules publish what happened to them and the
+// handlers subscribed to it react in the background, so publishers don't depend on the modules
+// interested in their events.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultQueueSize applies when the bus is created without a queue size
+	defaultQueueSize = 256
+	// defaultWorkers applies when the bus is created without workers
+	defaultWorkers = 2
+	// handlerTimeout bounds the time a handler takes on one event
+	handlerTimeout = time.Minute
+)
+
+// Event is something that happened in a module; subscribers select events by their name
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to an event. Its error is logged; the event is not delivered again.
+type Handler func(ctx context.Context, event Event) error
+
+// Publisher publishes events without waiting for their handlers
+type Publisher interface {
+	// Publish queues event for its subscribers. Events published while the queue is full or after
+	// the bus is closed are dropped.
+	Publish(event Event)
+}
+
+// Bus delivers the published events to the handlers subscribed to their name
+type Bus interface {
+	Publisher
+	// Subscribe registers handler for the events named name, before events are published
+	Subscribe(name string, handler Handler)
+	// QueueDepth reports the events waiting for their handlers
+	QueueDepth() int
+	// Close stops accepting events and waits for the queued ones until ctx is done
+	Close(ctx context.Context) error
+}
+
+type bus struct {
+	handlersMu sync.RWMutex
+	handlers   map[string][]Handler
+
+	queue  chan Event
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewBus creates a bus and starts its workers. Handlers run outside the request that published
+// the event, each with its own context; events carry what their handlers need.
+func NewBus(workers, queueSize int) Bus {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	b := &bus{
+		handlers: make(map[string][]Handler),
+		queue:    make(chan Event, queueSize),
+	}
+	for i := 0; i < workers; i++ {
+		b.wg.Add(1)
+		go b.work()
+	}
+	return b
+}
+
+// Subscribe registers handler for the events named name
+func (b *bus) Subscribe(name string, handler Handler) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish queues event; events nobody subscribed to are not queued
+func (b *bus) Publish(event Event) {
+	b.handlersMu.RLock()
+	subscribed := len(b.handlers[event.EventName()]) > 0
+	b.handlersMu.RUnlock()
+	if !subscribed {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		slog.Warn("Event bus is closed, dropping event", "event", event.EventName())
+		return
+	}
+
+	select {
+	case b.queue <- event:
+	default:
+		slog.Warn("Event queue is full, dropping event", "event", event.EventName())
+	}
+}
+
+// QueueDepth reports the events waiting for their handlers
+func (b *bus) QueueDepth() int {
+	return len(b.queue)
+}
+
+// Close stops accepting events and waits for the workers to drain the queue
+func (b *bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("event handlers did not finish: %w", ctx.Err())
+	}
+}
+
+func (b *bus) work() {
+	defer b.wg.Done()
+	for event := range b.queue {
+		b.handlersMu.RLock()
+		handlers := b.handlers[event.EventName()]
+		b.handlersMu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := b.handle(handler, event); err != nil {
+				slog.Error("Event handler failed", "event", event.EventName(), "error", err)
+			}
+		}
+	}
+}
+
+// handle runs one handler, turning a panic into an error so the other handlers still run
+func (b *bus) handle(handler Handler, event Event) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) handle(ctx context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) recorded() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func TestBus_DeliversToSubscribers(t *testing.T) {
+	b := NewBus(1, 10)
+	published, prices := &recorder{}, &recorder{}
+	b.Subscribe(ImovelPublishedName, published.handle)
+	b.Subscribe(PriceChangedName, prices.handle)
+	b.Subscribe(PriceChangedName, func(ctx context.Context, event Event) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "handlers run with a timeout")
+		return errors.New("failing handlers don't stop the others")
+	})
+	b.Subscribe(PriceChangedName, func(ctx context.Context, event Event) error {
+		panic("neither do panicking ones")
+	})
+
+	b.Publish(ImovelPublished{ImovelID: 1})
+	b.Publish(PriceChanged{ImovelID: 2, Tipo: "VENDA", PrecoAnterior: 100, Preco: 90})
+	b.Publish(PriceChanged{ImovelID: 3, Tipo: "ALUGUEL", PrecoAnterior: 10, Preco: 12})
+	b.Publish(ImportFinished{RunID: 4})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, b.Close(ctx))
+
+	assert.Equal(t, []Event{ImovelPublished{ImovelID: 1}}, published.recorded())
+	assert.Equal(t, []Event{
+		PriceChanged{ImovelID: 2, Tipo: "VENDA", PrecoAnterior: 100, Preco: 90},
+		PriceChanged{ImovelID: 3, Tipo: "ALUGUEL", PrecoAnterior: 10, Preco: 12},
+	}, prices.recorded())
+	assert.Zero(t, b.QueueDepth())
+
+	// Publishing after Close is dropped instead of panicking on the closed queue
+	b.Publish(ImovelPublished{ImovelID: 5})
+	assert.Len(t, published.recorded(), 1)
+}
+
+func TestBus_DropsEventsWhenQueueIsFull(t *testing.T) {
+	b := NewBus(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	handled := &recorder{}
+	b.Subscribe(ImovelPublishedName, func(ctx context.Context, event Event) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return handled.handle(ctx, event)
+	})
+
+	b.Publish(ImovelPublished{ImovelID: 1})
+	<-started
+	b.Publish(ImovelPublished{ImovelID: 2})
+	b.Publish(ImovelPublished{ImovelID: 3})
+	assert.Equal(t, 1, b.QueueDepth())
+
+	close(release)
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, []Event{ImovelPublished{ImovelID: 1}, ImovelPublished{ImovelID: 2}}, handled.recorded())
+}
@@ -0,0 +1,97 @@
+package events
+
+import "time"
+
+// Names of the domain events
+const (
+	ImportFinishedName  = "import.finished"
+	LeadCreatedName     = "lead.created"
+	ImovelPublishedName = "imovel.published"
+	PriceChangedName    = "imovel.price_changed"
+	LoginFailedName     = "auth.login_failed"
+	LoginLockedName     = "auth.login_locked"
+)
+
+// ImportFinished is published when an import run from the external source ends, failed or not.
+// RunID is zero when the run could not be recorded.
+type ImportFinished struct {
+	RunID       uint
+	Source      string
+	Status      string // COMPLETED or FAILED
+	Error       string // why the run stopped
+	Total       int
+	Created     int
+	Updated     int
+	Unchanged   int
+	Failed      int
+	Removed     int
+	Quarantined int
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+// EventName identifies the event to its subscribers
+func (ImportFinished) EventName() string { return ImportFinishedName }
+
+// LeadCreated is published when someone asks to be contacted about a property
+type LeadCreated struct {
+	LeadID   uint
+	ImovelID uint
+	Nome     string
+	Email    string
+	Telefone string
+	Mensagem string
+}
+
+// EventName identifies the event to its subscribers
+func (LeadCreated) EventName() string { return LeadCreatedName }
+
+// ImovelPublished is published when a property goes to PUBLICADO. Origem tells who published it,
+// as in the price history (API, SCHEDULER).
+type ImovelPublished struct {
+	ImovelID uint
+	Codigo   string
+	Titulo   string
+	Origem   string
+}
+
+// EventName identifies the event to its subscribers
+func (ImovelPublished) EventName() string { return ImovelPublishedName }
+
+// PriceChanged is published when the sale (VENDA) or rental (ALUGUEL) price of a property changes
+// from one value to another. Origem is the origin recorded in the price history.
+type PriceChanged struct {
+	ImovelID      uint
+	Tipo          string
+	PrecoAnterior float64
+	Preco         float64
+	Origem        string
+}
+
+// EventName identifies the event to its subscribers
+func (PriceChanged) EventName() string { return PriceChangedName }
+
+// LoginFailed is published for each failed login. UserID is zero when no account has the email.
+type LoginFailed struct {
+	UserID    uint
+	Email     string
+	IPAddress string
+	Reason    string // invalid_credentials, invalid_two_factor_code or locked
+}
+
+// EventName identifies the event to its subscribers
+func (LoginFailed) EventName() string { return LoginFailedName }
+
+// LoginLocked is published when failed logins lock an account or an address out. Email and UserID
+// are empty for an address lockout, and UserID is zero when no account has the email.
+type LoginLocked struct {
+	UserID      uint
+	Email       string
+	Name        string
+	IPAddress   string
+	Attempts    int
+	LockedUntil time.Time
+}
+
+// EventName identifies the event to its subscribers
+func (LoginLocked) EventName() string { return LoginLockedName }
@@ -0,0 +1,34 @@
+package favoritos
+
+import "time"
+
+// ListQuery represents the pagination of favorites and favorite counts
+type ListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// FavoritoStatus is returned when saving a property
+type FavoritoStatus struct {
+	ImovelID uint `json:"imovel_id"`
+	Favorito bool `json:"favorito"`
+}
+
+// FavoritoResponse is a saved property in the list of its owner
+type FavoritoResponse struct {
+	ImovelID  uint      `json:"imovel_id"`
+	Codigo    string    `json:"codigo"`
+	Titulo    string    `json:"titulo"`
+	Slug      string    `json:"slug"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImovelFavoritoStats is how many times a property was saved, in total and by signed-in users
+type ImovelFavoritoStats struct {
+	ImovelID  uint   `json:"imovel_id"`
+	Codigo    string `json:"codigo"`
+	Titulo    string `json:"titulo"`
+	Favoritos int64  `json:"favoritos"`
+	Usuarios  int64  `json:"usuarios"`
+}
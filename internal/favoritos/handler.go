@@ -0,0 +1,185 @@
+package favoritos
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+const (
+	// DeviceTokenHeader carries the random token a device keeps favorites under before signing in
+	DeviceTokenHeader = "X-Device-Token"
+
+	minDeviceTokenLength = 16
+	maxDeviceTokenLength = 64
+)
+
+// Handler defines HTTP handlers for favoritos
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new favorito handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type imovelURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Save a property as favorite
+// @Description Save a published property for the authenticated user or, anonymously, for the device identified by X-Device-Token. Favorites saved on a device move to the user on the first authenticated request sending its token. Saving a property again is a no-op (200).
+// @Tags favoritos
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param X-Device-Token header string false "Random device token (16-64 characters), required without authentication"
+// @Success 201 {object} errors.Response{success=bool,data=FavoritoStatus}
+// @Success 200 {object} errors.Response{success=bool,data=FavoritoStatus} "Already saved"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Missing or invalid device token"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid or expired token"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Property not found or not published"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/favorite [post]
+func (h *Handler) AddFavorito(c *gin.Context) {
+	var uri imovelURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	owner, ok := h.owner(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.AddFavorito(c.Request.Context(), owner, uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, apiErrors.Success(FavoritoStatus{ImovelID: uri.ID, Favorito: true}))
+}
+
+// @Summary Remove a property from favorites
+// @Description Removing a property that is not saved is a no-op
+// @Tags favoritos
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param X-Device-Token header string false "Random device token (16-64 characters), required without authentication"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Missing or invalid device token"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid or expired token"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/favorite [delete]
+func (h *Handler) RemoveFavorito(c *gin.Context) {
+	var uri imovelURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	owner, ok := h.owner(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveFavorito(c.Request.Context(), owner, uri.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List my favorite properties
+// @Description Properties saved by the authenticated user or by the device identified by X-Device-Token, last saved first
+// @Tags favoritos
+// @Produce json
+// @Security BearerAuth
+// @Param X-Device-Token header string false "Random device token (16-64 characters), required without authentication"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[FavoritoResponse]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Missing or invalid device token"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid or expired token"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/me/favoritos [get]
+func (h *Handler) ListFavoritos(c *gin.Context) {
+	var query ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	owner, ok := h.owner(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ListFavoritos(c.Request.Context(), owner, &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Most saved properties
+// @Description How many users and devices saved each property, most saved first
+// @Tags favoritos
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[ImovelFavoritoStats]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imoveis/favoritos [get]
+func (h *Handler) ListStats(c *gin.Context) {
+	var query ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListStats(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// owner identifies the authenticated user and the device of the request. It writes a 400 and
+// returns false when the device token is malformed.
+func (h *Handler) owner(c *gin.Context) (Owner, bool) {
+	owner := Owner{UserID: contextutil.GetUserID(c), DeviceToken: c.GetHeader(DeviceTokenHeader)}
+	if owner.DeviceToken != "" && (len(owner.DeviceToken) < minDeviceTokenLength || len(owner.DeviceToken) > maxDeviceTokenLength) {
+		_ = c.Error(apiErrors.BadRequest("X-Device-Token must have between 16 and 64 characters"))
+		return Owner{}, false
+	}
+	return owner, true
+}
+
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrImovelNotFound):
+		_ = c.Error(apiErrors.NotFound("Property not found"))
+	case errors.Is(err, ErrNoOwner):
+		_ = c.Error(apiErrors.BadRequest("Authenticate or send an X-Device-Token header"))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
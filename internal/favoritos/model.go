@@ -0,0 +1,26 @@
+// Package favoritos keeps the properties saved by end users, signed in or identified by an
+// anonymous device token, and how many times each property was saved.
+package favoritos
+
+import "time"
+
+// Favorito is a property saved by a user or, before signing in, by a device. Exactly one of UserID
+// and DeviceToken is set; device favorites move to the user once the device signs in.
+type Favorito struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ImovelID    uint      `gorm:"not null;index;uniqueIndex:idx_favoritos_user_imovel,priority:2;uniqueIndex:idx_favoritos_device_imovel,priority:2" json:"imovel_id"`
+	UserID      *uint     `gorm:"uniqueIndex:idx_favoritos_user_imovel,priority:1" json:"user_id,omitempty"`
+	DeviceToken *string   `gorm:"size:64;uniqueIndex:idx_favoritos_device_imovel,priority:1" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for Favorito model
+func (Favorito) TableName() string {
+	return "favoritos"
+}
+
+// Owner identifies who saves favorites: the authenticated user, or else the anonymous device
+type Owner struct {
+	UserID      uint
+	DeviceToken string
+}
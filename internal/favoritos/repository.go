@@ -0,0 +1,129 @@
+package favoritos
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines favorito repository interface
+type Repository interface {
+	// Add saves the property for owner and reports whether it was not saved yet
+	Add(ctx context.Context, owner Owner, imovelID uint) (bool, error)
+	Remove(ctx context.Context, owner Owner, imovelID uint) error
+	// List returns one page of the properties saved by owner, last saved first, with their total
+	List(ctx context.Context, owner Owner, query *ListQuery) ([]FavoritoResponse, int64, error)
+	// Stats returns one page of the saved properties, most saved first, with their total
+	Stats(ctx context.Context, query *ListQuery) ([]ImovelFavoritoStats, int64, error)
+	// ClaimDevice moves the favorites of the device to the user, dropping those the user already has
+	ClaimDevice(ctx context.Context, deviceToken string, userID uint) error
+	// PublishedImovelExists reports whether the property is published
+	PublishedImovelExists(ctx context.Context, id uint) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new favorito repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ownedBy limits db to the favorites of owner
+func ownedBy(db *gorm.DB, owner Owner) *gorm.DB {
+	if owner.UserID != 0 {
+		return db.Where("favoritos.user_id = ?", owner.UserID)
+	}
+	return db.Where("favoritos.device_token = ?", owner.DeviceToken)
+}
+
+// Add saves the property for owner and reports whether it was not saved yet
+func (r *repository) Add(ctx context.Context, owner Owner, imovelID uint) (bool, error) {
+	favorito := &Favorito{ImovelID: imovelID}
+	if owner.UserID != 0 {
+		favorito.UserID = &owner.UserID
+	} else {
+		favorito.DeviceToken = &owner.DeviceToken
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(favorito)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Remove deletes the property from the favorites of owner, if saved
+func (r *repository) Remove(ctx context.Context, owner Owner, imovelID uint) error {
+	return ownedBy(r.db.WithContext(ctx), owner).Where("imovel_id = ?", imovelID).Delete(&Favorito{}).Error
+}
+
+// List returns one page of the properties saved by owner, last saved first, with their total
+func (r *repository) List(ctx context.Context, owner Owner, query *ListQuery) ([]FavoritoResponse, int64, error) {
+	db := ownedBy(r.db.WithContext(ctx).Model(&Favorito{}), owner).
+		Joins("JOIN imoveis ON imoveis.id = favoritos.imovel_id AND imoveis.deleted_at IS NULL")
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	favoritos := make([]FavoritoResponse, 0, query.Limit)
+	err := db.Select("favoritos.imovel_id, imoveis.codigo, imoveis.titulo, imoveis.slug, imoveis.status, favoritos.created_at").
+		Order("favoritos.created_at DESC, favoritos.id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Scan(&favoritos).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return favoritos, total, nil
+}
+
+// Stats returns one page of the saved properties, most saved first, with their total
+func (r *repository) Stats(ctx context.Context, query *ListQuery) ([]ImovelFavoritoStats, int64, error) {
+	db := r.db.WithContext(ctx).Model(&Favorito{}).
+		Joins("JOIN imoveis ON imoveis.id = favoritos.imovel_id AND imoveis.deleted_at IS NULL")
+
+	var total int64
+	if err := db.Distinct("favoritos.imovel_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	stats := make([]ImovelFavoritoStats, 0, query.Limit)
+	err := r.db.WithContext(ctx).Model(&Favorito{}).
+		Joins("JOIN imoveis ON imoveis.id = favoritos.imovel_id AND imoveis.deleted_at IS NULL").
+		Select("favoritos.imovel_id, imoveis.codigo, imoveis.titulo, COUNT(*) AS favoritos, COUNT(favoritos.user_id) AS usuarios").
+		Group("favoritos.imovel_id, imoveis.codigo, imoveis.titulo").
+		Order("COUNT(*) DESC, favoritos.imovel_id ASC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return stats, total, nil
+}
+
+// ClaimDevice moves the favorites of the device to the user, dropping those the user already has
+func (r *repository) ClaimDevice(ctx context.Context, deviceToken string, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		saved := tx.Model(&Favorito{}).Select("imovel_id").Where("user_id = ?", userID)
+		if err := tx.Where("device_token = ? AND imovel_id IN (?)", deviceToken, saved).Delete(&Favorito{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Favorito{}).
+			Where("device_token = ?", deviceToken).
+			Updates(map[string]interface{}{"user_id": userID, "device_token": nil}).Error
+	})
+}
+
+// PublishedImovelExists reports whether the property is published
+func (r *repository) PublishedImovelExists(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("imoveis").
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, imoveis.StatusPublicado).
+		Count(&count).Error
+	return count > 0, err
+}
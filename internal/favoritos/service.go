@@ -0,0 +1,102 @@
+package favoritos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+var (
+	// ErrImovelNotFound is returned when saving a property that is not published
+	ErrImovelNotFound = errors.New("property not found")
+	// ErrNoOwner is returned when the request is neither authenticated nor sent from a device
+	ErrNoOwner = errors.New("sign in or identify the device to keep favorites")
+)
+
+// Service defines favorito service interface
+type Service interface {
+	AddFavorito(ctx context.Context, owner Owner, imovelID uint) (bool, error)
+	RemoveFavorito(ctx context.Context, owner Owner, imovelID uint) error
+	ListFavoritos(ctx context.Context, owner Owner, query *ListQuery) (*pagination.Page[FavoritoResponse], error)
+	ListStats(ctx context.Context, query *ListQuery) (*pagination.Page[ImovelFavoritoStats], error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new favorito service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// AddFavorito saves the published property for owner and reports whether it was not saved yet.
+// Saving it again is a no-op.
+func (s *service) AddFavorito(ctx context.Context, owner Owner, imovelID uint) (bool, error) {
+	if err := s.resolve(ctx, owner); err != nil {
+		return false, err
+	}
+
+	exists, err := s.repo.PublishedImovelExists(ctx, imovelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find property: %w", err)
+	}
+	if !exists {
+		return false, ErrImovelNotFound
+	}
+
+	created, err := s.repo.Add(ctx, owner, imovelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add favorito: %w", err)
+	}
+	return created, nil
+}
+
+// RemoveFavorito removes the property from the favorites of owner; removing one that is not
+// saved is a no-op
+func (s *service) RemoveFavorito(ctx context.Context, owner Owner, imovelID uint) error {
+	if err := s.resolve(ctx, owner); err != nil {
+		return err
+	}
+	if err := s.repo.Remove(ctx, owner, imovelID); err != nil {
+		return fmt.Errorf("failed to remove favorito: %w", err)
+	}
+	return nil
+}
+
+// ListFavoritos returns one page of the properties saved by owner, last saved first
+func (s *service) ListFavoritos(ctx context.Context, owner Owner, query *ListQuery) (*pagination.Page[FavoritoResponse], error) {
+	if err := s.resolve(ctx, owner); err != nil {
+		return nil, err
+	}
+	favoritos, total, err := s.repo.List(ctx, owner, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list favoritos: %w", err)
+	}
+	return pagination.New(favoritos, total, query.Page, query.Limit), nil
+}
+
+// ListStats returns one page of the saved properties, most saved first
+func (s *service) ListStats(ctx context.Context, query *ListQuery) (*pagination.Page[ImovelFavoritoStats], error) {
+	stats, total, err := s.repo.Stats(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list favorito stats: %w", err)
+	}
+	return pagination.New(stats, total, query.Page, query.Limit), nil
+}
+
+// resolve checks that owner identifies someone and, when an authenticated user still sends the
+// token of the device, moves the favorites saved anonymously on it to the user
+func (s *service) resolve(ctx context.Context, owner Owner) error {
+	if owner.UserID == 0 && owner.DeviceToken == "" {
+		return ErrNoOwner
+	}
+	if owner.UserID != 0 && owner.DeviceToken != "" {
+		if err := s.repo.ClaimDevice(ctx, owner.DeviceToken, owner.UserID); err != nil {
+			return fmt.Errorf("failed to claim device favoritos: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package favoritos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+const deviceToken = "0f8c1e6a-3b2d-4c59-9a7e-5d1f2b3c4d5e"
+
+func setupService(t *testing.T) Service {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&Favorito{}))
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY,
+		codigo TEXT,
+		titulo TEXT,
+		slug TEXT,
+		status TEXT,
+		deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, codigo, titulo, slug, status, deleted_at) VALUES
+		(1, 'AP001', 'Apartamento no centro', 'apartamento-ap001', 'PUBLICADO', NULL),
+		(2, 'CA002', 'Casa com quintal', 'casa-ca002', 'PUBLICADO', NULL),
+		(3, 'AP003', 'Apartamento em edição', 'apartamento-ap003', 'EM_EDICAO', NULL),
+		(4, 'AP004', 'Apartamento removido', 'apartamento-ap004', 'PUBLICADO', '2026-01-01 00:00:00')`).Error)
+
+	return NewService(NewRepository(database))
+}
+
+func listIDs(t *testing.T, svc Service, owner Owner) []uint {
+	t.Helper()
+
+	page, err := svc.ListFavoritos(context.Background(), owner, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	ids := make([]uint, 0, len(page.Results))
+	for _, favorito := range page.Results {
+		ids = append(ids, favorito.ImovelID)
+	}
+	return ids
+}
+
+func TestAddFavorito(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	user := Owner{UserID: 10}
+
+	created, err := svc.AddFavorito(ctx, user, 1)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = svc.AddFavorito(ctx, user, 1)
+	require.NoError(t, err)
+	assert.False(t, created, "saving again is a no-op")
+
+	for _, imovelID := range []uint{3, 4, 99} {
+		_, err := svc.AddFavorito(ctx, user, imovelID)
+		assert.ErrorIs(t, err, ErrImovelNotFound, "imovel %d", imovelID)
+	}
+
+	_, err = svc.AddFavorito(ctx, Owner{}, 1)
+	assert.ErrorIs(t, err, ErrNoOwner)
+
+	page, err := svc.ListFavoritos(ctx, user, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "AP001", page.Results[0].Codigo)
+	assert.Equal(t, "apartamento-ap001", page.Results[0].Slug)
+	assert.Empty(t, listIDs(t, svc, Owner{UserID: 11}), "favorites are per user")
+}
+
+func TestRemoveFavorito(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	device := Owner{DeviceToken: deviceToken}
+
+	_, err := svc.AddFavorito(ctx, device, 1)
+	require.NoError(t, err)
+	_, err = svc.AddFavorito(ctx, device, 2)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RemoveFavorito(ctx, device, 1))
+	require.NoError(t, svc.RemoveFavorito(ctx, device, 1), "removing again is a no-op")
+	assert.Equal(t, []uint{2}, listIDs(t, svc, device))
+}
+
+func TestClaimDevice(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	device := Owner{DeviceToken: deviceToken}
+
+	_, err := svc.AddFavorito(ctx, device, 1)
+	require.NoError(t, err)
+	_, err = svc.AddFavorito(ctx, device, 2)
+	require.NoError(t, err)
+	_, err = svc.AddFavorito(ctx, Owner{UserID: 10}, 1)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []uint{1, 2}, listIDs(t, svc, Owner{UserID: 10, DeviceToken: deviceToken}))
+	assert.Empty(t, listIDs(t, svc, device), "the device favorites moved to the user")
+	assert.ElementsMatch(t, []uint{1, 2}, listIDs(t, svc, Owner{UserID: 10}))
+}
+
+func TestListStats(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	for _, owner := range []Owner{{UserID: 10}, {UserID: 11}, {DeviceToken: deviceToken}} {
+		_, err := svc.AddFavorito(ctx, owner, 2)
+		require.NoError(t, err)
+	}
+	_, err := svc.AddFavorito(ctx, Owner{UserID: 10}, 1)
+	require.NoError(t, err)
+
+	page, err := svc.ListStats(ctx, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, []ImovelFavoritoStats{
+		{ImovelID: 2, Codigo: "CA002", Titulo: "Casa com quintal", Favoritos: 3, Usuarios: 2},
+		{ImovelID: 1, Codigo: "AP001", Titulo: "Apartamento no centro", Favoritos: 1, Usuarios: 1},
+	}, page.Results)
+}
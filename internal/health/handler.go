@@ -64,3 +64,18 @@ func (h *Handler) Ready(c *gin.Context) {
 
 	c.JSON(statusCode, response)
 }
+
+// Verbose godoc
+// @Summary      Verbose health report
+// @Description  Detailed health report with per-check latency, last errors, database pool stats, queue depths and runtime metrics (admin only)
+// @Tags         Health
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  VerboseHealthResponse
+// @Router       /api/v1/admin/health [get]
+func (h *Handler) Verbose(c *gin.Context) {
+	ctx := c.Request.Context()
+	response := h.service.GetVerboseHealth(ctx)
+	c.JSON(http.StatusOK, response)
+}
@@ -27,6 +27,10 @@ func (m *mockService) GetReadiness(ctx context.Context) HealthResponse {
 	return m.response
 }
 
+func (m *mockService) GetVerboseHealth(ctx context.Context) VerboseHealthResponse {
+	return VerboseHealthResponse{HealthResponse: m.response, Queues: map[string]int64{}}
+}
+
 func TestHandler_Health(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// MigrationStatusFunc reports the applied schema version, whether it is dirty, and the versions still pending
+type MigrationStatusFunc func(ctx context.Context) (current uint, dirty bool, pending []uint, err error)
+
+// MigrationChecker fails when the database schema is behind the migrations shipped with the binary
+type MigrationChecker struct {
+	status MigrationStatusFunc
+}
+
+func NewMigrationChecker(status MigrationStatusFunc) *MigrationChecker {
+	return &MigrationChecker{status: status}
+}
+
+func (m *MigrationChecker) Name() string {
+	return "migrations"
+}
+
+func (m *MigrationChecker) Check(ctx context.Context) CheckResult {
+	current, dirty, pending, err := m.status(ctx)
+	if err != nil {
+		return CheckResult{
+			Status:  CheckWarn,
+			Message: "Unable to determine migration status",
+		}
+	}
+
+	details := map[string]interface{}{
+		"current_version": current,
+		"pending":         pending,
+	}
+
+	if dirty {
+		return CheckResult{
+			Status:  CheckFail,
+			Message: fmt.Sprintf("Database schema is dirty at version %d", current),
+			Details: details,
+		}
+	}
+
+	if len(pending) > 0 {
+		return CheckResult{
+			Status:  CheckFail,
+			Message: fmt.Sprintf("%d pending migration(s)", len(pending)),
+			Details: details,
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckPass,
+		Message: "Database schema is up to date",
+		Details: details,
+	}
+}
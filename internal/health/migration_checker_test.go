@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationChecker_Name(t *testing.T) {
+	checker := NewMigrationChecker(nil)
+	assert.Equal(t, "migrations", checker.Name())
+}
+
+func TestMigrationChecker_Check(t *testing.T) {
+	tests := []struct {
+		name           string
+		current        uint
+		dirty          bool
+		pending        []uint
+		err            error
+		expectedStatus CheckStatus
+	}{
+		{name: "up to date", current: 20261017090000, expectedStatus: CheckPass},
+		{name: "pending migrations", current: 1, pending: []uint{2, 3}, expectedStatus: CheckFail},
+		{name: "dirty schema", current: 2, dirty: true, expectedStatus: CheckFail},
+		{name: "status unavailable", err: errors.New("no such directory"), expectedStatus: CheckWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewMigrationChecker(func(ctx context.Context) (uint, bool, []uint, error) {
+				return tt.current, tt.dirty, tt.pending, tt.err
+			})
+
+			result := checker.Check(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.NotEmpty(t, result.Message)
+		})
+	}
+}
@@ -31,5 +31,39 @@ type CheckResult struct {
 	Status       CheckStatus `json:"status"`
 	Message      string      `json:"message,omitempty"`
 	ResponseTime string      `json:"response_time,omitempty"`
+	LatencyMs    int64       `json:"latency_ms"`
+	LastError    string      `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time  `json:"last_error_at,omitempty"`
 	Details      interface{} `json:"details,omitempty"`
 }
+
+// VerboseHealthResponse extends the health report with internal metrics for operators
+type VerboseHealthResponse struct {
+	HealthResponse
+	Database *DatabasePoolStats `json:"database,omitempty"`
+	Queues   map[string]int64   `json:"queues"`
+	Runtime  RuntimeStats       `json:"runtime"`
+}
+
+// DatabasePoolStats mirrors sql.DBStats in a JSON-friendly form
+type DatabasePoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// RuntimeStats reports basic Go runtime metrics
+type RuntimeStats struct {
+	Goroutines int    `json:"goroutines"`
+	HeapAlloc  uint64 `json:"heap_alloc_bytes"`
+	NumGC      uint32 `json:"num_gc"`
+	GoVersion  string `json:"go_version"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+}
@@ -2,7 +2,12 @@ package health
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -10,33 +15,78 @@ type Service interface {
 	GetHealth(ctx context.Context) HealthResponse
 	GetLiveness(ctx context.Context) HealthResponse
 	GetReadiness(ctx context.Context) HealthResponse
+	GetVerboseHealth(ctx context.Context) VerboseHealthResponse
+}
+
+// QueueDepthFunc reports the number of pending entries in a background queue
+type QueueDepthFunc func(ctx context.Context) (int64, error)
+
+// MemoryQueue adapts the depth of an in-process queue, which cannot fail, to a QueueDepthFunc
+func MemoryQueue(depth func() int) QueueDepthFunc {
+	return func(ctx context.Context) (int64, error) {
+		return int64(depth()), nil
+	}
+}
+
+// Option configures optional service behaviour
+type Option func(*service)
+
+// WithCheckTimeout bounds the duration of each individual checker run
+func WithCheckTimeout(timeout time.Duration) Option {
+	return func(s *service) {
+		s.checkTimeout = timeout
+	}
+}
+
+// WithDBStats exposes connection pool statistics in the verbose report
+func WithDBStats(stats func() sql.DBStats) Option {
+	return func(s *service) {
+		s.dbStats = stats
+	}
+}
+
+// WithQueue registers a queue whose depth is included in the verbose report
+func WithQueue(name string, depth QueueDepthFunc) Option {
+	return func(s *service) {
+		s.queues[name] = depth
+	}
+}
+
+type lastError struct {
+	message string
+	at      time.Time
 }
 
 type service struct {
-	checkers    []Checker
-	startTime   time.Time
-	version     string
-	environment string
+	checkers     []Checker
+	startTime    time.Time
+	version      string
+	environment  string
+	checkTimeout time.Duration
+	dbStats      func() sql.DBStats
+	queues       map[string]QueueDepthFunc
+
+	mu         sync.Mutex
+	lastErrors map[string]lastError
 }
 
-func NewService(checkers []Checker, version, environment string) Service {
-	return &service{
+func NewService(checkers []Checker, version, environment string, opts ...Option) Service {
+	s := &service{
 		checkers:    checkers,
 		startTime:   time.Now(),
 		version:     version,
 		environment: environment,
+		queues:      make(map[string]QueueDepthFunc),
+		lastErrors:  make(map[string]lastError),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *service) GetHealth(ctx context.Context) HealthResponse {
-	return HealthResponse{
-		Status:      StatusHealthy,
-		Version:     s.version,
-		Timestamp:   time.Now(),
-		Uptime:      s.formatUptime(),
-		Environment: s.environment,
-		Checks:      make(map[string]CheckResult),
-	}
+	return s.runChecks(ctx)
 }
 
 func (s *service) GetLiveness(ctx context.Context) HealthResponse {
@@ -51,11 +101,56 @@ func (s *service) GetLiveness(ctx context.Context) HealthResponse {
 }
 
 func (s *service) GetReadiness(ctx context.Context) HealthResponse {
+	return s.runChecks(ctx)
+}
+
+func (s *service) GetVerboseHealth(ctx context.Context) VerboseHealthResponse {
+	response := VerboseHealthResponse{
+		HealthResponse: s.runChecks(ctx),
+		Queues:         make(map[string]int64, len(s.queues)),
+		Runtime:        runtimeStats(),
+	}
+
+	if s.dbStats != nil {
+		stats := s.dbStats()
+		response.Database = &DatabasePoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration.String(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+	}
+
+	names := make([]string, 0, len(s.queues))
+	for name := range s.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		depth, err := s.queues[name](ctx)
+		if err != nil {
+			slog.Warn("Failed to read queue depth", "queue", name, "error", err)
+			depth = -1
+		}
+		response.Queues[name] = depth
+	}
+
+	return response
+}
+
+// runChecks executes every checker, recording its latency and remembering the
+// most recent failure so operators can see intermittent errors after recovery
+func (s *service) runChecks(ctx context.Context) HealthResponse {
 	checks := make(map[string]CheckResult)
 	overallStatus := StatusHealthy
 
 	for _, checker := range s.checkers {
-		result := checker.Check(ctx)
+		result := s.runCheck(ctx, checker)
 		checks[checker.Name()] = result
 
 		if result.Status == CheckFail {
@@ -75,6 +170,56 @@ func (s *service) GetReadiness(ctx context.Context) HealthResponse {
 	}
 }
 
+func (s *service) runCheck(ctx context.Context, checker Checker) CheckResult {
+	checkCtx := ctx
+	if s.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, s.checkTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result := checker.Check(checkCtx)
+	latency := time.Since(start)
+
+	result.LatencyMs = latency.Milliseconds()
+	if result.ResponseTime == "" {
+		result.ResponseTime = fmt.Sprintf("%dms", latency.Milliseconds())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if result.Status != CheckPass {
+		message := result.Message
+		if message == "" {
+			message = string(result.Status)
+		}
+		s.lastErrors[checker.Name()] = lastError{message: message, at: time.Now()}
+	}
+	if last, ok := s.lastErrors[checker.Name()]; ok {
+		at := last.at
+		result.LastError = last.message
+		result.LastErrorAt = &at
+	}
+
+	return result
+}
+
+func runtimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return RuntimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		NumGC:      mem.NumGC,
+		GoVersion:  runtime.Version(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+	}
+}
+
 func (s *service) formatUptime() string {
 	uptime := time.Since(s.startTime)
 	days := int(uptime.Hours() / 24)
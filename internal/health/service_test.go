@@ -2,6 +2,8 @@ package health
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -142,3 +144,54 @@ func TestService_FormatUptime(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetHealth_RunsCheckers(t *testing.T) {
+	svc := NewService([]Checker{
+		&mockChecker{name: "db", result: CheckResult{Status: CheckWarn, Message: "Slow"}},
+	}, "1.0.0", "test")
+
+	response := svc.GetHealth(context.Background())
+
+	assert.Equal(t, StatusDegraded, response.Status)
+	assert.Contains(t, response.Checks, "db")
+	assert.NotEmpty(t, response.Checks["db"].ResponseTime)
+}
+
+func TestService_LastErrorIsRetainedAfterRecovery(t *testing.T) {
+	checker := &mockChecker{name: "db", result: CheckResult{Status: CheckFail, Message: "Database connection failed"}}
+	svc := NewService([]Checker{checker}, "1.0.0", "test")
+
+	failed := svc.GetReadiness(context.Background())
+	assert.Equal(t, StatusUnhealthy, failed.Status)
+	assert.Equal(t, "Database connection failed", failed.Checks["db"].LastError)
+
+	checker.result = CheckResult{Status: CheckPass, Message: "OK"}
+	recovered := svc.GetReadiness(context.Background())
+
+	assert.Equal(t, StatusHealthy, recovered.Status)
+	assert.Equal(t, "Database connection failed", recovered.Checks["db"].LastError)
+	assert.NotNil(t, recovered.Checks["db"].LastErrorAt)
+}
+
+func TestService_GetVerboseHealth(t *testing.T) {
+	svc := NewService(
+		[]Checker{&mockChecker{name: "db", result: CheckResult{Status: CheckPass}}},
+		"1.0.0", "test",
+		WithDBStats(func() sql.DBStats { return sql.DBStats{OpenConnections: 3, InUse: 1, Idle: 2} }),
+		WithQueue("emails", func(ctx context.Context) (int64, error) { return 7, nil }),
+		WithQueue("broken", func(ctx context.Context) (int64, error) { return 0, errors.New("unavailable") }),
+		WithQueue("anexos", MemoryQueue(func() int { return 2 })),
+	)
+
+	response := svc.GetVerboseHealth(context.Background())
+
+	assert.Equal(t, StatusHealthy, response.Status)
+	if assert.NotNil(t, response.Database) {
+		assert.Equal(t, 3, response.Database.OpenConnections)
+		assert.Equal(t, 1, response.Database.InUse)
+	}
+	assert.Equal(t, int64(7), response.Queues["emails"])
+	assert.Equal(t, int64(-1), response.Queues["broken"])
+	assert.Equal(t, int64(2), response.Queues["anexos"])
+	assert.Positive(t, response.Runtime.Goroutines)
+}
@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+
+	mail "github.com/wneessen/go-mail"
+)
+
+// SMTPChecker fails when the SMTP server refuses the connection or the credentials, so a deploy
+// with a wrong host or password is caught before the first email is sent
+type SMTPChecker struct {
+	client *mail.Client
+}
+
+// NewSMTPChecker checks the server of client, whose timeout bounds each SMTP command
+func NewSMTPChecker(client *mail.Client) *SMTPChecker {
+	return &SMTPChecker{client: client}
+}
+
+func (s *SMTPChecker) Name() string {
+	return "smtp"
+}
+
+func (s *SMTPChecker) Check(ctx context.Context) CheckResult {
+	conn, err := s.client.DialToSMTPClientWithContext(ctx)
+	if err != nil {
+		slog.Warn("SMTP health check failed", "server", s.client.ServerAddr(), "error", err)
+		return CheckResult{
+			Status:  CheckFail,
+			Message: "SMTP connection or authentication failed",
+		}
+	}
+	defer func() {
+		if err := s.client.CloseWithSMTPClient(conn); err != nil {
+			slog.Warn("Failed to close SMTP health check connection", "error", err)
+		}
+	}()
+
+	if err := conn.Noop(); err != nil {
+		slog.Warn("SMTP health check failed", "server", s.client.ServerAddr(), "error", err)
+		return CheckResult{
+			Status:  CheckFail,
+			Message: "SMTP server did not respond",
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckPass,
+		Message: "SMTP server accepting connections",
+	}
+}
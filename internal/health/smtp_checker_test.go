@@ -0,0 +1,121 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "github.com/wneessen/go-mail"
+)
+
+// startSMTPServer serves a minimal SMTP dialogue on localhost; authReply answers AUTH
+func startSMTPServer(t *testing.T, authReply string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, authReply)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveSMTP(conn net.Conn, authReply string) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " ")[0])
+		switch command {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply(authReply)
+		case "NOOP", "RSET":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+func newTestSMTPClient(t *testing.T, port int) *mail.Client {
+	t.Helper()
+	client, err := mail.NewClient("localhost",
+		mail.WithPort(port),
+		mail.WithTLSPolicy(mail.NoTLS),
+		mail.WithSMTPAuth(mail.SMTPAuthPlain),
+		mail.WithUsername("user"),
+		mail.WithPassword("secret"),
+		mail.WithTimeout(2*time.Second),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func TestSMTPChecker_Name(t *testing.T) {
+	checker := NewSMTPChecker(newTestSMTPClient(t, 25))
+	assert.Equal(t, "smtp", checker.Name())
+}
+
+func TestSMTPChecker_Check(t *testing.T) {
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := unreachable.Addr().(*net.TCPAddr).Port
+	require.NoError(t, unreachable.Close())
+
+	tests := []struct {
+		name           string
+		port           func(t *testing.T) int
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "server accepts the credentials",
+			port:           func(t *testing.T) int { return startSMTPServer(t, "235 Authentication successful") },
+			expectedStatus: CheckPass,
+		},
+		{
+			name:           "wrong credentials",
+			port:           func(t *testing.T) int { return startSMTPServer(t, "535 Authentication failed") },
+			expectedStatus: CheckFail,
+		},
+		{
+			name:           "server unreachable",
+			port:           func(t *testing.T) int { return closedPort },
+			expectedStatus: CheckFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewSMTPChecker(newTestSMTPClient(t, tt.port(t)))
+
+			result := checker.Check(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.NotEmpty(t, result.Message)
+		})
+	}
+}
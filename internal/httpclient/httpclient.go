@@ -0,0 +1,373 @@
+// Package httpclient sends the outbound HTTP requests of the services to third-party APIs, with
+// per-request timeouts, retries, default headers, trace propagation and logging of the responses
+// that could not be used.
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultTimeout applies to each attempt when WithTimeout is not given
+	DefaultTimeout = 30 * time.Second
+	// maxLoggedBody caps how much of a response body is logged
+	maxLoggedBody = 2048
+
+	tracerName = "github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+)
+
+// StatusError is a response with a status other than 200
+type StatusError struct {
+	Service    string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.Service, e.StatusCode)
+}
+
+// DecodeError is a response whose body is not the JSON expected
+type DecodeError struct {
+	Service string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to parse response of %s: %v", e.Service, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Transient reports whether a failed request may go through if sent again: 5xx and 429 responses
+// and transport failures such as timeouts and refused connections. Bodies that fail to decode are
+// not, as the same body comes back.
+func Transient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	var decodeErr *DecodeError
+	return !errors.As(err, &decodeErr)
+}
+
+// Backoff is the wait before the retry following attempt (0 for the first request): base doubled
+// on each attempt, capped at max, with half of it randomized so concurrent callers do not retry
+// in step
+func Backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// RetryFunc decides whether a request that failed with err on attempt (0 for the first request)
+// is sent again, and after how long
+type RetryFunc func(attempt int, err error) (time.Duration, bool)
+
+// Hooks are called around the attempts of a request; all are optional
+type Hooks struct {
+	// BeforeAttempt runs before every attempt, retries included, e.g. to wait on a rate limit; an
+	// error fails the request without sending it
+	BeforeAttempt func(ctx context.Context, req *http.Request) error
+	// OnRetry runs before waiting delay to send again a request that failed with err
+	OnRetry func(ctx context.Context, req *http.Request, attempt int, delay time.Duration, err error)
+}
+
+// Client sends requests to one service. It is safe for concurrent use.
+type Client struct {
+	service      string
+	http         *http.Client
+	timeout      time.Duration
+	header       http.Header
+	retry        RetryFunc
+	hooks        Hooks
+	logger       func(context.Context) *slog.Logger
+	logResponses bool
+	tracer       trace.Tracer
+	propagator   propagation.TextMapPropagator
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends the requests through client, e.g. to share its transport. Its Timeout
+// should be left unset: WithTimeout applies to each attempt.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.http = client
+		}
+	}
+}
+
+// WithTimeout bounds each attempt of a request, reading the body included
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithHeader sets a header on every request; the headers given to a request take precedence
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithRetry sends failed requests again as retry decides. Without it every request is sent once.
+func WithRetry(retry RetryFunc) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithHooks calls hooks around the attempts of every request
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = hooks
+	}
+}
+
+// WithLogger writes the logs of a request to the logger returned for its context instead of
+// slog.Default
+func WithLogger(logger func(context.Context) *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithResponseLogging logs the status, content type and start of the body of the responses with an
+// unexpected status or a body that failed to decode
+func WithResponseLogging(enabled bool) Option {
+	return func(c *Client) {
+		c.logResponses = enabled
+	}
+}
+
+// WithTracerProvider creates the spans of the requests with provider instead of the global one
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) {
+		if provider != nil {
+			c.tracer = provider.Tracer(tracerName)
+		}
+	}
+}
+
+// WithPropagator injects the trace context into the requests with propagator instead of the
+// global one
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Client) {
+		if propagator != nil {
+			c.propagator = propagator
+		}
+	}
+}
+
+// New creates a client for service, the name used in errors, logs and spans (e.g. "external API")
+func New(service string, opts ...Option) *Client {
+	c := &Client{
+		service: service,
+		http:    &http.Client{},
+		timeout: DefaultTimeout,
+		header:  http.Header{},
+		logger:  func(context.Context) *slog.Logger { return slog.Default() },
+		tracer:  otel.Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+	return c
+}
+
+// Get fetches url with header and returns the body of its 200 response, retrying the failures
+// as configured. Other statuses return a *StatusError.
+func (c *Client) Get(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, url, header)
+		if err != nil {
+			return nil, err
+		}
+		if c.hooks.BeforeAttempt != nil {
+			if err := c.hooks.BeforeAttempt(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := c.attempt(req, attempt)
+		if err == nil || ctx.Err() != nil || c.retry == nil {
+			return body, err
+		}
+		delay, retry := c.retry(attempt, err)
+		if !retry {
+			return nil, err
+		}
+
+		if c.hooks.OnRetry != nil {
+			c.hooks.OnRetry(ctx, req, attempt, delay, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// GetJSON fetches url like Get and decodes its body into v. A body that is not the JSON expected
+// returns a *DecodeError.
+func (c *Client) GetJSON(ctx context.Context, url string, header http.Header, v interface{}) error {
+	body, err := c.Get(ctx, url, header)
+	if err != nil {
+		return err
+	}
+	return c.Decode(ctx, url, body, v)
+}
+
+// Decode decodes the JSON body of the response to url into v, logging the body that failed to
+// decode when response logging is enabled
+func (c *Client) Decode(ctx context.Context, url string, body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		if c.logResponses {
+			c.logger(ctx).Warn("Malformed response from "+c.service, "url", url, "error", err,
+				"size", len(body), "body", truncate(body))
+		}
+		return &DecodeError{Service: c.service, Err: err}
+	}
+	return nil
+}
+
+// Do sends req once, traced and with the default headers, for responses the caller reads as a
+// stream. The caller closes the body; the deadline of req's context applies instead of the timeout.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	c.setHeaders(req, nil)
+	ctx, span := c.startSpan(req.Context(), req, 0)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := c.http.Do(req)
+	endSpan(span, resp, err)
+	return resp, err
+}
+
+func (c *Client) newRequest(ctx context.Context, url string, header http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req, header)
+	return req, nil
+}
+
+// setHeaders sets the default headers on req, then header
+func (c *Client) setHeaders(req *http.Request, header http.Header) {
+	for key, values := range c.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+}
+
+// attempt sends req once within the timeout and reads its response
+func (c *Client) attempt(req *http.Request, attempt int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+	defer cancel()
+	ctx, span := c.startSpan(ctx, req, attempt)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := c.http.Do(req)
+	if err != nil {
+		endSpan(span, nil, err)
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		endSpan(span, resp, err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &StatusError{Service: c.service, StatusCode: resp.StatusCode}
+		endSpan(span, resp, statusErr)
+		if c.logResponses {
+			c.logger(ctx).Warn("Unexpected response from "+c.service, "url", req.URL.String(), "status", resp.StatusCode,
+				"attempt", attempt+1, "content_type", resp.Header.Get("Content-Type"), "size", len(body), "body", truncate(body))
+		}
+		return nil, statusErr
+	}
+	endSpan(span, resp, nil)
+	return body, nil
+}
+
+// startSpan starts the client span of an attempt of req under ctx
+func (c *Client) startSpan(ctx context.Context, req *http.Request, attempt int) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.String()),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("peer.service", c.service),
+	}
+	if attempt > 0 {
+		attrs = append(attrs, attribute.Int("http.request.resend_count", attempt))
+	}
+	return c.tracer.Start(ctx, req.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an attempt on its span
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// truncate returns the start of body for the logs
+func truncate(body []byte) string {
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "..."
+	}
+	return string(body)
+}
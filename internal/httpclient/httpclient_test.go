@@ -0,0 +1,173 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func retryUpTo(max int) RetryFunc {
+	return func(attempt int, err error) (time.Duration, bool) {
+		return time.Millisecond, Transient(err) && attempt < max
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		var requests atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}))
+		defer server.Close()
+
+		var attempts, retries int
+		client := New("test API", WithRetry(retryUpTo(3)), WithHooks(Hooks{
+			BeforeAttempt: func(context.Context, *http.Request) error { attempts++; return nil },
+			OnRetry:       func(context.Context, *http.Request, int, time.Duration, error) { retries++ },
+		}))
+
+		body, err := client.Get(ctx, server.URL, nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ok":true}`, string(body))
+		assert.Equal(t, int64(3), requests.Load())
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, 2, retries)
+	})
+
+	t.Run("not client errors", func(t *testing.T) {
+		var requests atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := New("test API", WithRetry(retryUpTo(3))).Get(ctx, server.URL, nil)
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		assert.EqualError(t, err, "test API returned status 404")
+		assert.Equal(t, int64(1), requests.Load())
+	})
+
+	t.Run("timeout per attempt", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		_, err := New("test API", WithTimeout(20*time.Millisecond)).Get(ctx, server.URL, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, Transient(err))
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		var received http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+		}))
+		defer server.Close()
+
+		client := New("test API", WithHeader("User-Agent", "triiio"), WithHeader("X-Source", "default"))
+		_, err := client.Get(ctx, server.URL, http.Header{"X-Source": {"request"}})
+		require.NoError(t, err)
+		assert.Equal(t, "triiio", received.Get("User-Agent"))
+		assert.Equal(t, "request", received.Get("X-Source"))
+	})
+
+	t.Run("propagates the trace", func(t *testing.T) {
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+		}))
+		defer server.Close()
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		traced := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+		}))
+
+		client := New("test API", WithPropagator(propagation.TraceContext{}))
+		_, err := client.Get(traced, server.URL, nil)
+		require.NoError(t, err)
+		assert.Contains(t, traceparent, traceID.String())
+	})
+}
+
+func TestClient_GetJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			_, _ = w.Write([]byte(`<html>Bad gateway</html>`))
+			return
+		}
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`upstream down`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	client := New("test API", WithResponseLogging(true), WithLogger(func(context.Context) *slog.Logger { return logger }))
+
+	var result struct{ ID int }
+	require.NoError(t, client.GetJSON(context.Background(), server.URL+"/ok", nil, &result))
+	assert.Equal(t, 7, result.ID)
+	assert.Empty(t, logs.String())
+
+	err := client.GetJSON(context.Background(), server.URL+"/broken", nil, &result)
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.False(t, Transient(err))
+	assert.Contains(t, logs.String(), "Malformed response from test API")
+	assert.Contains(t, logs.String(), "Bad gateway")
+
+	logs.Reset()
+	err = client.GetJSON(context.Background(), server.URL+"/error", nil, &result)
+	assert.EqualError(t, err, "test API returned status 502")
+	assert.Contains(t, logs.String(), "upstream down")
+
+	t.Run("quiet by default", func(t *testing.T) {
+		logs.Reset()
+		quiet := New("test API", WithLogger(func(context.Context) *slog.Logger { return logger }))
+		err := quiet.GetJSON(context.Background(), server.URL+"/broken", nil, &result)
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Empty(t, logs.String())
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 3; attempt++ {
+		full := 100 * time.Millisecond << attempt
+		delay := Backoff(100*time.Millisecond, 30*time.Second, attempt)
+		assert.GreaterOrEqual(t, delay, full/2)
+		assert.LessOrEqual(t, delay, full)
+	}
+	assert.LessOrEqual(t, Backoff(time.Second, 30*time.Second, 40), 30*time.Second)
+	assert.LessOrEqual(t, Backoff(time.Second, 30*time.Second, 70), 30*time.Second)
+}
@@ -0,0 +1,237 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/media"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+const (
+	// anexoQueueSize bounds the anexos waiting for renditions; further anexos are skipped
+	anexoQueueSize = 256
+	// anexoProcessTimeout bounds the download, resizing and upload of one anexo
+	anexoProcessTimeout = 2 * time.Minute
+	// maxAnexoSourceBytes bounds the original image read from storage or an external URL
+	maxAnexoSourceBytes = 40 << 20
+	// defaultImageWorkers applies when storage.image_workers is not configured
+	defaultImageWorkers = 2
+)
+
+// ErrAnexoNotFound is returned when an attachment does not exist
+var ErrAnexoNotFound = errors.New("attachment not found")
+
+// AnexoProcessor generates resized renditions of image attachments in the background
+type AnexoProcessor interface {
+	// Enqueue schedules an anexo for processing without blocking the caller
+	Enqueue(anexoID uint)
+	// Process generates, stores and records the renditions of an anexo
+	Process(ctx context.Context, anexoID uint) error
+	// QueueDepth reports the anexos waiting to be processed
+	QueueDepth() int
+	// Close stops accepting anexos and waits for the queued ones until ctx is done
+	Close(ctx context.Context) error
+}
+
+type anexoProcessor struct {
+	repo       Repository
+	storage    storage.Storage
+	httpClient *http.Client
+	queue      chan uint
+	mu         sync.RWMutex
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// NewAnexoProcessor creates a processor and starts its workers. Renditions are written to store
+// under anexos/{id}/.
+func NewAnexoProcessor(repo Repository, store storage.Storage, workers int) AnexoProcessor {
+	if workers <= 0 {
+		workers = defaultImageWorkers
+	}
+
+	p := &anexoProcessor{
+		repo:       repo,
+		storage:    store,
+		httpClient: &http.Client{Timeout: anexoProcessTimeout},
+		queue:      make(chan uint, anexoQueueSize),
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Enqueue schedules an anexo; when the queue is full the anexo is skipped and keeps no variants
+func (p *anexoProcessor) Enqueue(anexoID uint) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- anexoID:
+	default:
+		log.Printf("Image processing queue is full, skipping renditions for anexo %d", anexoID)
+	}
+}
+
+// QueueDepth reports the anexos waiting to be processed
+func (p *anexoProcessor) QueueDepth() int {
+	return len(p.queue)
+}
+
+// Close stops accepting anexos and waits for the workers to drain the queue
+func (p *anexoProcessor) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("image processing did not finish: %w", ctx.Err())
+	}
+}
+
+func (p *anexoProcessor) work() {
+	defer p.wg.Done()
+	for anexoID := range p.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), anexoProcessTimeout)
+		if err := p.Process(ctx, anexoID); err != nil {
+			log.Printf("Failed to generate renditions for anexo %d: %v", anexoID, err)
+		}
+		cancel()
+	}
+}
+
+// Process generates a JPEG and a WebP file for every media.DefaultRenditions entry.
+// Non-image anexos are ignored.
+func (p *anexoProcessor) Process(ctx context.Context, anexoID uint) error {
+	anexo, err := p.repo.FindAnexoByID(ctx, anexoID)
+	if err != nil {
+		return fmt.Errorf("failed to find attachment: %w", err)
+	}
+	if anexo == nil {
+		return ErrAnexoNotFound
+	}
+	if !anexo.Image {
+		return nil
+	}
+
+	data, err := p.readSource(ctx, anexo)
+	if err != nil {
+		return err
+	}
+	img, err := media.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	variants := make(AnexoVariants, len(media.DefaultRenditions))
+	for _, rendition := range media.DefaultRenditions {
+		resized := media.Resize(img, rendition.MaxWidth)
+		variant, err := p.storeRendition(ctx, anexo.ID, rendition.Name, resized)
+		if err != nil {
+			return err
+		}
+		variants[rendition.Name] = *variant
+	}
+
+	if err := p.repo.UpdateAnexoVariants(ctx, anexo.ID, variants); err != nil {
+		return fmt.Errorf("failed to save attachment variants: %w", err)
+	}
+	return nil
+}
+
+// storeRendition uploads the JPEG rendition and, when it is smaller, the WebP one
+func (p *anexoProcessor) storeRendition(ctx context.Context, anexoID uint, name string, img image.Image) (*AnexoVariant, error) {
+	encoded, err := media.EncodeRendition(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s rendition: %w", name, err)
+	}
+
+	key := fmt.Sprintf("anexos/%d/%s", anexoID, name)
+	jpegObj, err := p.storage.Put(ctx, key+".jpg", bytes.NewReader(encoded.JPEG), int64(len(encoded.JPEG)), "image/jpeg")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store %s rendition: %w", name, err)
+	}
+
+	variant := &AnexoVariant{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+		URL:    jpegObj.URL,
+	}
+	if encoded.WebP != nil {
+		webpObj, err := p.storage.Put(ctx, key+".webp", bytes.NewReader(encoded.WebP), int64(len(encoded.WebP)), "image/webp")
+		if err != nil {
+			return nil, fmt.Errorf("failed to store %s webp rendition: %w", name, err)
+		}
+		variant.WebPURL = webpObj.URL
+	}
+	return variant, nil
+}
+
+// readSource loads the original image from storage for uploaded anexos or downloads it otherwise
+func (p *anexoProcessor) readSource(ctx context.Context, anexo *Anexo) ([]byte, error) {
+	var body io.ReadCloser
+	if !anexo.IsExternalURL && anexo.Path != "" {
+		reader, err := p.storage.Get(ctx, anexo.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read original image: %w", err)
+		}
+		body = reader
+	} else {
+		if anexo.URL == "" {
+			return nil, fmt.Errorf("attachment %d has no path or URL", anexo.ID)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, anexo.URL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download original image: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to download original image: status %d", resp.StatusCode)
+		}
+		body = resp.Body
+	}
+	defer func() {
+		if err := body.Close(); err != nil {
+			log.Printf("Failed to close image source: %v", err)
+		}
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxAnexoSourceBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original image: %w", err)
+	}
+	if len(data) > maxAnexoSourceBytes {
+		return nil, fmt.Errorf("original image exceeds %d bytes", maxAnexoSourceBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,152 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+func TestAnexoProcessor_UploadedImage(t *testing.T) {
+	_, database := setupCreateService(t)
+	// The worker goroutine must see the same in-memory database
+	sqlDB, err := database.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := storage.NewLocalStorage(dir, "")
+	repo := NewRepository(database)
+	processor := NewAnexoProcessor(repo, store, 1)
+	svc := NewService(repo, WithAnexoProcessor(processor))
+
+	imovel, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	// A flat floor-plan-like image, where lossless WebP beats JPEG
+	plan := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+	for i := range plan.Pix {
+		plan.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, plan))
+
+	uploads := NewUploadService(svc, store, &config.StorageConfig{})
+	anexo, err := uploads.UploadAnexo(ctx, imovel.ID, &UploadedFile{
+		Nome:    "planta.png",
+		Size:    int64(buf.Len()),
+		Content: bytes.NewReader(buf.Bytes()),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, anexo.Variants, "renditions are generated asynchronously")
+
+	closeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	require.NoError(t, processor.Close(closeCtx))
+
+	anexos, err := svc.GetAnexos(ctx, imovel.ID)
+	require.NoError(t, err)
+	require.Len(t, anexos, 1)
+	variants := anexos[0].Variants
+	require.Len(t, variants, 3)
+
+	assert.Equal(t, 320, variants["thumbnail"].Width)
+	assert.Equal(t, 160, variants["thumbnail"].Height)
+	assert.Equal(t, 800, variants["medium"].Width)
+	assert.Equal(t, 1000, variants["large"].Width, "images are not scaled up")
+
+	thumbnail := variants["thumbnail"]
+	assert.Equal(t, "/uploads/anexos/1/thumbnail.jpg", thumbnail.URL)
+	assert.Equal(t, "/uploads/anexos/1/thumbnail.webp", thumbnail.WebPURL)
+	for _, name := range []string{"thumbnail.jpg", "thumbnail.webp", "large.jpg"} {
+		_, err := os.Stat(filepath.Join(dir, "anexos", "1", name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestAnexoProcessor_ExternalImage(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	// Noise compresses poorly losslessly, so only the JPEG rendition is kept
+	photo := image.NewNRGBA(image.Rect(0, 0, 400, 300))
+	rand.New(rand.NewSource(1)).Read(photo.Pix)
+	for i := 3; i < len(photo.Pix); i += 4 {
+		photo.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, photo, nil))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foto.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	imovel, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	repo := NewRepository(database)
+	processor := NewAnexoProcessor(repo, storage.NewLocalStorage(t.TempDir(), ""), 1)
+	defer func() { _ = processor.Close(ctx) }()
+
+	foto := &Anexo{Nome: "foto.jpg", URL: server.URL + "/foto.jpg", Image: true, IsExternalURL: true}
+	require.NoError(t, svc.AddAnexo(ctx, imovel.ID, foto))
+	missing := &Anexo{Nome: "gone.jpg", URL: server.URL + "/gone.jpg", Image: true, IsExternalURL: true}
+	require.NoError(t, svc.AddAnexo(ctx, imovel.ID, missing))
+	pdf := &Anexo{Nome: "contrato.pdf", URL: server.URL + "/contrato.pdf", IsExternalURL: true}
+	require.NoError(t, svc.AddAnexo(ctx, imovel.ID, pdf))
+
+	require.NoError(t, processor.Process(ctx, foto.ID))
+	stored, err := repo.FindAnexoByID(ctx, foto.ID)
+	require.NoError(t, err)
+	require.Contains(t, stored.Variants, "thumbnail")
+	assert.Equal(t, 240, stored.Variants["thumbnail"].Height)
+	assert.Equal(t, "/uploads/anexos/1/thumbnail.jpg", stored.Variants["thumbnail"].URL)
+	assert.Empty(t, stored.Variants["thumbnail"].WebPURL)
+	assert.Equal(t, 400, stored.Variants["large"].Width)
+
+	assert.Error(t, processor.Process(ctx, missing.ID))
+	require.NoError(t, processor.Process(ctx, pdf.ID), "non-image anexos are skipped")
+	stored, err = repo.FindAnexoByID(ctx, pdf.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Variants)
+
+	assert.ErrorIs(t, processor.Process(ctx, 999), ErrAnexoNotFound)
+}
+
+func TestAnexoVariants_ScanValue(t *testing.T) {
+	variants := AnexoVariants{"thumbnail": {Width: 320, Height: 240, URL: "/t.jpg"}}
+	value, err := variants.Value()
+	require.NoError(t, err)
+
+	var scanned AnexoVariants
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, variants, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	var empty AnexoVariants
+	value, err = empty.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
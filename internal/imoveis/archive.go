@@ -0,0 +1,236 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+const (
+	// archiveBatchSize bounds the properties archived by a single run; later runs pick up the rest
+	archiveBatchSize = 200
+	// defaultArchiveInterval applies when archive.interval_seconds is not configured
+	defaultArchiveInterval = 24 * time.Hour
+)
+
+// ArchiveNotifier sends a corretor the summary of the properties archived from their portfolio
+type ArchiveNotifier interface {
+	NotifyArchived(ctx context.Context, corretor *CorretorPrincipalResponse, archived []ArchivedImovel) error
+}
+
+// WithStaleArchive sets the archive period of organizacoes without their own dias_arquivamento
+// (0 never archives them) and the notifier told about archived properties, which may be nil
+func WithStaleArchive(defaultDays int, notifier ArchiveNotifier) ServiceOption {
+	return func(s *service) {
+		s.archiveDefaultDays = defaultDays
+		s.archiveNotifier = notifier
+	}
+}
+
+// ArchiveStale archives the published properties not updated or imported within the archive period
+// of their organizacao, recording the reason in the audit trail, and sends each corretor a summary.
+// Failures on a property or notification are reported in the response without stopping the run.
+func (s *service) ArchiveStale(ctx context.Context, now time.Time) (*ArchiveStaleResponse, error) {
+	ctx = withPriceOrigin(ctx, PriceOriginArchiver)
+
+	stale, err := s.repo.FindStale(ctx, now, s.archiveDefaultDays, archiveBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stale properties: %w", err)
+	}
+
+	result := &ArchiveStaleResponse{Arquivados: []ArchivedImovel{}}
+	for i := range stale {
+		archived, err := s.archiveStale(ctx, &stale[i])
+		if err != nil {
+			result.Falhas = append(result.Falhas, fmt.Sprintf("property %d: %v", stale[i].ID, err))
+			continue
+		}
+		result.Arquivados = append(result.Arquivados, *archived)
+	}
+
+	if s.archiveNotifier != nil {
+		s.notifyArchived(ctx, result)
+	}
+	return result, nil
+}
+
+// archiveStale archives one stale property and audits it with the reason
+func (s *service) archiveStale(ctx context.Context, imovel *Imovel) (*ArchivedImovel, error) {
+	dias := s.archiveDefaultDays
+	if imovel.CorretorPrincipal != nil && imovel.CorretorPrincipal.Organizacao != nil && imovel.CorretorPrincipal.Organizacao.DiasArquivamento != nil {
+		dias = *imovel.CorretorPrincipal.Organizacao.DiasArquivamento
+	}
+	motivo := fmt.Sprintf("sem atualização há mais de %d dias (última em %s)", dias, imovel.UpdatedAt.Format("02/01/2006"))
+
+	if err := s.repo.UpdateStatus(ctx, imovel.ID, StatusArquivado, false); err != nil {
+		return nil, fmt.Errorf("failed to archive: %w", err)
+	}
+	response, err := s.auditedTransition(withAuditMotivo(ctx, motivo), imovel, AuditAcaoUnpublish)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchivedImovel{
+		ID:                response.ID,
+		Codigo:            response.Codigo,
+		Titulo:            response.Titulo,
+		Motivo:            motivo,
+		UltimaAtualizacao: imovel.UpdatedAt,
+		CorretorPrincipal: response.CorretorPrincipal,
+	}, nil
+}
+
+// notifyArchived sends one summary per corretor. Properties without a corretor email are skipped.
+func (s *service) notifyArchived(ctx context.Context, result *ArchiveStaleResponse) {
+	byCorretor := map[uint][]ArchivedImovel{}
+	corretores := map[uint]*CorretorPrincipalResponse{}
+	for _, archived := range result.Arquivados {
+		corretor := archived.CorretorPrincipal
+		if corretor == nil || corretor.Email == "" {
+			continue
+		}
+		byCorretor[corretor.ID] = append(byCorretor[corretor.ID], archived)
+		corretores[corretor.ID] = corretor
+	}
+
+	ids := make([]uint, 0, len(byCorretor))
+	for id := range byCorretor {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if err := s.archiveNotifier.NotifyArchived(ctx, corretores[id], byCorretor[id]); err != nil {
+			slog.Warn("Failed to notify corretor of archived properties", "corretor_id", id, "error", err)
+			result.Falhas = append(result.Falhas, fmt.Sprintf("notification to corretor %d: %v", id, err))
+			continue
+		}
+		result.Notificados++
+	}
+}
+
+type emailArchiveNotifier struct {
+	sender email.Service
+}
+
+// NewEmailArchiveNotifier emails the summary to the corretor using the notification template
+func NewEmailArchiveNotifier(sender email.Service) ArchiveNotifier {
+	return &emailArchiveNotifier{sender: sender}
+}
+
+func (n *emailArchiveNotifier) NotifyArchived(ctx context.Context, corretor *CorretorPrincipalResponse, archived []ArchivedImovel) error {
+	details := make(map[string]string, len(archived))
+	for _, imovel := range archived {
+		details[imovel.Codigo] = fmt.Sprintf("%s — %s", imovel.Titulo, imovel.Motivo)
+	}
+
+	_, err := n.sender.SendTemplateEmail(ctx, &email.SendTemplateEmailRequest{
+		To:           []string{corretor.Email},
+		Subject:      fmt.Sprintf("%d imóveis arquivados por falta de atualização", len(archived)),
+		TemplateName: "notification",
+		TemplateData: map[string]interface{}{
+			"Type":      "warning",
+			"Title":     "Anúncios arquivados",
+			"Message":   fmt.Sprintf("Olá, %s. Os imóveis abaixo estavam sem atualização e foram arquivados. Atualize os dados e publique novamente para reativá-los.", corretor.Nome),
+			"Details":   details,
+			"Timestamp": time.Now().Format("02/01/2006 15:04"),
+		},
+	})
+	return err
+}
+
+// NewArchiveStaleTask exposes the archiver as the archive_stale maintenance task. It unpublishes
+// listings, so it runs only when named in the request.
+func NewArchiveStaleTask(service Service) maintenance.Task {
+	return maintenance.TaskFunc{
+		TaskName: "archive_stale",
+		Explicit: true,
+		Fn: func(ctx context.Context, report maintenance.ProgressFunc) error {
+			result, err := service.ArchiveStale(ctx, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			report(len(result.Arquivados), len(result.Arquivados)+len(result.Falhas))
+			return nil
+		},
+	}
+}
+
+// StaleArchiver runs ArchiveStale periodically in the background
+type StaleArchiver interface {
+	// Close stops the archiver and waits for the current run until ctx is done
+	Close(ctx context.Context) error
+}
+
+type staleArchiver struct {
+	service  Service
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewStaleArchiver creates an archiver and starts it, running every interval. The first run waits
+// one interval so restarts do not archive right away.
+func NewStaleArchiver(service Service, interval time.Duration) StaleArchiver {
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &staleArchiver{
+		service:  service,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go a.loop(ctx)
+	return a
+}
+
+func (a *staleArchiver) loop(ctx context.Context) {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.run(ctx)
+		}
+	}
+}
+
+func (a *staleArchiver) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, a.interval)
+	defer cancel()
+
+	result, err := a.service.ArchiveStale(ctx, time.Now().UTC())
+	if err != nil {
+		slog.Error("Stale listings archive run failed", "error", err)
+		return
+	}
+	if len(result.Arquivados) > 0 || len(result.Falhas) > 0 {
+		slog.Info("Archived stale listings", "arquivados", len(result.Arquivados), "notificados", result.Notificados, "falhas", len(result.Falhas))
+	}
+}
+
+// Close cancels the pending work and waits for the loop to exit until ctx is done
+func (a *staleArchiver) Close(ctx context.Context) error {
+	a.once.Do(a.cancel)
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
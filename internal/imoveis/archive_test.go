@@ -0,0 +1,118 @@
+package imoveis
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type recordingArchiveNotifier struct {
+	summaries map[string][]string
+}
+
+func (n *recordingArchiveNotifier) NotifyArchived(ctx context.Context, corretor *CorretorPrincipalResponse, archived []ArchivedImovel) error {
+	for _, imovel := range archived {
+		n.summaries[corretor.Email] = append(n.summaries[corretor.Email], imovel.Codigo)
+	}
+	return nil
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// createStale creates a property of the corretor with the given status, last updated at updatedAt
+func createStale(t *testing.T, svc Service, database *gorm.DB, codigo string, corretorID uint, status string, updatedAt time.Time) uint {
+	t.Helper()
+
+	created, err := svc.CreateImovel(context.Background(), nestedCreateRequest(codigo))
+	require.NoError(t, err)
+	require.NoError(t, database.Model(&Imovel{}).Where("id = ?", created.ID).UpdateColumns(map[string]interface{}{
+		"corretor_principal_id": corretorID,
+		"status":                status,
+		"published":             status == StatusPublicado,
+		"updated_at":            updatedAt,
+	}).Error)
+	return created.ID
+}
+
+func TestArchiveStale(t *testing.T) {
+	_, database := setupCreateService(t)
+	notifier := &recordingArchiveNotifier{summaries: map[string][]string{}}
+	svc := NewService(NewRepository(database), WithStaleArchive(180, notifier))
+	ctx := context.Background()
+	now := time.Now().UTC()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	// Sol archives after 30 days, Lua never does and Mar uses the default
+	for _, org := range []Organizacao{
+		{ID: 1, Nome: "Sol", DiasArquivamento: intPtr(30)},
+		{ID: 2, Nome: "Lua", DiasArquivamento: intPtr(0)},
+		{ID: 3, Nome: "Mar"},
+	} {
+		require.NoError(t, database.Create(&org).Error)
+	}
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, nome, email, organizacao_id) VALUES
+		(1, 'Ana', 'ana@sol.com', 1), (2, 'Bia', 'bia@lua.com', 2), (3, 'Caio', 'caio@mar.com', 3)`).Error)
+
+	solStale := createStale(t, svc, database, "AP-001", 1, StatusPublicado, daysAgo(40))
+	createStale(t, svc, database, "AP-002", 1, StatusPublicado, daysAgo(10))
+	createStale(t, svc, database, "AP-003", 2, StatusPublicado, daysAgo(400))
+	marStale := createStale(t, svc, database, "AP-004", 3, StatusPublicado, daysAgo(200))
+	createStale(t, svc, database, "AP-005", 3, StatusPublicado, daysAgo(100))
+	orphanStale := createStale(t, svc, database, "AP-006", 0, StatusPublicado, daysAgo(200))
+	createStale(t, svc, database, "AP-007", 3, StatusEmEdicao, daysAgo(300))
+
+	result, err := svc.ArchiveStale(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, result.Falhas)
+
+	var archivedIDs []uint
+	for _, archived := range result.Arquivados {
+		archivedIDs = append(archivedIDs, archived.ID)
+	}
+	sort.Slice(archivedIDs, func(i, j int) bool { return archivedIDs[i] < archivedIDs[j] })
+	assert.Equal(t, []uint{solStale, marStale, orphanStale}, archivedIDs)
+
+	archived, err := svc.GetImovel(ctx, solStale)
+	require.NoError(t, err)
+	assert.Equal(t, StatusArquivado, archived.Status)
+	assert.False(t, archived.Published)
+
+	audit, err := svc.GetAuditLog(ctx, solStale, &AuditListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, audit.Results)
+	assert.Equal(t, AuditAcaoUnpublish, audit.Results[0].Acao)
+	assert.Equal(t, PriceOriginArchiver, audit.Results[0].Origem)
+	assert.Contains(t, audit.Results[0].Motivo, "mais de 30 dias")
+
+	// One summary per corretor with an email; the property without corretor is not notified
+	assert.Equal(t, 2, result.Notificados)
+	assert.Equal(t, map[string][]string{
+		"ana@sol.com":  {"AP-001"},
+		"caio@mar.com": {"AP-004"},
+	}, notifier.summaries)
+
+	t.Run("archived properties are not picked again", func(t *testing.T) {
+		result, err := svc.ArchiveStale(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, result.Arquivados)
+	})
+
+	t.Run("without a default only organizacoes with a period archive", func(t *testing.T) {
+		svc := NewService(NewRepository(database))
+		createStale(t, svc, database, "AP-008", 3, StatusPublicado, daysAgo(1000))
+		sol := createStale(t, svc, database, "AP-009", 1, StatusPublicado, daysAgo(31))
+
+		result, err := svc.ArchiveStale(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, result.Arquivados, 1)
+		assert.Equal(t, sol, result.Arquivados[0].ID)
+		assert.Zero(t, result.Notificados)
+	})
+}
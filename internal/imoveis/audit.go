@@ -0,0 +1,235 @@
+package imoveis
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+// Audited entities
+const (
+	AuditEntidadeImovel          = "IMOVEL"
+	AuditEntidadeAnexo           = "ANEXO"
+	AuditEntidadeCaracteristicas = "CARACTERISTICAS"
+	AuditEntidadePacote          = "PACOTE"
+	AuditEntidadePrecoVenda      = "PRECO_VENDA"
+	AuditEntidadePrecoAluguel    = "PRECO_ALUGUEL"
+)
+
+// Audited actions
+const (
+	AuditAcaoCreate     = "CREATE"
+	AuditAcaoUpdate     = "UPDATE"
+	AuditAcaoDelete     = "DELETE"
+	AuditAcaoHardDelete = "HARD_DELETE"
+	AuditAcaoRestore    = "RESTORE"
+	AuditAcaoPublish    = "PUBLISH"
+	AuditAcaoUnpublish  = "UNPUBLISH"
+)
+
+// auditIgnoredFields are bookkeeping fields left out of the diffs, at any nesting level
+var auditIgnoredFields = map[string]bool{
+	"created_at":    true,
+	"updated_at":    true,
+	"version":       true,
+	"visualizacoes": true,
+	"lockedFields":  true,
+}
+
+// AuditChange is the previous and new value of a changed field
+type AuditChange struct {
+	Anterior interface{} `json:"anterior"`
+	Novo     interface{} `json:"novo"`
+}
+
+// AuditChanges maps dotted field paths (precoVenda.preco) to their change, stored as JSONB
+type AuditChanges map[string]AuditChange
+
+// Value implements driver.Valuer
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (c *AuditChanges) Scan(value interface{}) error {
+	var data []byte
+	switch src := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported type for audit changes: %T", value)
+	}
+	return json.Unmarshal(data, c)
+}
+
+type auditMotivoKey struct{}
+
+// withAuditMotivo records motivo as the reason of the audit entries recorded with ctx
+func withAuditMotivo(ctx context.Context, motivo string) context.Context {
+	return context.WithValue(ctx, auditMotivoKey{}, motivo)
+}
+
+func auditMotivo(ctx context.Context) string {
+	motivo, _ := ctx.Value(auditMotivoKey{}).(string)
+	return motivo
+}
+
+// RecordAudit records an audit entry of a property. before and after are snapshots (models or
+// responses) of the affected entity, either of which may be nil; the entry stores the fields that
+// differ between them. Updates that changed nothing are not recorded. The user and the reason
+// (see withAuditMotivo) are read from the request context.
+func RecordAudit(ctx context.Context, db *gorm.DB, imovelID uint, entidade, acao string, before, after interface{}) error {
+	changes, err := diffAudit(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff audit snapshots: %w", err)
+	}
+	if acao == AuditAcaoUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	entry := ImovelAudit{
+		ImovelID:   imovelID,
+		Entidade:   entidade,
+		Acao:       acao,
+		Origem:     priceOrigin(ctx),
+		Motivo:     auditMotivo(ctx),
+		Alteracoes: changes,
+	}
+	if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+		entry.UserID = &userID
+	}
+
+	if err := db.WithContext(ctx).Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// diffAudit compares the JSON representations of two snapshots field by field. Fields missing from
+// before are only reported when after has a non-empty value, so creations list what was set.
+func diffAudit(before, after interface{}) (AuditChanges, error) {
+	previous, err := auditSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := auditSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := AuditChanges{}
+	for field, value := range current {
+		old, existed := previous[field]
+		if existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+		if !existed && isEmptyAuditValue(value) {
+			continue
+		}
+		changes[field] = AuditChange{Anterior: old, Novo: value}
+	}
+	for field, old := range previous {
+		if _, ok := current[field]; ok || isEmptyAuditValue(old) {
+			continue
+		}
+		changes[field] = AuditChange{Anterior: old}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// auditSnapshot flattens the JSON representation of v into dotted field paths
+func auditSnapshot(v interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if v == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	if object, ok := decoded.(map[string]interface{}); ok {
+		flattenAudit("", object, fields)
+	}
+	return fields, nil
+}
+
+func flattenAudit(prefix string, object map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range object {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenAudit(path, v, fields)
+		case []interface{}:
+			fields[path] = auditListValue(v)
+		default:
+			fields[path] = v
+		}
+	}
+}
+
+// auditListValue reduces lists of related records (anexos, caracteristicas) to their ids
+func auditListValue(list []interface{}) interface{} {
+	ids := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return list
+		}
+		id, ok := object["id"]
+		if !ok {
+			return list
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func isEmptyAuditValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case float64:
+		return value == 0
+	case bool:
+		return !value
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
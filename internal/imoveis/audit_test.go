@@ -0,0 +1,108 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+func TestDiffAudit(t *testing.T) {
+	before := &ImovelResponse{
+		Titulo:          "Apartamento",
+		NumQuartos:      2,
+		PrecoVenda:      &PrecoVendaResponse{ID: 1, Preco: 500000},
+		Caracteristicas: []CaracteristicaResponse{{ID: 1, Nome: "Piscina"}},
+		Visualizacoes:   3,
+	}
+	after := &ImovelResponse{
+		Titulo:          "Apartamento reformado",
+		NumQuartos:      2,
+		PrecoVenda:      &PrecoVendaResponse{ID: 1, Preco: 480000},
+		Caracteristicas: []CaracteristicaResponse{{ID: 1, Nome: "Piscina"}, {ID: 4, Nome: "Churrasqueira"}},
+		Visualizacoes:   10,
+	}
+
+	changes, err := diffAudit(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, AuditChanges{
+		"titulo":           {Anterior: "Apartamento", Novo: "Apartamento reformado"},
+		"precoVenda.preco": {Anterior: 500000.0, Novo: 480000.0},
+		"caracteristicas":  {Anterior: []interface{}{1.0}, Novo: []interface{}{1.0, 4.0}},
+	}, changes)
+
+	// Creations list the fields that were set
+	changes, err = diffAudit(nil, &Anexo{Nome: "fachada.jpg", Image: true})
+	require.NoError(t, err)
+	assert.Equal(t, AuditChanges{
+		"nome":  {Novo: "fachada.jpg"},
+		"image": {Novo: true},
+	}, changes)
+
+	changes, err = diffAudit(before, before)
+	require.NoError(t, err)
+	assert.Nil(t, changes)
+}
+
+func TestImovelAuditLog(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := contextutil.WithUserID(context.Background(), 7)
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	titulo := "Apartamento reformado"
+	_, err = svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{Titulo: titulo})
+	require.NoError(t, err)
+	// Updates that change nothing are not recorded
+	_, err = svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{Titulo: titulo})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.ReplaceCaracteristicas(ctx, created.ID, []uint{2}))
+	require.NoError(t, svc.DeleteImovel(context.Background(), created.ID))
+
+	log, err := svc.GetAuditLog(ctx, created.ID, &AuditListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), log.Total)
+	require.Len(t, log.Results, 4)
+
+	deleted := log.Results[0]
+	assert.Equal(t, AuditAcaoDelete, deleted.Acao)
+	assert.Nil(t, deleted.UserID, "anonymous changes have no user")
+
+	caracteristicas := log.Results[1]
+	assert.Equal(t, AuditEntidadeCaracteristicas, caracteristicas.Entidade)
+	assert.Equal(t, AuditChanges{
+		"caracteristicas": {Anterior: []interface{}{1.0, 2.0}, Novo: []interface{}{2.0}},
+	}, caracteristicas.Alteracoes)
+
+	updated := log.Results[2]
+	assert.Equal(t, AuditAcaoUpdate, updated.Acao)
+	require.NotNil(t, updated.UserID)
+	assert.Equal(t, uint(7), *updated.UserID)
+	assert.Equal(t, PriceOriginAPI, updated.Origem)
+	assert.Equal(t, titulo, updated.Alteracoes["titulo"].Novo)
+	assert.Len(t, updated.Alteracoes, 1)
+
+	createdEntry := log.Results[3]
+	assert.Equal(t, AuditAcaoCreate, createdEntry.Acao)
+	assert.Equal(t, "AP-001", createdEntry.Alteracoes["codigo"].Novo)
+	assert.Equal(t, 550000.0, createdEntry.Alteracoes["precoVenda.preco"].Novo)
+
+	// The trail outlives the property
+	_, err = svc.RestoreImovel(ctx, created.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.HardDeleteImovel(ctx, created.ID))
+	log, err = svc.GetAuditLog(ctx, created.ID, &AuditListQuery{Page: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), log.Total)
+	assert.Equal(t, AuditAcaoHardDelete, log.Results[0].Acao)
+	assert.Equal(t, AuditAcaoRestore, log.Results[1].Acao)
+	assert.True(t, log.HasNext)
+
+	_, err = svc.GetAuditLog(ctx, 999, &AuditListQuery{Page: 1, Limit: 10})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+}
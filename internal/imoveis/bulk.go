@@ -0,0 +1,132 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+// statusAuditAcao is the audit action recorded for each bulk status target
+var statusAuditAcao = map[string]string{
+	StatusPublicado: AuditAcaoPublish,
+	StatusArquivado: AuditAcaoUnpublish,
+}
+
+// BulkUpdateStatus moves several properties to a status in a single transaction. Properties that
+// do not exist, belong to another corretor or cannot make the transition are reported and skipped; database errors roll
+// back the whole batch.
+func (s *service) BulkUpdateStatus(ctx context.Context, req *BulkStatusRequest) (*BulkResultResponse, error) {
+	if _, ok := statusAuditAcao[req.Status]; !ok {
+		return nil, fmt.Errorf("%w: unsupported bulk status %s", ErrInvalidImovel, req.Status)
+	}
+
+	ids := uniqueIDs(req.IDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		imoveis, err := s.repo.FindForWorkflow(txCtx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve properties: %w", err)
+		}
+		byID := make(map[uint]*Imovel, len(imoveis))
+		for i := range imoveis {
+			byID[imoveis[i].ID] = &imoveis[i]
+		}
+
+		published := req.Status == StatusPublicado
+		for _, id := range ids {
+			imovel := byID[id]
+			if imovel == nil {
+				results = append(results, BulkItemResult{ID: id, Error: ErrImovelNotFound.Error()})
+				continue
+			}
+			if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+				results = append(results, BulkItemResult{ID: id, Error: err.Error()})
+				continue
+			}
+			if err := checkTransition(currentStatus(imovel), req.Status); err != nil {
+				results = append(results, BulkItemResult{ID: id, Error: err.Error()})
+				continue
+			}
+			if published {
+				if err := checkPublishRequirements(imovel); err != nil {
+					results = append(results, BulkItemResult{ID: id, Error: err.Error()})
+					continue
+				}
+			}
+
+			if err := s.repo.UpdateStatus(txCtx, id, req.Status, published); err != nil {
+				return fmt.Errorf("failed to update status of property %d: %w", id, err)
+			}
+			before := map[string]interface{}{"status": imovel.Status, "published": imovel.Published}
+			after := map[string]interface{}{"status": req.Status, "published": published}
+			if err := s.repo.RecordAudit(txCtx, id, AuditEntidadeImovel, statusAuditAcao[req.Status], before, after); err != nil {
+				return err
+			}
+			if published {
+				s.publish(txCtx, events.ImovelPublished{ImovelID: id, Codigo: imovel.Codigo, Titulo: imovel.Titulo, Origem: priceOrigin(ctx)})
+			}
+			results = append(results, BulkItemResult{ID: id, Success: true})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newBulkResult(results), nil
+}
+
+// BulkDelete soft deletes several properties in a single transaction, reporting the ones that
+// do not exist or belong to another corretor
+func (s *service) BulkDelete(ctx context.Context, req *BulkDeleteRequest) (*BulkResultResponse, error) {
+	ids := uniqueIDs(req.IDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		imoveis, err := s.repo.FindForWorkflow(txCtx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve properties: %w", err)
+		}
+		corretores := make(map[uint]uint, len(imoveis))
+		for _, imovel := range imoveis {
+			corretores[imovel.ID] = imovel.CorretorPrincipalID
+		}
+
+		for _, id := range ids {
+			corretorPrincipalID, exists := corretores[id]
+			if !exists {
+				results = append(results, BulkItemResult{ID: id, Error: ErrImovelNotFound.Error()})
+				continue
+			}
+			if err := CheckCorretorScope(ctx, corretorPrincipalID); err != nil {
+				results = append(results, BulkItemResult{ID: id, Error: err.Error()})
+				continue
+			}
+			if err := s.repo.Delete(txCtx, id); err != nil {
+				return fmt.Errorf("failed to delete property %d: %w", id, err)
+			}
+			if err := s.repo.RecordAudit(txCtx, id, AuditEntidadeImovel, AuditAcaoDelete, nil, nil); err != nil {
+				return err
+			}
+			results = append(results, BulkItemResult{ID: id, Success: true})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newBulkResult(results), nil
+}
+
+func newBulkResult(results []BulkItemResult) *BulkResultResponse {
+	response := &BulkResultResponse{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	return response
+}
@@ -0,0 +1,112 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+func TestBulkUpdateStatus(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	var ids []uint
+	for _, codigo := range []string{"AP-001", "AP-002", "AP-003"} {
+		created, err := svc.CreateImovel(ctx, nestedCreateRequest(codigo))
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+	// The third property has no anexo and cannot be published
+	for _, id := range ids[:2] {
+		require.NoError(t, svc.AddAnexo(ctx, id, &Anexo{Nome: "fachada.jpg", Path: "fachada.jpg", Image: true}))
+	}
+
+	result, err := svc.BulkUpdateStatus(ctx, &BulkStatusRequest{IDs: []uint{ids[0], ids[1], ids[2], 999, ids[0]}, Status: StatusPublicado})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Total, "duplicate ids are processed once")
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, BulkItemResult{ID: ids[0], Success: true}, result.Results[0])
+	assert.Contains(t, result.Results[2].Error, "anexos")
+	assert.Equal(t, BulkItemResult{ID: 999, Error: ErrImovelNotFound.Error()}, result.Results[3])
+
+	published, err := svc.GetImovel(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, StatusPublicado, published.Status)
+	assert.True(t, published.Published)
+	assert.Equal(t, uint(2), published.Version)
+
+	result, err = svc.BulkUpdateStatus(ctx, &BulkStatusRequest{IDs: []uint{ids[0], ids[2]}, Status: StatusArquivado})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Contains(t, result.Results[1].Error, "cannot move from EM_EDICAO to ARQUIVADO")
+
+	archived, err := svc.GetImovel(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, StatusArquivado, archived.Status)
+	assert.False(t, archived.Published)
+
+	log, err := svc.GetAuditLog(ctx, ids[0], &AuditListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, AuditAcaoUnpublish, log.Results[0].Acao)
+	assert.Equal(t, AuditAcaoPublish, log.Results[1].Acao)
+}
+
+func TestBulkDelete(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	first, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+	second, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-002"))
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteImovel(ctx, second.ID))
+
+	result, err := svc.BulkDelete(ctx, &BulkDeleteRequest{IDs: []uint{first.ID, second.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, BulkItemResult{ID: second.ID, Error: ErrImovelNotFound.Error()}, result.Results[1], "already deleted")
+
+	_, err = svc.GetImovel(ctx, first.ID)
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+
+	trash, err := svc.ListTrash(ctx, &TrashListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), trash.Total)
+}
+
+func TestPublish_PublishesEvents(t *testing.T) {
+	_, database := setupCreateService(t)
+	publisher := &recordingPublisher{}
+	svc := NewService(NewRepository(database), WithEvents(publisher))
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	single := createScheduled(t, svc, database, "AP-001", nil, nil, true)
+	bulk := createScheduled(t, svc, database, "AP-002", nil, nil, true)
+	notReady := createScheduled(t, svc, database, "AP-003", nil, nil, false)
+	scheduled := createScheduled(t, svc, database, "AP-004", timePtr(now.Add(-time.Minute)), nil, true)
+
+	_, err := svc.PublishImovel(ctx, single.ID)
+	require.NoError(t, err)
+	_, err = svc.BulkUpdateStatus(ctx, &BulkStatusRequest{IDs: []uint{bulk.ID, notReady.ID}, Status: StatusPublicado})
+	require.NoError(t, err)
+	_, err = svc.RunSchedule(ctx, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []events.Event{
+		events.ImovelPublished{ImovelID: single.ID, Codigo: "AP-001", Titulo: single.Titulo, Origem: PriceOriginAPI},
+		events.ImovelPublished{ImovelID: bulk.ID, Codigo: "AP-002", Titulo: bulk.Titulo, Origem: PriceOriginAPI},
+		events.ImovelPublished{ImovelID: scheduled.ID, Codigo: "AP-004", Titulo: scheduled.Titulo, Origem: PriceOriginScheduler},
+	}, publisher.events)
+
+	// Archiving publishes nothing
+	_, err = svc.UnpublishImovel(ctx, single.ID)
+	require.NoError(t, err)
+	assert.Len(t, publisher.events, 3)
+}
@@ -0,0 +1,339 @@
+package imoveis
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/cache"
+)
+
+const (
+	// cacheKeyPrefix namespaces the imoveis entries; bump it when the cached responses change shape
+	cacheKeyPrefix = "imoveis:v1:"
+	// listGenerationKey holds the generation embedded in list keys. Writes replace it, which
+	// orphans every cached list at once; the orphans expire with their TTL.
+	listGenerationKey = cacheKeyPrefix + "list:generation"
+	// maxCachedListPage bounds the pages cached per filter combination; deep pages are rarely
+	// requested twice
+	maxCachedListPage = 5
+	// defaultCacheTTL is used when no positive ttl is configured
+	defaultCacheTTL = time.Minute
+)
+
+// cachedService serves GetImovel and the common ListImoveis queries from a cache and invalidates
+// them on every write made through the service (API, importer, uploads and scheduler). Cache
+// failures are logged and fall back to the wrapped service.
+type cachedService struct {
+	Service
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedService wraps service with a response cache. Entries live for ttl at most, which bounds
+// how long changes made elsewhere (e.g. prices edited in precos or views counted) take to show up.
+func NewCachedService(service Service, c cache.Cache, ttl time.Duration) Service {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &cachedService{Service: service, cache: c, ttl: ttl}
+}
+
+// CacheWarmer loads the first pages of the default public listing, and the imoveis on them,
+// through service so they are cached again after a flush
+func CacheWarmer(service Service) cache.Warmer {
+	return func(ctx context.Context) error {
+		for page := 1; page <= maxCachedListPage; page++ {
+			result, err := service.ListImoveis(ctx, &ImovelListQuery{Page: page, Limit: 10, Order: "desc"})
+			if err != nil {
+				return err
+			}
+			for _, imovel := range result.Results {
+				if _, err := service.GetImovel(ctx, imovel.ID); err != nil {
+					return err
+				}
+			}
+			if !result.HasNext {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+func imovelCacheKey(id uint) string {
+	return cacheKeyPrefix + "imovel:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// GetImovel returns the cached property when available
+func (s *cachedService) GetImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	key := imovelCacheKey(id)
+	var cached ImovelResponse
+	if s.load(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	imovel, err := s.Service.GetImovel(ctx, id)
+	if err != nil || imovel == nil {
+		return imovel, err
+	}
+	s.store(ctx, key, imovel)
+	return imovel, nil
+}
+
+// ListImoveis caches the first pages of offset queries. Cursor pages and fields projections are
+// not cached: the former are meant to be walked once and the latter do not survive a round trip.
+func (s *cachedService) ListImoveis(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error) {
+	if query.UsesCursor() || len(query.Fields) > 0 || query.Page > maxCachedListPage {
+		return s.Service.ListImoveis(ctx, query)
+	}
+
+	key, ok := s.listKey(ctx, query)
+	if !ok {
+		return s.Service.ListImoveis(ctx, query)
+	}
+	var cached ImovelListResponse
+	if s.load(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	result, err := s.Service.ListImoveis(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.store(ctx, key, result)
+	return result, nil
+}
+
+// listKey identifies the query within the current list generation
+func (s *cachedService) listKey(ctx context.Context, query *ImovelListQuery) (string, bool) {
+	generation, ok, err := s.cache.Get(ctx, listGenerationKey)
+	if err != nil {
+		slog.Warn("Failed to read imoveis cache", "key", listGenerationKey, "error", err)
+		return "", false
+	}
+	if !ok {
+		// Start a new generation rather than reading an evicted one as empty, which would bring
+		// back lists cached before it was replaced
+		generation = []byte(newListGeneration())
+		if err := s.cache.Set(ctx, listGenerationKey, generation, 0); err != nil {
+			slog.Warn("Failed to write imoveis cache", "key", listGenerationKey, "error", err)
+			return "", false
+		}
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return cacheKeyPrefix + "list:" + string(generation) + ":" + hex.EncodeToString(sum[:]), true
+}
+
+func newListGeneration() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// load decodes the entry under key into dest and reports whether it was found
+func (s *cachedService) load(ctx context.Context, key string, dest interface{}) bool {
+	data, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("Failed to read imoveis cache", "key", key, "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		slog.Warn("Discarding undecodable imoveis cache entry", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+func (s *cachedService) store(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Warn("Failed to encode imoveis cache entry", "key", key, "error", err)
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.ttl); err != nil {
+		slog.Warn("Failed to write imoveis cache", "key", key, "error", err)
+	}
+}
+
+// invalidate drops the cached properties ids and every cached list. It runs after failed writes
+// too, since bulk operations and imports can partially succeed.
+func (s *cachedService) invalidate(ctx context.Context, ids ...uint) {
+	keys := make([]string, 0, len(ids)+1)
+	keys = append(keys, listGenerationKey)
+	for _, id := range ids {
+		keys = append(keys, imovelCacheKey(id))
+	}
+	// Within a transaction, a read before the commit could cache the old data again
+	afterCommit(ctx, func() {
+		if err := s.cache.Delete(ctx, keys...); err != nil {
+			slog.Warn("Failed to invalidate imoveis cache", "keys", keys, "error", err)
+		}
+	})
+}
+
+func (s *cachedService) CreateImovel(ctx context.Context, req *CreateImovelRequest) (*ImovelResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.CreateImovel(ctx, req)
+}
+
+func (s *cachedService) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRequest) (*ImovelResponse, error) {
+	defer s.invalidate(ctx, id)
+	return s.Service.UpdateImovel(ctx, id, req)
+}
+
+func (s *cachedService) PatchImovel(ctx context.Context, id uint, req *PatchImovelRequest) (*ImovelResponse, error) {
+	defer s.invalidate(ctx, id)
+	return s.Service.PatchImovel(ctx, id, req)
+}
+
+func (s *cachedService) PublishImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	defer s.invalidate(ctx, id)
+	return s.Service.PublishImovel(ctx, id)
+}
+
+func (s *cachedService) UnpublishImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	defer s.invalidate(ctx, id)
+	return s.Service.UnpublishImovel(ctx, id)
+}
+
+func (s *cachedService) DeleteImovel(ctx context.Context, id uint) error {
+	defer s.invalidate(ctx, id)
+	return s.Service.DeleteImovel(ctx, id)
+}
+
+func (s *cachedService) HardDeleteImovel(ctx context.Context, id uint) error {
+	defer s.invalidate(ctx, id)
+	return s.Service.HardDeleteImovel(ctx, id)
+}
+
+func (s *cachedService) RestoreImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	defer s.invalidate(ctx, id)
+	return s.Service.RestoreImovel(ctx, id)
+}
+
+func (s *cachedService) CreateImovelBatch(ctx context.Context, reqs []CreateImovelRequest) error {
+	defer s.invalidate(ctx)
+	return s.Service.CreateImovelBatch(ctx, reqs)
+}
+
+func (s *cachedService) UpdateImovelBatch(ctx context.Context, imoveis []Imovel) error {
+	ids := make([]uint, len(imoveis))
+	for i := range imoveis {
+		ids[i] = imoveis[i].ID
+	}
+	defer s.invalidate(ctx, ids...)
+	return s.Service.UpdateImovelBatch(ctx, imoveis)
+}
+
+func (s *cachedService) BulkUpdateStatus(ctx context.Context, req *BulkStatusRequest) (*BulkResultResponse, error) {
+	defer s.invalidate(ctx, req.IDs...)
+	return s.Service.BulkUpdateStatus(ctx, req)
+}
+
+func (s *cachedService) BulkDelete(ctx context.Context, req *BulkDeleteRequest) (*BulkResultResponse, error) {
+	defer s.invalidate(ctx, req.IDs...)
+	return s.Service.BulkDelete(ctx, req)
+}
+
+func (s *cachedService) RunSchedule(ctx context.Context, now time.Time) ([]ScheduledChange, error) {
+	changes, err := s.Service.RunSchedule(ctx, now)
+	if len(changes) > 0 {
+		ids := make([]uint, len(changes))
+		for i, change := range changes {
+			ids[i] = change.Imovel.ID
+		}
+		s.invalidate(ctx, ids...)
+	}
+	return changes, err
+}
+
+func (s *cachedService) ArchiveStale(ctx context.Context, now time.Time) (*ArchiveStaleResponse, error) {
+	result, err := s.Service.ArchiveStale(ctx, now)
+	if result != nil && len(result.Arquivados) > 0 {
+		ids := make([]uint, len(result.Arquivados))
+		for i, archived := range result.Arquivados {
+			ids[i] = archived.ID
+		}
+		s.invalidate(ctx, ids...)
+	}
+	return result, err
+}
+
+func (s *cachedService) MarkRemovedFromSource(ctx context.Context, id uint, policy string) error {
+	defer s.invalidate(ctx, id)
+	return s.Service.MarkRemovedFromSource(ctx, id, policy)
+}
+
+func (s *cachedService) AddAnexo(ctx context.Context, imovelID uint, anexo *Anexo) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AddAnexo(ctx, imovelID, anexo)
+}
+
+func (s *cachedService) RemoveAnexo(ctx context.Context, imovelID, anexoID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.RemoveAnexo(ctx, imovelID, anexoID)
+}
+
+func (s *cachedService) AttachEndereco(ctx context.Context, imovelID, enderecoID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachEndereco(ctx, imovelID, enderecoID)
+}
+
+func (s *cachedService) AttachEmpreendimento(ctx context.Context, imovelID, empreendimentoID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachEmpreendimento(ctx, imovelID, empreendimentoID)
+}
+
+func (s *cachedService) AttachPlanta(ctx context.Context, imovelID, plantaID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachPlanta(ctx, imovelID, plantaID)
+}
+
+func (s *cachedService) AttachPacote(ctx context.Context, imovelID, pacoteID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachPacote(ctx, imovelID, pacoteID)
+}
+
+func (s *cachedService) AttachOrganizacao(ctx context.Context, imovelID, organizacaoID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachOrganizacao(ctx, imovelID, organizacaoID)
+}
+
+func (s *cachedService) AttachPrecoVenda(ctx context.Context, imovelID, precoVendaID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachPrecoVenda(ctx, imovelID, precoVendaID)
+}
+
+func (s *cachedService) AttachPrecoAluguel(ctx context.Context, imovelID, precoAluguelID uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AttachPrecoAluguel(ctx, imovelID, precoAluguelID)
+}
+
+func (s *cachedService) AddCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.AddCaracteristicas(ctx, imovelID, caracteristicaIDs)
+}
+
+func (s *cachedService) RemoveCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.RemoveCaracteristicas(ctx, imovelID, caracteristicaIDs)
+}
+
+func (s *cachedService) ReplaceCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error {
+	defer s.invalidate(ctx, imovelID)
+	return s.Service.ReplaceCaracteristicas(ctx, imovelID, caracteristicaIDs)
+}
@@ -0,0 +1,145 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/cache"
+)
+
+func setupCachedService(t *testing.T) (Service, *gorm.DB, *ImovelResponse) {
+	t.Helper()
+	svc, database := setupCreateService(t)
+	cached := NewCachedService(svc, cache.NewMemoryCache(100), time.Minute)
+
+	created, err := cached.CreateImovel(context.Background(), nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+	return cached, database, created
+}
+
+// changeBehindCache edits a property without going through the service, like another instance
+// or a change made in precos would
+func changeBehindCache(t *testing.T, database *gorm.DB, id uint, titulo string) {
+	t.Helper()
+	require.NoError(t, database.Model(&Imovel{}).Where("id = ?", id).Update("titulo", titulo).Error)
+}
+
+func TestCachedService_GetImovel(t *testing.T) {
+	ctx := context.Background()
+	svc, database, created := setupCachedService(t)
+
+	first, err := svc.GetImovel(ctx, created.ID)
+	require.NoError(t, err)
+	changeBehindCache(t, database, created.ID, "Alterado fora do cache")
+
+	cached, err := svc.GetImovel(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Titulo, cached.Titulo, "served from the cache")
+	assert.Equal(t, first.PrecoVenda.Preco, cached.PrecoVenda.Preco)
+	assert.Len(t, cached.Caracteristicas, 2)
+
+	patched, err := svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{
+		Titulo: Nullable[string]{Set: true, Value: "Titulo pelo servico"},
+	})
+	require.NoError(t, err)
+
+	fresh, err := svc.GetImovel(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Titulo pelo servico", fresh.Titulo)
+	assert.Equal(t, patched.Version, fresh.Version)
+
+	t.Run("missing properties are not cached", func(t *testing.T) {
+		_, err := svc.GetImovel(ctx, 9999)
+		assert.ErrorIs(t, err, ErrImovelNotFound)
+	})
+}
+
+func TestCachedService_ListImoveis(t *testing.T) {
+	ctx := context.Background()
+	svc, database, created := setupCachedService(t)
+	query := func() *ImovelListQuery { return &ImovelListQuery{Page: 1, Limit: 10, Order: "desc"} }
+
+	first, err := svc.ListImoveis(ctx, query())
+	require.NoError(t, err)
+	require.Len(t, first.Results, 1)
+	changeBehindCache(t, database, created.ID, "Alterado fora do cache")
+
+	cached, err := svc.ListImoveis(ctx, query())
+	require.NoError(t, err)
+	assert.Equal(t, first.Results[0].Titulo, cached.Results[0].Titulo)
+
+	t.Run("fields queries bypass the cache", func(t *testing.T) {
+		q := query()
+		q.Fields = []string{"titulo"}
+		result, err := svc.ListImoveis(ctx, q)
+		require.NoError(t, err)
+		assert.Equal(t, "Alterado fora do cache", result.Results[0].Titulo)
+	})
+
+	t.Run("writes invalidate lists", func(t *testing.T) {
+		_, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-002"))
+		require.NoError(t, err)
+
+		result, err := svc.ListImoveis(ctx, query())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Total)
+	})
+
+	t.Run("deletes invalidate lists and detail", func(t *testing.T) {
+		require.NoError(t, svc.DeleteImovel(ctx, created.ID))
+
+		result, err := svc.ListImoveis(ctx, query())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Total)
+
+		_, err = svc.GetImovel(ctx, created.ID)
+		assert.ErrorIs(t, err, ErrImovelNotFound)
+	})
+}
+
+func TestCachedService_EvictedGenerationDoesNotRestoreLists(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := setupCachedService(t)
+	query := &ImovelListQuery{Page: 1, Limit: 10, Order: "desc"}
+
+	_, err := svc.ListImoveis(ctx, query)
+	require.NoError(t, err)
+
+	// Losing the generation (eviction or a restarted shared cache) must not bring back the lists
+	// of the previous generation, even if a write is missed
+	backing := svc.(*cachedService).cache
+	require.NoError(t, backing.Delete(ctx, listGenerationKey))
+	_, err = svc.(*cachedService).Service.CreateImovel(ctx, nestedCreateRequest("AP-002"))
+	require.NoError(t, err)
+
+	result, err := svc.ListImoveis(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+}
+
+func TestCacheWarmer(t *testing.T) {
+	ctx := context.Background()
+	svc, database := setupCreateService(t)
+	c := cache.NewMemoryCache(100)
+	cached := NewCachedService(svc, c, time.Minute)
+	created, err := cached.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	require.NoError(t, c.Clear(ctx))
+	require.NoError(t, CacheWarmer(cached)(ctx))
+	changeBehindCache(t, database, created.ID, "Alterado fora do cache")
+
+	imovel, err := cached.GetImovel(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Titulo, imovel.Titulo, "the imovel was cached by the warmer")
+
+	list, err := cached.ListImoveis(ctx, &ImovelListQuery{Page: 1, Limit: 10, Order: "desc"})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, created.Titulo, list.Results[0].Titulo, "the default listing was cached by the warmer")
+}
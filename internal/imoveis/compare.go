@@ -0,0 +1,145 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bounds of the number of properties compared at once
+const (
+	minCompareItems = 2
+	maxCompareItems = 4
+)
+
+// parseCompareIDs parses the comma separated ids of the comparison endpoint, dropping duplicates
+func parseCompareIDs(raw string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 0)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid property id %q", part)
+		}
+		ids = append(ids, uint(id))
+	}
+
+	ids = uniqueIDs(ids)
+	if len(ids) < minCompareItems || len(ids) > maxCompareItems {
+		return nil, fmt.Errorf("between %d and %d distinct property ids are required", minCompareItems, maxCompareItems)
+	}
+	return ids, nil
+}
+
+// CompareImoveis returns the given properties side by side, in the requested order
+func (s *service) CompareImoveis(ctx context.Context, ids []uint) (*ImovelComparisonResponse, error) {
+	imoveis, err := s.repo.FindForComparison(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve properties: %w", err)
+	}
+	byID := make(map[uint]*Imovel, len(imoveis))
+	for i := range imoveis {
+		byID[imoveis[i].ID] = &imoveis[i]
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if byID[id] == nil {
+			missing = append(missing, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrImovelNotFound, strings.Join(missing, ", "))
+	}
+
+	response := &ImovelComparisonResponse{
+		Imoveis:         make([]ComparedImovel, len(ids)),
+		Caracteristicas: []CaracteristicaComparison{},
+	}
+	matrix := map[uint]*CaracteristicaComparison{}
+	for i, id := range ids {
+		imovel := byID[id]
+		response.Imoveis[i] = newComparedImovel(imovel)
+
+		for _, caracteristica := range imovel.Caracteristicas {
+			row := matrix[caracteristica.ID]
+			if row == nil {
+				row = &CaracteristicaComparison{
+					ID:        caracteristica.ID,
+					Nome:      caracteristica.Nome,
+					Categoria: caracteristica.CategoriaNome,
+					Presente:  make([]bool, len(ids)),
+				}
+				matrix[caracteristica.ID] = row
+			}
+			row.Presente[i] = true
+		}
+	}
+
+	for _, row := range matrix {
+		response.Caracteristicas = append(response.Caracteristicas, *row)
+	}
+	sort.Slice(response.Caracteristicas, func(i, j int) bool {
+		a, b := response.Caracteristicas[i], response.Caracteristicas[j]
+		if a.Categoria != b.Categoria {
+			return a.Categoria < b.Categoria
+		}
+		return a.Nome < b.Nome
+	})
+
+	return response, nil
+}
+
+// newComparedImovel normalizes a property loaded by FindForComparison. Inactive prices are left out.
+func newComparedImovel(imovel *Imovel) ComparedImovel {
+	compared := ComparedImovel{
+		ID:           imovel.ID,
+		Codigo:       imovel.Codigo,
+		Slug:         imovel.Slug,
+		Titulo:       imovel.Titulo,
+		Tipo:         imovel.Tipo,
+		Objetivo:     imovel.Objetivo,
+		Metragem:     imovel.Metragem,
+		NumQuartos:   imovel.NumQuartos,
+		NumSuites:    imovel.NumSuites,
+		NumBanheiros: imovel.NumBanheiros,
+		NumVagas:     imovel.NumVagas,
+		Condominio:   imovel.Condominio,
+		IPTU:         imovel.IPTU,
+	}
+	if imovel.Endereco != nil {
+		compared.Bairro = imovel.Endereco.Bairro
+		compared.Cidade = imovel.Endereco.Cidade
+	}
+	if imovel.PrecoVenda != nil && imovel.PrecoVenda.Ativo {
+		preco := imovel.PrecoVenda.Preco
+		compared.PrecoVenda = &preco
+	}
+	if imovel.PrecoAluguel != nil && imovel.PrecoAluguel.Ativo {
+		preco := imovel.PrecoAluguel.Preco
+		compared.PrecoAluguel = &preco
+	}
+
+	compared.Preco = compared.PrecoVenda
+	if imovel.Objetivo == "ALUGAR" {
+		compared.Preco = compared.PrecoAluguel
+	}
+	if compared.Preco != nil && imovel.Metragem > 0 {
+		precoM2 := math.Round(*compared.Preco/imovel.Metragem*100) / 100
+		compared.PrecoM2 = &precoM2
+	}
+
+	for _, anexo := range imovel.Anexos {
+		if anexo.Image {
+			compared.Foto = anexo.URL
+			break
+		}
+	}
+	return compared
+}
@@ -0,0 +1,69 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompareIDs(t *testing.T) {
+	ids, err := parseCompareIDs(" 3, 1,3 ,,2")
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 1, 2}, ids)
+
+	for _, raw := range []string{"1", "1,1", "1,2,3,4,5", "1,abc", "0,1"} {
+		_, err := parseCompareIDs(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestCompareImoveis(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	venda, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	req := nestedCreateRequest("AP-002")
+	req.Objetivo = "ALUGAR"
+	req.Metragem = 0
+	req.Condominio = 450
+	req.Caracteristicas = []uint{2}
+	aluguel, err := svc.CreateImovel(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, database.Create(&Anexo{ImovelID: &aluguel.ID, URL: "https://cdn.example.com/a.jpg", Image: true}).Error)
+
+	comparison, err := svc.CompareImoveis(ctx, []uint{aluguel.ID, venda.ID})
+	require.NoError(t, err)
+	require.Len(t, comparison.Imoveis, 2)
+
+	// Request order is kept
+	first, second := comparison.Imoveis[0], comparison.Imoveis[1]
+	assert.Equal(t, aluguel.ID, first.ID)
+	assert.Equal(t, venda.ID, second.ID)
+
+	require.NotNil(t, first.Preco)
+	assert.Equal(t, 2800.0, *first.Preco)
+	assert.Nil(t, first.PrecoM2, "no metragem")
+	assert.Equal(t, 450.0, first.Condominio)
+	assert.Equal(t, "https://cdn.example.com/a.jpg", first.Foto)
+
+	require.NotNil(t, second.Preco)
+	assert.Equal(t, 550000.0, *second.Preco)
+	require.NotNil(t, second.PrecoM2)
+	assert.Equal(t, 7638.89, *second.PrecoM2)
+	assert.Empty(t, second.Foto)
+
+	// Caracteristicas matrix, sorted by nome, aligned with the properties
+	require.Len(t, comparison.Caracteristicas, 2)
+	assert.Equal(t, "Churrasqueira", comparison.Caracteristicas[0].Nome)
+	assert.Equal(t, []bool{true, true}, comparison.Caracteristicas[0].Presente)
+	assert.Equal(t, "Piscina", comparison.Caracteristicas[1].Nome)
+	assert.Equal(t, []bool{false, true}, comparison.Caracteristicas[1].Presente)
+
+	_, err = svc.CompareImoveis(ctx, []uint{venda.ID, 999})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+	assert.Contains(t, err.Error(), "999")
+}
@@ -0,0 +1,115 @@
+package imoveis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// bodyETag returns a weak ETag for a response body. The version prefix, when given, lets clients
+// send the ETag back as If-Match on updates.
+func bodyETag(version string, body []byte) string {
+	sum := sha256.Sum256(body)
+	tag := hex.EncodeToString(sum[:8])
+	if version != "" {
+		tag = version + "-" + tag
+	}
+	return `W/"` + tag + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the weak comparison
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// notModifiedSince reports whether an If-Modified-Since header is at or after lastModified. HTTP
+// dates have second precision.
+func notModifiedSince(header string, lastModified time.Time) bool {
+	if header == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// lastModified is the latest change of the property or of the relations in the response
+func (r *ImovelResponse) lastModified() time.Time {
+	latest := r.UpdatedAt
+	consider := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	if r.PrecoVenda != nil {
+		consider(r.PrecoVenda.UpdatedAt)
+	}
+	if r.PrecoAluguel != nil {
+		consider(r.PrecoAluguel.UpdatedAt)
+	}
+	if r.Pacote != nil {
+		consider(r.Pacote.UpdatedAt)
+	}
+	for _, anexo := range r.Anexos {
+		consider(anexo.UpdatedAt)
+	}
+	for _, caracteristica := range r.Caracteristicas {
+		consider(caracteristica.UpdatedAt)
+	}
+	return latest
+}
+
+// writeImovel responds with a property, honouring If-None-Match and If-Modified-Since
+func writeImovel(c *gin.Context, imovel *ImovelResponse) {
+	writeConditional(c, imovel, strconv.FormatUint(uint64(imovel.Version), 10), imovel.lastModified())
+}
+
+// writeConditional responds with the success envelope of data, or 304 Not Modified when the client
+// copy is current. The ETag is derived from the body, so it also changes with related records.
+// If-None-Match takes precedence over If-Modified-Since, which is ignored when lastModified is zero:
+// lists cannot use it, since removing a property does not move the latest change forward.
+func writeConditional(c *gin.Context, data interface{}, version string, lastModified time.Time) {
+	body, err := json.Marshal(apiErrors.Success(data))
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	etag := bodyETag(version, body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		if etagMatches(header, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if notModifiedSince(c.GetHeader("If-Modified-Since"), lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
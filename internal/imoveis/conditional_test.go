@@ -0,0 +1,128 @@
+package imoveis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupConditionalRouter(t *testing.T) (*gin.Engine, Service, *ImovelResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	svc, _ := setupCreateService(t)
+	created, err := svc.CreateImovel(context.Background(), nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	handler := NewHandler(svc, nil, nil)
+	router := gin.New()
+	router.GET("/imoveis", handler.ListImoveis)
+	router.GET("/imoveis/:id", handler.GetImovel)
+	return router, svc, created
+}
+
+func conditionalGet(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetImovel_ConditionalRequests(t *testing.T) {
+	router, svc, created := setupConditionalRouter(t)
+	path := "/imoveis/" + strconv.FormatUint(uint64(created.ID), 10)
+
+	first := conditionalGet(router, path, nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+	assert.Regexp(t, `^W/"1-[0-9a-f]{16}"$`, etag)
+	assert.NotEmpty(t, lastModified)
+	assert.Equal(t, "no-cache", first.Header().Get("Cache-Control"))
+	assert.Contains(t, first.Body.String(), `"codigo":"AP-001"`)
+
+	t.Run("matching etag", func(t *testing.T) {
+		w := conditionalGet(router, path, map[string]string{"If-None-Match": `"other", ` + etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("stale etag", func(t *testing.T) {
+		w := conditionalGet(router, path, map[string]string{"If-None-Match": `W/"1-0000000000000000"`})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not modified since", func(t *testing.T) {
+		w := conditionalGet(router, path, map[string]string{"If-Modified-Since": lastModified})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("modified since", func(t *testing.T) {
+		since := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+		w := conditionalGet(router, path, map[string]string{"If-Modified-Since": since})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("etag takes precedence", func(t *testing.T) {
+		w := conditionalGet(router, path, map[string]string{
+			"If-None-Match":     `W/"stale"`,
+			"If-Modified-Since": lastModified,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("changes after update", func(t *testing.T) {
+		_, err := svc.PatchImovel(context.Background(), created.ID, &PatchImovelRequest{
+			Titulo: Nullable[string]{Set: true, Value: "Novo titulo"},
+		})
+		require.NoError(t, err)
+
+		w := conditionalGet(router, path, map[string]string{"If-None-Match": etag})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.Regexp(t, `^W/"2-`, w.Header().Get("ETag"))
+	})
+}
+
+func TestListImoveis_ConditionalRequests(t *testing.T) {
+	router, svc, _ := setupConditionalRouter(t)
+
+	first := conditionalGet(router, "/imoveis", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{16}"$`, etag)
+	assert.Empty(t, first.Header().Get("Last-Modified"))
+
+	w := conditionalGet(router, "/imoveis", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = conditionalGet(router, "/imoveis", map[string]string{
+		"If-Modified-Since": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	})
+	assert.Equal(t, http.StatusOK, w.Code, "lists do not honour If-Modified-Since")
+
+	_, err := svc.CreateImovel(context.Background(), nestedCreateRequest("AP-002"))
+	require.NoError(t, err)
+
+	w = conditionalGet(router, "/imoveis", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches("*", `W/"1-abc"`))
+	assert.True(t, etagMatches(`"1-abc"`, `W/"1-abc"`))
+	assert.True(t, etagMatches(`"x", W/"1-abc"`, `W/"1-abc"`))
+	assert.False(t, etagMatches(`W/"1-abd"`, `W/"1-abc"`))
+}
@@ -0,0 +1,64 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func TestRepositoryCorretorStats(t *testing.T) {
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&PrecoVenda{}, &PrecoAluguel{}))
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY,
+		status TEXT,
+		published BOOLEAN,
+		visualizacoes INTEGER,
+		corretor_principal_id INTEGER,
+		preco_venda_id INTEGER,
+		preco_aluguel_id INTEGER,
+		deleted_at DATETIME
+	)`).Error)
+
+	require.NoError(t, database.Create(&[]PrecoVenda{
+		{ID: 1, IdIntegracao: "pv1", Preco: 400000, Ativo: true},
+		{ID: 2, IdIntegracao: "pv2", Preco: 600000, Ativo: true},
+		{ID: 3, IdIntegracao: "pv3", Preco: 9000000, Ativo: false},
+	}).Error)
+	require.NoError(t, database.Create(&PrecoAluguel{ID: 1, IdIntegracao: "pa1", Preco: 3000, Ativo: true}).Error)
+
+	rows := []string{
+		`(1, 'PUBLICADO', true, 10, 7, 1, NULL, NULL)`,
+		`(2, 'PUBLICADO', true, 5, 7, 2, NULL, NULL)`,
+		`(3, '', false, 1, 7, 3, 1, NULL)`,
+		`(4, 'ARQUIVADO', false, 2, 7, NULL, NULL, NULL)`,
+		`(5, 'PUBLICADO', true, 99, 7, 1, NULL, '2026-01-01 00:00:00')`,
+		`(6, 'PUBLICADO', true, 50, 8, 1, NULL, NULL)`,
+	}
+	for _, row := range rows {
+		require.NoError(t, database.Exec(`INSERT INTO imoveis
+			(id, status, published, visualizacoes, corretor_principal_id, preco_venda_id, preco_aluguel_id, deleted_at)
+			VALUES `+row).Error)
+	}
+
+	repo := NewRepository(database)
+	stats, err := repo.CorretorStats(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), stats.Total)
+	assert.Equal(t, int64(2), stats.Publicados)
+	assert.Equal(t, int64(18), stats.TotalVisualizacoes)
+	assert.Equal(t, map[string]int64{StatusPublicado: 2, StatusEmEdicao: 1, StatusArquivado: 1}, stats.ByStatus)
+	assert.InDelta(t, 500000, stats.PrecoMedioVenda, 0.001)
+	assert.InDelta(t, 3000, stats.PrecoMedioAluguel, 0.001)
+
+	empty, err := repo.CorretorStats(context.Background(), 99)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), empty.Total)
+	assert.Empty(t, empty.ByStatus)
+}
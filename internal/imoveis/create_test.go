@@ -0,0 +1,113 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func setupCreateService(t *testing.T) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(
+		&Endereco{}, &Caracteristica{}, &Empreendimento{}, &Torres{}, &Plantas{}, &Anexo{},
+		&Organizacao{}, &Pacote{}, &PrecoVenda{}, &PrecoAluguel{},
+	))
+	// Idiomas/BairrosAtuacao are text[] columns sqlite cannot bind; preloads only need the table
+	require.NoError(t, database.Exec(`CREATE TABLE corretores_principais (
+		id INTEGER PRIMARY KEY,
+		nome TEXT,
+		organizacao_id INTEGER,
+		foto_id INTEGER,
+		deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, database.AutoMigrate(&Imovel{}, &HistoricoPreco{}, &ImovelAudit{}))
+	require.NoError(t, database.Create(&Caracteristica{Nome: "Piscina"}).Error)
+	require.NoError(t, database.Create(&Caracteristica{Nome: "Churrasqueira"}).Error)
+
+	return NewService(NewRepository(database)), database
+}
+
+func nestedCreateRequest(codigo string) *CreateImovelRequest {
+	return &CreateImovelRequest{
+		IdIntegracao: "manual-" + codigo,
+		Titulo:       "Apartamento no centro",
+		Codigo:       codigo,
+		Tipo:         "APARTAMENTO",
+		Objetivo:     "VENDER",
+		Finalidade:   "RESIDENTIAL",
+		Descricao:    "Apartamento com vista para o parque",
+		Metragem:     72,
+		Endereco: &NestedEnderecoRequest{
+			Rua: "Rua XV de Novembro", Numero: 100, Cidade: "Curitiba", Estado: "pr",
+		},
+		PrecoVenda:      &NestedPrecoVendaRequest{Preco: 550000, AceitaFGTS: true},
+		PrecoAluguel:    &NestedPrecoAluguelRequest{Preco: 2800},
+		Caracteristicas: []uint{1, 2, 2},
+	}
+}
+
+func TestCreateImovel_NestedRelations(t *testing.T) {
+	svc, _ := setupCreateService(t)
+
+	created, err := svc.CreateImovel(context.Background(), nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	require.NotNil(t, created.Endereco)
+	assert.Equal(t, "Curitiba", created.Endereco.Cidade)
+	assert.Equal(t, "PR", created.Endereco.Estado)
+	require.NotNil(t, created.PrecoVenda)
+	assert.Equal(t, 550000.0, created.PrecoVenda.Preco)
+	assert.True(t, created.PrecoVenda.Ativo)
+	require.NotNil(t, created.PrecoAluguel)
+	assert.Equal(t, 2800.0, created.PrecoAluguel.Preco)
+	assert.Len(t, created.Caracteristicas, 2)
+}
+
+func TestCreateImovel_RollsBackOnFailure(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	// Same id_integracao passes the codigo check but fails on the unique index inside the transaction
+	req := nestedCreateRequest("AP-002")
+	req.IdIntegracao = ""
+	require.NoError(t, database.Exec(`UPDATE imoveis SET id_integracao = ''`).Error)
+	_, err = svc.CreateImovel(ctx, req)
+	require.Error(t, err)
+
+	var enderecos, precos int64
+	require.NoError(t, database.Model(&Endereco{}).Count(&enderecos).Error)
+	require.NoError(t, database.Model(&PrecoVenda{}).Count(&precos).Error)
+	assert.Equal(t, int64(1), enderecos)
+	assert.Equal(t, int64(1), precos)
+}
+
+func TestCreateImovel_InvalidNestedCombinations(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	req := nestedCreateRequest("AP-001")
+	req.EnderecoID = 1
+	_, err := svc.CreateImovel(ctx, req)
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+
+	req = nestedCreateRequest("AP-001")
+	req.PrecoVenda = nil
+	_, err = svc.CreateImovel(ctx, req)
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+
+	req = nestedCreateRequest("AP-001")
+	req.Caracteristicas = []uint{1, 99}
+	_, err = svc.CreateImovel(ctx, req)
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+}
@@ -0,0 +1,40 @@
+package imoveis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// listCursor is the keyset position of the last property of a page
+type listCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+}
+
+// encodeListCursor returns the opaque cursor pointing after imovel
+func encodeListCursor(imovel *Imovel) string {
+	data, _ := json.Marshal(listCursor{CreatedAt: imovel.CreatedAt, ID: imovel.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListCursor parses a cursor returned in next_cursor. An empty cursor starts from the first page.
+func decodeListCursor(cursor string) (*listCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var decoded listCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == 0 || decoded.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &decoded, nil
+}
@@ -0,0 +1,73 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListImoveis_Cursor(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		imovel, err := svc.CreateImovel(ctx, nestedCreateRequest(fmt.Sprintf("AP-%03d", i)))
+		require.NoError(t, err)
+		createdAt := base.Add(time.Duration(i) * time.Hour)
+		if i == 3 {
+			createdAt = base.Add(2 * time.Hour) // ties with imovel 2, ordered by id
+		}
+		require.NoError(t, database.Model(&Imovel{}).Where("id = ?", imovel.ID).Update("created_at", createdAt).Error)
+	}
+
+	collect := func(order string) []uint {
+		var ids []uint
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			result, err := svc.ListImoveis(ctx, &ImovelListQuery{Limit: 2, Order: order, Cursor: &cursor})
+			require.NoError(t, err)
+			assert.Equal(t, cursor != "", result.HasPrev)
+			for _, r := range result.Results {
+				ids = append(ids, r.ID)
+			}
+			if !result.HasNext {
+				assert.Empty(t, result.NextCursor)
+				return ids
+			}
+			require.NotEmpty(t, result.NextCursor)
+			cursor = result.NextCursor
+		}
+		t.Fatal("pagination did not end")
+		return nil
+	}
+
+	assert.Equal(t, []uint{5, 4, 3, 2, 1}, collect("desc"))
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, collect("asc"))
+
+	offset, err := svc.ListImoveis(ctx, &ImovelListQuery{Page: 1, Limit: 2, Order: "desc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), offset.Total, "offset pagination is unchanged")
+	assert.Empty(t, offset.NextCursor)
+}
+
+func TestListImoveis_InvalidCursor(t *testing.T) {
+	svc, _ := setupCreateService(t)
+
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", encodeListCursor(&Imovel{})} {
+		_, err := svc.ListImoveis(context.Background(), &ImovelListQuery{Limit: 10, Cursor: &cursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestImovelListQuery_CursorErrors(t *testing.T) {
+	cursor := ""
+	assert.Empty(t, (&ImovelListQuery{Sort: "preco"}).cursorErrors(), "only checked in cursor mode")
+	assert.Empty(t, (&ImovelListQuery{Sort: "created_at", Cursor: &cursor}).cursorErrors())
+	assert.Contains(t, (&ImovelListQuery{Sort: "preco", Cursor: &cursor}).cursorErrors(), "sort")
+	assert.Contains(t, (&ImovelListQuery{Mode: "map", Cursor: &cursor}).cursorErrors(), "cursor")
+}
@@ -0,0 +1,67 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// destaquesCacheTTL is how long the featured pool of a filter combination is reused; changes to
+	// pacotes or publications show up on the homepage after at most this long
+	destaquesCacheTTL = 5 * time.Minute
+	// destaquesCacheSize bounds the cached filter combinations
+	destaquesCacheSize = 256
+	// maxDestaquesPool bounds the featured properties rotated for a filter combination
+	maxDestaquesPool = 200
+)
+
+// destaquesCache keeps the featured pool per filter combination, so rotating the homepage
+// listings does not hit the database on every request. It is per instance.
+type destaquesCache struct {
+	pools *expirable.LRU[string, []ImovelResponse]
+}
+
+func newDestaquesCache(size int, ttl time.Duration) *destaquesCache {
+	return &destaquesCache{pools: expirable.NewLRU[string, []ImovelResponse](size, nil, ttl)}
+}
+
+// key identifies the filter combination of a query; limit and rotation are applied to the pool
+func (q *DestaquesQuery) key() string {
+	return strings.Join([]string{q.Objetivo, q.Tipo, strings.ToLower(strings.TrimSpace(q.Cidade))}, "|")
+}
+
+// ListDestaques returns up to query.Limit featured properties: published ones whose pacote is
+// em_destaque. With Random a different selection is drawn from the cached pool on each call.
+func (s *service) ListDestaques(ctx context.Context, query *DestaquesQuery) ([]ImovelResponse, error) {
+	key := query.key()
+	pool, ok := s.destaques.pools.Get(key)
+	if !ok {
+		imoveis, err := s.repo.FindDestaques(ctx, query, maxDestaquesPool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve featured properties: %w", err)
+		}
+		pool = make([]ImovelResponse, len(imoveis))
+		for i := range imoveis {
+			pool[i] = *s.mapToResponse(&imoveis[i])
+		}
+		s.destaques.pools.Add(key, pool)
+	}
+
+	// The cached pool is shared between requests, so rotate a copy
+	results := make([]ImovelResponse, len(pool))
+	copy(results, pool)
+	if query.Random {
+		rand.Shuffle(len(results), func(i, j int) {
+			results[i], results[j] = results[j], results[i]
+		})
+	}
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDestaques(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	destaque := &Pacote{IdIntegracao: "destaque", Titulo: "Destaque", EmDestaque: true}
+	require.NoError(t, database.Create(destaque).Error)
+	basico := &Pacote{IdIntegracao: "basico", Titulo: "Basico"}
+	require.NoError(t, database.Create(basico).Error)
+
+	// createListing creates a property in a pacote, published unless draft is set
+	createListing := func(codigo string, pacoteID uint, draft bool, updatedAt time.Time) uint {
+		created, err := svc.CreateImovel(ctx, nestedCreateRequest(codigo))
+		require.NoError(t, err)
+		updates := map[string]interface{}{"pacote_id": pacoteID, "updated_at": updatedAt}
+		if !draft {
+			updates["status"] = StatusPublicado
+			updates["published"] = true
+		}
+		require.NoError(t, database.Model(&Imovel{}).Where("id = ?", created.ID).UpdateColumns(updates).Error)
+		return created.ID
+	}
+
+	now := time.Now().UTC()
+	older := createListing("AP-001", destaque.ID, false, now.Add(-2*time.Hour))
+	newer := createListing("AP-002", destaque.ID, false, now.Add(-time.Hour))
+	latest := createListing("AP-003", destaque.ID, false, now)
+	createListing("AP-004", basico.ID, false, now)
+	createListing("AP-005", destaque.ID, true, now)
+
+	results, err := svc.ListDestaques(ctx, &DestaquesQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{latest, newer}, destaqueIDs(results))
+	require.NotNil(t, results[0].Pacote)
+	assert.True(t, results[0].Pacote.EmDestaque)
+
+	results, err = svc.ListDestaques(ctx, &DestaquesQuery{Limit: 8, Random: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint{older, newer, latest}, destaqueIDs(results))
+
+	_, err = svc.ListDestaques(ctx, &DestaquesQuery{Limit: 8, Objetivo: "ALUGAR"})
+	require.NoError(t, err)
+	results, err = svc.ListDestaques(ctx, &DestaquesQuery{Limit: 8, Objetivo: "ALUGAR"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// The pool is cached: unfeaturing the pacote shows up once the entry expires
+	require.NoError(t, database.Model(destaque).Update("em_destaque", false).Error)
+	results, err = svc.ListDestaques(ctx, &DestaquesQuery{Limit: 8})
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	svc.(*service).destaques.pools.Purge()
+	results, err = svc.ListDestaques(ctx, &DestaquesQuery{Limit: 8})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func destaqueIDs(results []ImovelResponse) []uint {
+	ids := make([]uint, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	return ids
+}
@@ -1,6 +1,13 @@
 package imoveis
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
 
 // CreateImovelRequest represents property creation request
 type CreateImovelRequest struct {
@@ -23,14 +30,54 @@ type CreateImovelRequest struct {
 	InscricaoIPTU string  `json:"inscricaoIPTU" binding:"omitempty,max=50"`
 
 	// Relations
-	EnderecoID          uint   `json:"endereco_id" binding:"required"`
+	EnderecoID          uint   `json:"endereco_id" binding:"required_without=Endereco"`
 	EmpreendimentoID    uint   `json:"empreendimento_id" binding:"omitempty"`
 	PlantaID            uint   `json:"planta_id" binding:"omitempty"`
 	CorretorPrincipalID uint   `json:"corretor_principal_id" binding:"omitempty"`
 	PacoteID            uint   `json:"pacote_id" binding:"omitempty"`
 	PrecoVendaID        uint   `json:"preco_venda_id" binding:"omitempty"`
 	PrecoAluguelID      uint   `json:"preco_aluguel_id" binding:"omitempty"`
-	Caracteristicas     []uint `json:"caracteristicas" binding:"omitempty,dive"`
+	Caracteristicas     []uint `json:"caracteristicas" binding:"omitempty,dive,min=1"`
+
+	// Scheduled publication and expiration, applied by the publication scheduler
+	PublicarEm *time.Time `json:"publicarEm" binding:"omitempty"`
+	ExpiraEm   *time.Time `json:"expiraEm" binding:"omitempty"`
+
+	// Nested relations, created in the same transaction as the property.
+	// Each one is an alternative to the matching *_id field above.
+	Endereco     *NestedEnderecoRequest     `json:"endereco" binding:"omitempty"`
+	PrecoVenda   *NestedPrecoVendaRequest   `json:"precoVenda" binding:"omitempty"`
+	PrecoAluguel *NestedPrecoAluguelRequest `json:"precoAluguel" binding:"omitempty"`
+}
+
+// NestedEnderecoRequest represents an address created together with a property
+type NestedEnderecoRequest struct {
+	Rua       string  `json:"rua" binding:"required,max=255"`
+	Numero    int     `json:"numero" binding:"omitempty,min=0"`
+	Bairro    string  `json:"bairro" binding:"omitempty,max=255"`
+	Cidade    string  `json:"cidade" binding:"required,max=255"`
+	Estado    string  `json:"estado" binding:"required,len=2"`
+	CEP       string  `json:"cep" binding:"omitempty,max=9"`
+	Latitude  float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// NestedPrecoVendaRequest represents a selling price created together with a property; Ativo defaults to true
+type NestedPrecoVendaRequest struct {
+	Preco                       float64 `json:"preco" binding:"required,gt=0"`
+	AceitaFinanciamentoBancario bool    `json:"aceitaFinanciamentoBancario"`
+	AceitaFinanciamentoDireto   bool    `json:"aceitaFinanciamentoDireto"`
+	AceitaPermuta               bool    `json:"aceitaPermuta"`
+	AceitaCartaDeCredito        bool    `json:"aceitaCartaDeCredito"`
+	AceitaFGTS                  bool    `json:"aceitaFGTS"`
+	Ativo                       *bool   `json:"ativo"`
+}
+
+// NestedPrecoAluguelRequest represents a rental price created together with a property; Ativo defaults to true
+type NestedPrecoAluguelRequest struct {
+	Preco        float64 `json:"preco" binding:"required,gt=0"`
+	AceitaFiador bool    `json:"aceitaFiador"`
+	Ativo        *bool   `json:"ativo"`
 }
 
 // UpdateImovelRequest represents property update request
@@ -52,6 +99,10 @@ type UpdateImovelRequest struct {
 	IPTU          *float64 `json:"iptu" binding:"omitempty,min=0"`
 	InscricaoIPTU string   `json:"inscricaoIPTU" binding:"omitempty,max=50"`
 
+	// SEO metadata for the public site; an empty string clears the field
+	MetaTitle       *string `json:"metaTitle" binding:"omitempty,max=70"`
+	MetaDescription *string `json:"metaDescription" binding:"omitempty,max=160"`
+
 	// Relations
 	EnderecoID          *uint  `json:"endereco_id" binding:"omitempty"`
 	EmpreendimentoID    *uint  `json:"empreendimento_id" binding:"omitempty"`
@@ -64,6 +115,14 @@ type UpdateImovelRequest struct {
 	Published           *bool  `json:"published" binding:"omitempty"`
 	Closed              *bool  `json:"closed" binding:"omitempty"`
 	Caracteristicas     []uint `json:"caracteristicas" binding:"omitempty,dive"`
+
+	// Scheduled publication and expiration; use PATCH with null to clear them
+	PublicarEm *time.Time `json:"publicarEm" binding:"omitempty"`
+	ExpiraEm   *time.Time `json:"expiraEm" binding:"omitempty"`
+
+	// Version is the version the client read; the update fails with 409 when the property has
+	// changed since. The handler falls back to the If-Match header.
+	Version *uint `json:"version"`
 }
 
 // ImovelResponse represents property response
@@ -88,6 +147,11 @@ type ImovelResponse struct {
 	IPTU          float64 `json:"iptu"`
 	InscricaoIPTU string  `json:"inscricaoIPTU"`
 
+	// SEO
+	Slug            string `json:"slug"`
+	MetaTitle       string `json:"metaTitle"`
+	MetaDescription string `json:"metaDescription"`
+
 	// Relations
 	Endereco          *EnderecoResponse          `json:"endereco,omitempty"`
 	Empreendimento    *EmpreendimentoResponse    `json:"empreendimento,omitempty"`
@@ -100,28 +164,38 @@ type ImovelResponse struct {
 	Caracteristicas   []CaracteristicaResponse   `json:"caracteristicas,omitempty"`
 
 	// Metadata
-	Status        string    `json:"status"`
-	Published     bool      `json:"published"`
-	Closed        bool      `json:"closed"`
-	Visualizacoes int       `json:"visualizacoes"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Status           string       `json:"status"`
+	Published        bool         `json:"published"`
+	Closed           bool         `json:"closed"`
+	PublicarEm       *time.Time   `json:"publicarEm,omitempty"`
+	ExpiraEm         *time.Time   `json:"expiraEm,omitempty"`
+	RemovidoOrigemEm *time.Time   `json:"removidoOrigemEm,omitempty"`
+	LockedFields     LockedFields `json:"lockedFields,omitempty"`
+	Version          uint         `json:"version"`
+	Visualizacoes    int          `json:"visualizacoes"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+
+	// fields restricts the marshaled attributes for list queries using fields=
+	fields map[string]bool
 }
 
 // AnexoResponse represents attachment response
 type AnexoResponse struct {
-	ID            uint      `json:"id"`
-	Nome          string    `json:"nome"`
-	Path          string    `json:"path"`
-	Tamanho       int64     `json:"tamanho"`
-	Tipo          string    `json:"tipo"`
-	URL           string    `json:"url"`
-	CanPublish    bool      `json:"canPublish"`
-	Image         bool      `json:"image"`
-	Video         bool      `json:"video"`
-	IsExternalURL bool      `json:"isExternalUrl"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uint          `json:"id"`
+	Nome          string        `json:"nome"`
+	Path          string        `json:"path"`
+	Tamanho       int64         `json:"tamanho"`
+	Tipo          string        `json:"tipo"`
+	URL           string        `json:"url"`
+	CanPublish    bool          `json:"canPublish"`
+	Image         bool          `json:"image"`
+	Video         bool          `json:"video"`
+	IsExternalURL bool          `json:"isExternalUrl"`
+	ExternalURL   string        `json:"externalUrl,omitempty"`
+	Variants      AnexoVariants `json:"variants,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
 }
 
 // EnderecoResponse represents address response
@@ -159,32 +233,6 @@ type TorresResponse struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
-// CreateEmpreendimentoRequest represents enterprise creation request
-type CreateEmpreendimentoRequest struct {
-	Titulo          string `json:"titulo" binding:"required,min=3,max=255"`
-	Descricao       string `json:"descricao" binding:"required,min=10,max=5000"`
-	DataEntrega     string `json:"data_entrega" binding:"omitempty,datetime=2006-01-02"`
-	EtapaLancamento string `json:"etapa_lancamento" binding:"omitempty,oneof=LANCAMENTO PRE_LANCAMENTO PRONTO EM_CONSTRUCAO"`
-	Finalidade      string `json:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
-	Tipo            string `json:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
-	Status          string `json:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
-	Localizacao     string `json:"localizacao" binding:"omitempty,max=255"`
-	EnderecoID      uint   `json:"endereco_id" binding:"omitempty"`
-}
-
-// UpdateEmpreendimentoRequest represents enterprise update request
-type UpdateEmpreendimentoRequest struct {
-	Titulo          string `json:"titulo" binding:"omitempty,min=3,max=255"`
-	Descricao       string `json:"descricao" binding:"omitempty,min=10,max=5000"`
-	DataEntrega     string `json:"data_entrega" binding:"omitempty,datetime=2006-01-02"`
-	EtapaLancamento string `json:"etapa_lancamento" binding:"omitempty,oneof=LANCAMENTO PRE_LANCAMENTO PRONTO EM_CONSTRUCAO"`
-	Finalidade      string `json:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
-	Tipo            string `json:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
-	Status          string `json:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
-	Localizacao     string `json:"localizacao" binding:"omitempty,max=255"`
-	EnderecoID      *uint  `json:"endereco_id" binding:"omitempty"`
-}
-
 // EmpreendimentoResponse represents enterprise response
 type EmpreendimentoResponse struct {
 	ID              uint                     `json:"id"`
@@ -275,16 +323,20 @@ type PrecoAluguelResponse struct {
 
 // ImovelListQuery represents query parameters for listing properties
 type ImovelListQuery struct {
-	Page             int     `form:"page,default=1" binding:"min=1"`
-	Limit            int     `form:"limit,default=10" binding:"min=1,max=100"`
-	Codigo           string  `form:"codigo" binding:"omitempty,max=50"`
-	Tipo             string  `form:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
-	Objetivo         string  `form:"objetivo" binding:"omitempty,oneof=VENDER ALUGAR"`
-	Finalidade       string  `form:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
-	Status           string  `form:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
-	Published        *bool   `form:"published" binding:"omitempty"`
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	Limit      int    `form:"limit,default=10" binding:"min=1,max=100"`
+	Codigo     string `form:"codigo" binding:"omitempty,max=50"`
+	Tipo       string `form:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
+	Objetivo   string `form:"objetivo" binding:"omitempty,oneof=VENDER ALUGAR"`
+	Finalidade string `form:"finalidade" binding:"omitempty,oneof=RESIDENTIAL COMERCIAL MISTO"`
+	Status     string `form:"status" binding:"omitempty,oneof=PUBLICADO EM_EDICAO ARQUIVADO"`
+	Published  *bool  `form:"published" binding:"omitempty"`
+	// RemovidoOrigem filters the imported properties the last full import no longer found in the source
+	RemovidoOrigem   *bool   `form:"removido_origem" binding:"omitempty"`
 	MinPreco         float64 `form:"min_preco" binding:"omitempty,min=0"`
 	MaxPreco         float64 `form:"max_preco" binding:"omitempty,min=0"`
+	MinPrecoAluguel  float64 `form:"min_preco_aluguel" binding:"omitempty,min=0"`
+	MaxPrecoAluguel  float64 `form:"max_preco_aluguel" binding:"omitempty,min=0"`
 	MinMetragem      float64 `form:"min_metragem" binding:"omitempty,min=0"`
 	MaxMetragem      float64 `form:"max_metragem" binding:"omitempty,min=0"`
 	Rua              string  `form:"rua" binding:"omitempty,max=200"`
@@ -294,17 +346,627 @@ type ImovelListQuery struct {
 	NumBanheiros     int     `form:"num_banheiros" binding:"omitempty,min=0"`
 	NumGaragens      int     `form:"num_garagens" binding:"omitempty,min=0"`
 	EmpreendimentoID uint    `form:"empreendimento_id" binding:"omitempty"`
-	Sort             string  `form:"sort" binding:"omitempty,oneof=created_at updated_at preco titulo metragem"`
-	Order            string  `form:"order,default=desc" binding:"oneof=asc desc"`
+
+	// Caracteristicas filters by feature IDs (e.g. caracteristicas=1,5,9); match "any" (default) or "all"
+	Caracteristicas      []uint `form:"caracteristicas" collection_format:"csv" binding:"omitempty,max=50,dive,min=1"`
+	CaracteristicasMatch string `form:"caracteristicas_match" binding:"omitempty,oneof=any all"`
+
+	// Bounding box (map viewport); all four must be set for the filter to apply
+	MinLat *float64 `form:"min_lat" binding:"omitempty,min=-90,max=90"`
+	MaxLat *float64 `form:"max_lat" binding:"omitempty,min=-90,max=90"`
+	MinLng *float64 `form:"min_lng" binding:"omitempty,min=-180,max=180"`
+	MaxLng *float64 `form:"max_lng" binding:"omitempty,min=-180,max=180"`
+
+	// Mode "map" returns only the fields needed to plot markers
+	Mode string `form:"mode" binding:"omitempty,oneof=full map"`
+
+	// Sort accepts several fields with optional directions (e.g. sort=preco_venda:asc,created_at:desc);
+	// fields without a direction use Order
+	Sort  string `form:"sort" binding:"omitempty,max=200"`
+	Order string `form:"order,default=desc" binding:"oneof=asc desc"`
+
+	// Cursor switches to keyset pagination ordered by created_at and id; send it empty for the
+	// first page and then the returned next_cursor. Page is ignored and total/pages are not computed.
+	Cursor *string `form:"cursor" binding:"omitempty,max=200"`
+
+	// Include limits the relations loaded (e.g. include=endereco,precoVenda,anexos); defaults to all
+	// list relations. Fields limits the attributes returned (e.g. fields=titulo,codigo); id is always kept.
+	Include []string `form:"include" collection_format:"csv" binding:"omitempty,max=10"`
+	Fields  []string `form:"fields" collection_format:"csv" binding:"omitempty,max=30"`
+}
+
+// UsesCursor reports whether keyset pagination was requested
+func (q *ImovelListQuery) UsesCursor() bool {
+	return q.Cursor != nil
+}
+
+// cursorErrors validates the options that cannot be combined with cursor pagination
+func (q *ImovelListQuery) cursorErrors() map[string]string {
+	if !q.UsesCursor() {
+		return nil
+	}
+
+	details := map[string]string{}
+	if terms, err := parseSortTerms(q.Sort, q.Order); err == nil && (len(terms) > 1 || len(terms) == 1 && terms[0].Field != "created_at") {
+		details["sort"] = "cursor pagination only supports created_at"
+	}
+	if q.Mode == "map" {
+		details["cursor"] = "cursor pagination is not supported in map mode"
+	}
+	return details
+}
+
+// HasBoundingBox reports whether a complete viewport was given
+func (q *ImovelListQuery) HasBoundingBox() bool {
+	return q.MinLat != nil && q.MaxLat != nil && q.MinLng != nil && q.MaxLng != nil
+}
+
+// boundingBoxErrors validates that the viewport is either complete and ordered or absent
+func (q *ImovelListQuery) boundingBoxErrors() map[string]string {
+	if q.MinLat == nil && q.MaxLat == nil && q.MinLng == nil && q.MaxLng == nil {
+		return nil
+	}
+	if !q.HasBoundingBox() {
+		return map[string]string{"bbox": "min_lat, max_lat, min_lng and max_lng must be provided together"}
+	}
+
+	details := map[string]string{}
+	if *q.MinLat > *q.MaxLat {
+		details["min_lat"] = "must be less than or equal to max_lat"
+	}
+	if *q.MinLng > *q.MaxLng {
+		details["min_lng"] = "must be less than or equal to max_lng"
+	}
+	return details
+}
+
+// ImovelMapItem is the lightweight representation used by map views
+type ImovelMapItem struct {
+	ID        uint    `json:"id"`
+	Titulo    string  `json:"titulo"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Preco     float64 `json:"preco"`
+}
+
+// ImovelMapListResponse represents paginated property list response in map mode
+type ImovelMapListResponse struct {
+	Total   int64           `json:"total"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+	Pages   int64           `json:"pages"`
+	HasNext bool            `json:"hasNext"`
+	HasPrev bool            `json:"hasPrev"`
+	Results []ImovelMapItem `json:"results"`
 }
 
 // ImovelListResponse represents paginated property list response
-type ImovelListResponse struct {
+type ImovelListResponse = pagination.Page[ImovelResponse]
+
+// ViewRegisteredResponse reports whether a view was counted or deduplicated
+type ViewRegisteredResponse struct {
+	ImovelID uint `json:"imovel_id"`
+	Counted  bool `json:"counted"`
+}
+
+// ImovelViewStats represents the view count of a property
+type ImovelViewStats struct {
+	ImovelID      uint   `json:"imovel_id"`
+	Codigo        string `json:"codigo"`
+	Titulo        string `json:"titulo"`
+	Visualizacoes int64  `json:"visualizacoes"`
+}
+
+// CorretorViewStats represents the views aggregated over a corretor's properties
+type CorretorViewStats struct {
+	CorretorID         uint    `json:"corretor_id"`
+	Nome               string  `json:"nome"`
+	TotalImoveis       int64   `json:"total_imoveis"`
+	TotalVisualizacoes int64   `json:"total_visualizacoes"`
+	MediaVisualizacoes float64 `json:"media_visualizacoes"`
+}
+
+// CorretorImovelStats aggregates the portfolio of a corretor principal; average prices only
+// consider active prices
+type CorretorImovelStats struct {
+	Total              int64            `json:"total"`
+	Publicados         int64            `json:"publicados"`
+	ByStatus           map[string]int64 `json:"by_status"`
+	TotalVisualizacoes int64            `json:"total_visualizacoes"`
+	PrecoMedioVenda    float64          `json:"preco_medio_venda"`
+	PrecoMedioAluguel  float64          `json:"preco_medio_aluguel"`
+}
+
+// CorretorImoveisResponse represents a page of a corretor's properties with portfolio stats
+type CorretorImoveisResponse struct {
+	CorretorID uint                `json:"corretor_id"`
+	Stats      CorretorImovelStats `json:"stats"`
+	Imoveis    *ImovelListResponse `json:"imoveis"`
+}
+
+// ViewStatsQuery represents query parameters for view rankings
+type ViewStatsQuery struct {
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// TrashListQuery represents query parameters for listing soft-deleted properties
+type TrashListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// TrashedImovelResponse represents a soft-deleted property
+type TrashedImovelResponse struct {
+	ImovelResponse
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashListResponse represents paginated soft-deleted property list response
+type TrashListResponse struct {
+	Total   int64                   `json:"total"`
+	Page    int                     `json:"page"`
+	Limit   int                     `json:"limit"`
+	Pages   int64                   `json:"pages"`
+	HasNext bool                    `json:"hasNext"`
+	HasPrev bool                    `json:"hasPrev"`
+	Results []TrashedImovelResponse `json:"results"`
+}
+
+// ImovelStatsResponse represents public property counters
+type ImovelStatsResponse struct {
+	Total    int64            `json:"total"`
+	ByStatus map[string]int64 `json:"by_status"`
+}
+
+// PriceHistoryQuery represents query parameters for the price history of a property
+type PriceHistoryQuery struct {
+	Tipo string `form:"tipo" binding:"omitempty,oneof=VENDA ALUGUEL"`
+}
+
+// SimulacaoQuery represents the financing simulation parameters: entrada is the down payment (20% of
+// the price when omitted), prazo the term in months and taxa the effective annual interest rate in %
+type SimulacaoQuery struct {
+	Entrada *float64 `form:"entrada" binding:"omitempty,min=0"`
+	Prazo   int      `form:"prazo,default=360" binding:"min=12,max=420"`
+	Taxa    float64  `form:"taxa" binding:"required,gt=0,max=50"`
+}
+
+// ParcelaSimulacao represents one monthly installment of an amortization table
+type ParcelaSimulacao struct {
+	Numero      int     `json:"numero"`
+	Parcela     float64 `json:"parcela"`
+	Amortizacao float64 `json:"amortizacao"`
+	Juros       float64 `json:"juros"`
+	Saldo       float64 `json:"saldo"`
+}
+
+// SistemaAmortizacaoResponse summarizes an amortization system. RendaMinima is the monthly income
+// needed for the first installment to fit the usual 30% income commitment limit.
+type SistemaAmortizacaoResponse struct {
+	PrimeiraParcela float64            `json:"primeiraParcela"`
+	UltimaParcela   float64            `json:"ultimaParcela"`
+	TotalPago       float64            `json:"totalPago"`
+	TotalJuros      float64            `json:"totalJuros"`
+	RendaMinima     float64            `json:"rendaMinima"`
+	Parcelas        []ParcelaSimulacao `json:"parcelas"`
+}
+
+// SimulacaoResponse represents a financing simulation of a property under the SAC and PRICE systems
+type SimulacaoResponse struct {
+	ImovelID        uint                       `json:"imovelId"`
+	PrecoVenda      float64                    `json:"precoVenda"`
+	Entrada         float64                    `json:"entrada"`
+	ValorFinanciado float64                    `json:"valorFinanciado"`
+	Prazo           int                        `json:"prazo"`
+	TaxaAnual       float64                    `json:"taxaAnual"`
+	TaxaMensal      float64                    `json:"taxaMensal"`
+	SAC             SistemaAmortizacaoResponse `json:"sac"`
+	Price           SistemaAmortizacaoResponse `json:"price"`
+}
+
+// HistoricoPrecoResponse represents one price change of a property. Variacao is the change in
+// percent relative to PrecoAnterior, omitted when either price is missing.
+type HistoricoPrecoResponse struct {
+	ID            uint      `json:"id"`
+	Tipo          string    `json:"tipo"`
+	Preco         *float64  `json:"preco"`
+	PrecoAnterior *float64  `json:"precoAnterior"`
+	Variacao      *float64  `json:"variacao,omitempty"`
+	Origem        string    `json:"origem"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DestaquesQuery represents query parameters for the featured listings endpoint. Random rotates the
+// featured properties on every request; otherwise the most recently updated come first.
+type DestaquesQuery struct {
+	Limit    int    `form:"limit,default=8" binding:"min=1,max=24"`
+	Objetivo string `form:"objetivo" binding:"omitempty,oneof=VENDER ALUGAR"`
+	Tipo     string `form:"tipo" binding:"omitempty,oneof=APARTAMENTO CASA COMERCIAL SALA_COMERCIAL TERRENO GALPAO"`
+	Cidade   string `form:"cidade" binding:"omitempty,max=100"`
+	Random   bool   `form:"random,default=true"`
+}
+
+// CompareQuery represents query parameters for the comparison endpoint: ids=1,2,3
+type CompareQuery struct {
+	IDs string `form:"ids" binding:"required"`
+}
+
+// ComparedImovel represents the normalized attributes of a property in a comparison. Preco is the
+// active price of its objetivo (rental for ALUGAR, sale otherwise) and PrecoM2 that price per m².
+type ComparedImovel struct {
+	ID           uint     `json:"id"`
+	Codigo       string   `json:"codigo"`
+	Slug         string   `json:"slug"`
+	Titulo       string   `json:"titulo"`
+	Tipo         string   `json:"tipo"`
+	Objetivo     string   `json:"objetivo"`
+	Bairro       string   `json:"bairro"`
+	Cidade       string   `json:"cidade"`
+	Metragem     float64  `json:"metragem"`
+	NumQuartos   int      `json:"numQuartos"`
+	NumSuites    int      `json:"numSuites"`
+	NumBanheiros int      `json:"numBanheiros"`
+	NumVagas     int      `json:"numVagas"`
+	Preco        *float64 `json:"preco"`
+	PrecoM2      *float64 `json:"precoM2"`
+	PrecoVenda   *float64 `json:"precoVenda"`
+	PrecoAluguel *float64 `json:"precoAluguel"`
+	Condominio   float64  `json:"condominio"`
+	IPTU         float64  `json:"iptu"`
+	Foto         string   `json:"foto,omitempty"`
+}
+
+// CaracteristicaComparison tells which compared properties have a caracteristica. Presente is
+// aligned with ImovelComparisonResponse.Imoveis.
+type CaracteristicaComparison struct {
+	ID        uint   `json:"id"`
+	Nome      string `json:"nome"`
+	Categoria string `json:"categoria,omitempty"`
+	Presente  []bool `json:"presente"`
+}
+
+// ImovelComparisonResponse represents properties side by side, in the requested order, with the
+// union of their caracteristicas
+type ImovelComparisonResponse struct {
+	Imoveis         []ComparedImovel           `json:"imoveis"`
+	Caracteristicas []CaracteristicaComparison `json:"caracteristicas"`
+}
+
+// BulkStatusRequest represents a status change applied to several properties at once
+type BulkStatusRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1,max=100,dive,gt=0"`
+	Status string `json:"status" binding:"required,oneof=PUBLICADO ARQUIVADO"`
+}
+
+// BulkDeleteRequest represents the soft deletion of several properties at once
+type BulkDeleteRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100,dive,gt=0"`
+}
+
+// BulkItemResult reports the outcome of a bulk operation for one property
+type BulkItemResult struct {
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkResultResponse reports the outcome of a bulk operation, one result per distinct ID in request order
+type BulkResultResponse struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// AuditListQuery represents query parameters for the audit trail of a property
+type AuditListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// ArchivedImovel is a published property archived for not being updated within the archive period
+// of its organizacao
+type ArchivedImovel struct {
+	ID                uint                       `json:"id"`
+	Codigo            string                     `json:"codigo"`
+	Titulo            string                     `json:"titulo"`
+	Motivo            string                     `json:"motivo"`
+	UltimaAtualizacao time.Time                  `json:"ultimaAtualizacao"`
+	CorretorPrincipal *CorretorPrincipalResponse `json:"corretorPrincipal,omitempty"`
+}
+
+// ArchiveStaleResponse summarizes a run of the stale listings archiver. Falhas lists the properties
+// and notifications that failed; they do not stop the run.
+type ArchiveStaleResponse struct {
+	Arquivados  []ArchivedImovel `json:"arquivados"`
+	Notificados int              `json:"notificados"`
+	Falhas      []string         `json:"falhas,omitempty"`
+}
+
+// ImovelAuditResponse represents one audit entry of a property
+type ImovelAuditResponse struct {
+	ID         uint         `json:"id"`
+	UserID     *uint        `json:"user_id"`
+	Entidade   string       `json:"entidade"`
+	Acao       string       `json:"acao"`
+	Origem     string       `json:"origem"`
+	Motivo     string       `json:"motivo,omitempty"`
+	Alteracoes AuditChanges `json:"alteracoes"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// AuditListResponse represents the paginated audit trail of a property, newest first
+type AuditListResponse struct {
+	Total   int64                 `json:"total"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+	Pages   int64                 `json:"pages"`
+	HasNext bool                  `json:"hasNext"`
+	HasPrev bool                  `json:"hasPrev"`
+	Results []ImovelAuditResponse `json:"results"`
+}
+
+// FacetCount represents the number of matching properties for one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceRangeFacet represents the number of matching properties within a price band;
+// Max is omitted for the open-ended top band
+type PriceRangeFacet struct {
+	Label string   `json:"label"`
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// ImovelFacetsResponse represents the filter counts for the current search
+type ImovelFacetsResponse struct {
+	Total      int64             `json:"total"`
+	Tipo       []FacetCount      `json:"tipo"`
+	Bairro     []FacetCount      `json:"bairro"`
+	Cidade     []FacetCount      `json:"cidade"`
+	FaixaPreco []PriceRangeFacet `json:"faixaPreco"`
+	NumQuartos []FacetCount      `json:"numQuartos"`
+}
+
+// EmpreendimentoCountResponse represents the number of properties of an enterprise
+type EmpreendimentoCountResponse struct {
+	EmpreendimentoID uint  `json:"empreendimento_id"`
+	Total            int64 `json:"total"`
+}
+
+// CaracteristicasRequest carries the characteristic IDs to remove from a property
+type CaracteristicasRequest struct {
+	Caracteristicas []uint `json:"caracteristicas" binding:"required,min=1,dive,min=1"`
+}
+
+// ReplaceCaracteristicasRequest carries the full set of characteristic IDs of a property
+type ReplaceCaracteristicasRequest struct {
+	Caracteristicas []uint `json:"caracteristicas" binding:"required,dive,min=1"`
+}
+
+// PageQuery represents plain page/limit query parameters
+type PageQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// ImportProgress counts the listings of an import run; Total is known once the list is fetched
+type ImportProgress struct {
+	Total     int `json:"total"`
+	Processed int `json:"processed"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+	Removed   int `json:"removed"`
+	// Listings held in quarantine for failing validation
+	Quarantined int `json:"quarantined"`
+	// Requests to the external API held back by the rate limit, and how long they waited
+	Throttled  int   `json:"throttled"`
+	ThrottleMs int64 `json:"throttle_ms"`
+}
+
+// ImportResult summarizes an import run: its counts, the run recorded in import_runs and how long
+// it took
+type ImportResult struct {
+	RunID uint `json:"run_id,omitempty"`
+	ImportProgress
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// String summarizes the counts of the run
+func (r *ImportResult) String() string {
+	summary := fmt.Sprintf("%d created, %d updated, %d unchanged, %d removed, %d failed", r.Created, r.Updated, r.Unchanged, r.Removed, r.Failed)
+	if r.Quarantined > 0 {
+		summary += fmt.Sprintf(", %d quarantined", r.Quarantined)
+	}
+	return summary
+}
+
+// ImportJobResponse represents an import run in the background and its progress. Report holds
+// the final summary, or the error that stopped the run.
+type ImportJobResponse struct {
+	ID          string                `json:"job_id"`
+	Status      maintenance.JobStatus `json:"status"`
+	Full        bool                  `json:"full"`
+	Filters     *ImportFilters        `json:"filters,omitempty"`
+	Resume      uint                  `json:"resume,omitempty"`
+	RequestedBy uint                  `json:"requested_by"`
+	ImportProgress
+	RunID      uint       `json:"run_id,omitempty"`
+	Report     string     `json:"report,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Outcomes of importing a single property or applying a webhook event
+const (
+	ImportOutcomeCreated     = "created"
+	ImportOutcomeUpdated     = "updated"
+	ImportOutcomeRemoved     = "removed"
+	ImportOutcomeIgnored     = "ignored"
+	ImportOutcomeQuarantined = "quarantined"
+)
+
+// ImportPropertyResponse represents the property imported from a single listing and whether it was
+// created or updated
+type ImportPropertyResponse struct {
+	ExternalID uint            `json:"external_id"`
+	Outcome    string          `json:"outcome"`
+	Imovel     *ImovelResponse `json:"imovel"`
+	// Quarantine is the entry a listing failing validation is held in, instead of a property
+	Quarantine *ImportQuarantineResponse `json:"quarantine,omitempty"`
+}
+
+// WebhookResponse represents what an event pushed by the external API did to its property
+type WebhookResponse struct {
+	Event      string `json:"event"`
+	PropertyID uint   `json:"property_id"`
+	Outcome    string `json:"outcome"`
+	ImovelID   uint   `json:"imovel_id,omitempty"`
+}
+
+// ImportRunListQuery represents query parameters for the import runs and their errors
+type ImportRunListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// ImportRunResponse represents a recorded execution of the importer
+type ImportRunResponse struct {
+	ID         uint       `json:"id"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	FullSync   bool       `json:"full_sync"`
+	UserID     *uint      `json:"user_id"`
+	Total      int        `json:"total"`
+	Created    int        `json:"created"`
+	Updated    int        `json:"updated"`
+	Unchanged  int        `json:"unchanged"`
+	Failed     int        `json:"failed"`
+	Removed    int        `json:"removed"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Throttled  int        `json:"throttled"`
+	ThrottleMs int64      `json:"throttle_ms"`
+
+	// Listings held in quarantine for failing validation
+	Quarantined int `json:"quarantined"`
+
+	// Listings from the start of the list that went through, and the interrupted run resumed
+	Checkpoint           int   `json:"checkpoint"`
+	CheckpointExternalID uint  `json:"checkpoint_external_id"`
+	ResumedFrom          *uint `json:"resumed_from,omitempty"`
+
+	// Filters scoped the run to part of the catalog; nil imported all of it
+	Filters *ImportFilters `json:"filters,omitempty"`
+}
+
+// ImportRunListResponse represents the paginated import runs, newest first
+type ImportRunListResponse struct {
+	Total   int64               `json:"total"`
+	Page    int                 `json:"page"`
+	Limit   int                 `json:"limit"`
+	Pages   int64               `json:"pages"`
+	HasNext bool                `json:"hasNext"`
+	HasPrev bool                `json:"hasPrev"`
+	Results []ImportRunResponse `json:"results"`
+}
+
+// ImportRunErrorResponse represents a listing that failed to import
+type ImportRunErrorResponse struct {
+	ExternalID uint      `json:"external_id"`
+	Codigo     string    `json:"codigo"`
+	Error      string    `json:"error"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ImportRunErrorListResponse represents the paginated failures of an import run
+type ImportRunErrorListResponse struct {
+	Total   int64                    `json:"total"`
+	Page    int                      `json:"page"`
+	Limit   int                      `json:"limit"`
+	Pages   int64                    `json:"pages"`
+	HasNext bool                     `json:"hasNext"`
+	HasPrev bool                     `json:"hasPrev"`
+	Results []ImportRunErrorResponse `json:"results"`
+}
+
+// Statuses of the import conflicts a list is filtered by
+const (
+	ImportConflictStatusPending  = "pending"
+	ImportConflictStatusResolved = "resolved"
+	ImportConflictStatusAll      = "all"
+)
+
+// ImportConflictListQuery holds the pagination and status filter of the import conflicts list
+type ImportConflictListQuery struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Status string `form:"status,default=pending" binding:"oneof=pending resolved all"`
+}
+
+// ImportConflictListResponse represents the paginated import conflicts
+type ImportConflictListResponse struct {
 	Total   int64            `json:"total"`
 	Page    int              `json:"page"`
 	Limit   int              `json:"limit"`
 	Pages   int64            `json:"pages"`
 	HasNext bool             `json:"hasNext"`
 	HasPrev bool             `json:"hasPrev"`
-	Results []ImovelResponse `json:"results"`
+	Results []ImportConflict `json:"results"`
+}
+
+// ResolveImportConflictRequest picks the value of an import conflict that stays: LOCAL keeps the
+// field locked, REMOTE writes the value of the source and unlocks it
+type ResolveImportConflictRequest struct {
+	Resolucao string `json:"resolucao" binding:"required,oneof=LOCAL REMOTE"`
+}
+
+// Statuses of the quarantined listings a list is filtered by
+const (
+	ImportQuarantineStatusPending   = "pending"
+	ImportQuarantineStatusReleased  = "released"
+	ImportQuarantineStatusDiscarded = "discarded"
+	ImportQuarantineStatusAll       = "all"
+)
+
+// ImportQuarantineListQuery holds the pagination and status filter of the quarantined listings
+type ImportQuarantineListQuery struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Status string `form:"status,default=pending" binding:"oneof=pending released discarded all"`
+}
+
+// ImportQuarantineResponse represents a listing held in quarantine, with its payload as decoded
+// from the source and the violations that kept it from being imported
+type ImportQuarantineResponse struct {
+	ID           uint             `json:"id"`
+	Source       string           `json:"source"`
+	ExternalID   uint             `json:"external_id"`
+	Codigo       string           `json:"codigo"`
+	Violations   ImportViolations `json:"violations"`
+	Payload      json.RawMessage  `json:"payload" swaggertype:"object"`
+	Status       string           `json:"status"`
+	ResolvidoEm  *time.Time       `json:"resolvidoEm,omitempty"`
+	ResolvidoPor *uint            `json:"resolvidoPor,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// ImportQuarantineListResponse represents the paginated quarantined listings
+type ImportQuarantineListResponse struct {
+	Total   int64                      `json:"total"`
+	Page    int                        `json:"page"`
+	Limit   int                        `json:"limit"`
+	Pages   int64                      `json:"pages"`
+	HasNext bool                       `json:"hasNext"`
+	HasPrev bool                       `json:"hasPrev"`
+	Results []ImportQuarantineResponse `json:"results"`
 }
@@ -1,15 +1,18 @@
 package imoveis
 
-// External API DTOs for mapping responses from dev-api-backend.pi8.com.br
+// External API DTOs for mapping responses from dev-api-backend.pi8.com.br. Every ExternalSource
+// driver returns its listings in this shape.
 
 // ExternalAPIResponse represents the top-level response structure
 type ExternalAPIResponse struct {
 	Results ExternalResults `json:"results"`
 }
 
-// ExternalResults contains the entities array
+// ExternalResults contains the entities array and, when the list is paginated, the page counts
 type ExternalResults struct {
-	Entities []ExternalImovel `json:"entities"`
+	Entities   []ExternalImovel `json:"entities"`
+	Total      int              `json:"total,omitempty"`
+	TotalPages int              `json:"totalPages,omitempty"`
 }
 
 // ExternalImovel represents a property from the external API
@@ -38,6 +41,7 @@ type ExternalImovel struct {
 	PrecoVenda        *ExternalPrecoVenda   `json:"precoVenda"`
 	PrecoAluguel      *ExternalPrecoAluguel `json:"precoAluguel"`
 	Compartilhamentos []interface{}         `json:"compartilhamentos"`
+	UpdatedAt         string                `json:"updatedAt,omitempty"`
 }
 
 // ExternalEndereco represents address from external API
@@ -138,19 +142,20 @@ type ExternalDetailedImovel struct {
 
 // ExternalEmpreendimento represents enterprise from external API
 type ExternalEmpreendimento struct {
-	ID              uint             `json:"id"`
-	Codigo          string           `json:"codigo"`
-	Titulo          string           `json:"titulo"`
-	Descricao       string           `json:"descricao"`
-	DataEntrega     string           `json:"data_entrega"`
-	EtapaLancamento string           `json:"etapa_lancamento"`
-	Finalidade      string           `json:"finalidade"`
-	Tipo            string           `json:"tipo"`
-	Status          string           `json:"status"`
-	Localizacao     string           `json:"localizacao"`
-	Endereco        ExternalEndereco `json:"endereco"`
-	Torres          []ExternalTorre  `json:"torres"`
-	Plantas         []ExternalPlanta `json:"plantas"`
+	ID              uint                     `json:"id"`
+	Codigo          string                   `json:"codigo"`
+	Titulo          string                   `json:"titulo"`
+	Descricao       string                   `json:"descricao"`
+	DataEntrega     string                   `json:"data_entrega"`
+	EtapaLancamento string                   `json:"etapa_lancamento"`
+	Finalidade      string                   `json:"finalidade"`
+	Tipo            string                   `json:"tipo"`
+	Status          string                   `json:"status"`
+	Localizacao     string                   `json:"localizacao"`
+	Endereco        ExternalEndereco         `json:"endereco"`
+	Torres          []ExternalTorre          `json:"torres"`
+	Plantas         []ExternalPlanta         `json:"plantas"`
+	Caracteristicas []ExternalCaracteristica `json:"caracteristicas"`
 }
 
 // ExternalTorre represents tower from external API
@@ -170,3 +175,10 @@ type ExternalPlanta struct {
 	Metragem float64  `json:"metragem"`
 	Imagens  []string `json:"imagens"`
 }
+
+// ExternalCaracteristica represents a feature of an enterprise from external API
+type ExternalCaracteristica struct {
+	ID        uint   `json:"id"`
+	Nome      string `json:"nome"`
+	Categoria string `json:"categoria"`
+}
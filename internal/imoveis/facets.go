@@ -0,0 +1,179 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// priceBand is a [Min, Max) price interval; a zero Max leaves the band open-ended
+type priceBand struct {
+	Min float64
+	Max float64
+}
+
+// salePriceBands group sale prices for the faixaPreco facet
+var salePriceBands = []priceBand{
+	{0, 200000}, {200000, 400000}, {400000, 700000}, {700000, 1000000}, {1000000, 2000000}, {2000000, 0},
+}
+
+// rentPriceBands group rental prices for the faixaPreco facet when searching ALUGAR
+var rentPriceBands = []priceBand{
+	{0, 1500}, {1500, 3000}, {3000, 5000}, {5000, 10000}, {10000, 0},
+}
+
+// label returns a readable name for the band, e.g. "200000-400000" or "2000000+"
+func (b priceBand) label() string {
+	min := strconv.FormatFloat(b.Min, 'f', -1, 64)
+	if b.Max == 0 {
+		return min + "+"
+	}
+	return min + "-" + strconv.FormatFloat(b.Max, 'f', -1, 64)
+}
+
+// priceBandsFor returns the bands matching the searched objetivo
+func priceBandsFor(query *ImovelListQuery) []priceBand {
+	if query.Objetivo == "ALUGAR" {
+		return rentPriceBands
+	}
+	return salePriceBands
+}
+
+// priceBandCase builds a CASE expression returning the index of the band column falls in
+func priceBandCase(column string, bands []priceBand) (string, []interface{}) {
+	var sql strings.Builder
+	var args []interface{}
+	sql.WriteString("CASE")
+	for i, band := range bands[:len(bands)-1] {
+		fmt.Fprintf(&sql, " WHEN %s < ? THEN %d", column, i)
+		args = append(args, band.Max)
+	}
+	fmt.Fprintf(&sql, " ELSE %d END", len(bands)-1)
+	return sql.String(), args
+}
+
+// facetRow is one (facet, value) group of the facets query
+type facetRow struct {
+	Facet string
+	Value string
+	Total int64
+}
+
+// Facets counts the properties matching the list filters grouped by tipo, bairro, cidade,
+// price band and bedrooms. The filtered set is computed once and every facet is grouped in
+// the same statement.
+func (r *repository) Facets(ctx context.Context, query *ImovelListQuery) (*ImovelFacetsResponse, error) {
+	filtered := r.applyListFilters(r.db.WithContext(ctx).Model(&Imovel{}), query, false).
+		Select("imoveis.id, imoveis.tipo, imoveis.num_quartos, imoveis.endereco_id, imoveis.preco_venda_id, imoveis.preco_aluguel_id")
+
+	bands := priceBandsFor(query)
+	priceJoin := "JOIN preco_vendas p ON p.id = f.preco_venda_id"
+	if query.Objetivo == "ALUGAR" {
+		priceJoin = "JOIN preco_aluguels p ON p.id = f.preco_aluguel_id"
+	}
+	bandCase, bandArgs := priceBandCase("p.preco", bands)
+
+	args := []interface{}{filtered}
+	args = append(args, bandArgs...)
+	args = append(args, true)
+
+	var rows []facetRow
+	// WHY: Raw is not filtered by db.TenantScope, but the filtered subquery is, so f only holds
+	// the properties of the organizacao of ctx
+	if err := r.db.WithContext(ctx).Raw(`WITH f AS (?)
+		SELECT 'total' AS facet, '' AS value, COUNT(*) AS total FROM f
+		UNION ALL
+		SELECT 'tipo', COALESCE(f.tipo, ''), COUNT(*) FROM f GROUP BY f.tipo
+		UNION ALL
+		SELECT 'bairro', COALESCE(e.bairro, ''), COUNT(*) FROM f JOIN enderecos e ON e.id = f.endereco_id GROUP BY e.bairro
+		UNION ALL
+		SELECT 'cidade', COALESCE(e.cidade, ''), COUNT(*) FROM f JOIN enderecos e ON e.id = f.endereco_id GROUP BY e.cidade
+		UNION ALL
+		SELECT 'num_quartos', CAST(COALESCE(f.num_quartos, 0) AS TEXT), COUNT(*) FROM f GROUP BY f.num_quartos
+		UNION ALL
+		SELECT 'faixa_preco', CAST(b.band AS TEXT), COUNT(*) FROM (
+			SELECT `+bandCase+` AS band FROM f `+priceJoin+` AND p.ativo = ? AND p.deleted_at IS NULL
+		) b GROUP BY b.band`, args...).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return buildFacetsResponse(rows, bands), nil
+}
+
+// buildFacetsResponse turns the grouped rows into facet lists: text facets by count then value,
+// bedrooms ascending and price bands in band order. Empty values are left out.
+func buildFacetsResponse(rows []facetRow, bands []priceBand) *ImovelFacetsResponse {
+	facets := &ImovelFacetsResponse{
+		Tipo:       []FacetCount{},
+		Bairro:     []FacetCount{},
+		Cidade:     []FacetCount{},
+		FaixaPreco: []PriceRangeFacet{},
+		NumQuartos: []FacetCount{},
+	}
+
+	bandCounts := make([]int64, len(bands))
+	for _, row := range rows {
+		value := strings.TrimSpace(row.Value)
+		switch row.Facet {
+		case "total":
+			facets.Total = row.Total
+		case "tipo":
+			facets.Tipo = appendFacet(facets.Tipo, value, row.Total)
+		case "bairro":
+			facets.Bairro = appendFacet(facets.Bairro, value, row.Total)
+		case "cidade":
+			facets.Cidade = appendFacet(facets.Cidade, value, row.Total)
+		case "num_quartos":
+			facets.NumQuartos = appendFacet(facets.NumQuartos, value, row.Total)
+		case "faixa_preco":
+			if index, err := strconv.Atoi(value); err == nil && index >= 0 && index < len(bands) {
+				bandCounts[index] += row.Total
+			}
+		}
+	}
+
+	for _, list := range [][]FacetCount{facets.Tipo, facets.Bairro, facets.Cidade} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Count != list[j].Count {
+				return list[i].Count > list[j].Count
+			}
+			return list[i].Value < list[j].Value
+		})
+	}
+	sort.Slice(facets.NumQuartos, func(i, j int) bool {
+		a, _ := strconv.Atoi(facets.NumQuartos[i].Value)
+		b, _ := strconv.Atoi(facets.NumQuartos[j].Value)
+		return a < b
+	})
+
+	for i, band := range bands {
+		if bandCounts[i] == 0 {
+			continue
+		}
+		facet := PriceRangeFacet{Label: band.label(), Min: band.Min, Count: bandCounts[i]}
+		if band.Max > 0 {
+			max := band.Max
+			facet.Max = &max
+		}
+		facets.FaixaPreco = append(facets.FaixaPreco, facet)
+	}
+
+	return facets
+}
+
+// appendFacet adds a value to a facet list, merging values that differ only by surrounding spaces
+func appendFacet(list []FacetCount, value string, count int64) []FacetCount {
+	if value == "" {
+		return list
+	}
+	for i := range list {
+		if list[i].Value == value {
+			list[i].Count += count
+			return list
+		}
+	}
+	return append(list, FacetCount{Value: value, Count: count})
+}
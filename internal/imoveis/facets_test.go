@@ -0,0 +1,88 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFacets(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	fixtures := []struct {
+		codigo, tipo, bairro, cidade string
+		quartos                      int
+		venda, aluguel               float64
+	}{
+		{"AP-001", "APARTAMENTO", "Batel", "Curitiba", 2, 550000, 2800},
+		{"AP-002", "APARTAMENTO", "Batel", "Curitiba", 3, 650000, 3500},
+		{"CA-001", "CASA", "Centro", "Curitiba", 3, 1200000, 6000},
+		{"CA-002", "CASA", "", "Londrina", 4, 2500000, 12000},
+	}
+	for _, f := range fixtures {
+		req := nestedCreateRequest(f.codigo)
+		req.Tipo = f.tipo
+		req.NumQuartos = f.quartos
+		req.Endereco.Bairro = f.bairro
+		req.Endereco.Cidade = f.cidade
+		req.PrecoVenda.Preco = f.venda
+		req.PrecoAluguel.Preco = f.aluguel
+		_, err := svc.CreateImovel(ctx, req)
+		require.NoError(t, err)
+	}
+
+	facets, err := svc.GetFacets(ctx, &ImovelListQuery{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), facets.Total)
+	assert.Equal(t, []FacetCount{{"APARTAMENTO", 2}, {"CASA", 2}}, facets.Tipo)
+	assert.Equal(t, []FacetCount{{"Batel", 2}, {"Centro", 1}}, facets.Bairro, "empty bairros are left out")
+	assert.Equal(t, []FacetCount{{"Curitiba", 3}, {"Londrina", 1}}, facets.Cidade)
+	assert.Equal(t, []FacetCount{{"2", 1}, {"3", 2}, {"4", 1}}, facets.NumQuartos)
+
+	require.Len(t, facets.FaixaPreco, 3)
+	assert.Equal(t, "400000-700000", facets.FaixaPreco[0].Label)
+	assert.Equal(t, int64(2), facets.FaixaPreco[0].Count)
+	assert.Equal(t, "1000000-2000000", facets.FaixaPreco[1].Label)
+	assert.Equal(t, "2000000+", facets.FaixaPreco[2].Label)
+	assert.Equal(t, 2000000.0, facets.FaixaPreco[2].Min)
+	assert.Nil(t, facets.FaixaPreco[2].Max)
+
+	facets, err = svc.GetFacets(ctx, &ImovelListQuery{Tipo: "CASA", Objetivo: "VENDER"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), facets.Total)
+	assert.Equal(t, []FacetCount{{"CASA", 2}}, facets.Tipo)
+	assert.Equal(t, []FacetCount{{"3", 1}, {"4", 1}}, facets.NumQuartos)
+}
+
+func TestGetFacets_RentBands(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	for _, codigo := range []string{"AP-001", "AP-002"} {
+		req := nestedCreateRequest(codigo)
+		req.Objetivo = "ALUGAR"
+		_, err := svc.CreateImovel(ctx, req)
+		require.NoError(t, err)
+	}
+	// Inactive prices are not counted
+	require.NoError(t, database.Model(&PrecoAluguel{}).Where("id = ?", 2).Update("ativo", false).Error)
+
+	facets, err := svc.GetFacets(ctx, &ImovelListQuery{Objetivo: "ALUGAR"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), facets.Total)
+	require.Len(t, facets.FaixaPreco, 1)
+	assert.Equal(t, "1500-3000", facets.FaixaPreco[0].Label)
+	assert.Equal(t, int64(1), facets.FaixaPreco[0].Count)
+	require.NotNil(t, facets.FaixaPreco[0].Max)
+	assert.Equal(t, 3000.0, *facets.FaixaPreco[0].Max)
+}
+
+func TestPriceBandCase(t *testing.T) {
+	sql, args := priceBandCase("p.preco", []priceBand{{0, 100}, {100, 200}, {200, 0}})
+	assert.Equal(t, "CASE WHEN p.preco < ? THEN 0 WHEN p.preco < ? THEN 1 ELSE 2 END", sql)
+	assert.Equal(t, []interface{}{100.0, 200.0}, args)
+}
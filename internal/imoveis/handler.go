@@ -1,47 +1,418 @@
 package imoveis
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
 	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
 )
 
 // Handler defines HTTP handlers for imovel operations
 type Handler struct {
 	service       Service
 	importService ImportService
+	uploadService UploadService
 }
 
 // NewHandler creates a new imovel handler
-func NewHandler(service Service, importService ImportService) *Handler {
+func NewHandler(service Service, importService ImportService, uploadService UploadService) *Handler {
 	return &Handler{
 		service:       service,
 		importService: importService,
+		uploadService: uploadService,
 	}
 }
 
 // @Summary Import properties from external API
-// @Description Import all published properties from dev-api-backend.pi8.com.br. Uses upsert logic - creates new properties and updates existing ones based on id_integracao mapping. Existing properties are detected via id_integracao field and updated with latest data. Attachments are deduplicated by URL.
+// @Description Start importing the published properties from dev-api-backend.pi8.com.br in the background and return the job right away; its progress is available at /api/v1/imoveis/import/jobs/{id}. Uses upsert logic - creates new properties and updates existing ones based on id_integracao mapping. Attachments are deduplicated by URL. When incremental imports are enabled only the properties changed since the last successful run are imported, unless full is set. Resume picks up an interrupted run from its checkpoint, with its mode and filters, instead of starting over.
 // @Tags imoveis
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Import completed with statistics (created, updated, failed counts)"
+// @Param full query bool false "Re-import every property, ignoring the incremental state"
+// @Param resume query int false "ID of an interrupted import run to resume"
+// @Param filters body ImportFilters false "Import only the listings matching all the filters given; scoped runs neither handle removed listings nor move the incremental watermark"
+// @Success 202 {object} errors.Response{success=bool,data=ImportJobResponse}
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/imoveis/import [post]
 func (h *Handler) ImportProperties(c *gin.Context) {
-	if err := h.importService.ImportPublishedProperties(c.Request.Context()); err != nil {
+	var opts ImportOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	// Filters may also come in the body, e.g. {"cidades": ["São Paulo"], "ids": [123, 456]}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			_ = c.Error(apiErrors.FromGinValidation(err))
+			return
+		}
+	}
+
+	job, err := h.importService.StartImport(c.Request.Context(), opts, contextutil.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, ErrImportRunning) {
+			_ = c.Error(apiErrors.Conflict("An import is already running"))
+			return
+		}
+		if errors.Is(err, ErrImportRunNotFound) {
+			_ = c.Error(apiErrors.NotFound("Import run not found"))
+			return
+		}
+		if errors.Is(err, ErrImportRunNotResumable) {
+			_ = c.Error(apiErrors.Conflict("Import run completed, nothing to resume"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, apiErrors.Success(job))
+}
+
+// @Summary Import a single property from external API
+// @Description Import or refresh the one property of a listing by its external ID right away, with all its relations, without a full run and regardless of the incremental state. Useful to fix a single listing out of sync. A listing failing validation is held in quarantine instead (outcome quarantined).
+// @Tags imoveis
+// @Produce json
+// @Security BearerAuth
+// @Param externalId path uint true "External listing ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImportPropertyResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/import/{externalId} [post]
+func (h *Handler) ImportProperty(c *gin.Context) {
+	var req struct {
+		ExternalID uint `uri:"externalId" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.importService.ImportProperty(c.Request.Context(), req.ExternalID)
+	if err != nil {
+		if errors.Is(err, ErrExternalListingNotFound) {
+			_ = c.Error(apiErrors.NotFound("Listing not found in external API"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Import completed",
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Receive events from the external API
+// @Description Webhook the external API calls when a listing is created, updated or deleted, so its property is synced right away instead of on the next import. The X-Pi8-Signature header is the hex HMAC-SHA256, under the shared secret (externalapi.webhook_secret), of "<X-Pi8-Timestamp>.<X-Pi8-Delivery>.<body>". Events signed more than externalapi.webhook_tolerance seconds away from the server clock are rejected, and a delivery ID already applied is ignored. Created and updated listings are imported; deleted ones are handled by the removed policy. A failure returns an error so the event is sent again with the same delivery ID.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param X-Pi8-Signature header string true "HMAC-SHA256 of the timestamp, delivery ID and body"
+// @Param X-Pi8-Timestamp header string true "Unix time the event was signed at"
+// @Param X-Pi8-Delivery header string true "Delivery ID"
+// @Param event body WebhookEvent true "Event"
+// @Success 200 {object} errors.Response{success=bool,data=WebhookResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/integrations/pi8/webhook [post]
+func (h *Handler) ReceiveWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Failed to read request body"))
+		return
+	}
+
+	result, err := h.importService.HandleWebhook(c.Request.Context(), body, WebhookHeaders{
+		Signature:  c.GetHeader(WebhookSignatureHeader),
+		Timestamp:  c.GetHeader(WebhookTimestampHeader),
+		DeliveryID: c.GetHeader(WebhookDeliveryHeader),
 	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrWebhookDisabled):
+			_ = c.Error(apiErrors.NotFound("Webhook not configured"))
+		case errors.Is(err, ErrInvalidWebhookSignature):
+			_ = c.Error(apiErrors.Unauthorized("Invalid webhook signature"))
+		case errors.Is(err, ErrStaleWebhook):
+			_ = c.Error(apiErrors.Unauthorized("Webhook timestamp outside the tolerance window"))
+		case errors.Is(err, ErrInvalidWebhookEvent):
+			_ = c.Error(apiErrors.BadRequest(err.Error()))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get import job progress
+// @Description Retrieve the status, the processed/created/updated/failed counts and the final report of an import job
+// @Tags imoveis
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImportJobResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/import/jobs/{id} [get]
+func (h *Handler) GetImportJob(c *gin.Context) {
+	job, err := h.importService.GetImportJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrImportJobNotFound) {
+			_ = c.Error(apiErrors.NotFound("Import job not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(job))
+}
+
+// @Summary List import runs (Admin only)
+// @Description Recorded executions of the importer with their counts, duration and outcome, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=ImportRunListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports [get]
+func (h *Handler) ListImportRuns(c *gin.Context) {
+	var query ImportRunListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.importService.ListImportRuns(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary List import run errors (Admin only)
+// @Description The listings that failed to import in a run and why
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Import run ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=ImportRunErrorListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/{id}/errors [get]
+func (h *Handler) ListImportRunErrors(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query ImportRunListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.importService.ListImportRunErrors(c.Request.Context(), req.ID, &query)
+	if err != nil {
+		if errors.Is(err, ErrImportRunNotFound) {
+			_ = c.Error(apiErrors.NotFound("Import run not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary List import conflicts (Admin only)
+// @Description Fields edited by hand the source changed, held for review under the review conflict policy, oldest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "pending, resolved or all" default(pending)
+// @Success 200 {object} errors.Response{success=bool,data=ImportConflictListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/conflicts [get]
+func (h *Handler) ListImportConflicts(c *gin.Context) {
+	var query ImportConflictListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.importService.ListImportConflicts(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Resolve an import conflict (Admin only)
+// @Description LOCAL keeps the value edited by hand and the field locked; REMOTE writes the value of the source and unlocks the field
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Import conflict ID"
+// @Param request body ResolveImportConflictRequest true "Resolution"
+// @Success 200 {object} errors.Response{success=bool,data=ImportConflict}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/conflicts/{id}/resolve [post]
+func (h *Handler) ResolveImportConflict(c *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req ResolveImportConflictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	conflict, err := h.importService.ResolveImportConflict(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrImportConflictNotFound):
+			_ = c.Error(apiErrors.NotFound("Import conflict not found"))
+		case errors.Is(err, ErrImportConflictResolved):
+			_ = c.Error(apiErrors.Conflict("Import conflict already resolved"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(conflict))
+}
+
+// @Summary List quarantined listings (Admin only)
+// @Description Listings of the source that failed validation (codigo, metragem, tipo, CEP), held with their payload instead of being imported, oldest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "pending, released, discarded or all" default(pending)
+// @Success 200 {object} errors.Response{success=bool,data=ImportQuarantineListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/quarantine [get]
+func (h *Handler) ListImportQuarantine(c *gin.Context) {
+	var query ImportQuarantineListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.importService.ListImportQuarantine(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Retry a quarantined listing (Admin only)
+// @Description Imports the listing again from the source; the entry is released when it now passes validation and refreshed otherwise
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Quarantine entry ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImportQuarantineResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/quarantine/{id}/retry [post]
+func (h *Handler) RetryImportQuarantine(c *gin.Context) {
+	h.resolveQuarantine(c, h.importService.RetryImportQuarantine)
+}
+
+// @Summary Discard a quarantined listing (Admin only)
+// @Description Dismisses the entry; the listing is held again only once the source changes it
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Quarantine entry ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImportQuarantineResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imports/quarantine/{id}/discard [post]
+func (h *Handler) DiscardImportQuarantine(c *gin.Context) {
+	h.resolveQuarantine(c, h.importService.DiscardImportQuarantine)
+}
+
+// resolveQuarantine applies action to the quarantine entry in the path
+func (h *Handler) resolveQuarantine(c *gin.Context, action func(context.Context, uint) (*ImportQuarantineResponse, error)) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	entry, err := action(c.Request.Context(), req.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrQuarantineNotFound):
+			_ = c.Error(apiErrors.NotFound("Quarantined listing not found"))
+		case errors.Is(err, ErrExternalListingNotFound):
+			_ = c.Error(apiErrors.NotFound("Listing not found in external API"))
+		case errors.Is(err, ErrQuarantineResolved):
+			_ = c.Error(apiErrors.Conflict("Quarantined listing already released or discarded"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(entry))
 }
 
 // @Summary Get property by ID
@@ -49,95 +420,808 @@ func (h *Handler) ImportProperties(c *gin.Context) {
 // @Tags imoveis
 // @Accept json
 // @Produce json
-// @Param id path uint true "Property ID"
-// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Param id path uint true "Property ID"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param If-Modified-Since header string false "Last-Modified of a previous response"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Success 304 "Not modified"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id} [get]
+func (h *Handler) GetImovel(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.GetImovel(c.Request.Context(), req.ID)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	if imovel == nil {
+		_ = c.Error(apiErrors.NotFound("Property not found"))
+		return
+	}
+
+	writeImovel(c, imovel)
+}
+
+// @Summary Get property by slug
+// @Description Get a property by its public URL slug (e.g. apartamento-3-quartos-moema-ap1234)
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param slug path string true "Property slug"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param If-Modified-Since header string false "Last-Modified of a previous response"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Success 304 "Not modified"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/slug/{slug} [get]
+func (h *Handler) GetImovelBySlug(c *gin.Context) {
+	var req struct {
+		Slug string `uri:"slug" binding:"required,max=255"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.GetImovelBySlug(c.Request.Context(), req.Slug)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeImovel(c, imovel)
+}
+
+// @Summary Create a new property
+// @Description Create a new property. endereco, precoVenda and precoAluguel may be sent as nested objects
+// @Description instead of IDs; they are created with the property in a single transaction.
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateImovelRequest true "Property creation request"
+// @Success 201 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis [post]
+func (h *Handler) CreateImovel(c *gin.Context) {
+	var req CreateImovelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.CreateImovel(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(imovel))
+}
+
+// @Summary Update a property
+// @Description Update an existing property
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param If-Match header string false "Version of the property being updated, when not sent in the body"
+// @Param request body UpdateImovelRequest true "Property update request"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id} [put]
+func (h *Handler) UpdateImovel(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateImovelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if !bindVersion(c, &req.Version) {
+		return
+	}
+	if req.Version == nil {
+		_ = c.Error(apiErrors.ValidationError(map[string]string{
+			"version": "required: send the version of the property in the body or an If-Match header",
+		}))
+		return
+	}
+
+	imovel, err := h.service.UpdateImovel(c.Request.Context(), uriReq.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+}
+
+// @Summary Partially update a property
+// @Description Apply a JSON merge patch to a property. Absent fields are kept, null clears optional fields (descricao, numAndar, empreendimento_id, ...) and other values replace them.
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param If-Match header string false "Version of the property being patched; the patch fails with 409 when it changed since"
+// @Param request body PatchImovelRequest true "Property merge patch"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id} [patch]
+func (h *Handler) PatchImovel(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PatchImovelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid request body"))
+		return
+	}
+
+	if details := req.Validate(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+	if !bindVersion(c, &req.Version) {
+		return
+	}
+
+	imovel, err := h.service.PatchImovel(c.Request.Context(), uriReq.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+}
+
+// @Summary Publish a property
+// @Description Move a property from EM_EDICAO to PUBLICADO. Requires an address, at least one attachment and an active price for its objetivo.
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/publish [post]
+func (h *Handler) PublishImovel(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.PublishImovel(c.Request.Context(), req.ID)
+	if err != nil {
+		var reqErr *PublishRequirementsError
+		if errors.As(err, &reqErr) {
+			_ = c.Error(apiErrors.ValidationError(reqErr.Missing))
+			return
+		}
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+}
+
+// @Summary Unpublish a property
+// @Description Archive a published property (PUBLICADO to ARQUIVADO)
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/unpublish [post]
+func (h *Handler) UnpublishImovel(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.UnpublishImovel(c.Request.Context(), req.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+}
+
+// @Summary Permanently delete a property (Admin only)
+// @Description Hard delete a property, bypassing the trash
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imoveis/{id} [delete]
+func (h *Handler) HardDeleteImovel(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.HardDeleteImovel(c.Request.Context(), req.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Property statistics
+// @Description Total number of properties and count per status
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Success 200 {object} errors.Response{success=bool,data=ImovelStatsResponse}
+// @Router /api/v1/imoveis/stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+	stats, err := h.service.GetStats(c.Request.Context())
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(stats))
+}
+
+// @Summary Search facets
+// @Description Counts of the properties matching the list filters grouped by tipo, bairro, cidade, price band and
+// @Description number of bedrooms. Price bands use rental prices when objetivo is ALUGAR and sale prices otherwise.
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param codigo query string false "Property code (partial match)"
+// @Param tipo query string false "Property type (APARTAMENTO, CASA, COMERCIAL, SALA_COMERCIAL, TERRENO, GALPAO)"
+// @Param objetivo query string false "Property objective (VENDER, ALUGAR)"
+// @Param finalidade query string false "Property purpose (RESIDENTIAL, COMERCIAL, MISTO)"
+// @Param status query string false "Property status (PUBLICADO, EM_EDICAO, ARQUIVADO)"
+// @Param published query bool false "Published status"
+// @Param removido_origem query bool false "Imported properties removed from the source (true) or not (false)"
+// @Param min_preco query number false "Minimum sale price"
+// @Param max_preco query number false "Maximum sale price"
+// @Param min_preco_aluguel query number false "Minimum rental price"
+// @Param max_preco_aluguel query number false "Maximum rental price"
+// @Param min_metragem query number false "Minimum square meters"
+// @Param max_metragem query number false "Maximum square meters"
+// @Param rua query string false "Street name (partial match)"
+// @Param cidade query string false "City name (partial match)"
+// @Param bairro query string false "Neighborhood name (partial match)"
+// @Param num_quartos query int false "Minimum number of bedrooms"
+// @Param num_banheiros query int false "Minimum number of bathrooms"
+// @Param num_garagens query int false "Minimum number of parking spaces"
+// @Param empreendimento_id query uint false "Development ID"
+// @Param caracteristicas query string false "Comma-separated caracteristica IDs (e.g. 1,5,9)"
+// @Param caracteristicas_match query string false "Caracteristicas matching mode (any, all)" default(any)
+// @Param min_lat query number false "Viewport south latitude (requires all four bounds)"
+// @Param max_lat query number false "Viewport north latitude"
+// @Param min_lng query number false "Viewport west longitude"
+// @Param max_lng query number false "Viewport east longitude"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelFacetsResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/facets [get]
+func (h *Handler) GetFacets(c *gin.Context) {
+	var query ImovelListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if details := query.boundingBoxErrors(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+
+	facets, err := h.service.GetFacets(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(facets))
+}
+
+// @Summary List properties of an enterprise
+// @Description Paginated list of the properties of an empreendimento
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Empreendimento ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[ImovelResponse]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/imoveis [get]
+func (h *Handler) ListByEmpreendimento(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	results, total, err := h.service.ListImovelsByEmpreendimento(c.Request.Context(), uriReq.ID, query.Page, query.Limit)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(newListResponse(results, total, query.Page, query.Limit)))
+}
+
+// @Summary Count properties of an enterprise
+// @Description Number of properties of an empreendimento
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Empreendimento ID"
+// @Success 200 {object} errors.Response{success=bool,data=EmpreendimentoCountResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/empreendimentos/{id}/imoveis/count [get]
+func (h *Handler) CountByEmpreendimento(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	total, err := h.service.CountImovelsByEmpreendimento(c.Request.Context(), uriReq.ID)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(EmpreendimentoCountResponse{EmpreendimentoID: uriReq.ID, Total: total}))
+}
+
+// @Summary List properties of an organization
+// @Description Paginated list of the properties whose corretor principal belongs to the organizacao
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Organizacao ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[ImovelResponse]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes/{id}/imoveis [get]
+func (h *Handler) ListByOrganizacao(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	results, total, err := h.service.ListImovelsByOrganizacao(c.Request.Context(), uriReq.ID, query.Page, query.Limit)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(newListResponse(results, total, query.Page, query.Limit)))
+}
+
+// @Summary List properties of a corretor
+// @Description Paginated list of the properties of a corretor principal with stats over the whole portfolio (counts by status, views and average active prices)
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Corretor ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=CorretorImoveisResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/corretores/{id}/imoveis [get]
+func (h *Handler) ListByCorretor(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListImovelsByCorretor(c.Request.Context(), uriReq.ID, query.Page, query.Limit)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Restore a deleted property
+// @Description Restore a soft-deleted property
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/restore [post]
+func (h *Handler) RestoreImovel(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	imovel, err := h.service.RestoreImovel(c.Request.Context(), req.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+}
+
+// @Summary List deleted properties (Admin only)
+// @Description Paginated list of soft-deleted properties, most recently deleted first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=TrashListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imoveis/trash [get]
+func (h *Handler) ListTrash(c *gin.Context) {
+	var query TrashListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListTrash(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Archive stale properties (Admin only)
+// @Description Archive the published properties not updated or imported within the archive period of their organizacao (dias_arquivamento, or the configured default), recording the reason in the audit log and emailing each corretor a summary. The same job runs periodically.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=ArchiveStaleResponse}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imoveis/archive-stale [post]
+func (h *Handler) ArchiveStale(c *gin.Context) {
+	result, err := h.service.ArchiveStale(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get property audit log (Admin only)
+// @Description Who created, changed, published or deleted a property and its related records, with the changed fields, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=AuditListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/imoveis/{id}/audit [get]
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query AuditListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.GetAuditLog(c.Request.Context(), req.ID, &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary List featured properties
+// @Description Published properties whose pacote is em_destaque, for the homepage. The featured pool is cached for a few minutes and rotated randomly unless random=false, which returns the most recently updated first.
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param limit query int false "Maximum number of properties" default(8)
+// @Param objetivo query string false "Objective (VENDER, ALUGAR)"
+// @Param tipo query string false "Property type (APARTAMENTO, CASA, COMERCIAL, SALA_COMERCIAL, TERRENO, GALPAO)"
+// @Param cidade query string false "City name (partial match)"
+// @Param random query bool false "Rotate the featured properties randomly" default(true)
+// @Success 200 {object} errors.Response{success=bool,data=[]ImovelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/destaques [get]
+func (h *Handler) ListDestaques(c *gin.Context) {
+	var query DestaquesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	destaques, err := h.service.ListDestaques(c.Request.Context(), &query)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(destaques))
+}
+
+// @Summary Compare properties
+// @Description Return 2 to 4 properties side by side with normalized prices (price per m²), condominio and a caracteristicas matrix
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma separated property IDs, e.g. 1,2,3"
+// @Success 200 {object} errors.Response{success=bool,data=ImovelComparisonResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
-// @Router /api/v1/imoveis/{id} [get]
-func (h *Handler) GetImovel(c *gin.Context) {
-	var req struct {
-		ID uint `uri:"id" binding:"required"`
-	}
-
-	if err := c.ShouldBindUri(&req); err != nil {
+// @Router /api/v1/imoveis/compare [get]
+func (h *Handler) CompareImoveis(c *gin.Context) {
+	var query CompareQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
 		_ = c.Error(apiErrors.FromGinValidation(err))
 		return
 	}
 
-	imovel, err := h.service.GetImovel(c.Request.Context(), req.ID)
+	ids, err := parseCompareIDs(query.IDs)
 	if err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		_ = c.Error(apiErrors.ValidationError(map[string]string{"ids": err.Error()}))
 		return
 	}
 
-	if imovel == nil {
-		_ = c.Error(apiErrors.NotFound("Property not found"))
+	comparison, err := h.service.CompareImoveis(c.Request.Context(), ids)
+	if err != nil {
+		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+	c.JSON(http.StatusOK, apiErrors.Success(comparison))
 }
 
-// @Summary Create a new property
-// @Description Create a new property
+// @Summary Change the status of several properties
+// @Description Publish (PUBLICADO) or archive (ARQUIVADO) up to 100 properties in a single transaction. Properties that do not exist or cannot make the transition are reported per item and skipped.
 // @Tags imoveis
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body CreateImovelRequest true "Property creation request"
-// @Success 201 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Param request body BulkStatusRequest true "Property IDs and target status"
+// @Success 200 {object} errors.Response{success=bool,data=BulkResultResponse}
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
-// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
-// @Router /api/v1/imoveis [post]
-func (h *Handler) CreateImovel(c *gin.Context) {
-	var req CreateImovelRequest
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/bulk/status [post]
+func (h *Handler) BulkUpdateStatus(c *gin.Context) {
+	var req BulkStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		_ = c.Error(apiErrors.FromGinValidation(err))
 		return
 	}
 
-	imovel, err := h.service.CreateImovel(c.Request.Context(), &req)
+	result, err := h.service.BulkUpdateStatus(c.Request.Context(), &req)
 	if err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, apiErrors.Success(imovel))
+	c.JSON(http.StatusOK, apiErrors.Success(result))
 }
 
-// @Summary Update a property
-// @Description Update an existing property
+// @Summary Delete several properties
+// @Description Soft delete up to 100 properties in a single transaction. Properties that do not exist are reported per item.
 // @Tags imoveis
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body BulkDeleteRequest true "Property IDs"
+// @Success 200 {object} errors.Response{success=bool,data=BulkResultResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/bulk/delete [post]
+func (h *Handler) BulkDelete(c *gin.Context) {
+	var req BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.BulkDelete(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Register a property view
+// @Description Atomically increment the view counter. Repeated views from the same IP, or the same IP and X-Session-ID header when one is sent, within 30 minutes are not counted. Deduplication is kept in memory by each API instance.
+// @Tags imoveis
+// @Accept json
+// @Produce json
 // @Param id path uint true "Property ID"
-// @Param request body UpdateImovelRequest true "Property update request"
-// @Success 200 {object} errors.Response{success=bool,data=ImovelResponse}
+// @Param X-Session-ID header string false "Viewer session ID, told apart from other viewers on the same IP"
+// @Success 200 {object} errors.Response{success=bool,data=ViewRegisteredResponse}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
-// @Router /api/v1/imoveis/{id} [put]
-func (h *Handler) UpdateImovel(c *gin.Context) {
-	var uriReq struct {
+// @Router /api/v1/imoveis/{id}/view [post]
+func (h *Handler) RegisterView(c *gin.Context) {
+	var req struct {
 		ID uint `uri:"id" binding:"required"`
 	}
 
-	if err := c.ShouldBindUri(&uriReq); err != nil {
+	if err := c.ShouldBindUri(&req); err != nil {
 		_ = c.Error(apiErrors.FromGinValidation(err))
 		return
 	}
 
-	var req UpdateImovelRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	viewer := viewerKey(c.ClientIP(), c.GetHeader("X-Session-ID"))
+	result, err := h.service.RegisterView(c.Request.Context(), req.ID, viewer)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Most viewed properties
+// @Description Ranking of properties by view count
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of properties" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=[]ImovelViewStats}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/stats/views [get]
+func (h *Handler) GetViewStats(c *gin.Context) {
+	var query ViewStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
 		_ = c.Error(apiErrors.FromGinValidation(err))
 		return
 	}
 
-	imovel, err := h.service.UpdateImovel(c.Request.Context(), uriReq.ID, &req)
+	stats, err := h.service.TopViewedImoveis(c.Request.Context(), query.Limit)
 	if err != nil {
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, apiErrors.Success(imovel))
+	c.JSON(http.StatusOK, apiErrors.Success(stats))
+}
+
+// @Summary Property views per corretor
+// @Description Total and average property views aggregated per corretor principal
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of corretores" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=[]CorretorViewStats}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/stats/views/corretores [get]
+func (h *Handler) GetCorretorViewStats(c *gin.Context) {
+	var query ViewStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	stats, err := h.service.ViewStatsByCorretor(c.Request.Context(), query.Limit)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(stats))
 }
 
 // @Summary Delete a property
@@ -148,6 +1232,7 @@ func (h *Handler) UpdateImovel(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path uint true "Property ID"
 // @Success 204 "No Content"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/imoveis/{id} [delete]
 func (h *Handler) DeleteImovel(c *gin.Context) {
@@ -161,7 +1246,7 @@ func (h *Handler) DeleteImovel(c *gin.Context) {
 	}
 
 	if err := h.service.DeleteImovel(c.Request.Context(), req.ID); err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		h.handleServiceError(c, err)
 		return
 	}
 
@@ -181,8 +1266,11 @@ func (h *Handler) DeleteImovel(c *gin.Context) {
 // @Param finalidade query string false "Property purpose (RESIDENTIAL, COMERCIAL, MISTO)"
 // @Param status query string false "Property status (PUBLICADO, EM_EDICAO, ARQUIVADO)"
 // @Param published query bool false "Published status"
-// @Param min_preco query number false "Minimum price"
-// @Param max_preco query number false "Maximum price"
+// @Param removido_origem query bool false "Imported properties removed from the source (true) or not (false)"
+// @Param min_preco query number false "Minimum sale price"
+// @Param max_preco query number false "Maximum sale price"
+// @Param min_preco_aluguel query number false "Minimum rental price"
+// @Param max_preco_aluguel query number false "Maximum rental price"
 // @Param min_metragem query number false "Minimum square meters"
 // @Param max_metragem query number false "Maximum square meters"
 // @Param rua query string false "Street name (partial match)"
@@ -192,9 +1280,22 @@ func (h *Handler) DeleteImovel(c *gin.Context) {
 // @Param num_banheiros query int false "Minimum number of bathrooms"
 // @Param num_garagens query int false "Minimum number of parking spaces"
 // @Param empreendimento_id query uint false "Development ID"
-// @Param sort query string false "Sort field (created_at, updated_at, preco, titulo, metragem)" default(created_at)
-// @Param order query string false "Sort order (asc, desc)" default(desc)
-// @Success 200 {object} errors.Response{success=bool,data=ImovelListResponse}
+// @Param caracteristicas query string false "Comma-separated caracteristica IDs (e.g. 1,5,9)"
+// @Param caracteristicas_match query string false "Caracteristicas matching mode (any, all)" default(any)
+// @Param min_lat query number false "Viewport south latitude (requires all four bounds)"
+// @Param max_lat query number false "Viewport north latitude"
+// @Param min_lng query number false "Viewport west longitude"
+// @Param max_lng query number false "Viewport east longitude"
+// @Param mode query string false "Response mode (full, map); map returns only id, titulo, coordinates and preco" default(full)
+// @Param sort query string false "Comma-separated sort fields with optional direction, e.g. preco_venda:asc,created_at:desc (created_at, updated_at, titulo, metragem, num_quartos, visualizacoes, preco, preco_venda, preco_aluguel)" default(created_at)
+// @Param order query string false "Sort order for fields without a direction (asc, desc)" default(desc)
+// @Param include query string false "Comma-separated relations to load (endereco, empreendimento, planta, corretorPrincipal, pacote, precoVenda, precoAluguel, anexos, caracteristicas)"
+// @Param fields query string false "Comma-separated property attributes to return (e.g. titulo,codigo,status); id is always returned"
+// @Param cursor query string false "Keyset pagination: empty for the first page, then the returned next_cursor (sort must be created_at)"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[ImovelResponse]}
+// @Success 200 {object} errors.Response{success=bool,data=ImovelMapListResponse} "mode=map"
+// @Success 304 "Not modified"
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/imoveis [get]
 func (h *Handler) ListImoveis(c *gin.Context) {
@@ -204,25 +1305,60 @@ func (h *Handler) ListImoveis(c *gin.Context) {
 		return
 	}
 
+	if details := query.boundingBoxErrors(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+	if details := query.sortErrors(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+	if details := query.cursorErrors(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+	if details := query.selectionErrors(); len(details) > 0 {
+		_ = c.Error(apiErrors.ValidationError(details))
+		return
+	}
+
+	if query.Mode == "map" {
+		result, err := h.service.ListImoveisMap(c.Request.Context(), &query)
+		if err != nil {
+			_ = c.Error(apiErrors.InternalServerError(err))
+			return
+		}
+
+		writeConditional(c, result, "", time.Time{})
+		return
+	}
+
 	result, err := h.service.ListImoveis(c.Request.Context(), &query)
 	if err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, apiErrors.Success(result))
+	writeConditional(c, result, "", time.Time{})
 }
 
 // @Summary Add attachment to property
-// @Description Add an image or document attachment to a property
+// @Description Add an image or document attachment to a property. Send JSON metadata pointing to an external URL,
+// @Description or a multipart form with a "file" field to upload it to storage; uploads return the created attachment.
 // @Tags imoveis
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security BearerAuth
 // @Param id path uint true "Property ID"
-// @Param request body Anexo true "Attachment data"
-// @Success 201 {object} map[string]interface{}
+// @Param request body Anexo false "Attachment data (JSON)"
+// @Param file formData file false "File to upload (multipart)"
+// @Param nome formData string false "Attachment name, defaults to the file name"
+// @Param canPublish formData bool false "Whether the attachment may be published"
+// @Success 201 {object} errors.Response{success=bool,data=AnexoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 413 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/imoveis/{id}/anexos [post]
 func (h *Handler) AddAnexo(c *gin.Context) {
 	var uriReq struct {
@@ -234,6 +1370,11 @@ func (h *Handler) AddAnexo(c *gin.Context) {
 		return
 	}
 
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		h.uploadAnexo(c, uriReq.ID)
+		return
+	}
+
 	var anexo Anexo
 	if err := c.ShouldBindJSON(&anexo); err != nil {
 		_ = c.Error(apiErrors.FromGinValidation(err))
@@ -241,13 +1382,56 @@ func (h *Handler) AddAnexo(c *gin.Context) {
 	}
 
 	if err := h.service.AddAnexo(c.Request.Context(), uriReq.ID, &anexo); err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		h.handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Attachment added"})
 }
 
+// uploadAnexo handles the multipart variant of AddAnexo
+func (h *Handler) uploadAnexo(c *gin.Context, imovelID uint) {
+	maxBytes := h.uploadService.MaxUploadBytes()
+	// Leave room for the other form fields and multipart boundaries
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			_ = c.Error(apiErrors.PayloadTooLarge(ErrFileTooLarge.Error()))
+			return
+		}
+		_ = c.Error(apiErrors.BadRequest("multipart field 'file' is required"))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	nome := c.PostForm("nome")
+	if nome == "" {
+		nome = header.Filename
+	}
+
+	anexo, err := h.uploadService.UploadAnexo(c.Request.Context(), imovelID, &UploadedFile{
+		Nome:       nome,
+		Size:       header.Size,
+		Content:    file,
+		CanPublish: c.PostForm("canPublish") == "true",
+	})
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(anexo))
+}
+
 // @Summary Get property attachments
 // @Description Get all attachments for a property
 // @Tags imoveis
@@ -276,6 +1460,80 @@ func (h *Handler) GetAnexos(c *gin.Context) {
 	c.JSON(http.StatusOK, apiErrors.Success(anexos))
 }
 
+// @Summary Get property price history
+// @Description Sale and rental price changes of a property, oldest first
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Property ID"
+// @Param tipo query string false "Price type (VENDA, ALUGUEL)"
+// @Success 200 {object} errors.Response{success=bool,data=[]HistoricoPrecoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/historico-precos [get]
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query PriceHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	historico, err := h.service.GetPriceHistory(c.Request.Context(), req.ID, &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(historico))
+}
+
+// @Summary Simulate property financing
+// @Description SAC and PRICE amortization tables for financing the active selling price minus the down payment, with the monthly income required by the first installment
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Param id path uint true "Property ID"
+// @Param entrada query number false "Down payment (defaults to 20% of the price)"
+// @Param prazo query int false "Term in months (12-420)" default(360)
+// @Param taxa query number true "Effective annual interest rate in %"
+// @Success 200 {object} errors.Response{success=bool,data=SimulacaoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/simulacao [get]
+func (h *Handler) SimulateFinancing(c *gin.Context) {
+	var req struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var query SimulacaoQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	simulacao, err := h.service.SimulateFinancing(c.Request.Context(), req.ID, &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(simulacao))
+}
+
 // @Summary Add characteristics to property
 // @Description Add multiple characteristics to a property
 // @Tags imoveis
@@ -285,6 +1543,7 @@ func (h *Handler) GetAnexos(c *gin.Context) {
 // @Param id path uint true "Property ID"
 // @Param request body map[string][]uint true "Characteristics IDs"
 // @Success 201 {object} map[string]interface{}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/imoveis/{id}/caracteristicas [post]
 func (h *Handler) AddCaracteristicas(c *gin.Context) {
@@ -307,13 +1566,94 @@ func (h *Handler) AddCaracteristicas(c *gin.Context) {
 	}
 
 	if err := h.service.AddCaracteristicas(c.Request.Context(), uriReq.ID, req.Caracteristicas); err != nil {
-		_ = c.Error(apiErrors.InternalServerError(err))
+		h.handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Characteristics added"})
 }
 
+// @Summary Remove characteristics from property
+// @Description Remove the given characteristics from a property; IDs not linked to it are ignored
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param request body CaracteristicasRequest true "Characteristics IDs"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/caracteristicas [delete]
+func (h *Handler) RemoveCaracteristicas(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req CaracteristicasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.RemoveCaracteristicas(c.Request.Context(), uriReq.ID, req.Caracteristicas); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Replace property characteristics
+// @Description Replace the full set of characteristics of a property; an empty list removes them all
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Property ID"
+// @Param request body ReplaceCaracteristicasRequest true "Characteristics IDs"
+// @Success 200 {object} errors.Response{success=bool,data=[]CaracteristicaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/caracteristicas [put]
+func (h *Handler) ReplaceCaracteristicas(c *gin.Context) {
+	var uriReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req ReplaceCaracteristicasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.ReplaceCaracteristicas(ctx, uriReq.ID, req.Caracteristicas); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	caracteristicas, err := h.service.GetCaracteristicas(ctx, uriReq.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(caracteristicas))
+}
+
 // @Summary Get property characteristics
 // @Description Get all characteristics for a property
 // @Tags imoveis
@@ -341,3 +1681,54 @@ func (h *Handler) GetCaracteristicas(c *gin.Context) {
 
 	c.JSON(http.StatusOK, apiErrors.Success(caracteristicas))
 }
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrImovelNotFound):
+		_ = c.Error(apiErrors.NotFound("Property not found"))
+	case errors.Is(err, ErrCodigoExists):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	case errors.Is(err, ErrInvalidImovel):
+		_ = c.Error(apiErrors.BadRequest(err.Error()))
+	case errors.Is(err, ErrInvalidTransition):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	case errors.Is(err, ErrForbidden):
+		_ = c.Error(apiErrors.Forbidden("Property belongs to another corretor"))
+	case errors.Is(err, ErrVersionConflict):
+		_ = c.Error(apiErrors.Conflict("Property was modified by another request; reload it and try again"))
+	case errors.Is(err, ErrFileTooLarge):
+		_ = c.Error(apiErrors.PayloadTooLarge(err.Error()))
+	case errors.Is(err, ErrUnsupportedFileType):
+		_ = c.Error(apiErrors.BadRequest(err.Error()))
+	case errors.Is(err, ErrInvalidCursor):
+		_ = c.Error(apiErrors.BadRequest(err.Error()))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
+
+// bindVersion fills version from the If-Match header when the body did not carry one. It accepts
+// 3, "3", W/"3" and the ETag of GET (W/"3-<hash>"), and reports false after writing a validation
+// error for anything else.
+func bindVersion(c *gin.Context, version **uint) bool {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if *version != nil || header == "" {
+		return true
+	}
+
+	tag, _, _ := strings.Cut(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), "-")
+	value, err := strconv.ParseUint(tag, 10, 0)
+	if err != nil || value == 0 {
+		_ = c.Error(apiErrors.ValidationError(map[string]string{"If-Match": "must be the version of the property"}))
+		return false
+	}
+	parsed := uint(value)
+	*version = &parsed
+	return true
+}
+
+// newListResponse wraps a page of properties in the list envelope
+func newListResponse(results []ImovelResponse, total int64, page, limit int) *ImovelListResponse {
+	return pagination.New(results, total, page, limit)
+}
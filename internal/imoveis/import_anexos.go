@@ -0,0 +1,156 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+)
+
+// anexoOwner is the record a set of imported anexos belongs to, with how anexos are added to and
+// removed from it
+type anexoOwner struct {
+	name   string
+	owner  AnexoOwner
+	id     uint
+	add    func(ctx context.Context, anexo *Anexo) error
+	remove func(ctx context.Context, anexo *Anexo) error
+}
+
+// syncAnexosFromImages makes the imported anexos of a property match the images of its listing.
+// Additions and removals are audited.
+func (is *importService) syncAnexosFromImages(ctx context.Context, imovelID uint, images []importImage) error {
+	return is.syncImportedAnexos(ctx, anexoOwner{
+		name:  "property",
+		owner: AnexoOwnerImovel,
+		id:    imovelID,
+		add: func(ctx context.Context, anexo *Anexo) error {
+			return is.service.AddAnexo(ctx, imovelID, anexo)
+		},
+		remove: func(ctx context.Context, anexo *Anexo) error {
+			return is.service.RemoveAnexo(ctx, imovelID, anexo.ID)
+		},
+	}, images)
+}
+
+// syncImportedAnexos makes the imported anexos of owner match images, keeping the IDs other
+// records point to: anexos of images still listed are moved to their position in the listing,
+// new images are added and the anexos of images gone from the listing are removed. Images are
+// matched by external URL, then by content hash; anexos uploaded by hand are left alone.
+func (is *importService) syncImportedAnexos(ctx context.Context, owner anexoOwner, images []importImage) error {
+	existing, err := is.repo.ListImportedAnexos(ctx, owner.owner, owner.id)
+	if err != nil {
+		return fmt.Errorf("failed to read existing anexos: %w", err)
+	}
+
+	byURL := map[string][]int{}
+	byHash := map[string][]int{}
+	for i, anexo := range existing {
+		byURL[importedURL(&anexo)] = append(byURL[importedURL(&anexo)], i)
+		if anexo.ContentHash != "" {
+			byHash[anexo.ContentHash] = append(byHash[anexo.ContentHash], i)
+		}
+	}
+	matched := make([]bool, len(existing))
+	take := func(candidates []int) *Anexo {
+		for _, i := range candidates {
+			if !matched[i] {
+				matched[i] = true
+				return &existing[i]
+			}
+		}
+		return nil
+	}
+
+	added, removed := 0, 0
+	for i, image := range images {
+		ordem := i + 1
+		anexo := take(byURL[image.externalURL])
+		if anexo == nil && image.hash != "" {
+			anexo = take(byHash[image.hash])
+		}
+
+		if anexo != nil {
+			if err := is.updateImportedAnexo(ctx, anexo, image, ordem); err != nil {
+				return fmt.Errorf("failed to update image %d: %w", ordem, err)
+			}
+			continue
+		}
+		if err := owner.add(ctx, importedAnexo(image, ordem)); err != nil {
+			return fmt.Errorf("failed to add image %d: %w", ordem, err)
+		}
+		added++
+	}
+
+	for i := range existing {
+		if matched[i] {
+			continue
+		}
+		if err := owner.remove(ctx, &existing[i]); err != nil {
+			return fmt.Errorf("failed to remove image %s: %w", importedURL(&existing[i]), err)
+		}
+		removed++
+	}
+
+	if added > 0 || removed > 0 {
+		importLogger(ctx).Info("Synced imported anexos", "owner", owner.name, "owner_id", owner.id, "added", added, "removed", removed)
+	}
+	return nil
+}
+
+// importedURL is the URL at the source of an imported anexo; anexos imported before the URL was
+// recorded apart only have it as their URL
+func importedURL(anexo *Anexo) string {
+	if anexo.ExternalURL != "" {
+		return anexo.ExternalURL
+	}
+	return anexo.URL
+}
+
+// importedAnexo builds the anexo of a new image found at position ordem of the listing
+func importedAnexo(image importImage, ordem int) *Anexo {
+	anexo := &Anexo{
+		Nome:          fmt.Sprintf("Image %d", ordem),
+		URL:           image.externalURL,
+		Tipo:          "image",
+		Image:         true,
+		Video:         false,
+		IsExternalURL: true,
+		ExternalURL:   image.externalURL,
+		Ordem:         ordem,
+		CanPublish:    true,
+	}
+	// Downloaded images are served from the storage; the external URL stays as their origin
+	if image.stored() {
+		anexo.URL = image.url
+		anexo.Path = image.path
+		anexo.Tipo = image.contentType
+		anexo.Tamanho = image.size
+		anexo.IsExternalURL = false
+		anexo.ContentHash = image.hash
+	}
+	return anexo
+}
+
+// updateImportedAnexo moves an anexo to position ordem and points it at the stored copy of its
+// image, writing only what changed. An anexo already stored is kept when the image was not
+// downloaded this time.
+func (is *importService) updateImportedAnexo(ctx context.Context, anexo *Anexo, image importImage, ordem int) error {
+	updates := map[string]interface{}{}
+	if anexo.Ordem != ordem {
+		updates["ordem"] = ordem
+	}
+	if anexo.ExternalURL != image.externalURL {
+		updates["external_url"] = image.externalURL
+	}
+	if image.stored() && anexo.Path != image.path {
+		updates["url"] = image.url
+		updates["path"] = image.path
+		updates["tipo"] = image.contentType
+		updates["tamanho"] = image.size
+		updates["is_external_url"] = false
+		updates["content_hash"] = image.hash
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return is.repo.PatchAnexo(ctx, anexo.ID, updates)
+}
@@ -0,0 +1,80 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPublishedProperties_SyncsAnexosInPlace(t *testing.T) {
+	ctx := context.Background()
+	listing := externalListing(1)
+	listing.Imagens = []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg", "https://cdn.example.com/3.jpg"}
+	api := &fakeExternalAPI{listings: []ExternalImovel{listing}}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	imovel := findImported(t, database, "1")
+	before := map[string]uint{}
+	for _, anexo := range importedAnexos(t, importer, "1") {
+		before[anexo.URL] = anexo.ID
+	}
+	require.Len(t, before, 3)
+	uploaded := &Anexo{Nome: "planta.pdf", URL: "/uploads/planta.pdf", Path: "planta.pdf", Tipo: "application/pdf"}
+	require.NoError(t, importer.service.AddAnexo(ctx, imovel.ID, uploaded))
+
+	api.listings[0].Imagens = []string{"https://cdn.example.com/3.jpg", "https://cdn.example.com/1.jpg", "https://cdn.example.com/4.jpg"}
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "0 created, 1 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	resp, err := importer.service.GetImovel(ctx, imovel.ID)
+	require.NoError(t, err)
+	require.Len(t, resp.Anexos, 4)
+	assert.Equal(t, "https://cdn.example.com/3.jpg", resp.Anexos[0].URL)
+	assert.Equal(t, before["https://cdn.example.com/3.jpg"], resp.Anexos[0].ID)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", resp.Anexos[1].URL)
+	assert.Equal(t, before["https://cdn.example.com/1.jpg"], resp.Anexos[1].ID)
+	assert.Equal(t, "https://cdn.example.com/4.jpg", resp.Anexos[2].URL)
+	assert.Greater(t, resp.Anexos[2].ID, uploaded.ID)
+	// Anexos uploaded by hand are not touched by the import
+	assert.Equal(t, uploaded.ID, resp.Anexos[3].ID)
+
+	var removed Anexo
+	require.NoError(t, database.Unscoped().First(&removed, before["https://cdn.example.com/2.jpg"]).Error)
+	assert.True(t, removed.DeletedAt.Valid)
+	var audits int64
+	require.NoError(t, database.Model(&ImovelAudit{}).
+		Where("imovel_id = ? AND entidade = ? AND acao = ?", imovel.ID, AuditEntidadeAnexo, AuditAcaoDelete).
+		Count(&audits).Error)
+	assert.Equal(t, int64(1), audits)
+}
+
+func TestSyncAnexosFromImages_LegacyAnexos(t *testing.T) {
+	ctx := context.Background()
+	listing := externalListing(1)
+	importer, database := setupImportService(t, &fakeExternalAPI{listings: []ExternalImovel{listing}}, false)
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	imovel := findImported(t, database, "1")
+
+	// Imported before the external URL was recorded apart
+	legacy := &Anexo{Nome: "Image 1", URL: "https://cdn.example.com/1.jpg", Tipo: "image", Image: true, IsExternalURL: true}
+	require.NoError(t, importer.service.AddAnexo(ctx, imovel.ID, legacy))
+
+	images := []importImage{{externalURL: "https://cdn.example.com/1.jpg"}}
+	require.NoError(t, importer.syncAnexosFromImages(ctx, imovel.ID, images))
+
+	var anexos []Anexo
+	require.NoError(t, database.Where("imovel_id = ?", imovel.ID).Find(&anexos).Error)
+	require.Len(t, anexos, 1)
+	assert.Equal(t, legacy.ID, anexos[0].ID)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", anexos[0].ExternalURL)
+	assert.Equal(t, 1, anexos[0].Ordem)
+}
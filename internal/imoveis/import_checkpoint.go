@@ -0,0 +1,99 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// importCheckpointInterval is how many listings go through between the checkpoints saved while a
+// run goes on; the end of the run always saves the last one
+const importCheckpointInterval = 25
+
+// ErrImportRunNotResumable is returned when resuming an import run that completed
+var ErrImportRunNotResumable = errors.New("import run completed, nothing to resume")
+
+// resumePoint is where a resumed run picks up the work of an interrupted one
+type resumePoint struct {
+	// originID and startedAt are of the first run of the chain, when the sync the resumed runs
+	// finish began
+	originID  uint
+	startedAt time.Time
+	// checkpoint listings of the list, up to the one with externalID, went through
+	checkpoint int
+	externalID uint
+}
+
+// prepareResume loads the run opts.Resume names and sets opts to its mode and filters, so the
+// resumed run lists the same properties
+func (is *importService) prepareResume(ctx context.Context, opts *ImportOptions) (*resumePoint, error) {
+	prev, err := is.repo.FindImportRun(ctx, opts.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import run: %w", err)
+	}
+	if prev == nil || prev.Source != is.integrationSource {
+		return nil, ErrImportRunNotFound
+	}
+	if prev.Status == ImportRunCompleted {
+		return nil, ErrImportRunNotResumable
+	}
+
+	origin := prev
+	if prev.ResumedFrom != nil {
+		if origin, err = is.repo.FindImportRun(ctx, *prev.ResumedFrom); err != nil {
+			return nil, fmt.Errorf("failed to find import run: %w", err)
+		}
+		if origin == nil {
+			origin = prev
+		}
+	}
+
+	opts.Full = prev.FullSync
+	opts.ImportFilters = ImportFilters{}
+	if prev.Filters != nil {
+		opts.ImportFilters = *prev.Filters
+	}
+	return &resumePoint{
+		originID:   origin.ID,
+		startedAt:  origin.StartedAt,
+		checkpoint: prev.Checkpoint,
+		externalID: prev.CheckpointExternalID,
+	}, nil
+}
+
+// skip returns how many listings from the start of listings the interrupted run went through. The
+// source may have changed the list since, so the checkpoint listing is looked up by its ID; when
+// it is gone the run starts over.
+func (p *resumePoint) skip(listings []ExternalImovel) int {
+	if p == nil || p.checkpoint == 0 {
+		return 0
+	}
+	if p.checkpoint <= len(listings) && listings[p.checkpoint-1].ID == p.externalID {
+		return p.checkpoint
+	}
+	for i := range listings {
+		if listings[i].ID == p.externalID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// saveCheckpoint moves the checkpoint of run to the listing with externalID, at position
+// checkpoint of the list, storing it every importCheckpointInterval listings. Saving is best
+// effort: a run that cannot save it is resumed from an earlier one.
+func (is *importService) saveCheckpoint(ctx context.Context, run *ImportRun, checkpoint int, externalID uint) {
+	if run == nil {
+		return
+	}
+
+	due := checkpoint/importCheckpointInterval > run.Checkpoint/importCheckpointInterval
+	run.Checkpoint, run.CheckpointExternalID = checkpoint, externalID
+	if !due {
+		return
+	}
+	if err := is.repo.SaveImportRunCheckpoint(context.WithoutCancel(ctx), run.ID, checkpoint, externalID); err != nil {
+		importLogger(ctx).Warn("Failed to record import checkpoint", "checkpoint", checkpoint, "error", err)
+	}
+}
@@ -0,0 +1,104 @@
+package imoveis
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+
+	var run ImportRun
+	require.NoError(t, database.First(&run, result.RunID).Error)
+	assert.Equal(t, 3, run.Checkpoint)
+	assert.Equal(t, uint(3), run.CheckpointExternalID)
+	assert.Nil(t, run.ResumedFrom)
+
+	t.Run("completed run", func(t *testing.T) {
+		_, err := importer.ImportPublishedProperties(ctx, ImportOptions{Resume: run.ID})
+		assert.ErrorIs(t, err, ErrImportRunNotResumable)
+		_, err = importer.StartImport(ctx, ImportOptions{Resume: run.ID}, 0)
+		assert.ErrorIs(t, err, ErrImportRunNotResumable)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		_, err := importer.StartImport(ctx, ImportOptions{Resume: 999}, 0)
+		assert.ErrorIs(t, err, ErrImportRunNotFound)
+	})
+}
+
+func TestImportPublishedProperties_Resume(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{}
+	for id := uint(1); id <= 5; id++ {
+		api.listings = append(api.listings, externalListing(id))
+	}
+	importer, database := setupImportService(t, api, false)
+
+	// A run that crashed after importing 1 and 2 in order, with 4 done ahead by another worker
+	crashed := &ImportRun{
+		Source:               "pi8",
+		Status:               ImportRunRunning,
+		FullSync:             true,
+		StartedAt:            time.Now().UTC().Add(-time.Minute),
+		Checkpoint:           2,
+		CheckpointExternalID: 2,
+	}
+	require.NoError(t, database.Create(crashed).Error)
+	for _, i := range []int{0, 1, 3} {
+		_, err := importer.importListing(ctx, &api.listings[i])
+		require.NoError(t, err)
+	}
+	api.reset()
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{Resume: crashed.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Unchanged)
+
+	// Only the listings past the checkpoint and not imported yet are fetched
+	requested := append([]uint(nil), api.detailRequests...)
+	sort.Slice(requested, func(i, j int) bool { return requested[i] < requested[j] })
+	assert.Equal(t, []uint{3, 5}, requested)
+
+	var resumed ImportRun
+	require.NoError(t, database.First(&resumed, result.RunID).Error)
+	assert.Equal(t, ImportRunCompleted, resumed.Status)
+	assert.True(t, resumed.FullSync)
+	require.NotNil(t, resumed.ResumedFrom)
+	assert.Equal(t, crashed.ID, *resumed.ResumedFrom)
+	assert.Equal(t, 5, resumed.Checkpoint)
+	assert.Equal(t, uint(5), resumed.CheckpointExternalID)
+}
+
+func TestResumePointSkip(t *testing.T) {
+	listings := []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}
+
+	tests := []struct {
+		name  string
+		point *resumePoint
+		want  int
+	}{
+		{"no resume", nil, 0},
+		{"no checkpoint", &resumePoint{}, 0},
+		{"same list", &resumePoint{checkpoint: 2, externalID: 2}, 2},
+		{"listing moved", &resumePoint{checkpoint: 1, externalID: 2}, 2},
+		{"listing gone", &resumePoint{checkpoint: 2, externalID: 9}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.point.skip(listings))
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+// Conflict policies: what the importer does with the fields of a property edited by hand
+const (
+	ConflictPolicyLocalWins  = "local_wins"  // keep the local value (default)
+	ConflictPolicyRemoteWins = "remote_wins" // overwrite it with the value of the source
+	ConflictPolicyReview     = "review"      // keep the local value and hold the one of the source for review
+)
+
+// Resolutions of an import conflict
+const (
+	ConflictResolucaoLocal  = "LOCAL"
+	ConflictResolucaoRemote = "REMOTE"
+)
+
+var (
+	// ErrImportConflictNotFound is returned when an import conflict does not exist
+	ErrImportConflictNotFound = errors.New("import conflict not found")
+	// ErrImportConflictResolved is returned when resolving a conflict already resolved
+	ErrImportConflictResolved = errors.New("import conflict already resolved")
+)
+
+// lockableField is a field of a property the importer writes, and so one a manual edit locks
+type lockableField struct {
+	name   string // JSON name, the same in ImovelResponse, UpdateImovelRequest and PatchImovelRequest
+	column string
+}
+
+var lockableFields = []lockableField{
+	{"titulo", "titulo"},
+	{"tipo", "tipo"},
+	{"objetivo", "objetivo"},
+	{"finalidade", "finalidade"},
+	{"descricao", "descricao"},
+	{"metragem", "metragem"},
+	{"numQuartos", "num_quartos"},
+	{"numSuites", "num_suites"},
+	{"numBanheiros", "num_banheiros"},
+	{"numVagas", "num_vagas"},
+	{"numAndar", "num_andar"},
+	{"unidade", "unidade"},
+	{"condominio", "condominio"},
+}
+
+// isLockableField reports whether name is the JSON name of a lockable field
+func isLockableField(name string) bool {
+	for _, field := range lockableFields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LockedFields lists the JSON names of the fields of a property edited by hand, stored as JSONB
+type LockedFields []string
+
+// Value implements driver.Valuer
+func (l LockedFields) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l *LockedFields) Scan(value interface{}) error {
+	var data []byte
+	switch src := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported type for locked fields: %T", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Has reports whether the field is locked
+func (l LockedFields) Has(name string) bool {
+	for _, locked := range l {
+		if locked == name {
+			return true
+		}
+	}
+	return false
+}
+
+// with returns the locked fields plus names, without duplicates
+func (l LockedFields) with(names ...string) LockedFields {
+	locked := append(LockedFields{}, l...)
+	for _, name := range names {
+		if !locked.Has(name) {
+			locked = append(locked, name)
+		}
+	}
+	return locked
+}
+
+// without returns the locked fields minus name
+func (l LockedFields) without(name string) LockedFields {
+	locked := LockedFields{}
+	for _, field := range l {
+		if field != name {
+			locked = append(locked, field)
+		}
+	}
+	return locked
+}
+
+// jsonFields returns the JSON encoded fields of v, a struct
+func jsonFields(v interface{}) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	// Plain structs of this package always encode into an object
+	data, _ := json.Marshal(v)
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// isEmptyJSON reports whether value is null or an empty string
+func isEmptyJSON(value json.RawMessage) bool {
+	return bytes.Equal(value, []byte("null")) || bytes.Equal(value, []byte(`""`))
+}
+
+// editedFields returns the lockable fields whose value differs between before and after
+func editedFields(before, after *ImovelResponse) []string {
+	previous, current := jsonFields(before), jsonFields(after)
+	var edited []string
+	for _, field := range lockableFields {
+		if !bytes.Equal(previous[field.name], current[field.name]) {
+			edited = append(edited, field.name)
+		}
+	}
+	return edited
+}
+
+// patchedFields returns the lockable fields whose value the column updates of a patch change
+func patchedFields(before *ImovelResponse, updates map[string]interface{}) []string {
+	previous := jsonFields(before)
+	var edited []string
+	for _, field := range lockableFields {
+		value, ok := updates[field.column]
+		if !ok {
+			continue
+		}
+		if data, err := json.Marshal(value); err != nil || !bytes.Equal(previous[field.name], data) {
+			edited = append(edited, field.name)
+		}
+	}
+	return edited
+}
+
+// applyConflictPolicy leaves out of req the fields of existing locked by a manual edit, unless the
+// policy lets the source win. Under the review policy the values of the source left out are held
+// as conflicts. Fields whose value agrees with the source are no conflict.
+func (is *importService) applyConflictPolicy(ctx context.Context, existing *ImovelResponse, req *UpdateImovelRequest) (*UpdateImovelRequest, error) {
+	if is.conflictPolicy == ConflictPolicyRemoteWins || len(existing.LockedFields) == 0 {
+		return req, nil
+	}
+
+	local, remote := jsonFields(existing), jsonFields(req)
+	kept := false
+	for _, name := range existing.LockedFields {
+		value, ok := remote[name]
+		// UpdateImovel skips empty values, so they write nothing to conflict with
+		if !ok || isEmptyJSON(value) || bytes.Equal(local[name], value) {
+			continue
+		}
+		delete(remote, name)
+		kept = true
+
+		if is.conflictPolicy != ConflictPolicyReview {
+			continue
+		}
+		if err := is.holdConflict(ctx, existing.ID, name, string(local[name]), string(value)); err != nil {
+			return nil, err
+		}
+	}
+	if !kept {
+		return req, nil
+	}
+
+	data, err := json.Marshal(remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to leave out locked fields: %w", err)
+	}
+	filtered := &UpdateImovelRequest{}
+	if err := json.Unmarshal(data, filtered); err != nil {
+		return nil, fmt.Errorf("failed to leave out locked fields: %w", err)
+	}
+	return filtered, nil
+}
+
+// holdConflict records the value of the source for a locked field, refreshing the pending conflict
+// of the field if there is one. A value already turned down in favour of the local one is not
+// held again.
+func (is *importService) holdConflict(ctx context.Context, imovelID uint, campo, valorLocal, valorRemoto string) error {
+	conflict, err := is.repo.FindLatestImportConflict(ctx, imovelID, campo)
+	if err != nil {
+		return fmt.Errorf("failed to find import conflict: %w", err)
+	}
+	if conflict != nil && conflict.Resolucao == ConflictResolucaoLocal && conflict.ValorRemoto == valorRemoto {
+		return nil
+	}
+	if conflict == nil || conflict.ResolvidoEm != nil {
+		conflict = &ImportConflict{ImovelID: imovelID, Campo: campo}
+	}
+	conflict.ValorLocal = valorLocal
+	conflict.ValorRemoto = valorRemoto
+	if err := is.repo.SaveImportConflict(ctx, conflict); err != nil {
+		return fmt.Errorf("failed to record import conflict: %w", err)
+	}
+	importLogger(ctx).Info("Held import conflict for review", "imovel_id", imovelID, "campo", campo)
+	return nil
+}
+
+// ListImportConflicts returns the import conflicts, oldest first
+func (is *importService) ListImportConflicts(ctx context.Context, query *ImportConflictListQuery) (*ImportConflictListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+	if query.Status == "" {
+		query.Status = ImportConflictStatusPending
+	}
+
+	conflicts, total, err := is.repo.ListImportConflicts(ctx, query.Status, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import conflicts: %w", err)
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &ImportConflictListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: conflicts,
+	}, nil
+}
+
+// ResolveImportConflict keeps the local value of a conflict, or writes the one of the source and
+// unlocks the field so later imports keep it up to date
+func (is *importService) ResolveImportConflict(ctx context.Context, id uint, req *ResolveImportConflictRequest) (*ImportConflict, error) {
+	conflict, err := is.repo.FindImportConflict(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import conflict: %w", err)
+	}
+	if conflict == nil {
+		return nil, ErrImportConflictNotFound
+	}
+	if conflict.ResolvidoEm != nil {
+		return nil, ErrImportConflictResolved
+	}
+
+	err = is.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if req.Resolucao == ConflictResolucaoRemote {
+			if err := is.acceptRemote(txCtx, conflict); err != nil {
+				return err
+			}
+		}
+
+		resolvidoEm := time.Now().UTC()
+		conflict.Resolucao = req.Resolucao
+		conflict.ResolvidoEm = &resolvidoEm
+		if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+			conflict.ResolvidoPor = &userID
+		}
+		if err := is.repo.SaveImportConflict(txCtx, conflict); err != nil {
+			return fmt.Errorf("failed to resolve import conflict: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+// acceptRemote unlocks the field of a conflict and writes the value of the source
+func (is *importService) acceptRemote(ctx context.Context, conflict *ImportConflict) error {
+	update := &UpdateImovelRequest{}
+	data, err := json.Marshal(map[string]json.RawMessage{conflict.Campo: json.RawMessage(conflict.ValorRemoto)})
+	if err == nil {
+		err = json.Unmarshal(data, update)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value of the source for %s: %w", conflict.Campo, err)
+	}
+
+	if err := is.repo.UnlockField(ctx, conflict.ImovelID, conflict.Campo); err != nil {
+		return fmt.Errorf("failed to unlock %s: %w", conflict.Campo, err)
+	}
+	// Written as an import, so the field is not locked again
+	if _, err := is.service.UpdateImovel(withPriceOrigin(ctx, PriceOriginImport), conflict.ImovelID, update); err != nil {
+		return fmt.Errorf("failed to write the value of the source: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProperty_LockedFields(t *testing.T) {
+	ctx := context.Background()
+
+	// setup imports listing 1 and edits its titulo and numQuartos by hand
+	setup := func(t *testing.T, policy string) (*importService, *fakeExternalAPI, uint) {
+		api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+		importer, database := setupImportService(t, api, false)
+		importer.conflictPolicy = policy
+		_, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+
+		id := findImported(t, database, "1").ID
+		quartos := 3
+		edited, err := importer.service.UpdateImovel(ctx, id, &UpdateImovelRequest{Titulo: "Apartamento com vista", NumQuartos: &quartos})
+		require.NoError(t, err)
+		assert.Equal(t, LockedFields{"titulo", "numQuartos"}, edited.LockedFields)
+
+		api.listings[0].Titulo = "Apartamento reformado"
+		api.listings[0].NumQuartos = 2
+		api.listings[0].Metragem = 80
+		return importer, api, id
+	}
+
+	t.Run("local wins", func(t *testing.T) {
+		importer, _, _ := setup(t, ConflictPolicyLocalWins)
+
+		result, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento com vista", result.Imovel.Titulo)
+		assert.Equal(t, 3, result.Imovel.NumQuartos)
+		assert.Equal(t, 80.0, result.Imovel.Metragem)
+		assert.Equal(t, LockedFields{"titulo", "numQuartos"}, result.Imovel.LockedFields)
+
+		conflicts, err := importer.ListImportConflicts(ctx, &ImportConflictListQuery{})
+		require.NoError(t, err)
+		assert.Zero(t, conflicts.Total)
+	})
+
+	t.Run("remote wins", func(t *testing.T) {
+		importer, _, _ := setup(t, ConflictPolicyRemoteWins)
+
+		result, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento reformado", result.Imovel.Titulo)
+		assert.Equal(t, 2, result.Imovel.NumQuartos)
+		// The import does not lift the locks, so switching back to local_wins protects them again
+		assert.Equal(t, LockedFields{"titulo", "numQuartos"}, result.Imovel.LockedFields)
+	})
+
+	t.Run("review", func(t *testing.T) {
+		importer, _, id := setup(t, ConflictPolicyReview)
+
+		result, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento com vista", result.Imovel.Titulo)
+		assert.Equal(t, 80.0, result.Imovel.Metragem)
+
+		// Importing again refreshes the pending conflicts instead of adding more
+		_, err = importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		conflicts, err := importer.ListImportConflicts(ctx, &ImportConflictListQuery{})
+		require.NoError(t, err)
+		require.Len(t, conflicts.Results, 2)
+		titulo, quartos := conflicts.Results[0], conflicts.Results[1]
+		assert.Equal(t, id, titulo.ImovelID)
+		assert.Equal(t, "titulo", titulo.Campo)
+		assert.Equal(t, `"Apartamento com vista"`, titulo.ValorLocal)
+		assert.Equal(t, `"Apartamento reformado"`, titulo.ValorRemoto)
+		assert.Equal(t, "numQuartos", quartos.Campo)
+		assert.Equal(t, "2", quartos.ValorRemoto)
+
+		resolved, err := importer.ResolveImportConflict(ctx, titulo.ID, &ResolveImportConflictRequest{Resolucao: ConflictResolucaoRemote})
+		require.NoError(t, err)
+		assert.Equal(t, ConflictResolucaoRemote, resolved.Resolucao)
+		assert.NotNil(t, resolved.ResolvidoEm)
+		imovel, err := importer.service.GetImovel(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento reformado", imovel.Titulo)
+		assert.Equal(t, LockedFields{"numQuartos"}, imovel.LockedFields)
+
+		_, err = importer.ResolveImportConflict(ctx, quartos.ID, &ResolveImportConflictRequest{Resolucao: ConflictResolucaoLocal})
+		require.NoError(t, err)
+		_, err = importer.ResolveImportConflict(ctx, quartos.ID, &ResolveImportConflictRequest{Resolucao: ConflictResolucaoRemote})
+		assert.ErrorIs(t, err, ErrImportConflictResolved)
+
+		// A value of the source turned down is not held again
+		_, err = importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		conflicts, err = importer.ListImportConflicts(ctx, &ImportConflictListQuery{})
+		require.NoError(t, err)
+		assert.Zero(t, conflicts.Total)
+		imovel, err = importer.service.GetImovel(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 3, imovel.NumQuartos)
+	})
+
+	t.Run("patch replaces the locks", func(t *testing.T) {
+		importer, _, id := setup(t, ConflictPolicyLocalWins)
+
+		_, err := importer.service.PatchImovel(ctx, id, &PatchImovelRequest{LockedFields: Nullable[[]string]{Set: true, Null: true}})
+		require.NoError(t, err)
+		result, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento reformado", result.Imovel.Titulo)
+		assert.Empty(t, result.Imovel.LockedFields)
+
+		patched, err := importer.service.PatchImovel(ctx, id, &PatchImovelRequest{Unidade: Nullable[string]{Set: true, Value: "12B"}})
+		require.NoError(t, err)
+		assert.Equal(t, LockedFields{"unidade"}, patched.LockedFields)
+	})
+}
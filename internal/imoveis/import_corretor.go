@@ -0,0 +1,61 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+)
+
+// syncCorretorFoto links the photo of the source to a corretor as an anexo, downloaded to the
+// storage when enabled. An imported photo is updated in place when the photo at the source
+// changes; a photo uploaded by hand is replaced by the imported one but not deleted. Without a
+// photo at the source the current one is kept.
+func (is *importService) syncCorretorFoto(ctx context.Context, corretor *CorretorPrincipal, foto *ExternalFoto, images listingImages) error {
+	if foto == nil || foto.URL == "" {
+		return nil
+	}
+	image := images.of([]string{foto.URL})[0]
+
+	if corretor.FotoID != 0 {
+		current, err := is.repo.FindAnexoByID(ctx, corretor.FotoID)
+		if err != nil {
+			return fmt.Errorf("failed to read corretor foto: %w", err)
+		}
+		if current != nil && (current.ExternalURL != "" || current.IsExternalURL) {
+			if importedURL(current) == foto.URL {
+				return is.updateImportedAnexo(ctx, current, image, 0)
+			}
+			replacement := corretorFotoAnexo(corretor, image)
+			if err := is.repo.PatchAnexo(ctx, current.ID, map[string]interface{}{
+				"nome":            replacement.Nome,
+				"url":             replacement.URL,
+				"path":            replacement.Path,
+				"tipo":            replacement.Tipo,
+				"tamanho":         replacement.Tamanho,
+				"is_external_url": replacement.IsExternalURL,
+				"external_url":    replacement.ExternalURL,
+				"content_hash":    replacement.ContentHash,
+			}); err != nil {
+				return fmt.Errorf("failed to update corretor foto: %w", err)
+			}
+			importLogger(ctx).Info("Updated corretor foto", "corretor_id", corretor.ID)
+			return nil
+		}
+	}
+
+	anexo := corretorFotoAnexo(corretor, image)
+	if err := is.repo.CreateAnexo(ctx, anexo); err != nil {
+		return fmt.Errorf("failed to create corretor foto: %w", err)
+	}
+	if err := is.repo.SetCorretorFoto(ctx, corretor.ID, anexo.ID); err != nil {
+		return fmt.Errorf("failed to link corretor foto: %w", err)
+	}
+	corretor.FotoID = anexo.ID
+	return nil
+}
+
+// corretorFotoAnexo builds the anexo of the photo of a corretor; it belongs to no property
+func corretorFotoAnexo(corretor *CorretorPrincipal, image importImage) *Anexo {
+	anexo := importedAnexo(image, 0)
+	anexo.Nome = fmt.Sprintf("Foto %s", corretor.Nome)
+	return anexo
+}
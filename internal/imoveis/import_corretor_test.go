@@ -0,0 +1,70 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCorretorFoto(t *testing.T) {
+	ctx := context.Background()
+	importer, database := setupImportService(t, &fakeExternalAPI{}, false)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, nome) VALUES (1, 'Ana')`).Error)
+	corretor := &CorretorPrincipal{ID: 1, Nome: "Ana"}
+	foto := func(url string) *ExternalFoto { return &ExternalFoto{URL: url, Tipo: "image/jpeg"} }
+	linkedFoto := func() Anexo {
+		var fotoID uint
+		require.NoError(t, database.Raw(`SELECT foto_id FROM corretores_principais WHERE id = 1`).Scan(&fotoID).Error)
+		var anexo Anexo
+		require.NoError(t, database.First(&anexo, fotoID).Error)
+		return anexo
+	}
+
+	require.NoError(t, importer.syncCorretorFoto(ctx, corretor, foto("https://cdn.example.com/ana.jpg"), listingImages{}))
+	first := linkedFoto()
+	assert.Equal(t, corretor.FotoID, first.ID)
+	assert.Equal(t, "Foto Ana", first.Nome)
+	assert.Equal(t, "https://cdn.example.com/ana.jpg", first.URL)
+	assert.True(t, first.IsExternalURL)
+	assert.Nil(t, first.ImovelID)
+
+	t.Run("a new photo at the source updates the anexo in place", func(t *testing.T) {
+		require.NoError(t, importer.syncCorretorFoto(ctx, corretor, foto("https://cdn.example.com/ana-2.jpg"), listingImages{}))
+		updated := linkedFoto()
+		assert.Equal(t, first.ID, updated.ID)
+		assert.Equal(t, "https://cdn.example.com/ana-2.jpg", updated.URL)
+		assert.Equal(t, "https://cdn.example.com/ana-2.jpg", updated.ExternalURL)
+	})
+
+	t.Run("a downloaded photo is served from the storage", func(t *testing.T) {
+		images := listingImages{"https://cdn.example.com/ana-2.jpg": {
+			externalURL: "https://cdn.example.com/ana-2.jpg", path: "imoveis/imports/abc.jpg",
+			url: "/uploads/imoveis/imports/abc.jpg", contentType: "image/jpeg", hash: "abc", size: 10,
+		}}
+		require.NoError(t, importer.syncCorretorFoto(ctx, corretor, foto("https://cdn.example.com/ana-2.jpg"), images))
+		stored := linkedFoto()
+		assert.Equal(t, first.ID, stored.ID)
+		assert.Equal(t, "imoveis/imports/abc.jpg", stored.Path)
+		assert.False(t, stored.IsExternalURL)
+	})
+
+	t.Run("no photo at the source keeps the current one", func(t *testing.T) {
+		require.NoError(t, importer.syncCorretorFoto(ctx, corretor, nil, listingImages{}))
+		assert.Equal(t, first.ID, linkedFoto().ID)
+	})
+
+	t.Run("a photo uploaded by hand is replaced but kept", func(t *testing.T) {
+		manual := &Anexo{Nome: "ana.png", URL: "/uploads/ana.png", Path: "ana.png", Tipo: "image/png", Image: true}
+		require.NoError(t, database.Create(manual).Error)
+		require.NoError(t, database.Exec(`UPDATE corretores_principais SET foto_id = ? WHERE id = 1`, manual.ID).Error)
+		corretor.FotoID = manual.ID
+
+		require.NoError(t, importer.syncCorretorFoto(ctx, corretor, foto("https://cdn.example.com/ana-3.jpg"), listingImages{}))
+		replaced := linkedFoto()
+		assert.NotEqual(t, manual.ID, replaced.ID)
+		assert.Equal(t, "https://cdn.example.com/ana-3.jpg", replaced.ExternalURL)
+		require.NoError(t, database.First(&Anexo{}, manual.ID).Error)
+	})
+}
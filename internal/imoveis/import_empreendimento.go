@@ -0,0 +1,246 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// syncEmpreendimentoRelations brings the endereço, torres, plantas and caracteristicas of an
+// imported empreendimento in line with the source. Torres and plantas are matched by their
+// external ID; those added by hand are left alone, as are caracteristicas linked by hand.
+func (is *importService) syncEmpreendimentoRelations(ctx context.Context, empreendimento *Empreendimento, ext *ExternalEmpreendimento, images listingImages) error {
+	if err := is.syncEmpreendimentoEndereco(ctx, empreendimento, &ext.Endereco); err != nil {
+		return err
+	}
+	if err := is.syncTorres(ctx, empreendimento.ID, ext.Torres); err != nil {
+		return err
+	}
+	if err := is.syncPlantas(ctx, empreendimento.ID, ext.Plantas, images); err != nil {
+		return err
+	}
+	return is.syncCaracteristicas(ctx, empreendimento, ext.Caracteristicas)
+}
+
+// syncEmpreendimentoEndereco updates the endereço of an empreendimento in place, or creates it on
+// the first import that carries one. An empty endereço at the source keeps the current one.
+func (is *importService) syncEmpreendimentoEndereco(ctx context.Context, empreendimento *Empreendimento, ext *ExternalEndereco) error {
+	if ext.Rua == "" {
+		return nil
+	}
+
+	if empreendimento.EnderecoID != 0 {
+		updates := map[string]interface{}{
+			"rua":       ext.Rua,
+			"numero":    ext.Numero,
+			"bairro":    ext.Bairro,
+			"cidade":    ext.Cidade,
+			"estado":    ext.Estado,
+			"cep":       ext.CEP,
+			"latitude":  ext.Latitude,
+			"longitude": ext.Longitude,
+		}
+		if err := is.repo.PatchEndereco(ctx, empreendimento.EnderecoID, updates); err != nil {
+			return fmt.Errorf("failed to update empreendimento endereco: %w", err)
+		}
+		return nil
+	}
+
+	enderecoID, err := is.createEndereco(ctx, ext)
+	if err != nil {
+		return fmt.Errorf("failed to create empreendimento endereco: %w", err)
+	}
+	if err := is.repo.SetEmpreendimentoEndereco(ctx, empreendimento.ID, enderecoID); err != nil {
+		return fmt.Errorf("failed to link empreendimento endereco: %w", err)
+	}
+	empreendimento.EnderecoID = enderecoID
+	return nil
+}
+
+// syncTorres upserts the torres of an empreendimento by external ID and deletes the imported
+// torres gone from the source
+func (is *importService) syncTorres(ctx context.Context, empreendimentoID uint, exts []ExternalTorre) error {
+	existing, err := is.repo.ListImportedTorres(ctx, empreendimentoID)
+	if err != nil {
+		return fmt.Errorf("failed to read existing torres: %w", err)
+	}
+	byID := make(map[string]*Torres, len(existing))
+	for i := range existing {
+		byID[existing[i].IdIntegracao] = &existing[i]
+	}
+
+	listed := map[string]bool{}
+	for _, ext := range exts {
+		if ext.ID == 0 {
+			continue
+		}
+		idIntegracao := fmt.Sprintf("%d", ext.ID)
+		listed[idIntegracao] = true
+
+		torre, ok := byID[idIntegracao]
+		if !ok {
+			torre = &Torres{
+				IdIntegracao:     idIntegracao,
+				Nome:             ext.Nome,
+				TotalColunas:     ext.TotalColunas,
+				TotalElevadores:  ext.TotalElevadores,
+				TotalPavimentos:  ext.TotalPavimentos,
+				TotalUnidades:    ext.TotalUnidades,
+				EmpreendimentoID: empreendimentoID,
+			}
+			if err := is.repo.CreateTorre(ctx, torre); err != nil {
+				return fmt.Errorf("failed to create torre %s: %w", idIntegracao, err)
+			}
+			continue
+		}
+
+		if torre.Nome == ext.Nome && torre.TotalColunas == ext.TotalColunas &&
+			torre.TotalElevadores == ext.TotalElevadores && torre.TotalPavimentos == ext.TotalPavimentos &&
+			torre.TotalUnidades == ext.TotalUnidades {
+			continue
+		}
+		updates := map[string]interface{}{
+			"nome":             ext.Nome,
+			"total_colunas":    ext.TotalColunas,
+			"total_elevadores": ext.TotalElevadores,
+			"total_pavimentos": ext.TotalPavimentos,
+			"total_unidades":   ext.TotalUnidades,
+		}
+		if err := is.repo.PatchTorre(ctx, torre.ID, updates); err != nil {
+			return fmt.Errorf("failed to update torre %s: %w", idIntegracao, err)
+		}
+	}
+
+	for i := range existing {
+		if listed[existing[i].IdIntegracao] {
+			continue
+		}
+		if err := is.repo.DeleteTorre(ctx, existing[i].ID); err != nil {
+			return fmt.Errorf("failed to delete torre %s: %w", existing[i].IdIntegracao, err)
+		}
+		importLogger(ctx).Info("Deleted torre gone from the source", "torre", existing[i].IdIntegracao, "empreendimento_id", empreendimentoID)
+	}
+	return nil
+}
+
+// syncPlantas upserts the plantas of an empreendimento by external ID, syncing their images as
+// anexos, and deletes the imported plantas gone from the source along with their anexos
+func (is *importService) syncPlantas(ctx context.Context, empreendimentoID uint, exts []ExternalPlanta, images listingImages) error {
+	existing, err := is.repo.ListImportedPlantas(ctx, empreendimentoID)
+	if err != nil {
+		return fmt.Errorf("failed to read existing plantas: %w", err)
+	}
+	byID := make(map[string]*Plantas, len(existing))
+	for i := range existing {
+		byID[existing[i].IdIntegracao] = &existing[i]
+	}
+
+	listed := map[string]bool{}
+	for _, ext := range exts {
+		if ext.ID == 0 {
+			continue
+		}
+		idIntegracao := fmt.Sprintf("%d", ext.ID)
+		listed[idIntegracao] = true
+
+		planta, ok := byID[idIntegracao]
+		if !ok {
+			planta = &Plantas{
+				IdIntegracao:     idIntegracao,
+				Nome:             ext.Nome,
+				Metragem:         ext.Metragem,
+				EmpreendimentoID: empreendimentoID,
+			}
+			if err := is.repo.CreatePlanta(ctx, planta); err != nil {
+				return fmt.Errorf("failed to create planta %s: %w", idIntegracao, err)
+			}
+		} else if planta.Nome != ext.Nome || planta.Metragem != ext.Metragem {
+			updates := map[string]interface{}{
+				"nome":     ext.Nome,
+				"metragem": ext.Metragem,
+			}
+			if err := is.repo.PatchPlanta(ctx, planta.ID, updates); err != nil {
+				return fmt.Errorf("failed to update planta %s: %w", idIntegracao, err)
+			}
+		}
+
+		if err := is.syncPlantaAnexos(ctx, planta.ID, images.of(ext.Imagens)); err != nil {
+			return fmt.Errorf("failed to sync images of planta %s: %w", idIntegracao, err)
+		}
+	}
+
+	for i := range existing {
+		if listed[existing[i].IdIntegracao] {
+			continue
+		}
+		if err := is.repo.DeletePlanta(ctx, existing[i].ID); err != nil {
+			return fmt.Errorf("failed to delete planta %s: %w", existing[i].IdIntegracao, err)
+		}
+		importLogger(ctx).Info("Deleted planta gone from the source", "planta", existing[i].IdIntegracao, "empreendimento_id", empreendimentoID)
+	}
+	return nil
+}
+
+// syncPlantaAnexos makes the imported anexos of a planta match its images
+func (is *importService) syncPlantaAnexos(ctx context.Context, plantaID uint, images []importImage) error {
+	return is.syncImportedAnexos(ctx, anexoOwner{
+		name:  "planta",
+		owner: AnexoOwnerPlanta,
+		id:    plantaID,
+		add: func(ctx context.Context, anexo *Anexo) error {
+			anexo.PlantaID = &plantaID
+			return is.repo.CreateAnexo(ctx, anexo)
+		},
+		remove: func(ctx context.Context, anexo *Anexo) error {
+			return is.repo.DeleteAnexo(ctx, anexo.ID)
+		},
+	}, images)
+}
+
+// syncCaracteristicas links the caracteristicas of the source to an empreendimento, matching the
+// catalog by name regardless of case and adding the ones it lacks. Links are only added, so
+// caracteristicas linked by hand stay.
+func (is *importService) syncCaracteristicas(ctx context.Context, empreendimento *Empreendimento, exts []ExternalCaracteristica) error {
+	if len(exts) == 0 {
+		return nil
+	}
+
+	linked, err := is.repo.ListEmpreendimentoCaracteristicas(ctx, empreendimento.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read empreendimento caracteristicas: %w", err)
+	}
+	known := make(map[string]bool, len(linked))
+	for _, caracteristica := range linked {
+		known[strings.ToLower(caracteristica.Nome)] = true
+	}
+
+	var missing []Caracteristica
+	for _, ext := range exts {
+		nome := strings.TrimSpace(ext.Nome)
+		key := strings.ToLower(nome)
+		if nome == "" || known[key] {
+			continue
+		}
+		known[key] = true
+
+		caracteristica, err := is.repo.FindCaracteristicaByNome(ctx, nome)
+		if err != nil {
+			return fmt.Errorf("failed to look up caracteristica %q: %w", nome, err)
+		}
+		if caracteristica == nil {
+			caracteristica = &Caracteristica{Nome: nome, CategoriaNome: ext.Categoria}
+			if err := is.repo.CreateCaracteristica(ctx, caracteristica); err != nil {
+				return fmt.Errorf("failed to create caracteristica %q: %w", nome, err)
+			}
+		}
+		missing = append(missing, *caracteristica)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := is.repo.LinkEmpreendimentoCaracteristicas(ctx, empreendimento.ID, missing); err != nil {
+		return fmt.Errorf("failed to link empreendimento caracteristicas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPublishedProperties_EmpreendimentoRelations(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{
+		listings: []ExternalImovel{externalListing(1)},
+		empreendimento: &ExternalEmpreendimento{
+			ID:       70,
+			Titulo:   "Residencial Jardins",
+			Endereco: ExternalEndereco{Rua: "Rua das Flores", Numero: 100, Cidade: "Curitiba", Estado: "PR"},
+			Torres: []ExternalTorre{
+				{ID: 1, Nome: "Torre A", TotalPavimentos: 20, TotalUnidades: 80},
+				{ID: 2, Nome: "Torre B", TotalPavimentos: 18, TotalUnidades: 72},
+			},
+			Plantas: []ExternalPlanta{
+				{ID: 10, Nome: "2 quartos", Metragem: 65, Imagens: []string{"https://cdn.example.com/p1.jpg", "https://cdn.example.com/p2.jpg"}},
+				{ID: 11, Nome: "3 quartos", Metragem: 85, Imagens: []string{"https://cdn.example.com/p3.jpg"}},
+			},
+			Caracteristicas: []ExternalCaracteristica{
+				{ID: 5, Nome: "piscina", Categoria: "Lazer"},
+				{ID: 6, Nome: "Academia", Categoria: "Lazer"},
+			},
+		},
+	}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	var empreendimento Empreendimento
+	require.NoError(t, database.Preload("Endereco").Preload("Torres").Preload("Plantas.Anexos").
+		Preload("Caracteristicas").Where("id_integracao = ?", "70").First(&empreendimento).Error)
+	require.NotNil(t, empreendimento.Endereco)
+	assert.Equal(t, "Rua das Flores", empreendimento.Endereco.Rua)
+	require.Len(t, empreendimento.Torres, 2)
+	assert.Equal(t, "1", empreendimento.Torres[0].IdIntegracao)
+	assert.Equal(t, 20, empreendimento.Torres[0].TotalPavimentos)
+	require.Len(t, empreendimento.Plantas, 2)
+	assert.Len(t, empreendimento.Plantas[0].Anexos, 2)
+	assert.Len(t, empreendimento.Plantas[1].Anexos, 1)
+	require.Len(t, empreendimento.Caracteristicas, 2)
+
+	// The catalog is matched by name regardless of case
+	var piscinas int64
+	require.NoError(t, database.Model(&Caracteristica{}).Where("LOWER(nome) = ?", "piscina").Count(&piscinas).Error)
+	assert.Equal(t, int64(1), piscinas)
+
+	torreB := empreendimento.Torres[1]
+	plantaID := empreendimento.Plantas[0].ID
+	removedPlanta := empreendimento.Plantas[1]
+	manual := &Torres{Nome: "Torre Comercial", EmpreendimentoID: empreendimento.ID}
+	require.NoError(t, database.Create(manual).Error)
+
+	api.listings[0].Titulo = "Apartamento reformado"
+	api.empreendimento.Endereco.Numero = 200
+	api.empreendimento.Torres = []ExternalTorre{{ID: 2, Nome: "Torre B", TotalPavimentos: 19, TotalUnidades: 76}}
+	api.empreendimento.Plantas = []ExternalPlanta{
+		{ID: 10, Nome: "2 quartos", Metragem: 66, Imagens: []string{"https://cdn.example.com/p2.jpg"}},
+	}
+	api.empreendimento.Caracteristicas = []ExternalCaracteristica{{ID: 7, Nome: "Salão de festas"}}
+
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "0 created, 1 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	var updated Empreendimento
+	require.NoError(t, database.Preload("Endereco").Preload("Torres").Preload("Plantas.Anexos").
+		Preload("Caracteristicas").First(&updated, empreendimento.ID).Error)
+	assert.Equal(t, empreendimento.EnderecoID, updated.EnderecoID)
+	assert.Equal(t, 200, updated.Endereco.Numero)
+
+	// Torres are updated in place, those gone from the source deleted and those added by hand kept
+	require.Len(t, updated.Torres, 2)
+	assert.Equal(t, torreB.ID, updated.Torres[0].ID)
+	assert.Equal(t, 19, updated.Torres[0].TotalPavimentos)
+	assert.Equal(t, manual.ID, updated.Torres[1].ID)
+
+	require.Len(t, updated.Plantas, 1)
+	assert.Equal(t, plantaID, updated.Plantas[0].ID)
+	assert.Equal(t, 66.0, updated.Plantas[0].Metragem)
+	require.Len(t, updated.Plantas[0].Anexos, 1)
+	assert.Equal(t, "https://cdn.example.com/p2.jpg", updated.Plantas[0].Anexos[0].ExternalURL)
+	var orphans int64
+	require.NoError(t, database.Model(&Anexo{}).Where("planta_id = ?", removedPlanta.ID).Count(&orphans).Error)
+	assert.Zero(t, orphans)
+
+	// Caracteristicas are only added
+	assert.Len(t, updated.Caracteristicas, 3)
+}
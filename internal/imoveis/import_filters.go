@@ -0,0 +1,74 @@
+package imoveis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ImportFilters scope an import run to the listings matching all the filters given, so staging and
+// debugging do not have to sync the whole catalog. Scoped runs neither handle the listings removed
+// from the source nor move the incremental watermark, as they do not see the whole catalog.
+type ImportFilters struct {
+	// Cidades matches the city of the listing address, ignoring case
+	Cidades []string `form:"cidades" json:"cidades,omitempty"`
+	// Corretores matches the e-mail, ignoring case, or the external ID of the main corretor
+	Corretores []string `form:"corretores" json:"corretores,omitempty"`
+	// Status matches the status of the listing at the source, ignoring case
+	Status []string `form:"status" json:"status,omitempty"`
+	// IDs matches the external listing IDs
+	IDs []uint `form:"ids" json:"ids,omitempty"`
+}
+
+// scoped reports whether any filter is set
+func (f *ImportFilters) scoped() bool {
+	return len(f.Cidades) > 0 || len(f.Corretores) > 0 || len(f.Status) > 0 || len(f.IDs) > 0
+}
+
+// match reports whether a listing passes every filter set
+func (f *ImportFilters) match(listing *ExternalImovel) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, listing.ID) {
+		return false
+	}
+	if len(f.Cidades) > 0 && !containsFold(f.Cidades, listing.Endereco.Cidade) {
+		return false
+	}
+	if len(f.Status) > 0 && !containsFold(f.Status, listing.Status) {
+		return false
+	}
+	if len(f.Corretores) > 0 {
+		corretor := listing.CorretorPrincipal
+		if !containsFold(f.Corretores, corretor.Email) && !slices.Contains(f.Corretores, fmt.Sprintf("%d", corretor.ID)) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the listings passing every filter set
+func (f *ImportFilters) filter(listings []ExternalImovel) []ExternalImovel {
+	if !f.scoped() {
+		return listings
+	}
+	var matched []ExternalImovel
+	for i := range listings {
+		if f.match(&listings[i]) {
+			matched = append(matched, listings[i])
+		}
+	}
+	return matched
+}
+
+// containsFold reports whether values holds s, ignoring case and surrounding spaces
+func containsFold(values []string, s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), s) {
+			return true
+		}
+	}
+	return false
+}
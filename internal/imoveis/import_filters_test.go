@@ -0,0 +1,75 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportFilters_Match(t *testing.T) {
+	listing := externalListing(7)
+	listing.Status = "PUBLICADO"
+	listing.Endereco.Cidade = "São Paulo"
+	listing.CorretorPrincipal = ExternalCorretor{ID: 42, Email: "ana@example.com"}
+
+	tests := []struct {
+		name    string
+		filters ImportFilters
+		want    bool
+	}{
+		{"no filters", ImportFilters{}, true},
+		{"city ignoring case", ImportFilters{Cidades: []string{" são paulo"}}, true},
+		{"other city", ImportFilters{Cidades: []string{"Campinas"}}, false},
+		{"corretor by e-mail", ImportFilters{Corretores: []string{"ANA@example.com"}}, true},
+		{"corretor by external ID", ImportFilters{Corretores: []string{"42"}}, true},
+		{"other corretor", ImportFilters{Corretores: []string{"bruno@example.com"}}, false},
+		{"status", ImportFilters{Status: []string{"publicado"}}, true},
+		{"ID list", ImportFilters{IDs: []uint{3, 7}}, true},
+		{"every filter must match", ImportFilters{IDs: []uint{7}, Cidades: []string{"Campinas"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filters.match(&listing))
+		})
+	}
+}
+
+func TestImportPublishedProperties_Scoped(t *testing.T) {
+	ctx := context.Background()
+	listings := []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}
+	listings[0].Endereco.Cidade = "São Paulo"
+	listings[1].Endereco.Cidade = "Campinas"
+	listings[2].Endereco.Cidade = "São Paulo"
+	api := &fakeExternalAPI{listings: listings}
+	importer, database := setupImportService(t, api, true)
+	importer.removedPolicy = RemovedPolicyDelete
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{ImportFilters: ImportFilters{IDs: []uint{1, 2}}})
+	require.NoError(t, err)
+	require.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	assert.ElementsMatch(t, []uint{1, 2}, api.detailRequests)
+
+	// A scoped run does not move the watermark, so the next run still lists everything
+	var watermarks int64
+	require.NoError(t, database.Model(&ImportSyncState{}).Count(&watermarks).Error)
+	assert.Zero(t, watermarks)
+
+	// Nor does it take the listings it skipped as removed from the source
+	api.reset()
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{ImportFilters: ImportFilters{Cidades: []string{"são paulo"}}})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 1 unchanged, 0 removed, 0 failed", result.String())
+	assert.Equal(t, []uint{3}, api.detailRequests)
+	findImported(t, database, "2")
+
+	var run ImportRun
+	require.NoError(t, database.Order("id DESC").First(&run).Error)
+	require.NotNil(t, run.Filters)
+	assert.Equal(t, []string{"são paulo"}, run.Filters.Cidades)
+	runs, err := importer.ListImportRuns(ctx, &ImportRunListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, runs.Results, 2)
+	assert.Equal(t, run.Filters, runs.Results[0].Filters)
+}
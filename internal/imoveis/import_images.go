@@ -0,0 +1,168 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// importImage is an image of a listing ready to become an anexo: a stored object when it was
+// downloaded, otherwise the external URL only
+type importImage struct {
+	externalURL string
+	path        string
+	url         string
+	contentType string
+	hash        string
+	size        int64
+}
+
+// stored reports whether the image lives in our storage
+func (img importImage) stored() bool {
+	return img.path != ""
+}
+
+// listingImages are the prepared images of a listing, of the plantas of its empreendimento and the
+// photo of its corretor, by external URL
+type listingImages map[string]importImage
+
+// of returns the prepared images of imageURLs in order; images not prepared are linked at their
+// external URL
+func (li listingImages) of(imageURLs []string) []importImage {
+	images := make([]importImage, len(imageURLs))
+	for i, imageURL := range imageURLs {
+		image, ok := li[imageURL]
+		if !ok {
+			image = importImage{externalURL: imageURL}
+		}
+		images[i] = image
+	}
+	return images
+}
+
+// listingImageURLs returns the images of a listing followed by those of the plantas of its
+// empreendimento and the photo of its corretor
+func listingImageURLs(ext *ExternalDetailedImovel) []string {
+	imageURLs := append([]string(nil), ext.Imagens...)
+	if ext.Empreendimento != nil {
+		for _, planta := range ext.Empreendimento.Plantas {
+			imageURLs = append(imageURLs, planta.Imagens...)
+		}
+	}
+	if foto := ext.CorretorPrincipal.Foto; foto != nil && foto.URL != "" {
+		imageURLs = append(imageURLs, foto.URL)
+	}
+	return imageURLs
+}
+
+// prepareImages downloads the images of a listing to the storage when enabled. Images downloaded
+// before are reused, by external URL without downloading them again or by content hash; an image
+// that fails to download is linked at its external URL, as without the option.
+func (is *importService) prepareImages(ctx context.Context, imageURLs []string) listingImages {
+	images := listingImages{}
+	if is.images == nil || len(imageURLs) == 0 {
+		return images
+	}
+
+	known, err := is.repo.ListStoredAnexosByURL(ctx, imageURLs)
+	if err != nil {
+		importLogger(ctx).Warn("Failed to look up downloaded images", "error", err)
+	}
+	byURL := make(map[string]Anexo, len(known))
+	for _, anexo := range known {
+		byURL[anexo.ExternalURL] = anexo
+	}
+
+	for _, imageURL := range imageURLs {
+		if _, done := images[imageURL]; done {
+			continue
+		}
+		if anexo, ok := byURL[imageURL]; ok {
+			images[imageURL] = storedImage(imageURL, &anexo)
+			continue
+		}
+
+		image, err := is.downloadImage(ctx, imageURL)
+		if err != nil {
+			importLogger(ctx).Warn("Failed to download image, linking the external URL", "url", imageURL, "error", err)
+			continue
+		}
+		images[imageURL] = image
+	}
+	return images
+}
+
+// downloadImage fetches an image and stores it under its content hash, unless an anexo already
+// holds the same content
+func (is *importService) downloadImage(ctx context.Context, imageURL string) (importImage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return importImage{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := is.client.http.Do(req)
+	if err != nil {
+		return importImage{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			importLogger(ctx).Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return importImage{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAnexoSourceBytes+1))
+	if err != nil {
+		return importImage{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxAnexoSourceBytes {
+		return importImage{}, fmt.Errorf("%w (%d bytes)", ErrFileTooLarge, maxAnexoSourceBytes)
+	}
+	contentType := strings.ToLower(strings.SplitN(http.DetectContentType(data), ";", 2)[0])
+	if !strings.HasPrefix(contentType, "image/") {
+		return importImage{}, fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	existing, err := is.repo.FindStoredAnexoByHash(ctx, hash)
+	if err != nil {
+		return importImage{}, fmt.Errorf("failed to look up image by hash: %w", err)
+	}
+	if existing != nil {
+		return storedImage(imageURL, existing), nil
+	}
+
+	key := fmt.Sprintf("imoveis/imports/%s%s", hash, mimeExtensions[contentType])
+	obj, err := is.images.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return importImage{}, fmt.Errorf("failed to store image: %w", err)
+	}
+	return importImage{
+		externalURL: imageURL,
+		path:        obj.Key,
+		url:         obj.URL,
+		contentType: contentType,
+		hash:        hash,
+		size:        obj.Size,
+	}, nil
+}
+
+// storedImage reuses the object of an anexo downloaded before for imageURL
+func storedImage(imageURL string, anexo *Anexo) importImage {
+	return importImage{
+		externalURL: imageURL,
+		path:        anexo.Path,
+		url:         anexo.URL,
+		contentType: anexo.Tipo,
+		hash:        anexo.ContentHash,
+		size:        anexo.Tamanho,
+	}
+}
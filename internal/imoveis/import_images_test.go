@@ -0,0 +1,142 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+// imageServer serves the same PNG under /a.png, /b.png and /c.png, and a different one under
+// /other.png; other paths are missing. It counts the requests per path.
+func imageServer(t *testing.T) (*httptest.Server, func(path string) int) {
+	t.Helper()
+	encode := func(c color.Color) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		img.Set(0, 0, c)
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+	same := encode(color.White)
+	other := encode(color.Black)
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/a.png", "/b.png", "/c.png":
+			_, _ = w.Write(same)
+		case "/other.png":
+			_, _ = w.Write(other)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+}
+
+func importedAnexos(t *testing.T, importer *importService, idIntegracao string) []Anexo {
+	t.Helper()
+	var anexos []Anexo
+	require.NoError(t, importer.repo.(*repository).db.
+		Where("imovel_id = (SELECT id FROM imoveis WHERE id_integracao = ?)", idIntegracao).
+		Order("id").Find(&anexos).Error)
+	return anexos
+}
+
+func TestImportPublishedProperties_DownloadImages(t *testing.T) {
+	ctx := context.Background()
+	images, requests := imageServer(t)
+
+	first, second := externalListing(1), externalListing(2)
+	first.Imagens = []string{images.URL + "/a.png", images.URL + "/other.png", images.URL + "/missing.png"}
+	second.Imagens = []string{images.URL + "/b.png"}
+	api := &fakeExternalAPI{listings: []ExternalImovel{first, second}}
+	importer, _ := setupImportService(t, api, false)
+	dir := t.TempDir()
+	importer.images = storage.NewLocalStorage(dir, "")
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	anexos := importedAnexos(t, importer, "1")
+	require.Len(t, anexos, 3)
+	downloaded := anexos[0]
+	assert.False(t, downloaded.IsExternalURL)
+	assert.Equal(t, images.URL+"/a.png", downloaded.ExternalURL)
+	assert.Equal(t, "image/png", downloaded.Tipo)
+	assert.NotEmpty(t, downloaded.ContentHash)
+	assert.Contains(t, downloaded.URL, downloaded.Path)
+	_, err = os.Stat(filepath.Join(dir, downloaded.Path))
+	assert.NoError(t, err)
+	assert.NotEqual(t, downloaded.Path, anexos[1].Path)
+
+	// A broken image is linked at its external URL
+	assert.True(t, anexos[2].IsExternalURL)
+	assert.Equal(t, images.URL+"/missing.png", anexos[2].URL)
+	assert.Empty(t, anexos[2].Path)
+
+	// The same content is stored once
+	same := importedAnexos(t, importer, "2")
+	require.Len(t, same, 1)
+	assert.Equal(t, downloaded.Path, same[0].Path)
+	assert.Equal(t, images.URL+"/b.png", same[0].ExternalURL)
+
+	t.Run("downloaded images are not fetched again", func(t *testing.T) {
+		api.listings[0].Titulo = "Apartamento reformado"
+		api.listings[1].Imagens = append(api.listings[1].Imagens, images.URL+"/c.png")
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "0 created, 2 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+		assert.Equal(t, 1, requests("/a.png"))
+		assert.Equal(t, 1, requests("/b.png"))
+		assert.Equal(t, 1, requests("/c.png"))
+		assert.Equal(t, 2, requests("/missing.png"))
+
+		anexos := importedAnexos(t, importer, "2")
+		require.Len(t, anexos, 2)
+		assert.Equal(t, downloaded.Path, anexos[0].Path)
+		assert.Equal(t, downloaded.Path, anexos[1].Path)
+	})
+}
+
+func TestImportPublishedProperties_ExternalImages(t *testing.T) {
+	listing := externalListing(1)
+	listing.Imagens = []string{"https://cdn.example.com/1.jpg"}
+	importer, _ := setupImportService(t, &fakeExternalAPI{listings: []ExternalImovel{listing}}, false)
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	anexos := importedAnexos(t, importer, "1")
+	require.Len(t, anexos, 1)
+	assert.True(t, anexos[0].IsExternalURL)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", anexos[0].URL)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", anexos[0].ExternalURL)
+	assert.Empty(t, anexos[0].ContentHash)
+}
@@ -0,0 +1,142 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+var (
+	// ErrImportJobNotFound is returned when an import job does not exist
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportRunning is returned when an import job is already in progress
+	ErrImportRunning = errors.New("an import is already running")
+)
+
+// maxRetainedImportJobs bounds how many finished import jobs are kept in memory for status lookups
+const maxRetainedImportJobs = 20
+
+// StartImport queues an import run in the background and returns its job right away. Only one
+// import runs at a time. The run to resume, if any, is checked before the job is queued.
+func (is *importService) StartImport(ctx context.Context, opts ImportOptions, requestedBy uint) (*ImportJobResponse, error) {
+	// The job shows the mode and filters of the run it resumes
+	shown := opts
+	if opts.Resume != 0 {
+		if _, err := is.prepareResume(ctx, &shown); err != nil {
+			return nil, err
+		}
+	}
+
+	is.jobsMu.Lock()
+	if is.runningJob != "" {
+		is.jobsMu.Unlock()
+		return nil, ErrImportRunning
+	}
+
+	job := &ImportJobResponse{
+		ID:          uuid.NewString(),
+		Status:      maintenance.StatusQueued,
+		Full:        shown.Full,
+		Resume:      opts.Resume,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if shown.scoped() {
+		filters := shown.ImportFilters
+		job.Filters = &filters
+	}
+	if is.jobs == nil {
+		is.jobs = make(map[string]*ImportJobResponse)
+	}
+	is.jobs[job.ID] = job
+	is.jobHistory = append(is.jobHistory, job.ID)
+	is.runningJob = job.ID
+	is.pruneJobsLocked()
+	snapshot := *job
+	is.jobsMu.Unlock()
+
+	go is.runJob(job.ID, opts, requestedBy)
+
+	return &snapshot, nil
+}
+
+// GetImportJob returns a snapshot of the import job progress
+func (is *importService) GetImportJob(ctx context.Context, id string) (*ImportJobResponse, error) {
+	is.jobsMu.Lock()
+	defer is.jobsMu.Unlock()
+
+	job, ok := is.jobs[id]
+	if !ok {
+		return nil, ErrImportJobNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+func (is *importService) runJob(jobID string, opts ImportOptions, requestedBy uint) {
+	// Jobs outlive the HTTP request that started them; the changes are still attributed to the
+	// user who started it
+	ctx := withImportLogger(context.Background(), is.logger.With("job_id", jobID))
+	if requestedBy != 0 {
+		ctx = contextutil.WithUserID(ctx, requestedBy)
+	}
+
+	is.updateJob(jobID, func(job *ImportJobResponse) {
+		now := time.Now().UTC()
+		job.Status = maintenance.StatusRunning
+		job.StartedAt = &now
+	})
+
+	opts.Progress = func(progress ImportProgress) {
+		is.updateJob(jobID, func(job *ImportJobResponse) {
+			job.ImportProgress = progress
+		})
+	}
+	result, err := is.ImportPublishedProperties(ctx, opts)
+
+	report := ""
+	is.updateJob(jobID, func(job *ImportJobResponse) {
+		now := time.Now().UTC()
+		job.FinishedAt = &now
+		job.ImportProgress = result.ImportProgress
+		job.RunID = result.RunID
+		job.Status = maintenance.StatusCompleted
+		job.Report = result.String()
+		if err != nil {
+			job.Status = maintenance.StatusFailed
+			job.Report = err.Error()
+		}
+		report = job.Report
+		is.runningJob = ""
+	})
+
+	importLogger(ctx).Info("Import job finished", "report", report)
+}
+
+// updateJob applies fn to the job while holding the jobs lock
+func (is *importService) updateJob(jobID string, fn func(job *ImportJobResponse)) {
+	is.jobsMu.Lock()
+	defer is.jobsMu.Unlock()
+
+	if job, ok := is.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+// pruneJobsLocked drops the oldest finished jobs beyond maxRetainedImportJobs. Callers must hold
+// is.jobsMu.
+func (is *importService) pruneJobsLocked() {
+	for len(is.jobHistory) > maxRetainedImportJobs {
+		oldest := is.jobHistory[0]
+		if oldest == is.runningJob {
+			return
+		}
+		delete(is.jobs, oldest)
+		is.jobHistory = is.jobHistory[1:]
+	}
+}
@@ -0,0 +1,93 @@
+package imoveis
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+// waitImportJob polls the job until it finishes
+func waitImportJob(t *testing.T, importer ImportService, id string) *ImportJobResponse {
+	t.Helper()
+	var job *ImportJobResponse
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = importer.GetImportJob(context.Background(), id)
+		require.NoError(t, err)
+		return job.FinishedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestStartImport(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}}
+	importer, _ := setupImportService(t, api, false)
+
+	job, err := importer.StartImport(ctx, ImportOptions{Full: true}, 7)
+	require.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, maintenance.StatusQueued, job.Status)
+	assert.Equal(t, uint(7), job.RequestedBy)
+	assert.True(t, job.Full)
+
+	finished := waitImportJob(t, importer, job.ID)
+	assert.Equal(t, maintenance.StatusCompleted, finished.Status)
+	assert.Equal(t, ImportProgress{Total: 3, Processed: 3, Created: 3}, finished.ImportProgress)
+	assert.Equal(t, "3 created, 0 updated, 0 unchanged, 0 removed, 0 failed", finished.Report)
+	assert.NotZero(t, finished.RunID)
+	assert.NotNil(t, finished.StartedAt)
+
+	_, err = importer.GetImportJob(ctx, "missing")
+	assert.ErrorIs(t, err, ErrImportJobNotFound)
+}
+
+func TestStartImport_OneAtATime(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+	importer, _ := setupImportService(t, api, false)
+
+	// Hold the run on its first request to the source
+	release := make(chan struct{})
+	upstream := importer.client.httpClient.Transport
+	importer.client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-release
+		return upstream.RoundTrip(req)
+	})
+
+	first, err := importer.StartImport(ctx, ImportOptions{}, 1)
+	require.NoError(t, err)
+
+	_, err = importer.StartImport(ctx, ImportOptions{}, 1)
+	assert.ErrorIs(t, err, ErrImportRunning)
+
+	close(release)
+	assert.Equal(t, maintenance.StatusCompleted, waitImportJob(t, importer, first.ID).Status)
+
+	second, err := importer.StartImport(ctx, ImportOptions{}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, maintenance.StatusCompleted, waitImportJob(t, importer, second.ID).Status)
+}
+
+func TestStartImport_FailedRun(t *testing.T) {
+	importer, _ := setupImportService(t, &fakeExternalAPI{}, false)
+
+	job, err := importer.StartImport(context.Background(), ImportOptions{}, 1)
+	require.NoError(t, err)
+
+	finished := waitImportJob(t, importer, job.ID)
+	assert.Equal(t, maintenance.StatusFailed, finished.Status)
+	assert.Equal(t, "no properties found in external API", finished.Report)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,56 @@
+package imoveis
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// importLoggerKey carries the logger of an import through its context
+type importLoggerKey struct{}
+
+// importCorrelationKey carries the correlation ID of an import through its context
+type importCorrelationKey struct{}
+
+// WithLogger sends the import logs to logger instead of slog.Default
+func WithLogger(logger *slog.Logger) ImportServiceOption {
+	return func(is *importService) {
+		if logger != nil {
+			is.logger = logger
+		}
+	}
+}
+
+// withImportLogger attaches logger to ctx for the import code it reaches
+func withImportLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, importLoggerKey{}, logger)
+}
+
+// importLogger returns the logger of the import ctx belongs to, or slog.Default outside of one
+func importLogger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(importLoggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// correlate gives an import started with ctx its correlation ID, logged as correlation_id by
+// every line of the import so its logs can be told apart from those of concurrent ones. An import
+// started from another one, such as the webhook importing a listing, keeps the ID it runs under.
+func (is *importService) correlate(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(importCorrelationKey{}).(string); ok {
+		return ctx
+	}
+
+	logger, ok := ctx.Value(importLoggerKey{}).(*slog.Logger)
+	if !ok {
+		logger = is.logger
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	id := uuid.NewString()
+	ctx = context.WithValue(ctx, importCorrelationKey{}, id)
+	return withImportLogger(ctx, logger.With("correlation_id", id))
+}
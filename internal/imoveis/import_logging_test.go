@@ -0,0 +1,70 @@
+package imoveis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logLines decodes the JSON lines written to buf with the given message
+func logLines(t *testing.T, buf *bytes.Buffer, msg string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		if line["msg"] == msg {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestImportPublishedProperties_StructuredLogs(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2)}}
+	importer, database := setupImportService(t, api, false)
+	var buf bytes.Buffer
+	WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))(importer)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	var run ImportRun
+	require.NoError(t, database.Order("id DESC").First(&run).Error)
+	imported := logLines(t, &buf, "Imported listing")
+	require.Len(t, imported, 2)
+	correlationID := imported[0]["correlation_id"]
+	assert.NotEmpty(t, correlationID)
+	for _, line := range imported {
+		assert.Equal(t, correlationID, line["correlation_id"])
+		assert.Equal(t, float64(run.ID), line["import_run_id"])
+		assert.Equal(t, ImportOutcomeCreated, line["action"])
+		assert.Contains(t, []interface{}{"EXT-001", "EXT-002"}, line["codigo"])
+		assert.Contains(t, line, "external_id")
+		assert.Contains(t, line, "duration")
+	}
+	finished := logLines(t, &buf, "Import run finished")
+	require.Len(t, finished, 1)
+	assert.Equal(t, correlationID, finished[0]["correlation_id"])
+
+	t.Run("every run has its own correlation ID", func(t *testing.T) {
+		buf.Reset()
+		_, err := importer.ImportProperty(ctx, 1)
+		require.NoError(t, err)
+
+		lines := logLines(t, &buf, "Imported listing")
+		require.Len(t, lines, 1)
+		assert.NotEmpty(t, lines[0]["correlation_id"])
+		assert.NotEqual(t, correlationID, lines[0]["correlation_id"])
+		assert.Equal(t, ImportOutcomeUpdated, lines[0]["action"])
+	})
+}
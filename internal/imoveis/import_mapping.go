@@ -0,0 +1,134 @@
+package imoveis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// Transforms applied to the values of the source no mapping matches
+const (
+	MappingTransformUpper      = "upper"
+	MappingTransformLower      = "lower"
+	MappingTransformUpperSnake = "upper_snake"
+)
+
+// mappedFields are the fields of the listings externalapi.mappings may translate
+var mappedFields = map[string]bool{
+	"tipo":                      true,
+	"objetivo":                  true,
+	"finalidade":                true,
+	"status":                    true,
+	"empreendimento_tipo":       true,
+	"empreendimento_finalidade": true,
+	"empreendimento_status":     true,
+}
+
+// fieldMapping translates the values of one enum field of the source to local ones
+type fieldMapping struct {
+	values    map[string]string
+	transform string
+	allowed   map[string]bool
+	fallback  string
+}
+
+// fieldMappings holds the mapping of each field; fields without one keep the values of the source
+type fieldMappings map[string]*fieldMapping
+
+// newFieldMappings builds the mappings set in externalapi.mappings, refusing unknown fields and
+// transforms so a typo does not go unnoticed until the data is wrong
+func newFieldMappings(cfg map[string]config.FieldMappingConfig) (fieldMappings, error) {
+	mappings := make(fieldMappings, len(cfg))
+	for field, fieldCfg := range cfg {
+		field = strings.ToLower(field)
+		if !mappedFields[field] {
+			return nil, fmt.Errorf("unknown field %q in external API mappings", field)
+		}
+		switch fieldCfg.Transform {
+		case "", MappingTransformUpper, MappingTransformLower, MappingTransformUpperSnake:
+		default:
+			return nil, fmt.Errorf("unknown transform %q for field %q in external API mappings", fieldCfg.Transform, field)
+		}
+
+		mapping := &fieldMapping{
+			values:    make(map[string]string, len(fieldCfg.Values)),
+			transform: fieldCfg.Transform,
+			fallback:  fieldCfg.Default,
+		}
+		// The configuration lowercases the keys, so values are matched ignoring case
+		for from, to := range fieldCfg.Values {
+			mapping.values[strings.ToLower(strings.TrimSpace(from))] = to
+		}
+		if len(fieldCfg.Allowed) > 0 {
+			mapping.allowed = make(map[string]bool, len(fieldCfg.Allowed))
+			for _, value := range fieldCfg.Allowed {
+				mapping.allowed[value] = true
+			}
+		}
+		mappings[field] = mapping
+	}
+	return mappings, nil
+}
+
+// translate returns the local value of a value of the source
+func (m *fieldMapping) translate(value string) string {
+	value = strings.TrimSpace(value)
+	if mapped, ok := m.values[strings.ToLower(value)]; ok {
+		return mapped
+	}
+
+	switch m.transform {
+	case MappingTransformUpper:
+		value = strings.ToUpper(value)
+	case MappingTransformLower:
+		value = strings.ToLower(value)
+	case MappingTransformUpperSnake:
+		value = strings.ToUpper(strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+			return r == ' ' || r == '-' || r == '_'
+		}), "_"))
+	}
+
+	if value == "" || (m.allowed != nil && !m.allowed[value]) {
+		return m.fallback
+	}
+	return value
+}
+
+// value returns the local value of a value of the source for field
+func (m fieldMappings) value(field, value string) string {
+	mapping, ok := m[field]
+	if !ok {
+		return value
+	}
+	return mapping.translate(value)
+}
+
+// applyListing translates the enum fields of a listing of the published list, before it is
+// filtered and its checksum taken, so a new mapping imports the listings it changes again
+func (m fieldMappings) applyListing(ext *ExternalImovel) {
+	if len(m) == 0 {
+		return
+	}
+	ext.Tipo = m.value("tipo", ext.Tipo)
+	ext.Objetivo = m.value("objetivo", ext.Objetivo)
+	ext.Finalidade = m.value("finalidade", ext.Finalidade)
+	ext.Status = m.value("status", ext.Status)
+}
+
+// applyDetails translates the enum fields of a listing and of its empreendimento before they are
+// written
+func (m fieldMappings) applyDetails(ext *ExternalDetailedImovel) {
+	if len(m) == 0 {
+		return
+	}
+	ext.Tipo = m.value("tipo", ext.Tipo)
+	ext.Objetivo = m.value("objetivo", ext.Objetivo)
+	ext.Finalidade = m.value("finalidade", ext.Finalidade)
+	ext.Status = m.value("status", ext.Status)
+	if ext.Empreendimento != nil {
+		ext.Empreendimento.Tipo = m.value("empreendimento_tipo", ext.Empreendimento.Tipo)
+		ext.Empreendimento.Finalidade = m.value("empreendimento_finalidade", ext.Empreendimento.Finalidade)
+		ext.Empreendimento.Status = m.value("empreendimento_status", ext.Empreendimento.Status)
+	}
+}
@@ -0,0 +1,90 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func TestFieldMappings_Value(t *testing.T) {
+	mappings, err := newFieldMappings(map[string]config.FieldMappingConfig{
+		"tipo": {
+			Values:    map[string]string{"apto": "APARTAMENTO"},
+			Transform: MappingTransformUpperSnake,
+			Allowed:   []string{"APARTAMENTO", "CASA", "SALA_COMERCIAL"},
+			Default:   "COMERCIAL",
+		},
+		"finalidade": {Values: map[string]string{"residencial": "RESIDENTIAL"}, Transform: MappingTransformUpper},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		field string
+		value string
+		want  string
+	}{
+		{"mapped ignoring case", "tipo", " Apto ", "APARTAMENTO"},
+		{"transformed", "tipo", "sala comercial", "SALA_COMERCIAL"},
+		{"outside allowed", "tipo", "loft", "COMERCIAL"},
+		{"empty", "tipo", "", "COMERCIAL"},
+		{"mapped", "finalidade", "RESIDENCIAL", "RESIDENTIAL"},
+		{"upper without allowed", "finalidade", "misto", "MISTO"},
+		{"field without mapping", "objetivo", "vender", "vender"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mappings.value(tt.field, tt.value))
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := newFieldMappings(map[string]config.FieldMappingConfig{"cor": {}})
+		assert.EqualError(t, err, `unknown field "cor" in external API mappings`)
+	})
+
+	t.Run("unknown transform", func(t *testing.T) {
+		_, err := newFieldMappings(map[string]config.FieldMappingConfig{"tipo": {Transform: "title"}})
+		assert.EqualError(t, err, `unknown transform "title" for field "tipo" in external API mappings`)
+	})
+}
+
+func TestImportPublishedProperties_FieldMappings(t *testing.T) {
+	ctx := context.Background()
+	listings := []ExternalImovel{externalListing(1), externalListing(2)}
+	listings[0].Tipo = "Apto"
+	listings[0].Finalidade = "residencial"
+	listings[1].Tipo = "sala comercial"
+	listings[1].Finalidade = "comercial"
+	api := &fakeExternalAPI{
+		listings:       listings,
+		empreendimento: &ExternalEmpreendimento{ID: 50, Titulo: "Residencial Jardins", Finalidade: "residencial"},
+	}
+	importer, database := setupImportService(t, api, false)
+	mappings, err := newFieldMappings(map[string]config.FieldMappingConfig{
+		"tipo":                      {Values: map[string]string{"apto": "APARTAMENTO"}, Transform: MappingTransformUpperSnake},
+		"finalidade":                {Values: map[string]string{"residencial": "RESIDENTIAL"}, Transform: MappingTransformUpper},
+		"empreendimento_finalidade": {Values: map[string]string{"residencial": "RESIDENTIAL"}},
+	})
+	require.NoError(t, err)
+	importer.mappings = mappings
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	first := findImported(t, database, "1")
+	assert.Equal(t, "APARTAMENTO", first.Tipo)
+	assert.Equal(t, "RESIDENTIAL", first.Finalidade)
+	second := findImported(t, database, "2")
+	assert.Equal(t, "SALA_COMERCIAL", second.Tipo)
+	assert.Equal(t, "COMERCIAL", second.Finalidade)
+
+	var empreendimento Empreendimento
+	require.NoError(t, database.Where("id_integracao = ?", "50").First(&empreendimento).Error)
+	assert.Equal(t, "RESIDENTIAL", empreendimento.Finalidade)
+}
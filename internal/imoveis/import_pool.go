@@ -0,0 +1,214 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultImportWorkers applies when externalapi.workers is not configured
+const defaultImportWorkers = 8
+
+// importOutcome is the result of importing one listing
+type importOutcome int
+
+const (
+	importCreated importOutcome = iota
+	importUpdated
+	importUnchanged
+	importFailed
+	importQuarantined
+)
+
+// String names the outcome in the logs
+func (o importOutcome) String() string {
+	switch o {
+	case importCreated:
+		return ImportOutcomeCreated
+	case importUpdated:
+		return ImportOutcomeUpdated
+	case importUnchanged:
+		return "unchanged"
+	case importQuarantined:
+		return ImportOutcomeQuarantined
+	default:
+		return "failed"
+	}
+}
+
+// importCounts aggregates the outcomes of an import run over listed listings, the imported
+// properties found removed from the source after it and the requests held back by the rate limit
+type importCounts struct {
+	listed, created, updated, unchanged, failed int
+	removed, quarantined                        int
+	throttled                                   int
+	throttleWait                                time.Duration
+}
+
+func (c *importCounts) add(outcome importOutcome) {
+	switch outcome {
+	case importCreated:
+		c.created++
+	case importUpdated:
+		c.updated++
+	case importUnchanged:
+		c.unchanged++
+	case importQuarantined:
+		c.quarantined++
+	default:
+		c.failed++
+	}
+}
+
+func (c *importCounts) total() int {
+	return c.created + c.updated + c.unchanged + c.failed + c.quarantined
+}
+
+func (c *importCounts) progress() ImportProgress {
+	return ImportProgress{
+		Total:     c.listed,
+		Processed: c.total(),
+		Created:   c.created,
+		Updated:   c.updated,
+		Unchanged: c.unchanged,
+		Failed:    c.failed,
+		Removed:   c.removed,
+
+		Quarantined: c.quarantined,
+	}
+}
+
+// importHooks are called by importAll from a single goroutine; all are optional
+type importHooks struct {
+	// progress receives the counts after every listing
+	progress func(ImportProgress)
+	// failed receives each listing that failed and why
+	failed func(listing *ExternalImovel, err error)
+	// checkpoint receives how many listings from the start went through without failing, in the
+	// order given, whenever that grows; workers finish out of order, so it trails the progress
+	checkpoint func(done int)
+}
+
+type importResult struct {
+	index   int
+	listing *ExternalImovel
+	outcome importOutcome
+	err     error
+}
+
+// importAll runs fn for every listing on the import workers and aggregates the outcomes. A panic
+// in fn fails that listing only. Listings not started when ctx is done are not counted.
+func (is *importService) importAll(ctx context.Context, listings []ExternalImovel, hooks importHooks, fn func(*ExternalImovel) (importOutcome, error)) importCounts {
+	jobs := make(chan int)
+	results := make(chan importResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < is.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				listing := &listings[index]
+				outcome, err := isolateImport(ctx, listing, fn)
+				results <- importResult{index: index, listing: listing, outcome: outcome, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range listings {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	counts := importCounts{listed: len(listings)}
+	if hooks.progress != nil {
+		hooks.progress(counts.progress())
+	}
+	succeeded := make([]bool, len(listings))
+	done := 0
+	for result := range results {
+		counts.add(result.outcome)
+		if result.outcome != importFailed {
+			succeeded[result.index] = true
+			reached := done
+			for done < len(listings) && succeeded[done] {
+				done++
+			}
+			if done > reached && hooks.checkpoint != nil {
+				hooks.checkpoint(done)
+			}
+		}
+		if result.outcome == importFailed && hooks.failed != nil {
+			err := result.err
+			if err == nil {
+				err = errors.New("import failed")
+			}
+			hooks.failed(result.listing, err)
+		}
+		if hooks.progress != nil {
+			hooks.progress(counts.progress())
+		}
+	}
+	return counts
+}
+
+// isolateImport runs fn, turning a panic into a failed listing so the run goes on
+func isolateImport(ctx context.Context, listing *ExternalImovel, fn func(*ExternalImovel) (importOutcome, error)) (outcome importOutcome, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			importLogger(ctx).Error("Import of listing panicked", "external_id", listing.ID, "codigo", listing.Codigo,
+				"action", importFailed.String(), "panic", r, "stack", string(debug.Stack()))
+			outcome, err = importFailed, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(listing)
+}
+
+// relationLocks serializes the imports of properties sharing a relation (empreendimento, corretor,
+// organizacao, prices) so concurrent workers do not both create it. Keys are spread over a fixed
+// set of mutexes.
+type relationLocks struct {
+	stripes [64]sync.Mutex
+}
+
+// lock locks all keys at once and returns the function unlocking them. Stripes are taken in order,
+// so callers locking overlapping keys cannot deadlock.
+func (l *relationLocks) lock(keys ...string) func() {
+	taken := map[uint32]bool{}
+	stripes := make([]uint32, 0, len(keys))
+	for _, key := range keys {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		stripe := h.Sum32() % uint32(len(l.stripes))
+		if !taken[stripe] {
+			taken[stripe] = true
+			stripes = append(stripes, stripe)
+		}
+	}
+	sort.Slice(stripes, func(i, j int) bool { return stripes[i] < stripes[j] })
+
+	for _, stripe := range stripes {
+		l.stripes[stripe].Lock()
+	}
+	return func() {
+		for _, stripe := range stripes {
+			l.stripes[stripe].Unlock()
+		}
+	}
+}
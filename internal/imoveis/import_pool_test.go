@@ -0,0 +1,72 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPublishedProperties_Concurrent(t *testing.T) {
+	api := &fakeExternalAPI{
+		empreendimento: &ExternalEmpreendimento{ID: 900, Titulo: "Residencial Parque"},
+	}
+	for id := uint(1); id <= 12; id++ {
+		api.listings = append(api.listings, externalListing(id))
+	}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "12 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	// The empreendimento shared by every property is created once
+	var empreendimentos []Empreendimento
+	require.NoError(t, database.Find(&empreendimentos).Error)
+	require.Len(t, empreendimentos, 1)
+	var linked int64
+	require.NoError(t, database.Model(&Imovel{}).Where("empreendimento_id = ?", empreendimentos[0].ID).Count(&linked).Error)
+	assert.Equal(t, int64(12), linked)
+}
+
+func TestImportAll_IsolatesFailures(t *testing.T) {
+	importer := &importService{workers: 3}
+	listings := []ExternalImovel{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+
+	var last ImportProgress
+	failures := map[uint]string{}
+	hooks := importHooks{
+		progress: func(progress ImportProgress) { last = progress },
+		failed:   func(listing *ExternalImovel, err error) { failures[listing.ID] = err.Error() },
+	}
+	counts := importer.importAll(context.Background(), listings, hooks, func(listing *ExternalImovel) (importOutcome, error) {
+		switch listing.ID {
+		case 2:
+			panic("unexpected payload")
+		case 3:
+			return importFailed, errors.New("failed to fetch details: timeout")
+		case 4:
+			return importUnchanged, nil
+		}
+		return importCreated, nil
+	})
+
+	assert.Equal(t, importCounts{listed: 5, created: 2, unchanged: 1, failed: 2}, counts)
+	assert.Equal(t, ImportProgress{Total: 5, Processed: 5, Created: 2, Unchanged: 1, Failed: 2}, last)
+	assert.Equal(t, map[uint]string{2: "panic: unexpected payload", 3: "failed to fetch details: timeout"}, failures)
+}
+
+func TestImportAll_StopsOnCancel(t *testing.T) {
+	importer := &importService{workers: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	listings := make([]ExternalImovel, 10)
+
+	counts := importer.importAll(ctx, listings, importHooks{}, func(*ExternalImovel) (importOutcome, error) {
+		cancel()
+		return importUpdated, nil
+	})
+
+	assert.Less(t, counts.total(), len(listings))
+}
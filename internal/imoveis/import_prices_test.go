@@ -0,0 +1,83 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportListing_DeactivatedPrices(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("detaches a price no longer needed", func(t *testing.T) {
+		listing := externalListing(1)
+		api := &fakeExternalAPI{listings: []ExternalImovel{listing}}
+		importer, database := setupImportService(t, api, false)
+
+		_, err := importer.importListing(ctx, &listing)
+		require.NoError(t, err)
+		imported := findImported(t, database, "1")
+		require.NotZero(t, imported.PrecoVendaID)
+		precoVendaID := imported.PrecoVendaID
+
+		// The source turned the listing into a rental and deactivated its selling price
+		listing.Objetivo = "ALUGAR"
+		listing.PrecoVenda = &ExternalPrecoVenda{ID: 101, Preco: 450000, Ativo: false}
+		listing.PrecoAluguel = &ExternalPrecoAluguel{ID: 201, Preco: 2500, Ativo: true}
+		api.listings[0] = listing
+		outcome, err := importer.importListing(ctx, &listing)
+		require.NoError(t, err)
+		assert.Equal(t, importUpdated, outcome)
+
+		imported = findImported(t, database, "1")
+		assert.Equal(t, "ALUGAR", imported.Objetivo)
+		assert.Zero(t, imported.PrecoVendaID)
+		assert.NotZero(t, imported.PrecoAluguelID)
+
+		var precoVenda PrecoVenda
+		require.NoError(t, database.First(&precoVenda, precoVendaID).Error)
+		assert.False(t, precoVenda.Ativo)
+		assert.Equal(t, 450000.0, precoVenda.Preco)
+	})
+
+	t.Run("keeps the price the objetivo requires", func(t *testing.T) {
+		listing := externalListing(2)
+		api := &fakeExternalAPI{listings: []ExternalImovel{listing}}
+		importer, database := setupImportService(t, api, false)
+
+		_, err := importer.importListing(ctx, &listing)
+		require.NoError(t, err)
+
+		listing.PrecoVenda = &ExternalPrecoVenda{ID: 102, Preco: 450000, Ativo: false}
+		api.listings[0] = listing
+		_, err = importer.importListing(ctx, &listing)
+		require.NoError(t, err)
+
+		imported := findImported(t, database, "2")
+		require.NotZero(t, imported.PrecoVendaID)
+		var precoVenda PrecoVenda
+		require.NoError(t, database.First(&precoVenda, imported.PrecoVendaID).Error)
+		assert.False(t, precoVenda.Ativo)
+	})
+
+	t.Run("never imported", func(t *testing.T) {
+		listing := externalListing(3)
+		listing.Objetivo = "ALUGAR"
+		listing.PrecoVenda = &ExternalPrecoVenda{ID: 103, Preco: 450000, Ativo: false}
+		listing.PrecoAluguel = &ExternalPrecoAluguel{ID: 203, Preco: 2500, Ativo: true}
+		api := &fakeExternalAPI{listings: []ExternalImovel{listing}}
+		importer, database := setupImportService(t, api, false)
+
+		outcome, err := importer.importListing(ctx, &listing)
+		require.NoError(t, err)
+		assert.Equal(t, importCreated, outcome)
+
+		imported := findImported(t, database, "3")
+		assert.Zero(t, imported.PrecoVendaID)
+		var count int64
+		require.NoError(t, database.Model(&PrecoVenda{}).Where("id_integracao = ?", "103").Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
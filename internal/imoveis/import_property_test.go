@@ -0,0 +1,37 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProperty(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2)}}
+	importer, database := setupImportService(t, api, true)
+
+	result, err := importer.ImportProperty(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), result.ExternalID)
+	assert.Equal(t, ImportOutcomeCreated, result.Outcome)
+	assert.Equal(t, "Apartamento 2", result.Imovel.Titulo)
+	assert.Equal(t, []uint{2}, api.detailRequests)
+	assert.Empty(t, api.listRequests)
+
+	// Only the requested listing is imported
+	var count int64
+	require.NoError(t, database.Model(&Imovel{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	api.listings[1].Titulo = "Apartamento reformado"
+	result, err = importer.ImportProperty(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeUpdated, result.Outcome)
+	assert.Equal(t, "Apartamento reformado", findImported(t, database, "2").Titulo)
+
+	_, err = importer.ImportProperty(ctx, 99)
+	assert.ErrorIs(t, err, ErrExternalListingNotFound)
+}
@@ -0,0 +1,195 @@
+package imoveis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+// Statuses of a quarantined listing
+const (
+	QuarantinePending   = "PENDING"
+	QuarantineReleased  = "RELEASED"  // imported once the source fixed it
+	QuarantineDiscarded = "DISCARDED" // dismissed by hand; the same payload is not held again
+)
+
+var (
+	// ErrQuarantineNotFound is returned when a quarantined listing does not exist
+	ErrQuarantineNotFound = errors.New("quarantined listing not found")
+	// ErrQuarantineResolved is returned when retrying or discarding an entry no longer pending
+	ErrQuarantineResolved = errors.New("quarantined listing already released or discarded")
+)
+
+// validateListing returns what about a listing would make a half-broken property, by field, after
+// its values were mapped
+func validateListing(ext *ExternalDetailedImovel) ImportViolations {
+	violations := ImportViolations{}
+	if strings.TrimSpace(ext.Codigo) == "" {
+		violations["codigo"] = "is required"
+	}
+	if ext.Metragem <= 0 {
+		violations["metragem"] = "must be greater than 0"
+	}
+	if !slices.Contains(validTipos, ext.Tipo) {
+		violations["tipo"] = fmt.Sprintf("unknown tipo %q", ext.Tipo)
+	}
+	if ext.Endereco.CEP != "" && !validCEP(ext.Endereco.CEP) {
+		violations["endereco.cep"] = "must have 8 digits"
+	}
+	return violations
+}
+
+// validCEP reports whether cep has 8 digits, ignoring the usual punctuation ("01001-000")
+func validCEP(cep string) bool {
+	digits := 0
+	for _, r := range cep {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '-' || r == '.' || r == ' ':
+		default:
+			return false
+		}
+	}
+	return digits == 8
+}
+
+// quarantine holds a listing that failed validation with its payload, refreshing the pending entry
+// of the listing if there is one. A payload discarded before is not held again.
+func (is *importService) quarantine(ctx context.Context, ext *ExternalDetailedImovel, payload []byte, violations ImportViolations) error {
+	sum := sha256.Sum256(payload)
+	checksum := hex.EncodeToString(sum[:])
+
+	entry, err := is.repo.FindLatestQuarantine(ctx, is.integrationSource, ext.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find quarantined listing: %w", err)
+	}
+	if entry != nil && entry.Status == QuarantineDiscarded && entry.Checksum == checksum {
+		return nil
+	}
+	if entry == nil || entry.Status != QuarantinePending {
+		entry = &ImportQuarantine{Source: is.integrationSource, ExternalID: ext.ID, Status: QuarantinePending}
+	}
+	entry.Codigo = ext.Codigo
+	entry.Violations = violations
+	entry.Payload = string(payload)
+	entry.Checksum = checksum
+	if err := is.repo.SaveImportQuarantine(ctx, entry); err != nil {
+		return fmt.Errorf("failed to quarantine listing: %w", err)
+	}
+	return nil
+}
+
+// ListImportQuarantine returns the quarantined listings, oldest first
+func (is *importService) ListImportQuarantine(ctx context.Context, query *ImportQuarantineListQuery) (*ImportQuarantineListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+	if query.Status == "" {
+		query.Status = ImportQuarantineStatusPending
+	}
+
+	entries, total, err := is.repo.ListImportQuarantine(ctx, query.Status, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve quarantined listings: %w", err)
+	}
+
+	results := make([]ImportQuarantineResponse, len(entries))
+	for i := range entries {
+		results[i] = *quarantineResponse(&entries[i])
+	}
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &ImportQuarantineListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// RetryImportQuarantine imports the listing of a pending entry again, for when the source fixed it
+// or the mappings changed. The entry is released when the listing now passes validation, and
+// refreshed otherwise.
+func (is *importService) RetryImportQuarantine(ctx context.Context, id uint) (*ImportQuarantineResponse, error) {
+	entry, err := is.pendingQuarantine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := is.ImportProperty(ctx, entry.ExternalID); err != nil {
+		return nil, err
+	}
+
+	entry, err = is.repo.FindImportQuarantine(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quarantined listing: %w", err)
+	}
+	return quarantineResponse(entry), nil
+}
+
+// DiscardImportQuarantine dismisses a pending entry; the listing is held again only once the
+// source changes it
+func (is *importService) DiscardImportQuarantine(ctx context.Context, id uint) (*ImportQuarantineResponse, error) {
+	entry, err := is.pendingQuarantine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvidoEm := time.Now().UTC()
+	entry.Status = QuarantineDiscarded
+	entry.ResolvidoEm = &resolvidoEm
+	if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+		entry.ResolvidoPor = &userID
+	}
+	if err := is.repo.SaveImportQuarantine(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to discard quarantined listing: %w", err)
+	}
+	return quarantineResponse(entry), nil
+}
+
+// pendingQuarantine returns a quarantined listing still waiting for review
+func (is *importService) pendingQuarantine(ctx context.Context, id uint) (*ImportQuarantine, error) {
+	entry, err := is.repo.FindImportQuarantine(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quarantined listing: %w", err)
+	}
+	if entry == nil {
+		return nil, ErrQuarantineNotFound
+	}
+	if entry.Status != QuarantinePending {
+		return nil, ErrQuarantineResolved
+	}
+	return entry, nil
+}
+
+func quarantineResponse(entry *ImportQuarantine) *ImportQuarantineResponse {
+	resp := &ImportQuarantineResponse{
+		ID:           entry.ID,
+		Source:       entry.Source,
+		ExternalID:   entry.ExternalID,
+		Codigo:       entry.Codigo,
+		Violations:   entry.Violations,
+		Status:       entry.Status,
+		ResolvidoEm:  entry.ResolvidoEm,
+		ResolvidoPor: entry.ResolvidoPor,
+		CreatedAt:    entry.CreatedAt,
+		UpdatedAt:    entry.UpdatedAt,
+	}
+	if json.Valid([]byte(entry.Payload)) {
+		resp.Payload = json.RawMessage(entry.Payload)
+	}
+	return resp
+}
@@ -0,0 +1,97 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateListing(t *testing.T) {
+	valid := ExternalDetailedImovel{Codigo: "EXT-001", Tipo: "APARTAMENTO", Metragem: 70, Endereco: ExternalEndereco{CEP: "01001-000"}}
+	assert.Empty(t, validateListing(&valid))
+
+	invalid := ExternalDetailedImovel{Codigo: " ", Tipo: "Loft", Endereco: ExternalEndereco{CEP: "0100-100"}}
+	assert.Equal(t, ImportViolations{
+		"codigo":       "is required",
+		"metragem":     "must be greater than 0",
+		"tipo":         `unknown tipo "Loft"`,
+		"endereco.cep": "must have 8 digits",
+	}, validateListing(&invalid))
+}
+
+func TestImportPublishedProperties_Quarantine(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}}
+	api.listings[1].Metragem = 0
+	api.listings[2].Tipo = "LOFT"
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed, 2 quarantined", result.String())
+	var count int64
+	require.NoError(t, database.Model(&Imovel{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	var run ImportRun
+	require.NoError(t, database.First(&run, result.RunID).Error)
+	assert.Equal(t, 2, run.Quarantined)
+
+	// Importing again refreshes the pending entries instead of adding more
+	_, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	pending, err := importer.ListImportQuarantine(ctx, &ImportQuarantineListQuery{})
+	require.NoError(t, err)
+	require.Len(t, pending.Results, 2)
+	// WHY: the worker pool quarantines the listings concurrently, so the entries have no fixed order
+	byExternalID := map[uint]ImportQuarantineResponse{}
+	for _, entry := range pending.Results {
+		byExternalID[entry.ExternalID] = entry
+	}
+	metragem, tipo := byExternalID[2], byExternalID[3]
+	require.NotZero(t, tipo.ID)
+	assert.Equal(t, uint(2), metragem.ExternalID)
+	assert.Equal(t, "EXT-002", metragem.Codigo)
+	assert.Equal(t, ImportViolations{"metragem": "must be greater than 0"}, metragem.Violations)
+	assert.Contains(t, string(metragem.Payload), `"codigo":"EXT-002"`)
+	assert.Equal(t, QuarantinePending, metragem.Status)
+
+	t.Run("retry releases the listing once the source fixes it", func(t *testing.T) {
+		api.listings[1].Metragem = 55
+		entry, err := importer.RetryImportQuarantine(ctx, metragem.ID)
+		require.NoError(t, err)
+		assert.Equal(t, QuarantineReleased, entry.Status)
+		assert.NotNil(t, entry.ResolvidoEm)
+		assert.Equal(t, 55.0, findImported(t, database, "2").Metragem)
+
+		_, err = importer.RetryImportQuarantine(ctx, metragem.ID)
+		assert.ErrorIs(t, err, ErrQuarantineResolved)
+	})
+
+	t.Run("a discarded payload is not held again", func(t *testing.T) {
+		entry, err := importer.DiscardImportQuarantine(ctx, tipo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, QuarantineDiscarded, entry.Status)
+
+		result, err := importer.ImportProperty(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, ImportOutcomeQuarantined, result.Outcome)
+		assert.Nil(t, result.Imovel)
+		pending, err := importer.ListImportQuarantine(ctx, &ImportQuarantineListQuery{})
+		require.NoError(t, err)
+		assert.Zero(t, pending.Total)
+
+		api.listings[2].Titulo = "Loft reformado"
+		_, err = importer.ImportProperty(ctx, 3)
+		require.NoError(t, err)
+		pending, err = importer.ListImportQuarantine(ctx, &ImportQuarantineListQuery{})
+		require.NoError(t, err)
+		require.Len(t, pending.Results, 1)
+		assert.NotEqual(t, tipo.ID, pending.Results[0].ID)
+
+		all, err := importer.ListImportQuarantine(ctx, &ImportQuarantineListQuery{Status: ImportQuarantineStatusAll})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), all.Total)
+	})
+}
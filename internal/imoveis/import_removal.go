@@ -0,0 +1,71 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// maxRemovedShare bounds the share of the imported properties a run handles as removed; a bigger
+// drop is more likely a broken list at the source than properties taken down
+const maxRemovedShare = 0.5
+
+// handleRemoved applies the removed policy to the imported properties missing from listings, the
+// complete published list of a full run. Properties that fail are recorded in run and counted as
+// failed.
+func (is *importService) handleRemoved(ctx context.Context, listings []ExternalImovel, run *ImportRun, counts *importCounts) error {
+	if is.removedPolicy == "" || is.removedPolicy == RemovedPolicyNone {
+		return nil
+	}
+
+	listed := make(map[string]bool, len(listings))
+	for _, listing := range listings {
+		listed[strconv.FormatUint(uint64(listing.ID), 10)] = true
+	}
+
+	imported, err := is.repo.ListImported(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read imported properties: %w", err)
+	}
+
+	var missing []int
+	for i, imovel := range imported {
+		if !listed[imovel.IdIntegracao] {
+			missing = append(missing, i)
+		}
+	}
+	if float64(len(missing)) > maxRemovedShare*float64(len(imported)) {
+		return fmt.Errorf("%d of %d imported properties are missing from the source; none were handled as removed", len(missing), len(imported))
+	}
+
+	// The changes are audited as made by the import
+	ctx = withPriceOrigin(ctx, PriceOriginImport)
+	for _, i := range missing {
+		imovel := imported[i]
+		if err := is.service.MarkRemovedFromSource(ctx, imovel.ID, is.removedPolicy); err != nil {
+			importLogger(ctx).Warn("Failed to handle property removed from the source", "external_id", imovel.IdIntegracao,
+				"codigo", imovel.Codigo, "action", ImportOutcomeRemoved, "error", err)
+			externalID, _ := strconv.ParseUint(imovel.IdIntegracao, 10, 64)
+			is.recordRunItem(ctx, run, &ExternalImovel{ID: uint(externalID), Codigo: imovel.Codigo},
+				fmt.Errorf("failed to handle removal from the source: %w", err))
+			counts.failed++
+			continue
+		}
+		counts.removed++
+	}
+	return nil
+}
+
+// restoreRemoved restores the property deleted by the removed policy when its listing comes back,
+// so the import updates it instead of colliding with its id_integracao. Reports whether there was one.
+func (is *importService) restoreRemoved(ctx context.Context, idIntegracao string) (bool, error) {
+	imovel, err := is.repo.FindRemovedFromSource(ctx, idIntegracao)
+	if err != nil || imovel == nil {
+		return false, err
+	}
+
+	if _, err := is.service.RestoreImovel(withPriceOrigin(ctx, PriceOriginImport), imovel.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
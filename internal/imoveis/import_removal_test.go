@@ -0,0 +1,143 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupRemovalImport imports four listings, publishes the properties and drops the fourth listing
+// from the source
+func setupRemovalImport(t *testing.T, policy string) (*importService, *gorm.DB, *fakeExternalAPI) {
+	t.Helper()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3), externalListing(4)}}
+	importer, database := setupImportService(t, api, false)
+	importer.removedPolicy = policy
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "4 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	require.NoError(t, database.Model(&Imovel{}).Where("1 = 1").
+		Updates(map[string]interface{}{"status": StatusPublicado, "published": true}).Error)
+
+	api.listings = api.listings[:3]
+	return importer, database, api
+}
+
+func findImported(t *testing.T, database *gorm.DB, idIntegracao string) Imovel {
+	t.Helper()
+	var imovel Imovel
+	require.NoError(t, database.Unscoped().Where("id_integracao = ?", idIntegracao).First(&imovel).Error)
+	return imovel
+}
+
+func TestImportPublishedProperties_RemovedArchive(t *testing.T) {
+	ctx := context.Background()
+	importer, database, api := setupRemovalImport(t, RemovedPolicyArchive)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 3 updated, 0 unchanged, 1 removed, 0 failed", result.String())
+
+	removed := findImported(t, database, "4")
+	assert.Equal(t, StatusArquivado, removed.Status)
+	assert.False(t, removed.Published)
+	assert.NotNil(t, removed.RemovidoOrigemEm)
+	assert.Nil(t, findImported(t, database, "3").RemovidoOrigemEm)
+
+	var audit ImovelAudit
+	require.NoError(t, database.Where("imovel_id = ? AND acao = ?", removed.ID, AuditAcaoUnpublish).First(&audit).Error)
+	assert.Equal(t, PriceOriginImport, audit.Origem)
+	assert.Equal(t, motivoRemovidoOrigem, audit.Motivo)
+
+	var run ImportRun
+	require.NoError(t, database.Order("id DESC").First(&run).Error)
+	assert.Equal(t, 1, run.Removed)
+
+	t.Run("already flagged properties are left alone", func(t *testing.T) {
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 3 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	})
+
+	t.Run("a listing back in the source clears the flag", func(t *testing.T) {
+		api.listings = append(api.listings, externalListing(4))
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 4 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+		returned := findImported(t, database, "4")
+		assert.Nil(t, returned.RemovidoOrigemEm)
+		assert.Equal(t, StatusArquivado, returned.Status)
+	})
+}
+
+func TestImportPublishedProperties_RemovedDelete(t *testing.T) {
+	ctx := context.Background()
+	importer, database, api := setupRemovalImport(t, RemovedPolicyDelete)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 3 updated, 0 unchanged, 1 removed, 0 failed", result.String())
+	assert.True(t, findImported(t, database, "4").DeletedAt.Valid)
+
+	// The deleted property is restored instead of colliding with the returning listing
+	api.listings = append(api.listings, externalListing(4))
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 4 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	returned := findImported(t, database, "4")
+	assert.False(t, returned.DeletedAt.Valid)
+	assert.Nil(t, returned.RemovidoOrigemEm)
+}
+
+func TestImportPublishedProperties_RemovedReview(t *testing.T) {
+	ctx := context.Background()
+	importer, database, _ := setupRemovalImport(t, RemovedPolicyReview)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 3 updated, 0 unchanged, 1 removed, 0 failed", result.String())
+
+	removed := findImported(t, database, "4")
+	assert.Equal(t, StatusPublicado, removed.Status)
+	assert.NotNil(t, removed.RemovidoOrigemEm)
+
+	flagged := true
+	list, err := importer.service.ListImoveis(ctx, &ImovelListQuery{Page: 1, Limit: 10, Order: "desc", RemovidoOrigem: &flagged})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, removed.ID, list.Results[0].ID)
+	assert.NotNil(t, list.Results[0].RemovidoOrigemEm)
+}
+
+func TestImportPublishedProperties_RemovedSkipped(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("when most properties are missing", func(t *testing.T) {
+		importer, database, api := setupRemovalImport(t, RemovedPolicyArchive)
+		api.listings = api.listings[:1]
+
+		_, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		assert.EqualError(t, err, "3 of 4 imported properties are missing from the source; none were handled as removed")
+
+		var flagged int64
+		require.NoError(t, database.Model(&Imovel{}).Where("removido_origem_em IS NOT NULL").Count(&flagged).Error)
+		assert.Zero(t, flagged)
+	})
+
+	t.Run("on incremental runs", func(t *testing.T) {
+		importer, database, _ := setupRemovalImport(t, RemovedPolicyArchive)
+		importer.incremental = true
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 0 updated, 3 unchanged, 0 removed, 0 failed", result.String())
+		assert.Equal(t, StatusPublicado, findImported(t, database, "4").Status)
+	})
+}
@@ -0,0 +1,696 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportRepository is the storage the importer writes the listings of the external API to.
+// Records of the source are matched by their id_integracao; Upsert methods create the record or
+// update the one already imported and fill the argument with what was stored. Find methods
+// return nil when there is no such record.
+type ImportRepository interface {
+	// Transaction runs fn in a database transaction; calls made with the context passed to fn join it
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+
+	// Imported properties
+	ImportChecksums(ctx context.Context, syncedSince *time.Time) (map[string]string, error)
+	SaveImportChecksum(ctx context.Context, imovelID uint, checksum string) error
+	ListImported(ctx context.Context) ([]ImportedImovel, error)
+	FindRemovedFromSource(ctx context.Context, idIntegracao string) (*Imovel, error)
+
+	// Runs
+	FindSyncState(ctx context.Context, source string) (*ImportSyncState, error)
+	SaveSyncState(ctx context.Context, state *ImportSyncState) error
+	CreateImportRun(ctx context.Context, run *ImportRun) error
+	SaveImportRun(ctx context.Context, run *ImportRun) error
+	SaveImportRunCheckpoint(ctx context.Context, runID uint, checkpoint int, externalID uint) error
+	CreateImportRunItem(ctx context.Context, item *ImportRunItem) error
+	ExistsImportRun(ctx context.Context, id uint) (bool, error)
+	FindImportRun(ctx context.Context, id uint) (*ImportRun, error)
+	ListImportRuns(ctx context.Context, page, limit int) ([]ImportRun, int64, error)
+	ListImportRunItems(ctx context.Context, runID uint, page, limit int) ([]ImportRunItem, int64, error)
+
+	// Fields edited by hand and the conflicts of the source with them
+	UnlockField(ctx context.Context, imovelID uint, campo string) error
+	FindImportConflict(ctx context.Context, id uint) (*ImportConflict, error)
+	FindLatestImportConflict(ctx context.Context, imovelID uint, campo string) (*ImportConflict, error)
+	SaveImportConflict(ctx context.Context, conflict *ImportConflict) error
+	ListImportConflicts(ctx context.Context, status string, page, limit int) ([]ImportConflict, int64, error)
+
+	// Listings held in quarantine for failing validation
+	FindImportQuarantine(ctx context.Context, id uint) (*ImportQuarantine, error)
+	FindLatestQuarantine(ctx context.Context, source string, externalID uint) (*ImportQuarantine, error)
+	SaveImportQuarantine(ctx context.Context, entry *ImportQuarantine) error
+	ReleaseQuarantine(ctx context.Context, source string, externalID uint) error
+	ListImportQuarantine(ctx context.Context, status string, page, limit int) ([]ImportQuarantine, int64, error)
+
+	// Relations
+	UpsertEmpreendimento(ctx context.Context, empreendimento *Empreendimento) error
+	UpsertPrecoVenda(ctx context.Context, preco *PrecoVenda) error
+	UpsertPrecoAluguel(ctx context.Context, preco *PrecoAluguel) error
+	DeactivatePrecoVenda(ctx context.Context, idIntegracao string) (uint, error)
+	DeactivatePrecoAluguel(ctx context.Context, idIntegracao string) (uint, error)
+	UpsertOrganizacao(ctx context.Context, org *Organizacao) error
+	UpsertCorretor(ctx context.Context, corretor *CorretorPrincipal) error
+	SetCorretorFoto(ctx context.Context, corretorID, anexoID uint) error
+	PatchEndereco(ctx context.Context, id uint, updates map[string]interface{}) error
+	SetEmpreendimentoEndereco(ctx context.Context, empreendimentoID, enderecoID uint) error
+
+	// Torres and plantas of empreendimentos
+	ListImportedTorres(ctx context.Context, empreendimentoID uint) ([]Torres, error)
+	CreateTorre(ctx context.Context, torre *Torres) error
+	PatchTorre(ctx context.Context, id uint, updates map[string]interface{}) error
+	DeleteTorre(ctx context.Context, id uint) error
+	ListImportedPlantas(ctx context.Context, empreendimentoID uint) ([]Plantas, error)
+	CreatePlanta(ctx context.Context, planta *Plantas) error
+	PatchPlanta(ctx context.Context, id uint, updates map[string]interface{}) error
+	DeletePlanta(ctx context.Context, id uint) error
+
+	// Caracteristicas of empreendimentos
+	ListEmpreendimentoCaracteristicas(ctx context.Context, empreendimentoID uint) ([]Caracteristica, error)
+	FindCaracteristicaByNome(ctx context.Context, nome string) (*Caracteristica, error)
+	CreateCaracteristica(ctx context.Context, caracteristica *Caracteristica) error
+	LinkEmpreendimentoCaracteristicas(ctx context.Context, empreendimentoID uint, caracteristicas []Caracteristica) error
+
+	// Anexos
+	FindAnexoByID(ctx context.Context, id uint) (*Anexo, error)
+	ListImportedAnexos(ctx context.Context, owner AnexoOwner, ownerID uint) ([]Anexo, error)
+	ListStoredAnexosByURL(ctx context.Context, externalURLs []string) ([]Anexo, error)
+	FindStoredAnexoByHash(ctx context.Context, hash string) (*Anexo, error)
+	CreateAnexo(ctx context.Context, anexo *Anexo) error
+	PatchAnexo(ctx context.Context, id uint, updates map[string]interface{}) error
+	DeleteAnexo(ctx context.Context, id uint) error
+}
+
+// AnexoOwner is the kind of record imported anexos belong to
+type AnexoOwner string
+
+// Records imported anexos belong to
+const (
+	AnexoOwnerImovel AnexoOwner = "imovel_id"
+	AnexoOwnerPlanta AnexoOwner = "planta_id"
+)
+
+// ImportedImovel identifies a property imported from the external API
+type ImportedImovel struct {
+	ID           uint
+	IdIntegracao string
+	Codigo       string
+}
+
+// importedScope selects the properties imported from the source and still listed there
+const importedScope = "id_integracao <> '' AND import_checksum IS NOT NULL AND import_checksum <> '' AND removido_origem_em IS NULL"
+
+// ImportChecksums maps the id_integracao of the imported properties to their listing checksum,
+// leaving out those flagged as removed from the source. With syncedSince, only the properties
+// written since then are mapped.
+func (r *repository) ImportChecksums(ctx context.Context, syncedSince *time.Time) (map[string]string, error) {
+	var rows []struct {
+		IdIntegracao   string
+		ImportChecksum string
+	}
+	db := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select("id_integracao, import_checksum").
+		Where(importedScope)
+	if syncedSince != nil {
+		db = db.Where("updated_at >= ?", *syncedSince)
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	checksums := make(map[string]string, len(rows))
+	for _, row := range rows {
+		checksums[row.IdIntegracao] = row.ImportChecksum
+	}
+	return checksums, nil
+}
+
+// SaveImportChecksum stores the checksum of a listing without touching updated_at or version,
+// which belong to the property edits, and clears the flag of a property removed from the source
+func (r *repository) SaveImportChecksum(ctx context.Context, imovelID uint, checksum string) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", imovelID).
+		UpdateColumns(map[string]interface{}{"import_checksum": checksum, "removido_origem_em": nil}).Error
+}
+
+// ListImported returns the properties imported from the source and not flagged as removed
+func (r *repository) ListImported(ctx context.Context) ([]ImportedImovel, error) {
+	var imported []ImportedImovel
+	err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select("id, id_integracao, codigo").
+		Where(importedScope).
+		Scan(&imported).Error
+	return imported, err
+}
+
+// FindRemovedFromSource returns the property deleted when its listing was removed from the source
+func (r *repository) FindRemovedFromSource(ctx context.Context, idIntegracao string) (*Imovel, error) {
+	var imovel Imovel
+	result := r.getDB(ctx).WithContext(ctx).Unscoped().Select("id").
+		Where("id_integracao = ? AND deleted_at IS NOT NULL AND removido_origem_em IS NOT NULL", idIntegracao).
+		Limit(1).Find(&imovel)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &imovel, nil
+}
+
+// FindSyncState returns the incremental sync state of an integration source
+func (r *repository) FindSyncState(ctx context.Context, source string) (*ImportSyncState, error) {
+	var state ImportSyncState
+	err := r.getDB(ctx).WithContext(ctx).Where("source = ?", source).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveSyncState creates or replaces the sync state of its integration source
+func (r *repository) SaveSyncState(ctx context.Context, state *ImportSyncState) error {
+	return r.getDB(ctx).WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"synced_at", "updated_at"}),
+	}).Create(state).Error
+}
+
+// CreateImportRun records the start of an import run
+func (r *repository) CreateImportRun(ctx context.Context, run *ImportRun) error {
+	return r.getDB(ctx).WithContext(ctx).Create(run).Error
+}
+
+// SaveImportRun records the progress or outcome of an import run
+func (r *repository) SaveImportRun(ctx context.Context, run *ImportRun) error {
+	return r.getDB(ctx).WithContext(ctx).Save(run).Error
+}
+
+// SaveImportRunCheckpoint records how far a running import run got, leaving the rest of the run
+// as it is
+func (r *repository) SaveImportRunCheckpoint(ctx context.Context, runID uint, checkpoint int, externalID uint) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&ImportRun{}).Where("id = ?", runID).
+		UpdateColumns(map[string]interface{}{"checkpoint": checkpoint, "checkpoint_external_id": externalID}).Error
+}
+
+// CreateImportRunItem records a listing that failed to import
+func (r *repository) CreateImportRunItem(ctx context.Context, item *ImportRunItem) error {
+	return r.getDB(ctx).WithContext(ctx).Create(item).Error
+}
+
+// ExistsImportRun reports whether an import run exists
+func (r *repository) ExistsImportRun(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.getDB(ctx).WithContext(ctx).Model(&ImportRun{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// FindImportRun returns an import run
+func (r *repository) FindImportRun(ctx context.Context, id uint) (*ImportRun, error) {
+	var run ImportRun
+	result := r.getDB(ctx).WithContext(ctx).Limit(1).Find(&run, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+// ListImportRuns returns a page of the import runs, newest first, with their total
+func (r *repository) ListImportRuns(ctx context.Context, page, limit int) ([]ImportRun, int64, error) {
+	var runs []ImportRun
+	var total int64
+	db := r.getDB(ctx).WithContext(ctx).Model(&ImportRun{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// ListImportRunItems returns a page of the listings that failed in an import run, in the order
+// they failed, with their total
+func (r *repository) ListImportRunItems(ctx context.Context, runID uint, page, limit int) ([]ImportRunItem, int64, error) {
+	var items []ImportRunItem
+	var total int64
+	db := r.getDB(ctx).WithContext(ctx).Model(&ImportRunItem{}).Where("run_id = ?", runID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id").Offset((page - 1) * limit).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// UnlockField removes campo from the locked fields of a property
+func (r *repository) UnlockField(ctx context.Context, imovelID uint, campo string) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var imovel Imovel
+	if err := db.Select("id", "locked_fields").First(&imovel, imovelID).Error; err != nil {
+		return err
+	}
+	return db.Model(&Imovel{}).Where("id = ?", imovelID).
+		UpdateColumn("locked_fields", imovel.LockedFields.without(campo)).Error
+}
+
+// FindImportConflict returns an import conflict by ID
+func (r *repository) FindImportConflict(ctx context.Context, id uint) (*ImportConflict, error) {
+	var conflict ImportConflict
+	result := r.getDB(ctx).WithContext(ctx).Limit(1).Find(&conflict, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &conflict, nil
+}
+
+// FindLatestImportConflict returns the last conflict recorded for a field of a property, pending
+// or not
+func (r *repository) FindLatestImportConflict(ctx context.Context, imovelID uint, campo string) (*ImportConflict, error) {
+	var conflict ImportConflict
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("imovel_id = ? AND campo = ?", imovelID, campo).
+		Order("id DESC").Limit(1).Find(&conflict)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &conflict, nil
+}
+
+// SaveImportConflict records a new import conflict or the changes to one
+func (r *repository) SaveImportConflict(ctx context.Context, conflict *ImportConflict) error {
+	return r.getDB(ctx).WithContext(ctx).Save(conflict).Error
+}
+
+// ListImportConflicts returns a page of the import conflicts with the given status (pending,
+// resolved or all), oldest first, with their total
+func (r *repository) ListImportConflicts(ctx context.Context, status string, page, limit int) ([]ImportConflict, int64, error) {
+	var conflicts []ImportConflict
+	var total int64
+	db := r.getDB(ctx).WithContext(ctx).Model(&ImportConflict{})
+	switch status {
+	case ImportConflictStatusPending:
+		db = db.Where("resolvido_em IS NULL")
+	case ImportConflictStatusResolved:
+		db = db.Where("resolvido_em IS NOT NULL")
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id").Offset((page - 1) * limit).Limit(limit).Find(&conflicts).Error; err != nil {
+		return nil, 0, err
+	}
+	return conflicts, total, nil
+}
+
+// FindImportQuarantine returns a quarantined listing by ID
+func (r *repository) FindImportQuarantine(ctx context.Context, id uint) (*ImportQuarantine, error) {
+	var entry ImportQuarantine
+	result := r.getDB(ctx).WithContext(ctx).Limit(1).Find(&entry, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// FindLatestQuarantine returns the last entry recorded for a listing of the source, whatever its
+// status
+func (r *repository) FindLatestQuarantine(ctx context.Context, source string, externalID uint) (*ImportQuarantine, error) {
+	var entry ImportQuarantine
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("source = ? AND external_id = ?", source, externalID).
+		Order("id DESC").Limit(1).Find(&entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// SaveImportQuarantine records a new quarantined listing or the changes to one
+func (r *repository) SaveImportQuarantine(ctx context.Context, entry *ImportQuarantine) error {
+	return r.getDB(ctx).WithContext(ctx).Save(entry).Error
+}
+
+// ReleaseQuarantine marks the pending entry of a listing released, once it was imported
+func (r *repository) ReleaseQuarantine(ctx context.Context, source string, externalID uint) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&ImportQuarantine{}).
+		Where("source = ? AND external_id = ? AND status = ?", source, externalID, QuarantinePending).
+		Updates(map[string]interface{}{"status": QuarantineReleased, "resolvido_em": time.Now().UTC()}).Error
+}
+
+// ListImportQuarantine returns a page of the quarantined listings with the given status (pending,
+// released, discarded or all), oldest first, with their total
+func (r *repository) ListImportQuarantine(ctx context.Context, status string, page, limit int) ([]ImportQuarantine, int64, error) {
+	var entries []ImportQuarantine
+	var total int64
+	db := r.getDB(ctx).WithContext(ctx).Model(&ImportQuarantine{})
+	if status != ImportQuarantineStatusAll {
+		db = db.Where("status = ?", strings.ToUpper(status))
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id").Offset((page - 1) * limit).Limit(limit).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// UpsertEmpreendimento creates or updates an empreendimento by id_integracao. Its dates and
+// endereço are left alone, and an empty finalidade keeps the current one.
+func (r *repository) UpsertEmpreendimento(ctx context.Context, empreendimento *Empreendimento) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var existing Empreendimento
+	result := db.Where("id_integracao = ?", empreendimento.IdIntegracao).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Empty dates are no valid date, and the endereço is linked apart
+		return db.Omit("DataEntrega", "EtapaLancamento", "EnderecoID").Create(empreendimento).Error
+	}
+
+	existing.Titulo = empreendimento.Titulo
+	existing.Descricao = empreendimento.Descricao
+	existing.Tipo = empreendimento.Tipo
+	existing.Status = empreendimento.Status
+	existing.Localizacao = empreendimento.Localizacao
+	if empreendimento.Finalidade != "" {
+		existing.Finalidade = empreendimento.Finalidade
+	}
+	if err := db.Model(&existing).
+		Select("titulo", "descricao", "tipo", "status", "localizacao", "finalidade").
+		Updates(&existing).Error; err != nil {
+		return err
+	}
+	*empreendimento = existing
+	return nil
+}
+
+// UpsertPrecoVenda creates or updates a selling price by id_integracao; the pacote fields are
+// left alone
+func (r *repository) UpsertPrecoVenda(ctx context.Context, preco *PrecoVenda) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var existing PrecoVenda
+	result := db.Where("id_integracao = ?", preco.IdIntegracao).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return db.Create(preco).Error
+	}
+
+	existing.Preco = preco.Preco
+	existing.AceitaFinanciamentoBancario = preco.AceitaFinanciamentoBancario
+	existing.AceitaFinanciamentoDireto = preco.AceitaFinanciamentoDireto
+	existing.AceitaPermuta = preco.AceitaPermuta
+	existing.AceitaCartaDeCredito = preco.AceitaCartaDeCredito
+	existing.AceitaFGTS = preco.AceitaFGTS
+	existing.Ativo = preco.Ativo
+	if err := db.Save(&existing).Error; err != nil {
+		return err
+	}
+	*preco = existing
+	return nil
+}
+
+// UpsertPrecoAluguel creates or updates a rental price by id_integracao
+func (r *repository) UpsertPrecoAluguel(ctx context.Context, preco *PrecoAluguel) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var existing PrecoAluguel
+	result := db.Where("id_integracao = ?", preco.IdIntegracao).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return db.Create(preco).Error
+	}
+
+	existing.Preco = preco.Preco
+	existing.AceitaFiador = preco.AceitaFiador
+	existing.Ativo = preco.Ativo
+	if err := db.Save(&existing).Error; err != nil {
+		return err
+	}
+	*preco = existing
+	return nil
+}
+
+// DeactivatePrecoVenda flags the selling price imported with idIntegracao as inactive and returns
+// its ID, or 0 when it was never imported
+func (r *repository) DeactivatePrecoVenda(ctx context.Context, idIntegracao string) (uint, error) {
+	return deactivatePreco(r.getDB(ctx).WithContext(ctx), &PrecoVenda{}, idIntegracao)
+}
+
+// DeactivatePrecoAluguel flags the rental price imported with idIntegracao as inactive and returns
+// its ID, or 0 when it was never imported
+func (r *repository) DeactivatePrecoAluguel(ctx context.Context, idIntegracao string) (uint, error) {
+	return deactivatePreco(r.getDB(ctx).WithContext(ctx), &PrecoAluguel{}, idIntegracao)
+}
+
+func deactivatePreco(db *gorm.DB, model interface{}, idIntegracao string) (uint, error) {
+	var ids []uint
+	if err := db.Model(model).Where("id_integracao = ?", idIntegracao).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := db.Model(model).Where("id = ?", ids[0]).Update("ativo", false).Error; err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// UpsertOrganizacao creates or updates an organizacao by name, which the source has no ID for
+func (r *repository) UpsertOrganizacao(ctx context.Context, org *Organizacao) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var existing Organizacao
+	result := db.Where("nome = ?", org.Nome).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return db.Create(org).Error
+	}
+
+	if existing.Perfil != org.Perfil {
+		existing.Perfil = org.Perfil
+		if err := db.Save(&existing).Error; err != nil {
+			return err
+		}
+	}
+	*org = existing
+	return nil
+}
+
+// UpsertCorretor creates or updates a corretor principal by id_integracao. The photo is linked
+// apart with SetCorretorFoto; idiomas and bairros are only set on creation, and a zero
+// organizacao keeps the current one.
+func (r *repository) UpsertCorretor(ctx context.Context, corretor *CorretorPrincipal) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	var existing CorretorPrincipal
+	result := db.Where("id_integracao = ?", corretor.IdIntegracao).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// A zero FotoID would break its foreign key
+		return db.Omit("FotoID").Create(corretor).Error
+	}
+
+	changed := existing.Nome != corretor.Nome || existing.Email != corretor.Email || existing.Whatsapp != corretor.Whatsapp ||
+		(corretor.OrganizacaoID != 0 && existing.OrganizacaoID != corretor.OrganizacaoID)
+	if changed {
+		existing.Nome = corretor.Nome
+		existing.Email = corretor.Email
+		existing.Whatsapp = corretor.Whatsapp
+		if corretor.OrganizacaoID != 0 {
+			existing.OrganizacaoID = corretor.OrganizacaoID
+		}
+		if err := db.Omit("FotoID").Save(&existing).Error; err != nil {
+			return err
+		}
+	}
+	*corretor = existing
+	return nil
+}
+
+// SetCorretorFoto links an anexo as the photo of a corretor principal
+func (r *repository) SetCorretorFoto(ctx context.Context, corretorID, anexoID uint) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&CorretorPrincipal{}).
+		Where("id = ?", corretorID).
+		Update("foto_id", anexoID).Error
+}
+
+// PatchEndereco updates the given columns of an endereço
+func (r *repository) PatchEndereco(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Endereco{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// SetEmpreendimentoEndereco links an endereço to an empreendimento
+func (r *repository) SetEmpreendimentoEndereco(ctx context.Context, empreendimentoID, enderecoID uint) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Empreendimento{}).
+		Where("id = ?", empreendimentoID).
+		Update("endereco_id", enderecoID).Error
+}
+
+// ListImportedTorres returns the torres of an empreendimento imported from the source
+func (r *repository) ListImportedTorres(ctx context.Context, empreendimentoID uint) ([]Torres, error) {
+	var torres []Torres
+	err := r.getDB(ctx).WithContext(ctx).
+		Where("empreendimento_id = ? AND id_integracao <> ''", empreendimentoID).
+		Find(&torres).Error
+	return torres, err
+}
+
+// CreateTorre creates a torre
+func (r *repository) CreateTorre(ctx context.Context, torre *Torres) error {
+	return r.getDB(ctx).WithContext(ctx).Create(torre).Error
+}
+
+// PatchTorre updates the given columns of a torre
+func (r *repository) PatchTorre(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Torres{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteTorre deletes a torre
+func (r *repository) DeleteTorre(ctx context.Context, id uint) error {
+	return r.getDB(ctx).WithContext(ctx).Delete(&Torres{}, id).Error
+}
+
+// ListImportedPlantas returns the plantas of an empreendimento imported from the source
+func (r *repository) ListImportedPlantas(ctx context.Context, empreendimentoID uint) ([]Plantas, error) {
+	var plantas []Plantas
+	err := r.getDB(ctx).WithContext(ctx).
+		Where("empreendimento_id = ? AND id_integracao <> ''", empreendimentoID).
+		Find(&plantas).Error
+	return plantas, err
+}
+
+// CreatePlanta creates a planta
+func (r *repository) CreatePlanta(ctx context.Context, planta *Plantas) error {
+	return r.getDB(ctx).WithContext(ctx).Create(planta).Error
+}
+
+// PatchPlanta updates the given columns of a planta
+func (r *repository) PatchPlanta(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Plantas{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeletePlanta deletes a planta with its anexos
+func (r *repository) DeletePlanta(ctx context.Context, id uint) error {
+	db := r.getDB(ctx).WithContext(ctx)
+	if err := db.Where("planta_id = ?", id).Delete(&Anexo{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(&Plantas{}, id).Error
+}
+
+// ListEmpreendimentoCaracteristicas returns the caracteristicas linked to an empreendimento
+func (r *repository) ListEmpreendimentoCaracteristicas(ctx context.Context, empreendimentoID uint) ([]Caracteristica, error) {
+	var caracteristicas []Caracteristica
+	err := r.getDB(ctx).WithContext(ctx).Model(&Empreendimento{ID: empreendimentoID}).
+		Association("Caracteristicas").Find(&caracteristicas)
+	return caracteristicas, err
+}
+
+// FindCaracteristicaByNome returns the oldest caracteristica of the catalog with a name,
+// regardless of case
+func (r *repository) FindCaracteristicaByNome(ctx context.Context, nome string) (*Caracteristica, error) {
+	var caracteristica Caracteristica
+	result := r.getDB(ctx).WithContext(ctx).Where("LOWER(nome) = ?", strings.ToLower(nome)).Order("id").Limit(1).Find(&caracteristica)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &caracteristica, nil
+}
+
+// CreateCaracteristica adds a caracteristica to the catalog
+func (r *repository) CreateCaracteristica(ctx context.Context, caracteristica *Caracteristica) error {
+	return r.getDB(ctx).WithContext(ctx).Create(caracteristica).Error
+}
+
+// LinkEmpreendimentoCaracteristicas links caracteristicas of the catalog to an empreendimento,
+// keeping the ones it has
+func (r *repository) LinkEmpreendimentoCaracteristicas(ctx context.Context, empreendimentoID uint, caracteristicas []Caracteristica) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Empreendimento{ID: empreendimentoID}).Omit("Caracteristicas.*").
+		Association("Caracteristicas").Append(&caracteristicas)
+}
+
+// ListImportedAnexos returns the anexos of a record imported from the source, in the order they
+// were created; those uploaded by hand are left out
+func (r *repository) ListImportedAnexos(ctx context.Context, owner AnexoOwner, ownerID uint) ([]Anexo, error) {
+	var anexos []Anexo
+	err := r.getDB(ctx).WithContext(ctx).
+		Where(string(owner)+" = ? AND (external_url <> '' OR is_external_url = ?)", ownerID, true).
+		Order("id").Find(&anexos).Error
+	return anexos, err
+}
+
+// ListStoredAnexosByURL returns the anexos, deleted or not, whose image was downloaded from one of
+// the external URLs
+func (r *repository) ListStoredAnexosByURL(ctx context.Context, externalURLs []string) ([]Anexo, error) {
+	var anexos []Anexo
+	err := r.getDB(ctx).WithContext(ctx).Unscoped().
+		Where("external_url IN ? AND content_hash <> '' AND path <> ''", externalURLs).
+		Find(&anexos).Error
+	return anexos, err
+}
+
+// FindStoredAnexoByHash returns an anexo, deleted or not, stored with the given content hash
+func (r *repository) FindStoredAnexoByHash(ctx context.Context, hash string) (*Anexo, error) {
+	var anexo Anexo
+	result := r.getDB(ctx).WithContext(ctx).Unscoped().Where("content_hash = ? AND path <> ''", hash).Limit(1).Find(&anexo)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &anexo, nil
+}
+
+// CreateAnexo creates an anexo linked to whatever records it sets
+func (r *repository) CreateAnexo(ctx context.Context, anexo *Anexo) error {
+	return r.getDB(ctx).WithContext(ctx).Create(anexo).Error
+}
+
+// PatchAnexo updates the given columns of an anexo
+func (r *repository) PatchAnexo(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Anexo{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteAnexo deletes an anexo
+func (r *repository) DeleteAnexo(ctx context.Context, id uint) error {
+	return r.getDB(ctx).WithContext(ctx).Delete(&Anexo{}, id).Error
+}
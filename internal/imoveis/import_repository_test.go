@@ -0,0 +1,79 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportRepository_Upserts(t *testing.T) {
+	ctx := context.Background()
+	_, database := setupCreateService(t)
+	repo := NewRepository(database)
+
+	t.Run("preco venda keeps the pacote set by hand", func(t *testing.T) {
+		preco := &PrecoVenda{IdIntegracao: "10", Preco: 300000, Ativo: true}
+		require.NoError(t, repo.UpsertPrecoVenda(ctx, preco))
+		require.NoError(t, database.Model(preco).Update("pacote_titulo", "Lançamento").Error)
+
+		again := &PrecoVenda{IdIntegracao: "10", Preco: 320000, AceitaFGTS: true, Ativo: true}
+		require.NoError(t, repo.UpsertPrecoVenda(ctx, again))
+		assert.Equal(t, preco.ID, again.ID)
+		assert.Equal(t, 320000.0, again.Preco)
+		assert.True(t, again.AceitaFGTS)
+		assert.Equal(t, "Lançamento", again.PacoteTitulo)
+	})
+
+	t.Run("empreendimento keeps its finalidade when the source has none", func(t *testing.T) {
+		empreendimento := &Empreendimento{IdIntegracao: "20", Titulo: "Jardins", Finalidade: "RESIDENTIAL"}
+		require.NoError(t, repo.UpsertEmpreendimento(ctx, empreendimento))
+
+		again := &Empreendimento{IdIntegracao: "20", Titulo: "Jardins II"}
+		require.NoError(t, repo.UpsertEmpreendimento(ctx, again))
+		assert.Equal(t, empreendimento.ID, again.ID)
+		assert.Equal(t, "RESIDENTIAL", again.Finalidade)
+
+		var stored Empreendimento
+		require.NoError(t, database.First(&stored, empreendimento.ID).Error)
+		assert.Equal(t, "Jardins II", stored.Titulo)
+		assert.Equal(t, "RESIDENTIAL", stored.Finalidade)
+	})
+
+	t.Run("organizacao is matched by name", func(t *testing.T) {
+		org := &Organizacao{Nome: "Imobiliária Centro", Perfil: "IMOBILIARIA"}
+		require.NoError(t, repo.UpsertOrganizacao(ctx, org))
+
+		again := &Organizacao{Nome: "Imobiliária Centro", Perfil: "CONSTRUTORA"}
+		require.NoError(t, repo.UpsertOrganizacao(ctx, again))
+		assert.Equal(t, org.ID, again.ID)
+		assert.Equal(t, "CONSTRUTORA", again.Perfil)
+	})
+}
+
+// failingPrecoRepository fails every write of a selling price
+type failingPrecoRepository struct {
+	ImportRepository
+}
+
+func (failingPrecoRepository) UpsertPrecoVenda(context.Context, *PrecoVenda) error {
+	return errors.New("connection reset")
+}
+
+func TestImportPublishedProperties_ThroughRepository(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+	importer, _ := setupImportService(t, api, false)
+	importer.repo = failingPrecoRepository{ImportRepository: importer.repo}
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 0 updated, 0 unchanged, 0 removed, 1 failed", result.String())
+
+	failures, err := importer.ListImportRunErrors(ctx, result.RunID, &ImportRunListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, failures.Results, 1)
+	assert.Contains(t, failures.Results[0].Error, "failed to upsert preco venda: connection reset")
+}
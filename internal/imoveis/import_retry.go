@@ -0,0 +1,131 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+)
+
+const (
+	// defaultImportRetryBase applies when externalapi.retry_base_ms is not configured
+	defaultImportRetryBase = 500 * time.Millisecond
+	// maxImportRetryDelay caps the backoff between two attempts
+	maxImportRetryDelay = 30 * time.Second
+	// defaultImportBreakerCooldown applies when externalapi.breaker_cooldown is not configured
+	defaultImportBreakerCooldown = time.Minute
+)
+
+// ErrSourceUnavailable is returned without calling the external API while the circuit breaker is
+// open, after too many requests in a row failed
+var ErrSourceUnavailable = errors.New("external API unavailable")
+
+// retry decides whether a failed request to the external API is sent again: transient failures
+// are, up to maxRetries times, after a backoff from retryBase
+func (c *sourceClient) retry(attempt int, err error) (time.Duration, bool) {
+	if !httpclient.Transient(err) || attempt >= c.maxRetries {
+		return 0, false
+	}
+	return httpclient.Backoff(c.retryBase, maxImportRetryDelay, attempt), true
+}
+
+// logRetry logs a failed request to the external API about to be sent again
+func (c *sourceClient) logRetry(ctx context.Context, req *http.Request, attempt int, delay time.Duration, err error) {
+	importLogger(ctx).Warn("External API request failed, retrying", "url", req.URL.String(), "delay", delay.Round(time.Millisecond),
+		"attempt", attempt+1, "max_retries", c.maxRetries, "error", err)
+}
+
+// get fetches url through the circuit breaker with header set, retrying transient failures, and
+// decodes its JSON body into v
+func (c *sourceClient) get(ctx context.Context, url string, header http.Header, v interface{}) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+
+	err := c.http.GetJSON(ctx, url, header, v)
+	switch {
+	case err == nil:
+		c.breaker.succeeded()
+	case ctx.Err() != nil:
+		// Cancelled by the caller, which tells nothing about the source
+		c.breaker.released()
+		return ctx.Err()
+	case httpclient.Transient(err):
+		c.breaker.failed()
+	default:
+		// The source answered, so it is up
+		c.breaker.succeeded()
+	}
+	return err
+}
+
+// circuitBreaker opens after threshold requests in a row failed: calls are refused until cooldown
+// passes, then a single request probes whether the source is back. A zero threshold never opens.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// isOpen reports whether calls are being refused
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.threshold > 0 && b.failures >= b.threshold
+}
+
+// allow returns ErrSourceUnavailable while the breaker is open; every allowed call must be
+// followed by succeeded, failed or released
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+	if wait := b.cooldown - b.now().Sub(b.openedAt); wait > 0 {
+		return fmt.Errorf("%w: %d requests in a row failed, retry in %s", ErrSourceUnavailable, b.failures, wait.Round(time.Second))
+	}
+	if b.probing {
+		return fmt.Errorf("%w: checking whether it is back", ErrSourceUnavailable)
+	}
+	b.probing = true
+	return nil
+}
+
+func (b *circuitBreaker) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) failed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		// A failed probe opens it for another cooldown
+		b.openedAt = b.now()
+	}
+}
+
+func (b *circuitBreaker) released() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
@@ -0,0 +1,130 @@
+package imoveis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySource answers the detail requests with status until failures runs out, then lets them
+// through to api
+func flakySource(t *testing.T, importer *importService, api *fakeExternalAPI, status int, failures int64) *atomic.Int64 {
+	t.Helper()
+	var detailRequests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/properties/published/") {
+			if detailRequests.Add(1) <= failures {
+				w.WriteHeader(status)
+				return
+			}
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	importer.source.(*pi8Source).baseURL = server.URL
+	importer.client.retryBase = time.Millisecond
+	return &detailRequests
+}
+
+func TestImportPropertyDetails_Retries(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+
+	t.Run("transient failures", func(t *testing.T) {
+		importer, _ := setupImportService(t, api, false)
+		importer.client.maxRetries = 3
+		requests := flakySource(t, importer, api, http.StatusServiceUnavailable, 2)
+
+		details, err := importer.ImportPropertyDetails(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "EXT-001", details.Codigo)
+		assert.Equal(t, int64(3), requests.Load())
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		importer, _ := setupImportService(t, api, false)
+		importer.client.maxRetries = 2
+		requests := flakySource(t, importer, api, http.StatusBadGateway, 10)
+
+		_, err := importer.ImportPropertyDetails(ctx, 1)
+		assert.EqualError(t, err, "external API returned status 502")
+		assert.Equal(t, int64(3), requests.Load())
+	})
+
+	t.Run("not client errors", func(t *testing.T) {
+		importer, _ := setupImportService(t, api, false)
+		importer.client.maxRetries = 3
+		requests := flakySource(t, importer, api, http.StatusNotFound, 10)
+
+		_, err := importer.ImportPropertyDetails(ctx, 1)
+		assert.EqualError(t, err, "external API returned status 404")
+		assert.Equal(t, int64(1), requests.Load())
+	})
+}
+
+func TestImportPublishedProperties_BreakerAbortsRun(t *testing.T) {
+	listings := make([]ExternalImovel, 6)
+	for i := range listings {
+		listings[i] = externalListing(uint(i + 1))
+	}
+	api := &fakeExternalAPI{listings: listings}
+	importer, _ := setupImportService(t, api, false)
+	importer.workers = 1
+	importer.client.breaker = newCircuitBreaker(2, time.Minute)
+	requests := flakySource(t, importer, api, http.StatusInternalServerError, 100)
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	assert.ErrorIs(t, err, ErrSourceUnavailable)
+	assert.Contains(t, err.Error(), "import interrupted after")
+	// The result still reports the run and what was done before it stopped
+	require.NotNil(t, result)
+	assert.NotZero(t, result.RunID)
+	// Once open, the breaker answers without calling the source
+	assert.Equal(t, int64(2), requests.Load())
+
+	// Later runs fail fast until the cooldown passes
+	_, err = importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	assert.ErrorIs(t, err, ErrSourceUnavailable)
+	assert.Equal(t, int64(2), requests.Load())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	require.NoError(t, breaker.allow())
+	breaker.failed()
+	require.NoError(t, breaker.allow())
+	breaker.failed()
+	assert.True(t, breaker.isOpen())
+	assert.ErrorIs(t, breaker.allow(), ErrSourceUnavailable)
+
+	// After the cooldown a single probe goes through
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.allow())
+	assert.ErrorIs(t, breaker.allow(), ErrSourceUnavailable)
+
+	// A failed probe opens it again
+	breaker.failed()
+	assert.ErrorIs(t, breaker.allow(), ErrSourceUnavailable)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.allow())
+	breaker.succeeded()
+	assert.False(t, breaker.isOpen())
+	require.NoError(t, breaker.allow())
+
+	disabled := newCircuitBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		disabled.failed()
+	}
+	assert.NoError(t, disabled.allow())
+}
@@ -0,0 +1,227 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+// Import run statuses
+const (
+	ImportRunRunning   = "RUNNING"
+	ImportRunCompleted = "COMPLETED"
+	ImportRunFailed    = "FAILED"
+)
+
+// ErrImportRunNotFound is returned when an import run does not exist
+var ErrImportRunNotFound = errors.New("import run not found")
+
+// startRun records the start of a run, resuming the one of resume if set. Recording is best
+// effort: the import goes on without it.
+func (is *importService) startRun(ctx context.Context, opts ImportOptions, resume *resumePoint) *ImportRun {
+	run := &ImportRun{
+		Source:    is.integrationSource,
+		Status:    ImportRunRunning,
+		FullSync:  !is.incremental || opts.Full,
+		StartedAt: time.Now().UTC(),
+	}
+	if opts.scoped() {
+		filters := opts.ImportFilters
+		run.Filters = &filters
+	}
+	if resume != nil {
+		run.ResumedFrom = &resume.originID
+	}
+	if userID, ok := contextutil.UserIDFromContext(ctx); ok {
+		run.UserID = &userID
+	}
+
+	if err := is.repo.CreateImportRun(ctx, run); err != nil {
+		importLogger(ctx).Warn("Failed to record import run", "error", err)
+		return nil
+	}
+	return run
+}
+
+// recordRunItem records a listing that failed to import in run
+func (is *importService) recordRunItem(ctx context.Context, run *ImportRun, listing *ExternalImovel, failure error) {
+	if run == nil {
+		return
+	}
+
+	item := &ImportRunItem{
+		RunID:      run.ID,
+		ExternalID: listing.ID,
+		Codigo:     listing.Codigo,
+		Error:      failure.Error(),
+	}
+	if err := is.repo.CreateImportRunItem(context.WithoutCancel(ctx), item); err != nil {
+		importLogger(ctx).Warn("Failed to record import failure", "external_id", listing.ID, "codigo", listing.Codigo, "error", err)
+	}
+}
+
+// finishRun records the counts and outcome of run; err is what stopped it, if anything
+func (is *importService) finishRun(ctx context.Context, run *ImportRun, counts importCounts, err error) {
+	if run == nil {
+		return
+	}
+
+	finishedAt := time.Now().UTC()
+	run.Status = ImportRunCompleted
+	if err != nil {
+		run.Status = ImportRunFailed
+		run.Error = err.Error()
+	}
+	run.Total = counts.listed
+	run.Created = counts.created
+	run.Updated = counts.updated
+	run.Unchanged = counts.unchanged
+	run.Failed = counts.failed
+	run.Removed = counts.removed
+	run.Quarantined = counts.quarantined
+	run.Throttled = counts.throttled
+	run.ThrottleMs = counts.throttleWait.Milliseconds()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+
+	// An interrupted run is still recorded
+	if err := is.repo.SaveImportRun(context.WithoutCancel(ctx), run); err != nil {
+		importLogger(ctx).Warn("Failed to record end of import run", "error", err)
+	}
+}
+
+// publishFinished publishes the outcome of a run, recorded or not, to the event bus
+func (is *importService) publishFinished(run *ImportRun, counts importCounts, startedAt time.Time, err error) {
+	if is.events == nil {
+		return
+	}
+
+	event := events.ImportFinished{
+		Source:      is.integrationSource,
+		Status:      ImportRunCompleted,
+		Total:       counts.listed,
+		Created:     counts.created,
+		Updated:     counts.updated,
+		Unchanged:   counts.unchanged,
+		Failed:      counts.failed,
+		Removed:     counts.removed,
+		Quarantined: counts.quarantined,
+		StartedAt:   startedAt.UTC(),
+		FinishedAt:  time.Now().UTC(),
+	}
+	if err != nil {
+		event.Status = ImportRunFailed
+		event.Error = err.Error()
+	}
+	if run != nil {
+		event.RunID = run.ID
+		event.StartedAt = run.StartedAt
+		if run.FinishedAt != nil {
+			event.FinishedAt = *run.FinishedAt
+		}
+	}
+	is.events.Publish(event)
+}
+
+// ListImportRuns returns the recorded import runs, newest first
+func (is *importService) ListImportRuns(ctx context.Context, query *ImportRunListQuery) (*ImportRunListResponse, error) {
+	normalizeImportRunListQuery(query)
+
+	runs, total, err := is.repo.ListImportRuns(ctx, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import runs: %w", err)
+	}
+
+	results := make([]ImportRunResponse, len(runs))
+	for i, run := range runs {
+		results[i] = ImportRunResponse{
+			ID:         run.ID,
+			Source:     run.Source,
+			Status:     run.Status,
+			FullSync:   run.FullSync,
+			UserID:     run.UserID,
+			Total:      run.Total,
+			Created:    run.Created,
+			Updated:    run.Updated,
+			Unchanged:  run.Unchanged,
+			Failed:     run.Failed,
+			Removed:    run.Removed,
+			Error:      run.Error,
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			DurationMs: run.DurationMs,
+			Throttled:  run.Throttled,
+			ThrottleMs: run.ThrottleMs,
+			Filters:    run.Filters,
+
+			Quarantined: run.Quarantined,
+
+			Checkpoint:           run.Checkpoint,
+			CheckpointExternalID: run.CheckpointExternalID,
+			ResumedFrom:          run.ResumedFrom,
+		}
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &ImportRunListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// ListImportRunErrors returns the listings that failed in an import run, in the order they failed
+func (is *importService) ListImportRunErrors(ctx context.Context, runID uint, query *ImportRunListQuery) (*ImportRunErrorListResponse, error) {
+	normalizeImportRunListQuery(query)
+
+	exists, err := is.repo.ExistsImportRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import run: %w", err)
+	}
+	if !exists {
+		return nil, ErrImportRunNotFound
+	}
+
+	items, total, err := is.repo.ListImportRunItems(ctx, runID, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import errors: %w", err)
+	}
+
+	results := make([]ImportRunErrorResponse, len(items))
+	for i, item := range items {
+		results[i] = ImportRunErrorResponse{
+			ExternalID: item.ExternalID,
+			Codigo:     item.Codigo,
+			Error:      item.Error,
+			CreatedAt:  item.CreatedAt,
+		}
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &ImportRunErrorListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+func normalizeImportRunListQuery(query *ImportRunListQuery) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+}
@@ -0,0 +1,84 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+func TestImportRuns(t *testing.T) {
+	ctx := contextutil.WithUserID(context.Background(), 7)
+	failing := externalListing(2)
+	failing.PrecoVenda = nil
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), failing, externalListing(3)}}
+	importer, _ := setupImportService(t, api, false)
+	publisher := &recordingPublisher{}
+	WithImportEvents(publisher)(importer)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Failed)
+
+	runs, err := importer.ListImportRuns(ctx, &ImportRunListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, runs.Results, 1)
+	run := runs.Results[0]
+	assert.Equal(t, ImportRunCompleted, run.Status)
+	assert.Equal(t, "pi8", run.Source)
+	assert.True(t, run.FullSync)
+	require.NotNil(t, run.UserID)
+	assert.Equal(t, uint(7), *run.UserID)
+	assert.Equal(t, 3, run.Total)
+	assert.Equal(t, 2, run.Created)
+	assert.Equal(t, 1, run.Failed)
+	assert.NotNil(t, run.FinishedAt)
+	assert.Equal(t, run.ID, result.RunID)
+
+	require.Len(t, publisher.events, 1)
+	finished := publisher.events[0].(events.ImportFinished)
+	assert.Equal(t, run.ID, finished.RunID)
+	assert.Equal(t, "pi8", finished.Source)
+	assert.Equal(t, ImportRunCompleted, finished.Status)
+	assert.Equal(t, 3, finished.Total)
+	assert.Equal(t, 2, finished.Created)
+	assert.Equal(t, 1, finished.Failed)
+	assert.Equal(t, *run.FinishedAt, finished.FinishedAt)
+
+	failures, err := importer.ListImportRunErrors(ctx, run.ID, &ImportRunListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, failures.Results, 1)
+	assert.Equal(t, uint(2), failures.Results[0].ExternalID)
+	assert.Equal(t, "EXT-002", failures.Results[0].Codigo)
+	assert.Contains(t, failures.Results[0].Error, "must have a selling price")
+
+	t.Run("failed run", func(t *testing.T) {
+		api.listings = nil
+
+		_, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		assert.EqualError(t, err, "no properties found in external API")
+
+		runs, err := importer.ListImportRuns(ctx, &ImportRunListQuery{Page: 1, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), runs.Total)
+		assert.True(t, runs.HasNext)
+		require.Len(t, runs.Results, 1)
+		assert.Equal(t, ImportRunFailed, runs.Results[0].Status)
+		assert.Equal(t, "no properties found in external API", runs.Results[0].Error)
+
+		require.Len(t, publisher.events, 2)
+		finished := publisher.events[1].(events.ImportFinished)
+		assert.Equal(t, ImportRunFailed, finished.Status)
+		assert.Equal(t, "no properties found in external API", finished.Error)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		_, err := importer.ListImportRunErrors(ctx, 999, &ImportRunListQuery{Page: 1, Limit: 20})
+		assert.ErrorIs(t, err, ErrImportRunNotFound)
+	})
+}
@@ -3,192 +3,534 @@ package imoveis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 )
 
+// ErrExternalListingNotFound is returned when the external API has no listing with the requested ID
+var ErrExternalListingNotFound = errors.New("listing not found in external API")
+
 // ImportService defines the interface for importing properties from external API
 type ImportService interface {
-	ImportPublishedProperties(ctx context.Context) error
+	ImportPublishedProperties(ctx context.Context, opts ImportOptions) (*ImportResult, error)
 	ImportPropertyDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error)
+	ImportProperty(ctx context.Context, externalID uint) (*ImportPropertyResponse, error)
+	HandleWebhook(ctx context.Context, body []byte, headers WebhookHeaders) (*WebhookResponse, error)
+
+	// Background runs
+	StartImport(ctx context.Context, opts ImportOptions, requestedBy uint) (*ImportJobResponse, error)
+	GetImportJob(ctx context.Context, id string) (*ImportJobResponse, error)
+
+	// Run history
+	ListImportRuns(ctx context.Context, query *ImportRunListQuery) (*ImportRunListResponse, error)
+	ListImportRunErrors(ctx context.Context, runID uint, query *ImportRunListQuery) (*ImportRunErrorListResponse, error)
+	ListImportConflicts(ctx context.Context, query *ImportConflictListQuery) (*ImportConflictListResponse, error)
+	ResolveImportConflict(ctx context.Context, id uint, req *ResolveImportConflictRequest) (*ImportConflict, error)
+	ListImportQuarantine(ctx context.Context, query *ImportQuarantineListQuery) (*ImportQuarantineListResponse, error)
+	RetryImportQuarantine(ctx context.Context, id uint) (*ImportQuarantineResponse, error)
+	DiscardImportQuarantine(ctx context.Context, id uint) (*ImportQuarantineResponse, error)
+}
+
+// ImportOptions tunes an import run
+type ImportOptions struct {
+	// Full re-imports every property even when incremental imports are enabled
+	Full bool `form:"full" json:"full"`
+	ImportFilters
+	// Resume picks up the interrupted run with this ID from its checkpoint, with its mode and filters
+	Resume uint `form:"resume" json:"resume"`
+	// Progress, when set, is called as listings are imported
+	Progress func(ImportProgress) `form:"-" json:"-"`
 }
 
 type importService struct {
 	service           Service
-	httpClient        *http.Client
-	baseURL           string
-	apiKey            string
+	repo              ImportRepository
+	source            ExternalSource
+	client            *sourceClient
 	integrationSource string
+	incremental       bool
+	workers           int
+	removedPolicy     string
+	conflictPolicy    string
+	webhookSecret     string
+	webhookTolerance  time.Duration
+	webhookDeliveries *webhookDeliveries
+	mappings          fieldMappings
+	images            storage.Storage
+	logger            *slog.Logger
+	locks             relationLocks
+	events            events.Publisher
+
+	jobsMu     sync.Mutex
+	jobs       map[string]*ImportJobResponse
+	jobHistory []string
+	runningJob string
 }
 
-// NewImportService creates a new import service
-func NewImportService(service Service, extCfg *config.ExternalAPIConfig) ImportService {
-	timeout := time.Duration(extCfg.TimeoutSeconds) * time.Second
-	if timeout == 0 {
-		timeout = 30 * time.Second
+// ImportServiceOption configures optional import service behaviour
+type ImportServiceOption func(*importService)
+
+// WithImageDownload stores the images of the imported listings in store instead of linking to the
+// source, which may remove them at any time
+func WithImageDownload(store storage.Storage) ImportServiceOption {
+	return func(is *importService) {
+		is.images = store
 	}
+}
 
-	return &importService{
+// WithImportEvents publishes the end of each import run to publisher
+func WithImportEvents(publisher events.Publisher) ImportServiceOption {
+	return func(is *importService) {
+		is.events = publisher
+	}
+}
+
+// NewImportService creates a new import service reading from the source driver selected in extCfg.
+// Properties are written through service and their relations through repo.
+func NewImportService(service Service, repo ImportRepository, extCfg *config.ExternalAPIConfig, opts ...ImportServiceOption) (ImportService, error) {
+	workers := extCfg.Workers
+	if workers <= 0 {
+		workers = defaultImportWorkers
+	}
+
+	client := newSourceClient(extCfg, workers)
+	source, err := newExternalSource(extCfg, client)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := newFieldMappings(extCfg.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	conflictPolicy := extCfg.ConflictPolicy
+	switch conflictPolicy {
+	case "":
+		conflictPolicy = ConflictPolicyLocalWins
+	case ConflictPolicyLocalWins, ConflictPolicyRemoteWins, ConflictPolicyReview:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q in external API config", conflictPolicy)
+	}
+
+	webhookTolerance := time.Duration(extCfg.WebhookTolerance) * time.Second
+	if webhookTolerance <= 0 {
+		webhookTolerance = defaultWebhookTolerance
+	}
+
+	is := &importService{
 		service:           service,
-		httpClient:        &http.Client{Timeout: timeout},
-		baseURL:           extCfg.BaseURL,
-		apiKey:            extCfg.APIKey,
+		repo:              repo,
+		source:            source,
+		client:            client,
 		integrationSource: extCfg.IntegrationSource,
-	}
+		incremental:       extCfg.Incremental,
+		workers:           workers,
+		removedPolicy:     extCfg.RemovedPolicy,
+		conflictPolicy:    conflictPolicy,
+		webhookSecret:     extCfg.WebhookSecret,
+		webhookTolerance:  webhookTolerance,
+		webhookDeliveries: newWebhookDeliveries(webhookTolerance),
+		mappings:          mappings,
+		logger:            slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(is)
+	}
+	return is, nil
 }
 
 // ImportPublishedProperties imports all published properties from external API
-// Uses upsert logic: creates new properties or updates existing ones
-func (is *importService) ImportPublishedProperties(ctx context.Context) error {
-	// Fetch list of published properties
-	listURL := fmt.Sprintf("%s/api/properties/published", is.baseURL)
+// Uses upsert logic: creates new properties or updates existing ones. Incremental runs only list
+// the properties updated since the last run without failures and skip the listings whose checksum
+// matches the one imported, without fetching their details. Every run is recorded in import_runs
+// with the listings that failed and the requests held back by the rate limit. A run that went
+// through returns its result even when some listings failed; the error is what stopped the run,
+// with the result of what was done before. Runs save a checkpoint as they go, so one interrupted
+// can be resumed with opts.Resume instead of starting over.
+func (is *importService) ImportPublishedProperties(ctx context.Context, opts ImportOptions) (*ImportResult, error) {
+	startedAt := time.Now()
+	ctx = is.correlate(ctx)
+
+	var resume *resumePoint
+	if opts.Resume != 0 {
+		var err error
+		if resume, err = is.prepareResume(ctx, &opts); err != nil {
+			return &ImportResult{}, err
+		}
+	}
+	run := is.startRun(ctx, opts, resume)
+	if run != nil {
+		ctx = withImportLogger(ctx, importLogger(ctx).With("import_run_id", run.ID))
+	}
+	logger := importLogger(ctx)
+	logger.Info("Import run started", "full", opts.Full, "incremental", is.incremental && !opts.Full, "scoped", opts.scoped(),
+		"resumed_from", opts.Resume)
+
+	stats := &throttleStats{}
+	ctx = withThrottleStats(ctx, stats)
+	if progress := opts.Progress; progress != nil {
+		opts.Progress = func(p ImportProgress) {
+			stats.fill(&p)
+			progress(p)
+		}
+	}
 
-	properties, err := is.fetchPublishedList(ctx, listURL)
+	counts, err := is.importPublished(ctx, opts, run, resume)
+	counts.throttled, counts.throttleWait = stats.snapshot()
+	if counts.throttled > 0 {
+		logger.Info("Rate limit held back requests to the external API", "requests", counts.throttled, "wait", counts.throttleWait.Round(time.Millisecond))
+	}
+	is.finishRun(ctx, run, counts, err)
+	is.publishFinished(run, counts, startedAt, err)
+
+	result := &ImportResult{ImportProgress: counts.progress(), DurationMs: time.Since(startedAt).Milliseconds()}
+	stats.fill(&result.ImportProgress)
+	if run != nil {
+		result.RunID = run.ID
+	}
 	if err != nil {
-		return fmt.Errorf("failed to fetch published properties: %w", err)
+		logger.Error("Import run failed", "processed", counts.total(), "error", err)
+		return result, err
 	}
+	logger.Info("Import run finished", "listed", counts.listed, "created", counts.created, "updated", counts.updated,
+		"unchanged", counts.unchanged, "removed", counts.removed, "failed", counts.failed)
+	return result, nil
+}
 
-	if len(properties) == 0 {
-		return fmt.Errorf("no properties found in external API")
+// importPublished lists the published properties and imports them on the workers. A resumed run
+// leaves out the listings up to the checkpoint of the interrupted one and, even when full, skips
+// the listings already imported unchanged since the sync began.
+func (is *importService) importPublished(ctx context.Context, opts ImportOptions, run *ImportRun, resume *resumePoint) (importCounts, error) {
+	startedAt := time.Now().UTC()
+	incremental := is.incremental && !opts.Full
+	if resume != nil {
+		startedAt = resume.startedAt
+	}
+
+	var since *time.Time
+	if incremental {
+		var err error
+		if since, err = is.syncWatermark(ctx); err != nil {
+			return importCounts{}, fmt.Errorf("failed to read import watermark: %w", err)
+		}
+	}
+	skipUnchanged := incremental || resume != nil
+	var checksums map[string]string
+	if skipUnchanged {
+		var syncedSince *time.Time
+		if !incremental {
+			syncedSince = &resume.startedAt
+		}
+		var err error
+		if checksums, err = is.repo.ImportChecksums(ctx, syncedSince); err != nil {
+			return importCounts{}, fmt.Errorf("failed to read import checksums: %w", err)
+		}
+	}
+
+	properties, err := is.source.ListPublished(ctx, since)
+	if err != nil {
+		return importCounts{}, fmt.Errorf("failed to fetch published properties: %w", err)
 	}
 
-	// Process each property
-	var successCount, errorCount, updateCount int
-	for _, extImovel := range properties {
-		// Fetch detailed info for this property (includes empreendimento and torres)
-		log.Printf("####PROPERTIER %v", extImovel.ID)
-		detailedImovel, err := is.ImportPropertyDetails(ctx, extImovel.ID)
+	if len(properties) == 0 && since == nil {
+		return importCounts{}, fmt.Errorf("no properties found in external API")
+	}
+	for i := range properties {
+		is.mappings.applyListing(&properties[i])
+	}
+	if opts.scoped() {
+		listed := len(properties)
+		properties = opts.filter(properties)
+		importLogger(ctx).Info("Import scoped by filters", "matched", len(properties), "listed", listed)
+	}
+
+	pending := properties
+	if skip := resume.skip(properties); skip > 0 {
+		pending = properties[skip:]
+		if run != nil {
+			run.Checkpoint, run.CheckpointExternalID = skip, properties[skip-1].ID
+		}
+		importLogger(ctx).Info("Resuming import from checkpoint", "skipped", skip, "external_id", properties[skip-1].ID)
+	}
+	offset := len(properties) - len(pending)
+
+	// The run stops handing out listings once the breaker opens; the ones in flight fail fast
+	dispatchCtx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	hooks := importHooks{
+		progress: opts.Progress,
+		failed: func(extImovel *ExternalImovel, err error) {
+			is.recordRunItem(ctx, run, extImovel, err)
+		},
+		checkpoint: func(done int) {
+			is.saveCheckpoint(ctx, run, offset+done, pending[done-1].ID)
+		},
+	}
+	counts := is.importAll(dispatchCtx, pending, hooks, func(extImovel *ExternalImovel) (importOutcome, error) {
+		checksum := listingChecksum(extImovel)
+		if skipUnchanged && checksum != "" && checksums[fmt.Sprintf("%d", extImovel.ID)] == checksum {
+			return importUnchanged, nil
+		}
+		outcome, err := is.importListing(ctx, extImovel)
+		if is.client.breaker.isOpen() {
+			stop(ErrSourceUnavailable)
+		}
+		return outcome, err
+	})
+	if err := context.Cause(dispatchCtx); err != nil {
+		return counts, fmt.Errorf("import interrupted after %d properties: %w", counts.total(), err)
+	}
+
+	// A run with failures keeps the previous watermark so the failed properties are listed again
+	if counts.failed == 0 && !opts.scoped() {
+		if err := is.saveSyncWatermark(ctx, startedAt); err != nil {
+			importLogger(ctx).Warn("Failed to move the import watermark", "error", err)
+		}
+	}
+
+	// Only the complete list tells which properties were removed from the source
+	if since == nil && !opts.scoped() {
+		err := is.handleRemoved(ctx, properties, run, &counts)
+		if opts.Progress != nil {
+			opts.Progress(counts.progress())
+		}
 		if err != nil {
-			fmt.Printf("Warning: Failed to fetch details for property %d: %v\n", extImovel.ID, err)
-			errorCount++
-			continue
+			return counts, err
 		}
+	}
 
-		idIntegracao := fmt.Sprintf("%d", detailedImovel.ID)
+	return counts, nil
+}
+
+// importListing fetches the details of a listing and creates or updates its property with all its
+// relations in one transaction, so a failure leaves nothing behind
+func (is *importService) importListing(ctx context.Context, extImovel *ExternalImovel) (importOutcome, error) {
+	startedAt := time.Now()
+	logger := importLogger(ctx).With("external_id", extImovel.ID)
+	logger.Debug("Importing listing")
+
+	// Fetch detailed info for this property (includes empreendimento and torres)
+	detailedImovel, err := is.ImportPropertyDetails(ctx, extImovel.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch listing details", "action", importFailed.String(), "duration", time.Since(startedAt), "error", err)
+		return importFailed, fmt.Errorf("failed to fetch details: %w", err)
+	}
+	logger = logger.With("codigo", detailedImovel.Codigo)
+	// The payload is kept as it came, before the mappings, for the quarantine
+	payload, err := json.Marshal(detailedImovel)
+	if err != nil {
+		return importFailed, fmt.Errorf("failed to encode listing: %w", err)
+	}
+	is.mappings.applyDetails(detailedImovel)
 
-		// Check if property already exists by IdIntegracao
-		existingImovel, err := is.service.GetImovelByIdIntegracao(ctx, idIntegracao)
-		if err == nil && existingImovel != nil {
+	// Listings that would make a half-broken property are held for review instead
+	if violations := validateListing(detailedImovel); len(violations) > 0 {
+		if err := is.quarantine(ctx, detailedImovel, payload, violations); err != nil {
+			logger.Error("Failed to quarantine listing", "action", importFailed.String(), "duration", time.Since(startedAt), "error", err)
+			return importFailed, err
+		}
+		logger.Warn("Quarantined invalid listing", "action", importQuarantined.String(), "duration", time.Since(startedAt), "violations", violations)
+		return importQuarantined, nil
+	}
+
+	idIntegracao := fmt.Sprintf("%d", detailedImovel.ID)
+	checksum := listingChecksum(extImovel)
+	images := is.prepareImages(ctx, listingImageURLs(detailedImovel))
+
+	// The shared relations stay locked until the commit, so another worker finds them created
+	// instead of creating them again
+	defer is.locks.lock(relationLockKeys(detailedImovel)...)()
+
+	outcome := importCreated
+	err = is.repo.Transaction(ctx, func(txCtx context.Context) error {
+		existingImovel, err := is.findImported(txCtx, idIntegracao)
+		if err != nil {
+			return err
+		}
+
+		imovelID := uint(0)
+		if existingImovel != nil {
 			// Property exists - update it and its relationships
-			fmt.Printf("Property %s already exists (ID: %d), updating...\n", detailedImovel.Codigo, existingImovel.ID)
-			if _, err := is.upsertImovelAndRelationships(ctx, existingImovel.ID, detailedImovel, true); err != nil {
-				fmt.Printf("Warning: Failed to update property %s: %v\n", detailedImovel.Codigo, err)
-				errorCount++
-				continue
+			outcome = importUpdated
+			imovelID = existingImovel.ID
+			if _, err := is.upsertImovelAndRelationships(txCtx, existingImovel, detailedImovel, images); err != nil {
+				return err
 			}
-			updateCount++
 		} else {
 			// Property doesn't exist - create it and its relationships
-			imovelResp, err := is.upsertImovelAndRelationships(ctx, 0, detailedImovel, false)
+			imovelResp, err := is.upsertImovelAndRelationships(txCtx, nil, detailedImovel, images)
 			if err != nil {
-				fmt.Printf("Warning: Failed to create property %s: %v\n", detailedImovel.Codigo, err)
-				errorCount++
-				continue
+				return err
 			}
+			imovelID = imovelResp.ID
+		}
 
-			fmt.Printf("Successfully created property: %s (ID: %d)\n", detailedImovel.Codigo, imovelResp.ID)
-			successCount++
+		if err := is.repo.ReleaseQuarantine(txCtx, is.integrationSource, detailedImovel.ID); err != nil {
+			return fmt.Errorf("failed to release quarantined listing: %w", err)
+		}
+
+		if checksum == "" {
+			return nil
+		}
+		if err := is.repo.SaveImportChecksum(txCtx, imovelID, checksum); err != nil {
+			return fmt.Errorf("failed to save import checksum: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to import listing, changes rolled back", "action", importFailed.String(), "duration", time.Since(startedAt), "error", err)
+		return importFailed, err
 	}
 
-	return fmt.Errorf("import completed: %d created, %d updated, %d failed", successCount, updateCount, errorCount)
+	logger.Info("Imported listing", "action", outcome.String(), "duration", time.Since(startedAt))
+	return outcome, nil
 }
 
-// ImportPropertyDetails fetches detailed property information including empreendimento
-func (is *importService) ImportPropertyDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error) {
-	detailURL := fmt.Sprintf("%s/api/properties/published/%d", is.baseURL, externalID)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
-
+// ImportProperty imports or refreshes the one property of a listing, outside of any run and
+// whatever the incremental state says
+func (is *importService) ImportProperty(ctx context.Context, externalID uint) (*ImportPropertyResponse, error) {
+	ctx = is.correlate(ctx)
+	outcome, err := is.importListing(ctx, &ExternalImovel{ID: externalID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		var statusErr *httpclient.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, ErrExternalListingNotFound
+		}
+		return nil, err
+	}
+	if outcome == importQuarantined {
+		entry, err := is.repo.FindLatestQuarantine(ctx, is.integrationSource, externalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read quarantined listing: %w", err)
+		}
+		resp := &ImportPropertyResponse{ExternalID: externalID, Outcome: ImportOutcomeQuarantined}
+		if entry != nil {
+			resp.Quarantine = quarantineResponse(entry)
+		}
+		return resp, nil
 	}
 
-	is.setHeaders(req)
-
-	resp, err := is.httpClient.Do(req)
+	imovel, err := is.service.GetImovelByIdIntegracao(ctx, fmt.Sprintf("%d", externalID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch property details: %w", err)
+		return nil, fmt.Errorf("failed to read imported property: %w", err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("Failed to close response body: %v", err)
-		}
-	}()
+	resp := &ImportPropertyResponse{ExternalID: externalID, Outcome: ImportOutcomeCreated, Imovel: imovel}
+	if outcome == importUpdated {
+		resp.Outcome = ImportOutcomeUpdated
+	}
+	return resp, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("external API returned status %d", resp.StatusCode)
+// findImported returns the property imported from the listing, bringing back one deleted when the
+// listing was removed from the source, or nil when there is none
+func (is *importService) findImported(ctx context.Context, idIntegracao string) (*ImovelResponse, error) {
+	existingImovel, err := is.service.GetImovelByIdIntegracao(ctx, idIntegracao)
+	if err == nil {
+		return existingImovel, nil
+	}
+	if !errors.Is(err, ErrImovelNotFound) {
+		return nil, fmt.Errorf("failed to find property: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	restored, err := is.restoreRemoved(ctx, idIntegracao)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to restore property removed from the source: %w", err)
 	}
-
-	var result struct {
-		Results ExternalDetailedImovel `json:"results"`
+	if !restored {
+		return nil, nil
 	}
+	return is.service.GetImovelByIdIntegracao(ctx, idIntegracao)
+}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// relationLockKeys returns the relations of the listing other properties may share
+func relationLockKeys(ext *ExternalDetailedImovel) []string {
+	var keys []string
+	if ext.Empreendimento != nil {
+		keys = append(keys, fmt.Sprintf("empreendimento:%d", ext.Empreendimento.ID))
+	}
+	if ext.PrecoVenda != nil {
+		keys = append(keys, fmt.Sprintf("preco_venda:%d", ext.PrecoVenda.ID))
+	}
+	if ext.PrecoAluguel != nil {
+		keys = append(keys, fmt.Sprintf("preco_aluguel:%d", ext.PrecoAluguel.ID))
+	}
+	if ext.CorretorPrincipal.Email != "" {
+		keys = append(keys, fmt.Sprintf("corretor:%d", ext.CorretorPrincipal.ID))
+		if ext.CorretorPrincipal.Organizacao.Nome != "" {
+			keys = append(keys, "organizacao:"+ext.CorretorPrincipal.Organizacao.Nome)
+		}
 	}
+	return keys
+}
 
-	return &result.Results, nil
+// ImportPropertyDetails fetches detailed property information including empreendimento
+func (is *importService) ImportPropertyDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error) {
+	return is.source.GetDetails(ctx, externalID)
 }
 
 // upsertImovelAndRelationships creates or updates a property and all its relationships
-// isUpdate=true means we're updating an existing property, false means creating new
-func (is *importService) upsertImovelAndRelationships(ctx context.Context, imovelID uint, ext *ExternalDetailedImovel, isUpdate bool) (*ImovelResponse, error) {
+// existing is the property read by the caller, updated along with the fields the conflict policy
+// lets the source write, or nil to create a new one; images are the prepared images of the
+// listing. Stops at the first failure; the caller runs it in a transaction to roll back what was
+// written.
+func (is *importService) upsertImovelAndRelationships(ctx context.Context, existing *ImovelResponse, ext *ExternalDetailedImovel, images listingImages) (*ImovelResponse, error) {
 	var imovelResp *ImovelResponse
 	var err error
 
+	var imovelID uint
+	if existing != nil {
+		imovelID = existing.ID
+	}
+
+	// Price changes made by this import are recorded as such in the price history
+	ctx = withPriceOrigin(ctx, PriceOriginImport)
+
 	// Always upsert relationships first (works for both create and update)
+	// Relations without an external ID cannot be matched to a local record and are left out
 	var empreendimentoID uint
-	if ext.Empreendimento != nil {
-		empID, err := is.upsertEmpreendimento(ctx, ext.Empreendimento)
-		if err != nil {
-			fmt.Printf("Warning: Failed to handle empreendimento for property %s: %v\n", ext.Codigo, err)
-		} else {
-			empreendimentoID = empID
+	if ext.Empreendimento != nil && ext.Empreendimento.ID != 0 {
+		if empreendimentoID, err = is.upsertEmpreendimento(ctx, ext.Empreendimento, images); err != nil {
+			return nil, err
 		}
 	}
 
 	var precoVendaID uint
-	if ext.PrecoVenda != nil && ext.PrecoVenda.Ativo {
-		pvID, err := is.upsertPrecoVenda(ctx, ext.PrecoVenda)
-		if err != nil {
-			fmt.Printf("Warning: Failed to handle preco venda for property %s: %v\n", ext.Codigo, err)
-		} else {
-			precoVendaID = pvID
+	if ext.PrecoVenda != nil && ext.PrecoVenda.Ativo && ext.PrecoVenda.ID != 0 {
+		if precoVendaID, err = is.upsertPrecoVenda(ctx, ext.PrecoVenda); err != nil {
+			return nil, err
 		}
 	}
 
 	var precoAluguelID uint
-	if ext.PrecoAluguel != nil && ext.PrecoAluguel.Ativo {
-		paID, err := is.upsertPrecoAluguel(ctx, ext.PrecoAluguel)
-		if err != nil {
-			fmt.Printf("Warning: Failed to handle preco aluguel for property %s: %v\n", ext.Codigo, err)
-		} else {
-			precoAluguelID = paID
+	if ext.PrecoAluguel != nil && ext.PrecoAluguel.Ativo && ext.PrecoAluguel.ID != 0 {
+		if precoAluguelID, err = is.upsertPrecoAluguel(ctx, ext.PrecoAluguel); err != nil {
+			return nil, err
 		}
 	}
 
+	// Prices the source deactivated are deactivated here too, and detached from the property below
+	var inactive inactivePrices
+	if inactive, err = is.deactivatePrices(ctx, ext); err != nil {
+		return nil, err
+	}
+
 	var corretorPrincipalID uint
 	if ext.CorretorPrincipal.Email != "" {
-		cpID, err := is.upsertCorretorPrincipal(ctx, &ext.CorretorPrincipal)
-		if err != nil {
-			fmt.Printf("Warning: Failed to handle corretor principal for property %s: %v\n", ext.Codigo, err)
-		} else {
-			corretorPrincipalID = cpID
+		if corretorPrincipalID, err = is.upsertCorretorPrincipal(ctx, &ext.CorretorPrincipal, images); err != nil {
+			return nil, err
 		}
 	}
 
-	if isUpdate {
+	if existing != nil {
 		// Update existing property with new field values AND relationships
 		updateReq := &UpdateImovelRequest{
 			Titulo:       ext.Titulo,
@@ -204,6 +546,8 @@ func (is *importService) upsertImovelAndRelationships(ctx context.Context, imove
 			NumAndar:     &ext.NumAndar,
 			Unidade:      ext.Unidade,
 			Condominio:   &ext.Condominio,
+			// Fails instead of overwriting edits made since the property was read
+			Version: &existing.Version,
 		}
 
 		// Update relationships (use pointers for optional fields)
@@ -220,15 +564,23 @@ func (is *importService) upsertImovelAndRelationships(ctx context.Context, imove
 			updateReq.CorretorPrincipalID = &corretorPrincipalID
 		}
 
+		// Fields edited by hand are left out as the conflict policy says
+		if updateReq, err = is.applyConflictPolicy(ctx, existing, updateReq); err != nil {
+			return nil, err
+		}
+
 		imovelResp, err = is.service.UpdateImovel(ctx, imovelID, updateReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update property: %w", err)
 		}
+		if imovelResp, err = is.detachInactivePrices(ctx, imovelResp, inactive); err != nil {
+			return nil, err
+		}
 
 		// Update endereco if present
 		if ext.Endereco.Rua != "" {
 			if err := is.upsertEndereco(ctx, imovelID, &ext.Endereco); err != nil {
-				fmt.Printf("Warning: Failed to update endereco for property %s: %v\n", ext.Codigo, err)
+				return nil, fmt.Errorf("failed to update endereco: %w", err)
 			}
 		}
 	} else {
@@ -242,7 +594,15 @@ func (is *importService) upsertImovelAndRelationships(ctx context.Context, imove
 		}
 
 		// Create new property with all relationships already upserted above
-		createReq := is.transformExternalToCreateRequest(ext, enderecoID, empreendimentoID, precoVendaID, precoAluguelID, corretorPrincipalID)
+		createReq := is.source.MapToCreateRequest(ext, ImportRelations{
+			EnderecoID:          enderecoID,
+			EmpreendimentoID:    empreendimentoID,
+			PrecoVendaID:        precoVendaID,
+			PrecoAluguelID:      precoAluguelID,
+			CorretorPrincipalID: corretorPrincipalID,
+		})
+		// Later runs find the property by the listing ID whatever the driver set
+		createReq.IdIntegracao = fmt.Sprintf("%d", ext.ID)
 		imovelResp, err = is.service.CreateImovel(ctx, createReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create property: %w", err)
@@ -253,8 +613,8 @@ func (is *importService) upsertImovelAndRelationships(ctx context.Context, imove
 	// Handle Anexos (Images/Attachments)
 	// DELETE old anexos and recreate with current data from external API
 	// This ensures removed images are deleted and new images are added
-	if err := is.syncAnexosFromImages(ctx, imovelID, ext.Imagens); err != nil {
-		fmt.Printf("Warning: Failed to sync attachments for property %s: %v\n", ext.Codigo, err)
+	if err := is.syncAnexosFromImages(ctx, imovelID, images.of(ext.Imagens)); err != nil {
+		return nil, fmt.Errorf("failed to sync attachments: %w", err)
 	}
 
 	return imovelResp, nil
@@ -295,8 +655,9 @@ func (is *importService) upsertEndereco(ctx context.Context, imovelID uint, extE
 	return is.service.AttachEndereco(ctx, imovelID, enderecoID)
 }
 
-// upsertEmpreendimento creates or updates an enterprise and its nested relationships
-func (is *importService) upsertEmpreendimento(_ context.Context, ext *ExternalEmpreendimento) (uint, error) {
+// upsertEmpreendimento creates or updates an enterprise and its nested relationships; images holds
+// the prepared images of its plantas
+func (is *importService) upsertEmpreendimento(ctx context.Context, ext *ExternalEmpreendimento, images listingImages) (uint, error) {
 	if ext == nil {
 		return 0, fmt.Errorf("empreendimento is nil")
 	}
@@ -305,64 +666,27 @@ func (is *importService) upsertEmpreendimento(_ context.Context, ext *ExternalEm
 		return 0, fmt.Errorf("empreendimento has no valid external ID")
 	}
 
-	idIntegracao := fmt.Sprintf("%d", ext.ID)
-
-	// Check if empreendimento with this external ID already exists
-	var existing Empreendimento
-	err := is.service.(*service).repo.(*repository).db.
-		Where("id_integracao = ?", idIntegracao).
-		First(&existing).Error
-
-	if err == nil {
-		// Empreendimento exists, update relevant fields only (skip dates, createdAt)
-		updates := map[string]interface{}{
-			"titulo":      ext.Titulo,
-			"descricao":   ext.Descricao,
-			"tipo":        ext.Tipo,
-			"status":      ext.Status,
-			"localizacao": ext.Localizacao,
-		}
-
-		if ext.Finalidade != "" {
-			updates["finalidade"] = ext.Finalidade
-		}
-
-		// Only update if there are changes (GORM will handle this efficiently)
-		if err := is.service.(*service).repo.(*repository).db.
-			Model(&existing).
-			Updates(updates).Error; err != nil {
-			return 0, fmt.Errorf("failed to update empreendimento: %w", err)
-		}
-
-		return existing.ID, nil
-	}
-
-	// Create new empreendimento - skip fields with date type that cause empty string errors
 	empreendimento := &Empreendimento{
-		IdIntegracao: idIntegracao,
+		IdIntegracao: fmt.Sprintf("%d", ext.ID),
 		Titulo:       ext.Titulo,
 		Descricao:    ext.Descricao,
 		Tipo:         ext.Tipo,
 		Status:       ext.Status,
 		Localizacao:  ext.Localizacao,
+		Finalidade:   ext.Finalidade,
 	}
-
-	if ext.Finalidade != "" {
-		empreendimento.Finalidade = ext.Finalidade
+	if err := is.repo.UpsertEmpreendimento(ctx, empreendimento); err != nil {
+		return 0, fmt.Errorf("failed to upsert empreendimento: %w", err)
 	}
-
-	// Use Select to omit problematic fields (data_entrega, etapa_lancamento, endereco_id)
-	if err := is.service.(*service).repo.(*repository).db.
-		Omit("DataEntrega", "EtapaLancamento", "EnderecoID").
-		Create(empreendimento).Error; err != nil {
-		return 0, fmt.Errorf("failed to create empreendimento: %w", err)
+	if err := is.syncEmpreendimentoRelations(ctx, empreendimento, ext, images); err != nil {
+		return 0, err
 	}
 
 	return empreendimento.ID, nil
 }
 
 // upsertPrecoVenda creates or updates a selling price record
-func (is *importService) upsertPrecoVenda(_ context.Context, ext *ExternalPrecoVenda) (uint, error) {
+func (is *importService) upsertPrecoVenda(ctx context.Context, ext *ExternalPrecoVenda) (uint, error) {
 	if ext == nil {
 		return 0, fmt.Errorf("preco venda is nil")
 	}
@@ -371,34 +695,8 @@ func (is *importService) upsertPrecoVenda(_ context.Context, ext *ExternalPrecoV
 		return 0, fmt.Errorf("preco venda has no valid external ID")
 	}
 
-	idIntegracao := fmt.Sprintf("%d", ext.ID)
-
-	// Check if preco venda with this external ID already exists
-	var existing PrecoVenda
-	err := is.service.(*service).repo.(*repository).db.
-		Where("id_integracao = ?", idIntegracao).
-		First(&existing).Error
-
-	if err == nil {
-		// Preco venda exists, update it and return its local ID
-		existing.Preco = ext.Preco
-		existing.AceitaFinanciamentoBancario = ext.AceitaFinanciamentoBancario
-		existing.AceitaFinanciamentoDireto = ext.AceitaFinanciamentoDireto
-		existing.AceitaPermuta = ext.AceitaPermuta
-		existing.AceitaCartaDeCredito = ext.AceitaCartaDeCredito
-		existing.AceitaFGTS = ext.AceitaFGTS
-		existing.Ativo = ext.Ativo
-
-		if err := is.service.(*service).repo.(*repository).db.Save(&existing).Error; err != nil {
-			return 0, fmt.Errorf("failed to update preco venda: %w", err)
-		}
-
-		return existing.ID, nil
-	}
-
-	// Create new preco venda
 	precoVenda := &PrecoVenda{
-		IdIntegracao:                idIntegracao,
+		IdIntegracao:                fmt.Sprintf("%d", ext.ID),
 		Preco:                       ext.Preco,
 		AceitaFinanciamentoBancario: ext.AceitaFinanciamentoBancario,
 		AceitaFinanciamentoDireto:   ext.AceitaFinanciamentoDireto,
@@ -407,16 +705,15 @@ func (is *importService) upsertPrecoVenda(_ context.Context, ext *ExternalPrecoV
 		AceitaFGTS:                  ext.AceitaFGTS,
 		Ativo:                       ext.Ativo,
 	}
-
-	if err := is.service.(*service).repo.(*repository).db.Create(precoVenda).Error; err != nil {
-		return 0, fmt.Errorf("failed to create preco venda: %w", err)
+	if err := is.repo.UpsertPrecoVenda(ctx, precoVenda); err != nil {
+		return 0, fmt.Errorf("failed to upsert preco venda: %w", err)
 	}
 
 	return precoVenda.ID, nil
 }
 
 // upsertPrecoAluguel creates or updates a rental price record
-func (is *importService) upsertPrecoAluguel(_ context.Context, ext *ExternalPrecoAluguel) (uint, error) {
+func (is *importService) upsertPrecoAluguel(ctx context.Context, ext *ExternalPrecoAluguel) (uint, error) {
 	if ext == nil {
 		return 0, fmt.Errorf("preco aluguel is nil")
 	}
@@ -425,169 +722,93 @@ func (is *importService) upsertPrecoAluguel(_ context.Context, ext *ExternalPrec
 		return 0, fmt.Errorf("preco aluguel has no valid external ID")
 	}
 
-	idIntegracao := fmt.Sprintf("%d", ext.ID)
-
-	// Check if preco aluguel with this external ID already exists
-	var existing PrecoAluguel
-	err := is.service.(*service).repo.(*repository).db.
-		Where("id_integracao = ?", idIntegracao).
-		First(&existing).Error
-
-	if err == nil {
-		// Preco aluguel exists, update it and return its local ID
-		existing.Preco = ext.Preco
-		existing.AceitaFiador = ext.AceitaFiador
-		existing.Ativo = ext.Ativo
-
-		if err := is.service.(*service).repo.(*repository).db.Save(&existing).Error; err != nil {
-			return 0, fmt.Errorf("failed to update preco aluguel: %w", err)
-		}
-
-		return existing.ID, nil
-	}
-
-	// Create new preco aluguel
 	precoAluguel := &PrecoAluguel{
-		IdIntegracao: idIntegracao,
+		IdIntegracao: fmt.Sprintf("%d", ext.ID),
 		Preco:        ext.Preco,
 		AceitaFiador: ext.AceitaFiador,
 		Ativo:        ext.Ativo,
 	}
-
-	if err := is.service.(*service).repo.(*repository).db.Create(precoAluguel).Error; err != nil {
-		return 0, fmt.Errorf("failed to create preco aluguel: %w", err)
+	if err := is.repo.UpsertPrecoAluguel(ctx, precoAluguel); err != nil {
+		return 0, fmt.Errorf("failed to upsert preco aluguel: %w", err)
 	}
 
 	return precoAluguel.ID, nil
 }
 
-// setHeaders adds required API headers to the request
-func (is *importService) setHeaders(req *http.Request) {
-	req.Header.Set("x-api-key", is.apiKey)
-	req.Header.Set("x-integration-source", is.integrationSource)
-	req.Header.Set("Content-Type", "application/json")
+// inactivePrices are the local IDs of the prices of a listing the source deactivated, 0 when none
+type inactivePrices struct {
+	precoVendaID, precoAluguelID uint
 }
 
-// fetchPublishedList fetches the list of published properties
-func (is *importService) fetchPublishedList(ctx context.Context, url string) ([]ExternalImovel, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	is.setHeaders(req)
-
-	resp, err := is.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch properties: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("Failed to close response body: %v", err)
+// deactivatePrices flags as inactive the imported prices the source deactivated in the listing
+func (is *importService) deactivatePrices(ctx context.Context, ext *ExternalDetailedImovel) (inactivePrices, error) {
+	var inactive inactivePrices
+	var err error
+	if ext.PrecoVenda != nil && !ext.PrecoVenda.Ativo && ext.PrecoVenda.ID != 0 {
+		if inactive.precoVendaID, err = is.repo.DeactivatePrecoVenda(ctx, fmt.Sprintf("%d", ext.PrecoVenda.ID)); err != nil {
+			return inactivePrices{}, fmt.Errorf("failed to deactivate preco venda: %w", err)
 		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("external API returned status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	var apiResp ExternalAPIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if ext.PrecoAluguel != nil && !ext.PrecoAluguel.Ativo && ext.PrecoAluguel.ID != 0 {
+		if inactive.precoAluguelID, err = is.repo.DeactivatePrecoAluguel(ctx, fmt.Sprintf("%d", ext.PrecoAluguel.ID)); err != nil {
+			return inactivePrices{}, fmt.Errorf("failed to deactivate preco aluguel: %w", err)
+		}
 	}
-
-	return apiResp.Results.Entities, nil
+	return inactive, nil
 }
 
-// transformExternalToCreateRequest converts external API response to CreateImovelRequest
-func (is *importService) transformExternalToCreateRequest(ext *ExternalDetailedImovel, enderecoID uint, empreendimentoID uint, precoVendaID uint, precoAluguelID uint, corretorPrincipalID uint) *CreateImovelRequest {
-	// Default values
-	descricao := ext.Descricao
-	if descricao == "" {
-		descricao = fmt.Sprintf("%s - %s", ext.Titulo, ext.Tipo)
-	}
-
-	req := &CreateImovelRequest{
-		IdIntegracao:        fmt.Sprintf("%d", ext.ID),
-		Titulo:              ext.Titulo,
-		Codigo:              ext.Codigo,
-		Tipo:                ext.Tipo,
-		Objetivo:            ext.Objetivo,
-		Finalidade:          ext.Finalidade,
-		Descricao:           descricao,
-		Metragem:            ext.Metragem,
-		NumQuartos:          ext.NumQuartos,
-		NumSuites:           ext.NumSuites,
-		NumBanheiros:        ext.NumBanheiros,
-		NumVagas:            ext.NumVagas,
-		NumAndar:            ext.NumAndar,
-		Unidade:             ext.Unidade,
-		Condominio:          ext.Condominio,
-		EnderecoID:          enderecoID,
-		CorretorPrincipalID: corretorPrincipalID,
+// detachInactivePrices detaches from the property the deactivated prices still attached to it,
+// but for the one its objetivo requires (the selling price to VENDER, the rental price to
+// ALUGAR), which stays attached as inactive
+func (is *importService) detachInactivePrices(ctx context.Context, imovel *ImovelResponse, inactive inactivePrices) (*ImovelResponse, error) {
+	patch := &PatchImovelRequest{}
+	detach := false
+	if inactive.precoVendaID != 0 && imovel.PrecoVenda != nil && imovel.PrecoVenda.ID == inactive.precoVendaID {
+		if imovel.Objetivo == "VENDER" {
+			importLogger(ctx).Warn("Source deactivated the selling price of a property for sale, kept attached", "imovel_id", imovel.ID)
+		} else {
+			patch.PrecoVendaID = Nullable[uint]{Set: true, Null: true}
+			detach = true
+		}
 	}
-
-	// Only set empreendimento if it was created successfully
-	if empreendimentoID != 0 {
-		req.EmpreendimentoID = empreendimentoID
+	if inactive.precoAluguelID != 0 && imovel.PrecoAluguel != nil && imovel.PrecoAluguel.ID == inactive.precoAluguelID {
+		if imovel.Objetivo == "ALUGAR" {
+			importLogger(ctx).Warn("Source deactivated the rental price of a property for rent, kept attached", "imovel_id", imovel.ID)
+		} else {
+			patch.PrecoAluguelID = Nullable[uint]{Set: true, Null: true}
+			detach = true
+		}
 	}
-
-	// Set pricing IDs if they were created successfully
-	if precoVendaID != 0 {
-		req.PrecoVendaID = precoVendaID
+	if !detach {
+		return imovel, nil
 	}
 
-	if precoAluguelID != 0 {
-		req.PrecoAluguelID = precoAluguelID
+	detached, err := is.service.PatchImovel(ctx, imovel.ID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detach inactive prices: %w", err)
 	}
-
-	return req
+	return detached, nil
 }
 
 // upsertOrganizacao creates or updates organizacao and returns its ID
-func (is *importService) upsertOrganizacao(_ context.Context, extOrg *ExternalOrganizacao) (uint, error) {
+func (is *importService) upsertOrganizacao(ctx context.Context, extOrg *ExternalOrganizacao) (uint, error) {
 	if extOrg == nil || extOrg.Nome == "" {
 		return 0, fmt.Errorf("organizacao is empty")
 	}
 
-	// Try to find existing organizacao by external ID
-	var org Organizacao
-
-	// Since we don't have IdIntegracao in Organizacao model, we search by Nome
-	// This assumes Nome is unique for organizations
-	result := is.service.(*service).repo.(*repository).db.Where("nome = ?", extOrg.Nome).First(&org)
-
-	if result.Error == nil {
-		// Organizacao exists, update if needed
-		if org.Perfil != extOrg.Perfil {
-			org.Perfil = extOrg.Perfil
-			if err := is.service.(*service).repo.(*repository).db.Save(&org).Error; err != nil {
-				return 0, fmt.Errorf("failed to update organizacao: %w", err)
-			}
-		}
-		return org.ID, nil
-	}
-
-	// Create new organizacao
-	org = Organizacao{
+	org := &Organizacao{
 		Nome:   extOrg.Nome,
 		Perfil: extOrg.Perfil,
 	}
-
-	if err := is.service.(*service).repo.(*repository).db.Create(&org).Error; err != nil {
-		return 0, fmt.Errorf("failed to create organizacao: %w", err)
+	if err := is.repo.UpsertOrganizacao(ctx, org); err != nil {
+		return 0, fmt.Errorf("failed to upsert organizacao: %w", err)
 	}
 
 	return org.ID, nil
 }
 
 // upsertCorretorPrincipal creates or updates corretor principal and returns its ID
-func (is *importService) upsertCorretorPrincipal(ctx context.Context, extCorretor *ExternalCorretor) (uint, error) {
+func (is *importService) upsertCorretorPrincipal(ctx context.Context, extCorretor *ExternalCorretor, images listingImages) (uint, error) {
 	if extCorretor == nil || extCorretor.Email == "" {
 		return 0, fmt.Errorf("corretor principal is empty")
 	}
@@ -602,43 +823,8 @@ func (is *importService) upsertCorretorPrincipal(ctx context.Context, extCorreto
 		organizacaoID = orgID
 	}
 
-	// Try to find existing corretor by IdIntegracao
-	var corretor CorretorPrincipal
-	idIntegracao := fmt.Sprintf("%d", extCorretor.ID)
-
-	result := is.service.(*service).repo.(*repository).db.Where("id_integracao = ?", idIntegracao).First(&corretor)
-
-	if result.Error == nil {
-		// Corretor exists, update if needed
-		updated := false
-		if corretor.Nome != extCorretor.Nome {
-			corretor.Nome = extCorretor.Nome
-			updated = true
-		}
-		if corretor.Email != extCorretor.Email {
-			corretor.Email = extCorretor.Email
-			updated = true
-		}
-		if corretor.Whatsapp != extCorretor.Whatsapp {
-			corretor.Whatsapp = extCorretor.Whatsapp
-			updated = true
-		}
-		if organizacaoID != 0 && corretor.OrganizacaoID != organizacaoID {
-			corretor.OrganizacaoID = organizacaoID
-			updated = true
-		}
-
-		if updated {
-			if err := is.service.(*service).repo.(*repository).db.Save(&corretor).Error; err != nil {
-				return 0, fmt.Errorf("failed to update corretor principal: %w", err)
-			}
-		}
-		return corretor.ID, nil
-	}
-
-	// Create new corretor principal
-	corretor = CorretorPrincipal{
-		IdIntegracao:   idIntegracao,
+	corretor := &CorretorPrincipal{
+		IdIntegracao:   fmt.Sprintf("%d", extCorretor.ID),
 		Nome:           extCorretor.Nome,
 		Email:          extCorretor.Email,
 		Whatsapp:       extCorretor.Whatsapp,
@@ -646,43 +832,13 @@ func (is *importService) upsertCorretorPrincipal(ctx context.Context, extCorreto
 		BairrosAtuacao: extCorretor.BairrosAtuacao,
 		OrganizacaoID:  organizacaoID,
 	}
-
-	// Don't set FotoID -it will be NULL by default (uint zero value causes FK violation)
-	if err := is.service.(*service).repo.(*repository).db.Omit("FotoID").Create(&corretor).Error; err != nil {
-		return 0, fmt.Errorf("failed to create corretor principal: %w", err)
-	}
-
-	return corretor.ID, nil
-}
-
-// syncAnexosFromImages synchronizes image attachments for a property
-// Deletes all existing anexos for this property and recreates them from current external API data
-// This ensures that removed images are deleted and new images are added correctly
-func (is *importService) syncAnexosFromImages(ctx context.Context, imovelID uint, imageURLs []string) error {
-	// Step 1: Delete all existing anexos for this property
-	// This ensures removed images from external API are also removed locally
-	db := is.service.(*service).repo.(*repository).db
-	if err := db.Where("imovel_id = ?", imovelID).Delete(&Anexo{}).Error; err != nil {
-		return fmt.Errorf("failed to delete existing anexos: %w", err)
+	if err := is.repo.UpsertCorretor(ctx, corretor); err != nil {
+		return 0, fmt.Errorf("failed to upsert corretor principal: %w", err)
 	}
-
-	// Step 2: Create new anexos from current external API data
-	for i, imageURL := range imageURLs {
-		anexo := &Anexo{
-			Nome:          fmt.Sprintf("Image %d", i+1),
-			URL:           imageURL,
-			Tipo:          "image",
-			Image:         true,
-			Video:         false,
-			IsExternalURL: true,
-			CanPublish:    true,
-		}
-
-		if err := is.service.AddAnexo(ctx, imovelID, anexo); err != nil {
-			return fmt.Errorf("failed to add image %d: %w", i+1, err)
-		}
+	// The photo is synced apart
+	if err := is.syncCorretorFoto(ctx, corretor, extCorretor.Foto, images); err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("Synced %d anexos for property ID %d\n", len(imageURLs), imovelID)
-	return nil
+	return corretor.ID, nil
 }
@@ -0,0 +1,101 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+)
+
+// SourceDriverPI8 is the driver of the pi8 CRM API, used when externalapi.driver is not set
+const SourceDriverPI8 = "pi8"
+
+// ExternalSource is a provider the importer reads listings from, a CRM or portal. Drivers return
+// their listings as the External* types, so the upsert logic is the same for every provider.
+type ExternalSource interface {
+	// ListPublished returns the published listings, only those updated since the given time when it is set
+	ListPublished(ctx context.Context, since *time.Time) ([]ExternalImovel, error)
+	// GetDetails returns a listing with its relations
+	GetDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error)
+	// MapToCreateRequest builds the property of a new listing, linked to its relations already written
+	MapToCreateRequest(ext *ExternalDetailedImovel, relations ImportRelations) *CreateImovelRequest
+}
+
+// ImportRelations are the records written for the relations of a listing before its property;
+// an ID is zero when the listing has no such relation
+type ImportRelations struct {
+	EnderecoID          uint
+	EmpreendimentoID    uint
+	PrecoVendaID        uint
+	PrecoAluguelID      uint
+	CorretorPrincipalID uint
+}
+
+// newExternalSource creates the source selected by extCfg.Driver, calling its API through client
+func newExternalSource(extCfg *config.ExternalAPIConfig, client *sourceClient) (ExternalSource, error) {
+	switch extCfg.Driver {
+	case "", SourceDriverPI8:
+		return newPI8Source(extCfg, client), nil
+	default:
+		return nil, fmt.Errorf("unknown external source driver %q", extCfg.Driver)
+	}
+}
+
+// sourceClient sends the requests of a source driver: they wait their turn on the rate limit, go
+// through the circuit breaker and are retried on transient failures. The limit and the breaker are
+// shared by every run.
+type sourceClient struct {
+	http *httpclient.Client
+	// httpClient holds the connections to the source, shared with the image downloads
+	httpClient *http.Client
+	maxRetries int
+	retryBase  time.Duration
+	breaker    *circuitBreaker
+	limiter    *rate.Limiter
+}
+
+func newSourceClient(extCfg *config.ExternalAPIConfig, workers int) *sourceClient {
+	timeout := time.Duration(extCfg.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	retryBase := time.Duration(extCfg.RetryBaseMs) * time.Millisecond
+	if retryBase <= 0 {
+		retryBase = defaultImportRetryBase
+	}
+	cooldown := time.Duration(extCfg.BreakerCooldown) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultImportBreakerCooldown
+	}
+
+	// The workers share the connections, so the source never sees more requests at once than workers
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = workers
+	transport.MaxIdleConnsPerHost = workers
+
+	c := &sourceClient{
+		httpClient: &http.Client{Transport: transport},
+		maxRetries: extCfg.MaxRetries,
+		retryBase:  retryBase,
+		breaker:    newCircuitBreaker(extCfg.BreakerThreshold, cooldown),
+		limiter:    newImportLimiter(extCfg.RateLimit, extCfg.RateBurst),
+	}
+	c.http = httpclient.New("external API",
+		httpclient.WithHTTPClient(c.httpClient),
+		httpclient.WithTimeout(timeout),
+		httpclient.WithRetry(c.retry),
+		httpclient.WithHooks(httpclient.Hooks{
+			// Retries take their turn on the rate limit too
+			BeforeAttempt: func(ctx context.Context, req *http.Request) error { return c.throttle(ctx, req.URL.String()) },
+			OnRetry:       c.logRetry,
+		}),
+		httpclient.WithLogger(importLogger),
+		httpclient.WithResponseLogging(extCfg.LogFailedResponses),
+	)
+	return c
+}
@@ -0,0 +1,146 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+const (
+	// defaultImportPageSize applies when externalapi.page_size is not configured
+	defaultImportPageSize = 100
+	// maxImportListPages stops paging through a list that never ends
+	maxImportListPages = 1000
+)
+
+// pi8Source reads the published listings of the pi8 CRM API, whose responses the External* types
+// mirror
+type pi8Source struct {
+	client            *sourceClient
+	baseURL           string
+	apiKey            string
+	integrationSource string
+	pageSize          int
+}
+
+func newPI8Source(extCfg *config.ExternalAPIConfig, client *sourceClient) *pi8Source {
+	pageSize := extCfg.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultImportPageSize
+	}
+	return &pi8Source{
+		client:            client,
+		baseURL:           extCfg.BaseURL,
+		apiKey:            extCfg.APIKey,
+		integrationSource: extCfg.IntegrationSource,
+		pageSize:          pageSize,
+	}
+}
+
+// header returns the required API headers
+func (s *pi8Source) header() http.Header {
+	header := http.Header{}
+	header.Set("x-api-key", s.apiKey)
+	header.Set("x-integration-source", s.integrationSource)
+	header.Set("Content-Type", "application/json")
+	return header
+}
+
+// ListPublished fetches the list of published properties page by page. Paging stops on a short
+// page, past the reported total pages or when a page brings no new property (a source ignoring
+// the pagination parameters).
+func (s *pi8Source) ListPublished(ctx context.Context, since *time.Time) ([]ExternalImovel, error) {
+	var properties []ExternalImovel
+	seen := map[uint]bool{}
+	for page := 1; page <= maxImportListPages; page++ {
+		results, err := s.fetchPublishedPage(ctx, page, since)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		added := 0
+		for _, entity := range results.Entities {
+			if seen[entity.ID] {
+				continue
+			}
+			seen[entity.ID] = true
+			properties = append(properties, entity)
+			added++
+		}
+
+		if added == 0 || len(results.Entities) < s.pageSize || (results.TotalPages > 0 && page >= results.TotalPages) {
+			break
+		}
+	}
+
+	return properties, nil
+}
+
+// fetchPublishedPage fetches one page of the list of published properties
+func (s *pi8Source) fetchPublishedPage(ctx context.Context, page int, since *time.Time) (*ExternalResults, error) {
+	params := url.Values{}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("limit", strconv.Itoa(s.pageSize))
+	if since != nil {
+		params.Set("updated_since", since.UTC().Format(time.RFC3339))
+	}
+	listURL := fmt.Sprintf("%s/api/properties/published?%s", s.baseURL, params.Encode())
+
+	var apiResp ExternalAPIResponse
+	if err := s.client.get(ctx, listURL, s.header(), &apiResp); err != nil {
+		return nil, err
+	}
+
+	return &apiResp.Results, nil
+}
+
+// GetDetails fetches detailed property information including empreendimento
+func (s *pi8Source) GetDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error) {
+	detailURL := fmt.Sprintf("%s/api/properties/published/%d", s.baseURL, externalID)
+
+	var result struct {
+		Results ExternalDetailedImovel `json:"results"`
+	}
+	if err := s.client.get(ctx, detailURL, s.header(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Results, nil
+}
+
+// MapToCreateRequest converts a pi8 listing to CreateImovelRequest
+func (s *pi8Source) MapToCreateRequest(ext *ExternalDetailedImovel, relations ImportRelations) *CreateImovelRequest {
+	// Default values
+	descricao := ext.Descricao
+	if descricao == "" {
+		descricao = fmt.Sprintf("%s - %s", ext.Titulo, ext.Tipo)
+	}
+
+	return &CreateImovelRequest{
+		IdIntegracao:        fmt.Sprintf("%d", ext.ID),
+		Titulo:              ext.Titulo,
+		Codigo:              ext.Codigo,
+		Tipo:                ext.Tipo,
+		Objetivo:            ext.Objetivo,
+		Finalidade:          ext.Finalidade,
+		Descricao:           descricao,
+		Metragem:            ext.Metragem,
+		NumQuartos:          ext.NumQuartos,
+		NumSuites:           ext.NumSuites,
+		NumBanheiros:        ext.NumBanheiros,
+		NumVagas:            ext.NumVagas,
+		NumAndar:            ext.NumAndar,
+		Unidade:             ext.Unidade,
+		Condominio:          ext.Condominio,
+		EnderecoID:          relations.EnderecoID,
+		EmpreendimentoID:    relations.EmpreendimentoID,
+		PrecoVendaID:        relations.PrecoVendaID,
+		PrecoAluguelID:      relations.PrecoAluguelID,
+		CorretorPrincipalID: relations.CorretorPrincipalID,
+	}
+}
@@ -0,0 +1,84 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// stubSource is a provider other than pi8, serving its listings from memory
+type stubSource struct {
+	listings []ExternalImovel
+}
+
+func (s *stubSource) ListPublished(ctx context.Context, since *time.Time) ([]ExternalImovel, error) {
+	return s.listings, nil
+}
+
+func (s *stubSource) GetDetails(ctx context.Context, externalID uint) (*ExternalDetailedImovel, error) {
+	for _, listing := range s.listings {
+		if listing.ID == externalID {
+			return &ExternalDetailedImovel{
+				ID: listing.ID, Codigo: listing.Codigo, Titulo: listing.Titulo, Tipo: listing.Tipo,
+				Objetivo: listing.Objetivo, Finalidade: listing.Finalidade, Metragem: listing.Metragem,
+				PrecoVenda: listing.PrecoVenda,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("listing %d not found", externalID)
+}
+
+func (s *stubSource) MapToCreateRequest(ext *ExternalDetailedImovel, relations ImportRelations) *CreateImovelRequest {
+	return &CreateImovelRequest{
+		IdIntegracao: "stub-" + ext.Codigo,
+		Titulo:       ext.Titulo,
+		Codigo:       ext.Codigo,
+		Tipo:         ext.Tipo,
+		Objetivo:     ext.Objetivo,
+		Finalidade:   ext.Finalidade,
+		Descricao:    "Importado do portal",
+		Metragem:     ext.Metragem,
+		PrecoVendaID: relations.PrecoVendaID,
+	}
+}
+
+func TestImportPublishedProperties_OtherSource(t *testing.T) {
+	ctx := context.Background()
+	importer, database := setupImportService(t, &fakeExternalAPI{}, false)
+	source := &stubSource{listings: []ExternalImovel{externalListing(1), externalListing(2)}}
+	importer.source = source
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	imported := findImported(t, database, "1")
+	assert.Equal(t, "Importado do portal", imported.Descricao)
+	var preco PrecoVenda
+	require.NoError(t, database.First(&preco, imported.PrecoVendaID).Error)
+	assert.Equal(t, 450000.0, preco.Preco)
+
+	// Matched by the listing ID on the next run, whatever the driver set as id_integracao
+	source.listings[0].Titulo = "Apartamento reformado"
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "0 created, 2 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	assert.Equal(t, "Apartamento reformado", findImported(t, database, "1").Titulo)
+}
+
+func TestNewImportService_Driver(t *testing.T) {
+	svc, database := setupCreateService(t)
+
+	importer, err := NewImportService(svc, NewRepository(database), &config.ExternalAPIConfig{Driver: SourceDriverPI8})
+	require.NoError(t, err)
+	assert.IsType(t, &pi8Source{}, importer.(*importService).source)
+
+	_, err = NewImportService(svc, NewRepository(database), &config.ExternalAPIConfig{Driver: "vista"})
+	assert.EqualError(t, err, `unknown external source driver "vista"`)
+}
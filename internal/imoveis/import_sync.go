@@ -0,0 +1,50 @@
+package imoveis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// syncWatermarkOverlap is subtracted from the start of a run before it is saved as the watermark,
+// covering clock skew with the source; listings seen twice are skipped by their checksum
+const syncWatermarkOverlap = 5 * time.Minute
+
+// listingChecksum identifies the content of an external listing. The view count is left out since
+// it changes without the property being edited.
+func listingChecksum(ext *ExternalImovel) string {
+	listing := *ext
+	listing.Visualizacoes = 0
+
+	data, err := json.Marshal(listing)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// syncWatermark returns when the last run without failures of this integration source started,
+// or nil when there was none
+func (is *importService) syncWatermark(ctx context.Context) (*time.Time, error) {
+	state, err := is.repo.FindSyncState(ctx, is.integrationSource)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	return &state.SyncedAt, nil
+}
+
+// saveSyncWatermark records startedAt, less the overlap, as the watermark of the next incremental run
+func (is *importService) saveSyncWatermark(ctx context.Context, startedAt time.Time) error {
+	state := &ImportSyncState{
+		Source:   is.integrationSource,
+		SyncedAt: startedAt.Add(-syncWatermarkOverlap),
+	}
+	if err := is.repo.SaveSyncState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save import watermark: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package imoveis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// fakeExternalAPI serves a paginated published list and the property details
+type fakeExternalAPI struct {
+	mu             sync.Mutex
+	listings       []ExternalImovel
+	empreendimento *ExternalEmpreendimento
+	listRequests   []string
+	detailRequests []uint
+}
+
+func (f *fakeExternalAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/api/properties/published" {
+		f.listRequests = append(f.listRequests, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := min((page-1)*limit, len(f.listings))
+		end := min(start+limit, len(f.listings))
+
+		var resp ExternalAPIResponse
+		resp.Results.Entities = f.listings[start:end]
+		resp.Results.Total = len(f.listings)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/properties/published/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	f.detailRequests = append(f.detailRequests, uint(id))
+	for _, listing := range f.listings {
+		if listing.ID == uint(id) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": ExternalDetailedImovel{
+				ID:             listing.ID,
+				Codigo:         listing.Codigo,
+				Titulo:         listing.Titulo,
+				Tipo:           listing.Tipo,
+				Objetivo:       listing.Objetivo,
+				Finalidade:     listing.Finalidade,
+				Metragem:       listing.Metragem,
+				NumQuartos:     listing.NumQuartos,
+				PrecoVenda:     listing.PrecoVenda,
+				PrecoAluguel:   listing.PrecoAluguel,
+				Imagens:        listing.Imagens,
+				Empreendimento: f.empreendimento,
+			}})
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (f *fakeExternalAPI) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listRequests = nil
+	f.detailRequests = nil
+}
+
+func externalListing(id uint) ExternalImovel {
+	return ExternalImovel{
+		ID:         id,
+		Codigo:     fmt.Sprintf("EXT-%03d", id),
+		Titulo:     fmt.Sprintf("Apartamento %d", id),
+		Tipo:       "APARTAMENTO",
+		Objetivo:   "VENDER",
+		Finalidade: "RESIDENTIAL",
+		Metragem:   70,
+		PrecoVenda: &ExternalPrecoVenda{ID: 100 + id, Preco: 450000, Ativo: true},
+	}
+}
+
+func setupImportService(t *testing.T, api *fakeExternalAPI, incremental bool) (*importService, *gorm.DB) {
+	t.Helper()
+	svc, database := setupCreateService(t)
+	require.NoError(t, database.AutoMigrate(&ImportSyncState{}, &ImportRun{}, &ImportRunItem{}, &ImportConflict{}, &ImportQuarantine{}))
+	// Each connection to :memory: is a new database; the import workers must share one
+	sqlDB, err := database.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	importer, err := NewImportService(svc, NewRepository(database), &config.ExternalAPIConfig{
+		BaseURL:           server.URL,
+		IntegrationSource: "pi8",
+		PageSize:          2,
+		Incremental:       incremental,
+		Workers:           4,
+	})
+	require.NoError(t, err)
+	return importer.(*importService), database
+}
+
+func TestImportPublishedProperties_Paginated(t *testing.T) {
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "3 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	assert.Equal(t, []string{"limit=2&page=1", "limit=2&page=2"}, api.listRequests)
+	var count int64
+	require.NoError(t, database.Model(&Imovel{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestImportPublishedProperties_StopsWhenPaginationIsIgnored(t *testing.T) {
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2)}}
+	importer, _ := setupImportService(t, api, false)
+
+	// A source ignoring page returns the same first page forever
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/properties/published" {
+			r.URL.RawQuery = "page=1&limit=2"
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	source := importer.source.(*pi8Source)
+	source.baseURL = server.URL
+
+	properties, err := source.ListPublished(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, properties, 2)
+	assert.Len(t, api.listRequests, 2)
+}
+
+func TestImportPublishedProperties_Incremental(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2)}}
+	importer, database := setupImportService(t, api, true)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "2 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	assert.NotContains(t, api.listRequests[0], "updated_since")
+
+	var state ImportSyncState
+	require.NoError(t, database.First(&state, "source = ?", "pi8").Error)
+
+	t.Run("skips unchanged listings and asks for changes since the last run", func(t *testing.T) {
+		api.reset()
+		api.listings[1].Titulo = "Apartamento reformado"
+		api.listings[0].Visualizacoes = 250
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 1 updated, 1 unchanged, 0 removed, 0 failed", result.String())
+
+		assert.Contains(t, api.listRequests[0], "updated_since=")
+		assert.Equal(t, []uint{2}, api.detailRequests)
+		imovel, err := importer.service.GetImovelByIdIntegracao(ctx, "2")
+		require.NoError(t, err)
+		assert.Equal(t, "Apartamento reformado", imovel.Titulo)
+	})
+
+	t.Run("full run imports every listing", func(t *testing.T) {
+		api.reset()
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{Full: true})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 2 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+		assert.NotContains(t, api.listRequests[0], "updated_since")
+		assert.ElementsMatch(t, []uint{1, 2}, api.detailRequests)
+	})
+
+	t.Run("failed run keeps the watermark", func(t *testing.T) {
+		require.NoError(t, database.Model(&ImportSyncState{}).Where("source = ?", "pi8").
+			Update("synced_at", state.SyncedAt).Error)
+		api.reset()
+		// Properties for sale cannot be created without a selling price
+		failing := externalListing(3)
+		failing.PrecoVenda = nil
+		api.listings = append(api.listings, failing)
+
+		result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 created, 0 updated, 2 unchanged, 0 removed, 1 failed", result.String())
+
+		var after ImportSyncState
+		require.NoError(t, database.First(&after, "source = ?", "pi8").Error)
+		assert.True(t, state.SyncedAt.Equal(after.SyncedAt))
+	})
+}
@@ -0,0 +1,85 @@
+package imoveis
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// slowThrottleWait is the wait on the rate limit above which a request is logged
+const slowThrottleWait = 5 * time.Second
+
+// newImportLimiter returns the limiter of the requests to the external API, or nil when
+// requestsPerSecond is zero; a burst below one lets a single request through at a time
+func newImportLimiter(requestsPerSecond, burst int) *rate.Limiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+}
+
+// throttleStats counts the requests of a run held back by the rate limit, and how long they waited
+type throttleStats struct {
+	requests atomic.Int64
+	wait     atomic.Int64
+}
+
+func (s *throttleStats) record(wait time.Duration) {
+	s.requests.Add(1)
+	s.wait.Add(int64(wait))
+}
+
+// snapshot returns the requests held back so far and their total wait
+func (s *throttleStats) snapshot() (int, time.Duration) {
+	return int(s.requests.Load()), time.Duration(s.wait.Load())
+}
+
+// fill copies the stats into progress
+func (s *throttleStats) fill(progress *ImportProgress) {
+	requests, wait := s.snapshot()
+	progress.Throttled = requests
+	progress.ThrottleMs = wait.Milliseconds()
+}
+
+type throttleStatsKey struct{}
+
+// withThrottleStats returns a context whose requests to the external API record their waits in stats
+func withThrottleStats(ctx context.Context, stats *throttleStats) context.Context {
+	return context.WithValue(ctx, throttleStatsKey{}, stats)
+}
+
+// throttle waits until the rate limit lets a request through to the external API, recording the
+// wait in the stats of ctx. The turn is given back when ctx is done first.
+func (c *sourceClient) throttle(ctx context.Context, url string) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	reservation := c.limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+
+	if stats, ok := ctx.Value(throttleStatsKey{}).(*throttleStats); ok {
+		stats.record(delay)
+	}
+	if delay >= slowThrottleWait {
+		importLogger(ctx).Warn("Slow wait on the external API rate limit", "url", url, "wait", delay.Round(time.Millisecond))
+	}
+	return nil
+}
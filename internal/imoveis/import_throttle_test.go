@@ -0,0 +1,64 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestImportPublishedProperties_RateLimited(t *testing.T) {
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1), externalListing(2), externalListing(3)}}
+	importer, database := setupImportService(t, api, false)
+	importer.client.limiter = newImportLimiter(50, 1)
+
+	var last ImportProgress
+	startedAt := time.Now()
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{
+		Progress: func(progress ImportProgress) { last = progress },
+	})
+	require.NoError(t, err)
+	require.Equal(t, "3 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+
+	// Two list pages and three details, one every 20ms after the first
+	assert.GreaterOrEqual(t, time.Since(startedAt), 80*time.Millisecond)
+
+	var run ImportRun
+	require.NoError(t, database.Order("id DESC").First(&run).Error)
+	assert.GreaterOrEqual(t, run.Throttled, 4)
+	assert.Positive(t, run.ThrottleMs)
+	assert.Equal(t, run.Throttled, last.Throttled)
+	assert.Equal(t, run.ThrottleMs, last.ThrottleMs)
+	assert.Equal(t, run.Throttled, result.Throttled)
+}
+
+func TestThrottle(t *testing.T) {
+	client := &sourceClient{limiter: rate.NewLimiter(rate.Every(time.Hour), 1)}
+	stats := &throttleStats{}
+	ctx := withThrottleStats(context.Background(), stats)
+
+	require.NoError(t, client.throttle(ctx, "/first"))
+
+	// A request cancelled while waiting gives its turn back and is not counted
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.throttle(cancelled, "/second"), context.DeadlineExceeded)
+	requests, wait := stats.snapshot()
+	assert.Zero(t, requests)
+	assert.Zero(t, wait)
+
+	unlimited := &sourceClient{}
+	assert.NoError(t, unlimited.throttle(ctx, "/any"))
+}
+
+func TestNewImportLimiter(t *testing.T) {
+	assert.Nil(t, newImportLimiter(0, 5))
+
+	limiter := newImportLimiter(10, 0)
+	require.NotNil(t, limiter)
+	assert.Equal(t, rate.Limit(10), limiter.Limit())
+	assert.Equal(t, 1, limiter.Burst())
+}
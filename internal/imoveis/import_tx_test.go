@@ -0,0 +1,125 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// failAnexoInserts makes every insert into anexos fail, the last step of a property import
+func failAnexoInserts(t *testing.T, database *gorm.DB) {
+	t.Helper()
+	require.NoError(t, database.Callback().Create().Before("gorm:create").Register("test:fail_anexos", func(tx *gorm.DB) {
+		if tx.Statement.Table == "anexos" {
+			_ = tx.AddError(errors.New("disk full"))
+		}
+	}))
+	t.Cleanup(func() { _ = database.Callback().Create().Remove("test:fail_anexos") })
+}
+
+func countRows(t *testing.T, database *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, database.Model(model).Count(&count).Error)
+	return count
+}
+
+func TestImportListing_RollsBackFailedCreate(t *testing.T) {
+	listing := externalListing(1)
+	listing.Imagens = []string{"https://cdn.example.com/1.jpg"}
+	api := &fakeExternalAPI{
+		listings:       []ExternalImovel{listing, externalListing(2)},
+		empreendimento: &ExternalEmpreendimento{ID: 50, Titulo: "Residencial Aurora"},
+	}
+	importer, database := setupImportService(t, api, false)
+	failAnexoInserts(t, database)
+
+	result, err := importer.ImportPublishedProperties(context.Background(), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 1 failed", result.String())
+
+	// Only the listing without images went through, with its own price
+	assert.Equal(t, int64(1), countRows(t, database, &Imovel{}))
+	assert.Equal(t, int64(1), countRows(t, database, &PrecoVenda{}))
+	assert.Equal(t, int64(1), countRows(t, database, &Empreendimento{}))
+	var audits int64
+	require.NoError(t, database.Model(&ImovelAudit{}).Where("entidade = ? AND acao = ?", AuditEntidadeImovel, AuditAcaoCreate).Count(&audits).Error)
+	assert.Equal(t, int64(1), audits)
+
+	var item ImportRunItem
+	require.NoError(t, database.First(&item).Error)
+	assert.Equal(t, uint(1), item.ExternalID)
+	assert.Contains(t, item.Error, "disk full")
+}
+
+func TestImportListing_RollsBackFailedUpdate(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+	importer, database := setupImportService(t, api, false)
+
+	result, err := importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 0 unchanged, 0 removed, 0 failed", result.String())
+	before := findImported(t, database, "1")
+	history := countRows(t, database, &HistoricoPreco{})
+
+	api.listings[0].Titulo = "Apartamento reformado"
+	api.listings[0].PrecoVenda.Preco = 480000
+	api.listings[0].Imagens = []string{"https://cdn.example.com/1.jpg"}
+	failAnexoInserts(t, database)
+
+	result, err = importer.ImportPublishedProperties(ctx, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 created, 0 updated, 0 unchanged, 0 removed, 1 failed", result.String())
+
+	after := findImported(t, database, "1")
+	assert.Equal(t, before.Titulo, after.Titulo)
+	assert.Equal(t, before.Version, after.Version)
+	assert.Equal(t, before.ImportChecksum, after.ImportChecksum)
+
+	var preco PrecoVenda
+	require.NoError(t, database.First(&preco, after.PrecoVendaID).Error)
+	assert.Equal(t, 450000.0, preco.Preco)
+	assert.Equal(t, history, countRows(t, database, &HistoricoPreco{}))
+}
+
+func TestTransaction_AfterCommit(t *testing.T) {
+	_, database := setupCreateService(t)
+	repo := NewRepository(database)
+	ctx := context.Background()
+
+	var ran []string
+	err := repo.Transaction(ctx, func(txCtx context.Context) error {
+		afterCommit(txCtx, func() { ran = append(ran, "outer") })
+
+		// A savepoint rolled back drops its hooks only
+		_ = repo.Transaction(txCtx, func(nestedCtx context.Context) error {
+			afterCommit(nestedCtx, func() { ran = append(ran, "rolled back") })
+			return errors.New("boom")
+		})
+		require.NoError(t, repo.Transaction(txCtx, func(nestedCtx context.Context) error {
+			afterCommit(nestedCtx, func() { ran = append(ran, "nested") })
+			return nil
+		}))
+
+		assert.Empty(t, ran)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "nested"}, ran)
+
+	ran = nil
+	err = repo.Transaction(ctx, func(txCtx context.Context) error {
+		afterCommit(txCtx, func() { ran = append(ran, "rolled back") })
+		return errors.New("boom")
+	})
+	assert.Error(t, err)
+	assert.Empty(t, ran)
+
+	afterCommit(ctx, func() { ran = append(ran, "no transaction") })
+	assert.Equal(t, []string{"no transaction"}, ran)
+}
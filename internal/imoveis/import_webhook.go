@@ -0,0 +1,219 @@
+package imoveis
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256, under externalapi.webhook_secret and
+	// optionally prefixed with "sha256=", of "<timestamp>.<delivery id>.<body>"
+	WebhookSignatureHeader = "X-Pi8-Signature"
+	// WebhookTimestampHeader carries the Unix time, in seconds, the event was signed at
+	WebhookTimestampHeader = "X-Pi8-Timestamp"
+	// WebhookDeliveryHeader carries the ID of the delivery, kept when the event is sent again
+	WebhookDeliveryHeader = "X-Pi8-Delivery"
+)
+
+const (
+	// maxWebhookBodyBytes caps the events read from the webhook
+	maxWebhookBodyBytes = 64 << 10
+	// defaultWebhookTolerance applies when externalapi.webhook_tolerance is not configured
+	defaultWebhookTolerance = 5 * time.Minute
+	// webhookDeliveriesSize bounds the remembered delivery IDs
+	webhookDeliveriesSize = 10000
+	// maxWebhookDeliveryIDLength bounds the delivery IDs kept in memory
+	maxWebhookDeliveryIDLength = 200
+)
+
+// Events the external API pushes about its listings
+const (
+	WebhookEventCreated = "property.created"
+	WebhookEventUpdated = "property.updated"
+	WebhookEventDeleted = "property.deleted"
+)
+
+var (
+	// ErrWebhookDisabled is returned when no webhook secret is configured
+	ErrWebhookDisabled = errors.New("webhook is not configured")
+	// ErrInvalidWebhookSignature is returned when the body does not match its signature
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrStaleWebhook is returned when the signed timestamp is outside the tolerance window
+	ErrStaleWebhook = errors.New("webhook timestamp outside the tolerance window")
+	// ErrInvalidWebhookEvent is returned for a payload that is not a known event about a listing
+	ErrInvalidWebhookEvent = errors.New("invalid webhook event")
+)
+
+// WebhookHeaders are the signature headers of a webhook request
+type WebhookHeaders struct {
+	Signature  string
+	Timestamp  string
+	DeliveryID string
+}
+
+// webhookDeliveries remembers the delivery IDs received within the tolerance window, so a signed
+// request captured and sent again is not applied twice. Older requests are rejected by their
+// timestamp. It is in memory and per instance: with several replicas a replay may be applied
+// once per replica, which only repeats an import of the current state of the listing.
+type webhookDeliveries struct {
+	mu   sync.Mutex
+	seen *expirable.LRU[string, struct{}]
+}
+
+func newWebhookDeliveries(tolerance time.Duration) *webhookDeliveries {
+	// Timestamps are accepted up to tolerance in the past and in the future
+	return &webhookDeliveries{seen: expirable.NewLRU[string, struct{}](webhookDeliveriesSize, nil, 2*tolerance)}
+}
+
+// claim returns true the first time a delivery ID is seen
+func (d *webhookDeliveries) claim(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.seen.Peek(id); ok {
+		return false
+	}
+	d.seen.Add(id, struct{}{})
+	return true
+}
+
+// release forgets a delivery that failed, so the sender can retry it
+func (d *webhookDeliveries) release(id string) {
+	d.seen.Remove(id)
+}
+
+// WebhookEvent is a change to a listing pushed by the external API
+type WebhookEvent struct {
+	Event      string `json:"event"`
+	PropertyID uint   `json:"property_id"`
+}
+
+// HandleWebhook verifies an event pushed by the external API and applies it right away: created
+// and updated listings are imported as by ImportProperty, deleted ones handled by the removed
+// policy as if a full import no longer found them. A failure is returned so the sender retries;
+// a delivery already applied is ignored.
+func (is *importService) HandleWebhook(ctx context.Context, body []byte, headers WebhookHeaders) (*WebhookResponse, error) {
+	ctx = is.correlate(ctx)
+	if is.webhookSecret == "" {
+		return nil, ErrWebhookDisabled
+	}
+	if headers.DeliveryID == "" || len(headers.DeliveryID) > maxWebhookDeliveryIDLength || !validWebhookSignature(is.webhookSecret, headers, body) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	signedAt, err := strconv.ParseInt(headers.Timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidWebhookSignature
+	}
+	if skew := time.Since(time.Unix(signedAt, 0)); skew > is.webhookTolerance || skew < -is.webhookTolerance {
+		return nil, ErrStaleWebhook
+	}
+
+	if !is.webhookDeliveries.claim(headers.DeliveryID) {
+		importLogger(ctx).Info("Ignored webhook delivery already received", "delivery_id", headers.DeliveryID)
+		return &WebhookResponse{Outcome: ImportOutcomeIgnored}, nil
+	}
+	resp, err := is.applyWebhook(ctx, body)
+	if err != nil {
+		is.webhookDeliveries.release(headers.DeliveryID)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// applyWebhook applies the event in body
+func (is *importService) applyWebhook(ctx context.Context, body []byte) (*WebhookResponse, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookEvent, err)
+	}
+	if event.PropertyID == 0 {
+		return nil, fmt.Errorf("%w: missing property_id", ErrInvalidWebhookEvent)
+	}
+	resp := &WebhookResponse{Event: event.Event, PropertyID: event.PropertyID}
+
+	switch event.Event {
+	case WebhookEventCreated, WebhookEventUpdated:
+		result, err := is.ImportProperty(ctx, event.PropertyID)
+		if errors.Is(err, ErrExternalListingNotFound) {
+			// Removed again before the event got here; the delete event follows
+			resp.Outcome = ImportOutcomeIgnored
+			return resp, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Outcome = result.Outcome
+		if result.Imovel != nil {
+			resp.ImovelID = result.Imovel.ID
+		}
+	case WebhookEventDeleted:
+		imovelID, err := is.removeFromWebhook(ctx, event.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Outcome = ImportOutcomeIgnored
+		if imovelID != 0 {
+			resp.Outcome = ImportOutcomeRemoved
+			resp.ImovelID = imovelID
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhookEvent, event.Event)
+	}
+
+	importLogger(ctx).Info("Applied webhook event", "event", event.Event, "external_id", event.PropertyID, "action", resp.Outcome)
+	return resp, nil
+}
+
+// removeFromWebhook applies the removed policy to the property of a deleted listing and returns
+// its ID, or 0 when there is nothing to do: no policy, no such property or already handled
+func (is *importService) removeFromWebhook(ctx context.Context, externalID uint) (uint, error) {
+	if is.removedPolicy == "" || is.removedPolicy == RemovedPolicyNone {
+		return 0, nil
+	}
+
+	imovel, err := is.service.GetImovelByIdIntegracao(ctx, fmt.Sprintf("%d", externalID))
+	if errors.Is(err, ErrImovelNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find property: %w", err)
+	}
+	if imovel.RemovidoOrigemEm != nil {
+		return 0, nil
+	}
+
+	// The changes are audited as made by the import
+	if err := is.service.MarkRemovedFromSource(withPriceOrigin(ctx, PriceOriginImport), imovel.ID, is.removedPolicy); err != nil {
+		return 0, fmt.Errorf("failed to handle removal from the source: %w", err)
+	}
+	return imovel.ID, nil
+}
+
+// validWebhookSignature reports whether the signature header is the HMAC-SHA256 under secret of
+// the timestamp, the delivery ID and body
+func validWebhookSignature(secret string, headers WebhookHeaders, body []byte) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(headers.Signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	return hmac.Equal(got, webhookSignature(secret, headers.Timestamp, headers.DeliveryID, body))
+}
+
+// webhookSignature signs the timestamp and delivery ID along with body, so neither can be
+// replaced to replay the body
+func webhookSignature(secret, timestamp, deliveryID string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + deliveryID + "."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,138 @@
+package imoveis
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signWebhook signs body as the external API does, sent now under a new delivery ID
+func signWebhook(secret, body string) WebhookHeaders {
+	return signWebhookAt(secret, body, time.Now(), fmt.Sprintf("delivery-%d", time.Now().UnixNano()))
+}
+
+func signWebhookAt(secret, body string, at time.Time, deliveryID string) WebhookHeaders {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + deliveryID + "." + body))
+	return WebhookHeaders{
+		Signature:  "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		Timestamp:  timestamp,
+		DeliveryID: deliveryID,
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+	importer, database := setupImportService(t, api, false)
+	importer.webhookSecret = "segredo"
+	importer.removedPolicy = RemovedPolicyDelete
+	send := func(body string) (*WebhookResponse, error) {
+		return importer.HandleWebhook(ctx, []byte(body), signWebhook("segredo", body))
+	}
+
+	result, err := send(`{"event":"property.created","property_id":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeCreated, result.Outcome)
+	imovel := findImported(t, database, "1")
+	assert.Equal(t, imovel.ID, result.ImovelID)
+
+	api.listings[0].Titulo = "Apartamento reformado"
+	result, err = send(`{"event":"property.updated","property_id":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeUpdated, result.Outcome)
+	assert.Equal(t, "Apartamento reformado", findImported(t, database, "1").Titulo)
+
+	api.listings = nil
+	result, err = send(`{"event":"property.deleted","property_id":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeRemoved, result.Outcome)
+	var removed Imovel
+	require.NoError(t, database.Unscoped().First(&removed, imovel.ID).Error)
+	assert.True(t, removed.DeletedAt.Valid)
+	assert.NotNil(t, removed.RemovidoOrigemEm)
+
+	// Sent again, or about a listing never imported
+	result, err = send(`{"event":"property.deleted","property_id":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeIgnored, result.Outcome)
+	result, err = send(`{"event":"property.updated","property_id":2}`)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeIgnored, result.Outcome)
+}
+
+func TestHandleWebhook_Rejected(t *testing.T) {
+	ctx := context.Background()
+	importer, _ := setupImportService(t, &fakeExternalAPI{}, false)
+	body := `{"event":"property.created","property_id":1}`
+
+	_, err := importer.HandleWebhook(ctx, []byte(body), signWebhook("segredo", body))
+	assert.ErrorIs(t, err, ErrWebhookDisabled)
+
+	importer.webhookSecret = "segredo"
+	_, err = importer.HandleWebhook(ctx, []byte(body), signWebhook("outro", body))
+	assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+	_, err = importer.HandleWebhook(ctx, []byte(body), WebhookHeaders{})
+	assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+
+	t.Run("signed headers cannot be replaced", func(t *testing.T) {
+		headers := signWebhook("segredo", body)
+		headers.DeliveryID = "another-delivery"
+		_, err := importer.HandleWebhook(ctx, []byte(body), headers)
+		assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+
+		headers = signWebhook("segredo", body)
+		headers.Timestamp = strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+		_, err = importer.HandleWebhook(ctx, []byte(body), headers)
+		assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+	})
+
+	t.Run("outside the tolerance window", func(t *testing.T) {
+		_, err := importer.HandleWebhook(ctx, []byte(body), signWebhookAt("segredo", body, time.Now().Add(-10*time.Minute), "old"))
+		assert.ErrorIs(t, err, ErrStaleWebhook)
+		_, err = importer.HandleWebhook(ctx, []byte(body), signWebhookAt("segredo", body, time.Now().Add(10*time.Minute), "future"))
+		assert.ErrorIs(t, err, ErrStaleWebhook)
+	})
+
+	for _, invalid := range []string{`{"event":"property.moved","property_id":1}`, `{"event":"property.created"}`, `not json`} {
+		_, err = importer.HandleWebhook(ctx, []byte(invalid), signWebhook("segredo", invalid))
+		assert.ErrorIs(t, err, ErrInvalidWebhookEvent, invalid)
+	}
+}
+
+func TestHandleWebhook_Replay(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeExternalAPI{listings: []ExternalImovel{externalListing(1)}}
+	importer, database := setupImportService(t, api, false)
+	importer.webhookSecret = "segredo"
+	body := `{"event":"property.updated","property_id":1}`
+	headers := signWebhookAt("segredo", body, time.Now(), "delivery-1")
+
+	result, err := importer.HandleWebhook(ctx, []byte(body), headers)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeCreated, result.Outcome)
+
+	api.listings[0].Titulo = "Alterado antes do replay"
+	result, err = importer.HandleWebhook(ctx, []byte(body), headers)
+	require.NoError(t, err)
+	assert.Equal(t, ImportOutcomeIgnored, result.Outcome, "a delivery is applied once")
+	assert.NotEqual(t, "Alterado antes do replay", findImported(t, database, "1").Titulo)
+
+	t.Run("failed deliveries can be retried", func(t *testing.T) {
+		invalid := `{"event":"property.moved","property_id":1}`
+		retried := signWebhookAt("segredo", invalid, time.Now(), "delivery-2")
+		_, err := importer.HandleWebhook(ctx, []byte(invalid), retried)
+		assert.ErrorIs(t, err, ErrInvalidWebhookEvent)
+		_, err = importer.HandleWebhook(ctx, []byte(invalid), retried)
+		assert.ErrorIs(t, err, ErrInvalidWebhookEvent, "the failed delivery was not remembered")
+	})
+}
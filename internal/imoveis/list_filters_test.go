@@ -0,0 +1,122 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFilterJoins(t *testing.T) {
+	minLat, maxLat, minLng, maxLng := -24.0, -23.0, -47.0, -46.0
+	query := &ImovelListQuery{
+		MinPreco:        100000,
+		MaxPreco:        900000,
+		MinPrecoAluguel: 1000,
+		MaxPrecoAluguel: 5000,
+		Cidade:          "Sao Paulo",
+		Bairro:          "Moema",
+		MinLat:          &minLat,
+		MaxLat:          &maxLat,
+		MinLng:          &minLng,
+		MaxLng:          &maxLng,
+	}
+
+	assert.Equal(t, []string{
+		"INNER JOIN preco_vendas ON preco_vendas.id = imoveis.preco_venda_id",
+		"INNER JOIN preco_aluguels ON preco_aluguels.id = imoveis.preco_aluguel_id",
+		"INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id",
+	}, listFilterJoins(query, true))
+	assert.Empty(t, listFilterJoins(&ImovelListQuery{}, false))
+}
+
+func TestListImoveis_CombinedFilters(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	fixtures := []struct {
+		codigo          string
+		venda, aluguel  float64
+		caracteristicas []uint
+	}{
+		{"AP-001", 250000, 1500, []uint{1}},
+		{"AP-002", 450000, 2500, []uint{1, 2}},
+		{"AP-003", 550000, 3500, []uint{1, 2}},
+		{"AP-004", 650000, 4500, []uint{2}},
+		{"AP-005", 850000, 6500, []uint{1, 2}},
+	}
+	for _, f := range fixtures {
+		req := nestedCreateRequest(f.codigo)
+		req.PrecoVenda.Preco = f.venda
+		req.PrecoAluguel.Preco = f.aluguel
+		req.Caracteristicas = f.caracteristicas
+		_, err := svc.CreateImovel(ctx, req)
+		require.NoError(t, err)
+	}
+
+	list := func(query *ImovelListQuery) (int64, []string) {
+		if query.Page == 0 {
+			query.Page, query.Limit = 1, 10
+		}
+		result, err := svc.ListImoveis(ctx, query)
+		require.NoError(t, err)
+		var codigos []string
+		for _, r := range result.Results {
+			codigos = append(codigos, r.Codigo)
+		}
+		return result.Total, codigos
+	}
+
+	tests := []struct {
+		name     string
+		query    *ImovelListQuery
+		total    int64
+		expected []string
+	}{
+		{
+			name:     "sale price range",
+			query:    &ImovelListQuery{MinPreco: 400000, MaxPreco: 700000, Sort: "preco_venda:asc"},
+			total:    3,
+			expected: []string{"AP-002", "AP-003", "AP-004"},
+		},
+		{
+			name:     "sale and rental price ranges",
+			query:    &ImovelListQuery{MinPreco: 400000, MaxPreco: 700000, MinPrecoAluguel: 3000, MaxPrecoAluguel: 5000, Sort: "preco:asc"},
+			total:    2,
+			expected: []string{"AP-003", "AP-004"},
+		},
+		{
+			name: "price ranges and all caracteristicas",
+			query: &ImovelListQuery{
+				MinPreco: 200000, MaxPreco: 900000, MaxPrecoAluguel: 6000,
+				Caracteristicas: []uint{1, 2}, CaracteristicasMatch: "all", Sort: "preco_aluguel:desc",
+			},
+			total:    2,
+			expected: []string{"AP-003", "AP-002"},
+		},
+		{
+			name:     "count ignores pagination",
+			query:    &ImovelListQuery{Page: 2, Limit: 2, MinPreco: 200000, MaxPreco: 900000, Sort: "preco_venda:asc"},
+			total:    5,
+			expected: []string{"AP-003", "AP-004"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, codigos := list(tt.query)
+			assert.Equal(t, tt.total, total)
+			assert.Equal(t, tt.expected, codigos)
+		})
+	}
+
+	t.Run("cursor pages use the same filters", func(t *testing.T) {
+		empty := ""
+		result, err := svc.ListImoveis(ctx, &ImovelListQuery{
+			Limit: 10, Order: "asc", Cursor: &empty, MinPreco: 400000, MaxPreco: 700000,
+		})
+		require.NoError(t, err)
+		assert.Len(t, result.Results, 3)
+	})
+}
@@ -0,0 +1,136 @@
+package imoveis
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSortTerms caps the number of fields of a sort parameter
+const maxSortTerms = 5
+
+// sortPriceJoins are the aliased price joins used for sorting, kept apart from the ones added by the
+// price filters
+var sortPriceJoins = map[string]string{
+	"sort_pv": "LEFT JOIN preco_vendas AS sort_pv ON sort_pv.id = imoveis.preco_venda_id AND sort_pv.deleted_at IS NULL",
+	"sort_pa": "LEFT JOIN preco_aluguels AS sort_pa ON sort_pa.id = imoveis.preco_aluguel_id AND sort_pa.deleted_at IS NULL",
+}
+
+// listSortField describes a sortable field: either a column or a price expression with its joins
+type listSortField struct {
+	Column clause.Column
+	Expr   string
+	Joins  []string
+}
+
+// listSortFields is the whitelist of sort fields accepted by the list endpoints
+var listSortFields = map[string]listSortField{
+	"created_at":    {Column: clause.Column{Table: "imoveis", Name: "created_at"}},
+	"updated_at":    {Column: clause.Column{Table: "imoveis", Name: "updated_at"}},
+	"titulo":        {Column: clause.Column{Table: "imoveis", Name: "titulo"}},
+	"metragem":      {Column: clause.Column{Table: "imoveis", Name: "metragem"}},
+	"num_quartos":   {Column: clause.Column{Table: "imoveis", Name: "num_quartos"}},
+	"visualizacoes": {Column: clause.Column{Table: "imoveis", Name: "visualizacoes"}},
+	"preco_venda":   {Expr: "sort_pv.preco", Joins: []string{"sort_pv"}},
+	"preco_aluguel": {Expr: "sort_pa.preco", Joins: []string{"sort_pa"}},
+	// preco is the price of the property's objetivo: rental for ALUGAR, sale otherwise
+	"preco": {
+		Expr:  "CASE WHEN imoveis.objetivo = 'ALUGAR' THEN sort_pa.preco ELSE sort_pv.preco END",
+		Joins: []string{"sort_pv", "sort_pa"},
+	},
+}
+
+// sortTerm is one field of a sort parameter
+type sortTerm struct {
+	Field string
+	Desc  bool
+}
+
+// parseSortTerms parses sort=field[:asc|desc],... Fields without a direction use defaultOrder, desc
+// unless it is asc.
+func parseSortTerms(sort, defaultOrder string) ([]sortTerm, error) {
+	var terms []sortTerm
+	seen := map[string]bool{}
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field, direction, hasDirection := strings.Cut(part, ":")
+		if !hasDirection {
+			direction = "desc"
+			if defaultOrder == "asc" {
+				direction = "asc"
+			}
+		}
+		if _, ok := listSortFields[field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q", field)
+		}
+		if direction != "asc" && direction != "desc" {
+			return nil, fmt.Errorf("invalid direction %q for %s", direction, field)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("duplicate sort field %q", field)
+		}
+		seen[field] = true
+		terms = append(terms, sortTerm{Field: field, Desc: direction == "desc"})
+	}
+
+	if len(terms) > maxSortTerms {
+		return nil, fmt.Errorf("at most %d sort fields are allowed", maxSortTerms)
+	}
+	return terms, nil
+}
+
+// sortTerms returns the requested ordering, created_at in the requested order when sort is empty
+func (q *ImovelListQuery) sortTerms() []sortTerm {
+	terms, err := parseSortTerms(q.Sort, q.Order)
+	if err != nil || len(terms) == 0 {
+		return []sortTerm{{Field: "created_at", Desc: q.Order != "asc"}}
+	}
+	return terms
+}
+
+// sortErrors validates the sort parameter
+func (q *ImovelListQuery) sortErrors() map[string]string {
+	if _, err := parseSortTerms(q.Sort, q.Order); err != nil {
+		return map[string]string{"sort": err.Error()}
+	}
+	return nil
+}
+
+// applyListSort orders by the requested fields, joining the price tables when needed. Properties
+// without the sorted price come last in both directions, and id breaks ties so pages are stable.
+func applyListSort(db *gorm.DB, query *ImovelListQuery) *gorm.DB {
+	terms := query.sortTerms()
+
+	joined := map[string]bool{}
+	for _, term := range terms {
+		for _, alias := range listSortFields[term.Field].Joins {
+			if !joined[alias] {
+				db = db.Joins(sortPriceJoins[alias])
+				joined[alias] = true
+			}
+		}
+	}
+
+	for _, term := range terms {
+		field := listSortFields[term.Field]
+		if field.Expr == "" {
+			db = db.Order(clause.OrderByColumn{Column: field.Column, Desc: term.Desc})
+			continue
+		}
+		direction := "ASC"
+		if term.Desc {
+			direction = "DESC"
+		}
+		// Expressions come from the whitelist above, never from the request
+		db = db.Order("(" + field.Expr + ") IS NULL").
+			Order("(" + field.Expr + ") " + direction)
+	}
+
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Table: "imoveis", Name: "id"}})
+}
@@ -0,0 +1,91 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortTerms(t *testing.T) {
+	terms, err := parseSortTerms("preco_venda:asc, created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, []sortTerm{{Field: "preco_venda"}, {Field: "created_at", Desc: true}}, terms)
+
+	terms, err = parseSortTerms("", "desc")
+	require.NoError(t, err)
+	assert.Empty(t, terms)
+
+	for _, sort := range []string{
+		"id; DROP TABLE imoveis",
+		"preco_venda:sideways",
+		"titulo,titulo:asc",
+		"created_at,updated_at,titulo,metragem,num_quartos,visualizacoes",
+	} {
+		_, err := parseSortTerms(sort, "desc")
+		assert.Error(t, err, sort)
+	}
+}
+
+func TestImovelListQuery_SortErrors(t *testing.T) {
+	cursor := ""
+	assert.Empty(t, (&ImovelListQuery{Sort: "preco_aluguel:desc,titulo", Order: "asc"}).sortErrors())
+	assert.Contains(t, (&ImovelListQuery{Sort: "deleted_at"}).sortErrors(), "sort")
+	assert.Empty(t, (&ImovelListQuery{Sort: "created_at:asc", Cursor: &cursor}).cursorErrors())
+	assert.Contains(t, (&ImovelListQuery{Sort: "created_at,titulo", Cursor: &cursor}).cursorErrors(), "sort")
+}
+
+func TestListImoveis_MultiSort(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	fixtures := []struct {
+		codigo         string
+		quartos        int
+		venda, aluguel float64
+	}{
+		{"AP-001", 2, 500000, 3000},
+		{"AP-002", 3, 300000, 2000},
+		{"AP-003", 3, 300000, 4000},
+		{"AP-004", 1, 0, 1500},
+	}
+	for _, f := range fixtures {
+		req := nestedCreateRequest(f.codigo)
+		req.NumQuartos = f.quartos
+		req.PrecoVenda.Preco = f.venda
+		req.PrecoAluguel.Preco = f.aluguel
+		if f.venda == 0 {
+			// Rental listings are sorted by their rental price under preco
+			req.Objetivo = "ALUGAR"
+			req.PrecoVenda = nil
+		}
+		_, err := svc.CreateImovel(ctx, req)
+		require.NoError(t, err)
+	}
+
+	codigos := func(query *ImovelListQuery) []string {
+		result, err := svc.ListImoveis(ctx, query)
+		require.NoError(t, err)
+		var codigos []string
+		for _, r := range result.Results {
+			codigos = append(codigos, r.Codigo)
+		}
+		return codigos
+	}
+
+	assert.Equal(t, []string{"AP-002", "AP-003", "AP-001", "AP-004"},
+		codigos(&ImovelListQuery{Sort: "preco_venda:asc", Order: "desc"}), "missing prices come last, ties by id")
+	assert.Equal(t, []string{"AP-001", "AP-002", "AP-003", "AP-004"},
+		codigos(&ImovelListQuery{Sort: "preco_venda:desc"}))
+	assert.Equal(t, []string{"AP-003", "AP-002", "AP-001", "AP-004"},
+		codigos(&ImovelListQuery{Sort: "preco_venda:asc,preco_aluguel:desc"}))
+	assert.Equal(t, []string{"AP-003", "AP-002", "AP-001", "AP-004"},
+		codigos(&ImovelListQuery{Sort: "num_quartos,preco_aluguel", Order: "desc"}))
+	assert.Equal(t, []string{"AP-004", "AP-002", "AP-003", "AP-001"},
+		codigos(&ImovelListQuery{Sort: "preco:asc"}))
+
+	// Sorting joins never clash with the price filter joins
+	assert.Equal(t, []string{"AP-002", "AP-003"},
+		codigos(&ImovelListQuery{Sort: "preco_venda:asc", MaxPreco: 400000}))
+}
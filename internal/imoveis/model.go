@@ -1,6 +1,9 @@
 package imoveis
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -18,6 +21,10 @@ type Anexo struct {
 	Image            bool           `json:"image"`
 	Video            bool           `json:"video"`
 	IsExternalURL    bool           `json:"isExternalUrl"`
+	ExternalURL      string         `json:"externalUrl,omitempty"` // URL of an imported image at the source
+	ContentHash      string         `gorm:"index" json:"-"`        // SHA-256 of the stored content of imported images
+	Ordem            int            `json:"ordem"`                 // position of an imported image in the source, from 1; 0 for the others
+	Variants         AnexoVariants  `gorm:"type:jsonb" json:"variants,omitempty"`
 	ImovelID         *uint          `json:"imovel_id,omitempty"`
 	EmpreendimentoID *uint          `json:"empreendimento_id,omitempty"`
 	PlantaID         *uint          `json:"planta_id,omitempty"`
@@ -26,6 +33,47 @@ type Anexo struct {
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// AnexoVariant is a resized rendition of an image anexo. WebPURL is empty when the WebP
+// file would be larger than the JPEG.
+type AnexoVariant struct {
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	URL     string `json:"url"`
+	WebPURL string `json:"webpUrl,omitempty"`
+}
+
+// AnexoVariants maps rendition names (thumbnail, medium, large) to their files, stored as JSONB
+type AnexoVariants map[string]AnexoVariant
+
+// Value implements driver.Valuer
+func (v AnexoVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (v *AnexoVariants) Scan(value interface{}) error {
+	var data []byte
+	switch src := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported type for anexo variants: %T", value)
+	}
+	return json.Unmarshal(data, v)
+}
+
 // Endereco represents an address
 type Endereco struct {
 	ID        uint    `gorm:"primarykey" json:"id"`
@@ -41,6 +89,7 @@ type Endereco struct {
 
 type Plantas struct {
 	ID               uint           `gorm:"primarykey" json:"id"`
+	IdIntegracao     string         `gorm:"index" json:"id_integracao,omitempty"`
 	Nome             string         `json:"nome"`
 	Metragem         float64        `json:"metragem"`
 	EmpreendimentoID uint           `json:"empreendimento_id,omitempty"`
@@ -51,12 +100,15 @@ type Plantas struct {
 }
 
 type Organizacao struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Nome      string         `json:"nome"`
-	Perfil    string         `json:"perfil"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID     uint   `gorm:"primarykey" json:"id"`
+	Nome   string `json:"nome"`
+	Perfil string `json:"perfil"`
+	// DiasArquivamento archives published properties not updated for this many days; nil uses the
+	// configured default and 0 never archives
+	DiasArquivamento *int           `json:"dias_arquivamento,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName overrides the table name used by GORM (prevents using "organizacaos")
@@ -77,6 +129,7 @@ type CorretorPrincipal struct {
 	BairrosAtuacao []string       `gorm:"type:text[]" json:"bairros_atuacao"`
 	OrganizacaoID  uint           `json:"organizacao_id"`
 	Organizacao    *Organizacao   `gorm:"foreignKey:OrganizacaoID" json:"organizacao,omitempty"`
+	Ativo          bool           `gorm:"default:true" json:"ativo"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -134,6 +187,7 @@ type Empreendimento struct {
 
 type Torres struct {
 	ID               uint            `gorm:"primarykey" json:"id"`
+	IdIntegracao     string          `gorm:"index" json:"id_integracao,omitempty"`
 	Nome             string          `json:"nome"`
 	TotalColunas     int             `json:"totalColunas"`
 	TotalElevadores  int             `json:"totalElevadores"`
@@ -190,6 +244,11 @@ type Imovel struct {
 	Finalidade    string `json:"finalidade"` // RESIDENTIAL, COMERCIAL
 	Descricao     string `gorm:"type:text" json:"descricao"`
 
+	// SEO: Slug is generated from tipo, quartos, bairro and codigo; the meta fields are edited by hand
+	Slug            string `gorm:"index" json:"slug"`
+	MetaTitle       string `json:"metaTitle"`
+	MetaDescription string `json:"metaDescription"`
+
 	// Property Details
 	Metragem     float64 `json:"metragem"`
 	NumQuartos   int     `json:"numQuartos"`
@@ -225,6 +284,10 @@ type Imovel struct {
 	Published bool   `gorm:"default:false" json:"published"`
 	Closed    bool   `gorm:"default:false" json:"closed"`
 
+	// Scheduling: the publication scheduler publishes the property at PublicarEm and archives it at ExpiraEm
+	PublicarEm *time.Time `gorm:"index" json:"publicarEm,omitempty"`
+	ExpiraEm   *time.Time `gorm:"index" json:"expiraEm,omitempty"`
+
 	// Plant reference
 	PlantaID uint     `json:"plantaID,omitempty"`
 	Planta   *Plantas `gorm:"foreignKey:PlantaID" json:"planta,omitempty"`
@@ -240,7 +303,16 @@ type Imovel struct {
 	// Characteristics
 	Caracteristicas []Caracteristica `gorm:"many2many:imovel_caracteristicas;" json:"caracteristicas,omitempty"`
 
+	// Import: checksum of the external listing last imported, compared by incremental imports, and
+	// when a full import last found the listing gone from the source (cleared if it comes back)
+	ImportChecksum   string     `gorm:"size:64" json:"-"`
+	RemovidoOrigemEm *time.Time `gorm:"index" json:"removidoOrigemEm,omitempty"`
+
+	// Fields edited by hand, which the importer leaves alone according to externalapi.conflict_policy
+	LockedFields LockedFields `gorm:"type:jsonb" json:"lockedFields,omitempty"`
+
 	// Metadata
+	Version       uint           `gorm:"not null;default:1" json:"version"` // incremented by every edit, checked by updates
 	Visualizacoes int            `gorm:"default:0" json:"visualizacoes"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
@@ -251,3 +323,178 @@ type Imovel struct {
 func (Imovel) TableName() string {
 	return "imoveis"
 }
+
+// HistoricoPreco records a change of the sale or rental price of a property
+type HistoricoPreco struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	ImovelID      uint      `gorm:"index;not null" json:"imovel_id"`
+	Tipo          string    `gorm:"not null" json:"tipo"` // VENDA, ALUGUEL
+	Preco         *float64  `json:"preco"`                // nil when the price was removed
+	PrecoAnterior *float64  `json:"precoAnterior"`        // nil for the first recorded price
+	Origem        string    `json:"origem"`               // API, IMPORT, MIGRATION
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (HistoricoPreco) TableName() string {
+	return "historico_precos"
+}
+
+// ImovelAudit is an entry of the audit trail of a property: who did what and which fields changed.
+// It has no foreign key so the trail outlives permanently deleted properties.
+type ImovelAudit struct {
+	ID         uint         `gorm:"primarykey" json:"id"`
+	ImovelID   uint         `gorm:"index;not null" json:"imovel_id"`
+	UserID     *uint        `json:"user_id"`                  // nil for unauthenticated jobs
+	Entidade   string       `gorm:"not null" json:"entidade"` // IMOVEL, ANEXO, CARACTERISTICAS, PACOTE, PRECO_VENDA, PRECO_ALUGUEL
+	Acao       string       `gorm:"not null" json:"acao"`     // CREATE, UPDATE, DELETE, HARD_DELETE, RESTORE, PUBLISH, UNPUBLISH
+	Origem     string       `gorm:"not null" json:"origem"`   // API, IMPORT, SCHEDULER, ARCHIVER
+	Motivo     string       `json:"motivo,omitempty"`         // why a job made the change
+	Alteracoes AuditChanges `gorm:"type:jsonb" json:"alteracoes"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (ImovelAudit) TableName() string {
+	return "imovel_audits"
+}
+
+// ImportSyncState records, per integration source, when the last import run without failures
+// started. Incremental imports ask the source for the properties updated since then.
+type ImportSyncState struct {
+	Source    string    `gorm:"primarykey;size:255" json:"source"`
+	SyncedAt  time.Time `gorm:"not null" json:"synced_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (ImportSyncState) TableName() string {
+	return "import_sync_states"
+}
+
+// ImportRun is an execution of the importer with its counts; Status is RUNNING until it ends
+type ImportRun struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Source     string     `gorm:"not null" json:"source"`
+	Status     string     `gorm:"not null" json:"status"` // RUNNING, COMPLETED, FAILED
+	FullSync   bool       `json:"full_sync"`              // incremental mode was off
+	UserID     *uint      `json:"user_id"`                // nil for the CLI
+	Total      int        `json:"total"`
+	Created    int        `json:"created"`
+	Updated    int        `json:"updated"`
+	Unchanged  int        `json:"unchanged"`
+	Failed     int        `json:"failed"`
+	Removed    int        `json:"removed"`                          // imported properties no longer listed by the source
+	Error      string     `gorm:"type:text" json:"error,omitempty"` // why the run stopped
+	StartedAt  time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Throttled  int        `json:"throttled"`   // requests held back by the rate limit
+	ThrottleMs int64      `json:"throttle_ms"` // time those requests waited
+	// Filters scoped the run to part of the catalog; nil imported all of it
+	Filters *ImportFilters `gorm:"serializer:json;type:text" json:"filters,omitempty"`
+	// Listings held in quarantine for failing validation
+	Quarantined int `json:"quarantined"`
+	// Checkpoint counts the listings from the start of the list that went through, the last of
+	// them being CheckpointExternalID; resuming the run picks up after it. ResumedFrom is the
+	// run that began the interrupted sync this one finishes.
+	Checkpoint           int   `json:"checkpoint"`
+	CheckpointExternalID uint  `json:"checkpoint_external_id"`
+	ResumedFrom          *uint `json:"resumed_from,omitempty"`
+}
+
+// TableName specifies the table name
+func (ImportRun) TableName() string {
+	return "import_runs"
+}
+
+// ImportRunItem is a listing that failed to import in a run
+type ImportRunItem struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	RunID      uint      `gorm:"index;not null" json:"run_id"`
+	ExternalID uint      `json:"external_id"`
+	Codigo     string    `json:"codigo"`
+	Error      string    `gorm:"type:text;not null" json:"error"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (ImportRunItem) TableName() string {
+	return "import_run_items"
+}
+
+// ImportConflict is a locally edited field of a property the source changed, held for review
+// under the review conflict policy. Values are stored as JSON; a field has at most one pending
+// conflict, refreshed by every import until it is resolved.
+type ImportConflict struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	ImovelID     uint       `gorm:"index;not null" json:"imovel_id"`
+	Campo        string     `gorm:"size:50;not null" json:"campo"`
+	ValorLocal   string     `gorm:"type:text" json:"valorLocal"`
+	ValorRemoto  string     `gorm:"type:text" json:"valorRemoto"`
+	Resolucao    string     `gorm:"size:10" json:"resolucao,omitempty"` // LOCAL, REMOTE; empty while pending
+	ResolvidoEm  *time.Time `json:"resolvidoEm,omitempty"`
+	ResolvidoPor *uint      `json:"resolvidoPor,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (ImportConflict) TableName() string {
+	return "import_conflicts"
+}
+
+// ImportViolations maps the fields of a listing that failed validation to why, stored as JSONB
+type ImportViolations map[string]string
+
+// Value implements driver.Valuer
+func (v ImportViolations) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (v *ImportViolations) Scan(value interface{}) error {
+	var data []byte
+	switch src := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported type for import violations: %T", value)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ImportQuarantine is a listing of the source that failed validation, held with its payload
+// instead of being imported as a half-broken property. A listing has at most one pending entry,
+// refreshed by every import until the source fixes the listing or the entry is discarded.
+type ImportQuarantine struct {
+	ID           uint             `gorm:"primarykey" json:"id"`
+	Source       string           `gorm:"not null;index:idx_import_quarantine_listing" json:"source"`
+	ExternalID   uint             `gorm:"not null;index:idx_import_quarantine_listing" json:"external_id"`
+	Codigo       string           `json:"codigo"`
+	Violations   ImportViolations `gorm:"type:jsonb" json:"violations"`
+	Payload      string           `gorm:"type:jsonb" json:"-"`                  // the listing as decoded from the source
+	Checksum     string           `gorm:"size:64" json:"-"`                     // SHA-256 of Payload, to tell a discarded payload apart
+	Status       string           `gorm:"size:10;not null;index" json:"status"` // PENDING, RELEASED, DISCARDED
+	ResolvidoEm  *time.Time       `json:"resolvidoEm,omitempty"`
+	ResolvidoPor *uint            `json:"resolvidoPor,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (ImportQuarantine) TableName() string {
+	return "import_quarantine"
+}
@@ -0,0 +1,238 @@
+package imoveis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Nullable distinguishes a field absent from a JSON body from one explicitly set to null
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only invoked for keys present in the body, so Set records presence
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// PatchImovelRequest is a JSON merge patch for a property: absent keys are left untouched,
+// null clears the field (zero value, or NULL for optional relations) and any other value replaces it.
+// Titulo, codigo, tipo, objetivo, finalidade, metragem and endereco_id cannot be cleared.
+type PatchImovelRequest struct {
+	Titulo        Nullable[string]  `json:"titulo" swaggertype:"string"`
+	Codigo        Nullable[string]  `json:"codigo" swaggertype:"string"`
+	Tipo          Nullable[string]  `json:"tipo" swaggertype:"string"`
+	Objetivo      Nullable[string]  `json:"objetivo" swaggertype:"string"`
+	Finalidade    Nullable[string]  `json:"finalidade" swaggertype:"string"`
+	Descricao     Nullable[string]  `json:"descricao" swaggertype:"string"`
+	Metragem      Nullable[float64] `json:"metragem" swaggertype:"number"`
+	NumQuartos    Nullable[int]     `json:"numQuartos" swaggertype:"integer"`
+	NumSuites     Nullable[int]     `json:"numSuites" swaggertype:"integer"`
+	NumBanheiros  Nullable[int]     `json:"numBanheiros" swaggertype:"integer"`
+	NumVagas      Nullable[int]     `json:"numVagas" swaggertype:"integer"`
+	NumAndar      Nullable[int]     `json:"numAndar" swaggertype:"integer"`
+	Unidade       Nullable[string]  `json:"unidade" swaggertype:"string"`
+	Condominio    Nullable[float64] `json:"condominio" swaggertype:"number"`
+	IPTU          Nullable[float64] `json:"iptu" swaggertype:"number"`
+	InscricaoIPTU Nullable[string]  `json:"inscricaoIPTU" swaggertype:"string"`
+
+	// SEO metadata
+	MetaTitle       Nullable[string] `json:"metaTitle" swaggertype:"string"`
+	MetaDescription Nullable[string] `json:"metaDescription" swaggertype:"string"`
+
+	// Relations
+	EnderecoID          Nullable[uint]   `json:"endereco_id" swaggertype:"integer"`
+	EmpreendimentoID    Nullable[uint]   `json:"empreendimento_id" swaggertype:"integer"`
+	PlantaID            Nullable[uint]   `json:"planta_id" swaggertype:"integer"`
+	CorretorPrincipalID Nullable[uint]   `json:"corretor_principal_id" swaggertype:"integer"`
+	PacoteID            Nullable[uint]   `json:"pacote_id" swaggertype:"integer"`
+	PrecoVendaID        Nullable[uint]   `json:"preco_venda_id" swaggertype:"integer"`
+	PrecoAluguelID      Nullable[uint]   `json:"preco_aluguel_id" swaggertype:"integer"`
+	Caracteristicas     Nullable[[]uint] `json:"caracteristicas" swaggertype:"array,integer"`
+
+	// Scheduling; null cancels the scheduled publication or expiration
+	PublicarEm Nullable[time.Time] `json:"publicarEm" swaggertype:"string"`
+	ExpiraEm   Nullable[time.Time] `json:"expiraEm" swaggertype:"string"`
+
+	// LockedFields replaces the fields locked against the importer; null unlocks them all
+	LockedFields Nullable[[]string] `json:"lockedFields" swaggertype:"array,string"`
+
+	// Version is the optional version the client read, see UpdateImovelRequest
+	Version *uint `json:"version"`
+}
+
+var (
+	validTipos       = []string{"APARTAMENTO", "CASA", "COMERCIAL", "SALA_COMERCIAL", "TERRENO", "GALPAO"}
+	validObjetivos   = []string{"VENDER", "ALUGAR"}
+	validFinalidades = []string{"RESIDENTIAL", "COMERCIAL", "MISTO"}
+)
+
+// Validate mirrors the binding rules of CreateImovelRequest and returns the violations per field
+func (r *PatchImovelRequest) Validate() map[string]string {
+	details := map[string]string{}
+
+	required := map[string]bool{
+		"titulo":      r.Titulo.Null,
+		"codigo":      r.Codigo.Null,
+		"tipo":        r.Tipo.Null,
+		"objetivo":    r.Objetivo.Null,
+		"finalidade":  r.Finalidade.Null,
+		"metragem":    r.Metragem.Null,
+		"endereco_id": r.EnderecoID.Null,
+	}
+	for field, null := range required {
+		if null {
+			details[field] = "cannot be cleared"
+		}
+	}
+
+	checkLength(details, "titulo", r.Titulo, 3, 255)
+	checkLength(details, "codigo", r.Codigo, 1, 50)
+	checkLength(details, "descricao", r.Descricao, 10, 5000)
+	checkLength(details, "unidade", r.Unidade, 0, 20)
+	checkLength(details, "inscricaoIPTU", r.InscricaoIPTU, 0, 50)
+	checkLength(details, "metaTitle", r.MetaTitle, 0, 70)
+	checkLength(details, "metaDescription", r.MetaDescription, 0, 160)
+
+	checkOneOf(details, "tipo", r.Tipo, validTipos)
+	checkOneOf(details, "objetivo", r.Objetivo, validObjetivos)
+	checkOneOf(details, "finalidade", r.Finalidade, validFinalidades)
+
+	if hasValue(r.Metragem) && r.Metragem.Value <= 0 {
+		details["metragem"] = "must be greater than 0"
+	}
+	for field, value := range map[string]Nullable[int]{
+		"numQuartos":   r.NumQuartos,
+		"numSuites":    r.NumSuites,
+		"numBanheiros": r.NumBanheiros,
+		"numVagas":     r.NumVagas,
+	} {
+		if hasValue(value) && value.Value < 0 {
+			details[field] = "must be 0 or greater"
+		}
+	}
+	for field, value := range map[string]Nullable[float64]{
+		"condominio": r.Condominio,
+		"iptu":       r.IPTU,
+	} {
+		if hasValue(value) && value.Value < 0 {
+			details[field] = "must be 0 or greater"
+		}
+	}
+	for field, value := range map[string]Nullable[uint]{
+		"endereco_id":           r.EnderecoID,
+		"empreendimento_id":     r.EmpreendimentoID,
+		"planta_id":             r.PlantaID,
+		"corretor_principal_id": r.CorretorPrincipalID,
+		"pacote_id":             r.PacoteID,
+		"preco_venda_id":        r.PrecoVendaID,
+		"preco_aluguel_id":      r.PrecoAluguelID,
+	} {
+		if hasValue(value) && value.Value == 0 {
+			details[field] = "must be a valid ID; use null to clear"
+		}
+	}
+	if hasValue(r.Caracteristicas) {
+		for _, id := range r.Caracteristicas.Value {
+			if id == 0 {
+				details["caracteristicas"] = "must contain valid IDs"
+				break
+			}
+		}
+	}
+	if hasValue(r.LockedFields) {
+		for _, name := range r.LockedFields.Value {
+			if !isLockableField(name) {
+				details["lockedFields"] = fmt.Sprintf("unknown field %q", name)
+				break
+			}
+		}
+	}
+
+	return details
+}
+
+// columnUpdates converts the patch into a column map for the imoveis table; cleared
+// relations become NULL so foreign key constraints are not violated
+func (r *PatchImovelRequest) columnUpdates() map[string]interface{} {
+	updates := map[string]interface{}{}
+
+	setColumn(updates, "titulo", r.Titulo, "")
+	setColumn(updates, "codigo", r.Codigo, "")
+	setColumn(updates, "tipo", r.Tipo, "")
+	setColumn(updates, "objetivo", r.Objetivo, "")
+	setColumn(updates, "finalidade", r.Finalidade, "")
+	setColumn(updates, "descricao", r.Descricao, "")
+	setColumn(updates, "metragem", r.Metragem, 0)
+	setColumn(updates, "num_quartos", r.NumQuartos, 0)
+	setColumn(updates, "num_suites", r.NumSuites, 0)
+	setColumn(updates, "num_banheiros", r.NumBanheiros, 0)
+	setColumn(updates, "num_vagas", r.NumVagas, 0)
+	setColumn(updates, "num_andar", r.NumAndar, 0)
+	setColumn(updates, "unidade", r.Unidade, "")
+	setColumn(updates, "condominio", r.Condominio, 0)
+	setColumn(updates, "iptu", r.IPTU, 0)
+	setColumn(updates, "inscricao_iptu", r.InscricaoIPTU, "")
+	setColumn(updates, "meta_title", r.MetaTitle, "")
+	setColumn(updates, "meta_description", r.MetaDescription, "")
+
+	setColumn(updates, "endereco_id", r.EnderecoID, nil)
+	setColumn(updates, "empreendimento_id", r.EmpreendimentoID, nil)
+	setColumn(updates, "planta_id", r.PlantaID, nil)
+	setColumn(updates, "corretor_principal_id", r.CorretorPrincipalID, nil)
+	setColumn(updates, "pacote_id", r.PacoteID, nil)
+	setColumn(updates, "preco_venda_id", r.PrecoVendaID, nil)
+	setColumn(updates, "preco_aluguel_id", r.PrecoAluguelID, nil)
+
+	setColumn(updates, "publicar_em", r.PublicarEm, nil)
+	setColumn(updates, "expira_em", r.ExpiraEm, nil)
+
+	return updates
+}
+
+func hasValue[T any](n Nullable[T]) bool {
+	return n.Set && !n.Null
+}
+
+func setColumn[T any](updates map[string]interface{}, column string, n Nullable[T], cleared interface{}) {
+	if !n.Set {
+		return
+	}
+	if n.Null {
+		updates[column] = cleared
+		return
+	}
+	updates[column] = n.Value
+}
+
+func checkLength(details map[string]string, field string, n Nullable[string], min, max int) {
+	if !hasValue(n) {
+		return
+	}
+	length := utf8.RuneCountInString(n.Value)
+	if length < min || length > max {
+		details[field] = fmt.Sprintf("must be between %d and %d characters", min, max)
+	}
+}
+
+func checkOneOf(details map[string]string, field string, n Nullable[string], allowed []string) {
+	if !hasValue(n) {
+		return
+	}
+	for _, value := range allowed {
+		if n.Value == value {
+			return
+		}
+	}
+	details[field] = fmt.Sprintf("must be one of %v", allowed)
+}
@@ -0,0 +1,55 @@
+package imoveis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchImovelRequest_Decode(t *testing.T) {
+	var req PatchImovelRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"titulo":"Novo titulo","descricao":null,"numAndar":null,"empreendimento_id":null,"pacote_id":7}`), &req))
+
+	assert.True(t, req.Titulo.Set)
+	assert.Equal(t, "Novo titulo", req.Titulo.Value)
+	assert.True(t, req.Descricao.Set)
+	assert.True(t, req.Descricao.Null)
+	assert.False(t, req.Codigo.Set, "absent fields must not be marked as set")
+
+	assert.Equal(t, map[string]interface{}{
+		"titulo":            "Novo titulo",
+		"descricao":         "",
+		"num_andar":         0,
+		"empreendimento_id": nil,
+		"pacote_id":         uint(7),
+	}, req.columnUpdates())
+}
+
+func TestPatchImovelRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string
+	}{
+		{name: "valid patch", body: `{"descricao":null,"numQuartos":3,"tipo":"CASA"}`},
+		{name: "required field cleared", body: `{"titulo":null,"endereco_id":null}`, wantFields: []string{"titulo", "endereco_id"}},
+		{name: "invalid enum and length", body: `{"tipo":"CASTELO","titulo":"ab"}`, wantFields: []string{"tipo", "titulo"}},
+		{name: "zero relation id", body: `{"planta_id":0}`, wantFields: []string{"planta_id"}},
+		{name: "negative counts", body: `{"numVagas":-1,"iptu":-10}`, wantFields: []string{"numVagas", "iptu"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req PatchImovelRequest
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+			details := req.Validate()
+			assert.Len(t, details, len(tt.wantFields))
+			for _, field := range tt.wantFields {
+				assert.Contains(t, details, field)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+const (
+	// HistoricoTipoVenda marks sale price history entries
+	HistoricoTipoVenda = "VENDA"
+	// HistoricoTipoAluguel marks rental price history entries
+	HistoricoTipoAluguel = "ALUGUEL"
+
+	// PriceOriginAPI marks price changes made through the API
+	PriceOriginAPI = "API"
+	// PriceOriginImport marks price changes made by the external importer
+	PriceOriginImport = "IMPORT"
+	// PriceOriginScheduler marks changes made by the publication scheduler
+	PriceOriginScheduler = "SCHEDULER"
+	// PriceOriginArchiver marks changes made by the stale listings archiver
+	PriceOriginArchiver = "ARCHIVER"
+)
+
+type priceOriginKey struct{}
+
+// withPriceOrigin tags the price history entries recorded with ctx
+func withPriceOrigin(ctx context.Context, origem string) context.Context {
+	return context.WithValue(ctx, priceOriginKey{}, origem)
+}
+
+func priceOrigin(ctx context.Context) string {
+	if origem, ok := ctx.Value(priceOriginKey{}).(string); ok {
+		return origem
+	}
+	return PriceOriginAPI
+}
+
+// RecordPriceHistory compares the current sale and rental prices of the given properties with
+// their latest history entries and records the ones that changed, returning the new entries. It is
+// idempotent, so callers run it after any write that may affect a price instead of tracking old
+// values themselves.
+func RecordPriceHistory(ctx context.Context, db *gorm.DB, imovelIDs []uint) ([]HistoricoPreco, error) {
+	if len(imovelIDs) == 0 {
+		return nil, nil
+	}
+
+	var current []struct {
+		ID      uint
+		Venda   *float64
+		Aluguel *float64
+	}
+	if err := db.WithContext(ctx).Model(&Imovel{}).
+		Select("imoveis.id, hist_pv.preco AS venda, hist_pa.preco AS aluguel").
+		Joins("LEFT JOIN preco_vendas AS hist_pv ON hist_pv.id = imoveis.preco_venda_id AND hist_pv.deleted_at IS NULL").
+		Joins("LEFT JOIN preco_aluguels AS hist_pa ON hist_pa.id = imoveis.preco_aluguel_id AND hist_pa.deleted_at IS NULL").
+		Where("imoveis.id IN ?", imovelIDs).
+		Scan(&current).Error; err != nil {
+		return nil, fmt.Errorf("failed to read current prices: %w", err)
+	}
+
+	var latest []HistoricoPreco
+	if err := db.WithContext(ctx).
+		Where("id IN (?)", db.Model(&HistoricoPreco{}).
+			Select("MAX(id)").
+			Where("imovel_id IN ?", imovelIDs).
+			Group("imovel_id, tipo")).
+		Find(&latest).Error; err != nil {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+
+	type historyKey struct {
+		imovelID uint
+		tipo     string
+	}
+	last := make(map[historyKey]*HistoricoPreco, len(latest))
+	for i := range latest {
+		last[historyKey{latest[i].ImovelID, latest[i].Tipo}] = &latest[i]
+	}
+
+	origem := priceOrigin(ctx)
+	var entries []HistoricoPreco
+	for _, row := range current {
+		for tipo, preco := range map[string]*float64{HistoricoTipoVenda: row.Venda, HistoricoTipoAluguel: row.Aluguel} {
+			previous := last[historyKey{row.ID, tipo}]
+			if previous == nil && preco == nil {
+				continue
+			}
+			if previous != nil && samePrice(previous.Preco, preco) {
+				continue
+			}
+
+			entry := HistoricoPreco{ImovelID: row.ID, Tipo: tipo, Preco: preco, Origem: origem}
+			if previous != nil {
+				entry.PrecoAnterior = previous.Preco
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	if err := db.WithContext(ctx).Create(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to record price history: %w", err)
+	}
+	return entries, nil
+}
+
+// PriceChangedEvents returns the events of the history entries that change a price to another
+// value. Prices set for the first time or removed are not reported.
+func PriceChangedEvents(entries []HistoricoPreco) []events.PriceChanged {
+	var changes []events.PriceChanged
+	for _, entry := range entries {
+		if entry.PrecoAnterior == nil || entry.Preco == nil {
+			continue
+		}
+		changes = append(changes, events.PriceChanged{
+			ImovelID:      entry.ImovelID,
+			Tipo:          entry.Tipo,
+			PrecoAnterior: *entry.PrecoAnterior,
+			Preco:         *entry.Preco,
+			Origem:        entry.Origem,
+		})
+	}
+	return changes
+}
+
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
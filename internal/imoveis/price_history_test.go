@@ -0,0 +1,100 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+func TestPriceHistory(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+	repo := NewRepository(database)
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	historico, err := svc.GetPriceHistory(ctx, created.ID, &PriceHistoryQuery{})
+	require.NoError(t, err)
+	require.Len(t, historico, 2, "the initial prices are recorded")
+	for _, entry := range historico {
+		assert.Nil(t, entry.PrecoAnterior)
+		assert.Nil(t, entry.Variacao)
+		assert.Equal(t, PriceOriginAPI, entry.Origem)
+	}
+
+	// Writes that do not change a price record nothing
+	titulo := "Apartamento reformado"
+	_, err = svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{Titulo: titulo})
+	require.NoError(t, err)
+	recorded, err := repo.RecordPriceHistory(ctx, []uint{created.ID})
+	require.NoError(t, err)
+	assert.Empty(t, recorded)
+
+	// A cheaper sale price attached by the importer
+	cheaper := &PrecoVenda{Preco: 495000, Ativo: true}
+	require.NoError(t, repo.CreatePrecoVenda(ctx, cheaper))
+	require.NoError(t, svc.AttachPrecoVenda(withPriceOrigin(ctx, PriceOriginImport), created.ID, cheaper.ID))
+
+	// Removing the rental price is recorded too
+	_, err = svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{PrecoAluguelID: Nullable[uint]{Set: true, Null: true}})
+	require.NoError(t, err)
+
+	venda, err := svc.GetPriceHistory(ctx, created.ID, &PriceHistoryQuery{Tipo: HistoricoTipoVenda})
+	require.NoError(t, err)
+	require.Len(t, venda, 2)
+	latest := venda[1]
+	assert.Equal(t, 495000.0, *latest.Preco)
+	assert.Equal(t, 550000.0, *latest.PrecoAnterior)
+	require.NotNil(t, latest.Variacao)
+	assert.Equal(t, -10.0, *latest.Variacao)
+	assert.Equal(t, PriceOriginImport, latest.Origem)
+
+	aluguel, err := svc.GetPriceHistory(ctx, created.ID, &PriceHistoryQuery{Tipo: HistoricoTipoAluguel})
+	require.NoError(t, err)
+	require.Len(t, aluguel, 2)
+	assert.Nil(t, aluguel[1].Preco)
+	assert.Equal(t, 2800.0, *aluguel[1].PrecoAnterior)
+
+	_, err = svc.GetPriceHistory(ctx, 999, &PriceHistoryQuery{})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+}
+
+// recordingPublisher records the events published by the service
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestPriceHistory_PublishesPriceChanges(t *testing.T) {
+	_, database := setupCreateService(t)
+	publisher := &recordingPublisher{}
+	svc := NewService(NewRepository(database), WithEvents(publisher))
+	ctx := context.Background()
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+	assert.Empty(t, publisher.events, "initial prices are not changes")
+
+	cheaper := &PrecoVenda{Preco: 495000, Ativo: true}
+	require.NoError(t, database.Create(cheaper).Error)
+	require.NoError(t, svc.AttachPrecoVenda(withPriceOrigin(ctx, PriceOriginImport), created.ID, cheaper.ID))
+
+	_, err = svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{PrecoAluguelID: Nullable[uint]{Set: true, Null: true}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []events.Event{events.PriceChanged{
+		ImovelID:      created.ID,
+		Tipo:          HistoricoTipoVenda,
+		PrecoAnterior: 550000,
+		Preco:         495000,
+		Origem:        PriceOriginImport,
+	}}, publisher.events, "removed prices are not reported")
+}
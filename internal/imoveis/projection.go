@@ -0,0 +1,198 @@
+package imoveis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// listIncludes maps the include names of the list endpoints to their preloads
+var listIncludes = map[string]func(*gorm.DB) *gorm.DB{
+	"endereco": func(db *gorm.DB) *gorm.DB { return db.Preload("Endereco") },
+	"empreendimento": func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Endereco")
+		})
+	},
+	"planta": func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Planta", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Anexos")
+		})
+	},
+	"corretorPrincipal": func(db *gorm.DB) *gorm.DB {
+		return db.Preload("CorretorPrincipal").
+			Preload("CorretorPrincipal.Organizacao").
+			Preload("CorretorPrincipal.Foto")
+	},
+	"pacote":          func(db *gorm.DB) *gorm.DB { return db.Preload("Pacote") },
+	"precoVenda":      func(db *gorm.DB) *gorm.DB { return db.Preload("PrecoVenda") },
+	"precoAluguel":    func(db *gorm.DB) *gorm.DB { return db.Preload("PrecoAluguel") },
+	"anexos":          func(db *gorm.DB) *gorm.DB { return db.Preload("Anexos", orderedAnexos) },
+	"caracteristicas": func(db *gorm.DB) *gorm.DB { return db.Preload("Caracteristicas") },
+}
+
+// defaultListIncludes are loaded when no include parameter is given
+var defaultListIncludes = []string{
+	"endereco", "empreendimento", "planta", "corretorPrincipal", "pacote", "precoVenda", "precoAluguel", "anexos",
+}
+
+// includeForeignKeys are the columns a belongs-to preload needs when fields restricts the select
+var includeForeignKeys = map[string]string{
+	"endereco":          "endereco_id",
+	"empreendimento":    "empreendimento_id",
+	"planta":            "planta_id",
+	"corretorPrincipal": "corretor_principal_id",
+	"pacote":            "pacote_id",
+	"precoVenda":        "preco_venda_id",
+	"precoAluguel":      "preco_aluguel_id",
+}
+
+// listFieldColumns maps the selectable ImovelResponse attributes to imoveis columns
+var listFieldColumns = map[string]string{
+	"id":               "id",
+	"id_integracao":    "id_integracao",
+	"titulo":           "titulo",
+	"codigo":           "codigo",
+	"seqCodigo":        "seq_codigo",
+	"tipo":             "tipo",
+	"objetivo":         "objetivo",
+	"finalidade":       "finalidade",
+	"descricao":        "descricao",
+	"metragem":         "metragem",
+	"numQuartos":       "num_quartos",
+	"numSuites":        "num_suites",
+	"numBanheiros":     "num_banheiros",
+	"numVagas":         "num_vagas",
+	"numAndar":         "num_andar",
+	"unidade":          "unidade",
+	"condominio":       "condominio",
+	"iptu":             "iptu",
+	"inscricaoIPTU":    "inscricao_iptu",
+	"slug":             "slug",
+	"metaTitle":        "meta_title",
+	"metaDescription":  "meta_description",
+	"status":           "status",
+	"published":        "published",
+	"closed":           "closed",
+	"publicarEm":       "publicar_em",
+	"expiraEm":         "expira_em",
+	"removidoOrigemEm": "removido_origem_em",
+	"version":          "version",
+	"visualizacoes":    "visualizacoes",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+}
+
+// selectionErrors validates the include and fields parameters
+func (q *ImovelListQuery) selectionErrors() map[string]string {
+	details := map[string]string{}
+	if unknown := unknownNames(q.Include, func(name string) bool { return listIncludes[name] != nil }); len(unknown) > 0 {
+		details["include"] = "unknown relations: " + strings.Join(unknown, ", ")
+	}
+	if unknown := unknownNames(q.Fields, func(name string) bool { return listFieldColumns[name] != "" }); len(unknown) > 0 {
+		details["fields"] = "unknown fields: " + strings.Join(unknown, ", ")
+	}
+	if q.Mode == "map" && (len(q.Include) > 0 || len(q.Fields) > 0) {
+		details["mode"] = "include and fields are not supported in map mode"
+	}
+	return details
+}
+
+func unknownNames(names []string, known func(string) bool) []string {
+	var unknown []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" && !known(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// includes returns the relations to load, the defaults when none were requested
+func (q *ImovelListQuery) includes() []string {
+	if len(q.Include) == 0 {
+		return defaultListIncludes
+	}
+	var names []string
+	for _, name := range q.Include {
+		if name = strings.TrimSpace(name); listIncludes[name] != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// applyListSelection preloads the requested relations and, when fields is set, selects only the
+// columns needed for the requested attributes and relations
+func applyListSelection(db *gorm.DB, query *ImovelListQuery) *gorm.DB {
+	includes := query.includes()
+	for _, name := range includes {
+		db = listIncludes[name](db)
+	}
+
+	if len(query.Fields) == 0 {
+		return db
+	}
+
+	// id and created_at are always read: preloads and cursors depend on them
+	columns := map[string]bool{"id": true, "created_at": true}
+	for _, field := range query.Fields {
+		if column := listFieldColumns[strings.TrimSpace(field)]; column != "" {
+			columns[column] = true
+		}
+	}
+	for _, name := range includes {
+		if column := includeForeignKeys[name]; column != "" {
+			columns[column] = true
+		}
+	}
+
+	selected := make([]string, 0, len(columns))
+	for column := range columns {
+		selected = append(selected, "imoveis."+column)
+	}
+	sort.Strings(selected)
+	return db.Select(selected)
+}
+
+// responseFields returns the JSON keys kept when fields is set, nil to return every attribute
+func (q *ImovelListQuery) responseFields() map[string]bool {
+	if len(q.Fields) == 0 {
+		return nil
+	}
+
+	keys := map[string]bool{"id": true}
+	for _, field := range q.Fields {
+		if field = strings.TrimSpace(field); listFieldColumns[field] != "" {
+			keys[field] = true
+		}
+	}
+	for _, name := range q.includes() {
+		keys[name] = true
+	}
+	return keys
+}
+
+// MarshalJSON writes only the selected attributes when the response was built for a fields query
+func (r ImovelResponse) MarshalJSON() ([]byte, error) {
+	type plain ImovelResponse
+	data, err := json.Marshal(plain(r))
+	if err != nil || r.fields == nil {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to project property fields: %w", err)
+	}
+	projected := make(map[string]json.RawMessage, len(r.fields))
+	for key, value := range all {
+		if r.fields[key] {
+			projected[key] = value
+		}
+	}
+	return json.Marshal(projected)
+}
@@ -0,0 +1,73 @@
+package imoveis
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListImoveis_IncludeAndFields(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	all, err := svc.ListImoveis(ctx, &ImovelListQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all.Results, 1)
+	assert.NotNil(t, all.Results[0].Endereco, "all list relations are loaded by default")
+	assert.NotNil(t, all.Results[0].PrecoVenda)
+	assert.Empty(t, all.Results[0].Caracteristicas)
+
+	selected, err := svc.ListImoveis(ctx, &ImovelListQuery{
+		Limit:   10,
+		Include: []string{"precoVenda", "caracteristicas"},
+		Fields:  []string{"titulo", "codigo"},
+	})
+	require.NoError(t, err)
+	require.Len(t, selected.Results, 1)
+	result := selected.Results[0]
+	assert.Nil(t, result.Endereco)
+	assert.Nil(t, result.PrecoAluguel)
+	require.NotNil(t, result.PrecoVenda)
+	assert.Equal(t, 550000.0, result.PrecoVenda.Preco)
+	assert.Len(t, result.Caracteristicas, 2)
+	assert.Empty(t, result.Descricao, "unselected columns are not read")
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var body map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &body))
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"id", "titulo", "codigo", "precoVenda", "caracteristicas"}, keys)
+
+	cursor := ""
+	page, err := svc.ListImoveis(ctx, &ImovelListQuery{Limit: 10, Fields: []string{"titulo"}, Cursor: &cursor})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "Apartamento no centro", page.Results[0].Titulo)
+	assert.NotNil(t, page.Results[0].Endereco)
+}
+
+func TestImovelResponse_MarshalJSONWithoutFields(t *testing.T) {
+	data, err := json.Marshal(ImovelResponse{ID: 1, Titulo: "Casa"})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"descricao":""`, "responses without a selection keep every attribute")
+}
+
+func TestImovelListQuery_SelectionErrors(t *testing.T) {
+	assert.Empty(t, (&ImovelListQuery{Include: []string{"endereco", " anexos"}, Fields: []string{"titulo"}}).selectionErrors())
+
+	details := (&ImovelListQuery{Include: []string{"proprietario"}, Fields: []string{"senha"}}).selectionErrors()
+	assert.Contains(t, details["include"], "proprietario")
+	assert.Contains(t, details["fields"], "senha")
+
+	assert.Contains(t, (&ImovelListQuery{Mode: "map", Fields: []string{"titulo"}}).selectionErrors(), "mode")
+}
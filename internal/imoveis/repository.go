@@ -3,6 +3,8 @@ package imoveis
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,20 +16,39 @@ type Repository interface {
 
 	// Read
 	FindByID(ctx context.Context, id uint) (*Imovel, error)
+	FindForWorkflow(ctx context.Context, ids []uint) ([]Imovel, error)
+	FindForComparison(ctx context.Context, ids []uint) ([]Imovel, error)
+	FindDestaques(ctx context.Context, query *DestaquesQuery, limit int) ([]Imovel, error)
+	FindDueForPublication(ctx context.Context, now time.Time, limit int) ([]Imovel, error)
+	FindDueForExpiration(ctx context.Context, now time.Time, limit int) ([]Imovel, error)
+	FindStale(ctx context.Context, now time.Time, defaultDays, limit int) ([]Imovel, error)
 	FindByCodigo(ctx context.Context, codigo string) (*Imovel, error)
+	FindBySlug(ctx context.Context, slug string) (*Imovel, error)
+	FindWithEndereco(ctx context.Context, id uint) (*Imovel, error)
 	FindByIdIntegracao(ctx context.Context, idIntegracao string) (*Imovel, error)
 
 	// Update
 	Update(ctx context.Context, imovel *Imovel) error
+	Patch(ctx context.Context, id uint, updates map[string]interface{}) error
+	PatchVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error
+	UpdateStatus(ctx context.Context, id uint, status string, published bool) error
+	SetRemovidoOrigem(ctx context.Context, id uint, at time.Time) error
 
 	// Delete
 	Delete(ctx context.Context, id uint) error
 	HardDelete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) (bool, error)
+	FindDeletedCorretor(ctx context.Context, id uint) (corretorPrincipalID uint, found bool, err error)
+	ListDeleted(ctx context.Context, page, limit int) ([]Imovel, int64, error)
 
 	// List & Filter
 	List(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error)
+	ListByCursor(ctx context.Context, query *ImovelListQuery, after *listCursor) (*ImovelListResponse, error)
+	ListMap(ctx context.Context, query *ImovelListQuery) (*ImovelMapListResponse, error)
+	Facets(ctx context.Context, query *ImovelListQuery) (*ImovelFacetsResponse, error)
 	ListByEmpreendimento(ctx context.Context, empreendimentoID uint, page, limit int) ([]Imovel, int64, error)
 	ListByCorretorPrincipal(ctx context.Context, corretorPrincipalID uint, page, limit int) ([]Imovel, int64, error)
+	ListByOrganizacao(ctx context.Context, organizacaoID uint, page, limit int) ([]Imovel, int64, error)
 
 	// Bulk Operations
 	CreateBatch(ctx context.Context, imoveis []Imovel) error
@@ -38,14 +59,23 @@ type Repository interface {
 	CountByStatus(ctx context.Context, status string) (int64, error)
 	CountByEmpreendimento(ctx context.Context, empreendimentoID uint) (int64, error)
 
+	// Views
+	IncrementViews(ctx context.Context, id uint) (bool, error)
+	TopViewed(ctx context.Context, limit int) ([]ImovelViewStats, error)
+	ViewStatsByCorretor(ctx context.Context, limit int) ([]CorretorViewStats, error)
+	CorretorStats(ctx context.Context, corretorPrincipalID uint) (*CorretorImovelStats, error)
+
 	// Exists
 	ExistsByCodigo(ctx context.Context, codigo string) (bool, error)
+	ExistsBySlug(ctx context.Context, slug string, excludeID uint) (bool, error)
 	ExistsByIdIntegracao(ctx context.Context, idIntegracao string) (bool, error)
 
 	// Relationships - Anexos
 	AddAnexo(ctx context.Context, imovelID uint, anexo *Anexo) error
 	RemoveAnexo(ctx context.Context, imovelID, anexoID uint) error
 	GetAnexos(ctx context.Context, imovelID uint) ([]Anexo, error)
+	FindAnexoByID(ctx context.Context, id uint) (*Anexo, error)
+	UpdateAnexoVariants(ctx context.Context, id uint, variants AnexoVariants) error
 
 	// Relationships - Single associations
 	UpdateEndereco(ctx context.Context, imovelID, enderecoID uint) error
@@ -53,19 +83,41 @@ type Repository interface {
 	UpdatePlanta(ctx context.Context, imovelID, plantaID uint) error
 	UpdatePacote(ctx context.Context, imovelID, pacoteID uint) error
 	UpdateCorretorPrincipal(ctx context.Context, imovelID, corretorPrincipalID uint) error
+	CorretorExists(ctx context.Context, corretorPrincipalID uint) (bool, error)
 	UpdatePrecoVenda(ctx context.Context, imovelID, precoVendaID uint) error
 	UpdatePrecoAluguel(ctx context.Context, imovelID, precoAluguelID uint) error
+	UpdateSlug(ctx context.Context, id uint, slug string) error
 
-	// Endereco management
+	// Endereco and price management
 	CreateEndereco(ctx context.Context, endereco *Endereco) error
+	CreatePrecoVenda(ctx context.Context, preco *PrecoVenda) error
+	CreatePrecoAluguel(ctx context.Context, preco *PrecoAluguel) error
 
 	// Relationships - Caracteristicas
 	AddCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error
 	RemoveCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error
 	GetCaracteristicas(ctx context.Context, imovelID uint) ([]Caracteristica, error)
 	RemoveAllCaracteristicas(ctx context.Context, imovelID uint) error
+	CountCaracteristicas(ctx context.Context, caracteristicaIDs []uint) (int64, error)
+
+	// Price history
+	RecordPriceHistory(ctx context.Context, imovelIDs []uint) ([]HistoricoPreco, error)
+	ListPriceHistory(ctx context.Context, imovelID uint, tipo string) ([]HistoricoPreco, error)
+
+	// Audit trail
+	RecordAudit(ctx context.Context, imovelID uint, entidade, acao string, before, after interface{}) error
+	ListAudit(ctx context.Context, imovelID uint, page, limit int) ([]ImovelAudit, int64, error)
+
+	// Transaction runs fn in a database transaction; repository calls made with the
+	// context passed to fn join it
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+
+	// Import from the external API
+	ImportRepository
 }
 
+type txKey struct{}
+
 type repository struct {
 	db *gorm.DB
 }
@@ -75,18 +127,74 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// getDB returns the DB from context if in transaction, otherwise returns the repository's DB
+func (r *repository) getDB(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return r.db
+}
+
+// Transaction executes a function within a database transaction. Called within another
+// transaction it runs in a savepoint of it. Functions registered with afterCommit run once the
+// outermost transaction commits.
+func (r *repository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	hooks, nested := ctx.Value(txHooksKey{}).(*[]func())
+	if !nested {
+		hooks = &[]func(){}
+		ctx = context.WithValue(ctx, txHooksKey{}, hooks)
+	}
+
+	registered := len(*hooks)
+	err := r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, txKey{}, tx)
+		return fn(txCtx)
+	})
+	if err != nil {
+		// Drop the hooks of the work rolled back
+		*hooks = (*hooks)[:registered]
+		return err
+	}
+
+	if !nested {
+		for _, hook := range *hooks {
+			hook()
+		}
+	}
+	return nil
+}
+
+type txHooksKey struct{}
+
+// afterCommit runs fn once the transaction of ctx commits, or right away outside a transaction;
+// fn is dropped if the transaction rolls back. Side effects seen outside the database (queues,
+// caches) go through it so they never act on uncommitted rows.
+func afterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(txHooksKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
 // Create creates a new property
 func (r *repository) Create(ctx context.Context, imovel *Imovel) error {
-	if err := r.db.WithContext(ctx).Create(imovel).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Create(imovel).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// orderedAnexos sorts the anexos of a property: the imported images in the order of the source,
+// then the others as they were added
+func orderedAnexos(db *gorm.DB) *gorm.DB {
+	return db.Order("ordem = 0, ordem, id")
+}
+
 // FindByID retrieves a property by ID with all relations
 func (r *repository) FindByID(ctx context.Context, id uint) (*Imovel, error) {
 	var imovel Imovel
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Preload("Endereco").
 		Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
 			return db.Preload("Endereco").Preload("Torres").Preload("Plantas").Preload("Caracteristicas").Preload("Anexos")
@@ -100,7 +208,8 @@ func (r *repository) FindByID(ctx context.Context, id uint) (*Imovel, error) {
 		Preload("Pacote").
 		Preload("PrecoVenda").
 		Preload("PrecoAluguel").
-		Preload("Anexos").
+		Preload("Anexos", orderedAnexos).
+		Preload("Caracteristicas").
 		Where("id = ?", id).
 		First(&imovel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -111,10 +220,157 @@ func (r *repository) FindByID(ctx context.Context, id uint) (*Imovel, error) {
 	return &imovel, nil
 }
 
+// FindForWorkflow loads the given properties with the relations checked by the publication
+// workflow, joining the transaction of ctx if any. Missing IDs are left out.
+func (r *repository) FindForWorkflow(ctx context.Context, ids []uint) ([]Imovel, error) {
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Preload("Endereco").
+		Preload("Anexos", orderedAnexos).
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Where("id IN ?", ids).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
+// FindDueForPublication returns the properties being edited whose publicar_em has passed, with the
+// relations checked by the publication workflow. Properties that would already be expired are left out.
+func (r *repository) FindDueForPublication(ctx context.Context, now time.Time, limit int) ([]Imovel, error) {
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Preload("Endereco").
+		Preload("Anexos", orderedAnexos).
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Where("status IN ? OR status IS NULL", []string{StatusEmEdicao, ""}).
+		Where("publicar_em <= ?", now).
+		Where("expira_em IS NULL OR expira_em > ?", now).
+		Order("publicar_em").
+		Limit(limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
+// FindDueForExpiration returns the published properties whose expira_em has passed
+func (r *repository) FindDueForExpiration(ctx context.Context, now time.Time, limit int) ([]Imovel, error) {
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Where("status = ?", StatusPublicado).
+		Where("expira_em <= ?", now).
+		Order("expira_em").
+		Limit(limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
+// FindStale returns the published properties not updated within the archive period of the
+// organizacao of their corretor: its dias_arquivamento when set, defaultDays otherwise (0 never
+// archives). The oldest come first, with the corretor and organizacao preloaded.
+func (r *repository) FindStale(ctx context.Context, now time.Time, defaultDays, limit int) ([]Imovel, error) {
+	var periods []int
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Organizacao{}).
+		Where("dias_arquivamento > 0").
+		Distinct().
+		Pluck("dias_arquivamento", &periods).Error; err != nil {
+		return nil, err
+	}
+
+	// One condition per distinct period keeps the date arithmetic out of SQL
+	var conditions []string
+	var args []interface{}
+	for _, dias := range periods {
+		conditions = append(conditions, "(organizacoes.dias_arquivamento = ? AND imoveis.updated_at < ?)")
+		args = append(args, dias, now.AddDate(0, 0, -dias))
+	}
+	if defaultDays > 0 {
+		// Also matches properties without a corretor or organizacao
+		conditions = append(conditions, "(organizacoes.dias_arquivamento IS NULL AND imoveis.updated_at < ?)")
+		args = append(args, now.AddDate(0, 0, -defaultDays))
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Select("imoveis.*").
+		Joins("LEFT JOIN corretores_principais ON corretores_principais.id = imoveis.corretor_principal_id AND corretores_principais.deleted_at IS NULL").
+		Joins("LEFT JOIN organizacoes ON organizacoes.id = corretores_principais.organizacao_id AND organizacoes.deleted_at IS NULL").
+		Preload("CorretorPrincipal").
+		Preload("CorretorPrincipal.Organizacao").
+		Where("imoveis.status = ?", StatusPublicado).
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Order("imoveis.updated_at").
+		Order("imoveis.id").
+		Limit(limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
+// FindDestaques returns the published properties whose pacote is em_destaque, most recently updated
+// first, with the relations shown on listing cards
+func (r *repository) FindDestaques(ctx context.Context, query *DestaquesQuery, limit int) ([]Imovel, error) {
+	db := r.getDB(ctx).WithContext(ctx).
+		Joins("INNER JOIN pacotes ON pacotes.id = imoveis.pacote_id AND pacotes.deleted_at IS NULL").
+		Where("pacotes.em_destaque = ?", true).
+		Where("imoveis.status = ? AND imoveis.published = ?", StatusPublicado, true)
+	if query.Objetivo != "" {
+		db = db.Where("imoveis.objetivo = ?", query.Objetivo)
+	}
+	if query.Tipo != "" {
+		db = db.Where("imoveis.tipo = ?", query.Tipo)
+	}
+	if query.Cidade != "" {
+		db = db.Joins("INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id").
+			Where("enderecos.cidade ILIKE ?", "%"+query.Cidade+"%")
+	}
+
+	var imoveis []Imovel
+	if err := db.
+		Preload("Endereco").
+		Preload("Pacote").
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Preload("Anexos", orderedAnexos).
+		Order("imoveis.updated_at DESC").
+		Order("imoveis.id DESC").
+		Limit(limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
+// FindForComparison loads the given properties with the relations shown side by side by the
+// comparison endpoint. Missing IDs are left out.
+func (r *repository) FindForComparison(ctx context.Context, ids []uint) ([]Imovel, error) {
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Preload("Endereco").
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Preload("Caracteristicas").
+		Preload("Anexos", orderedAnexos).
+		Where("id IN ?", ids).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+	return imoveis, nil
+}
+
 // FindByCodigo retrieves a property by codigo
 func (r *repository) FindByCodigo(ctx context.Context, codigo string) (*Imovel, error) {
 	var imovel Imovel
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Preload("Endereco").
 		Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
 			return db.Preload("Endereco").Preload("Torres").Preload("Plantas").Preload("Caracteristicas").Preload("Anexos")
@@ -128,7 +384,7 @@ func (r *repository) FindByCodigo(ctx context.Context, codigo string) (*Imovel,
 		Preload("Pacote").
 		Preload("PrecoVenda").
 		Preload("PrecoAluguel").
-		Preload("Anexos").
+		Preload("Anexos", orderedAnexos).
 		Where("codigo = ?", codigo).
 		First(&imovel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -139,10 +395,54 @@ func (r *repository) FindByCodigo(ctx context.Context, codigo string) (*Imovel,
 	return &imovel, nil
 }
 
+// FindBySlug retrieves a property by slug
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Imovel, error) {
+	var imovel Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Preload("Endereco").
+		Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Endereco").Preload("Torres").Preload("Plantas").Preload("Caracteristicas").Preload("Anexos")
+		}).
+		Preload("Planta", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Anexos")
+		}).
+		Preload("CorretorPrincipal").
+		Preload("CorretorPrincipal.Organizacao").
+		Preload("CorretorPrincipal.Foto").
+		Preload("Pacote").
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Preload("Anexos", orderedAnexos).
+		Preload("Caracteristicas").
+		Where("slug = ?", slug).
+		First(&imovel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &imovel, nil
+}
+
+// FindWithEndereco retrieves a property with only its endereco preloaded, joining the transaction
+// of ctx if any
+func (r *repository) FindWithEndereco(ctx context.Context, id uint) (*Imovel, error) {
+	var imovel Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Preload("Endereco").
+		First(&imovel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &imovel, nil
+}
+
 // FindByIdIntegracao retrieves a property by integration ID
 func (r *repository) FindByIdIntegracao(ctx context.Context, idIntegracao string) (*Imovel, error) {
 	var imovel Imovel
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Preload("Endereco").
 		Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
 			return db.Preload("Endereco").Preload("Torres").Preload("Plantas").Preload("Caracteristicas").Preload("Anexos")
@@ -156,7 +456,7 @@ func (r *repository) FindByIdIntegracao(ctx context.Context, idIntegracao string
 		Preload("Pacote").
 		Preload("PrecoVenda").
 		Preload("PrecoAluguel").
-		Preload("Anexos").
+		Preload("Anexos", orderedAnexos).
 		Where("id_integracao = ?", idIntegracao).
 		First(&imovel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -169,19 +469,80 @@ func (r *repository) FindByIdIntegracao(ctx context.Context, idIntegracao string
 
 // Update updates a property
 func (r *repository) Update(ctx context.Context, imovel *Imovel) error {
+	// Optimistic locking: only write over the version that was loaded, and move it forward
+	expected := imovel.Version
+	imovel.Version = expected + 1
+
 	// Omit associations to prevent GORM from trying to update them
 	// Only update the imovel table fields, not related entities
-	if err := r.db.WithContext(ctx).Model(imovel).
+	result := r.getDB(ctx).WithContext(ctx).Model(imovel).
+		Where("version = ?", expected).
 		Omit("Endereco", "Empreendimento", "Planta", "CorretorPrincipal", "Pacote", "PrecoVenda", "PrecoAluguel", "Anexos").
-		Updates(imovel).Error; err != nil {
+		Updates(imovel)
+	if result.Error != nil {
+		imovel.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		imovel.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Patch updates only the given columns, allowing zero values and NULLs to be written
+func (r *repository) Patch(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// PatchVersion is Patch with optimistic locking: the columns are only written when the stored
+// version still equals version, which is then incremented. Returns ErrVersionConflict otherwise.
+func (r *repository) PatchVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error {
+	columns := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		columns[column] = value
+	}
+	columns["version"] = gorm.Expr("version + 1")
+
+	result := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// UpdateStatus sets the publication status and flag together, bumping the version. Joins the
+// transaction of ctx if any.
+func (r *repository) UpdateStatus(ctx context.Context, id uint, status string, published bool) error {
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "published": published, "version": gorm.Expr("version + 1")}).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-// Delete soft deletes a property
+// SetRemovidoOrigem records when the import found the property gone from its source, bumping the
+// version but not updated_at, which tracks the property edits
+func (r *repository) SetRemovidoOrigem(ctx context.Context, id uint, at time.Time) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"removido_origem_em": at, "version": gorm.Expr("version + 1")}).Error
+}
+
+// Delete soft deletes a property, joining the transaction of ctx if any
 func (r *repository) Delete(ctx context.Context, id uint) error {
-	if err := r.db.WithContext(ctx).Delete(&Imovel{}, id).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Delete(&Imovel{}, id).Error; err != nil {
 		return err
 	}
 	return nil
@@ -189,123 +550,302 @@ func (r *repository) Delete(ctx context.Context, id uint) error {
 
 // HardDelete permanently deletes a property
 func (r *repository) HardDelete(ctx context.Context, id uint) error {
-	if err := r.db.WithContext(ctx).Unscoped().Delete(&Imovel{}, id).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Unscoped().Delete(&Imovel{}, id).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// Restore clears deleted_at of a soft-deleted property; returns false when there is no such deleted property
+func (r *repository) Restore(ctx context.Context, id uint) (bool, error) {
+	result := r.getDB(ctx).WithContext(ctx).Unscoped().Model(&Imovel{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindDeletedCorretor returns the corretor principal of a soft-deleted property; found is false
+// when there is no such deleted property
+func (r *repository) FindDeletedCorretor(ctx context.Context, id uint) (uint, bool, error) {
+	var imoveis []Imovel
+	if err := r.getDB(ctx).WithContext(ctx).Unscoped().
+		Select("id", "corretor_principal_id").
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Limit(1).
+		Find(&imoveis).Error; err != nil {
+		return 0, false, err
+	}
+	if len(imoveis) == 0 {
+		return 0, false, nil
+	}
+	return imoveis[0].CorretorPrincipalID, true, nil
+}
+
+// ListDeleted retrieves soft-deleted properties, most recently deleted first
+func (r *repository) ListDeleted(ctx context.Context, page, limit int) ([]Imovel, int64, error) {
+	var imoveis []Imovel
+	var total int64
+
+	db := r.getDB(ctx).WithContext(ctx).Unscoped().Model(&Imovel{}).Where("deleted_at IS NOT NULL")
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := db.Preload("Endereco").
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Order("deleted_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return imoveis, total, nil
+}
+
 // List retrieves properties with filtering and pagination
 func (r *repository) List(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error) {
 	var imoveis []Imovel
+
+	total, err := r.countListed(ctx, query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply sorting
+	db := applyListSort(r.applyListFilters(r.getDB(ctx).WithContext(ctx), query, false), query)
+
+	// Apply pagination
+	offset := (query.Page - 1) * query.Limit
+	if err := applyListSelection(db, query).
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+
+	// Build response
+	results := make([]ImovelResponse, len(imoveis))
+	fields := query.responseFields()
+	for i, imovel := range imoveis {
+		results[i] = r.mapToResponse(&imovel)
+		results[i].fields = fields
+	}
+
+	return newListResponse(results, total, query.Page, query.Limit), nil
+}
+
+// ListByCursor retrieves the page of properties after the given keyset position, ordered by
+// created_at and id. One extra row is read to know whether another page follows; total is not counted.
+func (r *repository) ListByCursor(ctx context.Context, query *ImovelListQuery, after *listCursor) (*ImovelListResponse, error) {
+	var imoveis []Imovel
+
+	db := r.applyListFilters(r.getDB(ctx).WithContext(ctx), query, false)
+
+	direction, comparison := "DESC", "<"
+	if !query.sortTerms()[0].Desc {
+		direction, comparison = "ASC", ">"
+	}
+	if after != nil {
+		db = db.Where(
+			"(imoveis.created_at "+comparison+" ? OR (imoveis.created_at = ? AND imoveis.id "+comparison+" ?))",
+			after.CreatedAt, after.CreatedAt, after.ID,
+		)
+	}
+
+	if err := applyListSelection(db, query).
+		Order("imoveis.created_at " + direction).
+		Order("imoveis.id " + direction).
+		Limit(query.Limit + 1).
+		Find(&imoveis).Error; err != nil {
+		return nil, err
+	}
+
+	hasNext := len(imoveis) > query.Limit
+	if hasNext {
+		imoveis = imoveis[:query.Limit]
+	}
+
+	results := make([]ImovelResponse, len(imoveis))
+	fields := query.responseFields()
+	for i, imovel := range imoveis {
+		results[i] = r.mapToResponse(&imovel)
+		results[i].fields = fields
+	}
+
+	response := &ImovelListResponse{
+		Limit:   query.Limit,
+		HasNext: hasNext,
+		HasPrev: after != nil,
+		Results: results,
+	}
+	if hasNext {
+		response.NextCursor = encodeListCursor(&imoveis[len(imoveis)-1])
+	}
+	return response, nil
+}
+
+// countListed counts the properties matching the list filters in a dedicated query, so the sort
+// joins and preloads of the page query never reach the COUNT
+func (r *repository) countListed(ctx context.Context, query *ImovelListQuery, withEndereco bool) (int64, error) {
 	var total int64
+	err := r.applyListFilters(r.getDB(ctx).WithContext(ctx).Model(&Imovel{}), query, withEndereco).
+		Count(&total).Error
+	return total, err
+}
+
+// listFilterJoins returns the joins the list filters need, each once and in a fixed order. They
+// follow belongs-to relations, so they never multiply rows; caracteristicas use a subquery for that
+// reason. withEndereco forces the enderecos join even when no address filter is set.
+func listFilterJoins(query *ImovelListQuery, withEndereco bool) []string {
+	var joins []string
+	if query.MinPreco > 0 || query.MaxPreco > 0 {
+		joins = append(joins, "INNER JOIN preco_vendas ON preco_vendas.id = imoveis.preco_venda_id")
+	}
+	if query.MinPrecoAluguel > 0 || query.MaxPrecoAluguel > 0 {
+		joins = append(joins, "INNER JOIN preco_aluguels ON preco_aluguels.id = imoveis.preco_aluguel_id")
+	}
+	if withEndereco || query.Rua != "" || query.Cidade != "" || query.Bairro != "" || query.HasBoundingBox() {
+		joins = append(joins, "INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id")
+	}
+	return joins
+}
 
-	db := r.db.WithContext(ctx)
+// applyListFilters adds the WHERE clauses and joins shared by List, ListMap and Facets.
+// Columns are qualified since the joined tables share some of their names.
+func (r *repository) applyListFilters(db *gorm.DB, query *ImovelListQuery, withEndereco bool) *gorm.DB {
+	for _, join := range listFilterJoins(query, withEndereco) {
+		db = db.Joins(join)
+	}
 
-	// Apply filters
 	if query.Codigo != "" {
-		db = db.Where("codigo ILIKE ?", "%"+query.Codigo+"%")
+		db = db.Where("imoveis.codigo ILIKE ?", "%"+query.Codigo+"%")
 	}
 	if query.Tipo != "" {
-		db = db.Where("tipo = ?", query.Tipo)
+		db = db.Where("imoveis.tipo = ?", query.Tipo)
 	}
 	if query.Objetivo != "" {
-		db = db.Where("objetivo = ?", query.Objetivo)
+		db = db.Where("imoveis.objetivo = ?", query.Objetivo)
 	}
 	if query.Finalidade != "" {
-		db = db.Where("finalidade = ?", query.Finalidade)
+		db = db.Where("imoveis.finalidade = ?", query.Finalidade)
 	}
 	if query.Status != "" {
-		db = db.Where("status = ?", query.Status)
+		db = db.Where("imoveis.status = ?", query.Status)
 	}
 	if query.Published != nil {
-		db = db.Where("published = ?", *query.Published)
+		db = db.Where("imoveis.published = ?", *query.Published)
+	}
+	if query.RemovidoOrigem != nil {
+		if *query.RemovidoOrigem {
+			db = db.Where("imoveis.removido_origem_em IS NOT NULL")
+		} else {
+			db = db.Where("imoveis.removido_origem_em IS NULL")
+		}
 	}
 	if query.MinPreco > 0 {
-		db = db.Joins("LEFT JOIN preco_vendas ON preco_vendas.id = imoveis.preco_venda_id").
-			Where("preco_vendas.preco >= ?", query.MinPreco)
+		db = db.Where("preco_vendas.preco >= ?", query.MinPreco)
 	}
 	if query.MaxPreco > 0 {
-		db = db.Joins("LEFT JOIN preco_vendas ON preco_vendas.id = imoveis.preco_venda_id").
-			Where("preco_vendas.preco <= ?", query.MaxPreco)
+		db = db.Where("preco_vendas.preco <= ?", query.MaxPreco)
+	}
+	if query.MinPrecoAluguel > 0 {
+		db = db.Where("preco_aluguels.preco >= ?", query.MinPrecoAluguel)
+	}
+	if query.MaxPrecoAluguel > 0 {
+		db = db.Where("preco_aluguels.preco <= ?", query.MaxPrecoAluguel)
 	}
 	if query.MinMetragem > 0 {
-		db = db.Where("metragem >= ?", query.MinMetragem)
+		db = db.Where("imoveis.metragem >= ?", query.MinMetragem)
 	}
 	if query.MaxMetragem > 0 {
-		db = db.Where("metragem <= ?", query.MaxMetragem)
+		db = db.Where("imoveis.metragem <= ?", query.MaxMetragem)
 	}
 	if query.Rua != "" {
-		db = db.Joins("INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id").
-			Where("enderecos.rua ILIKE ?", "%"+query.Rua+"%")
+		db = db.Where("enderecos.rua ILIKE ?", "%"+query.Rua+"%")
 	}
 	if query.Cidade != "" {
-		db = db.Joins("INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id").
-			Where("enderecos.cidade ILIKE ?", "%"+query.Cidade+"%")
+		db = db.Where("enderecos.cidade ILIKE ?", "%"+query.Cidade+"%")
 	}
 	if query.Bairro != "" {
-		db = db.Joins("INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id").
-			Where("enderecos.bairro ILIKE ?", "%"+query.Bairro+"%")
+		db = db.Where("enderecos.bairro ILIKE ?", "%"+query.Bairro+"%")
+	}
+	if query.HasBoundingBox() {
+		db = db.Where("enderecos.latitude BETWEEN ? AND ?", *query.MinLat, *query.MaxLat).
+			Where("enderecos.longitude BETWEEN ? AND ?", *query.MinLng, *query.MaxLng)
 	}
 	if query.NumQuartos > 0 {
-		db = db.Where("num_quartos >= ?", query.NumQuartos)
+		db = db.Where("imoveis.num_quartos >= ?", query.NumQuartos)
 	}
 	if query.NumBanheiros > 0 {
-		db = db.Where("num_banheiros >= ?", query.NumBanheiros)
+		db = db.Where("imoveis.num_banheiros >= ?", query.NumBanheiros)
 	}
 	if query.NumGaragens > 0 {
-		db = db.Where("num_vagas >= ?", query.NumGaragens)
+		db = db.Where("imoveis.num_vagas >= ?", query.NumGaragens)
 	}
 	if query.EmpreendimentoID > 0 {
-		db = db.Where("empreendimento_id = ?", query.EmpreendimentoID)
+		db = db.Where("imoveis.empreendimento_id = ?", query.EmpreendimentoID)
+	}
+	if len(query.Caracteristicas) > 0 {
+		// Subquery instead of a join so matching several features never duplicates rows
+		matching := r.db.Table("imovel_caracteristicas").
+			Select("imovel_id").
+			Where("caracteristica_id IN ?", query.Caracteristicas).
+			Group("imovel_id")
+		if query.CaracteristicasMatch == "all" {
+			matching = matching.Having("COUNT(DISTINCT caracteristica_id) = ?", len(uniqueIDs(query.Caracteristicas)))
+		}
+		db = db.Where("imoveis.id IN (?)", matching)
 	}
 
-	// Count total
-	if err := db.Model(&Imovel{}).Count(&total).Error; err != nil {
-		return nil, err
-	}
+	return db
+}
 
-	// Apply sorting
-	sortField := "created_at"
-	if query.Sort != "" {
-		sortField = query.Sort
+// uniqueIDs removes duplicated IDs preserving order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]struct{}, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
 	}
-	order := "DESC"
-	if query.Order == "asc" {
-		order = "ASC"
+	return unique
+}
+
+// ListMap retrieves the lightweight marker data of properties matching the filters.
+// Properties without an endereco cannot be plotted and are left out.
+func (r *repository) ListMap(ctx context.Context, query *ImovelListQuery) (*ImovelMapListResponse, error) {
+	total, err := r.countListed(ctx, query, true)
+	if err != nil {
+		return nil, err
 	}
-	db = db.Order(sortField + " " + order)
 
-	// Apply pagination
+	// Aliased price joins so they never clash with the ones added by the price filters
+	results := make([]ImovelMapItem, 0, query.Limit)
 	offset := (query.Page - 1) * query.Limit
-	if err := db.Preload("Endereco").
-		Preload("Empreendimento", func(db *gorm.DB) *gorm.DB {
-			return db.Preload("Endereco")
-		}).
-		Preload("Planta", func(db *gorm.DB) *gorm.DB {
-			return db.Preload("Anexos")
-		}).
-		Preload("CorretorPrincipal").
-		Preload("CorretorPrincipal.Organizacao").
-		Preload("CorretorPrincipal.Foto").
-		Preload("Pacote").
-		Preload("PrecoVenda").
-		Preload("PrecoAluguel").
-		Preload("Anexos").
+	if err := r.applyListFilters(r.getDB(ctx).WithContext(ctx).Model(&Imovel{}), query, true).
+		Select(`imoveis.id, imoveis.titulo, enderecos.latitude, enderecos.longitude,
+			CASE WHEN imoveis.objetivo = 'ALUGAR' THEN COALESCE(map_pa.preco, 0) ELSE COALESCE(map_pv.preco, 0) END AS preco`).
+		Joins("LEFT JOIN preco_vendas AS map_pv ON map_pv.id = imoveis.preco_venda_id").
+		Joins("LEFT JOIN preco_aluguels AS map_pa ON map_pa.id = imoveis.preco_aluguel_id").
+		Scopes(func(db *gorm.DB) *gorm.DB { return applyListSort(db, query) }).
 		Offset(offset).
 		Limit(query.Limit).
-		Find(&imoveis).Error; err != nil {
+		Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
-	// Build response
 	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
-	results := make([]ImovelResponse, len(imoveis))
-	for i, imovel := range imoveis {
-		results[i] = r.mapToResponse(&imovel)
-	}
-
-	return &ImovelListResponse{
+	return &ImovelMapListResponse{
 		Total:   total,
 		Page:    query.Page,
 		Limit:   query.Limit,
@@ -321,7 +861,7 @@ func (r *repository) ListByEmpreendimento(ctx context.Context, empreendimentoID
 	var imoveis []Imovel
 	var total int64
 
-	db := r.db.WithContext(ctx).Where("empreendimento_id = ?", empreendimentoID)
+	db := r.getDB(ctx).WithContext(ctx).Where("empreendimento_id = ?", empreendimentoID)
 
 	if err := db.Model(&Imovel{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -337,7 +877,7 @@ func (r *repository) ListByEmpreendimento(ctx context.Context, empreendimentoID
 		Preload("Pacote").
 		Preload("PrecoVenda").
 		Preload("PrecoAluguel").
-		Preload("Anexos").
+		Preload("Anexos", orderedAnexos).
 		Offset(offset).
 		Limit(limit).
 		Order("created_at DESC").
@@ -353,7 +893,7 @@ func (r *repository) ListByCorretorPrincipal(ctx context.Context, corretorPrinci
 	var imoveis []Imovel
 	var total int64
 
-	db := r.db.WithContext(ctx).Where("corretor_principal_id = ?", corretorPrincipalID)
+	db := r.getDB(ctx).WithContext(ctx).Where("corretor_principal_id = ?", corretorPrincipalID)
 
 	if err := db.Model(&Imovel{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -368,7 +908,41 @@ func (r *repository) ListByCorretorPrincipal(ctx context.Context, corretorPrinci
 		Preload("Pacote").
 		Preload("PrecoVenda").
 		Preload("PrecoAluguel").
-		Preload("Anexos").
+		Preload("Anexos", orderedAnexos).
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&imoveis).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return imoveis, total, nil
+}
+
+// ListByOrganizacao retrieves properties whose corretor principal belongs to the organization
+func (r *repository) ListByOrganizacao(ctx context.Context, organizacaoID uint, page, limit int) ([]Imovel, int64, error) {
+	var imoveis []Imovel
+	var total int64
+
+	db := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("corretor_principal_id IN (?)", r.db.Model(&CorretorPrincipal{}).
+			Select("id").
+			Where("organizacao_id = ?", organizacaoID))
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := db.Preload("Endereco").
+		Preload("Empreendimento").
+		Preload("Planta").
+		Preload("CorretorPrincipal").
+		Preload("CorretorPrincipal.Organizacao").
+		Preload("Pacote").
+		Preload("PrecoVenda").
+		Preload("PrecoAluguel").
+		Preload("Anexos", orderedAnexos).
 		Offset(offset).
 		Limit(limit).
 		Order("created_at DESC").
@@ -381,7 +955,7 @@ func (r *repository) ListByCorretorPrincipal(ctx context.Context, corretorPrinci
 
 // CreateBatch creates multiple properties
 func (r *repository) CreateBatch(ctx context.Context, imoveis []Imovel) error {
-	if err := r.db.WithContext(ctx).CreateInBatches(imoveis, 100).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).CreateInBatches(imoveis, 100).Error; err != nil {
 		return err
 	}
 	return nil
@@ -389,7 +963,7 @@ func (r *repository) CreateBatch(ctx context.Context, imoveis []Imovel) error {
 
 // UpdateBatch updates multiple properties
 func (r *repository) UpdateBatch(ctx context.Context, imoveis []Imovel) error {
-	if err := r.db.WithContext(ctx).Save(imoveis).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Save(imoveis).Error; err != nil {
 		return err
 	}
 	return nil
@@ -398,7 +972,7 @@ func (r *repository) UpdateBatch(ctx context.Context, imoveis []Imovel) error {
 // Count returns total number of properties
 func (r *repository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).Count(&count).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -407,7 +981,7 @@ func (r *repository) Count(ctx context.Context) (int64, error) {
 // CountByStatus returns count of properties by status
 func (r *repository) CountByStatus(ctx context.Context, status string) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{}).
 		Where("status = ?", status).
 		Count(&count).Error; err != nil {
@@ -419,7 +993,7 @@ func (r *repository) CountByStatus(ctx context.Context, status string) (int64, e
 // CountByEmpreendimento returns count of properties by enterprise
 func (r *repository) CountByEmpreendimento(ctx context.Context, empreendimentoID uint) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{}).
 		Where("empreendimento_id = ?", empreendimentoID).
 		Count(&count).Error; err != nil {
@@ -428,10 +1002,105 @@ func (r *repository) CountByEmpreendimento(ctx context.Context, empreendimentoID
 	return count, nil
 }
 
+// IncrementViews atomically adds one view; returns false when the property does not exist
+func (r *repository) IncrementViews(ctx context.Context, id uint) (bool, error) {
+	result := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", id).
+		UpdateColumn("visualizacoes", gorm.Expr("visualizacoes + ?", 1))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TopViewed returns the most viewed properties
+func (r *repository) TopViewed(ctx context.Context, limit int) ([]ImovelViewStats, error) {
+	stats := make([]ImovelViewStats, 0, limit)
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select("id AS imovel_id, codigo, titulo, visualizacoes").
+		Order("visualizacoes DESC, id ASC").
+		Limit(limit).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ViewStatsByCorretor aggregates property views per corretor principal
+func (r *repository) ViewStatsByCorretor(ctx context.Context, limit int) ([]CorretorViewStats, error) {
+	stats := make([]CorretorViewStats, 0, limit)
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select(`corretores_principais.id AS corretor_id, corretores_principais.nome,
+			COUNT(imoveis.id) AS total_imoveis,
+			COALESCE(SUM(imoveis.visualizacoes), 0) AS total_visualizacoes,
+			COALESCE(AVG(imoveis.visualizacoes), 0) AS media_visualizacoes`).
+		Joins("INNER JOIN corretores_principais ON corretores_principais.id = imoveis.corretor_principal_id").
+		Group("corretores_principais.id, corretores_principais.nome").
+		Order("total_visualizacoes DESC, corretores_principais.id ASC").
+		Limit(limit).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CorretorStats aggregates counts, views and average active prices of a corretor's properties
+func (r *repository) CorretorStats(ctx context.Context, corretorPrincipalID uint) (*CorretorImovelStats, error) {
+	var totals struct {
+		Total              int64
+		Publicados         int64
+		TotalVisualizacoes int64
+		PrecoMedioVenda    float64
+		PrecoMedioAluguel  float64
+	}
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select(`COUNT(imoveis.id) AS total,
+			COALESCE(SUM(CASE WHEN imoveis.published THEN 1 ELSE 0 END), 0) AS publicados,
+			COALESCE(SUM(imoveis.visualizacoes), 0) AS total_visualizacoes,
+			COALESCE(AVG(stats_pv.preco), 0) AS preco_medio_venda,
+			COALESCE(AVG(stats_pa.preco), 0) AS preco_medio_aluguel`).
+		Joins("LEFT JOIN preco_vendas stats_pv ON stats_pv.id = imoveis.preco_venda_id AND stats_pv.ativo = ? AND stats_pv.deleted_at IS NULL", true).
+		Joins("LEFT JOIN preco_aluguels stats_pa ON stats_pa.id = imoveis.preco_aluguel_id AND stats_pa.ativo = ? AND stats_pa.deleted_at IS NULL", true).
+		Where("imoveis.corretor_principal_id = ?", corretorPrincipalID).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &CorretorImovelStats{
+		Total:              totals.Total,
+		Publicados:         totals.Publicados,
+		ByStatus:           map[string]int64{},
+		TotalVisualizacoes: totals.TotalVisualizacoes,
+		PrecoMedioVenda:    totals.PrecoMedioVenda,
+		PrecoMedioAluguel:  totals.PrecoMedioAluguel,
+	}
+
+	var byStatus []struct {
+		Status string
+		Total  int64
+	}
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Select("status, COUNT(*) AS total").
+		Where("corretor_principal_id = ?", corretorPrincipalID).
+		Group("status").
+		Scan(&byStatus).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range byStatus {
+		status := row.Status
+		if status == "" {
+			status = StatusEmEdicao
+		}
+		stats.ByStatus[status] += row.Total
+	}
+
+	return stats, nil
+}
+
 // ExistsByCodigo checks if a property exists by codigo
 func (r *repository) ExistsByCodigo(ctx context.Context, codigo string) (bool, error) {
 	var exists bool
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{}).
 		Select("count(*) > 0").
 		Where("codigo = ?", codigo).
@@ -441,10 +1110,25 @@ func (r *repository) ExistsByCodigo(ctx context.Context, codigo string) (bool, e
 	return exists, nil
 }
 
+// ExistsBySlug checks if a property other than excludeID uses the slug. Trashed properties
+// keep their slug, so they are included.
+func (r *repository) ExistsBySlug(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	var exists bool
+	if err := r.getDB(ctx).WithContext(ctx).
+		Unscoped().
+		Model(&Imovel{}).
+		Select("count(*) > 0").
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Scan(&exists).Error; err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
 // ExistsByIdIntegracao checks if a property exists by integration ID
 func (r *repository) ExistsByIdIntegracao(ctx context.Context, idIntegracao string) (bool, error) {
 	var exists bool
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{}).
 		Select("count(*) > 0").
 		Where("id_integracao = ?", idIntegracao).
@@ -469,7 +1153,7 @@ func (r *repository) AddAnexo(ctx context.Context, imovelID uint, anexo *Anexo)
 	}
 
 	// Create anexo, omitting zero-value foreign keys to avoid constraint violations
-	db := r.db.WithContext(ctx)
+	db := r.getDB(ctx).WithContext(ctx)
 	if len(omitFields) > 0 {
 		db = db.Omit(omitFields...)
 	}
@@ -482,7 +1166,7 @@ func (r *repository) AddAnexo(ctx context.Context, imovelID uint, anexo *Anexo)
 
 // RemoveAnexo removes an attachment from a property
 func (r *repository) RemoveAnexo(ctx context.Context, imovelID, anexoID uint) error {
-	if err := r.db.WithContext(ctx).Where("id = ? AND imovel_id = ?", anexoID, imovelID).Delete(&Anexo{}).Error; err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Where("id = ? AND imovel_id = ?", anexoID, imovelID).Delete(&Anexo{}).Error; err != nil {
 		return err
 	}
 	return nil
@@ -491,7 +1175,7 @@ func (r *repository) RemoveAnexo(ctx context.Context, imovelID, anexoID uint) er
 // GetAnexos retrieves all attachments for a property
 func (r *repository) GetAnexos(ctx context.Context, imovelID uint) ([]Anexo, error) {
 	var anexos []Anexo
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Where("imovel_id = ?", imovelID).
 		Order("created_at DESC").
 		Find(&anexos).Error; err != nil {
@@ -500,9 +1184,28 @@ func (r *repository) GetAnexos(ctx context.Context, imovelID uint) ([]Anexo, err
 	return anexos, nil
 }
 
+// FindAnexoByID retrieves an attachment by ID
+func (r *repository) FindAnexoByID(ctx context.Context, id uint) (*Anexo, error) {
+	var anexo Anexo
+	if err := r.getDB(ctx).WithContext(ctx).Where("id = ?", id).First(&anexo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &anexo, nil
+}
+
+// UpdateAnexoVariants stores the generated renditions of an attachment
+func (r *repository) UpdateAnexoVariants(ctx context.Context, id uint, variants AnexoVariants) error {
+	return r.getDB(ctx).WithContext(ctx).Model(&Anexo{}).
+		Where("id = ?", id).
+		Update("variants", variants).Error
+}
+
 // UpdateEndereco updates the address of a property
 func (r *repository) UpdateEndereco(ctx context.Context, imovelID, enderecoID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("endereco_id", enderecoID).Error; err != nil {
 		return err
@@ -512,7 +1215,7 @@ func (r *repository) UpdateEndereco(ctx context.Context, imovelID, enderecoID ui
 
 // UpdateEmpreendimento updates the enterprise of a property
 func (r *repository) UpdateEmpreendimento(ctx context.Context, imovelID, empreendimentoID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("empreendimento_id", empreendimentoID).Error; err != nil {
 		return err
@@ -522,7 +1225,7 @@ func (r *repository) UpdateEmpreendimento(ctx context.Context, imovelID, empreen
 
 // UpdatePlanta updates the floor plan of a property
 func (r *repository) UpdatePlanta(ctx context.Context, imovelID, plantaID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("planta_id", plantaID).Error; err != nil {
 		return err
@@ -532,7 +1235,7 @@ func (r *repository) UpdatePlanta(ctx context.Context, imovelID, plantaID uint)
 
 // UpdatePacote updates the package of a property
 func (r *repository) UpdatePacote(ctx context.Context, imovelID, pacoteID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("pacote_id", pacoteID).Error; err != nil {
 		return err
@@ -542,7 +1245,7 @@ func (r *repository) UpdatePacote(ctx context.Context, imovelID, pacoteID uint)
 
 // UpdateCorretorPrincipal updates the real estate agent of a property
 func (r *repository) UpdateCorretorPrincipal(ctx context.Context, imovelID, corretorPrincipalID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("corretor_principal_id", corretorPrincipalID).Error; err != nil {
 		return err
@@ -550,9 +1253,21 @@ func (r *repository) UpdateCorretorPrincipal(ctx context.Context, imovelID, corr
 	return nil
 }
 
+// CorretorExists reports whether a corretor principal that is not deleted has the given ID; under
+// a tenant (see db.TenantScope) only the corretores of its organizacao are found
+func (r *repository) CorretorExists(ctx context.Context, corretorPrincipalID uint) (bool, error) {
+	var count int64
+	if err := r.getDB(ctx).WithContext(ctx).Model(&CorretorPrincipal{}).
+		Where("id = ?", corretorPrincipalID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // UpdatePrecoVenda updates the selling price of a property
 func (r *repository) UpdatePrecoVenda(ctx context.Context, imovelID, precoVendaID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("preco_venda_id", precoVendaID).Error; err != nil {
 		return err
@@ -562,7 +1277,7 @@ func (r *repository) UpdatePrecoVenda(ctx context.Context, imovelID, precoVendaI
 
 // UpdatePrecoAluguel updates the rental price of a property
 func (r *repository) UpdatePrecoAluguel(ctx context.Context, imovelID, precoAluguelID uint) error {
-	if err := r.db.WithContext(ctx).Model(&Imovel{}).
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
 		Where("id = ?", imovelID).
 		Update("preco_aluguel_id", precoAluguelID).Error; err != nil {
 		return err
@@ -570,6 +1285,58 @@ func (r *repository) UpdatePrecoAluguel(ctx context.Context, imovelID, precoAlug
 	return nil
 }
 
+// RecordPriceHistory records the price changes of the given properties, joining the transaction
+// of ctx if any
+func (r *repository) RecordPriceHistory(ctx context.Context, imovelIDs []uint) ([]HistoricoPreco, error) {
+	return RecordPriceHistory(ctx, r.getDB(ctx), imovelIDs)
+}
+
+// ListPriceHistory retrieves the price history of a property, oldest first, optionally of one tipo
+func (r *repository) ListPriceHistory(ctx context.Context, imovelID uint, tipo string) ([]HistoricoPreco, error) {
+	var historico []HistoricoPreco
+	db := r.getDB(ctx).WithContext(ctx).Where("imovel_id = ?", imovelID)
+	if tipo != "" {
+		db = db.Where("tipo = ?", tipo)
+	}
+	if err := db.Order("created_at ASC").Order("id ASC").Find(&historico).Error; err != nil {
+		return nil, err
+	}
+	return historico, nil
+}
+
+// RecordAudit records an audit entry of a property, joining the transaction of ctx if any
+func (r *repository) RecordAudit(ctx context.Context, imovelID uint, entidade, acao string, before, after interface{}) error {
+	return RecordAudit(ctx, r.getDB(ctx), imovelID, entidade, acao, before, after)
+}
+
+// ListAudit retrieves the audit trail of a property, newest first. Entries of permanently
+// deleted properties are kept.
+func (r *repository) ListAudit(ctx context.Context, imovelID uint, page, limit int) ([]ImovelAudit, int64, error) {
+	var entries []ImovelAudit
+	var total int64
+
+	db := r.getDB(ctx).WithContext(ctx).Model(&ImovelAudit{}).Where("imovel_id = ?", imovelID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// UpdateSlug sets the slug of a property, joining the transaction of ctx if any
+func (r *repository) UpdateSlug(ctx context.Context, id uint, slug string) error {
+	if err := r.getDB(ctx).WithContext(ctx).Model(&Imovel{}).
+		Where("id = ?", id).
+		Update("slug", slug).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 // AddCaracteristicas adds characteristics to a property
 func (r *repository) AddCaracteristicas(ctx context.Context, imovelID uint, caracteristicaIDs []uint) error {
 	if len(caracteristicaIDs) == 0 {
@@ -582,7 +1349,7 @@ func (r *repository) AddCaracteristicas(ctx context.Context, imovelID uint, cara
 		caracteristicas[i] = Caracteristica{ID: id}
 	}
 
-	if err := r.db.WithContext(ctx).Model(imovel).Association("Caracteristicas").Append(caracteristicas); err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Model(imovel).Association("Caracteristicas").Append(caracteristicas); err != nil {
 		return err
 	}
 	return nil
@@ -595,7 +1362,7 @@ func (r *repository) RemoveCaracteristicas(ctx context.Context, imovelID uint, c
 	}
 
 	imovel := &Imovel{ID: imovelID}
-	if err := r.db.WithContext(ctx).Model(imovel).Association("Caracteristicas").Delete(caracteristicaIDs); err != nil {
+	if err := r.getDB(ctx).WithContext(ctx).Model(imovel).Association("Caracteristicas").Delete(caracteristicaIDs); err != nil {
 		return err
 	}
 	return nil
@@ -604,7 +1371,7 @@ func (r *repository) RemoveCaracteristicas(ctx context.Context, imovelID uint, c
 // GetCaracteristicas retrieves all characteristics for a property
 func (r *repository) GetCaracteristicas(ctx context.Context, imovelID uint) ([]Caracteristica, error) {
 	var caracteristicas []Caracteristica
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{ID: imovelID}).
 		Association("Caracteristicas").
 		Find(&caracteristicas); err != nil {
@@ -615,7 +1382,7 @@ func (r *repository) GetCaracteristicas(ctx context.Context, imovelID uint) ([]C
 
 // RemoveAllCaracteristicas removes all characteristics from a property
 func (r *repository) RemoveAllCaracteristicas(ctx context.Context, imovelID uint) error {
-	if err := r.db.WithContext(ctx).
+	if err := r.getDB(ctx).WithContext(ctx).
 		Model(&Imovel{ID: imovelID}).
 		Association("Caracteristicas").
 		Clear(); err != nil {
@@ -624,34 +1391,54 @@ func (r *repository) RemoveAllCaracteristicas(ctx context.Context, imovelID uint
 	return nil
 }
 
+// CountCaracteristicas counts how many of the given characteristic IDs exist
+func (r *repository) CountCaracteristicas(ctx context.Context, caracteristicaIDs []uint) (int64, error) {
+	var count int64
+	if len(caracteristicaIDs) == 0 {
+		return 0, nil
+	}
+	err := r.getDB(ctx).WithContext(ctx).
+		Model(&Caracteristica{}).
+		Where("id IN ?", caracteristicaIDs).
+		Count(&count).Error
+	return count, err
+}
+
 // mapToResponse converts Imovel model to response DTO
 func (r *repository) mapToResponse(imovel *Imovel) ImovelResponse {
 	response := ImovelResponse{
-		ID:            imovel.ID,
-		IdIntegracao:  imovel.Id_Integracao,
-		Titulo:        imovel.Titulo,
-		Codigo:        imovel.Codigo,
-		SeqCodigo:     imovel.SeqCodigo,
-		Tipo:          imovel.Tipo,
-		Objetivo:      imovel.Objetivo,
-		Finalidade:    imovel.Finalidade,
-		Descricao:     imovel.Descricao,
-		Metragem:      imovel.Metragem,
-		NumQuartos:    imovel.NumQuartos,
-		NumSuites:     imovel.NumSuites,
-		NumBanheiros:  imovel.NumBanheiros,
-		NumVagas:      imovel.NumVagas,
-		NumAndar:      imovel.NumAndar,
-		Unidade:       imovel.Unidade,
-		Condominio:    imovel.Condominio,
-		IPTU:          imovel.IPTU,
-		InscricaoIPTU: imovel.InscricaoIPTU,
-		Status:        imovel.Status,
-		Published:     imovel.Published,
-		Closed:        imovel.Closed,
-		Visualizacoes: imovel.Visualizacoes,
-		CreatedAt:     imovel.CreatedAt,
-		UpdatedAt:     imovel.UpdatedAt,
+		ID:               imovel.ID,
+		IdIntegracao:     imovel.Id_Integracao,
+		Titulo:           imovel.Titulo,
+		Codigo:           imovel.Codigo,
+		SeqCodigo:        imovel.SeqCodigo,
+		Tipo:             imovel.Tipo,
+		Objetivo:         imovel.Objetivo,
+		Finalidade:       imovel.Finalidade,
+		Descricao:        imovel.Descricao,
+		Metragem:         imovel.Metragem,
+		NumQuartos:       imovel.NumQuartos,
+		NumSuites:        imovel.NumSuites,
+		NumBanheiros:     imovel.NumBanheiros,
+		NumVagas:         imovel.NumVagas,
+		NumAndar:         imovel.NumAndar,
+		Unidade:          imovel.Unidade,
+		Condominio:       imovel.Condominio,
+		IPTU:             imovel.IPTU,
+		InscricaoIPTU:    imovel.InscricaoIPTU,
+		Slug:             imovel.Slug,
+		MetaTitle:        imovel.MetaTitle,
+		MetaDescription:  imovel.MetaDescription,
+		Status:           imovel.Status,
+		Published:        imovel.Published,
+		Closed:           imovel.Closed,
+		PublicarEm:       imovel.PublicarEm,
+		ExpiraEm:         imovel.ExpiraEm,
+		RemovidoOrigemEm: imovel.RemovidoOrigemEm,
+		Version:          imovel.Version,
+		Visualizacoes:    imovel.Visualizacoes,
+		CreatedAt:        imovel.CreatedAt,
+		UpdatedAt:        imovel.UpdatedAt,
 	}
 
 	// Map relationships
@@ -718,6 +1505,8 @@ func (r *repository) mapToResponse(imovel *Imovel) ImovelResponse {
 				Image:         imovel.CorretorPrincipal.Foto.Image,
 				Video:         imovel.CorretorPrincipal.Foto.Video,
 				IsExternalURL: imovel.CorretorPrincipal.Foto.IsExternalURL,
+				ExternalURL:   imovel.CorretorPrincipal.Foto.ExternalURL,
+				Variants:      imovel.CorretorPrincipal.Foto.Variants,
 				CreatedAt:     imovel.CorretorPrincipal.Foto.CreatedAt,
 				UpdatedAt:     imovel.CorretorPrincipal.Foto.UpdatedAt,
 			}
@@ -790,16 +1579,40 @@ func (r *repository) mapToResponse(imovel *Imovel) ImovelResponse {
 				Image:         anexo.Image,
 				Video:         anexo.Video,
 				IsExternalURL: anexo.IsExternalURL,
+				ExternalURL:   anexo.ExternalURL,
+				Variants:      anexo.Variants,
 				CreatedAt:     anexo.CreatedAt,
 				UpdatedAt:     anexo.UpdatedAt,
 			}
 		}
 	}
 
+	// Map caracteristicas
+	for _, c := range imovel.Caracteristicas {
+		response.Caracteristicas = append(response.Caracteristicas, CaracteristicaResponse{
+			ID:            c.ID,
+			Nome:          c.Nome,
+			CategoriaID:   c.CategoriaID,
+			CategoriaNome: c.CategoriaNome,
+			CreatedAt:     c.CreatedAt,
+			UpdatedAt:     c.UpdatedAt,
+		})
+	}
+
 	return response
 }
 
 // CreateEndereco creates a new address
 func (r *repository) CreateEndereco(ctx context.Context, endereco *Endereco) error {
-	return r.db.WithContext(ctx).Create(endereco).Error
+	return r.getDB(ctx).WithContext(ctx).Create(endereco).Error
+}
+
+// CreatePrecoVenda creates a new selling price; id_integracao is left NULL so its unique index only applies to imports
+func (r *repository) CreatePrecoVenda(ctx context.Context, preco *PrecoVenda) error {
+	return r.getDB(ctx).WithContext(ctx).Omit("IdIntegracao").Create(preco).Error
+}
+
+// CreatePrecoAluguel creates a new rental price; id_integracao is left NULL so its unique index only applies to imports
+func (r *repository) CreatePrecoAluguel(ctx context.Context, preco *PrecoAluguel) error {
+	return r.getDB(ctx).WithContext(ctx).Omit("IdIntegracao").Create(preco).Error
 }
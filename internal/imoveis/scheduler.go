@@ -0,0 +1,263 @@
+package imoveis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+)
+
+const (
+	// scheduleBatchSize bounds the properties published or expired by a single scheduler run
+	scheduleBatchSize = 100
+	// defaultScheduleInterval applies when scheduler.interval_seconds is not configured
+	defaultScheduleInterval = time.Minute
+)
+
+// Events of a scheduler run
+const (
+	ScheduleEventPublished = "PUBLICADO"
+	ScheduleEventExpired   = "EXPIRADO"
+	ScheduleEventCanceled  = "CANCELADO"
+)
+
+// ScheduledChange is a property changed by a scheduler run. Motivo explains canceled publications.
+type ScheduledChange struct {
+	Evento string
+	Motivo string
+	Imovel *ImovelResponse
+}
+
+// checkSchedule ensures a property does not expire before it is published
+func checkSchedule(publicarEm, expiraEm *time.Time) error {
+	if publicarEm != nil && expiraEm != nil && !expiraEm.After(*publicarEm) {
+		return fmt.Errorf("%w: expiraEm must be after publicarEm", ErrInvalidImovel)
+	}
+	return nil
+}
+
+// nullableTime returns the value of a patched time, nil when it is cleared
+func nullableTime(n Nullable[time.Time]) *time.Time {
+	if n.Null {
+		return nil
+	}
+	return &n.Value
+}
+
+// RunSchedule publishes the properties whose publicar_em has passed and archives the published ones
+// whose expira_em has passed. Properties missing publication requirements get their publicar_em
+// cleared instead of being retried on every run. Errors on a property do not stop the run; they are
+// joined into the returned error along with the changes that were made.
+func (s *service) RunSchedule(ctx context.Context, now time.Time) ([]ScheduledChange, error) {
+	ctx = withPriceOrigin(ctx, PriceOriginScheduler)
+
+	var changes []ScheduledChange
+	var errs []error
+
+	due, err := s.repo.FindDueForPublication(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve properties due for publication: %w", err)
+	}
+	for i := range due {
+		change, err := s.publishScheduled(ctx, &due[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("property %d: %w", due[i].ID, err))
+			continue
+		}
+		changes = append(changes, *change)
+	}
+
+	expired, err := s.repo.FindDueForExpiration(ctx, now, scheduleBatchSize)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to retrieve properties due for expiration: %w", err))
+		return changes, errors.Join(errs...)
+	}
+	for i := range expired {
+		imovel := &expired[i]
+		if err := s.repo.UpdateStatus(ctx, imovel.ID, StatusArquivado, false); err != nil {
+			errs = append(errs, fmt.Errorf("property %d: failed to expire: %w", imovel.ID, err))
+			continue
+		}
+		response, err := s.auditedTransition(ctx, imovel, AuditAcaoUnpublish)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("property %d: %w", imovel.ID, err))
+			continue
+		}
+		changes = append(changes, ScheduledChange{Evento: ScheduleEventExpired, Imovel: response})
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// publishScheduled publishes a property due for publication, or cancels its schedule when it is
+// not ready to be published
+func (s *service) publishScheduled(ctx context.Context, imovel *Imovel) (*ScheduledChange, error) {
+	if requirementsErr := checkPublishRequirements(imovel); requirementsErr != nil {
+		before := s.mapToResponse(imovel)
+		if err := s.repo.PatchVersion(ctx, imovel.ID, imovel.Version, map[string]interface{}{"publicar_em": nil}); err != nil {
+			return nil, fmt.Errorf("failed to cancel scheduled publication: %w", err)
+		}
+		response, err := s.auditedUpdate(ctx, imovel.ID, AuditEntidadeImovel, before)
+		if err != nil {
+			return nil, err
+		}
+		return &ScheduledChange{Evento: ScheduleEventCanceled, Motivo: requirementsErr.Error(), Imovel: response}, nil
+	}
+
+	if err := s.repo.UpdateStatus(ctx, imovel.ID, StatusPublicado, true); err != nil {
+		return nil, fmt.Errorf("failed to publish: %w", err)
+	}
+	response, err := s.auditedTransition(ctx, imovel, AuditAcaoPublish)
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduledChange{Evento: ScheduleEventPublished, Imovel: response}, nil
+}
+
+// ScheduleNotifier tells the corretor of a property about a change made by the scheduler
+type ScheduleNotifier interface {
+	NotifySchedule(ctx context.Context, change ScheduledChange) error
+}
+
+type emailScheduleNotifier struct {
+	sender email.Service
+}
+
+// NewEmailScheduleNotifier notifies the corretor principal by email, using the notification template.
+// Properties without a corretor or whose corretor has no email are skipped.
+func NewEmailScheduleNotifier(sender email.Service) ScheduleNotifier {
+	return &emailScheduleNotifier{sender: sender}
+}
+
+// scheduleEmails holds the subject format, title, message and alert type of each event
+var scheduleEmails = map[string]struct {
+	Subject string
+	Title   string
+	Message string
+	Type    string
+}{
+	ScheduleEventPublished: {"Imóvel %s publicado", "Publicação realizada", "O imóvel foi publicado conforme o agendamento.", "success"},
+	ScheduleEventExpired:   {"Imóvel %s expirado", "Anúncio expirado", "O imóvel atingiu a data de expiração e foi arquivado.", "warning"},
+	ScheduleEventCanceled:  {"Publicação do imóvel %s cancelada", "Publicação agendada cancelada", "O imóvel não pôde ser publicado na data agendada. Complete os dados e agende novamente.", "error"},
+}
+
+func (n *emailScheduleNotifier) NotifySchedule(ctx context.Context, change ScheduledChange) error {
+	imovel := change.Imovel
+	if imovel == nil || imovel.CorretorPrincipal == nil || imovel.CorretorPrincipal.Email == "" {
+		return nil
+	}
+	content, ok := scheduleEmails[change.Evento]
+	if !ok {
+		return fmt.Errorf("unknown schedule event %s", change.Evento)
+	}
+
+	details := map[string]string{
+		"Código": imovel.Codigo,
+		"Título": imovel.Titulo,
+		"Status": imovel.Status,
+	}
+	data := map[string]interface{}{
+		"Type":      content.Type,
+		"Title":     content.Title,
+		"Message":   content.Message,
+		"Details":   details,
+		"Timestamp": time.Now().Format("02/01/2006 15:04"),
+	}
+	if change.Motivo != "" {
+		data["AlertMessage"] = change.Motivo
+	}
+
+	_, err := n.sender.SendTemplateEmail(ctx, &email.SendTemplateEmailRequest{
+		To:           []string{imovel.CorretorPrincipal.Email},
+		Subject:      fmt.Sprintf(content.Subject, imovel.Codigo),
+		TemplateName: "notification",
+		TemplateData: data,
+	})
+	return err
+}
+
+// PublicationScheduler runs RunSchedule periodically in the background
+type PublicationScheduler interface {
+	// Close stops the scheduler and waits for the current run until ctx is done
+	Close(ctx context.Context) error
+}
+
+type publicationScheduler struct {
+	service  Service
+	notifier ScheduleNotifier
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewPublicationScheduler creates a scheduler and starts it, running once right away and then every
+// interval. notifier may be nil to disable notifications.
+func NewPublicationScheduler(service Service, interval time.Duration, notifier ScheduleNotifier) PublicationScheduler {
+	if interval <= 0 {
+		interval = defaultScheduleInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &publicationScheduler{
+		service:  service,
+		notifier: notifier,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go p.loop(ctx)
+	return p
+}
+
+func (p *publicationScheduler) loop(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run applies the schedule once, bounded by the interval so runs never overlap
+func (p *publicationScheduler) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+
+	changes, err := p.service.RunSchedule(ctx, time.Now().UTC())
+	if err != nil {
+		slog.Error("Publication schedule run failed", "error", err)
+	}
+
+	for _, change := range changes {
+		slog.Info("Scheduled publication change", "imovel_id", change.Imovel.ID, "evento", change.Evento, "motivo", change.Motivo)
+		if p.notifier == nil {
+			continue
+		}
+		if err := p.notifier.NotifySchedule(ctx, change); err != nil {
+			slog.Warn("Failed to notify corretor", "imovel_id", change.Imovel.ID, "evento", change.Evento, "error", err)
+		}
+	}
+}
+
+// Close cancels the pending work and waits for the loop to exit until ctx is done
+func (p *publicationScheduler) Close(ctx context.Context) error {
+	p.once.Do(p.cancel)
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
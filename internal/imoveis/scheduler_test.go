@@ -0,0 +1,149 @@
+package imoveis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// createScheduled creates a property with the given schedule, with an anexo when ready is true
+func createScheduled(t *testing.T, svc Service, database *gorm.DB, codigo string, publicarEm, expiraEm *time.Time, ready bool) *ImovelResponse {
+	t.Helper()
+
+	req := nestedCreateRequest(codigo)
+	req.PublicarEm = publicarEm
+	req.ExpiraEm = expiraEm
+	created, err := svc.CreateImovel(context.Background(), req)
+	require.NoError(t, err)
+	if ready {
+		require.NoError(t, database.Create(&Anexo{ImovelID: &created.ID, URL: "https://cdn.example.com/" + codigo + ".jpg", Image: true}).Error)
+	}
+	return created
+}
+
+func TestScheduleValidation(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	req := nestedCreateRequest("AP-001")
+	req.PublicarEm = timePtr(now.Add(time.Hour))
+	req.ExpiraEm = timePtr(now)
+	_, err := svc.CreateImovel(ctx, req)
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+
+	created := createScheduled(t, svc, database, "AP-002", timePtr(now.Add(time.Hour)), nil, false)
+
+	// Checked against the stored publicarEm
+	_, err = svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{ExpiraEm: Nullable[time.Time]{Set: true, Value: now}})
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+
+	patched, err := svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{
+		PublicarEm: Nullable[time.Time]{Set: true, Null: true},
+		ExpiraEm:   Nullable[time.Time]{Set: true, Value: now},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, patched.PublicarEm)
+	require.NotNil(t, patched.ExpiraEm)
+}
+
+func TestRunSchedule(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	due := createScheduled(t, svc, database, "AP-001", timePtr(now.Add(-time.Minute)), timePtr(now.Add(time.Hour)), true)
+	notReady := createScheduled(t, svc, database, "AP-002", timePtr(now.Add(-time.Minute)), nil, false)
+	future := createScheduled(t, svc, database, "AP-003", timePtr(now.Add(time.Hour)), nil, true)
+
+	changes, err := svc.RunSchedule(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	events := map[uint]ScheduledChange{}
+	for _, change := range changes {
+		events[change.Imovel.ID] = change
+	}
+	assert.Equal(t, ScheduleEventPublished, events[due.ID].Evento)
+	assert.Equal(t, StatusPublicado, events[due.ID].Imovel.Status)
+	assert.True(t, events[due.ID].Imovel.Published)
+
+	// Not ready: the schedule is canceled so it is not retried on every run
+	assert.Equal(t, ScheduleEventCanceled, events[notReady.ID].Evento)
+	assert.Contains(t, events[notReady.ID].Motivo, "anexos")
+	assert.Nil(t, events[notReady.ID].Imovel.PublicarEm)
+	assert.Equal(t, StatusEmEdicao, events[notReady.ID].Imovel.Status)
+
+	unchanged, err := svc.GetImovel(ctx, future.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusEmEdicao, unchanged.Status)
+
+	var audit ImovelAudit
+	require.NoError(t, database.Where("imovel_id = ? AND acao = ?", due.ID, AuditAcaoPublish).First(&audit).Error)
+	assert.Equal(t, PriceOriginScheduler, audit.Origem)
+
+	// Nothing left to do until the expiration
+	changes, err = svc.RunSchedule(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = svc.RunSchedule(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	expired := map[uint]string{}
+	for _, change := range changes {
+		expired[change.Imovel.ID] = change.Evento
+	}
+	assert.Equal(t, ScheduleEventExpired, expired[due.ID])
+	assert.Equal(t, ScheduleEventPublished, expired[future.ID])
+
+	archived, err := svc.GetImovel(ctx, due.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusArquivado, archived.Status)
+	assert.False(t, archived.Published)
+}
+
+type recordingNotifier struct {
+	mu      sync.Mutex
+	changes []ScheduledChange
+}
+
+func (n *recordingNotifier) NotifySchedule(ctx context.Context, change ScheduledChange) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.changes = append(n.changes, change)
+	return nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.changes)
+}
+
+func TestPublicationScheduler(t *testing.T) {
+	svc, database := setupCreateService(t)
+	created := createScheduled(t, svc, database, "AP-001", timePtr(time.Now().UTC().Add(-time.Minute)), nil, true)
+
+	notifier := &recordingNotifier{}
+	scheduler := NewPublicationScheduler(svc, time.Hour, notifier)
+
+	// The first run happens right away
+	require.Eventually(t, func() bool { return notifier.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, created.ID, notifier.changes[0].Imovel.ID)
+	assert.Equal(t, ScheduleEventPublished, notifier.changes[0].Evento)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, scheduler.Close(ctx))
+	require.NoError(t, scheduler.Close(ctx))
+}
@@ -0,0 +1,73 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+// CheckCorretorScope returns ErrForbidden when ctx is limited to the imoveis of a corretor
+// principal (corretor users, see contextutil.WithCorretorScope) and corretorPrincipalID, the
+// corretor of the property being changed, is another one. Unscoped requests pass.
+func CheckCorretorScope(ctx context.Context, corretorPrincipalID uint) error {
+	corretorID, scoped := contextutil.CorretorScopeFromContext(ctx)
+	if scoped && (corretorID == 0 || corretorPrincipalID != corretorID) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// scopedCorretor returns the corretor principal of a new property: the one requested, or for
+// scoped requests that did not name one, the user's own
+func scopedCorretor(ctx context.Context, requested uint) (uint, error) {
+	corretorID, scoped := contextutil.CorretorScopeFromContext(ctx)
+	if scoped && requested == 0 {
+		requested = corretorID
+	}
+	return requested, CheckCorretorScope(ctx, requested)
+}
+
+// checkTenantCorretor keeps the properties of a request scoped to an organizacao (see
+// db.WithOrganizacao) within it: they need a corretor principal, and it must be one of the
+// organizacao's, or the property would leave the tenant. Unscoped requests pass.
+func (s *service) checkTenantCorretor(ctx context.Context, corretorPrincipalID uint) error {
+	if _, scoped := db.OrganizacaoFromContext(ctx); !scoped {
+		return nil
+	}
+	if corretorPrincipalID == 0 {
+		return fmt.Errorf("%w: properties of an organizacao need a corretor principal", ErrInvalidImovel)
+	}
+	exists, err := s.repo.CorretorExists(ctx, corretorPrincipalID)
+	if err != nil {
+		return fmt.Errorf("failed to find corretor principal: %w", err)
+	}
+	if !exists {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// PropertyOwner is the corretor principal of a property and the organizacao of that corretor
+type PropertyOwner struct {
+	CorretorPrincipalID uint
+	OrganizacaoID       uint
+}
+
+// CheckOwnersScope returns ErrForbidden when a record shared by properties (an endereco, an
+// empreendimento) is used by one ctx may not change: one of another corretor (see
+// CheckCorretorScope), or outside the organizacao the request is scoped to (see
+// db.WithOrganizacao). Records no property uses pass.
+func CheckOwnersScope(ctx context.Context, owners []PropertyOwner) error {
+	organizacaoID, tenantScoped := db.OrganizacaoFromContext(ctx)
+	for _, owner := range owners {
+		if err := CheckCorretorScope(ctx, owner.CorretorPrincipalID); err != nil {
+			return err
+		}
+		if tenantScoped && owner.OrganizacaoID != organizacaoID {
+			return ErrForbidden
+		}
+	}
+	return nil
+}
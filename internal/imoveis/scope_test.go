@@ -0,0 +1,100 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func TestCheckCorretorScope(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, CheckCorretorScope(ctx, 5), "unscoped requests change every property")
+	assert.NoError(t, CheckCorretorScope(contextutil.WithCorretorScope(ctx, 5), 5))
+	assert.ErrorIs(t, CheckCorretorScope(contextutil.WithCorretorScope(ctx, 5), 6), ErrForbidden)
+	assert.ErrorIs(t, CheckCorretorScope(contextutil.WithCorretorScope(ctx, 5), 0), ErrForbidden)
+	assert.ErrorIs(t, CheckCorretorScope(contextutil.WithCorretorScope(ctx, 0), 0), ErrForbidden, "an unlinked corretor changes nothing")
+}
+
+func TestService_CorretorScope(t *testing.T) {
+	svc, database := setupCreateService(t)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, nome) VALUES (5, 'Ana'), (6, 'Bruno')`).Error)
+	ana := contextutil.WithCorretorScope(context.Background(), 5)
+	bruno := contextutil.WithCorretorScope(context.Background(), 6)
+
+	request := nestedCreateRequest("AP-001")
+	created, err := svc.CreateImovel(ana, request)
+	require.NoError(t, err)
+	require.NotNil(t, created.CorretorPrincipal)
+	assert.Equal(t, uint(5), created.CorretorPrincipal.ID, "new properties go to the user's corretor")
+
+	request = nestedCreateRequest("AP-002")
+	request.CorretorPrincipalID = 6
+	_, err = svc.CreateImovel(ana, request)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.UpdateImovel(bruno, created.ID, &UpdateImovelRequest{Titulo: "Apartamento reformado"})
+	assert.ErrorIs(t, err, ErrForbidden)
+	other := uint(6)
+	_, err = svc.UpdateImovel(ana, created.ID, &UpdateImovelRequest{CorretorPrincipalID: &other})
+	assert.ErrorIs(t, err, ErrForbidden, "a corretor cannot hand the property over")
+	_, err = svc.PatchImovel(ana, created.ID, &PatchImovelRequest{CorretorPrincipalID: Nullable[uint]{Set: true, Null: true}})
+	assert.ErrorIs(t, err, ErrForbidden)
+	updated, err := svc.UpdateImovel(ana, created.ID, &UpdateImovelRequest{Titulo: "Apartamento reformado"})
+	require.NoError(t, err)
+	assert.Equal(t, "Apartamento reformado", updated.Titulo)
+
+	assert.ErrorIs(t, svc.DeleteImovel(bruno, created.ID), ErrForbidden)
+	result, err := svc.BulkDelete(bruno, &BulkDeleteRequest{IDs: []uint{created.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, ErrForbidden.Error(), result.Results[0].Error)
+
+	require.NoError(t, svc.DeleteImovel(ana, created.ID))
+	_, err = svc.RestoreImovel(bruno, created.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = svc.RestoreImovel(ana, created.ID)
+	assert.NoError(t, err)
+
+	// Admins and gestores are not scoped
+	_, err = svc.UpdateImovel(context.Background(), created.ID, &UpdateImovelRequest{CorretorPrincipalID: &other})
+	assert.NoError(t, err)
+}
+
+func TestService_TenantScope(t *testing.T) {
+	svc, database := setupCreateService(t)
+	require.NoError(t, database.Use(db.NewTenantScope(db.TenantTables)))
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, nome, organizacao_id) VALUES
+		(5, 'Ana', 1), (6, 'Bruno', 2)`).Error)
+	orgOne := db.WithOrganizacao(context.Background(), 1)
+	orgTwo := db.WithOrganizacao(context.Background(), 2)
+
+	request := nestedCreateRequest("AP-001")
+	request.CorretorPrincipalID = 5
+	created, err := svc.CreateImovel(orgOne, request)
+	require.NoError(t, err)
+
+	request = nestedCreateRequest("AP-002")
+	request.CorretorPrincipalID = 6
+	_, err = svc.CreateImovel(orgOne, request)
+	assert.ErrorIs(t, err, ErrForbidden, "corretores of another organizacao are not found")
+	_, err = svc.CreateImovel(orgOne, nestedCreateRequest("AP-003"))
+	assert.ErrorIs(t, err, ErrInvalidImovel, "the property would leave the organizacao")
+
+	_, err = svc.GetImovel(orgTwo, created.ID)
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+	other := uint(6)
+	_, err = svc.UpdateImovel(orgOne, created.ID, &UpdateImovelRequest{CorretorPrincipalID: &other})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	found, err := svc.GetImovel(orgOne, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "AP-001", found.Codigo)
+	_, err = svc.GetImovel(context.Background(), created.ID)
+	assert.NoError(t, err, "unscoped requests see every organizacao")
+}
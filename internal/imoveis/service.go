@@ -4,6 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+var (
+	// ErrImovelNotFound is returned when a property does not exist
+	ErrImovelNotFound = errors.New("property not found")
+	// ErrCodigoExists is returned when another property already uses the codigo
+	ErrCodigoExists = errors.New("property codigo already exists")
+	// ErrInvalidImovel is returned when a change would break a property business rule
+	ErrInvalidImovel = errors.New("invalid property")
+	// ErrInvalidTransition is returned when a status change is not allowed by the publication workflow
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrVersionConflict is returned when a property was changed since the version the client read
+	ErrVersionConflict = errors.New("property was modified by another request")
+	// ErrForbidden is returned when a corretor user changes a property of another corretor
+	ErrForbidden = errors.New("property belongs to another corretor")
 )
 
 // Service defines the interface for property business logic
@@ -12,24 +33,49 @@ type Service interface {
 	CreateImovel(ctx context.Context, req *CreateImovelRequest) (*ImovelResponse, error)
 	GetImovel(ctx context.Context, id uint) (*ImovelResponse, error)
 	GetImovelByCodigo(ctx context.Context, codigo string) (*ImovelResponse, error)
+	GetImovelBySlug(ctx context.Context, slug string) (*ImovelResponse, error)
 	GetImovelByIdIntegracao(ctx context.Context, idIntegracao string) (*ImovelResponse, error)
 	UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRequest) (*ImovelResponse, error)
+	PatchImovel(ctx context.Context, id uint, req *PatchImovelRequest) (*ImovelResponse, error)
+	PublishImovel(ctx context.Context, id uint) (*ImovelResponse, error)
+	UnpublishImovel(ctx context.Context, id uint) (*ImovelResponse, error)
 	DeleteImovel(ctx context.Context, id uint) error
 	HardDeleteImovel(ctx context.Context, id uint) error
+	RestoreImovel(ctx context.Context, id uint) (*ImovelResponse, error)
+	ListTrash(ctx context.Context, query *TrashListQuery) (*TrashListResponse, error)
 
 	// List & Filter
 	ListImoveis(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error)
+	ListImoveisMap(ctx context.Context, query *ImovelListQuery) (*ImovelMapListResponse, error)
 	ListImovelsByEmpreendimento(ctx context.Context, empreendimentoID uint, page, limit int) ([]ImovelResponse, int64, error)
 	ListImovelsByOrganizacao(ctx context.Context, organizacaoID uint, page, limit int) ([]ImovelResponse, int64, error)
+	ListImovelsByCorretor(ctx context.Context, corretorPrincipalID uint, page, limit int) (*CorretorImoveisResponse, error)
 
 	// Bulk Operations
 	CreateImovelBatch(ctx context.Context, reqs []CreateImovelRequest) error
 	UpdateImovelBatch(ctx context.Context, imoveis []Imovel) error
+	BulkUpdateStatus(ctx context.Context, req *BulkStatusRequest) (*BulkResultResponse, error)
+	BulkDelete(ctx context.Context, req *BulkDeleteRequest) (*BulkResultResponse, error)
 
 	// Statistics
 	CountImoveis(ctx context.Context) (int64, error)
 	CountImovelsByStatus(ctx context.Context, status string) (int64, error)
 	CountImovelsByEmpreendimento(ctx context.Context, empreendimentoID uint) (int64, error)
+	GetStats(ctx context.Context) (*ImovelStatsResponse, error)
+	GetFacets(ctx context.Context, query *ImovelListQuery) (*ImovelFacetsResponse, error)
+	CompareImoveis(ctx context.Context, ids []uint) (*ImovelComparisonResponse, error)
+	ListDestaques(ctx context.Context, query *DestaquesQuery) ([]ImovelResponse, error)
+	SimulateFinancing(ctx context.Context, id uint, query *SimulacaoQuery) (*SimulacaoResponse, error)
+	RunSchedule(ctx context.Context, now time.Time) ([]ScheduledChange, error)
+	ArchiveStale(ctx context.Context, now time.Time) (*ArchiveStaleResponse, error)
+	MarkRemovedFromSource(ctx context.Context, id uint, policy string) error
+	GetPriceHistory(ctx context.Context, imovelID uint, query *PriceHistoryQuery) ([]HistoricoPrecoResponse, error)
+	GetAuditLog(ctx context.Context, imovelID uint, query *AuditListQuery) (*AuditListResponse, error)
+
+	// Views
+	RegisterView(ctx context.Context, id uint, viewer string) (*ViewRegisteredResponse, error)
+	TopViewedImoveis(ctx context.Context, limit int) ([]ImovelViewStats, error)
+	ViewStatsByCorretor(ctx context.Context, limit int) ([]CorretorViewStats, error)
 
 	// Existence checks
 	ImovelExistsByCodigo(ctx context.Context, codigo string) (bool, error)
@@ -60,23 +106,75 @@ type Service interface {
 }
 
 type service struct {
-	repo Repository
+	repo               Repository
+	views              *viewDeduper
+	destaques          *destaquesCache
+	anexoProcessor     AnexoProcessor
+	archiveDefaultDays int
+	archiveNotifier    ArchiveNotifier
+	events             events.Publisher
+}
+
+// ServiceOption configures optional service behaviour
+type ServiceOption func(*service)
+
+// WithAnexoProcessor generates renditions for the image anexos added through the service
+func WithAnexoProcessor(processor AnexoProcessor) ServiceOption {
+	return func(s *service) {
+		s.anexoProcessor = processor
+	}
+}
+
+// WithEvents publishes the publications and price changes made through the service to publisher
+func WithEvents(publisher events.Publisher) ServiceOption {
+	return func(s *service) {
+		s.events = publisher
+	}
 }
 
 // NewService creates a new property service
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{
+		repo:      repo,
+		views:     newViewDeduper(viewDedupSize, viewDedupWindow),
+		destaques: newDestaquesCache(destaquesCacheSize, destaquesCacheTTL),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// CreateImovel creates a new property
+// CreateImovel creates a new property. Nested endereco, prices and caracteristicas are created
+// and linked in the same transaction, so a failure leaves nothing behind.
 func (s *service) CreateImovel(ctx context.Context, req *CreateImovelRequest) (*ImovelResponse, error) {
 	// Validate business rules
-	if req.Objetivo == "ALUGAR" && req.PrecoAluguelID == 0 {
-		return nil, fmt.Errorf("rental properties must have a rental price")
+	if req.Endereco != nil && req.EnderecoID != 0 {
+		return nil, fmt.Errorf("%w: send either endereco or endereco_id", ErrInvalidImovel)
+	}
+	if req.PrecoVenda != nil && req.PrecoVendaID != 0 {
+		return nil, fmt.Errorf("%w: send either precoVenda or preco_venda_id", ErrInvalidImovel)
+	}
+	if req.PrecoAluguel != nil && req.PrecoAluguelID != 0 {
+		return nil, fmt.Errorf("%w: send either precoAluguel or preco_aluguel_id", ErrInvalidImovel)
 	}
-	if req.Objetivo == "VENDER" && req.PrecoVendaID == 0 {
-		return nil, fmt.Errorf("properties for sale must have a selling price")
+	if req.Objetivo == "ALUGAR" && req.PrecoAluguelID == 0 && req.PrecoAluguel == nil {
+		return nil, fmt.Errorf("%w: rental properties must have a rental price", ErrInvalidImovel)
 	}
+	if req.Objetivo == "VENDER" && req.PrecoVendaID == 0 && req.PrecoVenda == nil {
+		return nil, fmt.Errorf("%w: properties for sale must have a selling price", ErrInvalidImovel)
+	}
+	if err := checkSchedule(req.PublicarEm, req.ExpiraEm); err != nil {
+		return nil, err
+	}
+	corretorPrincipalID, err := scopedCorretor(ctx, req.CorretorPrincipalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTenantCorretor(ctx, corretorPrincipalID); err != nil {
+		return nil, err
+	}
+	req.CorretorPrincipalID = corretorPrincipalID
 
 	// Check if codigo already exists
 	exists, err := s.repo.ExistsByCodigo(ctx, req.Codigo)
@@ -84,7 +182,7 @@ func (s *service) CreateImovel(ctx context.Context, req *CreateImovelRequest) (*
 		return nil, fmt.Errorf("failed to check codigo uniqueness: %w", err)
 	}
 	if exists {
-		return nil, fmt.Errorf("property with codigo '%s' already exists", req.Codigo)
+		return nil, fmt.Errorf("%w: '%s'", ErrCodigoExists, req.Codigo)
 	}
 
 	// Check if idIntegracao is unique (if provided)
@@ -98,6 +196,17 @@ func (s *service) CreateImovel(ctx context.Context, req *CreateImovelRequest) (*
 		}
 	}
 
+	caracteristicaIDs := uniqueIDs(req.Caracteristicas)
+	if len(caracteristicaIDs) > 0 {
+		count, err := s.repo.CountCaracteristicas(ctx, caracteristicaIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check characteristics: %w", err)
+		}
+		if count != int64(len(caracteristicaIDs)) {
+			return nil, fmt.Errorf("%w: unknown caracteristica id", ErrInvalidImovel)
+		}
+	}
+
 	// Create model from request
 	imovel := &Imovel{
 		Id_Integracao:       req.IdIntegracao,
@@ -118,53 +227,116 @@ func (s *service) CreateImovel(ctx context.Context, req *CreateImovelRequest) (*
 		IPTU:                req.IPTU,
 		InscricaoIPTU:       req.InscricaoIPTU,
 		EnderecoID:          req.EnderecoID,
+		EmpreendimentoID:    req.EmpreendimentoID,
+		PrecoVendaID:        req.PrecoVendaID,
+		PrecoAluguelID:      req.PrecoAluguelID,
 		PlantaID:            req.PlantaID,
 		CorretorPrincipalID: req.CorretorPrincipalID,
 		PacoteID:            req.PacoteID,
-		Status:              "EM_EDICAO", // Default status
+		Status:              StatusEmEdicao, // Default status
 		Published:           false,
 		Closed:              false,
+		PublicarEm:          req.PublicarEm,
+		ExpiraEm:            req.ExpiraEm,
 	}
 
-	// Only set optional foreign keys if they're provided (non-zero)
-	if req.EmpreendimentoID != 0 {
-		imovel.EmpreendimentoID = req.EmpreendimentoID
-	}
-	if req.PrecoVendaID != 0 {
-		imovel.PrecoVendaID = req.PrecoVendaID
-	}
-	if req.PrecoAluguelID != 0 {
-		imovel.PrecoAluguelID = req.PrecoAluguelID
-	}
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if req.Endereco != nil {
+			endereco := newNestedEndereco(req.Endereco)
+			if err := s.repo.CreateEndereco(txCtx, endereco); err != nil {
+				return fmt.Errorf("failed to create address: %w", err)
+			}
+			imovel.EnderecoID = endereco.ID
+		}
+		if req.PrecoVenda != nil {
+			preco := newNestedPrecoVenda(req.PrecoVenda)
+			if err := s.repo.CreatePrecoVenda(txCtx, preco); err != nil {
+				return fmt.Errorf("failed to create selling price: %w", err)
+			}
+			imovel.PrecoVendaID = preco.ID
+		}
+		if req.PrecoAluguel != nil {
+			preco := newNestedPrecoAluguel(req.PrecoAluguel)
+			if err := s.repo.CreatePrecoAluguel(txCtx, preco); err != nil {
+				return fmt.Errorf("failed to create rental price: %w", err)
+			}
+			imovel.PrecoAluguelID = preco.ID
+		}
 
-	// Build list of fields to omit based on zero values
-	omitFields := []string{}
-	if req.EmpreendimentoID == 0 {
-		omitFields = append(omitFields, "EmpreendimentoID")
-	}
-	if req.PrecoVendaID == 0 {
-		omitFields = append(omitFields, "PrecoVendaID")
-	}
-	if req.PrecoAluguelID == 0 {
-		omitFields = append(omitFields, "PrecoAluguelID")
+		// Only set optional foreign keys if they're provided (non-zero)
+		omitFields := []string{"Endereco", "Empreendimento", "Planta", "CorretorPrincipal", "Pacote", "PrecoVenda", "PrecoAluguel", "Anexos", "Caracteristicas"}
+		for field, id := range map[string]uint{
+			"EmpreendimentoID":    imovel.EmpreendimentoID,
+			"PrecoVendaID":        imovel.PrecoVendaID,
+			"PrecoAluguelID":      imovel.PrecoAluguelID,
+			"PlantaID":            imovel.PlantaID,
+			"CorretorPrincipalID": imovel.CorretorPrincipalID,
+			"PacoteID":            imovel.PacoteID,
+		} {
+			if id == 0 {
+				omitFields = append(omitFields, field)
+			}
+		}
+
+		// Save to repository with omitted fields
+		if err := s.repo.(*repository).getDB(txCtx).WithContext(txCtx).Omit(omitFields...).Create(imovel).Error; err != nil {
+			return fmt.Errorf("failed to create property: %w", err)
+		}
+
+		if err := s.repo.AddCaracteristicas(txCtx, imovel.ID, caracteristicaIDs); err != nil {
+			return fmt.Errorf("failed to add characteristics: %w", err)
+		}
+		if err := s.assignSlug(txCtx, imovel.ID); err != nil {
+			return err
+		}
+		return s.recordPriceHistory(txCtx, []uint{imovel.ID})
+	})
+	if err != nil {
+		return nil, err
 	}
-	if req.PlantaID == 0 {
-		omitFields = append(omitFields, "PlantaID")
+
+	// Retrieve and return
+	created, err := s.GetImovel(ctx, imovel.ID)
+	if err != nil {
+		return nil, err
 	}
-	if req.CorretorPrincipalID == 0 {
-		omitFields = append(omitFields, "CorretorPrincipalID")
+	if err := s.repo.RecordAudit(ctx, imovel.ID, AuditEntidadeImovel, AuditAcaoCreate, nil, created); err != nil {
+		return nil, err
 	}
-	if req.PacoteID == 0 {
-		omitFields = append(omitFields, "PacoteID")
+	return created, nil
+}
+
+func newNestedEndereco(req *NestedEnderecoRequest) *Endereco {
+	return &Endereco{
+		Rua:       strings.TrimSpace(req.Rua),
+		Numero:    req.Numero,
+		Bairro:    strings.TrimSpace(req.Bairro),
+		Cidade:    strings.TrimSpace(req.Cidade),
+		Estado:    strings.ToUpper(req.Estado),
+		CEP:       strings.TrimSpace(req.CEP),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
 	}
+}
 
-	// Save to repository with omitted fields
-	if err := s.repo.(*repository).db.Omit(omitFields...).Create(imovel).Error; err != nil {
-		return nil, fmt.Errorf("failed to create property: %w", err)
+func newNestedPrecoVenda(req *NestedPrecoVendaRequest) *PrecoVenda {
+	return &PrecoVenda{
+		Preco:                       req.Preco,
+		AceitaFinanciamentoBancario: req.AceitaFinanciamentoBancario,
+		AceitaFinanciamentoDireto:   req.AceitaFinanciamentoDireto,
+		AceitaPermuta:               req.AceitaPermuta,
+		AceitaCartaDeCredito:        req.AceitaCartaDeCredito,
+		AceitaFGTS:                  req.AceitaFGTS,
+		Ativo:                       req.Ativo == nil || *req.Ativo,
 	}
+}
 
-	// Retrieve and return
-	return s.GetImovel(ctx, imovel.ID)
+func newNestedPrecoAluguel(req *NestedPrecoAluguelRequest) *PrecoAluguel {
+	return &PrecoAluguel{
+		Preco:        req.Preco,
+		AceitaFiador: req.AceitaFiador,
+		Ativo:        req.Ativo == nil || *req.Ativo,
+	}
 }
 
 // GetImovel retrieves a property by ID
@@ -179,7 +351,7 @@ func (s *service) GetImovel(ctx context.Context, id uint) (*ImovelResponse, erro
 	}
 
 	if imovel == nil {
-		return nil, fmt.Errorf("property not found")
+		return nil, ErrImovelNotFound
 	}
 
 	return s.mapToResponse(imovel), nil
@@ -203,6 +375,156 @@ func (s *service) GetImovelByCodigo(ctx context.Context, codigo string) (*Imovel
 	return s.mapToResponse(imovel), nil
 }
 
+// GetPriceHistory returns the price changes of a property, oldest first
+func (s *service) GetPriceHistory(ctx context.Context, imovelID uint, query *PriceHistoryQuery) ([]HistoricoPrecoResponse, error) {
+	imovel, err := s.repo.FindWithEndereco(ctx, imovelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+
+	historico, err := s.repo.ListPriceHistory(ctx, imovelID, query.Tipo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price history: %w", err)
+	}
+
+	responses := make([]HistoricoPrecoResponse, len(historico))
+	for i, entry := range historico {
+		responses[i] = HistoricoPrecoResponse{
+			ID:            entry.ID,
+			Tipo:          entry.Tipo,
+			Preco:         entry.Preco,
+			PrecoAnterior: entry.PrecoAnterior,
+			Origem:        entry.Origem,
+			CreatedAt:     entry.CreatedAt,
+		}
+		if entry.Preco != nil && entry.PrecoAnterior != nil && *entry.PrecoAnterior != 0 {
+			variacao := math.Round((*entry.Preco-*entry.PrecoAnterior) / *entry.PrecoAnterior * 10000) / 100
+			responses[i].Variacao = &variacao
+		}
+	}
+	return responses, nil
+}
+
+// GetAuditLog returns the audit trail of a property, newest first. It stays available after the
+// property is deleted.
+func (s *service) GetAuditLog(ctx context.Context, imovelID uint, query *AuditListQuery) (*AuditListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	entries, total, err := s.repo.ListAudit(ctx, imovelID, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve audit log: %w", err)
+	}
+	if total == 0 {
+		// Deleted properties always have entries, so an empty trail needs the property to exist
+		imovel, err := s.repo.FindWithEndereco(ctx, imovelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify property: %w", err)
+		}
+		if imovel == nil {
+			return nil, ErrImovelNotFound
+		}
+	}
+
+	results := make([]ImovelAuditResponse, len(entries))
+	for i, entry := range entries {
+		results[i] = ImovelAuditResponse{
+			ID:         entry.ID,
+			UserID:     entry.UserID,
+			Entidade:   entry.Entidade,
+			Acao:       entry.Acao,
+			Origem:     entry.Origem,
+			Motivo:     entry.Motivo,
+			Alteracoes: entry.Alteracoes,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &AuditListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// auditedUpdate reloads a property and records the fields changed since before
+func (s *service) auditedUpdate(ctx context.Context, id uint, entidade string, before *ImovelResponse) (*ImovelResponse, error) {
+	updated, err := s.GetImovel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordAudit(ctx, id, entidade, AuditAcaoUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// auditedTransition reloads a property after a workflow transition and records it
+func (s *service) auditedTransition(ctx context.Context, imovel *Imovel, acao string) (*ImovelResponse, error) {
+	before := s.mapToResponse(imovel)
+	updated, err := s.GetImovel(ctx, imovel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordAudit(ctx, imovel.ID, AuditEntidadeImovel, acao, before, updated); err != nil {
+		return nil, err
+	}
+	if acao == AuditAcaoPublish {
+		s.publish(ctx, events.ImovelPublished{
+			ImovelID: updated.ID,
+			Codigo:   updated.Codigo,
+			Titulo:   updated.Titulo,
+			Origem:   priceOrigin(ctx),
+		})
+	}
+	return updated, nil
+}
+
+// recordPriceHistory records the price changes of the given properties and publishes them
+func (s *service) recordPriceHistory(ctx context.Context, imovelIDs []uint) error {
+	entries, err := s.repo.RecordPriceHistory(ctx, imovelIDs)
+	if err != nil {
+		return err
+	}
+	for _, change := range PriceChangedEvents(entries) {
+		s.publish(ctx, change)
+	}
+	return nil
+}
+
+// publish hands event to the event bus once the transaction of ctx commits
+func (s *service) publish(ctx context.Context, event events.Event) {
+	if s.events == nil {
+		return
+	}
+	afterCommit(ctx, func() { s.events.Publish(event) })
+}
+
+// GetImovelBySlug retrieves a property by its public URL slug
+func (s *service) GetImovelBySlug(ctx context.Context, slug string) (*ImovelResponse, error) {
+	imovel, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+
+	return s.mapToResponse(imovel), nil
+}
+
 // GetImovelByIdIntegracao retrieves a property by integration ID
 func (s *service) GetImovelByIdIntegracao(ctx context.Context, idIntegracao string) (*ImovelResponse, error) {
 	if idIntegracao == "" {
@@ -215,7 +537,7 @@ func (s *service) GetImovelByIdIntegracao(ctx context.Context, idIntegracao stri
 	}
 
 	if imovel == nil {
-		return nil, fmt.Errorf("property with idIntegracao '%s' not found", idIntegracao)
+		return nil, ErrImovelNotFound
 	}
 
 	return s.mapToResponse(imovel), nil
@@ -234,8 +556,15 @@ func (s *service) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRe
 	}
 
 	if imovel == nil {
-		return nil, fmt.Errorf("property not found")
+		return nil, ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return nil, err
 	}
+	if req.Version != nil && *req.Version != imovel.Version {
+		return nil, ErrVersionConflict
+	}
+	before := s.mapToResponse(imovel)
 
 	// Check for codigo uniqueness if changing it
 	if req.Codigo != "" && req.Codigo != imovel.Codigo {
@@ -244,7 +573,7 @@ func (s *service) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRe
 			return nil, fmt.Errorf("failed to check codigo uniqueness: %w", err)
 		}
 		if exists {
-			return nil, fmt.Errorf("property with codigo '%s' already exists", req.Codigo)
+			return nil, fmt.Errorf("%w: '%s'", ErrCodigoExists, req.Codigo)
 		}
 		imovel.Codigo = req.Codigo
 	}
@@ -296,6 +625,15 @@ func (s *service) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRe
 		imovel.InscricaoIPTU = req.InscricaoIPTU
 	}
 
+	// Written as columns so an empty string clears them, which Updates would skip
+	seo := map[string]interface{}{}
+	if req.MetaTitle != nil {
+		seo["meta_title"] = strings.TrimSpace(*req.MetaTitle)
+	}
+	if req.MetaDescription != nil {
+		seo["meta_description"] = strings.TrimSpace(*req.MetaDescription)
+	}
+
 	// Update relationships if provided
 	if req.EnderecoID != nil {
 		imovel.EnderecoID = *req.EnderecoID
@@ -307,6 +645,13 @@ func (s *service) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRe
 		imovel.PlantaID = *req.PlantaID
 	}
 	if req.CorretorPrincipalID != nil {
+		// A corretor user cannot hand the property over to another corretor
+		if err := CheckCorretorScope(ctx, *req.CorretorPrincipalID); err != nil {
+			return nil, err
+		}
+		if err := s.checkTenantCorretor(ctx, *req.CorretorPrincipalID); err != nil {
+			return nil, err
+		}
 		imovel.CorretorPrincipalID = *req.CorretorPrincipalID
 	}
 	if req.PacoteID != nil {
@@ -319,24 +664,213 @@ func (s *service) UpdateImovel(ctx context.Context, id uint, req *UpdateImovelRe
 		imovel.PrecoAluguelID = *req.PrecoAluguelID
 	}
 
-	// Update status fields
-	if req.Status != "" {
-		imovel.Status = req.Status
+	// Publication is driven by the publish/unpublish workflow; only unchanged values are accepted here
+	if req.Status != "" && req.Status != currentStatus(imovel) {
+		return nil, fmt.Errorf("%w: use the publish/unpublish endpoints to change the status", ErrInvalidTransition)
 	}
-	if req.Published != nil {
-		imovel.Published = *req.Published
+	if req.Published != nil && *req.Published != imovel.Published {
+		return nil, fmt.Errorf("%w: use the publish/unpublish endpoints to change publication", ErrInvalidTransition)
 	}
 	if req.Closed != nil {
 		imovel.Closed = *req.Closed
 	}
 
+	if req.PublicarEm != nil || req.ExpiraEm != nil {
+		if req.PublicarEm != nil {
+			imovel.PublicarEm = req.PublicarEm
+		}
+		if req.ExpiraEm != nil {
+			imovel.ExpiraEm = req.ExpiraEm
+		}
+		if err := checkSchedule(imovel.PublicarEm, imovel.ExpiraEm); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fields edited by hand are locked against the importer
+	if priceOrigin(ctx) != PriceOriginImport {
+		if edited := editedFields(before, s.mapToResponse(imovel)); len(edited) > 0 {
+			imovel.LockedFields = imovel.LockedFields.with(edited...)
+		}
+	}
+
 	// Update in repository
 	if err := s.repo.Update(ctx, imovel); err != nil {
 		return nil, fmt.Errorf("failed to update property: %w", err)
 	}
+	if len(seo) > 0 {
+		if err := s.repo.Patch(ctx, id, seo); err != nil {
+			return nil, fmt.Errorf("failed to update property: %w", err)
+		}
+	}
+	if err := s.assignSlug(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.recordPriceHistory(ctx, []uint{id}); err != nil {
+		return nil, err
+	}
 
 	// Retrieve and return updated property
-	return s.GetImovel(ctx, id)
+	return s.auditedUpdate(ctx, id, AuditEntidadeImovel, before)
+}
+
+// PatchImovel applies a merge patch to a property, allowing optional fields to be cleared
+func (s *service) PatchImovel(ctx context.Context, id uint, req *PatchImovelRequest) (*ImovelResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid property ID")
+	}
+
+	imovel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return nil, err
+	}
+	if req.CorretorPrincipalID.Set {
+		// A corretor user cannot hand the property over to another corretor, nor clear it
+		if err := CheckCorretorScope(ctx, req.CorretorPrincipalID.Value); err != nil {
+			return nil, err
+		}
+		if err := s.checkTenantCorretor(ctx, req.CorretorPrincipalID.Value); err != nil {
+			return nil, err
+		}
+	}
+	if req.Version != nil && *req.Version != imovel.Version {
+		return nil, ErrVersionConflict
+	}
+	before := s.mapToResponse(imovel)
+
+	if hasValue(req.Codigo) && req.Codigo.Value != imovel.Codigo {
+		exists, err := s.repo.ExistsByCodigo(ctx, req.Codigo.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check codigo uniqueness: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: '%s'", ErrCodigoExists, req.Codigo.Value)
+		}
+	}
+
+	// Same rule as CreateImovel, evaluated against the state after the patch
+	objetivo := imovel.Objetivo
+	if hasValue(req.Objetivo) {
+		objetivo = req.Objetivo.Value
+	}
+	precoVendaID := imovel.PrecoVendaID
+	if req.PrecoVendaID.Set {
+		precoVendaID = req.PrecoVendaID.Value
+	}
+	precoAluguelID := imovel.PrecoAluguelID
+	if req.PrecoAluguelID.Set {
+		precoAluguelID = req.PrecoAluguelID.Value
+	}
+	if objetivo == "ALUGAR" && precoAluguelID == 0 {
+		return nil, fmt.Errorf("%w: rental properties must have a rental price", ErrInvalidImovel)
+	}
+	if objetivo == "VENDER" && precoVendaID == 0 {
+		return nil, fmt.Errorf("%w: properties for sale must have a selling price", ErrInvalidImovel)
+	}
+	if hasValue(req.PublicarEm) || hasValue(req.ExpiraEm) {
+		publicarEm, expiraEm := imovel.PublicarEm, imovel.ExpiraEm
+		if req.PublicarEm.Set {
+			publicarEm = nullableTime(req.PublicarEm)
+		}
+		if req.ExpiraEm.Set {
+			expiraEm = nullableTime(req.ExpiraEm)
+		}
+		if err := checkSchedule(publicarEm, expiraEm); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := req.columnUpdates()
+	// Fields edited by hand are locked against the importer, unless the patch sets the locks itself
+	if req.LockedFields.Set {
+		updates["locked_fields"] = LockedFields(req.LockedFields.Value)
+	} else if edited := patchedFields(before, updates); len(edited) > 0 && priceOrigin(ctx) != PriceOriginImport {
+		updates["locked_fields"] = imovel.LockedFields.with(edited...)
+	}
+	if len(updates) > 0 || req.Caracteristicas.Set {
+		// Also bumps the version, failing if the property changed since it was loaded
+		if err := s.repo.PatchVersion(ctx, id, imovel.Version, updates); err != nil {
+			return nil, fmt.Errorf("failed to patch property: %w", err)
+		}
+	}
+	if len(updates) > 0 {
+		if err := s.assignSlug(ctx, id); err != nil {
+			return nil, err
+		}
+		if err := s.recordPriceHistory(ctx, []uint{id}); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Caracteristicas.Set {
+		if err := s.repo.RemoveAllCaracteristicas(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to remove existing characteristics: %w", err)
+		}
+		if len(req.Caracteristicas.Value) > 0 {
+			if err := s.repo.AddCaracteristicas(ctx, id, req.Caracteristicas.Value); err != nil {
+				return nil, fmt.Errorf("failed to add characteristics: %w", err)
+			}
+		}
+	}
+
+	return s.auditedUpdate(ctx, id, AuditEntidadeImovel, before)
+}
+
+// PublishImovel moves a property from EM_EDICAO to PUBLICADO once it has the required data
+func (s *service) PublishImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	imovel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return nil, err
+	}
+
+	if err := checkTransition(currentStatus(imovel), StatusPublicado); err != nil {
+		return nil, err
+	}
+	if err := checkPublishRequirements(imovel); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, StatusPublicado, true); err != nil {
+		return nil, fmt.Errorf("failed to publish property: %w", err)
+	}
+
+	return s.auditedTransition(ctx, imovel, AuditAcaoPublish)
+}
+
+// UnpublishImovel archives a published property, removing it from public listings
+func (s *service) UnpublishImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	imovel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return nil, err
+	}
+
+	if err := checkTransition(currentStatus(imovel), StatusArquivado); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, StatusArquivado, false); err != nil {
+		return nil, fmt.Errorf("failed to unpublish property: %w", err)
+	}
+
+	return s.auditedTransition(ctx, imovel, AuditAcaoUnpublish)
 }
 
 // DeleteImovel soft deletes a property
@@ -352,7 +886,10 @@ func (s *service) DeleteImovel(ctx context.Context, id uint) error {
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return err
 	}
 
 	// Soft delete
@@ -360,7 +897,7 @@ func (s *service) DeleteImovel(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to delete property: %w", err)
 	}
 
-	return nil
+	return s.repo.RecordAudit(ctx, id, AuditEntidadeImovel, AuditAcaoDelete, nil, nil)
 }
 
 // HardDeleteImovel permanently deletes a property
@@ -376,7 +913,7 @@ func (s *service) HardDeleteImovel(ctx context.Context, id uint) error {
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	// Hard delete
@@ -384,20 +921,90 @@ func (s *service) HardDeleteImovel(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to permanently delete property: %w", err)
 	}
 
-	return nil
+	return s.repo.RecordAudit(ctx, id, AuditEntidadeImovel, AuditAcaoHardDelete, nil, nil)
 }
 
-// ListImoveis retrieves properties with filtering and pagination
-func (s *service) ListImoveis(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error) {
-	// Validate pagination parameters
+// RestoreImovel brings a soft-deleted property back
+func (s *service) RestoreImovel(ctx context.Context, id uint) (*ImovelResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid property ID")
+	}
+
+	if _, scoped := contextutil.CorretorScopeFromContext(ctx); scoped {
+		corretorPrincipalID, found, err := s.repo.FindDeletedCorretor(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find property: %w", err)
+		}
+		if !found {
+			return nil, ErrImovelNotFound
+		}
+		if err := CheckCorretorScope(ctx, corretorPrincipalID); err != nil {
+			return nil, err
+		}
+	}
+
+	restored, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore property: %w", err)
+	}
+	if !restored {
+		return nil, ErrImovelNotFound
+	}
+	if err := s.repo.RecordAudit(ctx, id, AuditEntidadeImovel, AuditAcaoRestore, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return s.GetImovel(ctx, id)
+}
+
+// ListTrash retrieves soft-deleted properties with pagination
+func (s *service) ListTrash(ctx context.Context, query *TrashListQuery) (*TrashListResponse, error) {
 	if query.Page < 1 {
 		query.Page = 1
 	}
-	if query.Limit < 1 {
+	if query.Limit < 1 || query.Limit > 100 {
 		query.Limit = 10
 	}
-	if query.Limit > 100 {
-		query.Limit = 100
+
+	imoveis, total, err := s.repo.ListDeleted(ctx, query.Page, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted properties: %w", err)
+	}
+
+	results := make([]TrashedImovelResponse, len(imoveis))
+	for i := range imoveis {
+		results[i] = TrashedImovelResponse{
+			ImovelResponse: *s.mapToResponse(&imoveis[i]),
+			DeletedAt:      imoveis[i].DeletedAt.Time,
+		}
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &TrashListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+// ListImoveis retrieves properties with filtering and pagination
+func (s *service) ListImoveis(ctx context.Context, query *ImovelListQuery) (*ImovelListResponse, error) {
+	normalizeListQuery(query)
+
+	if query.UsesCursor() {
+		after, err := decodeListCursor(*query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		result, err := s.repo.ListByCursor(ctx, query, after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list properties: %w", err)
+		}
+		return result, nil
 	}
 
 	// Retrieve from repository
@@ -409,6 +1016,31 @@ func (s *service) ListImoveis(ctx context.Context, query *ImovelListQuery) (*Imo
 	return result, nil
 }
 
+// ListImoveisMap retrieves lightweight marker data for map views
+func (s *service) ListImoveisMap(ctx context.Context, query *ImovelListQuery) (*ImovelMapListResponse, error) {
+	normalizeListQuery(query)
+
+	result, err := s.repo.ListMap(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties for map: %w", err)
+	}
+
+	return result, nil
+}
+
+// normalizeListQuery clamps pagination parameters
+func normalizeListQuery(query *ImovelListQuery) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 10
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+}
+
 // ListImovelsByEmpreendimento retrieves properties by enterprise
 func (s *service) ListImovelsByEmpreendimento(ctx context.Context, empreendimentoID uint, page, limit int) ([]ImovelResponse, int64, error) {
 	if empreendimentoID == 0 {
@@ -457,7 +1089,7 @@ func (s *service) ListImovelsByOrganizacao(ctx context.Context, organizacaoID ui
 	}
 
 	// Retrieve from repository
-	imoveis, total, err := s.repo.ListByCorretorPrincipal(ctx, organizacaoID, page, limit)
+	imoveis, total, err := s.repo.ListByOrganizacao(ctx, organizacaoID, page, limit)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list properties by organization: %w", err)
 	}
@@ -471,6 +1103,34 @@ func (s *service) ListImovelsByOrganizacao(ctx context.Context, organizacaoID ui
 	return responses, total, nil
 }
 
+// ListImovelsByCorretor retrieves a page of a corretor's properties along with stats over the whole portfolio
+func (s *service) ListImovelsByCorretor(ctx context.Context, corretorPrincipalID uint, page, limit int) (*CorretorImoveisResponse, error) {
+	if corretorPrincipalID == 0 {
+		return nil, errors.New("invalid corretor ID")
+	}
+
+	imoveis, total, err := s.repo.ListByCorretorPrincipal(ctx, corretorPrincipalID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties by corretor: %w", err)
+	}
+
+	stats, err := s.repo.CorretorStats(ctx, corretorPrincipalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate corretor stats: %w", err)
+	}
+
+	responses := make([]ImovelResponse, len(imoveis))
+	for i := range imoveis {
+		responses[i] = *s.mapToResponse(&imoveis[i])
+	}
+
+	return &CorretorImoveisResponse{
+		CorretorID: corretorPrincipalID,
+		Stats:      *stats,
+		Imoveis:    newListResponse(responses, total, page, limit),
+	}, nil
+}
+
 // CreateImovelBatch creates multiple properties
 func (s *service) CreateImovelBatch(ctx context.Context, reqs []CreateImovelRequest) error {
 	if len(reqs) == 0 {
@@ -484,6 +1144,9 @@ func (s *service) CreateImovelBatch(ctx context.Context, reqs []CreateImovelRequ
 		if req.Codigo == "" {
 			return fmt.Errorf("property at index %d: codigo is required", i)
 		}
+		if err := s.checkTenantCorretor(ctx, req.CorretorPrincipalID); err != nil {
+			return fmt.Errorf("property at index %d: %w", i, err)
+		}
 
 		imoveis[i] = Imovel{
 			Id_Integracao:       req.IdIntegracao,
@@ -520,6 +1183,19 @@ func (s *service) CreateImovelBatch(ctx context.Context, reqs []CreateImovelRequ
 	if err := s.repo.CreateBatch(ctx, imoveis); err != nil {
 		return fmt.Errorf("failed to create properties in batch: %w", err)
 	}
+	ids := make([]uint, len(imoveis))
+	for i := range imoveis {
+		if err := s.assignSlug(ctx, imoveis[i].ID); err != nil {
+			return err
+		}
+		if err := s.repo.RecordAudit(ctx, imoveis[i].ID, AuditEntidadeImovel, AuditAcaoCreate, nil, s.mapToResponse(&imoveis[i])); err != nil {
+			return err
+		}
+		ids[i] = imoveis[i].ID
+	}
+	if err := s.recordPriceHistory(ctx, ids); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -573,6 +1249,75 @@ func (s *service) CountImovelsByEmpreendimento(ctx context.Context, empreendimen
 	return count, nil
 }
 
+// GetStats returns the total number of properties and the count per publication status
+func (s *service) GetStats(ctx context.Context) (*ImovelStatsResponse, error) {
+	total, err := s.CountImoveis(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ImovelStatsResponse{Total: total, ByStatus: make(map[string]int64, 3)}
+	for _, status := range []string{StatusEmEdicao, StatusPublicado, StatusArquivado} {
+		count, err := s.CountImovelsByStatus(ctx, status)
+		if err != nil {
+			return nil, err
+		}
+		stats.ByStatus[status] = count
+	}
+
+	return stats, nil
+}
+
+// GetFacets returns the filter counts for the properties matching query
+func (s *service) GetFacets(ctx context.Context, query *ImovelListQuery) (*ImovelFacetsResponse, error) {
+	normalizeListQuery(query)
+
+	facets, err := s.repo.Facets(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute property facets: %w", err)
+	}
+
+	return facets, nil
+}
+
+// RegisterView counts a view of a property, ignoring repeated views by the same viewer
+// (session ID or IP) within the dedup window
+func (s *service) RegisterView(ctx context.Context, id uint, viewer string) (*ViewRegisteredResponse, error) {
+	if !s.views.claim(id, viewer) {
+		return &ViewRegisteredResponse{ImovelID: id, Counted: false}, nil
+	}
+
+	found, err := s.repo.IncrementViews(ctx, id)
+	if err != nil {
+		s.views.release(id, viewer)
+		return nil, fmt.Errorf("failed to register view: %w", err)
+	}
+	if !found {
+		s.views.release(id, viewer)
+		return nil, ErrImovelNotFound
+	}
+
+	return &ViewRegisteredResponse{ImovelID: id, Counted: true}, nil
+}
+
+// TopViewedImoveis returns the most viewed properties
+func (s *service) TopViewedImoveis(ctx context.Context, limit int) ([]ImovelViewStats, error) {
+	stats, err := s.repo.TopViewed(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve view stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ViewStatsByCorretor returns views aggregated per corretor principal
+func (s *service) ViewStatsByCorretor(ctx context.Context, limit int) ([]CorretorViewStats, error) {
+	stats, err := s.repo.ViewStatsByCorretor(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve corretor view stats: %w", err)
+	}
+	return stats, nil
+}
+
 // ImovelExistsByCodigo checks if a property exists by codigo
 func (s *service) ImovelExistsByCodigo(ctx context.Context, codigo string) (bool, error) {
 	if codigo == "" {
@@ -602,31 +1347,39 @@ func (s *service) ImovelExistsByIdIntegracao(ctx context.Context, idIntegracao s
 // mapToResponse converts Imovel model to response DTO
 func (s *service) mapToResponse(imovel *Imovel) *ImovelResponse {
 	response := &ImovelResponse{
-		ID:            imovel.ID,
-		IdIntegracao:  imovel.Id_Integracao,
-		Titulo:        imovel.Titulo,
-		Codigo:        imovel.Codigo,
-		SeqCodigo:     imovel.SeqCodigo,
-		Tipo:          imovel.Tipo,
-		Objetivo:      imovel.Objetivo,
-		Finalidade:    imovel.Finalidade,
-		Descricao:     imovel.Descricao,
-		Metragem:      imovel.Metragem,
-		NumQuartos:    imovel.NumQuartos,
-		NumSuites:     imovel.NumSuites,
-		NumBanheiros:  imovel.NumBanheiros,
-		NumVagas:      imovel.NumVagas,
-		NumAndar:      imovel.NumAndar,
-		Unidade:       imovel.Unidade,
-		Condominio:    imovel.Condominio,
-		IPTU:          imovel.IPTU,
-		InscricaoIPTU: imovel.InscricaoIPTU,
-		Status:        imovel.Status,
-		Published:     imovel.Published,
-		Closed:        imovel.Closed,
-		Visualizacoes: imovel.Visualizacoes,
-		CreatedAt:     imovel.CreatedAt,
-		UpdatedAt:     imovel.UpdatedAt,
+		ID:               imovel.ID,
+		IdIntegracao:     imovel.Id_Integracao,
+		Titulo:           imovel.Titulo,
+		Codigo:           imovel.Codigo,
+		SeqCodigo:        imovel.SeqCodigo,
+		Tipo:             imovel.Tipo,
+		Objetivo:         imovel.Objetivo,
+		Finalidade:       imovel.Finalidade,
+		Descricao:        imovel.Descricao,
+		Metragem:         imovel.Metragem,
+		NumQuartos:       imovel.NumQuartos,
+		NumSuites:        imovel.NumSuites,
+		NumBanheiros:     imovel.NumBanheiros,
+		NumVagas:         imovel.NumVagas,
+		NumAndar:         imovel.NumAndar,
+		Unidade:          imovel.Unidade,
+		Condominio:       imovel.Condominio,
+		IPTU:             imovel.IPTU,
+		InscricaoIPTU:    imovel.InscricaoIPTU,
+		Slug:             imovel.Slug,
+		MetaTitle:        imovel.MetaTitle,
+		MetaDescription:  imovel.MetaDescription,
+		Status:           imovel.Status,
+		Published:        imovel.Published,
+		Closed:           imovel.Closed,
+		PublicarEm:       imovel.PublicarEm,
+		ExpiraEm:         imovel.ExpiraEm,
+		RemovidoOrigemEm: imovel.RemovidoOrigemEm,
+		LockedFields:     imovel.LockedFields,
+		Version:          imovel.Version,
+		Visualizacoes:    imovel.Visualizacoes,
+		CreatedAt:        imovel.CreatedAt,
+		UpdatedAt:        imovel.UpdatedAt,
 	}
 
 	// Map relationships
@@ -693,6 +1446,8 @@ func (s *service) mapToResponse(imovel *Imovel) *ImovelResponse {
 				Image:         imovel.CorretorPrincipal.Foto.Image,
 				Video:         imovel.CorretorPrincipal.Foto.Video,
 				IsExternalURL: imovel.CorretorPrincipal.Foto.IsExternalURL,
+				ExternalURL:   imovel.CorretorPrincipal.Foto.ExternalURL,
+				Variants:      imovel.CorretorPrincipal.Foto.Variants,
 				CreatedAt:     imovel.CorretorPrincipal.Foto.CreatedAt,
 				UpdatedAt:     imovel.CorretorPrincipal.Foto.UpdatedAt,
 			}
@@ -765,12 +1520,26 @@ func (s *service) mapToResponse(imovel *Imovel) *ImovelResponse {
 				Image:         anexo.Image,
 				Video:         anexo.Video,
 				IsExternalURL: anexo.IsExternalURL,
+				ExternalURL:   anexo.ExternalURL,
+				Variants:      anexo.Variants,
 				CreatedAt:     anexo.CreatedAt,
 				UpdatedAt:     anexo.UpdatedAt,
 			}
 		}
 	}
 
+	// Map caracteristicas
+	for _, c := range imovel.Caracteristicas {
+		response.Caracteristicas = append(response.Caracteristicas, CaracteristicaResponse{
+			ID:            c.ID,
+			Nome:          c.Nome,
+			CategoriaID:   c.CategoriaID,
+			CategoriaNome: c.CategoriaNome,
+			CreatedAt:     c.CreatedAt,
+			UpdatedAt:     c.UpdatedAt,
+		})
+	}
+
 	return response
 }
 
@@ -788,12 +1557,22 @@ func (s *service) AddAnexo(ctx context.Context, imovelID uint, anexo *Anexo) err
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return err
 	}
 
 	if err := s.repo.AddAnexo(ctx, imovelID, anexo); err != nil {
 		return fmt.Errorf("failed to add attachment: %w", err)
 	}
+	if err := s.repo.RecordAudit(ctx, imovelID, AuditEntidadeAnexo, AuditAcaoCreate, nil, anexo); err != nil {
+		return err
+	}
+
+	if anexo.Image && s.anexoProcessor != nil {
+		afterCommit(ctx, func() { s.anexoProcessor.Enqueue(anexo.ID) })
+	}
 
 	return nil
 }
@@ -804,11 +1583,19 @@ func (s *service) RemoveAnexo(ctx context.Context, imovelID, anexoID uint) error
 		return errors.New("invalid property or attachment ID")
 	}
 
+	anexo, err := s.repo.FindAnexoByID(ctx, anexoID)
+	if err != nil {
+		return fmt.Errorf("failed to find attachment: %w", err)
+	}
+
 	if err := s.repo.RemoveAnexo(ctx, imovelID, anexoID); err != nil {
 		return fmt.Errorf("failed to remove attachment: %w", err)
 	}
 
-	return nil
+	if anexo == nil {
+		return nil
+	}
+	return s.repo.RecordAudit(ctx, imovelID, AuditEntidadeAnexo, AuditAcaoDelete, anexo, nil)
 }
 
 // GetAnexos retrieves all attachments for a property
@@ -835,6 +1622,8 @@ func (s *service) GetAnexos(ctx context.Context, imovelID uint) ([]AnexoResponse
 			Image:         anexo.Image,
 			Video:         anexo.Video,
 			IsExternalURL: anexo.IsExternalURL,
+			ExternalURL:   anexo.ExternalURL,
+			Variants:      anexo.Variants,
 			CreatedAt:     anexo.CreatedAt,
 			UpdatedAt:     anexo.UpdatedAt,
 		}
@@ -855,7 +1644,7 @@ func (s *service) AttachEndereco(ctx context.Context, imovelID, enderecoID uint)
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdateEndereco(ctx, imovelID, enderecoID); err != nil {
@@ -882,7 +1671,7 @@ func (s *service) AttachEmpreendimento(ctx context.Context, imovelID, empreendim
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdateEmpreendimento(ctx, imovelID, empreendimentoID); err != nil {
@@ -904,7 +1693,7 @@ func (s *service) AttachPlanta(ctx context.Context, imovelID, plantaID uint) err
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdatePlanta(ctx, imovelID, plantaID); err != nil {
@@ -926,7 +1715,7 @@ func (s *service) AttachPacote(ctx context.Context, imovelID, pacoteID uint) err
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdatePacote(ctx, imovelID, pacoteID); err != nil {
@@ -948,7 +1737,7 @@ func (s *service) AttachOrganizacao(ctx context.Context, imovelID, organizacaoID
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdateCorretorPrincipal(ctx, imovelID, organizacaoID); err != nil {
@@ -970,14 +1759,14 @@ func (s *service) AttachPrecoVenda(ctx context.Context, imovelID, precoVendaID u
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdatePrecoVenda(ctx, imovelID, precoVendaID); err != nil {
 		return fmt.Errorf("failed to attach selling price: %w", err)
 	}
 
-	return nil
+	return s.recordPriceHistory(ctx, []uint{imovelID})
 }
 
 // AttachPrecoAluguel attaches a rental price to a property
@@ -992,14 +1781,14 @@ func (s *service) AttachPrecoAluguel(ctx context.Context, imovelID, precoAluguel
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
 	}
 
 	if err := s.repo.UpdatePrecoAluguel(ctx, imovelID, precoAluguelID); err != nil {
 		return fmt.Errorf("failed to attach rental price: %w", err)
 	}
 
-	return nil
+	return s.recordPriceHistory(ctx, []uint{imovelID})
 }
 
 // AddCaracteristicas adds characteristics to a property
@@ -1018,14 +1807,18 @@ func (s *service) AddCaracteristicas(ctx context.Context, imovelID uint, caracte
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return err
 	}
 
 	if err := s.repo.AddCaracteristicas(ctx, imovelID, caracteristicaIDs); err != nil {
 		return fmt.Errorf("failed to add characteristics: %w", err)
 	}
 
-	return nil
+	_, err = s.auditedUpdate(ctx, imovelID, AuditEntidadeCaracteristicas, s.mapToResponse(imovel))
+	return err
 }
 
 // RemoveCaracteristicas removes characteristics from a property
@@ -1044,14 +1837,18 @@ func (s *service) RemoveCaracteristicas(ctx context.Context, imovelID uint, cara
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return err
 	}
 
 	if err := s.repo.RemoveCaracteristicas(ctx, imovelID, caracteristicaIDs); err != nil {
 		return fmt.Errorf("failed to remove characteristics: %w", err)
 	}
 
-	return nil
+	_, err = s.auditedUpdate(ctx, imovelID, AuditEntidadeCaracteristicas, s.mapToResponse(imovel))
+	return err
 }
 
 // GetCaracteristicas retrieves all characteristics for a property
@@ -1092,7 +1889,10 @@ func (s *service) ReplaceCaracteristicas(ctx context.Context, imovelID uint, car
 	}
 
 	if imovel == nil {
-		return fmt.Errorf("property not found")
+		return ErrImovelNotFound
+	}
+	if err := CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return err
 	}
 
 	// Remove all existing characteristics
@@ -1101,11 +1901,13 @@ func (s *service) ReplaceCaracteristicas(ctx context.Context, imovelID uint, car
 	}
 
 	// Add new characteristics
+	caracteristicaIDs = uniqueIDs(caracteristicaIDs)
 	if len(caracteristicaIDs) > 0 {
 		if err := s.repo.AddCaracteristicas(ctx, imovelID, caracteristicaIDs); err != nil {
 			return fmt.Errorf("failed to add characteristics: %w", err)
 		}
 	}
 
-	return nil
+	_, err = s.auditedUpdate(ctx, imovelID, AuditEntidadeCaracteristicas, s.mapToResponse(imovel))
+	return err
 }
@@ -0,0 +1,112 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+const (
+	// defaultEntradaRatio is the down payment assumed when the simulation does not send one
+	defaultEntradaRatio = 0.2
+	// maxIncomeCommitment is the share of the monthly income an installment may take
+	maxIncomeCommitment = 0.3
+)
+
+// roundMoney rounds a value to cents
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// monthlyRate converts an effective annual rate in % to the equivalent monthly rate
+func monthlyRate(annual float64) float64 {
+	return math.Pow(1+annual/100, 1.0/12) - 1
+}
+
+// amortizeSAC builds a SAC table: constant amortization, decreasing installments
+func amortizeSAC(principal, rate float64, months int) []ParcelaSimulacao {
+	parcelas := make([]ParcelaSimulacao, months)
+	amortizacao := principal / float64(months)
+	saldo := principal
+	for k := range parcelas {
+		juros := saldo * rate
+		saldo -= amortizacao
+		parcelas[k] = ParcelaSimulacao{Numero: k + 1, Parcela: amortizacao + juros, Amortizacao: amortizacao, Juros: juros, Saldo: math.Max(saldo, 0)}
+	}
+	return parcelas
+}
+
+// amortizePrice builds a PRICE (French system) table: constant installments, increasing amortization
+func amortizePrice(principal, rate float64, months int) []ParcelaSimulacao {
+	parcelas := make([]ParcelaSimulacao, months)
+	parcela := principal * rate / (1 - math.Pow(1+rate, -float64(months)))
+	saldo := principal
+	for k := range parcelas {
+		juros := saldo * rate
+		amortizacao := parcela - juros
+		saldo -= amortizacao
+		parcelas[k] = ParcelaSimulacao{Numero: k + 1, Parcela: parcela, Amortizacao: amortizacao, Juros: juros, Saldo: math.Max(saldo, 0)}
+	}
+	return parcelas
+}
+
+// summarizeAmortization totals a table and rounds its values to cents
+func summarizeAmortization(parcelas []ParcelaSimulacao, principal float64) SistemaAmortizacaoResponse {
+	var total float64
+	for i := range parcelas {
+		total += parcelas[i].Parcela
+	}
+	summary := SistemaAmortizacaoResponse{
+		PrimeiraParcela: roundMoney(parcelas[0].Parcela),
+		UltimaParcela:   roundMoney(parcelas[len(parcelas)-1].Parcela),
+		TotalPago:       roundMoney(total),
+		TotalJuros:      roundMoney(total - principal),
+		RendaMinima:     roundMoney(parcelas[0].Parcela / maxIncomeCommitment),
+		Parcelas:        parcelas,
+	}
+	for i := range parcelas {
+		parcelas[i].Parcela = roundMoney(parcelas[i].Parcela)
+		parcelas[i].Amortizacao = roundMoney(parcelas[i].Amortizacao)
+		parcelas[i].Juros = roundMoney(parcelas[i].Juros)
+		parcelas[i].Saldo = roundMoney(parcelas[i].Saldo)
+	}
+	return summary
+}
+
+// SimulateFinancing simulates financing the active selling price of a property, minus the down
+// payment, under the SAC and PRICE amortization systems
+func (s *service) SimulateFinancing(ctx context.Context, id uint, query *SimulacaoQuery) (*SimulacaoResponse, error) {
+	imovel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+	if imovel.PrecoVenda == nil || !imovel.PrecoVenda.Ativo || imovel.PrecoVenda.Preco <= 0 {
+		return nil, fmt.Errorf("%w: property has no active selling price", ErrInvalidImovel)
+	}
+
+	preco := imovel.PrecoVenda.Preco
+	entrada := roundMoney(preco * defaultEntradaRatio)
+	if query.Entrada != nil {
+		entrada = *query.Entrada
+	}
+	if entrada >= preco {
+		return nil, fmt.Errorf("%w: entrada must be lower than the selling price", ErrInvalidImovel)
+	}
+
+	principal := preco - entrada
+	rate := monthlyRate(query.Taxa)
+	return &SimulacaoResponse{
+		ImovelID:        imovel.ID,
+		PrecoVenda:      preco,
+		Entrada:         entrada,
+		ValorFinanciado: roundMoney(principal),
+		Prazo:           query.Prazo,
+		TaxaAnual:       query.Taxa,
+		TaxaMensal:      math.Round(rate*1e6) / 1e6,
+		SAC:             summarizeAmortization(amortizeSAC(principal, rate, query.Prazo), principal),
+		Price:           summarizeAmortization(amortizePrice(principal, rate, query.Prazo), principal),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmortizationTables(t *testing.T) {
+	sac := summarizeAmortization(amortizeSAC(1000, 0.01, 2), 1000)
+	assert.Equal(t, 510.0, sac.PrimeiraParcela)
+	assert.Equal(t, 505.0, sac.UltimaParcela)
+	assert.Equal(t, 15.0, sac.TotalJuros)
+	assert.Equal(t, 1700.0, sac.RendaMinima)
+	assert.Equal(t, 500.0, sac.Parcelas[0].Saldo)
+	assert.Equal(t, 0.0, sac.Parcelas[1].Saldo)
+
+	price := summarizeAmortization(amortizePrice(1000, 0.01, 2), 1000)
+	assert.Equal(t, 507.51, price.PrimeiraParcela)
+	assert.Equal(t, 507.51, price.UltimaParcela)
+	assert.Equal(t, 10.0, price.Parcelas[0].Juros)
+	assert.Equal(t, 0.0, price.Parcelas[1].Saldo)
+	assert.InDelta(t, 15.02, price.TotalJuros, 0.01)
+
+	assert.InDelta(t, 0.009489, monthlyRate(12), 1e-6)
+}
+
+func TestSimulateFinancing(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	simulacao, err := svc.SimulateFinancing(ctx, created.ID, &SimulacaoQuery{Prazo: 360, Taxa: 12})
+	require.NoError(t, err)
+	assert.Equal(t, 110000.0, simulacao.Entrada, "20% of the price by default")
+	assert.Equal(t, 440000.0, simulacao.ValorFinanciado)
+	require.Len(t, simulacao.SAC.Parcelas, 360)
+	require.Len(t, simulacao.Price.Parcelas, 360)
+	assert.Equal(t, 0.0, simulacao.SAC.Parcelas[359].Saldo)
+	assert.Equal(t, 0.0, simulacao.Price.Parcelas[359].Saldo)
+
+	// SAC starts higher and pays less interest overall
+	assert.Greater(t, simulacao.SAC.PrimeiraParcela, simulacao.Price.PrimeiraParcela)
+	assert.Less(t, simulacao.SAC.UltimaParcela, simulacao.Price.UltimaParcela)
+	assert.Less(t, simulacao.SAC.TotalJuros, simulacao.Price.TotalJuros)
+	assert.InDelta(t, simulacao.SAC.PrimeiraParcela/0.3, simulacao.SAC.RendaMinima, 0.01)
+
+	entrada := 550000.0
+	_, err = svc.SimulateFinancing(ctx, created.ID, &SimulacaoQuery{Entrada: &entrada, Prazo: 360, Taxa: 12})
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+
+	_, err = svc.SimulateFinancing(ctx, 999, &SimulacaoQuery{Prazo: 360, Taxa: 12})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+
+	req := nestedCreateRequest("AP-002")
+	req.Objetivo = "ALUGAR"
+	req.PrecoVenda = nil
+	aluguel, err := svc.CreateImovel(ctx, req)
+	require.NoError(t, err)
+	_, err = svc.SimulateFinancing(ctx, aluguel.ID, &SimulacaoQuery{Prazo: 360, Taxa: 12})
+	assert.ErrorIs(t, err, ErrInvalidImovel)
+}
@@ -0,0 +1,98 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSlugAttempts bounds the numeric suffixes tried when a slug is already taken
+const maxSlugAttempts = 50
+
+// accentFolder maps the accented letters used in Portuguese to plain ASCII
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// slugify lowercases s, folds accents and joins the remaining letters and digits with hyphens
+func slugify(s string) string {
+	s = accentFolder.Replace(strings.ToLower(s))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// buildImovelSlug returns the base slug of a property, e.g. apartamento-3-quartos-moema-ap1234.
+// The bairro is read from the preloaded endereco.
+func buildImovelSlug(imovel *Imovel) string {
+	parts := []string{imovel.Tipo}
+	switch {
+	case imovel.NumQuartos == 1:
+		parts = append(parts, "1 quarto")
+	case imovel.NumQuartos > 1:
+		parts = append(parts, strconv.Itoa(imovel.NumQuartos)+" quartos")
+	}
+	if imovel.Endereco != nil {
+		parts = append(parts, imovel.Endereco.Bairro)
+	}
+	parts = append(parts, imovel.Codigo)
+
+	return slugify(strings.Join(parts, " "))
+}
+
+// assignSlug regenerates the slug of a property from its current tipo, quartos, bairro and codigo,
+// appending -2, -3, ... when another property already uses it. Repository calls join the
+// transaction of ctx, if any.
+func (s *service) assignSlug(ctx context.Context, id uint) error {
+	imovel, err := s.repo.FindWithEndereco(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load property for slug: %w", err)
+	}
+	if imovel == nil {
+		return ErrImovelNotFound
+	}
+
+	base := buildImovelSlug(imovel)
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		slug := base
+		if attempt > 1 {
+			slug = base + "-" + strconv.Itoa(attempt)
+		}
+
+		exists, err := s.repo.ExistsBySlug(ctx, slug, id)
+		if err != nil {
+			return fmt.Errorf("failed to check slug uniqueness: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		if slug == imovel.Slug {
+			return nil
+		}
+		if err := s.repo.UpdateSlug(ctx, id, slug); err != nil {
+			return fmt.Errorf("failed to update slug: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no free slug for '%s'", base)
+}
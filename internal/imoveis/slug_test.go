@@ -0,0 +1,76 @@
+package imoveis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "apartamento-3-quartos-sao-joao-ap-1234", slugify("APARTAMENTO 3 quartos São  João AP-1234"))
+	assert.Equal(t, "sala-comercial-acu", slugify("  SALA_COMERCIAL / Açu! "))
+	assert.Equal(t, "", slugify("--"))
+}
+
+func TestImovelSlug(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	req := nestedCreateRequest("AP1234")
+	req.NumQuartos = 3
+	req.Endereco.Bairro = "Moema"
+	created, err := svc.CreateImovel(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "apartamento-3-quartos-moema-ap1234", created.Slug)
+
+	// A codigo differing only in punctuation collides after slugify
+	req = nestedCreateRequest("ap.1234")
+	req.NumQuartos = 3
+	req.Endereco.Bairro = "Moema"
+	other, err := svc.CreateImovel(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "apartamento-3-quartos-moema-ap-1234", other.Slug)
+
+	req = nestedCreateRequest("AP-1234")
+	req.NumQuartos = 3
+	req.Endereco.Bairro = "Moema"
+	third, err := svc.CreateImovel(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "apartamento-3-quartos-moema-ap-1234-2", third.Slug)
+
+	found, err := svc.GetImovelBySlug(ctx, "apartamento-3-quartos-moema-ap1234")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	_, err = svc.GetImovelBySlug(ctx, "missing")
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+
+	// Updates regenerate the slug and set the SEO metadata
+	quartos := 1
+	title := " Apartamento em Moema "
+	updated, err := svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{NumQuartos: &quartos, MetaTitle: &title})
+	require.NoError(t, err)
+	assert.Equal(t, "apartamento-1-quarto-moema-ap1234", updated.Slug)
+	assert.Equal(t, "Apartamento em Moema", updated.MetaTitle)
+
+	empty := ""
+	updated, err = svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{MetaTitle: &empty})
+	require.NoError(t, err)
+	assert.Empty(t, updated.MetaTitle)
+
+	// Unchanged attributes keep the suffixed slug
+	description := "Tres quartos perto do parque"
+	updated, err = svc.UpdateImovel(ctx, third.ID, &UpdateImovelRequest{MetaDescription: &description})
+	require.NoError(t, err)
+	assert.Equal(t, "apartamento-3-quartos-moema-ap-1234-2", updated.Slug)
+	assert.Equal(t, description, updated.MetaDescription)
+
+	patched, err := svc.PatchImovel(ctx, third.ID, &PatchImovelRequest{
+		Tipo:            Nullable[string]{Set: true, Value: "CASA"},
+		MetaDescription: Nullable[string]{Set: true, Null: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "casa-3-quartos-moema-ap-1234", patched.Slug)
+	assert.Empty(t, patched.MetaDescription)
+}
@@ -0,0 +1,57 @@
+package imoveis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policies for the imported properties a full import no longer finds in the source
+// (externalapi.removed_policy)
+const (
+	RemovedPolicyNone    = "none"
+	RemovedPolicyArchive = "archive"
+	RemovedPolicyDelete  = "delete"
+	RemovedPolicyReview  = "review"
+)
+
+// motivoRemovidoOrigem is the audit reason of the changes made to properties removed from the source
+const motivoRemovidoOrigem = "removido da origem da integração"
+
+// MarkRemovedFromSource flags a property as gone from its import source and applies policy to it:
+// archive unpublishes it, delete soft deletes it and review only sets the flag for an admin to
+// decide. Properties that cannot be archived (e.g. still being edited) are only flagged. The import
+// clears the flag if the listing comes back.
+func (s *service) MarkRemovedFromSource(ctx context.Context, id uint, policy string) error {
+	imovel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve property: %w", err)
+	}
+	if imovel == nil {
+		return ErrImovelNotFound
+	}
+
+	ctx = withAuditMotivo(ctx, motivoRemovidoOrigem)
+	if err := s.repo.SetRemovidoOrigem(ctx, id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to flag property: %w", err)
+	}
+
+	switch policy {
+	case RemovedPolicyDelete:
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete property: %w", err)
+		}
+		return s.repo.RecordAudit(ctx, id, AuditEntidadeImovel, AuditAcaoDelete, nil, nil)
+	case RemovedPolicyArchive:
+		if checkTransition(currentStatus(imovel), StatusArquivado) == nil {
+			if err := s.repo.UpdateStatus(ctx, id, StatusArquivado, false); err != nil {
+				return fmt.Errorf("failed to archive property: %w", err)
+			}
+			_, err := s.auditedTransition(ctx, imovel, AuditAcaoUnpublish)
+			return err
+		}
+	}
+
+	_, err = s.auditedTransition(ctx, imovel, AuditAcaoUpdate)
+	return err
+}
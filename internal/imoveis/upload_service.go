@@ -0,0 +1,174 @@
+package imoveis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+var (
+	// ErrFileTooLarge is returned when an upload exceeds the configured maximum size
+	ErrFileTooLarge = errors.New("file exceeds the maximum upload size")
+	// ErrUnsupportedFileType is returned when the detected MIME type is not allowed
+	ErrUnsupportedFileType = errors.New("file type is not allowed")
+)
+
+// defaultMaxUploadBytes applies when storage.max_upload_bytes is not configured
+const defaultMaxUploadBytes = 10 << 20
+
+// defaultAllowedMIMETypes applies when storage.allowed_mime_types is not configured
+var defaultAllowedMIMETypes = []string{"image/jpeg", "image/png", "image/webp", "video/mp4", "application/pdf"}
+
+// mimeExtensions names stored objects; other types keep the extension of the uploaded file
+var mimeExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+	"video/mp4":       ".mp4",
+	"application/pdf": ".pdf",
+}
+
+// UploadedFile is a file received in a multipart request
+type UploadedFile struct {
+	Nome       string
+	Size       int64
+	Content    io.Reader
+	CanPublish bool
+}
+
+// UploadService stores uploaded files and registers them as property attachments
+type UploadService interface {
+	UploadAnexo(ctx context.Context, imovelID uint, file *UploadedFile) (*AnexoResponse, error)
+	MaxUploadBytes() int64
+}
+
+type uploadService struct {
+	service      Service
+	storage      storage.Storage
+	maxBytes     int64
+	allowedTypes map[string]bool
+}
+
+// NewUploadService creates a new attachment upload service
+func NewUploadService(service Service, store storage.Storage, cfg *config.StorageConfig) UploadService {
+	maxBytes := cfg.MaxUploadBytes
+	if maxBytes == 0 {
+		maxBytes = defaultMaxUploadBytes
+	}
+
+	types := cfg.AllowedMIMETypes
+	if len(types) == 0 {
+		types = defaultAllowedMIMETypes
+	}
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	return &uploadService{
+		service:      service,
+		storage:      store,
+		maxBytes:     maxBytes,
+		allowedTypes: allowed,
+	}
+}
+
+// MaxUploadBytes returns the largest accepted file size
+func (us *uploadService) MaxUploadBytes() int64 {
+	return us.maxBytes
+}
+
+// UploadAnexo validates the file, streams it to storage and adds it as an attachment.
+// The MIME type is sniffed from the content; the client-provided Content-Type is ignored.
+func (us *uploadService) UploadAnexo(ctx context.Context, imovelID uint, file *UploadedFile) (*AnexoResponse, error) {
+	if file.Size > us.maxBytes {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrFileTooLarge, us.maxBytes)
+	}
+
+	imovel, err := us.service.GetImovel(ctx, imovelID)
+	if err != nil {
+		return nil, err
+	}
+	var corretorPrincipalID uint
+	if imovel.CorretorPrincipal != nil {
+		corretorPrincipalID = imovel.CorretorPrincipal.ID
+	}
+	if err := CheckCorretorScope(ctx, corretorPrincipalID); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	contentType := strings.ToLower(strings.SplitN(http.DetectContentType(head), ";", 2)[0])
+	if !us.allowedTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+
+	ext, ok := mimeExtensions[contentType]
+	if !ok {
+		ext = strings.ToLower(filepath.Ext(file.Nome))
+	}
+	key := fmt.Sprintf("imoveis/%d/%s%s", imovelID, uuid.NewString(), ext)
+
+	body := io.MultiReader(bytes.NewReader(head), file.Content)
+	obj, err := us.storage.Put(ctx, key, body, file.Size, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	nome := strings.TrimSpace(file.Nome)
+	if nome == "" {
+		nome = filepath.Base(key)
+	}
+	anexo := &Anexo{
+		Nome:       nome,
+		Path:       obj.Key,
+		Tamanho:    obj.Size,
+		Tipo:       contentType,
+		URL:        obj.URL,
+		CanPublish: file.CanPublish,
+		Image:      strings.HasPrefix(contentType, "image/"),
+		Video:      strings.HasPrefix(contentType, "video/"),
+	}
+	if err := us.service.AddAnexo(ctx, imovelID, anexo); err != nil {
+		// Do not leave orphaned objects behind when the row could not be saved
+		if delErr := us.storage.Delete(context.WithoutCancel(ctx), obj.Key); delErr != nil {
+			log.Printf("Failed to delete orphaned upload %s: %v", obj.Key, delErr)
+		}
+		return nil, err
+	}
+
+	return &AnexoResponse{
+		ID:            anexo.ID,
+		Nome:          anexo.Nome,
+		Path:          anexo.Path,
+		Tamanho:       anexo.Tamanho,
+		Tipo:          anexo.Tipo,
+		URL:           anexo.URL,
+		CanPublish:    anexo.CanPublish,
+		Image:         anexo.Image,
+		Video:         anexo.Video,
+		IsExternalURL: anexo.IsExternalURL,
+		ExternalURL:   anexo.ExternalURL,
+		Variants:      anexo.Variants,
+		CreatedAt:     anexo.CreatedAt,
+		UpdatedAt:     anexo.UpdatedAt,
+	}, nil
+}
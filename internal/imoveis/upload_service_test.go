@@ -0,0 +1,70 @@
+package imoveis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func TestUploadAnexo(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	imovel, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	uploads := NewUploadService(svc, storage.NewLocalStorage(dir, ""), &config.StorageConfig{MaxUploadBytes: 1024})
+
+	content := pngHeader + "fake image data"
+	anexo, err := uploads.UploadAnexo(ctx, imovel.ID, &UploadedFile{
+		Nome:    "fachada.png",
+		Size:    int64(len(content)),
+		Content: strings.NewReader(content),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fachada.png", anexo.Nome)
+	assert.Equal(t, "image/png", anexo.Tipo)
+	assert.Equal(t, int64(len(content)), anexo.Tamanho)
+	assert.True(t, anexo.Image)
+	assert.False(t, anexo.IsExternalURL)
+	assert.True(t, strings.HasPrefix(anexo.Path, "imoveis/1/"))
+	assert.Equal(t, "/uploads/"+anexo.Path, anexo.URL)
+
+	stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(anexo.Path)))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(stored))
+
+	anexos, err := svc.GetAnexos(ctx, imovel.ID)
+	require.NoError(t, err)
+	assert.Len(t, anexos, 1)
+}
+
+func TestUploadAnexo_Rejections(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	imovel, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	uploads := NewUploadService(svc, storage.NewLocalStorage(t.TempDir(), ""), &config.StorageConfig{MaxUploadBytes: 16})
+
+	_, err = uploads.UploadAnexo(ctx, imovel.ID, &UploadedFile{Nome: "big.png", Size: 17, Content: strings.NewReader(pngHeader)})
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+
+	_, err = uploads.UploadAnexo(ctx, imovel.ID, &UploadedFile{Nome: "notes.png", Size: 5, Content: strings.NewReader("hello")})
+	assert.ErrorIs(t, err, ErrUnsupportedFileType, "the type is sniffed, not taken from the name")
+
+	_, err = uploads.UploadAnexo(ctx, 999, &UploadedFile{Nome: "a.png", Size: 8, Content: strings.NewReader(pngHeader[:8])})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+}
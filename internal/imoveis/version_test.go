@@ -0,0 +1,115 @@
+package imoveis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateImovel_OptimisticLocking(t *testing.T) {
+	svc, _ := setupCreateService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), created.Version)
+
+	// Two clients read version 1; the first write wins
+	version := created.Version
+	updated, err := svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{Titulo: "Editado pelo admin", Version: &version})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), updated.Version)
+
+	_, err = svc.UpdateImovel(ctx, created.ID, &UpdateImovelRequest{Titulo: "Editado pelo importador", Version: &version})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	_, err = svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{
+		Titulo:  Nullable[string]{Set: true, Value: "Patch atrasado"},
+		Version: &version,
+	})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	current, err := svc.GetImovel(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Editado pelo admin", current.Titulo)
+
+	// Patches move the version forward too, including characteristics-only ones
+	patched, err := svc.PatchImovel(ctx, created.ID, &PatchImovelRequest{Caracteristicas: Nullable[[]uint]{Set: true, Value: []uint{2}}})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), patched.Version)
+}
+
+func TestRepositoryUpdate_StaleVersion(t *testing.T) {
+	svc, database := setupCreateService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateImovel(ctx, nestedCreateRequest("AP-001"))
+	require.NoError(t, err)
+
+	repo := NewRepository(database)
+	first, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+
+	first.Titulo = "Primeira escrita"
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, uint(2), first.Version)
+
+	// A writer that loaded the same version before the first one committed is rejected
+	second.Titulo = "Segunda escrita"
+	assert.ErrorIs(t, repo.Update(ctx, second), ErrVersionConflict)
+	assert.Equal(t, uint(1), second.Version)
+
+	assert.ErrorIs(t, repo.PatchVersion(ctx, created.ID, 1, map[string]interface{}{"titulo": "x"}), ErrVersionConflict)
+	require.NoError(t, repo.PatchVersion(ctx, created.ID, 2, map[string]interface{}{"titulo": "Terceira escrita"}))
+
+	stored, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Terceira escrita", stored.Titulo)
+	assert.Equal(t, uint(3), stored.Version)
+}
+
+func TestBindVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bodyVersion := uint(4)
+	tests := []struct {
+		name     string
+		header   string
+		body     *uint
+		expected *uint
+		ok       bool
+	}{
+		{name: "no header", ok: true},
+		{name: "plain", header: "3", expected: uintPtr(3), ok: true},
+		{name: "quoted", header: `"3"`, expected: uintPtr(3), ok: true},
+		{name: "weak", header: `W/"3"`, expected: uintPtr(3), ok: true},
+		{name: "get etag", header: `W/"3-1a2b3c4d5e6f7a8b"`, expected: uintPtr(3), ok: true},
+		{name: "body wins", header: "3", body: &bodyVersion, expected: &bodyVersion, ok: true},
+		{name: "invalid", header: "abc", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version := tt.body
+			assert.Equal(t, tt.ok, bindVersion(c, &version))
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
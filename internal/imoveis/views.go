@@ -0,0 +1,71 @@
+package imoveis
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// viewDedupWindow is how long repeated views from the same viewer are ignored
+	viewDedupWindow = 30 * time.Minute
+	// viewDedupSize bounds the number of remembered viewer/property pairs
+	viewDedupSize = 100000
+	// maxViewSessionLength bounds the part of the session ID kept in the dedup key
+	maxViewSessionLength = 128
+)
+
+// viewerKey identifies a viewer by client IP plus the X-Session-ID it sent, or by the IP alone.
+// The session only tells apart viewers behind the same IP (offices, mobile carriers); since the
+// client chooses it, it is never trusted on its own, so a session sent from another IP is
+// another viewer.
+func viewerKey(ip, session string) string {
+	if session == "" {
+		return ip
+	}
+	if len(session) > maxViewSessionLength {
+		session = session[:maxViewSessionLength]
+	}
+	return ip + "|" + session
+}
+
+// viewDeduper remembers recent viewer/property pairs so refreshes are not counted twice.
+// It is in memory and per instance: with several replicas behind a load balancer a viewer may be
+// counted once per replica, and a restart forgets every claim.
+type viewDeduper struct {
+	mu     sync.Mutex
+	recent *expirable.LRU[string, struct{}]
+}
+
+func newViewDeduper(size int, window time.Duration) *viewDeduper {
+	return &viewDeduper{recent: expirable.NewLRU[string, struct{}](size, nil, window)}
+}
+
+// claim returns true the first time a viewer is seen for a property within the window
+func (d *viewDeduper) claim(imovelID uint, viewer string) bool {
+	if viewer == "" {
+		return true
+	}
+
+	key := fmt.Sprintf("%d:%s", imovelID, viewer)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Peek honours the TTL, unlike Contains which may report expired entries
+	if _, ok := d.recent.Peek(key); ok {
+		return false
+	}
+	d.recent.Add(key, struct{}{})
+	return true
+}
+
+// release forgets a claim whose increment failed so the next attempt is counted
+func (d *viewDeduper) release(imovelID uint, viewer string) {
+	if viewer == "" {
+		return
+	}
+	d.recent.Remove(fmt.Sprintf("%d:%s", imovelID, viewer))
+}
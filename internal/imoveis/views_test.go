@@ -0,0 +1,41 @@
+package imoveis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewDeduper(t *testing.T) {
+	d := newViewDeduper(10, time.Minute)
+
+	assert.True(t, d.claim(1, "10.0.0.1"))
+	assert.False(t, d.claim(1, "10.0.0.1"), "same viewer within the window is deduplicated")
+	assert.True(t, d.claim(2, "10.0.0.1"), "different property is counted")
+	assert.True(t, d.claim(1, "session-abc"), "different viewer is counted")
+
+	d.release(1, "10.0.0.1")
+	assert.True(t, d.claim(1, "10.0.0.1"), "released claims are counted again")
+
+	assert.True(t, d.claim(1, ""))
+	assert.True(t, d.claim(1, ""), "anonymous viewers are never deduplicated")
+}
+
+func TestViewDeduper_Expires(t *testing.T) {
+	d := newViewDeduper(10, 20*time.Millisecond)
+
+	assert.True(t, d.claim(1, "viewer"))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, d.claim(1, "viewer"))
+}
+
+func TestViewerKey(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", viewerKey("10.0.0.1", ""), "anonymous traffic is keyed on the IP")
+	assert.Equal(t, "10.0.0.1|abc", viewerKey("10.0.0.1", "abc"))
+	assert.NotEqual(t, viewerKey("10.0.0.1", "abc"), viewerKey("10.0.0.2", "abc"), "a session is bound to its IP")
+
+	long := viewerKey("10.0.0.1", strings.Repeat("x", 1000))
+	assert.Len(t, long, len("10.0.0.1|")+maxViewSessionLength)
+}
@@ -0,0 +1,82 @@
+package imoveis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Property publication statuses
+const (
+	StatusEmEdicao  = "EM_EDICAO"
+	StatusPublicado = "PUBLICADO"
+	StatusArquivado = "ARQUIVADO"
+)
+
+// statusTransitions is the publication state machine: EM_EDICAO → PUBLICADO → ARQUIVADO
+var statusTransitions = map[string][]string{
+	StatusEmEdicao:  {StatusPublicado},
+	StatusPublicado: {StatusArquivado},
+	StatusArquivado: {},
+}
+
+// PublishRequirementsError lists the data a property is missing to be published
+type PublishRequirementsError struct {
+	Missing map[string]string
+}
+
+func (e *PublishRequirementsError) Error() string {
+	fields := make([]string, 0, len(e.Missing))
+	for field := range e.Missing {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf("property is not ready to be published: missing %s", strings.Join(fields, ", "))
+}
+
+// currentStatus treats properties without a status (e.g. legacy imports) as being edited
+func currentStatus(imovel *Imovel) string {
+	if imovel.Status == "" {
+		return StatusEmEdicao
+	}
+	return imovel.Status
+}
+
+// checkTransition validates a status change against the state machine
+func checkTransition(from, to string) error {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidTransition, from, to)
+}
+
+// checkPublishRequirements ensures the property has an endereco, at least one anexo and an
+// active price matching its objetivo. The imovel must be loaded with those relations.
+func checkPublishRequirements(imovel *Imovel) error {
+	missing := map[string]string{}
+
+	if imovel.Endereco == nil {
+		missing["endereco"] = "an address is required"
+	}
+	if len(imovel.Anexos) == 0 {
+		missing["anexos"] = "at least one attachment is required"
+	}
+
+	switch imovel.Objetivo {
+	case "ALUGAR":
+		if imovel.PrecoAluguel == nil || !imovel.PrecoAluguel.Ativo {
+			missing["precoAluguel"] = "an active rental price is required"
+		}
+	default:
+		if imovel.PrecoVenda == nil || !imovel.PrecoVenda.Ativo {
+			missing["precoVenda"] = "an active selling price is required"
+		}
+	}
+
+	if len(missing) > 0 {
+		return &PublishRequirementsError{Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package imoveis
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTransition(t *testing.T) {
+	assert.NoError(t, checkTransition(StatusEmEdicao, StatusPublicado))
+	assert.NoError(t, checkTransition(StatusPublicado, StatusArquivado))
+
+	assert.True(t, errors.Is(checkTransition(StatusEmEdicao, StatusArquivado), ErrInvalidTransition))
+	assert.True(t, errors.Is(checkTransition(StatusArquivado, StatusPublicado), ErrInvalidTransition))
+	assert.True(t, errors.Is(checkTransition(StatusPublicado, StatusPublicado), ErrInvalidTransition))
+}
+
+func TestCheckPublishRequirements(t *testing.T) {
+	ready := &Imovel{
+		Objetivo:   "VENDER",
+		Endereco:   &Endereco{ID: 1},
+		Anexos:     []Anexo{{ID: 1}},
+		PrecoVenda: &PrecoVenda{ID: 1, Ativo: true},
+	}
+	assert.NoError(t, checkPublishRequirements(ready))
+
+	err := checkPublishRequirements(&Imovel{
+		Objetivo:     "ALUGAR",
+		PrecoAluguel: &PrecoAluguel{ID: 1, Ativo: false},
+	})
+	var reqErr *PublishRequirementsError
+	assert.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, []string{"anexos", "endereco", "precoAluguel"}, keys(reqErr.Missing))
+}
+
+func keys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
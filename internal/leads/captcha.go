@@ -0,0 +1,96 @@
+package leads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/httpclient"
+)
+
+const (
+	// defaultCaptchaVerifyURL is used when leads.captcha_verify_url is not configured
+	defaultCaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	// captchaTimeout bounds the verification of a captcha response
+	captchaTimeout = 10 * time.Second
+)
+
+// ErrInvalidCaptcha is returned when the captcha response is missing or refused by the provider
+var ErrInvalidCaptcha = errors.New("invalid captcha")
+
+// CaptchaVerifier checks the captcha response sent with a lead
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+type siteVerifyCaptcha struct {
+	secret    string
+	verifyURL string
+	http      *httpclient.Client
+}
+
+// NewCaptchaVerifier verifies captcha responses with the siteverify API shared by reCAPTCHA,
+// hCaptcha and Turnstile, or returns nil when no secret is configured
+func NewCaptchaVerifier(cfg *config.LeadsConfig) CaptchaVerifier {
+	if cfg.CaptchaSecret == "" {
+		return nil
+	}
+	verifyURL := cfg.CaptchaVerifyURL
+	if verifyURL == "" {
+		verifyURL = defaultCaptchaVerifyURL
+	}
+	return &siteVerifyCaptcha{
+		secret:    cfg.CaptchaSecret,
+		verifyURL: verifyURL,
+		http:      httpclient.New("captcha", httpclient.WithTimeout(captchaTimeout)),
+	}
+}
+
+// Verify returns ErrInvalidCaptcha when the provider refuses token
+func (v *siteVerifyCaptcha) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return ErrInvalidCaptcha
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	ctx, cancel := context.WithTimeout(ctx, captchaTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to verify captcha: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return &httpclient.StatusError{Service: "captcha", StatusCode: resp.StatusCode}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return fmt.Errorf("failed to read captcha response: %w", err)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := v.http.Decode(ctx, v.verifyURL, body, &result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return ErrInvalidCaptcha
+	}
+	return nil
+}
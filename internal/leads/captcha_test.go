@@ -0,0 +1,37 @@
+package leads
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func TestCaptchaVerifier(t *testing.T) {
+	assert.Nil(t, NewCaptchaVerifier(&config.LeadsConfig{}), "disabled without a secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret-1", r.PostForm.Get("secret"))
+		assert.Equal(t, "192.0.2.10", r.PostForm.Get("remoteip"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("response") == "valid" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer server.Close()
+
+	verifier := NewCaptchaVerifier(&config.LeadsConfig{CaptchaSecret: "secret-1", CaptchaVerifyURL: server.URL})
+	ctx := context.Background()
+
+	assert.NoError(t, verifier.Verify(ctx, "valid", "192.0.2.10"))
+	assert.ErrorIs(t, verifier.Verify(ctx, "forged", "192.0.2.10"), ErrInvalidCaptcha)
+	assert.ErrorIs(t, verifier.Verify(ctx, "", "192.0.2.10"), ErrInvalidCaptcha, "missing response")
+}
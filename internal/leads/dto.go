@@ -0,0 +1,26 @@
+package leads
+
+// CreateLeadRequest is a contact request left on the public page of a property. CaptchaToken is
+// the response of the captcha widget, required when leads.captcha_secret is configured.
+type CreateLeadRequest struct {
+	Nome         string `json:"nome" binding:"required,max=255"`
+	Email        string `json:"email" binding:"required,email,max=255"`
+	Telefone     string `json:"telefone" binding:"omitempty,max=30"`
+	Mensagem     string `json:"mensagem" binding:"omitempty,max=2000"`
+	CaptchaToken string `json:"captcha_token"`
+}
+
+// UpdateLeadRequest moves a lead forward in the pipeline and/or replaces the notes of the corretor
+type UpdateLeadRequest struct {
+	Status      string  `json:"status" binding:"omitempty,oneof=NOVO CONTATADO VISITOU PROPOSTA FECHADO"`
+	Observacoes *string `json:"observacoes" binding:"omitempty,max=5000"`
+}
+
+// ListQuery represents the filters of the lead list
+type ListQuery struct {
+	Status     string `form:"status" binding:"omitempty,oneof=NOVO CONTATADO VISITOU PROPOSTA FECHADO"`
+	ImovelID   uint   `form:"imovel_id"`
+	CorretorID uint   `form:"corretor_id"`
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	Limit      int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
@@ -0,0 +1,171 @@
+package leads
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for leads
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new lead handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type leadURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Request contact about a property
+// @Description Leave a contact request on a published property. Its corretor principal (or, without one, the admins) is notified by email. Rate limited per IP by leads.rate_limit_requests; captcha_token is required when a captcha is configured.
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param id path uint true "Property ID"
+// @Param request body CreateLeadRequest true "Contact request"
+// @Success 201 {object} errors.Response{success=bool,data=Lead}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error or invalid captcha"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Property not found or not published"
+// @Failure 429 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Too many requests"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/leads [post]
+func (h *Handler) CreateLead(c *gin.Context) {
+	var uri leadURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	var req CreateLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	lead, err := h.service.CreateLead(c.Request.Context(), uri.ID, &req, c.ClientIP())
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(lead))
+}
+
+// @Summary List leads
+// @Description Contact requests, newest first. Corretor users only see the leads of their own properties.
+// @Tags leads
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status (NOVO, CONTATADO, VISITOU, PROPOSTA, FECHADO)"
+// @Param imovel_id query int false "Property ID"
+// @Param corretor_id query int false "Corretor principal ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[Lead]}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/leads [get]
+func (h *Handler) ListLeads(c *gin.Context) {
+	var query ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListLeads(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get a lead
+// @Tags leads
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Lead ID"
+// @Success 200 {object} errors.Response{success=bool,data=Lead}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Lead of another corretor"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/leads/{id} [get]
+func (h *Handler) GetLead(c *gin.Context) {
+	var uri leadURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	lead, err := h.service.GetLead(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(lead))
+}
+
+// @Summary Update a lead
+// @Description Move a lead forward in the pipeline (NOVO → CONTATADO → VISITOU → PROPOSTA → FECHADO, steps may be skipped) and/or replace the notes of the corretor
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Lead ID"
+// @Param request body UpdateLeadRequest true "New status and/or notes"
+// @Success 200 {object} errors.Response{success=bool,data=Lead}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Lead of another corretor"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Backward status transition"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/leads/{id} [patch]
+func (h *Handler) UpdateLead(c *gin.Context) {
+	var uri leadURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+	var req UpdateLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	lead, err := h.service.UpdateLead(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(lead))
+}
+
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrLeadNotFound):
+		_ = c.Error(apiErrors.NotFound("Lead not found"))
+	case errors.Is(err, ErrImovelNotFound):
+		_ = c.Error(apiErrors.NotFound("Property not found"))
+	case errors.Is(err, ErrInvalidCaptcha):
+		_ = c.Error(apiErrors.BadRequest("Invalid captcha"))
+	case errors.Is(err, ErrForbidden):
+		_ = c.Error(apiErrors.Forbidden(err.Error()))
+	case errors.Is(err, ErrInvalidTransition):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
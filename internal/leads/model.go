@@ -0,0 +1,40 @@
+// Package leads captures the contact requests left on the public page of a property and follows
+// them through the sales pipeline of its corretor principal.
+package leads
+
+import "time"
+
+// Lead pipeline statuses
+const (
+	StatusNovo      = "NOVO"
+	StatusContatado = "CONTATADO"
+	StatusVisitou   = "VISITOU"
+	StatusProposta  = "PROPOSTA"
+	StatusFechado   = "FECHADO"
+)
+
+// pipeline is the order leads move through: NOVO → CONTATADO → VISITOU → PROPOSTA → FECHADO.
+// Leads move forward only, possibly skipping steps (a lead may close without a visit).
+var pipeline = []string{StatusNovo, StatusContatado, StatusVisitou, StatusProposta, StatusFechado}
+
+// Lead is a request to be contacted about a property. CorretorPrincipalID is the corretor of the
+// property when the lead was left, whom it is assigned to.
+type Lead struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	ImovelID            uint      `gorm:"not null;index" json:"imovel_id"`
+	CorretorPrincipalID *uint     `gorm:"index" json:"corretor_principal_id,omitempty"`
+	Nome                string    `gorm:"size:255;not null" json:"nome"`
+	Email               string    `gorm:"size:255;not null" json:"email"`
+	Telefone            string    `gorm:"size:30" json:"telefone,omitempty"`
+	Mensagem            string    `gorm:"type:text" json:"mensagem,omitempty"`
+	Status              string    `gorm:"size:20;not null;default:NOVO;index" json:"status"`
+	Observacoes         string    `gorm:"type:text" json:"observacoes,omitempty"` // notes of the corretor
+	IPAddress           string    `gorm:"size:45" json:"-"`
+	CreatedAt           time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Lead model
+func (Lead) TableName() string {
+	return "leads"
+}
@@ -0,0 +1,107 @@
+package leads
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// ImovelRef is the published property a lead is left on
+type ImovelRef struct {
+	ID                  uint
+	CorretorPrincipalID *uint
+}
+
+// Repository defines lead repository interface
+type Repository interface {
+	Create(ctx context.Context, lead *Lead) error
+	FindByID(ctx context.Context, id uint) (*Lead, error)
+	Update(ctx context.Context, lead *Lead) error
+	// List returns one page of the leads matching query, newest first, with their total. A non-nil
+	// corretorID limits them to the leads of that corretor principal.
+	List(ctx context.Context, query *ListQuery, corretorID *uint) ([]Lead, int64, error)
+	// FindPublishedImovel returns the published property id, or nil when there is none
+	FindPublishedImovel(ctx context.Context, id uint) (*ImovelRef, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new lead repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create stores a new lead
+func (r *repository) Create(ctx context.Context, lead *Lead) error {
+	return r.db.WithContext(ctx).Create(lead).Error
+}
+
+// FindByID returns the lead, or nil when there is none
+func (r *repository) FindByID(ctx context.Context, id uint) (*Lead, error) {
+	var lead Lead
+	err := r.db.WithContext(ctx).First(&lead, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lead, nil
+}
+
+// Update saves the status and notes of the lead
+func (r *repository) Update(ctx context.Context, lead *Lead) error {
+	return r.db.WithContext(ctx).Model(lead).Select("status", "observacoes", "updated_at").Updates(lead).Error
+}
+
+// List returns one page of the leads matching query, newest first, with their total
+func (r *repository) List(ctx context.Context, query *ListQuery, corretorID *uint) ([]Lead, int64, error) {
+	db := r.db.WithContext(ctx).Model(&Lead{})
+	if corretorID != nil {
+		db = db.Where("corretor_principal_id = ?", *corretorID)
+	}
+	if query.CorretorID != 0 {
+		db = db.Where("corretor_principal_id = ?", query.CorretorID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.ImovelID != 0 {
+		db = db.Where("imovel_id = ?", query.ImovelID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var leads []Lead
+	err := db.Order("created_at DESC, id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&leads).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return leads, total, nil
+}
+
+// FindPublishedImovel returns the published property id, or nil when there is none
+func (r *repository) FindPublishedImovel(ctx context.Context, id uint) (*ImovelRef, error) {
+	var refs []ImovelRef
+	err := r.db.WithContext(ctx).
+		Table("imoveis").
+		Select("id, NULLIF(corretor_principal_id, 0) AS corretor_principal_id").
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, imoveis.StatusPublicado).
+		Limit(1).
+		Scan(&refs).Error
+	if err != nil || len(refs) == 0 {
+		return nil, err
+	}
+	return &refs[0], nil
+}
@@ -0,0 +1,178 @@
+package leads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
+)
+
+var (
+	// ErrLeadNotFound is returned when a lead is not found
+	ErrLeadNotFound = errors.New("lead not found")
+	// ErrImovelNotFound is returned when leaving a lead on a property that is not published
+	ErrImovelNotFound = errors.New("property not found")
+	// ErrInvalidTransition is returned when moving a lead backwards in the pipeline
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrForbidden is returned when a corretor user reaches the lead of another corretor
+	ErrForbidden = errors.New("lead belongs to another corretor")
+)
+
+// Service defines lead service interface
+type Service interface {
+	CreateLead(ctx context.Context, imovelID uint, req *CreateLeadRequest, remoteIP string) (*Lead, error)
+	ListLeads(ctx context.Context, query *ListQuery) (*pagination.Page[Lead], error)
+	GetLead(ctx context.Context, id uint) (*Lead, error)
+	UpdateLead(ctx context.Context, id uint, req *UpdateLeadRequest) (*Lead, error)
+}
+
+type service struct {
+	repo    Repository
+	captcha CaptchaVerifier
+	events  events.Publisher
+}
+
+// ServiceOption configures optional service behaviour
+type ServiceOption func(*service)
+
+// WithCaptcha requires the leads to carry a captcha response accepted by verifier
+func WithCaptcha(verifier CaptchaVerifier) ServiceOption {
+	return func(s *service) {
+		s.captcha = verifier
+	}
+}
+
+// WithEvents publishes the new leads to publisher, which notifies the corretor of the property
+func WithEvents(publisher events.Publisher) ServiceOption {
+	return func(s *service) {
+		s.events = publisher
+	}
+}
+
+// NewService creates a new lead service
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateLead stores a contact request about a published property, assigned to its corretor
+// principal, and publishes it
+func (s *service) CreateLead(ctx context.Context, imovelID uint, req *CreateLeadRequest, remoteIP string) (*Lead, error) {
+	if s.captcha != nil {
+		if err := s.captcha.Verify(ctx, req.CaptchaToken, remoteIP); err != nil {
+			return nil, err
+		}
+	}
+
+	imovel, err := s.repo.FindPublishedImovel(ctx, imovelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+
+	lead := &Lead{
+		ImovelID:            imovel.ID,
+		CorretorPrincipalID: imovel.CorretorPrincipalID,
+		Nome:                strings.TrimSpace(req.Nome),
+		Email:               strings.TrimSpace(req.Email),
+		Telefone:            strings.TrimSpace(req.Telefone),
+		Mensagem:            strings.TrimSpace(req.Mensagem),
+		Status:              StatusNovo,
+		IPAddress:           remoteIP,
+	}
+	if err := s.repo.Create(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	if s.events != nil {
+		s.events.Publish(events.LeadCreated{
+			LeadID:   lead.ID,
+			ImovelID: lead.ImovelID,
+			Nome:     lead.Nome,
+			Email:    lead.Email,
+			Telefone: lead.Telefone,
+			Mensagem: lead.Mensagem,
+		})
+	}
+	return lead, nil
+}
+
+// ListLeads returns one page of the leads matching query, newest first. Corretor users only see
+// their own leads.
+func (s *service) ListLeads(ctx context.Context, query *ListQuery) (*pagination.Page[Lead], error) {
+	var corretorID *uint
+	if scoped, ok := contextutil.CorretorScopeFromContext(ctx); ok {
+		corretorID = &scoped
+	}
+	leads, total, err := s.repo.List(ctx, query, corretorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leads: %w", err)
+	}
+	return pagination.New(leads, total, query.Page, query.Limit), nil
+}
+
+// GetLead returns the lead; corretor users only reach their own
+func (s *service) GetLead(ctx context.Context, id uint) (*Lead, error) {
+	lead, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lead: %w", err)
+	}
+	if lead == nil {
+		return nil, ErrLeadNotFound
+	}
+	if err := checkCorretorScope(ctx, lead); err != nil {
+		return nil, err
+	}
+	return lead, nil
+}
+
+// UpdateLead moves the lead forward in the pipeline and/or replaces its notes
+func (s *service) UpdateLead(ctx context.Context, id uint, req *UpdateLeadRequest) (*Lead, error) {
+	lead, err := s.GetLead(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != "" && req.Status != lead.Status {
+		if err := checkTransition(lead.Status, req.Status); err != nil {
+			return nil, err
+		}
+		lead.Status = req.Status
+	}
+	if req.Observacoes != nil {
+		lead.Observacoes = strings.TrimSpace(*req.Observacoes)
+	}
+
+	if err := s.repo.Update(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to update lead: %w", err)
+	}
+	return lead, nil
+}
+
+// checkCorretorScope returns ErrForbidden when ctx is limited to the leads of another corretor
+// principal (corretor users, see contextutil.WithCorretorScope). Unscoped requests pass.
+func checkCorretorScope(ctx context.Context, lead *Lead) error {
+	corretorID, scoped := contextutil.CorretorScopeFromContext(ctx)
+	if scoped && (lead.CorretorPrincipalID == nil || corretorID == 0 || *lead.CorretorPrincipalID != corretorID) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// checkTransition allows moving a lead forward in the pipeline only
+func checkTransition(from, to string) error {
+	if slices.Index(pipeline, to) > slices.Index(pipeline, from) {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidTransition, from, to)
+}
@@ -0,0 +1,150 @@
+package leads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+type recordingPublisher struct {
+	published []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.published = append(p.published, event)
+}
+
+type fakeCaptcha struct {
+	err error
+}
+
+func (f fakeCaptcha) Verify(ctx context.Context, token, remoteIP string) error {
+	return f.err
+}
+
+func setupService(t *testing.T, opts ...ServiceOption) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&Lead{}))
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY,
+		status TEXT,
+		corretor_principal_id INTEGER,
+		deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, status, corretor_principal_id, deleted_at) VALUES
+		(1, 'PUBLICADO', 7, NULL),
+		(2, 'PUBLICADO', 0, NULL),
+		(3, 'EM_EDICAO', 7, NULL),
+		(4, 'PUBLICADO', 7, '2026-01-01 00:00:00')`).Error)
+
+	return NewService(NewRepository(database), opts...), database
+}
+
+func newLead() *CreateLeadRequest {
+	return &CreateLeadRequest{Nome: " João ", Email: "joao@example.com", Telefone: "11 99999-0000", Mensagem: "Posso visitar sábado?"}
+}
+
+func TestCreateLead(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, _ := setupService(t, WithEvents(publisher))
+	ctx := context.Background()
+
+	lead, err := svc.CreateLead(ctx, 1, newLead(), "192.0.2.10")
+	require.NoError(t, err)
+	assert.Equal(t, StatusNovo, lead.Status)
+	assert.Equal(t, "João", lead.Nome)
+	require.NotNil(t, lead.CorretorPrincipalID)
+	assert.Equal(t, uint(7), *lead.CorretorPrincipalID, "assigned to the corretor of the property")
+	assert.Equal(t, []events.Event{events.LeadCreated{
+		LeadID: lead.ID, ImovelID: 1, Nome: "João", Email: "joao@example.com", Telefone: "11 99999-0000", Mensagem: "Posso visitar sábado?",
+	}}, publisher.published)
+
+	lead, err = svc.CreateLead(ctx, 2, newLead(), "192.0.2.10")
+	require.NoError(t, err)
+	assert.Nil(t, lead.CorretorPrincipalID, "properties without corretor leave the lead unassigned")
+
+	for _, imovelID := range []uint{3, 4, 99} {
+		_, err := svc.CreateLead(ctx, imovelID, newLead(), "192.0.2.10")
+		assert.ErrorIs(t, err, ErrImovelNotFound, "imovel %d", imovelID)
+	}
+	assert.Len(t, publisher.published, 2)
+}
+
+func TestCreateLead_Captcha(t *testing.T) {
+	svc, database := setupService(t, WithCaptcha(fakeCaptcha{err: ErrInvalidCaptcha}))
+
+	_, err := svc.CreateLead(context.Background(), 1, newLead(), "192.0.2.10")
+	assert.ErrorIs(t, err, ErrInvalidCaptcha)
+
+	var count int64
+	require.NoError(t, database.Model(&Lead{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestListLeads_CorretorScope(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateLead(ctx, 1, newLead(), "")
+	require.NoError(t, err)
+	other, err := svc.CreateLead(ctx, 2, newLead(), "")
+	require.NoError(t, err)
+
+	page, err := svc.ListLeads(ctx, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	scoped := contextutil.WithCorretorScope(ctx, 7)
+	page, err = svc.ListLeads(scoped, &ListQuery{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, uint(1), page.Results[0].ImovelID)
+
+	_, err = svc.GetLead(scoped, other.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = svc.GetLead(ctx, 99)
+	assert.ErrorIs(t, err, ErrLeadNotFound)
+
+	page, err = svc.ListLeads(ctx, &ListQuery{Status: StatusFechado, Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+}
+
+func TestUpdateLead_Pipeline(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	lead, err := svc.CreateLead(ctx, 1, newLead(), "")
+	require.NoError(t, err)
+
+	notes := " Ligar depois das 18h "
+	lead, err = svc.UpdateLead(ctx, lead.ID, &UpdateLeadRequest{Status: StatusContatado, Observacoes: &notes})
+	require.NoError(t, err)
+	assert.Equal(t, StatusContatado, lead.Status)
+	assert.Equal(t, "Ligar depois das 18h", lead.Observacoes)
+
+	lead, err = svc.UpdateLead(ctx, lead.ID, &UpdateLeadRequest{Status: StatusProposta})
+	require.NoError(t, err, "steps may be skipped")
+
+	_, err = svc.UpdateLead(ctx, lead.ID, &UpdateLeadRequest{Status: StatusVisitou})
+	assert.True(t, errors.Is(err, ErrInvalidTransition), "leads do not move backwards")
+
+	stored, err := svc.GetLead(ctx, lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusProposta, stored.Status)
+	assert.Equal(t, "Ligar depois das 18h", stored.Observacoes, "notes are kept when not sent")
+
+	_, err = svc.UpdateLead(contextutil.WithCorretorScope(ctx, 8), lead.ID, &UpdateLeadRequest{Status: StatusFechado})
+	assert.ErrorIs(t, err, ErrForbidden)
+}
@@ -0,0 +1,46 @@
+package maintenance
+
+import "time"
+
+// JobStatus represents the lifecycle state of a maintenance job or task
+type JobStatus string
+
+const (
+	StatusQueued    JobStatus = "queued"
+	StatusRunning   JobStatus = "running"
+	StatusCompleted JobStatus = "completed"
+	StatusFailed    JobStatus = "failed"
+)
+
+// ReindexRequest selects which maintenance tasks to run; when empty, all registered tasks run
+// except the explicit ones (those that archive or delete data), which must be named
+type ReindexRequest struct {
+	Tasks []string `json:"tasks" binding:"omitempty,dive,min=1,max=100"`
+}
+
+// TaskProgress reports the progress of a single task within a job
+type TaskProgress struct {
+	Name       string     `json:"name"`
+	Status     JobStatus  `json:"status"`
+	Done       int        `json:"done"`
+	Total      int        `json:"total"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobResponse represents a maintenance job and the progress of its tasks
+type JobResponse struct {
+	ID          string         `json:"id"`
+	Status      JobStatus      `json:"status"`
+	RequestedBy uint           `json:"requested_by"`
+	Tasks       []TaskProgress `json:"tasks"`
+	CreatedAt   time.Time      `json:"created_at"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+}
+
+// TasksResponse lists the maintenance tasks available on this instance
+type TasksResponse struct {
+	Tasks []string `json:"tasks"`
+}
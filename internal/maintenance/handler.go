@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler handles maintenance-related HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new maintenance handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Reindex godoc
+// @Summary Start reindex and cache rebuild
+// @Description Run maintenance tasks (reindex, analyze, analytics refresh, cache flush and warm-up...) as a background job. Runs all registered tasks when none are specified, except tasks that archive or delete data (archive_stale, purge_sliders), which run only when named.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ReindexRequest false "Tasks to run"
+// @Success 202 {object} errors.Response{success=bool,data=JobResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/maintenance/reindex [post]
+func (h *Handler) Reindex(c *gin.Context) {
+	var req ReindexRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(apiErrors.FromGinValidation(err))
+			return
+		}
+	}
+
+	job, err := h.service.StartReindex(c.Request.Context(), req, contextutil.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, ErrUnknownTask) {
+			_ = c.Error(apiErrors.BadRequest(fmt.Sprintf("%s (available: %s)", err.Error(), strings.Join(h.service.ListTasks(), ", "))))
+			return
+		}
+		if errors.Is(err, ErrJobRunning) {
+			_ = c.Error(apiErrors.Conflict("A maintenance job is already running"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, apiErrors.Success(job))
+}
+
+// GetJob godoc
+// @Summary Get maintenance job progress
+// @Description Retrieve the status and per-task progress of a maintenance job
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} errors.Response{success=bool,data=JobResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/maintenance/jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	job, err := h.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			_ = c.Error(apiErrors.NotFound("Maintenance job not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(job))
+}
+
+// ListTasks godoc
+// @Summary List maintenance tasks
+// @Description List the maintenance tasks that can be triggered on this instance
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=TasksResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/maintenance/tasks [get]
+func (h *Handler) ListTasks(c *gin.Context) {
+	c.JSON(http.StatusOK, apiErrors.Success(TasksResponse{Tasks: h.service.ListTasks()}))
+}
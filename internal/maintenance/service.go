@@ -0,0 +1,228 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrJobNotFound is returned when a maintenance job does not exist
+	ErrJobNotFound = errors.New("maintenance job not found")
+	// ErrJobRunning is returned when a maintenance job is already in progress
+	ErrJobRunning = errors.New("a maintenance job is already running")
+	// ErrUnknownTask is returned when a requested task is not registered
+	ErrUnknownTask = errors.New("unknown maintenance task")
+)
+
+// maxRetainedJobs bounds how many finished jobs are kept in memory for progress lookups
+const maxRetainedJobs = 50
+
+// Service defines maintenance service interface
+type Service interface {
+	StartReindex(ctx context.Context, req ReindexRequest, requestedBy uint) (*JobResponse, error)
+	GetJob(ctx context.Context, id string) (*JobResponse, error)
+	ListTasks() []string
+}
+
+type service struct {
+	tasks map[string]Task
+	order []string
+
+	mu      sync.Mutex
+	jobs    map[string]*JobResponse
+	history []string
+	running string
+}
+
+// NewService creates a maintenance service with the given tasks, run in registration order
+func NewService(tasks ...Task) Service {
+	s := &service{
+		tasks: make(map[string]Task, len(tasks)),
+		jobs:  make(map[string]*JobResponse),
+	}
+	for _, task := range tasks {
+		if _, exists := s.tasks[task.Name()]; exists {
+			continue
+		}
+		s.tasks[task.Name()] = task
+		s.order = append(s.order, task.Name())
+	}
+	return s
+}
+
+// ListTasks returns the registered task names
+func (s *service) ListTasks() []string {
+	names := make([]string, len(s.order))
+	copy(names, s.order)
+	return names
+}
+
+// StartReindex validates the requested tasks and runs them sequentially in the background
+func (s *service) StartReindex(ctx context.Context, req ReindexRequest, requestedBy uint) (*JobResponse, error) {
+	selected, err := s.selectTasks(req.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.running != "" {
+		s.mu.Unlock()
+		return nil, ErrJobRunning
+	}
+
+	job := &JobResponse{
+		ID:          uuid.NewString(),
+		Status:      StatusQueued,
+		RequestedBy: requestedBy,
+		Tasks:       make([]TaskProgress, len(selected)),
+		CreatedAt:   time.Now().UTC(),
+	}
+	for i, task := range selected {
+		job.Tasks[i] = TaskProgress{Name: task.Name(), Status: StatusQueued}
+	}
+
+	s.jobs[job.ID] = job
+	s.history = append(s.history, job.ID)
+	s.running = job.ID
+	s.pruneLocked()
+	snapshot := cloneJob(job)
+	s.mu.Unlock()
+
+	go s.run(job.ID, selected)
+
+	return snapshot, nil
+}
+
+// GetJob returns a snapshot of the job progress
+func (s *service) GetJob(ctx context.Context, id string) (*JobResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return cloneJob(job), nil
+}
+
+// selectTasks resolves the requested tasks; when none are named it selects every registered task
+// except the explicit ones
+func (s *service) selectTasks(names []string) ([]Task, error) {
+	if len(names) == 0 {
+		for _, name := range s.order {
+			if !runsOnlyWhenNamed(s.tasks[name]) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	selected := make([]Task, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		task, ok := s.tasks[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTask, name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		selected = append(selected, task)
+	}
+	return selected, nil
+}
+
+func runsOnlyWhenNamed(task Task) bool {
+	explicit, ok := task.(ExplicitTask)
+	return ok && explicit.RunOnlyWhenNamed()
+}
+
+func (s *service) run(jobID string, tasks []Task) {
+	// Jobs outlive the HTTP request that started them
+	ctx := context.Background()
+
+	s.update(jobID, func(job *JobResponse) {
+		now := time.Now().UTC()
+		job.Status = StatusRunning
+		job.StartedAt = &now
+	})
+
+	failed := false
+	for i, task := range tasks {
+		s.update(jobID, func(job *JobResponse) {
+			now := time.Now().UTC()
+			job.Tasks[i].Status = StatusRunning
+			job.Tasks[i].StartedAt = &now
+		})
+
+		err := task.Run(ctx, func(done, total int) {
+			s.update(jobID, func(job *JobResponse) {
+				job.Tasks[i].Done = done
+				job.Tasks[i].Total = total
+			})
+		})
+
+		s.update(jobID, func(job *JobResponse) {
+			now := time.Now().UTC()
+			job.Tasks[i].FinishedAt = &now
+			if err != nil {
+				job.Tasks[i].Status = StatusFailed
+				job.Tasks[i].Error = err.Error()
+				return
+			}
+			job.Tasks[i].Status = StatusCompleted
+		})
+
+		if err != nil {
+			failed = true
+			slog.Error("Maintenance task failed", "job_id", jobID, "task", task.Name(), "error", err)
+		}
+	}
+
+	s.update(jobID, func(job *JobResponse) {
+		now := time.Now().UTC()
+		job.FinishedAt = &now
+		job.Status = StatusCompleted
+		if failed {
+			job.Status = StatusFailed
+		}
+		s.running = ""
+	})
+
+	slog.Info("Maintenance job finished", "job_id", jobID, "failed", failed)
+}
+
+// update applies fn to the job while holding the service lock
+func (s *service) update(jobID string, fn func(job *JobResponse)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+// pruneLocked drops the oldest finished jobs beyond maxRetainedJobs. Callers must hold s.mu.
+func (s *service) pruneLocked() {
+	for len(s.history) > maxRetainedJobs {
+		oldest := s.history[0]
+		if oldest == s.running {
+			return
+		}
+		delete(s.jobs, oldest)
+		s.history = s.history[1:]
+	}
+}
+
+func cloneJob(job *JobResponse) *JobResponse {
+	clone := *job
+	clone.Tasks = make([]TaskProgress, len(job.Tasks))
+	copy(clone.Tasks, job.Tasks)
+	return &clone
+}
@@ -0,0 +1,150 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func waitForJob(t *testing.T, svc Service, id string) *JobResponse {
+	t.Helper()
+
+	var job *JobResponse
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.GetJob(context.Background(), id)
+		require.NoError(t, err)
+		return job.FinishedAt != nil
+	}, time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+func TestService_StartReindex_RunsAllTasks(t *testing.T) {
+	var ran []string
+	svc := NewService(
+		TaskFunc{TaskName: "search", Fn: func(ctx context.Context, report ProgressFunc) error {
+			ran = append(ran, "search")
+			report(3, 3)
+			return nil
+		}},
+		TaskFunc{TaskName: "sitemap", Fn: func(ctx context.Context, report ProgressFunc) error {
+			ran = append(ran, "sitemap")
+			return nil
+		}},
+	)
+
+	job, err := svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	require.NoError(t, err)
+	assert.Len(t, job.Tasks, 2)
+
+	finished := waitForJob(t, svc, job.ID)
+
+	assert.Equal(t, StatusCompleted, finished.Status)
+	assert.Equal(t, []string{"search", "sitemap"}, ran)
+	assert.Equal(t, 3, finished.Tasks[0].Done)
+	assert.Equal(t, 3, finished.Tasks[0].Total)
+	assert.Equal(t, uint(1), finished.RequestedBy)
+}
+
+func TestService_StartReindex_TaskFailure(t *testing.T) {
+	svc := NewService(
+		TaskFunc{TaskName: "search", Fn: func(ctx context.Context, report ProgressFunc) error {
+			return errors.New("index unavailable")
+		}},
+		TaskFunc{TaskName: "caches", Fn: func(ctx context.Context, report ProgressFunc) error {
+			return nil
+		}},
+	)
+
+	job, err := svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	require.NoError(t, err)
+
+	finished := waitForJob(t, svc, job.ID)
+
+	assert.Equal(t, StatusFailed, finished.Status)
+	assert.Equal(t, StatusFailed, finished.Tasks[0].Status)
+	assert.Equal(t, "index unavailable", finished.Tasks[0].Error)
+	assert.Equal(t, StatusCompleted, finished.Tasks[1].Status)
+}
+
+func TestService_StartReindex_UnknownTask(t *testing.T) {
+	svc := NewService(TaskFunc{TaskName: "caches", Fn: func(ctx context.Context, report ProgressFunc) error { return nil }})
+
+	_, err := svc.StartReindex(context.Background(), ReindexRequest{Tasks: []string{"feeds"}}, 1)
+
+	assert.ErrorIs(t, err, ErrUnknownTask)
+}
+
+func TestService_StartReindex_RejectsConcurrentJobs(t *testing.T) {
+	release := make(chan struct{})
+	svc := NewService(TaskFunc{TaskName: "slow", Fn: func(ctx context.Context, report ProgressFunc) error {
+		<-release
+		return nil
+	}})
+
+	job, err := svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	require.NoError(t, err)
+
+	_, err = svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	assert.ErrorIs(t, err, ErrJobRunning)
+
+	close(release)
+	waitForJob(t, svc, job.ID)
+
+	_, err = svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	assert.NoError(t, err)
+}
+
+func TestService_GetJob_NotFound(t *testing.T) {
+	svc := NewService()
+
+	_, err := svc.GetJob(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestService_StartReindex_ExplicitTasksRunOnlyWhenNamed(t *testing.T) {
+	var ran []string
+	task := func(name string, explicit bool) TaskFunc {
+		return TaskFunc{TaskName: name, Explicit: explicit, Fn: func(ctx context.Context, report ProgressFunc) error {
+			ran = append(ran, name)
+			return nil
+		}}
+	}
+	svc := NewService(task("analyze", false), task("archive_stale", true))
+
+	job, err := svc.StartReindex(context.Background(), ReindexRequest{}, 1)
+	require.NoError(t, err)
+	waitForJob(t, svc, job.ID)
+	assert.Equal(t, []string{"analyze"}, ran)
+
+	ran = nil
+	job, err = svc.StartReindex(context.Background(), ReindexRequest{Tasks: []string{"archive_stale"}}, 1)
+	require.NoError(t, err)
+	waitForJob(t, svc, job.ID)
+	assert.Equal(t, []string{"archive_stale"}, ran)
+	assert.Equal(t, []string{"analyze", "archive_stale"}, svc.ListTasks())
+}
+
+func TestNewReindexTask(t *testing.T) {
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.Exec("CREATE TABLE imoveis (id INTEGER PRIMARY KEY, codigo TEXT)").Error)
+	require.NoError(t, database.Exec("CREATE INDEX idx_imoveis_codigo ON imoveis (codigo)").Error)
+
+	var progress [][2]int
+	task := NewReindexTask(database, "imoveis")
+	assert.Equal(t, "reindex", task.Name())
+	require.NoError(t, task.Run(context.Background(), func(done, total int) { progress = append(progress, [2]int{done, total}) }))
+	assert.Equal(t, [][2]int{{1, 1}}, progress)
+
+	err = NewReindexTask(database, "missing").Run(context.Background(), func(done, total int) {})
+	assert.ErrorContains(t, err, "failed to reindex missing")
+}
@@ -0,0 +1,83 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ProgressFunc reports how many units of work a task has completed out of total
+type ProgressFunc func(done, total int)
+
+// Task is a unit of maintenance work (index rebuild, statistics refresh, cache warm-up...)
+// that modules register so it can be triggered from the admin API
+type Task interface {
+	Name() string
+	Run(ctx context.Context, report ProgressFunc) error
+}
+
+// ExplicitTask is implemented by tasks that change data (archiving, deleting...), which run only
+// when named in the request and never as part of the default "run everything" selection
+type ExplicitTask interface {
+	Task
+	RunOnlyWhenNamed() bool
+}
+
+// TaskFunc adapts a function into a Task. Explicit tasks run only when named in the request.
+type TaskFunc struct {
+	TaskName string
+	Fn       func(ctx context.Context, report ProgressFunc) error
+	Explicit bool
+}
+
+func (t TaskFunc) Name() string {
+	return t.TaskName
+}
+
+func (t TaskFunc) RunOnlyWhenNamed() bool {
+	return t.Explicit
+}
+
+func (t TaskFunc) Run(ctx context.Context, report ProgressFunc) error {
+	return t.Fn(ctx, report)
+}
+
+// NewAnalyzeTask refreshes the Postgres planner statistics of the given tables,
+// which keeps listing queries fast after bulk data fixes
+func NewAnalyzeTask(db *gorm.DB, tables ...string) Task {
+	return TaskFunc{
+		TaskName: "analyze",
+		Fn: func(ctx context.Context, report ProgressFunc) error {
+			for i, table := range tables {
+				if err := db.WithContext(ctx).Exec(fmt.Sprintf("ANALYZE %s", table)).Error; err != nil {
+					return fmt.Errorf("failed to analyze %s: %w", table, err)
+				}
+				report(i+1, len(tables))
+			}
+			return nil
+		},
+	}
+}
+
+// NewReindexTask rebuilds the indexes of the given tables, which the listing and search queries
+// rely on, after bulk data fixes left them bloated. On Postgres the rebuild runs concurrently so
+// the tables stay writable.
+func NewReindexTask(db *gorm.DB, tables ...string) Task {
+	statement := "REINDEX %s"
+	if db.Dialector.Name() == "postgres" {
+		statement = "REINDEX TABLE CONCURRENTLY %s"
+	}
+	return TaskFunc{
+		TaskName: "reindex",
+		Fn: func(ctx context.Context, report ProgressFunc) error {
+			for i, table := range tables {
+				if err := db.WithContext(ctx).Exec(fmt.Sprintf(statement, table)).Error; err != nil {
+					return fmt.Errorf("failed to reindex %s: %w", table, err)
+				}
+				report(i+1, len(tables))
+			}
+			return nil
+		},
+	}
+}
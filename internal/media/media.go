@@ -0,0 +1,112 @@
+// Package media decodes uploaded images and produces the resized renditions served to clients.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Formats accepted as rendition sources
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxSourcePixels bounds the decoded size of a source image, guarding against decompression bombs
+const MaxSourcePixels = 50_000_000
+
+// JPEGQuality is used for the JPEG renditions
+const JPEGQuality = 82
+
+// ErrImageTooLarge is returned when a source image exceeds MaxSourcePixels
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// Rendition is a named target size. Images are scaled down to MaxWidth keeping the aspect ratio
+// and are never scaled up.
+type Rendition struct {
+	Name     string
+	MaxWidth int
+}
+
+// DefaultRenditions are generated for every image anexo
+var DefaultRenditions = []Rendition{
+	{Name: "thumbnail", MaxWidth: 320},
+	{Name: "medium", MaxWidth: 800},
+	{Name: "large", MaxWidth: 1600},
+}
+
+// Decode reads a JPEG, PNG, GIF or WebP image after checking its dimensions
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxSourcePixels {
+		return nil, fmt.Errorf("%w (%dx%d)", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// Resize scales img down to maxWidth with a Catmull-Rom filter. Smaller images are copied unchanged.
+func Resize(img image.Image, maxWidth int) *image.NRGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if maxWidth > 0 && width > maxWidth {
+		height = max(1, height*maxWidth/width)
+		width = maxWidth
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+		return dst
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// EncodeJPEG writes img as a JPEG. JPEG has no alpha channel, so transparent areas become white.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, img, bounds.Min, draw.Over)
+	return jpeg.Encode(w, flat, &jpeg.Options{Quality: JPEGQuality})
+}
+
+// EncodedRendition holds the files of a rendition: the JPEG, and the WebP when it is smaller
+type EncodedRendition struct {
+	JPEG []byte
+	// WebP is nil when the lossless WebP encoding is not smaller than the JPEG
+	WebP []byte
+}
+
+// EncodeRendition encodes img as JPEG and as lossless WebP, keeping the WebP only when it is
+// smaller than the JPEG so that serving it never costs clients more bandwidth. WebP wins on
+// floor plans and other flat graphics; photos end up served as JPEG only.
+func EncodeRendition(img image.Image) (*EncodedRendition, error) {
+	var jpegBuf, webpBuf bytes.Buffer
+	if err := EncodeJPEG(&jpegBuf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+	}
+	if err := EncodeWebP(&webpBuf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode webp: %w", err)
+	}
+
+	encoded := &EncodedRendition{JPEG: jpegBuf.Bytes()}
+	if webpBuf.Len() < jpegBuf.Len() {
+		encoded.WebP = webpBuf.Bytes()
+	}
+	return encoded, nil
+}
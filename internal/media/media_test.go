@@ -0,0 +1,187 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func gradient(width, height int, withAlpha bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			a := uint8(0xff)
+			if withAlpha {
+				a = uint8((x + y) % 256)
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: a})
+		}
+	}
+	return img
+}
+
+func noise(width, height int) *image.NRGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rng.Read(img.Pix)
+	return img
+}
+
+// floorPlan draws a grid of thin dark lines on white, like a floor plan
+func floorPlan(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			if x%80 == 0 || y%50 == 0 {
+				c = color.NRGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// photo adds sensor-like noise to a gradient, which lossless codecs cannot compress much
+func photo(width, height int) *image.NRGBA {
+	img := gradient(width, height, false)
+	rng := rand.New(rand.NewSource(2))
+	for i := range img.Pix {
+		if i%4 != 3 {
+			img.Pix[i] += uint8(rng.Intn(24))
+		}
+	}
+	return img
+}
+
+func TestEncodeWebP_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"single pixel", gradient(1, 1, false)},
+		{"flat color", image.NewNRGBA(image.Rect(0, 0, 40, 30))},
+		{"gradient", gradient(300, 200, false)},
+		{"alpha", gradient(64, 48, true)},
+		{"spans predictor tiles", gradient(700, 3, false)},
+		{"noise", noise(97, 61)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, EncodeWebP(&buf, tt.img))
+
+			decoded, err := webp.Decode(&buf)
+			require.NoError(t, err)
+			require.Equal(t, tt.img.Bounds(), decoded.Bounds())
+
+			for y := 0; y < tt.img.Bounds().Dy(); y++ {
+				for x := 0; x < tt.img.Bounds().Dx(); x++ {
+					want := tt.img.NRGBAAt(x, y)
+					got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+					if want.A == 0 {
+						// Fully transparent pixels carry no color once converted
+						assert.Equal(t, uint8(0), got.A)
+						continue
+					}
+					require.Equal(t, want, got, "pixel %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWebP_CompressesFlatGraphics(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeWebP(&buf, image.NewNRGBA(image.Rect(0, 0, 800, 600))))
+	assert.Less(t, buf.Len(), 1024)
+}
+
+func TestEncodeWebP_Size(t *testing.T) {
+	plan := floorPlan(800, 600)
+	var webpBuf, pngBuf bytes.Buffer
+	require.NoError(t, EncodeWebP(&webpBuf, plan))
+	require.NoError(t, png.Encode(&pngBuf, plan))
+	assert.Less(t, webpBuf.Len(), pngBuf.Len()/4, "floor plans compress far better than PNG")
+
+	noisy := photo(400, 300)
+	webpBuf.Reset()
+	require.NoError(t, EncodeWebP(&webpBuf, noisy))
+	assert.Less(t, webpBuf.Len(), len(noisy.Pix)*3/4, "photos still compress below the raw pixels")
+}
+
+func TestEncodeRendition(t *testing.T) {
+	t.Run("keeps the WebP of flat graphics", func(t *testing.T) {
+		encoded, err := EncodeRendition(floorPlan(800, 600))
+		require.NoError(t, err)
+		require.NotNil(t, encoded.WebP)
+		assert.Less(t, len(encoded.WebP), len(encoded.JPEG)/10)
+
+		decoded, err := webp.Decode(bytes.NewReader(encoded.WebP))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 800, 600), decoded.Bounds())
+	})
+
+	t.Run("drops the WebP of photos larger than their JPEG", func(t *testing.T) {
+		encoded, err := EncodeRendition(photo(800, 600))
+		require.NoError(t, err)
+		assert.Nil(t, encoded.WebP)
+		assert.NotEmpty(t, encoded.JPEG)
+		assert.Less(t, len(encoded.JPEG), 800*600*3/8)
+	})
+}
+
+func TestPrefixEncode(t *testing.T) {
+	for value := uint32(1); value <= maxBackwardLength; value++ {
+		symbol, extra, extraBits := prefixEncode(value)
+		require.Less(t, symbol, uint32(numLengthCodes))
+
+		// Inverse used by decoders
+		decoded := symbol + 1
+		if symbol >= 4 {
+			decoded = (2+symbol&1)<<((symbol-2)>>1) + extra + 1
+		}
+		require.Equal(t, value, decoded)
+		require.Less(t, extra, uint32(1)<<extraBits)
+	}
+}
+
+func TestResize(t *testing.T) {
+	resized := Resize(gradient(1000, 500, false), 320)
+	assert.Equal(t, image.Rect(0, 0, 320, 160), resized.Bounds())
+
+	small := Resize(gradient(200, 100, false), 320)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), small.Bounds(), "images are not scaled up")
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, gradient(10, 5, false)))
+
+	img, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 5), img.Bounds())
+
+	_, err = Decode([]byte("not an image"))
+	assert.Error(t, err)
+}
+
+func TestEncodeJPEG_FlattensTransparency(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeJPEG(&buf, image.NewNRGBA(image.Rect(0, 0, 8, 8))))
+
+	img, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+	r, g, b, _ := img.At(4, 4).RGBA()
+	assert.Greater(t, r>>8, uint32(250))
+	assert.Greater(t, g>>8, uint32(250))
+	assert.Greater(t, b>>8, uint32(250))
+}
@@ -0,0 +1,531 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// VP8L bitstream constants, see https://developers.google.com/speed/webp/docs/webp_lossless_bitstream_specification
+const (
+	vp8lSignature       = 0x2f
+	vp8lMaxDimension    = 1 << 14
+	transformPredictor  = 0
+	transformSubtractG  = 2
+	predictorBits       = 9
+	predictorAverageLT  = 7
+	maxCodeLength       = 15
+	maxCodeLengthLength = 7
+	numLiteralCodes     = 256
+	numLengthCodes      = 24
+	numDistanceCodes    = 40
+	minBackwardLength   = 3
+	maxBackwardLength   = 4096
+	// Distance codes 1 and 2 address the pixel above and the pixel to the left
+	distanceCodeUp   = 1
+	distanceCodeLeft = 2
+)
+
+// codeLengthCodeOrder is the order in which the code length code lengths are written
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// ErrWebPDimensions is returned for images WebP cannot represent
+var ErrWebPDimensions = errors.New("webp: image dimensions out of range")
+
+// EncodeWebP writes img as a lossless WebP (VP8L) image.
+// Pixels go through the subtract-green and average predictor transforms and are Huffman coded
+// as literals or as runs copied from the left or upper pixel; the color cache and general LZ77
+// matching are not used. This compresses flat graphics such as floor plans well, but photos are
+// usually larger than their JPEG rendition: use EncodeRendition, which only keeps the smaller.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+		return ErrWebPDimensions
+	}
+
+	argb, hasAlpha := toARGB(img)
+
+	bw := &bitWriter{}
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // version
+
+	// Transforms are listed in the order they are applied; the decoder undoes them in reverse
+	subtractGreen(argb)
+	bw.write(1, 1)
+	bw.write(transformSubtractG, 2)
+
+	residuals := predict(argb, width, height)
+	bw.write(1, 1)
+	bw.write(transformPredictor, 2)
+	bw.write(predictorBits-2, 3)
+	tiles := make([]uint32, tileCount(width)*tileCount(height))
+	for i := range tiles {
+		tiles[i] = 0xff000000 | predictorAverageLT<<8 // the mode is stored in the green channel
+	}
+	writeImageData(bw, tiles, tileCount(width), false)
+	bw.write(0, 1) // no more transforms
+
+	writeImageData(bw, residuals, width, true)
+	data := bw.bytes()
+
+	var header [20]byte
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(12+len(data)+len(data)&1))
+	copy(header[8:12], "WEBP")
+	copy(header[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if len(data)&1 == 1 {
+		data = append(data, 0) // chunks are padded to an even size
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// toARGB returns the pixels as packed 0xAARRGGBB values in row order
+func toARGB(img image.Image) ([]uint32, bool) {
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+		bounds = nrgba.Bounds()
+	}
+
+	argb := make([]uint32, 0, bounds.Dx()*bounds.Dy())
+	hasAlpha := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := nrgba.Pix[nrgba.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < bounds.Dx(); x++ {
+			r, g, b, a := row[4*x], row[4*x+1], row[4*x+2], row[4*x+3]
+			if a != 0xff {
+				hasAlpha = true
+			}
+			argb = append(argb, uint32(a)<<24|uint32(r)<<16|uint32(g)<<8|uint32(b))
+		}
+	}
+	return argb, hasAlpha
+}
+
+// subtractGreen subtracts the green channel from red and blue in place
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		green := (p >> 8) & 0xff
+		red := ((p>>16)&0xff - green) & 0xff
+		blue := (p&0xff - green) & 0xff
+		argb[i] = p&0xff00ff00 | red<<16 | blue
+	}
+}
+
+// predict returns the residuals of the predictor transform. The decoder predicts the first pixel
+// as opaque black, the rest of the first row from the left pixel, the first column from the top
+// pixel and every other pixel from the tile mode, here the average of left and top.
+func predict(argb []uint32, width, height int) []uint32 {
+	residuals := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			var predicted uint32
+			switch {
+			case x == 0 && y == 0:
+				predicted = 0xff000000
+			case y == 0:
+				predicted = argb[i-1]
+			case x == 0:
+				predicted = argb[i-width]
+			default:
+				predicted = average2(argb[i-1], argb[i-width])
+			}
+			residuals[i] = subPixels(argb[i], predicted)
+		}
+	}
+	return residuals
+}
+
+// average2 averages each 8-bit channel, rounding down
+func average2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xfefefefe) >> 1) + (a & b)
+}
+
+// subPixels subtracts each 8-bit channel modulo 256
+func subPixels(a, b uint32) uint32 {
+	var out uint32
+	for shift := 0; shift < 32; shift += 8 {
+		out |= ((a>>shift - b>>shift) & 0xff) << shift
+	}
+	return out
+}
+
+func tileCount(size int) int {
+	return (size + 1<<predictorBits - 1) >> predictorBits
+}
+
+// pixelToken is a literal pixel or, when length > 0, a copy of length pixels from an earlier position
+type pixelToken struct {
+	pixel        uint32
+	length       uint32
+	distanceCode uint32
+}
+
+// tokenize replaces runs of pixels equal to their left or upper neighbour with backward references.
+// After the predictor transform flat areas become long runs of identical residuals.
+func tokenize(pixels []uint32, width int) []pixelToken {
+	tokens := make([]pixelToken, 0, len(pixels))
+	for i := 0; i < len(pixels); {
+		left, up := 0, 0
+		if i >= 1 {
+			for i+left < len(pixels) && left < maxBackwardLength && pixels[i+left] == pixels[i+left-1] {
+				left++
+			}
+		}
+		if i >= width {
+			for i+up < len(pixels) && up < maxBackwardLength && pixels[i+up] == pixels[i+up-width] {
+				up++
+			}
+		}
+
+		switch {
+		case max(left, up) < minBackwardLength:
+			tokens = append(tokens, pixelToken{pixel: pixels[i]})
+			i++
+		case up > left:
+			tokens = append(tokens, pixelToken{length: uint32(up), distanceCode: distanceCodeUp})
+			i += up
+		default:
+			tokens = append(tokens, pixelToken{length: uint32(left), distanceCode: distanceCodeLeft})
+			i += left
+		}
+	}
+	return tokens
+}
+
+// prefixEncode splits a backward reference length or distance code into a prefix symbol and extra bits
+func prefixEncode(value uint32) (symbol, extra, extraBits uint32) {
+	if value <= 4 {
+		return value - 1, 0, 0
+	}
+	value--
+	highest := uint32(bits.Len32(value) - 1)
+	second := (value >> (highest - 1)) & 1
+	return 2*highest + second, value & (1<<(highest-1) - 1), highest - 1
+}
+
+// writeImageData entropy codes pixels with a single group of five prefix codes
+func writeImageData(bw *bitWriter, pixels []uint32, width int, topLevel bool) {
+	bw.write(0, 1) // no color cache
+	if topLevel {
+		bw.write(0, 1) // no meta prefix codes
+	}
+
+	tokens := tokenize(pixels, width)
+
+	green := make([]uint32, numLiteralCodes+numLengthCodes)
+	red := make([]uint32, numLiteralCodes)
+	blue := make([]uint32, numLiteralCodes)
+	alpha := make([]uint32, numLiteralCodes)
+	distance := make([]uint32, numDistanceCodes)
+	for _, t := range tokens {
+		if t.length > 0 {
+			lengthSymbol, _, _ := prefixEncode(t.length)
+			distanceSymbol, _, _ := prefixEncode(t.distanceCode)
+			green[numLiteralCodes+lengthSymbol]++
+			distance[distanceSymbol]++
+			continue
+		}
+		green[(t.pixel>>8)&0xff]++
+		red[(t.pixel>>16)&0xff]++
+		blue[t.pixel&0xff]++
+		alpha[t.pixel>>24]++
+	}
+
+	greenCode := writePrefixCode(bw, green)
+	redCode := writePrefixCode(bw, red)
+	blueCode := writePrefixCode(bw, blue)
+	alphaCode := writePrefixCode(bw, alpha)
+	distanceCode := writePrefixCode(bw, distance)
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			symbol, extra, extraBits := prefixEncode(t.length)
+			greenCode.write(bw, numLiteralCodes+symbol)
+			bw.write(extra, extraBits)
+			symbol, extra, extraBits = prefixEncode(t.distanceCode)
+			distanceCode.write(bw, symbol)
+			bw.write(extra, extraBits)
+			continue
+		}
+		greenCode.write(bw, (t.pixel>>8)&0xff)
+		redCode.write(bw, (t.pixel>>16)&0xff)
+		blueCode.write(bw, t.pixel&0xff)
+		alphaCode.write(bw, t.pixel>>24)
+	}
+}
+
+// prefixCode holds bit-reversed canonical Huffman codes ready for the LSB-first bit writer
+type prefixCode struct {
+	lengths []uint32
+	codes   []uint32
+}
+
+func (pc *prefixCode) write(bw *bitWriter, symbol uint32) {
+	if length := pc.lengths[symbol]; length > 0 {
+		bw.write(pc.codes[symbol], length)
+	}
+}
+
+// newPrefixCode assigns canonical codes to lengths. A code with a single symbol takes zero bits.
+func newPrefixCode(lengths []uint32) *prefixCode {
+	pc := &prefixCode{lengths: make([]uint32, len(lengths)), codes: make([]uint32, len(lengths))}
+
+	used := 0
+	var count [maxCodeLength + 1]uint32
+	for _, length := range lengths {
+		if length > 0 {
+			used++
+			count[length]++
+		}
+	}
+	if used <= 1 {
+		return pc
+	}
+
+	var next [maxCodeLength + 1]uint32
+	code := uint32(0)
+	for length := 1; length <= maxCodeLength; length++ {
+		code = (code + count[length-1]) << 1
+		next[length] = code
+	}
+
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		pc.lengths[symbol] = length
+		pc.codes[symbol] = reverseBits(next[length], length)
+		next[length]++
+	}
+	return pc
+}
+
+// writePrefixCode writes the code for a histogram and returns it.
+// Zero or one used symbol is written as a simple code that takes no bits per symbol.
+func writePrefixCode(bw *bitWriter, histogram []uint32) *prefixCode {
+	used, symbol := 0, 0
+	for s, n := range histogram {
+		if n > 0 {
+			used++
+			symbol = s
+		}
+	}
+
+	if used <= 1 && symbol < numLiteralCodes {
+		bw.write(1, 1) // simple code
+		bw.write(0, 1) // one symbol
+		if symbol < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbol), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbol), 8)
+		}
+		return &prefixCode{lengths: make([]uint32, len(histogram)), codes: make([]uint32, len(histogram))}
+	}
+
+	lengths := huffmanLengths(histogram, maxCodeLength)
+	bw.write(0, 1) // normal code
+	writeCodeLengths(bw, lengths)
+	return newPrefixCode(lengths)
+}
+
+// codeLengthToken is a symbol of the code length alphabet: 0-15 are literal lengths,
+// 16 repeats the previous length and 17/18 repeat zeros
+type codeLengthToken struct {
+	symbol    uint32
+	extra     uint32
+	extraBits uint32
+}
+
+// writeCodeLengths run-length encodes lengths and writes them with their own prefix code
+func writeCodeLengths(bw *bitWriter, lengths []uint32) {
+	tokens := codeLengthTokens(lengths)
+
+	histogram := make([]uint32, len(codeLengthCodeOrder))
+	for _, t := range tokens {
+		histogram[t.symbol]++
+	}
+	codeLengthLengths := huffmanLengths(histogram, maxCodeLengthLength)
+
+	n := len(codeLengthCodeOrder)
+	for n > 4 && codeLengthLengths[codeLengthCodeOrder[n-1]] == 0 {
+		n--
+	}
+	bw.write(uint32(n-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:n] {
+		bw.write(codeLengthLengths[symbol], 3)
+	}
+	bw.write(0, 1) // lengths are written for the whole alphabet
+
+	code := newPrefixCode(codeLengthLengths)
+	for _, t := range tokens {
+		code.write(bw, t.symbol)
+		if t.extraBits > 0 {
+			bw.write(t.extra, t.extraBits)
+		}
+	}
+}
+
+func codeLengthTokens(lengths []uint32) []codeLengthToken {
+	var tokens []codeLengthToken
+	for i := 0; i < len(lengths); {
+		length := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == length {
+			run++
+		}
+		i += run
+
+		if length == 0 {
+			for run >= 3 {
+				if run >= 11 {
+					n := min(run, 138)
+					tokens = append(tokens, codeLengthToken{symbol: 18, extra: uint32(n - 11), extraBits: 7})
+					run -= n
+				} else {
+					n := min(run, 10)
+					tokens = append(tokens, codeLengthToken{symbol: 17, extra: uint32(n - 3), extraBits: 3})
+					run -= n
+				}
+			}
+		} else {
+			tokens = append(tokens, codeLengthToken{symbol: length})
+			run--
+			for run >= 3 {
+				n := min(run, 6)
+				tokens = append(tokens, codeLengthToken{symbol: 16, extra: uint32(n - 3), extraBits: 2})
+				run -= n
+			}
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, codeLengthToken{symbol: length})
+		}
+	}
+	return tokens
+}
+
+// huffmanLengths computes code lengths no longer than maxLength. When the optimal tree is too
+// deep the frequencies are flattened and the tree rebuilt.
+func huffmanLengths(histogram []uint32, maxLength uint32) []uint32 {
+	freq := append([]uint32(nil), histogram...)
+	for {
+		lengths := buildHuffmanLengths(freq)
+		longest := uint32(0)
+		for _, length := range lengths {
+			longest = max(longest, length)
+		}
+		if longest <= maxLength {
+			return lengths
+		}
+		for i, f := range freq {
+			if f > 0 {
+				freq[i] = f/2 + 1
+			}
+		}
+	}
+}
+
+func buildHuffmanLengths(freq []uint32) []uint32 {
+	type node struct {
+		weight uint64
+		parent int
+	}
+
+	lengths := make([]uint32, len(freq))
+	var nodes []node
+	var symbols []int
+	for symbol, f := range freq {
+		if f > 0 {
+			nodes = append(nodes, node{weight: uint64(f), parent: -1})
+			symbols = append(symbols, symbol)
+		}
+	}
+	switch len(symbols) {
+	case 0:
+		return lengths
+	case 1:
+		lengths[symbols[0]] = 1
+		return lengths
+	}
+
+	active := make([]int, len(nodes))
+	for i := range active {
+		active[i] = i
+	}
+	for len(active) > 1 {
+		sort.Slice(active, func(i, j int) bool {
+			a, b := nodes[active[i]], nodes[active[j]]
+			return a.weight < b.weight || (a.weight == b.weight && active[i] < active[j])
+		})
+		parent := len(nodes)
+		nodes = append(nodes, node{weight: nodes[active[0]].weight + nodes[active[1]].weight, parent: -1})
+		nodes[active[0]].parent = parent
+		nodes[active[1]].parent = parent
+		active = append(active[2:], parent)
+	}
+
+	for leaf, symbol := range symbols {
+		depth := uint32(0)
+		for n := leaf; nodes[n].parent != -1; n = nodes[n].parent {
+			depth++
+		}
+		lengths[symbol] = depth
+	}
+	return lengths
+}
+
+func reverseBits(code, length uint32) uint32 {
+	reversed := uint32(0)
+	for i := uint32(0); i < length; i++ {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	return reversed
+}
+
+// bitWriter packs values least significant bit first, as VP8L requires
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nBits uint32
+}
+
+func (bw *bitWriter) write(value, n uint32) {
+	bw.acc |= uint64(value) << bw.nBits
+	bw.nBits += n
+	for bw.nBits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nBits -= 8
+	}
+}
+
+func (bw *bitWriter) bytes() []byte {
+	if bw.nBits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nBits = 0, 0
+	}
+	return bw.buf
+}
@@ -25,3 +25,15 @@ func RequireRole(role string) gin.HandlerFunc {
 func RequireAdmin() gin.HandlerFunc {
 	return RequireRole("admin")
 }
+
+// RequirePermission returns a middleware that checks if one of the user's roles grants permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !contextutil.HasPermission(c, permission) {
+			c.JSON(http.StatusForbidden, errors.Forbidden("insufficient permissions"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
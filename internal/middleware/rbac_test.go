@@ -155,3 +155,69 @@ func TestRequireAdmin(t *testing.T) {
 		})
 	}
 }
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		permission     string
+		userRoles      []string
+		expectedStatus int
+	}{
+		{
+			name:           "admin has every permission",
+			permission:     auth.PermissionImportRun,
+			userRoles:      []string{"user", "admin"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "role grants the permission",
+			permission:     auth.PermissionImoveisWrite,
+			userRoles:      []string{"user", "corretor"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "role does not grant the permission",
+			permission:     auth.PermissionEmailsSend,
+			userRoles:      []string{"user", "corretor"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "user role grants nothing",
+			permission:     auth.PermissionSlidersWrite,
+			userRoles:      []string{"user"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "no authenticated user",
+			permission:     auth.PermissionSlidersWrite,
+			userRoles:      nil,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, router := gin.CreateTestContext(w)
+
+			router.Use(func(c *gin.Context) {
+				if tt.userRoles != nil {
+					c.Set(auth.KeyUser, &auth.Claims{UserID: 1, Email: "test@example.com", Roles: tt.userRoles})
+				}
+				c.Next()
+			})
+
+			router.Use(RequirePermission(tt.permission))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
+			router.ServeHTTP(w, c.Request)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
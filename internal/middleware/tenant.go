@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// TenantHeader lets admins act on the data of another organizacao
+const TenantHeader = "X-Organizacao-ID"
+
+// Tenant sets the organizacao whose imoveis, sliders and emails the request sees (see
+// db.TenantScope). Users are scoped to the organizacao of their token; admins may switch to any
+// other with the X-Organizacao-ID header, which is ignored for everyone else. Admins without an
+// organizacao see every organizacao; any other user without one is refused, as an unscoped
+// request would see the data of every tenant. It must run after the auth middleware.
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := contextutil.GetUser(c)
+		if claims == nil {
+			c.Next()
+			return
+		}
+
+		organizacaoID := claims.OrganizacaoID
+		if header := c.GetHeader(TenantHeader); header != "" && contextutil.IsAdmin(c) {
+			id, err := strconv.ParseUint(header, 10, 32)
+			if err != nil || id == 0 {
+				c.JSON(http.StatusBadRequest, errors.BadRequest("invalid "+TenantHeader+" header"))
+				c.Abort()
+				return
+			}
+			organizacaoID = uint(id)
+		}
+
+		if organizacaoID == 0 {
+			if !contextutil.IsAdmin(c) {
+				c.JSON(http.StatusForbidden, errors.Forbidden("user does not belong to an organizacao"))
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Request = c.Request.WithContext(db.WithOrganizacao(c.Request.Context(), organizacaoID))
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+func TestTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		header         string
+		expectedCode   int
+		expectedTenant uint
+		expectedScoped bool
+	}{
+		{name: "anonymous request", expectedCode: http.StatusOK},
+		{name: "user without organizacao is refused", claims: &auth.Claims{UserID: 1, Roles: []string{"gestor"}}, expectedCode: http.StatusForbidden},
+		{name: "admin without organizacao sees every organizacao", claims: &auth.Claims{UserID: 1, Roles: []string{"admin"}}, expectedCode: http.StatusOK},
+		{
+			name:           "user of an organizacao",
+			claims:         &auth.Claims{UserID: 2, OrganizacaoID: 3},
+			expectedCode:   http.StatusOK,
+			expectedTenant: 3,
+			expectedScoped: true,
+		},
+		{
+			name:           "non-admin cannot switch organizacao",
+			claims:         &auth.Claims{UserID: 2, Roles: []string{"gestor"}, OrganizacaoID: 3},
+			header:         "4",
+			expectedCode:   http.StatusOK,
+			expectedTenant: 3,
+			expectedScoped: true,
+		},
+		{
+			name:           "admin switches organizacao",
+			claims:         &auth.Claims{UserID: 5, Roles: []string{"admin"}, OrganizacaoID: 3},
+			header:         "4",
+			expectedCode:   http.StatusOK,
+			expectedTenant: 4,
+			expectedScoped: true,
+		},
+		{
+			name:         "admin sends an invalid organizacao",
+			claims:       &auth.Claims{UserID: 5, Roles: []string{"admin"}},
+			header:       "abc",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant uint
+			var gotScoped bool
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.claims != nil {
+					c.Set(auth.KeyUser, tt.claims)
+				}
+				c.Next()
+			})
+			router.Use(Tenant())
+			router.GET("/test", func(c *gin.Context) {
+				gotTenant, gotScoped = db.OrganizacaoFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedTenant, gotTenant)
+			assert.Equal(t, tt.expectedScoped, gotScoped)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// RequireTwoFactor returns a middleware that rejects users with one of roles whose access token was
+// not obtained through a two-factor login. It must run after the auth middleware; with no roles it
+// lets every request through.
+func RequireTwoFactor(roles []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := contextutil.GetUser(c)
+		if claims == nil || claims.TwoFactor {
+			c.Next()
+			return
+		}
+		for _, role := range roles {
+			if contextutil.HasRole(c, role) {
+				c.JSON(http.StatusForbidden, errors.Forbidden("two-factor authentication required"))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
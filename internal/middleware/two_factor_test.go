@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+func TestRequireTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		expectedStatus int
+	}{
+		{
+			name:           "required role without 2FA",
+			claims:         &auth.Claims{UserID: 1, Roles: []string{"user", "admin"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "required role with 2FA",
+			claims:         &auth.Claims{UserID: 1, Roles: []string{"admin"}, TwoFactor: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "other role without 2FA",
+			claims:         &auth.Claims{UserID: 1, Roles: []string{"user"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no authenticated user",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			router.Use(func(c *gin.Context) {
+				if tt.claims != nil {
+					c.Set(auth.KeyUser, tt.claims)
+				}
+				c.Next()
+			})
+			router.Use(RequireTwoFactor([]string{"admin"}))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "two-factor authentication required")
+			}
+		})
+	}
+}
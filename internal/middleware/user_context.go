@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+// PropagateUser copies the authenticated user's ID into the request context, so services can
+// attribute the changes they make, and limits corretor users to their own imoveis. It must run
+// after the auth middleware.
+func PropagateUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := contextutil.GetUser(c)
+		if claims != nil && claims.UserID != 0 {
+			ctx := contextutil.WithUserID(c.Request.Context(), claims.UserID)
+			if auth.ScopedToCorretor(claims.Roles) {
+				ctx = contextutil.WithCorretorScope(ctx, claims.CorretorID)
+			}
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
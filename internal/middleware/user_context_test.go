@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+)
+
+func TestPropagateUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		claims           *auth.Claims
+		expectedID       uint
+		expectedOK       bool
+		expectedCorretor uint
+		expectedScoped   bool
+	}{
+		{name: "authenticated user", claims: &auth.Claims{UserID: 42}, expectedID: 42, expectedOK: true},
+		{name: "anonymous request", claims: nil, expectedID: 0, expectedOK: false},
+		{
+			name:             "corretor user",
+			claims:           &auth.Claims{UserID: 7, Roles: []string{"user", "corretor"}, CorretorID: 3},
+			expectedID:       7,
+			expectedOK:       true,
+			expectedCorretor: 3,
+			expectedScoped:   true,
+		},
+		{
+			name:           "unlinked corretor user",
+			claims:         &auth.Claims{UserID: 8, Roles: []string{"user", "corretor"}},
+			expectedID:     8,
+			expectedOK:     true,
+			expectedScoped: true,
+		},
+		{name: "gestor who is also corretor", claims: &auth.Claims{UserID: 9, Roles: []string{"corretor", "gestor"}, CorretorID: 3}, expectedID: 9, expectedOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID, gotCorretor uint
+			var gotOK, gotScoped bool
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.claims != nil {
+					c.Set(auth.KeyUser, tt.claims)
+				}
+				c.Next()
+			})
+			router.Use(PropagateUser())
+			router.GET("/test", func(c *gin.Context) {
+				gotID, gotOK = contextutil.UserIDFromContext(c.Request.Context())
+				gotCorretor, gotScoped = contextutil.CorretorScopeFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedID, gotID)
+			assert.Equal(t, tt.expectedOK, gotOK)
+			assert.Equal(t, tt.expectedCorretor, gotCorretor)
+			assert.Equal(t, tt.expectedScoped, gotScoped)
+		})
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -200,3 +204,58 @@ func (m *Migrator) Close() error {
 	}
 	return nil
 }
+
+// CurrentVersion reads the applied schema version directly from the migrations table
+// without acquiring a dedicated connection, which makes it cheap enough for health probes.
+// A database that has never been migrated reports version 0.
+func CurrentVersion(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations')::text").Scan(&table); err != nil {
+		return 0, false, fmt.Errorf("failed to look up migrations table: %w", err)
+	}
+	if !table.Valid {
+		return 0, false, nil
+	}
+
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	return uint(version), dirty, nil
+}
+
+// PendingVersions returns the versions of the up migrations in dir that are newer than current, in ascending order
+func PendingVersions(dir string, current uint) ([]uint, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var pending []uint
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if !found {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > current {
+			pending = append(pending, uint(version))
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+	return pending, nil
+}
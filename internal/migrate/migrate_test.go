@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -664,3 +666,30 @@ func TestMigrator_Close_DatabaseError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to close database")
 }
+
+func TestPendingVersions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20240101000000_create_users_table.up.sql",
+		"20240101000000_create_users_table.down.sql",
+		"20240201000000_add_roles.up.sql",
+		"20240201000000_add_roles.down.sql",
+		"20240301000000_add_sliders.up.sql",
+		"README.md",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600))
+	}
+
+	pending, err := PendingVersions(dir, 20240101000000)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{20240201000000, 20240301000000}, pending)
+
+	pending, err = PendingVersions(dir, 20240301000000)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestPendingVersions_MissingDirectory(t *testing.T) {
+	_, err := PendingVersions(filepath.Join(t.TempDir(), "missing"), 0)
+	assert.Error(t, err)
+}
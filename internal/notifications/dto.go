@@ -0,0 +1,21 @@
+package notifications
+
+// PreferenceRequest opts the user in or out of a notification through a channel
+type PreferenceRequest struct {
+	Event   string `json:"event" binding:"required,oneof=new_lead import_summary price_change"`
+	Channel string `json:"channel" binding:"required,oneof=email"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+// UpdatePreferencesRequest changes some of the notification preferences of the user; the others
+// are kept
+type UpdatePreferencesRequest struct {
+	Preferences []PreferenceRequest `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// PreferenceResponse tells whether the user receives a notification through a channel
+type PreferenceResponse struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+}
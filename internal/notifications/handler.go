@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for the notification preferences
+type Handler struct {
+	service PreferenceService
+}
+
+// NewHandler creates a new notification preferences handler
+func NewHandler(service PreferenceService) *Handler {
+	return &Handler{service: service}
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description List, for each notification (new_lead, import_summary, price_change) and channel (email), whether the current user receives it. Notifications are received unless opted out of.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=[]PreferenceResponse} "Success response with the preferences"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to list preferences"
+// @Router /api/v1/auth/me/notification-preferences [get]
+func (h *Handler) GetPreferences(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preferences))
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Opt the current user in or out of notifications per channel. Preferences left out of the request are kept.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdatePreferencesRequest true "Preferences to change"
+// @Success 200 {object} errors.Response{success=bool,data=[]PreferenceResponse} "Success response with all the preferences"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to update preferences"
+// @Router /api/v1/auth/me/notification-preferences [put]
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preferences, err := h.service.UpdatePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preferences))
+}
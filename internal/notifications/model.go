@@ -0,0 +1,37 @@
+package notifications
+
+import "time"
+
+// Notifications users can opt out of
+const (
+	EventNewLead       = "new_lead"
+	EventImportSummary = "import_summary"
+	EventPriceChange   = "price_change"
+)
+
+// Channels notifications are delivered through; push is planned
+const (
+	ChannelEmail = "email"
+)
+
+// Events lists the notifications users can opt out of, in the order preferences are listed
+var Events = []string{EventNewLead, EventImportSummary, EventPriceChange}
+
+// Channels lists the channels notifications are delivered through
+var Channels = []string{ChannelEmail}
+
+// Preference records whether a user receives a notification through a channel. Users without a
+// preference for a notification and channel receive it.
+type Preference struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_notification_preferences_user_event_channel"`
+	Event     string `gorm:"size:50;not null;uniqueIndex:idx_notification_preferences_user_event_channel"`
+	Channel   string `gorm:"size:20;not null;uniqueIndex:idx_notification_preferences_user_event_channel"`
+	Enabled   bool   `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for Preference model
+func (Preference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,284 @@
+// Package notifications sends the automatic emails of the domain events published on the event
+// bus: import runs summarized to the admins, new leads, publications and price changes to the
+// corretor principal of the property, and login lockouts to the owner of the account. Users may
+// opt out of the import summaries, new leads and price changes.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// notificationTemplate is the email template of every notification
+const notificationTemplate = "notification"
+
+// ImovelFinder loads the property of an event, with its corretor principal
+type ImovelFinder interface {
+	GetImovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error)
+}
+
+// Notifier sends the notification emails of the events it subscribes to
+type Notifier interface {
+	// Subscribe registers the notification handlers on bus
+	Subscribe(bus events.Bus)
+}
+
+type notifier struct {
+	sender  email.Service
+	imoveis ImovelFinder
+	repo    Repository
+}
+
+// NewNotifier creates a notifier sending through sender, which queues the emails. Events whose
+// recipient has no email are skipped.
+func NewNotifier(sender email.Service, finder ImovelFinder, repo Repository) Notifier {
+	return &notifier{sender: sender, imoveis: finder, repo: repo}
+}
+
+// Subscribe registers the notification handlers on bus
+func (n *notifier) Subscribe(bus events.Bus) {
+	bus.Subscribe(events.ImportFinishedName, n.importFinished)
+	bus.Subscribe(events.LeadCreatedName, n.leadCreated)
+	bus.Subscribe(events.ImovelPublishedName, n.imovelPublished)
+	bus.Subscribe(events.PriceChangedName, n.priceChanged)
+	bus.Subscribe(events.LoginLockedName, n.loginLocked)
+}
+
+// importFinished sends the summary of an import run to the admins
+func (n *notifier) importFinished(ctx context.Context, event events.Event) error {
+	run := event.(events.ImportFinished)
+
+	kind, subject, message := "success", "Importação %s concluída", "A importação do catálogo terminou."
+	switch {
+	case run.Status == imoveis.ImportRunFailed:
+		kind, subject, message = "error", "Importação %s interrompida", "A importação do catálogo foi interrompida antes do fim. O que foi importado até a falha foi mantido."
+	case run.Failed > 0 || run.Quarantined > 0:
+		kind, subject, message = "warning", "Importação %s concluída com falhas", "A importação do catálogo terminou, mas alguns imóveis não foram importados."
+	}
+
+	details := map[string]string{
+		"Origem":        run.Source,
+		"Listados":      strconv.Itoa(run.Total),
+		"Criados":       strconv.Itoa(run.Created),
+		"Atualizados":   strconv.Itoa(run.Updated),
+		"Inalterados":   strconv.Itoa(run.Unchanged),
+		"Falhas":        strconv.Itoa(run.Failed),
+		"Removidos":     strconv.Itoa(run.Removed),
+		"Em quarentena": strconv.Itoa(run.Quarantined),
+		"Duração":       run.FinishedAt.Sub(run.StartedAt).Round(time.Second).String(),
+	}
+	if run.RunID != 0 {
+		details["Execução"] = fmt.Sprintf("#%d", run.RunID)
+	}
+
+	admins, err := n.repo.ListAdminEmails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list admins: %w", err)
+	}
+	return n.send(ctx, EventImportSummary, admins, fmt.Sprintf(subject, run.Source), map[string]interface{}{
+		"Type":         kind,
+		"Title":        "Importação de imóveis",
+		"Message":      message,
+		"AlertMessage": run.Error,
+		"Details":      details,
+	})
+}
+
+// leadCreated tells the corretor of the property about a new lead; leads without a property or
+// whose property has no corretor go to the admins
+func (n *notifier) leadCreated(ctx context.Context, event events.Event) error {
+	lead := event.(events.LeadCreated)
+
+	details := map[string]string{
+		"Nome":     lead.Nome,
+		"Email":    lead.Email,
+		"Telefone": lead.Telefone,
+	}
+	subject := fmt.Sprintf("Novo contato de %s", lead.Nome)
+	var to []string
+	if lead.ImovelID != 0 {
+		imovel, err := n.imovel(ctx, lead.ImovelID)
+		if err != nil {
+			return err
+		}
+		if imovel != nil {
+			details["Imóvel"] = fmt.Sprintf("%s — %s", imovel.Codigo, imovel.Titulo)
+			subject = fmt.Sprintf("Novo contato sobre o imóvel %s", imovel.Codigo)
+			to = corretorEmail(imovel)
+		}
+	}
+	if len(to) == 0 {
+		admins, err := n.repo.ListAdminEmails(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list admins: %w", err)
+		}
+		to = admins
+	}
+
+	return n.send(ctx, EventNewLead, to, subject, map[string]interface{}{
+		"Type":         "info",
+		"Title":        "Novo contato",
+		"Message":      fmt.Sprintf("%s pediu para ser contatado.", lead.Nome),
+		"AlertMessage": lead.Mensagem,
+		"Details":      details,
+	})
+}
+
+// imovelPublished tells the corretor that the property was published. Publications made by the
+// scheduler are left to scheduler.notify_corretor, which reports them with the other scheduled
+// changes.
+func (n *notifier) imovelPublished(ctx context.Context, event events.Event) error {
+	published := event.(events.ImovelPublished)
+	if published.Origem == imoveis.PriceOriginScheduler {
+		return nil
+	}
+
+	imovel, err := n.imovel(ctx, published.ImovelID)
+	if err != nil || imovel == nil {
+		return err
+	}
+	return n.send(ctx, "", corretorEmail(imovel), fmt.Sprintf("Imóvel %s publicado", imovel.Codigo), map[string]interface{}{
+		"Type":    "success",
+		"Title":   "Imóvel publicado",
+		"Message": "O imóvel já aparece nas listagens públicas.",
+		"Details": map[string]string{
+			"Código": imovel.Codigo,
+			"Título": imovel.Titulo,
+		},
+	})
+}
+
+// priceChanged tells the corretor that the sale or rental price of the property changed
+func (n *notifier) priceChanged(ctx context.Context, event events.Event) error {
+	change := event.(events.PriceChanged)
+
+	imovel, err := n.imovel(ctx, change.ImovelID)
+	if err != nil || imovel == nil {
+		return err
+	}
+
+	tipo := "venda"
+	if change.Tipo == imoveis.HistoricoTipoAluguel {
+		tipo = "aluguel"
+	}
+	details := map[string]string{
+		"Código":         imovel.Codigo,
+		"Título":         imovel.Titulo,
+		"Preço anterior": formatBRL(change.PrecoAnterior),
+		"Novo preço":     formatBRL(change.Preco),
+	}
+	if change.PrecoAnterior != 0 {
+		variacao := (change.Preco - change.PrecoAnterior) / change.PrecoAnterior * 100
+		details["Variação"] = strings.Replace(fmt.Sprintf("%+.1f%%", variacao), ".", ",", 1)
+	}
+
+	return n.send(ctx, EventPriceChange, corretorEmail(imovel), fmt.Sprintf("Preço de %s do imóvel %s alterado", tipo, imovel.Codigo), map[string]interface{}{
+		"Type":    "info",
+		"Title":   "Preço alterado",
+		"Message": fmt.Sprintf("O preço de %s do imóvel foi alterado.", tipo),
+		"Details": details,
+	})
+}
+
+// loginLocked warns the owner of an account locked out after failed logins, in case someone else
+// is trying their password. Lockouts of an address or of an email without account are not sent.
+func (n *notifier) loginLocked(ctx context.Context, event events.Event) error {
+	locked := event.(events.LoginLocked)
+	if locked.UserID == 0 || locked.Email == "" {
+		return nil
+	}
+
+	details := map[string]string{
+		"Tentativas":    strconv.Itoa(locked.Attempts),
+		"Bloqueada até": locked.LockedUntil.Format("02/01/2006 15:04"),
+	}
+	if locked.IPAddress != "" {
+		details["Endereço IP"] = locked.IPAddress
+	}
+	return n.send(ctx, "", []string{locked.Email}, "Sua conta foi bloqueada temporariamente", map[string]interface{}{
+		"Type":         "warning",
+		"Title":        "Conta bloqueada",
+		"Message":      fmt.Sprintf("Olá, %s. Após várias tentativas de login com senha incorreta, o acesso à sua conta foi bloqueado temporariamente.", locked.Name),
+		"AlertMessage": "Se não foi você, altere sua senha assim que o bloqueio terminar.",
+		"Details":      details,
+	})
+}
+
+// imovel loads the property of an event; deleted properties return nil
+func (n *notifier) imovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error) {
+	imovel, err := n.imoveis.GetImovel(ctx, id)
+	if errors.Is(err, imoveis.ErrImovelNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load property %d: %w", id, err)
+	}
+	return imovel, nil
+}
+
+// send queues a notification email for each recipient, so they don't see each other's address.
+// Recipients who opted out of preference are skipped; notifications without preference are sent
+// to everyone.
+func (n *notifier) send(ctx context.Context, preference string, to []string, subject string, data map[string]interface{}) error {
+	if preference != "" && len(to) > 0 {
+		optedOut, err := n.repo.ListOptedOutEmails(ctx, preference, ChannelEmail, to)
+		if err != nil {
+			return fmt.Errorf("failed to load notification preferences: %w", err)
+		}
+		to = slices.DeleteFunc(slices.Clone(to), func(recipient string) bool {
+			return slices.Contains(optedOut, strings.ToLower(recipient))
+		})
+	}
+	data["Timestamp"] = time.Now().Format("02/01/2006 15:04")
+
+	var errs []error
+	for _, recipient := range to {
+		if _, err := n.sender.SendTemplateEmail(ctx, &email.SendTemplateEmailRequest{
+			To:           []string{recipient},
+			Subject:      subject,
+			TemplateName: notificationTemplate,
+			TemplateData: data,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify %s: %w", recipient, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// corretorEmail returns the email of the corretor principal of the property, if any
+func corretorEmail(imovel *imoveis.ImovelResponse) []string {
+	if imovel.CorretorPrincipal == nil || imovel.CorretorPrincipal.Email == "" {
+		return nil
+	}
+	return []string{imovel.CorretorPrincipal.Email}
+}
+
+// formatBRL formats a price in reais, e.g. R$ 1.250.000,00
+func formatBRL(value float64) string {
+	cents := int64(math.Round(math.Abs(value) * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
@@ -0,0 +1,287 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// recordingSender records the template emails instead of queueing them
+type recordingSender struct {
+	email.Service
+	sent []*email.SendTemplateEmailRequest
+}
+
+func (s *recordingSender) SendTemplateEmail(ctx context.Context, req *email.SendTemplateEmailRequest) (*email.EmailResponse, error) {
+	s.sent = append(s.sent, req)
+	return &email.EmailResponse{}, nil
+}
+
+type fakeFinder map[uint]*imoveis.ImovelResponse
+
+func (f fakeFinder) GetImovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error) {
+	if imovel, ok := f[id]; ok {
+		return imovel, nil
+	}
+	return nil, imoveis.ErrImovelNotFound
+}
+
+// fakeRepository lists admins, and the emails opted out of each notification
+type fakeRepository struct {
+	Repository
+	admins   []string
+	optedOut map[string][]string
+}
+
+func (r *fakeRepository) ListAdminEmails(ctx context.Context) ([]string, error) {
+	return r.admins, nil
+}
+
+func (r *fakeRepository) ListOptedOutEmails(ctx context.Context, event, channel string, emails []string) ([]string, error) {
+	return r.optedOut[event], nil
+}
+
+func newTestNotifier() (*notifier, *recordingSender) {
+	sender := &recordingSender{}
+	finder := fakeFinder{
+		1: {ID: 1, Codigo: "AP-001", Titulo: "Apartamento no centro", CorretorPrincipal: &imoveis.CorretorPrincipalResponse{Nome: "Rita", Email: "rita@example.com"}},
+		2: {ID: 2, Codigo: "CA-002", Titulo: "Casa sem corretor"},
+	}
+	repo := &fakeRepository{admins: []string{"admin@example.com", "ops@example.com"}, optedOut: map[string][]string{}}
+	return NewNotifier(sender, finder, repo).(*notifier), sender
+}
+
+func TestNotifier_ImportFinished(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+	started := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, n.importFinished(ctx, events.ImportFinished{
+		RunID: 12, Source: "pi8", Status: imoveis.ImportRunCompleted,
+		Total: 10, Created: 3, Updated: 5, Unchanged: 1, Failed: 1,
+		StartedAt: started, FinishedAt: started.Add(95 * time.Second),
+	}))
+
+	require.Len(t, sender.sent, 2, "each admin gets their own email")
+	assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].To)
+	assert.Equal(t, []string{"ops@example.com"}, sender.sent[1].To)
+	summary := sender.sent[0]
+	assert.Equal(t, "notification", summary.TemplateName)
+	assert.Equal(t, "Importação pi8 concluída com falhas", summary.Subject)
+	assert.Equal(t, "warning", summary.TemplateData["Type"])
+	details := summary.TemplateData["Details"].(map[string]string)
+	assert.Equal(t, "#12", details["Execução"])
+	assert.Equal(t, "3", details["Criados"])
+	assert.Equal(t, "1", details["Falhas"])
+	assert.Equal(t, "1m35s", details["Duração"])
+
+	sender.sent = nil
+	require.NoError(t, n.importFinished(ctx, events.ImportFinished{Source: "pi8", Status: imoveis.ImportRunFailed, Error: "external API unavailable"}))
+	require.Len(t, sender.sent, 2)
+	assert.Equal(t, "Importação pi8 interrompida", sender.sent[0].Subject)
+	assert.Equal(t, "error", sender.sent[0].TemplateData["Type"])
+	assert.Equal(t, "external API unavailable", sender.sent[0].TemplateData["AlertMessage"])
+	assert.NotContains(t, sender.sent[0].TemplateData["Details"], "Execução", "unrecorded runs have no ID")
+}
+
+func TestNotifier_LeadCreated(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+
+	require.NoError(t, n.leadCreated(ctx, events.LeadCreated{ImovelID: 1, Nome: "João", Email: "joao@example.com", Mensagem: "Posso visitar sábado?"}))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, []string{"rita@example.com"}, sender.sent[0].To)
+	assert.Equal(t, "Novo contato sobre o imóvel AP-001", sender.sent[0].Subject)
+	assert.Equal(t, "Posso visitar sábado?", sender.sent[0].TemplateData["AlertMessage"])
+	assert.Equal(t, "AP-001 — Apartamento no centro", sender.sent[0].TemplateData["Details"].(map[string]string)["Imóvel"])
+
+	// Without a corretor, or without a property, the admins are told
+	for _, imovelID := range []uint{2, 0, 99} {
+		sender.sent = nil
+		require.NoError(t, n.leadCreated(ctx, events.LeadCreated{ImovelID: imovelID, Nome: "Ana"}))
+		require.Len(t, sender.sent, 2, "imovel %d", imovelID)
+		assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].To)
+	}
+}
+
+func TestNotifier_OptedOut(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+	n.repo.(*fakeRepository).optedOut = map[string][]string{
+		EventImportSummary: {"ops@example.com"},
+		EventPriceChange:   {"rita@example.com"},
+	}
+
+	require.NoError(t, n.importFinished(ctx, events.ImportFinished{Source: "pi8", Status: imoveis.ImportRunCompleted}))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].To)
+
+	sender.sent = nil
+	require.NoError(t, n.priceChanged(ctx, events.PriceChanged{ImovelID: 1, Tipo: imoveis.HistoricoTipoVenda, PrecoAnterior: 1, Preco: 2}))
+	assert.Empty(t, sender.sent)
+
+	// Opting out of a notification leaves the others, and those without preference, alone
+	require.NoError(t, n.leadCreated(ctx, events.LeadCreated{ImovelID: 1, Nome: "João"}))
+	require.NoError(t, n.imovelPublished(ctx, events.ImovelPublished{ImovelID: 1, Origem: imoveis.PriceOriginAPI}))
+	assert.Len(t, sender.sent, 2)
+}
+
+func TestNotifier_ImovelPublished(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+
+	require.NoError(t, n.imovelPublished(ctx, events.ImovelPublished{ImovelID: 1, Origem: imoveis.PriceOriginAPI}))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, []string{"rita@example.com"}, sender.sent[0].To)
+	assert.Equal(t, "Imóvel AP-001 publicado", sender.sent[0].Subject)
+
+	// Scheduled publications are notified by the scheduler; properties without a corretor or
+	// deleted since are skipped
+	require.NoError(t, n.imovelPublished(ctx, events.ImovelPublished{ImovelID: 1, Origem: imoveis.PriceOriginScheduler}))
+	require.NoError(t, n.imovelPublished(ctx, events.ImovelPublished{ImovelID: 2, Origem: imoveis.PriceOriginAPI}))
+	require.NoError(t, n.imovelPublished(ctx, events.ImovelPublished{ImovelID: 99, Origem: imoveis.PriceOriginAPI}))
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestNotifier_PriceChanged(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+
+	require.NoError(t, n.priceChanged(ctx, events.PriceChanged{
+		ImovelID: 1, Tipo: imoveis.HistoricoTipoVenda, PrecoAnterior: 550000, Preco: 495000, Origem: imoveis.PriceOriginImport,
+	}))
+	require.NoError(t, n.priceChanged(ctx, events.PriceChanged{
+		ImovelID: 1, Tipo: imoveis.HistoricoTipoAluguel, PrecoAnterior: 2800, Preco: 2950.5,
+	}))
+
+	require.Len(t, sender.sent, 2)
+	assert.Equal(t, "Preço de venda do imóvel AP-001 alterado", sender.sent[0].Subject)
+	assert.Equal(t, map[string]string{
+		"Código":         "AP-001",
+		"Título":         "Apartamento no centro",
+		"Preço anterior": "R$ 550.000,00",
+		"Novo preço":     "R$ 495.000,00",
+		"Variação":       "-10,0%",
+	}, sender.sent[0].TemplateData["Details"])
+	assert.Equal(t, "Preço de aluguel do imóvel AP-001 alterado", sender.sent[1].Subject)
+	assert.Equal(t, "+5,4%", sender.sent[1].TemplateData["Details"].(map[string]string)["Variação"])
+}
+
+func TestNotifier_LoginLocked(t *testing.T) {
+	ctx := context.Background()
+	n, sender := newTestNotifier()
+	until := time.Date(2026, 10, 17, 9, 15, 0, 0, time.UTC)
+
+	require.NoError(t, n.loginLocked(ctx, events.LoginLocked{
+		UserID: 7, Email: "ana@example.com", Name: "Ana", IPAddress: "10.0.0.1", Attempts: 5, LockedUntil: until,
+	}))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, []string{"ana@example.com"}, sender.sent[0].To)
+	assert.Equal(t, "Sua conta foi bloqueada temporariamente", sender.sent[0].Subject)
+	assert.Equal(t, map[string]string{
+		"Tentativas":    "5",
+		"Bloqueada até": "17/10/2026 09:15",
+		"Endereço IP":   "10.0.0.1",
+	}, sender.sent[0].TemplateData["Details"])
+
+	// Address lockouts and emails without account are not sent
+	require.NoError(t, n.loginLocked(ctx, events.LoginLocked{IPAddress: "10.0.0.1", Attempts: 20, LockedUntil: until}))
+	require.NoError(t, n.loginLocked(ctx, events.LoginLocked{Email: "nobody@example.com", Attempts: 5, LockedUntil: until}))
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestNotifier_Subscribe(t *testing.T) {
+	n, sender := newTestNotifier()
+	bus := events.NewBus(1, 10)
+	n.Subscribe(bus)
+
+	bus.Publish(events.ImovelPublished{ImovelID: 1, Origem: imoveis.PriceOriginAPI})
+	require.NoError(t, bus.Close(context.Background()))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Imóvel AP-001 publicado", sender.sent[0].Subject)
+}
+
+func TestFormatBRL(t *testing.T) {
+	assert.Equal(t, "R$ 0,00", formatBRL(0))
+	assert.Equal(t, "R$ 999,99", formatBRL(999.99))
+	assert.Equal(t, "R$ 1.250.000,00", formatBRL(1250000))
+	assert.Equal(t, "-R$ 1.000,50", formatBRL(-1000.5))
+}
+
+func TestRepository_ListAdminEmails(t *testing.T) {
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE roles (id INTEGER PRIMARY KEY, name TEXT)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE user_roles (user_id INTEGER, role_id INTEGER)`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO roles (id, name) VALUES (1, 'user'), (2, 'admin')`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO users (id, email, deleted_at) VALUES
+		(1, 'ana@example.com', NULL),
+		(2, 'bruno@example.com', NULL),
+		(3, 'carla@example.com', '2026-01-01 00:00:00')`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO user_roles (user_id, role_id) VALUES (1, 1), (2, 1), (2, 2), (3, 2)`).Error)
+
+	emails, err := NewRepository(database).ListAdminEmails(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bruno@example.com"}, emails, "deleted admins are skipped")
+}
+
+func TestRepository_Preferences(t *testing.T) {
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, database.AutoMigrate(&Preference{}))
+	require.NoError(t, database.Exec(`INSERT INTO users (id, email, deleted_at) VALUES
+		(1, 'Ana@Example.com', NULL),
+		(2, 'bruno@example.com', NULL),
+		(3, 'carla@example.com', '2026-01-01 00:00:00')`).Error)
+
+	ctx := context.Background()
+	service := NewPreferenceService(NewRepository(database))
+	off, on := false, true
+
+	preferences, err := service.GetPreferences(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, preferences, len(Events))
+	for _, preference := range preferences {
+		assert.True(t, preference.Enabled, "%s is received by default", preference.Event)
+	}
+
+	preferences, err = service.UpdatePreferences(ctx, 1, &UpdatePreferencesRequest{Preferences: []PreferenceRequest{
+		{Event: EventPriceChange, Channel: ChannelEmail, Enabled: &on},
+		{Event: EventPriceChange, Channel: ChannelEmail, Enabled: &off},
+		{Event: EventNewLead, Channel: ChannelEmail, Enabled: &off},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []PreferenceResponse{
+		{Event: EventNewLead, Channel: ChannelEmail, Enabled: false},
+		{Event: EventImportSummary, Channel: ChannelEmail, Enabled: true},
+		{Event: EventPriceChange, Channel: ChannelEmail, Enabled: false},
+	}, preferences)
+
+	_, err = service.UpdatePreferences(ctx, 1, &UpdatePreferencesRequest{Preferences: []PreferenceRequest{
+		{Event: EventNewLead, Channel: ChannelEmail, Enabled: &on},
+	}})
+	require.NoError(t, err)
+	_, err = service.UpdatePreferences(ctx, 3, &UpdatePreferencesRequest{Preferences: []PreferenceRequest{
+		{Event: EventPriceChange, Channel: ChannelEmail, Enabled: &off},
+	}})
+	require.NoError(t, err)
+
+	repo := NewRepository(database)
+	optedOut, err := repo.ListOptedOutEmails(ctx, EventPriceChange, ChannelEmail, []string{"ana@example.com", "bruno@example.com", "carla@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ana@example.com"}, optedOut, "deleted users are skipped")
+
+	optedOut, err = repo.ListOptedOutEmails(ctx, EventNewLead, ChannelEmail, []string{"ANA@example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, optedOut, "opted back in")
+}
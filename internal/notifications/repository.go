@@ -0,0 +1,86 @@
+package notifications
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/user"
+)
+
+// Repository reads the recipients of the notifications and stores their preferences
+type Repository interface {
+	// ListAdminEmails returns the email of every active admin user
+	ListAdminEmails(ctx context.Context) ([]string, error)
+	// ListOptedOutEmails returns, lowercased, those of emails whose user opted out of event
+	// through channel
+	ListOptedOutEmails(ctx context.Context, event, channel string, emails []string) ([]string, error)
+	ListPreferences(ctx context.Context, userID uint) ([]Preference, error)
+	SavePreferences(ctx context.Context, preferences []Preference) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new notifications repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ListAdminEmails returns the email of every active admin user
+func (r *repository) ListAdminEmails(ctx context.Context) ([]string, error) {
+	var emails []string
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Joins("JOIN user_roles ON user_roles.user_id = users.id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name = ? AND users.deleted_at IS NULL", user.RoleAdmin).
+		Distinct().
+		Order("users.email").
+		Pluck("users.email", &emails).Error
+	return emails, err
+}
+
+// ListOptedOutEmails returns, lowercased, those of emails whose user opted out of event through
+// channel. Emails are matched case-insensitively, as the corretor of a property may have been
+// registered with another case than their user.
+func (r *repository) ListOptedOutEmails(ctx context.Context, event, channel string, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	var optedOut []string
+	err := r.db.WithContext(ctx).
+		Table("notification_preferences").
+		Joins("JOIN users ON users.id = notification_preferences.user_id").
+		Where("notification_preferences.event = ? AND notification_preferences.channel = ? AND NOT notification_preferences.enabled", event, channel).
+		Where("LOWER(users.email) IN ? AND users.deleted_at IS NULL", lowered).
+		Distinct().
+		Pluck("LOWER(users.email)", &optedOut).Error
+	return optedOut, err
+}
+
+// ListPreferences returns the stored preferences of the user
+func (r *repository) ListPreferences(ctx context.Context, userID uint) ([]Preference, error) {
+	var preferences []Preference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// SavePreferences creates the preferences, or updates those already set
+func (r *repository) SavePreferences(ctx context.Context, preferences []Preference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+)
+
+// PreferenceService manages the notification preferences of the users
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID uint) ([]PreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID uint, req *UpdatePreferencesRequest) ([]PreferenceResponse, error)
+}
+
+type preferenceService struct {
+	repo Repository
+}
+
+// NewPreferenceService creates a new notification preference service
+func NewPreferenceService(repo Repository) PreferenceService {
+	return &preferenceService{repo: repo}
+}
+
+// GetPreferences returns every notification and channel with whether the user receives it
+func (s *preferenceService) GetPreferences(ctx context.Context, userID uint) ([]PreferenceResponse, error) {
+	stored, err := s.repo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+
+	enabled := make(map[[2]string]bool, len(stored))
+	for _, preference := range stored {
+		enabled[[2]string{preference.Event, preference.Channel}] = preference.Enabled
+	}
+	preferences := make([]PreferenceResponse, 0, len(Events)*len(Channels))
+	for _, event := range Events {
+		for _, channel := range Channels {
+			on, ok := enabled[[2]string{event, channel}]
+			preferences = append(preferences, PreferenceResponse{Event: event, Channel: channel, Enabled: on || !ok})
+		}
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences stores the preferences of req, returning all the preferences of the user
+func (s *preferenceService) UpdatePreferences(ctx context.Context, userID uint, req *UpdatePreferencesRequest) ([]PreferenceResponse, error) {
+	// A notification and channel given twice keeps its last value, as one upsert cannot change a
+	// row twice
+	preferences := make([]Preference, 0, len(req.Preferences))
+	index := make(map[[2]string]int, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		key := [2]string{preference.Event, preference.Channel}
+		if i, ok := index[key]; ok {
+			preferences[i].Enabled = *preference.Enabled
+			continue
+		}
+		index[key] = len(preferences)
+		preferences = append(preferences, Preference{
+			UserID:  userID,
+			Event:   preference.Event,
+			Channel: preference.Channel,
+			Enabled: *preference.Enabled,
+		})
+	}
+	if err := s.repo.SavePreferences(ctx, preferences); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return s.GetPreferences(ctx, userID)
+}
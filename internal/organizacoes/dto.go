@@ -0,0 +1,56 @@
+package organizacoes
+
+import (
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// maxDiasArquivamento bounds the archive period of an agency
+const maxDiasArquivamento = 3650
+
+// CreateOrganizacaoRequest represents agency creation request. DiasArquivamento archives published
+// imoveis not updated for that many days; absent uses the configured default and 0 never archives.
+type CreateOrganizacaoRequest struct {
+	Nome             string `json:"nome" binding:"required,min=2,max=255"`
+	Perfil           string `json:"perfil" binding:"omitempty,max=100"`
+	DiasArquivamento *int   `json:"dias_arquivamento" binding:"omitempty,min=0,max=3650"`
+}
+
+// UpdateOrganizacaoRequest represents agency update request; absent fields are left untouched and
+// a null dias_arquivamento restores the configured default
+type UpdateOrganizacaoRequest struct {
+	Nome             string                `json:"nome" binding:"omitempty,min=2,max=255"`
+	Perfil           *string               `json:"perfil" binding:"omitempty,max=100"`
+	DiasArquivamento imoveis.Nullable[int] `json:"dias_arquivamento" swaggertype:"integer"`
+}
+
+// OrganizacaoListQuery represents query parameters for listing agencies
+type OrganizacaoListQuery struct {
+	Page  int    `form:"page,default=1" binding:"min=1"`
+	Limit int    `form:"limit,default=10" binding:"min=1,max=100"`
+	Nome  string `form:"nome" binding:"omitempty,max=255"`
+}
+
+// OrganizacaoResponse represents agency response; dias_arquivamento is null when the configured
+// default applies
+type OrganizacaoResponse struct {
+	ID               uint      `json:"id"`
+	Nome             string    `json:"nome"`
+	Perfil           string    `json:"perfil"`
+	DiasArquivamento *int      `json:"dias_arquivamento"`
+	TotalCorretores  int64     `json:"total_corretores"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// OrganizacaoListResponse represents paginated agency list response
+type OrganizacaoListResponse struct {
+	Total   int64                 `json:"total"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+	Pages   int64                 `json:"pages"`
+	HasNext bool                  `json:"hasNext"`
+	HasPrev bool                  `json:"hasPrev"`
+	Results []OrganizacaoResponse `json:"results"`
+}
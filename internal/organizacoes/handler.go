@@ -0,0 +1,177 @@
+package organizacoes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+)
+
+// Handler defines HTTP handlers for agency operations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new agency handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type uriRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Create agency
+// @Description Create a new organizacao; names are unique (case-insensitive)
+// @Tags organizacoes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrganizacaoRequest true "Organizacao creation request"
+// @Success 201 {object} errors.Response{success=bool,data=OrganizacaoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes [post]
+func (h *Handler) CreateOrganizacao(c *gin.Context) {
+	var req CreateOrganizacaoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	organizacao, err := h.service.CreateOrganizacao(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(organizacao))
+}
+
+// @Summary List agencies
+// @Description List organizacoes with their number of corretores
+// @Tags organizacoes
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param nome query string false "Filter by name (partial, case-insensitive)"
+// @Success 200 {object} errors.Response{success=bool,data=OrganizacaoListResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes [get]
+func (h *Handler) ListOrganizacoes(c *gin.Context) {
+	var query OrganizacaoListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	result, err := h.service.ListOrganizacoes(c.Request.Context(), &query)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(result))
+}
+
+// @Summary Get agency by ID
+// @Description Retrieve an organizacao with its number of corretores
+// @Tags organizacoes
+// @Accept json
+// @Produce json
+// @Param id path uint true "Organizacao ID"
+// @Success 200 {object} errors.Response{success=bool,data=OrganizacaoResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes/{id} [get]
+func (h *Handler) GetOrganizacao(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	organizacao, err := h.service.GetOrganizacao(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(organizacao))
+}
+
+// @Summary Update agency
+// @Description Update the provided fields of an organizacao
+// @Tags organizacoes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Organizacao ID"
+// @Param request body UpdateOrganizacaoRequest true "Organizacao update request"
+// @Success 200 {object} errors.Response{success=bool,data=OrganizacaoResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes/{id} [put]
+func (h *Handler) UpdateOrganizacao(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdateOrganizacaoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	organizacao, err := h.service.UpdateOrganizacao(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(organizacao))
+}
+
+// @Summary Delete agency
+// @Description Soft delete an organizacao; fails while it still has corretores
+// @Tags organizacoes
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Organizacao ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/organizacoes/{id} [delete]
+func (h *Handler) DeleteOrganizacao(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.DeleteOrganizacao(c.Request.Context(), uri.ID); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrOrganizacaoNotFound):
+		_ = c.Error(apiErrors.NotFound("Organizacao not found"))
+	case errors.Is(err, ErrNomeExists), errors.Is(err, ErrHasCorretores):
+		_ = c.Error(apiErrors.Conflict(err.Error()))
+	case errors.Is(err, ErrInvalidDiasArquivamento):
+		_ = c.Error(apiErrors.ValidationError(map[string]string{"dias_arquivamento": err.Error()}))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
@@ -0,0 +1,125 @@
+package organizacoes
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Repository defines agency repository interface
+type Repository interface {
+	Create(ctx context.Context, organizacao *imoveis.Organizacao) error
+	FindByID(ctx context.Context, id uint) (*imoveis.Organizacao, error)
+	FindByNome(ctx context.Context, nome string) (*imoveis.Organizacao, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, query *OrganizacaoListQuery) ([]imoveis.Organizacao, int64, error)
+	CountCorretores(ctx context.Context, ids []uint) (map[uint]int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new agency repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create creates a new agency
+func (r *repository) Create(ctx context.Context, organizacao *imoveis.Organizacao) error {
+	return r.db.WithContext(ctx).Create(organizacao).Error
+}
+
+// FindByID finds an agency by ID
+func (r *repository) FindByID(ctx context.Context, id uint) (*imoveis.Organizacao, error) {
+	var organizacao imoveis.Organizacao
+	if err := r.db.WithContext(ctx).First(&organizacao, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &organizacao, nil
+}
+
+// FindByNome finds an agency by case-insensitive name; the importer matches agencies by name
+func (r *repository) FindByNome(ctx context.Context, nome string) (*imoveis.Organizacao, error) {
+	var organizacao imoveis.Organizacao
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(nome) = LOWER(?)", nome).
+		First(&organizacao).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &organizacao, nil
+}
+
+// Update updates the given columns of an agency
+func (r *repository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&imoveis.Organizacao{ID: id}).
+		Updates(updates).Error
+}
+
+// Delete soft deletes an agency
+func (r *repository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&imoveis.Organizacao{}, id).Error
+}
+
+// List retrieves agencies with pagination, ordered by name
+func (r *repository) List(ctx context.Context, query *OrganizacaoListQuery) ([]imoveis.Organizacao, int64, error) {
+	var organizacoes []imoveis.Organizacao
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&imoveis.Organizacao{})
+	if query.Nome != "" {
+		db = db.Where("LOWER(nome) LIKE LOWER(?)", "%"+query.Nome+"%")
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	if err := db.
+		Order("nome ASC, id ASC").
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&organizacoes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return organizacoes, total, nil
+}
+
+// CountCorretores counts the non-deleted agents of each agency
+func (r *repository) CountCorretores(ctx context.Context, ids []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		OrganizacaoID uint
+		Total         int64
+	}
+	if err := r.db.WithContext(ctx).
+		Table("corretores_principais").
+		Select("organizacao_id, COUNT(*) AS total").
+		Where("organizacao_id IN ? AND deleted_at IS NULL", ids).
+		Group("organizacao_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.OrganizacaoID] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,197 @@
+package organizacoes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrOrganizacaoNotFound is returned when an agency does not exist
+	ErrOrganizacaoNotFound = errors.New("organizacao not found")
+	// ErrNomeExists is returned when another agency already uses the name
+	ErrNomeExists = errors.New("organizacao with this nome already exists")
+	// ErrHasCorretores is returned when deleting an agency that still has agents
+	ErrHasCorretores = errors.New("organizacao still has corretores")
+	// ErrInvalidDiasArquivamento is returned for archive periods out of range
+	ErrInvalidDiasArquivamento = fmt.Errorf("dias_arquivamento must be between 0 and %d", maxDiasArquivamento)
+)
+
+// Service defines agency service interface
+type Service interface {
+	CreateOrganizacao(ctx context.Context, req *CreateOrganizacaoRequest) (*OrganizacaoResponse, error)
+	GetOrganizacao(ctx context.Context, id uint) (*OrganizacaoResponse, error)
+	UpdateOrganizacao(ctx context.Context, id uint, req *UpdateOrganizacaoRequest) (*OrganizacaoResponse, error)
+	DeleteOrganizacao(ctx context.Context, id uint) error
+	ListOrganizacoes(ctx context.Context, query *OrganizacaoListQuery) (*OrganizacaoListResponse, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new agency service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreateOrganizacao creates a new agency
+func (s *service) CreateOrganizacao(ctx context.Context, req *CreateOrganizacaoRequest) (*OrganizacaoResponse, error) {
+	nome := strings.TrimSpace(req.Nome)
+	if err := s.ensureNomeAvailable(ctx, 0, nome); err != nil {
+		return nil, err
+	}
+
+	organizacao := &imoveis.Organizacao{
+		Nome:             nome,
+		Perfil:           strings.TrimSpace(req.Perfil),
+		DiasArquivamento: req.DiasArquivamento,
+	}
+	if err := s.repo.Create(ctx, organizacao); err != nil {
+		return nil, fmt.Errorf("failed to create organizacao: %w", err)
+	}
+
+	return toResponse(organizacao, 0), nil
+}
+
+// GetOrganizacao retrieves an agency by ID with its number of agents
+func (s *service) GetOrganizacao(ctx context.Context, id uint) (*OrganizacaoResponse, error) {
+	organizacao, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountCorretores(ctx, []uint{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count corretores: %w", err)
+	}
+
+	return toResponse(organizacao, counts[id]), nil
+}
+
+// UpdateOrganizacao updates the provided fields of an agency
+func (s *service) UpdateOrganizacao(ctx context.Context, id uint, req *UpdateOrganizacaoRequest) (*OrganizacaoResponse, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Nome != "" {
+		nome := strings.TrimSpace(req.Nome)
+		if err := s.ensureNomeAvailable(ctx, id, nome); err != nil {
+			return nil, err
+		}
+		updates["nome"] = nome
+	}
+	if req.Perfil != nil {
+		updates["perfil"] = strings.TrimSpace(*req.Perfil)
+	}
+	if req.DiasArquivamento.Set {
+		if req.DiasArquivamento.Null {
+			updates["dias_arquivamento"] = nil
+		} else if dias := req.DiasArquivamento.Value; dias < 0 || dias > maxDiasArquivamento {
+			return nil, ErrInvalidDiasArquivamento
+		} else {
+			updates["dias_arquivamento"] = dias
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update organizacao: %w", err)
+		}
+	}
+
+	return s.GetOrganizacao(ctx, id)
+}
+
+// DeleteOrganizacao soft deletes an agency; agents must be moved or removed first
+func (s *service) DeleteOrganizacao(ctx context.Context, id uint) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+
+	counts, err := s.repo.CountCorretores(ctx, []uint{id})
+	if err != nil {
+		return fmt.Errorf("failed to count corretores: %w", err)
+	}
+	if counts[id] > 0 {
+		return fmt.Errorf("%w: %d corretores must be reassigned first", ErrHasCorretores, counts[id])
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete organizacao: %w", err)
+	}
+	return nil
+}
+
+// ListOrganizacoes lists agencies with their number of agents
+func (s *service) ListOrganizacoes(ctx context.Context, query *OrganizacaoListQuery) (*OrganizacaoListResponse, error) {
+	organizacoes, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizacoes: %w", err)
+	}
+
+	ids := make([]uint, len(organizacoes))
+	for i := range organizacoes {
+		ids[i] = organizacoes[i].ID
+	}
+	counts, err := s.repo.CountCorretores(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count corretores: %w", err)
+	}
+
+	results := make([]OrganizacaoResponse, len(organizacoes))
+	for i := range organizacoes {
+		results[i] = *toResponse(&organizacoes[i], counts[organizacoes[i].ID])
+	}
+
+	pages := (total + int64(query.Limit) - 1) / int64(query.Limit)
+	return &OrganizacaoListResponse{
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		Pages:   pages,
+		HasNext: int64(query.Page) < pages,
+		HasPrev: query.Page > 1,
+		Results: results,
+	}, nil
+}
+
+func (s *service) find(ctx context.Context, id uint) (*imoveis.Organizacao, error) {
+	organizacao, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organizacao: %w", err)
+	}
+	if organizacao == nil {
+		return nil, ErrOrganizacaoNotFound
+	}
+	return organizacao, nil
+}
+
+// ensureNomeAvailable rejects a name used by another agency, since the importer matches agencies by name
+func (s *service) ensureNomeAvailable(ctx context.Context, id uint, nome string) error {
+	existing, err := s.repo.FindByNome(ctx, nome)
+	if err != nil {
+		return fmt.Errorf("failed to check organizacao nome: %w", err)
+	}
+	if existing != nil && existing.ID != id {
+		return ErrNomeExists
+	}
+	return nil
+}
+
+func toResponse(o *imoveis.Organizacao, totalCorretores int64) *OrganizacaoResponse {
+	return &OrganizacaoResponse{
+		ID:               o.ID,
+		Nome:             o.Nome,
+		Perfil:           o.Perfil,
+		DiasArquivamento: o.DiasArquivamento,
+		TotalCorretores:  totalCorretores,
+		CreatedAt:        o.CreatedAt,
+		UpdatedAt:        o.UpdatedAt,
+	}
+}
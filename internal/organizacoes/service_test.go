@@ -0,0 +1,130 @@
+package organizacoes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+func setupService(t *testing.T) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&imoveis.Organizacao{}))
+	require.NoError(t, database.Exec(`CREATE TABLE corretores_principais (
+		id INTEGER PRIMARY KEY,
+		organizacao_id INTEGER,
+		deleted_at DATETIME
+	)`).Error)
+
+	return NewService(NewRepository(database)), database
+}
+
+func TestCreateOrganizacao_UniqueNome(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: " Imobiliária Sol ", Perfil: "IMOBILIARIA"})
+	require.NoError(t, err)
+	assert.Equal(t, "Imobiliária Sol", created.Nome)
+
+	_, err = svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "imobiliária sol"})
+	assert.ErrorIs(t, err, ErrNomeExists)
+
+	other, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "Imobiliária Lua"})
+	require.NoError(t, err)
+
+	_, err = svc.UpdateOrganizacao(ctx, other.ID, &UpdateOrganizacaoRequest{Nome: "Imobiliária Sol"})
+	assert.ErrorIs(t, err, ErrNomeExists)
+
+	perfil := "CORRETOR_AUTONOMO"
+	updated, err := svc.UpdateOrganizacao(ctx, other.ID, &UpdateOrganizacaoRequest{Nome: "IMOBILIÁRIA LUA", Perfil: &perfil})
+	require.NoError(t, err)
+	assert.Equal(t, "IMOBILIÁRIA LUA", updated.Nome)
+	assert.Equal(t, perfil, updated.Perfil)
+}
+
+func TestListOrganizacoes_CountsCorretores(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	sol, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "Sol"})
+	require.NoError(t, err)
+	lua, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "Lua"})
+	require.NoError(t, err)
+
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, organizacao_id, deleted_at) VALUES
+		(1, ?, NULL), (2, ?, NULL), (3, ?, '2026-01-01 00:00:00')`, sol.ID, sol.ID, sol.ID).Error)
+
+	result, err := svc.ListOrganizacoes(ctx, &OrganizacaoListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "Lua", result.Results[0].Nome)
+	assert.Equal(t, int64(0), result.Results[0].TotalCorretores)
+	assert.Equal(t, "Sol", result.Results[1].Nome)
+	assert.Equal(t, int64(2), result.Results[1].TotalCorretores)
+
+	filtered, err := svc.ListOrganizacoes(ctx, &OrganizacaoListQuery{Page: 1, Limit: 10, Nome: "lu"})
+	require.NoError(t, err)
+	require.Len(t, filtered.Results, 1)
+	assert.Equal(t, lua.ID, filtered.Results[0].ID)
+}
+
+func TestDeleteOrganizacao(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	sol, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "Sol"})
+	require.NoError(t, err)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, organizacao_id) VALUES (1, ?)`, sol.ID).Error)
+
+	assert.ErrorIs(t, svc.DeleteOrganizacao(ctx, sol.ID), ErrHasCorretores)
+
+	require.NoError(t, database.Exec(`UPDATE corretores_principais SET organizacao_id = NULL`).Error)
+	require.NoError(t, svc.DeleteOrganizacao(ctx, sol.ID))
+
+	_, err = svc.GetOrganizacao(ctx, sol.ID)
+	assert.ErrorIs(t, err, ErrOrganizacaoNotFound)
+	assert.ErrorIs(t, svc.DeleteOrganizacao(ctx, sol.ID), ErrOrganizacaoNotFound)
+}
+
+func TestUpdateOrganizacao_DiasArquivamento(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	dias := 60
+	sol, err := svc.CreateOrganizacao(ctx, &CreateOrganizacaoRequest{Nome: "Sol", DiasArquivamento: &dias})
+	require.NoError(t, err)
+	require.NotNil(t, sol.DiasArquivamento)
+	assert.Equal(t, 60, *sol.DiasArquivamento)
+
+	updated, err := svc.UpdateOrganizacao(ctx, sol.ID, &UpdateOrganizacaoRequest{
+		DiasArquivamento: imoveis.Nullable[int]{Set: true, Value: 0},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, updated.DiasArquivamento)
+	assert.Zero(t, *updated.DiasArquivamento)
+
+	_, err = svc.UpdateOrganizacao(ctx, sol.ID, &UpdateOrganizacaoRequest{
+		DiasArquivamento: imoveis.Nullable[int]{Set: true, Value: -1},
+	})
+	assert.ErrorIs(t, err, ErrInvalidDiasArquivamento)
+
+	// Absent keeps the period, null restores the default
+	updated, err = svc.UpdateOrganizacao(ctx, sol.ID, &UpdateOrganizacaoRequest{Nome: "Sol Imóveis"})
+	require.NoError(t, err)
+	assert.NotNil(t, updated.DiasArquivamento)
+
+	updated, err = svc.UpdateOrganizacao(ctx, sol.ID, &UpdateOrganizacaoRequest{
+		DiasArquivamento: imoveis.Nullable[int]{Set: true, Null: true},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, updated.DiasArquivamento)
+}
@@ -0,0 +1,34 @@
+package pagination
+
+// Page is the envelope of every paginated list: one page of results with the metadata to fetch
+// the others. Lists paged by cursor leave Total and Pages empty and set NextCursor instead.
+type Page[T any] struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Pages      int64  `json:"pages"`
+	HasNext    bool   `json:"hasNext"`
+	HasPrev    bool   `json:"hasPrev"`
+	Results    []T    `json:"results"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// New wraps page number page of limit results, out of total, in the envelope
+func New[T any](results []T, total int64, page, limit int) *Page[T] {
+	if results == nil {
+		results = []T{}
+	}
+	var pages int64
+	if limit > 0 {
+		pages = (total + int64(limit) - 1) / int64(limit)
+	}
+	return &Page[T]{
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		Pages:   pages,
+		HasNext: int64(page) < pages,
+		HasPrev: page > 1,
+		Results: results,
+	}
+}
@@ -0,0 +1,43 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int64
+		page, limit int
+		want        Page[int]
+	}{
+		{"first page", 25, 1, 10, Page[int]{Total: 25, Page: 1, Limit: 10, Pages: 3, HasNext: true}},
+		{"middle page", 25, 2, 10, Page[int]{Total: 25, Page: 2, Limit: 10, Pages: 3, HasNext: true, HasPrev: true}},
+		{"last page", 25, 3, 10, Page[int]{Total: 25, Page: 3, Limit: 10, Pages: 3, HasPrev: true}},
+		{"past the end", 25, 5, 10, Page[int]{Total: 25, Page: 5, Limit: 10, Pages: 3, HasPrev: true}},
+		{"empty", 0, 1, 10, Page[int]{Page: 1, Limit: 10}},
+		{"no limit", 5, 1, 0, Page[int]{Total: 5, Page: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New[int](nil, tt.total, tt.page, tt.limit)
+			tt.want.Results = []int{}
+			assert.Equal(t, &tt.want, got)
+		})
+	}
+}
+
+func TestPage_JSON(t *testing.T) {
+	data, err := json.Marshal(New([]string{"a"}, 1, 1, 10))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":1,"page":1,"limit":10,"pages":1,"hasNext":false,"hasPrev":false,"results":["a"]}`, string(data))
+
+	// An empty page still lists its results, as [] rather than null
+	data, err = json.Marshal(New[string](nil, 0, 1, 10))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"results":[]`)
+}
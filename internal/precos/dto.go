@@ -0,0 +1,55 @@
+package precos
+
+// PacoteRequest represents package creation request, also used to set the package of a property
+type PacoteRequest struct {
+	Titulo     string `json:"titulo" binding:"required,max=255"`
+	Descricao  string `json:"descricao" binding:"omitempty,max=5000"`
+	Exclusivo  bool   `json:"exclusivo"`
+	EmDestaque bool   `json:"em_destaque"`
+}
+
+// UpdatePacoteRequest represents package update request; absent fields are left untouched
+type UpdatePacoteRequest struct {
+	Titulo     *string `json:"titulo" binding:"omitempty,min=1,max=255"`
+	Descricao  *string `json:"descricao" binding:"omitempty,max=5000"`
+	Exclusivo  *bool   `json:"exclusivo"`
+	EmDestaque *bool   `json:"em_destaque"`
+}
+
+// PrecoVendaRequest represents selling price creation request, also used to set the price of a property.
+// Ativo defaults to true when omitted.
+type PrecoVendaRequest struct {
+	Preco                       float64 `json:"preco" binding:"required,gt=0"`
+	AceitaFinanciamentoBancario bool    `json:"aceitaFinanciamentoBancario"`
+	AceitaFinanciamentoDireto   bool    `json:"aceitaFinanciamentoDireto"`
+	AceitaPermuta               bool    `json:"aceitaPermuta"`
+	AceitaCartaDeCredito        bool    `json:"aceitaCartaDeCredito"`
+	AceitaFGTS                  bool    `json:"aceitaFGTS"`
+	Ativo                       *bool   `json:"ativo"`
+}
+
+// UpdatePrecoVendaRequest represents selling price update request; absent fields are left untouched
+type UpdatePrecoVendaRequest struct {
+	Preco                       *float64 `json:"preco" binding:"omitempty,gt=0"`
+	AceitaFinanciamentoBancario *bool    `json:"aceitaFinanciamentoBancario"`
+	AceitaFinanciamentoDireto   *bool    `json:"aceitaFinanciamentoDireto"`
+	AceitaPermuta               *bool    `json:"aceitaPermuta"`
+	AceitaCartaDeCredito        *bool    `json:"aceitaCartaDeCredito"`
+	AceitaFGTS                  *bool    `json:"aceitaFGTS"`
+	Ativo                       *bool    `json:"ativo"`
+}
+
+// PrecoAluguelRequest represents rental price creation request, also used to set the price of a property.
+// Ativo defaults to true when omitted.
+type PrecoAluguelRequest struct {
+	Preco        float64 `json:"preco" binding:"required,gt=0"`
+	AceitaFiador bool    `json:"aceitaFiador"`
+	Ativo        *bool   `json:"ativo"`
+}
+
+// UpdatePrecoAluguelRequest represents rental price update request; absent fields are left untouched
+type UpdatePrecoAluguelRequest struct {
+	Preco        *float64 `json:"preco" binding:"omitempty,gt=0"`
+	AceitaFiador *bool    `json:"aceitaFiador"`
+	Ativo        *bool    `json:"ativo"`
+}
@@ -0,0 +1,406 @@
+package precos
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// Handler defines HTTP handlers for package and price operations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new pricing handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type uriRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// @Summary Create package
+// @Description Create a pacote that can be attached to imoveis
+// @Tags pacotes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PacoteRequest true "Pacote creation request"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.PacoteResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/pacotes [post]
+func (h *Handler) CreatePacote(c *gin.Context) {
+	var req PacoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	pacote, err := h.service.CreatePacote(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(pacote))
+}
+
+// @Summary Get package by ID
+// @Description Retrieve a pacote
+// @Tags pacotes
+// @Accept json
+// @Produce json
+// @Param id path uint true "Pacote ID"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PacoteResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/pacotes/{id} [get]
+func (h *Handler) GetPacote(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	pacote, err := h.service.GetPacote(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(pacote))
+}
+
+// @Summary Update package
+// @Description Update the provided fields of a pacote
+// @Tags pacotes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Pacote ID"
+// @Param request body UpdatePacoteRequest true "Pacote update request"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PacoteResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/pacotes/{id} [put]
+func (h *Handler) UpdatePacote(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdatePacoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	pacote, err := h.service.UpdatePacote(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(pacote))
+}
+
+// @Summary Create selling price
+// @Description Create a preco de venda that can be attached to imoveis
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PrecoVendaRequest true "Preco venda creation request"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.PrecoVendaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-venda [post]
+func (h *Handler) CreatePrecoVenda(c *gin.Context) {
+	var req PrecoVendaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.CreatePrecoVenda(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(preco))
+}
+
+// @Summary Get selling price by ID
+// @Description Retrieve a preco de venda
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Param id path uint true "Preco venda ID"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoVendaResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-venda/{id} [get]
+func (h *Handler) GetPrecoVenda(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.GetPrecoVenda(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// @Summary Update selling price
+// @Description Update the provided fields of a preco de venda
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Preco venda ID"
+// @Param request body UpdatePrecoVendaRequest true "Preco venda update request"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoVendaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-venda/{id} [put]
+func (h *Handler) UpdatePrecoVenda(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdatePrecoVendaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.UpdatePrecoVenda(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// @Summary Create rental price
+// @Description Create a preco de aluguel that can be attached to imoveis
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PrecoAluguelRequest true "Preco aluguel creation request"
+// @Success 201 {object} errors.Response{success=bool,data=imoveis.PrecoAluguelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-aluguel [post]
+func (h *Handler) CreatePrecoAluguel(c *gin.Context) {
+	var req PrecoAluguelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.CreatePrecoAluguel(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(preco))
+}
+
+// @Summary Get rental price by ID
+// @Description Retrieve a preco de aluguel
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Param id path uint true "Preco aluguel ID"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoAluguelResponse}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-aluguel/{id} [get]
+func (h *Handler) GetPrecoAluguel(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.GetPrecoAluguel(c.Request.Context(), uri.ID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// @Summary Update rental price
+// @Description Update the provided fields of a preco de aluguel
+// @Tags precos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Preco aluguel ID"
+// @Param request body UpdatePrecoAluguelRequest true "Preco aluguel update request"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoAluguelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/precos-aluguel/{id} [put]
+func (h *Handler) UpdatePrecoAluguel(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req UpdatePrecoAluguelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.UpdatePrecoAluguel(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// @Summary Set property package
+// @Description Overwrite the pacote of an imovel, creating and attaching a new one when it has none
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Imovel ID"
+// @Param request body PacoteRequest true "Pacote"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PacoteResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/pacote [put]
+func (h *Handler) SetImovelPacote(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PacoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	pacote, err := h.service.SetImovelPacote(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(pacote))
+}
+
+// @Summary Set property selling price
+// @Description Overwrite the preco de venda of an imovel, creating and attaching a new one when it has none
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Imovel ID"
+// @Param request body PrecoVendaRequest true "Preco venda"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoVendaResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/preco-venda [put]
+func (h *Handler) SetImovelPrecoVenda(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PrecoVendaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.SetImovelPrecoVenda(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// @Summary Set property rental price
+// @Description Overwrite the preco de aluguel of an imovel, creating and attaching a new one when it has none
+// @Tags imoveis
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Imovel ID"
+// @Param request body PrecoAluguelRequest true "Preco aluguel"
+// @Success 200 {object} errors.Response{success=bool,data=imoveis.PrecoAluguelResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/imoveis/{id}/preco-aluguel [put]
+func (h *Handler) SetImovelPrecoAluguel(c *gin.Context) {
+	var uri uriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	var req PrecoAluguelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	preco, err := h.service.SetImovelPrecoAluguel(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(preco))
+}
+
+// handleServiceError maps service errors to API errors
+func (h *Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPacoteNotFound):
+		_ = c.Error(apiErrors.NotFound("Pacote not found"))
+	case errors.Is(err, ErrPrecoVendaNotFound):
+		_ = c.Error(apiErrors.NotFound("Preco venda not found"))
+	case errors.Is(err, ErrPrecoAluguelNotFound):
+		_ = c.Error(apiErrors.NotFound("Preco aluguel not found"))
+	case errors.Is(err, ErrImovelNotFound):
+		_ = c.Error(apiErrors.NotFound("Imovel not found"))
+	case errors.Is(err, imoveis.ErrForbidden):
+		_ = c.Error(apiErrors.Forbidden("Imovel belongs to another corretor"))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
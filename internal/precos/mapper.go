@@ -0,0 +1,78 @@
+package precos
+
+import (
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+func newPacote(req *PacoteRequest) *imoveis.Pacote {
+	return &imoveis.Pacote{
+		Titulo:     strings.TrimSpace(req.Titulo),
+		Descricao:  strings.TrimSpace(req.Descricao),
+		Exclusivo:  req.Exclusivo,
+		EmDestaque: req.EmDestaque,
+	}
+}
+
+func newPrecoVenda(req *PrecoVendaRequest) *imoveis.PrecoVenda {
+	return &imoveis.PrecoVenda{
+		Preco:                       req.Preco,
+		AceitaFinanciamentoBancario: req.AceitaFinanciamentoBancario,
+		AceitaFinanciamentoDireto:   req.AceitaFinanciamentoDireto,
+		AceitaPermuta:               req.AceitaPermuta,
+		AceitaCartaDeCredito:        req.AceitaCartaDeCredito,
+		AceitaFGTS:                  req.AceitaFGTS,
+		Ativo:                       activeOrDefault(req.Ativo),
+	}
+}
+
+func newPrecoAluguel(req *PrecoAluguelRequest) *imoveis.PrecoAluguel {
+	return &imoveis.PrecoAluguel{
+		Preco:        req.Preco,
+		AceitaFiador: req.AceitaFiador,
+		Ativo:        activeOrDefault(req.Ativo),
+	}
+}
+
+func toPacoteResponse(p *imoveis.Pacote) *imoveis.PacoteResponse {
+	return &imoveis.PacoteResponse{
+		ID:         p.ID,
+		Titulo:     p.Titulo,
+		Descricao:  p.Descricao,
+		Exclusivo:  p.Exclusivo,
+		EmDestaque: p.EmDestaque,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+func toPrecoVendaResponse(p *imoveis.PrecoVenda) *imoveis.PrecoVendaResponse {
+	return &imoveis.PrecoVendaResponse{
+		ID:                          p.ID,
+		Preco:                       p.Preco,
+		AceitaFinanciamentoBancario: p.AceitaFinanciamentoBancario,
+		AceitaFinanciamentoDireto:   p.AceitaFinanciamentoDireto,
+		AceitaPermuta:               p.AceitaPermuta,
+		AceitaCartaDeCredito:        p.AceitaCartaDeCredito,
+		AceitaFGTS:                  p.AceitaFGTS,
+		Ativo:                       p.Ativo,
+		PacoteTitulo:                p.PacoteTitulo,
+		PacoteDescricao:             p.PacoteDescricao,
+		PacoteExclusivo:             p.PacoteExclusivo,
+		PacoteEmDestaque:            p.PacoteEmDestaque,
+		CreatedAt:                   p.CreatedAt,
+		UpdatedAt:                   p.UpdatedAt,
+	}
+}
+
+func toPrecoAluguelResponse(p *imoveis.PrecoAluguel) *imoveis.PrecoAluguelResponse {
+	return &imoveis.PrecoAluguelResponse{
+		ID:           p.ID,
+		Preco:        p.Preco,
+		AceitaFiador: p.AceitaFiador,
+		Ativo:        p.Ativo,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,196 @@
+package precos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+type txKey struct{}
+
+// Repository defines pricing repository interface
+type Repository interface {
+	CreatePacote(ctx context.Context, pacote *imoveis.Pacote) error
+	FindPacote(ctx context.Context, id uint) (*imoveis.Pacote, error)
+	UpdatePacote(ctx context.Context, id uint, updates map[string]interface{}) error
+
+	CreatePrecoVenda(ctx context.Context, preco *imoveis.PrecoVenda) error
+	FindPrecoVenda(ctx context.Context, id uint) (*imoveis.PrecoVenda, error)
+	UpdatePrecoVenda(ctx context.Context, id uint, updates map[string]interface{}) error
+
+	CreatePrecoAluguel(ctx context.Context, preco *imoveis.PrecoAluguel) error
+	FindPrecoAluguel(ctx context.Context, id uint) (*imoveis.PrecoAluguel, error)
+	UpdatePrecoAluguel(ctx context.Context, id uint, updates map[string]interface{}) error
+
+	FindImovel(ctx context.Context, id uint) (*imoveis.Imovel, error)
+	FindImovelIDsByPreco(ctx context.Context, column string, precoID uint) ([]uint, error)
+	FindPrecoOwners(ctx context.Context, column string, precoID uint) ([]imoveis.PropertyOwner, error)
+	RecordPriceHistory(ctx context.Context, imovelIDs []uint) ([]imoveis.HistoricoPreco, error)
+	RecordAudit(ctx context.Context, imovelID uint, entidade, acao string, before, after interface{}) error
+	UpdateImovel(ctx context.Context, id uint, updates map[string]interface{}) error
+
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new pricing repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// getDB returns the DB from context if in transaction, otherwise returns the repository's DB
+func (r *repository) getDB(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return r.db
+}
+
+// CreatePacote creates a new package; id_integracao is left NULL so its unique index only applies to imports
+func (r *repository) CreatePacote(ctx context.Context, pacote *imoveis.Pacote) error {
+	return r.getDB(ctx).WithContext(ctx).Omit("IdIntegracao").Create(pacote).Error
+}
+
+// FindPacote finds a package by ID
+func (r *repository) FindPacote(ctx context.Context, id uint) (*imoveis.Pacote, error) {
+	var pacote imoveis.Pacote
+	if err := r.getDB(ctx).WithContext(ctx).First(&pacote, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pacote, nil
+}
+
+// UpdatePacote updates the given columns of a package
+func (r *repository) UpdatePacote(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).
+		Model(&imoveis.Pacote{ID: id}).
+		Updates(updates).Error
+}
+
+// CreatePrecoVenda creates a new selling price
+func (r *repository) CreatePrecoVenda(ctx context.Context, preco *imoveis.PrecoVenda) error {
+	return r.getDB(ctx).WithContext(ctx).Omit("IdIntegracao").Create(preco).Error
+}
+
+// FindPrecoVenda finds a selling price by ID
+func (r *repository) FindPrecoVenda(ctx context.Context, id uint) (*imoveis.PrecoVenda, error) {
+	var preco imoveis.PrecoVenda
+	if err := r.getDB(ctx).WithContext(ctx).First(&preco, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preco, nil
+}
+
+// UpdatePrecoVenda updates the given columns of a selling price
+func (r *repository) UpdatePrecoVenda(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).
+		Model(&imoveis.PrecoVenda{ID: id}).
+		Updates(updates).Error
+}
+
+// CreatePrecoAluguel creates a new rental price
+func (r *repository) CreatePrecoAluguel(ctx context.Context, preco *imoveis.PrecoAluguel) error {
+	return r.getDB(ctx).WithContext(ctx).Omit("IdIntegracao").Create(preco).Error
+}
+
+// FindPrecoAluguel finds a rental price by ID
+func (r *repository) FindPrecoAluguel(ctx context.Context, id uint) (*imoveis.PrecoAluguel, error) {
+	var preco imoveis.PrecoAluguel
+	if err := r.getDB(ctx).WithContext(ctx).First(&preco, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preco, nil
+}
+
+// UpdatePrecoAluguel updates the given columns of a rental price
+func (r *repository) UpdatePrecoAluguel(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).
+		Model(&imoveis.PrecoAluguel{ID: id}).
+		Updates(updates).Error
+}
+
+// FindImovel loads only the pricing references of a property
+func (r *repository) FindImovel(ctx context.Context, id uint) (*imoveis.Imovel, error) {
+	var imovel imoveis.Imovel
+	if err := r.getDB(ctx).WithContext(ctx).
+		Select("id", "preco_venda_id", "preco_aluguel_id", "pacote_id", "corretor_principal_id").
+		First(&imovel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &imovel, nil
+}
+
+// UpdateImovel updates the given columns of a property
+func (r *repository) UpdateImovel(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.getDB(ctx).WithContext(ctx).
+		Model(&imoveis.Imovel{ID: id}).
+		Updates(updates).Error
+}
+
+// Transaction executes a function within a database transaction
+func (r *repository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, txKey{}, tx)
+		return fn(txCtx)
+	})
+}
+
+// FindImovelIDsByPreco returns the properties referencing a price; column is preco_venda_id or preco_aluguel_id
+func (r *repository) FindImovelIDsByPreco(ctx context.Context, column string, precoID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.getDB(ctx).WithContext(ctx).
+		Model(&imoveis.Imovel{}).
+		Where(clause.Eq{Column: clause.Column{Name: column}, Value: precoID}).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindPrecoOwners returns the corretor and organizacao of every property referencing a price or
+// package, including the properties outside the organizacao of ctx; column is one of the constant
+// price columns, never user input
+func (r *repository) FindPrecoOwners(ctx context.Context, column string, precoID uint) ([]imoveis.PropertyOwner, error) {
+	var owners []imoveis.PropertyOwner
+	// WHY: Raw is not filtered by db.TenantScope, so prices shared with another tenant are found;
+	// imoveis.CheckOwnersScope compares their organizacao with the request's
+	if err := r.getDB(ctx).WithContext(ctx).
+		Raw(fmt.Sprintf(`SELECT i.corretor_principal_id, COALESCE(c.organizacao_id, 0) AS organizacao_id
+			FROM imoveis i
+			LEFT JOIN corretores_principais c ON c.id = i.corretor_principal_id
+			WHERE i.%s = ? AND i.deleted_at IS NULL`, column), precoID).
+		Scan(&owners).Error; err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// RecordPriceHistory records the price changes of the given properties
+func (r *repository) RecordPriceHistory(ctx context.Context, imovelIDs []uint) ([]imoveis.HistoricoPreco, error) {
+	return imoveis.RecordPriceHistory(ctx, r.getDB(ctx), imovelIDs)
+}
+
+// RecordAudit records an audit entry of a property
+func (r *repository) RecordAudit(ctx context.Context, imovelID uint, entidade, acao string, before, after interface{}) error {
+	return imoveis.RecordAudit(ctx, r.getDB(ctx), imovelID, entidade, acao, before, after)
+}
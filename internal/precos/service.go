@@ -0,0 +1,490 @@
+package precos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+var (
+	// ErrPacoteNotFound is returned when a package does not exist
+	ErrPacoteNotFound = errors.New("pacote not found")
+	// ErrPrecoVendaNotFound is returned when a selling price does not exist
+	ErrPrecoVendaNotFound = errors.New("preco venda not found")
+	// ErrPrecoAluguelNotFound is returned when a rental price does not exist
+	ErrPrecoAluguelNotFound = errors.New("preco aluguel not found")
+	// ErrImovelNotFound is returned when the property to attach to does not exist
+	ErrImovelNotFound = errors.New("imovel not found")
+)
+
+// Service defines pricing service interface
+type Service interface {
+	CreatePacote(ctx context.Context, req *PacoteRequest) (*imoveis.PacoteResponse, error)
+	GetPacote(ctx context.Context, id uint) (*imoveis.PacoteResponse, error)
+	UpdatePacote(ctx context.Context, id uint, req *UpdatePacoteRequest) (*imoveis.PacoteResponse, error)
+
+	CreatePrecoVenda(ctx context.Context, req *PrecoVendaRequest) (*imoveis.PrecoVendaResponse, error)
+	GetPrecoVenda(ctx context.Context, id uint) (*imoveis.PrecoVendaResponse, error)
+	UpdatePrecoVenda(ctx context.Context, id uint, req *UpdatePrecoVendaRequest) (*imoveis.PrecoVendaResponse, error)
+
+	CreatePrecoAluguel(ctx context.Context, req *PrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error)
+	GetPrecoAluguel(ctx context.Context, id uint) (*imoveis.PrecoAluguelResponse, error)
+	UpdatePrecoAluguel(ctx context.Context, id uint, req *UpdatePrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error)
+
+	SetImovelPacote(ctx context.Context, imovelID uint, req *PacoteRequest) (*imoveis.PacoteResponse, error)
+	SetImovelPrecoVenda(ctx context.Context, imovelID uint, req *PrecoVendaRequest) (*imoveis.PrecoVendaResponse, error)
+	SetImovelPrecoAluguel(ctx context.Context, imovelID uint, req *PrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error)
+}
+
+type service struct {
+	repo   Repository
+	events events.Publisher
+}
+
+// ServiceOption configures optional service behaviour
+type ServiceOption func(*service)
+
+// WithEvents publishes the price changes made through the service to publisher
+func WithEvents(publisher events.Publisher) ServiceOption {
+	return func(s *service) {
+		s.events = publisher
+	}
+}
+
+// NewService creates a new pricing service
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreatePacote creates a new package
+func (s *service) CreatePacote(ctx context.Context, req *PacoteRequest) (*imoveis.PacoteResponse, error) {
+	pacote := newPacote(req)
+	if err := s.repo.CreatePacote(ctx, pacote); err != nil {
+		return nil, fmt.Errorf("failed to create pacote: %w", err)
+	}
+	return toPacoteResponse(pacote), nil
+}
+
+// GetPacote retrieves a package by ID
+func (s *service) GetPacote(ctx context.Context, id uint) (*imoveis.PacoteResponse, error) {
+	pacote, err := s.findPacote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toPacoteResponse(pacote), nil
+}
+
+// UpdatePacote updates the provided fields of a package
+func (s *service) UpdatePacote(ctx context.Context, id uint, req *UpdatePacoteRequest) (*imoveis.PacoteResponse, error) {
+	if _, err := s.findPacote(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwners(ctx, "pacote_id", id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Titulo != nil {
+		updates["titulo"] = strings.TrimSpace(*req.Titulo)
+	}
+	if req.Descricao != nil {
+		updates["descricao"] = strings.TrimSpace(*req.Descricao)
+	}
+	if req.Exclusivo != nil {
+		updates["exclusivo"] = *req.Exclusivo
+	}
+	if req.EmDestaque != nil {
+		updates["em_destaque"] = *req.EmDestaque
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdatePacote(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update pacote: %w", err)
+		}
+	}
+
+	return s.GetPacote(ctx, id)
+}
+
+// CreatePrecoVenda creates a new selling price
+func (s *service) CreatePrecoVenda(ctx context.Context, req *PrecoVendaRequest) (*imoveis.PrecoVendaResponse, error) {
+	preco := newPrecoVenda(req)
+	if err := s.repo.CreatePrecoVenda(ctx, preco); err != nil {
+		return nil, fmt.Errorf("failed to create preco venda: %w", err)
+	}
+	return toPrecoVendaResponse(preco), nil
+}
+
+// GetPrecoVenda retrieves a selling price by ID
+func (s *service) GetPrecoVenda(ctx context.Context, id uint) (*imoveis.PrecoVendaResponse, error) {
+	preco, err := s.findPrecoVenda(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toPrecoVendaResponse(preco), nil
+}
+
+// UpdatePrecoVenda updates the provided fields of a selling price
+func (s *service) UpdatePrecoVenda(ctx context.Context, id uint, req *UpdatePrecoVendaRequest) (*imoveis.PrecoVendaResponse, error) {
+	if _, err := s.findPrecoVenda(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwners(ctx, "preco_venda_id", id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Preco != nil {
+		updates["preco"] = *req.Preco
+	}
+	if req.AceitaFinanciamentoBancario != nil {
+		updates["aceita_financiamento_bancario"] = *req.AceitaFinanciamentoBancario
+	}
+	if req.AceitaFinanciamentoDireto != nil {
+		updates["aceita_financiamento_direto"] = *req.AceitaFinanciamentoDireto
+	}
+	if req.AceitaPermuta != nil {
+		updates["aceita_permuta"] = *req.AceitaPermuta
+	}
+	if req.AceitaCartaDeCredito != nil {
+		updates["aceita_carta_de_credito"] = *req.AceitaCartaDeCredito
+	}
+	if req.AceitaFGTS != nil {
+		updates["aceita_fgts"] = *req.AceitaFGTS
+	}
+	if req.Ativo != nil {
+		updates["ativo"] = *req.Ativo
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdatePrecoVenda(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update preco venda: %w", err)
+		}
+	}
+	if req.Preco != nil {
+		if err := s.recordPriceHistory(ctx, "preco_venda_id", id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPrecoVenda(ctx, id)
+}
+
+// CreatePrecoAluguel creates a new rental price
+func (s *service) CreatePrecoAluguel(ctx context.Context, req *PrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error) {
+	preco := newPrecoAluguel(req)
+	if err := s.repo.CreatePrecoAluguel(ctx, preco); err != nil {
+		return nil, fmt.Errorf("failed to create preco aluguel: %w", err)
+	}
+	return toPrecoAluguelResponse(preco), nil
+}
+
+// GetPrecoAluguel retrieves a rental price by ID
+func (s *service) GetPrecoAluguel(ctx context.Context, id uint) (*imoveis.PrecoAluguelResponse, error) {
+	preco, err := s.findPrecoAluguel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toPrecoAluguelResponse(preco), nil
+}
+
+// UpdatePrecoAluguel updates the provided fields of a rental price
+func (s *service) UpdatePrecoAluguel(ctx context.Context, id uint, req *UpdatePrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error) {
+	if _, err := s.findPrecoAluguel(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwners(ctx, "preco_aluguel_id", id); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Preco != nil {
+		updates["preco"] = *req.Preco
+	}
+	if req.AceitaFiador != nil {
+		updates["aceita_fiador"] = *req.AceitaFiador
+	}
+	if req.Ativo != nil {
+		updates["ativo"] = *req.Ativo
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdatePrecoAluguel(ctx, id, updates); err != nil {
+			return nil, fmt.Errorf("failed to update preco aluguel: %w", err)
+		}
+	}
+	if req.Preco != nil {
+		if err := s.recordPriceHistory(ctx, "preco_aluguel_id", id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPrecoAluguel(ctx, id)
+}
+
+// SetImovelPacote overwrites the package of a property, creating and attaching one when it has none
+func (s *service) SetImovelPacote(ctx context.Context, imovelID uint, req *PacoteRequest) (*imoveis.PacoteResponse, error) {
+	var pacoteID uint
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		imovel, err := s.findImovel(txCtx, imovelID)
+		if err != nil {
+			return err
+		}
+
+		if imovel.PacoteID != 0 {
+			existing, err := s.repo.FindPacote(txCtx, imovel.PacoteID)
+			if err != nil {
+				return fmt.Errorf("failed to find pacote: %w", err)
+			}
+			if existing != nil {
+				pacoteID = existing.ID
+				if err := s.repo.UpdatePacote(txCtx, existing.ID, map[string]interface{}{
+					"titulo":      strings.TrimSpace(req.Titulo),
+					"descricao":   strings.TrimSpace(req.Descricao),
+					"exclusivo":   req.Exclusivo,
+					"em_destaque": req.EmDestaque,
+				}); err != nil {
+					return err
+				}
+				updated, err := s.repo.FindPacote(txCtx, existing.ID)
+				if err != nil {
+					return fmt.Errorf("failed to find pacote: %w", err)
+				}
+				return s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePacote, imoveis.AuditAcaoUpdate, existing, updated)
+			}
+		}
+
+		pacote := newPacote(req)
+		if err := s.repo.CreatePacote(txCtx, pacote); err != nil {
+			return fmt.Errorf("failed to create pacote: %w", err)
+		}
+		pacoteID = pacote.ID
+		if err := s.repo.UpdateImovel(txCtx, imovelID, map[string]interface{}{"pacote_id": pacote.ID}); err != nil {
+			return err
+		}
+		return s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePacote, imoveis.AuditAcaoCreate, nil, pacote)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPacote(ctx, pacoteID)
+}
+
+// SetImovelPrecoVenda overwrites the selling price of a property, creating and attaching one when it has none
+func (s *service) SetImovelPrecoVenda(ctx context.Context, imovelID uint, req *PrecoVendaRequest) (*imoveis.PrecoVendaResponse, error) {
+	var precoID uint
+	var history []imoveis.HistoricoPreco
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		imovel, err := s.findImovel(txCtx, imovelID)
+		if err != nil {
+			return err
+		}
+
+		if imovel.PrecoVendaID != 0 {
+			existing, err := s.repo.FindPrecoVenda(txCtx, imovel.PrecoVendaID)
+			if err != nil {
+				return fmt.Errorf("failed to find preco venda: %w", err)
+			}
+			if existing != nil {
+				precoID = existing.ID
+				preco := newPrecoVenda(req)
+				if err := s.repo.UpdatePrecoVenda(txCtx, existing.ID, map[string]interface{}{
+					"preco":                         preco.Preco,
+					"aceita_financiamento_bancario": preco.AceitaFinanciamentoBancario,
+					"aceita_financiamento_direto":   preco.AceitaFinanciamentoDireto,
+					"aceita_permuta":                preco.AceitaPermuta,
+					"aceita_carta_de_credito":       preco.AceitaCartaDeCredito,
+					"aceita_fgts":                   preco.AceitaFGTS,
+					"ativo":                         preco.Ativo,
+				}); err != nil {
+					return err
+				}
+				updated, err := s.repo.FindPrecoVenda(txCtx, existing.ID)
+				if err != nil {
+					return fmt.Errorf("failed to find preco venda: %w", err)
+				}
+				if err := s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePrecoVenda, imoveis.AuditAcaoUpdate, existing, updated); err != nil {
+					return err
+				}
+				history, err = s.repo.RecordPriceHistory(txCtx, []uint{imovelID})
+				return err
+			}
+		}
+
+		preco := newPrecoVenda(req)
+		if err := s.repo.CreatePrecoVenda(txCtx, preco); err != nil {
+			return fmt.Errorf("failed to create preco venda: %w", err)
+		}
+		precoID = preco.ID
+		if err := s.repo.UpdateImovel(txCtx, imovelID, map[string]interface{}{"preco_venda_id": preco.ID}); err != nil {
+			return err
+		}
+		if err := s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePrecoVenda, imoveis.AuditAcaoCreate, nil, preco); err != nil {
+			return err
+		}
+		history, err = s.repo.RecordPriceHistory(txCtx, []uint{imovelID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishPriceChanges(history)
+
+	return s.GetPrecoVenda(ctx, precoID)
+}
+
+// SetImovelPrecoAluguel overwrites the rental price of a property, creating and attaching one when it has none
+func (s *service) SetImovelPrecoAluguel(ctx context.Context, imovelID uint, req *PrecoAluguelRequest) (*imoveis.PrecoAluguelResponse, error) {
+	var precoID uint
+	var history []imoveis.HistoricoPreco
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		imovel, err := s.findImovel(txCtx, imovelID)
+		if err != nil {
+			return err
+		}
+
+		if imovel.PrecoAluguelID != 0 {
+			existing, err := s.repo.FindPrecoAluguel(txCtx, imovel.PrecoAluguelID)
+			if err != nil {
+				return fmt.Errorf("failed to find preco aluguel: %w", err)
+			}
+			if existing != nil {
+				precoID = existing.ID
+				preco := newPrecoAluguel(req)
+				if err := s.repo.UpdatePrecoAluguel(txCtx, existing.ID, map[string]interface{}{
+					"preco":         preco.Preco,
+					"aceita_fiador": preco.AceitaFiador,
+					"ativo":         preco.Ativo,
+				}); err != nil {
+					return err
+				}
+				updated, err := s.repo.FindPrecoAluguel(txCtx, existing.ID)
+				if err != nil {
+					return fmt.Errorf("failed to find preco aluguel: %w", err)
+				}
+				if err := s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePrecoAluguel, imoveis.AuditAcaoUpdate, existing, updated); err != nil {
+					return err
+				}
+				history, err = s.repo.RecordPriceHistory(txCtx, []uint{imovelID})
+				return err
+			}
+		}
+
+		preco := newPrecoAluguel(req)
+		if err := s.repo.CreatePrecoAluguel(txCtx, preco); err != nil {
+			return fmt.Errorf("failed to create preco aluguel: %w", err)
+		}
+		precoID = preco.ID
+		if err := s.repo.UpdateImovel(txCtx, imovelID, map[string]interface{}{"preco_aluguel_id": preco.ID}); err != nil {
+			return err
+		}
+		if err := s.repo.RecordAudit(txCtx, imovelID, imoveis.AuditEntidadePrecoAluguel, imoveis.AuditAcaoCreate, nil, preco); err != nil {
+			return err
+		}
+		history, err = s.repo.RecordPriceHistory(txCtx, []uint{imovelID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishPriceChanges(history)
+
+	return s.GetPrecoAluguel(ctx, precoID)
+}
+
+// recordPriceHistory records the new value of a price for every property referencing it
+func (s *service) recordPriceHistory(ctx context.Context, column string, precoID uint) error {
+	imovelIDs, err := s.repo.FindImovelIDsByPreco(ctx, column, precoID)
+	if err != nil {
+		return fmt.Errorf("failed to find properties of price: %w", err)
+	}
+	history, err := s.repo.RecordPriceHistory(ctx, imovelIDs)
+	if err != nil {
+		return err
+	}
+	s.publishPriceChanges(history)
+	return nil
+}
+
+// publishPriceChanges publishes the price changes of the recorded history entries
+func (s *service) publishPriceChanges(history []imoveis.HistoricoPreco) {
+	if s.events == nil {
+		return
+	}
+	for _, change := range imoveis.PriceChangedEvents(history) {
+		s.events.Publish(change)
+	}
+}
+
+func (s *service) findPacote(ctx context.Context, id uint) (*imoveis.Pacote, error) {
+	pacote, err := s.repo.FindPacote(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pacote: %w", err)
+	}
+	if pacote == nil {
+		return nil, ErrPacoteNotFound
+	}
+	return pacote, nil
+}
+
+func (s *service) findPrecoVenda(ctx context.Context, id uint) (*imoveis.PrecoVenda, error) {
+	preco, err := s.repo.FindPrecoVenda(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find preco venda: %w", err)
+	}
+	if preco == nil {
+		return nil, ErrPrecoVendaNotFound
+	}
+	return preco, nil
+}
+
+func (s *service) findPrecoAluguel(ctx context.Context, id uint) (*imoveis.PrecoAluguel, error) {
+	preco, err := s.repo.FindPrecoAluguel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find preco aluguel: %w", err)
+	}
+	if preco == nil {
+		return nil, ErrPrecoAluguelNotFound
+	}
+	return preco, nil
+}
+
+// findImovel loads the property whose prices are set, refusing the ones of another corretor
+func (s *service) findImovel(ctx context.Context, id uint) (*imoveis.Imovel, error) {
+	imovel, err := s.repo.FindImovel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find imovel: %w", err)
+	}
+	if imovel == nil {
+		return nil, ErrImovelNotFound
+	}
+	if err := imoveis.CheckCorretorScope(ctx, imovel.CorretorPrincipalID); err != nil {
+		return nil, err
+	}
+	return imovel, nil
+}
+
+// checkOwners refuses changes to a price or package, referenced by column, when one of the
+// properties using it may not be changed by the request: one of another corretor, or outside the
+// organizacao of the request
+func (s *service) checkOwners(ctx context.Context, column string, id uint) error {
+	owners, err := s.repo.FindPrecoOwners(ctx, column, id)
+	if err != nil {
+		return fmt.Errorf("failed to find properties of price: %w", err)
+	}
+	return imoveis.CheckOwnersScope(ctx, owners)
+}
+
+// activeOrDefault treats an omitted ativo flag as an active price
+func activeOrDefault(ativo *bool) bool {
+	return ativo == nil || *ativo
+}
@@ -0,0 +1,219 @@
+package precos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+func setupService(t *testing.T) (Service, *gorm.DB) {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&imoveis.Pacote{}, &imoveis.PrecoVenda{}, &imoveis.PrecoAluguel{}, &imoveis.HistoricoPreco{}, &imoveis.ImovelAudit{}))
+	require.NoError(t, database.Exec(`CREATE TABLE imoveis (
+		id INTEGER PRIMARY KEY,
+		preco_venda_id INTEGER,
+		preco_aluguel_id INTEGER,
+		pacote_id INTEGER,
+		corretor_principal_id INTEGER,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, database.Exec(`CREATE TABLE corretores_principais (id INTEGER PRIMARY KEY, organizacao_id INTEGER)`).Error)
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, corretor_principal_id) VALUES (1, 5)`).Error)
+
+	return NewService(NewRepository(database)), database
+}
+
+func TestCreateAndUpdatePrecoVenda(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreatePrecoVenda(ctx, &PrecoVendaRequest{Preco: 500000, AceitaFGTS: true})
+	require.NoError(t, err)
+	assert.True(t, created.Ativo)
+	assert.True(t, created.AceitaFGTS)
+
+	// Two manual prices must not collide on the id_integracao unique index
+	_, err = svc.CreatePrecoVenda(ctx, &PrecoVendaRequest{Preco: 1})
+	require.NoError(t, err)
+
+	preco := 450000.0
+	inativo := false
+	updated, err := svc.UpdatePrecoVenda(ctx, created.ID, &UpdatePrecoVendaRequest{Preco: &preco, Ativo: &inativo})
+	require.NoError(t, err)
+	assert.Equal(t, 450000.0, updated.Preco)
+	assert.False(t, updated.Ativo)
+	assert.True(t, updated.AceitaFGTS)
+
+	_, err = svc.UpdatePrecoVenda(ctx, 999, &UpdatePrecoVendaRequest{Preco: &preco})
+	assert.ErrorIs(t, err, ErrPrecoVendaNotFound)
+}
+
+func TestSetImovelPrecoVenda_CreatesThenOverwrites(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	first, err := svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 300000, AceitaPermuta: true})
+	require.NoError(t, err)
+
+	var precoVendaID uint
+	require.NoError(t, database.Raw("SELECT preco_venda_id FROM imoveis WHERE id = 1").Scan(&precoVendaID).Error)
+	assert.Equal(t, first.ID, precoVendaID)
+
+	second, err := svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 320000})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 320000.0, second.Preco)
+	assert.False(t, second.AceitaPermuta, "PUT replaces the whole price")
+
+	var count int64
+	require.NoError(t, database.Model(&imoveis.PrecoVenda{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	_, err = svc.SetImovelPrecoVenda(ctx, 999, &PrecoVendaRequest{Preco: 1})
+	assert.ErrorIs(t, err, ErrImovelNotFound)
+	require.NoError(t, database.Model(&imoveis.PrecoVenda{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestSetImovelPrecoVenda_CorretorScope(t *testing.T) {
+	svc, _ := setupService(t)
+
+	_, err := svc.SetImovelPrecoVenda(contextutil.WithCorretorScope(context.Background(), 6), 1, &PrecoVendaRequest{Preco: 300000})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden, "the property belongs to another corretor")
+
+	_, err = svc.SetImovelPrecoVenda(contextutil.WithCorretorScope(context.Background(), 5), 1, &PrecoVendaRequest{Preco: 300000})
+	assert.NoError(t, err)
+}
+
+func TestUpdatePrices_CorretorScope(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	venda, err := svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 300000})
+	require.NoError(t, err)
+	aluguel, err := svc.SetImovelPrecoAluguel(ctx, 1, &PrecoAluguelRequest{Preco: 2500})
+	require.NoError(t, err)
+	pacote, err := svc.SetImovelPacote(ctx, 1, &PacoteRequest{Titulo: "Lançamento"})
+	require.NoError(t, err)
+
+	foreign := contextutil.WithCorretorScope(ctx, 6)
+	preco := 1.0
+	titulo := "Outro"
+	_, err = svc.UpdatePrecoVenda(foreign, venda.ID, &UpdatePrecoVendaRequest{Preco: &preco})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden, "the property belongs to another corretor")
+	_, err = svc.UpdatePrecoAluguel(foreign, aluguel.ID, &UpdatePrecoAluguelRequest{Preco: &preco})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden)
+	_, err = svc.UpdatePacote(foreign, pacote.ID, &UpdatePacoteRequest{Titulo: &titulo})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden)
+
+	current, err := svc.GetPrecoVenda(ctx, venda.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 300000.0, current.Preco)
+
+	updated, err := svc.UpdatePrecoVenda(contextutil.WithCorretorScope(ctx, 5), venda.ID, &UpdatePrecoVendaRequest{Preco: &preco})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, updated.Preco)
+
+	// A price shared with a property of another corretor is refused as a whole
+	require.NoError(t, database.Exec(`INSERT INTO imoveis (id, corretor_principal_id, preco_aluguel_id) VALUES (2, 6, ?)`, aluguel.ID).Error)
+	_, err = svc.UpdatePrecoAluguel(contextutil.WithCorretorScope(ctx, 5), aluguel.ID, &UpdatePrecoAluguelRequest{Preco: &preco})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden)
+}
+
+func TestUpdatePrices_TenantScope(t *testing.T) {
+	svc, database := setupService(t)
+	require.NoError(t, database.Exec(`INSERT INTO corretores_principais (id, organizacao_id) VALUES (5, 1), (6, 2)`).Error)
+	require.NoError(t, database.Use(db.NewTenantScope(db.TenantTables)))
+
+	venda, err := svc.SetImovelPrecoVenda(context.Background(), 1, &PrecoVendaRequest{Preco: 300000})
+	require.NoError(t, err)
+
+	preco := 1.0
+	_, err = svc.UpdatePrecoVenda(db.WithOrganizacao(context.Background(), 2), venda.ID, &UpdatePrecoVendaRequest{Preco: &preco})
+	assert.ErrorIs(t, err, imoveis.ErrForbidden, "the property belongs to another organizacao")
+
+	_, err = svc.UpdatePrecoVenda(db.WithOrganizacao(context.Background(), 1), venda.ID, &UpdatePrecoVendaRequest{Preco: &preco})
+	assert.NoError(t, err)
+}
+
+func TestSetImovelPrecoAluguelAndPacote(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	aluguel, err := svc.SetImovelPrecoAluguel(ctx, 1, &PrecoAluguelRequest{Preco: 2500, AceitaFiador: true})
+	require.NoError(t, err)
+	assert.True(t, aluguel.Ativo)
+
+	pacote, err := svc.SetImovelPacote(ctx, 1, &PacoteRequest{Titulo: " Lançamento ", EmDestaque: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Lançamento", pacote.Titulo)
+
+	var row struct {
+		PrecoAluguelID uint
+		PacoteID       uint
+	}
+	require.NoError(t, database.Raw("SELECT preco_aluguel_id, pacote_id FROM imoveis WHERE id = 1").Scan(&row).Error)
+	assert.Equal(t, aluguel.ID, row.PrecoAluguelID)
+	assert.Equal(t, pacote.ID, row.PacoteID)
+
+	titulo := "Exclusivo"
+	updated, err := svc.UpdatePacote(ctx, pacote.ID, &UpdatePacoteRequest{Titulo: &titulo})
+	require.NoError(t, err)
+	assert.Equal(t, "Exclusivo", updated.Titulo)
+	assert.True(t, updated.EmDestaque)
+}
+
+func TestPriceChangesAreRecorded(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := context.Background()
+
+	first, err := svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 300000})
+	require.NoError(t, err)
+	_, err = svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 300000, AceitaFGTS: true})
+	require.NoError(t, err)
+
+	preco := 280000.0
+	_, err = svc.UpdatePrecoVenda(ctx, first.ID, &UpdatePrecoVendaRequest{Preco: &preco})
+	require.NoError(t, err)
+
+	var historico []imoveis.HistoricoPreco
+	require.NoError(t, database.Order("id").Find(&historico).Error)
+	require.Len(t, historico, 2, "unchanged prices are not recorded again")
+	assert.Equal(t, 300000.0, *historico[0].Preco)
+	assert.Equal(t, 280000.0, *historico[1].Preco)
+	assert.Equal(t, 300000.0, *historico[1].PrecoAnterior)
+	assert.Equal(t, imoveis.HistoricoTipoVenda, historico[1].Tipo)
+}
+
+func TestSetImovelPricesAreAudited(t *testing.T) {
+	svc, database := setupService(t)
+	ctx := contextutil.WithUserID(context.Background(), 9)
+
+	_, err := svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 300000})
+	require.NoError(t, err)
+	_, err = svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 320000})
+	require.NoError(t, err)
+	_, err = svc.SetImovelPrecoVenda(ctx, 1, &PrecoVendaRequest{Preco: 320000})
+	require.NoError(t, err)
+
+	var entries []imoveis.ImovelAudit
+	require.NoError(t, database.Order("id").Find(&entries).Error)
+	require.Len(t, entries, 2, "unchanged prices are not audited")
+	assert.Equal(t, imoveis.AuditAcaoCreate, entries[0].Acao)
+	assert.Equal(t, imoveis.AuditEntidadePrecoVenda, entries[1].Entidade)
+	assert.Equal(t, imoveis.AuditAcaoUpdate, entries[1].Acao)
+	require.NotNil(t, entries[1].UserID)
+	assert.Equal(t, uint(9), *entries[1].UserID)
+	assert.Equal(t, imoveis.AuditChanges{"preco": {Anterior: 300000.0, Novo: 320000.0}}, entries[1].Alteracoes)
+}
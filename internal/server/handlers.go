@@ -1,16 +1,44 @@
 package server
 
 import (
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/analytics"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/authaudit"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/caracteristicas"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/corretores"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/empreendimentos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/enderecos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/favoritos"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/health"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/leads"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/notifications"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/organizacoes"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/precos"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/sliders"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/user"
 )
 
 // Handlers aggregates handler instances and shared services used by route registration.
 type Handlers struct {
-	User    *user.Handler
-	Sliders *sliders.Handler
-	Imoveis *imoveis.Handler
-	Email   *email.Handler
+	User            *user.Handler
+	Sliders         *sliders.Handler
+	Imoveis         *imoveis.Handler
+	Caracteristicas *caracteristicas.Handler
+	Empreendimentos *empreendimentos.Handler
+	Corretores      *corretores.Handler
+	Organizacoes    *organizacoes.Handler
+	Enderecos       *enderecos.Handler
+	Precos          *precos.Handler
+	Email           *email.Handler
+	Maintenance     *maintenance.Handler
+	Analytics       *analytics.Handler
+	AuthAudit       *authaudit.Handler
+	Notifications   *notifications.Handler
+	Leads           *leads.Handler
+	Favoritos       *favoritos.Handler
+
+	// Queues are the background queues whose depth is reported by the verbose health check
+	Queues map[string]health.QueueDepthFunc
 }
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -9,9 +13,13 @@ import (
 
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/favoritos"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/health"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/middleware"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/migrate"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 )
 
 // SetupRouter creates and configures the Gin router
@@ -35,7 +43,7 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization")
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", favoritos.DeviceTokenHeader)
 	router.Use(cors.New(corsConfig))
 
 	var checkers []health.Checker
@@ -43,7 +51,26 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 		dbChecker := health.NewDatabaseChecker(db)
 		checkers = append(checkers, dbChecker)
 	}
-	healthService := health.NewService(checkers, cfg.App.Version, cfg.App.Environment)
+	if cfg.Health.MigrationCheckEnabled && db != nil {
+		checkers = append(checkers, health.NewMigrationChecker(migrationStatus(db, cfg.Migrations.Directory)))
+	}
+	if cfg.Health.SMTPCheckEnabled {
+		if checker := smtpChecker(&cfg.Email, time.Duration(cfg.Health.Timeout)*time.Second); checker != nil {
+			checkers = append(checkers, checker)
+		}
+	}
+	healthOptions := []health.Option{
+		health.WithCheckTimeout(time.Duration(cfg.Health.Timeout) * time.Second),
+	}
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			healthOptions = append(healthOptions, health.WithDBStats(sqlDB.Stats))
+		}
+	}
+	for name, depth := range h.Queues {
+		healthOptions = append(healthOptions, health.WithQueue(name, depth))
+	}
+	healthService := health.NewService(checkers, cfg.App.Version, cfg.App.Environment, healthOptions...)
 	healthHandler := health.NewHandler(healthService)
 
 	router.GET("/health", healthHandler.Health)
@@ -52,6 +79,15 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Uploads stored by the local storage driver; s3 objects are served by the bucket/CDN
+	if cfg.Storage.Driver == "" || cfg.Storage.Driver == "local" {
+		localDir := cfg.Storage.LocalDir
+		if localDir == "" {
+			localDir = "./uploads"
+		}
+		router.Static(storage.DefaultLocalURL, localDir)
+	}
+
 	rlCfg := cfg.Ratelimit
 	if rlCfg.Enabled {
 		router.Use(
@@ -76,20 +112,36 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 		)
 	}
 
+	// Users with these roles reach the protected API only with tokens from a 2FA login; the auth
+	// endpoints below stay open to them so they can enroll
+	twoFactor := middleware.RequireTwoFactor(cfg.TwoFactor.RequiredRoles)
+
 	v1 := router.Group("/api/v1")
 	{
 		authGroup := v1.Group("/auth")
 		{
 			authGroup.POST("/register", h.User.Register)
+			authGroup.POST("/invitations/accept", h.User.AcceptInvitation)
+			authGroup.GET("/google", h.User.GoogleLogin)
+			authGroup.GET("/google/callback", h.User.GoogleCallback)
 			authGroup.POST("/login", h.User.Login)
 			authGroup.POST("/refresh", h.User.RefreshToken)
 			authGroup.POST("/logout", auth.AuthMiddleware(authService), h.User.Logout)
 			authGroup.GET("/me", auth.AuthMiddleware(authService), h.User.GetMe)
+			authGroup.GET("/me/notification-preferences", auth.AuthMiddleware(authService), h.Notifications.GetPreferences)
+			authGroup.PUT("/me/notification-preferences", auth.AuthMiddleware(authService), h.Notifications.UpdatePreferences)
+			authGroup.GET("/sessions", auth.AuthMiddleware(authService), h.User.ListSessions)
+			authGroup.DELETE("/sessions/:id", auth.AuthMiddleware(authService), h.User.RevokeSession)
+			authGroup.POST("/2fa/verify", h.User.VerifyTwoFactor)
+			authGroup.POST("/2fa/enroll", auth.AuthMiddleware(authService), h.User.EnrollTwoFactor)
+			authGroup.POST("/2fa/confirm", auth.AuthMiddleware(authService), h.User.ConfirmTwoFactor)
+			authGroup.POST("/2fa/disable", auth.AuthMiddleware(authService), h.User.DisableTwoFactor)
+			authGroup.PUT("/password", auth.AuthMiddleware(authService), h.User.ChangePassword)
 		}
 
 		// User endpoints - authenticated users can access their own resources
 		usersGroup := v1.Group("/users")
-		usersGroup.Use(auth.AuthMiddleware(authService))
+		usersGroup.Use(auth.AuthMiddleware(authService), twoFactor)
 		{
 			usersGroup.GET("/:id", h.User.GetUser)
 			usersGroup.PUT("/:id", h.User.UpdateUser)
@@ -98,13 +150,73 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 
 		// Admin endpoints - admin role required, following REST best practices
 		adminGroup := v1.Group("/admin")
-		adminGroup.Use(auth.AuthMiddleware(authService), middleware.RequireAdmin())
+		adminGroup.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequireAdmin(), middleware.PropagateUser(), middleware.Tenant())
 		{
 			// User management endpoints
 			adminGroup.GET("/users", h.User.ListUsers)
+			adminGroup.POST("/users/invite", h.User.InviteUser)
 			adminGroup.GET("/users/:id", h.User.GetUser)
 			adminGroup.PUT("/users/:id", h.User.UpdateUser)
 			adminGroup.DELETE("/users/:id", h.User.DeleteUser)
+
+			// Role assignment endpoints
+			adminGroup.GET("/roles", h.User.ListRoles)
+			adminGroup.POST("/users/:id/roles", h.User.AssignRole)
+			adminGroup.DELETE("/users/:id/roles/:role", h.User.RemoveRole)
+			adminGroup.PUT("/users/:id/corretor", h.User.LinkCorretor)
+			adminGroup.PUT("/users/:id/organizacao", h.User.AssignOrganizacao)
+
+			// Authentication audit log
+			adminGroup.GET("/auth-events", h.AuthAudit.ListEvents)
+
+			// Imovel trash, stale listings archive, permanent deletion and audit log
+			adminGroup.GET("/imoveis/trash", h.Imoveis.ListTrash)
+			adminGroup.POST("/imoveis/archive-stale", h.Imoveis.ArchiveStale)
+			adminGroup.DELETE("/imoveis/:id", h.Imoveis.HardDeleteImovel)
+			adminGroup.GET("/imoveis/:id/audit", h.Imoveis.GetAuditLog)
+			adminGroup.GET("/imoveis/favoritos", h.Favoritos.ListStats)
+
+			// Every slider, including disabled and unscheduled ones, and the slider trash
+			adminGroup.GET("/sliders", h.Sliders.ListAllSliders)
+			adminGroup.GET("/sliders/trash", h.Sliders.ListDeletedSliders)
+			adminGroup.POST("/sliders/:id/restore", h.Sliders.RestoreSlider)
+
+			// Import run history, conflicts with fields edited by hand and quarantined listings
+			adminGroup.GET("/imports", h.Imoveis.ListImportRuns)
+			adminGroup.GET("/imports/:id/errors", h.Imoveis.ListImportRunErrors)
+			adminGroup.GET("/imports/conflicts", h.Imoveis.ListImportConflicts)
+			adminGroup.POST("/imports/conflicts/:id/resolve", h.Imoveis.ResolveImportConflict)
+			adminGroup.GET("/imports/quarantine", h.Imoveis.ListImportQuarantine)
+			adminGroup.POST("/imports/quarantine/:id/retry", h.Imoveis.RetryImportQuarantine)
+			adminGroup.POST("/imports/quarantine/:id/discard", h.Imoveis.DiscardImportQuarantine)
+
+			// Verbose health report with internal metrics
+			adminGroup.GET("/health", healthHandler.Verbose)
+
+			// Maintenance jobs
+			adminGroup.GET("/maintenance/tasks", h.Maintenance.ListTasks)
+			adminGroup.POST("/maintenance/reindex", h.Maintenance.Reindex)
+			adminGroup.GET("/maintenance/jobs/:id", h.Maintenance.GetJob)
+
+			// Email diagnostics, send log and emails that could not be sent
+			adminGroup.POST("/emails/test", h.Email.TestConfiguration)
+			adminGroup.GET("/emails", h.Email.ListEmailLogs)
+			adminGroup.GET("/emails/dead-letters", h.Email.ListDeadLetters)
+			adminGroup.GET("/emails/:id", h.Email.GetEmail)
+
+			// Addresses that do not receive marketing emails
+			adminGroup.GET("/emails/suppressions", h.Email.ListSuppressions)
+			adminGroup.POST("/emails/suppressions", h.Email.CreateSuppression)
+			adminGroup.DELETE("/emails/suppressions/:id", h.Email.DeleteSuppression)
+
+			// Email templates, their versions and preview
+			adminGroup.GET("/emails/templates", h.Email.ListEmailTemplates)
+			adminGroup.POST("/emails/templates", h.Email.CreateEmailTemplate)
+			adminGroup.GET("/emails/templates/:id", h.Email.GetEmailTemplate)
+			adminGroup.PUT("/emails/templates/:id", h.Email.UpdateEmailTemplate)
+			adminGroup.DELETE("/emails/templates/:id", h.Email.DeleteEmailTemplate)
+			adminGroup.GET("/emails/templates/:id/versions", h.Email.ListEmailTemplateVersions)
+			adminGroup.POST("/emails/templates/:id/preview", h.Email.PreviewEmailTemplate)
 		}
 
 		public := v1.Group("/sliders")
@@ -114,14 +226,21 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 			public.GET("/items/:item_id", h.Sliders.GetSliderItem)
 			public.GET(":id", h.Sliders.GetSlider)
 			public.GET("/:id/items", h.Sliders.GetSliderItems)
+			public.POST("/items/:item_id/click", h.Sliders.RecordClick)
+			public.POST("/impressions", h.Sliders.RecordImpressions)
 		}
 
-		// Protected routes
+		// Protected routes - sliders:write permission required
 		protected := v1.Group("/sliders")
-		protected.Use(auth.AuthMiddleware(authService))
+		protected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionSlidersWrite), middleware.Tenant())
 		{
 			protected.POST("", h.Sliders.CreateSlider)
 			protected.POST("/:id/items", h.Sliders.AddSliderItem)
+			protected.PUT("/:id/items", h.Sliders.ReplaceSliderItems)
+			protected.POST("/:id/items/upload", h.Sliders.UploadSliderItem)
+			protected.POST("/:id/preview-token", h.Sliders.GeneratePreviewToken)
+			protected.GET("/:id/stats", h.Sliders.GetSliderStats)
+			protected.POST("/:id/duplicate", h.Sliders.DuplicateSlider)
 			protected.PUT("/items/:item_id", h.Sliders.UpdateSliderItem)
 			protected.DELETE("/items/:item_id", h.Sliders.DeleteSliderItem)
 
@@ -133,30 +252,269 @@ func SetupRouter(h *Handlers, authService auth.Service, cfg *config.Config, db *
 		imoveisPublic := v1.Group("/imoveis")
 		{
 			imoveisPublic.GET("", h.Imoveis.ListImoveis)
+			imoveisPublic.GET("/stats", h.Imoveis.GetStats)
+			imoveisPublic.GET("/facets", h.Imoveis.GetFacets)
+			imoveisPublic.GET("/compare", h.Imoveis.CompareImoveis)
+			imoveisPublic.GET("/destaques", h.Imoveis.ListDestaques)
+			imoveisPublic.GET("/slug/:slug", h.Imoveis.GetImovelBySlug)
 			imoveisPublic.GET("/:id", h.Imoveis.GetImovel)
 			imoveisPublic.GET("/:id/anexos", h.Imoveis.GetAnexos)
 			imoveisPublic.GET("/:id/caracteristicas", h.Imoveis.GetCaracteristicas)
+			imoveisPublic.GET("/:id/historico-precos", h.Imoveis.GetPriceHistory)
+			imoveisPublic.GET("/:id/simulacao", h.Imoveis.SimulateFinancing)
+			imoveisPublic.POST("/:id/view", h.Imoveis.RegisterView)
+			imoveisPublic.POST("/:id/leads", leadsRateLimit(cfg.Leads), h.Leads.CreateLead)
+			imoveisPublic.POST("/:id/favorite", auth.OptionalAuthMiddleware(authService), h.Favoritos.AddFavorito)
+			imoveisPublic.DELETE("/:id/favorite", auth.OptionalAuthMiddleware(authService), h.Favoritos.RemoveFavorito)
 		}
 
+		// Writes need imoveis:write and imports import:run; view stats stay open to any signed-in user
+		imoveisWrite := middleware.RequirePermission(auth.PermissionImoveisWrite)
+		importRun := middleware.RequirePermission(auth.PermissionImportRun)
 		imoveisProtected := v1.Group("/imoveis")
-		imoveisProtected.Use(auth.AuthMiddleware(authService))
+		imoveisProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.PropagateUser(), middleware.Tenant())
 		{
-			imoveisProtected.POST("", h.Imoveis.CreateImovel)
-			imoveisProtected.POST("/import", h.Imoveis.ImportProperties)
-			imoveisProtected.PUT("/:id", h.Imoveis.UpdateImovel)
-			imoveisProtected.DELETE("/:id", h.Imoveis.DeleteImovel)
-			imoveisProtected.POST("/:id/anexos", h.Imoveis.AddAnexo)
-			imoveisProtected.POST("/:id/caracteristicas", h.Imoveis.AddCaracteristicas)
+			imoveisProtected.POST("", imoveisWrite, h.Imoveis.CreateImovel)
+			imoveisProtected.POST("/import", importRun, h.Imoveis.ImportProperties)
+			imoveisProtected.GET("/import/jobs/:id", importRun, h.Imoveis.GetImportJob)
+			imoveisProtected.POST("/import/:externalId", importRun, h.Imoveis.ImportProperty)
+			imoveisProtected.POST("/bulk/status", imoveisWrite, h.Imoveis.BulkUpdateStatus)
+			imoveisProtected.POST("/bulk/delete", imoveisWrite, h.Imoveis.BulkDelete)
+			imoveisProtected.GET("/stats/views", h.Imoveis.GetViewStats)
+			imoveisProtected.GET("/stats/views/corretores", h.Imoveis.GetCorretorViewStats)
+			imoveisProtected.PUT("/:id", imoveisWrite, h.Imoveis.UpdateImovel)
+			imoveisProtected.PATCH("/:id", imoveisWrite, h.Imoveis.PatchImovel)
+			imoveisProtected.POST("/:id/publish", imoveisWrite, h.Imoveis.PublishImovel)
+			imoveisProtected.POST("/:id/unpublish", imoveisWrite, h.Imoveis.UnpublishImovel)
+			imoveisProtected.DELETE("/:id", imoveisWrite, h.Imoveis.DeleteImovel)
+			imoveisProtected.POST("/:id/restore", imoveisWrite, h.Imoveis.RestoreImovel)
+			imoveisProtected.POST("/:id/anexos", imoveisWrite, h.Imoveis.AddAnexo)
+			imoveisProtected.POST("/:id/caracteristicas", imoveisWrite, h.Imoveis.AddCaracteristicas)
+			imoveisProtected.PUT("/:id/caracteristicas", imoveisWrite, h.Imoveis.ReplaceCaracteristicas)
+			imoveisProtected.DELETE("/:id/caracteristicas", imoveisWrite, h.Imoveis.RemoveCaracteristicas)
+			imoveisProtected.PUT("/:id/pacote", imoveisWrite, h.Precos.SetImovelPacote)
+			imoveisProtected.PUT("/:id/preco-venda", imoveisWrite, h.Precos.SetImovelPrecoVenda)
+			imoveisProtected.PUT("/:id/preco-aluguel", imoveisWrite, h.Precos.SetImovelPrecoAluguel)
 		}
 
-		// Email endpoints - protected
+		// Events pushed by the external API, authenticated by their signature
+		integrations := v1.Group("/integrations")
+		{
+			integrations.POST("/pi8/webhook", h.Imoveis.ReceiveWebhook)
+		}
+
+		// Market analytics
+		analyticsPublic := v1.Group("/analytics")
+		{
+			analyticsPublic.GET("/preco-m2", h.Analytics.GetPrecoM2)
+		}
+
+		// Caracteristicas catalog
+		caracteristicasPublic := v1.Group("/caracteristicas")
+		{
+			caracteristicasPublic.GET("", h.Caracteristicas.ListCaracteristicas)
+			caracteristicasPublic.GET("/:id", h.Caracteristicas.GetCaracteristica)
+		}
+
+		// Catalog writes - admin role required
+		caracteristicasProtected := v1.Group("/caracteristicas")
+		caracteristicasProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequireAdmin())
+		{
+			caracteristicasProtected.POST("", h.Caracteristicas.CreateCaracteristica)
+			caracteristicasProtected.PUT("/:id", h.Caracteristicas.UpdateCaracteristica)
+			caracteristicasProtected.DELETE("/:id", h.Caracteristicas.DeleteCaracteristica)
+		}
+
+		// Empreendimentos endpoints
+		empreendimentosPublic := v1.Group("/empreendimentos")
+		{
+			empreendimentosPublic.GET("", h.Empreendimentos.ListEmpreendimentos)
+			empreendimentosPublic.GET("/:id", h.Empreendimentos.GetEmpreendimento)
+			empreendimentosPublic.GET("/:id/torres", h.Empreendimentos.ListTorres)
+			empreendimentosPublic.GET("/:id/plantas", h.Empreendimentos.ListPlantas)
+			empreendimentosPublic.GET("/:id/anexos", h.Empreendimentos.ListAnexos)
+			empreendimentosPublic.GET("/:id/imoveis", h.Imoveis.ListByEmpreendimento)
+			empreendimentosPublic.GET("/:id/imoveis/count", h.Imoveis.CountByEmpreendimento)
+		}
+
+		empreendimentosProtected := v1.Group("/empreendimentos")
+		empreendimentosProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionImoveisWrite), middleware.PropagateUser(), middleware.Tenant())
+		{
+			empreendimentosProtected.POST("", h.Empreendimentos.CreateEmpreendimento)
+			empreendimentosProtected.PUT("/:id", h.Empreendimentos.UpdateEmpreendimento)
+			empreendimentosProtected.DELETE("/:id", h.Empreendimentos.DeleteEmpreendimento)
+			empreendimentosProtected.POST("/:id/torres", h.Empreendimentos.AddTorre)
+			empreendimentosProtected.PUT("/:id/torres/:torre_id", h.Empreendimentos.UpdateTorre)
+			empreendimentosProtected.DELETE("/:id/torres/:torre_id", h.Empreendimentos.DeleteTorre)
+			empreendimentosProtected.POST("/:id/plantas", h.Empreendimentos.AddPlanta)
+			empreendimentosProtected.PUT("/:id/plantas/:planta_id", h.Empreendimentos.UpdatePlanta)
+			empreendimentosProtected.DELETE("/:id/plantas/:planta_id", h.Empreendimentos.DeletePlanta)
+			empreendimentosProtected.POST("/:id/anexos", h.Empreendimentos.AddAnexo)
+			empreendimentosProtected.DELETE("/:id/anexos/:anexo_id", h.Empreendimentos.DeleteAnexo)
+		}
+
+		// Corretores endpoints
+		corretoresPublic := v1.Group("/corretores")
+		{
+			corretoresPublic.GET("", h.Corretores.ListCorretores)
+			corretoresPublic.GET("/:id", h.Corretores.GetCorretor)
+			corretoresPublic.GET("/:id/imoveis", h.Imoveis.ListByCorretor)
+		}
+
+		// Corretores writes - corretores:write permission required
+		corretoresProtected := v1.Group("/corretores")
+		corretoresProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionCorretoresWrite), middleware.Tenant())
+		{
+			corretoresProtected.POST("", h.Corretores.CreateCorretor)
+			corretoresProtected.PUT("/:id", h.Corretores.UpdateCorretor)
+			corretoresProtected.POST("/:id/deactivate", h.Corretores.DeactivateCorretor)
+			corretoresProtected.POST("/:id/activate", h.Corretores.ActivateCorretor)
+			corretoresProtected.PUT("/:id/foto", h.Corretores.SetFoto)
+			corretoresProtected.DELETE("/:id/foto", h.Corretores.RemoveFoto)
+		}
+
+		// Leads: corretor users see those of their own properties
+		leadsProtected := v1.Group("/leads")
+		leadsProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionImoveisWrite), middleware.PropagateUser(), middleware.Tenant())
+		{
+			leadsProtected.GET("", h.Leads.ListLeads)
+			leadsProtected.GET("/:id", h.Leads.GetLead)
+			leadsProtected.PATCH("/:id", h.Leads.UpdateLead)
+		}
+
+		// Favoritos of the authenticated user or, anonymously, of the device (X-Device-Token)
+		meGroup := v1.Group("/me")
+		meGroup.Use(auth.OptionalAuthMiddleware(authService))
+		{
+			meGroup.GET("/favoritos", h.Favoritos.ListFavoritos)
+		}
+
+		// Organizacoes endpoints
+		organizacoesPublic := v1.Group("/organizacoes")
+		{
+			organizacoesPublic.GET("", h.Organizacoes.ListOrganizacoes)
+			organizacoesPublic.GET("/:id", h.Organizacoes.GetOrganizacao)
+			organizacoesPublic.GET("/:id/corretores", h.Corretores.ListByOrganizacao)
+			organizacoesPublic.GET("/:id/imoveis", h.Imoveis.ListByOrganizacao)
+		}
+
+		// Organizacoes writes - admin role required
+		organizacoesProtected := v1.Group("/organizacoes")
+		organizacoesProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequireAdmin())
+		{
+			organizacoesProtected.POST("", h.Organizacoes.CreateOrganizacao)
+			organizacoesProtected.PUT("/:id", h.Organizacoes.UpdateOrganizacao)
+			organizacoesProtected.DELETE("/:id", h.Organizacoes.DeleteOrganizacao)
+		}
+
+		// Enderecos endpoints
+		enderecosPublic := v1.Group("/enderecos")
+		{
+			enderecosPublic.GET("/cep/:cep", h.Enderecos.LookupCEP)
+			enderecosPublic.GET("/:id", h.Enderecos.GetEndereco)
+		}
+
+		enderecosProtected := v1.Group("/enderecos")
+		enderecosProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionImoveisWrite), middleware.PropagateUser(), middleware.Tenant())
+		{
+			enderecosProtected.POST("", h.Enderecos.CreateEndereco)
+			enderecosProtected.PUT("/:id", h.Enderecos.UpdateEndereco)
+		}
+
+		// Pacotes and precos endpoints
+		precosPublic := v1.Group("")
+		{
+			precosPublic.GET("/pacotes/:id", h.Precos.GetPacote)
+			precosPublic.GET("/precos-venda/:id", h.Precos.GetPrecoVenda)
+			precosPublic.GET("/precos-aluguel/:id", h.Precos.GetPrecoAluguel)
+		}
+
+		precosProtected := v1.Group("")
+		precosProtected.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionImoveisWrite), middleware.PropagateUser(), middleware.Tenant())
+		{
+			precosProtected.POST("/pacotes", h.Precos.CreatePacote)
+			precosProtected.PUT("/pacotes/:id", h.Precos.UpdatePacote)
+			precosProtected.POST("/precos-venda", h.Precos.CreatePrecoVenda)
+			precosProtected.PUT("/precos-venda/:id", h.Precos.UpdatePrecoVenda)
+			precosProtected.POST("/precos-aluguel", h.Precos.CreatePrecoAluguel)
+			precosProtected.PUT("/precos-aluguel/:id", h.Precos.UpdatePrecoAluguel)
+		}
+
+		// Email delivery webhook - public, authenticated by the token of email.webhook_secret
+		v1.POST("/emails/webhooks/:provider", h.Email.DeliveryWebhook)
+
+		// Unsubscribe link of marketing emails - public, authenticated by the signed token
+		v1.POST("/emails/unsubscribe", h.Email.Unsubscribe)
+
+		// Email endpoints - emails:send permission required
 		emailGroup := v1.Group("/emails")
-		emailGroup.Use(auth.AuthMiddleware(authService))
+		emailGroup.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequirePermission(auth.PermissionEmailsSend), middleware.Tenant())
 		{
 			emailGroup.POST("/send", h.Email.SendEmail)
 			emailGroup.POST("/send-template", h.Email.SendTemplateEmail)
 		}
+
+		// Email campaigns - admin role required
+		campaignGroup := v1.Group("/emails/campaigns")
+		campaignGroup.Use(auth.AuthMiddleware(authService), twoFactor, middleware.RequireAdmin(), middleware.PropagateUser(), middleware.Tenant())
+		{
+			campaignGroup.POST("", h.Email.CreateCampaign)
+			campaignGroup.GET("", h.Email.ListCampaigns)
+			campaignGroup.GET("/:id", h.Email.GetCampaign)
+			campaignGroup.POST("/:id/cancel", h.Email.CancelCampaign)
+		}
 	}
 
 	return router
 }
+
+// migrationStatus compares the applied schema version against the migrations directory
+func migrationStatus(db *gorm.DB, dir string) health.MigrationStatusFunc {
+	return func(ctx context.Context) (uint, bool, []uint, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return 0, false, nil, err
+		}
+
+		current, dirty, err := migrate.CurrentVersion(ctx, sqlDB)
+		if err != nil {
+			return 0, false, nil, err
+		}
+
+		pending, err := migrate.PendingVersions(dir, current)
+		if err != nil {
+			return current, dirty, nil, err
+		}
+
+		return current, dirty, pending, nil
+	}
+}
+
+// smtpChecker returns the SMTP health checker when email is sent through an SMTP server with
+// credentials configured, nil otherwise
+func smtpChecker(cfg *config.EmailConfig, timeout time.Duration) health.Checker {
+	provider, err := email.NewProvider(cfg)
+	if err != nil || provider.Name() != email.ProviderSMTP || provider.Validate() != nil {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	client, err := email.NewSMTPClient(cfg, timeout)
+	if err != nil {
+		slog.Warn("SMTP health check disabled", "error", err)
+		return nil
+	}
+	return health.NewSMTPChecker(client)
+}
+
+// leadsRateLimit limits the public contact requests per IP, on top of the global rate limit
+func leadsRateLimit(cfg config.LeadsConfig) gin.HandlerFunc {
+	if cfg.RateLimitRequests <= 0 || cfg.RateLimitWindow <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middleware.NewRateLimitMiddleware(cfg.RateLimitWindow, cfg.RateLimitRequests, func(c *gin.Context) string {
+		return "leads:" + c.ClientIP()
+	}, nil)
+}
@@ -63,3 +63,67 @@ func TestSetupRouter_HealthEndpoint(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "status")
 	assert.Contains(t, w.Body.String(), "healthy")
 }
+
+func TestSMTPChecker(t *testing.T) {
+	smtpConfig := config.EmailConfig{Provider: "smtp", Host: "smtp.example.com", Port: 587, Username: "user", Password: "secret"}
+	checker := smtpChecker(&smtpConfig, 5*time.Second)
+	if assert.NotNil(t, checker) {
+		assert.Equal(t, "smtp", checker.Name())
+	}
+
+	withoutCredentials := smtpConfig
+	withoutCredentials.Username = ""
+	assert.Nil(t, smtpChecker(&withoutCredentials, 5*time.Second), "email is not configured")
+
+	ses := smtpConfig
+	ses.Provider = "ses"
+	assert.Nil(t, smtpChecker(&ses, 5*time.Second), "only SMTP is checked")
+}
+
+// claimsAuthService accepts any bearer token as the user of claims
+type claimsAuthService struct {
+	auth.Service
+	claims *auth.Claims
+}
+
+func (s claimsAuthService) ValidateToken(string) (*auth.Claims, error) {
+	return s.claims, nil
+}
+
+func TestSetupRouter_WriteRoutesRequirePermission(t *testing.T) {
+	testConfig := &config.Config{App: config.AppConfig{Environment: "test"}}
+
+	tests := []struct {
+		method string
+		path   string
+		roles  []string
+	}{
+		{http.MethodPost, "/api/v1/pacotes", []string{"user", "marketing"}},
+		{http.MethodPut, "/api/v1/precos-venda/1", []string{"user", "marketing"}},
+		{http.MethodPut, "/api/v1/precos-aluguel/1", []string{"user"}},
+		{http.MethodPost, "/api/v1/enderecos", []string{"user", "marketing"}},
+		{http.MethodPut, "/api/v1/enderecos/1", []string{"user"}},
+		{http.MethodPost, "/api/v1/empreendimentos", []string{"user", "marketing"}},
+		{http.MethodDelete, "/api/v1/empreendimentos/1/torres/2", []string{"user"}},
+		{http.MethodPost, "/api/v1/corretores", []string{"user", "corretor"}},
+		{http.MethodPost, "/api/v1/corretores/1/deactivate", []string{"user", "marketing"}},
+		{http.MethodPost, "/api/v1/organizacoes", []string{"user", "gestor"}},
+		{http.MethodDelete, "/api/v1/organizacoes/1", []string{"user", "corretor"}},
+		{http.MethodPost, "/api/v1/caracteristicas", []string{"user", "gestor"}},
+		{http.MethodPut, "/api/v1/caracteristicas/1", []string{"user", "corretor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			authService := claimsAuthService{claims: &auth.Claims{UserID: 7, Roles: tt.roles}}
+			router := SetupRouter(&Handlers{}, authService, testConfig, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		})
+	}
+}
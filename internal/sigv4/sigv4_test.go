@@ -0,0 +1,36 @@
+package sigv4
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	creds := Credentials{AccessKey: "AKID", SecretKey: "secret"}
+	sign := func(body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://email.sa-east-1.amazonaws.com/v2/email/outbound-emails", strings.NewReader(body))
+		require.NoError(t, err)
+		Sign(req, PayloadHash([]byte(body)), "ses", "sa-east-1", creds, now)
+		return req
+	}
+
+	req := sign(`{"a":1}`)
+	assert.Equal(t, "20261017T120000Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, PayloadHash([]byte(`{"a":1}`)), req.Header.Get("X-Amz-Content-Sha256"))
+	assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKID/20261017/sa-east-1/ses/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+
+	assert.Equal(t, req.Header.Get("Authorization"), sign(`{"a":1}`).Header.Get("Authorization"), "signing is deterministic")
+	assert.NotEqual(t, req.Header.Get("Authorization"), sign(`{"a":2}`).Header.Get("Authorization"), "the body is signed")
+}
+
+func TestEncodePath(t *testing.T) {
+	assert.Equal(t, "/anexos/planta%20baixa%281%29.pdf", EncodePath("/anexos/planta baixa(1).pdf"))
+	assert.Equal(t, "/a-b_c.d~e/f", EncodePath("/a-b_c.d~e/f"))
+}
@@ -0,0 +1,219 @@
+package sliders
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/cache"
+)
+
+const (
+	// cacheKeyPrefix namespaces the sliders entries; bump it when the cached responses change shape
+	cacheKeyPrefix = "sliders:v2:"
+	// locationGenerationKey holds the generation embedded in location keys. Writes replace it,
+	// which orphans every cached location at once; the orphans expire with their TTL.
+	locationGenerationKey = cacheKeyPrefix + "location:generation"
+	// DefaultCacheTTL is used when no positive ttl is configured. It is short because active
+	// windows open and close and linked properties change without going through the service.
+	DefaultCacheTTL = 30 * time.Second
+	// warmPageSize is the page of sliders read at a time when warming the cache
+	warmPageSize = 50
+)
+
+// cachedService serves GetSliderByLocation from a cache and invalidates every location on any
+// slider or item write. Preview requests bypass the cache. Cache failures are logged and fall back
+// to the wrapped service.
+type cachedService struct {
+	Service
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedService wraps service with a cache of the public location lookups that lives for ttl
+// at most
+func NewCachedService(service Service, c cache.Cache, ttl time.Duration) Service {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &cachedService{Service: service, cache: c, ttl: ttl}
+}
+
+// GetSliderByLocation returns the cached variant when available
+func (s *cachedService) GetSliderByLocation(ctx context.Context, query *SliderLocationQuery) (*SliderResponse, error) {
+	if query.PreviewToken != "" {
+		return s.Service.GetSliderByLocation(ctx, query)
+	}
+
+	key, ok := s.locationKey(ctx, query)
+	if !ok {
+		return s.Service.GetSliderByLocation(ctx, query)
+	}
+	var cached SliderResponse
+	if s.load(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	slider, err := s.Service.GetSliderByLocation(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.store(ctx, key, slider)
+	return slider, nil
+}
+
+// locationKey identifies the query within the current generation. Requests are keyed by the
+// locales they resolve to rather than the raw Accept-Language header, which varies a lot more.
+func (s *cachedService) locationKey(ctx context.Context, query *SliderLocationQuery) (string, bool) {
+	generation, ok, err := s.cache.Get(ctx, locationGenerationKey)
+	if err != nil {
+		slog.Warn("Failed to read sliders cache", "key", locationGenerationKey, "error", err)
+		return "", false
+	}
+	if !ok {
+		// Start a new generation rather than reading an evicted one as empty, which would bring
+		// back locations cached before it was replaced
+		generation = []byte(newLocationGeneration())
+		if err := s.cache.Set(ctx, locationGenerationKey, generation, 0); err != nil {
+			slog.Warn("Failed to write sliders cache", "key", locationGenerationKey, "error", err)
+			return "", false
+		}
+	}
+
+	prefs := localePreferences(query.Locale, query.AcceptLanguage)
+	locales := make([]string, len(prefs))
+	for i, pref := range prefs {
+		locales[i] = pref.String()
+	}
+	data, err := json.Marshal([]string{query.Location, strings.Join(locales, ","), normalizeAudience(query.Audience)})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return cacheKeyPrefix + "location:" + string(generation) + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// CacheWarmer loads the default variant of every location with an active slider through service
+// so it is cached again after a flush
+func CacheWarmer(service Service) cache.Warmer {
+	return func(ctx context.Context) error {
+		seen := make(map[string]bool)
+		for page := 1; ; page++ {
+			sliders, total, err := service.ListSliders(ctx, page, warmPageSize)
+			if err != nil {
+				return err
+			}
+			for _, slider := range sliders {
+				if seen[slider.Location] {
+					continue
+				}
+				seen[slider.Location] = true
+				_, err := service.GetSliderByLocation(ctx, &SliderLocationQuery{Location: slider.Location})
+				if err != nil && !errors.Is(err, ErrSliderNotFound) {
+					return err
+				}
+			}
+			if len(sliders) == 0 || int64(page*warmPageSize) >= total {
+				return nil
+			}
+		}
+	}
+}
+
+func newLocationGeneration() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// load decodes the entry under key into dest and reports whether it was found
+func (s *cachedService) load(ctx context.Context, key string, dest interface{}) bool {
+	data, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("Failed to read sliders cache", "key", key, "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		slog.Warn("Discarding undecodable sliders cache entry", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+func (s *cachedService) store(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Warn("Failed to encode sliders cache entry", "key", key, "error", err)
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.ttl); err != nil {
+		slog.Warn("Failed to write sliders cache", "key", key, "error", err)
+	}
+}
+
+// invalidate drops every cached location. It runs after failed writes too, since replacing or
+// duplicating items can fail after part of the work was done.
+func (s *cachedService) invalidate(ctx context.Context) {
+	if err := s.cache.Delete(ctx, locationGenerationKey); err != nil {
+		slog.Warn("Failed to invalidate sliders cache", "key", locationGenerationKey, "error", err)
+	}
+}
+
+func (s *cachedService) CreateSlider(ctx context.Context, req *CreateSliderRequest) (*SliderResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.CreateSlider(ctx, req)
+}
+
+func (s *cachedService) UpdateSlider(ctx context.Context, id uint, req *UpdateSliderRequest) (*SliderResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.UpdateSlider(ctx, id, req)
+}
+
+func (s *cachedService) DuplicateSlider(ctx context.Context, id uint, req *DuplicateSliderRequest) (*SliderResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.DuplicateSlider(ctx, id, req)
+}
+
+func (s *cachedService) DeleteSlider(ctx context.Context, id uint) error {
+	defer s.invalidate(ctx)
+	return s.Service.DeleteSlider(ctx, id)
+}
+
+func (s *cachedService) RestoreSlider(ctx context.Context, id uint) (*SliderResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.RestoreSlider(ctx, id)
+}
+
+func (s *cachedService) AddSliderItem(ctx context.Context, sliderID uint, req *CreateSliderItemRequest) (*SliderItemResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.AddSliderItem(ctx, sliderID, req)
+}
+
+func (s *cachedService) UploadSliderItem(ctx context.Context, sliderID uint, file *UploadedFile, req *UploadSliderItemRequest) (*SliderItemResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.UploadSliderItem(ctx, sliderID, file, req)
+}
+
+func (s *cachedService) UpdateSliderItem(ctx context.Context, itemID uint, req *UpdateSliderItemRequest) (*SliderItemResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.UpdateSliderItem(ctx, itemID, req)
+}
+
+func (s *cachedService) DeleteSliderItem(ctx context.Context, itemID uint) error {
+	defer s.invalidate(ctx)
+	return s.Service.DeleteSliderItem(ctx, itemID)
+}
+
+func (s *cachedService) ReplaceSliderItems(ctx context.Context, sliderID uint, req *ReplaceSliderItemsRequest) ([]SliderItemResponse, error) {
+	defer s.invalidate(ctx)
+	return s.Service.ReplaceSliderItems(ctx, sliderID, req)
+}
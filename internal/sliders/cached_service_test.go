@@ -0,0 +1,104 @@
+package sliders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/cache"
+)
+
+func TestCachedService_GetSliderByLocation(t *testing.T) {
+	ctx := context.Background()
+	home := newCarousel()
+	english := &Slider{ID: 2, Location: "home", Locale: "en", Enabled: true}
+	svc := newPreviewService(time.Hour, home, english)
+	cached := NewCachedService(svc, cache.NewMemoryCache(100), time.Minute)
+	query := &SliderLocationQuery{Location: "home"}
+
+	first, err := cached.GetSliderByLocation(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, first.Items, 3)
+
+	// A change made behind the service, like one from another instance, is served stale
+	home.Items[0].ImageURL = "https://cdn/changed.jpg"
+	again, err := cached.GetSliderByLocation(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn/a.jpg", again.Items[0].ImageURL)
+
+	t.Run("locales are cached apart", func(t *testing.T) {
+		resp, err := cached.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home", AcceptLanguage: "en-US,en;q=0.8"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), resp.ID)
+	})
+
+	t.Run("preview requests bypass the cache", func(t *testing.T) {
+		token, err := svc.GeneratePreviewToken(ctx, home.ID)
+		require.NoError(t, err)
+
+		resp, err := cached.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home", PreviewToken: token.Token})
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn/changed.jpg", resp.Items[0].ImageURL)
+	})
+
+	t.Run("item writes invalidate every location", func(t *testing.T) {
+		_, err := cached.UpdateSliderItem(ctx, 2, &UpdateSliderItemRequest{Titulo: "Novo"})
+		require.NoError(t, err)
+
+		resp, err := cached.GetSliderByLocation(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn/changed.jpg", resp.Items[0].ImageURL)
+		assert.Equal(t, "Novo", resp.Items[1].Titulo)
+	})
+}
+
+func TestHandler_GetSliderByLocation_CacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := newPreviewService(time.Hour, newCarousel())
+	router := gin.New()
+	router.GET("/sliders/location", NewHandler(svc, 45*time.Second).GetSliderByLocation)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sliders/location?location=home", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=45", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+
+	token, err := svc.GeneratePreviewToken(context.Background(), 1)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sliders/location?location=home&preview_token="+token.Token, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+}
+
+func (r *stubRepository) List(ctx context.Context, page, perPage int, activeAt *time.Time) ([]Slider, int64, error) {
+	var sliders []Slider
+	for _, slider := range r.sliders {
+		if activeAt == nil || slider.activeAt(*activeAt) {
+			sliders = append(sliders, *slider)
+		}
+	}
+	return sliders, int64(len(sliders)), nil
+}
+
+func TestCacheWarmer(t *testing.T) {
+	ctx := context.Background()
+	home := newCarousel()
+	svc := newPreviewService(time.Hour, home)
+	c := cache.NewMemoryCache(100)
+	cached := NewCachedService(svc, c, time.Minute)
+
+	require.NoError(t, CacheWarmer(cached)(ctx))
+	home.Items[0].ImageURL = "https://cdn/changed.jpg"
+
+	resp, err := cached.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn/a.jpg", resp.Items[0].ImageURL, "the location was cached by the warmer")
+}
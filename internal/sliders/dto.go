@@ -1,63 +1,223 @@
 package sliders
 
-import "time"
+import (
+	"time"
 
-// CreateSliderRequest represents slider creation request
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// CreateSliderRequest represents slider creation request. Locale (a BCP 47 tag such as pt-BR) and
+// audience make the slider a variant of its location; location and locale are unique together and
+// the empty locale is the default variant.
 type CreateSliderRequest struct {
-	Name     string                    `json:"name" binding:"required,min=1,max=200"`
-	Type     int                       `json:"type" binding:"required,min=0,max=2"`
-	Location string                    `json:"location" binding:"required,min=1,max=255"`
-	Items    []CreateSliderItemRequest `json:"items" binding:"dive"`
+	Name        string                    `json:"name" binding:"required,min=1,max=200"`
+	Type        *SliderType               `json:"type" binding:"required" swaggertype:"string" enums:"slideshow,carousel,static"`
+	Location    string                    `json:"location" binding:"required,min=1,max=255"`
+	Locale      string                    `json:"locale" binding:"omitempty,max=35"`
+	Audience    string                    `json:"audience" binding:"omitempty,max=100"`
+	Enabled     *bool                     `json:"enabled"`
+	ActiveFrom  *time.Time                `json:"active_from" binding:"omitempty"`
+	ActiveUntil *time.Time                `json:"active_until" binding:"omitempty"`
+	Items       []CreateSliderItemRequest `json:"items" binding:"dive"`
 }
 
-// UpdateSliderRequest represents slider update request
+// UpdateSliderRequest represents slider update request; an empty locale or audience clears it
 type UpdateSliderRequest struct {
-	Name     string `json:"name" binding:"omitempty,min=1,max=200"`
-	Type     *int   `json:"type" binding:"omitempty,min=0,max=2"`
-	Location string `json:"location" binding:"omitempty,min=1,max=255"`
+	Name        string      `json:"name" binding:"omitempty,min=1,max=200"`
+	Type        *SliderType `json:"type" swaggertype:"string" enums:"slideshow,carousel,static"`
+	Location    string      `json:"location" binding:"omitempty,min=1,max=255"`
+	Locale      *string     `json:"locale" binding:"omitempty,max=35"`
+	Audience    *string     `json:"audience" binding:"omitempty,max=100"`
+	Enabled     *bool       `json:"enabled"`
+	ActiveFrom  *time.Time  `json:"active_from" binding:"omitempty"`
+	ActiveUntil *time.Time  `json:"active_until" binding:"omitempty"`
+}
+
+// DuplicateSliderRequest represents the location and optional name and locale of a copy of a
+// slider; the locale defaults to the source's. The copy is disabled unless enabled is given, so it
+// can be edited and previewed before it goes live.
+type DuplicateSliderRequest struct {
+	Location string  `json:"location" binding:"required,min=1,max=255"`
+	Locale   *string `json:"locale" binding:"omitempty,max=35"`
+	Name     string  `json:"name" binding:"omitempty,min=1,max=200"`
+	Enabled  *bool   `json:"enabled"`
+}
+
+// SliderLocationQuery selects the variant of a location served to a request. Locale takes
+// precedence over AcceptLanguage, the request's Accept-Language header.
+type SliderLocationQuery struct {
+	Location       string
+	Locale         string
+	Audience       string
+	AcceptLanguage string
+	PreviewToken   string
 }
 
-// CreateSliderItemRequest represents slider item creation request
+// CreateSliderItemRequest represents slider item creation request. An item linked to a published
+// property through imovel_id may leave image_url empty and render the property's data instead.
 type CreateSliderItemRequest struct {
-	ImageURL string   `json:"image_url" binding:"required,min=1,max=2048"`
-	LinkURL  string   `json:"link_url" binding:"omitempty,max=2048"`
-	Content  string   `json:"content" binding:"omitempty,max=1000"`
-	Order    int      `json:"order" binding:"required,min=0"`
-	Tags     []string `json:"tags" binding:"omitempty,dive,max=100"`
-	Titulo   string   `json:"titulo" binding:"omitempty,max=255"`
+	ImageURL    string     `json:"image_url" binding:"required_without=ImovelID,max=2048"`
+	LinkURL     string     `json:"link_url" binding:"omitempty,max=2048"`
+	Content     string     `json:"content" binding:"omitempty,max=1000"`
+	Order       int        `json:"order" binding:"required,min=0"`
+	Tags        []string   `json:"tags" binding:"omitempty,dive,max=100"`
+	Titulo      string     `json:"titulo" binding:"omitempty,max=255"`
+	ImovelID    *uint      `json:"imovel_id" binding:"omitempty,min=1"`
+	Enabled     *bool      `json:"enabled"`
+	ActiveFrom  *time.Time `json:"active_from" binding:"omitempty"`
+	ActiveUntil *time.Time `json:"active_until" binding:"omitempty"`
+}
+
+// UploadSliderItemRequest holds the form fields sent along with an uploaded item image; the
+// image_url of the item is filled in from the stored image
+type UploadSliderItemRequest struct {
+	LinkURL     string     `form:"link_url" binding:"omitempty,max=2048"`
+	Content     string     `form:"content" binding:"omitempty,max=1000"`
+	Order       int        `form:"order" binding:"min=0"`
+	Tags        []string   `form:"tags" binding:"omitempty,dive,max=100"`
+	Titulo      string     `form:"titulo" binding:"omitempty,max=255"`
+	ImovelID    *uint      `form:"imovel_id" binding:"omitempty,min=1"`
+	Enabled     *bool      `form:"enabled"`
+	ActiveFrom  *time.Time `form:"active_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	ActiveUntil *time.Time `form:"active_until" time_format:"2006-01-02T15:04:05Z07:00"`
 }
 
-// UpdateSliderItemRequest represents slider item update request
+// UpdateSliderItemRequest represents slider item update request; imovel_id 0 unlinks the property
 type UpdateSliderItemRequest struct {
-	ImageURL string   `json:"image_url" binding:"omitempty,min=1,max=2048"`
-	LinkURL  string   `json:"link_url" binding:"omitempty,max=2048"`
-	Content  string   `json:"content" binding:"omitempty,max=1000"`
-	Order    *int     `json:"order" binding:"omitempty,min=0"`
-	Tags     []string `json:"tags" binding:"omitempty,dive,max=100"`
-	Titulo   string   `json:"titulo" binding:"omitempty,max=255"`
+	ImageURL    string     `json:"image_url" binding:"omitempty,min=1,max=2048"`
+	LinkURL     string     `json:"link_url" binding:"omitempty,max=2048"`
+	Content     string     `json:"content" binding:"omitempty,max=1000"`
+	Order       *int       `json:"order" binding:"omitempty,min=0"`
+	Tags        []string   `json:"tags" binding:"omitempty,dive,max=100"`
+	Titulo      string     `json:"titulo" binding:"omitempty,max=255"`
+	ImovelID    *uint      `json:"imovel_id"`
+	Enabled     *bool      `json:"enabled"`
+	ActiveFrom  *time.Time `json:"active_from" binding:"omitempty"`
+	ActiveUntil *time.Time `json:"active_until" binding:"omitempty"`
 }
 
 // SliderResponse represents slider response
 type SliderResponse struct {
-	ID        uint                 `json:"id"`
-	Name      string               `json:"name"`
-	Type      int                  `json:"type"`
-	Location  string               `json:"location"`
-	Items     []SliderItemResponse `json:"items"`
-	CreatedAt time.Time            `json:"created_at"`
-	UpdatedAt time.Time            `json:"updated_at"`
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Type        SliderType           `json:"type" swaggertype:"string" enums:"slideshow,carousel,static"`
+	Location    string               `json:"location"`
+	Locale      string               `json:"locale"`
+	Audience    string               `json:"audience"`
+	Enabled     bool                 `json:"enabled"`
+	ActiveFrom  *time.Time           `json:"active_from,omitempty"`
+	ActiveUntil *time.Time           `json:"active_until,omitempty"`
+	Items       []SliderItemResponse `json:"items"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// DeletedSliderResponse represents a soft-deleted slider with the items a restore brings back
+type DeletedSliderResponse struct {
+	SliderResponse
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// PurgeResult counts the sliders and items permanently deleted by a purge
+type PurgeResult struct {
+	Sliders int64 `json:"sliders"`
+	Items   int64 `json:"items"`
 }
 
 // SliderItemResponse represents slider item response
 type SliderItemResponse struct {
-	ID        uint      `json:"id"`
-	SliderID  uint      `json:"slider_id"`
-	ImageURL  string    `json:"image_url"`
-	LinkURL   string    `json:"link_url"`
-	Content   string    `json:"content"`
-	Order     int       `json:"order"`
-	Tags      []string  `json:"tags"`
-	Titulo    string    `json:"titulo"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint                  `json:"id"`
+	SliderID    uint                  `json:"slider_id"`
+	ImageURL    string                `json:"image_url"`
+	Variants    imoveis.AnexoVariants `json:"variants,omitempty"`
+	LinkURL     string                `json:"link_url"`
+	Content     string                `json:"content"`
+	Order       int                   `json:"order"`
+	Tags        []string              `json:"tags"`
+	Titulo      string                `json:"titulo"`
+	Enabled     bool                  `json:"enabled"`
+	ActiveFrom  *time.Time            `json:"active_from,omitempty"`
+	ActiveUntil *time.Time            `json:"active_until,omitempty"`
+	ImovelID    *uint                 `json:"imovel_id,omitempty"`
+	// Imovel is the live data of the linked property, absent when it no longer exists
+	Imovel    *LinkedImovelResponse `json:"imovel,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// ReplaceSliderItemRequest is an item of a replaced item set: the existing item with id, updated to
+// the given fields, or a new item when id is absent
+type ReplaceSliderItemRequest struct {
+	ID *uint `json:"id" binding:"omitempty,min=1"`
+	CreateSliderItemRequest
+}
+
+// ReplaceSliderItemsRequest represents the whole item set of a slider; items left out are deleted
+type ReplaceSliderItemsRequest struct {
+	Items []ReplaceSliderItemRequest `json:"items" binding:"required,dive"`
+}
+
+// LinkedImovelResponse is the trimmed property a slider item links to: what a banner renders
+type LinkedImovelResponse struct {
+	ID           uint                   `json:"id"`
+	Codigo       string                 `json:"codigo"`
+	Slug         string                 `json:"slug"`
+	Titulo       string                 `json:"titulo"`
+	Tipo         string                 `json:"tipo"`
+	Objetivo     string                 `json:"objetivo"`
+	Published    bool                   `json:"published"`
+	PrecoVenda   *float64               `json:"preco_venda,omitempty"`
+	PrecoAluguel *float64               `json:"preco_aluguel,omitempty"`
+	Capa         *imoveis.AnexoResponse `json:"capa,omitempty"`
+}
+
+// PreviewTokenResponse represents a signed preview token for a slider
+type PreviewTokenResponse struct {
+	Token      string    `json:"token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	PreviewURL string    `json:"preview_url"`
+}
+
+// RecordImpressionsRequest represents a batch of slider item impressions. An item listed several
+// times counts one impression per occurrence; unknown items are ignored.
+type RecordImpressionsRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required,min=1,max=500,dive,min=1"`
+}
+
+// SliderStatsQuery represents the days of the slider stats, both included (YYYY-MM-DD, UTC).
+// They default to the last 30 days.
+type SliderStatsQuery struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// DailyStatsResponse represents the impressions and clicks of a slider item on a day
+type DailyStatsResponse struct {
+	Day         string `json:"day"`
+	Impressions int64  `json:"impressions"`
+	Clicks      int64  `json:"clicks"`
+}
+
+// SliderItemStatsResponse represents the impressions and clicks of a slider item over the period.
+// CTR is clicks per impression.
+type SliderItemStatsResponse struct {
+	ItemID      uint                 `json:"item_id"`
+	Titulo      string               `json:"titulo"`
+	ImageURL    string               `json:"image_url"`
+	Order       int                  `json:"order"`
+	Impressions int64                `json:"impressions"`
+	Clicks      int64                `json:"clicks"`
+	CTR         float64              `json:"ctr"`
+	Days        []DailyStatsResponse `json:"days"`
+}
+
+// SliderStatsResponse represents the impressions and clicks of a slider's items over a period
+type SliderStatsResponse struct {
+	SliderID    uint                      `json:"slider_id"`
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	Impressions int64                     `json:"impressions"`
+	Clicks      int64                     `json:"clicks"`
+	CTR         float64                   `json:"ctr"`
+	Items       []SliderItemStatsResponse `json:"items"`
 }
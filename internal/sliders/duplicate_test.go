@@ -0,0 +1,81 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *stubRepository) Create(ctx context.Context, slider *Slider) error {
+	slider.ID = uint(len(r.sliders) + 1)
+	r.sliders[slider.ID] = slider
+	return nil
+}
+
+func TestService_DuplicateSlider(t *testing.T) {
+	ctx := context.Background()
+	until := time.Now().Add(24 * time.Hour)
+
+	newSource := func() *Slider {
+		source := newCarousel()
+		source.Name = "Home"
+		source.ActiveUntil = &until
+		source.Items[1].ImovelID = uintPtr(10)
+		return source
+	}
+
+	t.Run("copies the slider and its items", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newSource())
+
+		copied, err := svc.DuplicateSlider(ctx, 1, &DuplicateSliderRequest{Location: "home-natal"})
+		require.NoError(t, err)
+		assert.NotEqual(t, uint(1), copied.ID)
+		assert.Equal(t, "home-natal", copied.Location)
+		assert.Equal(t, "Home", copied.Name)
+		assert.Equal(t, SliderType_Carousel, copied.Type)
+		assert.False(t, copied.Enabled)
+		assert.Equal(t, &until, copied.ActiveUntil)
+
+		require.Len(t, copied.Items, 3)
+		for i, item := range copied.Items {
+			assert.Equal(t, copied.ID, item.SliderID)
+			assert.Equal(t, newCarousel().Items[i].ImageURL, item.ImageURL)
+		}
+		assert.Equal(t, uintPtr(10), copied.Items[1].ImovelID)
+
+		// The item linking to an unpublished property is only shown in preview
+		token, err := svc.GeneratePreviewToken(ctx, 1)
+		require.NoError(t, err)
+		source, err := svc.GetSlider(ctx, 1, token.Token)
+		require.NoError(t, err)
+		assert.Len(t, source.Items, 3)
+		assert.Equal(t, uint(1), source.Items[0].ID)
+	})
+
+	t.Run("name and enabled override the source", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newSource())
+		enabled := true
+
+		copied, err := svc.DuplicateSlider(ctx, 1, &DuplicateSliderRequest{Location: "home-natal", Name: "Natal", Enabled: &enabled})
+		require.NoError(t, err)
+		assert.Equal(t, "Natal", copied.Name)
+		assert.True(t, copied.Enabled)
+	})
+
+	t.Run("location in use", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newSource())
+
+		_, err := svc.DuplicateSlider(ctx, 1, &DuplicateSliderRequest{Location: "home"})
+		assert.ErrorIs(t, err, ErrLocationExists)
+	})
+
+	t.Run("unknown slider", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{})
+
+		_, err := svc.DuplicateSlider(ctx, 1, &DuplicateSliderRequest{Location: "home-natal"})
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+}
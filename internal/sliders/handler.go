@@ -1,21 +1,31 @@
 package sliders
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/pagination"
 )
 
 type Handler struct {
 	service Service
+	// cacheMaxAge is how long clients and CDNs may reuse a location lookup
+	cacheMaxAge time.Duration
 }
 
-// NewHandler creates a new slider handler
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+// NewHandler creates a new slider handler; location lookups may be cached for cacheMaxAge
+// (DefaultCacheTTL when not positive)
+func NewHandler(service Service, cacheMaxAge time.Duration) *Handler {
+	if cacheMaxAge <= 0 {
+		cacheMaxAge = DefaultCacheTTL
+	}
+	return &Handler{service: service, cacheMaxAge: cacheMaxAge}
 }
 
 // @Summary Create slider
@@ -39,13 +49,34 @@ func (h *Handler) CreateSlider(c *gin.Context) {
 	slider, err := h.service.CreateSlider(c.Request.Context(), &req)
 	if err != nil {
 		if err == ErrLocationExists {
-			_ = c.Error(apiErrors.Conflict("Location already exists"))
+			_ = c.Error(apiErrors.Conflict("Location already has a slider for this locale"))
+			return
+		}
+		if err == ErrInvalidLocale {
+			_ = c.Error(apiErrors.BadRequest("Invalid locale"))
 			return
 		}
 		if err == ErrInvalidType {
 			_ = c.Error(apiErrors.BadRequest("Invalid slider type"))
 			return
 		}
+		if err == ErrImovelNotFound {
+			_ = c.Error(apiErrors.BadRequest("Linked property not found"))
+			return
+		}
+		if err == ErrImovelNotPublished {
+			_ = c.Error(apiErrors.BadRequest("Linked property is not published"))
+			return
+		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -59,7 +90,9 @@ func (h *Handler) CreateSlider(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Slider ID"
+// @Param preview_token query string false "Preview token granting access to a disabled slider"
 // @Success 200 {object} errors.Response{success=bool,data=SliderResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/sliders/{id} [get]
 func (h *Handler) GetSlider(c *gin.Context) {
@@ -69,12 +102,16 @@ func (h *Handler) GetSlider(c *gin.Context) {
 		return
 	}
 
-	slider, err := h.service.GetSlider(c.Request.Context(), uint(id))
+	slider, err := h.service.GetSlider(c.Request.Context(), uint(id), c.Query("preview_token"))
 	if err != nil {
 		if err == ErrSliderNotFound {
 			_ = c.Error(apiErrors.NotFound("Slider not found"))
 			return
 		}
+		if err == ErrInvalidPreviewToken {
+			_ = c.Error(apiErrors.Unauthorized("Invalid or expired preview token"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -83,31 +120,53 @@ func (h *Handler) GetSlider(c *gin.Context) {
 }
 
 // @Summary Get slider by location
-// @Description Retrieve a slider and its items by location
+// @Description Retrieve the slider of a location that best matches the locale and audience, with its currently active items. The locale query parameter takes precedence over Accept-Language; the default-locale variant is the fallback.
 // @Tags sliders
 // @Accept json
 // @Produce json
 // @Param location query string true "Slider location"
+// @Param locale query string false "Preferred locale (BCP 47, e.g. en-US)"
+// @Param audience query string false "Audience segment"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param preview_token query string false "Preview token granting access to a disabled slider"
 // @Success 200 {object} errors.Response{success=bool,data=SliderResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/sliders/location [get]
 func (h *Handler) GetSliderByLocation(c *gin.Context) {
-	location := c.Query("location")
-	if location == "" {
+	query := &SliderLocationQuery{
+		Location:       c.Query("location"),
+		Locale:         c.Query("locale"),
+		Audience:       c.Query("audience"),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		PreviewToken:   c.Query("preview_token"),
+	}
+	if query.Location == "" {
 		_ = c.Error(apiErrors.BadRequest("Location parameter is required"))
 		return
 	}
 
-	slider, err := h.service.GetSliderByLocation(c.Request.Context(), location)
+	slider, err := h.service.GetSliderByLocation(c.Request.Context(), query)
 	if err != nil {
 		if err == ErrSliderNotFound {
 			_ = c.Error(apiErrors.NotFound("Slider not found"))
 			return
 		}
+		if err == ErrInvalidPreviewToken {
+			_ = c.Error(apiErrors.Unauthorized("Invalid or expired preview token"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
 
+	// Previews show unpublished content to whoever holds the token and must not be shared
+	if query.PreviewToken != "" {
+		c.Header("Cache-Control", "private, no-store")
+	} else {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cacheMaxAge.Seconds())))
+		c.Header("Vary", "Accept-Language")
+	}
 	c.JSON(http.StatusOK, apiErrors.Success(slider))
 }
 
@@ -144,13 +203,26 @@ func (h *Handler) UpdateSlider(c *gin.Context) {
 			return
 		}
 		if err == ErrLocationExists {
-			_ = c.Error(apiErrors.Conflict("Location already exists"))
+			_ = c.Error(apiErrors.Conflict("Location already has a slider for this locale"))
+			return
+		}
+		if err == ErrInvalidLocale {
+			_ = c.Error(apiErrors.BadRequest("Invalid locale"))
 			return
 		}
 		if err == ErrInvalidType {
 			_ = c.Error(apiErrors.BadRequest("Invalid slider type"))
 			return
 		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -189,13 +261,13 @@ func (h *Handler) DeleteSlider(c *gin.Context) {
 }
 
 // @Summary List sliders
-// @Description Retrieve paginated list of sliders
+// @Description Retrieve a paginated list of the sliders shown at the moment, with their active items
 // @Tags sliders
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
-// @Success 200 {object} errors.Response{success=bool,data=[]SliderResponse}
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[SliderResponse]}
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/sliders [get]
 func (h *Handler) ListSliders(c *gin.Context) {
@@ -220,16 +292,7 @@ func (h *Handler) ListSliders(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    sliders,
-		"pagination": gin.H{
-			"page":        page,
-			"per_page":    perPage,
-			"total":       total,
-			"total_pages": (total + int64(perPage) - 1) / int64(perPage),
-		},
-	})
+	c.JSON(http.StatusOK, apiErrors.Success(pagination.New(sliders, total, page, perPage)))
 }
 
 // @Summary Add slider item
@@ -263,6 +326,118 @@ func (h *Handler) AddSliderItem(c *gin.Context) {
 			_ = c.Error(apiErrors.NotFound("Slider not found"))
 			return
 		}
+		if err == ErrImovelNotFound {
+			_ = c.Error(apiErrors.BadRequest("Linked property not found"))
+			return
+		}
+		if err == ErrImovelNotPublished {
+			_ = c.Error(apiErrors.BadRequest("Linked property is not published"))
+			return
+		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(item))
+}
+
+// @Summary Upload slider item
+// @Description Add an item showing an uploaded image (JPEG, PNG, GIF or WebP). The image is stored with thumbnail, medium and large renditions and becomes the item's image_url.
+// @Tags sliders
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Param file formData file true "Image"
+// @Param order formData int false "Item order"
+// @Param link_url formData string false "Link URL"
+// @Param content formData string false "Content"
+// @Param titulo formData string false "Title"
+// @Param tags formData []string false "Tags"
+// @Param imovel_id formData int false "Linked property ID"
+// @Param enabled formData bool false "Whether the item is enabled (default true)"
+// @Param active_from formData string false "Start of the active window (RFC 3339)"
+// @Param active_until formData string false "End of the active window (RFC 3339)"
+// @Success 201 {object} errors.Response{success=bool,data=SliderItemResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 413 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/{id}/items/upload [post]
+func (h *Handler) UploadSliderItem(c *gin.Context) {
+	sliderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	maxBytes := h.service.MaxUploadBytes()
+	// Leave room for the other form fields and multipart boundaries
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			_ = c.Error(apiErrors.PayloadTooLarge(ErrFileTooLarge.Error()))
+			return
+		}
+		_ = c.Error(apiErrors.BadRequest("multipart field 'file' is required"))
+		return
+	}
+
+	var req UploadSliderItemRequest
+	if err := c.ShouldBind(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	item, err := h.service.UploadSliderItem(c.Request.Context(), uint(sliderID), &UploadedFile{Size: header.Size, Content: file}, &req)
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider not found"))
+			return
+		}
+		if errors.Is(err, ErrFileTooLarge) {
+			_ = c.Error(apiErrors.PayloadTooLarge(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrUnsupportedFileType) || errors.Is(err, ErrInvalidImage) {
+			_ = c.Error(apiErrors.BadRequest(err.Error()))
+			return
+		}
+		if err == ErrImovelNotFound {
+			_ = c.Error(apiErrors.BadRequest("Linked property not found"))
+			return
+		}
+		if err == ErrImovelNotPublished {
+			_ = c.Error(apiErrors.BadRequest("Linked property is not published"))
+			return
+		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -271,12 +446,14 @@ func (h *Handler) AddSliderItem(c *gin.Context) {
 }
 
 // @Summary Get slider item
-// @Description Retrieve a specific slider item by ID
+// @Description Retrieve a slider item by ID. Items that are not shown at the moment (disabled, unscheduled or in such a slider) need a preview token of their slider.
 // @Tags sliders
 // @Accept json
 // @Produce json
 // @Param item_id path int true "Slider item ID"
+// @Param preview_token query string false "Preview token granting access to the items of a disabled slider"
 // @Success 200 {object} errors.Response{success=bool,data=SliderItemResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/sliders/items/{item_id} [get]
 func (h *Handler) GetSliderItem(c *gin.Context) {
@@ -286,12 +463,16 @@ func (h *Handler) GetSliderItem(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.GetSliderItem(c.Request.Context(), uint(itemID))
+	item, err := h.service.GetSliderItem(c.Request.Context(), uint(itemID), c.Query("preview_token"))
 	if err != nil {
 		if err == ErrSliderItemNotFound {
 			_ = c.Error(apiErrors.NotFound("Slider item not found"))
 			return
 		}
+		if err == ErrInvalidPreviewToken {
+			_ = c.Error(apiErrors.Unauthorized("Invalid or expired preview token"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -330,6 +511,23 @@ func (h *Handler) UpdateSliderItem(c *gin.Context) {
 			_ = c.Error(apiErrors.NotFound("Slider item not found"))
 			return
 		}
+		if err == ErrImovelNotFound {
+			_ = c.Error(apiErrors.BadRequest("Linked property not found"))
+			return
+		}
+		if err == ErrImovelNotPublished {
+			_ = c.Error(apiErrors.BadRequest("Linked property is not published"))
+			return
+		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -367,13 +565,233 @@ func (h *Handler) DeleteSliderItem(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// @Summary Duplicate slider
+// @Description Copy a slider and all its items to a new location, e.g. to prepare a seasonal variant. The copy is disabled unless enabled is given.
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Param request body DuplicateSliderRequest true "Location and name of the copy"
+// @Success 201 {object} errors.Response{success=bool,data=SliderResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/{id}/duplicate [post]
+func (h *Handler) DuplicateSlider(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	var req DuplicateSliderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	slider, err := h.service.DuplicateSlider(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider not found"))
+			return
+		}
+		if err == ErrLocationExists {
+			_ = c.Error(apiErrors.Conflict("Location already has a slider for this locale"))
+			return
+		}
+		if err == ErrInvalidLocale {
+			_ = c.Error(apiErrors.BadRequest("Invalid locale"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(slider))
+}
+
+// @Summary List all sliders (Admin only)
+// @Description Paginated list of every slider, including disabled and unscheduled ones, with all their items
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[SliderResponse]}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/sliders [get]
+func (h *Handler) ListAllSliders(c *gin.Context) {
+	page := 1
+	perPage := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if pp := c.Query("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 && parsed <= 100 {
+			perPage = parsed
+		}
+	}
+
+	sliders, total, err := h.service.ListAllSliders(c.Request.Context(), page, perPage)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(pagination.New(sliders, total, page, perPage)))
+}
+
+// @Summary List deleted sliders (Admin only)
+// @Description Paginated list of soft-deleted sliders with the items a restore brings back, most recently deleted first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} errors.Response{success=bool,data=pagination.Page[DeletedSliderResponse]}
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/sliders/trash [get]
+func (h *Handler) ListDeletedSliders(c *gin.Context) {
+	page := 1
+	perPage := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if pp := c.Query("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 && parsed <= 100 {
+			perPage = parsed
+		}
+	}
+
+	sliders, total, err := h.service.ListDeletedSliders(c.Request.Context(), page, perPage)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(pagination.New(sliders, total, page, perPage)))
+}
+
+// @Summary Restore a deleted slider (Admin only)
+// @Description Restore a soft-deleted slider with the items deleted along with it. Items deleted before the slider stay deleted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Success 200 {object} errors.Response{success=bool,data=SliderResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/admin/sliders/{id}/restore [post]
+func (h *Handler) RestoreSlider(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	slider, err := h.service.RestoreSlider(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Deleted slider not found"))
+			return
+		}
+		if err == ErrLocationExists {
+			_ = c.Error(apiErrors.Conflict("Location already has a slider for this locale"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(slider))
+}
+
+// @Summary Replace slider items
+// @Description Replace the whole item set of a slider in one transaction: items with an id are updated, items without one are created and the existing items left out are deleted
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Param request body ReplaceSliderItemsRequest true "Slider item set"
+// @Success 200 {object} errors.Response{success=bool,data=[]SliderItemResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/{slider_id}/items [put]
+func (h *Handler) ReplaceSliderItems(c *gin.Context) {
+	sliderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	var req ReplaceSliderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	items, err := h.service.ReplaceSliderItems(c.Request.Context(), uint(sliderID), &req)
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider not found"))
+			return
+		}
+		if err == ErrItemNotInSlider {
+			_ = c.Error(apiErrors.BadRequest("Item does not belong to the slider"))
+			return
+		}
+		if err == ErrDuplicateItem {
+			_ = c.Error(apiErrors.BadRequest("Item listed more than once"))
+			return
+		}
+		if err == ErrImovelNotFound {
+			_ = c.Error(apiErrors.BadRequest("Linked property not found"))
+			return
+		}
+		if err == ErrImovelNotPublished {
+			_ = c.Error(apiErrors.BadRequest("Linked property is not published"))
+			return
+		}
+		if err == ErrInvalidActiveWindow {
+			_ = c.Error(apiErrors.BadRequest("active_until must be after active_from"))
+			return
+		}
+		var limitErr *LimitViolationError
+		if errors.As(err, &limitErr) {
+			_ = c.Error(apiErrors.ValidationError(limitErr.Violations))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(items))
+}
+
 // @Summary Get slider items
 // @Description Retrieve all items for a specific slider
 // @Tags sliders
 // @Accept json
 // @Produce json
 // @Param id path int true "Slider ID"
+// @Param preview_token query string false "Preview token granting access to a disabled slider"
 // @Success 200 {object} errors.Response{success=bool,data=[]SliderItemResponse}
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
 // @Router /api/v1/sliders/{slider_id}/items [get]
 func (h *Handler) GetSliderItems(c *gin.Context) {
@@ -383,15 +801,145 @@ func (h *Handler) GetSliderItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.service.GetSliderItems(c.Request.Context(), uint(sliderID))
+	items, err := h.service.GetSliderItems(c.Request.Context(), uint(sliderID), c.Query("preview_token"))
 	if err != nil {
 		if err == ErrSliderNotFound {
 			_ = c.Error(apiErrors.NotFound("Slider not found"))
 			return
 		}
+		if err == ErrInvalidPreviewToken {
+			_ = c.Error(apiErrors.Unauthorized("Invalid or expired preview token"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, apiErrors.Success(items))
 }
+
+// @Summary Generate slider preview token
+// @Description Issue a short-lived signed token that renders a disabled slider through the public endpoints
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Success 201 {object} errors.Response{success=bool,data=PreviewTokenResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/{id}/preview-token [post]
+func (h *Handler) GeneratePreviewToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	token, err := h.service.GeneratePreviewToken(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(token))
+}
+
+// @Summary Register a slider item click
+// @Description Count a click on a slider item for the current day
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Param item_id path int true "Slider item ID"
+// @Success 204
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/items/{item_id}/click [post]
+func (h *Handler) RecordClick(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid item ID"))
+		return
+	}
+
+	if err := h.service.RecordClick(c.Request.Context(), uint(itemID)); err != nil {
+		if err == ErrSliderItemNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider item not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Register slider item impressions
+// @Description Count a batch of slider item impressions for the current day. Clients buffer the items shown and send them together; an item listed several times counts once per occurrence and unknown items are ignored.
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Param request body RecordImpressionsRequest true "Impressions batch"
+// @Success 204
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/impressions [post]
+func (h *Handler) RecordImpressions(c *gin.Context) {
+	var req RecordImpressionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.service.RecordImpressions(c.Request.Context(), &req); err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Slider stats
+// @Description Impressions, clicks and click-through rate of each item of a slider, per day and in total over a period of at most 366 days
+// @Tags sliders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slider ID"
+// @Param from query string false "First day (YYYY-MM-DD, UTC); defaults to 30 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD, UTC); defaults to today"
+// @Success 200 {object} errors.Response{success=bool,data=SliderStatsResponse}
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo}
+// @Router /api/v1/sliders/{id}/stats [get]
+func (h *Handler) GetSliderStats(c *gin.Context) {
+	sliderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid slider ID"))
+		return
+	}
+
+	var query SliderStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	stats, err := h.service.GetSliderStats(c.Request.Context(), uint(sliderID), &query)
+	if err != nil {
+		if err == ErrSliderNotFound {
+			_ = c.Error(apiErrors.NotFound("Slider not found"))
+			return
+		}
+		if errors.Is(err, ErrInvalidStatsPeriod) {
+			_ = c.Error(apiErrors.BadRequest(err.Error()))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(stats))
+}
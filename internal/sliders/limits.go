@@ -0,0 +1,263 @@
+package sliders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder for dimension checks
+	_ "image/jpeg" // register JPEG decoder for dimension checks
+	_ "image/png"  // register PNG decoder for dimension checks
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// LimitViolationError is returned when a slider or its items exceed the limits configured for the slider type.
+// Violations maps the offending field (e.g. "items", "items[2].content") to a human-readable message.
+type LimitViolationError struct {
+	Violations map[string]string
+}
+
+func (e *LimitViolationError) Error() string {
+	return fmt.Sprintf("slider limits exceeded: %d violation(s)", len(e.Violations))
+}
+
+// maxInspectBytes caps how many bytes of a remote image are counted when the server does not
+// report its size
+const maxInspectBytes = 50 << 20
+
+// maxHeaderBytes is how much of a remote image is read to decode its dimensions
+const maxHeaderBytes = 64 << 10
+
+// maxInspectRedirects caps the redirects followed while inspecting an image
+const maxInspectRedirects = 3
+
+// ImageInfo describes the properties of a remote image relevant to slider limits
+type ImageInfo struct {
+	Width  int
+	Height int
+	Bytes  int64
+}
+
+// ImageInspector resolves the size and dimensions of an image referenced by URL
+type ImageInspector interface {
+	Inspect(ctx context.Context, url string) (*ImageInfo, error)
+}
+
+type httpImageInspector struct {
+	client *http.Client
+}
+
+// NewHTTPImageInspector creates an ImageInspector that fetches images over HTTP(S). The URLs come
+// from admins, so the client only connects to public addresses (checked after DNS resolution, and
+// again on every redirect) and ignores the proxy environment.
+func NewHTTPImageInspector(timeout time.Duration) ImageInspector {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	return &httpImageInspector{client: &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxInspectRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxInspectRedirects)
+			}
+			return checkImageURL(req.URL)
+		},
+	}}
+}
+
+// publicAddressOnly refuses connections to loopback, private, link-local and other non-public addresses
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("address %s is not public", host)
+	}
+	return nil
+}
+
+// checkImageURL accepts only absolute http and https URLs
+func checkImageURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// Inspect requests the first bytes of the image and decodes its header. Bytes comes from
+// Content-Range or Content-Length; only when the server reports neither is the body counted (up
+// to maxInspectBytes, without keeping it). Width and Height are zero when the format cannot be
+// decoded (only JPEG, PNG and GIF are supported).
+func (i *httpImageInspector) Inspect(ctx context.Context, rawURL string) (*ImageInfo, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+	if err := checkImageURL(u); err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", maxHeaderBytes-1))
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("failed to fetch image: unexpected status %d", resp.StatusCode)
+	}
+
+	header, err := io.ReadAll(io.LimitReader(resp.Body, maxHeaderBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	info := &ImageInfo{Bytes: imageSize(resp)}
+	if info.Bytes < 0 {
+		rest, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxInspectBytes+1-int64(len(header))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image: %w", err)
+		}
+		info.Bytes = int64(len(header)) + rest
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(header)); err == nil {
+		info.Width = cfg.Width
+		info.Height = cfg.Height
+	}
+
+	return info, nil
+}
+
+// imageSize returns the full size of the image reported by the response, or -1 when unknown
+func imageSize(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes 0-65535/1048576
+		contentRange := resp.Header.Get("Content-Range")
+		if slash := strings.LastIndex(contentRange, "/"); slash >= 0 {
+			if size, err := strconv.ParseInt(contentRange[slash+1:], 10, 64); err == nil {
+				return size
+			}
+		}
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// limitCandidate is an item (new or existing) checked against the slider type limits
+type limitCandidate struct {
+	field      string
+	imageURL   string
+	content    string
+	checkImage bool
+	// image describes an uploaded image, which is checked without downloading imageURL
+	image *ImageInfo
+}
+
+// limitsFor returns the configured limits for the given slider type
+func (s *service) limitsFor(sliderType SliderType) config.SliderLimitsConfig {
+	switch sliderType {
+	case SliderType_Carousel:
+		return s.limits.Carousel
+	case SliderType_Static:
+		return s.limits.Static
+	default:
+		return s.limits.Slideshow
+	}
+}
+
+// enforceLimits validates the total item count and each candidate item against the limits
+// of the slider type, collecting every violation into a LimitViolationError
+func (s *service) enforceLimits(ctx context.Context, sliderType SliderType, itemCount int, items []limitCandidate) error {
+	limits := s.limitsFor(sliderType)
+	violations := make(map[string]string)
+
+	if limits.MaxItems > 0 && itemCount > limits.MaxItems {
+		violations["items"] = fmt.Sprintf("%s sliders accept at most %d items (got %d)", sliderType, limits.MaxItems, itemCount)
+	}
+
+	for _, item := range items {
+		if limits.MaxContentLength > 0 && utf8.RuneCountInString(item.content) > limits.MaxContentLength {
+			violations[fieldPath(item.field, "content")] = fmt.Sprintf("content must be at most %d characters", limits.MaxContentLength)
+		}
+		if item.checkImage && (item.imageURL != "" || item.image != nil) {
+			s.checkImage(ctx, limits, item, violations)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &LimitViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// checkImage inspects the item image when any image limit is configured
+func (s *service) checkImage(ctx context.Context, limits config.SliderLimitsConfig, item limitCandidate, violations map[string]string) {
+	checkDimensions := limits.MinImageWidth > 0 || limits.MinImageHeight > 0 || limits.MaxImageWidth > 0 || limits.MaxImageHeight > 0
+	if !checkDimensions && limits.MaxImageBytes == 0 {
+		return
+	}
+
+	field := fieldPath(item.field, "image_url")
+	info := item.image
+	if info == nil {
+		var err error
+		if info, err = s.inspector.Inspect(ctx, item.imageURL); err != nil {
+			violations[field] = fmt.Sprintf("image could not be inspected: %v", err)
+			return
+		}
+	}
+
+	if limits.MaxImageBytes > 0 && info.Bytes > limits.MaxImageBytes {
+		violations[field] = fmt.Sprintf("image must be at most %d bytes (got %d)", limits.MaxImageBytes, info.Bytes)
+		return
+	}
+
+	if !checkDimensions {
+		return
+	}
+
+	switch {
+	case info.Width == 0 || info.Height == 0:
+		violations[field] = "image dimensions could not be determined (supported formats: JPEG, PNG, GIF)"
+	case info.Width < limits.MinImageWidth || info.Height < limits.MinImageHeight:
+		violations[field] = fmt.Sprintf("image must be at least %dx%d pixels (got %dx%d)",
+			limits.MinImageWidth, limits.MinImageHeight, info.Width, info.Height)
+	case (limits.MaxImageWidth > 0 && info.Width > limits.MaxImageWidth) ||
+		(limits.MaxImageHeight > 0 && info.Height > limits.MaxImageHeight):
+		violations[field] = fmt.Sprintf("image must be at most %dx%d pixels (got %dx%d)",
+			limits.MaxImageWidth, limits.MaxImageHeight, info.Width, info.Height)
+	}
+}
+
+func fieldPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
@@ -0,0 +1,205 @@
+package sliders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+type stubInspector struct {
+	info *ImageInfo
+	err  error
+}
+
+func (s *stubInspector) Inspect(ctx context.Context, url string) (*ImageInfo, error) {
+	return s.info, s.err
+}
+
+func newLimitsService(limits config.SlidersConfig, inspector ImageInspector) *service {
+	return &service{limits: limits, inspector: inspector}
+}
+
+func TestService_EnforceLimits(t *testing.T) {
+	limits := config.SlidersConfig{
+		Carousel: config.SliderLimitsConfig{
+			MaxItems:         2,
+			MaxContentLength: 10,
+			MinImageWidth:    800,
+			MinImageHeight:   400,
+			MaxImageWidth:    4000,
+			MaxImageHeight:   2000,
+			MaxImageBytes:    1024,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		sliderType SliderType
+		itemCount  int
+		items      []limitCandidate
+		inspector  ImageInspector
+		violations []string
+	}{
+		{
+			name:       "within limits",
+			sliderType: SliderType_Carousel,
+			itemCount:  2,
+			items:      []limitCandidate{{field: "items[0]", imageURL: "http://img", content: "short", checkImage: true}},
+			inspector:  &stubInspector{info: &ImageInfo{Width: 1200, Height: 600, Bytes: 512}},
+		},
+		{
+			name:       "too many items",
+			sliderType: SliderType_Carousel,
+			itemCount:  3,
+			inspector:  &stubInspector{},
+			violations: []string{"items"},
+		},
+		{
+			name:       "content too long",
+			sliderType: SliderType_Carousel,
+			itemCount:  1,
+			items:      []limitCandidate{{field: "items[0]", content: strings.Repeat("a", 11)}},
+			inspector:  &stubInspector{},
+			violations: []string{"items[0].content"},
+		},
+		{
+			name:       "image too small",
+			sliderType: SliderType_Carousel,
+			itemCount:  1,
+			items:      []limitCandidate{{imageURL: "http://img", checkImage: true}},
+			inspector:  &stubInspector{info: &ImageInfo{Width: 200, Height: 100, Bytes: 512}},
+			violations: []string{"image_url"},
+		},
+		{
+			name:       "image too large in bytes",
+			sliderType: SliderType_Carousel,
+			itemCount:  1,
+			items:      []limitCandidate{{imageURL: "http://img", checkImage: true}},
+			inspector:  &stubInspector{info: &ImageInfo{Width: 1200, Height: 600, Bytes: 4096}},
+			violations: []string{"image_url"},
+		},
+		{
+			name:       "image cannot be fetched",
+			sliderType: SliderType_Carousel,
+			itemCount:  1,
+			items:      []limitCandidate{{imageURL: "http://img", checkImage: true}},
+			inspector:  &stubInspector{err: errors.New("connection refused")},
+			violations: []string{"image_url"},
+		},
+		{
+			name:       "image check skipped when not requested",
+			sliderType: SliderType_Carousel,
+			itemCount:  1,
+			items:      []limitCandidate{{imageURL: "http://img"}},
+			inspector:  &stubInspector{err: errors.New("should not be called")},
+		},
+		{
+			name:       "unconfigured type has no limits",
+			sliderType: SliderType_Static,
+			itemCount:  50,
+			items:      []limitCandidate{{imageURL: "http://img", content: strings.Repeat("a", 5000), checkImage: true}},
+			inspector:  &stubInspector{err: errors.New("should not be called")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newLimitsService(limits, tt.inspector)
+
+			err := svc.enforceLimits(context.Background(), tt.sliderType, tt.itemCount, tt.items)
+			if len(tt.violations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var limitErr *LimitViolationError
+			require.ErrorAs(t, err, &limitErr)
+			assert.Len(t, limitErr.Violations, len(tt.violations))
+			for _, field := range tt.violations {
+				assert.Contains(t, limitErr.Violations, field)
+			}
+		})
+	}
+}
+
+func TestPublicAddressOnly(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "10.0.0.5:443", "192.168.1.10:80", "169.254.169.254:80", "[::1]:443", "[fd00::1]:80", "0.0.0.0:80"} {
+		assert.Error(t, publicAddressOnly("tcp", address, nil), address)
+	}
+	for _, address := range []string{"93.184.216.34:443", "[2606:2800:220:1:248:1893:25c8:1946]:80"} {
+		assert.NoError(t, publicAddressOnly("tcp", address, nil), address)
+	}
+}
+
+func TestHTTPImageInspector_RejectsURLs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the inspector must not reach a loopback server")
+	}))
+	defer srv.Close()
+	inspector := NewHTTPImageInspector(time.Second)
+
+	for _, rawURL := range []string{"file:///etc/passwd", "gopher://example.com/x", "ftp://example.com/a.png", "/relative.png"} {
+		_, err := inspector.Inspect(context.Background(), rawURL)
+		assert.ErrorContains(t, err, "invalid image URL", rawURL)
+	}
+
+	_, err := inspector.Inspect(context.Background(), srv.URL+"/a.png")
+	assert.ErrorContains(t, err, "is not public")
+}
+
+func TestHTTPImageInspector_ReadsHeaderOnly(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1600, 900))))
+	// Pad past the header budget so the full image is larger than what is read
+	data := append(buf.Bytes(), make([]byte, 4*maxHeaderBytes)...)
+
+	served := make(chan int, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &countingWriter{ResponseWriter: w}
+		if r.URL.Path == "/chunked.png" {
+			// No Range support and no Content-Length
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = rec.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			_, _ = rec.Write(data[len(data)/2:])
+		} else {
+			http.ServeContent(rec, r, "a.png", time.Time{}, bytes.NewReader(data))
+		}
+		served <- rec.n
+	}))
+	defer srv.Close()
+	inspector := &httpImageInspector{client: srv.Client()}
+
+	info, err := inspector.Inspect(context.Background(), srv.URL+"/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, &ImageInfo{Width: 1600, Height: 900, Bytes: int64(len(data))}, info)
+	assert.Equal(t, maxHeaderBytes, <-served, "only the requested range is sent")
+
+	info, err = inspector.Inspect(context.Background(), srv.URL+"/chunked.png")
+	require.NoError(t, err)
+	assert.Equal(t, &ImageInfo{Width: 1600, Height: 900, Bytes: int64(len(data))}, info)
+	<-served
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += n
+	return n, err
+}
@@ -0,0 +1,107 @@
+package sliders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+// ImovelLookup resolves the properties slider items link to; imoveis.Service implements it
+type ImovelLookup interface {
+	GetImovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error)
+}
+
+// checkLinkedImovel validates that the property an item links to exists and is published
+func (s *service) checkLinkedImovel(ctx context.Context, id uint) error {
+	imovel, err := s.findImovel(ctx, id)
+	if err != nil {
+		return err
+	}
+	if imovel == nil {
+		return ErrImovelNotFound
+	}
+	if !imovel.Published {
+		return ErrImovelNotPublished
+	}
+	return nil
+}
+
+// findImovel returns the property with id, nil when it does not exist
+func (s *service) findImovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error) {
+	if s.imoveis == nil {
+		return nil, nil
+	}
+	imovel, err := s.imoveis.GetImovel(ctx, id)
+	if err != nil {
+		if errors.Is(err, imoveis.ErrImovelNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find linked property: %w", err)
+	}
+	return imovel, nil
+}
+
+// linkedImoveis loads the properties the items link to, each once. Properties that no longer
+// exist are left out of the map.
+func (s *service) linkedImoveis(ctx context.Context, items []SliderItem) (map[uint]*imoveis.ImovelResponse, error) {
+	linked := make(map[uint]*imoveis.ImovelResponse)
+	seen := make(map[uint]bool)
+	for _, item := range items {
+		if item.ImovelID == nil || seen[*item.ImovelID] {
+			continue
+		}
+		seen[*item.ImovelID] = true
+
+		imovel, err := s.findImovel(ctx, *item.ImovelID)
+		if err != nil {
+			return nil, err
+		}
+		if imovel != nil {
+			linked[*item.ImovelID] = imovel
+		}
+	}
+	return linked, nil
+}
+
+// publishedItems drops the items linked to a property that is gone or no longer published
+func publishedItems(items []SliderItem, linked map[uint]*imoveis.ImovelResponse) []SliderItem {
+	kept := make([]SliderItem, 0, len(items))
+	for _, item := range items {
+		if item.ImovelID != nil {
+			if imovel := linked[*item.ImovelID]; imovel == nil || !imovel.Published {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// toLinkedImovel trims a property to what a slider item renders. Only active prices are shown,
+// and the cover is the first published image.
+func toLinkedImovel(imovel *imoveis.ImovelResponse) *LinkedImovelResponse {
+	response := &LinkedImovelResponse{
+		ID:        imovel.ID,
+		Codigo:    imovel.Codigo,
+		Slug:      imovel.Slug,
+		Titulo:    imovel.Titulo,
+		Tipo:      imovel.Tipo,
+		Objetivo:  imovel.Objetivo,
+		Published: imovel.Published,
+	}
+	if imovel.PrecoVenda != nil && imovel.PrecoVenda.Ativo {
+		response.PrecoVenda = &imovel.PrecoVenda.Preco
+	}
+	if imovel.PrecoAluguel != nil && imovel.PrecoAluguel.Ativo {
+		response.PrecoAluguel = &imovel.PrecoAluguel.Preco
+	}
+	for i := range imovel.Anexos {
+		if anexo := &imovel.Anexos[i]; anexo.Image && anexo.CanPublish {
+			response.Capa = anexo
+			break
+		}
+	}
+	return response
+}
@@ -0,0 +1,118 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
+
+type stubImovelLookup map[uint]*imoveis.ImovelResponse
+
+func (l stubImovelLookup) GetImovel(ctx context.Context, id uint) (*imoveis.ImovelResponse, error) {
+	if imovel, ok := l[id]; ok {
+		return imovel, nil
+	}
+	return nil, imoveis.ErrImovelNotFound
+}
+
+func (r *stubRepository) CreateItem(ctx context.Context, item *SliderItem) error {
+	for _, slider := range r.sliders {
+		for _, existing := range slider.Items {
+			item.ID = max(item.ID, existing.ID)
+		}
+	}
+	item.ID++
+	r.sliders[item.SliderID].Items = append(r.sliders[item.SliderID].Items, *item)
+	return nil
+}
+
+func newLinkedService(lookup stubImovelLookup, sliders ...*Slider) *service {
+	svc := newPreviewService(time.Hour, sliders...)
+	svc.imoveis = lookup
+	return svc
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestService_AddSliderItem_LinkedImovel(t *testing.T) {
+	ctx := context.Background()
+	lookup := stubImovelLookup{
+		10: {ID: 10, Titulo: "Apartamento no centro", Published: true},
+		11: {ID: 11, Titulo: "Casa em edição", Published: false},
+	}
+
+	tests := []struct {
+		name     string
+		imovelID uint
+		wantErr  error
+	}{
+		{"published property", 10, nil},
+		{"unpublished property", 11, ErrImovelNotPublished},
+		{"unknown property", 99, ErrImovelNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newLinkedService(lookup, &Slider{ID: 1, Location: "home", Enabled: true})
+
+			item, err := svc.AddSliderItem(ctx, 1, &CreateSliderItemRequest{ImovelID: uintPtr(tt.imovelID)})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, item.Imovel)
+			assert.Equal(t, "Apartamento no centro", item.Imovel.Titulo)
+		})
+	}
+}
+
+func TestService_GetSliderByLocation_LinkedImovel(t *testing.T) {
+	ctx := context.Background()
+	lookup := stubImovelLookup{
+		10: {
+			ID:           10,
+			Titulo:       "Apartamento no centro",
+			Published:    true,
+			PrecoVenda:   &imoveis.PrecoVendaResponse{Preco: 500000, Ativo: true},
+			PrecoAluguel: &imoveis.PrecoAluguelResponse{Preco: 2500, Ativo: false},
+			Anexos: []imoveis.AnexoResponse{
+				{ID: 1, Nome: "planta.pdf"},
+				{ID: 2, Nome: "fachada.jpg", Image: true, CanPublish: true, URL: "https://cdn/fachada.jpg"},
+			},
+		},
+		11: {ID: 11, Titulo: "Casa arquivada", Published: false},
+	}
+	slider := &Slider{
+		ID:       1,
+		Location: "home",
+		Enabled:  true,
+		Items: []SliderItem{
+			{ID: 1, Enabled: true, ImageURL: "https://cdn/banner.jpg"},
+			{ID: 2, Enabled: true, ImovelID: uintPtr(10)},
+			{ID: 3, Enabled: true, ImovelID: uintPtr(11)},
+			{ID: 4, Enabled: true, ImovelID: uintPtr(12)},
+		},
+	}
+	svc := newLinkedService(lookup, slider)
+
+	resp, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home"})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 2)
+	assert.Nil(t, resp.Items[0].Imovel)
+
+	linked := resp.Items[1].Imovel
+	require.NotNil(t, linked)
+	assert.Equal(t, uint(10), linked.ID)
+	require.NotNil(t, linked.PrecoVenda)
+	assert.Equal(t, 500000.0, *linked.PrecoVenda)
+	assert.Nil(t, linked.PrecoAluguel)
+	require.NotNil(t, linked.Capa)
+	assert.Equal(t, uint(2), linked.Capa.ID)
+}
@@ -0,0 +1,106 @@
+package sliders
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidLocale is returned when a locale is not a valid BCP 47 language tag
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// normalizeLocale returns the canonical form of a BCP 47 tag (pt-br becomes pt-BR), "" for the
+// default locale
+func normalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// normalizeAudience returns the audience compared case-insensitively, "" for everyone
+func normalizeAudience(audience string) string {
+	return strings.ToLower(strings.TrimSpace(audience))
+}
+
+// localePreferences returns the locales a request accepts, most preferred first: the locale query
+// parameter when given, otherwise the Accept-Language header. Malformed values are ignored.
+func localePreferences(locale, acceptLanguage string) []language.Tag {
+	if locale = strings.TrimSpace(locale); locale != "" {
+		if tag, err := language.Parse(locale); err == nil {
+			return []language.Tag{tag}
+		}
+		return nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// resolveVariant picks the variant of a location that best serves prefs and audience. Variants
+// for another audience are skipped. Each preferred locale is tried in turn, first exactly (pt-BR),
+// then by language (pt, then any other pt variant); the default locale is the last resort.
+func resolveVariant(variants []Slider, prefs []language.Tag, audience string) *Slider {
+	audience = normalizeAudience(audience)
+	candidates := make([]*Slider, 0, len(variants))
+	for i := range variants {
+		if variants[i].Audience == "" || variants[i].Audience == audience {
+			candidates = append(candidates, &variants[i])
+		}
+	}
+
+	for _, pref := range prefs {
+		if match := matchLocale(candidates, pref); match != nil {
+			return match
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate.Locale == "" {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// matchLocale returns the candidate of the locale pref, or else of its language
+func matchLocale(candidates []*Slider, pref language.Tag) *Slider {
+	if pref == language.Und {
+		return nil
+	}
+	exact := pref.String()
+	base, _ := pref.Base()
+
+	var sameBase, sameLanguage *Slider
+	for _, candidate := range candidates {
+		if candidate.Locale == "" {
+			continue
+		}
+		if candidate.Locale == exact {
+			return candidate
+		}
+		tag, err := language.Parse(candidate.Locale)
+		if err != nil {
+			continue
+		}
+		if candidateBase, _ := tag.Base(); candidateBase != base {
+			continue
+		}
+		if tag.String() == base.String() {
+			sameBase = candidate
+		} else if sameLanguage == nil {
+			sameLanguage = candidate
+		}
+	}
+	if sameBase != nil {
+		return sameBase
+	}
+	return sameLanguage
+}
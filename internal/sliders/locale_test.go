@@ -0,0 +1,106 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	locale, err := normalizeLocale(" pt-br ")
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", locale)
+
+	locale, err = normalizeLocale("")
+	require.NoError(t, err)
+	assert.Equal(t, "", locale)
+
+	_, err = normalizeLocale("not a locale")
+	assert.ErrorIs(t, err, ErrInvalidLocale)
+}
+
+func TestResolveVariant(t *testing.T) {
+	variants := []Slider{
+		{ID: 1, Location: "home"},
+		{ID: 2, Location: "home", Locale: "en"},
+		{ID: 3, Location: "home", Locale: "pt-BR"},
+		{ID: 4, Location: "home", Locale: "es-MX", Audience: "investidores"},
+	}
+
+	tests := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		audience       string
+		wantID         uint
+	}{
+		{"no preference falls back to default", "", "", "", 1},
+		{"exact locale", "pt-BR", "", "", 3},
+		{"regional preference matches language", "en-GB", "", "", 2},
+		{"language preference matches region", "pt", "", "", 3},
+		{"accept-language in quality order", "", "fr;q=1, en-US;q=0.8, pt-BR;q=0.5", "", 2},
+		{"query parameter wins over header", "pt-BR", "en-US", "", 3},
+		{"unknown locale falls back to default", "de", "", "", 1},
+		{"other audience is skipped", "es-MX", "", "", 1},
+		{"matching audience", "es-MX", "", "Investidores", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant := resolveVariant(variants, localePreferences(tt.locale, tt.acceptLanguage), tt.audience)
+			require.NotNil(t, variant)
+			assert.Equal(t, tt.wantID, variant.ID)
+		})
+	}
+
+	t.Run("no default variant", func(t *testing.T) {
+		assert.Nil(t, resolveVariant(variants[1:], localePreferences("de", ""), ""))
+	})
+}
+
+func TestService_GetSliderByLocation_Locale(t *testing.T) {
+	ctx := context.Background()
+	ptBR := &Slider{ID: 1, Location: "home", Enabled: true}
+	en := &Slider{ID: 2, Location: "home", Locale: "en", Enabled: true}
+	enDraft := &Slider{ID: 3, Location: "home", Locale: "en-US", Enabled: false}
+
+	t.Run("accept-language selects the variant", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, ptBR, en, enDraft)
+
+		resp, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home", AcceptLanguage: "en-US,en;q=0.9"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), resp.ID)
+		assert.Equal(t, "en", resp.Locale)
+	})
+
+	t.Run("preview token selects its variant", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, ptBR, en, enDraft)
+
+		token, err := svc.GeneratePreviewToken(ctx, enDraft.ID)
+		require.NoError(t, err)
+
+		resp, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home", PreviewToken: token.Token})
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), resp.ID)
+	})
+
+	t.Run("same location and locale conflicts", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, ptBR, en)
+
+		_, err := svc.CreateSlider(ctx, &CreateSliderRequest{Name: "Home EN", Type: sliderTypePtr(SliderType_Carousel), Location: "home", Locale: "EN"})
+		assert.ErrorIs(t, err, ErrLocationExists)
+	})
+
+	t.Run("invalid locale is rejected", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, ptBR)
+
+		_, err := svc.UpdateSlider(ctx, ptBR.ID, &UpdateSliderRequest{Locale: strPtr("??")})
+		assert.ErrorIs(t, err, ErrInvalidLocale)
+	})
+}
+
+func strPtr(v string) *string {
+	return &v
+}
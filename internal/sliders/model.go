@@ -1,51 +1,62 @@
 package sliders
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+)
 
 type Slider struct {
-	ID        uint         `gorm:"primaryKey" json:"id"`
-	Name      string       `gorm:"not null" json:"name"`
-	Type      SliderType   `gorm:"not null" json:"type"`
-	Location  string       `gorm:"not null" json:"location"`
-	Items     []SliderItem `gorm:"foreignKey:SliderID" json:"items"`
-	CreatedAt time.Time    `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Name          string         `gorm:"not null" json:"name"`
+	Type          SliderType     `gorm:"not null" json:"type"`
+	Location      string         `gorm:"not null" json:"location"`
+	Locale        string         `gorm:"not null" json:"locale"`
+	Audience      string         `gorm:"not null" json:"audience"`
+	Enabled       bool           `gorm:"not null" json:"enabled"`
+	ActiveFrom    *time.Time     `json:"active_from"`
+	ActiveUntil   *time.Time     `json:"active_until"`
+	Items         []SliderItem   `gorm:"foreignKey:SliderID" json:"items"`
+	OrganizacaoID *uint          `gorm:"index" json:"organizacao_id,omitempty"` // set from the request tenant
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type SliderItem struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	SliderID  uint      `gorm:"not null" json:"slider_id"`
-	ImageURL  string    `gorm:"not null" json:"image_url"`
-	LinkURL   string    `gorm:"not null" json:"link_url"`
-	Content   string    `gorm:"not null" json:"content"`
-	Order     int       `gorm:"not null" json:"order"`
-	Tags      []string  `gorm:"type:jsonb" json:"tags"`
-	Titulo    string    `gorm:"not null" json:"titulo"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	SliderID uint   `gorm:"not null" json:"slider_id"`
+	ImageURL string `gorm:"not null" json:"image_url"`
+	// Variants are the responsive renditions of an uploaded image; external URLs have none
+	Variants    imoveis.AnexoVariants `gorm:"type:jsonb" json:"variants,omitempty"`
+	LinkURL     string                `gorm:"not null" json:"link_url"`
+	Content     string                `gorm:"not null" json:"content"`
+	Order       int                   `gorm:"not null" json:"order"`
+	Tags        []string              `gorm:"type:jsonb" json:"tags"`
+	Titulo      string                `gorm:"not null" json:"titulo"`
+	ImovelID    *uint                 `gorm:"index" json:"imovel_id"`
+	Enabled     bool                  `gorm:"not null" json:"enabled"`
+	ActiveFrom  *time.Time            `json:"active_from"`
+	ActiveUntil *time.Time            `json:"active_until"`
+	CreatedAt   time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt        `gorm:"index" json:"-"`
 }
 
-type SliderType int
-
-const (
-	SliderType_Slideshow SliderType = iota
-	SliderType_Carousel
-	SliderType_Static
-)
+func (Slider) TableName() string {
+	return "sliders"
+}
 
-func (st SliderType) String() string {
-	switch st {
-	case SliderType_Slideshow:
-		return "slideshow"
-	case SliderType_Carousel:
-		return "carousel"
-	case SliderType_Static:
-		return "static"
-	default:
-		return "unknown"
-	}
+// SliderItemStat counts the impressions and clicks of a slider item on a day (UTC)
+type SliderItemStat struct {
+	SliderItemID uint      `gorm:"primaryKey" json:"slider_item_id"`
+	Day          time.Time `gorm:"primaryKey;type:date" json:"day"`
+	Impressions  int64     `gorm:"not null;default:0" json:"impressions"`
+	Clicks       int64     `gorm:"not null;default:0" json:"clicks"`
 }
 
-func (Slider) TableName() string {
-	return "sliders"
+func (SliderItemStat) TableName() string {
+	return "slider_item_stats"
 }
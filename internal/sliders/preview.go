@@ -0,0 +1,101 @@
+package sliders
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// previewTokenAudience scopes preview tokens so they cannot be confused with other JWTs
+	previewTokenAudience = "slider-preview"
+	// defaultPreviewTokenTTL is used when no TTL is configured
+	defaultPreviewTokenTTL = time.Hour
+)
+
+// derivePreviewKey derives a dedicated signing key from the application secret, so a
+// preview token can never be accepted as an access token (and vice versa).
+func derivePreviewKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(previewTokenAudience))
+	return mac.Sum(nil)
+}
+
+// GeneratePreviewToken issues a short-lived signed token granting read access to a slider
+// through the public endpoints regardless of its enabled state
+func (s *service) GeneratePreviewToken(ctx context.Context, id uint) (*PreviewTokenResponse, error) {
+	slider, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderNotFound
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.previewTTL)
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(slider.ID), 10),
+		Audience:  jwt.ClaimStrings{previewTokenAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.previewKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign preview token: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("location", slider.Location)
+	query.Set("preview_token", token)
+
+	return &PreviewTokenResponse{
+		Token:      token,
+		ExpiresAt:  expiresAt.UTC(),
+		PreviewURL: "/api/v1/sliders/location?" + query.Encode(),
+	}, nil
+}
+
+// validatePreviewToken checks that the token is valid, unexpired and issued for the given slider
+func (s *service) validatePreviewToken(token string, sliderID uint) error {
+	parsed, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return s.previewKey, nil
+	}, jwt.WithAudience(previewTokenAudience), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return ErrInvalidPreviewToken
+	}
+
+	claims, ok := parsed.Claims.(*jwt.RegisteredClaims)
+	if !ok {
+		return ErrInvalidPreviewToken
+	}
+
+	subject, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || uint(subject) != sliderID {
+		return ErrInvalidPreviewToken
+	}
+
+	return nil
+}
+
+// checkVisibility hides disabled sliders, and those outside their active window, from public reads
+// unless a valid preview token is provided
+func (s *service) checkVisibility(slider *Slider, previewToken string) error {
+	if previewToken != "" {
+		return s.validatePreviewToken(previewToken, slider.ID)
+	}
+	if !slider.activeAt(time.Now()) {
+		return ErrSliderNotFound
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package sliders
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRepository struct {
+	Repository
+	sliders map[uint]*Slider
+	deleted map[uint]*Slider
+	stats   []SliderItemStat
+}
+
+func (r *stubRepository) FindByID(ctx context.Context, id uint) (*Slider, error) {
+	return r.sliders[id], nil
+}
+
+func (r *stubRepository) FindByLocation(ctx context.Context, location, locale string) (*Slider, error) {
+	for _, slider := range r.sliders {
+		if slider.Location == location && slider.Locale == locale {
+			return slider, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) ListByLocation(ctx context.Context, location string) ([]Slider, error) {
+	var variants []Slider
+	for _, slider := range r.sliders {
+		if slider.Location == location {
+			variants = append(variants, *slider)
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants, nil
+}
+
+func newPreviewService(ttl time.Duration, sliders ...*Slider) *service {
+	repo := &stubRepository{sliders: make(map[uint]*Slider)}
+	for _, slider := range sliders {
+		repo.sliders[slider.ID] = slider
+	}
+	return &service{
+		repo:       repo,
+		previewKey: derivePreviewKey("hKLmNpQrStUvWxYzABCDEFGHIJKLMNOP"),
+		previewTTL: ttl,
+	}
+}
+
+func TestService_PreviewToken(t *testing.T) {
+	draft := &Slider{ID: 1, Location: "home", Enabled: false}
+	other := &Slider{ID: 2, Location: "footer", Enabled: false}
+	ctx := context.Background()
+
+	t.Run("disabled slider is hidden without token", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, draft)
+
+		_, err := svc.GetSlider(ctx, draft.ID, "")
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+
+	t.Run("valid token renders disabled slider", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, draft)
+
+		token, err := svc.GeneratePreviewToken(ctx, draft.ID)
+		require.NoError(t, err)
+		assert.Contains(t, token.PreviewURL, "preview_token=")
+
+		resp, err := svc.GetSlider(ctx, draft.ID, token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, draft.ID, resp.ID)
+	})
+
+	t.Run("token for another slider is rejected", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, draft, other)
+
+		token, err := svc.GeneratePreviewToken(ctx, other.ID)
+		require.NoError(t, err)
+
+		_, err = svc.GetSlider(ctx, draft.ID, token.Token)
+		assert.ErrorIs(t, err, ErrInvalidPreviewToken)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		svc := newPreviewService(-time.Minute, draft)
+
+		token, err := svc.GeneratePreviewToken(ctx, draft.ID)
+		require.NoError(t, err)
+
+		_, err = svc.GetSlider(ctx, draft.ID, token.Token)
+		assert.ErrorIs(t, err, ErrInvalidPreviewToken)
+	})
+
+	t.Run("malformed token is rejected", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, draft)
+
+		_, err := svc.GetSlider(ctx, draft.ID, "not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidPreviewToken)
+	})
+
+	t.Run("generating token for unknown slider fails", func(t *testing.T) {
+		svc := newPreviewService(time.Hour)
+
+		_, err := svc.GeneratePreviewToken(ctx, 99)
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+}
@@ -0,0 +1,121 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *stubRepository) UpdateItem(ctx context.Context, item *SliderItem) error {
+	items := r.sliders[item.SliderID].Items
+	for i := range items {
+		if items[i].ID == item.ID {
+			items[i] = *item
+		}
+	}
+	return nil
+}
+
+func (r *stubRepository) DeleteItem(ctx context.Context, id uint) error {
+	for _, slider := range r.sliders {
+		kept := make([]SliderItem, 0, len(slider.Items))
+		for _, item := range slider.Items {
+			if item.ID != id {
+				kept = append(kept, item)
+			}
+		}
+		slider.Items = kept
+	}
+	return nil
+}
+
+func (r *stubRepository) GetSliderItems(ctx context.Context, sliderID uint) ([]SliderItem, error) {
+	return r.sliders[sliderID].Items, nil
+}
+
+func (r *stubRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func replaceItem(id uint, imageURL string, order int) ReplaceSliderItemRequest {
+	req := ReplaceSliderItemRequest{CreateSliderItemRequest: CreateSliderItemRequest{ImageURL: imageURL, Order: order}}
+	if id != 0 {
+		req.ID = &id
+	}
+	return req
+}
+
+func newCarousel() *Slider {
+	return &Slider{
+		ID:       1,
+		Type:     SliderType_Carousel,
+		Location: "home",
+		Enabled:  true,
+		Items: []SliderItem{
+			{ID: 1, SliderID: 1, ImageURL: "https://cdn/a.jpg", Order: 0, Enabled: true},
+			{ID: 2, SliderID: 1, ImageURL: "https://cdn/b.jpg", Order: 1, Enabled: true},
+			{ID: 3, SliderID: 1, ImageURL: "https://cdn/c.jpg", Order: 2, Enabled: true},
+		},
+	}
+}
+
+func TestService_ReplaceSliderItems(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updates, creates and deletes", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newCarousel())
+
+		items, err := svc.ReplaceSliderItems(ctx, 1, &ReplaceSliderItemsRequest{Items: []ReplaceSliderItemRequest{
+			replaceItem(3, "https://cdn/c.jpg", 0),
+			replaceItem(1, "https://cdn/a2.jpg", 1),
+			replaceItem(0, "https://cdn/d.jpg", 2),
+		}})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		byID := map[uint]SliderItemResponse{}
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+		assert.NotContains(t, byID, uint(2))
+		assert.Equal(t, 0, byID[3].Order)
+		assert.Equal(t, "https://cdn/a2.jpg", byID[1].ImageURL)
+		assert.Equal(t, 1, byID[1].Order)
+	})
+
+	t.Run("empty set clears the slider", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newCarousel())
+
+		items, err := svc.ReplaceSliderItems(ctx, 1, &ReplaceSliderItemsRequest{Items: []ReplaceSliderItemRequest{}})
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("item of another slider is rejected", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newCarousel())
+
+		_, err := svc.ReplaceSliderItems(ctx, 1, &ReplaceSliderItemsRequest{Items: []ReplaceSliderItemRequest{
+			replaceItem(9, "https://cdn/x.jpg", 0),
+		}})
+		assert.ErrorIs(t, err, ErrItemNotInSlider)
+	})
+
+	t.Run("item listed twice is rejected", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{}, newCarousel())
+
+		_, err := svc.ReplaceSliderItems(ctx, 1, &ReplaceSliderItemsRequest{Items: []ReplaceSliderItemRequest{
+			replaceItem(1, "https://cdn/a.jpg", 0),
+			replaceItem(1, "https://cdn/a.jpg", 1),
+		}})
+		assert.ErrorIs(t, err, ErrDuplicateItem)
+	})
+
+	t.Run("unknown slider", func(t *testing.T) {
+		svc := newLinkedService(stubImovelLookup{})
+
+		_, err := svc.ReplaceSliderItems(ctx, 1, &ReplaceSliderItemsRequest{})
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+}
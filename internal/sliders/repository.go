@@ -3,8 +3,10 @@ package sliders
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type txKey struct{}
@@ -13,15 +15,23 @@ type txKey struct{}
 type Repository interface {
 	Create(ctx context.Context, slider *Slider) error
 	FindByID(ctx context.Context, id uint) (*Slider, error)
-	FindByLocation(ctx context.Context, location string) (*Slider, error)
+	FindByLocation(ctx context.Context, location, locale string) (*Slider, error)
+	ListByLocation(ctx context.Context, location string) ([]Slider, error)
 	Update(ctx context.Context, slider *Slider) error
 	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, page, perPage int) ([]Slider, int64, error)
+	List(ctx context.Context, page, perPage int, activeAt *time.Time) ([]Slider, int64, error)
+	FindDeletedByID(ctx context.Context, id uint) (*Slider, error)
+	ListDeleted(ctx context.Context, page, perPage int) ([]Slider, int64, error)
+	Restore(ctx context.Context, slider *Slider) error
+	Purge(ctx context.Context, before time.Time) (*PurgeResult, error)
 	CreateItem(ctx context.Context, item *SliderItem) error
 	FindItemByID(ctx context.Context, id uint) (*SliderItem, error)
 	UpdateItem(ctx context.Context, item *SliderItem) error
 	DeleteItem(ctx context.Context, id uint) error
 	GetSliderItems(ctx context.Context, sliderID uint) ([]SliderItem, error)
+	ExistingItemIDs(ctx context.Context, ids []uint) ([]uint, error)
+	AddItemStats(ctx context.Context, stats []SliderItemStat) error
+	ListItemStats(ctx context.Context, sliderID uint, from, to time.Time) ([]SliderItemStat, error)
 	Transaction(ctx context.Context, fn func(context.Context) error) error
 }
 
@@ -66,12 +76,12 @@ func (r *repository) FindByID(ctx context.Context, id uint) (*Slider, error) {
 	return &slider, nil
 }
 
-// FindByLocation finds a slider by location
-func (r *repository) FindByLocation(ctx context.Context, location string) (*Slider, error) {
+// FindByLocation finds the variant of a location for a locale ("" for the default one)
+func (r *repository) FindByLocation(ctx context.Context, location, locale string) (*Slider, error) {
 	var slider Slider
 	result := r.getDB(ctx).WithContext(ctx).Preload("Items", func(db *gorm.DB) *gorm.DB {
 		return db.Order("\"order\" ASC")
-	}).Where("location = ?", location).First(&slider)
+	}).Where("location = ? AND locale = ?", location, locale).First(&slider)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -81,33 +91,163 @@ func (r *repository) FindByLocation(ctx context.Context, location string) (*Slid
 	return &slider, nil
 }
 
+// ListByLocation retrieves every locale and audience variant of a location
+func (r *repository) ListByLocation(ctx context.Context, location string) ([]Slider, error) {
+	var sliders []Slider
+	result := r.getDB(ctx).WithContext(ctx).Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("\"order\" ASC")
+	}).Where("location = ?", location).Order("id ASC").Find(&sliders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sliders, nil
+}
+
 // Update updates a slider in the database
 func (r *repository) Update(ctx context.Context, slider *Slider) error {
-	result := r.getDB(ctx).WithContext(ctx).Model(slider).Select("name", "type", "location", "updated_at").Save(slider)
+	result := r.getDB(ctx).WithContext(ctx).Model(slider).Select("name", "type", "location", "locale", "audience", "enabled", "active_from", "active_until", "updated_at").Save(slider)
 	if result.Error != nil {
 		return result.Error
 	}
 	return nil
 }
 
-// Delete soft deletes a slider from the database
+// Delete soft deletes a slider and its items. They share the deletion time, which tells the items
+// deleted with the slider from those deleted before it.
 func (r *repository) Delete(ctx context.Context, id uint) error {
-	result := r.getDB(ctx).WithContext(ctx).Delete(&Slider{}, id)
+	return r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletedAt := time.Now()
+		result := tx.Model(&Slider{}).Where("id = ?", id).UpdateColumn("deleted_at", deletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&SliderItem{}).Where("slider_id = ?", id).UpdateColumn("deleted_at", deletedAt).Error
+	})
+}
+
+// FindDeletedByID finds a soft-deleted slider by ID with the items deleted along with it
+func (r *repository) FindDeletedByID(ctx context.Context, id uint) (*Slider, error) {
+	var slider Slider
+	result := r.getDB(ctx).WithContext(ctx).Unscoped().
+		Preload("Items", unscopedItems).
+		Where("deleted_at IS NOT NULL").
+		First(&slider, id)
 	if result.Error != nil {
-		return result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	slider.Items = deletedWith(&slider)
+	return &slider, nil
+}
+
+// ListDeleted retrieves soft-deleted sliders with the items deleted along with them, most recently
+// deleted first
+func (r *repository) ListDeleted(ctx context.Context, page, perPage int) ([]Slider, int64, error) {
+	var sliders []Slider
+	var total int64
+
+	query := r.getDB(ctx).WithContext(ctx).Unscoped().Model(&Slider{}).Where("deleted_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	return nil
+
+	offset := (page - 1) * perPage
+	if err := query.Preload("Items", unscopedItems).
+		Order("deleted_at DESC").
+		Offset(offset).
+		Limit(perPage).
+		Find(&sliders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for i := range sliders {
+		sliders[i].Items = deletedWith(&sliders[i])
+	}
+	return sliders, total, nil
+}
+
+// Restore undeletes a slider and the items deleted along with it; items deleted before the slider
+// stay deleted
+func (r *repository) Restore(ctx context.Context, slider *Slider) error {
+	return r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&SliderItem{}).
+			Where("slider_id = ? AND deleted_at = ?", slider.ID, slider.DeletedAt.Time).
+			UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Model(&Slider{}).
+			Where("id = ? AND deleted_at IS NOT NULL", slider.ID).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Purge permanently deletes the sliders deleted before the given time with all their items, and
+// the items deleted before it from other sliders. Their stats go with them (ON DELETE CASCADE).
+func (r *repository) Purge(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	purged := &PurgeResult{}
+	err := r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&Slider{}).Select("id").Where("deleted_at < ?", before)
+
+		items := tx.Unscoped().Where("deleted_at < ? OR slider_id IN (?)", before, expired).Delete(&SliderItem{})
+		if items.Error != nil {
+			return items.Error
+		}
+		purged.Items = items.RowsAffected
+
+		sliders := tx.Unscoped().Where("deleted_at < ?", before).Delete(&Slider{})
+		if sliders.Error != nil {
+			return sliders.Error
+		}
+		purged.Sliders = sliders.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// unscopedItems preloads deleted items too, in order
+func unscopedItems(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Order("\"order\" ASC")
 }
 
-// List retrieves paginated list of sliders
-func (r *repository) List(ctx context.Context, page, perPage int) ([]Slider, int64, error) {
+// deletedWith returns the items of a deleted slider that were deleted along with it
+func deletedWith(slider *Slider) []SliderItem {
+	items := make([]SliderItem, 0, len(slider.Items))
+	for _, item := range slider.Items {
+		if item.DeletedAt.Valid && item.DeletedAt.Time.Equal(slider.DeletedAt.Time) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// List retrieves paginated list of sliders; with activeAt, only the sliders enabled and
+// scheduled at that time
+func (r *repository) List(ctx context.Context, page, perPage int, activeAt *time.Time) ([]Slider, int64, error) {
 	var sliders []Slider
 	var total int64
 
 	query := r.getDB(ctx).WithContext(ctx).Model(&Slider{})
+	if activeAt != nil {
+		query = query.Where("enabled = ?", true).
+			Where("active_from IS NULL OR active_from <= ?", *activeAt).
+			Where("active_until IS NULL OR active_until > ?", *activeAt)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -148,7 +288,7 @@ func (r *repository) FindItemByID(ctx context.Context, id uint) (*SliderItem, er
 
 // UpdateItem updates a slider item
 func (r *repository) UpdateItem(ctx context.Context, item *SliderItem) error {
-	result := r.getDB(ctx).WithContext(ctx).Model(item).Select("image_url", "link_url", "content", "order", "tags", "titulo", "updated_at").Save(item)
+	result := r.getDB(ctx).WithContext(ctx).Model(item).Select("image_url", "variants", "link_url", "content", "order", "tags", "titulo", "imovel_id", "enabled", "active_from", "active_until", "updated_at").Save(item)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -177,6 +317,44 @@ func (r *repository) GetSliderItems(ctx context.Context, sliderID uint) ([]Slide
 	return items, nil
 }
 
+// ExistingItemIDs returns which of ids are slider items that exist
+func (r *repository) ExistingItemIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var existing []uint
+	result := r.getDB(ctx).WithContext(ctx).Model(&SliderItem{}).Where("id IN ?", ids).Pluck("id", &existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return existing, nil
+}
+
+// AddItemStats adds the impressions and clicks of each stat to the counters of its item and day
+func (r *repository) AddItemStats(ctx context.Context, stats []SliderItemStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.getDB(ctx).WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "slider_item_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"impressions": gorm.Expr("slider_item_stats.impressions + excluded.impressions"),
+			"clicks":      gorm.Expr("slider_item_stats.clicks + excluded.clicks"),
+		}),
+	}).Create(&stats).Error
+}
+
+// ListItemStats retrieves the daily counters of the items of a slider from from to to, both included
+func (r *repository) ListItemStats(ctx context.Context, sliderID uint, from, to time.Time) ([]SliderItemStat, error) {
+	var stats []SliderItemStat
+	result := r.getDB(ctx).WithContext(ctx).
+		Joins("JOIN slider_items ON slider_items.id = slider_item_stats.slider_item_id").
+		Where("slider_items.slider_id = ? AND slider_item_stats.day BETWEEN ? AND ?", sliderID, from, to).
+		Order("slider_item_stats.day ASC").
+		Find(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stats, nil
+}
+
 // Transaction executes a function within a database transaction
 func (r *repository) Transaction(ctx context.Context, fn func(context.Context) error) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -0,0 +1,124 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+
+	_, err = sqlDB.Exec(`
+		CREATE TABLE sliders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL DEFAULT '',
+			type INTEGER NOT NULL DEFAULT 0,
+			location TEXT NOT NULL DEFAULT '',
+			locale TEXT NOT NULL DEFAULT '',
+			audience TEXT NOT NULL DEFAULT '',
+			enabled BOOLEAN NOT NULL DEFAULT true,
+			active_from DATETIME,
+			active_until DATETIME,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME
+		);
+		CREATE TABLE slider_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			slider_id INTEGER NOT NULL,
+			"order" INTEGER NOT NULL DEFAULT 0,
+			deleted_at DATETIME
+		);
+		CREATE TABLE slider_item_stats (
+			slider_item_id INTEGER NOT NULL REFERENCES slider_items(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			impressions INTEGER NOT NULL DEFAULT 0,
+			clicks INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (slider_item_id, day)
+		);
+
+		INSERT INTO sliders (id, location) VALUES (1, 'home'), (2, 'busca');
+		INSERT INTO slider_items (id, slider_id) VALUES (1, 1), (2, 1), (3, 2);
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_ItemStats(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupTestDB(t))
+	today := statsDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	require.NoError(t, repo.AddItemStats(ctx, []SliderItemStat{
+		{SliderItemID: 1, Day: yesterday, Impressions: 10, Clicks: 1},
+		{SliderItemID: 1, Day: today, Impressions: 4},
+		{SliderItemID: 3, Day: today, Impressions: 7},
+	}))
+	// Counters of the same item and day add up
+	require.NoError(t, repo.AddItemStats(ctx, []SliderItemStat{
+		{SliderItemID: 1, Day: today, Impressions: 2, Clicks: 1},
+		{SliderItemID: 2, Day: today, Clicks: 1},
+	}))
+
+	existing, err := repo.ExistingItemIDs(ctx, []uint{1, 3, 9})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint{1, 3}, existing)
+
+	stats, err := repo.ListItemStats(ctx, 1, yesterday, today)
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	counts := map[uint]map[string]SliderItemStat{}
+	for _, stat := range stats {
+		if counts[stat.SliderItemID] == nil {
+			counts[stat.SliderItemID] = map[string]SliderItemStat{}
+		}
+		counts[stat.SliderItemID][stat.Day.UTC().Format(statsDayLayout)] = stat
+	}
+	assert.Equal(t, int64(10), counts[1][yesterday.Format(statsDayLayout)].Impressions)
+	assert.Equal(t, int64(6), counts[1][today.Format(statsDayLayout)].Impressions)
+	assert.Equal(t, int64(1), counts[1][today.Format(statsDayLayout)].Clicks)
+	assert.Equal(t, int64(1), counts[2][today.Format(statsDayLayout)].Clicks)
+	assert.NotContains(t, counts, uint(3))
+
+	stats, err = repo.ListItemStats(ctx, 1, today, today)
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+}
+
+func TestRepository_List_ActiveAt(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	now := time.Now().UTC()
+
+	require.NoError(t, db.Exec(`INSERT INTO sliders (id, location, enabled) VALUES (3, 'rascunho', false)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO sliders (id, location, active_from) VALUES (4, 'natal', ?)`, now.Add(time.Hour)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO sliders (id, location, active_until) VALUES (5, 'verao', ?)`, now.Add(-time.Hour)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO sliders (id, location, active_from, active_until) VALUES (6, 'campanha', ?, ?)`, now.Add(-time.Hour), now.Add(time.Hour)).Error)
+
+	sliders, total, err := repo.List(ctx, 1, 10, &now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	ids := make([]uint, len(sliders))
+	for i, slider := range sliders {
+		ids[i] = slider.ID
+	}
+	assert.ElementsMatch(t, []uint{1, 2, 6}, ids)
+
+	_, total, err = repo.List(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+}
@@ -0,0 +1,43 @@
+package sliders
+
+import "time"
+
+// checkActiveWindow rejects an active window that ends before it starts
+func checkActiveWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return ErrInvalidActiveWindow
+	}
+	return nil
+}
+
+// inActiveWindow reports whether t falls within [from, until); a nil bound leaves that side open
+func inActiveWindow(from, until *time.Time, t time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if until != nil && !t.Before(*until) {
+		return false
+	}
+	return true
+}
+
+// activeAt reports whether the slider is enabled and scheduled to be shown at t
+func (s *Slider) activeAt(t time.Time) bool {
+	return s.Enabled && inActiveWindow(s.ActiveFrom, s.ActiveUntil, t)
+}
+
+// activeAt reports whether the item is enabled and scheduled to be shown at t
+func (i *SliderItem) activeAt(t time.Time) bool {
+	return i.Enabled && inActiveWindow(i.ActiveFrom, i.ActiveUntil, t)
+}
+
+// activeItems returns the items of the slider shown at t, keeping their order
+func (s *Slider) activeItems(t time.Time) []SliderItem {
+	items := make([]SliderItem, 0, len(s.Items))
+	for _, item := range s.Items {
+		if item.activeAt(t) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
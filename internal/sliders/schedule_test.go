@@ -0,0 +1,137 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckActiveWindow(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	assert.NoError(t, checkActiveWindow(nil, nil))
+	assert.NoError(t, checkActiveWindow(&now, nil))
+	assert.NoError(t, checkActiveWindow(nil, &now))
+	assert.NoError(t, checkActiveWindow(&now, &later))
+	assert.ErrorIs(t, checkActiveWindow(&later, &now), ErrInvalidActiveWindow)
+	assert.ErrorIs(t, checkActiveWindow(&now, &now), ErrInvalidActiveWindow)
+}
+
+func TestService_GetSliderByLocation_Schedule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	campaign := &Slider{
+		ID:       1,
+		Location: "home",
+		Enabled:  true,
+		Items: []SliderItem{
+			{ID: 1, Order: 0, Enabled: true},
+			{ID: 2, Order: 1, Enabled: false},
+			{ID: 3, Order: 2, Enabled: true, ActiveFrom: &future},
+			{ID: 4, Order: 3, Enabled: true, ActiveUntil: &past},
+			{ID: 5, Order: 4, Enabled: true, ActiveFrom: &past, ActiveUntil: &future},
+		},
+	}
+
+	t.Run("only active items are returned", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, campaign)
+
+		resp, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home"})
+		require.NoError(t, err)
+		ids := make([]uint, len(resp.Items))
+		for i, item := range resp.Items {
+			ids[i] = item.ID
+		}
+		assert.Equal(t, []uint{1, 5}, ids)
+	})
+
+	t.Run("preview token shows every item", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, campaign)
+
+		token, err := svc.GeneratePreviewToken(ctx, campaign.ID)
+		require.NoError(t, err)
+
+		resp, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "home", PreviewToken: token.Token})
+		require.NoError(t, err)
+		assert.Len(t, resp.Items, 5)
+	})
+
+	t.Run("slider scheduled ahead is hidden", func(t *testing.T) {
+		scheduled := &Slider{ID: 2, Location: "black-friday", Enabled: true, ActiveFrom: &future}
+		svc := newPreviewService(time.Hour, scheduled)
+
+		_, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "black-friday"})
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+
+	t.Run("expired slider is hidden", func(t *testing.T) {
+		expired := &Slider{ID: 3, Location: "summer", Enabled: true, ActiveUntil: &past}
+		svc := newPreviewService(time.Hour, expired)
+
+		_, err := svc.GetSliderByLocation(ctx, &SliderLocationQuery{Location: "summer"})
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+}
+
+func TestService_PublicReads_Schedule(t *testing.T) {
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour)
+
+	campaign := &Slider{
+		ID:       1,
+		Location: "home",
+		Enabled:  true,
+		Items: []SliderItem{
+			{ID: 1, SliderID: 1, Order: 0, Enabled: true},
+			{ID: 2, SliderID: 1, Order: 1, Enabled: false},
+			{ID: 3, SliderID: 1, Order: 2, Enabled: true, ActiveFrom: &future},
+		},
+	}
+	draft := &Slider{ID: 2, Location: "footer", Enabled: false, Items: []SliderItem{{ID: 4, SliderID: 2, Enabled: true}}}
+
+	t.Run("slider and its items show only active items", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, campaign, draft)
+
+		resp, err := svc.GetSlider(ctx, campaign.ID, "")
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, uint(1), resp.Items[0].ID)
+
+		items, err := svc.GetSliderItems(ctx, campaign.ID, "")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, uint(1), items[0].ID)
+	})
+
+	t.Run("inactive items are hidden without token", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, campaign, draft)
+
+		item, err := svc.GetSliderItem(ctx, 1, "")
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), item.ID)
+
+		for _, id := range []uint{2, 3, 4} {
+			_, err = svc.GetSliderItem(ctx, id, "")
+			assert.ErrorIs(t, err, ErrSliderItemNotFound, "item %d", id)
+		}
+	})
+
+	t.Run("preview token shows the items of its slider", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, campaign, draft)
+		token, err := svc.GeneratePreviewToken(ctx, draft.ID)
+		require.NoError(t, err)
+
+		item, err := svc.GetSliderItem(ctx, 4, token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), item.ID)
+
+		_, err = svc.GetSliderItem(ctx, 2, token.Token)
+		assert.ErrorIs(t, err, ErrInvalidPreviewToken, "the token was issued for another slider")
+	})
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
 )
 
 var (
@@ -13,54 +18,137 @@ var (
 	ErrSliderNotFound = errors.New("slider not found")
 	// ErrSliderItemNotFound is returned when slider item is not found
 	ErrSliderItemNotFound = errors.New("slider item not found")
-	// ErrLocationExists is returned when location already exists
+	// ErrLocationExists is returned when the location already has a slider for the locale
 	ErrLocationExists = errors.New("location already exists")
 	// ErrInvalidType is returned when slider type is invalid
 	ErrInvalidType = errors.New("invalid slider type")
+	// ErrInvalidPreviewToken is returned when a preview token is malformed, expired or issued for another slider
+	ErrInvalidPreviewToken = errors.New("invalid or expired preview token")
+	// ErrInvalidActiveWindow is returned when active_until is not after active_from
+	ErrInvalidActiveWindow = errors.New("active_until must be after active_from")
+	// ErrImovelNotFound is returned when a slider item links to a property that does not exist
+	ErrImovelNotFound = errors.New("linked property not found")
+	// ErrImovelNotPublished is returned when a slider item links to a property that is not published
+	ErrImovelNotPublished = errors.New("linked property is not published")
+	// ErrItemNotInSlider is returned when a replaced item set names an item of another slider
+	ErrItemNotInSlider = errors.New("item does not belong to the slider")
+	// ErrDuplicateItem is returned when a replaced item set names the same item twice
+	ErrDuplicateItem = errors.New("item listed more than once")
 )
 
 // Service defines slider service interface
 type Service interface {
 	CreateSlider(ctx context.Context, req *CreateSliderRequest) (*SliderResponse, error)
-	GetSlider(ctx context.Context, id uint) (*SliderResponse, error)
-	GetSliderByLocation(ctx context.Context, location string) (*SliderResponse, error)
+	GetSlider(ctx context.Context, id uint, previewToken string) (*SliderResponse, error)
+	GetSliderByLocation(ctx context.Context, query *SliderLocationQuery) (*SliderResponse, error)
 	UpdateSlider(ctx context.Context, id uint, req *UpdateSliderRequest) (*SliderResponse, error)
+	DuplicateSlider(ctx context.Context, id uint, req *DuplicateSliderRequest) (*SliderResponse, error)
 	DeleteSlider(ctx context.Context, id uint) error
 	ListSliders(ctx context.Context, page, perPage int) ([]SliderResponse, int64, error)
+	ListAllSliders(ctx context.Context, page, perPage int) ([]SliderResponse, int64, error)
+	ListDeletedSliders(ctx context.Context, page, perPage int) ([]DeletedSliderResponse, int64, error)
+	RestoreSlider(ctx context.Context, id uint) (*SliderResponse, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (*PurgeResult, error)
 	AddSliderItem(ctx context.Context, sliderID uint, req *CreateSliderItemRequest) (*SliderItemResponse, error)
-	GetSliderItem(ctx context.Context, itemID uint) (*SliderItemResponse, error)
+	UploadSliderItem(ctx context.Context, sliderID uint, file *UploadedFile, req *UploadSliderItemRequest) (*SliderItemResponse, error)
+	MaxUploadBytes() int64
+	GetSliderItem(ctx context.Context, itemID uint, previewToken string) (*SliderItemResponse, error)
 	UpdateSliderItem(ctx context.Context, itemID uint, req *UpdateSliderItemRequest) (*SliderItemResponse, error)
 	DeleteSliderItem(ctx context.Context, itemID uint) error
-	GetSliderItems(ctx context.Context, sliderID uint) ([]SliderItemResponse, error)
+	ReplaceSliderItems(ctx context.Context, sliderID uint, req *ReplaceSliderItemsRequest) ([]SliderItemResponse, error)
+	GetSliderItems(ctx context.Context, sliderID uint, previewToken string) ([]SliderItemResponse, error)
+	GeneratePreviewToken(ctx context.Context, id uint) (*PreviewTokenResponse, error)
+	RecordClick(ctx context.Context, itemID uint) error
+	RecordImpressions(ctx context.Context, req *RecordImpressionsRequest) error
+	GetSliderStats(ctx context.Context, sliderID uint, query *SliderStatsQuery) (*SliderStatsResponse, error)
 }
 
 type service struct {
-	repo Repository
+	repo       Repository
+	imoveis    ImovelLookup
+	limits     config.SlidersConfig
+	inspector  ImageInspector
+	previewKey []byte
+	previewTTL time.Duration
+	storage    storage.Storage
+	maxUpload  int64
 }
 
-// NewService creates a new slider service
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// NewService creates a new slider service enforcing the configured per-type limits,
+// signing preview tokens with a key derived from the JWT secret and resolving the
+// properties items link to through imovelLookup. Uploaded item images are written to store.
+func NewService(repo Repository, cfg *config.Config, imovelLookup ImovelLookup, store storage.Storage) Service {
+	s := &service{
+		repo:       repo,
+		imoveis:    imovelLookup,
+		limits:     cfg.Sliders,
+		inspector:  NewHTTPImageInspector(5 * time.Second),
+		previewKey: derivePreviewKey(cfg.JWT.Secret),
+		previewTTL: cfg.Sliders.PreviewTokenTTL,
+		storage:    store,
+		maxUpload:  cfg.Storage.MaxUploadBytes,
+	}
+	if s.previewTTL <= 0 {
+		s.previewTTL = defaultPreviewTokenTTL
+	}
+	if s.maxUpload <= 0 {
+		s.maxUpload = defaultMaxUploadBytes
+	}
+	return s
 }
 
 // CreateSlider creates a new slider
 func (s *service) CreateSlider(ctx context.Context, req *CreateSliderRequest) (*SliderResponse, error) {
-	if req.Type < 0 || req.Type > 2 {
+	if req.Type == nil || !req.Type.Valid() {
 		return nil, ErrInvalidType
 	}
+	if err := checkActiveWindow(req.ActiveFrom, req.ActiveUntil); err != nil {
+		return nil, err
+	}
+	for _, itemReq := range req.Items {
+		if err := checkActiveWindow(itemReq.ActiveFrom, itemReq.ActiveUntil); err != nil {
+			return nil, err
+		}
+		if itemReq.ImovelID != nil {
+			if err := s.checkLinkedImovel(ctx, *itemReq.ImovelID); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	existingSlider, err := s.repo.FindByLocation(ctx, req.Location)
+	locale, err := normalizeLocale(req.Locale)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check existing location: %w", err)
+		return nil, err
 	}
-	if existingSlider != nil {
-		return nil, ErrLocationExists
+	if err := s.checkLocationFree(ctx, req.Location, locale); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]limitCandidate, len(req.Items))
+	for i, itemReq := range req.Items {
+		candidates[i] = limitCandidate{
+			field:      fmt.Sprintf("items[%d]", i),
+			imageURL:   itemReq.ImageURL,
+			content:    itemReq.Content,
+			checkImage: true,
+		}
+	}
+	if err := s.enforceLimits(ctx, *req.Type, len(req.Items), candidates); err != nil {
+		return nil, err
 	}
 
 	slider := &Slider{
-		Name:     req.Name,
-		Type:     SliderType(req.Type),
-		Location: req.Location,
+		Name:        req.Name,
+		Type:        *req.Type,
+		Location:    req.Location,
+		Locale:      locale,
+		Audience:    normalizeAudience(req.Audience),
+		Enabled:     true,
+		ActiveFrom:  req.ActiveFrom,
+		ActiveUntil: req.ActiveUntil,
+	}
+	if req.Enabled != nil {
+		slider.Enabled = *req.Enabled
 	}
 
 	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
@@ -69,15 +157,7 @@ func (s *service) CreateSlider(ctx context.Context, req *CreateSliderRequest) (*
 		}
 
 		for _, itemReq := range req.Items {
-			item := &SliderItem{
-				SliderID: slider.ID,
-				ImageURL: itemReq.ImageURL,
-				LinkURL:  itemReq.LinkURL,
-				Content:  itemReq.Content,
-				Order:    itemReq.Order,
-				Tags:     itemReq.Tags,
-				Titulo:   itemReq.Titulo,
-			}
+			item := newSliderItem(slider.ID, &itemReq)
 			if err := s.repo.CreateItem(txCtx, item); err != nil {
 				return fmt.Errorf("failed to create slider item: %w", err)
 			}
@@ -98,11 +178,12 @@ func (s *service) CreateSlider(ctx context.Context, req *CreateSliderRequest) (*
 		return nil, fmt.Errorf("failed to reload slider: slider not found after creation")
 	}
 
-	return s.sliderToResponse(slider), nil
+	return s.sliderWithImoveis(ctx, slider)
 }
 
-// GetSlider retrieves a slider by ID
-func (s *service) GetSlider(ctx context.Context, id uint) (*SliderResponse, error) {
+// GetSlider retrieves a slider by ID with its currently active items. Disabled or unscheduled
+// sliders, and every item, are only returned with a valid preview token.
+func (s *service) GetSlider(ctx context.Context, id uint, previewToken string) (*SliderResponse, error) {
 	slider, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find slider: %w", err)
@@ -110,19 +191,47 @@ func (s *service) GetSlider(ctx context.Context, id uint) (*SliderResponse, erro
 	if slider == nil {
 		return nil, ErrSliderNotFound
 	}
-	return s.sliderToResponse(slider), nil
+	if err := s.checkVisibility(slider, previewToken); err != nil {
+		return nil, err
+	}
+	if previewToken != "" {
+		return s.sliderWithImoveis(ctx, slider)
+	}
+	return s.publicSlider(ctx, slider, time.Now())
 }
 
-// GetSliderByLocation retrieves a slider by location
-func (s *service) GetSliderByLocation(ctx context.Context, location string) (*SliderResponse, error) {
-	slider, err := s.repo.FindByLocation(ctx, location)
+// GetSliderByLocation retrieves the variant of a location that best matches the locale and audience
+// of the query, with only its currently active items. Disabled or unscheduled variants are skipped;
+// a valid preview token returns the variant it was issued for with every item.
+func (s *service) GetSliderByLocation(ctx context.Context, query *SliderLocationQuery) (*SliderResponse, error) {
+	variants, err := s.repo.ListByLocation(ctx, query.Location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find slider: %w", err)
 	}
+	if len(variants) == 0 {
+		return nil, ErrSliderNotFound
+	}
+	if query.PreviewToken != "" {
+		for i := range variants {
+			if s.validatePreviewToken(query.PreviewToken, variants[i].ID) == nil {
+				return s.sliderWithImoveis(ctx, &variants[i])
+			}
+		}
+		return nil, ErrInvalidPreviewToken
+	}
+
+	now := time.Now()
+	visible := make([]Slider, 0, len(variants))
+	for _, variant := range variants {
+		if variant.activeAt(now) {
+			visible = append(visible, variant)
+		}
+	}
+	slider := resolveVariant(visible, localePreferences(query.Locale, query.AcceptLanguage), query.Audience)
 	if slider == nil {
 		return nil, ErrSliderNotFound
 	}
-	return s.sliderToResponse(slider), nil
+	return s.publicSlider(ctx, slider, now)
 }
 
 // UpdateSlider updates a slider
@@ -139,22 +248,57 @@ func (s *service) UpdateSlider(ctx context.Context, id uint, req *UpdateSliderRe
 		slider.Name = req.Name
 	}
 	if req.Type != nil {
-		if *req.Type < 0 || *req.Type > 2 {
+		if !req.Type.Valid() {
 			return nil, ErrInvalidType
 		}
-		slider.Type = SliderType(*req.Type)
-	}
-	if req.Location != "" {
-		if req.Location != slider.Location {
-			existingSlider, err := s.repo.FindByLocation(ctx, req.Location)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check existing location: %w", err)
+		newType := *req.Type
+		if newType != slider.Type {
+			candidates := make([]limitCandidate, len(slider.Items))
+			for i, item := range slider.Items {
+				candidates[i] = limitCandidate{
+					field:      fmt.Sprintf("items[%d]", i),
+					imageURL:   item.ImageURL,
+					content:    item.Content,
+					checkImage: true,
+				}
 			}
-			if existingSlider != nil {
-				return nil, ErrLocationExists
+			if err := s.enforceLimits(ctx, newType, len(slider.Items), candidates); err != nil {
+				return nil, err
 			}
 		}
-		slider.Location = req.Location
+		slider.Type = newType
+	}
+	location, locale := slider.Location, slider.Locale
+	if req.Location != "" {
+		location = req.Location
+	}
+	if req.Locale != nil {
+		if locale, err = normalizeLocale(*req.Locale); err != nil {
+			return nil, err
+		}
+	}
+	if location != slider.Location || locale != slider.Locale {
+		if err := s.checkLocationFree(ctx, location, locale); err != nil {
+			return nil, err
+		}
+		slider.Location, slider.Locale = location, locale
+	}
+	if req.Audience != nil {
+		slider.Audience = normalizeAudience(*req.Audience)
+	}
+	if req.Enabled != nil {
+		slider.Enabled = *req.Enabled
+	}
+	if req.ActiveFrom != nil || req.ActiveUntil != nil {
+		if req.ActiveFrom != nil {
+			slider.ActiveFrom = req.ActiveFrom
+		}
+		if req.ActiveUntil != nil {
+			slider.ActiveUntil = req.ActiveUntil
+		}
+		if err := checkActiveWindow(slider.ActiveFrom, slider.ActiveUntil); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.Update(ctx, slider); err != nil {
@@ -166,7 +310,89 @@ func (s *service) UpdateSlider(ctx context.Context, id uint, req *UpdateSliderRe
 		return nil, fmt.Errorf("failed to reload slider: %w", err)
 	}
 
-	return s.sliderToResponse(slider), nil
+	return s.sliderWithImoveis(ctx, slider)
+}
+
+// DuplicateSlider copies a slider and all its items to a new location in one transaction. The
+// items keep their content, order, links and schedules; their stats are not copied.
+func (s *service) DuplicateSlider(ctx context.Context, id uint, req *DuplicateSliderRequest) (*SliderResponse, error) {
+	source, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if source == nil {
+		return nil, ErrSliderNotFound
+	}
+
+	locale := source.Locale
+	if req.Locale != nil {
+		if locale, err = normalizeLocale(*req.Locale); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkLocationFree(ctx, req.Location, locale); err != nil {
+		return nil, err
+	}
+
+	slider := &Slider{
+		Name:        source.Name,
+		Type:        source.Type,
+		Location:    req.Location,
+		Locale:      locale,
+		Audience:    source.Audience,
+		ActiveFrom:  source.ActiveFrom,
+		ActiveUntil: source.ActiveUntil,
+	}
+	if req.Name != "" {
+		slider.Name = req.Name
+	}
+	if req.Enabled != nil {
+		slider.Enabled = *req.Enabled
+	}
+
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.Create(txCtx, slider); err != nil {
+			return fmt.Errorf("failed to create slider: %w", err)
+		}
+
+		for _, sourceItem := range source.Items {
+			item := sourceItem
+			item.ID = 0
+			item.SliderID = slider.ID
+			item.CreatedAt = time.Time{}
+			item.UpdatedAt = time.Time{}
+			if err := s.repo.CreateItem(txCtx, &item); err != nil {
+				return fmt.Errorf("failed to create slider item: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slider, err = s.repo.FindByID(ctx, slider.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload slider: %w", err)
+	}
+	if slider == nil {
+		return nil, fmt.Errorf("failed to reload slider: slider not found after creation")
+	}
+
+	return s.sliderWithImoveis(ctx, slider)
+}
+
+// checkLocationFree fails with ErrLocationExists when the location already has a slider for the locale
+func (s *service) checkLocationFree(ctx context.Context, location, locale string) error {
+	existingSlider, err := s.repo.FindByLocation(ctx, location, locale)
+	if err != nil {
+		return fmt.Errorf("failed to check existing location: %w", err)
+	}
+	if existingSlider != nil {
+		return ErrLocationExists
+	}
+	return nil
 }
 
 // DeleteSlider deletes a slider
@@ -180,8 +406,21 @@ func (s *service) DeleteSlider(ctx context.Context, id uint) error {
 	return nil
 }
 
-// ListSliders retrieves paginated list of sliders
+// ListSliders retrieves a paginated list of the sliders shown at the moment, with their active
+// items. Disabled and unscheduled sliders are left out; see ListAllSliders.
 func (s *service) ListSliders(ctx context.Context, page, perPage int) ([]SliderResponse, int64, error) {
+	now := time.Now()
+	return s.listSliders(ctx, page, perPage, &now)
+}
+
+// ListAllSliders retrieves a paginated list of every slider with all its items, for admins
+func (s *service) ListAllSliders(ctx context.Context, page, perPage int) ([]SliderResponse, int64, error) {
+	return s.listSliders(ctx, page, perPage, nil)
+}
+
+// listSliders lists the sliders active at activeAt with their active items, or every slider and
+// item when activeAt is nil
+func (s *service) listSliders(ctx context.Context, page, perPage int, activeAt *time.Time) ([]SliderResponse, int64, error) {
 	if page < 1 {
 		return nil, 0, fmt.Errorf("page must be >= 1")
 	}
@@ -192,14 +431,29 @@ func (s *service) ListSliders(ctx context.Context, page, perPage int) ([]SliderR
 		return nil, 0, fmt.Errorf("perPage must be <= 100")
 	}
 
-	sliders, total, err := s.repo.List(ctx, page, perPage)
+	sliders, total, err := s.repo.List(ctx, page, perPage, activeAt)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list sliders: %w", err)
 	}
 
+	var items []SliderItem
+	for i := range sliders {
+		if activeAt != nil {
+			sliders[i].Items = sliders[i].activeItems(*activeAt)
+		}
+		items = append(items, sliders[i].Items...)
+	}
+	linked, err := s.linkedImoveis(ctx, items)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	responses := make([]SliderResponse, len(sliders))
-	for i, slider := range sliders {
-		responses[i] = *s.sliderToResponse(&slider)
+	for i := range sliders {
+		if activeAt != nil {
+			sliders[i].Items = publishedItems(sliders[i].Items, linked)
+		}
+		responses[i] = *s.sliderToResponse(&sliders[i], linked)
 	}
 
 	return responses, total, nil
@@ -214,26 +468,33 @@ func (s *service) AddSliderItem(ctx context.Context, sliderID uint, req *CreateS
 	if slider == nil {
 		return nil, ErrSliderNotFound
 	}
+	if err := checkActiveWindow(req.ActiveFrom, req.ActiveUntil); err != nil {
+		return nil, err
+	}
+	if req.ImovelID != nil {
+		if err := s.checkLinkedImovel(ctx, *req.ImovelID); err != nil {
+			return nil, err
+		}
+	}
 
-	item := &SliderItem{
-		SliderID: sliderID,
-		ImageURL: req.ImageURL,
-		LinkURL:  req.LinkURL,
-		Content:  req.Content,
-		Order:    req.Order,
-		Tags:     req.Tags,
-		Titulo:   req.Titulo,
+	candidate := limitCandidate{imageURL: req.ImageURL, content: req.Content, checkImage: true}
+	if err := s.enforceLimits(ctx, slider.Type, len(slider.Items)+1, []limitCandidate{candidate}); err != nil {
+		return nil, err
 	}
 
+	item := newSliderItem(sliderID, req)
+
 	if err := s.repo.CreateItem(ctx, item); err != nil {
 		return nil, fmt.Errorf("failed to create slider item: %w", err)
 	}
 
-	return s.itemToResponse(item), nil
+	return s.itemWithImovel(ctx, item)
 }
 
-// GetSliderItem retrieves a slider item by ID
-func (s *service) GetSliderItem(ctx context.Context, itemID uint) (*SliderItemResponse, error) {
+// GetSliderItem retrieves a slider item by ID. Items shown at the moment (enabled and scheduled,
+// in a slider that is too, linking to a published property) are public; the others are only
+// returned with a valid preview token of their slider.
+func (s *service) GetSliderItem(ctx context.Context, itemID uint, previewToken string) (*SliderItemResponse, error) {
 	item, err := s.repo.FindItemByID(ctx, itemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find slider item: %w", err)
@@ -241,7 +502,33 @@ func (s *service) GetSliderItem(ctx context.Context, itemID uint) (*SliderItemRe
 	if item == nil {
 		return nil, ErrSliderItemNotFound
 	}
-	return s.itemToResponse(item), nil
+	slider, err := s.repo.FindByID(ctx, item.SliderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderItemNotFound
+	}
+
+	if previewToken != "" {
+		if err := s.validatePreviewToken(previewToken, slider.ID); err != nil {
+			return nil, err
+		}
+		return s.itemWithImovel(ctx, item)
+	}
+
+	now := time.Now()
+	if !slider.activeAt(now) || !item.activeAt(now) {
+		return nil, ErrSliderItemNotFound
+	}
+	linked, err := s.linkedImoveis(ctx, []SliderItem{*item})
+	if err != nil {
+		return nil, err
+	}
+	if len(publishedItems([]SliderItem{*item}, linked)) == 0 {
+		return nil, ErrSliderItemNotFound
+	}
+	return s.itemToResponse(item, linked), nil
 }
 
 // UpdateSliderItem updates a slider item
@@ -254,8 +541,29 @@ func (s *service) UpdateSliderItem(ctx context.Context, itemID uint, req *Update
 		return nil, ErrSliderItemNotFound
 	}
 
-	if req.ImageURL != "" {
+	if req.ImageURL != "" || req.Content != "" {
+		slider, err := s.repo.FindByID(ctx, item.SliderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find slider: %w", err)
+		}
+		if slider == nil {
+			return nil, ErrSliderNotFound
+		}
+
+		candidate := limitCandidate{
+			imageURL:   req.ImageURL,
+			content:    req.Content,
+			checkImage: req.ImageURL != "" && req.ImageURL != item.ImageURL,
+		}
+		// The item count is unchanged by an update, so only the item itself is checked
+		if err := s.enforceLimits(ctx, slider.Type, 0, []limitCandidate{candidate}); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ImageURL != "" && req.ImageURL != item.ImageURL {
 		item.ImageURL = req.ImageURL
+		item.Variants = nil
 	}
 	if req.LinkURL != "" {
 		item.LinkURL = req.LinkURL
@@ -272,12 +580,37 @@ func (s *service) UpdateSliderItem(ctx context.Context, itemID uint, req *Update
 	if req.Titulo != "" {
 		item.Titulo = req.Titulo
 	}
+	if req.Enabled != nil {
+		item.Enabled = *req.Enabled
+	}
+	if req.ActiveFrom != nil || req.ActiveUntil != nil {
+		if req.ActiveFrom != nil {
+			item.ActiveFrom = req.ActiveFrom
+		}
+		if req.ActiveUntil != nil {
+			item.ActiveUntil = req.ActiveUntil
+		}
+		if err := checkActiveWindow(item.ActiveFrom, item.ActiveUntil); err != nil {
+			return nil, err
+		}
+	}
+	if req.ImovelID != nil {
+		switch {
+		case *req.ImovelID == 0:
+			item.ImovelID = nil
+		case item.ImovelID == nil || *item.ImovelID != *req.ImovelID:
+			if err := s.checkLinkedImovel(ctx, *req.ImovelID); err != nil {
+				return nil, err
+			}
+			item.ImovelID = req.ImovelID
+		}
+	}
 
 	if err := s.repo.UpdateItem(ctx, item); err != nil {
 		return nil, fmt.Errorf("failed to update slider item: %w", err)
 	}
 
-	return s.itemToResponse(item), nil
+	return s.itemWithImovel(ctx, item)
 }
 
 // DeleteSliderItem deletes a slider item
@@ -291,8 +624,111 @@ func (s *service) DeleteSliderItem(ctx context.Context, itemID uint) error {
 	return nil
 }
 
-// GetSliderItems retrieves all items for a slider
-func (s *service) GetSliderItems(ctx context.Context, sliderID uint) ([]SliderItemResponse, error) {
+// ReplaceSliderItems makes req the whole item set of the slider in one transaction: listed items
+// with an id are updated, those without one are created and the items left out are deleted
+func (s *service) ReplaceSliderItems(ctx context.Context, sliderID uint, req *ReplaceSliderItemsRequest) ([]SliderItemResponse, error) {
+	slider, err := s.repo.FindByID(ctx, sliderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderNotFound
+	}
+
+	existing := make(map[uint]*SliderItem, len(slider.Items))
+	for i := range slider.Items {
+		existing[slider.Items[i].ID] = &slider.Items[i]
+	}
+
+	listed := make(map[uint]bool, len(req.Items))
+	candidates := make([]limitCandidate, len(req.Items))
+	for i, itemReq := range req.Items {
+		var current *SliderItem
+		if itemReq.ID != nil {
+			if current = existing[*itemReq.ID]; current == nil {
+				return nil, ErrItemNotInSlider
+			}
+			if listed[*itemReq.ID] {
+				return nil, ErrDuplicateItem
+			}
+			listed[*itemReq.ID] = true
+		}
+
+		if err := checkActiveWindow(itemReq.ActiveFrom, itemReq.ActiveUntil); err != nil {
+			return nil, err
+		}
+		// A link kept from before is not checked again, so unpublishing the property does not
+		// block saving the rest of the slider
+		if itemReq.ImovelID != nil && (current == nil || current.ImovelID == nil || *current.ImovelID != *itemReq.ImovelID) {
+			if err := s.checkLinkedImovel(ctx, *itemReq.ImovelID); err != nil {
+				return nil, err
+			}
+		}
+
+		candidates[i] = limitCandidate{
+			field:      fmt.Sprintf("items[%d]", i),
+			imageURL:   itemReq.ImageURL,
+			content:    itemReq.Content,
+			checkImage: current == nil || current.ImageURL != itemReq.ImageURL,
+		}
+	}
+	if err := s.enforceLimits(ctx, slider.Type, len(req.Items), candidates); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		for _, item := range slider.Items {
+			if listed[item.ID] {
+				continue
+			}
+			if err := s.repo.DeleteItem(txCtx, item.ID); err != nil {
+				return fmt.Errorf("failed to delete slider item: %w", err)
+			}
+		}
+
+		for i := range req.Items {
+			itemReq := &req.Items[i]
+			item := newSliderItem(sliderID, &itemReq.CreateSliderItemRequest)
+			if itemReq.ID == nil {
+				if err := s.repo.CreateItem(txCtx, item); err != nil {
+					return fmt.Errorf("failed to create slider item: %w", err)
+				}
+				continue
+			}
+			item.ID = *itemReq.ID
+			// The renditions of an uploaded image are kept as long as the item keeps the image
+			if current := existing[item.ID]; current.ImageURL == item.ImageURL {
+				item.Variants = current.Variants
+			}
+			if err := s.repo.UpdateItem(txCtx, item); err != nil {
+				return fmt.Errorf("failed to update slider item: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetSliderItems(ctx, sliderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slider items: %w", err)
+	}
+	linked, err := s.linkedImoveis(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]SliderItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = *s.itemToResponse(&item, linked)
+	}
+	return responses, nil
+}
+
+// GetSliderItems retrieves the currently active items of a slider. Items of disabled or
+// unscheduled sliders, and inactive items, require a valid preview token.
+func (s *service) GetSliderItems(ctx context.Context, sliderID uint, previewToken string) ([]SliderItemResponse, error) {
 	slider, err := s.repo.FindByID(ctx, sliderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find slider: %w", err)
@@ -300,50 +736,133 @@ func (s *service) GetSliderItems(ctx context.Context, sliderID uint) ([]SliderIt
 	if slider == nil {
 		return nil, ErrSliderNotFound
 	}
+	if err := s.checkVisibility(slider, previewToken); err != nil {
+		return nil, err
+	}
 
 	items, err := s.repo.GetSliderItems(ctx, sliderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get slider items: %w", err)
 	}
+	if previewToken == "" {
+		slider.Items = items
+		items = slider.activeItems(time.Now())
+	}
+
+	linked, err := s.linkedImoveis(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	if previewToken == "" {
+		items = publishedItems(items, linked)
+	}
 
 	responses := make([]SliderItemResponse, len(items))
 	for i, item := range items {
-		responses[i] = *s.itemToResponse(&item)
+		responses[i] = *s.itemToResponse(&item, linked)
 	}
 
 	return responses, nil
 }
 
+// newSliderItem builds the item req creates in the slider, enabled unless req says otherwise
+func newSliderItem(sliderID uint, req *CreateSliderItemRequest) *SliderItem {
+	item := &SliderItem{
+		SliderID:    sliderID,
+		ImageURL:    req.ImageURL,
+		LinkURL:     req.LinkURL,
+		Content:     req.Content,
+		Order:       req.Order,
+		Tags:        req.Tags,
+		Titulo:      req.Titulo,
+		ImovelID:    req.ImovelID,
+		Enabled:     true,
+		ActiveFrom:  req.ActiveFrom,
+		ActiveUntil: req.ActiveUntil,
+	}
+	if req.Enabled != nil {
+		item.Enabled = *req.Enabled
+	}
+	return item
+}
+
 // Helper methods to convert models to responses
 
-func (s *service) sliderToResponse(slider *Slider) *SliderResponse {
+// sliderWithImoveis converts the slider with the live data of the properties its items link to
+func (s *service) sliderWithImoveis(ctx context.Context, slider *Slider) (*SliderResponse, error) {
+	linked, err := s.linkedImoveis(ctx, slider.Items)
+	if err != nil {
+		return nil, err
+	}
+	return s.sliderToResponse(slider, linked), nil
+}
+
+// publicSlider converts the slider with only the items shown at t: active, and linking to a
+// published property when linked to one
+func (s *service) publicSlider(ctx context.Context, slider *Slider, t time.Time) (*SliderResponse, error) {
+	active := *slider
+	active.Items = slider.activeItems(t)
+	linked, err := s.linkedImoveis(ctx, active.Items)
+	if err != nil {
+		return nil, err
+	}
+	active.Items = publishedItems(active.Items, linked)
+	return s.sliderToResponse(&active, linked), nil
+}
+
+// itemWithImovel converts the item with the live data of the property it links to
+func (s *service) itemWithImovel(ctx context.Context, item *SliderItem) (*SliderItemResponse, error) {
+	linked, err := s.linkedImoveis(ctx, []SliderItem{*item})
+	if err != nil {
+		return nil, err
+	}
+	return s.itemToResponse(item, linked), nil
+}
+
+func (s *service) sliderToResponse(slider *Slider, linked map[uint]*imoveis.ImovelResponse) *SliderResponse {
 	items := make([]SliderItemResponse, len(slider.Items))
 	for i, item := range slider.Items {
-		items[i] = *s.itemToResponse(&item)
+		items[i] = *s.itemToResponse(&item, linked)
 	}
 
 	return &SliderResponse{
-		ID:        slider.ID,
-		Name:      slider.Name,
-		Type:      int(slider.Type),
-		Location:  slider.Location,
-		Items:     items,
-		CreatedAt: slider.CreatedAt,
-		UpdatedAt: slider.UpdatedAt,
+		ID:          slider.ID,
+		Name:        slider.Name,
+		Type:        slider.Type,
+		Location:    slider.Location,
+		Locale:      slider.Locale,
+		Audience:    slider.Audience,
+		Enabled:     slider.Enabled,
+		ActiveFrom:  slider.ActiveFrom,
+		ActiveUntil: slider.ActiveUntil,
+		Items:       items,
+		CreatedAt:   slider.CreatedAt,
+		UpdatedAt:   slider.UpdatedAt,
 	}
 }
 
-func (s *service) itemToResponse(item *SliderItem) *SliderItemResponse {
-	return &SliderItemResponse{
-		ID:        item.ID,
-		SliderID:  item.SliderID,
-		ImageURL:  item.ImageURL,
-		LinkURL:   item.LinkURL,
-		Content:   item.Content,
-		Order:     item.Order,
-		Tags:      item.Tags,
-		Titulo:    item.Titulo,
-		CreatedAt: item.CreatedAt,
-		UpdatedAt: item.UpdatedAt,
+func (s *service) itemToResponse(item *SliderItem, linked map[uint]*imoveis.ImovelResponse) *SliderItemResponse {
+	response := &SliderItemResponse{
+		ID:          item.ID,
+		SliderID:    item.SliderID,
+		ImageURL:    item.ImageURL,
+		Variants:    item.Variants,
+		LinkURL:     item.LinkURL,
+		Content:     item.Content,
+		Order:       item.Order,
+		Tags:        item.Tags,
+		Titulo:      item.Titulo,
+		Enabled:     item.Enabled,
+		ActiveFrom:  item.ActiveFrom,
+		ActiveUntil: item.ActiveUntil,
+		ImovelID:    item.ImovelID,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+	if item.ImovelID != nil {
+		if imovel := linked[*item.ImovelID]; imovel != nil {
+			response.Imovel = toLinkedImovel(imovel)
+		}
 	}
+	return response
 }
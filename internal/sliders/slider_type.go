@@ -0,0 +1,81 @@
+package sliders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SliderType is how a slider is rendered. It is stored as an integer and named in the API.
+type SliderType int
+
+const (
+	SliderType_Slideshow SliderType = iota
+	SliderType_Carousel
+	SliderType_Static
+)
+
+// sliderTypeNames is the registry of slider types, indexed by their stored value. New types are
+// added here and to the limits of the slider service.
+var sliderTypeNames = [...]string{
+	SliderType_Slideshow: "slideshow",
+	SliderType_Carousel:  "carousel",
+	SliderType_Static:    "static",
+}
+
+// ParseSliderType resolves a type by name, case-insensitively. The stored integer ("1") is
+// accepted too for clients written before types were named.
+func ParseSliderType(value string) (SliderType, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for i, name := range sliderTypeNames {
+		if name == value {
+			return SliderType(i), nil
+		}
+	}
+	if n, err := strconv.Atoi(value); err == nil && SliderType(n).Valid() {
+		return SliderType(n), nil
+	}
+	return 0, fmt.Errorf("%w %q (expected one of %s)", ErrInvalidType, value, strings.Join(sliderTypeNames[:], ", "))
+}
+
+// Valid reports whether st is a registered type
+func (st SliderType) Valid() bool {
+	return st >= 0 && int(st) < len(sliderTypeNames)
+}
+
+func (st SliderType) String() string {
+	if !st.Valid() {
+		return "unknown"
+	}
+	return sliderTypeNames[st]
+}
+
+// MarshalJSON writes the type name
+func (st SliderType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(st.String())
+}
+
+// UnmarshalJSON reads a type name or, for backward compatibility, its integer value
+func (st *SliderType) UnmarshalJSON(data []byte) error {
+	var value string
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("%w: must be a name or an integer", ErrInvalidType)
+		}
+		value = strconv.Itoa(n)
+	}
+
+	parsed, err := ParseSliderType(value)
+	if err != nil {
+		return err
+	}
+	*st = parsed
+	return nil
+}
@@ -0,0 +1,82 @@
+package sliders
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sliderTypePtr(v SliderType) *SliderType {
+	return &v
+}
+
+func TestParseSliderType(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    SliderType
+		wantErr bool
+	}{
+		{"slideshow", SliderType_Slideshow, false},
+		{" Carousel ", SliderType_Carousel, false},
+		{"static", SliderType_Static, false},
+		{"2", SliderType_Static, false},
+		{"3", 0, true},
+		{"-1", 0, true},
+		{"banner", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSliderType(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliderType_JSON(t *testing.T) {
+	t.Run("names and legacy integers are accepted", func(t *testing.T) {
+		var req CreateSliderRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"type":"carousel"}`), &req))
+		assert.Equal(t, SliderType_Carousel, *req.Type)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"type":0}`), &req))
+		assert.Equal(t, SliderType_Slideshow, *req.Type)
+	})
+
+	t.Run("unknown types are rejected", func(t *testing.T) {
+		var req CreateSliderRequest
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"type":"banner"}`), &req), ErrInvalidType)
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"type":7}`), &req), ErrInvalidType)
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"type":1.5}`), &req), ErrInvalidType)
+	})
+
+	t.Run("responses use the name", func(t *testing.T) {
+		data, err := json.Marshal(SliderResponse{Type: SliderType_Static})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"type":"static"`)
+	})
+}
+
+func TestService_SliderType(t *testing.T) {
+	ctx := context.Background()
+	svc := newPreviewService(time.Hour)
+
+	created, err := svc.CreateSlider(ctx, &CreateSliderRequest{Name: "Home", Type: sliderTypePtr(SliderType_Slideshow), Location: "home"})
+	require.NoError(t, err)
+	assert.Equal(t, SliderType_Slideshow, created.Type)
+
+	_, err = svc.CreateSlider(ctx, &CreateSliderRequest{Name: "Other", Type: sliderTypePtr(SliderType(9)), Location: "other"})
+	assert.ErrorIs(t, err, ErrInvalidType)
+
+	_, err = svc.UpdateSlider(ctx, created.ID, &UpdateSliderRequest{Type: sliderTypePtr(SliderType(-1))})
+	assert.ErrorIs(t, err, ErrInvalidType)
+}
@@ -0,0 +1,169 @@
+package sliders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// statsDayLayout is the format of the days of the stats
+	statsDayLayout = "2006-01-02"
+	// defaultStatsDays is the period of the stats when no from is given
+	defaultStatsDays = 30
+	// maxStatsDays bounds the period of the stats
+	maxStatsDays = 366
+)
+
+// ErrInvalidStatsPeriod is returned when the stats period ends before it starts or is too long
+var ErrInvalidStatsPeriod = errors.New("invalid stats period")
+
+// statsDay is the UTC day t counts on
+func statsDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// RecordClick counts a click on a slider item for the current day
+func (s *service) RecordClick(ctx context.Context, itemID uint) error {
+	item, err := s.repo.FindItemByID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to find slider item: %w", err)
+	}
+	if item == nil {
+		return ErrSliderItemNotFound
+	}
+
+	stat := SliderItemStat{SliderItemID: itemID, Day: statsDay(time.Now()), Clicks: 1}
+	if err := s.repo.AddItemStats(ctx, []SliderItemStat{stat}); err != nil {
+		return fmt.Errorf("failed to record click: %w", err)
+	}
+	return nil
+}
+
+// RecordImpressions counts a batch of impressions for the current day, one write for the batch
+func (s *service) RecordImpressions(ctx context.Context, req *RecordImpressionsRequest) error {
+	counts := make(map[uint]int64, len(req.ItemIDs))
+	ids := make([]uint, 0, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		if counts[id] == 0 {
+			ids = append(ids, id)
+		}
+		counts[id]++
+	}
+
+	existing, err := s.repo.ExistingItemIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to find slider items: %w", err)
+	}
+
+	day := statsDay(time.Now())
+	stats := make([]SliderItemStat, len(existing))
+	for i, id := range existing {
+		stats[i] = SliderItemStat{SliderItemID: id, Day: day, Impressions: counts[id]}
+	}
+	if err := s.repo.AddItemStats(ctx, stats); err != nil {
+		return fmt.Errorf("failed to record impressions: %w", err)
+	}
+	return nil
+}
+
+// GetSliderStats returns the impressions, clicks and click-through rate of every item of the slider
+// over the period, in item order, with the totals of the slider
+func (s *service) GetSliderStats(ctx context.Context, sliderID uint, query *SliderStatsQuery) (*SliderStatsResponse, error) {
+	from, to, err := statsPeriod(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	slider, err := s.repo.FindByID(ctx, sliderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderNotFound
+	}
+
+	stats, err := s.repo.ListItemStats(ctx, sliderID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve slider stats: %w", err)
+	}
+
+	response := &SliderStatsResponse{
+		SliderID: sliderID,
+		From:     from.Format(statsDayLayout),
+		To:       to.Format(statsDayLayout),
+		Items:    make([]SliderItemStatsResponse, len(slider.Items)),
+	}
+	byItem := make(map[uint]*SliderItemStatsResponse, len(slider.Items))
+	for i, item := range slider.Items {
+		response.Items[i] = SliderItemStatsResponse{
+			ItemID:   item.ID,
+			Titulo:   item.Titulo,
+			ImageURL: item.ImageURL,
+			Order:    item.Order,
+			Days:     []DailyStatsResponse{},
+		}
+		byItem[item.ID] = &response.Items[i]
+	}
+
+	for _, stat := range stats {
+		item := byItem[stat.SliderItemID]
+		if item == nil {
+			continue
+		}
+		item.Impressions += stat.Impressions
+		item.Clicks += stat.Clicks
+		item.Days = append(item.Days, DailyStatsResponse{
+			Day:         stat.Day.UTC().Format(statsDayLayout),
+			Impressions: stat.Impressions,
+			Clicks:      stat.Clicks,
+		})
+		response.Impressions += stat.Impressions
+		response.Clicks += stat.Clicks
+	}
+	for i := range response.Items {
+		response.Items[i].CTR = clickThroughRate(response.Items[i].Clicks, response.Items[i].Impressions)
+	}
+	response.CTR = clickThroughRate(response.Clicks, response.Impressions)
+
+	return response, nil
+}
+
+// statsPeriod resolves the days of query, defaulting to the defaultStatsDays days up to now
+func statsPeriod(query *SliderStatsQuery, now time.Time) (time.Time, time.Time, error) {
+	to := statsDay(now)
+	if query.To != "" {
+		parsed, err := time.Parse(statsDayLayout, query.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidStatsPeriod)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultStatsDays - 1))
+	if query.From != "" {
+		parsed, err := time.Parse(statsDayLayout, query.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidStatsPeriod)
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must not be before from", ErrInvalidStatsPeriod)
+	}
+	if to.Sub(from) >= maxStatsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: at most %d days", ErrInvalidStatsPeriod, maxStatsDays)
+	}
+	return from, to, nil
+}
+
+// clickThroughRate is clicks per impression rounded to 4 decimals, 0 without impressions
+func clickThroughRate(clicks, impressions int64) float64 {
+	if impressions == 0 {
+		return 0
+	}
+	return math.Round(float64(clicks)/float64(impressions)*10000) / 10000
+}
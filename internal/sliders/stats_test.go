@@ -0,0 +1,119 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *stubRepository) FindItemByID(ctx context.Context, id uint) (*SliderItem, error) {
+	for _, slider := range r.sliders {
+		for i := range slider.Items {
+			if slider.Items[i].ID == id {
+				return &slider.Items[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) ExistingItemIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var existing []uint
+	for _, id := range ids {
+		if item, _ := r.FindItemByID(ctx, id); item != nil {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+func (r *stubRepository) AddItemStats(ctx context.Context, stats []SliderItemStat) error {
+	r.stats = append(r.stats, stats...)
+	return nil
+}
+
+func (r *stubRepository) ListItemStats(ctx context.Context, sliderID uint, from, to time.Time) ([]SliderItemStat, error) {
+	return r.stats, nil
+}
+
+func TestService_RecordStats(t *testing.T) {
+	ctx := context.Background()
+	svc := newPreviewService(time.Hour, newCarousel())
+	repo := svc.repo.(*stubRepository)
+
+	require.NoError(t, svc.RecordClick(ctx, 2))
+	assert.ErrorIs(t, svc.RecordClick(ctx, 99), ErrSliderItemNotFound)
+
+	require.NoError(t, svc.RecordImpressions(ctx, &RecordImpressionsRequest{ItemIDs: []uint{1, 2, 1, 99}}))
+
+	today := statsDay(time.Now())
+	assert.ElementsMatch(t, []SliderItemStat{
+		{SliderItemID: 2, Day: today, Clicks: 1},
+		{SliderItemID: 1, Day: today, Impressions: 2},
+		{SliderItemID: 2, Day: today, Impressions: 1},
+	}, repo.stats)
+}
+
+func TestService_GetSliderStats(t *testing.T) {
+	ctx := context.Background()
+	svc := newPreviewService(time.Hour, newCarousel())
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	svc.repo.(*stubRepository).stats = []SliderItemStat{
+		{SliderItemID: 1, Day: day, Impressions: 100, Clicks: 3},
+		{SliderItemID: 1, Day: day.AddDate(0, 0, 1), Impressions: 50, Clicks: 2},
+		{SliderItemID: 3, Day: day, Impressions: 40},
+	}
+
+	stats, err := svc.GetSliderStats(ctx, 1, &SliderStatsQuery{From: "2026-10-01", To: "2026-10-07"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", stats.From)
+	assert.Equal(t, "2026-10-07", stats.To)
+	assert.Equal(t, int64(190), stats.Impressions)
+	assert.Equal(t, int64(5), stats.Clicks)
+	assert.Equal(t, 0.0263, stats.CTR)
+
+	require.Len(t, stats.Items, 3)
+	assert.Equal(t, int64(150), stats.Items[0].Impressions)
+	assert.Equal(t, 0.0333, stats.Items[0].CTR)
+	assert.Len(t, stats.Items[0].Days, 2)
+	assert.Zero(t, stats.Items[1].Impressions)
+	assert.Empty(t, stats.Items[1].Days)
+	assert.Zero(t, stats.Items[2].CTR)
+
+	_, err = svc.GetSliderStats(ctx, 99, &SliderStatsQuery{})
+	assert.ErrorIs(t, err, ErrSliderNotFound)
+}
+
+func TestStatsPeriod(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    SliderStatsQuery
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{"defaults to the last 30 days", SliderStatsQuery{}, "2026-09-18", "2026-10-17", false},
+		{"from only", SliderStatsQuery{From: "2026-10-01"}, "2026-10-01", "2026-10-17", false},
+		{"to only", SliderStatsQuery{To: "2026-09-30"}, "2026-09-01", "2026-09-30", false},
+		{"single day", SliderStatsQuery{From: "2026-10-01", To: "2026-10-01"}, "2026-10-01", "2026-10-01", false},
+		{"to before from", SliderStatsQuery{From: "2026-10-02", To: "2026-10-01"}, "", "", true},
+		{"too long", SliderStatsQuery{From: "2025-01-01", To: "2026-10-01"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := statsPeriod(&tt.query, now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStatsPeriod)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, from.Format(statsDayLayout))
+			assert.Equal(t, tt.wantTo, to.Format(statsDayLayout))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package sliders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+// ListDeletedSliders retrieves soft-deleted sliders, most recently deleted first
+func (s *service) ListDeletedSliders(ctx context.Context, page, perPage int) ([]DeletedSliderResponse, int64, error) {
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page must be >= 1")
+	}
+	if perPage < 1 || perPage > 100 {
+		return nil, 0, fmt.Errorf("perPage must be between 1 and 100")
+	}
+
+	sliders, total, err := s.repo.ListDeleted(ctx, page, perPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list deleted sliders: %w", err)
+	}
+
+	var items []SliderItem
+	for _, slider := range sliders {
+		items = append(items, slider.Items...)
+	}
+	linked, err := s.linkedImoveis(ctx, items)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]DeletedSliderResponse, len(sliders))
+	for i := range sliders {
+		responses[i] = DeletedSliderResponse{
+			SliderResponse: *s.sliderToResponse(&sliders[i], linked),
+			DeletedAt:      sliders[i].DeletedAt.Time,
+		}
+	}
+	return responses, total, nil
+}
+
+// RestoreSlider undeletes a slider with the items deleted along with it. It fails with
+// ErrLocationExists when another slider has taken its location and locale meanwhile.
+func (s *service) RestoreSlider(ctx context.Context, id uint) (*SliderResponse, error) {
+	slider, err := s.repo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderNotFound
+	}
+	if err := s.checkLocationFree(ctx, slider.Location, slider.Locale); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, slider); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSliderNotFound
+		}
+		return nil, fmt.Errorf("failed to restore slider: %w", err)
+	}
+
+	restored, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload slider: %w", err)
+	}
+	if restored == nil {
+		return nil, ErrSliderNotFound
+	}
+	return s.sliderWithImoveis(ctx, restored)
+}
+
+// PurgeDeleted permanently deletes the sliders and items deleted before the given time
+func (s *service) PurgeDeleted(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	purged, err := s.repo.Purge(ctx, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge deleted sliders: %w", err)
+	}
+	return purged, nil
+}
+
+// NewPurgeTask permanently deletes the sliders and items deleted more than retention ago. The
+// trash is lost for good, so it runs only when named in the request.
+func NewPurgeTask(service Service, retention time.Duration) maintenance.Task {
+	return maintenance.TaskFunc{
+		TaskName: "purge_sliders",
+		Explicit: true,
+		Fn: func(ctx context.Context, report maintenance.ProgressFunc) error {
+			purged, err := service.PurgeDeleted(ctx, time.Now().Add(-retention))
+			if err != nil {
+				return err
+			}
+			done := int(purged.Sliders + purged.Items)
+			report(done, done)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package sliders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/maintenance"
+)
+
+func (r *stubRepository) FindDeletedByID(ctx context.Context, id uint) (*Slider, error) {
+	if slider, ok := r.deleted[id]; ok {
+		return slider, nil
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) Restore(ctx context.Context, slider *Slider) error {
+	delete(r.deleted, slider.ID)
+	slider.DeletedAt = gorm.DeletedAt{}
+	r.sliders[slider.ID] = slider
+	return nil
+}
+
+func TestRepository_DeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupTestDB(t))
+
+	// Item 2 was removed before the slider, so it stays deleted on restore
+	require.NoError(t, repo.DeleteItem(ctx, 2))
+	require.NoError(t, repo.Delete(ctx, 1))
+	assert.ErrorIs(t, repo.Delete(ctx, 1), gorm.ErrRecordNotFound)
+
+	found, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	deleted, total, err := repo.ListDeleted(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, deleted, 1)
+	require.Len(t, deleted[0].Items, 1)
+	assert.Equal(t, uint(1), deleted[0].Items[0].ID)
+
+	slider, err := repo.FindDeletedByID(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, slider)
+	require.NoError(t, repo.Restore(ctx, slider))
+
+	restored, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	require.Len(t, restored.Items, 1)
+	assert.Equal(t, uint(1), restored.Items[0].ID)
+
+	missing, err := repo.FindDeletedByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestRepository_Purge(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	require.NoError(t, repo.DeleteItem(ctx, 2))
+	require.NoError(t, repo.Delete(ctx, 2))
+
+	// Nothing was deleted before the cutoff yet
+	purged, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &PurgeResult{}, purged)
+
+	purged, err = repo.Purge(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, &PurgeResult{Sliders: 1, Items: 2}, purged)
+
+	var remaining int64
+	require.NoError(t, db.Unscoped().Model(&SliderItem{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+	require.NoError(t, db.Unscoped().Model(&Slider{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestService_RestoreSlider(t *testing.T) {
+	ctx := context.Background()
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	t.Run("restores the slider", func(t *testing.T) {
+		svc := newPreviewService(time.Hour)
+		svc.repo.(*stubRepository).deleted = map[uint]*Slider{
+			4: {ID: 4, Location: "home", Enabled: true, DeletedAt: deletedAt},
+		}
+
+		resp, err := svc.RestoreSlider(ctx, 4)
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), resp.ID)
+
+		_, err = svc.RestoreSlider(ctx, 4)
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+
+	t.Run("location taken since the delete", func(t *testing.T) {
+		svc := newPreviewService(time.Hour, &Slider{ID: 5, Location: "home"})
+		svc.repo.(*stubRepository).deleted = map[uint]*Slider{
+			4: {ID: 4, Location: "home", DeletedAt: deletedAt},
+		}
+
+		_, err := svc.RestoreSlider(ctx, 4)
+		assert.ErrorIs(t, err, ErrLocationExists)
+	})
+}
+
+func TestNewPurgeTask_RunsOnlyWhenNamed(t *testing.T) {
+	task := NewPurgeTask(nil, 30*24*time.Hour)
+
+	explicit, ok := task.(maintenance.ExplicitTask)
+	require.True(t, ok)
+	assert.True(t, explicit.RunOnlyWhenNamed())
+}
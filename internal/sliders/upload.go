@@ -0,0 +1,188 @@
+package sliders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/imoveis"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/media"
+)
+
+// defaultMaxUploadBytes applies when storage.max_upload_bytes is not configured
+const defaultMaxUploadBytes = 10 << 20
+
+var (
+	// ErrFileTooLarge is returned when an upload exceeds the configured maximum size
+	ErrFileTooLarge = errors.New("file exceeds the maximum upload size")
+	// ErrUnsupportedFileType is returned when an upload is not a JPEG, PNG, GIF or WebP image
+	ErrUnsupportedFileType = errors.New("file type is not allowed")
+	// ErrInvalidImage is returned when an uploaded image cannot be decoded
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// imageExtensions names the stored originals and lists the accepted image types
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// UploadedFile is an image received in a multipart request
+type UploadedFile struct {
+	Size    int64
+	Content io.Reader
+}
+
+// toCreateRequest returns the item fields of req, without an image yet
+func (req *UploadSliderItemRequest) toCreateRequest() *CreateSliderItemRequest {
+	return &CreateSliderItemRequest{
+		LinkURL:     req.LinkURL,
+		Content:     req.Content,
+		Order:       req.Order,
+		Tags:        req.Tags,
+		Titulo:      req.Titulo,
+		ImovelID:    req.ImovelID,
+		Enabled:     req.Enabled,
+		ActiveFrom:  req.ActiveFrom,
+		ActiveUntil: req.ActiveUntil,
+	}
+}
+
+// MaxUploadBytes returns the largest accepted image size
+func (s *service) MaxUploadBytes() int64 {
+	return s.maxUpload
+}
+
+// UploadSliderItem stores an uploaded image with its media.DefaultRenditions and adds an item
+// showing it. The image is checked against the limits of the slider type before anything is
+// stored, and the stored files are removed again when the item cannot be saved.
+func (s *service) UploadSliderItem(ctx context.Context, sliderID uint, file *UploadedFile, req *UploadSliderItemRequest) (*SliderItemResponse, error) {
+	if file.Size > s.maxUpload {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrFileTooLarge, s.maxUpload)
+	}
+
+	slider, err := s.repo.FindByID(ctx, sliderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slider: %w", err)
+	}
+	if slider == nil {
+		return nil, ErrSliderNotFound
+	}
+
+	itemReq := req.toCreateRequest()
+	if err := checkActiveWindow(itemReq.ActiveFrom, itemReq.ActiveUntil); err != nil {
+		return nil, err
+	}
+	if itemReq.ImovelID != nil {
+		if err := s.checkLinkedImovel(ctx, *itemReq.ImovelID); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file.Content, s.maxUpload+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxUpload {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrFileTooLarge, s.maxUpload)
+	}
+
+	// The type is sniffed from the content; the client-provided Content-Type is ignored
+	contentType := strings.ToLower(strings.SplitN(http.DetectContentType(data), ";", 2)[0])
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+	img, err := media.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	bounds := img.Bounds()
+	candidate := limitCandidate{
+		content:    itemReq.Content,
+		checkImage: true,
+		image:      &ImageInfo{Width: bounds.Dx(), Height: bounds.Dy(), Bytes: int64(len(data))},
+	}
+	if err := s.enforceLimits(ctx, slider.Type, len(slider.Items)+1, []limitCandidate{candidate}); err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("sliders/%d/%s", sliderID, uuid.NewString())
+	var stored []string
+	// Do not leave orphaned objects behind when the upload fails halfway
+	cleanup := func() {
+		for _, key := range stored {
+			if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil {
+				log.Printf("Failed to delete orphaned slider image %s: %v", key, err)
+			}
+		}
+	}
+
+	original, err := s.storage.Put(ctx, prefix+ext, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	stored = append(stored, original.Key)
+
+	variants := make(imoveis.AnexoVariants, len(media.DefaultRenditions))
+	for _, rendition := range media.DefaultRenditions {
+		variant, keys, err := s.storeRendition(ctx, prefix+"/"+rendition.Name, media.Resize(img, rendition.MaxWidth))
+		stored = append(stored, keys...)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to store %s rendition: %w", rendition.Name, err)
+		}
+		variants[rendition.Name] = *variant
+	}
+
+	itemReq.ImageURL = original.URL
+	item := newSliderItem(sliderID, itemReq)
+	item.Variants = variants
+	if err := s.repo.CreateItem(ctx, item); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to create slider item: %w", err)
+	}
+
+	return s.itemWithImovel(ctx, item)
+}
+
+// storeRendition uploads the JPEG rendition and, when it is smaller, the WebP one. The keys of
+// the stored files are returned even on failure so they can be cleaned up.
+func (s *service) storeRendition(ctx context.Context, key string, img image.Image) (*imoveis.AnexoVariant, []string, error) {
+	encoded, err := media.EncodeRendition(img)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jpegObj, err := s.storage.Put(ctx, key+".jpg", bytes.NewReader(encoded.JPEG), int64(len(encoded.JPEG)), "image/jpeg")
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := []string{jpegObj.Key}
+
+	variant := &imoveis.AnexoVariant{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+		URL:    jpegObj.URL,
+	}
+	if encoded.WebP != nil {
+		webpObj, err := s.storage.Put(ctx, key+".webp", bytes.NewReader(encoded.WebP), int64(len(encoded.WebP)), "image/webp")
+		if err != nil {
+			return nil, keys, err
+		}
+		keys = append(keys, webpObj.Key)
+		variant.WebPURL = webpObj.URL
+	}
+	return variant, keys, nil
+}
@@ -0,0 +1,124 @@
+package sliders
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/storage"
+)
+
+func newUploadService(t *testing.T, sliders ...*Slider) (*service, string) {
+	t.Helper()
+	dir := t.TempDir()
+	svc := newPreviewService(time.Hour, sliders...)
+	svc.storage = storage.NewLocalStorage(dir, "https://cdn.example.com/uploads")
+	svc.maxUpload = defaultMaxUploadBytes
+	return svc, dir
+}
+
+func pngFile(t *testing.T, width, height int) *UploadedFile {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: uint8(x), A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return &UploadedFile{Size: int64(buf.Len()), Content: &buf}
+}
+
+// storedFiles lists the files written under dir
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestService_UploadSliderItem(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the image with its renditions", func(t *testing.T) {
+		svc, dir := newUploadService(t, &Slider{ID: 1, Location: "home", Enabled: true})
+
+		item, err := svc.UploadSliderItem(ctx, 1, pngFile(t, 2000, 500), &UploadSliderItemRequest{Order: 2, Titulo: "Lançamento"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(item.ImageURL, "https://cdn.example.com/uploads/sliders/1/"))
+		assert.True(t, strings.HasSuffix(item.ImageURL, ".png"))
+		assert.Equal(t, 2, item.Order)
+		assert.Equal(t, "Lançamento", item.Titulo)
+		assert.True(t, item.Enabled)
+
+		require.Len(t, item.Variants, 3)
+		assert.Equal(t, 320, item.Variants["thumbnail"].Width)
+		assert.Equal(t, 1600, item.Variants["large"].Width)
+		assert.Equal(t, 400, item.Variants["large"].Height)
+		assert.GreaterOrEqual(t, len(storedFiles(t, dir)), 4)
+	})
+
+	t.Run("rejects files that are not images", func(t *testing.T) {
+		svc, dir := newUploadService(t, &Slider{ID: 1, Location: "home"})
+
+		file := &UploadedFile{Size: 13, Content: strings.NewReader("%PDF-1.4 test")}
+		_, err := svc.UploadSliderItem(ctx, 1, file, &UploadSliderItemRequest{})
+		assert.ErrorIs(t, err, ErrUnsupportedFileType)
+		assert.Empty(t, storedFiles(t, dir))
+	})
+
+	t.Run("rejects files over the upload size", func(t *testing.T) {
+		svc, _ := newUploadService(t, &Slider{ID: 1, Location: "home"})
+		svc.maxUpload = 64
+
+		_, err := svc.UploadSliderItem(ctx, 1, pngFile(t, 200, 200), &UploadSliderItemRequest{})
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+	})
+
+	t.Run("checks the image against the slider limits before storing it", func(t *testing.T) {
+		svc, dir := newUploadService(t, &Slider{ID: 1, Location: "home", Type: SliderType_Slideshow})
+		svc.limits = config.SlidersConfig{Slideshow: config.SliderLimitsConfig{MinImageWidth: 1200}}
+
+		_, err := svc.UploadSliderItem(ctx, 1, pngFile(t, 800, 400), &UploadSliderItemRequest{})
+		var limitErr *LimitViolationError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Contains(t, limitErr.Violations, "image_url")
+		assert.Empty(t, storedFiles(t, dir))
+	})
+
+	t.Run("unknown slider", func(t *testing.T) {
+		svc, _ := newUploadService(t)
+
+		_, err := svc.UploadSliderItem(ctx, 9, pngFile(t, 10, 10), &UploadSliderItemRequest{})
+		assert.ErrorIs(t, err, ErrSliderNotFound)
+	})
+}
+
+func TestService_UpdateSliderItem_NewImageDropsVariants(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newUploadService(t, &Slider{ID: 1, Location: "home", Enabled: true})
+
+	uploaded, err := svc.UploadSliderItem(ctx, 1, pngFile(t, 400, 200), &UploadSliderItemRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, uploaded.Variants)
+
+	item, err := svc.UpdateSliderItem(ctx, uploaded.ID, &UpdateSliderItemRequest{ImageURL: "https://cdn/other.jpg"})
+	require.NoError(t, err)
+	assert.Empty(t, item.Variants)
+}
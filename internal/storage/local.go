@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLocalURL is the path the router serves the local storage directory under
+const DefaultLocalURL = "/uploads"
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage that writes files below dir, meant for development
+func NewLocalStorage(dir, baseURL string) Storage {
+	if dir == "" {
+		dir = "./uploads"
+	}
+	if baseURL == "" {
+		baseURL = DefaultLocalURL
+	}
+	return &localStorage{dir: dir, baseURL: baseURL}
+}
+
+// Put writes body to dir/key, creating intermediate directories
+func (s *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	written, err := io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return &Object{
+		Key:         key,
+		URL:         joinURL(s.baseURL, key),
+		Size:        written,
+		ContentType: contentType,
+	}, nil
+}
+
+// Get opens dir/key
+func (s *localStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete removes dir/key
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+// ErrInvalidKey is returned for object keys that are empty or try to escape the storage root
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Object describes a stored file
+type Object struct {
+	Key         string
+	URL         string
+	Size        int64
+	ContentType string
+}
+
+// Storage persists uploaded files and exposes them through a public URL
+type Storage interface {
+	// Put streams body to key. size is the exact body length, required by S3 to avoid buffering.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (*Object, error)
+	// Get opens key for reading; the caller closes the returned reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage selected by cfg.Driver
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL), nil
+	case "s3":
+		return NewS3Storage(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// cleanKey rejects keys that are empty, absolute or contain ".." segments
+func cleanKey(key string) (string, error) {
+	key = strings.TrimLeft(key, "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return key, nil
+}
+
+// joinURL appends key to a base URL
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
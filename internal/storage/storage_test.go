@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+)
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStorage(dir, "")
+	ctx := context.Background()
+
+	obj, err := store.Put(ctx, "imoveis/1/foto.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/imoveis/1/foto.jpg", obj.URL)
+	assert.Equal(t, int64(4), obj.Size)
+
+	content, err := os.ReadFile(filepath.Join(dir, "imoveis", "1", "foto.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(content))
+
+	reader, err := store.Get(ctx, "imoveis/1/foto.jpg")
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "jpeg", string(content))
+
+	require.NoError(t, store.Delete(ctx, "imoveis/1/foto.jpg"))
+	require.NoError(t, store.Delete(ctx, "imoveis/1/foto.jpg"), "deleting a missing key is not an error")
+
+	_, err = store.Put(ctx, "../escape.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestS3Storage_PutSignsAndStreams(t *testing.T) {
+	var gotPath, gotAuth, gotBody, gotContentType string
+	var gotLength int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		gotContentType = r.Header.Get("Content-Type")
+		gotLength = r.ContentLength
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		assert.Equal(t, unsignedPayload, r.Header.Get("X-Amz-Content-Sha256"))
+		assert.Equal(t, "20261017T120000Z", r.Header.Get("X-Amz-Date"))
+	}))
+	defer server.Close()
+
+	store := NewS3Storage(&config.StorageConfig{
+		Endpoint:     server.URL,
+		Region:       "us-east-1",
+		Bucket:       "anexos",
+		AccessKey:    "AKID",
+		SecretKey:    "secret",
+		UsePathStyle: true,
+		PublicURL:    "https://cdn.example.com",
+	}).(*s3Storage)
+	store.now = func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) }
+
+	obj, err := store.Put(context.Background(), "imoveis/1/planta baixa.pdf", strings.NewReader("%PDF"), 4, "application/pdf")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/anexos/imoveis/1/planta%20baixa.pdf", gotPath)
+	assert.Equal(t, "%PDF", gotBody)
+	assert.Equal(t, int64(4), gotLength)
+	assert.Equal(t, "application/pdf", gotContentType)
+	assert.True(t, strings.HasPrefix(gotAuth,
+		"AWS4-HMAC-SHA256 Credential=AKID/20261017/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+	assert.Equal(t, "https://cdn.example.com/imoveis/1/planta baixa.pdf", obj.URL)
+}
+
+func TestS3Storage_Get(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/anexos/imoveis/1/foto.jpg", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	defer server.Close()
+
+	store := NewS3Storage(&config.StorageConfig{Endpoint: server.URL, Region: "us-east-1", Bucket: "anexos", UsePathStyle: true})
+
+	reader, err := store.Get(context.Background(), "imoveis/1/foto.jpg")
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(content))
+}
+
+func TestS3Storage_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer server.Close()
+
+	store := NewS3Storage(&config.StorageConfig{Endpoint: server.URL, Region: "us-east-1", Bucket: "anexos", UsePathStyle: true})
+
+	_, err := store.Put(context.Background(), "a.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
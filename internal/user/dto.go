@@ -1,5 +1,7 @@
 package user
 
+import "github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+
 // RegisterRequest represents registration request payload
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
@@ -13,20 +15,33 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// UpdateUserRequest represents user update request payload
+// UpdateUserRequest represents user update request payload. Locale is the BCP 47 tag (pt-BR, en)
+// of the emails sent to the user.
 type UpdateUserRequest struct {
-	Name  string `json:"name" binding:"omitempty,min=2,max=100"`
-	Email string `json:"email" binding:"omitempty,email"`
+	Name   string `json:"name" binding:"omitempty,min=2,max=100"`
+	Email  string `json:"email" binding:"omitempty,email"`
+	Locale string `json:"locale" binding:"omitempty,max=35,bcp47_language_tag"`
+}
+
+// ChangePasswordRequest represents a password change of the authenticated user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
 // UserResponse represents user response (without sensitive fields)
 type UserResponse struct {
-	ID        uint     `json:"id"`
-	Name      string   `json:"name"`
-	Email     string   `json:"email"`
-	Roles     []string `json:"roles"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID               uint     `json:"id"`
+	Name             string   `json:"name"`
+	Email            string   `json:"email"`
+	Locale           string   `json:"locale,omitempty"`
+	CorretorID       *uint    `json:"corretor_id,omitempty"`
+	OrganizacaoID    *uint    `json:"organizacao_id,omitempty"`
+	TwoFactorEnabled bool     `json:"two_factor_enabled"`
+	Roles            []string `json:"roles"`
+	Permissions      []string `json:"permissions"`
+	CreatedAt        string   `json:"created_at"`
+	UpdatedAt        string   `json:"updated_at"`
 }
 
 // AuthResponse represents authentication response
@@ -38,6 +53,108 @@ type AuthResponse struct {
 	User         UserResponse `json:"user"`
 }
 
+// TwoFactorChallengeResponse is returned by login instead of tokens when the user has 2FA enabled;
+// the token is exchanged for a token pair at /auth/2fa/verify together with a code
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorToken    string `json:"two_factor_token"`
+	ExpiresIn         int64  `json:"expires_in"`
+}
+
+// VerifyTwoFactorRequest represents the second step of a login with 2FA
+type VerifyTwoFactorRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorCodeRequest represents a TOTP or recovery code confirming a 2FA change
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorEnrollResponse holds the secret to add to an authenticator app, also as an otpauth:// URI
+// to render as a QR code
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse holds the recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// AssignRoleRequest represents the role given to a user
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// LinkCorretorRequest represents the corretor principal a user works as; null unlinks the user
+type LinkCorretorRequest struct {
+	CorretorID *uint `json:"corretor_id"`
+}
+
+// AssignOrganizacaoRequest represents the organizacao a user belongs to; null makes the user unscoped
+type AssignOrganizacaoRequest struct {
+	OrganizacaoID *uint `json:"organizacao_id"`
+}
+
+// InviteUserRequest represents an invitation sent by an admin; the role defaults to user
+type InviteUserRequest struct {
+	Email         string `json:"email" binding:"required,email"`
+	Name          string `json:"name" binding:"omitempty,max=100"`
+	Role          string `json:"role"`
+	OrganizacaoID *uint  `json:"organizacao_id"`
+}
+
+// InvitationResponse represents a sent invitation
+type InvitationResponse struct {
+	ID            uint   `json:"id"`
+	Email         string `json:"email"`
+	Name          string `json:"name,omitempty"`
+	Role          string `json:"role"`
+	OrganizacaoID *uint  `json:"organizacao_id,omitempty"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
+// AcceptInvitationRequest represents the account set up by the invitee; the name defaults to the
+// one given in the invitation
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"omitempty,min=2,max=100"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// RoleResponse represents a role with the permissions it grants
+type RoleResponse struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// ToRoleResponse converts Role model to RoleResponse DTO
+func ToRoleResponse(role *Role) RoleResponse {
+	return RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: auth.PermissionsForRoles([]string{role.Name}),
+	}
+}
+
+// ToInvitationResponse converts an Invitation to InvitationResponse
+func ToInvitationResponse(invitation *Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:            invitation.ID,
+		Email:         invitation.Email,
+		Name:          invitation.Name,
+		Role:          invitation.Role,
+		OrganizacaoID: invitation.OrganizacaoID,
+		ExpiresAt:     invitation.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
 // LegacyAuthResponse represents legacy authentication response (deprecated)
 type LegacyAuthResponse struct {
 	Token string       `json:"token"`
@@ -56,11 +173,16 @@ type UserListResponse struct {
 // ToUserResponse converts User model to UserResponse DTO
 func ToUserResponse(user *User) UserResponse {
 	return UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Roles:     user.GetRoleNames(),
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		Locale:           user.Locale,
+		CorretorID:       user.CorretorID,
+		OrganizacaoID:    user.OrganizacaoID,
+		TwoFactorEnabled: user.TwoFactorEnabled(),
+		Roles:            user.GetRoleNames(),
+		Permissions:      auth.PermissionsForRoles(user.GetRoleNames()),
+		CreatedAt:        user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
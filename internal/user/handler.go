@@ -1,13 +1,22 @@
 package user
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
+	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
 
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/authaudit"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
 	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/middleware"
@@ -17,14 +26,46 @@ import (
 type Handler struct {
 	userService Service
 	authService auth.Service
+	google      OAuthProvider
+	auditLog    authaudit.Recorder
 }
 
+// HandlerOption configures optional handler behaviour
+type HandlerOption func(*Handler)
+
 // NewHandler creates a new user handler
-func NewHandler(userService Service, authService auth.Service) *Handler {
-	return &Handler{
+func NewHandler(userService Service, authService auth.Service, opts ...HandlerOption) *Handler {
+	h := &Handler{
 		userService: userService,
 		authService: authService,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithAuditLog records the logins, token refreshes, logouts, password and role changes handled
+func WithAuditLog(recorder authaudit.Recorder) HandlerOption {
+	return func(h *Handler) {
+		h.auditLog = recorder
+	}
+}
+
+// audit records an authentication event with the client of the request
+func (h *Handler) audit(c *gin.Context, event authaudit.Event) {
+	if h.auditLog == nil {
+		return
+	}
+	event.IPAddress = c.ClientIP()
+	event.UserAgent = c.Request.UserAgent()
+	h.auditLog.Record(c.Request.Context(), &event)
+}
+
+// tokenContext returns the request context carrying the device asking for tokens, which is
+// recorded with the session they belong to
+func tokenContext(c *gin.Context) context.Context {
+	return auth.WithClient(c.Request.Context(), c.Request.UserAgent(), c.ClientIP())
 }
 
 // Register godoc
@@ -56,7 +97,56 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authService.GenerateTokenPair(c.Request.Context(), user.ID, user.Email, user.Name)
+	tokenPair, err := h.authService.GenerateTokenPair(tokenContext(c), user.ID, user.Email, user.Name)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(AuthResponse{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		TokenType:    tokenPair.TokenType,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		User:         ToUserResponse(user),
+	}))
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Create the account of an invitee with the token of the invitation link and the chosen password; the role and organizacao are the ones of the invitation. Returns access and refresh tokens.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body AcceptInvitationRequest true "Invitation token, password and optional name"
+// @Success 200 {object} errors.Response{success=bool,data=AuthResponse} "Success response with user data and tokens"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error, invalid or expired invitation"
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Email already exists"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to create user or generate token"
+// @Router /api/v1/auth/invitations/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	user, err := h.userService.AcceptInvitation(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidInvitation):
+			_ = c.Error(apiErrors.BadRequest("Invalid or expired invitation"))
+		case errors.Is(err, ErrNameRequired):
+			_ = c.Error(apiErrors.BadRequest("Name is required"))
+		case errors.Is(err, ErrEmailExists):
+			_ = c.Error(apiErrors.Conflict("Email already exists"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	tokenPair, err := h.authService.GenerateTokenPair(tokenContext(c), user.ID, user.Email, user.Name)
 	if err != nil {
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
@@ -78,9 +168,10 @@ func (h *Handler) Register(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Login request"
-// @Success 200 {object} errors.Response{success=bool,data=AuthResponse} "Success response with user data and tokens"
+// @Success 200 {object} errors.Response{success=bool,data=AuthResponse} "Success response with user data and tokens, or a TwoFactorChallengeResponse when the user has 2FA enabled"
 // @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error"
 // @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid email or password"
+// @Failure 429 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Account or address locked out after too many failed logins"
 // @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to authenticate user or generate token"
 // @Router /api/v1/auth/login [post]
 func (h *Handler) Login(c *gin.Context) {
@@ -90,22 +181,121 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.AuthenticateUser(c.Request.Context(), req)
+	user, err := h.userService.AuthenticateUser(tokenContext(c), req)
 	if err != nil {
 		if errors.Is(err, ErrInvalidCredentials) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeLoginFailed, Email: req.Email, Detail: "invalid_credentials"})
 			_ = c.Error(apiErrors.Unauthorized("Invalid email or password"))
 			return
 		}
+		var locked *auth.LockoutError
+		if errors.As(err, &locked) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeLoginFailed, Email: req.Email, Detail: "locked"})
+			lockedOut(c, locked)
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	h.loginResponse(c, user, "password")
+}
+
+// lockedOut answers a login refused by the login limiter with 429 and Retry-After
+func lockedOut(c *gin.Context, locked *auth.LockoutError) {
+	retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	_ = c.Error(apiErrors.TooManyRequests(retryAfter))
+}
+
+// loginResponse answers a successful first login step, through method, with a token pair, or
+// with a two-factor challenge when the user has 2FA enabled
+func (h *Handler) loginResponse(c *gin.Context, user *User, method string) {
+	if user.TwoFactorEnabled() {
+		challenge, err := h.authService.GenerateTwoFactorToken(user.ID)
+		if err != nil {
+			_ = c.Error(apiErrors.InternalServerError(err))
+			return
+		}
+		c.JSON(http.StatusOK, apiErrors.Success(TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			TwoFactorToken:    challenge,
+			ExpiresIn:         int64(auth.TwoFactorChallengeTTL.Seconds()),
+		}))
+		return
+	}
+
+	tokenPair, err := h.authService.GenerateTokenPair(tokenContext(c), user.ID, user.Email, user.Name)
+	if err != nil {
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
+	h.audit(c, authaudit.Event{Type: authaudit.TypeLoginSucceeded, UserID: &user.ID, Email: user.Email, Detail: method})
+
+	c.JSON(http.StatusOK, apiErrors.Success(AuthResponse{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		TokenType:    tokenPair.TokenType,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		User:         ToUserResponse(user),
+	}))
+}
+
+// VerifyTwoFactor godoc
+// @Summary Complete a login with 2FA
+// @Description Exchange the two-factor token returned by login and a TOTP or recovery code for a token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyTwoFactorRequest true "Two-factor token and code"
+// @Success 200 {object} errors.Response{success=bool,data=AuthResponse} "Success response with user data and tokens"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid or expired two-factor token, or invalid code"
+// @Failure 429 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Account locked out after too many failed attempts"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to generate token"
+// @Router /api/v1/auth/2fa/verify [post]
+func (h *Handler) VerifyTwoFactor(c *gin.Context) {
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
 
-	tokenPair, err := h.authService.GenerateTokenPair(c.Request.Context(), user.ID, user.Email, user.Name)
+	userID, err := h.authService.ValidateTwoFactorToken(req.TwoFactorToken)
 	if err != nil {
+		_ = c.Error(apiErrors.Unauthorized("Invalid or expired two-factor token"))
+		return
+	}
+
+	user, err := h.userService.VerifyTwoFactorLogin(tokenContext(c), req.TwoFactorToken, userID, req.Code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) || errors.Is(err, ErrTwoFactorNotEnabled) || errors.Is(err, ErrUserNotFound) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeLoginFailed, UserID: &userID, Detail: "invalid_two_factor_code"})
+			_ = c.Error(apiErrors.Unauthorized("Invalid two-factor code"))
+			return
+		}
+		if errors.Is(err, auth.ErrChallengeExhausted) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeLoginFailed, UserID: &userID, Detail: "two_factor_challenge_exhausted"})
+			_ = c.Error(apiErrors.Unauthorized("Invalid or expired two-factor token"))
+			return
+		}
+		var locked *auth.LockoutError
+		if errors.As(err, &locked) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeLoginFailed, UserID: &userID, Detail: "locked"})
+			lockedOut(c, locked)
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
 
+	tokenPair, err := h.authService.GenerateTokenPair(auth.WithTwoFactor(tokenContext(c)), user.ID, user.Email, user.Name)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	h.audit(c, authaudit.Event{Type: authaudit.TypeLoginSucceeded, UserID: &user.ID, Email: user.Email, Detail: "two_factor"})
+
 	c.JSON(http.StatusOK, apiErrors.Success(AuthResponse{
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
@@ -115,6 +305,173 @@ func (h *Handler) Login(c *gin.Context) {
 	}))
 }
 
+// EnrollTwoFactor godoc
+// @Summary Start 2FA enrollment
+// @Description Generate a TOTP secret for the current user, returned with its otpauth:// provisioning URI to show as a QR code. 2FA is enabled once a code is confirmed.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=TwoFactorEnrollResponse} "TOTP secret and provisioning URI"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Two-factor authentication already enabled"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to enroll"
+// @Router /api/v1/auth/2fa/enroll [post]
+func (h *Handler) EnrollTwoFactor(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	secret, uri, err := h.userService.EnrollTwoFactor(c.Request.Context(), userID)
+	if err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(TwoFactorEnrollResponse{Secret: secret, ProvisioningURI: uri}))
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Enable 2FA
+// @Description Enable 2FA with a code of the enrolled secret and return the recovery codes, shown only once. Sign in again to get tokens that pass the two-factor policy.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} errors.Response{success=bool,data=RecoveryCodesResponse} "Recovery codes"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error, invalid code or enrollment not started"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Two-factor authentication already enabled"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to enable 2FA"
+// @Router /api/v1/auth/2fa/confirm [post]
+func (h *Handler) ConfirmTwoFactor(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	codes, err := h.userService.ConfirmTwoFactor(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(RecoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable 2FA
+// @Description Disable 2FA with a TOTP or recovery code. Every session of the user is revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} errors.Response{success=bool,data=object} "Two-factor authentication disabled"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error, invalid code or 2FA not enabled"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to disable 2FA"
+// @Router /api/v1/auth/2fa/disable [post]
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	if err := h.userService.DisableTwoFactor(c.Request.Context(), userID, req.Code); err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+	if err := h.authService.RevokeAllUserTokens(c.Request.Context(), userID); err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(gin.H{"message": "Two-factor authentication disabled"}))
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the current user. Every session of the user is revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} errors.Response{success=bool,data=object} "Password changed"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error or wrong current password"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "User not found"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to change password"
+// @Router /api/v1/auth/password [put]
+func (h *Handler) ChangePassword(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	user, err := h.userService.ChangePassword(c.Request.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidCredentials):
+			_ = c.Error(apiErrors.BadRequest("Current password is incorrect"))
+		case errors.Is(err, ErrUserNotFound):
+			_ = c.Error(apiErrors.NotFound("User not found"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+	if err := h.authService.RevokeAllUserTokens(c.Request.Context(), userID); err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	h.audit(c, authaudit.Event{Type: authaudit.TypePasswordChanged, UserID: &user.ID, Email: user.Email})
+
+	c.JSON(http.StatusOK, apiErrors.Success(gin.H{"message": "Password changed"}))
+}
+
+// twoFactorError maps the errors of the 2FA management endpoints
+func (h *Handler) twoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		_ = c.Error(apiErrors.NotFound("User not found"))
+	case errors.Is(err, ErrTwoFactorEnabled):
+		_ = c.Error(apiErrors.Conflict("Two-factor authentication already enabled"))
+	case errors.Is(err, ErrTwoFactorNotEnabled):
+		_ = c.Error(apiErrors.BadRequest("Two-factor authentication not enabled"))
+	case errors.Is(err, ErrTwoFactorNotEnrolled):
+		_ = c.Error(apiErrors.BadRequest("Two-factor enrollment not started"))
+	case errors.Is(err, ErrInvalidTwoFactorCode):
+		_ = c.Error(apiErrors.BadRequest("Invalid two-factor code"))
+	default:
+		_ = c.Error(apiErrors.InternalServerError(err))
+	}
+}
+
 // GetUser godoc
 // @Summary Get user by ID
 // @Description Get a user by their ID (requires authentication)
@@ -157,7 +514,7 @@ func (h *Handler) GetUser(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update user information (requires authentication)
+// @Description Update user information (requires authentication). locale (BCP 47, e.g. pt-BR or en) selects the language of the emails sent to the user.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -202,6 +559,10 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 			_ = c.Error(apiErrors.Conflict("Email already exists"))
 			return
 		}
+		if errors.Is(err, ErrInvalidLocale) {
+			_ = c.Error(apiErrors.BadRequest("Invalid locale"))
+			return
+		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
@@ -270,23 +631,32 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authService.RefreshAccessToken(c.Request.Context(), req.RefreshToken)
+	tokenPair, err := h.authService.RefreshAccessToken(tokenContext(c), req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrExpiredToken) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeTokenRefreshFailed, Detail: "invalid_token"})
 			_ = c.Error(apiErrors.Unauthorized("Invalid or expired refresh token"))
 			return
 		}
 		if errors.Is(err, auth.ErrTokenReuse) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeTokenRefreshFailed, Detail: "token_reuse"})
 			_ = c.Error(apiErrors.Forbidden("Token reuse detected. All tokens have been revoked for security."))
 			return
 		}
 		if errors.Is(err, auth.ErrTokenRevoked) {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeTokenRefreshFailed, Detail: "token_revoked"})
 			_ = c.Error(apiErrors.Unauthorized("Token has been revoked"))
 			return
 		}
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
+	if h.auditLog != nil {
+		// The refresh token does not tell whose it is; the access token it was exchanged for does
+		if claims, err := h.authService.ValidateToken(tokenPair.AccessToken); err == nil {
+			h.audit(c, authaudit.Event{Type: authaudit.TypeTokenRefreshed, UserID: &claims.UserID, Email: claims.Email})
+		}
+	}
 
 	c.JSON(http.StatusOK, apiErrors.Success(auth.TokenPairResponse{
 		AccessToken:  tokenPair.AccessToken,
@@ -331,10 +701,82 @@ func (h *Handler) Logout(c *gin.Context) {
 		_ = c.Error(apiErrors.InternalServerError(err))
 		return
 	}
+	h.audit(c, authaudit.Event{Type: authaudit.TypeLogout, UserID: &userID, Email: contextutil.GetEmail(c)})
 
 	c.JSON(http.StatusOK, apiErrors.Success(gin.H{"message": "Successfully logged out"}))
 }
 
+// ListSessions godoc
+// @Summary List active sessions
+// @Description List the devices signed in to the current user's account, most recently used first; current marks the session of the request's access token
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=[]auth.Session} "Success response with the active sessions"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to list sessions"
+// @Router /api/v1/auth/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	claims := contextutil.GetUser(c)
+	if claims == nil || claims.UserID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), claims.UserID)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	if sessions == nil {
+		sessions = []auth.Session{}
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == claims.SessionID
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(sessions))
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Revoke every refresh token of one of the current user's sessions, signing that device out once its access token expires
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} errors.Response{success=bool,data=object} "Session revoked"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid session ID"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Unauthorized"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Session not found"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to revoke session"
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	userID := contextutil.GetUserID(c)
+	if userID == 0 {
+		_ = c.Error(apiErrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid session ID"))
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			_ = c.Error(apiErrors.NotFound("Session not found"))
+			return
+		}
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	h.audit(c, authaudit.Event{Type: authaudit.TypeLogout, UserID: &userID, Email: contextutil.GetEmail(c), Detail: "session " + sessionID.String()})
+
+	c.JSON(http.StatusOK, apiErrors.Success(gin.H{"message": "Session revoked"}))
+}
+
 // GetMe godoc
 // @Summary Get current user
 // @Description Get the currently authenticated user's information with roles
@@ -375,7 +817,7 @@ func (h *Handler) GetMe(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page (max 100)" default(20)
-// @Param role query string false "Filter by role (user or admin)"
+// @Param role query string false "Filter by role (user, admin, gestor, corretor or marketing)"
 // @Param search query string false "Search by name or email"
 // @Param sort query string false "Sort by field (created_at, updated_at, name, email)" default(created_at)
 // @Param order query string false "Sort order (asc or desc)" default(desc)
@@ -418,3 +860,360 @@ func (h *Handler) ListUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, apiErrors.Success(response))
 }
+
+// ListRoles godoc
+// @Summary List roles (Admin only)
+// @Description List the roles that can be assigned to users, with the permissions each one grants
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=[]RoleResponse} "Success response with roles"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to list roles"
+// @Router /api/v1/admin/roles [get]
+func (h *Handler) ListRoles(c *gin.Context) {
+	roles, err := h.userService.ListRoles(c.Request.Context())
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	responses := make([]RoleResponse, len(roles))
+	for i := range roles {
+		responses[i] = ToRoleResponse(&roles[i])
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(responses))
+}
+
+// AssignRole godoc
+// @Summary Assign a role to a user (Admin only)
+// @Description Give a role to a user. The new permissions apply to the access tokens issued afterwards.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body AssignRoleRequest true "Role to assign"
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=UserResponse} "Success response with the user's roles"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid user ID, validation error or unknown role"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "User not found"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to assign role"
+// @Router /api/v1/admin/users/{id}/roles [post]
+func (h *Handler) AssignRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	user, err := h.userService.AssignRole(c.Request.Context(), uint(id), req.Role)
+	if err != nil {
+		_ = c.Error(roleError(err))
+		return
+	}
+	h.auditRoleChange(c, authaudit.TypeRoleAssigned, user, req.Role)
+
+	c.JSON(http.StatusOK, apiErrors.Success(ToUserResponse(user)))
+}
+
+// RemoveRole godoc
+// @Summary Remove a role from a user (Admin only)
+// @Description Take a role from a user. The user role, and the admin role of the requesting admin, cannot be removed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role path string true "Role name"
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=UserResponse} "Success response with the user's roles"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid user ID or unknown role"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "User not found"
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Role cannot be removed"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to remove role"
+// @Router /api/v1/admin/users/{id}/roles/{role} [delete]
+func (h *Handler) RemoveRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	user, err := h.userService.RemoveRole(c.Request.Context(), uint(id), c.Param("role"))
+	if err != nil {
+		_ = c.Error(roleError(err))
+		return
+	}
+	h.auditRoleChange(c, authaudit.TypeRoleRemoved, user, c.Param("role"))
+
+	c.JSON(http.StatusOK, apiErrors.Success(ToUserResponse(user)))
+}
+
+// auditRoleChange records the role change of user made by the requesting admin
+func (h *Handler) auditRoleChange(c *gin.Context, eventType string, user *User, role string) {
+	actorID := contextutil.GetUserID(c)
+	h.audit(c, authaudit.Event{Type: eventType, UserID: &user.ID, ActorID: &actorID, Email: user.Email, Detail: role})
+}
+
+// roleError maps the errors of a role change to API errors
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		return apiErrors.NotFound("User not found")
+	case errors.Is(err, ErrRoleNotFound):
+		return apiErrors.BadRequest("Role not found")
+	case errors.Is(err, ErrProtectedRole):
+		return apiErrors.Conflict("Role cannot be removed")
+	default:
+		return apiErrors.InternalServerError(err)
+	}
+}
+
+// LinkCorretor godoc
+// @Summary Link a user to a corretor principal (Admin only)
+// @Description Set the corretor principal the user works as; null unlinks the user. Users with the corretor role can only change the imoveis of their corretor, from their next access token.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body LinkCorretorRequest true "Corretor principal"
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=UserResponse} "Success response with the linked corretor"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid user ID, validation error or unknown corretor"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "User not found"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to link corretor"
+// @Router /api/v1/admin/users/{id}/corretor [put]
+func (h *Handler) LinkCorretor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req LinkCorretorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	user, err := h.userService.LinkCorretor(c.Request.Context(), uint(id), req.CorretorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUserNotFound):
+			_ = c.Error(apiErrors.NotFound("User not found"))
+		case errors.Is(err, ErrCorretorNotFound):
+			_ = c.Error(apiErrors.BadRequest("Corretor not found"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(ToUserResponse(user)))
+}
+
+// AssignOrganizacao godoc
+// @Summary Assign a user to an organizacao (Admin only)
+// @Description Set the organizacao the user belongs to; null makes the user unscoped. From their next access token the user only sees the imoveis, sliders and emails of the organizacao.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body AssignOrganizacaoRequest true "Organizacao"
+// @Security BearerAuth
+// @Success 200 {object} errors.Response{success=bool,data=UserResponse} "Success response with the assigned organizacao"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid user ID, validation error or unknown organizacao"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "User not found"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to assign organizacao"
+// @Router /api/v1/admin/users/{id}/organizacao [put]
+func (h *Handler) AssignOrganizacao(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apiErrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req AssignOrganizacaoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	user, err := h.userService.AssignOrganizacao(c.Request.Context(), uint(id), req.OrganizacaoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUserNotFound):
+			_ = c.Error(apiErrors.NotFound("User not found"))
+		case errors.Is(err, ErrOrganizacaoNotFound):
+			_ = c.Error(apiErrors.BadRequest("Organizacao not found"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, apiErrors.Success(ToUserResponse(user)))
+}
+
+// InviteUser godoc
+// @Summary Invite a user (Admin only)
+// @Description Email a signed link through which the invitee sets their password and creates their account with the given role (user by default) and organizacao. Without an organizacao the invitee joins the organizacao of the request.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body InviteUserRequest true "Invitee, role and organizacao"
+// @Security BearerAuth
+// @Success 201 {object} errors.Response{success=bool,data=InvitationResponse} "Success response with the sent invitation"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Validation error, unknown role or organizacao"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Admin access required"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invitations not configured"
+// @Failure 409 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Email already exists"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to send invitation"
+// @Router /api/v1/admin/users/invite [post]
+func (h *Handler) InviteUser(c *gin.Context) {
+	var req InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apiErrors.FromGinValidation(err))
+		return
+	}
+
+	invitation, err := h.userService.InviteUser(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvitationsDisabled):
+			_ = c.Error(apiErrors.NotFound("Invitations not configured"))
+		case errors.Is(err, ErrRoleNotFound):
+			_ = c.Error(apiErrors.BadRequest("Role not found"))
+		case errors.Is(err, ErrOrganizacaoNotFound):
+			_ = c.Error(apiErrors.BadRequest("Organizacao not found"))
+		case errors.Is(err, ErrEmailExists):
+			_ = c.Error(apiErrors.Conflict("Email already exists"))
+		default:
+			_ = c.Error(apiErrors.InternalServerError(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiErrors.Success(ToInvitationResponse(invitation)))
+}
+
+const (
+	// googleStateCookie keeps the state and PKCE verifier of a Google sign-in until its callback
+	googleStateCookie = "google_oauth"
+	// googleStatePath scopes the state cookie to the Google sign-in endpoints
+	googleStatePath = "/api/v1/auth/google"
+	// googleStateTTL bounds the time the user has to complete the Google consent
+	googleStateTTL = 10 * time.Minute
+)
+
+// OAuthProvider runs the authorization-code flow of an identity provider
+type OAuthProvider interface {
+	AuthCodeURL(state, verifier string) string
+	Exchange(ctx context.Context, code, verifier string) (*auth.OAuthProfile, error)
+}
+
+// WithGoogleOAuth enables the Google sign-in through provider
+func WithGoogleOAuth(provider OAuthProvider) HandlerOption {
+	return func(h *Handler) {
+		h.google = provider
+	}
+}
+
+// GoogleLogin godoc
+// @Summary Sign in with Google
+// @Description Redirect to the Google consent page; Google then redirects to /auth/google/callback
+// @Tags auth
+// @Success 302 "Redirect to Google"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Google login not configured"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to start the sign-in"
+// @Router /api/v1/auth/google [get]
+func (h *Handler) GoogleLogin(c *gin.Context) {
+	if h.google == nil {
+		_ = c.Error(apiErrors.NotFound("Google login not configured"))
+		return
+	}
+
+	state, err := randomPassword()
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(googleStateCookie, state+"."+verifier, int(googleStateTTL.Seconds()), googleStatePath, "", true, true)
+	c.Redirect(http.StatusFound, h.google.AuthCodeURL(state, verifier))
+}
+
+// GoogleCallback godoc
+// @Summary Complete a sign-in with Google
+// @Description Exchange the authorization code sent by Google for a token pair, as password login does. The Google account is linked to the user with its verified email, or to a new user.
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State set by /auth/google"
+// @Success 200 {object} errors.Response{success=bool,data=AuthResponse} "Success response with user data and tokens, or a TwoFactorChallengeResponse when the user has 2FA enabled"
+// @Failure 400 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Missing authorization code"
+// @Failure 401 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Invalid state, or sign-in refused or failed at Google"
+// @Failure 403 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Email not verified or domain not allowed"
+// @Failure 404 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Google login not configured"
+// @Failure 500 {object} errors.Response{success=bool,error=errors.ErrorInfo} "Failed to sign in or generate token"
+// @Router /api/v1/auth/google/callback [get]
+func (h *Handler) GoogleCallback(c *gin.Context) {
+	if h.google == nil {
+		_ = c.Error(apiErrors.NotFound("Google login not configured"))
+		return
+	}
+
+	// The state cookie is used once
+	cookie, err := c.Cookie(googleStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(googleStateCookie, "", -1, googleStatePath, "", true, true)
+	state, verifier, ok := strings.Cut(cookie, ".")
+	if err != nil || !ok || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		_ = c.Error(apiErrors.Unauthorized("Invalid OAuth state"))
+		return
+	}
+	if c.Query("error") != "" {
+		_ = c.Error(apiErrors.Unauthorized("Google sign-in was refused"))
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		_ = c.Error(apiErrors.BadRequest("Missing authorization code"))
+		return
+	}
+
+	profile, err := h.google.Exchange(c.Request.Context(), code, verifier)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrOAuthEmailNotVerified):
+			_ = c.Error(apiErrors.Forbidden("Google account email is not verified"))
+		case errors.Is(err, auth.ErrOAuthDomainNotAllowed):
+			_ = c.Error(apiErrors.Forbidden("Email domain not allowed"))
+		default:
+			slog.Warn("Google sign-in failed", "error", err)
+			_ = c.Error(apiErrors.Unauthorized("Google sign-in failed"))
+		}
+		return
+	}
+
+	user, err := h.userService.AuthenticateOAuth(c.Request.Context(), *profile)
+	if err != nil {
+		_ = c.Error(apiErrors.InternalServerError(err))
+		return
+	}
+
+	h.loginResponse(c, user, auth.ProviderGoogle)
+}
@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -345,3 +347,174 @@ func TestHandler_Logout(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	current, other := uuid.New(), uuid.New()
+
+	t.Run("lists sessions marking the current one", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("ListSessions", mock.Anything, uint(1)).Return([]auth.Session{{ID: other}, {ID: current}}, nil)
+
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil)
+		c.Set(auth.KeyUser, &auth.Claims{UserID: 1, SessionID: current.String()})
+		handler := &Handler{authService: mockAuthService}
+		handler.ListSessions(c)
+		apiErrors.ErrorHandler()(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data []auth.Session `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Data, 2) {
+			assert.False(t, response.Data[0].Current)
+			assert.True(t, response.Data[1].Current)
+		}
+		mockAuthService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name           string
+		id             string
+		setupMocks     func(*MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "revokes the session",
+			id:   other.String(),
+			setupMocks: func(mas *MockAuthService) {
+				mas.On("RevokeSession", mock.Anything, uint(1), other).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "session of another user",
+			id:   other.String(),
+			setupMocks: func(mas *MockAuthService) {
+				mas.On("RevokeSession", mock.Anything, uint(1), other).Return(auth.ErrSessionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "invalid session ID", id: "abc", setupMocks: func(mas *MockAuthService) {}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			mockAuthService := new(MockAuthService)
+			tt.setupMocks(mockAuthService)
+
+			c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(auth.KeyUser, &auth.Claims{UserID: 1})
+			handler := &Handler{authService: mockAuthService}
+			handler.RevokeSession(c)
+			apiErrors.ErrorHandler()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_TwoFactorLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	enabledAt := time.Now()
+	user := &User{ID: 1, Name: "Ana", Email: "ana@example.com", TwoFactorAt: &enabledAt}
+
+	t.Run("login returns a challenge instead of tokens", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		mockService := new(MockService)
+		mockAuthService := new(MockAuthService)
+		mockService.On("AuthenticateUser", mock.Anything, mock.AnythingOfType("user.LoginRequest")).Return(user, nil)
+		mockAuthService.On("GenerateTwoFactorToken", uint(1)).Return("challenge", nil)
+
+		body, _ := json.Marshal(LoginRequest{Email: "ana@example.com", Password: "password123"})
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		NewHandler(mockService, mockAuthService).Login(c)
+		apiErrors.ErrorHandler()(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, true, response.Data["two_factor_required"])
+		assert.Equal(t, "challenge", response.Data["two_factor_token"])
+		assert.NotContains(t, response.Data, "access_token")
+		mockAuthService.AssertNotCalled(t, "GenerateTokenPair")
+	})
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*MockService, *MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "valid code issues a 2FA token pair",
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				mas.On("ValidateTwoFactorToken", "challenge").Return(uint(1), nil)
+				ms.On("VerifyTwoFactorLogin", mock.Anything, "challenge", uint(1), "123456").Return(user, nil)
+				mas.On("GenerateTokenPair", mock.Anything, uint(1), "ana@example.com", "Ana").Return(&auth.TokenPair{AccessToken: "access"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid code",
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				mas.On("ValidateTwoFactorToken", "challenge").Return(uint(1), nil)
+				ms.On("VerifyTwoFactorLogin", mock.Anything, "challenge", uint(1), "123456").Return(nil, ErrInvalidTwoFactorCode)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "challenge invalidated after too many codes",
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				mas.On("ValidateTwoFactorToken", "challenge").Return(uint(1), nil)
+				ms.On("VerifyTwoFactorLogin", mock.Anything, "challenge", uint(1), "123456").Return(nil, auth.ErrChallengeExhausted)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "account locked out",
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				mas.On("ValidateTwoFactorToken", "challenge").Return(uint(1), nil)
+				ms.On("VerifyTwoFactorLogin", mock.Anything, "challenge", uint(1), "123456").Return(nil, &auth.LockoutError{RetryAfter: time.Minute})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "expired challenge",
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				mas.On("ValidateTwoFactorToken", "challenge").Return(uint(0), auth.ErrExpiredToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			mockService := new(MockService)
+			mockAuthService := new(MockAuthService)
+			tt.setupMocks(mockService, mockAuthService)
+
+			body, _ := json.Marshal(VerifyTwoFactorRequest{TwoFactorToken: "challenge", Code: "123456"})
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa/verify", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			NewHandler(mockService, mockAuthService).VerifyTwoFactor(c)
+			apiErrors.ErrorHandler()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
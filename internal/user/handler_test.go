@@ -7,13 +7,17 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/authaudit"
 	apiErrors "github.com/vahiiiid/go-rest-api-boilerplate/internal/errors"
 )
 
@@ -66,6 +70,29 @@ func (m *MockAuthService) RevokeAllUserTokens(ctx context.Context, userID uint)
 	return args.Error(0)
 }
 
+func (m *MockAuthService) ListSessions(ctx context.Context, userID uint) ([]auth.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]auth.Session), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID uint, sessionID uuid.UUID) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GenerateTwoFactorToken(userID uint) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateTwoFactorToken(tokenString string) (uint, error) {
+	args := m.Called(tokenString)
+	return args.Get(0).(uint), args.Error(1)
+}
+
 func TestHandler_Register(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -520,6 +547,26 @@ func TestHandler_Login(t *testing.T) {
 				assert.Equal(t, "Invalid email or password", errorInfo["message"])
 			},
 		},
+		{
+			name: "account locked out",
+			requestBody: LoginRequest{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("AuthenticateUser", mock.Anything, mock.AnythingOfType("user.LoginRequest")).Return(nil, &auth.LockoutError{RetryAfter: 89500 * time.Millisecond})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "90", w.Header().Get("Retry-After"))
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				errorInfo, ok := response["error"].(map[string]interface{})
+				assert.True(t, ok, "error should be a map")
+				assert.Equal(t, float64(90), errorInfo["retry_after"])
+			},
+		},
 		{
 			name: "service error",
 			requestBody: LoginRequest{
@@ -1181,3 +1228,358 @@ func TestHandler_ListUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Invitations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		invite         bool
+		requestBody    interface{}
+		setupMocks     func(*MockService, *MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:        "invite sends the invitation",
+			invite:      true,
+			requestBody: InviteUserRequest{Email: "ana@example.com", Role: RoleAdmin},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("InviteUser", mock.Anything, InviteUserRequest{Email: "ana@example.com", Role: RoleAdmin}).
+					Return(&Invitation{ID: 3, Email: "ana@example.com", Role: RoleAdmin, ExpiresAt: time.Now()}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "invite without email",
+			invite:      true,
+			requestBody: InviteUserRequest{Role: RoleAdmin},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "invite a registered email",
+			invite:      true,
+			requestBody: InviteUserRequest{Email: "ana@example.com"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("InviteUser", mock.Anything, mock.AnythingOfType("user.InviteUserRequest")).Return(nil, ErrEmailExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "invitations not configured",
+			invite:      true,
+			requestBody: InviteUserRequest{Email: "ana@example.com"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("InviteUser", mock.Anything, mock.AnythingOfType("user.InviteUserRequest")).Return(nil, ErrInvitationsDisabled)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "accept issues a token pair",
+			requestBody: AcceptInvitationRequest{Token: "token", Password: "password123"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("AcceptInvitation", mock.Anything, mock.AnythingOfType("user.AcceptInvitationRequest")).
+					Return(&User{ID: 5, Name: "Ana", Email: "ana@example.com"}, nil)
+				mas.On("GenerateTokenPair", mock.Anything, uint(5), "ana@example.com", "Ana").Return(&auth.TokenPair{AccessToken: "access"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "accept an expired invitation",
+			requestBody: AcceptInvitationRequest{Token: "token", Password: "password123"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("AcceptInvitation", mock.Anything, mock.AnythingOfType("user.AcceptInvitationRequest")).Return(nil, ErrInvalidInvitation)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			mockService := new(MockService)
+			mockAuthService := new(MockAuthService)
+			tt.setupMocks(mockService, mockAuthService)
+
+			body, _ := json.Marshal(tt.requestBody)
+			handler := NewHandler(mockService, mockAuthService)
+			if tt.invite {
+				c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/invite", bytes.NewBuffer(body))
+				c.Request.Header.Set("Content-Type", "application/json")
+				handler.InviteUser(c)
+			} else {
+				c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/invitations/accept", bytes.NewBuffer(body))
+				c.Request.Header.Set("Content-Type", "application/json")
+				handler.AcceptInvitation(c)
+			}
+			apiErrors.ErrorHandler()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+type fakeOAuthProvider struct {
+	profile *auth.OAuthProfile
+	err     error
+}
+
+func (f *fakeOAuthProvider) AuthCodeURL(state, verifier string) string {
+	return "https://accounts.example.com/auth?state=" + state
+}
+
+func (f *fakeOAuthProvider) Exchange(_ context.Context, code, verifier string) (*auth.OAuthProfile, error) {
+	return f.profile, f.err
+}
+
+func TestHandler_GoogleLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	profile := &auth.OAuthProfile{Provider: auth.ProviderGoogle, Subject: "g-1", Email: "ana@example.com", Name: "Ana"}
+
+	// start runs /auth/google and returns the state cookie it set
+	start := func(t *testing.T, handler *Handler) *http.Cookie {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/google", nil)
+		handler.GoogleLogin(c)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.True(t, cookies[0].HttpOnly)
+			assert.True(t, cookies[0].Secure)
+			return cookies[0]
+		}
+		return nil
+	}
+	callback := func(handler *Handler, cookie *http.Cookie, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?"+query, nil)
+		if cookie != nil {
+			c.Request.AddCookie(cookie)
+		}
+		handler.GoogleCallback(c)
+		apiErrors.ErrorHandler()(c)
+		return w
+	}
+	stateOf := func(cookie *http.Cookie) string {
+		state, _, _ := strings.Cut(cookie.Value, ".")
+		return state
+	}
+
+	t.Run("issues a token pair", func(t *testing.T) {
+		mockService := new(MockService)
+		mockAuthService := new(MockAuthService)
+		mockService.On("AuthenticateOAuth", mock.Anything, *profile).Return(&User{ID: 1, Name: "Ana", Email: "ana@example.com"}, nil)
+		mockAuthService.On("GenerateTokenPair", mock.Anything, uint(1), "ana@example.com", "Ana").Return(&auth.TokenPair{AccessToken: "access"}, nil)
+		handler := NewHandler(mockService, mockAuthService, WithGoogleOAuth(&fakeOAuthProvider{profile: profile}))
+
+		cookie := start(t, handler)
+		w := callback(handler, cookie, "code=abc&state="+stateOf(cookie))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"access_token":"access"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		mockService := new(MockService)
+		handler := NewHandler(mockService, new(MockAuthService), WithGoogleOAuth(&fakeOAuthProvider{profile: profile}))
+
+		cookie := start(t, handler)
+		w := callback(handler, cookie, "code=abc&state=forged")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertNotCalled(t, "AuthenticateOAuth")
+	})
+
+	t.Run("missing state cookie", func(t *testing.T) {
+		handler := NewHandler(new(MockService), new(MockAuthService), WithGoogleOAuth(&fakeOAuthProvider{profile: profile}))
+
+		w := callback(handler, nil, "code=abc&state=abc")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("domain not allowed", func(t *testing.T) {
+		handler := NewHandler(new(MockService), new(MockAuthService), WithGoogleOAuth(&fakeOAuthProvider{err: auth.ErrOAuthDomainNotAllowed}))
+
+		cookie := start(t, handler)
+		w := callback(handler, cookie, "code=abc&state="+stateOf(cookie))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/google", nil)
+		NewHandler(new(MockService), new(MockAuthService)).GoogleLogin(c)
+		apiErrors.ErrorHandler()(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		setupMocks     func(*MockService, *MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:        "changes the password and revokes the sessions",
+			requestBody: ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("ChangePassword", mock.Anything, uint(1), ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"}).
+					Return(&User{ID: 1, Email: "john@example.com"}, nil)
+				mas.On("RevokeAllUserTokens", mock.Anything, uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "wrong current password",
+			requestBody: ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "newpassword"},
+			setupMocks: func(ms *MockService, mas *MockAuthService) {
+				ms.On("ChangePassword", mock.Anything, uint(1), mock.AnythingOfType("user.ChangePasswordRequest")).Return(nil, ErrInvalidCredentials)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "new password too short",
+			requestBody:    ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "123"},
+			setupMocks:     func(ms *MockService, mas *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockAuthService := new(MockAuthService)
+			tt.setupMocks(mockService, mockAuthService)
+			recorder := &recordingAuditLog{}
+			handler := NewHandler(mockService, mockAuthService, WithAuditLog(recorder))
+
+			body, _ := json.Marshal(tt.requestBody)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/auth/password", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(auth.KeyUser, &auth.Claims{UserID: 1})
+
+			handler.ChangePassword(c)
+			apiErrors.ErrorHandler()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				if assert.Len(t, recorder.events, 1) {
+					assert.Equal(t, authaudit.TypePasswordChanged, recorder.events[0].Type)
+				}
+			} else {
+				assert.Empty(t, recorder.events)
+			}
+			mockService.AssertExpectations(t)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+type recordingAuditLog struct {
+	events []authaudit.Event
+}
+
+func (r *recordingAuditLog) Record(_ context.Context, event *authaudit.Event) {
+	r.events = append(r.events, *event)
+}
+
+func TestHandler_AuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// run calls handle with a JSON body from a known client, returning the events recorded
+	run := func(handle func(*Handler, *gin.Context), ms *MockService, mas *MockAuthService, body interface{}, claims *auth.Claims) []authaudit.Event {
+		recorder := &recordingAuditLog{}
+		handler := NewHandler(ms, mas, WithAuditLog(recorder))
+
+		payload, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(payload))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("User-Agent", "test-agent")
+		c.Request.RemoteAddr = "192.0.2.10:1234"
+		if claims != nil {
+			c.Set(auth.KeyUser, claims)
+		}
+		handle(handler, c)
+		return recorder.events
+	}
+
+	t.Run("login succeeded", func(t *testing.T) {
+		ms, mas := new(MockService), new(MockAuthService)
+		ms.On("AuthenticateUser", mock.Anything, mock.AnythingOfType("user.LoginRequest")).Return(&User{ID: 1, Name: "John", Email: "john@example.com"}, nil)
+		mas.On("GenerateTokenPair", mock.Anything, uint(1), "john@example.com", "John").Return(&auth.TokenPair{AccessToken: "access"}, nil)
+
+		events := run((*Handler).Login, ms, mas, LoginRequest{Email: "john@example.com", Password: "password123"}, nil)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, authaudit.TypeLoginSucceeded, events[0].Type)
+			assert.Equal(t, uint(1), *events[0].UserID)
+			assert.Equal(t, "password", events[0].Detail)
+			assert.Equal(t, "192.0.2.10", events[0].IPAddress)
+			assert.Equal(t, "test-agent", events[0].UserAgent)
+		}
+	})
+
+	t.Run("login failed", func(t *testing.T) {
+		ms := new(MockService)
+		ms.On("AuthenticateUser", mock.Anything, mock.AnythingOfType("user.LoginRequest")).Return(nil, ErrInvalidCredentials)
+
+		events := run((*Handler).Login, ms, new(MockAuthService), LoginRequest{Email: "john@example.com", Password: "wrongpassword"}, nil)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, authaudit.TypeLoginFailed, events[0].Type)
+			assert.Nil(t, events[0].UserID)
+			assert.Equal(t, "john@example.com", events[0].Email)
+			assert.Equal(t, "invalid_credentials", events[0].Detail)
+		}
+	})
+
+	t.Run("token refreshed", func(t *testing.T) {
+		mas := new(MockAuthService)
+		mas.On("RefreshAccessToken", mock.Anything, "refresh").Return(&auth.TokenPair{AccessToken: "access"}, nil)
+		mas.On("ValidateToken", "access").Return(&auth.Claims{UserID: 1, Email: "john@example.com"}, nil)
+
+		events := run((*Handler).RefreshToken, new(MockService), mas, auth.RefreshTokenRequest{RefreshToken: "refresh"}, nil)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, authaudit.TypeTokenRefreshed, events[0].Type)
+			assert.Equal(t, uint(1), *events[0].UserID)
+		}
+	})
+
+	t.Run("token reuse", func(t *testing.T) {
+		mas := new(MockAuthService)
+		mas.On("RefreshAccessToken", mock.Anything, "refresh").Return(nil, auth.ErrTokenReuse)
+
+		events := run((*Handler).RefreshToken, new(MockService), mas, auth.RefreshTokenRequest{RefreshToken: "refresh"}, nil)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, authaudit.TypeTokenRefreshFailed, events[0].Type)
+			assert.Equal(t, "token_reuse", events[0].Detail)
+		}
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		mas := new(MockAuthService)
+		mas.On("RevokeUserRefreshToken", mock.Anything, uint(1), "refresh").Return(nil)
+
+		events := run((*Handler).Logout, new(MockService), mas, auth.RefreshTokenRequest{RefreshToken: "refresh"}, &auth.Claims{UserID: 1, Email: "john@example.com"})
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, authaudit.TypeLogout, events[0].Type)
+			assert.Equal(t, "john@example.com", events[0].Email)
+		}
+	})
+}
@@ -0,0 +1,299 @@
+package user
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/email"
+)
+
+const (
+	// invitationTokenAudience scopes invitation tokens so they cannot be confused with other JWTs
+	invitationTokenAudience = "user-invitation"
+	// defaultInvitationTTL is used when invites.ttl is not configured
+	defaultInvitationTTL = 72 * time.Hour
+)
+
+var (
+	// ErrInvitationsDisabled is returned when inviting users without invites.accept_url or email
+	ErrInvitationsDisabled = errors.New("invitations are not configured")
+	// ErrInvalidInvitation is returned when an invitation token is invalid, expired or already used
+	ErrInvalidInvitation = errors.New("invalid or expired invitation")
+	// ErrNameRequired is returned when accepting an invitation without a name, none being set in it
+	ErrNameRequired = errors.New("name is required")
+)
+
+// InvitationSender delivers the link through which an invitation is accepted
+type InvitationSender interface {
+	SendInvitation(ctx context.Context, invitation *Invitation, link string) error
+}
+
+// WithInvitations lets admins invite users: the link sent by sender points to cfg.AcceptURL with
+// a token signed with a key derived from secret
+func WithInvitations(cfg *config.InvitesConfig, secret string, sender InvitationSender) ServiceOption {
+	return func(s *service) {
+		s.invitationSender = sender
+		s.invitationURL = cfg.AcceptURL
+		s.invitationTTL = cfg.TTL
+		if s.invitationTTL <= 0 {
+			s.invitationTTL = defaultInvitationTTL
+		}
+		s.invitationKey = deriveInvitationKey(secret)
+	}
+}
+
+// deriveInvitationKey derives a dedicated signing key from the application secret, so an
+// invitation token can never be accepted as an access token (and vice versa)
+func deriveInvitationKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(invitationTokenAudience))
+	return mac.Sum(nil)
+}
+
+// InviteUser emails an invitation to create an account with the given role (user by default) and
+// organizacao. Without an organizacao the invitee joins the organizacao the request is scoped to.
+func (s *service) InviteUser(ctx context.Context, req InviteUserRequest) (*Invitation, error) {
+	if s.invitationSender == nil || s.invitationURL == "" {
+		return nil, ErrInvitationsDisabled
+	}
+
+	roleName := req.Role
+	if roleName == "" {
+		roleName = RoleUser
+	}
+	role, err := s.repo.FindRoleByName(ctx, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	organizacaoID := req.OrganizacaoID
+	if organizacaoID == nil {
+		if scoped, ok := db.OrganizacaoFromContext(ctx); ok {
+			organizacaoID = &scoped
+		}
+	}
+	if organizacaoID != nil {
+		exists, err := s.repo.OrganizacaoExists(ctx, *organizacaoID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organizacao: %w", err)
+		}
+		if !exists {
+			return nil, ErrOrganizacaoNotFound
+		}
+	}
+
+	existingUser, err := s.repo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing email: %w", err)
+	}
+	if existingUser != nil {
+		return nil, ErrEmailExists
+	}
+
+	invitation := &Invitation{
+		Email:         req.Email,
+		Name:          strings.TrimSpace(req.Name),
+		Role:          roleName,
+		OrganizacaoID: organizacaoID,
+		ExpiresAt:     time.Now().Add(s.invitationTTL),
+	}
+	if actorID, ok := contextutil.UserIDFromContext(ctx); ok {
+		invitation.InvitedBy = &actorID
+	}
+
+	// The email goes out only after the invitation is committed, so no link ever points to an
+	// invitation that was rolled back; one whose email could not be handed over is deleted again
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	link, err := s.invitationLink(invitation)
+	if err == nil {
+		err = s.invitationSender.SendInvitation(ctx, invitation, link)
+		if err != nil {
+			err = fmt.Errorf("failed to send invitation: %w", err)
+		}
+	}
+	if err != nil {
+		if deleteErr := s.repo.DeleteInvitation(ctx, invitation.ID); deleteErr != nil {
+			slog.Error("Failed to delete unsent invitation", "invitation_id", invitation.ID, "error", deleteErr)
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation creates the account of an invitee with the password they chose, the role and
+// organizacao of the invitation, and the name given in it unless req sets one. Each invitation is
+// accepted once.
+func (s *service) AcceptInvitation(ctx context.Context, req AcceptInvitationRequest) (*User, error) {
+	invitationID, err := s.parseInvitationToken(req.Token)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := s.repo.FindInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	now := time.Now()
+	if invitation == nil || invitation.AcceptedAt != nil || now.After(invitation.ExpiresAt) {
+		return nil, ErrInvalidInvitation
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = invitation.Name
+	}
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	existingUser, err := s.repo.FindByEmail(ctx, invitation.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing email: %w", err)
+	}
+	if existingUser != nil {
+		return nil, ErrEmailExists
+	}
+
+	hashedPassword, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user := &User{
+		Name:          name,
+		Email:         invitation.Email,
+		PasswordHash:  hashedPassword,
+		OrganizacaoID: invitation.OrganizacaoID,
+	}
+
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		accepted, err := s.repo.AcceptInvitation(txCtx, invitation.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+		if !accepted {
+			return ErrInvalidInvitation
+		}
+		if err := s.repo.Create(txCtx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if err := s.repo.AssignRole(txCtx, user.ID, RoleUser); err != nil {
+			return fmt.Errorf("failed to assign default role: %w", err)
+		}
+		if invitation.Role != RoleUser {
+			if err := s.repo.AssignRole(txCtx, user.ID, invitation.Role); err != nil {
+				return fmt.Errorf("failed to assign role: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = s.repo.FindByID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("failed to reload user: user not found after creation")
+	}
+	return user, nil
+}
+
+// invitationLink returns the accept URL carrying the signed token of the invitation
+func (s *service) invitationLink(invitation *Invitation) (string, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(invitation.ID), 10),
+		Audience:  jwt.ClaimStrings{invitationTokenAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(invitation.ExpiresAt),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.invitationKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign invitation token: %w", err)
+	}
+
+	link, err := url.Parse(s.invitationURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid invites.accept_url: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// parseInvitationToken checks that the token is valid and unexpired, returning its invitation ID
+func (s *service) parseInvitationToken(token string) (uint, error) {
+	if s.invitationKey == nil {
+		return 0, ErrInvalidInvitation
+	}
+	parsed, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return s.invitationKey, nil
+	}, jwt.WithAudience(invitationTokenAudience), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return 0, ErrInvalidInvitation
+	}
+
+	claims, ok := parsed.Claims.(*jwt.RegisteredClaims)
+	if !ok {
+		return 0, ErrInvalidInvitation
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidInvitation
+	}
+	return uint(id), nil
+}
+
+type emailInvitationSender struct {
+	sender email.Service
+}
+
+// NewEmailInvitationSender emails the invitation link using the notification template
+func NewEmailInvitationSender(sender email.Service) InvitationSender {
+	return &emailInvitationSender{sender: sender}
+}
+
+func (n *emailInvitationSender) SendInvitation(ctx context.Context, invitation *Invitation, link string) error {
+	greeting := "Olá."
+	if invitation.Name != "" {
+		greeting = fmt.Sprintf("Olá, %s.", invitation.Name)
+	}
+
+	_, err := n.sender.SendTemplateEmail(ctx, &email.SendTemplateEmailRequest{
+		To:           []string{invitation.Email},
+		Subject:      "Você foi convidado para criar sua conta",
+		TemplateName: "notification",
+		TemplateData: map[string]interface{}{
+			"Type":         "info",
+			"Title":        "Convite",
+			"Message":      greeting + " Você foi convidado para acessar a plataforma. Defina sua senha pelo link abaixo para criar sua conta.",
+			"AlertMessage": fmt.Sprintf("O convite vale até %s e só pode ser usado uma vez.", invitation.ExpiresAt.Format("02/01/2006 15:04")),
+			"ButtonText":   "Aceitar convite",
+			"ButtonURL":    link,
+			"Timestamp":    time.Now().Format("02/01/2006 15:04"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,225 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/db"
+)
+
+const testInvitationSecret = "test-secret-with-at-least-32-characters"
+
+type recordingInvitationSender struct {
+	invitation *Invitation
+	link       string
+	err        error
+}
+
+func (r *recordingInvitationSender) SendInvitation(_ context.Context, invitation *Invitation, link string) error {
+	r.invitation, r.link = invitation, link
+	return r.err
+}
+
+// orderCheckingSender records whether the invitation was already stored when it was sent
+type orderCheckingSender struct {
+	repo   *MockRepository
+	stored bool
+}
+
+func (s *orderCheckingSender) SendInvitation(_ context.Context, _ *Invitation, _ string) error {
+	s.stored = len(s.repo.Calls) > 0 && s.repo.Calls[len(s.repo.Calls)-1].Method == "CreateInvitation"
+	return nil
+}
+
+func withTestInvitations(sender InvitationSender) ServiceOption {
+	return WithInvitations(&config.InvitesConfig{AcceptURL: "https://app.example.com/convite?origem=email"}, testInvitationSecret, sender)
+}
+
+// invitationToken returns the token of a link sent for invitation
+func invitationToken(t *testing.T, invitation *Invitation) string {
+	sender := &recordingInvitationSender{}
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindRoleByName", mock.Anything, invitation.Role).Return(&Role{Name: invitation.Role}, nil)
+	mockRepo.On("FindByEmail", mock.Anything, invitation.Email).Return(nil, nil)
+	mockRepo.On("CreateInvitation", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { args.Get(1).(*Invitation).ID = invitation.ID }).
+		Return(nil)
+
+	_, err := NewService(mockRepo, withTestInvitations(sender)).InviteUser(context.Background(), InviteUserRequest{Email: invitation.Email, Role: invitation.Role})
+	require.NoError(t, err)
+	link, err := url.Parse(sender.link)
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func TestService_InviteUser(t *testing.T) {
+	t.Run("sends a signed link", func(t *testing.T) {
+		sender := &recordingInvitationSender{}
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindRoleByName", mock.Anything, RoleUser).Return(&Role{Name: RoleUser}, nil)
+		mockRepo.On("OrganizacaoExists", mock.Anything, uint(7)).Return(true, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		mockRepo.On("CreateInvitation", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { args.Get(1).(*Invitation).ID = 3 }).
+			Return(nil)
+
+		ctx := db.WithOrganizacao(contextutil.WithUserID(context.Background(), 1), 7)
+		invitation, err := NewService(mockRepo, withTestInvitations(sender)).InviteUser(ctx, InviteUserRequest{Email: "ana@example.com", Name: " Ana "})
+		require.NoError(t, err)
+		assert.Equal(t, RoleUser, invitation.Role, "the role defaults to user")
+		require.NotNil(t, invitation.OrganizacaoID)
+		assert.Equal(t, uint(7), *invitation.OrganizacaoID, "the organizacao defaults to the request's")
+		require.NotNil(t, invitation.InvitedBy)
+		assert.Equal(t, uint(1), *invitation.InvitedBy)
+		assert.Equal(t, "Ana", invitation.Name)
+		assert.WithinDuration(t, time.Now().Add(defaultInvitationTTL), invitation.ExpiresAt, time.Minute)
+
+		link, err := url.Parse(sender.link)
+		require.NoError(t, err)
+		assert.Equal(t, "app.example.com", link.Host)
+		assert.Equal(t, "email", link.Query().Get("origem"))
+		assert.NotEmpty(t, link.Query().Get("token"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		_, err := NewService(new(MockRepository)).InviteUser(context.Background(), InviteUserRequest{Email: "ana@example.com"})
+		assert.ErrorIs(t, err, ErrInvitationsDisabled)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindRoleByName", mock.Anything, "gerente").Return(nil, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).InviteUser(context.Background(), InviteUserRequest{Email: "ana@example.com", Role: "gerente"})
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("registered email", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindRoleByName", mock.Anything, RoleUser).Return(&Role{Name: RoleUser}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(&User{ID: 2}, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).InviteUser(context.Background(), InviteUserRequest{Email: "ana@example.com"})
+		assert.ErrorIs(t, err, ErrEmailExists)
+		mockRepo.AssertNotCalled(t, "CreateInvitation")
+	})
+
+	t.Run("sending fails", func(t *testing.T) {
+		sender := &recordingInvitationSender{err: errors.New("smtp down")}
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindRoleByName", mock.Anything, RoleUser).Return(&Role{Name: RoleUser}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		mockRepo.On("CreateInvitation", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { args.Get(1).(*Invitation).ID = 3 }).
+			Return(nil)
+		mockRepo.On("DeleteInvitation", mock.Anything, uint(3)).Return(nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(sender)).InviteUser(context.Background(), InviteUserRequest{Email: "ana@example.com"})
+		assert.ErrorContains(t, err, "failed to send invitation")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("sends after the invitation is stored", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		sender := &orderCheckingSender{repo: mockRepo}
+		mockRepo.On("FindRoleByName", mock.Anything, RoleUser).Return(&Role{Name: RoleUser}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		mockRepo.On("CreateInvitation", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(sender)).InviteUser(context.Background(), InviteUserRequest{Email: "ana@example.com"})
+		require.NoError(t, err)
+		assert.True(t, sender.stored, "the invitation was created before the email was sent")
+	})
+}
+
+func TestService_AcceptInvitation(t *testing.T) {
+	organizacaoID := uint(7)
+	pending := func() *Invitation {
+		return &Invitation{ID: 3, Email: "ana@example.com", Name: "Ana", Role: RoleAdmin, OrganizacaoID: &organizacaoID, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+	token := invitationToken(t, pending())
+
+	t.Run("creates the user with the role and organizacao", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindInvitationByID", mock.Anything, uint(3)).Return(pending(), nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		mockRepo.On("AcceptInvitation", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(true, nil)
+		var created *User
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				created = args.Get(1).(*User)
+				created.ID = 5
+			}).
+			Return(nil)
+		mockRepo.On("AssignRole", mock.Anything, uint(5), RoleUser).Return(nil)
+		mockRepo.On("AssignRole", mock.Anything, uint(5), RoleAdmin).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, uint(5)).Return(&User{ID: 5, Email: "ana@example.com"}, nil)
+
+		user, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), user.ID)
+		assert.Equal(t, "Ana", created.Name, "the name defaults to the invitation's")
+		assert.Equal(t, &organizacaoID, created.OrganizacaoID)
+		assert.NoError(t, verifyPassword(created.PasswordHash, "secret123"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		acceptedAt := time.Now()
+		invitation := pending()
+		invitation.AcceptedAt = &acceptedAt
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindInvitationByID", mock.Anything, uint(3)).Return(invitation, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+	})
+
+	t.Run("accepted concurrently", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindInvitationByID", mock.Anything, uint(3)).Return(pending(), nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		mockRepo.On("AcceptInvitation", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		invitation := pending()
+		invitation.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindInvitationByID", mock.Anything, uint(3)).Return(invitation, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+	})
+
+	t.Run("token signed with another secret", func(t *testing.T) {
+		other := WithInvitations(&config.InvitesConfig{AcceptURL: "https://app.example.com"}, "another-secret-with-at-least-32-chars", &recordingInvitationSender{})
+
+		_, err := NewService(new(MockRepository), other).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+	})
+
+	t.Run("name required", func(t *testing.T) {
+		invitation := pending()
+		invitation.Name = ""
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindInvitationByID", mock.Anything, uint(3)).Return(invitation, nil)
+
+		_, err := NewService(mockRepo, withTestInvitations(&recordingInvitationSender{})).AcceptInvitation(context.Background(), AcceptInvitationRequest{Token: token, Password: "secret123"})
+		assert.ErrorIs(t, err, ErrNameRequired)
+	})
+}
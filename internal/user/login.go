@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+// Reasons of the LoginFailed events
+const (
+	loginFailedInvalidCredentials   = "invalid_credentials"
+	loginFailedInvalidTwoFactorCode = "invalid_two_factor_code"
+	loginFailedLocked               = "locked"
+)
+
+// WithLoginLimiter locks accounts and addresses out after too many failed logins
+func WithLoginLimiter(limiter *auth.LoginLimiter) ServiceOption {
+	return func(s *service) {
+		s.loginLimiter = limiter
+	}
+}
+
+// WithEvents publishes the failed logins and lockouts of the service to publisher
+func WithEvents(publisher events.Publisher) ServiceOption {
+	return func(s *service) {
+		s.events = publisher
+	}
+}
+
+// checkLoginLock refuses logins while the account or the address of ctx (see auth.WithClient) is
+// locked out
+func (s *service) checkLoginLock(ctx context.Context, email string) error {
+	if s.loginLimiter == nil {
+		return nil
+	}
+	ip := auth.ClientIP(ctx)
+	if err := s.loginLimiter.Check(ctx, email, ip); err != nil {
+		s.publish(events.LoginFailed{Email: email, IPAddress: ip, Reason: loginFailedLocked})
+		return err
+	}
+	return nil
+}
+
+// loginFailed records a failed login of email for reason, user being nil when no account has it.
+// Failing to record it is logged rather than returned: the caller still answers with invalid
+// credentials.
+func (s *service) loginFailed(ctx context.Context, email string, user *User, reason string) {
+	ip := auth.ClientIP(ctx)
+	var userID uint
+	var name string
+	if user != nil {
+		userID, name = user.ID, user.Name
+	}
+	s.publish(events.LoginFailed{UserID: userID, Email: email, IPAddress: ip, Reason: reason})
+
+	if s.loginLimiter == nil {
+		return
+	}
+	failure, err := s.loginLimiter.Fail(ctx, email, ip)
+	if err != nil {
+		slog.Error("Failed to record failed login", "error", err)
+		return
+	}
+	if !failure.AccountLockedUntil.IsZero() {
+		slog.Warn("Account locked out after failed logins", "user_id", userID, "ip", ip, "attempts", failure.AccountFailures)
+		s.publish(events.LoginLocked{
+			UserID:      userID,
+			Email:       email,
+			Name:        name,
+			IPAddress:   ip,
+			Attempts:    failure.AccountFailures,
+			LockedUntil: failure.AccountLockedUntil,
+		})
+	}
+	if !failure.IPLockedUntil.IsZero() {
+		slog.Warn("Address locked out after failed logins", "ip", ip, "attempts", failure.IPFailures)
+		s.publish(events.LoginLocked{IPAddress: ip, Attempts: failure.IPFailures, LockedUntil: failure.IPLockedUntil})
+	}
+}
+
+// checkChallenge refuses a two-factor challenge invalidated by too many wrong codes
+func (s *service) checkChallenge(ctx context.Context, challenge string) error {
+	if s.loginLimiter == nil {
+		return nil
+	}
+	return s.loginLimiter.CheckChallenge(ctx, challenge)
+}
+
+// challengeFailed records a wrong code sent for a two-factor challenge
+func (s *service) challengeFailed(ctx context.Context, challenge string, user *User) {
+	if s.loginLimiter == nil {
+		return
+	}
+	exhausted, err := s.loginLimiter.FailChallenge(ctx, challenge)
+	if err != nil {
+		slog.Error("Failed to record invalid two-factor code", "error", err)
+		return
+	}
+	if exhausted {
+		slog.Warn("Two-factor challenge invalidated after invalid codes", "user_id", user.ID, "ip", auth.ClientIP(ctx))
+	}
+}
+
+// loginSucceeded clears the failed logins of email
+func (s *service) loginSucceeded(ctx context.Context, email string) {
+	if s.loginLimiter == nil {
+		return
+	}
+	if err := s.loginLimiter.Succeed(ctx, email); err != nil {
+		slog.Error("Failed to reset failed logins", "error", err)
+	}
+}
+
+func (s *service) publish(event events.Event) {
+	if s.events != nil {
+		s.events.Publish(event)
+	}
+}
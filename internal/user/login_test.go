@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/config"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
+)
+
+// recordingPublisher records the published events instead of handling them
+type recordingPublisher struct {
+	published []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.published = append(p.published, event)
+}
+
+func TestService_AuthenticateUser_LoginProtection(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &User{ID: 1, Name: "Ana", Email: "ana@example.com", PasswordHash: string(hashedPassword)}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(user, nil)
+	publisher := &recordingPublisher{}
+	limiter := auth.NewLoginLimiter(&config.LoginConfig{
+		Window:             time.Minute,
+		MaxAccountAttempts: 2,
+		Lockout:            time.Minute,
+	}, auth.NewMemoryAttemptStore(10, time.Minute))
+	service := NewService(mockRepo, WithLoginLimiter(limiter), WithEvents(publisher))
+	ctx := auth.WithClient(context.Background(), "Firefox", "10.0.0.1")
+
+	_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"})
+	var locked *auth.LockoutError
+	require.ErrorAs(t, err, &locked, "a locked account is refused even with the right password")
+	assert.Positive(t, locked.RetryAfter)
+
+	require.Len(t, publisher.published, 4)
+	assert.Equal(t, events.LoginFailed{UserID: 1, Email: "ana@example.com", IPAddress: "10.0.0.1", Reason: "invalid_credentials"}, publisher.published[0])
+	lockout, ok := publisher.published[2].(events.LoginLocked)
+	require.True(t, ok)
+	assert.Equal(t, uint(1), lockout.UserID)
+	assert.Equal(t, "Ana", lockout.Name)
+	assert.Equal(t, 2, lockout.Attempts)
+	assert.Equal(t, "locked", publisher.published[3].(events.LoginFailed).Reason)
+}
+
+func TestService_AuthenticateUser_ResetsFailures(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(&User{ID: 1, Email: "ana@example.com", PasswordHash: string(hashedPassword)}, nil)
+	limiter := auth.NewLoginLimiter(&config.LoginConfig{
+		Window:             time.Minute,
+		MaxAccountAttempts: 2,
+		Lockout:            time.Minute,
+	}, auth.NewMemoryAttemptStore(10, time.Minute))
+	service := NewService(mockRepo, WithLoginLimiter(limiter))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "wrong"})
+		require.ErrorIs(t, err, ErrInvalidCredentials, "attempt %d", i)
+		_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"})
+		require.NoError(t, err, "a successful login clears the failures")
+	}
+}
+
+func TestService_VerifyTwoFactorLogin_LoginProtection(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	user := &User{ID: 1, Email: "ana@example.com", PasswordHash: string(hashedPassword), TOTPSecret: secret, TwoFactorAt: &enabledAt}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(user, nil)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("UseRecoveryCode", mock.Anything, uint(1), mock.Anything).Return(false, nil)
+	publisher := &recordingPublisher{}
+	limiter := auth.NewLoginLimiter(&config.LoginConfig{
+		Window:               time.Minute,
+		MaxAccountAttempts:   4,
+		MaxTwoFactorAttempts: 2,
+		Lockout:              time.Minute,
+	}, auth.NewMemoryAttemptStore(10, time.Hour))
+	service := NewService(mockRepo, WithLoginLimiter(limiter), WithEvents(publisher))
+	ctx := context.Background()
+
+	// A wrong password, then the right one: the failure still counts until the second factor passes
+	_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "wrong"})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = service.AuthenticateUser(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge-a", 1, "ABCDE-FGHIJ")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge-a", 1, "ABCDE-FGHIJ")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge-a", 1, currentTOTPCode(t, secret))
+	require.ErrorIs(t, err, auth.ErrChallengeExhausted, "the challenge is invalidated even for the right code")
+	assert.Equal(t, events.LoginFailed{UserID: 1, Email: "ana@example.com", Reason: "invalid_two_factor_code"}, publisher.published[1])
+
+	// A fresh challenge does not reset the failures: the next wrong code locks the account
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge-b", 1, "ABCDE-FGHIJ")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge-b", 1, currentTOTPCode(t, secret))
+	var locked *auth.LockoutError
+	require.ErrorAs(t, err, &locked)
+	mockRepo.AssertNotCalled(t, "UpdateTOTPLastStep")
+}
+
+func TestService_VerifyTwoFactorLogin_ResetsFailures(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	user := &User{ID: 1, Email: "ana@example.com", TOTPSecret: secret, TwoFactorAt: &enabledAt}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("UseRecoveryCode", mock.Anything, uint(1), mock.Anything).Return(false, nil)
+	mockRepo.On("UpdateTOTPLastStep", mock.Anything, uint(1), mock.AnythingOfType("int64")).Return(true, nil)
+	limiter := auth.NewLoginLimiter(&config.LoginConfig{
+		Window:               time.Minute,
+		MaxAccountAttempts:   2,
+		MaxTwoFactorAttempts: 5,
+		Lockout:              time.Minute,
+	}, auth.NewMemoryAttemptStore(10, time.Hour))
+	service := NewService(mockRepo, WithLoginLimiter(limiter))
+	ctx := context.Background()
+
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge", 1, "ABCDE-FGHIJ")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = service.VerifyTwoFactorLogin(ctx, "challenge", 1, currentTOTPCode(t, secret))
+	require.NoError(t, err)
+
+	_, err = service.VerifyTwoFactorLogin(ctx, "next-challenge", 1, "ABCDE-FGHIJ")
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode, "the second factor cleared the failures, so the account is not locked")
+}
@@ -2,8 +2,11 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
 )
 
 // MockService is a mock implementation of the user service for testing handlers
@@ -61,6 +64,112 @@ func (m *MockService) PromoteToAdmin(ctx context.Context, userID uint) error {
 	return args.Error(0)
 }
 
+func (m *MockService) ListRoles(ctx context.Context) ([]Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Role), args.Error(1)
+}
+
+func (m *MockService) AssignRole(ctx context.Context, userID uint, roleName string) (*User, error) {
+	args := m.Called(ctx, userID, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) RemoveRole(ctx context.Context, userID uint, roleName string) (*User, error) {
+	args := m.Called(ctx, userID, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) LinkCorretor(ctx context.Context, userID uint, corretorID *uint) (*User, error) {
+	args := m.Called(ctx, userID, corretorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) AssignOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) (*User, error) {
+	args := m.Called(ctx, userID, organizacaoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) EnrollTwoFactor(ctx context.Context, userID uint) (string, string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockService) ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockService) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockService) ChangePassword(ctx context.Context, id uint, req ChangePasswordRequest) (*User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) VerifyTwoFactor(ctx context.Context, userID uint, code string) (*User, error) {
+	args := m.Called(ctx, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) VerifyTwoFactorLogin(ctx context.Context, challenge string, userID uint, code string) (*User, error) {
+	args := m.Called(ctx, challenge, userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) InviteUser(ctx context.Context, req InviteUserRequest) (*Invitation, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Invitation), args.Error(1)
+}
+
+func (m *MockService) AcceptInvitation(ctx context.Context, req AcceptInvitationRequest) (*User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockService) AuthenticateOAuth(ctx context.Context, profile auth.OAuthProfile) (*User, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
 // MockRepository is a mock implementation of the user repository for testing services
 type MockRepository struct {
 	mock.Mock
@@ -92,6 +201,11 @@ func (m *MockRepository) Update(ctx context.Context, user *User) error {
 	return args.Error(0)
 }
 
+func (m *MockRepository) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	args := m.Called(ctx, userID, passwordHash)
+	return args.Error(0)
+}
+
 func (m *MockRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -131,6 +245,90 @@ func (m *MockRepository) GetUserRoles(ctx context.Context, userID uint) ([]Role,
 	return args.Get(0).([]Role), args.Error(1)
 }
 
+func (m *MockRepository) ListRoles(ctx context.Context) ([]Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Role), args.Error(1)
+}
+
+func (m *MockRepository) CorretorExists(ctx context.Context, corretorID uint) (bool, error) {
+	args := m.Called(ctx, corretorID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) UpdateCorretor(ctx context.Context, userID uint, corretorID *uint) error {
+	args := m.Called(ctx, userID, corretorID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) OrganizacaoExists(ctx context.Context, organizacaoID uint) (bool, error) {
+	args := m.Called(ctx, organizacaoID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) UpdateOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) error {
+	args := m.Called(ctx, userID, organizacaoID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateTOTP(ctx context.Context, userID uint, secret string, enabledAt *time.Time) error {
+	args := m.Called(ctx, userID, secret, enabledAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateTOTPLastStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CreateInvitation(ctx context.Context, invitation *Invitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindInvitationByID(ctx context.Context, id uint) (*Invitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Invitation), args.Error(1)
+}
+
+func (m *MockRepository) DeleteInvitation(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AcceptInvitation(ctx context.Context, id uint, acceptedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, acceptedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) FindByIdentity(ctx context.Context, provider, subject string) (*User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockRepository) CreateIdentity(ctx context.Context, identity *Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
 func (m *MockRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
 	// Execute the transaction function directly for testing
 	return fn(ctx)
@@ -8,14 +8,20 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Name         string         `gorm:"not null" json:"name"`
-	Email        string         `gorm:"uniqueIndex;not null" json:"email"`
-	PasswordHash string         `gorm:"not null" json:"-"`
-	Roles        []Role         `gorm:"many2many:user_roles;" json:"-"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Name          string         `gorm:"not null" json:"name"`
+	Email         string         `gorm:"uniqueIndex;not null" json:"email"`
+	PasswordHash  string         `gorm:"not null" json:"-"`
+	Locale        string         `gorm:"size:35" json:"locale,omitempty"`       // BCP 47; empty uses email.default_locale
+	CorretorID    *uint          `gorm:"index" json:"corretor_id,omitempty"`    // corretor principal the user works as
+	OrganizacaoID *uint          `gorm:"index" json:"organizacao_id,omitempty"` // tenant of the user's requests
+	TOTPSecret    string         `gorm:"column:totp_secret;size:64" json:"-"`   // set on enrollment, before confirmation
+	TOTPLastStep  int64          `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	TwoFactorAt   *time.Time     `gorm:"column:two_factor_enabled_at" json:"-"` // when 2FA was confirmed; nil while off
+	Roles         []Role         `gorm:"many2many:user_roles;" json:"-"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName specifies the table name for User model
@@ -46,3 +52,57 @@ func (u *User) GetRoleNames() []string {
 	}
 	return roleNames
 }
+
+// TwoFactorEnabled reports whether the user confirmed a TOTP enrollment
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactorAt != nil
+}
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the authenticator is lost.
+// Only the SHA-256 of the code is stored.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for RecoveryCode model
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// Invitation lets someone create their account with the role and organizacao chosen by the admin
+// who invited them. It is accepted once, before ExpiresAt.
+type Invitation struct {
+	ID            uint   `gorm:"primaryKey"`
+	Email         string `gorm:"size:255;not null;index"`
+	Name          string `gorm:"size:100"`
+	Role          string `gorm:"size:50;not null"`
+	OrganizacaoID *uint
+	InvitedBy     *uint
+	ExpiresAt     time.Time `gorm:"not null"`
+	AcceptedAt    *time.Time
+	CreatedAt     time.Time
+}
+
+// TableName specifies the table name for Invitation model
+func (Invitation) TableName() string {
+	return "user_invitations"
+}
+
+// Identity links a user to their account at an external identity provider, such as Google
+type Identity struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Provider  string `gorm:"size:50;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string `gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string `gorm:"size:255"` // email at the provider when the identity was linked
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for Identity model
+func (Identity) TableName() string {
+	return "user_identities"
+}
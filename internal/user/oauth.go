@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+// AuthenticateOAuth signs in the user linked to an account at an identity provider, whose email
+// the provider verified. An account seen for the first time is linked to the user with its email,
+// or to a new user with the user role and a random password, who then signs in through the
+// provider only.
+func (s *service) AuthenticateOAuth(ctx context.Context, profile auth.OAuthProfile) (*User, error) {
+	user, err := s.repo.FindByIdentity(ctx, profile.Provider, profile.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	user, err = s.repo.FindByEmail(ctx, profile.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	identity := &Identity{Provider: profile.Provider, Subject: profile.Subject, Email: profile.Email}
+	if user != nil {
+		identity.UserID = user.ID
+		if err := s.repo.CreateIdentity(ctx, identity); err != nil {
+			return nil, fmt.Errorf("failed to link identity: %w", err)
+		}
+		return user, nil
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	name := strings.TrimSpace(profile.Name)
+	if name == "" {
+		name = profile.Email[:strings.LastIndex(profile.Email, "@")]
+	}
+	user = &User{Name: name, Email: profile.Email, PasswordHash: hashedPassword}
+
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.Create(txCtx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if err := s.repo.AssignRole(txCtx, user.ID, RoleUser); err != nil {
+			return fmt.Errorf("failed to assign default role: %w", err)
+		}
+		identity.UserID = user.ID
+		if err := s.repo.CreateIdentity(txCtx, identity); err != nil {
+			return fmt.Errorf("failed to link identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = s.repo.FindByID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("failed to reload user: user not found after creation")
+	}
+	return user, nil
+}
+
+// randomPassword returns a password nobody knows, for users created through a provider
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
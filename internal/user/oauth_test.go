@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+func TestService_AuthenticateOAuth(t *testing.T) {
+	profile := auth.OAuthProfile{Provider: auth.ProviderGoogle, Subject: "g-1", Email: "ana@example.com", Name: "Ana"}
+
+	t.Run("linked account", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByIdentity", mock.Anything, auth.ProviderGoogle, "g-1").Return(&User{ID: 1}, nil)
+
+		user, err := NewService(mockRepo).AuthenticateOAuth(context.Background(), profile)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		mockRepo.AssertNotCalled(t, "CreateIdentity")
+	})
+
+	t.Run("links the user with the email", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByIdentity", mock.Anything, auth.ProviderGoogle, "g-1").Return(nil, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(&User{ID: 1}, nil)
+		mockRepo.On("CreateIdentity", mock.Anything, &Identity{UserID: 1, Provider: auth.ProviderGoogle, Subject: "g-1", Email: "ana@example.com"}).Return(nil)
+
+		user, err := NewService(mockRepo).AuthenticateOAuth(context.Background(), profile)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("creates a user", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByIdentity", mock.Anything, auth.ProviderGoogle, "g-1").Return(nil, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		var created *User
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				created = args.Get(1).(*User)
+				created.ID = 2
+			}).
+			Return(nil)
+		mockRepo.On("AssignRole", mock.Anything, uint(2), RoleUser).Return(nil)
+		mockRepo.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(identity *Identity) bool { return identity.UserID == 2 })).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(&User{ID: 2}, nil)
+
+		user, err := NewService(mockRepo).AuthenticateOAuth(context.Background(), profile)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), user.ID)
+		assert.Equal(t, "Ana", created.Name)
+		assert.NotEmpty(t, created.PasswordHash)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("name defaults to the email", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByIdentity", mock.Anything, auth.ProviderGoogle, "g-1").Return(nil, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "ana@example.com").Return(nil, nil)
+		var created *User
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*User) }).
+			Return(nil)
+		mockRepo.On("AssignRole", mock.Anything, mock.Anything, RoleUser).Return(nil)
+		mockRepo.On("CreateIdentity", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, mock.Anything).Return(&User{}, nil)
+
+		noName := profile
+		noName.Name = ""
+		_, err := NewService(mockRepo).AuthenticateOAuth(context.Background(), noName)
+		require.NoError(t, err)
+		assert.Equal(t, "ana", created.Name)
+	})
+}
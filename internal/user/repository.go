@@ -24,6 +24,22 @@ type Repository interface {
 	RemoveRole(ctx context.Context, userID uint, roleName string) error
 	FindRoleByName(ctx context.Context, name string) (*Role, error)
 	GetUserRoles(ctx context.Context, userID uint) ([]Role, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	CorretorExists(ctx context.Context, corretorID uint) (bool, error)
+	UpdateCorretor(ctx context.Context, userID uint, corretorID *uint) error
+	OrganizacaoExists(ctx context.Context, organizacaoID uint) (bool, error)
+	UpdateOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) error
+	UpdatePassword(ctx context.Context, userID uint, passwordHash string) error
+	UpdateTOTP(ctx context.Context, userID uint, secret string, enabledAt *time.Time) error
+	UpdateTOTPLastStep(ctx context.Context, userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CreateInvitation(ctx context.Context, invitation *Invitation) error
+	FindInvitationByID(ctx context.Context, id uint) (*Invitation, error)
+	DeleteInvitation(ctx context.Context, id uint) error
+	AcceptInvitation(ctx context.Context, id uint, acceptedAt time.Time) (bool, error)
+	FindByIdentity(ctx context.Context, provider, subject string) (*User, error)
+	CreateIdentity(ctx context.Context, identity *Identity) error
 	Transaction(ctx context.Context, fn func(context.Context) error) error
 }
 
@@ -82,7 +98,7 @@ func (r *repository) FindByID(ctx context.Context, id uint) (*User, error) {
 // Update updates a user in the database
 func (r *repository) Update(ctx context.Context, user *User) error {
 	// WHY: Save() syncs associations, potentially clearing roles
-	result := r.getDB(ctx).WithContext(ctx).Select("name", "email", "password_hash", "updated_at").Save(user)
+	result := r.getDB(ctx).WithContext(ctx).Select("name", "email", "password_hash", "locale", "updated_at").Save(user)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -216,6 +232,192 @@ func (r *repository) GetUserRoles(ctx context.Context, userID uint) ([]Role, err
 	return roles, nil
 }
 
+// ListRoles retrieves all roles ordered by name
+func (r *repository) ListRoles(ctx context.Context) ([]Role, error) {
+	var roles []Role
+	if err := r.getDB(ctx).WithContext(ctx).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// CorretorExists reports whether a corretor principal that is not deleted has the given ID
+func (r *repository) CorretorExists(ctx context.Context, corretorID uint) (bool, error) {
+	var count int64
+	err := r.getDB(ctx).WithContext(ctx).
+		Table("corretores_principais").
+		Where("id = ? AND deleted_at IS NULL", corretorID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateCorretor links the user to a corretor principal, or unlinks them when corretorID is nil
+func (r *repository) UpdateCorretor(ctx context.Context, userID uint, corretorID *uint) error {
+	result := r.getDB(ctx).WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("corretor_id", corretorID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// OrganizacaoExists reports whether an organizacao that is not deleted has the given ID
+func (r *repository) OrganizacaoExists(ctx context.Context, organizacaoID uint) (bool, error) {
+	var count int64
+	err := r.getDB(ctx).WithContext(ctx).
+		Table("organizacoes").
+		Where("id = ? AND deleted_at IS NULL", organizacaoID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateOrganizacao moves the user to an organizacao, or makes them unscoped when organizacaoID is nil
+func (r *repository) UpdateOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) error {
+	result := r.getDB(ctx).WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("organizacao_id", organizacaoID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdatePassword replaces the password hash of the user
+func (r *repository) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	result := r.getDB(ctx).WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("password_hash", passwordHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateTOTP stores the user's TOTP secret and when 2FA was enabled (nil while pending or disabled),
+// resetting the last used step
+func (r *repository) UpdateTOTP(ctx context.Context, userID uint, secret string, enabledAt *time.Time) error {
+	result := r.getDB(ctx).WithContext(ctx).Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":           secret,
+		"totp_last_step":        0,
+		"two_factor_enabled_at": enabledAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateTOTPLastStep records step as the last TOTP step used by the user. It reports false when a
+// code of that step or a later one was already used, so that each code is accepted only once.
+func (r *repository) UpdateTOTPLastStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.getDB(ctx).WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores the given hashes instead
+func (r *repository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codeHashes) == 0 {
+			return nil
+		}
+		codes := make([]RecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks the user's unused recovery code with the given hash as used, reporting
+// false when there is none
+func (r *repository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.getDB(ctx).WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateInvitation stores a new invitation
+func (r *repository) CreateInvitation(ctx context.Context, invitation *Invitation) error {
+	return r.getDB(ctx).WithContext(ctx).Create(invitation).Error
+}
+
+// FindInvitationByID finds an invitation by ID, returning nil when there is none
+func (r *repository) FindInvitationByID(ctx context.Context, id uint) (*Invitation, error) {
+	var invitation Invitation
+	result := r.getDB(ctx).WithContext(ctx).First(&invitation, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &invitation, nil
+}
+
+// DeleteInvitation removes an invitation
+func (r *repository) DeleteInvitation(ctx context.Context, id uint) error {
+	return r.getDB(ctx).WithContext(ctx).Delete(&Invitation{}, id).Error
+}
+
+// AcceptInvitation marks a pending invitation as accepted, reporting false when it was already
+// accepted, so that concurrent requests cannot both use it
+func (r *repository) AcceptInvitation(ctx context.Context, id uint, acceptedAt time.Time) (bool, error) {
+	result := r.getDB(ctx).WithContext(ctx).Model(&Invitation{}).
+		Where("id = ? AND accepted_at IS NULL", id).
+		Update("accepted_at", acceptedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindByIdentity finds the user linked to the account subject of provider, returning nil when
+// none is
+func (r *repository) FindByIdentity(ctx context.Context, provider, subject string) (*User, error) {
+	var user User
+	result := r.getDB(ctx).WithContext(ctx).Preload("Roles").
+		Joins("JOIN user_identities ON user_identities.user_id = users.id").
+		Where("user_identities.provider = ? AND user_identities.subject = ?", provider, subject).
+		First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// CreateIdentity links a user to an account at an identity provider
+func (r *repository) CreateIdentity(ctx context.Context, identity *Identity) error {
+	return r.getDB(ctx).WithContext(ctx).Create(identity).Error
+}
+
 // Transaction executes a function within a database transaction
 func (r *repository) Transaction(ctx context.Context, fn func(context.Context) error) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,12 @@ func setupTestDB(t *testing.T) *gorm.DB {
 			name TEXT NOT NULL,
 			email TEXT UNIQUE NOT NULL,
 			password_hash TEXT NOT NULL,
+			locale TEXT,
+			corretor_id INTEGER,
+			organizacao_id INTEGER,
+			totp_secret TEXT,
+			totp_last_step INTEGER NOT NULL DEFAULT 0,
+			two_factor_enabled_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			deleted_at DATETIME
@@ -31,6 +38,48 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		CREATE INDEX idx_users_email ON users(email);
 		CREATE INDEX idx_users_deleted_at ON users(deleted_at);
 
+		CREATE TABLE user_recovery_codes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			code_hash TEXT NOT NULL,
+			used_at DATETIME,
+			created_at DATETIME
+		);
+
+		CREATE TABLE user_invitations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL,
+			name TEXT,
+			role TEXT NOT NULL,
+			organizacao_id INTEGER,
+			invited_by INTEGER,
+			expires_at DATETIME NOT NULL,
+			accepted_at DATETIME,
+			created_at DATETIME
+		);
+
+		CREATE TABLE user_identities (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			provider TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			email TEXT,
+			created_at DATETIME,
+			UNIQUE (provider, subject)
+		);
+
+		CREATE TABLE corretores_principais (
+			id INTEGER PRIMARY KEY,
+			nome TEXT,
+			deleted_at DATETIME
+		);
+
+		CREATE TABLE organizacoes (
+			id INTEGER PRIMARY KEY,
+			nome TEXT,
+			deleted_at DATETIME
+		);
+
 		CREATE TABLE roles (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			name TEXT UNIQUE NOT NULL,
@@ -175,6 +224,7 @@ func TestRepository_Update(t *testing.T) {
 
 	user.Name = "Updated Name"
 	user.Email = "updated@example.com"
+	user.Locale = "en"
 
 	err = repo.Update(context.Background(), user)
 	assert.NoError(t, err)
@@ -183,6 +233,7 @@ func TestRepository_Update(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Name", updatedUser.Name)
 	assert.Equal(t, "updated@example.com", updatedUser.Email)
+	assert.Equal(t, "en", updatedUser.Locale)
 }
 
 func TestRepository_Update_NonExistentUser(t *testing.T) {
@@ -715,3 +766,166 @@ func TestRepository_GetUserRoles_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, roles)
 }
+
+func TestRepository_LinkCorretor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO corretores_principais (id, nome, deleted_at) VALUES
+		(5, 'Ana', NULL), (6, 'Bruno', CURRENT_TIMESTAMP)`).Error)
+
+	exists, err := repo.CorretorExists(ctx, 5)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.CorretorExists(ctx, 6)
+	require.NoError(t, err)
+	assert.False(t, exists, "deleted corretores cannot be linked")
+
+	user := &User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	corretorID := uint(5)
+	require.NoError(t, repo.UpdateCorretor(ctx, user.ID, &corretorID))
+	found, err := repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.CorretorID)
+	assert.Equal(t, uint(5), *found.CorretorID)
+
+	require.NoError(t, repo.UpdateCorretor(ctx, user.ID, nil))
+	found, err = repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.CorretorID)
+
+	assert.ErrorIs(t, repo.UpdateCorretor(ctx, 999, nil), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_AssignOrganizacao(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO organizacoes (id, nome, deleted_at) VALUES
+		(3, 'Triiio', NULL), (4, 'Antiga', CURRENT_TIMESTAMP)`).Error)
+
+	exists, err := repo.OrganizacaoExists(ctx, 3)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.OrganizacaoExists(ctx, 4)
+	require.NoError(t, err)
+	assert.False(t, exists, "deleted organizacoes cannot be assigned")
+
+	user := &User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	organizacaoID := uint(3)
+	require.NoError(t, repo.UpdateOrganizacao(ctx, user.ID, &organizacaoID))
+	found, err := repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.OrganizacaoID)
+	assert.Equal(t, uint(3), *found.OrganizacaoID)
+
+	require.NoError(t, repo.UpdateOrganizacao(ctx, user.ID, nil))
+	found, err = repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.OrganizacaoID)
+
+	assert.ErrorIs(t, repo.UpdateOrganizacao(ctx, 999, nil), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_TwoFactor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user := &User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	enabledAt := time.Now()
+	require.NoError(t, repo.UpdateTOTP(ctx, user.ID, "SECRET", &enabledAt))
+	found, err := repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET", found.TOTPSecret)
+	assert.True(t, found.TwoFactorEnabled())
+
+	fresh, err := repo.UpdateTOTPLastStep(ctx, user.ID, 100)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+	fresh, err = repo.UpdateTOTPLastStep(ctx, user.ID, 100)
+	require.NoError(t, err)
+	assert.False(t, fresh, "a step is accepted once")
+	fresh, err = repo.UpdateTOTPLastStep(ctx, user.ID, 99)
+	require.NoError(t, err)
+	assert.False(t, fresh, "earlier steps are refused")
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, user.ID, []string{"a", "b"}))
+	used, err := repo.UseRecoveryCode(ctx, user.ID, "a")
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = repo.UseRecoveryCode(ctx, user.ID, "a")
+	require.NoError(t, err)
+	assert.False(t, used, "a recovery code is used once")
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, user.ID, []string{"c"}))
+	used, err = repo.UseRecoveryCode(ctx, user.ID, "b")
+	require.NoError(t, err)
+	assert.False(t, used, "replaced codes are gone")
+
+	require.NoError(t, repo.UpdateTOTP(ctx, user.ID, "", nil))
+	found, err = repo.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.False(t, found.TwoFactorEnabled())
+	assert.Zero(t, found.TOTPLastStep)
+}
+
+func TestRepository_Invitations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	invitation := &Invitation{Email: "ana@example.com", Role: RoleAdmin, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.CreateInvitation(ctx, invitation))
+	require.NotZero(t, invitation.ID)
+
+	found, err := repo.FindInvitationByID(ctx, invitation.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "ana@example.com", found.Email)
+	assert.Nil(t, found.AcceptedAt)
+
+	accepted, err := repo.AcceptInvitation(ctx, invitation.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, accepted)
+	accepted, err = repo.AcceptInvitation(ctx, invitation.ID, time.Now())
+	require.NoError(t, err)
+	assert.False(t, accepted, "an invitation is accepted once")
+
+	found, err = repo.FindInvitationByID(ctx, 999)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	require.NoError(t, repo.DeleteInvitation(ctx, invitation.ID))
+	found, err = repo.FindInvitationByID(ctx, invitation.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "deleted invitations are not found")
+}
+
+func TestRepository_Identities(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user := &User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	found, err := repo.FindByIdentity(ctx, "google", "g-1")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	require.NoError(t, repo.CreateIdentity(ctx, &Identity{UserID: user.ID, Provider: "google", Subject: "g-1"}))
+	found, err = repo.FindByIdentity(ctx, "google", "g-1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, user.ID, found.ID)
+
+	err = repo.CreateIdentity(ctx, &Identity{UserID: user.ID, Provider: "google", Subject: "g-1"})
+	assert.Error(t, err, "an account is linked to one user")
+}
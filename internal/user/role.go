@@ -1,12 +1,33 @@
 package user
 
-import "time"
+import (
+	"time"
 
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+// Role names. Every user has RoleUser; the others grant the permissions listed in auth.
 const (
-	RoleUser  = "user"
-	RoleAdmin = "admin"
+	RoleUser      = "user"
+	RoleAdmin     = auth.RoleAdmin
+	RoleGestor    = auth.RoleGestor
+	RoleCorretor  = auth.RoleCorretor
+	RoleMarketing = auth.RoleMarketing
 )
 
+// Roles lists the known roles
+var Roles = []string{RoleUser, RoleAdmin, RoleGestor, RoleCorretor, RoleMarketing}
+
+// IsValidRole checks if name is one of the known roles
+func IsValidRole(name string) bool {
+	for _, role := range Roles {
+		if role == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Role represents a user role in the system
 type Role struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
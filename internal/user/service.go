@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/text/language"
 	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/events"
 )
 
 var (
@@ -18,6 +24,16 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	// ErrInvalidRole is returned when role is invalid
 	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidLocale is returned when a locale is not a valid BCP 47 language tag
+	ErrInvalidLocale = errors.New("invalid locale")
+	// ErrRoleNotFound is returned when a role does not exist
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrProtectedRole is returned when removing a role would lock the user out
+	ErrProtectedRole = errors.New("role cannot be removed")
+	// ErrCorretorNotFound is returned when linking a user to a corretor principal that does not exist
+	ErrCorretorNotFound = errors.New("corretor not found")
+	// ErrOrganizacaoNotFound is returned when assigning a user to an organizacao that does not exist
+	ErrOrganizacaoNotFound = errors.New("organizacao not found")
 )
 
 // Service defines user service interface
@@ -26,20 +42,49 @@ type Service interface {
 	AuthenticateUser(ctx context.Context, req LoginRequest) (*User, error)
 	GetUserByID(ctx context.Context, id uint) (*User, error)
 	UpdateUser(ctx context.Context, id uint, req UpdateUserRequest) (*User, error)
+	ChangePassword(ctx context.Context, id uint, req ChangePasswordRequest) (*User, error)
 	DeleteUser(ctx context.Context, id uint) error
 	ListUsers(ctx context.Context, filters UserFilterParams, page, perPage int) ([]User, int64, error)
 	PromoteToAdmin(ctx context.Context, userID uint) error
+	ListRoles(ctx context.Context) ([]Role, error)
+	AssignRole(ctx context.Context, userID uint, roleName string) (*User, error)
+	RemoveRole(ctx context.Context, userID uint, roleName string) (*User, error)
+	LinkCorretor(ctx context.Context, userID uint, corretorID *uint) (*User, error)
+	AssignOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) (*User, error)
+	EnrollTwoFactor(ctx context.Context, userID uint) (secret string, provisioningURI string, err error)
+	ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID uint, code string) error
+	VerifyTwoFactor(ctx context.Context, userID uint, code string) (*User, error)
+	VerifyTwoFactorLogin(ctx context.Context, challenge string, userID uint, code string) (*User, error)
+	InviteUser(ctx context.Context, req InviteUserRequest) (*Invitation, error)
+	AcceptInvitation(ctx context.Context, req AcceptInvitationRequest) (*User, error)
+	AuthenticateOAuth(ctx context.Context, profile auth.OAuthProfile) (*User, error)
 }
 
 type service struct {
-	repo Repository
+	repo             Repository
+	totpIssuer       string
+	loginLimiter     *auth.LoginLimiter
+	events           events.Publisher
+	invitationSender InvitationSender
+	invitationURL    string
+	invitationTTL    time.Duration
+	invitationKey    []byte
 }
 
+// ServiceOption configures optional service behaviour
+type ServiceOption func(*service)
+
 // NewService creates a new user service
-func NewService(repo Repository) Service {
-	return &service{
-		repo: repo,
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{
+		repo:       repo,
+		totpIssuer: defaultTOTPIssuer,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // RegisterUser registers a new user
@@ -92,20 +137,33 @@ func (s *service) RegisterUser(ctx context.Context, req RegisterRequest) (*User,
 	return user, nil
 }
 
-// AuthenticateUser authenticates a user with email and password
+// AuthenticateUser authenticates a user with email and password. With a login limiter, failed
+// attempts are counted per account and per address of ctx, and locked out ones get an
+// *auth.LockoutError.
 func (s *service) AuthenticateUser(ctx context.Context, req LoginRequest) (*User, error) {
+	if err := s.checkLoginLock(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
+		s.loginFailed(ctx, req.Email, nil, loginFailedInvalidCredentials)
 		return nil, ErrInvalidCredentials
 	}
 
 	if err := verifyPassword(user.PasswordHash, req.Password); err != nil {
+		s.loginFailed(ctx, req.Email, user, loginFailedInvalidCredentials)
 		return nil, ErrInvalidCredentials
 	}
 
+	// WHY: the password alone does not complete a 2FA login; clearing the failures here would let
+	// the second factor be guessed without ever locking the account
+	if !user.TwoFactorEnabled() {
+		s.loginSucceeded(ctx, req.Email)
+	}
 	return user, nil
 }
 
@@ -144,6 +202,13 @@ func (s *service) UpdateUser(ctx context.Context, id uint, req UpdateUserRequest
 		}
 		user.Email = req.Email
 	}
+	if req.Locale != "" {
+		tag, err := language.Parse(req.Locale)
+		if err != nil {
+			return nil, ErrInvalidLocale
+		}
+		user.Locale = tag.String()
+	}
 
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
@@ -152,6 +217,27 @@ func (s *service) UpdateUser(ctx context.Context, id uint, req UpdateUserRequest
 	return user, nil
 }
 
+// ChangePassword replaces the user's password after checking the current one, returning
+// ErrInvalidCredentials when it is wrong
+func (s *service) ChangePassword(ctx context.Context, id uint, req ChangePasswordRequest) (*User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.repo.UpdatePassword(ctx, id, hashedPassword); err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+	return user, nil
+}
+
 // DeleteUser deletes a user
 func (s *service) DeleteUser(ctx context.Context, id uint) error {
 	if err := s.repo.Delete(ctx, id); err != nil {
@@ -176,7 +262,7 @@ func (s *service) ListUsers(ctx context.Context, filters UserFilterParams, page,
 		return nil, 0, fmt.Errorf("perPage must be <= 100")
 	}
 
-	if filters.Role != "" && filters.Role != RoleUser && filters.Role != RoleAdmin {
+	if filters.Role != "" && !IsValidRole(filters.Role) {
 		return nil, 0, ErrInvalidRole
 	}
 
@@ -209,6 +295,120 @@ func (s *service) PromoteToAdmin(ctx context.Context, userID uint) error {
 	return nil
 }
 
+// ListRoles returns every role
+func (s *service) ListRoles(ctx context.Context) ([]Role, error) {
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// AssignRole gives a role to a user; assigning a role the user already has is not an error.
+// The new permissions apply to the access tokens issued afterwards.
+func (s *service) AssignRole(ctx context.Context, userID uint, roleName string) (*User, error) {
+	user, err := s.findUserAndRole(ctx, userID, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.HasRole(roleName) {
+		if err := s.repo.AssignRole(ctx, userID, roleName); err != nil {
+			return nil, fmt.Errorf("failed to assign role: %w", err)
+		}
+	}
+	return s.GetUserByID(ctx, userID)
+}
+
+// RemoveRole takes a role from a user. The user role cannot be removed, and neither can the
+// admin role of the admin making the request, so that no admin locks themselves out.
+func (s *service) RemoveRole(ctx context.Context, userID uint, roleName string) (*User, error) {
+	user, err := s.findUserAndRole(ctx, userID, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleName == RoleUser {
+		return nil, ErrProtectedRole
+	}
+	if actorID, ok := contextutil.UserIDFromContext(ctx); ok && actorID == userID && roleName == RoleAdmin {
+		return nil, ErrProtectedRole
+	}
+
+	if user.HasRole(roleName) {
+		if err := s.repo.RemoveRole(ctx, userID, roleName); err != nil {
+			return nil, fmt.Errorf("failed to remove role: %w", err)
+		}
+	}
+	return s.GetUserByID(ctx, userID)
+}
+
+// LinkCorretor sets the corretor principal the user works as, or unlinks them when corretorID is
+// nil. Corretor users can only change the imoveis of that corretor, from their next access token.
+func (s *service) LinkCorretor(ctx context.Context, userID uint, corretorID *uint) (*User, error) {
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if corretorID != nil {
+		exists, err := s.repo.CorretorExists(ctx, *corretorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find corretor: %w", err)
+		}
+		if !exists {
+			return nil, ErrCorretorNotFound
+		}
+	}
+
+	if err := s.repo.UpdateCorretor(ctx, userID, corretorID); err != nil {
+		return nil, fmt.Errorf("failed to link corretor: %w", err)
+	}
+	return s.GetUserByID(ctx, userID)
+}
+
+// AssignOrganizacao sets the organizacao the user belongs to, or makes them unscoped when
+// organizacaoID is nil. From their next access token the user's requests only see its data.
+func (s *service) AssignOrganizacao(ctx context.Context, userID uint, organizacaoID *uint) (*User, error) {
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if organizacaoID != nil {
+		exists, err := s.repo.OrganizacaoExists(ctx, *organizacaoID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organizacao: %w", err)
+		}
+		if !exists {
+			return nil, ErrOrganizacaoNotFound
+		}
+	}
+
+	if err := s.repo.UpdateOrganizacao(ctx, userID, organizacaoID); err != nil {
+		return nil, fmt.Errorf("failed to assign organizacao: %w", err)
+	}
+	return s.GetUserByID(ctx, userID)
+}
+
+// findUserAndRole loads the user whose roles are changed and checks that the role exists
+func (s *service) findUserAndRole(ctx context.Context, userID uint, roleName string) (*User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	role, err := s.repo.FindRoleByName(ctx, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	return user, nil
+}
+
 // hashPassword hashes a plain text password using bcrypt
 func hashPassword(password string) (string, error) {
 	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -7,8 +7,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/contextutil"
 )
 
 func TestNewService(t *testing.T) {
@@ -257,11 +260,12 @@ func TestService_GetUserByID(t *testing.T) {
 
 func TestService_UpdateUser(t *testing.T) {
 	tests := []struct {
-		name        string
-		userID      uint
-		request     UpdateUserRequest
-		setupMock   func(*MockRepository)
-		expectedErr error
+		name           string
+		userID         uint
+		request        UpdateUserRequest
+		setupMock      func(*MockRepository)
+		expectedErr    error
+		expectedLocale string
 	}{
 		{
 			name:   "successful update",
@@ -303,6 +307,27 @@ func TestService_UpdateUser(t *testing.T) {
 			},
 			expectedErr: ErrEmailExists,
 		},
+		{
+			name:    "locale is stored in canonical form",
+			userID:  1,
+			request: UpdateUserRequest{Locale: "pt-br"},
+			setupMock: func(m *MockRepository) {
+				user := &User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+				m.On("FindByID", mock.Anything, uint(1)).Return(user, nil)
+				m.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+			},
+			expectedLocale: "pt-BR",
+		},
+		{
+			name:    "invalid locale",
+			userID:  1,
+			request: UpdateUserRequest{Locale: "not a locale"},
+			setupMock: func(m *MockRepository) {
+				user := &User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+				m.On("FindByID", mock.Anything, uint(1)).Return(user, nil)
+			},
+			expectedErr: ErrInvalidLocale,
+		},
 	}
 
 	for _, tt := range tests {
@@ -326,6 +351,9 @@ func TestService_UpdateUser(t *testing.T) {
 				if tt.request.Email != "" {
 					assert.Equal(t, tt.request.Email, user.Email)
 				}
+				if tt.expectedLocale != "" {
+					assert.Equal(t, tt.expectedLocale, user.Locale)
+				}
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -390,6 +418,43 @@ func TestService_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestService_ChangePassword(t *testing.T) {
+	currentHash, err := hashPassword("password123")
+	require.NoError(t, err)
+
+	t.Run("replaces the password", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, PasswordHash: currentHash}, nil)
+		var newHash string
+		mockRepo.On("UpdatePassword", mock.Anything, uint(1), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { newHash = args.String(2) }).
+			Return(nil)
+
+		user, err := NewService(mockRepo).ChangePassword(context.Background(), 1, ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		assert.NoError(t, verifyPassword(newHash, "newpassword"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, PasswordHash: currentHash}, nil)
+
+		_, err := NewService(mockRepo).ChangePassword(context.Background(), 1, ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "newpassword"})
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		mockRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, nil)
+
+		_, err := NewService(mockRepo).ChangePassword(context.Background(), 1, ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
 func TestHashPassword(t *testing.T) {
 	password := "testpassword123"
 	hashedPassword, err := hashPassword(password)
@@ -651,6 +716,262 @@ func TestService_PromoteToAdmin(t *testing.T) {
 	}
 }
 
+func TestService_AssignRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		roleName    string
+		setupMocks  func(*MockRepository)
+		expectedErr error
+	}{
+		{
+			name:     "assigns the role",
+			roleName: RoleCorretor,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}}}, nil).Once()
+				m.On("FindRoleByName", mock.Anything, RoleCorretor).Return(&Role{ID: 4, Name: RoleCorretor}, nil)
+				m.On("AssignRole", mock.Anything, uint(1), RoleCorretor).Return(nil)
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}, {Name: RoleCorretor}}}, nil).Once()
+			},
+		},
+		{
+			name:     "role already assigned - idempotent",
+			roleName: RoleCorretor,
+			setupMocks: func(m *MockRepository) {
+				user := &User{ID: 1, Roles: []Role{{Name: RoleUser}, {Name: RoleCorretor}}}
+				m.On("FindByID", mock.Anything, uint(1)).Return(user, nil)
+				m.On("FindRoleByName", mock.Anything, RoleCorretor).Return(&Role{ID: 4, Name: RoleCorretor}, nil)
+			},
+		},
+		{
+			name:     "user not found",
+			roleName: RoleCorretor,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(nil, nil)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:     "role not found",
+			roleName: "superuser",
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+				m.On("FindRoleByName", mock.Anything, "superuser").Return(nil, nil)
+			},
+			expectedErr: ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewService(mockRepo)
+			user, err := service.AssignRole(context.Background(), 1, tt.roleName)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, user.HasRole(tt.roleName))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_RemoveRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		roleName    string
+		setupMocks  func(*MockRepository)
+		expectedErr error
+	}{
+		{
+			name:     "removes the role",
+			ctx:      contextutil.WithUserID(context.Background(), 9),
+			roleName: RoleAdmin,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}, {Name: RoleAdmin}}}, nil).Once()
+				m.On("FindRoleByName", mock.Anything, RoleAdmin).Return(&Role{ID: 2, Name: RoleAdmin}, nil)
+				m.On("RemoveRole", mock.Anything, uint(1), RoleAdmin).Return(nil)
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}}}, nil).Once()
+			},
+		},
+		{
+			name:     "role not assigned - idempotent",
+			ctx:      context.Background(),
+			roleName: RoleMarketing,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}}}, nil)
+				m.On("FindRoleByName", mock.Anything, RoleMarketing).Return(&Role{ID: 5, Name: RoleMarketing}, nil)
+			},
+		},
+		{
+			name:     "user role is protected",
+			ctx:      context.Background(),
+			roleName: RoleUser,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}}}, nil)
+				m.On("FindRoleByName", mock.Anything, RoleUser).Return(&Role{ID: 1, Name: RoleUser}, nil)
+			},
+			expectedErr: ErrProtectedRole,
+		},
+		{
+			name:     "own admin role is protected",
+			ctx:      contextutil.WithUserID(context.Background(), 1),
+			roleName: RoleAdmin,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Roles: []Role{{Name: RoleUser}, {Name: RoleAdmin}}}, nil)
+				m.On("FindRoleByName", mock.Anything, RoleAdmin).Return(&Role{ID: 2, Name: RoleAdmin}, nil)
+			},
+			expectedErr: ErrProtectedRole,
+		},
+		{
+			name:     "role not found",
+			ctx:      context.Background(),
+			roleName: "superuser",
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+				m.On("FindRoleByName", mock.Anything, "superuser").Return(nil, nil)
+			},
+			expectedErr: ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewService(mockRepo)
+			user, err := service.RemoveRole(tt.ctx, 1, tt.roleName)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.False(t, user.HasRole(tt.roleName))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_LinkCorretor(t *testing.T) {
+	corretorID := uint(5)
+	linked := &User{ID: 1, CorretorID: &corretorID}
+
+	tests := []struct {
+		name        string
+		corretorID  *uint
+		setupMocks  func(*MockRepository)
+		expectedErr error
+	}{
+		{
+			name:       "links the corretor",
+			corretorID: &corretorID,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil).Once()
+				m.On("CorretorExists", mock.Anything, uint(5)).Return(true, nil)
+				m.On("UpdateCorretor", mock.Anything, uint(1), &corretorID).Return(nil)
+				m.On("FindByID", mock.Anything, uint(1)).Return(linked, nil).Once()
+			},
+		},
+		{
+			name:       "unlinks the user",
+			corretorID: nil,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(linked, nil).Once()
+				m.On("UpdateCorretor", mock.Anything, uint(1), (*uint)(nil)).Return(nil)
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil).Once()
+			},
+		},
+		{
+			name:       "user not found",
+			corretorID: &corretorID,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(nil, nil)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:       "corretor not found",
+			corretorID: &corretorID,
+			setupMocks: func(m *MockRepository) {
+				m.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+				m.On("CorretorExists", mock.Anything, uint(5)).Return(false, nil)
+			},
+			expectedErr: ErrCorretorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewService(mockRepo)
+			user, err := service.LinkCorretor(context.Background(), 1, tt.corretorID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.corretorID, user.CorretorID)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_AssignOrganizacao(t *testing.T) {
+	organizacaoID := uint(3)
+
+	t.Run("assigns the organizacao", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil).Once()
+		mockRepo.On("OrganizacaoExists", mock.Anything, uint(3)).Return(true, nil)
+		mockRepo.On("UpdateOrganizacao", mock.Anything, uint(1), &organizacaoID).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, OrganizacaoID: &organizacaoID}, nil).Once()
+
+		user, err := NewService(mockRepo).AssignOrganizacao(context.Background(), 1, &organizacaoID)
+		assert.NoError(t, err)
+		assert.Equal(t, &organizacaoID, user.OrganizacaoID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unscopes the user", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, OrganizacaoID: &organizacaoID}, nil).Once()
+		mockRepo.On("UpdateOrganizacao", mock.Anything, uint(1), (*uint)(nil)).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil).Once()
+
+		user, err := NewService(mockRepo).AssignOrganizacao(context.Background(), 1, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, user.OrganizacaoID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("organizacao not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+		mockRepo.On("OrganizacaoExists", mock.Anything, uint(3)).Return(false, nil)
+
+		user, err := NewService(mockRepo).AssignOrganizacao(context.Background(), 1, &organizacaoID)
+		assert.ErrorIs(t, err, ErrOrganizacaoNotFound)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestService_RegisterUser_ErrorPaths(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,216 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+const (
+	// defaultTOTPIssuer names the account in authenticator apps when no issuer is configured
+	defaultTOTPIssuer = "Triiio"
+	// recoveryCodeCount is the number of recovery codes issued when 2FA is enabled
+	recoveryCodeCount = 10
+)
+
+var (
+	// ErrTwoFactorEnabled is returned when enrolling a user who already has 2FA enabled
+	ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+	// ErrTwoFactorNotEnabled is returned when verifying or disabling 2FA of a user without it
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+	// ErrTwoFactorNotEnrolled is returned when confirming 2FA before enrolling
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication not enrolled")
+	// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code is wrong or already used
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+// recoveryCodeEncoding avoids padding and lowercase so codes are easy to type
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// WithTOTPIssuer sets the issuer shown next to the account in authenticator apps
+func WithTOTPIssuer(issuer string) ServiceOption {
+	return func(s *service) {
+		if issuer != "" {
+			s.totpIssuer = issuer
+		}
+	}
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the user and returns it with its provisioning
+// URI. 2FA is only enabled once a code of the secret is confirmed with ConfirmTwoFactor.
+func (s *service) EnrollTwoFactor(ctx context.Context, userID uint) (string, string, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TwoFactorEnabled() {
+		return "", "", ErrTwoFactorEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	if err := s.repo.UpdateTOTP(ctx, userID, secret, nil); err != nil {
+		return "", "", fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+	return secret, auth.TOTPProvisioningURI(s.totpIssuer, user.Email, secret), nil
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves their authenticator holds the enrolled secret,
+// returning the recovery codes. The codes are not stored in clear and cannot be shown again.
+func (s *service) ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if _, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now()); !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	enabledAt := time.Now()
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.UpdateTOTP(txCtx, userID, user.TOTPSecret, &enabledAt); err != nil {
+			return err
+		}
+		return s.repo.ReplaceRecoveryCodes(txCtx, userID, hashes)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor turns 2FA off after checking a TOTP or recovery code, dropping the secret and
+// the remaining recovery codes
+func (s *service) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	user, err := s.VerifyTwoFactor(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.UpdateTOTP(txCtx, user.ID, "", nil); err != nil {
+			return err
+		}
+		return s.repo.ReplaceRecoveryCodes(txCtx, user.ID, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// VerifyTwoFactor checks a TOTP code, or a recovery code, of a user with 2FA enabled. Each TOTP
+// code and each recovery code is accepted only once.
+func (s *service) VerifyTwoFactor(ctx context.Context, userID uint, code string) (*User, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyTwoFactorCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// VerifyTwoFactorLogin completes a login with the code sent for its two-factor challenge token.
+// With a login limiter, wrong codes count as failed logins of the account, the challenge is
+// invalidated (auth.ErrChallengeExhausted) after too many of them, and the failed logins of the
+// account are only cleared once the second factor passes.
+func (s *service) VerifyTwoFactorLogin(ctx context.Context, challenge string, userID uint, code string) (*User, error) {
+	if err := s.checkChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLoginLock(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyTwoFactorCode(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginFailed(ctx, user.Email, user, loginFailedInvalidTwoFactorCode)
+			s.challengeFailed(ctx, challenge, user)
+		}
+		return nil, err
+	}
+
+	s.loginSucceeded(ctx, user.Email)
+	return user, nil
+}
+
+// verifyTwoFactorCode checks a TOTP or recovery code of user, consuming it
+func (s *service) verifyTwoFactorCode(ctx context.Context, user *User, code string) error {
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == auth.TOTPDigits {
+		step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		fresh, err := s.repo.UpdateTOTPLastStep(ctx, user.ID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record TOTP code: %w", err)
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// generateRecoveryCodes returns n random codes formatted as XXXXX-XXXXX, with their hashes
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryCodeEncoding.EncodeToString(b)[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code ignoring case, spaces and dashes
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
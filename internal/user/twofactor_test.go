@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vahiiiid/go-rest-api-boilerplate/internal/auth"
+)
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := auth.TOTPCode(secret, auth.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestService_EnrollTwoFactor(t *testing.T) {
+	t.Run("stores a pending secret", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, Email: "ana@example.com"}, nil)
+		mockRepo.On("UpdateTOTP", mock.Anything, uint(1), mock.AnythingOfType("string"), (*time.Time)(nil)).Return(nil)
+
+		secret, uri, err := NewService(mockRepo, WithTOTPIssuer("Triiio Test")).EnrollTwoFactor(context.Background(), 1)
+		require.NoError(t, err)
+		assert.NotEmpty(t, secret)
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Triiio%20Test:ana@example.com?"))
+		assert.Contains(t, uri, "secret="+secret)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		enabledAt := time.Now()
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, TOTPSecret: "X", TwoFactorAt: &enabledAt}, nil)
+
+		_, _, err := NewService(mockRepo).EnrollTwoFactor(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrTwoFactorEnabled)
+		mockRepo.AssertNotCalled(t, "UpdateTOTP")
+	})
+}
+
+func TestService_ConfirmTwoFactor(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	t.Run("enables 2FA and issues recovery codes", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, TOTPSecret: secret}, nil)
+		mockRepo.On("UpdateTOTP", mock.Anything, uint(1), secret, mock.AnythingOfType("*time.Time")).Return(nil)
+		var hashes []string
+		mockRepo.On("ReplaceRecoveryCodes", mock.Anything, uint(1), mock.Anything).
+			Run(func(args mock.Arguments) { hashes = args.Get(2).([]string) }).
+			Return(nil)
+
+		codes, err := NewService(mockRepo).ConfirmTwoFactor(context.Background(), 1, currentTOTPCode(t, secret))
+		require.NoError(t, err)
+		require.Len(t, codes, recoveryCodeCount)
+		require.Len(t, hashes, recoveryCodeCount)
+		assert.Regexp(t, `^[A-Z2-7]{5}-[A-Z2-7]{5}$`, codes[0])
+		assert.Equal(t, hashRecoveryCode(codes[0]), hashes[0])
+		assert.NotContains(t, hashes, codes[0], "recovery codes are stored hashed")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, TOTPSecret: secret}, nil)
+
+		_, err := NewService(mockRepo).ConfirmTwoFactor(context.Background(), 1, "000000x")
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+		mockRepo.AssertNotCalled(t, "UpdateTOTP")
+	})
+
+	t.Run("not enrolled", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+
+		_, err := NewService(mockRepo).ConfirmTwoFactor(context.Background(), 1, "123456")
+		assert.ErrorIs(t, err, ErrTwoFactorNotEnrolled)
+	})
+}
+
+func TestService_VerifyTwoFactor(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	enabled := &User{ID: 1, TOTPSecret: secret, TwoFactorAt: &enabledAt}
+
+	t.Run("accepts a TOTP code once", func(t *testing.T) {
+		code := currentTOTPCode(t, secret)
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(enabled, nil)
+		mockRepo.On("UpdateTOTPLastStep", mock.Anything, uint(1), mock.AnythingOfType("int64")).Return(true, nil).Once()
+		mockRepo.On("UpdateTOTPLastStep", mock.Anything, uint(1), mock.AnythingOfType("int64")).Return(false, nil).Once()
+		service := NewService(mockRepo)
+
+		user, err := service.VerifyTwoFactor(context.Background(), 1, code)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+
+		_, err = service.VerifyTwoFactor(context.Background(), 1, code)
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode, "a replayed code is refused")
+	})
+
+	t.Run("accepts a recovery code", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(enabled, nil)
+		mockRepo.On("UseRecoveryCode", mock.Anything, uint(1), hashRecoveryCode("ABCDE-FGHIJ")).Return(true, nil)
+
+		_, err := NewService(mockRepo).VerifyTwoFactor(context.Background(), 1, "abcde fghij")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown recovery code", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(enabled, nil)
+		mockRepo.On("UseRecoveryCode", mock.Anything, uint(1), mock.Anything).Return(false, nil)
+
+		_, err := NewService(mockRepo).VerifyTwoFactor(context.Background(), 1, "ABCDE-FGHIJ")
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("2FA not enabled", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1}, nil)
+
+		_, err := NewService(mockRepo).VerifyTwoFactor(context.Background(), 1, "123456")
+		assert.ErrorIs(t, err, ErrTwoFactorNotEnabled)
+	})
+}
+
+func TestService_DisableTwoFactor(t *testing.T) {
+	enabledAt := time.Now()
+	mockRepo := new(MockRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&User{ID: 1, TOTPSecret: "X", TwoFactorAt: &enabledAt}, nil)
+	mockRepo.On("UseRecoveryCode", mock.Anything, uint(1), hashRecoveryCode("ABCDE-FGHIJ")).Return(true, nil)
+	mockRepo.On("UpdateTOTP", mock.Anything, uint(1), "", (*time.Time)(nil)).Return(nil)
+	mockRepo.On("ReplaceRecoveryCodes", mock.Anything, uint(1), []string(nil)).Return(nil)
+
+	err := NewService(mockRepo).DisableTwoFactor(context.Background(), 1, "ABCDE-FGHIJ")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_sliders_enabled;
+
+ALTER TABLE sliders DROP COLUMN IF EXISTS enabled;
+
+COMMIT;
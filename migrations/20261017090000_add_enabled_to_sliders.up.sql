@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_sliders_enabled ON sliders(enabled);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_enderecos_latitude_longitude;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_enderecos_latitude_longitude ON enderecos(latitude, longitude);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_imovel_caracteristicas_caracteristica_id;
+
+COMMIT;
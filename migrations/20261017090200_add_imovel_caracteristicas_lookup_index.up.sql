@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_imovel_caracteristicas_caracteristica_id ON imovel_caracteristicas(caracteristica_id, imovel_id);
+
+COMMIT;
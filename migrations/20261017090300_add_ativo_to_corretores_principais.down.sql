@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_corretores_principais_ativo;
+
+ALTER TABLE corretores_principais DROP COLUMN IF EXISTS ativo;
+
+COMMIT;
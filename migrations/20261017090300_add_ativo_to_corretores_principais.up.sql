@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE corretores_principais ADD COLUMN IF NOT EXISTS ativo BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_corretores_principais_ativo ON corretores_principais(ativo);
+
+COMMIT;
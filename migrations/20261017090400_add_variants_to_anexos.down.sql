@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE anexos DROP COLUMN IF EXISTS variants;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE anexos ADD COLUMN IF NOT EXISTS variants JSONB;
+
+COMMIT;
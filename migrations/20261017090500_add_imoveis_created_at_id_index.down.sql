@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_imoveis_created_at_id;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_imoveis_created_at_id ON imoveis(created_at, id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_imoveis_slug;
+
+ALTER TABLE imoveis DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS meta_title;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS slug;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS slug VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS meta_title VARCHAR(70) NOT NULL DEFAULT '';
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS meta_description VARCHAR(160) NOT NULL DEFAULT '';
+
+-- Properties created before slugs existed keep an empty slug until their next update
+CREATE UNIQUE INDEX IF NOT EXISTS idx_imoveis_slug ON imoveis(slug) WHERE slug <> '';
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS historico_precos (
+    id BIGSERIAL PRIMARY KEY,
+    imovel_id BIGINT NOT NULL REFERENCES imoveis(id) ON DELETE CASCADE,
+    tipo VARCHAR(10) NOT NULL,
+    preco DECIMAL,
+    preco_anterior DECIMAL,
+    origem VARCHAR(20) NOT NULL DEFAULT 'API',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_historico_precos_imovel_id ON historico_precos(imovel_id, tipo, id);
+
+-- Seed the current prices so the first change already has a previous value
+INSERT INTO historico_precos (imovel_id, tipo, preco, origem, created_at)
+SELECT i.id, 'VENDA', pv.preco, 'MIGRATION', COALESCE(pv.updated_at, NOW())
+FROM imoveis i
+JOIN preco_vendas pv ON pv.id = i.preco_venda_id AND pv.deleted_at IS NULL
+WHERE i.deleted_at IS NULL;
+
+INSERT INTO historico_precos (imovel_id, tipo, preco, origem, created_at)
+SELECT i.id, 'ALUGUEL', pa.preco, 'MIGRATION', COALESCE(pa.updated_at, NOW())
+FROM imoveis i
+JOIN preco_aluguels pa ON pa.id = i.preco_aluguel_id AND pa.deleted_at IS NULL
+WHERE i.deleted_at IS NULL;
+
+COMMIT;
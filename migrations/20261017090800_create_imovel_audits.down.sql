@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS imovel_audits;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- No foreign key on imovel_id: the trail must outlive permanently deleted properties
+CREATE TABLE IF NOT EXISTS imovel_audits (
+    id BIGSERIAL PRIMARY KEY,
+    imovel_id BIGINT NOT NULL,
+    user_id BIGINT,
+    entidade VARCHAR(20) NOT NULL,
+    acao VARCHAR(20) NOT NULL,
+    origem VARCHAR(20) NOT NULL DEFAULT 'API',
+    alteracoes JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_imovel_audits_imovel_id ON imovel_audits(imovel_id, id);
+CREATE INDEX IF NOT EXISTS idx_imovel_audits_user_id ON imovel_audits(user_id);
+
+COMMIT;
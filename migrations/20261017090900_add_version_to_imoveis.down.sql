@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE imoveis DROP COLUMN IF EXISTS version;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Optimistic locking: incremented by every edit, updates must send the version they read
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
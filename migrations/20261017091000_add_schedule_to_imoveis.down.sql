@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_imoveis_expira_em;
+DROP INDEX IF EXISTS idx_imoveis_publicar_em;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS expira_em;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS publicar_em;
+
+COMMIT;
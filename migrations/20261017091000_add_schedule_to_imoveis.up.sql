@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Scheduled publication and expiration, applied by the publication scheduler
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS publicar_em TIMESTAMP WITH TIME ZONE;
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS expira_em TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_imoveis_publicar_em ON imoveis(publicar_em);
+CREATE INDEX IF NOT EXISTS idx_imoveis_expira_em ON imoveis(expira_em);
+
+COMMIT;
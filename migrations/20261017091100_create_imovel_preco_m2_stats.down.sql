@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS imovel_preco_m2_stats;
+
+COMMIT;
@@ -0,0 +1,42 @@
+BEGIN;
+
+-- Price per m² of published imoveis per cidade, bairro, tipo and objetivo. Rows with an empty tipo
+-- aggregate every tipo of the bairro. Refreshed periodically by the analytics refresher.
+CREATE MATERIALIZED VIEW IF NOT EXISTS imovel_preco_m2_stats AS
+WITH precos AS (
+    SELECT
+        enderecos.cidade AS cidade,
+        COALESCE(enderecos.bairro, '') AS bairro,
+        imoveis.tipo AS tipo,
+        imoveis.objetivo AS objetivo,
+        CASE WHEN imoveis.objetivo = 'ALUGAR' THEN preco_aluguels.preco ELSE preco_vendas.preco END / imoveis.metragem AS preco_m2
+    FROM imoveis
+    INNER JOIN enderecos ON enderecos.id = imoveis.endereco_id
+    LEFT JOIN preco_vendas ON preco_vendas.id = imoveis.preco_venda_id
+        AND preco_vendas.deleted_at IS NULL AND preco_vendas.ativo
+    LEFT JOIN preco_aluguels ON preco_aluguels.id = imoveis.preco_aluguel_id
+        AND preco_aluguels.deleted_at IS NULL AND preco_aluguels.ativo
+    WHERE imoveis.deleted_at IS NULL
+        AND imoveis.status = 'PUBLICADO'
+        AND imoveis.published
+        AND imoveis.metragem > 0
+)
+SELECT
+    cidade,
+    bairro,
+    COALESCE(tipo, '') AS tipo,
+    objetivo,
+    COUNT(*) AS total,
+    AVG(preco_m2) AS media,
+    PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY preco_m2) AS mediana,
+    MIN(preco_m2) AS minimo,
+    MAX(preco_m2) AS maximo,
+    NOW() AS atualizado_em
+FROM precos
+WHERE preco_m2 IS NOT NULL
+GROUP BY GROUPING SETS ((cidade, bairro, tipo, objetivo), (cidade, bairro, objetivo));
+
+-- Required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_imovel_preco_m2_stats_key ON imovel_preco_m2_stats(cidade, bairro, tipo, objetivo);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_imoveis_status_updated_at;
+ALTER TABLE imovel_audits DROP COLUMN IF EXISTS motivo;
+ALTER TABLE organizacoes DROP COLUMN IF EXISTS dias_arquivamento;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Archive period of the organizacao's imoveis; NULL uses the configured default, 0 never archives
+ALTER TABLE organizacoes ADD COLUMN IF NOT EXISTS dias_arquivamento INTEGER;
+
+-- Why a job (stale listings archiver, scheduler) changed a property
+ALTER TABLE imovel_audits ADD COLUMN IF NOT EXISTS motivo TEXT;
+
+-- Stale listings are looked up by status and last update
+CREATE INDEX IF NOT EXISTS idx_imoveis_status_updated_at ON imoveis(status, updated_at);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS import_sync_states;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS import_checksum;
+
+COMMIT;
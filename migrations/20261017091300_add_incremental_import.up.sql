@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Checksum of the external listing last imported; incremental imports skip unchanged listings
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS import_checksum VARCHAR(64);
+
+-- Start of the last import run without failures, per integration source
+CREATE TABLE IF NOT EXISTS import_sync_states (
+    source VARCHAR(255) PRIMARY KEY,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
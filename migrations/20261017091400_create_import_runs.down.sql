@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS import_run_items;
+DROP TABLE IF EXISTS import_runs;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+-- Executions of the importer and their counts
+CREATE TABLE IF NOT EXISTS import_runs (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    full_sync BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id BIGINT,
+    total INTEGER NOT NULL DEFAULT 0,
+    created INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    unchanged INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_runs_started_at ON import_runs(started_at);
+
+-- Listings that failed to import, per run
+CREATE TABLE IF NOT EXISTS import_run_items (
+    id BIGSERIAL PRIMARY KEY,
+    run_id BIGINT NOT NULL REFERENCES import_runs(id) ON DELETE CASCADE,
+    external_id BIGINT,
+    codigo VARCHAR(255),
+    error TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_run_items_run_id ON import_run_items(run_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS removed;
+
+DROP INDEX IF EXISTS idx_imoveis_removido_origem_em;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS removido_origem_em;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- When a full import last found the listing of the property gone from the source
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS removido_origem_em TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_imoveis_removido_origem_em ON imoveis(removido_origem_em);
+
+-- Imported properties each run found removed from the source
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS removed INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS throttle_ms;
+ALTER TABLE import_runs DROP COLUMN IF EXISTS throttled;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Requests of each run held back by the rate limit of the external API, and how long they waited
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS throttled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS throttle_ms BIGINT NOT NULL DEFAULT 0;
+
+COMMIT;
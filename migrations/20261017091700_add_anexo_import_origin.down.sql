@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_anexos_content_hash;
+ALTER TABLE anexos DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE anexos DROP COLUMN IF EXISTS external_url;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Imported images stored in our storage keep the URL they were downloaded from, and the hash of
+-- their content so the same image is stored once
+ALTER TABLE anexos ADD COLUMN IF NOT EXISTS external_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE anexos ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_anexos_content_hash ON anexos(content_hash);
+
+COMMIT;
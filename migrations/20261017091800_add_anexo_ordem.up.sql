@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Position of imported images in the listing of the source; anexos are no longer recreated on
+-- every import, so the order is kept apart from the ids
+ALTER TABLE anexos ADD COLUMN IF NOT EXISTS ordem INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_plantas_id_integracao;
+DROP INDEX IF EXISTS idx_torres_id_integracao;
+ALTER TABLE plantas DROP COLUMN IF EXISTS id_integracao;
+ALTER TABLE torres DROP COLUMN IF EXISTS id_integracao;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- External IDs of imported torres and plantas, so each import updates them in place; rows added
+-- by hand keep an empty id_integracao and are left alone by the import
+ALTER TABLE torres ADD COLUMN IF NOT EXISTS id_integracao TEXT NOT NULL DEFAULT '';
+ALTER TABLE plantas ADD COLUMN IF NOT EXISTS id_integracao TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_torres_id_integracao ON torres (id_integracao);
+CREATE INDEX IF NOT EXISTS idx_plantas_id_integracao ON plantas (id_integracao);
+
+COMMIT;
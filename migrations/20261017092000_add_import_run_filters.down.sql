@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS filters;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Filters that scoped an import run to part of the catalog, as JSON; NULL for complete runs
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS filters TEXT;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS import_conflicts;
+ALTER TABLE imoveis DROP COLUMN IF EXISTS locked_fields;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Fields of the property edited by hand, as a JSON array; the importer leaves them alone
+ALTER TABLE imoveis ADD COLUMN IF NOT EXISTS locked_fields JSONB;
+
+-- Locally edited fields the source changed, held for review
+CREATE TABLE IF NOT EXISTS import_conflicts (
+    id BIGSERIAL PRIMARY KEY,
+    imovel_id BIGINT NOT NULL REFERENCES imoveis(id) ON DELETE CASCADE,
+    campo VARCHAR(50) NOT NULL,
+    valor_local TEXT,
+    valor_remoto TEXT,
+    resolucao VARCHAR(10),
+    resolvido_em TIMESTAMP WITH TIME ZONE,
+    resolvido_por BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_conflicts_imovel_id ON import_conflicts(imovel_id);
+
+-- One pending conflict per field
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_conflicts_pending ON import_conflicts(imovel_id, campo) WHERE resolvido_em IS NULL;
+
+COMMIT;
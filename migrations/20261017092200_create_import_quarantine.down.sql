@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS quarantined;
+DROP TABLE IF EXISTS import_quarantine;
+
+COMMIT;
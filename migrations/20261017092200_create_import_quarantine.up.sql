@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Listings of the source that failed validation, held with their payload for review
+CREATE TABLE IF NOT EXISTS import_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(255) NOT NULL,
+    external_id BIGINT NOT NULL,
+    codigo VARCHAR(255),
+    violations JSONB,
+    payload JSONB,
+    checksum VARCHAR(64),
+    status VARCHAR(10) NOT NULL,
+    resolvido_em TIMESTAMP WITH TIME ZONE,
+    resolvido_por BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_quarantine_listing ON import_quarantine(source, external_id);
+CREATE INDEX IF NOT EXISTS idx_import_quarantine_status ON import_quarantine(status);
+
+-- Listings each run held in quarantine
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS quarantined INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
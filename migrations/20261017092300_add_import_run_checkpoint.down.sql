@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS resumed_from;
+ALTER TABLE import_runs DROP COLUMN IF EXISTS checkpoint_external_id;
+ALTER TABLE import_runs DROP COLUMN IF EXISTS checkpoint;
+
+COMMIT;
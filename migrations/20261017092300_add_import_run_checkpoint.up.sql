@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Listings from the start of the list each run went through, so an interrupted run can be resumed
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS checkpoint INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS checkpoint_external_id BIGINT NOT NULL DEFAULT 0;
+
+-- The interrupted run a run resumed
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS resumed_from BIGINT REFERENCES import_runs(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE slider_items DROP COLUMN IF EXISTS active_until;
+ALTER TABLE slider_items DROP COLUMN IF EXISTS active_from;
+ALTER TABLE slider_items DROP COLUMN IF EXISTS enabled;
+
+ALTER TABLE sliders DROP COLUMN IF EXISTS active_until;
+ALTER TABLE sliders DROP COLUMN IF EXISTS active_from;
+
+COMMIT;
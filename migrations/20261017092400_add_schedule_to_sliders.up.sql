@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS active_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS active_until TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS enabled BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS active_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS active_until TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
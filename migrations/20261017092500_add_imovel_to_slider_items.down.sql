@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_slider_items_imovel_id;
+
+ALTER TABLE slider_items DROP COLUMN IF EXISTS imovel_id;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Property a slider item renders live data from
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS imovel_id BIGINT REFERENCES imoveis(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_slider_items_imovel_id ON slider_items(imovel_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS slider_item_stats;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Daily impression and click counters of slider items
+CREATE TABLE IF NOT EXISTS slider_item_stats (
+    slider_item_id BIGINT NOT NULL REFERENCES slider_items(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (slider_item_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slider_item_stats_day ON slider_item_stats(day);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_sliders_location_locale;
+
+ALTER TABLE sliders DROP COLUMN IF EXISTS audience;
+ALTER TABLE sliders DROP COLUMN IF EXISTS locale;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Locale and audience variants of a location; the empty locale is the default variant
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS audience VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sliders_location_locale ON sliders(location, locale);
+
+COMMIT;
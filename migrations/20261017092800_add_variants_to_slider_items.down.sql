@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE slider_items DROP COLUMN IF EXISTS variants;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Responsive renditions of slider item images uploaded through POST /sliders/:id/items/upload
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS variants JSONB;
+
+COMMIT;
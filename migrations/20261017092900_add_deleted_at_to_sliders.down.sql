@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Deleted sliders would otherwise reappear once the column is gone
+DELETE FROM slider_items WHERE deleted_at IS NOT NULL OR slider_id IN (SELECT id FROM sliders WHERE deleted_at IS NOT NULL);
+DELETE FROM sliders WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_sliders_location_locale;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sliders_location_locale ON sliders(location, locale);
+
+DROP INDEX IF EXISTS idx_slider_items_deleted_at;
+DROP INDEX IF EXISTS idx_sliders_deleted_at;
+
+ALTER TABLE slider_items DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE sliders DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Soft deletes; deleted sliders and items are purged by the purge_sliders maintenance task
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE slider_items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_sliders_deleted_at ON sliders(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_slider_items_deleted_at ON slider_items(deleted_at);
+
+-- A deleted slider must not keep its location and locale from a new slider
+DROP INDEX IF EXISTS idx_sliders_location_locale;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sliders_location_locale ON sliders(location, locale) WHERE deleted_at IS NULL;
+
+COMMIT;
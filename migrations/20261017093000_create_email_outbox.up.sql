@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Emails waiting to be sent by the background dispatcher. Failed sends are retried with backoff
+-- until max_attempts, then kept as DEAD for review.
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    recipients JSONB NOT NULL,
+    cc JSONB,
+    bcc JSONB,
+    subject VARCHAR(500) NOT NULL,
+    body TEXT NOT NULL,
+    is_html BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(10) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_due ON email_outbox(status, next_attempt_at);
+
+COMMIT;
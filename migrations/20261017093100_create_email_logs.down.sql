@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS email_logs;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS message_id;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS template_name;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+-- Template and Message-ID of queued emails, recorded in their send log
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS template_name VARCHAR(100);
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS message_id VARCHAR(255);
+
+-- One row per send attempt of a queued email, kept for support ("did the client receive it?")
+CREATE TABLE IF NOT EXISTS email_logs (
+    id BIGSERIAL PRIMARY KEY,
+    email_id BIGINT NOT NULL,
+    recipients JSONB NOT NULL,
+    cc JSONB,
+    bcc JSONB,
+    subject VARCHAR(500) NOT NULL,
+    template_name VARCHAR(100),
+    status VARCHAR(10) NOT NULL,
+    error TEXT,
+    message_id VARCHAR(255),
+    attempt INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_logs_email_id ON email_logs(email_id);
+CREATE INDEX IF NOT EXISTS idx_email_logs_created_at ON email_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_email_logs_status ON email_logs(status);
+
+COMMIT;
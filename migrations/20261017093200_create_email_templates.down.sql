@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS email_template_versions;
+DROP TABLE IF EXISTS email_templates;
+
+COMMIT;
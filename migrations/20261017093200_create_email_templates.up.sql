@@ -0,0 +1,30 @@
+BEGIN;
+
+-- Email templates edited through the API. A template named like a built-in one (default, welcome,
+-- notification) replaces it.
+CREATE TABLE IF NOT EXISTS email_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500),
+    html TEXT NOT NULL,
+    variables JSONB,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_templates_name ON email_templates(name);
+
+-- Every saved version of a template, the current one included
+CREATE TABLE IF NOT EXISTS email_template_versions (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES email_templates(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    html TEXT NOT NULL,
+    variables JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_template_versions_template_version ON email_template_versions(template_id, version);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE email_logs DROP COLUMN IF EXISTS provider_message_id;
+ALTER TABLE email_logs DROP COLUMN IF EXISTS provider;
+
+DROP INDEX IF EXISTS idx_email_outbox_provider_message_id;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS delivery_updated_at;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS delivery_status;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS provider_message_id;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS provider;
+
+COMMIT;
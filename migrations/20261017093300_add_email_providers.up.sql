@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Provider that sent each email, the ID it assigned and the delivery status reported by its webhook
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS provider VARCHAR(20);
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(255);
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS delivery_status VARCHAR(20);
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS delivery_updated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_provider_message_id ON email_outbox(provider, provider_message_id);
+
+ALTER TABLE email_logs ADD COLUMN IF NOT EXISTS provider VARCHAR(20);
+ALTER TABLE email_logs ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(255);
+
+COMMIT;
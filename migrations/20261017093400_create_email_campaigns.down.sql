@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_email_outbox_campaign_id;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS campaign_id;
+
+DROP TABLE IF EXISTS email_campaigns;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Bulk sends of a template to a recipient segment. Each recipient gets its own email in the
+-- outbox, scheduled according to rate_per_minute; progress is counted from those emails.
+CREATE TABLE IF NOT EXISTS email_campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    template_name VARCHAR(100) NOT NULL,
+    subject VARCHAR(500) NOT NULL,
+    template_data JSONB,
+    segment JSONB NOT NULL,
+    rate_per_minute INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    created_by BIGINT,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS campaign_id BIGINT REFERENCES email_campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_campaign_id ON email_outbox(campaign_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE email_campaigns DROP COLUMN IF EXISTS inline_images;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS inline_images;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Images embedded in the HTML of template emails and referenced as cid:<content_id>. Only the
+-- storage key is kept; the file is read from the storage when the email is sent.
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS inline_images JSONB;
+ALTER TABLE email_campaigns ADD COLUMN IF NOT EXISTS inline_images JSONB;
+
+COMMIT;
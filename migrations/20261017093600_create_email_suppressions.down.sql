@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE email_campaigns DROP COLUMN IF EXISTS suppressed;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS marketing;
+DROP TABLE IF EXISTS email_suppressions;
+
+COMMIT;
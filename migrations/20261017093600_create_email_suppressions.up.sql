@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Addresses that must not receive marketing emails: recipients who unsubscribed, hard bounces,
+-- spam complaints and addresses added by an admin. Emails are stored lowercased.
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(320) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    note VARCHAR(500),
+    email_id BIGINT,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_suppressions_email ON email_suppressions(email);
+
+-- Marketing emails are checked against the suppression list and carry an unsubscribe link;
+-- transactional emails are always sent.
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS marketing BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE email_campaigns ADD COLUMN IF NOT EXISTS suppressed INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Preferred locale of the user (BCP 47, e.g. pt-BR or en), used to pick the variant of the email
+-- templates sent to them. NULL uses email.default_locale.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
+
+COMMIT;
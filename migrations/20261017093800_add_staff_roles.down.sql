@@ -0,0 +1,6 @@
+BEGIN;
+
+DELETE FROM user_roles WHERE role_id IN (SELECT id FROM roles WHERE name IN ('gestor', 'corretor', 'marketing'));
+DELETE FROM roles WHERE name IN ('gestor', 'corretor', 'marketing');
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Staff roles; the permissions each one grants are defined in internal/auth/permissions.go
+INSERT INTO roles (name, description, created_at, updated_at) VALUES
+    ('gestor', 'Manages listings, sliders, emails and imports', NOW(), NOW()),
+    ('corretor', 'Edits listings', NOW(), NOW()),
+    ('marketing', 'Manages sliders and sends emails', NOW(), NOW())
+ON CONFLICT (name) DO NOTHING;
+
+COMMIT;
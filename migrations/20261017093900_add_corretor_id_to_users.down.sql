@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_users_corretor_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS corretor_id;
+
+COMMIT;
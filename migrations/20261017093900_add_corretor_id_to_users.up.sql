@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Corretor principal the user works as. Users with the corretor role can only change the imoveis
+-- of this corretor; NULL leaves them unable to change any.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS corretor_id BIGINT REFERENCES corretores_principais(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_corretor_id ON users(corretor_id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_corretores_principais_organizacao_id;
+
+DROP INDEX IF EXISTS idx_email_campaigns_organizacao_id;
+ALTER TABLE email_campaigns DROP COLUMN IF EXISTS organizacao_id;
+
+DROP INDEX IF EXISTS idx_email_outbox_organizacao_id;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS organizacao_id;
+
+DROP INDEX IF EXISTS idx_sliders_organizacao_id;
+ALTER TABLE sliders DROP COLUMN IF EXISTS organizacao_id;
+
+DROP INDEX IF EXISTS idx_users_organizacao_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organizacao_id;
+
+COMMIT;
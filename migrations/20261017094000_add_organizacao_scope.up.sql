@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Organizacao each user works for. Requests of users with one only see the imoveis, sliders and
+-- emails of that organizacao; NULL leaves the user unscoped.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS organizacao_id BIGINT REFERENCES organizacoes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_users_organizacao_id ON users(organizacao_id);
+
+-- Imoveis belong to the organizacao of their corretor principal and email logs to the one of
+-- their email; the tables below record it themselves. NULL rows are only visible unscoped.
+ALTER TABLE sliders ADD COLUMN IF NOT EXISTS organizacao_id BIGINT REFERENCES organizacoes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_sliders_organizacao_id ON sliders(organizacao_id);
+
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS organizacao_id BIGINT REFERENCES organizacoes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_email_outbox_organizacao_id ON email_outbox(organizacao_id);
+
+ALTER TABLE email_campaigns ADD COLUMN IF NOT EXISTS organizacao_id BIGINT REFERENCES organizacoes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_email_campaigns_organizacao_id ON email_campaigns(organizacao_id);
+
+CREATE INDEX IF NOT EXISTS idx_corretores_principais_organizacao_id ON corretores_principais(organizacao_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Device that started or last refreshed the session (token family), listed by GET /auth/sessions
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
+
+-- Active sessions of a user are the tokens that are neither used nor revoked
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_active ON refresh_tokens(user_id, created_at DESC)
+    WHERE used_at IS NULL AND revoked_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS two_factor;
+
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- TOTP secret, set on enrollment and kept once confirmed (two_factor_enabled_at)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+-- Last TOTP step accepted, so a code cannot be used twice
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at TIMESTAMP WITH TIME ZONE;
+
+-- Sessions started with a 2FA login keep it across refreshes
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS two_factor BOOLEAN NOT NULL DEFAULT false;
+
+-- Single-use codes replacing a TOTP code; only their SHA-256 is stored
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Invitations sent by admins; the invitee creates their account with the role and organizacao
+-- chosen here. accepted_at makes the signed link single-use.
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(100),
+    role VARCHAR(50) NOT NULL,
+    organizacao_id BIGINT REFERENCES organizacoes(id) ON DELETE SET NULL,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_invitations_email ON user_invitations(email);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Authentication audit log: logins, token refreshes, logouts, password and role changes
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    email VARCHAR(255),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(512),
+    detail VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_type ON auth_events(type);
+CREATE INDEX IF NOT EXISTS idx_auth_events_user_id ON auth_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_events_email ON auth_events(LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_auth_events_created_at ON auth_events(created_at);
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Contact requests left on the public page of a property, followed by its corretor principal
+CREATE TABLE IF NOT EXISTS leads (
+    id SERIAL PRIMARY KEY,
+    imovel_id BIGINT NOT NULL REFERENCES imoveis(id) ON DELETE CASCADE,
+    corretor_principal_id BIGINT REFERENCES corretores_principais(id) ON DELETE SET NULL,
+    nome VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    telefone VARCHAR(30),
+    mensagem TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'NOVO',
+    observacoes TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_leads_imovel_id ON leads(imovel_id);
+CREATE INDEX IF NOT EXISTS idx_leads_corretor_principal_id ON leads(corretor_principal_id);
+CREATE INDEX IF NOT EXISTS idx_leads_status ON leads(status);
+CREATE INDEX IF NOT EXISTS idx_leads_created_at ON leads(created_at);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Properties saved by users or, before signing in, by an anonymous device token
+CREATE TABLE IF NOT EXISTS favoritos (
+    id SERIAL PRIMARY KEY,
+    imovel_id BIGINT NOT NULL REFERENCES imoveis(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    device_token VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_favoritos_imovel_id ON favoritos(imovel_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favoritos_user_imovel ON favoritos(user_id, imovel_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favoritos_device_imovel ON favoritos(device_token, imovel_id);
+
+COMMIT;
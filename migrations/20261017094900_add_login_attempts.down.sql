@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS login_lockouts;
+DROP TABLE IF EXISTS login_attempts;
+
+COMMIT;
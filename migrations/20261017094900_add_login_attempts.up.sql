@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Failed logins and lockouts of the login limiter when login.store is "database"
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    attempt_key VARCHAR(300) NOT NULL,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_attempt_key ON login_attempts(attempt_key);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_attempted_at ON login_attempts(attempted_at);
+
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    attempt_key VARCHAR(300) PRIMARY KEY,
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_lockouts_locked_until ON login_lockouts(locked_until);
+
+COMMIT;